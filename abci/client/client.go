@@ -1,6 +1,7 @@
 package abcicli
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -21,6 +22,11 @@ const (
 // All `Sync` methods return the appropriate protobuf ResponseXxx struct and an error.
 // Note these are client errors, eg. ABCI socket connectivity issues.
 // Application-related errors are reflected in response via ABCI error codes and logs.
+//
+// The `WithContext` variants pass the deadline of the context to the
+// application in the request, and fail the request with the error of the
+// context once it is done. The response received afterwards is discarded, and
+// the callbacks aren't called.
 type Client interface {
 	service.Service
 
@@ -33,6 +39,7 @@ type Client interface {
 	SetOptionAsync(types.RequestSetOption) *ReqRes
 	DeliverTxAsync(types.RequestDeliverTx) *ReqRes
 	CheckTxAsync(types.RequestCheckTx) *ReqRes
+	CheckTxAsyncWithContext(context.Context, types.RequestCheckTx) *ReqRes
 	QueryAsync(types.RequestQuery) *ReqRes
 	CommitAsync() *ReqRes
 	InitChainAsync(types.RequestInitChain) *ReqRes
@@ -49,6 +56,7 @@ type Client interface {
 	SetOptionSync(types.RequestSetOption) (*types.ResponseSetOption, error)
 	DeliverTxSync(types.RequestDeliverTx) (*types.ResponseDeliverTx, error)
 	CheckTxSync(types.RequestCheckTx) (*types.ResponseCheckTx, error)
+	CheckTxSyncWithContext(context.Context, types.RequestCheckTx) (*types.ResponseCheckTx, error)
 	QuerySync(types.RequestQuery) (*types.ResponseQuery, error)
 	CommitSync() (*types.ResponseCommit, error)
 	InitChainSync(types.RequestInitChain) (*types.ResponseInitChain, error)
@@ -78,6 +86,30 @@ func NewClient(addr, transport string, mustConnect bool) (client Client, err err
 	return
 }
 
+// NewConcurrentClient returns a new ABCI client of the specified transport
+// type. With the gRPC transport, it allows up to concurrency outstanding
+// CheckTx requests, each made as its own unary call, while other requests keep
+// their order.
+//
+// The socket transport does not support concurrency above 1 and returns an
+// error for it. Its wire protocol carries no request IDs, so responses can only
+// be matched to requests in the order they were sent, and the server must
+// answer in that order.
+func NewConcurrentClient(addr, transport string, mustConnect bool, concurrency int) (client Client, err error) {
+	switch transport {
+	case "socket":
+		if concurrency > 1 {
+			return nil, fmt.Errorf("the socket transport does not support a CheckTx concurrency of %d", concurrency)
+		}
+		client = NewSocketClient(addr, mustConnect)
+	case "grpc":
+		client = NewConcurrentGRPCClient(addr, mustConnect, concurrency)
+	default:
+		err = fmt.Errorf("unknown abci transport %s", transport)
+	}
+	return
+}
+
 type Callback func(*types.Request, *types.Response)
 
 type ReqRes struct {
@@ -94,6 +126,15 @@ type ReqRes struct {
 	// called and once during the normal request.
 	callbackInvoked bool
 	cb              func(*types.Response) // A single callback that may be set.
+
+	// err is set when the request failed without a response, before marking
+	// it done.
+	err error
+
+	// completed is set by complete, which also stops the cancellation of the
+	// request by its context, if any, with stopCancel.
+	completed  bool
+	stopCancel func() bool
 }
 
 func NewReqRes(req *types.Request) *ReqRes {
@@ -147,6 +188,59 @@ func (r *ReqRes) GetCallback() func(*types.Response) {
 	return r.cb
 }
 
+// Err returns the error the request failed with, without a response, if any.
+// It must only be called once the request is done (see Wait).
+func (r *ReqRes) Err() error {
+	return r.err
+}
+
+// failedReqRes returns a request that already failed with err.
+func failedReqRes(req *types.Request, err error) *ReqRes {
+	reqres := NewReqRes(req)
+	reqres.complete(nil, err)
+	return reqres
+}
+
+// cancelWith fails the request with the error of ctx once ctx is done, unless
+// it completed before.
+func (r *ReqRes) cancelWith(ctx context.Context) {
+	if ctx.Done() == nil {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.stopCancel = context.AfterFunc(ctx, func() {
+		r.complete(nil, ctx.Err())
+	})
+}
+
+// complete sets the response of the request, or the error it failed with, and
+// marks it done. It returns false without doing anything if the request
+// already completed, e.g. because it was cancelled.
+func (r *ReqRes) complete(res *types.Response, err error) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.completed {
+		return false
+	}
+	r.completed = true
+	if r.stopCancel != nil {
+		r.stopCancel()
+	}
+	r.Response, r.err = res, err
+	r.Done()
+	return true
+}
+
+// checkTxWithDeadline sets the deadline of req to the one of ctx, unless it
+// has one already.
+func checkTxWithDeadline(ctx context.Context, req types.RequestCheckTx) types.RequestCheckTx {
+	if deadline, ok := ctx.Deadline(); ok && req.Deadline == nil {
+		req.Deadline = &deadline
+	}
+	return req
+}
+
 func waitGroup1() (wg *sync.WaitGroup) {
 	wg = &sync.WaitGroup{}
 	wg.Add(1)
@@ -1,6 +1,7 @@
 package abcicli
 
 import (
+	"container/list"
 	"fmt"
 	"net"
 	"sync"
@@ -29,6 +30,19 @@ type grpcClient struct {
 	conn     *grpc.ClientConn
 	chReqRes chan *ReqRes // dispatches "async" responses to callbacks *in order*, needed by mempool
 
+	// checkTxSem limits the number of concurrent CheckTx calls, if set.
+	checkTxSem chan struct{}
+
+	// In streaming mode all requests are sent over stream, and responses are
+	// matched to the pending requests in order, as in the socket protocol.
+	preferStream bool
+	stream       types.ABCIApplication_StreamClient
+	streamCancel context.CancelFunc
+	sendMtx      cmtsync.Mutex // serializes sends on stream
+	pendingMtx   cmtsync.Mutex
+	pending      *list.List // requests sent and waiting for a response
+	streamErr    error      // set once the stream failed
+
 	mtx   cmtsync.Mutex
 	addr  string
 	err   error
@@ -51,6 +65,26 @@ func NewGRPCClient(addr string, mustConnect bool) Client {
 	return cli
 }
 
+// NewConcurrentGRPCClient returns a gRPC client making up to concurrency
+// CheckTx calls at once, each as its own unary call.
+func NewConcurrentGRPCClient(addr string, mustConnect bool, concurrency int) Client {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	cli := NewGRPCClient(addr, mustConnect).(*grpcClient)
+	cli.checkTxSem = make(chan struct{}, concurrency)
+	return cli
+}
+
+// NewStreamingGRPCClient returns a gRPC client sending all requests over a
+// single bidirectional stream, if the application advertises support for it
+// in its Info response. Otherwise it falls back to unary calls.
+func NewStreamingGRPCClient(addr string, mustConnect bool) Client {
+	cli := NewGRPCClient(addr, mustConnect).(*grpcClient)
+	cli.preferStream = true
+	return cli
+}
+
 func dialerFunc(ctx context.Context, addr string) (net.Conn, error) {
 	return cmtnet.Connect(addr)
 }
@@ -63,24 +97,9 @@ func (cli *grpcClient) OnStart() error {
 	// This processes asynchronous request/response messages and dispatches
 	// them to callbacks.
 	go func() {
-		// Use a separate function to use defer for mutex unlocks (this handles panics)
-		callCb := func(reqres *ReqRes) {
-			cli.mtx.Lock()
-			defer cli.mtx.Unlock()
-
-			reqres.Done()
-
-			// Notify client listener if set
-			if cli.resCb != nil {
-				cli.resCb(reqres.Request, reqres.Response)
-			}
-
-			// Notify reqRes listener if set
-			reqres.InvokeCallback()
-		}
 		for reqres := range cli.chReqRes {
 			if reqres != nil {
-				callCb(reqres)
+				cli.callCb(reqres, reqres.Response)
 			} else {
 				cli.Logger.Error("Received nil reqres")
 			}
@@ -114,13 +133,63 @@ RETRY_LOOP:
 		}
 
 		cli.client = client
+		if cli.preferStream {
+			return cli.startStream()
+		}
+		return nil
+	}
+}
+
+// startStream opens the stream if the application supports it.
+func (cli *grpcClient) startStream() error {
+	info, err := cli.client.Info(context.Background(), &types.RequestInfo{}, grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("failed to query application info: %w", err)
+	}
+	if !info.Streaming {
+		cli.Logger.Info("Application doesn't support streaming, using unary calls")
 		return nil
 	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := cli.client.Stream(ctx)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to open stream: %w", err)
+	}
+	cli.stream = stream
+	cli.streamCancel = cancel
+	cli.pending = list.New()
+	go cli.recvStreamRoutine()
+	return nil
+}
+
+// callCb completes reqres with res and notifies the listeners, unless reqres
+// was cancelled.
+func (cli *grpcClient) callCb(reqres *ReqRes, res *types.Response) {
+	cli.mtx.Lock()
+	defer cli.mtx.Unlock()
+
+	if !reqres.complete(res, nil) {
+		// the request was cancelled: discard the response
+		return
+	}
+
+	// Notify client listener if set
+	if cli.resCb != nil {
+		cli.resCb(reqres.Request, reqres.Response)
+	}
+
+	// Notify reqRes listener if set
+	reqres.InvokeCallback()
 }
 
 func (cli *grpcClient) OnStop() {
 	cli.BaseService.OnStop()
 
+	if cli.streamCancel != nil {
+		cli.streamCancel()
+	}
 	if cli.conn != nil {
 		cli.conn.Close()
 	}
@@ -162,12 +231,14 @@ func (cli *grpcClient) SetResponseCallback(resCb Callback) {
 // GRPC calls are synchronous, but some callbacks expect to be called asynchronously
 // (eg. the mempool expects to be able to lock to remove bad txs from cache).
 // To accommodate, we finish each call in its own go-routine,
-// which is expensive, but easy - if you want something better, use the socket protocol!
-// maybe one day, if people really want it, we use grpc streams,
-// but hopefully not :D
+// which is expensive, but easy - if you want something better, use the socket protocol,
+// or the streaming mode (see NewStreamingGRPCClient).
 
 func (cli *grpcClient) EchoAsync(msg string) *ReqRes {
 	req := types.ToRequestEcho(msg)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.Echo(context.Background(), req.GetEcho(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -177,6 +248,9 @@ func (cli *grpcClient) EchoAsync(msg string) *ReqRes {
 
 func (cli *grpcClient) FlushAsync() *ReqRes {
 	req := types.ToRequestFlush()
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.Flush(context.Background(), req.GetFlush(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -186,6 +260,9 @@ func (cli *grpcClient) FlushAsync() *ReqRes {
 
 func (cli *grpcClient) InfoAsync(params types.RequestInfo) *ReqRes {
 	req := types.ToRequestInfo(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.Info(context.Background(), req.GetInfo(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -195,6 +272,9 @@ func (cli *grpcClient) InfoAsync(params types.RequestInfo) *ReqRes {
 
 func (cli *grpcClient) SetOptionAsync(params types.RequestSetOption) *ReqRes {
 	req := types.ToRequestSetOption(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.SetOption(context.Background(), req.GetSetOption(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -204,6 +284,9 @@ func (cli *grpcClient) SetOptionAsync(params types.RequestSetOption) *ReqRes {
 
 func (cli *grpcClient) DeliverTxAsync(params types.RequestDeliverTx) *ReqRes {
 	req := types.ToRequestDeliverTx(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.DeliverTx(context.Background(), req.GetDeliverTx(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -212,9 +295,33 @@ func (cli *grpcClient) DeliverTxAsync(params types.RequestDeliverTx) *ReqRes {
 }
 
 func (cli *grpcClient) CheckTxAsync(params types.RequestCheckTx) *ReqRes {
-	req := types.ToRequestCheckTx(params)
-	res, err := cli.client.CheckTx(context.Background(), req.GetCheckTx(), grpc.WaitForReady(true))
+	return cli.CheckTxAsyncWithContext(context.Background(), params)
+}
+
+func (cli *grpcClient) CheckTxAsyncWithContext(ctx context.Context, params types.RequestCheckTx) *ReqRes {
+	req := types.ToRequestCheckTx(checkTxWithDeadline(ctx, params))
+	if err := ctx.Err(); err != nil {
+		return failedReqRes(req, err)
+	}
+	if cli.stream != nil {
+		return cli.streamRequestWithContext(ctx, req)
+	}
+	if cli.checkTxSem != nil {
+		select {
+		case cli.checkTxSem <- struct{}{}:
+		case <-ctx.Done():
+			return failedReqRes(req, ctx.Err())
+		}
+	}
+	res, err := cli.client.CheckTx(ctx, req.GetCheckTx(), grpc.WaitForReady(true))
+	if cli.checkTxSem != nil {
+		<-cli.checkTxSem
+	}
 	if err != nil {
+		if ctx.Err() != nil {
+			// the call was cancelled, not the connection
+			return failedReqRes(req, ctx.Err())
+		}
 		cli.StopForError(err)
 	}
 	return cli.finishAsyncCall(req, &types.Response{Value: &types.Response_CheckTx{CheckTx: res}})
@@ -222,6 +329,9 @@ func (cli *grpcClient) CheckTxAsync(params types.RequestCheckTx) *ReqRes {
 
 func (cli *grpcClient) QueryAsync(params types.RequestQuery) *ReqRes {
 	req := types.ToRequestQuery(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.Query(context.Background(), req.GetQuery(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -231,6 +341,9 @@ func (cli *grpcClient) QueryAsync(params types.RequestQuery) *ReqRes {
 
 func (cli *grpcClient) CommitAsync() *ReqRes {
 	req := types.ToRequestCommit()
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.Commit(context.Background(), req.GetCommit(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -240,6 +353,9 @@ func (cli *grpcClient) CommitAsync() *ReqRes {
 
 func (cli *grpcClient) InitChainAsync(params types.RequestInitChain) *ReqRes {
 	req := types.ToRequestInitChain(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.InitChain(context.Background(), req.GetInitChain(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -249,6 +365,9 @@ func (cli *grpcClient) InitChainAsync(params types.RequestInitChain) *ReqRes {
 
 func (cli *grpcClient) BeginBlockAsync(params types.RequestBeginBlock) *ReqRes {
 	req := types.ToRequestBeginBlock(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.BeginBlock(context.Background(), req.GetBeginBlock(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -258,6 +377,9 @@ func (cli *grpcClient) BeginBlockAsync(params types.RequestBeginBlock) *ReqRes {
 
 func (cli *grpcClient) EndBlockAsync(params types.RequestEndBlock) *ReqRes {
 	req := types.ToRequestEndBlock(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.EndBlock(context.Background(), req.GetEndBlock(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -267,6 +389,9 @@ func (cli *grpcClient) EndBlockAsync(params types.RequestEndBlock) *ReqRes {
 
 func (cli *grpcClient) ListSnapshotsAsync(params types.RequestListSnapshots) *ReqRes {
 	req := types.ToRequestListSnapshots(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.ListSnapshots(context.Background(), req.GetListSnapshots(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -276,6 +401,9 @@ func (cli *grpcClient) ListSnapshotsAsync(params types.RequestListSnapshots) *Re
 
 func (cli *grpcClient) OfferSnapshotAsync(params types.RequestOfferSnapshot) *ReqRes {
 	req := types.ToRequestOfferSnapshot(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.OfferSnapshot(context.Background(), req.GetOfferSnapshot(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -285,6 +413,9 @@ func (cli *grpcClient) OfferSnapshotAsync(params types.RequestOfferSnapshot) *Re
 
 func (cli *grpcClient) LoadSnapshotChunkAsync(params types.RequestLoadSnapshotChunk) *ReqRes {
 	req := types.ToRequestLoadSnapshotChunk(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.LoadSnapshotChunk(context.Background(), req.GetLoadSnapshotChunk(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -294,6 +425,9 @@ func (cli *grpcClient) LoadSnapshotChunkAsync(params types.RequestLoadSnapshotCh
 
 func (cli *grpcClient) ApplySnapshotChunkAsync(params types.RequestApplySnapshotChunk) *ReqRes {
 	req := types.ToRequestApplySnapshotChunk(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.ApplySnapshotChunk(context.Background(), req.GetApplySnapshotChunk(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -306,6 +440,9 @@ func (cli *grpcClient) PrepareProposalAsync(
 ) *ReqRes {
 
 	req := types.ToRequestPrepareProposal(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.PrepareProposal(context.Background(), req.GetPrepareProposal(), grpc.WaitForReady(true))
 	if err != nil {
 		cli.StopForError(err)
@@ -325,6 +462,9 @@ func (cli *grpcClient) ProcessProposalAsync(
 ) *ReqRes {
 
 	req := types.ToRequestProcessProposal(params)
+	if cli.stream != nil {
+		return cli.streamRequest(req)
+	}
 	res, err := cli.client.ProcessProposal(context.Background(), req.GetProcessProposal(), grpc.WaitForReady(true))
 	if err != nil {
 		return nil
@@ -340,6 +480,96 @@ func (cli *grpcClient) ProcessProposalAsync(
 	)
 }
 
+//----------------------------------------
+// Streaming
+
+// streamRequest sends req over the stream. The returned ReqRes completes once
+// the response arrives, after the responses to all the earlier requests.
+func (cli *grpcClient) streamRequest(req *types.Request) *ReqRes {
+	return cli.streamRequestWithContext(context.Background(), req)
+}
+
+// streamRequestWithContext sends req over the stream. It fails with the error
+// of ctx once ctx is done, unless it completed before.
+func (cli *grpcClient) streamRequestWithContext(ctx context.Context, req *types.Request) *ReqRes {
+	reqres := NewReqRes(req)
+	reqres.cancelWith(ctx)
+
+	cli.sendMtx.Lock()
+	defer cli.sendMtx.Unlock()
+
+	cli.pendingMtx.Lock()
+	if err := cli.streamErr; err != nil {
+		cli.pendingMtx.Unlock()
+		cli.callCb(reqres, &types.Response{})
+		return reqres
+	}
+	cli.pending.PushBack(reqres)
+	cli.pendingMtx.Unlock()
+
+	if err := cli.stream.Send(req); err != nil {
+		// the receive routine fails all the pending requests
+		cli.StopForError(fmt.Errorf("failed to send request: %w", err))
+	}
+	return reqres
+}
+
+func (cli *grpcClient) recvStreamRoutine() {
+	for {
+		res, err := cli.stream.Recv()
+		if err != nil {
+			cli.failStream(fmt.Errorf("stream closed: %w", err))
+			return
+		}
+		if _, ok := res.Value.(*types.Response_Exception); ok {
+			cli.failStream(fmt.Errorf("application exception: %v", res.GetException().Error))
+			return
+		}
+
+		cli.pendingMtx.Lock()
+		next := cli.pending.Front()
+		if next == nil {
+			cli.pendingMtx.Unlock()
+			cli.failStream(fmt.Errorf("unexpected %T when nothing expected", res.Value))
+			return
+		}
+		reqres := next.Value.(*ReqRes)
+		if !resMatchesReq(reqres.Request, res) {
+			cli.pendingMtx.Unlock()
+			cli.failStream(fmt.Errorf("unexpected %T when response to %T expected",
+				res.Value, reqres.Request.Value))
+			return
+		}
+		cli.pending.Remove(next)
+		cli.pendingMtx.Unlock()
+
+		cli.callCb(reqres, res)
+	}
+}
+
+// failStream records err, completes all the pending requests with an empty
+// response and stops the client.
+func (cli *grpcClient) failStream(err error) {
+	cli.mtx.Lock()
+	if cli.err == nil {
+		cli.err = err
+	}
+	cli.mtx.Unlock()
+
+	cli.pendingMtx.Lock()
+	cli.streamErr = err
+	pending := cli.pending
+	cli.pending = list.New()
+	cli.pendingMtx.Unlock()
+
+	for e := pending.Front(); e != nil; e = e.Next() {
+		cli.callCb(e.Value.(*ReqRes), &types.Response{})
+	}
+	cli.StopForError(err)
+}
+
+//----------------------------------------
+
 // finishAsyncCall creates a ReqRes for an async call, and immediately populates it
 // with the response. We don't complete it until it's been ordered via the channel.
 func (cli *grpcClient) finishAsyncCall(req *types.Request, res *types.Response) *ReqRes {
@@ -411,6 +641,19 @@ func (cli *grpcClient) CheckTxSync(params types.RequestCheckTx) (*types.Response
 	return cli.finishSyncCall(reqres).GetCheckTx(), cli.Error()
 }
 
+func (cli *grpcClient) CheckTxSyncWithContext(
+	ctx context.Context,
+	params types.RequestCheckTx,
+) (*types.ResponseCheckTx, error) {
+	reqres := cli.CheckTxAsyncWithContext(ctx, params)
+	// The callbacks of a cancelled request aren't called: wait for it instead.
+	reqres.Wait()
+	if err := reqres.Err(); err != nil {
+		return nil, err
+	}
+	return reqres.Response.GetCheckTx(), cli.Error()
+}
+
 func (cli *grpcClient) QuerySync(req types.RequestQuery) (*types.ResponseQuery, error) {
 	reqres := cli.QueryAsync(req)
 	return cli.finishSyncCall(reqres).GetQuery(), cli.Error()
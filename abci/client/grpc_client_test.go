@@ -0,0 +1,62 @@
+package abcicli_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/server"
+	"github.com/tendermint/tendermint/abci/types"
+)
+
+func setupConcurrentGRPCClientServer(t testing.TB, app types.Application, concurrency int) abcicli.Client {
+	addr := "unix://" + filepath.Join(t.TempDir(), "abci.sock")
+
+	s, err := server.NewServer(addr, "grpc", app)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	t.Cleanup(func() {
+		if err := s.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	c := abcicli.NewConcurrentGRPCClient(addr, true, concurrency)
+	c.SetResponseCallback(func(*types.Request, *types.Response) {})
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		if err := c.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	return c
+}
+
+func TestConcurrentGRPCClientCheckTx(t *testing.T) {
+	const concurrency = 4
+	app := &latencyApp{latency: time.Millisecond}
+	c := setupConcurrentGRPCClientServer(t, app, concurrency)
+
+	checkTxs(t, c, 200, 2*concurrency)
+	assert.EqualValues(t, concurrency, atomic.LoadInt32(&app.maxCheckTx))
+}
+
+func BenchmarkConcurrentGRPCClientCheckTx(b *testing.B) {
+	for _, concurrency := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			app := &latencyApp{latency: time.Millisecond}
+			c := setupConcurrentGRPCClientServer(b, app, concurrency)
+
+			b.ResetTimer()
+			start := time.Now()
+			checkTxs(b, c, b.N*concurrency, concurrency)
+			b.ReportMetric(float64(b.N*concurrency)/time.Since(start).Seconds(), "txs/s")
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package abcicli
 
 import (
+	"context"
+	"sync"
+
 	types "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/service"
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
@@ -15,7 +18,16 @@ var _ Client = (*localClient)(nil)
 type localClient struct {
 	service.BaseService
 
-	mtx *cmtsync.Mutex
+	mtx sync.Locker
+	// checkTxMtx is locked by CheckTx calls instead of mtx. CheckTx calls run
+	// concurrently when it's the read lock of mtx, up to the capacity of
+	// checkTxSem, while all the other calls stay exclusive.
+	checkTxMtx sync.Locker
+	checkTxSem chan struct{}
+	// queryMtx is locked by Info and Query calls instead of mtx. It's a no-op
+	// if the app declares them safe for concurrent use (see
+	// types.ConcurrentQuerier).
+	queryMtx sync.Locker
 	types.Application
 	Callback
 }
@@ -23,7 +35,8 @@ type localClient struct {
 var _ Client = (*localClient)(nil)
 
 // NewLocalClient creates a local client, which will be directly calling the
-// methods of the given app.
+// methods of the given app. All the calls hold mtx, except Info and Query if
+// the app declares them safe for concurrent use (see types.ConcurrentQuerier).
 //
 // Both Async and Sync methods ignore the given context.Context parameter.
 func NewLocalClient(mtx *cmtsync.Mutex, app types.Application) Client {
@@ -32,6 +45,33 @@ func NewLocalClient(mtx *cmtsync.Mutex, app types.Application) Client {
 	}
 	cli := &localClient{
 		mtx:         mtx,
+		checkTxMtx:  mtx,
+		queryMtx:    queryLocker(mtx, app),
+		Application: app,
+	}
+	cli.BaseService = *service.NewBaseService(nil, "localClient", cli)
+	return cli
+}
+
+// NewConcurrentLocalClient creates a local client, which will be directly
+// calling the methods of the given app, allowing up to concurrency CheckTx
+// calls at once. The app must support concurrent CheckTx calls, and the
+// response callback must be safe for concurrent use.
+//
+// All the other calls, in particular the consensus ones, hold mtx exclusively,
+// so they are never run concurrently with CheckTx calls.
+func NewConcurrentLocalClient(mtx *cmtsync.RWMutex, app types.Application, concurrency int) Client {
+	if mtx == nil {
+		mtx = new(cmtsync.RWMutex)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	cli := &localClient{
+		mtx:         mtx,
+		checkTxMtx:  mtx.RLocker(),
+		checkTxSem:  make(chan struct{}, concurrency),
+		queryMtx:    queryLocker(mtx, app),
 		Application: app,
 	}
 	cli.BaseService = *service.NewBaseService(nil, "localClient", cli)
@@ -65,8 +105,8 @@ func (app *localClient) EchoAsync(msg string) *ReqRes {
 }
 
 func (app *localClient) InfoAsync(req types.RequestInfo) *ReqRes {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	app.queryMtx.Lock()
+	defer app.queryMtx.Unlock()
 
 	res := app.Application.Info(req)
 	return app.callback(
@@ -98,9 +138,19 @@ func (app *localClient) DeliverTxAsync(params types.RequestDeliverTx) *ReqRes {
 }
 
 func (app *localClient) CheckTxAsync(req types.RequestCheckTx) *ReqRes {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	return app.CheckTxAsyncWithContext(context.Background(), req)
+}
+
+// CheckTxAsyncWithContext can't interrupt the application, but doesn't call
+// it once ctx is done, e.g. while waiting for the lock.
+func (app *localClient) CheckTxAsyncWithContext(ctx context.Context, req types.RequestCheckTx) *ReqRes {
+	app.lockCheckTx()
+	defer app.unlockCheckTx()
 
+	req = checkTxWithDeadline(ctx, req)
+	if err := ctx.Err(); err != nil {
+		return failedReqRes(types.ToRequestCheckTx(req), err)
+	}
 	res := app.Application.CheckTx(req)
 	return app.callback(
 		types.ToRequestCheckTx(req),
@@ -109,8 +159,8 @@ func (app *localClient) CheckTxAsync(req types.RequestCheckTx) *ReqRes {
 }
 
 func (app *localClient) QueryAsync(req types.RequestQuery) *ReqRes {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	app.queryMtx.Lock()
+	defer app.queryMtx.Unlock()
 
 	res := app.Application.Query(req)
 	return app.callback(
@@ -244,8 +294,8 @@ func (app *localClient) EchoSync(msg string) (*types.ResponseEcho, error) {
 }
 
 func (app *localClient) InfoSync(req types.RequestInfo) (*types.ResponseInfo, error) {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	app.queryMtx.Lock()
+	defer app.queryMtx.Unlock()
 
 	res := app.Application.Info(req)
 	return &res, nil
@@ -268,16 +318,26 @@ func (app *localClient) DeliverTxSync(req types.RequestDeliverTx) (*types.Respon
 }
 
 func (app *localClient) CheckTxSync(req types.RequestCheckTx) (*types.ResponseCheckTx, error) {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	return app.CheckTxSyncWithContext(context.Background(), req)
+}
 
-	res := app.Application.CheckTx(req)
+func (app *localClient) CheckTxSyncWithContext(
+	ctx context.Context,
+	req types.RequestCheckTx,
+) (*types.ResponseCheckTx, error) {
+	app.lockCheckTx()
+	defer app.unlockCheckTx()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res := app.Application.CheckTx(checkTxWithDeadline(ctx, req))
 	return &res, nil
 }
 
 func (app *localClient) QuerySync(req types.RequestQuery) (*types.ResponseQuery, error) {
-	app.mtx.Lock()
-	defer app.mtx.Unlock()
+	app.queryMtx.Lock()
+	defer app.queryMtx.Unlock()
 
 	res := app.Application.Query(req)
 	return &res, nil
@@ -372,6 +432,33 @@ func (app *localClient) ProcessProposalSync(
 
 //-------------------------------------------------------
 
+func (app *localClient) lockCheckTx() {
+	if app.checkTxSem != nil {
+		app.checkTxSem <- struct{}{}
+	}
+	app.checkTxMtx.Lock()
+}
+
+func (app *localClient) unlockCheckTx() {
+	app.checkTxMtx.Unlock()
+	if app.checkTxSem != nil {
+		<-app.checkTxSem
+	}
+}
+
+// queryLocker returns the locker for Info and Query calls to app.
+func queryLocker(mtx sync.Locker, app types.Application) sync.Locker {
+	if q, ok := app.(types.ConcurrentQuerier); ok && q.ConcurrentQueries() {
+		return nopLocker{}
+	}
+	return mtx
+}
+
+type nopLocker struct{}
+
+func (nopLocker) Lock()   {}
+func (nopLocker) Unlock() {}
+
 func (app *localClient) callback(req *types.Request, res *types.Response) *ReqRes {
 	app.Callback(req, res)
 	rr := newLocalReqRes(req, res)
@@ -382,5 +469,6 @@ func (app *localClient) callback(req *types.Request, res *types.Response) *ReqRe
 func newLocalReqRes(req *types.Request, res *types.Response) *ReqRes {
 	reqRes := NewReqRes(req)
 	reqRes.Response = res
+	reqRes.Done()
 	return reqRes
 }
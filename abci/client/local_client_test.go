@@ -0,0 +1,227 @@
+package abcicli_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/types"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+// latencyApp takes latency to answer CheckTx and Commit, and records how many
+// calls it served at once.
+type latencyApp struct {
+	types.BaseApplication
+	latency time.Duration
+
+	running     int32
+	maxCheckTx  int32
+	commitShare int32 // set if a CheckTx ran during a Commit
+}
+
+func (app *latencyApp) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
+	running := atomic.AddInt32(&app.running, 1)
+	defer atomic.AddInt32(&app.running, -1)
+	for {
+		max := atomic.LoadInt32(&app.maxCheckTx)
+		if running <= max || atomic.CompareAndSwapInt32(&app.maxCheckTx, max, running) {
+			break
+		}
+	}
+	time.Sleep(app.latency)
+	return types.ResponseCheckTx{Code: types.CodeTypeOK}
+}
+
+func (app *latencyApp) Commit() types.ResponseCommit {
+	if atomic.AddInt32(&app.running, 1) != 1 {
+		atomic.StoreInt32(&app.commitShare, 1)
+	}
+	time.Sleep(app.latency)
+	if atomic.AddInt32(&app.running, -1) != 0 {
+		atomic.StoreInt32(&app.commitShare, 1)
+	}
+	return types.ResponseCommit{}
+}
+
+func checkTxs(t testing.TB, c abcicli.Client, n, workers int) {
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n/workers; i++ {
+				res, err := c.CheckTxSync(types.RequestCheckTx{Tx: []byte("tx")})
+				require.NoError(t, err)
+				require.True(t, res.IsOK())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentLocalClientCheckTx(t *testing.T) {
+	const concurrency = 4
+	app := &latencyApp{latency: time.Millisecond}
+	c := abcicli.NewConcurrentLocalClient(nil, app, concurrency)
+	c.SetResponseCallback(func(*types.Request, *types.Response) {})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			_, err := c.CommitSync()
+			require.NoError(t, err)
+		}
+	}()
+	checkTxs(t, c, 200, 2*concurrency)
+	<-done
+
+	assert.EqualValues(t, concurrency, atomic.LoadInt32(&app.maxCheckTx))
+	assert.Zero(t, atomic.LoadInt32(&app.commitShare), "CheckTx ran during Commit")
+}
+
+func TestLocalClientSerialCheckTx(t *testing.T) {
+	app := &latencyApp{latency: time.Millisecond}
+	c := abcicli.NewLocalClient(nil, app)
+	c.SetResponseCallback(func(*types.Request, *types.Response) {})
+
+	checkTxs(t, c, 40, 4)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&app.maxCheckTx))
+}
+
+func BenchmarkConcurrentLocalClientCheckTx(b *testing.B) {
+	for _, concurrency := range []int{1, 2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			app := &latencyApp{latency: time.Millisecond}
+			c := abcicli.NewConcurrentLocalClient(nil, app, concurrency)
+			c.SetResponseCallback(func(*types.Request, *types.Response) {})
+
+			b.ResetTimer()
+			start := time.Now()
+			checkTxs(b, c, b.N*concurrency, concurrency)
+			b.ReportMetric(float64(b.N*concurrency)/time.Since(start).Seconds(), "txs/s")
+		})
+	}
+}
+
+// blockingApp holds Commit until release is closed, simulating a long block
+// execution.
+type blockingApp struct {
+	types.BaseApplication
+	concurrentQueries bool
+	commitLatency     time.Duration
+	started, release  chan struct{}
+	commits           int32
+}
+
+func (app *blockingApp) ConcurrentQueries() bool {
+	return app.concurrentQueries
+}
+
+func (app *blockingApp) Commit() types.ResponseCommit {
+	if app.release != nil {
+		close(app.started)
+		<-app.release
+	}
+	atomic.AddInt32(&app.commits, 1)
+	time.Sleep(app.commitLatency)
+	return types.ResponseCommit{}
+}
+
+func TestLocalClientConcurrentQueries(t *testing.T) {
+	for _, concurrent := range []bool{true, false} {
+		t.Run(fmt.Sprintf("concurrent=%t", concurrent), func(t *testing.T) {
+			mtx := new(cmtsync.Mutex)
+			app := &blockingApp{
+				concurrentQueries: concurrent,
+				started:           make(chan struct{}),
+				release:           make(chan struct{}),
+			}
+			consensus := abcicli.NewLocalClient(mtx, app)
+			query := abcicli.NewLocalClient(mtx, app)
+
+			committed := make(chan struct{})
+			go func() {
+				defer close(committed)
+				_, err := consensus.CommitSync()
+				require.NoError(t, err)
+			}()
+			<-app.started
+
+			queried := make(chan struct{})
+			go func() {
+				defer close(queried)
+				_, err := query.QuerySync(types.RequestQuery{})
+				require.NoError(t, err)
+				_, err = query.InfoSync(types.RequestInfo{})
+				require.NoError(t, err)
+			}()
+
+			select {
+			case <-queried:
+				assert.True(t, concurrent, "queries didn't wait for Commit")
+			case <-time.After(50 * time.Millisecond):
+				assert.False(t, concurrent, "queries waited for Commit")
+			}
+			close(app.release)
+			<-committed
+			<-queried
+		})
+	}
+}
+
+func BenchmarkLocalClientQueryDuringCommit(b *testing.B) {
+	for _, concurrent := range []bool{false, true} {
+		b.Run(fmt.Sprintf("concurrent=%t", concurrent), func(b *testing.B) {
+			mtx := new(cmtsync.Mutex)
+			app := &blockingApp{concurrentQueries: concurrent, commitLatency: 20 * time.Millisecond}
+			consensus := abcicli.NewLocalClient(mtx, app)
+			query := abcicli.NewLocalClient(mtx, app)
+
+			// execute blocks back to back
+			done := make(chan struct{})
+			stopped := make(chan struct{})
+			go func() {
+				defer close(stopped)
+				for {
+					select {
+					case <-done:
+						return
+					default:
+					}
+					_, err := consensus.CommitSync()
+					require.NoError(b, err)
+				}
+			}()
+
+			for atomic.LoadInt32(&app.commits) == 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			var total, max time.Duration
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				start := time.Now()
+				_, err := query.QuerySync(types.RequestQuery{})
+				require.NoError(b, err)
+				latency := time.Since(start)
+				total += latency
+				if latency > max {
+					max = latency
+				}
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(total.Microseconds())/float64(b.N), "µs/query")
+			b.ReportMetric(float64(max.Microseconds()), "max-µs/query")
+			close(done)
+			<-stopped
+		})
+	}
+}
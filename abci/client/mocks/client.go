@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	abcicli "github.com/tendermint/tendermint/abci/client"
 	log "github.com/tendermint/tendermint/libs/log"
 
@@ -110,6 +112,22 @@ func (_m *Client) CheckTxAsync(_a0 types.RequestCheckTx) *abcicli.ReqRes {
 	return r0
 }
 
+// CheckTxAsyncWithContext provides a mock function with given fields: _a0, _a1
+func (_m *Client) CheckTxAsyncWithContext(_a0 context.Context, _a1 types.RequestCheckTx) *abcicli.ReqRes {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *abcicli.ReqRes
+	if rf, ok := ret.Get(0).(func(context.Context, types.RequestCheckTx) *abcicli.ReqRes); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*abcicli.ReqRes)
+		}
+	}
+
+	return r0
+}
+
 // CheckTxSync provides a mock function with given fields: _a0
 func (_m *Client) CheckTxSync(_a0 types.RequestCheckTx) (*types.ResponseCheckTx, error) {
 	ret := _m.Called(_a0)
@@ -133,6 +151,29 @@ func (_m *Client) CheckTxSync(_a0 types.RequestCheckTx) (*types.ResponseCheckTx,
 	return r0, r1
 }
 
+// CheckTxSyncWithContext provides a mock function with given fields: _a0, _a1
+func (_m *Client) CheckTxSyncWithContext(_a0 context.Context, _a1 types.RequestCheckTx) (*types.ResponseCheckTx, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *types.ResponseCheckTx
+	if rf, ok := ret.Get(0).(func(context.Context, types.RequestCheckTx) *types.ResponseCheckTx); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.ResponseCheckTx)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, types.RequestCheckTx) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CommitAsync provides a mock function with given fields:
 func (_m *Client) CommitAsync() *abcicli.ReqRes {
 	ret := _m.Called()
@@ -3,9 +3,9 @@ package abcicli
 import (
 	"bufio"
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net"
 	"reflect"
 	"time"
@@ -20,8 +20,33 @@ import (
 const (
 	reqQueueSize    = 256 // TODO make configurable
 	flushThrottleMS = 20  // Don't wait longer than...
+
+	defaultReconnectMinBackoff = 500 * time.Millisecond
+	defaultReconnectMaxBackoff = 30 * time.Second
+	reconnectHandshakeTimeout  = 10 * time.Second
 )
 
+// ErrConnectionLost is returned for the requests in flight when the
+// connection to the application drops, and for the requests made while the
+// client reconnects. They can be retried once reconnected.
+var ErrConnectionLost = errors.New("lost connection to the ABCI application")
+
+// ReconnectConfig configures how a socket client reconnects to the
+// application when the connection drops.
+type ReconnectConfig struct {
+	// Info request sent to the application once reconnected.
+	RequestInfo types.RequestInfo
+	// CheckInfo checks the Info response of the application once reconnected,
+	// e.g. that its last block height matches the node's. If it returns an
+	// error, the client stops with it instead of resuming.
+	CheckInfo func(types.ResponseInfo) error
+
+	// Delay before the first reconnection attempt, doubled after each failed
+	// attempt up to MaxBackoff. Default: 500ms and 30s.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
 // This is goroutine-safe, but users should beware that the application in
 // general is not meant to be interfaced with concurrent callers.
 type socketClient struct {
@@ -30,6 +55,12 @@ type socketClient struct {
 	addr        string
 	mustConnect bool
 	conn        net.Conn
+	connDone    chan struct{} // closed when conn drops
+	reconnect   *ReconnectConfig
+
+	// reconnecting is set while the client reconnects, during which requests
+	// fail with ErrConnectionLost.
+	reconnecting bool
 
 	reqQueue   chan *ReqRes
 	flushTimer *timer.ThrottleTimer
@@ -59,6 +90,23 @@ func NewSocketClient(addr string, mustConnect bool) Client {
 	return cli
 }
 
+// NewReconnectingSocketClient creates a new socket client like
+// NewSocketClient, which reconnects with exponential backoff when the
+// connection to the application drops. Once reconnected, it sends an Info
+// request to the application and only resumes if its response passes the
+// check of the config.
+func NewReconnectingSocketClient(addr string, mustConnect bool, config ReconnectConfig) Client {
+	if config.MinBackoff <= 0 {
+		config.MinBackoff = defaultReconnectMinBackoff
+	}
+	if config.MaxBackoff < config.MinBackoff {
+		config.MaxBackoff = defaultReconnectMaxBackoff
+	}
+	cli := NewSocketClient(addr, mustConnect).(*socketClient)
+	cli.reconnect = &config
+	return cli
+}
+
 // OnStart implements Service by connecting to the server and spawning reading
 // and writing goroutines.
 func (cli *socketClient) OnStart() error {
@@ -78,22 +126,36 @@ func (cli *socketClient) OnStart() error {
 			time.Sleep(time.Second * dialRetryIntervalSeconds)
 			continue
 		}
-		cli.conn = conn
-
-		go cli.sendRequestsRoutine(conn)
-		go cli.recvResponseRoutine(conn)
+		cli.startConn(conn)
 
 		return nil
 	}
 }
 
+// startConn spawns the reading and writing goroutines of conn.
+func (cli *socketClient) startConn(conn net.Conn) {
+	cli.mtx.Lock()
+	cli.conn = conn
+	cli.connDone = make(chan struct{})
+	cli.reconnecting = false
+	cli.err = nil
+	done := cli.connDone
+	cli.mtx.Unlock()
+
+	go cli.sendRequestsRoutine(conn, done)
+	go cli.recvResponseRoutine(conn, done)
+}
+
 // OnStop implements Service by closing connection and flushing all queues.
 func (cli *socketClient) OnStop() {
-	if cli.conn != nil {
-		cli.conn.Close()
+	cli.mtx.Lock()
+	conn := cli.conn
+	cli.mtx.Unlock()
+	if conn != nil {
+		conn.Close()
 	}
 
-	cli.flushQueue()
+	cli.flushQueue(cli.Error())
 	cli.flushTimer.Stop()
 }
 
@@ -116,17 +178,21 @@ func (cli *socketClient) SetResponseCallback(resCb Callback) {
 
 //----------------------------------------
 
-func (cli *socketClient) sendRequestsRoutine(conn io.Writer) {
+func (cli *socketClient) sendRequestsRoutine(conn net.Conn, done <-chan struct{}) {
 	w := bufio.NewWriter(conn)
 	for {
 		select {
 		case reqres := <-cli.reqQueue:
 			// cli.Logger.Debug("Sent request", "requestType", reflect.TypeOf(reqres.Request), "request", reqres.Request)
 
-			cli.willSendReq(reqres)
+			if !cli.willSendReq(conn, reqres) {
+				// the connection dropped while the request was queued
+				reqres.complete(nil, ErrConnectionLost)
+				return
+			}
 			err := types.WriteMessage(reqres.Request, w)
 			if err != nil {
-				cli.stopForError(fmt.Errorf("write to buffer: %w", err))
+				cli.connFailed(conn, fmt.Errorf("write to buffer: %w", err))
 				return
 			}
 
@@ -134,7 +200,7 @@ func (cli *socketClient) sendRequestsRoutine(conn io.Writer) {
 			if _, ok := reqres.Request.Value.(*types.Request_Flush); ok {
 				err = w.Flush()
 				if err != nil {
-					cli.stopForError(fmt.Errorf("flush buffer: %w", err))
+					cli.connFailed(conn, fmt.Errorf("flush buffer: %w", err))
 					return
 				}
 			}
@@ -144,19 +210,21 @@ func (cli *socketClient) sendRequestsRoutine(conn io.Writer) {
 			default:
 				// Probably will fill the buffer, or retry later.
 			}
+		case <-done:
+			return
 		case <-cli.Quit():
 			return
 		}
 	}
 }
 
-func (cli *socketClient) recvResponseRoutine(conn io.Reader) {
+func (cli *socketClient) recvResponseRoutine(conn net.Conn, done <-chan struct{}) {
 	r := bufio.NewReader(conn)
 	for {
 		var res = &types.Response{}
 		err := types.ReadMessage(r, res)
 		if err != nil {
-			cli.stopForError(fmt.Errorf("read message: %w", err))
+			cli.connFailed(conn, fmt.Errorf("read message: %w", err))
 			return
 		}
 
@@ -168,7 +236,7 @@ func (cli *socketClient) recvResponseRoutine(conn io.Reader) {
 			cli.stopForError(errors.New(r.Exception.Error))
 			return
 		default:
-			err := cli.didRecvResponse(res)
+			err := cli.didRecvResponse(conn, res)
 			if err != nil {
 				cli.stopForError(err)
 				return
@@ -177,16 +245,27 @@ func (cli *socketClient) recvResponseRoutine(conn io.Reader) {
 	}
 }
 
-func (cli *socketClient) willSendReq(reqres *ReqRes) {
+// willSendReq records reqres as sent on conn. It returns false if conn
+// dropped.
+func (cli *socketClient) willSendReq(conn net.Conn, reqres *ReqRes) bool {
 	cli.mtx.Lock()
 	defer cli.mtx.Unlock()
+	if cli.reconnecting || cli.conn != conn {
+		return false
+	}
 	cli.reqSent.PushBack(reqres)
+	return true
 }
 
-func (cli *socketClient) didRecvResponse(res *types.Response) error {
+func (cli *socketClient) didRecvResponse(conn net.Conn, res *types.Response) error {
 	cli.mtx.Lock()
 	defer cli.mtx.Unlock()
 
+	// The requests sent on a dropped connection already failed.
+	if cli.reconnecting || cli.conn != conn {
+		return nil
+	}
+
 	// Get the first ReqRes.
 	next := cli.reqSent.Front()
 	if next == nil {
@@ -199,10 +278,14 @@ func (cli *socketClient) didRecvResponse(res *types.Response) error {
 			reflect.TypeOf(res.Value), reflect.TypeOf(reqres.Request.Value))
 	}
 
-	reqres.Response = res
-	reqres.Done()            // release waiters
 	cli.reqSent.Remove(next) // pop first item from linked list
 
+	// Release waiters, unless the request was cancelled: then discard the
+	// response.
+	if !reqres.complete(res, nil) {
+		return nil
+	}
+
 	// Notify client listener if set (global callback).
 	if cli.resCb != nil {
 		cli.resCb(reqres.Request, res)
@@ -243,6 +326,13 @@ func (cli *socketClient) CheckTxAsync(req types.RequestCheckTx) *ReqRes {
 	return cli.queueRequest(types.ToRequestCheckTx(req))
 }
 
+func (cli *socketClient) CheckTxAsyncWithContext(ctx context.Context, req types.RequestCheckTx) *ReqRes {
+	if err := ctx.Err(); err != nil {
+		return failedReqRes(types.ToRequestCheckTx(req), err)
+	}
+	return cli.queueRequestWithContext(ctx, types.ToRequestCheckTx(checkTxWithDeadline(ctx, req)))
+}
+
 func (cli *socketClient) QueryAsync(req types.RequestQuery) *ReqRes {
 	return cli.queueRequest(types.ToRequestQuery(req))
 }
@@ -297,7 +387,7 @@ func (cli *socketClient) FlushSync() error {
 		return err
 	}
 	reqRes.Wait() // NOTE: if we don't flush the queue, its possible to get stuck here
-	return cli.Error()
+	return cli.reqResError(reqRes)
 }
 
 func (cli *socketClient) EchoSync(msg string) (*types.ResponseEcho, error) {
@@ -306,7 +396,7 @@ func (cli *socketClient) EchoSync(msg string) (*types.ResponseEcho, error) {
 		return nil, err
 	}
 
-	return reqres.Response.GetEcho(), cli.Error()
+	return reqres.Response.GetEcho(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) InfoSync(req types.RequestInfo) (*types.ResponseInfo, error) {
@@ -315,7 +405,7 @@ func (cli *socketClient) InfoSync(req types.RequestInfo) (*types.ResponseInfo, e
 		return nil, err
 	}
 
-	return reqres.Response.GetInfo(), cli.Error()
+	return reqres.Response.GetInfo(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) SetOptionSync(req types.RequestSetOption) (*types.ResponseSetOption, error) {
@@ -324,7 +414,7 @@ func (cli *socketClient) SetOptionSync(req types.RequestSetOption) (*types.Respo
 		return nil, err
 	}
 
-	return reqres.Response.GetSetOption(), cli.Error()
+	return reqres.Response.GetSetOption(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) DeliverTxSync(req types.RequestDeliverTx) (*types.ResponseDeliverTx, error) {
@@ -333,7 +423,7 @@ func (cli *socketClient) DeliverTxSync(req types.RequestDeliverTx) (*types.Respo
 		return nil, err
 	}
 
-	return reqres.Response.GetDeliverTx(), cli.Error()
+	return reqres.Response.GetDeliverTx(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) CheckTxSync(req types.RequestCheckTx) (*types.ResponseCheckTx, error) {
@@ -342,7 +432,20 @@ func (cli *socketClient) CheckTxSync(req types.RequestCheckTx) (*types.ResponseC
 		return nil, err
 	}
 
-	return reqres.Response.GetCheckTx(), cli.Error()
+	return reqres.Response.GetCheckTx(), cli.reqResError(reqres)
+}
+
+func (cli *socketClient) CheckTxSyncWithContext(
+	ctx context.Context,
+	req types.RequestCheckTx,
+) (*types.ResponseCheckTx, error) {
+	reqres := cli.CheckTxAsyncWithContext(ctx, req)
+	// Unlike FlushSync, don't wait for the flush response, which comes after
+	// the one to the cancelled request.
+	cli.FlushAsync()
+	reqres.Wait()
+
+	return reqres.Response.GetCheckTx(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) QuerySync(req types.RequestQuery) (*types.ResponseQuery, error) {
@@ -351,7 +454,7 @@ func (cli *socketClient) QuerySync(req types.RequestQuery) (*types.ResponseQuery
 		return nil, err
 	}
 
-	return reqres.Response.GetQuery(), cli.Error()
+	return reqres.Response.GetQuery(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) CommitSync() (*types.ResponseCommit, error) {
@@ -360,7 +463,7 @@ func (cli *socketClient) CommitSync() (*types.ResponseCommit, error) {
 		return nil, err
 	}
 
-	return reqres.Response.GetCommit(), cli.Error()
+	return reqres.Response.GetCommit(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) InitChainSync(req types.RequestInitChain) (*types.ResponseInitChain, error) {
@@ -369,7 +472,7 @@ func (cli *socketClient) InitChainSync(req types.RequestInitChain) (*types.Respo
 		return nil, err
 	}
 
-	return reqres.Response.GetInitChain(), cli.Error()
+	return reqres.Response.GetInitChain(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) BeginBlockSync(req types.RequestBeginBlock) (*types.ResponseBeginBlock, error) {
@@ -378,7 +481,7 @@ func (cli *socketClient) BeginBlockSync(req types.RequestBeginBlock) (*types.Res
 		return nil, err
 	}
 
-	return reqres.Response.GetBeginBlock(), cli.Error()
+	return reqres.Response.GetBeginBlock(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) EndBlockSync(req types.RequestEndBlock) (*types.ResponseEndBlock, error) {
@@ -387,7 +490,7 @@ func (cli *socketClient) EndBlockSync(req types.RequestEndBlock) (*types.Respons
 		return nil, err
 	}
 
-	return reqres.Response.GetEndBlock(), cli.Error()
+	return reqres.Response.GetEndBlock(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) ListSnapshotsSync(req types.RequestListSnapshots) (*types.ResponseListSnapshots, error) {
@@ -396,7 +499,7 @@ func (cli *socketClient) ListSnapshotsSync(req types.RequestListSnapshots) (*typ
 		return nil, err
 	}
 
-	return reqres.Response.GetListSnapshots(), cli.Error()
+	return reqres.Response.GetListSnapshots(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) OfferSnapshotSync(req types.RequestOfferSnapshot) (*types.ResponseOfferSnapshot, error) {
@@ -405,7 +508,7 @@ func (cli *socketClient) OfferSnapshotSync(req types.RequestOfferSnapshot) (*typ
 		return nil, err
 	}
 
-	return reqres.Response.GetOfferSnapshot(), cli.Error()
+	return reqres.Response.GetOfferSnapshot(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) LoadSnapshotChunkSync(
@@ -415,7 +518,7 @@ func (cli *socketClient) LoadSnapshotChunkSync(
 		return nil, err
 	}
 
-	return reqres.Response.GetLoadSnapshotChunk(), cli.Error()
+	return reqres.Response.GetLoadSnapshotChunk(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) ApplySnapshotChunkSync(
@@ -424,7 +527,7 @@ func (cli *socketClient) ApplySnapshotChunkSync(
 	if err := cli.FlushSync(); err != nil {
 		return nil, err
 	}
-	return reqres.Response.GetApplySnapshotChunk(), cli.Error()
+	return reqres.Response.GetApplySnapshotChunk(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) PrepareProposalSync(
@@ -435,7 +538,7 @@ func (cli *socketClient) PrepareProposalSync(
 	if err := cli.FlushSync(); err != nil {
 		return nil, err
 	}
-	return reqres.Response.GetPrepareProposal(), nil
+	return reqres.Response.GetPrepareProposal(), cli.reqResError(reqres)
 }
 
 func (cli *socketClient) ProcessProposalSync(
@@ -446,14 +549,29 @@ func (cli *socketClient) ProcessProposalSync(
 	if err := cli.FlushSync(); err != nil {
 		return nil, err
 	}
-	return reqres.Response.GetProcessProposal(), nil
+	return reqres.Response.GetProcessProposal(), cli.reqResError(reqres)
 }
 
 //----------------------------------------
 
 func (cli *socketClient) queueRequest(req *types.Request) *ReqRes {
+	return cli.queueRequestWithContext(context.Background(), req)
+}
+
+// queueRequestWithContext queues req, which fails with the error of ctx once
+// ctx is done, unless it completed before.
+func (cli *socketClient) queueRequestWithContext(ctx context.Context, req *types.Request) *ReqRes {
 	reqres := NewReqRes(req)
 
+	cli.mtx.Lock()
+	reconnecting := cli.reconnecting
+	cli.mtx.Unlock()
+	if reconnecting {
+		reqres.complete(nil, ErrConnectionLost)
+		return reqres
+	}
+	reqres.cancelWith(ctx)
+
 	// TODO: set cli.err if reqQueue times out
 	cli.reqQueue <- reqres
 
@@ -468,28 +586,146 @@ func (cli *socketClient) queueRequest(req *types.Request) *ReqRes {
 	return reqres
 }
 
-func (cli *socketClient) flushQueue() {
+// flushQueue marks all the in-flight and queued requests as failed with err,
+// and resolved.
+func (cli *socketClient) flushQueue(err error) {
 	cli.mtx.Lock()
 	defer cli.mtx.Unlock()
 
-	// mark all in-flight messages as resolved (they will get cli.Error())
+	// mark all in-flight messages as resolved
 	for req := cli.reqSent.Front(); req != nil; req = req.Next() {
 		reqres := req.Value.(*ReqRes)
-		reqres.Done()
+		reqres.complete(nil, err)
 	}
+	cli.reqSent.Init()
 
 	// mark all queued messages as resolved
 LOOP:
 	for {
 		select {
 		case reqres := <-cli.reqQueue:
-			reqres.Done()
+			reqres.complete(nil, err)
 		default:
 			break LOOP
 		}
 	}
 }
 
+// reqResError returns the error reqres failed with, if any, or the client
+// error.
+func (cli *socketClient) reqResError(reqres *ReqRes) error {
+	if reqres.err != nil {
+		return reqres.err
+	}
+	return cli.Error()
+}
+
+// connFailed handles the failure of conn with err: it stops the client, or
+// fails all requests with ErrConnectionLost and reconnects if configured to.
+func (cli *socketClient) connFailed(conn net.Conn, err error) {
+	if cli.reconnect == nil {
+		cli.stopForError(err)
+		return
+	}
+	if !cli.IsRunning() {
+		return
+	}
+
+	cli.mtx.Lock()
+	if cli.reconnecting || cli.conn != conn {
+		// the other routine of conn already handled it
+		cli.mtx.Unlock()
+		return
+	}
+	cli.reconnecting = true
+	cli.err = fmt.Errorf("%w: %v", ErrConnectionLost, err)
+	close(cli.connDone)
+	cli.mtx.Unlock()
+
+	conn.Close()
+	cli.Logger.Error("Lost connection to the application, reconnecting", "err", err)
+	cli.flushQueue(ErrConnectionLost)
+
+	go cli.reconnectRoutine()
+}
+
+// reconnectRoutine dials the application until it reconnects, with
+// exponential backoff, then checks its Info response before resuming.
+func (cli *socketClient) reconnectRoutine() {
+	backoff := cli.reconnect.MinBackoff
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-cli.Quit():
+			return
+		}
+
+		conn, err := cmtnet.Connect(cli.addr)
+		if err == nil {
+			var info *types.ResponseInfo
+			info, err = cli.handshake(conn)
+			if err == nil {
+				if err := cli.reconnect.CheckInfo(*info); err != nil {
+					conn.Close()
+					cli.mtx.Lock()
+					cli.err = nil // so that the check error is the client error
+					cli.mtx.Unlock()
+					cli.stopForError(fmt.Errorf("can't resume with the reconnected application: %w", err))
+					return
+				}
+				if !cli.IsRunning() {
+					conn.Close()
+					return
+				}
+				cli.startConn(conn)
+				cli.Logger.Info("Reconnected to the application", "last_block_height", info.LastBlockHeight)
+				return
+			}
+			conn.Close()
+		}
+
+		backoff *= 2
+		if backoff > cli.reconnect.MaxBackoff {
+			backoff = cli.reconnect.MaxBackoff
+		}
+		cli.Logger.Error("Failed to reconnect to the application", "err", err, "retry_in", backoff)
+	}
+}
+
+// handshake sends an Info request on conn, before its reading and writing
+// goroutines are spawned, and returns the response.
+func (cli *socketClient) handshake(conn net.Conn) (*types.ResponseInfo, error) {
+	if err := conn.SetDeadline(time.Now().Add(reconnectHandshakeTimeout)); err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(conn)
+	if err := types.WriteMessage(types.ToRequestInfo(cli.reconnect.RequestInfo), w); err != nil {
+		return nil, fmt.Errorf("write info request: %w", err)
+	}
+	if err := types.WriteMessage(types.ToRequestFlush(), w); err != nil {
+		return nil, fmt.Errorf("write flush request: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("flush buffer: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	res := &types.Response{}
+	if err := types.ReadMessage(r, res); err != nil {
+		return nil, fmt.Errorf("read info response: %w", err)
+	}
+	info := res.GetInfo()
+	if info == nil {
+		return nil, fmt.Errorf("unexpected %v when info response expected", reflect.TypeOf(res.Value))
+	}
+	if err := types.ReadMessage(r, &types.Response{}); err != nil {
+		return nil, fmt.Errorf("read flush response: %w", err)
+	}
+
+	return info, conn.SetDeadline(time.Time{})
+}
+
 //----------------------------------------
 
 func resMatchesReq(req *types.Request, res *types.Response) (ok bool) {
@@ -1,7 +1,10 @@
 package abcicli_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -187,3 +190,152 @@ func TestCallbackInvokedWhenSetEarly(t *testing.T) {
 	}
 	require.Eventually(t, called, time.Second, time.Millisecond*25)
 }
+
+// heightApp reports height as its last block height.
+type heightApp struct {
+	types.BaseApplication
+	height int64
+}
+
+func (app heightApp) Info(types.RequestInfo) types.ResponseInfo {
+	return types.ResponseInfo{LastBlockHeight: app.height}
+}
+
+func startSocketServer(t *testing.T, addr string, app types.Application) service.Service {
+	s, err := server.NewServer(addr, "socket", app)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	return s
+}
+
+func TestSocketClientReconnects(t *testing.T) {
+	addr := fmt.Sprintf("unix://%s/abci.sock", t.TempDir())
+	s := startSocketServer(t, addr, heightApp{height: 3})
+
+	c := abcicli.NewReconnectingSocketClient(addr, true, abcicli.ReconnectConfig{
+		CheckInfo: func(info types.ResponseInfo) error {
+			if info.LastBlockHeight != 3 {
+				return fmt.Errorf("application at height %d", info.LastBlockHeight)
+			}
+			return nil
+		},
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 50 * time.Millisecond,
+	})
+	require.NoError(t, c.Start())
+	t.Cleanup(func() {
+		if err := c.Stop(); err != nil {
+			t.Log(err)
+		}
+	})
+	_, err := c.CheckTxSync(types.RequestCheckTx{})
+	require.NoError(t, err)
+
+	// kill the app: requests fail fast with a retryable error
+	require.NoError(t, s.Stop())
+	require.Eventually(t, func() bool {
+		return errors.Is(c.Error(), abcicli.ErrConnectionLost)
+	}, time.Second, 5*time.Millisecond)
+	_, err = c.CheckTxSync(types.RequestCheckTx{})
+	require.ErrorIs(t, err, abcicli.ErrConnectionLost)
+
+	// restart it at the same height: the client resumes
+	s = startSocketServer(t, addr, heightApp{height: 3})
+	require.Eventually(t, func() bool {
+		_, err := c.CheckTxSync(types.RequestCheckTx{})
+		return err == nil
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, c.IsRunning())
+
+	// restart it at another height: the client stops
+	require.NoError(t, s.Stop())
+	s = startSocketServer(t, addr, heightApp{height: 2})
+	t.Cleanup(func() {
+		if err := s.Stop(); err != nil {
+			t.Log(err)
+		}
+	})
+	select {
+	case <-c.Quit():
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "client didn't stop")
+	}
+	assert.ErrorContains(t, c.Error(), "application at height 2")
+	assert.NotErrorIs(t, c.Error(), abcicli.ErrConnectionLost)
+}
+
+// deadlineApp reports the deadline of each CheckTx request, then blocks until
+// released.
+type deadlineApp struct {
+	types.BaseApplication
+	deadlines chan *time.Time
+	release   chan struct{}
+}
+
+func (app deadlineApp) CheckTx(req types.RequestCheckTx) types.ResponseCheckTx {
+	app.deadlines <- req.Deadline
+	<-app.release
+	return types.ResponseCheckTx{Code: uint32(len(req.Tx))}
+}
+
+func TestCheckTxWithContextCancelled(t *testing.T) {
+	for _, transport := range []string{"socket", "grpc"} {
+		t.Run(transport, func(t *testing.T) {
+			addr := fmt.Sprintf("unix://%s/abci.sock", t.TempDir())
+			app := deadlineApp{deadlines: make(chan *time.Time, 2), release: make(chan struct{})}
+			s, err := server.NewServer(addr, transport, app)
+			require.NoError(t, err)
+			require.NoError(t, s.Start())
+			c, err := abcicli.NewClient(addr, transport, true)
+			require.NoError(t, err)
+			require.NoError(t, c.Start())
+			t.Cleanup(func() {
+				if err := c.Stop(); err != nil {
+					t.Error(err)
+				}
+				if err := s.Stop(); err != nil {
+					t.Error(err)
+				}
+			})
+			_, err = c.EchoSync("hello") // wait for the server to serve the connection
+			require.NoError(t, err)
+			goroutines := runtime.NumGoroutine()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			errCh := make(chan error, 1)
+			go func() {
+				_, err := c.CheckTxSyncWithContext(ctx, types.RequestCheckTx{Tx: []byte("a")})
+				errCh <- err
+			}()
+
+			// cancel the call while the app checks the tx
+			deadline := <-app.deadlines
+			want, _ := ctx.Deadline()
+			require.NotNil(t, deadline)
+			assert.True(t, want.Equal(*deadline), "%v != %v", want, deadline)
+			cancel()
+			select {
+			case err := <-errCh:
+				require.ErrorIs(t, err, context.Canceled)
+			case <-time.After(time.Second):
+				t.Fatal("the call wasn't cancelled")
+			}
+			_, err = c.CheckTxSyncWithContext(ctx, types.RequestCheckTx{Tx: []byte("b")})
+			require.ErrorIs(t, err, context.Canceled)
+
+			// the late response is discarded and the client keeps working
+			close(app.release)
+			res, err := c.CheckTxSync(types.RequestCheckTx{Tx: []byte("cc")})
+			require.NoError(t, err)
+			assert.EqualValues(t, 2, res.Code)
+			assert.Nil(t, <-app.deadlines)
+			require.NoError(t, c.Error())
+
+			// no goroutine is left behind (Eventually would run one)
+			for i := 0; runtime.NumGoroutine() > goroutines; i++ {
+				require.Less(t, i, 100, "leaked goroutines")
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
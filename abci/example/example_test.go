@@ -45,8 +45,22 @@ func TestGRPC(t *testing.T) {
 	testGRPCSync(t, types.NewGRPCApplication(types.NewBaseApplication()))
 }
 
+func TestKVStoreGRPCStream(t *testing.T) {
+	fmt.Println("### Testing KVStore over GRPC stream")
+	testGRPCStream(t, types.NewGRPCApplication(kvstore.NewApplication()), true)
+}
+
+func TestBaseAppGRPCStream(t *testing.T) {
+	fmt.Println("### Testing BaseApp over GRPC stream")
+	testGRPCStream(t, types.NewGRPCApplication(types.NewBaseApplication()), true)
+}
+
+func TestGRPCStreamFallback(t *testing.T) {
+	fmt.Println("### Testing GRPC stream fallback to unary calls")
+	testGRPCStream(t, unaryGRPCApplication{types.NewGRPCApplication(types.NewBaseApplication())}, false)
+}
+
 func testStream(t *testing.T, app types.Application) {
-	numDeliverTxs := 20000
 	socketFile := fmt.Sprintf("test-%08x.sock", rand.Int31n(1<<30))
 	defer os.Remove(socketFile)
 	socket := fmt.Sprintf("unix://%v", socketFile)
@@ -75,6 +89,13 @@ func testStream(t *testing.T, app types.Application) {
 		}
 	})
 
+	testDeliverTxs(t, client)
+}
+
+// testDeliverTxs sends DeliverTx requests interleaved with flushes through
+// client, and checks that all of them succeed.
+func testDeliverTxs(t *testing.T, client abcicli.Client) {
+	numDeliverTxs := 20000
 	done := make(chan struct{})
 	counter := 0
 	client.SetResponseCallback(func(req *types.Request, res *types.Response) {
@@ -185,3 +206,57 @@ func testGRPCSync(t *testing.T, app types.ABCIApplicationServer) {
 
 	}
 }
+
+// unaryGRPCApplication doesn't advertise support for streaming.
+type unaryGRPCApplication struct {
+	*types.GRPCApplication
+}
+
+func (app unaryGRPCApplication) Info(ctx context.Context, req *types.RequestInfo) (*types.ResponseInfo, error) {
+	res, err := app.GRPCApplication.Info(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	res.Streaming = false
+	return res, nil
+}
+
+func testGRPCStream(t *testing.T, app types.ABCIApplicationServer, streaming bool) {
+	socketFile := fmt.Sprintf("/tmp/test-%08x.sock", rand.Int31n(1<<30))
+	defer os.Remove(socketFile)
+	socket := fmt.Sprintf("unix://%v", socketFile)
+
+	// Start the listener
+	server := abciserver.NewGRPCServer(socket, app)
+	server.SetLogger(log.TestingLogger().With("module", "abci-server"))
+	require.NoError(t, server.Start(), "Error starting GRPC server")
+	t.Cleanup(func() {
+		if err := server.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Connect to the socket
+	client := abcicli.NewStreamingGRPCClient(socket, true)
+	client.SetLogger(log.TestingLogger().With("module", "abci-client"))
+	require.NoError(t, client.Start(), "Error starting GRPC client")
+	t.Cleanup(func() {
+		if err := client.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	info, err := client.InfoSync(types.RequestInfo{})
+	require.NoError(t, err)
+	require.Equal(t, streaming, info.Streaming)
+
+	testDeliverTxs(t, client)
+	client.SetResponseCallback(nil)
+
+	// sync calls keep working after the async ones
+	res, err := client.CheckTxSync(types.RequestCheckTx{Tx: []byte("test")})
+	require.NoError(t, err)
+	require.Equal(t, code.CodeTypeOK, res.Code)
+	require.NoError(t, client.FlushSync())
+	require.NoError(t, client.Error())
+}
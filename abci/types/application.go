@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+	"io"
+
 	context "golang.org/x/net/context"
 )
 
@@ -33,6 +36,15 @@ type Application interface {
 	ApplySnapshotChunk(RequestApplySnapshotChunk) ResponseApplySnapshotChunk // Apply a shapshot chunk
 }
 
+// ConcurrentQuerier is an optional interface for applications whose Info and
+// Query methods, when ConcurrentQueries returns true, are safe to call
+// concurrently with one another and with any other method, in particular
+// the ones of the consensus connection. In-process clients then serve them
+// without waiting for block execution.
+type ConcurrentQuerier interface {
+	ConcurrentQueries() bool
+}
+
 //-------------------------------------------------------
 // BaseApplication is a base form of Application
 
@@ -126,6 +138,7 @@ func (app *GRPCApplication) Flush(ctx context.Context, req *RequestFlush) (*Resp
 
 func (app *GRPCApplication) Info(ctx context.Context, req *RequestInfo) (*ResponseInfo, error) {
 	res := app.app.Info(*req)
+	res.Streaming = true
 	return &res, nil
 }
 
@@ -204,3 +217,62 @@ func (app *GRPCApplication) ProcessProposal(
 	res := app.app.ProcessProposal(*req)
 	return &res, nil
 }
+
+// Stream serves the requests received on the stream one at a time, sending
+// back each response in the order the requests came in.
+func (app *GRPCApplication) Stream(stream ABCIApplication_StreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(app.handleRequest(stream.Context(), req)); err != nil {
+			return err
+		}
+	}
+}
+
+func (app *GRPCApplication) handleRequest(ctx context.Context, req *Request) *Response {
+	switch r := req.Value.(type) {
+	case *Request_Echo:
+		return ToResponseEcho(r.Echo.Message)
+	case *Request_Flush:
+		return ToResponseFlush()
+	case *Request_Info:
+		res, _ := app.Info(ctx, r.Info)
+		return ToResponseInfo(*res)
+	case *Request_SetOption:
+		return ToResponseSetOption(app.app.SetOption(*r.SetOption))
+	case *Request_DeliverTx:
+		return ToResponseDeliverTx(app.app.DeliverTx(*r.DeliverTx))
+	case *Request_CheckTx:
+		return ToResponseCheckTx(app.app.CheckTx(*r.CheckTx))
+	case *Request_Commit:
+		return ToResponseCommit(app.app.Commit())
+	case *Request_Query:
+		return ToResponseQuery(app.app.Query(*r.Query))
+	case *Request_InitChain:
+		return ToResponseInitChain(app.app.InitChain(*r.InitChain))
+	case *Request_BeginBlock:
+		return ToResponseBeginBlock(app.app.BeginBlock(*r.BeginBlock))
+	case *Request_EndBlock:
+		return ToResponseEndBlock(app.app.EndBlock(*r.EndBlock))
+	case *Request_ListSnapshots:
+		return ToResponseListSnapshots(app.app.ListSnapshots(*r.ListSnapshots))
+	case *Request_OfferSnapshot:
+		return ToResponseOfferSnapshot(app.app.OfferSnapshot(*r.OfferSnapshot))
+	case *Request_LoadSnapshotChunk:
+		return ToResponseLoadSnapshotChunk(app.app.LoadSnapshotChunk(*r.LoadSnapshotChunk))
+	case *Request_ApplySnapshotChunk:
+		return ToResponseApplySnapshotChunk(app.app.ApplySnapshotChunk(*r.ApplySnapshotChunk))
+	case *Request_PrepareProposal:
+		return ToResponsePrepareProposal(app.app.PrepareProposal(*r.PrepareProposal))
+	case *Request_ProcessProposal:
+		return ToResponseProcessProposal(app.app.ProcessProposal(*r.ProcessProposal))
+	default:
+		return ToResponseException(fmt.Sprintf("unknown request type %T", req.Value))
+	}
+}
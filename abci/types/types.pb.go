@@ -935,8 +935,9 @@ func (m *RequestBeginBlock) GetByzantineValidators() []Evidence {
 }
 
 type RequestCheckTx struct {
-	Tx   []byte      `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
-	Type CheckTxType `protobuf:"varint,2,opt,name=type,proto3,enum=tendermint.abci.CheckTxType" json:"type,omitempty"`
+	Tx       []byte      `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	Type     CheckTxType `protobuf:"varint,2,opt,name=type,proto3,enum=tendermint.abci.CheckTxType" json:"type,omitempty"`
+	Deadline *time.Time  `protobuf:"bytes,3,opt,name=deadline,proto3,stdtime" json:"deadline,omitempty"`
 }
 
 func (m *RequestCheckTx) Reset()         { *m = RequestCheckTx{} }
@@ -986,6 +987,13 @@ func (m *RequestCheckTx) GetType() CheckTxType {
 	return CheckTxType_New
 }
 
+func (m *RequestCheckTx) GetDeadline() *time.Time {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
 type RequestDeliverTx struct {
 	Tx []byte `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
 }
@@ -1893,6 +1901,7 @@ type ResponseInfo struct {
 	LastBlockHeight  int64        `protobuf:"varint,4,opt,name=last_block_height,json=lastBlockHeight,proto3" json:"last_block_height,omitempty"`
 	LastBlockAppHash []byte       `protobuf:"bytes,5,opt,name=last_block_app_hash,json=lastBlockAppHash,proto3" json:"last_block_app_hash,omitempty"`
 	Timeouts         TimeoutsInfo `protobuf:"bytes,6,opt,name=timeouts,proto3" json:"timeouts"`
+	Streaming        bool         `protobuf:"varint,7,opt,name=streaming,proto3" json:"streaming,omitempty"`
 }
 
 func (m *ResponseInfo) Reset()         { *m = ResponseInfo{} }
@@ -1970,6 +1979,13 @@ func (m *ResponseInfo) GetTimeouts() TimeoutsInfo {
 	return TimeoutsInfo{}
 }
 
+func (m *ResponseInfo) GetStreaming() bool {
+	if m != nil {
+		return m.Streaming
+	}
+	return false
+}
+
 // nondeterministic
 type ResponseSetOption struct {
 	Code uint32 `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
@@ -3495,11 +3511,12 @@ func (m *Evidence) GetTotalVotingPower() int64 {
 }
 
 type Snapshot struct {
-	Height   uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
-	Format   uint32 `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
-	Chunks   uint32 `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks,omitempty"`
-	Hash     []byte `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
-	Metadata []byte `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Height      uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Format      uint32   `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
+	Chunks      uint32   `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks,omitempty"`
+	Hash        []byte   `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
+	Metadata    []byte   `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	ChunkHashes [][]byte `protobuf:"bytes,6,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"`
 }
 
 func (m *Snapshot) Reset()         { *m = Snapshot{} }
@@ -3570,6 +3587,13 @@ func (m *Snapshot) GetMetadata() []byte {
 	return nil
 }
 
+func (m *Snapshot) GetChunkHashes() [][]byte {
+	if m != nil {
+		return m.ChunkHashes
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("tendermint.abci.CheckTxType", CheckTxType_name, CheckTxType_value)
 	proto.RegisterEnum("tendermint.abci.EvidenceType", EvidenceType_name, EvidenceType_value)
@@ -3630,201 +3654,206 @@ func init() {
 func init() { proto.RegisterFile("tendermint/abci/types.proto", fileDescriptor_252557cfdd89a31a) }
 
 var fileDescriptor_252557cfdd89a31a = []byte{
-	// 3101 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x5a, 0x4b, 0x73, 0xdc, 0xc6,
-	0xf1, 0x5f, 0xec, 0x1b, 0xbd, 0xdc, 0x07, 0x47, 0xb2, 0xb4, 0x82, 0x65, 0x52, 0x86, 0xca, 0x2f,
-	0xd9, 0x26, 0xff, 0xa6, 0x4b, 0xfe, 0xdb, 0xb1, 0x13, 0x9b, 0x5c, 0xad, 0xbc, 0xb4, 0x68, 0x92,
-	0x01, 0x57, 0x72, 0x5e, 0x16, 0x0c, 0x2e, 0x86, 0xbb, 0xb0, 0x76, 0x01, 0x18, 0xc0, 0xd2, 0xa4,
-	0x8e, 0xa9, 0xa4, 0x52, 0xf1, 0xc9, 0x55, 0xb9, 0xf8, 0xe2, 0x7b, 0x2e, 0xf9, 0x08, 0xc9, 0xd9,
-	0x55, 0x49, 0x55, 0x7c, 0xcc, 0x21, 0xe5, 0xa4, 0xec, 0x9c, 0xf2, 0x05, 0x52, 0x39, 0xa4, 0x92,
-	0x9a, 0x07, 0x9e, 0xbb, 0xe0, 0x82, 0x72, 0x6e, 0xb9, 0xcd, 0xf4, 0x74, 0xf7, 0xcc, 0x34, 0x66,
-	0xba, 0xfb, 0xd7, 0x18, 0x78, 0xdc, 0xc3, 0xa6, 0x8e, 0x9d, 0x89, 0x61, 0x7a, 0xeb, 0xda, 0xe1,
-	0xc0, 0x58, 0xf7, 0x4e, 0x6d, 0xec, 0xae, 0xd9, 0x8e, 0xe5, 0x59, 0xa8, 0x19, 0x0e, 0xae, 0x91,
-	0x41, 0xe9, 0x89, 0x08, 0xf7, 0xc0, 0x39, 0xb5, 0x3d, 0x6b, 0xdd, 0x76, 0x2c, 0xeb, 0x88, 0xf1,
-	0x4b, 0x57, 0x23, 0xc3, 0x54, 0x4f, 0x54, 0x5b, 0x6c, 0x94, 0x0b, 0x3f, 0xc0, 0xa7, 0xfe, 0xe8,
-	0x13, 0x33, 0xb2, 0xb6, 0xe6, 0x68, 0x13, 0x7f, 0x78, 0x75, 0x68, 0x59, 0xc3, 0x31, 0x5e, 0xa7,
-	0xbd, 0xc3, 0xe9, 0xd1, 0xba, 0x67, 0x4c, 0xb0, 0xeb, 0x69, 0x13, 0x9b, 0x33, 0x5c, 0x1c, 0x5a,
-	0x43, 0x8b, 0x36, 0xd7, 0x49, 0x8b, 0x53, 0x57, 0x92, 0x62, 0xfa, 0xd4, 0xd1, 0x3c, 0xc3, 0x32,
-	0xd9, 0xb8, 0xfc, 0x5b, 0x11, 0x2a, 0x0a, 0xfe, 0x68, 0x8a, 0x5d, 0x0f, 0x6d, 0x40, 0x11, 0x0f,
-	0x46, 0x56, 0x5b, 0xb8, 0x26, 0x3c, 0x5b, 0xdb, 0xb8, 0xba, 0x96, 0xd8, 0xfc, 0x1a, 0xe7, 0xeb,
-	0x0e, 0x46, 0x56, 0x2f, 0xa7, 0x50, 0x5e, 0x74, 0x13, 0x4a, 0x47, 0xe3, 0xa9, 0x3b, 0x6a, 0xe7,
-	0xa9, 0xd0, 0x13, 0x69, 0x42, 0xb7, 0x09, 0x53, 0x2f, 0xa7, 0x30, 0x6e, 0x32, 0x95, 0x61, 0x1e,
-	0x59, 0xed, 0xc2, 0xd9, 0x53, 0x6d, 0x9b, 0x47, 0x74, 0x2a, 0xc2, 0x8b, 0xb6, 0x00, 0x5c, 0xec,
-	0xa9, 0x96, 0x4d, 0x96, 0xdf, 0x2e, 0x52, 0xc9, 0x27, 0xd3, 0x24, 0x0f, 0xb0, 0xb7, 0x47, 0x19,
-	0x7b, 0x39, 0x45, 0x74, 0xfd, 0x0e, 0xd1, 0x61, 0x98, 0x86, 0xa7, 0x0e, 0x46, 0x9a, 0x61, 0xb6,
-	0x4b, 0x67, 0xeb, 0xd8, 0x36, 0x0d, 0xaf, 0x43, 0x18, 0x89, 0x0e, 0xc3, 0xef, 0x90, 0x2d, 0x7f,
-	0x34, 0xc5, 0xce, 0x69, 0xbb, 0x7c, 0xf6, 0x96, 0xbf, 0x4f, 0x98, 0xc8, 0x96, 0x29, 0x37, 0xea,
-	0x42, 0xed, 0x10, 0x0f, 0x0d, 0x53, 0x3d, 0x1c, 0x5b, 0x83, 0x07, 0xed, 0x0a, 0x15, 0x96, 0xd3,
-	0x84, 0xb7, 0x08, 0xeb, 0x16, 0xe1, 0xec, 0xe5, 0x14, 0x38, 0x0c, 0x7a, 0xe8, 0x0d, 0xa8, 0x0e,
-	0x46, 0x78, 0xf0, 0x40, 0xf5, 0x4e, 0xda, 0x55, 0xaa, 0x63, 0x35, 0x4d, 0x47, 0x87, 0xf0, 0xf5,
-	0x4f, 0x7a, 0x39, 0xa5, 0x32, 0x60, 0x4d, 0xb2, 0x7f, 0x1d, 0x8f, 0x8d, 0x63, 0xec, 0x10, 0x79,
-	0xf1, 0xec, 0xfd, 0xdf, 0x62, 0x9c, 0x54, 0x83, 0xa8, 0xfb, 0x1d, 0xf4, 0x26, 0x88, 0xd8, 0xd4,
-	0xf9, 0x36, 0x80, 0xaa, 0xb8, 0x96, 0x7a, 0x56, 0x4c, 0xdd, 0xdf, 0x44, 0x15, 0xf3, 0x36, 0x7a,
-	0x15, 0xca, 0x03, 0x6b, 0x32, 0x31, 0xbc, 0x76, 0x8d, 0x4a, 0xaf, 0xa4, 0x6e, 0x80, 0x72, 0xf5,
-	0x72, 0x0a, 0xe7, 0x47, 0xbb, 0xd0, 0x18, 0x1b, 0xae, 0xa7, 0xba, 0xa6, 0x66, 0xbb, 0x23, 0xcb,
-	0x73, 0xdb, 0x4b, 0x54, 0xc3, 0x53, 0x69, 0x1a, 0x76, 0x0c, 0xd7, 0x3b, 0xf0, 0x99, 0x7b, 0x39,
-	0xa5, 0x3e, 0x8e, 0x12, 0x88, 0x3e, 0xeb, 0xe8, 0x08, 0x3b, 0x81, 0xc2, 0x76, 0xfd, 0x6c, 0x7d,
-	0x7b, 0x84, 0xdb, 0x97, 0x27, 0xfa, 0xac, 0x28, 0x01, 0xfd, 0x18, 0x2e, 0x8c, 0x2d, 0x4d, 0x0f,
-	0xd4, 0xa9, 0x83, 0xd1, 0xd4, 0x7c, 0xd0, 0x6e, 0x50, 0xa5, 0xcf, 0xa5, 0x2e, 0xd2, 0xd2, 0x74,
-	0x5f, 0x45, 0x87, 0x08, 0xf4, 0x72, 0xca, 0xf2, 0x38, 0x49, 0x44, 0xf7, 0xe1, 0xa2, 0x66, 0xdb,
-	0xe3, 0xd3, 0xa4, 0xf6, 0x26, 0xd5, 0x7e, 0x23, 0x4d, 0xfb, 0x26, 0x91, 0x49, 0xaa, 0x47, 0xda,
-	0x0c, 0x15, 0xf5, 0xa1, 0x65, 0x3b, 0xd8, 0xd6, 0x1c, 0xac, 0xda, 0x8e, 0x65, 0x5b, 0xae, 0x36,
-	0x6e, 0xb7, 0xa8, 0xee, 0x67, 0xd2, 0x74, 0xef, 0x33, 0xfe, 0x7d, 0xce, 0xde, 0xcb, 0x29, 0x4d,
-	0x3b, 0x4e, 0x62, 0x5a, 0xad, 0x01, 0x76, 0xdd, 0x50, 0xeb, 0xf2, 0x22, 0xad, 0x94, 0x3f, 0xae,
-	0x35, 0x46, 0xda, 0xaa, 0x40, 0xe9, 0x58, 0x1b, 0x4f, 0xb1, 0xfc, 0x0c, 0xd4, 0x22, 0x6e, 0x09,
-	0xb5, 0xa1, 0x32, 0xc1, 0xae, 0xab, 0x0d, 0x31, 0xf5, 0x62, 0xa2, 0xe2, 0x77, 0xe5, 0x06, 0x2c,
-	0x45, 0x5d, 0x91, 0xfc, 0x6b, 0x01, 0x96, 0xfa, 0xc6, 0x04, 0x5b, 0x53, 0xcf, 0x25, 0x6e, 0x06,
-	0xed, 0x40, 0xd3, 0x63, 0x7d, 0xbe, 0x50, 0xcc, 0x1d, 0xe1, 0x95, 0x35, 0xe6, 0x43, 0xd7, 0x7c,
-	0x1f, 0xba, 0x76, 0x8b, 0xfb, 0xd0, 0xad, 0xea, 0x17, 0x5f, 0xad, 0xe6, 0x3e, 0xfb, 0xcb, 0xaa,
-	0xa0, 0x34, 0xb8, 0x2c, 0x5b, 0x21, 0x46, 0xef, 0x80, 0x4f, 0x51, 0xf9, 0x59, 0xcf, 0x67, 0x57,
-	0x56, 0xe7, 0xa2, 0xec, 0xfc, 0xcb, 0x93, 0x60, 0x8f, 0x74, 0xa1, 0x6d, 0xa8, 0x1c, 0x63, 0xc7,
-	0x25, 0x4e, 0x90, 0xef, 0x91, 0x77, 0xd1, 0x75, 0xa8, 0xd3, 0x5b, 0xa9, 0xfa, 0xe3, 0x64, 0xce,
-	0xa2, 0xb2, 0x44, 0x89, 0xf7, 0x38, 0xd3, 0x2a, 0xd4, 0xec, 0x0d, 0x3b, 0x60, 0x29, 0x50, 0x16,
-	0xb0, 0x37, 0x6c, 0xce, 0x20, 0x7f, 0x07, 0x5a, 0x49, 0x27, 0x8a, 0x5a, 0x50, 0x78, 0x80, 0x4f,
-	0xf9, 0x7c, 0xa4, 0x89, 0x2e, 0xf2, 0x2f, 0x40, 0xe7, 0x10, 0x15, 0xfe, 0x39, 0x7e, 0x9f, 0x0f,
-	0x84, 0x03, 0xef, 0x89, 0x5e, 0x85, 0x22, 0xd9, 0x10, 0x37, 0xa7, 0x34, 0x63, 0x81, 0xbe, 0x1f,
-	0xc9, 0x98, 0x09, 0x3e, 0x25, 0x26, 0xa0, 0x12, 0xe8, 0x0a, 0x71, 0x76, 0x9a, 0x61, 0xaa, 0x86,
-	0xce, 0xe7, 0xa9, 0xd0, 0xfe, 0xb6, 0x8e, 0xee, 0x40, 0x6b, 0x60, 0x99, 0x2e, 0x36, 0xdd, 0xa9,
-	0xab, 0xb2, 0x48, 0xc9, 0xa3, 0xc9, 0xac, 0x33, 0xea, 0xf8, 0x8c, 0xfb, 0x94, 0x4f, 0x69, 0x0e,
-	0xe2, 0x04, 0x74, 0x1b, 0xe0, 0x58, 0x1b, 0x1b, 0xba, 0xe6, 0x59, 0x8e, 0xdb, 0x2e, 0x5e, 0x2b,
-	0xcc, 0x55, 0x73, 0xcf, 0x67, 0xb9, 0x6b, 0xeb, 0x9a, 0x87, 0xb7, 0x8a, 0x64, 0xb5, 0x4a, 0x44,
-	0x12, 0x3d, 0x0d, 0x4d, 0xcd, 0xb6, 0x55, 0xd7, 0xd3, 0x3c, 0xac, 0x1e, 0x9e, 0x7a, 0xd8, 0xa5,
-	0x31, 0x66, 0x49, 0xa9, 0x6b, 0xb6, 0x7d, 0x40, 0xa8, 0x5b, 0x84, 0x88, 0x9e, 0x82, 0x06, 0x89,
-	0x27, 0x86, 0x36, 0x56, 0x47, 0xd8, 0x18, 0x8e, 0x3c, 0x1a, 0x4b, 0x0a, 0x4a, 0x9d, 0x53, 0x7b,
-	0x94, 0x28, 0xeb, 0xc1, 0x99, 0xa5, 0xb1, 0x04, 0x21, 0x28, 0xea, 0x9a, 0xa7, 0x51, 0x43, 0x2e,
-	0x29, 0xb4, 0x4d, 0x68, 0xb6, 0xe6, 0x8d, 0xb8, 0x79, 0x68, 0x1b, 0x5d, 0x82, 0x32, 0x57, 0x5b,
-	0xa0, 0x6a, 0x79, 0x8f, 0x7c, 0x33, 0xdb, 0xb1, 0x8e, 0x31, 0x0d, 0x9e, 0x55, 0x85, 0x75, 0xe4,
-	0x9f, 0xe5, 0x61, 0x79, 0x26, 0xea, 0x10, 0xbd, 0x23, 0xcd, 0x1d, 0xf9, 0x73, 0x91, 0x36, 0x7a,
-	0x85, 0xe8, 0xd5, 0x74, 0xec, 0xf0, 0xc3, 0xdc, 0x8e, 0x9a, 0x88, 0x65, 0x3a, 0x3d, 0x3a, 0xce,
-	0x4d, 0xc3, 0xb9, 0xd1, 0x1e, 0xb4, 0xc6, 0x9a, 0xeb, 0xdf, 0x04, 0x35, 0x12, 0xf9, 0x67, 0x63,
-	0xd7, 0x8e, 0xe6, 0xfb, 0x7d, 0x72, 0xd8, 0xb9, 0xa2, 0xc6, 0x38, 0x46, 0x45, 0x0a, 0x5c, 0x3c,
-	0x3c, 0x7d, 0xa8, 0x99, 0x9e, 0x61, 0x62, 0x75, 0xe6, 0xcb, 0x5d, 0x99, 0x51, 0xda, 0x3d, 0x36,
-	0x74, 0x6c, 0x0e, 0xfc, 0x4f, 0x76, 0x21, 0x10, 0x0e, 0x3e, 0xa9, 0x2b, 0x2b, 0xd0, 0x88, 0xc7,
-	0x4d, 0xd4, 0x80, 0xbc, 0x77, 0xc2, 0x0d, 0x90, 0xf7, 0x4e, 0xd0, 0xff, 0x41, 0x91, 0x6c, 0x92,
-	0x6e, 0xbe, 0x31, 0x27, 0x69, 0xe1, 0x72, 0xfd, 0x53, 0x1b, 0x2b, 0x94, 0x53, 0x96, 0x83, 0xdb,
-	0x10, 0xc4, 0xd2, 0xa4, 0x56, 0xf9, 0x39, 0x68, 0x26, 0x82, 0x65, 0xe4, 0xfb, 0x09, 0xd1, 0xef,
-	0x27, 0x37, 0xa1, 0x1e, 0x8b, 0x8c, 0xf2, 0x25, 0xb8, 0x38, 0x2f, 0xd0, 0xc9, 0xbf, 0x14, 0x82,
-	0x81, 0x58, 0xc4, 0x42, 0x37, 0xa1, 0x1a, 0x84, 0x3a, 0xdf, 0xbb, 0x25, 0xb7, 0xe1, 0x33, 0x2b,
-	0x01, 0x2b, 0xb9, 0x87, 0xe4, 0x5c, 0xd3, 0x03, 0x91, 0xa7, 0x2b, 0xaf, 0x68, 0xb6, 0xdd, 0x23,
-	0x67, 0x62, 0x15, 0x6a, 0x64, 0x28, 0xe1, 0x4e, 0x34, 0x3b, 0x70, 0x27, 0x1f, 0x40, 0x3b, 0x2d,
-	0xce, 0x25, 0x36, 0x5a, 0x0c, 0x0e, 0xea, 0x25, 0x28, 0x1f, 0x59, 0xce, 0x44, 0x63, 0x5e, 0xb3,
-	0xae, 0xf0, 0x1e, 0x39, 0xc0, 0x2c, 0xe6, 0x15, 0x28, 0x99, 0x75, 0x64, 0x15, 0xae, 0xa4, 0xc6,
-	0x3a, 0x22, 0x62, 0x98, 0x3a, 0x66, 0x16, 0xaf, 0x2b, 0xac, 0x13, 0x2a, 0x62, 0xbb, 0x61, 0x1d,
-	0x32, 0xad, 0x4b, 0x8d, 0x41, 0xf5, 0x8b, 0x0a, 0xef, 0xc9, 0x7f, 0x13, 0xe0, 0xd2, 0xfc, 0x88,
-	0x87, 0x6e, 0x02, 0x30, 0x97, 0x1b, 0x5c, 0xcc, 0xda, 0xc6, 0xa5, 0xd9, 0x6b, 0x71, 0x4b, 0xf3,
-	0x34, 0x45, 0xa4, 0x9c, 0xa4, 0x49, 0x1c, 0x45, 0x28, 0xa6, 0xba, 0xc6, 0x43, 0x76, 0xaa, 0x0a,
-	0x4a, 0x3d, 0xe0, 0x39, 0x30, 0x1e, 0xc6, 0x1d, 0x60, 0x21, 0xee, 0x00, 0x43, 0xdb, 0x15, 0x63,
-	0x97, 0xdc, 0xf7, 0xb6, 0xa5, 0xf3, 0x7a, 0x5b, 0xf9, 0x17, 0xd1, 0x6d, 0xc6, 0xe2, 0x6d, 0xe4,
-	0xe6, 0x0b, 0xe7, 0xba, 0xf9, 0x71, 0xf3, 0xe4, 0x33, 0x9a, 0x47, 0xfe, 0x15, 0x40, 0x55, 0xc1,
-	0xae, 0x4d, 0xdc, 0x34, 0xda, 0x02, 0x11, 0x9f, 0x0c, 0x30, 0x4b, 0xfb, 0x85, 0xd4, 0xb4, 0x99,
-	0x71, 0x77, 0x7d, 0x4e, 0x92, 0xb3, 0x06, 0x62, 0xe8, 0x65, 0x0e, 0x6d, 0xd2, 0x51, 0x0a, 0x17,
-	0x8f, 0x62, 0x9b, 0x57, 0x7c, 0x6c, 0x53, 0x48, 0x4d, 0x53, 0x99, 0x54, 0x02, 0xdc, 0xbc, 0xcc,
-	0xc1, 0x4d, 0x71, 0xc1, 0x64, 0x31, 0x74, 0xd3, 0x89, 0xa1, 0x9b, 0xd2, 0x82, 0x6d, 0xa6, 0xc0,
-	0x9b, 0x4e, 0x0c, 0xde, 0x94, 0x17, 0x28, 0x49, 0xc1, 0x37, 0xaf, 0xf8, 0xf8, 0xa6, 0xb2, 0x60,
-	0xdb, 0x09, 0x80, 0x73, 0x3b, 0x0e, 0x70, 0x18, 0x38, 0xb9, 0x9e, 0x2a, 0x9d, 0x8a, 0x70, 0xbe,
-	0x1b, 0x41, 0x38, 0x62, 0x2a, 0xbc, 0x60, 0x4a, 0xe6, 0x40, 0x9c, 0x4e, 0x0c, 0xe2, 0xc0, 0x02,
-	0x1b, 0xa4, 0x60, 0x9c, 0xb7, 0xa2, 0x18, 0xa7, 0x96, 0x0a, 0x93, 0xf8, 0xa1, 0x99, 0x07, 0x72,
-	0x5e, 0x0b, 0x40, 0xce, 0x52, 0x2a, 0x4a, 0xe3, 0x7b, 0x48, 0xa2, 0x9c, 0xbd, 0x19, 0x94, 0xc3,
-	0x50, 0xc9, 0xd3, 0xa9, 0x2a, 0x16, 0xc0, 0x9c, 0xbd, 0x19, 0x98, 0xd3, 0x58, 0xa0, 0x70, 0x01,
-	0xce, 0xf9, 0xc9, 0x7c, 0x9c, 0x93, 0x8e, 0x44, 0xf8, 0x32, 0xb3, 0x01, 0x1d, 0x35, 0x05, 0xe8,
-	0x30, 0x30, 0xf2, 0x7c, 0xaa, 0xfa, 0xcc, 0x48, 0xe7, 0xee, 0x1c, 0xa4, 0xc3, 0x30, 0xc9, 0xb3,
-	0xa9, 0xca, 0x33, 0x40, 0x9d, 0xbb, 0x73, 0xa0, 0x0e, 0x5a, 0xa8, 0x36, 0x3b, 0xd6, 0x79, 0x8e,
-	0xe4, 0x69, 0x09, 0x37, 0x47, 0x22, 0x19, 0x76, 0x1c, 0xcb, 0xe1, 0xb9, 0x39, 0xeb, 0xc8, 0xcf,
-	0x92, 0xcc, 0x31, 0x74, 0x69, 0x67, 0xe0, 0x22, 0x9a, 0x53, 0x44, 0xdc, 0x98, 0xfc, 0x4f, 0x21,
-	0x94, 0xa5, 0xc9, 0x56, 0x34, 0xeb, 0x14, 0x79, 0xd6, 0x19, 0xc1, 0x20, 0xf9, 0x38, 0x06, 0x59,
-	0x94, 0x0f, 0xa0, 0x1b, 0xb0, 0x4c, 0x93, 0x41, 0x16, 0x17, 0x62, 0x21, 0xac, 0x49, 0x06, 0xd8,
-	0x55, 0x62, 0xb1, 0xec, 0x45, 0xb8, 0x10, 0xe1, 0x0d, 0x52, 0x10, 0x96, 0x53, 0xb7, 0x02, 0xee,
-	0x4d, 0x9e, 0x8b, 0xbc, 0x09, 0x55, 0x8e, 0x9c, 0xdc, 0xd4, 0xe2, 0x4c, 0x14, 0xf3, 0xf1, 0x60,
-	0x15, 0x08, 0xc9, 0xef, 0x86, 0x16, 0x0e, 0xb1, 0x0f, 0x82, 0xe2, 0xc0, 0xd2, 0x31, 0x4f, 0x20,
-	0x68, 0x9b, 0xe0, 0xa1, 0xb1, 0x35, 0xe4, 0x21, 0x99, 0x34, 0x09, 0x57, 0xe0, 0xf4, 0x45, 0xe6,
-	0xd3, 0x79, 0x66, 0x9d, 0x70, 0xb6, 0x73, 0x91, 0x8b, 0xf0, 0xdf, 0x41, 0x2e, 0xf9, 0x47, 0x46,
-	0x2e, 0xd1, 0x0c, 0xaf, 0x10, 0xcf, 0xf0, 0xa2, 0x56, 0x2d, 0x3e, 0x8a, 0x55, 0xff, 0x21, 0x84,
-	0x67, 0x2c, 0x00, 0x32, 0x8f, 0x66, 0xd2, 0x30, 0x9d, 0x2b, 0xd1, 0x13, 0xc3, 0xd3, 0x39, 0x0e,
-	0x4f, 0xcb, 0x74, 0xe1, 0x71, 0x78, 0x5a, 0x61, 0x09, 0x1e, 0xed, 0xa0, 0x57, 0x41, 0xa4, 0xe5,
-	0x5a, 0xd5, 0xb2, 0x5d, 0x1e, 0xa0, 0x1e, 0x8f, 0xee, 0x85, 0x55, 0x65, 0xd7, 0xf6, 0x09, 0xcf,
-	0x9e, 0xed, 0x2a, 0x55, 0x9b, 0xb7, 0x22, 0xd9, 0x96, 0x18, 0xcb, 0xb6, 0xae, 0x82, 0x48, 0x56,
-	0xef, 0xda, 0xda, 0x00, 0xd3, 0x60, 0x23, 0x2a, 0x21, 0x41, 0xbe, 0x0f, 0x68, 0x36, 0xdc, 0xa1,
-	0x1e, 0x94, 0xf1, 0x31, 0x36, 0x3d, 0xf2, 0xd9, 0x0b, 0xc9, 0x84, 0x88, 0xe3, 0x15, 0x6c, 0x7a,
-	0x5b, 0x6d, 0x62, 0xc7, 0xbf, 0x7f, 0xb5, 0xda, 0x62, 0xdc, 0x2f, 0x58, 0x13, 0xc3, 0xc3, 0x13,
-	0xdb, 0x3b, 0x55, 0xb8, 0xbc, 0xfc, 0xe7, 0x3c, 0x01, 0x0f, 0xb1, 0x50, 0x38, 0xd7, 0xb6, 0xfe,
-	0x15, 0xce, 0x47, 0x80, 0x63, 0x36, 0x7b, 0xaf, 0x00, 0x0c, 0x35, 0x57, 0xfd, 0x58, 0x33, 0x3d,
-	0xac, 0x73, 0xa3, 0x47, 0x28, 0x48, 0x82, 0x2a, 0xe9, 0x4d, 0x5d, 0xac, 0x73, 0x0c, 0x1b, 0xf4,
-	0x23, 0xfb, 0xac, 0x7c, 0xbb, 0x7d, 0xc6, 0xad, 0x5c, 0x4d, 0x58, 0x39, 0x92, 0xb6, 0x8b, 0xd1,
-	0xb4, 0x9d, 0xac, 0xcd, 0x76, 0x0c, 0xcb, 0x31, 0xbc, 0x53, 0xfa, 0x69, 0x0a, 0x4a, 0xd0, 0x47,
-	0xd7, 0xa1, 0x3e, 0xc1, 0x13, 0xdb, 0xb2, 0xc6, 0x2a, 0x73, 0x9f, 0x35, 0x2a, 0xba, 0xc4, 0x89,
-	0x5d, 0xea, 0x45, 0x7f, 0x1e, 0xb9, 0xbf, 0x21, 0x80, 0xfb, 0x9f, 0x33, 0xb0, 0xfc, 0x07, 0x5a,
-	0xd5, 0x89, 0x27, 0x3b, 0xe8, 0x00, 0x96, 0x03, 0xff, 0xa1, 0x4e, 0xa9, 0x5f, 0xf1, 0x0f, 0x74,
-	0x56, 0x07, 0xd4, 0x3a, 0x8e, 0x93, 0x5d, 0xf4, 0x03, 0xb8, 0x9c, 0xf0, 0x8d, 0x81, 0xea, 0x7c,
-	0x46, 0x17, 0xf9, 0x58, 0xdc, 0x45, 0xfa, 0x9a, 0x43, 0x5b, 0x15, 0xbe, 0xa5, 0xad, 0xbe, 0xb5,
-	0x3f, 0xdc, 0x86, 0x46, 0x3c, 0xf7, 0x9b, 0x7b, 0x7c, 0xae, 0x43, 0xdd, 0xc1, 0x1e, 0xc1, 0x7e,
-	0xb1, 0x5a, 0xce, 0x12, 0x23, 0xf2, 0x0a, 0xd1, 0x3e, 0x3c, 0x36, 0x37, 0x07, 0x44, 0xff, 0x0f,
-	0x62, 0x98, 0x3e, 0x0a, 0x29, 0x65, 0x91, 0x00, 0xe9, 0x87, 0xbc, 0xf2, 0xef, 0x84, 0x50, 0x65,
-	0xbc, 0x76, 0xd0, 0x85, 0xb2, 0x83, 0xdd, 0xe9, 0x98, 0x81, 0xf5, 0xc6, 0xc6, 0x8b, 0xd9, 0xb2,
-	0x47, 0x42, 0x9d, 0x8e, 0x3d, 0x85, 0x0b, 0xcb, 0xf7, 0xa1, 0xcc, 0x28, 0xa8, 0x06, 0x95, 0xbb,
-	0xbb, 0x77, 0x76, 0xf7, 0xde, 0xdb, 0x6d, 0xe5, 0x10, 0x40, 0x79, 0xb3, 0xd3, 0xe9, 0xee, 0xf7,
-	0x5b, 0x02, 0x12, 0xa1, 0xb4, 0xb9, 0xb5, 0xa7, 0xf4, 0x5b, 0x79, 0x42, 0x56, 0xba, 0xef, 0x74,
-	0x3b, 0xfd, 0x56, 0x01, 0x2d, 0x43, 0x9d, 0xb5, 0xd5, 0xdb, 0x7b, 0xca, 0xbb, 0x9b, 0xfd, 0x56,
-	0x31, 0x42, 0x3a, 0xe8, 0xee, 0xde, 0xea, 0x2a, 0xad, 0x92, 0xfc, 0x12, 0x5c, 0x49, 0xcd, 0x37,
-	0x43, 0xdc, 0x2f, 0x44, 0x70, 0xbf, 0xfc, 0x59, 0x1e, 0xa4, 0xf4, 0x24, 0x12, 0xbd, 0x93, 0xd8,
-	0xf8, 0xc6, 0x39, 0x32, 0xd0, 0xc4, 0xee, 0xd1, 0x53, 0xd0, 0x70, 0xf0, 0x11, 0xf6, 0x06, 0x23,
-	0x96, 0xd4, 0xb2, 0x98, 0x5d, 0x57, 0xea, 0x9c, 0x4a, 0x85, 0x5c, 0xc6, 0xf6, 0x21, 0x1e, 0x78,
-	0x2a, 0xf3, 0x65, 0xec, 0xd4, 0x8a, 0x84, 0x8d, 0x50, 0x0f, 0x18, 0x51, 0xfe, 0xe0, 0x5c, 0xb6,
-	0x14, 0xa1, 0xa4, 0x74, 0xfb, 0xca, 0x0f, 0x5b, 0x05, 0x84, 0xa0, 0x41, 0x9b, 0xea, 0xc1, 0xee,
-	0xe6, 0xfe, 0x41, 0x6f, 0x8f, 0xd8, 0xf2, 0x02, 0x34, 0x7d, 0x5b, 0xfa, 0xc4, 0x92, 0xbc, 0x0f,
-	0x97, 0x53, 0x32, 0xe0, 0x47, 0x2c, 0x7d, 0xc8, 0xbf, 0x11, 0xa2, 0x2a, 0xe3, 0x65, 0x86, 0xb7,
-	0x13, 0x96, 0x5e, 0xcf, 0x9a, 0x37, 0x27, 0xcd, 0x2c, 0x41, 0x15, 0xf3, 0x9a, 0x1f, 0x35, 0xf0,
-	0x92, 0x12, 0xf4, 0xe5, 0x17, 0x17, 0x1b, 0x2d, 0x3c, 0x75, 0x79, 0xf9, 0xdf, 0x02, 0x34, 0x13,
-	0x3e, 0x06, 0x6d, 0x40, 0x89, 0x41, 0xc3, 0xb4, 0x5f, 0xa5, 0xd4, 0x45, 0x72, 0x87, 0xc4, 0x58,
-	0xd1, 0x1b, 0xb1, 0x25, 0xcd, 0xf8, 0x32, 0x66, 0x2c, 0xbf, 0x50, 0xc9, 0x45, 0x03, 0x09, 0xf4,
-	0x26, 0x88, 0x81, 0xb3, 0xe4, 0xf5, 0x88, 0x27, 0x67, 0xc5, 0x03, 0x37, 0xcb, 0xe5, 0x43, 0x19,
-	0xf4, 0x5a, 0x98, 0xb1, 0x17, 0x67, 0x01, 0x29, 0x17, 0x67, 0x0c, 0x5c, 0xd8, 0xe7, 0x97, 0x3b,
-	0x50, 0x8b, 0xec, 0x07, 0x3d, 0x0e, 0xe2, 0x44, 0x3b, 0xe1, 0xe5, 0x6d, 0x56, 0xa0, 0xac, 0x4e,
-	0xb4, 0x13, 0x56, 0xd9, 0xbe, 0x0c, 0x15, 0x32, 0x38, 0xd4, 0x5c, 0x5e, 0xd0, 0x2a, 0x4f, 0xb4,
-	0x93, 0xb7, 0x35, 0x57, 0x7e, 0x1f, 0x1a, 0xf1, 0xd2, 0x2e, 0xb9, 0x8b, 0x8e, 0x35, 0x35, 0x75,
-	0xaa, 0xa3, 0xa4, 0xb0, 0x0e, 0xba, 0x09, 0xa5, 0x63, 0x8b, 0xf9, 0xfb, 0xf9, 0x4e, 0xeb, 0x9e,
-	0xe5, 0xe1, 0x88, 0x5b, 0x65, 0xdc, 0xf2, 0x43, 0x28, 0x51, 0xff, 0x4d, 0x5c, 0x29, 0x2d, 0xd2,
-	0x72, 0xb4, 0x42, 0xda, 0xe8, 0x7d, 0x00, 0xcd, 0xf3, 0x1c, 0xe3, 0x70, 0x1a, 0x2a, 0x5e, 0x9d,
-	0xef, 0xff, 0x37, 0x7d, 0xbe, 0xad, 0xab, 0x3c, 0x10, 0x5c, 0x0c, 0x45, 0x23, 0xc1, 0x20, 0xa2,
-	0x50, 0xde, 0x85, 0x46, 0x5c, 0x36, 0xfa, 0xbb, 0x64, 0x69, 0xce, 0xef, 0x92, 0x20, 0x1f, 0x0d,
-	0xb2, 0xd9, 0x02, 0x2b, 0xc8, 0xd3, 0x8e, 0xfc, 0x89, 0x00, 0xd5, 0xfe, 0x09, 0x3f, 0xa3, 0x29,
-	0xb5, 0xe0, 0x50, 0x34, 0x1f, 0xad, 0x6b, 0xb2, 0xe2, 0x72, 0x21, 0x28, 0x59, 0xbf, 0x15, 0x5c,
-	0xa8, 0x62, 0xd6, 0x42, 0x88, 0x5f, 0xc1, 0xe3, 0xee, 0xfa, 0x75, 0x10, 0x83, 0x53, 0x45, 0x60,
-	0x9f, 0xa6, 0xeb, 0x0e, 0x76, 0x5d, 0xbe, 0x37, 0xbf, 0x4b, 0x7f, 0x2d, 0x58, 0x1f, 0xf3, 0xca,
-	0x69, 0x41, 0x61, 0x1d, 0x59, 0x87, 0x66, 0x22, 0xf2, 0xa3, 0xd7, 0xa1, 0x62, 0x4f, 0x0f, 0x55,
-	0xdf, 0x3c, 0x89, 0xcb, 0xe3, 0x27, 0xe0, 0xd3, 0xc3, 0xb1, 0x31, 0xb8, 0x83, 0x4f, 0xfd, 0xc5,
-	0xd8, 0xd3, 0xc3, 0x3b, 0xcc, 0x8a, 0x6c, 0x96, 0x7c, 0x74, 0x96, 0x63, 0xa8, 0xfa, 0x87, 0x02,
-	0x7d, 0x2f, 0x7a, 0x4f, 0xfc, 0x1f, 0x4e, 0xa9, 0xd9, 0x08, 0x57, 0x1f, 0xb9, 0x26, 0x37, 0x60,
-	0xd9, 0x35, 0x86, 0x26, 0xd6, 0xd5, 0x10, 0x78, 0xd2, 0xd9, 0xaa, 0x4a, 0x93, 0x0d, 0xec, 0xf8,
-	0xa8, 0x53, 0xfe, 0x97, 0x00, 0x55, 0xff, 0xc2, 0xa2, 0x97, 0x22, 0xe7, 0xae, 0x31, 0x27, 0x23,
-	0xf0, 0x19, 0xc3, 0xbf, 0x03, 0xf1, 0xb5, 0xe6, 0xcf, 0xbf, 0xd6, 0xb4, 0xdf, 0x3c, 0x7e, 0x05,
-	0xb8, 0x78, 0xee, 0xff, 0x6d, 0x2f, 0x00, 0xf2, 0x2c, 0x4f, 0x1b, 0xab, 0xc7, 0x96, 0x67, 0x98,
-	0x43, 0x95, 0x19, 0x9b, 0x25, 0xa5, 0x2d, 0x3a, 0x72, 0x8f, 0x0e, 0xec, 0x53, 0xbb, 0xff, 0x54,
-	0x80, 0x6a, 0x90, 0x1d, 0x9c, 0xb7, 0x94, 0x7f, 0x09, 0xca, 0x3c, 0x00, 0xb2, 0x5a, 0x3e, 0xef,
-	0x05, 0xff, 0x9d, 0x8a, 0x91, 0xff, 0x4e, 0x12, 0x54, 0x27, 0xd8, 0xd3, 0x68, 0x9c, 0x61, 0xd8,
-	0x3f, 0xe8, 0xdf, 0x78, 0x0d, 0x6a, 0x91, 0xff, 0x2e, 0xe4, 0xe6, 0xed, 0x76, 0xdf, 0x6b, 0xe5,
-	0xa4, 0xca, 0x27, 0x9f, 0x5f, 0x2b, 0xec, 0xe2, 0x8f, 0xc9, 0x99, 0x55, 0xba, 0x9d, 0x5e, 0xb7,
-	0x73, 0xa7, 0x25, 0x48, 0xb5, 0x4f, 0x3e, 0xbf, 0x56, 0x51, 0x30, 0xad, 0x15, 0xde, 0xe8, 0xc1,
-	0x52, 0xf4, 0xab, 0xc4, 0xc3, 0x01, 0x82, 0xc6, 0xad, 0xbb, 0xfb, 0x3b, 0xdb, 0x9d, 0xcd, 0x7e,
-	0x57, 0xbd, 0xb7, 0xd7, 0xef, 0xb6, 0x04, 0x74, 0x19, 0x2e, 0xec, 0x6c, 0xbf, 0xdd, 0xeb, 0xab,
-	0x9d, 0x9d, 0xed, 0xee, 0x6e, 0x5f, 0xdd, 0xec, 0xf7, 0x37, 0x3b, 0x77, 0x5a, 0xf9, 0x8d, 0x3f,
-	0xd6, 0xa0, 0xb9, 0xb9, 0xd5, 0xd9, 0x26, 0xf1, 0xdf, 0x18, 0x68, 0xbc, 0x16, 0x5b, 0xa4, 0xa5,
-	0x97, 0x33, 0xdf, 0xd1, 0x48, 0x67, 0x97, 0xa2, 0xd1, 0x6d, 0x28, 0xd1, 0xaa, 0x0c, 0x3a, 0xfb,
-	0x61, 0x8d, 0xb4, 0xa0, 0x36, 0x4d, 0x16, 0x43, 0xaf, 0xc7, 0x99, 0x2f, 0x6d, 0xa4, 0xb3, 0x4b,
-	0xd5, 0x48, 0x01, 0x31, 0xac, 0x8a, 0x2c, 0x7e, 0x79, 0x23, 0x65, 0x28, 0x5f, 0x13, 0x9d, 0x21,
-	0xb2, 0x5a, 0xfc, 0x12, 0x45, 0xca, 0xe0, 0xc0, 0xd0, 0x0e, 0x54, 0x7c, 0x30, 0xbc, 0xe8, 0x6d,
-	0x8c, 0xb4, 0xb0, 0xb4, 0x4c, 0x3e, 0x01, 0x2b, 0x5a, 0x9c, 0xfd, 0xd0, 0x47, 0x5a, 0x50, 0x27,
-	0x47, 0xdb, 0x50, 0xe6, 0xd9, 0xfe, 0x82, 0xf7, 0x2e, 0xd2, 0xa2, 0x52, 0x31, 0x31, 0x5a, 0x58,
-	0x4e, 0x5a, 0xfc, 0x7c, 0x49, 0xca, 0xf0, 0x0b, 0x00, 0xdd, 0x05, 0x88, 0x94, 0x28, 0x32, 0xbc,
-	0x4b, 0x92, 0xb2, 0x94, 0xf6, 0xd1, 0x1e, 0x54, 0x03, 0xc4, 0xb8, 0xf0, 0x95, 0x90, 0xb4, 0xb8,
-	0xc6, 0x8e, 0xee, 0x43, 0x3d, 0x8e, 0x74, 0xb2, 0xbd, 0xfd, 0x91, 0x32, 0x16, 0xcf, 0x89, 0xfe,
-	0x38, 0xec, 0xc9, 0xf6, 0x16, 0x48, 0xca, 0x58, 0x4b, 0x47, 0x1f, 0xc2, 0xf2, 0x2c, 0x2c, 0xc9,
-	0xfe, 0x34, 0x48, 0x3a, 0x47, 0x75, 0x1d, 0x4d, 0x00, 0xcd, 0x81, 0x33, 0xe7, 0x78, 0x29, 0x24,
-	0x9d, 0xa7, 0xd8, 0x8e, 0x74, 0x68, 0x26, 0x31, 0x42, 0xd6, 0x97, 0x43, 0x52, 0xe6, 0xc2, 0x3b,
-	0x9b, 0x25, 0x0e, 0x1b, 0xb2, 0xbe, 0x24, 0x92, 0x32, 0xd7, 0xe1, 0xb7, 0xba, 0x5f, 0x7c, 0xbd,
-	0x22, 0x7c, 0xf9, 0xf5, 0x8a, 0xf0, 0xd7, 0xaf, 0x57, 0x84, 0x4f, 0xbf, 0x59, 0xc9, 0x7d, 0xf9,
-	0xcd, 0x4a, 0xee, 0x4f, 0xdf, 0xac, 0xe4, 0x7e, 0xf4, 0xfc, 0xd0, 0xf0, 0x46, 0xd3, 0xc3, 0xb5,
-	0x81, 0x35, 0x59, 0x8f, 0x3e, 0xd9, 0x9c, 0xf7, 0x8c, 0xf4, 0xb0, 0x4c, 0x83, 0xee, 0xcb, 0xff,
-	0x09, 0x00, 0x00, 0xff, 0xff, 0x01, 0xd0, 0x52, 0xd6, 0x66, 0x2a, 0x00, 0x00,
+	// 3170 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe4, 0x5a, 0xcd, 0x73, 0xe3, 0xc6,
+	0xb1, 0x27, 0xf8, 0xcd, 0xe6, 0xa7, 0x66, 0xe5, 0x5d, 0x2e, 0xbc, 0x96, 0xd6, 0x70, 0xd9, 0x5e,
+	0xaf, 0x6d, 0xc9, 0x96, 0xcb, 0x7e, 0xf6, 0xf3, 0x7b, 0xcf, 0x96, 0xb8, 0x5c, 0x53, 0x5e, 0x59,
+	0xd2, 0x83, 0xb8, 0xeb, 0x7c, 0x79, 0x61, 0x90, 0x18, 0x91, 0xf0, 0x92, 0x00, 0x0c, 0x80, 0xb2,
+	0xb4, 0xe7, 0xa4, 0x52, 0xf1, 0xc9, 0x95, 0x1c, 0xe2, 0x8b, 0x8f, 0xa9, 0xca, 0x25, 0x7f, 0x42,
+	0x72, 0x76, 0x55, 0x72, 0xf0, 0x31, 0x87, 0x94, 0x93, 0xb2, 0x73, 0xca, 0x3f, 0x90, 0x53, 0x2a,
+	0xa9, 0xf9, 0x00, 0x38, 0x00, 0x09, 0x91, 0x5a, 0xe7, 0x96, 0x1b, 0xba, 0xd1, 0xdd, 0x33, 0xd3,
+	0x98, 0xe9, 0xee, 0x5f, 0x63, 0xe0, 0x71, 0x1f, 0x5b, 0x06, 0x76, 0xc7, 0xa6, 0xe5, 0x6f, 0xea,
+	0xbd, 0xbe, 0xb9, 0xe9, 0x9f, 0x39, 0xd8, 0xdb, 0x70, 0x5c, 0xdb, 0xb7, 0x51, 0x7d, 0xfa, 0x72,
+	0x83, 0xbc, 0x94, 0x9f, 0x10, 0xa4, 0xfb, 0xee, 0x99, 0xe3, 0xdb, 0x9b, 0x8e, 0x6b, 0xdb, 0xc7,
+	0x4c, 0x5e, 0xbe, 0x26, 0xbc, 0xa6, 0x76, 0x44, 0x6b, 0xf2, 0xb5, 0x59, 0xe5, 0x07, 0xf8, 0x2c,
+	0x78, 0xfb, 0xc4, 0x8c, 0xae, 0xa3, 0xbb, 0xfa, 0x38, 0x78, 0xbd, 0x3e, 0xb0, 0xed, 0xc1, 0x08,
+	0x6f, 0x52, 0xaa, 0x37, 0x39, 0xde, 0xf4, 0xcd, 0x31, 0xf6, 0x7c, 0x7d, 0xec, 0x70, 0x81, 0xd5,
+	0x81, 0x3d, 0xb0, 0xe9, 0xe3, 0x26, 0x79, 0xe2, 0xdc, 0xb5, 0xb8, 0x9a, 0x31, 0x71, 0x75, 0xdf,
+	0xb4, 0x2d, 0xf6, 0x5e, 0xf9, 0x6d, 0x09, 0x0a, 0x2a, 0xfe, 0x78, 0x82, 0x3d, 0x1f, 0x6d, 0x41,
+	0x16, 0xf7, 0x87, 0x76, 0x53, 0xba, 0x2e, 0xdd, 0x28, 0x6f, 0x5d, 0xdb, 0x88, 0x2d, 0x7e, 0x83,
+	0xcb, 0xb5, 0xfb, 0x43, 0xbb, 0x93, 0x52, 0xa9, 0x2c, 0x7a, 0x15, 0x72, 0xc7, 0xa3, 0x89, 0x37,
+	0x6c, 0xa6, 0xa9, 0xd2, 0x13, 0x49, 0x4a, 0xb7, 0x89, 0x50, 0x27, 0xa5, 0x32, 0x69, 0x32, 0x94,
+	0x69, 0x1d, 0xdb, 0xcd, 0xcc, 0xf9, 0x43, 0xed, 0x5a, 0xc7, 0x74, 0x28, 0x22, 0x8b, 0x76, 0x00,
+	0x3c, 0xec, 0x6b, 0xb6, 0x43, 0xa6, 0xdf, 0xcc, 0x52, 0xcd, 0x27, 0x93, 0x34, 0x8f, 0xb0, 0x7f,
+	0x40, 0x05, 0x3b, 0x29, 0xb5, 0xe4, 0x05, 0x04, 0xb1, 0x61, 0x5a, 0xa6, 0xaf, 0xf5, 0x87, 0xba,
+	0x69, 0x35, 0x73, 0xe7, 0xdb, 0xd8, 0xb5, 0x4c, 0xbf, 0x45, 0x04, 0x89, 0x0d, 0x33, 0x20, 0xc8,
+	0x92, 0x3f, 0x9e, 0x60, 0xf7, 0xac, 0x99, 0x3f, 0x7f, 0xc9, 0xff, 0x4f, 0x84, 0xc8, 0x92, 0xa9,
+	0x34, 0x6a, 0x43, 0xb9, 0x87, 0x07, 0xa6, 0xa5, 0xf5, 0x46, 0x76, 0xff, 0x41, 0xb3, 0x40, 0x95,
+	0x95, 0x24, 0xe5, 0x1d, 0x22, 0xba, 0x43, 0x24, 0x3b, 0x29, 0x15, 0x7a, 0x21, 0x85, 0xfe, 0x07,
+	0x8a, 0xfd, 0x21, 0xee, 0x3f, 0xd0, 0xfc, 0xd3, 0x66, 0x91, 0xda, 0x58, 0x4f, 0xb2, 0xd1, 0x22,
+	0x72, 0xdd, 0xd3, 0x4e, 0x4a, 0x2d, 0xf4, 0xd9, 0x23, 0x59, 0xbf, 0x81, 0x47, 0xe6, 0x09, 0x76,
+	0x89, 0x7e, 0xe9, 0xfc, 0xf5, 0xdf, 0x62, 0x92, 0xd4, 0x42, 0xc9, 0x08, 0x08, 0xf4, 0x16, 0x94,
+	0xb0, 0x65, 0xf0, 0x65, 0x00, 0x35, 0x71, 0x3d, 0x71, 0xaf, 0x58, 0x46, 0xb0, 0x88, 0x22, 0xe6,
+	0xcf, 0xe8, 0x75, 0xc8, 0xf7, 0xed, 0xf1, 0xd8, 0xf4, 0x9b, 0x65, 0xaa, 0xbd, 0x96, 0xb8, 0x00,
+	0x2a, 0xd5, 0x49, 0xa9, 0x5c, 0x1e, 0xed, 0x43, 0x6d, 0x64, 0x7a, 0xbe, 0xe6, 0x59, 0xba, 0xe3,
+	0x0d, 0x6d, 0xdf, 0x6b, 0x56, 0xa8, 0x85, 0xa7, 0x93, 0x2c, 0xec, 0x99, 0x9e, 0x7f, 0x14, 0x08,
+	0x77, 0x52, 0x6a, 0x75, 0x24, 0x32, 0x88, 0x3d, 0xfb, 0xf8, 0x18, 0xbb, 0xa1, 0xc1, 0x66, 0xf5,
+	0x7c, 0x7b, 0x07, 0x44, 0x3a, 0xd0, 0x27, 0xf6, 0x6c, 0x91, 0x81, 0x7e, 0x08, 0x97, 0x46, 0xb6,
+	0x6e, 0x84, 0xe6, 0xb4, 0xfe, 0x70, 0x62, 0x3d, 0x68, 0xd6, 0xa8, 0xd1, 0xe7, 0x12, 0x27, 0x69,
+	0xeb, 0x46, 0x60, 0xa2, 0x45, 0x14, 0x3a, 0x29, 0x75, 0x65, 0x14, 0x67, 0xa2, 0xfb, 0xb0, 0xaa,
+	0x3b, 0xce, 0xe8, 0x2c, 0x6e, 0xbd, 0x4e, 0xad, 0xdf, 0x4c, 0xb2, 0xbe, 0x4d, 0x74, 0xe2, 0xe6,
+	0x91, 0x3e, 0xc3, 0x45, 0x5d, 0x68, 0x38, 0x2e, 0x76, 0x74, 0x17, 0x6b, 0x8e, 0x6b, 0x3b, 0xb6,
+	0xa7, 0x8f, 0x9a, 0x0d, 0x6a, 0xfb, 0xd9, 0x24, 0xdb, 0x87, 0x4c, 0xfe, 0x90, 0x8b, 0x77, 0x52,
+	0x6a, 0xdd, 0x89, 0xb2, 0x98, 0x55, 0xbb, 0x8f, 0x3d, 0x6f, 0x6a, 0x75, 0x65, 0x91, 0x55, 0x2a,
+	0x1f, 0xb5, 0x1a, 0x61, 0xed, 0x14, 0x20, 0x77, 0xa2, 0x8f, 0x26, 0x58, 0x79, 0x16, 0xca, 0x42,
+	0x58, 0x42, 0x4d, 0x28, 0x8c, 0xb1, 0xe7, 0xe9, 0x03, 0x4c, 0xa3, 0x58, 0x49, 0x0d, 0x48, 0xa5,
+	0x06, 0x15, 0x31, 0x14, 0x29, 0xbf, 0x96, 0xa0, 0xd2, 0x35, 0xc7, 0xd8, 0x9e, 0xf8, 0x1e, 0x09,
+	0x33, 0x68, 0x0f, 0xea, 0x3e, 0xa3, 0xf9, 0x44, 0x31, 0x0f, 0x84, 0x57, 0x37, 0x58, 0x0c, 0xdd,
+	0x08, 0x62, 0xe8, 0xc6, 0x2d, 0x1e, 0x43, 0x77, 0x8a, 0x5f, 0x7e, 0xbd, 0x9e, 0xfa, 0xfc, 0xcf,
+	0xeb, 0x92, 0x5a, 0xe3, 0xba, 0x6c, 0x86, 0x18, 0xbd, 0x0b, 0x01, 0x47, 0xe3, 0x7b, 0x3d, 0xbd,
+	0xbc, 0xb1, 0x2a, 0x57, 0x65, 0xfb, 0x5f, 0x19, 0x87, 0x6b, 0xa4, 0x13, 0x6d, 0x42, 0xe1, 0x04,
+	0xbb, 0x1e, 0x09, 0x82, 0x7c, 0x8d, 0x9c, 0x44, 0x4f, 0x41, 0x95, 0x9e, 0x4a, 0x2d, 0x78, 0x4f,
+	0xc6, 0xcc, 0xaa, 0x15, 0xca, 0xbc, 0xc7, 0x85, 0xd6, 0xa1, 0xec, 0x6c, 0x39, 0xa1, 0x48, 0x86,
+	0x8a, 0x80, 0xb3, 0xe5, 0x70, 0x01, 0xe5, 0xbf, 0xa1, 0x11, 0x0f, 0xa2, 0xa8, 0x01, 0x99, 0x07,
+	0xf8, 0x8c, 0x8f, 0x47, 0x1e, 0xd1, 0x2a, 0xff, 0x02, 0x74, 0x8c, 0x92, 0xca, 0x3f, 0xc7, 0xef,
+	0xd3, 0xd0, 0x88, 0x47, 0x4f, 0xf4, 0x3a, 0x64, 0xc9, 0x82, 0xb8, 0x3b, 0xe5, 0x19, 0x0f, 0x74,
+	0x83, 0x4c, 0xc6, 0x5c, 0xf0, 0x19, 0x71, 0x01, 0xd5, 0x40, 0x57, 0x49, 0xb0, 0xd3, 0x4d, 0x4b,
+	0x33, 0x0d, 0x3e, 0x4e, 0x81, 0xd2, 0xbb, 0x06, 0xba, 0x03, 0x8d, 0xbe, 0x6d, 0x79, 0xd8, 0xf2,
+	0x26, 0x9e, 0xc6, 0x32, 0x65, 0x33, 0x93, 0x10, 0x8c, 0x5a, 0x81, 0xe0, 0x21, 0x95, 0x53, 0xeb,
+	0xfd, 0x28, 0x03, 0xdd, 0x06, 0x38, 0xd1, 0x47, 0xa6, 0xa1, 0xfb, 0xb6, 0xeb, 0x35, 0xb3, 0xd7,
+	0x33, 0x73, 0xcd, 0xdc, 0x0b, 0x44, 0xee, 0x3a, 0x86, 0xee, 0xe3, 0x9d, 0x2c, 0x99, 0xad, 0x2a,
+	0x68, 0xa2, 0x67, 0xa0, 0xae, 0x3b, 0x8e, 0xe6, 0xf9, 0xba, 0x8f, 0xb5, 0xde, 0x99, 0x8f, 0x3d,
+	0x9a, 0x63, 0x2a, 0x6a, 0x55, 0x77, 0x9c, 0x23, 0xc2, 0xdd, 0x21, 0x4c, 0xf4, 0x34, 0xd4, 0x48,
+	0x3e, 0x31, 0xf5, 0x91, 0x36, 0xc4, 0xe6, 0x60, 0xe8, 0xd3, 0x5c, 0x92, 0x51, 0xab, 0x9c, 0xdb,
+	0xa1, 0x4c, 0xc5, 0x80, 0x8a, 0x98, 0x4b, 0x10, 0x82, 0xac, 0xa1, 0xfb, 0x3a, 0x75, 0x64, 0x45,
+	0xa5, 0xcf, 0x84, 0xe7, 0xe8, 0xfe, 0x90, 0xbb, 0x87, 0x3e, 0xa3, 0xcb, 0x90, 0xe7, 0x66, 0x33,
+	0xd4, 0x2c, 0xa7, 0xc8, 0x37, 0x73, 0x5c, 0xfb, 0x04, 0xd3, 0xe4, 0x59, 0x54, 0x19, 0xa1, 0xfc,
+	0x38, 0x0d, 0x2b, 0x33, 0x59, 0x87, 0xd8, 0x1d, 0xea, 0xde, 0x30, 0x18, 0x8b, 0x3c, 0xa3, 0xd7,
+	0x88, 0x5d, 0xdd, 0xc0, 0x2e, 0xdf, 0xcc, 0x4d, 0xd1, 0x45, 0xac, 0xd2, 0xe9, 0xd0, 0xf7, 0xdc,
+	0x35, 0x5c, 0x1a, 0x1d, 0x40, 0x63, 0xa4, 0x7b, 0xc1, 0x49, 0xd0, 0x84, 0xcc, 0x3f, 0x9b, 0xbb,
+	0xf6, 0xf4, 0x20, 0xee, 0x93, 0xcd, 0xce, 0x0d, 0xd5, 0x46, 0x11, 0x2e, 0x52, 0x61, 0xb5, 0x77,
+	0xf6, 0x50, 0xb7, 0x7c, 0xd3, 0xc2, 0xda, 0xcc, 0x97, 0xbb, 0x3a, 0x63, 0xb4, 0x7d, 0x62, 0x1a,
+	0xd8, 0xea, 0x07, 0x9f, 0xec, 0x52, 0xa8, 0x1c, 0x7e, 0x52, 0x4f, 0xf9, 0x4c, 0x82, 0x5a, 0x34,
+	0x71, 0xa2, 0x1a, 0xa4, 0xfd, 0x53, 0xee, 0x81, 0xb4, 0x7f, 0x8a, 0x5e, 0x82, 0x2c, 0x59, 0x25,
+	0x5d, 0x7d, 0x6d, 0x4e, 0xd5, 0xc2, 0xf5, 0xba, 0x67, 0x0e, 0x56, 0xa9, 0x24, 0xc9, 0xd6, 0x06,
+	0xd6, 0x8d, 0x91, 0x69, 0xe1, 0x66, 0x66, 0xe1, 0xf6, 0xcf, 0xd2, 0xad, 0x1f, 0x6a, 0x28, 0x0a,
+	0x34, 0xe2, 0xa9, 0x38, 0x3e, 0x27, 0xe5, 0x39, 0xa8, 0xc7, 0x72, 0xad, 0xf0, 0xf9, 0x25, 0xf1,
+	0xf3, 0x2b, 0x75, 0xa8, 0x46, 0x12, 0xab, 0x72, 0x19, 0x56, 0xe7, 0xe5, 0x49, 0xe5, 0x67, 0x12,
+	0xac, 0xce, 0x4b, 0x78, 0xe8, 0x55, 0x28, 0x86, 0x99, 0x32, 0x08, 0x8e, 0x71, 0x27, 0x04, 0xc2,
+	0x6a, 0x28, 0x4a, 0x8e, 0x31, 0x39, 0x16, 0x74, 0x3f, 0xa5, 0xe9, 0xcc, 0x0b, 0xba, 0xe3, 0x74,
+	0xc8, 0x96, 0x5a, 0x87, 0xb2, 0xee, 0xcc, 0x44, 0x23, 0xdd, 0x09, 0xa3, 0xd1, 0x87, 0xd0, 0x4c,
+	0x4a, 0x93, 0xb1, 0x85, 0x66, 0xc3, 0x7d, 0x7e, 0x19, 0xf2, 0xc7, 0xb6, 0x3b, 0xd6, 0x59, 0xd0,
+	0xad, 0xaa, 0x9c, 0x22, 0xfb, 0x9f, 0xa5, 0xcc, 0x0c, 0x65, 0x33, 0x42, 0xd1, 0xe0, 0x6a, 0x62,
+	0xaa, 0x24, 0x2a, 0xa6, 0x65, 0x60, 0xe6, 0xf1, 0xaa, 0xca, 0x88, 0xa9, 0x21, 0xb6, 0x1a, 0x46,
+	0x90, 0x61, 0x3d, 0xea, 0x0c, 0x6a, 0xbf, 0xa4, 0x72, 0x4a, 0xf9, 0xab, 0x04, 0x97, 0xe7, 0x27,
+	0x4c, 0xf4, 0x2a, 0x00, 0x8b, 0xd8, 0xe1, 0xb9, 0x2e, 0x6f, 0x5d, 0x9e, 0x3d, 0x55, 0xb7, 0x74,
+	0x5f, 0x57, 0x4b, 0x54, 0x92, 0x3c, 0x92, 0x38, 0x33, 0x55, 0xd3, 0x3c, 0xf3, 0x21, 0xdb, 0x93,
+	0x19, 0xb5, 0x1a, 0xca, 0x1c, 0x99, 0x0f, 0xa3, 0xf1, 0x33, 0x13, 0x8d, 0x9f, 0x53, 0xdf, 0x65,
+	0x23, 0x31, 0x22, 0x08, 0xd6, 0xb9, 0x8b, 0x06, 0x6b, 0xe5, 0xa7, 0xe2, 0x32, 0x23, 0xe9, 0x5a,
+	0x08, 0x1c, 0xd2, 0x85, 0x02, 0x47, 0xd4, 0x3d, 0xe9, 0x25, 0xdd, 0xa3, 0xfc, 0x02, 0xa0, 0xa8,
+	0x62, 0xcf, 0xb1, 0x2d, 0x0f, 0xa3, 0x1d, 0x28, 0xe1, 0xd3, 0x3e, 0x66, 0xa8, 0x41, 0x4a, 0xac,
+	0xba, 0x99, 0x74, 0x3b, 0x90, 0x24, 0x25, 0x6f, 0xa8, 0x86, 0x5e, 0xe1, 0xc8, 0x28, 0x19, 0xe4,
+	0x70, 0x75, 0x11, 0x1a, 0xbd, 0x16, 0x40, 0xa3, 0x4c, 0x62, 0x95, 0xcb, 0xb4, 0x62, 0xd8, 0xe8,
+	0x15, 0x8e, 0x8d, 0xb2, 0x0b, 0x06, 0x8b, 0x80, 0xa3, 0x56, 0x04, 0x1c, 0xe5, 0x16, 0x2c, 0x33,
+	0x01, 0x1d, 0xb5, 0x22, 0xe8, 0x28, 0xbf, 0xc0, 0x48, 0x02, 0x3c, 0x7a, 0x2d, 0x80, 0x47, 0x85,
+	0x05, 0xcb, 0x8e, 0xe1, 0xa3, 0xdb, 0x51, 0x7c, 0xc4, 0xb0, 0xcd, 0x53, 0x89, 0xda, 0x89, 0x00,
+	0xe9, 0x7f, 0x05, 0x80, 0x54, 0x4a, 0x44, 0x27, 0xcc, 0xc8, 0x1c, 0x84, 0xd4, 0x8a, 0x20, 0x24,
+	0x58, 0xe0, 0x83, 0x04, 0x88, 0xf4, 0xb6, 0x08, 0x91, 0xca, 0x89, 0x28, 0x8b, 0x6f, 0x9a, 0x79,
+	0x18, 0xe9, 0x8d, 0x10, 0x23, 0x55, 0x12, 0x41, 0x1e, 0x5f, 0x43, 0x1c, 0x24, 0x1d, 0xcc, 0x80,
+	0x24, 0x06, 0x6a, 0x9e, 0x49, 0x34, 0xb1, 0x00, 0x25, 0x1d, 0xcc, 0xa0, 0xa4, 0xda, 0x02, 0x83,
+	0x0b, 0x60, 0xd2, 0x8f, 0xe6, 0xc3, 0xa4, 0x64, 0x20, 0xc3, 0xa7, 0xb9, 0x1c, 0x4e, 0xd2, 0x12,
+	0x70, 0x12, 0xc3, 0x32, 0xcf, 0x27, 0x9a, 0x5f, 0x1a, 0x28, 0xdd, 0x9d, 0x03, 0x94, 0x18, 0xa4,
+	0xb9, 0x91, 0x68, 0x7c, 0x09, 0xa4, 0x74, 0x77, 0x0e, 0x52, 0x42, 0x0b, 0xcd, 0x2e, 0x0f, 0x95,
+	0x9e, 0x83, 0x95, 0x40, 0x2d, 0x0c, 0x73, 0x24, 0x93, 0x61, 0xd7, 0xb5, 0x5d, 0x5e, 0xda, 0x33,
+	0x42, 0xb9, 0x01, 0x95, 0x50, 0xf4, 0x7c, 0x58, 0x45, 0x6b, 0x0a, 0x21, 0x8c, 0x29, 0xbf, 0x4c,
+	0x43, 0x45, 0x8c, 0x50, 0x91, 0xa2, 0xb5, 0xc4, 0x8b, 0x56, 0x01, 0xc2, 0xa4, 0xa3, 0x10, 0x66,
+	0x51, 0x3d, 0x80, 0x6e, 0xc2, 0x0a, 0xad, 0x25, 0x59, 0x5e, 0x88, 0xa4, 0xb0, 0x3a, 0x79, 0xc1,
+	0x8e, 0x12, 0x65, 0xa3, 0x17, 0xe1, 0x92, 0x20, 0x1b, 0x96, 0x20, 0xac, 0x24, 0x6f, 0x84, 0xd2,
+	0xdb, 0xbc, 0x16, 0x79, 0x0b, 0x8a, 0x1c, 0x78, 0x79, 0x89, 0xbd, 0x1d, 0x11, 0x32, 0xf2, 0x64,
+	0x15, 0x2a, 0xa1, 0x6b, 0x50, 0xf2, 0x7c, 0x17, 0xeb, 0x63, 0xd3, 0x1a, 0xd0, 0xf0, 0x57, 0x54,
+	0xa7, 0x0c, 0xe5, 0x3d, 0x58, 0x99, 0x89, 0xbf, 0xc4, 0x3b, 0x7d, 0xdb, 0xc0, 0xbc, 0xbc, 0xa0,
+	0xcf, 0x04, 0x6c, 0x8d, 0xec, 0x01, 0x4f, 0xd8, 0xe4, 0x91, 0x48, 0x85, 0x29, 0xa1, 0xc4, 0x22,
+	0x3e, 0x2f, 0xdb, 0x63, 0xa1, 0x78, 0x2e, 0x2c, 0x92, 0xfe, 0x3d, 0xb0, 0x28, 0xfd, 0xc8, 0xb0,
+	0x48, 0xac, 0xff, 0x32, 0xd1, 0xfa, 0x4f, 0xf4, 0x79, 0xf6, 0x11, 0x7c, 0xae, 0xfc, 0x5d, 0x82,
+	0x6a, 0x24, 0xa3, 0x3c, 0xba, 0x4b, 0xa7, 0xc5, 0x5e, 0x8e, 0xee, 0x27, 0x46, 0x04, 0xd8, 0x37,
+	0x4f, 0x27, 0x1e, 0xc5, 0xbe, 0x05, 0xca, 0x63, 0x04, 0x7a, 0x1d, 0x4a, 0xb4, 0x17, 0xac, 0xd9,
+	0x8e, 0xc7, 0xd3, 0xd7, 0xe3, 0xe2, 0x5a, 0x58, 0xcb, 0x77, 0xe3, 0x90, 0xc8, 0x1c, 0x38, 0x9e,
+	0x5a, 0x74, 0xf8, 0x93, 0x50, 0x8b, 0x95, 0x22, 0xb5, 0xd8, 0x35, 0x28, 0x91, 0xd9, 0x7b, 0x8e,
+	0xde, 0xc7, 0x34, 0x15, 0x95, 0xd4, 0x29, 0x43, 0xb9, 0x0f, 0x68, 0x36, 0x19, 0xa2, 0x0e, 0xe4,
+	0xf1, 0x09, 0xb6, 0x7c, 0xf2, 0xd9, 0x33, 0xf1, 0x72, 0x89, 0x83, 0x21, 0x6c, 0xf9, 0x3b, 0x4d,
+	0xe2, 0xc7, 0xbf, 0x7d, 0xbd, 0xde, 0x60, 0xd2, 0x2f, 0xd8, 0x63, 0xd3, 0xc7, 0x63, 0xc7, 0x3f,
+	0x53, 0xb9, 0xbe, 0xf2, 0xa7, 0x34, 0xd4, 0x83, 0x01, 0x02, 0x44, 0x34, 0xcf, 0xb7, 0xc1, 0x01,
+	0x4f, 0x0b, 0xa8, 0x74, 0x39, 0x7f, 0xaf, 0x01, 0x0c, 0x74, 0x4f, 0xfb, 0x44, 0xb7, 0x7c, 0x6c,
+	0x70, 0xa7, 0x0b, 0x1c, 0x24, 0x43, 0x91, 0x50, 0x13, 0x0f, 0x1b, 0x1c, 0x20, 0x87, 0xb4, 0xb0,
+	0xce, 0xc2, 0x77, 0x5b, 0x67, 0xd4, 0xcb, 0xc5, 0x98, 0x97, 0x85, 0xa2, 0xbe, 0x24, 0x16, 0xf5,
+	0x64, 0x6e, 0x8e, 0x6b, 0xda, 0xae, 0xe9, 0x9f, 0xd1, 0x4f, 0x93, 0x51, 0x43, 0x9a, 0xf4, 0x61,
+	0xc6, 0x78, 0xec, 0xd8, 0xf6, 0x48, 0x63, 0xc1, 0xb5, 0x4c, 0x55, 0x2b, 0x9c, 0xd9, 0xa6, 0x31,
+	0xf6, 0x27, 0xc2, 0xf9, 0x9d, 0xc2, 0xbb, 0xff, 0x38, 0x07, 0x2b, 0x7f, 0xa0, 0x2d, 0xa3, 0x68,
+	0x29, 0x84, 0x8e, 0x60, 0x25, 0x8c, 0x1f, 0xda, 0x84, 0xc6, 0x95, 0x60, 0x43, 0x2f, 0x1b, 0x80,
+	0x1a, 0x27, 0x51, 0xb6, 0x87, 0xbe, 0x07, 0x57, 0x62, 0xb1, 0x31, 0x34, 0x9d, 0x5e, 0x32, 0x44,
+	0x3e, 0x16, 0x0d, 0x91, 0x81, 0xe5, 0xa9, 0xaf, 0x32, 0xdf, 0xd1, 0x57, 0xdf, 0x39, 0x1e, 0xee,
+	0x42, 0x2d, 0xf0, 0x26, 0xab, 0x0c, 0xe7, 0x6e, 0x9f, 0xa7, 0xa0, 0xea, 0x62, 0x9f, 0x20, 0xc3,
+	0x48, 0xa3, 0xa8, 0xc2, 0x98, 0xbc, 0xfd, 0x74, 0x08, 0x8f, 0xcd, 0xad, 0x10, 0xd1, 0x7f, 0x41,
+	0x69, 0x5a, 0x5c, 0x4a, 0x09, 0x3d, 0x97, 0x40, 0x5c, 0x9d, 0xca, 0x2a, 0xbf, 0x93, 0xe0, 0xb1,
+	0xb9, 0x35, 0x22, 0x6a, 0x43, 0xde, 0xc5, 0xde, 0x64, 0xc4, 0xa0, 0x7c, 0x6d, 0xeb, 0xc5, 0xe5,
+	0x6a, 0x4b, 0xc2, 0x9d, 0x8c, 0x7c, 0x95, 0x2b, 0x2b, 0xf7, 0x21, 0xcf, 0x38, 0xa8, 0x0c, 0x85,
+	0xbb, 0xfb, 0x77, 0xf6, 0x0f, 0xde, 0xdf, 0x6f, 0xa4, 0x10, 0x40, 0x7e, 0xbb, 0xd5, 0x6a, 0x1f,
+	0x76, 0x1b, 0x12, 0x2a, 0x41, 0x6e, 0x7b, 0xe7, 0x40, 0xed, 0x36, 0xd2, 0x84, 0xad, 0xb6, 0xdf,
+	0x6d, 0xb7, 0xba, 0x8d, 0x0c, 0x5a, 0x81, 0x2a, 0x7b, 0xd6, 0x6e, 0x1f, 0xa8, 0xef, 0x6d, 0x77,
+	0x1b, 0x59, 0x81, 0x75, 0xd4, 0xde, 0xbf, 0xd5, 0x56, 0x1b, 0x39, 0xe5, 0x65, 0xb8, 0x1a, 0xcc,
+	0x63, 0xb6, 0x1d, 0x11, 0x76, 0x05, 0x24, 0xa1, 0x2b, 0xa0, 0x7c, 0x9e, 0x06, 0x39, 0xb9, 0xc4,
+	0x44, 0xef, 0xc6, 0x16, 0xbe, 0x75, 0x81, 0xfa, 0x34, 0xb6, 0x7a, 0xd2, 0x56, 0x74, 0xf1, 0x31,
+	0xf6, 0xfb, 0x43, 0x56, 0xf2, 0xb2, 0x9c, 0x5d, 0x55, 0xab, 0x9c, 0x4b, 0x95, 0x3c, 0x26, 0xf6,
+	0x11, 0xee, 0xfb, 0x1a, 0x8b, 0x65, 0x6c, 0xd7, 0x96, 0xd4, 0x2a, 0xe3, 0x1e, 0x31, 0xa6, 0xf2,
+	0xe1, 0x85, 0x7c, 0x59, 0x82, 0x9c, 0xda, 0xee, 0xaa, 0xdf, 0x6f, 0x64, 0x10, 0x82, 0x1a, 0x7d,
+	0xd4, 0x8e, 0xf6, 0xb7, 0x0f, 0x8f, 0x3a, 0x07, 0xc4, 0x97, 0x97, 0xa0, 0x1e, 0xf8, 0x32, 0x60,
+	0xe6, 0x94, 0x43, 0xb8, 0x92, 0x50, 0x1f, 0x3f, 0x62, 0x63, 0x44, 0xf9, 0x8d, 0x24, 0x9a, 0x8c,
+	0x36, 0x21, 0xde, 0x89, 0x79, 0x7a, 0x73, 0xd9, 0xaa, 0x3a, 0xee, 0x66, 0x19, 0x8a, 0x98, 0x37,
+	0x14, 0xa9, 0x83, 0x2b, 0x6a, 0x48, 0x2b, 0x2f, 0x2e, 0x76, 0xda, 0x74, 0xd7, 0xa5, 0x95, 0x7f,
+	0x4a, 0x50, 0x8f, 0xc5, 0x18, 0xb4, 0x05, 0x39, 0x06, 0x1c, 0x93, 0xfe, 0xc3, 0xd2, 0x10, 0xc9,
+	0x84, 0x55, 0x26, 0x4a, 0xfa, 0x8c, 0xc2, 0x94, 0x66, 0x62, 0x19, 0x73, 0x56, 0xd0, 0x05, 0xe5,
+	0xaa, 0xa1, 0x06, 0xf9, 0xa3, 0x17, 0x06, 0xcb, 0x66, 0x66, 0x16, 0xae, 0x32, 0xf5, 0x30, 0xcc,
+	0x72, 0xfd, 0xa9, 0x0e, 0x7a, 0x63, 0x5a, 0xcf, 0x67, 0x67, 0xe1, 0x2a, 0x57, 0x67, 0x02, 0x5c,
+	0x39, 0x90, 0x57, 0x5a, 0x50, 0x16, 0xd6, 0x83, 0x1e, 0x87, 0xd2, 0x58, 0x3f, 0xe5, 0xbd, 0x73,
+	0xd6, 0xbe, 0x2c, 0x8e, 0xf5, 0x53, 0xd6, 0x36, 0xbf, 0x02, 0x05, 0xf2, 0x72, 0xa0, 0x7b, 0xbc,
+	0xdd, 0x95, 0x1f, 0xeb, 0xa7, 0xef, 0xe8, 0x9e, 0xf2, 0x01, 0xd4, 0xa2, 0x7d, 0x63, 0x72, 0x16,
+	0x5d, 0x7b, 0x62, 0x19, 0xd4, 0x46, 0x4e, 0x65, 0x04, 0xf9, 0x75, 0x7b, 0x62, 0xb3, 0x78, 0x3f,
+	0x3f, 0x68, 0xdd, 0xb3, 0x7d, 0x2c, 0x84, 0x55, 0x26, 0xad, 0x3c, 0x84, 0x1c, 0x8d, 0xdf, 0x24,
+	0x94, 0xd2, 0x06, 0x30, 0xc7, 0x32, 0xe4, 0x19, 0x7d, 0x00, 0xa0, 0xfb, 0xbe, 0x6b, 0xf6, 0x26,
+	0x53, 0xc3, 0xeb, 0xf3, 0xe3, 0xff, 0x76, 0x20, 0xb7, 0x73, 0x8d, 0x27, 0x82, 0xd5, 0xa9, 0xaa,
+	0x90, 0x0c, 0x04, 0x83, 0xca, 0x3e, 0xd4, 0xa2, 0xba, 0xe2, 0xbf, 0x98, 0xca, 0x9c, 0x7f, 0x31,
+	0x61, 0x3d, 0x1a, 0x56, 0xb3, 0x19, 0xd6, 0xed, 0xa7, 0x84, 0xf2, 0xa9, 0x04, 0xc5, 0xee, 0x29,
+	0xdf, 0xa3, 0x09, 0x9d, 0xe2, 0xa9, 0x6a, 0x5a, 0xec, 0x7a, 0xb2, 0xd6, 0x73, 0x26, 0x6c, 0x87,
+	0xbf, 0x1d, 0x1e, 0xa8, 0xec, 0xb2, 0x6d, 0x92, 0xa0, 0xbf, 0xc7, 0xc3, 0xf5, 0x9b, 0x50, 0x0a,
+	0x77, 0x15, 0x01, 0x85, 0xba, 0x61, 0xb8, 0xd8, 0xf3, 0xf8, 0xda, 0x02, 0x92, 0x4c, 0xc7, 0xb1,
+	0x3f, 0xe1, 0x7d, 0xd5, 0x8c, 0xca, 0x08, 0xc5, 0x80, 0x7a, 0x2c, 0xf3, 0xa3, 0x37, 0xa1, 0xe0,
+	0x4c, 0x7a, 0x5a, 0xe0, 0x9e, 0xd8, 0xe1, 0x09, 0x0a, 0xf0, 0x49, 0x6f, 0x64, 0xf6, 0xef, 0xe0,
+	0xb3, 0x60, 0x32, 0xce, 0xa4, 0x77, 0x87, 0x79, 0x91, 0x8d, 0x92, 0x16, 0x47, 0x39, 0x81, 0x62,
+	0xb0, 0x29, 0xd0, 0xff, 0x89, 0xe7, 0x24, 0xf8, 0x9b, 0x95, 0x58, 0x8d, 0x70, 0xf3, 0xc2, 0x31,
+	0xb9, 0x09, 0x2b, 0x9e, 0x39, 0xb0, 0xb0, 0xa1, 0x4d, 0x61, 0x29, 0x1d, 0xad, 0xa8, 0xd6, 0xd9,
+	0x8b, 0xbd, 0x00, 0x93, 0x2a, 0xff, 0x90, 0xa0, 0x18, 0x1c, 0x58, 0xf4, 0xb2, 0xb0, 0xef, 0x6a,
+	0x73, 0x2a, 0x82, 0x40, 0x50, 0xf8, 0xf3, 0x10, 0x99, 0x6b, 0xfa, 0xe2, 0x73, 0x4d, 0xfa, 0x87,
+	0x14, 0xf4, 0x87, 0xb3, 0x17, 0xfe, 0x99, 0xf7, 0x02, 0x20, 0xdf, 0xf6, 0xf5, 0x91, 0x76, 0x62,
+	0xfb, 0xa6, 0x35, 0xd0, 0x98, 0xb3, 0x59, 0x51, 0xda, 0xa0, 0x6f, 0xee, 0xd1, 0x17, 0x87, 0xd4,
+	0xef, 0xbf, 0x92, 0xa0, 0x18, 0x56, 0x07, 0x17, 0x6d, 0xf4, 0x5f, 0x86, 0x3c, 0x4f, 0x80, 0xac,
+	0xd3, 0xcf, 0xa9, 0xf0, 0xa7, 0x56, 0x56, 0xf8, 0xa9, 0x25, 0x43, 0x71, 0x8c, 0x7d, 0x9d, 0xe6,
+	0x19, 0xd6, 0x19, 0x08, 0x69, 0xf4, 0x24, 0x54, 0xa8, 0x26, 0x85, 0xae, 0x98, 0x74, 0x05, 0x48,
+	0xb4, 0x2f, 0x53, 0x5e, 0x87, 0xb2, 0x6e, 0xbe, 0x01, 0x65, 0xe1, 0xb7, 0x0f, 0x39, 0x9c, 0xfb,
+	0xed, 0xf7, 0x1b, 0x29, 0xb9, 0xf0, 0xe9, 0x17, 0xd7, 0x33, 0xfb, 0xf8, 0x13, 0xb2, 0xad, 0xd5,
+	0x76, 0xab, 0xd3, 0x6e, 0xdd, 0x69, 0x48, 0x72, 0xf9, 0xd3, 0x2f, 0xae, 0x17, 0x54, 0x4c, 0x9b,
+	0x8d, 0x37, 0x3b, 0x50, 0x11, 0x3f, 0x5c, 0x34, 0x63, 0x20, 0xa8, 0xdd, 0xba, 0x7b, 0xb8, 0xb7,
+	0xdb, 0xda, 0xee, 0xb6, 0xb5, 0x7b, 0x07, 0xdd, 0x76, 0x43, 0x42, 0x57, 0xe0, 0xd2, 0xde, 0xee,
+	0x3b, 0x9d, 0xae, 0xd6, 0xda, 0xdb, 0x6d, 0xef, 0x77, 0xb5, 0xed, 0x6e, 0x77, 0xbb, 0x75, 0xa7,
+	0x91, 0xde, 0xfa, 0x79, 0x05, 0xea, 0xdb, 0x3b, 0xad, 0x5d, 0x52, 0x22, 0x98, 0x7d, 0x9d, 0x37,
+	0x73, 0xb3, 0xb4, 0x77, 0x73, 0xee, 0x3d, 0x1e, 0xf9, 0xfc, 0x5e, 0x36, 0xba, 0x0d, 0x39, 0xda,
+	0xd6, 0x41, 0xe7, 0x5f, 0xec, 0x91, 0x17, 0x34, 0xb7, 0xc9, 0x64, 0xe8, 0x09, 0x3a, 0xf7, 0xa6,
+	0x8f, 0x7c, 0x7e, 0xaf, 0x1b, 0xa9, 0x50, 0x9a, 0x36, 0x4e, 0x16, 0xdf, 0xfc, 0x91, 0x97, 0xe8,
+	0x7f, 0x13, 0x9b, 0x53, 0xf0, 0xb5, 0xf8, 0x26, 0x8c, 0xbc, 0x44, 0x8c, 0x43, 0x7b, 0x50, 0x08,
+	0xf0, 0xf2, 0xa2, 0xbb, 0x39, 0xf2, 0xc2, 0xde, 0x34, 0xf9, 0x04, 0xac, 0xaf, 0x71, 0xfe, 0x45,
+	0x23, 0x79, 0x41, 0xa3, 0x1d, 0xed, 0x42, 0x9e, 0x03, 0x82, 0x05, 0xf7, 0x6d, 0xe4, 0x45, 0xbd,
+	0x66, 0xe2, 0xb4, 0x69, 0xc7, 0x69, 0xf1, 0xf5, 0x29, 0x79, 0x89, 0x7f, 0x08, 0xe8, 0x2e, 0x80,
+	0xd0, 0xc5, 0x58, 0xe2, 0x5e, 0x94, 0xbc, 0xcc, 0xbf, 0x01, 0x74, 0x00, 0xc5, 0x10, 0x54, 0x2e,
+	0xbc, 0xa5, 0x24, 0x2f, 0x6e, 0xd2, 0xa3, 0xfb, 0x50, 0x8d, 0x82, 0xa1, 0xe5, 0xee, 0x1e, 0xc9,
+	0x4b, 0x76, 0xdf, 0x89, 0xfd, 0x28, 0x32, 0x5a, 0xee, 0x2e, 0x92, 0xbc, 0x64, 0x33, 0x1e, 0x7d,
+	0x04, 0x2b, 0xb3, 0xc8, 0x65, 0xf9, 0xab, 0x49, 0xf2, 0x05, 0xda, 0xf3, 0x68, 0x0c, 0x68, 0x0e,
+	0xe2, 0xb9, 0xc0, 0x4d, 0x25, 0xf9, 0x22, 0xdd, 0x7a, 0x64, 0x40, 0x3d, 0x0e, 0x23, 0x96, 0xbd,
+	0xb9, 0x24, 0x2f, 0xdd, 0xb9, 0x67, 0xa3, 0x44, 0x91, 0xc5, 0xb2, 0x37, 0x99, 0xe4, 0xa5, 0x1b,
+	0xf9, 0x68, 0x1b, 0xf2, 0x47, 0xb4, 0x73, 0x8c, 0x9a, 0x49, 0xc6, 0xe5, 0xab, 0x89, 0xd6, 0x6e,
+	0x48, 0x2f, 0x49, 0x3b, 0xed, 0x2f, 0xbf, 0x59, 0x93, 0xbe, 0xfa, 0x66, 0x4d, 0xfa, 0xcb, 0x37,
+	0x6b, 0xd2, 0x67, 0xdf, 0xae, 0xa5, 0xbe, 0xfa, 0x76, 0x2d, 0xf5, 0xc7, 0x6f, 0xd7, 0x52, 0x3f,
+	0x78, 0x7e, 0x60, 0xfa, 0xc3, 0x49, 0x6f, 0xa3, 0x6f, 0x8f, 0x37, 0xc5, 0x5b, 0xa7, 0xf3, 0x6e,
+	0xc2, 0xf6, 0xf2, 0x34, 0xb5, 0xbf, 0xf2, 0xaf, 0x01, 0x00, 0x14, 0x01, 0x21, 0x7c, 0x29, 0x2b,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -3856,6 +3885,7 @@ type ABCIApplicationClient interface {
 	ApplySnapshotChunk(ctx context.Context, in *RequestApplySnapshotChunk, opts ...grpc.CallOption) (*ResponseApplySnapshotChunk, error)
 	PrepareProposal(ctx context.Context, in *RequestPrepareProposal, opts ...grpc.CallOption) (*ResponsePrepareProposal, error)
 	ProcessProposal(ctx context.Context, in *RequestProcessProposal, opts ...grpc.CallOption) (*ResponseProcessProposal, error)
+	Stream(ctx context.Context, opts ...grpc.CallOption) (ABCIApplication_StreamClient, error)
 }
 
 type aBCIApplicationClient struct {
@@ -4019,6 +4049,37 @@ func (c *aBCIApplicationClient) ProcessProposal(ctx context.Context, in *Request
 	return out, nil
 }
 
+func (c *aBCIApplicationClient) Stream(ctx context.Context, opts ...grpc.CallOption) (ABCIApplication_StreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ABCIApplication_serviceDesc.Streams[0], "/tendermint.abci.ABCIApplication/Stream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aBCIApplicationStreamClient{stream}
+	return x, nil
+}
+
+type ABCIApplication_StreamClient interface {
+	Send(*Request) error
+	Recv() (*Response, error)
+	grpc.ClientStream
+}
+
+type aBCIApplicationStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aBCIApplicationStreamClient) Send(m *Request) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *aBCIApplicationStreamClient) Recv() (*Response, error) {
+	m := new(Response)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ABCIApplicationServer is the server API for ABCIApplication service.
 type ABCIApplicationServer interface {
 	Echo(context.Context, *RequestEcho) (*ResponseEcho, error)
@@ -4038,6 +4099,7 @@ type ABCIApplicationServer interface {
 	ApplySnapshotChunk(context.Context, *RequestApplySnapshotChunk) (*ResponseApplySnapshotChunk, error)
 	PrepareProposal(context.Context, *RequestPrepareProposal) (*ResponsePrepareProposal, error)
 	ProcessProposal(context.Context, *RequestProcessProposal) (*ResponseProcessProposal, error)
+	Stream(ABCIApplication_StreamServer) error
 }
 
 // UnimplementedABCIApplicationServer can be embedded to have forward compatible implementations.
@@ -4095,6 +4157,9 @@ func (*UnimplementedABCIApplicationServer) PrepareProposal(ctx context.Context,
 func (*UnimplementedABCIApplicationServer) ProcessProposal(ctx context.Context, req *RequestProcessProposal) (*ResponseProcessProposal, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProcessProposal not implemented")
 }
+func (*UnimplementedABCIApplicationServer) Stream(srv ABCIApplication_StreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method Stream not implemented")
+}
 
 func RegisterABCIApplicationServer(s *grpc.Server, srv ABCIApplicationServer) {
 	s.RegisterService(&_ABCIApplication_serviceDesc, srv)
@@ -4406,6 +4471,32 @@ func _ABCIApplication_ProcessProposal_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ABCIApplication_Stream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ABCIApplicationServer).Stream(&aBCIApplicationStreamServer{stream})
+}
+
+type ABCIApplication_StreamServer interface {
+	Send(*Response) error
+	Recv() (*Request, error)
+	grpc.ServerStream
+}
+
+type aBCIApplicationStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *aBCIApplicationStreamServer) Send(m *Response) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *aBCIApplicationStreamServer) Recv() (*Request, error) {
+	m := new(Request)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _ABCIApplication_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "tendermint.abci.ABCIApplication",
 	HandlerType: (*ABCIApplicationServer)(nil),
@@ -4479,7 +4570,14 @@ var _ABCIApplication_serviceDesc = grpc.ServiceDesc{
 			Handler:    _ABCIApplication_ProcessProposal_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       _ABCIApplication_Stream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "tendermint/abci/types.proto",
 }
 
@@ -5257,6 +5355,16 @@ func (m *RequestCheckTx) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Deadline != nil {
+		n24, err24 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Deadline, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Deadline):])
+		if err24 != nil {
+			return 0, err24
+		}
+		i -= n24
+		i = encodeVarintTypes(dAtA, i, uint64(n24))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.Type != 0 {
 		i = encodeVarintTypes(dAtA, i, uint64(m.Type))
 		i--
@@ -5523,12 +5631,12 @@ func (m *RequestPrepareProposal) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
-	n25, err25 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
-	if err25 != nil {
-		return 0, err25
+	n26, err26 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
+	if err26 != nil {
+		return 0, err26
 	}
-	i -= n25
-	i = encodeVarintTypes(dAtA, i, uint64(n25))
+	i -= n26
+	i = encodeVarintTypes(dAtA, i, uint64(n26))
 	i--
 	dAtA[i] = 0x2a
 	if m.Height != 0 {
@@ -6127,6 +6235,16 @@ func (m *ResponseInfo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Streaming {
+		i--
+		if m.Streaming {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
 	{
 		size, err := m.Timeouts.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -6813,20 +6931,20 @@ func (m *ResponseApplySnapshotChunk) MarshalToSizedBuffer(dAtA []byte) (int, err
 		}
 	}
 	if len(m.RefetchChunks) > 0 {
-		dAtA54 := make([]byte, len(m.RefetchChunks)*10)
-		var j53 int
+		dAtA55 := make([]byte, len(m.RefetchChunks)*10)
+		var j54 int
 		for _, num := range m.RefetchChunks {
 			for num >= 1<<7 {
-				dAtA54[j53] = uint8(uint64(num)&0x7f | 0x80)
+				dAtA55[j54] = uint8(uint64(num)&0x7f | 0x80)
 				num >>= 7
-				j53++
+				j54++
 			}
-			dAtA54[j53] = uint8(num)
-			j53++
+			dAtA55[j54] = uint8(num)
+			j54++
 		}
-		i -= j53
-		copy(dAtA[i:], dAtA54[:j53])
-		i = encodeVarintTypes(dAtA, i, uint64(j53))
+		i -= j54
+		copy(dAtA[i:], dAtA55[:j54])
+		i = encodeVarintTypes(dAtA, i, uint64(j54))
 		i--
 		dAtA[i] = 0x12
 	}
@@ -7338,12 +7456,12 @@ func (m *Evidence) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x28
 	}
-	n63, err63 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
-	if err63 != nil {
-		return 0, err63
+	n64, err64 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Time, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Time):])
+	if err64 != nil {
+		return 0, err64
 	}
-	i -= n63
-	i = encodeVarintTypes(dAtA, i, uint64(n63))
+	i -= n64
+	i = encodeVarintTypes(dAtA, i, uint64(n64))
 	i--
 	dAtA[i] = 0x22
 	if m.Height != 0 {
@@ -7389,6 +7507,15 @@ func (m *Snapshot) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.ChunkHashes) > 0 {
+		for iNdEx := len(m.ChunkHashes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ChunkHashes[iNdEx])
+			copy(dAtA[i:], m.ChunkHashes[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.ChunkHashes[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
 	if len(m.Metadata) > 0 {
 		i -= len(m.Metadata)
 		copy(dAtA[i:], m.Metadata)
@@ -7810,6 +7937,10 @@ func (m *RequestCheckTx) Size() (n int) {
 	if m.Type != 0 {
 		n += 1 + sovTypes(uint64(m.Type))
 	}
+	if m.Deadline != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.Deadline)
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -8243,6 +8374,9 @@ func (m *ResponseInfo) Size() (n int) {
 	}
 	l = m.Timeouts.Size()
 	n += 1 + l + sovTypes(uint64(l))
+	if m.Streaming {
+		n += 2
+	}
 	return n
 }
 
@@ -8785,6 +8919,12 @@ func (m *Snapshot) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if len(m.ChunkHashes) > 0 {
+		for _, b := range m.ChunkHashes {
+			l = len(b)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -10580,6 +10720,42 @@ func (m *RequestCheckTx) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deadline", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Deadline == nil {
+				m.Deadline = new(time.Time)
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(m.Deadline, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -12635,6 +12811,26 @@ func (m *ResponseInfo) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streaming", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Streaming = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -16312,6 +16508,38 @@ func (m *Snapshot) Unmarshal(dAtA []byte) error {
 				m.Metadata = []byte{}
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChunkHashes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChunkHashes = append(m.ChunkHashes, make([]byte, postIndex-iNdEx))
+			copy(m.ChunkHashes[len(m.ChunkHashes)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
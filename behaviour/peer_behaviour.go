@@ -1,6 +1,8 @@
 package behaviour
 
 import (
+	"time"
+
 	"github.com/tendermint/tendermint/p2p"
 )
 
@@ -47,3 +49,23 @@ type blockPart struct {
 func BlockPart(peerID p2p.ID, explanation string) PeerBehaviour {
 	return PeerBehaviour{peerID: peerID, reason: blockPart{explanation}}
 }
+
+type verifiedEvidence struct {
+	explanation string
+}
+
+// VerifiedEvidence returns a verifiedEvidence PeerBehaviour.
+func VerifiedEvidence(peerID p2p.ID, explanation string) PeerBehaviour {
+	return PeerBehaviour{peerID: peerID, reason: verifiedEvidence{explanation}}
+}
+
+type invalidEvidence struct {
+	explanation string
+	banTime     time.Duration
+}
+
+// InvalidEvidence returns an invalidEvidence PeerBehaviour, for a peer which
+// sent too much evidence failing verification. The peer is banned for banTime.
+func InvalidEvidence(peerID p2p.ID, explanation string, banTime time.Duration) PeerBehaviour {
+	return PeerBehaviour{peerID: peerID, reason: invalidEvidence{explanation, banTime}}
+}
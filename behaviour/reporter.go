@@ -33,12 +33,14 @@ func (spbr *SwitchReporter) Report(behaviour PeerBehaviour) error {
 	}
 
 	switch reason := behaviour.reason.(type) {
-	case consensusVote, blockPart:
+	case consensusVote, blockPart, verifiedEvidence:
 		spbr.sw.MarkPeerAsGood(peer)
 	case badMessage:
 		spbr.sw.StopPeerForError(peer, reason.explanation)
 	case messageOutOfOrder:
 		spbr.sw.StopPeerForError(peer, reason.explanation)
+	case invalidEvidence:
+		spbr.sw.BanPeerForError(peer, reason.explanation, reason.banTime)
 	default:
 		return errors.New("unknown reason reported")
 	}
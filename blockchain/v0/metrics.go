@@ -0,0 +1,124 @@
+package v0
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "blockchain"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Maximum number of blocks requested from peers and not applied yet.
+	RequestWindow metrics.Gauge
+	// Size of the blocks received from peers and not applied yet, in bytes.
+	BufferedBytes metrics.Gauge
+	// Number of block requests assigned to a peer and not answered yet.
+	PeerPendingRequests metrics.Gauge
+	// Moving average of the number of blocks applied per second.
+	BlocksPerSecond metrics.Gauge
+	// Moving average of the number of bytes of blocks downloaded per second.
+	BytesPerSecond metrics.Gauge
+	// Number of peers with pending block requests.
+	ActivePeers metrics.Gauge
+	// Number of block requests which timed out.
+	RequestTimeouts metrics.Counter
+	// Estimated time to reach the network tip in seconds, or -1 if the tip
+	// isn't being caught up with.
+	SyncETA metrics.Gauge
+
+	// the vector of PeerPendingRequests, to delete the series of the removed
+	// peers; nil unless the metrics are exported to Prometheus
+	peerPendingRequestsVec *stdprometheus.GaugeVec
+}
+
+// removePeer deletes the series of the peer from the per peer metrics, so that
+// the disconnected peers don't accumulate.
+func (m *Metrics) removePeer(peerID string) {
+	if m.peerPendingRequestsVec != nil {
+		m.peerPendingRequestsVec.DeletePartialMatch(stdprometheus.Labels{"peer_id": peerID})
+	}
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	peerPendingRequestsVec := stdprometheus.NewGaugeVec(stdprometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "peer_pending_requests",
+		Help:      "Number of block requests assigned to a peer and not answered yet.",
+	}, append(labels, "peer_id"))
+	stdprometheus.MustRegister(peerPendingRequestsVec)
+	return &Metrics{
+		RequestWindow: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "request_window",
+			Help:      "Maximum number of blocks requested from peers and not applied yet.",
+		}, labels).With(labelsAndValues...),
+		BufferedBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "buffered_bytes",
+			Help:      "Size of the blocks received from peers and not applied yet, in bytes.",
+		}, labels).With(labelsAndValues...),
+		PeerPendingRequests:    prometheus.NewGauge(peerPendingRequestsVec).With(labelsAndValues...),
+		peerPendingRequestsVec: peerPendingRequestsVec,
+		BlocksPerSecond: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "blocks_per_second",
+			Help:      "Moving average of the number of blocks applied per second.",
+		}, labels).With(labelsAndValues...),
+		BytesPerSecond: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bytes_per_second",
+			Help:      "Moving average of the number of bytes of blocks downloaded per second.",
+		}, labels).With(labelsAndValues...),
+		ActivePeers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "active_peers",
+			Help:      "Number of peers with pending block requests.",
+		}, labels).With(labelsAndValues...),
+		RequestTimeouts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "request_timeouts",
+			Help:      "Number of block requests which timed out.",
+		}, labels).With(labelsAndValues...),
+		SyncETA: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sync_eta_seconds",
+			Help:      "Estimated time to reach the network tip in seconds, or -1 if the tip isn't being caught up with.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		RequestWindow:       discard.NewGauge(),
+		BufferedBytes:       discard.NewGauge(),
+		PeerPendingRequests: discard.NewGauge(),
+		BlocksPerSecond:     discard.NewGauge(),
+		BytesPerSecond:      discard.NewGauge(),
+		ActivePeers:         discard.NewGauge(),
+		RequestTimeouts:     discard.NewCounter(),
+		SyncETA:             discard.NewGauge(),
+	}
+}
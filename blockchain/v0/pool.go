@@ -28,11 +28,22 @@ eg, L = latency = 0.1s
 */
 
 const (
-	requestIntervalMS         = 2
-	maxTotalRequesters        = 600
-	maxPendingRequests        = maxTotalRequesters
-	maxPendingRequestsPerPeer = 20
-	requestRetrySeconds       = 30
+	requestIntervalMS   = 2
+	maxTotalRequesters  = 600
+	maxPendingRequests  = maxTotalRequesters
+	requestRetrySeconds = 30
+
+	// The request window is the maximum number of requesters, i.e. of blocks
+	// requested and not applied yet. It grows while blocks are received with
+	// a low latency and applied as fast as they are received, and shrinks on
+	// timeouts or when the received blocks exceed the memory cap.
+	minRequestWindow     = 20
+	initialRequestWindow = 100
+
+	// Every peer is assigned at most as many pending requests as its own
+	// limit, which adapts to the peer like the request window.
+	initialPendingRequestsPerPeer = 20
+	maxPendingRequestsPerPeer     = 100
 
 	// Minimum recv rate to ensure we're receiving blocks from a peer fast
 	// enough. If a peer is not sending us data at at least that rate, we
@@ -48,6 +59,10 @@ const (
 
 var peerTimeout = 15 * time.Second // not const so we can override with tests
 
+// Maximum latency of block requests for which the request window and the
+// limit of pending requests of a peer grow.
+var targetRequestLatency = 2 * time.Second // not const so we can override with tests
+
 /*
 	Peers self report their heights when we join the block pool.
 	Starting from our latest pool.height, we request blocks
@@ -72,11 +87,19 @@ type BlockPool struct {
 	peers         map[p2p.ID]*bpPeer
 	maxPeerHeight int64 // the biggest reported height
 
+	// request window
+	window           int   // the maximum number of requesters
+	maxBufferedBytes int64 // the memory cap of the received blocks, 0 if unlimited
+
 	// atomic
-	numPending int32 // number of requests pending assignment or block response
+	numPending    int32        // number of requests pending assignment or block response
+	bufferedBytes atomic.Int64 // size of the received blocks not popped yet
 
 	requestsCh chan<- BlockRequest
 	errorsCh   chan<- peerError
+
+	progress syncProgress
+	metrics  *Metrics
 }
 
 // NewBlockPool returns a new BlockPool with the height equal to start. Block
@@ -89,8 +112,12 @@ func NewBlockPool(start int64, requestsCh chan<- BlockRequest, errorsCh chan<- p
 		height:     start,
 		numPending: 0,
 
+		window: initialRequestWindow,
+
 		requestsCh: requestsCh,
 		errorsCh:   errorsCh,
+
+		metrics: NopMetrics(),
 	}
 	bp.BaseService = *service.NewBaseService(nil, "BlockPool", bp)
 	return bp
@@ -99,16 +126,94 @@ func NewBlockPool(start int64, requestsCh chan<- BlockRequest, errorsCh chan<- p
 // OnStart implements service.Service by spawning requesters routine and recording
 // pool's start time.
 func (pool *BlockPool) OnStart() error {
-	go pool.makeRequestersRoutine()
+	pool.metrics.RequestWindow.Set(float64(pool.RequestWindow()))
+	go pool.makeRequestersRoutine(pool.Quit())
 	pool.startTime = time.Now()
+	pool.progress.start(pool.startTime, pool.MaxPeerHeight())
+	go pool.sampleProgressRoutine(pool.Quit())
+	return nil
+}
+
+// OnReset implements service.Service by dropping the requesters and the
+// pending requests of the peers, so that the pool can be started again after
+// falling behind consensus. The caller sets the height to sync from.
+func (pool *BlockPool) OnReset() error {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	for height, requester := range pool.requesters {
+		if requester.IsRunning() {
+			if err := requester.Stop(); err != nil {
+				pool.Logger.Error("Error stopping requester", "height", height, "err", err)
+			}
+		}
+		delete(pool.requesters, height)
+	}
+	atomic.StoreInt32(&pool.numPending, 0)
+	pool.bufferedBytes.Store(0)
+	pool.window = initialRequestWindow
+
+	for _, peer := range pool.peers {
+		if peer.timeout != nil {
+			peer.timeout.Stop()
+		}
+		peer.numPending = 0
+		peer.maxPending = initialPendingRequestsPerPeer
+		peer.updatePendingMetric()
+	}
+	pool.metrics.BufferedBytes.Set(0)
 	return nil
 }
 
-// spawns requesters as needed
-func (pool *BlockPool) makeRequestersRoutine() {
+// samples the sync progress, until quit is closed when the pool is stopped
+func (pool *BlockPool) sampleProgressRoutine(quit <-chan struct{}) {
+	ticker := time.NewTicker(progressSampleInterval)
+	defer ticker.Stop()
 	for {
-		if !pool.IsRunning() {
-			break
+		select {
+		case <-quit:
+			return
+		case now := <-ticker.C:
+			pool.sampleProgress(now)
+		}
+	}
+}
+
+func (pool *BlockPool) sampleProgress(now time.Time) {
+	pool.progress.sample(now, pool.MaxPeerHeight())
+	progress := pool.Progress()
+	pool.metrics.BlocksPerSecond.Set(progress.BlocksPerSecond)
+	pool.metrics.BytesPerSecond.Set(progress.BytesPerSecond)
+	pool.metrics.ActivePeers.Set(float64(progress.ActivePeers))
+	pool.metrics.SyncETA.Set(progress.ETA.Seconds())
+}
+
+// Progress returns the progress of the sync.
+func (pool *BlockPool) Progress() Progress {
+	pool.mtx.Lock()
+	height, maxPeerHeight := pool.height, pool.maxPeerHeight
+	activePeers := 0
+	for _, peer := range pool.peers {
+		if peer.numPending > 0 {
+			activePeers++
+		}
+	}
+	pool.mtx.Unlock()
+
+	progress := pool.progress.progress(height, maxPeerHeight)
+	progress.Syncing = pool.IsRunning()
+	progress.ActivePeers = activePeers
+	return progress
+}
+
+// spawns requesters as needed, until quit is closed when the pool is stopped.
+// The pool may be reset and started again before the routine notices.
+func (pool *BlockPool) makeRequestersRoutine(quit <-chan struct{}) {
+	for {
+		select {
+		case <-quit:
+			return
+		default:
 		}
 
 		_, numPending, lenRequesters := pool.GetStatus()
@@ -118,14 +223,14 @@ func (pool *BlockPool) makeRequestersRoutine() {
 			time.Sleep(requestIntervalMS * time.Millisecond)
 			// check for timed out peers
 			pool.removeTimedoutPeers()
-		case lenRequesters >= maxTotalRequesters:
+		case lenRequesters >= pool.RequestWindow(), pool.isBufferFull():
 			// sleep for a bit.
 			time.Sleep(requestIntervalMS * time.Millisecond)
 			// check for timed out peers
 			pool.removeTimedoutPeers()
 		default:
 			// request for more blocks.
-			pool.makeNextRequester()
+			pool.makeNextRequester(quit)
 		}
 	}
 }
@@ -146,6 +251,8 @@ func (pool *BlockPool) removeTimedoutPeers() {
 					"curRate", fmt.Sprintf("%d KB/s", curRate/1024),
 					"minRate", fmt.Sprintf("%d KB/s", minRecvRate/1024))
 				peer.didTimeout = true
+				pool.shrinkWindow()
+				pool.recordTimeout()
 			}
 		}
 		if peer.didTimeout {
@@ -163,6 +270,81 @@ func (pool *BlockPool) GetStatus() (height int64, numPending int32, lenRequester
 	return pool.height, atomic.LoadInt32(&pool.numPending), len(pool.requesters)
 }
 
+// RequestWindow returns the current maximum number of requesters.
+func (pool *BlockPool) RequestWindow() int {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	return pool.window
+}
+
+// BufferedBytes returns the size of the received blocks which weren't popped
+// yet.
+func (pool *BlockPool) BufferedBytes() int64 {
+	return pool.bufferedBytes.Load()
+}
+
+func (pool *BlockPool) isBufferFull() bool {
+	return pool.maxBufferedBytes > 0 && pool.bufferedBytes.Load() >= pool.maxBufferedBytes
+}
+
+func (pool *BlockPool) recordTimeout() {
+	pool.progress.requestTimedOut()
+	pool.metrics.RequestTimeouts.Add(1)
+}
+
+// shrinkWindow halves the request window. Requesters beyond the window are
+// not stopped, but new ones aren't made until their number is within it.
+func (pool *BlockPool) shrinkWindow() {
+	pool.window /= 2
+	if pool.window < minRequestWindow {
+		pool.window = minRequestWindow
+	}
+	pool.metrics.RequestWindow.Set(float64(pool.window))
+}
+
+// blockReceived accounts for a block received from a peer. The limit of
+// pending requests of the peer grows if its latency is low, and the request
+// window shrinks when the received blocks exceed the memory cap.
+func (pool *BlockPool) blockReceived(peer *bpPeer, latency time.Duration, blockSize int) {
+	pool.progress.blockDownloaded(blockSize)
+	buffered := pool.bufferedBytes.Add(int64(blockSize))
+	pool.metrics.BufferedBytes.Set(float64(buffered))
+	if pool.maxBufferedBytes > 0 && buffered > pool.maxBufferedBytes &&
+		buffered-int64(blockSize) <= pool.maxBufferedBytes {
+		pool.Logger.Debug("Received blocks exceed the memory cap, shrinking request window",
+			"buffered", buffered, "max", pool.maxBufferedBytes)
+		pool.shrinkWindow()
+	}
+	if peer == nil {
+		return
+	}
+	peer.updateLatency(latency)
+	if peer.latency <= targetRequestLatency && peer.maxPending < maxPendingRequestsPerPeer {
+		peer.maxPending++
+	}
+}
+
+// blockApplied accounts for a block popped after it was applied. The request
+// window grows if the block was received with a low latency, as long as the
+// application of blocks keeps up, i.e. fewer blocks wait to be applied than
+// are requested, and the received blocks stay well within the memory cap.
+func (pool *BlockPool) blockApplied(latency time.Duration, blockSize int) {
+	pool.progress.blockApplied()
+	buffered := pool.bufferedBytes.Add(-int64(blockSize))
+	pool.metrics.BufferedBytes.Set(float64(buffered))
+	if latency > targetRequestLatency || pool.window >= maxTotalRequesters {
+		return
+	}
+	numPending := int(atomic.LoadInt32(&pool.numPending))
+	applyKeepsUp := len(pool.requesters)-numPending < numPending
+	withinCap := pool.maxBufferedBytes == 0 || buffered <= pool.maxBufferedBytes/2
+	if applyKeepsUp && withinCap {
+		pool.window++
+		pool.metrics.RequestWindow.Set(float64(pool.window))
+	}
+}
+
 // IsCaughtUp returns true if this node is caught up, false - otherwise.
 // TODO: relax conditions, prevent abuse.
 func (pool *BlockPool) IsCaughtUp() bool {
@@ -203,6 +385,18 @@ func (pool *BlockPool) PeekTwoBlocks() (first *types.Block, second *types.Block)
 	return
 }
 
+// PeekBlock returns the block at the given height, or nil if it wasn't
+// received yet.
+func (pool *BlockPool) PeekBlock(height int64) *types.Block {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	if r := pool.requesters[height]; r != nil {
+		return r.getBlock()
+	}
+	return nil
+}
+
 // PopRequest pops the first block at pool.height.
 // It must have been validated by 'second'.Commit from PeekTwoBlocks().
 func (pool *BlockPool) PopRequest() {
@@ -219,6 +413,9 @@ func (pool *BlockPool) PopRequest() {
 			pool.Logger.Error("Error stopping requester", "err", err)
 		}
 		delete(pool.requesters, pool.height)
+		if r.getBlock() != nil {
+			pool.blockApplied(r.getLatency(), r.getBlockSize())
+		}
 		pool.height++
 	} else {
 		panic(fmt.Sprintf("Expected requester to pop, got nothing at height %v", pool.height))
@@ -267,12 +464,13 @@ func (pool *BlockPool) AddBlock(peerID p2p.ID, block *types.Block, blockSize int
 		return
 	}
 
-	if requester.setBlock(block, peerID) {
+	if requester.setBlock(block, peerID, blockSize) {
 		atomic.AddInt32(&pool.numPending, -1)
 		peer := pool.peers[peerID]
 		if peer != nil {
 			peer.decrPending(blockSize)
 		}
+		pool.blockReceived(peer, requester.getLatency(), blockSize)
 	} else {
 		pool.Logger.Info("invalid peer", "peer", peerID, "blockHeight", block.Height)
 		pool.sendError(errors.New("invalid peer"), peerID)
@@ -286,15 +484,23 @@ func (pool *BlockPool) MaxPeerHeight() int64 {
 	return pool.maxPeerHeight
 }
 
-// SetPeerRange sets the peer's alleged blockchain base and height.
+// SetPeerRange sets the peer's alleged blockchain base and height. Blocks are
+// only requested from peers whose range covers the height, hence requests
+// assigned to the peer for heights outside of its new range are redone with
+// other peers.
 func (pool *BlockPool) SetPeerRange(peerID p2p.ID, base int64, height int64) {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
 	peer := pool.peers[peerID]
 	if peer != nil {
+		prevHeight := peer.height
 		peer.base = base
 		peer.height = height
+		pool.redoUncoveredRequests(peer)
+		if prevHeight == pool.maxPeerHeight && height < prevHeight {
+			pool.updateMaxPeerHeight()
+		}
 	} else {
 		peer = newBPPeer(pool, peerID, base, height)
 		peer.setLogger(pool.Logger.With("peer", peerID))
@@ -306,6 +512,73 @@ func (pool *BlockPool) SetPeerRange(peerID p2p.ID, base int64, height int64) {
 	}
 }
 
+// AddNoBlock handles a peer's response that it doesn't have the block at the
+// given height, e.g. because the peer pruned it since it reported its range.
+// The peer isn't banned, but it isn't asked for blocks up to that height
+// anymore until it reports its range again, and the request is redone with
+// other peers.
+func (pool *BlockPool) AddNoBlock(peerID p2p.ID, height int64) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	peer := pool.peers[peerID]
+	if peer == nil || height < peer.base {
+		return
+	}
+	peer.base = height + 1
+	pool.redoUncoveredRequests(peer)
+}
+
+// redoUncoveredRequests redoes the pending requests assigned to the peer for
+// heights outside of its range.
+func (pool *BlockPool) redoUncoveredRequests(peer *bpPeer) {
+	for height, requester := range pool.requesters {
+		if height >= peer.base && height <= peer.height {
+			continue
+		}
+		if requester.getPeerID() == peer.id && requester.getBlock() == nil {
+			peer.logger.Debug("Peer doesn't have the requested block anymore, redoing request",
+				"height", height, "base", peer.base, "peerHeight", peer.height)
+			requester.redo(peer.id)
+		}
+	}
+}
+
+// releasePeer decrements the number of pending requests of a peer after a
+// request assigned to it was redone. It's a no-op if the peer was removed.
+func (pool *BlockPool) releasePeer(peerID p2p.ID) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	peer := pool.peers[peerID]
+	if peer == nil || peer.numPending == 0 {
+		return
+	}
+	peer.numPending--
+	peer.updatePendingMetric()
+	if peer.numPending == 0 {
+		peer.timeout.Stop()
+	}
+}
+
+// requestTimedOut shrinks the request window and halves the limit of pending
+// requests of a peer after a request assigned to it timed out, and releases
+// the request.
+func (pool *BlockPool) requestTimedOut(peerID p2p.ID) {
+	pool.mtx.Lock()
+	pool.shrinkWindow()
+	pool.recordTimeout()
+	if peer := pool.peers[peerID]; peer != nil {
+		peer.maxPending /= 2
+		if peer.maxPending < 1 {
+			peer.maxPending = 1
+		}
+	}
+	pool.mtx.Unlock()
+
+	pool.releasePeer(peerID)
+}
+
 // RemovePeer removes the peer with peerID from the pool. If there's no peer
 // with peerID, function is a no-op.
 func (pool *BlockPool) RemovePeer(peerID p2p.ID) {
@@ -329,6 +602,7 @@ func (pool *BlockPool) removePeer(peerID p2p.ID) {
 		}
 
 		delete(pool.peers, peerID)
+		pool.metrics.removePeer(string(peerID))
 
 		// Find a new peer with the biggest height and update maxPeerHeight if the
 		// peer's height was the biggest.
@@ -360,7 +634,7 @@ func (pool *BlockPool) pickIncrAvailablePeer(height int64) *bpPeer {
 			pool.removePeer(peer.id)
 			continue
 		}
-		if peer.numPending >= maxPendingRequestsPerPeer {
+		if peer.numPending >= peer.maxPending {
 			continue
 		}
 		if height < peer.base || height > peer.height {
@@ -372,7 +646,10 @@ func (pool *BlockPool) pickIncrAvailablePeer(height int64) *bpPeer {
 	return nil
 }
 
-func (pool *BlockPool) makeNextRequester() {
+// makeNextRequester starts a requester for the next height. The requester
+// stops once quit, the quit channel of the pool when it was started, is
+// closed.
+func (pool *BlockPool) makeNextRequester(quit <-chan struct{}) {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
@@ -381,7 +658,7 @@ func (pool *BlockPool) makeNextRequester() {
 		return
 	}
 
-	request := newBPRequester(pool, nextHeight)
+	request := newBPRequester(pool, nextHeight, quit)
 
 	pool.requesters[nextHeight] = request
 	atomic.AddInt32(&pool.numPending, 1)
@@ -435,6 +712,8 @@ func (pool *BlockPool) debug() string {
 type bpPeer struct {
 	didTimeout  bool
 	numPending  int32
+	maxPending  int32         // the limit of pending requests
+	latency     time.Duration // moving average of the latency of block requests
 	height      int64
 	base        int64
 	pool        *BlockPool
@@ -453,6 +732,7 @@ func newBPPeer(pool *BlockPool, peerID p2p.ID, base int64, height int64) *bpPeer
 		base:       base,
 		height:     height,
 		numPending: 0,
+		maxPending: initialPendingRequestsPerPeer,
 		logger:     log.NewNopLogger(),
 	}
 	return peer
@@ -482,10 +762,12 @@ func (peer *bpPeer) incrPending() {
 		peer.resetTimeout()
 	}
 	peer.numPending++
+	peer.updatePendingMetric()
 }
 
 func (peer *bpPeer) decrPending(recvSize int) {
 	peer.numPending--
+	peer.updatePendingMetric()
 	if peer.numPending == 0 {
 		peer.timeout.Stop()
 	} else {
@@ -494,6 +776,19 @@ func (peer *bpPeer) decrPending(recvSize int) {
 	}
 }
 
+func (peer *bpPeer) updatePendingMetric() {
+	peer.pool.metrics.PeerPendingRequests.With("peer_id", string(peer.id)).Set(float64(peer.numPending))
+}
+
+// updateLatency adds the latency of a block request to the moving average.
+func (peer *bpPeer) updateLatency(latency time.Duration) {
+	if peer.latency == 0 {
+		peer.latency = latency
+		return
+	}
+	peer.latency = (4*peer.latency + latency) / 5
+}
+
 func (peer *bpPeer) onTimeout() {
 	peer.pool.mtx.Lock()
 	defer peer.pool.mtx.Unlock()
@@ -502,6 +797,8 @@ func (peer *bpPeer) onTimeout() {
 	peer.pool.sendError(err, peer.id)
 	peer.logger.Error("SendTimeout", "reason", err, "timeout", peerTimeout)
 	peer.didTimeout = true
+	peer.pool.shrinkWindow()
+	peer.pool.recordTimeout()
 }
 
 //-------------------------------------
@@ -511,18 +808,25 @@ type bpRequester struct {
 	pool       *BlockPool
 	height     int64
 	gotBlockCh chan struct{}
-	redoCh     chan p2p.ID // redo may send multitime, add peerId to identify repeat
+	// poolQuit is the quit channel of the pool, kept from the start of the
+	// requester since resetting the pool replaces it.
+	poolQuit <-chan struct{}
+	redoCh   chan p2p.ID // redo may send multitime, add peerId to identify repeat
 
-	mtx    cmtsync.Mutex
-	peerID p2p.ID
-	block  *types.Block
+	mtx         cmtsync.Mutex
+	peerID      p2p.ID
+	requestTime time.Time
+	block       *types.Block
+	blockSize   int
+	latency     time.Duration // between the request and the block
 }
 
-func newBPRequester(pool *BlockPool, height int64) *bpRequester {
+func newBPRequester(pool *BlockPool, height int64, poolQuit <-chan struct{}) *bpRequester {
 	bpr := &bpRequester{
 		pool:       pool,
 		height:     height,
 		gotBlockCh: make(chan struct{}, 1),
+		poolQuit:   poolQuit,
 		redoCh:     make(chan p2p.ID, 1),
 
 		peerID: "",
@@ -538,13 +842,15 @@ func (bpr *bpRequester) OnStart() error {
 }
 
 // Returns true if the peer matches and block doesn't already exist.
-func (bpr *bpRequester) setBlock(block *types.Block, peerID p2p.ID) bool {
+func (bpr *bpRequester) setBlock(block *types.Block, peerID p2p.ID, blockSize int) bool {
 	bpr.mtx.Lock()
 	if bpr.block != nil || bpr.peerID != peerID {
 		bpr.mtx.Unlock()
 		return false
 	}
 	bpr.block = block
+	bpr.blockSize = blockSize
+	bpr.latency = time.Since(bpr.requestTime)
 	bpr.mtx.Unlock()
 
 	select {
@@ -560,23 +866,41 @@ func (bpr *bpRequester) getBlock() *types.Block {
 	return bpr.block
 }
 
+func (bpr *bpRequester) getBlockSize() int {
+	bpr.mtx.Lock()
+	defer bpr.mtx.Unlock()
+	return bpr.blockSize
+}
+
 func (bpr *bpRequester) getPeerID() p2p.ID {
 	bpr.mtx.Lock()
 	defer bpr.mtx.Unlock()
 	return bpr.peerID
 }
 
-// This is called from the requestRoutine, upon redo().
-func (bpr *bpRequester) reset() {
+func (bpr *bpRequester) getLatency() time.Duration {
+	bpr.mtx.Lock()
+	defer bpr.mtx.Unlock()
+	return bpr.latency
+}
+
+// This is called from the requestRoutine, upon redo(). It returns the peer
+// the block was requested from, if the block wasn't received from it yet.
+func (bpr *bpRequester) reset() (pendingPeerID p2p.ID) {
 	bpr.mtx.Lock()
 	defer bpr.mtx.Unlock()
 
 	if bpr.block != nil {
 		atomic.AddInt32(&bpr.pool.numPending, 1)
+		bpr.pool.metrics.BufferedBytes.Set(float64(bpr.pool.bufferedBytes.Add(-int64(bpr.blockSize))))
+	} else {
+		pendingPeerID = bpr.peerID
 	}
 
 	bpr.peerID = ""
 	bpr.block = nil
+	bpr.blockSize = 0
+	return pendingPeerID
 }
 
 // Tells bpRequester to pick another peer and try again.
@@ -609,8 +933,14 @@ OUTER_LOOP:
 			}
 			break PICK_PEER_LOOP
 		}
+		// the pool may have been reset while the peer was picked
+		if !bpr.IsRunning() {
+			bpr.pool.releasePeer(peer.id)
+			return
+		}
 		bpr.mtx.Lock()
 		bpr.peerID = peer.id
+		bpr.requestTime = time.Now()
 		bpr.mtx.Unlock()
 
 		to := time.NewTimer(requestRetrySeconds * time.Second)
@@ -619,7 +949,7 @@ OUTER_LOOP:
 	WAIT_LOOP:
 		for {
 			select {
-			case <-bpr.pool.Quit():
+			case <-bpr.poolQuit:
 				if err := bpr.Stop(); err != nil {
 					bpr.Logger.Error("Error stopped requester", "err", err)
 				}
@@ -629,11 +959,15 @@ OUTER_LOOP:
 			case <-to.C:
 				bpr.Logger.Debug("Retrying block request after timeout", "height", bpr.height, "peer", bpr.peerID)
 				// Simulate a redo
-				bpr.reset()
+				if peerID := bpr.reset(); peerID != "" {
+					bpr.pool.requestTimedOut(peerID)
+				}
 				continue OUTER_LOOP
 			case peerID := <-bpr.redoCh:
 				if peerID == bpr.peerID {
-					bpr.reset()
+					if peerID := bpr.reset(); peerID != "" {
+						bpr.pool.releasePeer(peerID)
+					}
 					continue OUTER_LOOP
 				} else {
 					continue WAIT_LOOP
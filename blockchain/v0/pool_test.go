@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -16,6 +18,8 @@ import (
 
 func init() {
 	peerTimeout = 2 * time.Second
+	targetRequestLatency = 100 * time.Millisecond
+	progressSampleInterval = 50 * time.Millisecond
 }
 
 type testPeer struct {
@@ -79,7 +83,9 @@ func makePeers(numPeers int, minHeight, maxHeight int64) testPeers {
 
 func TestBlockPoolBasic(t *testing.T) {
 	start := int64(42)
-	peers := makePeers(10, start+1, 1000)
+	// the pool requests at most a window of blocks ahead of the ones popped,
+	// so a peer must have the first block
+	peers := makePeers(10, start, 1000)
 	errorsCh := make(chan peerError, 1000)
 	requestsCh := make(chan BlockRequest, 1000)
 	pool := NewBlockPool(start, requestsCh, errorsCh)
@@ -114,6 +120,8 @@ func TestBlockPoolBasic(t *testing.T) {
 			}
 			first, second := pool.PeekTwoBlocks()
 			if first != nil && second != nil {
+				assert.Equal(t, first, pool.PeekBlock(first.Height))
+				assert.Equal(t, second, pool.PeekBlock(first.Height+1))
 				pool.PopRequest()
 			} else {
 				time.Sleep(1 * time.Second)
@@ -240,3 +248,369 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	assert.EqualValues(t, 0, pool.MaxPeerHeight())
 }
+
+func TestBlockPoolPrunedPeers(t *testing.T) {
+	requestsCh := make(chan BlockRequest, 1000)
+	errorsCh := make(chan peerError, 1000)
+	pool := NewBlockPool(1, requestsCh, errorsCh)
+	pool.SetLogger(log.TestingLogger())
+	require.NoError(t, pool.Start())
+	t.Cleanup(func() {
+		if err := pool.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// a peer which pruned its early blocks after reporting its range is
+	// assigned requests it may not be able to serve
+	pool.SetPeerRange("pruned", 1, 100)
+	uncovered := 0
+	var covered []BlockRequest
+	for i := 0; i < initialPendingRequestsPerPeer; i++ {
+		request := <-requestsCh
+		require.EqualValues(t, "pruned", request.PeerID)
+		if request.Height < 50 {
+			uncovered++
+		} else {
+			covered = append(covered, request)
+		}
+	}
+
+	// once it reports its new range, the requests outside of it are released,
+	// so it is assigned as many requests within its range
+	pool.SetPeerRange("pruned", 50, 100)
+	for i := 0; i < uncovered; i++ {
+		request := <-requestsCh
+		require.EqualValues(t, "pruned", request.PeerID)
+		require.GreaterOrEqual(t, request.Height, int64(50))
+		covered = append(covered, request)
+	}
+
+	// an archive peer, and a peer which reports its range before it pruned,
+	// but responds that it doesn't have the blocks below 60
+	pool.SetPeerRange("archive", 1, 100)
+	pool.SetPeerRange("stale", 1, 100)
+	for _, request := range covered {
+		pool.AddBlock(request.PeerID, &types.Block{Header: types.Header{Height: request.Height}}, 123)
+	}
+
+	go func() {
+		for {
+			if !pool.IsRunning() {
+				return
+			}
+			first, second := pool.PeekTwoBlocks()
+			if first != nil && second != nil {
+				pool.PopRequest()
+			} else {
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}()
+
+	noBlocks := 0
+	timeout := time.After(10 * time.Second)
+	for {
+		if height, _, _ := pool.GetStatus(); height == 100 {
+			break
+		}
+		select {
+		case err := <-errorsCh:
+			require.Fail(t, "peer was stopped", err.Error())
+		case request := <-requestsCh:
+			switch {
+			case request.PeerID == "pruned":
+				require.GreaterOrEqual(t, request.Height, int64(50), "pruned block requested")
+			case request.PeerID == "stale" && request.Height < 60:
+				pool.AddNoBlock(request.PeerID, request.Height)
+				noBlocks++
+				continue
+			}
+			block := &types.Block{Header: types.Header{Height: request.Height}}
+			pool.AddBlock(request.PeerID, block, 123)
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			require.FailNow(t, "timed out syncing blocks")
+		}
+	}
+
+	// the stale peer's missing blocks were fetched from the archive peer, and
+	// no peer was banned
+	assert.Positive(t, noBlocks)
+	assert.Len(t, pool.peers, 3)
+	assert.Empty(t, errorsCh)
+}
+
+// simPeer simulates a peer which responds to block requests after its
+// latency.
+type simPeer struct {
+	id      p2p.ID
+	latency time.Duration
+}
+
+// simResult summarizes a simulated sync.
+type simResult struct {
+	maxWindow        int
+	maxBufferedBytes int64
+	maxActivePeers   int
+	maxETA           time.Duration
+}
+
+// simulateSync syncs blocks from the simulated peers up to height, with the
+// given block sizes, applying every block after applyDelay. It returns once
+// the pool reached the height, and stops applying blocks.
+func simulateSync(
+	t *testing.T,
+	pool *BlockPool,
+	peers []simPeer,
+	height int64,
+	blockSize func(height int64) int,
+	applyDelay time.Duration,
+) simResult {
+	requestsCh := make(chan BlockRequest, maxTotalRequesters)
+	errorsCh := make(chan peerError, 1000)
+	pool.requestsCh = requestsCh
+	pool.errorsCh = errorsCh
+	pool.SetLogger(log.TestingLogger())
+	require.NoError(t, pool.Start())
+	t.Cleanup(func() {
+		if err := pool.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	latencies := make(map[p2p.ID]time.Duration, len(peers))
+	for _, peer := range peers {
+		latencies[peer.id] = peer.latency
+		pool.SetPeerRange(peer.id, 1, height+1)
+	}
+
+	// the blocks are applied up to height only, and until simulateSync
+	// returns, so that the pool stays at height once it reached it
+	stop, applied := make(chan struct{}), make(chan struct{})
+	defer func() {
+		close(stop)
+		<-applied
+	}()
+	go func() {
+		defer close(applied)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			first, second := pool.PeekTwoBlocks()
+			if h, _, _ := pool.GetStatus(); first == nil || second == nil || h >= height {
+				time.Sleep(time.Millisecond)
+				continue
+			}
+			time.Sleep(applyDelay)
+			pool.PopRequest()
+		}
+	}()
+
+	result := simResult{}
+	timeout := time.After(30 * time.Second)
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case request := <-requestsCh:
+			go func() {
+				time.Sleep(latencies[request.PeerID])
+				block := &types.Block{Header: types.Header{Height: request.Height}}
+				pool.AddBlock(request.PeerID, block, blockSize(request.Height))
+			}()
+		case err := <-errorsCh:
+			require.Fail(t, "peer was stopped", err.Error())
+		case <-ticker.C:
+			if window := pool.RequestWindow(); window > result.maxWindow {
+				result.maxWindow = window
+			}
+			if buffered := pool.BufferedBytes(); buffered > result.maxBufferedBytes {
+				result.maxBufferedBytes = buffered
+			}
+			progress := pool.Progress()
+			if progress.ActivePeers > result.maxActivePeers {
+				result.maxActivePeers = progress.ActivePeers
+			}
+			if progress.ETA > result.maxETA {
+				result.maxETA = progress.ETA
+			}
+			if h, _, _ := pool.GetStatus(); h >= height {
+				return result
+			}
+		case <-timeout:
+			require.FailNow(t, "timed out syncing blocks")
+		}
+	}
+}
+
+func TestBlockPoolAdaptiveWindow(t *testing.T) {
+	fast := []simPeer{{"a", time.Millisecond}, {"b", 2 * time.Millisecond}, {"c", 5 * time.Millisecond}}
+	slow := []simPeer{{"a", 200 * time.Millisecond}, {"b", 300 * time.Millisecond}}
+	smallBlocks := func(int64) int { return 1000 }
+	varyingBlocks := func(height int64) int { return 1000 * int(1+height%10) }
+
+	testcases := map[string]struct {
+		peers            []simPeer
+		blockSize        func(int64) int
+		applyDelay       time.Duration
+		maxBufferedBytes int64
+		expect           func(t *testing.T, pool *BlockPool, result simResult)
+	}{
+		"fast peers and fast apply grow the window": {
+			fast, smallBlocks, 0, 0,
+			func(t *testing.T, pool *BlockPool, result simResult) {
+				assert.Greater(t, result.maxWindow, initialRequestWindow)
+			},
+		},
+		"slow peers don't grow the window": {
+			slow, smallBlocks, 0, 0,
+			func(t *testing.T, pool *BlockPool, result simResult) {
+				assert.Equal(t, initialRequestWindow, result.maxWindow)
+			},
+		},
+		"slow apply doesn't grow the window": {
+			fast, smallBlocks, 10 * time.Millisecond, 0,
+			func(t *testing.T, pool *BlockPool, result simResult) {
+				assert.Less(t, result.maxWindow, initialRequestWindow+10)
+			},
+		},
+		"large blocks exceeding the memory cap shrink the window": {
+			fast, varyingBlocks, 5 * time.Millisecond, 50000,
+			func(t *testing.T, pool *BlockPool, result simResult) {
+				assert.Less(t, pool.RequestWindow(), initialRequestWindow)
+				// blocks requested before the cap was reached may still exceed it
+				assert.Less(t, result.maxBufferedBytes, int64(50000+10000*initialRequestWindow))
+			},
+		},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			pool := NewBlockPool(1, nil, nil)
+			pool.maxBufferedBytes = tc.maxBufferedBytes
+			result := simulateSync(t, pool, tc.peers, 300, tc.blockSize, tc.applyDelay)
+			tc.expect(t, pool, result)
+		})
+	}
+}
+
+func TestBlockPoolRequestTimedOut(t *testing.T) {
+	pool := NewBlockPool(1, make(chan BlockRequest, 1), make(chan peerError, 1))
+	pool.SetPeerRange("a", 1, 100)
+	peer := pool.peers["a"]
+	peer.incrPending()
+	t.Cleanup(func() { peer.timeout.Stop() })
+
+	// a timed out request halves the window and the limit of the peer
+	pool.requestTimedOut("a")
+	assert.Equal(t, initialRequestWindow/2, pool.RequestWindow())
+	assert.EqualValues(t, initialPendingRequestsPerPeer/2, peer.maxPending)
+	assert.Zero(t, peer.numPending)
+
+	// but not below their minimum
+	for i := 0; i < 10; i++ {
+		pool.requestTimedOut("a")
+	}
+	assert.Equal(t, minRequestWindow, pool.RequestWindow())
+	assert.EqualValues(t, 1, peer.maxPending)
+}
+
+func TestBlockPoolReset(t *testing.T) {
+	requestsCh := make(chan BlockRequest, 1000)
+	pool := NewBlockPool(1, requestsCh, make(chan peerError, 1000))
+	pool.SetLogger(log.TestingLogger())
+	pool.SetPeerRange("a", 1, 1000)
+	require.NoError(t, pool.Start())
+	request := <-requestsCh
+	assert.Less(t, request.Height, int64(500))
+
+	// once stopped and reset, the requesters and pending requests are dropped
+	require.NoError(t, pool.Stop())
+	require.NoError(t, pool.Reset())
+	_, numPending, lenRequesters := pool.GetStatus()
+	assert.Zero(t, numPending)
+	assert.Zero(t, lenRequesters)
+	assert.Zero(t, pool.peers["a"].numPending)
+
+	// and the pool syncs from the height it is started from again. Requests
+	// sent by requesters before they were stopped may still be received.
+	pool.height = 500
+	require.NoError(t, pool.Start())
+	t.Cleanup(func() {
+		if err := pool.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	timeout := time.After(5 * time.Second)
+	for request.Height != 500 {
+		select {
+		case request = <-requestsCh:
+		case <-timeout:
+			t.Fatal("block at height 500 wasn't requested")
+		}
+	}
+	_, _, lenRequesters = pool.GetStatus()
+	assert.Positive(t, lenRequesters)
+	pool.mtx.Lock()
+	_, ok := pool.requesters[499]
+	pool.mtx.Unlock()
+	assert.False(t, ok)
+}
+
+func TestBlockPoolProgressMetrics(t *testing.T) {
+	metrics := &Metrics{
+		RequestWindow:       generic.NewGauge("request_window"),
+		BufferedBytes:       generic.NewGauge("buffered_bytes"),
+		PeerPendingRequests: generic.NewGauge("peer_pending_requests"),
+		BlocksPerSecond:     generic.NewGauge("blocks_per_second"),
+		BytesPerSecond:      generic.NewGauge("bytes_per_second"),
+		ActivePeers:         generic.NewGauge("active_peers"),
+		RequestTimeouts:     generic.NewCounter("request_timeouts"),
+		SyncETA:             generic.NewGauge("sync_eta_seconds"),
+	}
+	pool := NewBlockPool(1, nil, nil)
+	pool.metrics = metrics
+	peers := []simPeer{{"a", time.Millisecond}, {"b", 5 * time.Millisecond}}
+	result := simulateSync(t, pool, peers, 300, func(int64) int { return 1000 }, 2*time.Millisecond)
+
+	// the rates were measured, and the network tip was approached while
+	// syncing
+	blockRate := metrics.BlocksPerSecond.(*generic.Gauge).Value()
+	assert.Positive(t, blockRate)
+	assert.Positive(t, metrics.BytesPerSecond.(*generic.Gauge).Value())
+	assert.Equal(t, 2, result.maxActivePeers)
+	assert.Positive(t, result.maxETA)
+	assert.Zero(t, metrics.RequestTimeouts.(*generic.Counter).Value())
+
+	progress := pool.Progress()
+	assert.True(t, progress.Syncing)
+	assert.EqualValues(t, 300, progress.Height)
+	assert.EqualValues(t, 301, progress.MaxPeerHeight)
+	assert.Zero(t, progress.ETA)
+
+	// timeouts are counted
+	pool.requestTimedOut("a")
+	assert.EqualValues(t, 1, metrics.RequestTimeouts.(*generic.Counter).Value())
+	assert.EqualValues(t, 1, pool.Progress().RequestTimeouts)
+}
+
+func TestBlockPoolRemovePeerMetrics(t *testing.T) {
+	metrics := PrometheusMetrics("bcv0_remove_peer_test")
+	pool := NewBlockPool(1, nil, nil)
+	pool.metrics = metrics
+
+	pool.SetPeerRange("a", 1, 10)
+	pool.SetPeerRange("b", 1, 10)
+	metrics.PeerPendingRequests.With("peer_id", "a").Set(1)
+	metrics.PeerPendingRequests.With("peer_id", "b").Set(2)
+	require.Equal(t, 2, testutil.CollectAndCount(metrics.peerPendingRequestsVec))
+
+	// the series of the removed peer is deleted, not set to 0
+	pool.RemovePeer("a")
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.peerPendingRequestsVec))
+	assert.EqualValues(t, 2, testutil.ToFloat64(metrics.peerPendingRequestsVec.WithLabelValues("b")))
+}
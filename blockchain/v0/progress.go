@@ -0,0 +1,135 @@
+package v0
+
+import (
+	"time"
+
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+var progressSampleInterval = time.Second // not const so we can override with tests
+
+// progressSmoothing is the weight of the latest sample in the moving averages
+// of the sync progress.
+const progressSmoothing = 0.2
+
+// Progress is a snapshot of the progress of fast sync.
+type Progress struct {
+	// Syncing is true while fast sync is running.
+	Syncing bool
+	// Height is the height of the next block to apply, and MaxPeerHeight the
+	// highest height reported by peers, i.e. the network tip.
+	Height        int64
+	MaxPeerHeight int64
+	// BlocksPerSecond and BytesPerSecond are moving averages of the rate at
+	// which blocks are applied and downloaded.
+	BlocksPerSecond float64
+	BytesPerSecond  float64
+	// ActivePeers is the number of peers with pending block requests.
+	ActivePeers int
+	// RequestTimeouts is the number of block requests which timed out.
+	RequestTimeouts int64
+	// ETA is the estimated time to reach the network tip, taking into account
+	// that it keeps moving, or -1 if the tip isn't being caught up with.
+	ETA time.Duration
+}
+
+// syncProgress tracks the rates at which blocks are applied and downloaded,
+// and at which the network tip moves, as moving averages sampled at regular
+// intervals.
+type syncProgress struct {
+	mtx cmtsync.Mutex
+
+	// since the last sample
+	appliedBlocks   int64
+	downloadedBytes int64
+
+	lastSample    time.Time
+	lastTipHeight int64
+	sampled       bool
+
+	blockRate float64
+	byteRate  float64
+	tipRate   float64
+	timeouts  int64
+}
+
+func (p *syncProgress) blockApplied() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.appliedBlocks++
+}
+
+func (p *syncProgress) blockDownloaded(size int) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.downloadedBytes += int64(size)
+}
+
+func (p *syncProgress) requestTimedOut() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.timeouts++
+}
+
+// start sets the time and tip height the first sample is measured from.
+func (p *syncProgress) start(now time.Time, tipHeight int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.lastSample = now
+	p.lastTipHeight = tipHeight
+}
+
+// sample updates the moving averages with the blocks applied and downloaded,
+// and the growth of the network tip, since the previous sample.
+func (p *syncProgress) sample(now time.Time, tipHeight int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	elapsed := now.Sub(p.lastSample).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	blockRate := float64(p.appliedBlocks) / elapsed
+	byteRate := float64(p.downloadedBytes) / elapsed
+	var tipRate float64
+	// the tip is unknown until peers report their heights
+	if p.lastTipHeight > 0 && tipHeight > p.lastTipHeight {
+		tipRate = float64(tipHeight-p.lastTipHeight) / elapsed
+	}
+	if p.sampled {
+		blockRate = progressSmoothing*blockRate + (1-progressSmoothing)*p.blockRate
+		byteRate = progressSmoothing*byteRate + (1-progressSmoothing)*p.byteRate
+		tipRate = progressSmoothing*tipRate + (1-progressSmoothing)*p.tipRate
+	}
+	p.blockRate, p.byteRate, p.tipRate = blockRate, byteRate, tipRate
+	p.sampled = true
+	p.appliedBlocks, p.downloadedBytes = 0, 0
+	p.lastSample = now
+	if tipHeight > p.lastTipHeight {
+		p.lastTipHeight = tipHeight
+	}
+}
+
+// progress returns the rates and the ETA to reach tipHeight from height.
+func (p *syncProgress) progress(height int64, tipHeight int64) Progress {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	eta := time.Duration(-1)
+	// the block at the tip can only be applied once the next one is committed
+	remaining := tipHeight - 1 - height
+	switch {
+	case tipHeight > 0 && remaining <= 0:
+		eta = 0
+	case p.blockRate > p.tipRate:
+		eta = time.Duration(float64(remaining) / (p.blockRate - p.tipRate) * float64(time.Second))
+	}
+	return Progress{
+		Height:          height,
+		MaxPeerHeight:   tipHeight,
+		BlocksPerSecond: p.blockRate,
+		BytesPerSecond:  p.byteRate,
+		RequestTimeouts: p.timeouts,
+		ETA:             eta,
+	}
+}
@@ -0,0 +1,59 @@
+package v0
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncProgress(t *testing.T) {
+	now := time.Now()
+	p := &syncProgress{}
+	p.start(now, 0)
+
+	// nothing is known before the first sample
+	assert.Equal(t, time.Duration(-1), p.progress(1, 0).ETA)
+
+	// the first sample sets the rates, the tip isn't known before it
+	for i := 0; i < 20; i++ {
+		p.blockApplied()
+		p.blockDownloaded(100)
+	}
+	now = now.Add(time.Second)
+	p.sample(now, 1000)
+	progress := p.progress(21, 1000)
+	assert.EqualValues(t, 20, progress.BlocksPerSecond)
+	assert.EqualValues(t, 2000, progress.BytesPerSecond)
+	assert.Equal(t, 978*time.Second/20, progress.ETA)
+
+	// the tip moving at 10 blocks/s while 20 blocks/s are applied halves the
+	// rate at which it is caught up with
+	height, tip := int64(21), int64(1000)
+	for i := 0; i < 50; i++ {
+		for j := 0; j < 20; j++ {
+			p.blockApplied()
+		}
+		height += 20
+		tip += 10
+		now = now.Add(time.Second)
+		p.sample(now, tip)
+	}
+	progress = p.progress(height, tip)
+	assert.InDelta(t, 20, progress.BlocksPerSecond, 0.01)
+	assert.InDelta(t, float64(tip-1-height)/10, progress.ETA.Seconds(), 0.1)
+
+	// the ETA is unknown when the tip moves faster than blocks are applied,
+	// and 0 once it is reached
+	for i := 0; i < 50; i++ {
+		p.blockApplied()
+		tip += 10
+		now = now.Add(time.Second)
+		p.sample(now, tip)
+	}
+	assert.Equal(t, time.Duration(-1), p.progress(height, tip).ETA)
+	assert.Equal(t, time.Duration(0), p.progress(tip-1, tip).ETA)
+
+	p.requestTimedOut()
+	assert.EqualValues(t, 1, p.progress(height, tip).RequestTimeouts)
+}
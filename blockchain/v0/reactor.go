@@ -1,8 +1,11 @@
 package v0
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -61,11 +64,53 @@ type BlockchainReactor struct {
 
 	requestsCh <-chan BlockRequest
 	errorsCh   <-chan peerError
+
+	metrics          *Metrics
+	maxBufferedBytes int64
+
+	// checkpoints are the hashes of trusted blocks by height, and
+	// lastCheckpoint the highest height among them.
+	checkpoints    map[int64][]byte
+	lastCheckpoint int64
+
+	// poolRoutineWg waits for the routine applying blocks, so that stopping
+	// the reactor lets the block being applied be committed.
+	poolRoutineWg sync.WaitGroup
+}
+
+// ReactorOption sets an optional parameter on the BlockchainReactor.
+type ReactorOption func(*BlockchainReactor)
+
+// WithMetrics sets the metrics of the reactor.
+func WithMetrics(metrics *Metrics) ReactorOption {
+	return func(bcR *BlockchainReactor) { bcR.metrics = metrics }
+}
+
+// WithMaxBufferedBytes caps the size of the blocks received from peers and
+// not applied yet. No more blocks are requested while the cap is exceeded. 0
+// disables the cap.
+func WithMaxBufferedBytes(size int64) ReactorOption {
+	return func(bcR *BlockchainReactor) { bcR.maxBufferedBytes = size }
+}
+
+// WithCheckpoints sets the hashes of trusted blocks by height. Blocks at these
+// heights which don't match them are rejected, on top of the verification of
+// their commits.
+func WithCheckpoints(checkpoints map[int64][]byte) ReactorOption {
+	return func(bcR *BlockchainReactor) {
+		bcR.checkpoints = checkpoints
+		bcR.lastCheckpoint = 0
+		for height := range checkpoints {
+			if height > bcR.lastCheckpoint {
+				bcR.lastCheckpoint = height
+			}
+		}
+	}
 }
 
 // NewBlockchainReactor returns new reactor instance.
 func NewBlockchainReactor(state sm.State, blockExec *sm.BlockExecutor, store *store.BlockStore,
-	fastSync bool) *BlockchainReactor {
+	fastSync bool, options ...ReactorOption) *BlockchainReactor {
 
 	if state.LastBlockHeight != store.Height() {
 		panic(fmt.Sprintf("state (%v) and store (%v) height mismatch", state.LastBlockHeight,
@@ -91,8 +136,14 @@ func NewBlockchainReactor(state sm.State, blockExec *sm.BlockExecutor, store *st
 		fastSync:     fastSync,
 		requestsCh:   requestsCh,
 		errorsCh:     errorsCh,
+		metrics:      NopMetrics(),
 	}
 	bcR.BaseReactor = *p2p.NewBaseReactor("BlockchainReactor", bcR)
+	for _, option := range options {
+		option(bcR)
+	}
+	pool.metrics = bcR.metrics
+	pool.maxBufferedBytes = bcR.maxBufferedBytes
 	return bcR
 }
 
@@ -109,32 +160,53 @@ func (bcR *BlockchainReactor) OnStart() error {
 		if err != nil {
 			return err
 		}
+		bcR.poolRoutineWg.Add(1)
 		go bcR.poolRoutine(false)
 	}
 	return nil
 }
 
-// SwitchToFastSync is called by the state sync reactor when switching to fast sync.
+// SwitchToFastSync is called by the state sync reactor when switching to fast
+// sync, and by the consensus reactor when it falls far behind its peers.
 func (bcR *BlockchainReactor) SwitchToFastSync(state sm.State) error {
+	if bcR.fastSync {
+		// the pool was stopped when switching to consensus
+		if err := bcR.pool.Reset(); err != nil {
+			return err
+		}
+	}
 	bcR.fastSync = true
 	bcR.initialState = state
 
 	bcR.pool.height = state.LastBlockHeight + 1
+	if state.LastBlockHeight == 0 {
+		// falling back from state sync, fast sync from genesis
+		bcR.pool.height = state.InitialHeight
+	}
 	err := bcR.pool.Start()
 	if err != nil {
 		return err
 	}
+	bcR.poolRoutineWg.Add(1)
 	go bcR.poolRoutine(true)
 	return nil
 }
 
-// OnStop implements service.Service.
+// Progress returns the progress of fast sync. Syncing is false if fast sync
+// isn't running, either because it is completed or because it didn't start.
+func (bcR *BlockchainReactor) Progress() Progress {
+	return bcR.pool.Progress()
+}
+
+// OnStop implements service.Service. It waits for the block being applied,
+// if any.
 func (bcR *BlockchainReactor) OnStop() {
 	if bcR.fastSync {
 		if err := bcR.pool.Stop(); err != nil {
 			bcR.Logger.Error("Error stopping pool", "err", err)
 		}
 	}
+	bcR.poolRoutineWg.Wait()
 }
 
 // GetChannels implements Reactor
@@ -176,8 +248,13 @@ func (bcR *BlockchainReactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 func (bcR *BlockchainReactor) respondToPeer(msg *bcproto.BlockRequest,
 	src p2p.Peer) (queued bool) {
 
-	block := bcR.store.LoadBlock(msg.Height)
-	if block != nil {
+	// unlike LoadBlock, LoadBlockRange reports corrupt blocks instead of
+	// panicking, so that a peer requesting one can't crash the node
+	result := bcR.store.LoadBlockRange(msg.Height, msg.Height)[0]
+	if result.Err != nil && !errors.Is(result.Err, store.ErrBlockNotFound) {
+		bcR.Logger.Error("failed to load requested block", "height", msg.Height, "err", result.Err)
+	}
+	if block := result.Block; block != nil {
 		bl, err := block.ToProto()
 		if err != nil {
 			bcR.Logger.Error("could not convert msg to protobuf", "err", err)
@@ -228,6 +305,7 @@ func (bcR *BlockchainReactor) ReceiveEnvelope(e p2p.Envelope) {
 		bcR.pool.SetPeerRange(e.Src.ID(), msg.Base, msg.Height)
 	case *bcproto.NoBlockResponse:
 		bcR.Logger.Debug("Peer does not have requested block", "peer", e.Src, "height", msg.Height)
+		bcR.pool.AddNoBlock(e.Src.ID(), msg.Height)
 	default:
 		bcR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 	}
@@ -250,9 +328,34 @@ func (bcR *BlockchainReactor) Receive(chID byte, peer p2p.Peer, msgBytes []byte)
 	})
 }
 
+// verifyCheckpoint returns an error if there is a checkpoint at the height of
+// a block and the block doesn't match it.
+func (bcR *BlockchainReactor) verifyCheckpoint(height int64, blockID types.BlockID) error {
+	hash, ok := bcR.checkpoints[height]
+	if !ok {
+		return nil
+	}
+	if !bytes.Equal(hash, blockID.Hash) {
+		return fmt.Errorf("block %X at height %d doesn't match checkpoint %X", blockID.Hash, height, hash)
+	}
+	bcR.Logger.Info("Block matches checkpoint", "height", height, "hash", blockID.Hash)
+	return nil
+}
+
+// logCheckpoints logs the checkpoints blocks are verified against from the
+// given height.
+func (bcR *BlockchainReactor) logCheckpoints(height int64) {
+	if bcR.lastCheckpoint < height {
+		return
+	}
+	bcR.Logger.Info("Verifying blocks against checkpoints", "checkpoints", len(bcR.checkpoints),
+		"last", bcR.lastCheckpoint)
+}
+
 // Handle messages from the poolReactor telling the reactor what to do.
 // NOTE: Don't sleep in the FOR_LOOP or otherwise slow it down!
 func (bcR *BlockchainReactor) poolRoutine(stateSynced bool) {
+	defer bcR.poolRoutineWg.Done()
 
 	trySyncTicker := time.NewTicker(trySyncIntervalMS * time.Millisecond)
 	defer trySyncTicker.Stop()
@@ -267,12 +370,17 @@ func (bcR *BlockchainReactor) poolRoutine(stateSynced bool) {
 
 	chainID := bcR.initialState.ChainID
 	state := bcR.initialState
+	bcR.logCheckpoints(state.LastBlockHeight + 1)
 
 	lastHundred := time.Now()
 	lastRate := 0.0
 
 	didProcessCh := make(chan struct{}, 1)
 
+	// pendingCh receives the verification of the next block, which runs in
+	// the background while the current block is executed.
+	var pendingCh <-chan *blockVerification
+
 	go func() {
 		for {
 			select {
@@ -356,15 +464,22 @@ FOR_LOOP:
 				didProcessCh <- struct{}{}
 			}
 
-			firstParts := first.MakePartSet(types.BlockPartSizeBytes)
-			firstPartSetHeader := firstParts.Header()
-			firstID := types.BlockID{Hash: first.Hash(), PartSetHeader: firstPartSetHeader}
-			// Finally, verify the first block using the second's commit
-			// NOTE: we can probably make this more efficient, but note that calling
-			// first.Hash() doesn't verify the tx contents, so MakePartSet() is
-			// currently necessary.
-			err := state.Validators.VerifyCommitLight(
-				chainID, firstID, first.Height, second.LastCommit)
+			// Finally, verify the first block using the second's commit. It
+			// was usually verified while the previous block was executed,
+			// unless the pool has replaced either block since.
+			var verification *blockVerification
+			if pendingCh != nil {
+				verification = <-pendingCh
+				pendingCh = nil
+			}
+			if verification == nil || !verification.matches(state.Validators, first, second) {
+				verification = verifyBlock(chainID, state.Validators, first, second)
+			}
+			firstParts, firstID, err := verification.parts, verification.blockID, verification.err
+
+			if err == nil {
+				err = bcR.verifyCheckpoint(first.Height, firstID)
+			}
 
 			if err == nil {
 				var stateMachineValid bool
@@ -405,6 +520,14 @@ FOR_LOOP:
 				continue FOR_LOOP
 			}
 
+			// Verify the second block while the first one is executed. The
+			// validators at its height are the next validators of the current
+			// state. If the verification fails, the second block isn't applied
+			// and its peers are stopped once it is processed, as above.
+			if third := bcR.pool.PeekBlock(second.Height + 1); third != nil {
+				pendingCh = verifyBlockAsync(chainID, state.NextValidators.Copy(), second, third)
+			}
+
 			bcR.pool.PopRequest()
 
 			// TODO: batch saves so we dont persist to disk every block
@@ -421,7 +544,8 @@ FOR_LOOP:
 			if blocksSynced%100 == 0 {
 				lastRate = 0.9*lastRate + 0.1*(100/time.Since(lastHundred).Seconds())
 				bcR.Logger.Info("Fast Sync Rate", "height", bcR.pool.height,
-					"max_peer_height", bcR.pool.MaxPeerHeight(), "blocks/s", lastRate)
+					"max_peer_height", bcR.pool.MaxPeerHeight(), "blocks/s", lastRate,
+					"eta", bcR.pool.Progress().ETA)
 				lastHundred = time.Now()
 			}
 
@@ -429,6 +553,10 @@ FOR_LOOP:
 
 		case <-bcR.Quit():
 			break FOR_LOOP
+
+		case <-bcR.pool.Quit():
+			// the reactor is stopping, and waits for this routine
+			break FOR_LOOP
 		}
 	}
 }
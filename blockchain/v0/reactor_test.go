@@ -195,6 +195,84 @@ func TestNoBlockResponse(t *testing.T) {
 	}
 }
 
+func TestCheckpoints(t *testing.T) {
+	config = cfg.ResetTestRoot("blockchain_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	maxBlockHeight := int64(65)
+
+	// checkpoints maps checkpoint heights to the heights of the blocks whose
+	// hashes are configured for them. A fabricated chain is served by a peer
+	// whose blocks are committed by other validators.
+	testcases := map[string]struct {
+		checkpoints map[int64]int64
+		fabricated  bool
+		matches     bool
+	}{
+		"matching":        {map[int64]int64{20: 20, 40: 40}, false, true},
+		"mismatching":     {map[int64]int64{20: 20, 40: 41}, false, false},
+		"above the chain": {map[int64]int64{20: 20, 100: 30}, false, true},
+		"fabricated":      {map[int64]int64{20: 20, 40: 40}, true, false},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			sourceGenDoc, sourcePrivVals := genDoc, privVals
+			if tc.fabricated {
+				sourceGenDoc, sourcePrivVals = randGenesisDoc(1, false, 30)
+			}
+			reactorPairs := []BlockchainReactorPair{
+				newBlockchainReactor(log.TestingLogger(), sourceGenDoc, sourcePrivVals, maxBlockHeight),
+				newBlockchainReactor(log.TestingLogger(), genDoc, privVals, 0),
+			}
+			hash := func(height int64) []byte {
+				return reactorPairs[0].reactor.store.LoadBlockMeta(height).BlockID.Hash
+			}
+			checkpoints := make(map[int64][]byte, len(tc.checkpoints))
+			for height, blockHeight := range tc.checkpoints {
+				checkpoints[height] = hash(blockHeight)
+			}
+			WithCheckpoints(checkpoints)(reactorPairs[1].reactor)
+
+			p2p.MakeConnectedSwitches(config.P2P, 2, func(i int, s *p2p.Switch) *p2p.Switch {
+				s.AddReactor("BLOCKCHAIN", reactorPairs[i].reactor)
+				return s
+			}, p2p.Connect2Switches)
+
+			defer func() {
+				for _, r := range reactorPairs {
+					err := r.reactor.Stop()
+					require.NoError(t, err)
+					err = r.app.Stop()
+					require.NoError(t, err)
+				}
+			}()
+
+			syncer := reactorPairs[1].reactor
+			require.Eventually(t, func() bool {
+				return syncer.pool.IsCaughtUp() || syncer.Switch.Peers().Size() == 0
+			}, 10*time.Second, 10*time.Millisecond)
+
+			switch {
+			case tc.matches:
+				assert.GreaterOrEqual(t, syncer.store.Height(), maxBlockHeight-2)
+				assert.EqualValues(t, hash(40), syncer.store.LoadBlockMeta(40).BlockID.Hash)
+			case tc.fabricated:
+				// the fabricated blocks match the checkpoints, but their
+				// commits aren't signed by the validators, so none is applied
+				assert.Zero(t, syncer.Switch.Peers().Size())
+				assert.Zero(t, syncer.store.Height())
+			default:
+				// the peer serving the block at the checkpoint is stopped
+				assert.Zero(t, syncer.Switch.Peers().Size())
+				assert.Less(t, syncer.store.Height(), int64(40))
+				assert.GreaterOrEqual(t, syncer.store.Height(), int64(20))
+			}
+		})
+	}
+}
+
 func TestLegacyReactorReceiveBasic(t *testing.T) {
 	config = cfg.ResetTestRoot("blockchain_reactor_test")
 	defer os.RemoveAll(config.RootDir)
@@ -304,6 +382,49 @@ func TestBadBlockStopsPeer(t *testing.T) {
 	assert.True(t, lastReactorPair.reactor.Switch.Peers().Size() < len(reactorPairs)-1)
 }
 
+// BenchmarkFastSync measures the rate at which a node fast syncs a local
+// chain from a single peer.
+func BenchmarkFastSync(b *testing.B) {
+	config = cfg.ResetTestRoot("blockchain_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+
+	maxBlockHeight := int64(300)
+	synced := int64(0)
+	elapsed := time.Duration(0)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		reactorPairs := []BlockchainReactorPair{
+			newBlockchainReactor(log.NewNopLogger(), genDoc, privVals, maxBlockHeight),
+			newBlockchainReactor(log.NewNopLogger(), genDoc, privVals, 0),
+		}
+		b.StartTimer()
+
+		start := time.Now()
+		p2p.MakeConnectedSwitches(config.P2P, 2, func(i int, s *p2p.Switch) *p2p.Switch {
+			s.AddReactor("BLOCKCHAIN", reactorPairs[i].reactor)
+			return s
+		}, p2p.Connect2Switches)
+		for reactorPairs[1].reactor.store.Height() < maxBlockHeight-1 {
+			time.Sleep(time.Millisecond)
+		}
+		elapsed += time.Since(start)
+		synced += reactorPairs[1].reactor.store.Height()
+
+		b.StopTimer()
+		for _, r := range reactorPairs {
+			if err := r.reactor.Switch.Stop(); err != nil {
+				b.Error(err)
+			}
+			if err := r.app.Stop(); err != nil {
+				b.Error(err)
+			}
+		}
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(synced)/elapsed.Seconds(), "blocks/s")
+}
+
 //----------------------------------------------
 // utility funcs
 
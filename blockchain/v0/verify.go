@@ -0,0 +1,60 @@
+package v0
+
+import (
+	"bytes"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// blockVerification is the result of verifying a block against the
+// LastCommit of the block following it.
+type blockVerification struct {
+	block   *types.Block
+	next    *types.Block
+	valHash []byte // hash of the validator set the commit was verified with
+
+	parts   *types.PartSet
+	blockID types.BlockID
+	err     error
+}
+
+// verifyBlock verifies block using the LastCommit of next, which must be
+// signed by vals, the validator set at the height of block.
+func verifyBlock(chainID string, vals *types.ValidatorSet, block, next *types.Block) *blockVerification {
+	// NOTE: we can probably make this more efficient, but note that calling
+	// block.Hash() doesn't verify the tx contents, so MakePartSet() is
+	// currently necessary.
+	parts := block.MakePartSet(types.BlockPartSizeBytes)
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+	return &blockVerification{
+		block:   block,
+		next:    next,
+		valHash: vals.Hash(),
+		parts:   parts,
+		blockID: blockID,
+		err:     vals.VerifyCommitLight(chainID, blockID, block.Height, next.LastCommit),
+	}
+}
+
+// verifyBlockAsync runs verifyBlock in the background, so that the
+// verification of a block can overlap with the execution of the block
+// preceding it. vals must not be modified until the result is received.
+func verifyBlockAsync(
+	chainID string,
+	vals *types.ValidatorSet,
+	block, next *types.Block,
+) <-chan *blockVerification {
+	resultCh := make(chan *blockVerification, 1)
+	go func() {
+		resultCh <- verifyBlock(chainID, vals, block, next)
+	}()
+	return resultCh
+}
+
+// matches returns true if the verification was done for the given blocks and
+// validator set. The pool replaces blocks whose peer was removed, hence a
+// verification started in the background may be outdated once the block is
+// processed.
+func (v *blockVerification) matches(vals *types.ValidatorSet, block, next *types.Block) bool {
+	return v.block == block && v.next == next && bytes.Equal(v.valHash, vals.Hash())
+}
@@ -0,0 +1,49 @@
+package v0
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestVerifyBlock(t *testing.T) {
+	config = cfg.ResetTestRoot("blockchain_reactor_test")
+	defer os.RemoveAll(config.RootDir)
+	genDoc, privVals := randGenesisDoc(1, false, 30)
+	pair := newBlockchainReactor(log.TestingLogger(), genDoc, privVals, 3)
+	defer func() {
+		require.NoError(t, pair.app.Stop())
+	}()
+	bs := pair.reactor.store
+	vals := pair.reactor.initialState.Validators
+	chainID := genDoc.ChainID
+
+	first, second, third := bs.LoadBlock(1), bs.LoadBlock(2), bs.LoadBlock(3)
+	verification := verifyBlock(chainID, vals, first, second)
+	require.NoError(t, verification.err)
+	assert.Equal(t, bs.LoadBlockMeta(1).BlockID, verification.blockID)
+	assert.Equal(t, verification.blockID.PartSetHeader, verification.parts.Header())
+	assert.True(t, verification.matches(vals, first, second))
+
+	// the verification in the background has the same result
+	async := <-verifyBlockAsync(chainID, vals.Copy(), second, third)
+	require.NoError(t, async.err)
+	assert.Equal(t, bs.LoadBlockMeta(2).BlockID, async.blockID)
+
+	// a verification doesn't match blocks replaced by the pool, nor another validator set
+	assert.False(t, verification.matches(vals, bs.LoadBlock(1), second))
+	assert.False(t, verification.matches(vals, first, bs.LoadBlock(2)))
+	otherVals, _ := types.RandValidatorSet(1, 30)
+	assert.False(t, verification.matches(otherVals, first, second))
+
+	// the commit must be signed by the given validators and for the block
+	assert.Error(t, verifyBlock(chainID, otherVals, first, second).err)
+	assert.Error(t, verifyBlock(chainID, vals, first, third).err)
+	assert.Error(t, verifyBlock("other", vals, first, second).err)
+}
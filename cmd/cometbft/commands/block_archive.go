@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	stdos "os"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmtdb "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/store"
+)
+
+var (
+	exportFromHeight int64
+	exportToHeight   int64
+)
+
+func init() {
+	ExportBlocksCmd.Flags().Int64Var(&exportFromHeight, "from", 0,
+		"the first height to export (default: the base of the block store)")
+	ExportBlocksCmd.Flags().Int64Var(&exportToHeight, "to", 0,
+		"the last height to export (default: the latest height of the block store)")
+}
+
+// ExportBlocksCmd exports a range of blocks from the block store into an
+// archive file.
+var ExportBlocksCmd = &cobra.Command{
+	Use:   "export-blocks [archive-file]",
+	Short: "export a range of blocks into an archive file",
+	Long: `
+Writes the blocks between the heights --from and --to (inclusive) from the block
+store into an archive file, which can be imported into the block store of another
+node with the import-blocks command. The node must be stopped while the blocks
+are exported.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exported, err := ExportBlocks(config, args[0], exportFromHeight, exportToHeight)
+		if err != nil {
+			return fmt.Errorf("failed to export blocks: %w", err)
+		}
+		fmt.Printf("Exported %d blocks to %v\n", exported, args[0])
+		return nil
+	},
+}
+
+// ImportBlocksCmd imports the blocks of an archive file into the block store.
+var ImportBlocksCmd = &cobra.Command{
+	Use:   "import-blocks [archive-file]",
+	Short: "import the blocks of an archive file into the block store",
+	Long: `
+Imports the blocks of an archive file written by the export-blocks command into
+the block store. The archive must continue the chain of the block store, i.e. it
+can't start above the height following the latest height of the store. Every block
+is validated against its checksum, its hash and the previous block before it is
+saved. Blocks which are in the store already are skipped, hence an interrupted
+import can be resumed by running the command again. The node must be stopped
+while the blocks are imported.
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		imported, height, err := ImportBlocks(config, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to import blocks: %w", err)
+		}
+		fmt.Printf("Imported %d blocks, the latest height of the block store is %d\n", imported, height)
+		return nil
+	},
+}
+
+// ExportBlocks writes the blocks between the given heights into an archive
+// file at path. Heights of 0 default to the base and the latest height of the
+// block store respectively. Returns the number of blocks exported.
+func ExportBlocks(config *cfg.Config, path string, from, to int64) (int64, error) {
+	blockStore, err := loadBlockStore(config)
+	if err != nil {
+		return 0, err
+	}
+	defer blockStore.Close()
+
+	if from == 0 {
+		from = blockStore.Base()
+	}
+	if to == 0 {
+		to = blockStore.Height()
+	}
+
+	f, err := stdos.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	exported, err := blockStore.ExportBlocks(w, from, to)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = stdos.Remove(path)
+		return 0, err
+	}
+	return exported, nil
+}
+
+// ImportBlocks imports the blocks of the archive file at path into the block
+// store, which is created if it doesn't exist. Returns the number of blocks
+// imported and the latest height of the block store.
+func ImportBlocks(config *cfg.Config, path string) (int64, int64, error) {
+	f, err := stdos.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	blockStoreDB, err := cmtdb.NewDB("blockstore", dbm.BackendType(config.DBBackend), config.DBDir())
+	if err != nil {
+		return 0, 0, err
+	}
+	blockStore := store.NewBlockStore(blockStoreDB)
+	defer blockStore.Close()
+
+	imported, err := blockStore.ImportBlocks(bufio.NewReader(f))
+	return imported, blockStore.Height(), err
+}
@@ -15,6 +15,7 @@ import (
 
 	dbm "github.com/cometbft/cometbft-db"
 
+	cmtdb "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtmath "github.com/tendermint/tendermint/libs/math"
 	cmtos "github.com/tendermint/tendermint/libs/os"
@@ -59,6 +60,7 @@ var (
 	witnessAddrsJoined string
 	chainID            string
 	home               string
+	dbBackend          string
 	maxOpenConnections int
 
 	sequential     bool
@@ -67,6 +69,9 @@ var (
 	trustedHash    []byte
 	trustLevelStr  string
 
+	pruningSize     uint16
+	pruningInterval int64
+
 	verbose bool
 
 	primaryKey   = []byte("primary")
@@ -82,6 +87,8 @@ func init() {
 		"CometBFT nodes to cross-check the primary node, comma-separated")
 	LightCmd.Flags().StringVar(&home, "home-dir", os.ExpandEnv(filepath.Join("$HOME", ".cometbft-light")),
 		"specify the home directory")
+	LightCmd.Flags().StringVar(&dbBackend, "db-backend", string(dbm.GoLevelDBBackend),
+		"database backend of the trusted store: goleveldb | cleveldb | boltdb | rocksdb | badgerdb | pebbledb")
 	LightCmd.Flags().IntVar(
 		&maxOpenConnections,
 		"max-open-connections",
@@ -98,6 +105,13 @@ func init() {
 	LightCmd.Flags().BoolVar(&sequential, "sequential", false,
 		"sequential verification. Verify all headers sequentially as opposed to using skipping verification",
 	)
+	LightCmd.Flags().Uint16Var(&pruningSize, "pruning-size", 1000,
+		"number of most recent headers kept in the trusted store. 0 disables pruning",
+	)
+	LightCmd.Flags().Int64Var(&pruningInterval, "pruning-interval", 0,
+		"also keep one header every pruning-interval heights, along with the first trusted header, "+
+			"for historical verification. 0 only keeps the most recent headers",
+	)
 }
 
 func runProxy(cmd *cobra.Command, args []string) error {
@@ -119,7 +133,7 @@ func runProxy(cmd *cobra.Command, args []string) error {
 		witnessesAddrs = strings.Split(witnessAddrsJoined, ",")
 	}
 
-	db, err := dbm.NewGoLevelDB("light-client-db", home)
+	db, err := cmtdb.NewDB("light-client-db", dbm.BackendType(dbBackend), home)
 	if err != nil {
 		return fmt.Errorf("can't create a db: %w", err)
 	}
@@ -164,6 +178,8 @@ func runProxy(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}),
+		light.PruningSize(pruningSize),
+		light.PruningInterval(pruningInterval),
 	}
 
 	if sequential {
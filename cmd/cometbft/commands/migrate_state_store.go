@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmtdb "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/os"
+	"github.com/tendermint/tendermint/state"
+)
+
+var migrateBatchSize int
+
+func init() {
+	MigrateStateStoreCmd.Flags().IntVar(&migrateBatchSize, "batch-size", 10000,
+		"number of keys migrated per atomic batch")
+}
+
+var MigrateStateStoreCmd = &cobra.Command{
+	Use:     "migrate-state-store",
+	Aliases: []string{"migrate_state_store"},
+	Short:   "migrate the state store to the current key layout",
+	Long: fmt.Sprintf(`
+Converts the state store in place to the current key layout (v%d), which uses
+a separate prefix per kind of data and big-endian encoded heights so that the
+store can be scanned in height order. The node refuses to start on a state
+store with an outdated layout.
+
+The node must be stopped while the store is migrated. An interrupted migration
+can be resumed by running the command again.
+`, state.StoreLayoutVersion),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return MigrateStateStore(config)
+	},
+}
+
+// MigrateStateStore converts the state store of the node with the given
+// config to the current key layout, logging the progress.
+func MigrateStateStore(config *cfg.Config) error {
+	if !os.FileExists(filepath.Join(config.DBDir(), "state.db")) {
+		return fmt.Errorf("no statestore found in %v", config.DBDir())
+	}
+	stateDB, err := cmtdb.NewDB("state", dbm.BackendType(config.DBBackend), config.DBDir())
+	if err != nil {
+		return err
+	}
+	defer stateDB.Close()
+
+	version, err := state.LoadStoreLayoutVersion(stateDB)
+	if err != nil {
+		return err
+	}
+	if version == state.StoreLayoutVersion {
+		logger.Info("state store already uses the current key layout", "version", version)
+		return nil
+	}
+
+	logger.Info("migrating state store", "from", version, "to", state.StoreLayoutVersion)
+	err = state.MigrateStoreLayout(stateDB, migrateBatchSize, func(migrated, total int64) {
+		logger.Info("migrating state store", "migrated", migrated, "total", total)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate state store: %w", err)
+	}
+	logger.Info("migrated state store", "version", state.StoreLayoutVersion)
+	return nil
+}
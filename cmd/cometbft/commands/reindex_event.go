@@ -10,6 +10,7 @@ import (
 
 	abcitypes "github.com/tendermint/tendermint/abci/types"
 	cmtcfg "github.com/tendermint/tendermint/config"
+	cmtdb "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/progressbar"
 	"github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/state/indexer"
@@ -110,7 +111,7 @@ func loadEventSinks(cfg *cmtcfg.Config) (indexer.BlockIndexer, txindex.TxIndexer
 		}
 		return es.BlockIndexer(), es.TxIndexer(), nil
 	case "kv":
-		store, err := dbm.NewDB("tx_index", dbm.BackendType(cfg.DBBackend), cfg.DBDir())
+		store, err := cmtdb.NewDB("tx_index", dbm.BackendType(cfg.DBBackend), cfg.DBDir())
 		if err != nil {
 			return nil, nil, err
 		}
@@ -132,65 +133,81 @@ type eventReIndexArgs struct {
 	stateStore   state.Store
 }
 
+// reIndexBatchSize is the number of blocks loaded from the block store at
+// once.
+const reIndexBatchSize = 100
+
 func eventReIndex(cmd *cobra.Command, args eventReIndexArgs) error {
 	var bar progressbar.Bar
 	bar.NewOption(args.startHeight-1, args.endHeight)
 
 	fmt.Println("start re-indexing events:")
 	defer bar.Finish()
-	for i := args.startHeight; i <= args.endHeight; i++ {
-		select {
-		case <-cmd.Context().Done():
-			return fmt.Errorf("event re-index terminated at height %d: %w", i, cmd.Context().Err())
-		default:
-			b := args.blockStore.LoadBlock(i)
-			if b == nil {
-				return fmt.Errorf("not able to load block at height %d from the blockstore", i)
+	for from := args.startHeight; from <= args.endHeight; from += reIndexBatchSize {
+		to := from + reIndexBatchSize - 1
+		if to > args.endHeight {
+			to = args.endHeight
+		}
+		for _, result := range args.blockStore.LoadBlockRange(from, to) {
+			select {
+			case <-cmd.Context().Done():
+				return fmt.Errorf("event re-index terminated at height %d: %w", result.Height, cmd.Context().Err())
+			default:
+				if result.Err != nil {
+					return fmt.Errorf("not able to load block at height %d from the blockstore: %w",
+						result.Height, result.Err)
+				}
+				if err := reIndexBlock(args, result.Block); err != nil {
+					return err
+				}
 			}
 
-			r, err := args.stateStore.LoadABCIResponses(i)
-			if err != nil {
-				return fmt.Errorf("not able to load ABCI Response at height %d from the statestore", i)
-			}
+			bar.Play(result.Height)
+		}
+	}
 
-			e := types.EventDataNewBlockHeader{
-				Header:           b.Header,
-				NumTxs:           int64(len(b.Txs)),
-				ResultBeginBlock: *r.BeginBlock,
-				ResultEndBlock:   *r.EndBlock,
-			}
+	return nil
+}
 
-			var batch *txindex.Batch
-			if e.NumTxs > 0 {
-				batch = txindex.NewBatch(e.NumTxs)
-
-				for i := range b.Data.Txs {
-					tr := abcitypes.TxResult{
-						Height: b.Height,
-						//nolint:gosec
-						Index:  uint32(i),
-						Tx:     b.Data.Txs[i],
-						Result: *(r.DeliverTxs[i]),
-					}
-
-					if err = batch.Add(&tr); err != nil {
-						return fmt.Errorf("adding tx to batch: %w", err)
-					}
-				}
+func reIndexBlock(args eventReIndexArgs, b *types.Block) error {
+	r, err := args.stateStore.LoadABCIResponses(b.Height)
+	if err != nil {
+		return fmt.Errorf("not able to load ABCI Response at height %d from the statestore", b.Height)
+	}
 
-				if err := args.txIndexer.AddBatch(batch); err != nil {
-					return fmt.Errorf("tx event re-index at height %d failed: %w", i, err)
-				}
+	e := types.EventDataNewBlockHeader{
+		Header:           b.Header,
+		NumTxs:           int64(len(b.Txs)),
+		ResultBeginBlock: *r.BeginBlock,
+		ResultEndBlock:   *r.EndBlock,
+	}
+
+	var batch *txindex.Batch
+	if e.NumTxs > 0 {
+		batch = txindex.NewBatch(e.NumTxs)
+
+		for i := range b.Data.Txs {
+			tr := abcitypes.TxResult{
+				Height: b.Height,
+				//nolint:gosec
+				Index:  uint32(i),
+				Tx:     b.Data.Txs[i],
+				Result: *(r.DeliverTxs[i]),
 			}
 
-			if err := args.blockIndexer.Index(e); err != nil {
-				return fmt.Errorf("block event re-index at height %d failed: %w", i, err)
+			if err = batch.Add(&tr); err != nil {
+				return fmt.Errorf("adding tx to batch: %w", err)
 			}
 		}
 
-		bar.Play(i)
+		if err := args.txIndexer.AddBatch(batch); err != nil {
+			return fmt.Errorf("tx event re-index at height %d failed: %w", b.Height, err)
+		}
 	}
 
+	if err := args.blockIndexer.Index(e); err != nil {
+		return fmt.Errorf("block event re-index at height %d failed: %w", b.Height, err)
+	}
 	return nil
 }
 
@@ -17,6 +17,7 @@ import (
 	blockmocks "github.com/tendermint/tendermint/state/indexer/mocks"
 	"github.com/tendermint/tendermint/state/mocks"
 	txmocks "github.com/tendermint/tendermint/state/txindex/mocks"
+	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -127,6 +128,23 @@ func TestLoadBlockStore(t *testing.T) {
 
 }
 
+// blockRange returns the results of loading the blocks between from and to,
+// the first of which failed with err, if set.
+func blockRange(from, to int64, err error) []types.BlockRangeResult {
+	var results []types.BlockRangeResult
+	for h := from; h <= to; h++ {
+		block := &types.Block{
+			Header: types.Header{Height: h},
+			Data:   types.Data{Txs: types.Txs{make(types.Tx, 1)}},
+		}
+		results = append(results, types.BlockRangeResult{Height: h, Block: block})
+	}
+	if err != nil {
+		results[0] = types.BlockRangeResult{Height: from, Err: err}
+	}
+	return results
+}
+
 func TestReIndexEvent(t *testing.T) {
 	mockBlockStore := &mocks.BlockStore{}
 	mockStateStore := &mocks.Store{}
@@ -136,9 +154,10 @@ func TestReIndexEvent(t *testing.T) {
 	mockBlockStore.
 		On("Base").Return(base).
 		On("Height").Return(height).
-		On("LoadBlock", base).Return(nil).Once().
-		On("LoadBlock", base).Return(&types.Block{Data: types.Data{Txs: types.Txs{make(types.Tx, 1)}}}).
-		On("LoadBlock", height).Return(&types.Block{Data: types.Data{Txs: types.Txs{make(types.Tx, 1)}}})
+		On("LoadBlockRange", base, height).Return(blockRange(base, height, store.ErrBlockNotFound)).Once().
+		On("LoadBlockRange", base, height).Return(blockRange(base, height, nil)).
+		On("LoadBlockRange", base, base).Return(blockRange(base, base, nil)).
+		On("LoadBlockRange", height, height).Return(blockRange(height, height, nil))
 
 	dtx := abcitypes.ResponseDeliverTx{}
 	abciResp := &protocmtstate.ABCIResponses{
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	stdos "os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -9,14 +10,22 @@ import (
 	dbm "github.com/cometbft/cometbft-db"
 
 	cfg "github.com/tendermint/tendermint/config"
+	cmtdb "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/os"
 	"github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/store"
 )
 
+var rollbackHeight int64
+
+func init() {
+	RollbackStateCmd.Flags().Int64Var(&rollbackHeight, "height", 0,
+		"roll back to this height, removing all blocks above it (default: roll back one height)")
+}
+
 var RollbackStateCmd = &cobra.Command{
 	Use:   "rollback",
-	Short: "rollback CometBFT state by one height",
+	Short: "rollback CometBFT state by one height, or to a given height",
 	Long: `
 A state rollback is performed to recover from an incorrect application state transition,
 when CometBFT has persisted an incorrect app hash and is thus unable to make
@@ -24,8 +33,36 @@ progress. Rollback overwrites a state at height n with the state at height n - 1
 The application should also roll back to height n - 1. No blocks are removed, so upon
 restarting CometBFT the transactions in block n will be re-executed against the
 application.
+
+With --height h, the state is instead rolled back to height h and all blocks above
+h are removed from the block store, so that they are produced again. The application
+must also roll back to height h. The height can't be below the base of the block
+store, nor cross a height whose state was pruned or skipped by state sync. The
+consensus WAL, which holds the consensus messages of the removed heights, is moved
+aside. An interrupted rollback can be completed by running the command again.
+
+Note that the private validator state is not changed: the validator refuses to sign
+at heights it has already signed, which protects against double signing, until its
+state is reset with unsafe-reset-priv-validator. Only do so if the whole network has
+rolled back to the same height.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if rollbackHeight > 0 {
+			height, hash, walBackup, err := RollbackStateToHeight(config, rollbackHeight)
+			if err != nil {
+				return fmt.Errorf("failed to rollback state: %w", err)
+			}
+
+			fmt.Printf("Rolled back state to height %d and hash %v\n", height, hash)
+			if walBackup != "" {
+				fmt.Printf("Moved the consensus WAL to %v\n", walBackup)
+			}
+			fmt.Println("The private validator state was not changed, hence the validator won't sign " +
+				"at heights it has signed before. If the whole network rolled back, reset it with " +
+				"unsafe-reset-priv-validator.")
+			return nil
+		}
+
 		height, hash, err := RollbackState(config)
 		if err != nil {
 			return fmt.Errorf("failed to rollback state: %w", err)
@@ -54,29 +91,87 @@ func RollbackState(config *cfg.Config) (int64, []byte, error) {
 	return state.Rollback(blockStore, stateStore)
 }
 
-func loadStateAndBlockStore(config *cfg.Config) (*store.BlockStore, state.Store, error) {
+// RollbackStateToHeight overwrites the state with the state at the given
+// height and removes all blocks above it. Since the consensus WAL contains
+// the messages of the removed heights, which would be replayed on start, it
+// is moved aside. Returns the latest state height and app hash, and the path
+// the WAL was moved to, if any, alongside an error if there was one.
+func RollbackStateToHeight(config *cfg.Config, height int64) (int64, []byte, string, error) {
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	if err != nil {
+		return -1, nil, "", err
+	}
+	defer func() {
+		_ = blockStore.Close()
+		_ = stateStore.Close()
+	}()
+
+	latestHeight := blockStore.Height()
+	stateHeight, hash, err := state.RollbackToHeight(blockStore, stateStore, height)
+	if err != nil {
+		return -1, nil, "", err
+	}
+
+	walBackup, err := backupWAL(config, latestHeight)
+	if err != nil {
+		return -1, nil, "", err
+	}
+	return stateHeight, hash, walBackup, nil
+}
+
+// backupWAL moves the consensus WAL directory aside, naming the backup after
+// the height the store was rolled back from. It returns the path of the
+// backup, or an empty string if there is no WAL.
+func backupWAL(config *cfg.Config, height int64) (string, error) {
+	walDir := filepath.Dir(config.Consensus.WalFile())
+	if !os.FileExists(walDir) {
+		return "", nil
+	}
+	backup := fmt.Sprintf("%s.rollback-%d", walDir, height)
+	if os.FileExists(backup) {
+		return "", fmt.Errorf("cannot move the consensus WAL, %v already exists", backup)
+	}
+	if err := stdos.Rename(walDir, backup); err != nil {
+		return "", fmt.Errorf("failed to move the consensus WAL: %w", err)
+	}
+	return backup, nil
+}
+
+func loadBlockStore(config *cfg.Config) (*store.BlockStore, error) {
 	dbType := dbm.BackendType(config.DBBackend)
 
 	if !os.FileExists(filepath.Join(config.DBDir(), "blockstore.db")) {
-		return nil, nil, fmt.Errorf("no blockstore found in %v", config.DBDir())
+		return nil, fmt.Errorf("no blockstore found in %v", config.DBDir())
 	}
 
 	// Get BlockStore
-	blockStoreDB, err := dbm.NewDB("blockstore", dbType, config.DBDir())
+	blockStoreDB, err := cmtdb.NewDB("blockstore", dbType, config.DBDir())
+	if err != nil {
+		return nil, err
+	}
+	return store.NewBlockStore(blockStoreDB), nil
+}
+
+func loadStateAndBlockStore(config *cfg.Config) (*store.BlockStore, state.Store, error) {
+	dbType := dbm.BackendType(config.DBBackend)
+
+	blockStore, err := loadBlockStore(config)
 	if err != nil {
 		return nil, nil, err
 	}
-	blockStore := store.NewBlockStore(blockStoreDB)
 
 	if !os.FileExists(filepath.Join(config.DBDir(), "state.db")) {
 		return nil, nil, fmt.Errorf("no statestore found in %v", config.DBDir())
 	}
 
 	// Get StateStore
-	stateDB, err := dbm.NewDB("state", dbType, config.DBDir())
+	stateDB, err := cmtdb.NewDB("state", dbType, config.DBDir())
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := state.CheckStoreLayout(stateDB); err != nil {
+		return nil, nil, err
+	}
 	stateStore := state.NewStore(stateDB, state.StoreOptions{
 		DiscardABCIResponses: config.Storage.DiscardABCIResponses,
 	})
@@ -77,15 +77,17 @@ var RootCmd = &cobra.Command{
 			logger = log.NewTMJSONLogger(log.NewSyncWriter(os.Stdout))
 		}
 
+		if viper.GetBool(cli.TraceFlag) {
+			logger = log.NewTracingLogger(logger)
+		}
+
+		// the level filter wraps the other loggers so that the node can
+		// change its levels at runtime
 		logger, err = cmtflags.ParseLogLevel(config.LogLevel, logger, cfg.DefaultLogLevel)
 		if err != nil {
 			return err
 		}
 
-		if viper.GetBool(cli.TraceFlag) {
-			logger = log.NewTracingLogger(logger)
-		}
-
 		logger = logger.With("module", "main")
 		return nil
 	},
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	cfg "github.com/tendermint/tendermint/config"
 	cmtos "github.com/tendermint/tendermint/libs/os"
@@ -143,6 +144,12 @@ func NewRunNodeCmd(nodeProvider nm.Provider) *cobra.Command {
 
 			logger.Info("Started node", "nodeInfo", n.Switch().NodeInfo())
 
+			// Reload the config fields which can be changed at runtime upon
+			// receiving SIGHUP.
+			if file := viper.ConfigFileUsed(); file != "" {
+				n.TrapReloadSignal(file)
+			}
+
 			// Stop upon receiving SIGTERM or CTRL-C.
 			cmtos.TrapSignal(logger, func() {
 				if n.IsRunning() {
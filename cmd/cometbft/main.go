@@ -30,7 +30,10 @@ func main() {
 		cmd.GenNodeKeyCmd,
 		cmd.VersionCmd,
 		cmd.RollbackStateCmd,
+		cmd.MigrateStateStoreCmd,
 		cmd.CompactGoLevelDBCmd,
+		cmd.ExportBlocksCmd,
+		cmd.ImportBlocksCmd,
 		debug.DebugCmd,
 		cli.NewCompletionCmd(rootCmd, true),
 	)
@@ -7,7 +7,12 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -29,6 +34,16 @@ const (
 	MempoolV0 = "v0"
 	MempoolV1 = "v1"
 	MempoolV2 = "v2"
+
+	// Block store verification modes, see StorageConfig.VerifyBlockStore.
+	VerifyBlockStoreNone    = "none"
+	VerifyBlockStoreSampled = "sampled"
+	VerifyBlockStoreFull    = "full"
+
+	// Policies for proposals the application didn't process in time, see
+	// ConsensusConfig.ProcessProposalTimeoutPolicy.
+	ProcessProposalTimeoutAccept = "accept"
+	ProcessProposalTimeoutReject = "reject"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -152,6 +167,9 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [consensus] section: %w", err)
 	}
+	if err := cfg.Storage.ValidateBasic(); err != nil {
+		return fmt.Errorf("error in [storage] section: %w", err)
+	}
 	if err := cfg.Instrumentation.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [instrumentation] section: %w", err)
 	}
@@ -182,7 +200,7 @@ type BaseConfig struct { //nolint: maligned
 	// and verifying their commits
 	FastSyncMode bool `mapstructure:"fast_sync"`
 
-	// Database backend: goleveldb | cleveldb | boltdb | rocksdb
+	// Database backend: goleveldb | cleveldb | boltdb | rocksdb | badgerdb | pebbledb
 	// * goleveldb (github.com/syndtr/goleveldb - most popular implementation)
 	//   - pure go
 	//   - stable
@@ -201,6 +219,11 @@ type BaseConfig struct { //nolint: maligned
 	// * badgerdb (uses github.com/dgraph-io/badger)
 	//   - EXPERIMENTAL
 	//   - use badgerdb build tag (go build -tags badgerdb)
+	// * pebbledb (uses github.com/cockroachdb/pebble)
+	//   - EXPERIMENTAL
+	//   - pure go
+	//   - lower write amplification than goleveldb on large stores
+	//   - not compatible with the on-disk format of other backends
 	DBBackend string `mapstructure:"db_backend"`
 
 	// Database directory
@@ -221,37 +244,81 @@ type BaseConfig struct { //nolint: maligned
 	// Path to the JSON file containing the last sign state of a validator
 	PrivValidatorState string `mapstructure:"priv_validator_state_file"`
 
+	// If true, wait for the last sign state of a validator to be flushed to
+	// disk, along with its directory, every time it's saved
+	PrivValidatorStateFsync bool `mapstructure:"priv_validator_state_fsync"`
+
 	// TCP or UNIX socket address for CometBFT to listen on for
-	// connections from an external PrivValidator process
+	// connections from an external PrivValidator process. Several comma
+	// separated addresses can be given, in priority order, to fail over
+	// between several signers, which must all hold the same key.
 	PrivValidatorListenAddr string `mapstructure:"priv_validator_laddr"`
 
+	// Number of consecutive failed requests to an external PrivValidator
+	// after which CometBFT fails over to the next address of
+	// priv_validator_laddr
+	PrivValidatorFailoverThreshold int `mapstructure:"priv_validator_failover_threshold"`
+
+	// How often the connection to an external PrivValidator is checked with
+	// a ping when idle. It must be less than the read timeout of the
+	// PrivValidator process
+	PrivValidatorPingInterval time.Duration `mapstructure:"priv_validator_ping_interval"`
+
+	// How long to wait for an external PrivValidator to answer a ping before
+	// dropping the connection
+	PrivValidatorPongTimeout time.Duration `mapstructure:"priv_validator_pong_timeout"`
+
+	// How long to wait for an external PrivValidator to answer a sign request
+	PrivValidatorSignTimeout time.Duration `mapstructure:"priv_validator_sign_timeout"`
+
 	// A JSON file containing the private key to use for p2p authenticated encryption
 	NodeKey string `mapstructure:"node_key_file"`
 
 	// Mechanism to connect to the ABCI application: socket | grpc
 	ABCI string `mapstructure:"abci"`
 
+	// Maximum number of CheckTx requests outstanding at once. 1 serializes
+	// them; above, the application must support concurrent CheckTx calls.
+	// Consensus calls are always made one at a time. Values above 1 need the
+	// grpc transport or an application compiled in with the binary: the
+	// socket protocol matches responses to requests by their order.
+	ABCICheckTxConcurrency int `mapstructure:"abci_check_tx_concurrency"`
+
 	// If true, query the ABCI app on connecting to a new peer
 	// so the app can decide if we should keep the connection or not
 	FilterPeers bool `mapstructure:"filter_peers"` // false
+
+	// ShutdownTimeout is how long stopping the node waits for the block being
+	// applied to be committed, and for the messages queued for the peers to
+	// be flushed. Past it, the node stops without closing its databases.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
 }
 
 // DefaultBaseConfig returns a default base configuration for a CometBFT node
 func DefaultBaseConfig() BaseConfig {
 	return BaseConfig{
-		Genesis:            defaultGenesisJSONPath,
-		PrivValidatorKey:   defaultPrivValKeyPath,
-		PrivValidatorState: defaultPrivValStatePath,
-		NodeKey:            defaultNodeKeyPath,
-		Moniker:            defaultMoniker,
-		ProxyApp:           "tcp://127.0.0.1:26658",
-		ABCI:               "socket",
-		LogLevel:           DefaultLogLevel,
-		LogFormat:          LogFormatPlain,
-		FastSyncMode:       true,
-		FilterPeers:        false,
-		DBBackend:          "goleveldb",
-		DBPath:             "data",
+		Genesis:                defaultGenesisJSONPath,
+		PrivValidatorKey:       defaultPrivValKeyPath,
+		PrivValidatorState:     defaultPrivValStatePath,
+		NodeKey:                defaultNodeKeyPath,
+		Moniker:                defaultMoniker,
+		ProxyApp:               "tcp://127.0.0.1:26658",
+		ABCI:                   "socket",
+		ABCICheckTxConcurrency: 1,
+		LogLevel:               DefaultLogLevel,
+		LogFormat:              LogFormatPlain,
+		FastSyncMode:           true,
+		FilterPeers:            false,
+		DBBackend:              "goleveldb",
+		DBPath:                 "data",
+
+		PrivValidatorStateFsync:        true,
+		PrivValidatorFailoverThreshold: 3,
+		PrivValidatorPingInterval:      3 * time.Second,
+		PrivValidatorPongTimeout:       5 * time.Second,
+		PrivValidatorSignTimeout:       5 * time.Second,
+
+		ShutdownTimeout: 30 * time.Second,
 	}
 }
 
@@ -294,6 +361,18 @@ func (cfg BaseConfig) DBDir() string {
 	return rootify(cfg.DBPath, cfg.RootDir)
 }
 
+// builtinProxyApps are the proxy_app names of the applications compiled in with
+// the binary, which run in process whatever the abci transport. Keep it in sync
+// with proxy.DefaultClientCreator.
+var builtinProxyApps = map[string]bool{
+	"counter":            true,
+	"counter_serial":     true,
+	"kvstore":            true,
+	"persistent_kvstore": true,
+	"e2e":                true,
+	"noop":               true,
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg BaseConfig) ValidateBasic() error {
@@ -302,6 +381,27 @@ func (cfg BaseConfig) ValidateBasic() error {
 	default:
 		return errors.New("unknown log_format (must be 'plain' or 'json')")
 	}
+	if cfg.PrivValidatorFailoverThreshold < 1 {
+		return errors.New("priv_validator_failover_threshold must be positive")
+	}
+	if cfg.PrivValidatorPingInterval <= 0 {
+		return errors.New("priv_validator_ping_interval must be positive")
+	}
+	if cfg.PrivValidatorPongTimeout <= 0 {
+		return errors.New("priv_validator_pong_timeout must be positive")
+	}
+	if cfg.PrivValidatorSignTimeout <= 0 {
+		return errors.New("priv_validator_sign_timeout must be positive")
+	}
+	if cfg.ABCICheckTxConcurrency < 1 {
+		return errors.New("abci_check_tx_concurrency must be positive")
+	}
+	if cfg.ABCICheckTxConcurrency > 1 && cfg.ABCI == "socket" && !builtinProxyApps[cfg.ProxyApp] {
+		return errors.New("abci_check_tx_concurrency above 1 is not supported by the socket transport, use grpc")
+	}
+	if cfg.ShutdownTimeout <= 0 {
+		return errors.New("shutdown_timeout must be positive")
+	}
 	return nil
 }
 
@@ -364,6 +464,12 @@ type RPCConfig struct {
 	// returning `ErrOutOfCapacity`.
 	SubscriptionBufferSize int `mapstructure:"experimental_subscription_buffer_size"`
 
+	// What happens to the events published to a subscription whose buffer is
+	// full: "terminate" closes the subscription with `ErrOutOfCapacity`, and
+	// "drop_oldest" drops its oldest events, the next event delivered
+	// reporting how many were dropped.
+	SubscriptionOverflowPolicy string `mapstructure:"experimental_subscription_overflow_policy"`
+
 	// The maximum number of responses that can be buffered per WebSocket
 	// client. If clients cannot read from the WebSocket endpoint fast enough,
 	// they will be disconnected, so increasing this parameter may reduce the
@@ -415,6 +521,24 @@ type RPCConfig struct {
 
 	// pprof listen address (https://golang.org/pkg/net/http/pprof)
 	PprofListenAddress string `mapstructure:"pprof_laddr"`
+
+	// The maximum time since the core goroutines of the node (the consensus
+	// receive routine and the event bus) last made progress before /livez
+	// reports the node as not live. 0 disables the check.
+	LivenessMaxHeartbeatAge time.Duration `mapstructure:"liveness_max_heartbeat_age"`
+
+	// The minimum number of peers of the node for /readyz to report it as
+	// ready.
+	ReadinessMinPeers int `mapstructure:"readiness_min_peers"`
+
+	// The maximum number of blocks the node can be behind its highest peer
+	// for /readyz to report it as ready.
+	ReadinessMaxSyncLag int64 `mapstructure:"readiness_max_sync_lag"`
+
+	// The maximum age of the last block of the node for /readyz to report it
+	// as ready. 0 disables the check, which is needed if empty blocks aren't
+	// created.
+	ReadinessMaxBlockAge time.Duration `mapstructure:"readiness_max_block_age"`
 }
 
 // DefaultRPCConfig returns a default configuration for the RPC server
@@ -430,17 +554,23 @@ func DefaultRPCConfig() *RPCConfig {
 		Unsafe:             false,
 		MaxOpenConnections: 900,
 
-		MaxSubscriptionClients:    100,
-		MaxSubscriptionsPerClient: 5,
-		SubscriptionBufferSize:    defaultSubscriptionBufferSize,
-		TimeoutBroadcastTxCommit:  10 * time.Second,
-		WebSocketWriteBufferSize:  defaultSubscriptionBufferSize,
+		MaxSubscriptionClients:     100,
+		MaxSubscriptionsPerClient:  5,
+		SubscriptionBufferSize:     defaultSubscriptionBufferSize,
+		SubscriptionOverflowPolicy: "terminate",
+		TimeoutBroadcastTxCommit:   10 * time.Second,
+		WebSocketWriteBufferSize:   defaultSubscriptionBufferSize,
 
 		MaxBodyBytes:   int64(1000000), // 1MB
 		MaxHeaderBytes: 1 << 20,        // same as the net/http default
 
 		TLSCertFile: "",
 		TLSKeyFile:  "",
+
+		LivenessMaxHeartbeatAge: time.Minute,
+		ReadinessMinPeers:       1,
+		ReadinessMaxSyncLag:     2,
+		ReadinessMaxBlockAge:    0,
 	}
 }
 
@@ -474,6 +604,12 @@ func (cfg *RPCConfig) ValidateBasic() error {
 			minSubscriptionBufferSize,
 		)
 	}
+	if cfg.SubscriptionOverflowPolicy != "terminate" && cfg.SubscriptionOverflowPolicy != "drop_oldest" {
+		return fmt.Errorf(
+			"experimental_subscription_overflow_policy must be \"terminate\" or \"drop_oldest\", got %q",
+			cfg.SubscriptionOverflowPolicy,
+		)
+	}
 	if cfg.WebSocketWriteBufferSize < cfg.SubscriptionBufferSize {
 		return fmt.Errorf(
 			"experimental_websocket_write_buffer_size must be >= experimental_subscription_buffer_size (%d)",
@@ -489,6 +625,18 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxHeaderBytes < 0 {
 		return errors.New("max_header_bytes can't be negative")
 	}
+	if cfg.LivenessMaxHeartbeatAge < 0 {
+		return errors.New("liveness_max_heartbeat_age can't be negative")
+	}
+	if cfg.ReadinessMinPeers < 0 {
+		return errors.New("readiness_min_peers can't be negative")
+	}
+	if cfg.ReadinessMaxSyncLag < 0 {
+		return errors.New("readiness_max_sync_lag can't be negative")
+	}
+	if cfg.ReadinessMaxBlockAge < 0 {
+		return errors.New("readiness_max_block_age can't be negative")
+	}
 	return nil
 }
 
@@ -773,6 +921,37 @@ type MempoolConfig struct {
 	// Only applicable to the v2 / CAT mempool
 	// Default is 200ms
 	MaxGossipDelay time.Duration `mapstructure:"max-gossip-delay"`
+
+	// MaxInFlightWantsPerPeer is the maximum number of transactions requested
+	// from a single peer at once. The transactions over the limit are
+	// requested from other peers which have them, or once a request to the
+	// peer completes.
+	// Only applicable to the v2 / CAT mempool
+	// Default is 16
+	MaxInFlightWantsPerPeer int `mapstructure:"max-in-flight-wants-per-peer"`
+
+	// RebroadcastAfterHeights, if non-zero, is the number of heights after
+	// which a transaction still in the mempool is broadcast again to the
+	// peers which haven't seen it, and again every as many heights.
+	// Only applicable to the v2 / CAT mempool
+	// Default is 10
+	RebroadcastAfterHeights int64 `mapstructure:"rebroadcast-after-heights"`
+
+	// PeerStateMsgRate is the number of SeenTx and WantTx messages accepted
+	// per second from a single peer, and PeerStateMsgBurst the number
+	// accepted at once. The messages over the limit are dropped, and a peer
+	// exceeding it repeatedly is disconnected. A PeerStateMsgRate of 0
+	// disables the limit.
+	// Only applicable to the v2 / CAT mempool
+	// Default is 1000 per second, with bursts of 5000
+	PeerStateMsgRate  int `mapstructure:"peer-state-msg-rate"`
+	PeerStateMsgBurst int `mapstructure:"peer-state-msg-burst"`
+
+	// CheckTxTimeout, if non-zero, is the deadline of each CheckTx call to the
+	// application, passed to it in the request. Calls past their deadline, or
+	// cancelled by the caller (e.g. a broadcast_tx_sync RPC request whose
+	// client went away), fail and the tx is discarded.
+	CheckTxTimeout time.Duration `mapstructure:"check-tx-timeout"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the CometBFT mempool
@@ -790,8 +969,12 @@ func DefaultMempoolConfig() *MempoolConfig {
 		MaxTxBytes:  1024 * 1024, // 1MB
 		ExperimentalMaxGossipConnectionsToNonPersistentPeers: 0,
 		ExperimentalMaxGossipConnectionsToPersistentPeers:    0,
-		TTLDuration:  0 * time.Second,
-		TTLNumBlocks: 0,
+		TTLDuration:             0 * time.Second,
+		TTLNumBlocks:            0,
+		MaxInFlightWantsPerPeer: 16,
+		RebroadcastAfterHeights: 10,
+		PeerStateMsgRate:        1000,
+		PeerStateMsgBurst:       5000,
 	}
 }
 
@@ -833,6 +1016,24 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.ExperimentalMaxGossipConnectionsToNonPersistentPeers < 0 {
 		return errors.New("experimental_max_gossip_connections_to_non_persistent_peers can't be negative")
 	}
+	if cfg.CheckTxTimeout < 0 {
+		return errors.New("check-tx-timeout can't be negative")
+	}
+	if cfg.MaxInFlightWantsPerPeer <= 0 {
+		return errors.New("max-in-flight-wants-per-peer must be positive")
+	}
+	if cfg.RebroadcastAfterHeights < 0 {
+		return errors.New("rebroadcast-after-heights can't be negative")
+	}
+	if cfg.PeerStateMsgRate < 0 {
+		return errors.New("peer-state-msg-rate can't be negative")
+	}
+	if cfg.PeerStateMsgBurst < 0 {
+		return errors.New("peer-state-msg-burst can't be negative")
+	}
+	if cfg.PeerStateMsgRate > 0 && cfg.PeerStateMsgBurst == 0 {
+		return errors.New("peer-state-msg-burst must be positive if peer-state-msg-rate is")
+	}
 	return nil
 }
 
@@ -850,6 +1051,32 @@ type StateSyncConfig struct {
 	DiscoveryTime       time.Duration `mapstructure:"discovery_time"`
 	ChunkRequestTimeout time.Duration `mapstructure:"chunk_request_timeout"`
 	ChunkFetchers       int32         `mapstructure:"chunk_fetchers"`
+	// ChunkRetries is the number of times a chunk is requested again after a
+	// request timed out, each time from a different peer if possible, before
+	// the snapshot is rejected.
+	ChunkRetries int32 `mapstructure:"chunk_retries"`
+	// DiscoveryTimeout is the time after which the node gives up on state
+	// sync and falls back to fast sync if the app didn't accept any snapshot,
+	// either because none were found or because all were rejected. 0 disables
+	// the fallback, i.e. snapshots are discovered until one is accepted.
+	DiscoveryTimeout time.Duration `mapstructure:"discovery_timeout"`
+	// SnapshotFormats are the snapshot formats the app can apply. They are
+	// advertised to peers when requesting snapshots, and snapshots in other
+	// formats are ignored. Empty means any format, which the app may reject.
+	SnapshotFormats []uint32 `mapstructure:"snapshot_formats"`
+	// ChunkCacheSize is the maximum size in bytes of the on-disk cache of the
+	// snapshot chunks served to peers. 0 disables the cache.
+	ChunkCacheSize int64 `mapstructure:"chunk_cache_size"`
+	// CompressChunks enables the snappy compression of the snapshot chunks
+	// served to peers which request it.
+	CompressChunks bool `mapstructure:"compress_chunks"`
+	// SnapshotPoolSize is the maximum size in bytes of the most recent local
+	// snapshot, whose chunks are written to disk ahead of time so that they are
+	// served to peers without loading them from the app. 0 disables the pool.
+	SnapshotPoolSize int64 `mapstructure:"snapshot_pool_size"`
+	// SnapshotPoolRefreshInterval is how often the app is checked for a new
+	// snapshot to write to the snapshot pool.
+	SnapshotPoolRefreshInterval time.Duration `mapstructure:"snapshot_pool_refresh_interval"`
 }
 
 func (cfg *StateSyncConfig) TrustHashBytes() []byte {
@@ -868,6 +1095,9 @@ func DefaultStateSyncConfig() *StateSyncConfig {
 		DiscoveryTime:       15 * time.Second,
 		ChunkRequestTimeout: 10 * time.Second,
 		ChunkFetchers:       4,
+		ChunkRetries:        10,
+
+		SnapshotPoolRefreshInterval: time.Minute,
 	}
 }
 
@@ -921,6 +1151,34 @@ func (cfg *StateSyncConfig) ValidateBasic() error {
 		if cfg.ChunkFetchers <= 0 {
 			return errors.New("chunk_fetchers is required")
 		}
+
+		if cfg.ChunkRetries < 0 {
+			return errors.New("chunk_retries can't be negative")
+		}
+
+		if cfg.DiscoveryTimeout < 0 {
+			return errors.New("discovery_timeout can't be negative")
+		}
+
+		formats := make(map[uint32]bool, len(cfg.SnapshotFormats))
+		for _, format := range cfg.SnapshotFormats {
+			if formats[format] {
+				return fmt.Errorf("duplicate snapshot_formats entry %d", format)
+			}
+			formats[format] = true
+		}
+	}
+
+	if cfg.ChunkCacheSize < 0 {
+		return errors.New("chunk_cache_size can't be negative")
+	}
+
+	if cfg.SnapshotPoolSize < 0 {
+		return errors.New("snapshot_pool_size can't be negative")
+	}
+
+	if cfg.SnapshotPoolSize > 0 && cfg.SnapshotPoolRefreshInterval <= 0 {
+		return errors.New("snapshot_pool_refresh_interval must be positive")
 	}
 
 	return nil
@@ -932,13 +1190,63 @@ func (cfg *StateSyncConfig) ValidateBasic() error {
 // FastSyncConfig defines the configuration for the CometBFT fast sync service
 type FastSyncConfig struct {
 	Version string `mapstructure:"version"`
+	// MaxBufferedBytes caps the size of the blocks received from peers and
+	// not applied yet. No more blocks are requested while the cap is
+	// exceeded, and fewer blocks are requested at once. 0 disables the cap.
+	MaxBufferedBytes int64 `mapstructure:"max_buffered_bytes"`
+	// ResyncThreshold is the number of heights the node may lag the median
+	// height of its peers in consensus. Once it lags by more for
+	// ResyncAfter, it switches back to fast sync until caught up. 0 disables
+	// it.
+	ResyncThreshold int64         `mapstructure:"resync_threshold"`
+	ResyncAfter     time.Duration `mapstructure:"resync_after"`
+	// Checkpoints are trusted block hashes, formatted as "height:hash" with
+	// the hash hex encoded. Fast synced blocks at these heights must match
+	// them, on top of having their commits verified.
+	Checkpoints []string `mapstructure:"checkpoints"`
 }
 
 // DefaultFastSyncConfig returns a default configuration for the fast sync service
 func DefaultFastSyncConfig() *FastSyncConfig {
 	return &FastSyncConfig{
-		Version: "v0",
+		Version:          "v0",
+		MaxBufferedBytes: 1024 * 1024 * 1024, // 1GB
+		ResyncThreshold:  100,
+		ResyncAfter:      time.Minute,
+	}
+}
+
+// CheckpointHashes returns the block hashes of the checkpoints by height.
+func (cfg *FastSyncConfig) CheckpointHashes() map[int64][]byte {
+	// validated in ValidateBasic, so we can safely panic here
+	checkpoints, err := parseCheckpoints(cfg.Checkpoints)
+	if err != nil {
+		panic(err)
 	}
+	return checkpoints
+}
+
+func parseCheckpoints(checkpoints []string) (map[int64][]byte, error) {
+	hashes := make(map[int64][]byte, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		parts := strings.Split(checkpoint, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("checkpoint %q is not formatted as height:hash", checkpoint)
+		}
+		height, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || height <= 0 {
+			return nil, fmt.Errorf("invalid height in checkpoint %q", checkpoint)
+		}
+		if _, ok := hashes[height]; ok {
+			return nil, fmt.Errorf("duplicate checkpoint at height %d", height)
+		}
+		hash, err := hex.DecodeString(parts[1])
+		if err != nil || len(hash) == 0 {
+			return nil, fmt.Errorf("invalid hash in checkpoint %q", checkpoint)
+		}
+		hashes[height] = hash
+	}
+	return hashes, nil
 }
 
 // TestFastSyncConfig returns a default configuration for the fast sync.
@@ -948,6 +1256,18 @@ func TestFastSyncConfig() *FastSyncConfig {
 
 // ValidateBasic performs basic validation.
 func (cfg *FastSyncConfig) ValidateBasic() error {
+	if cfg.MaxBufferedBytes < 0 {
+		return errors.New("max_buffered_bytes can't be negative")
+	}
+	if cfg.ResyncThreshold < 0 {
+		return errors.New("resync_threshold can't be negative")
+	}
+	if cfg.ResyncThreshold > 0 && cfg.ResyncAfter <= 0 {
+		return errors.New("resync_after must be positive")
+	}
+	if _, err := parseCheckpoints(cfg.Checkpoints); err != nil {
+		return fmt.Errorf("invalid checkpoints: %w", err)
+	}
 	switch cfg.Version {
 	case "v0":
 		return nil
@@ -976,6 +1296,17 @@ type ConsensusConfig struct {
 	TimeoutPropose time.Duration `mapstructure:"timeout_propose"`
 	// How much timeout_propose increases with each round
 	TimeoutProposeDelta time.Duration `mapstructure:"timeout_propose_delta"`
+	// Share of the propose timeout of the round given to the application to
+	// answer PrepareProposal and ProcessProposal. Past it, the proposer
+	// proposes the txs reaped from the mempool as they are, and the other
+	// validators apply ProcessProposalTimeoutPolicy. 0, the default, waits
+	// indefinitely. A call past the deadline keeps running on the consensus
+	// connection, and the proposer's fallback block lacks the data hash and
+	// square size computed by the application, so operators opt in.
+	ProposalDeadlineShare float64 `mapstructure:"proposal_deadline_share"`
+	// Whether to "accept" or "reject" a proposal the application didn't
+	// process before the deadline
+	ProcessProposalTimeoutPolicy string `mapstructure:"process_proposal_timeout_policy"`
 	// How long we wait after receiving +2/3 prevotes for “anything” (ie. not a single block or nil)
 	TimeoutPrevote time.Duration `mapstructure:"timeout_prevote"`
 	// How much the timeout_prevote increases with each round
@@ -1007,21 +1338,23 @@ type ConsensusConfig struct {
 // DefaultConsensusConfig returns a default configuration for the consensus service
 func DefaultConsensusConfig() *ConsensusConfig {
 	return &ConsensusConfig{
-		OnlyInternalWal:             true,
-		WalPath:                     filepath.Join(defaultDataDir, "cs.wal", "wal"),
-		TimeoutPropose:              3000 * time.Millisecond,
-		TimeoutProposeDelta:         500 * time.Millisecond,
-		TimeoutPrevote:              1000 * time.Millisecond,
-		TimeoutPrevoteDelta:         500 * time.Millisecond,
-		TimeoutPrecommit:            1000 * time.Millisecond,
-		TimeoutPrecommitDelta:       500 * time.Millisecond,
-		TimeoutCommit:               1000 * time.Millisecond,
-		SkipTimeoutCommit:           false,
-		CreateEmptyBlocks:           true,
-		CreateEmptyBlocksInterval:   0 * time.Second,
-		PeerGossipSleepDuration:     100 * time.Millisecond,
-		PeerQueryMaj23SleepDuration: 2000 * time.Millisecond,
-		DoubleSignCheckHeight:       int64(0),
+		OnlyInternalWal:              true,
+		WalPath:                      filepath.Join(defaultDataDir, "cs.wal", "wal"),
+		TimeoutPropose:               3000 * time.Millisecond,
+		TimeoutProposeDelta:          500 * time.Millisecond,
+		ProposalDeadlineShare:        0,
+		ProcessProposalTimeoutPolicy: ProcessProposalTimeoutReject,
+		TimeoutPrevote:               1000 * time.Millisecond,
+		TimeoutPrevoteDelta:          500 * time.Millisecond,
+		TimeoutPrecommit:             1000 * time.Millisecond,
+		TimeoutPrecommitDelta:        500 * time.Millisecond,
+		TimeoutCommit:                1000 * time.Millisecond,
+		SkipTimeoutCommit:            false,
+		CreateEmptyBlocks:            true,
+		CreateEmptyBlocksInterval:    0 * time.Second,
+		PeerGossipSleepDuration:      100 * time.Millisecond,
+		PeerQueryMaj23SleepDuration:  2000 * time.Millisecond,
+		DoubleSignCheckHeight:        int64(0),
 	}
 }
 
@@ -1069,6 +1402,18 @@ func (cfg *ConsensusConfig) ProposeWithCustomTimeout(round int32, customTimeout
 	return time.Duration(timeoutPropose.Nanoseconds()+cfg.TimeoutProposeDelta.Nanoseconds()*int64(round)) * time.Nanosecond
 }
 
+// ProposalDeadline returns how long to wait for the application to answer
+// PrepareProposal or ProcessProposal, or 0 to wait indefinitely.
+func (cfg *ConsensusConfig) ProposalDeadline(round int32) time.Duration {
+	return time.Duration(cfg.ProposalDeadlineShare * float64(cfg.Propose(round)))
+}
+
+// AcceptOnProcessProposalTimeout tells whether to accept a proposal the
+// application didn't process before the deadline.
+func (cfg *ConsensusConfig) AcceptOnProcessProposalTimeout() bool {
+	return cfg.ProcessProposalTimeoutPolicy == ProcessProposalTimeoutAccept
+}
+
 // Prevote returns the amount of time to wait for straggler votes after receiving any +2/3 prevotes
 func (cfg *ConsensusConfig) Prevote(round int32) time.Duration {
 	return time.Duration(
@@ -1125,6 +1470,15 @@ func (cfg *ConsensusConfig) ValidateBasic() error {
 	if cfg.TimeoutProposeDelta < 0 {
 		return errors.New("timeout_propose_delta can't be negative")
 	}
+	if cfg.ProposalDeadlineShare < 0 || cfg.ProposalDeadlineShare > 1 {
+		return errors.New("proposal_deadline_share must be between 0 and 1")
+	}
+	switch cfg.ProcessProposalTimeoutPolicy {
+	case ProcessProposalTimeoutAccept, ProcessProposalTimeoutReject:
+	default:
+		return fmt.Errorf("unknown process_proposal_timeout_policy %q, must be %q or %q",
+			cfg.ProcessProposalTimeoutPolicy, ProcessProposalTimeoutAccept, ProcessProposalTimeoutReject)
+	}
 	if cfg.TimeoutPrevote < 0 {
 		return errors.New("timeout_prevote can't be negative")
 	}
@@ -1167,13 +1521,89 @@ type StorageConfig struct {
 	// required for `/block_results` RPC queries, and to reindex events in the
 	// command-line tool.
 	DiscardABCIResponses bool `mapstructure:"discard_abci_responses"`
+
+	// RecomputeABCIResponses makes `/block_results` RPC queries for heights
+	// whose ABCI responses were discarded recompute them, by replaying the
+	// block in a read-only session of the application. This requires an
+	// application which supports replaying blocks (see
+	// state.ReplaySessionProvider). Without it, such queries return an error.
+	RecomputeABCIResponses bool `mapstructure:"recompute_abci_responses"`
+
+	// PruningInterval is how often blocks below the retain height set by the
+	// application are pruned in the background.
+	PruningInterval time.Duration `mapstructure:"pruning_interval"`
+
+	// PruningHeightsPerSecond limits the number of heights pruned per second.
+	// Set to 0 to disable the limit.
+	PruningHeightsPerSecond int64 `mapstructure:"pruning_heights_per_second"`
+
+	// PruningBytesPerSecond limits the number of bytes of block data pruned
+	// per second. Set to 0 to disable the limit.
+	PruningBytesPerSecond int64 `mapstructure:"pruning_bytes_per_second"`
+
+	// BlockRetentionDuration retains the blocks committed within the given
+	// duration, in addition to the blocks retained by the application. The
+	// lower of the retain height requested by the application and the one
+	// derived from this duration applies; if the application requests no
+	// retain height, only the duration applies. Set to 0 to disable.
+	BlockRetentionDuration time.Duration `mapstructure:"block_retention_duration"`
+
+	// StateCheckpointInterval keeps the validator set and consensus params of
+	// every n-th height when the state store is pruned, so that light clients
+	// can verify headers at these heights. Set to 0 to keep none.
+	StateCheckpointInterval int64 `mapstructure:"state_checkpoint_interval"`
+
+	// CompactAfterPruning triggers a compaction of the key range deleted by
+	// pruning, if the database backend supports it. With goleveldb, deleted
+	// keys only free up disk space once compacted.
+	CompactAfterPruning bool `mapstructure:"compact_after_pruning"`
+
+	// CompactionInterval is the minimum time between two compactions.
+	CompactionInterval time.Duration `mapstructure:"compaction_interval"`
+
+	// SeenBlocksHeights enables persisting all complete proposal blocks,
+	// including those that were not committed, for the given number of most
+	// recent heights. They can be queried by hash via the unsafe
+	// /unsafe_block_by_hash RPC endpoint. Set to 0 to disable.
+	SeenBlocksHeights int64 `mapstructure:"seen_blocks_heights"`
+
+	// SeenBlocksMaxCount bounds the number of seen blocks stored.
+	// Set to 0 to disable the bound.
+	SeenBlocksMaxCount int `mapstructure:"seen_blocks_max_count"`
+
+	// SeenBlocksMaxBytes bounds the total size of the seen blocks stored.
+	// Set to 0 to disable the bound.
+	SeenBlocksMaxBytes int64 `mapstructure:"seen_blocks_max_bytes"`
+
+	// VerifyBlockStore sets whether the integrity of the block store is
+	// verified on startup: "none" skips the verification, "sampled" checks
+	// VerifyBlockStoreSamples heights evenly spread over the store, and
+	// "full" checks every height. Corrupt heights are logged.
+	VerifyBlockStore string `mapstructure:"verify_block_store"`
+
+	// VerifyBlockStoreSamples is the number of heights checked by the
+	// "sampled" verification mode.
+	VerifyBlockStoreSamples int64 `mapstructure:"verify_block_store_samples"`
 }
 
 // DefaultStorageConfig returns the default configuration options relating to
 // CometBFT storage optimization.
 func DefaultStorageConfig() *StorageConfig {
 	return &StorageConfig{
-		DiscardABCIResponses: false,
+		DiscardABCIResponses:    false,
+		RecomputeABCIResponses:  false,
+		PruningInterval:         time.Second,
+		PruningHeightsPerSecond: 1000,
+		PruningBytesPerSecond:   0,
+		BlockRetentionDuration:  0,
+		StateCheckpointInterval: 10000,
+		CompactAfterPruning:     false,
+		CompactionInterval:      10 * time.Minute,
+		SeenBlocksHeights:       0,
+		SeenBlocksMaxCount:      1000,
+		SeenBlocksMaxBytes:      1024 * 1024 * 1024, // 1GB
+		VerifyBlockStore:        VerifyBlockStoreNone,
+		VerifyBlockStoreSamples: 1000,
 	}
 }
 
@@ -1181,10 +1611,64 @@ func DefaultStorageConfig() *StorageConfig {
 // testing.
 func TestStorageConfig() *StorageConfig {
 	return &StorageConfig{
-		DiscardABCIResponses: false,
+		DiscardABCIResponses:    false,
+		RecomputeABCIResponses:  false,
+		PruningInterval:         100 * time.Millisecond,
+		PruningHeightsPerSecond: 0,
+		PruningBytesPerSecond:   0,
+		BlockRetentionDuration:  0,
+		StateCheckpointInterval: 0,
+		CompactAfterPruning:     false,
+		CompactionInterval:      time.Second,
+		SeenBlocksHeights:       0,
+		SeenBlocksMaxCount:      1000,
+		SeenBlocksMaxBytes:      1024 * 1024 * 1024, // 1GB
+		VerifyBlockStore:        VerifyBlockStoreNone,
+		VerifyBlockStoreSamples: 1000,
 	}
 }
 
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *StorageConfig) ValidateBasic() error {
+	if cfg.PruningInterval <= 0 {
+		return errors.New("pruning_interval must be positive")
+	}
+	if cfg.PruningHeightsPerSecond < 0 {
+		return errors.New("pruning_heights_per_second can't be negative")
+	}
+	if cfg.PruningBytesPerSecond < 0 {
+		return errors.New("pruning_bytes_per_second can't be negative")
+	}
+	if cfg.BlockRetentionDuration < 0 {
+		return errors.New("block_retention_duration can't be negative")
+	}
+	if cfg.StateCheckpointInterval < 0 {
+		return errors.New("state_checkpoint_interval can't be negative")
+	}
+	if cfg.CompactionInterval < 0 {
+		return errors.New("compaction_interval can't be negative")
+	}
+	if cfg.SeenBlocksHeights < 0 {
+		return errors.New("seen_blocks_heights can't be negative")
+	}
+	if cfg.SeenBlocksMaxCount < 0 {
+		return errors.New("seen_blocks_max_count can't be negative")
+	}
+	if cfg.SeenBlocksMaxBytes < 0 {
+		return errors.New("seen_blocks_max_bytes can't be negative")
+	}
+	switch cfg.VerifyBlockStore {
+	case VerifyBlockStoreNone, VerifyBlockStoreSampled, VerifyBlockStoreFull:
+	default:
+		return fmt.Errorf("unknown verify_block_store mode %q", cfg.VerifyBlockStore)
+	}
+	if cfg.VerifyBlockStoreSamples <= 0 {
+		return errors.New("verify_block_store_samples must be positive")
+	}
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 // TxIndexConfig
 // Remember that Event has the following structure:
@@ -1247,6 +1731,17 @@ type InstrumentationConfig struct {
 	// Instrumentation namespace.
 	Namespace string `mapstructure:"namespace"`
 
+	// GlobalLabels is a comma separated list of label=value pairs added to
+	// every metric, along with the chain_id and node_id labels, e.g.
+	// "region=eu-west,operator=acme". It allows telling nodes apart when
+	// scraping a fleet into a single Prometheus.
+	GlobalLabels string `mapstructure:"global_labels"`
+
+	// When true, and Prometheus is enabled, the latency of database
+	// operations, the bytes read and written and the size on disk of every
+	// database are reported.
+	DBMetrics bool `mapstructure:"db_metrics"`
+
 	// TracePushConfig is the relative path of the push config. This second
 	// config contains credentials for where and how often to.
 	TracePushConfig string `mapstructure:"trace_push_config"`
@@ -1255,7 +1750,9 @@ type InstrumentationConfig struct {
 	// pulling data.
 	TracePullAddress string `mapstructure:"trace_pull_address"`
 
-	// TraceType is the type of tracer used. Options are "local" and "noop".
+	// TraceType is the type of tracer used. Options are "local", "file",
+	// "otlp", "buffer" and "noop". Several types may be combined as a comma separated
+	// list, for example "local,file".
 	TraceType string `mapstructure:"trace_type"`
 
 	// TraceBufferSize is the number of traces to write in a single batch.
@@ -1266,6 +1763,58 @@ type InstrumentationConfig struct {
 	// comma separate string. For example: "consensus_round_state,mempool_tx".
 	TracingTables string `mapstructure:"tracing_tables"`
 
+	// TraceCheckTxSampleRate is the fraction of the CheckTx calls written to
+	// the abci_call table, between 0 and 1. Other calls are all written.
+	TraceCheckTxSampleRate float64 `mapstructure:"trace_check_tx_sample_rate"`
+
+	// TraceSampleRates is the fraction of the events written to some tables,
+	// for the high frequency ones. It is represented as a comma separated
+	// list of table=rate pairs, the rate being a probability or a 1/N ratio.
+	// For example: "mempool_tx=0.1,mempool_peer_state=1/100". Other tables
+	// are not sampled.
+	TraceSampleRates string `mapstructure:"trace_sample_rates"`
+
+	// TraceFileDir is the directory the "file" tracer writes the traced
+	// tables to, relative to the home directory if not absolute.
+	TraceFileDir string `mapstructure:"trace_file_dir"`
+
+	// TraceFileMaxSize is the size, in bytes, at which the file of a table
+	// written by the "file" tracer is rotated.
+	TraceFileMaxSize int64 `mapstructure:"trace_file_max_size"`
+
+	// TraceFileMaxBackups is the number of rotated files kept per table by
+	// the "file" tracer. The oldest ones are removed.
+	TraceFileMaxBackups int `mapstructure:"trace_file_max_backups"`
+
+	// TraceOTLPEndpoint is the address, host:port, of the OpenTelemetry
+	// collector the "otlp" tracer exports the traced tables to, as log
+	// records over gRPC.
+	TraceOTLPEndpoint string `mapstructure:"trace_otlp_endpoint"`
+
+	// TraceOTLPInsecure disables TLS when connecting to TraceOTLPEndpoint.
+	TraceOTLPInsecure bool `mapstructure:"trace_otlp_insecure"`
+
+	// TracePeerBandwidthInterval is the interval at which the traffic of each
+	// peer is written to the peer_bandwidth table, if it is traced.
+	TracePeerBandwidthInterval time.Duration `mapstructure:"trace_peer_bandwidth_interval"`
+
+	// TraceDumpBufferSize is the number of rows of each table kept in memory
+	// by the "buffer" tracer.
+	TraceDumpBufferSize int `mapstructure:"trace_dump_buffer_size"`
+
+	// TraceDumpBufferSizes overrides TraceDumpBufferSize for some tables. It
+	// is represented as a comma separated list of table=size pairs. For
+	// example: "mempool_tx=100000,consensus_block=100".
+	TraceDumpBufferSizes string `mapstructure:"trace_dump_buffer_sizes"`
+
+	// TraceDumpAddress is the address the "buffer" tracer serves the rows it
+	// keeps on, under /trace_dump. If empty, the rows are not served.
+	TraceDumpAddress string `mapstructure:"trace_dump_address"`
+
+	// TraceDumpToken is the bearer token the requests to the trace dump
+	// endpoint must be authorized with. It is required by the endpoint.
+	TraceDumpToken string `mapstructure:"trace_dump_token"`
+
 	// PyroscopeURL is the pyroscope url used to establish a connection with a
 	// pyroscope continuous profiling server.
 	PyroscopeURL string `mapstructure:"pyroscope_url"`
@@ -1284,17 +1833,30 @@ type InstrumentationConfig struct {
 // reporting.
 func DefaultInstrumentationConfig() *InstrumentationConfig {
 	return &InstrumentationConfig{
-		Prometheus:           false,
-		PrometheusListenAddr: ":26660",
-		MaxOpenConnections:   3,
-		Namespace:            "cometbft",
-		TracePushConfig:      "",
-		TracePullAddress:     "",
-		TraceType:            "noop",
-		TraceBufferSize:      1000,
-		TracingTables:        DefaultTracingTables,
-		PyroscopeURL:         "",
-		PyroscopeTrace:       false,
+		Prometheus:                 false,
+		PrometheusListenAddr:       ":26660",
+		MaxOpenConnections:         3,
+		Namespace:                  "cometbft",
+		DBMetrics:                  false,
+		TracePushConfig:            "",
+		TracePullAddress:           "",
+		TraceType:                  "noop",
+		TraceBufferSize:            1000,
+		TracingTables:              DefaultTracingTables,
+		TraceCheckTxSampleRate:     0.01,
+		TraceSampleRates:           "",
+		TraceFileDir:               filepath.Join(defaultDataDir, "trace_files"),
+		TraceFileMaxSize:           100 * 1024 * 1024, // 100MB
+		TraceFileMaxBackups:        5,
+		TraceOTLPEndpoint:          "",
+		TraceOTLPInsecure:          false,
+		TracePeerBandwidthInterval: 10 * time.Second,
+		TraceDumpBufferSize:        10000,
+		TraceDumpBufferSizes:       "",
+		TraceDumpAddress:           "",
+		TraceDumpToken:             "",
+		PyroscopeURL:               "",
+		PyroscopeTrace:             false,
 		PyroscopeProfileTypes: []string{
 			"cpu",
 			"alloc_objects",
@@ -1320,6 +1882,38 @@ func (cfg *InstrumentationConfig) ValidateBasic() error {
 	if cfg.MaxOpenConnections < 0 {
 		return errors.New("max_open_connections can't be negative")
 	}
+	if _, err := cfg.ParseGlobalLabels(); err != nil {
+		return err
+	}
+	if cfg.TraceCheckTxSampleRate < 0 || cfg.TraceCheckTxSampleRate > 1 {
+		return errors.New("trace_check_tx_sample_rate must be between 0 and 1")
+	}
+	if _, err := cfg.ParseTraceSampleRates(); err != nil {
+		return err
+	}
+	for _, traceType := range strings.Split(cfg.TraceType, ",") {
+		if strings.TrimSpace(traceType) == "otlp" && cfg.TraceOTLPEndpoint == "" {
+			return errors.New("trace_otlp_endpoint is required by the otlp tracer")
+		}
+	}
+	if cfg.TraceFileMaxSize <= 0 {
+		return errors.New("trace_file_max_size must be positive")
+	}
+	if cfg.TraceFileMaxBackups < 0 {
+		return errors.New("trace_file_max_backups can't be negative")
+	}
+	if cfg.TracePeerBandwidthInterval <= 0 {
+		return errors.New("trace_peer_bandwidth_interval must be positive")
+	}
+	if cfg.TraceDumpBufferSize <= 0 {
+		return errors.New("trace_dump_buffer_size must be positive")
+	}
+	if _, err := cfg.ParseTraceDumpBufferSizes(); err != nil {
+		return err
+	}
+	if cfg.TraceDumpAddress != "" && cfg.TraceDumpToken == "" {
+		return errors.New("trace_dump_token is required to serve the trace dump endpoint")
+	}
 	if cfg.PyroscopeTrace && cfg.PyroscopeURL == "" {
 		return errors.New("pyroscope_trace can't be enabled if profiling is disabled")
 	}
@@ -1340,6 +1934,147 @@ func (cfg *InstrumentationConfig) ValidateBasic() error {
 	return nil
 }
 
+// ParseTraceSampleRates returns the sample rate of each table listed in
+// TraceSampleRates.
+func (cfg *InstrumentationConfig) ParseTraceSampleRates() (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(cfg.TraceSampleRates, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		table, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("trace_sample_rates: %q is not a table=rate pair", pair)
+		}
+		rate, err := parseSampleRate(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("trace_sample_rates: invalid rate for %s: %w", table, err)
+		}
+		rates[strings.TrimSpace(table)] = rate
+	}
+	return rates, nil
+}
+
+// ParseTraceDumpBufferSizes returns the buffer size of each table, the ones
+// listed in TraceDumpBufferSizes and TraceDumpBufferSize for the others.
+func (cfg *InstrumentationConfig) ParseTraceDumpBufferSizes() (map[string]int, error) {
+	sizes := make(map[string]int)
+	for _, pair := range strings.Split(cfg.TraceDumpBufferSizes, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		table, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("trace_dump_buffer_sizes: %q is not a table=size pair", pair)
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("trace_dump_buffer_sizes: invalid size for %s: %w", table, err)
+		}
+		if size <= 0 {
+			return nil, fmt.Errorf("trace_dump_buffer_sizes: size of %s must be positive", table)
+		}
+		sizes[strings.TrimSpace(table)] = size
+	}
+	for _, table := range strings.Split(cfg.TracingTables, ",") {
+		table = strings.TrimSpace(table)
+		if _, ok := sizes[table]; !ok && table != "" {
+			sizes[table] = cfg.TraceDumpBufferSize
+		}
+	}
+	return sizes, nil
+}
+
+// reservedMetricLabels are the labels set by the node on every metric, which
+// can't be global labels.
+var reservedMetricLabels = map[string]bool{"chain_id": true, "node_id": true, "version": true}
+
+// ParseGlobalLabels parses GlobalLabels into label and value pairs, e.g.
+// ["region", "eu-west", "operator", "acme"], as the metrics constructors take
+// them. The labels must be valid Prometheus label names, not reserved, and
+// their values valid metric label values.
+func (cfg *InstrumentationConfig) ParseGlobalLabels() ([]string, error) {
+	var labelsAndValues []string
+	seen := make(map[string]bool)
+	for _, pair := range strings.Split(cfg.GlobalLabels, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		label, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("global_labels: %q is not a label=value pair", pair)
+		}
+		label, value = strings.TrimSpace(label), strings.TrimSpace(value)
+		if !metricLabelRegexp.MatchString(label) || strings.HasPrefix(label, "__") {
+			return nil, fmt.Errorf("global_labels: invalid label name %q", label)
+		}
+		if reservedMetricLabels[label] {
+			return nil, fmt.Errorf("global_labels: label %s is set by the node", label)
+		}
+		if seen[label] {
+			return nil, fmt.Errorf("global_labels: duplicate label %s", label)
+		}
+		if err := ValidateMetricLabelValue(value); err != nil {
+			return nil, fmt.Errorf("global_labels: value of %s: %w", label, err)
+		}
+		seen[label] = true
+		labelsAndValues = append(labelsAndValues, label, value)
+	}
+	return labelsAndValues, nil
+}
+
+var metricLabelRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// ValidateMetricLabelValue returns an error if value can't be the value of a
+// label added to every metric: it must be non empty, valid UTF-8 and
+// printable.
+func ValidateMetricLabelValue(value string) error {
+	if value == "" {
+		return errors.New("empty value")
+	}
+	if !utf8.ValidString(value) {
+		return errors.New("value is not valid UTF-8")
+	}
+	for _, r := range value {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("value %q has a non printable character", value)
+		}
+	}
+	return nil
+}
+
+// parseSampleRate parses a probability, e.g. "0.01", or a 1/N ratio, e.g.
+// "1/100".
+func parseSampleRate(s string) (float64, error) {
+	var rate float64
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, err
+		}
+		d, err := strconv.ParseFloat(den, 64)
+		if err != nil {
+			return 0, err
+		}
+		if d <= 0 {
+			return 0, fmt.Errorf("%q has a non-positive denominator", s)
+		}
+		rate = n / d
+	} else {
+		var err error
+		if rate, err = strconv.ParseFloat(s, 64); err != nil {
+			return 0, err
+		}
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("%q is not between 0 and 1", s)
+	}
+	return rate, nil
+}
+
 //-----------------------------------------------------------------------------
 // Utils
 
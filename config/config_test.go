@@ -61,6 +61,34 @@ func TestBaseConfigValidateBasic(t *testing.T) {
 	// tamper with log format
 	cfg.LogFormat = "invalid"
 	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestBaseConfig()
+	cfg.PrivValidatorFailoverThreshold = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	for _, tamper := range []func(*BaseConfig){
+		func(c *BaseConfig) { c.PrivValidatorPingInterval = 0 },
+		func(c *BaseConfig) { c.PrivValidatorPongTimeout = -time.Second },
+		func(c *BaseConfig) { c.PrivValidatorSignTimeout = 0 },
+		func(c *BaseConfig) { c.ABCICheckTxConcurrency = -1 },
+		func(c *BaseConfig) { c.ABCICheckTxConcurrency = 0 },
+		func(c *BaseConfig) {
+			c.ProxyApp = "tcp://127.0.0.1:26658"
+			c.ABCICheckTxConcurrency = 2
+		},
+		func(c *BaseConfig) { c.ShutdownTimeout = 0 },
+	} {
+		cfg = TestBaseConfig()
+		tamper(&cfg)
+		assert.Error(t, cfg.ValidateBasic())
+	}
+
+	cfg = TestBaseConfig()
+	cfg.ABCICheckTxConcurrency = 2
+	assert.NoError(t, cfg.ValidateBasic(), "compiled in application")
+	cfg.ProxyApp = "tcp://127.0.0.1:26658"
+	cfg.ABCI = "grpc"
+	assert.NoError(t, cfg.ValidateBasic())
 }
 
 func TestRPCConfigValidateBasic(t *testing.T) {
@@ -75,6 +103,10 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		"TimeoutBroadcastTxCommit",
 		"MaxBodyBytes",
 		"MaxHeaderBytes",
+		"LivenessMaxHeartbeatAge",
+		"ReadinessMinPeers",
+		"ReadinessMaxSyncLag",
+		"ReadinessMaxBlockAge",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -82,6 +114,12 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg = TestRPCConfig()
+	cfg.SubscriptionOverflowPolicy = "drop_oldest"
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.SubscriptionOverflowPolicy = "block"
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestP2PConfigValidateBasic(t *testing.T) {
@@ -113,6 +151,10 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		"MaxTxsBytes",
 		"CacheSize",
 		"MaxTxBytes",
+		"CheckTxTimeout",
+		"RebroadcastAfterHeights",
+		"PeerStateMsgRate",
+		"PeerStateMsgBurst",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -120,11 +162,60 @@ func TestMempoolConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.MaxInFlightWantsPerPeer = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	// a rate of 0 disables the limit, whatever the burst
+	cfg = TestMempoolConfig()
+	cfg.PeerStateMsgRate = 0
+	cfg.PeerStateMsgBurst = 0
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.PeerStateMsgRate = 10
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestStateSyncConfigValidateBasic(t *testing.T) {
 	cfg := TestStateSyncConfig()
 	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.Enable = true
+	cfg.RPCServers = []string{"127.0.0.1:26657", "127.0.0.1:26658"}
+	cfg.TrustHeight = 1
+	cfg.TrustHash = "0123456789abcdef"
+	require.NoError(t, cfg.ValidateBasic())
+
+	cfg.ChunkRetries = 0
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.ChunkRetries = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ChunkRetries = 0
+
+	cfg.DiscoveryTimeout = time.Minute
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.DiscoveryTimeout = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.DiscoveryTimeout = 0
+
+	cfg.SnapshotFormats = []uint32{2, 1}
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.SnapshotFormats = []uint32{2, 1, 2}
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SnapshotFormats = nil
+
+	cfg.ChunkCacheSize = 1 << 20
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.ChunkCacheSize = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ChunkCacheSize = 0
+
+	cfg.SnapshotPoolSize = 1 << 30
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.SnapshotPoolRefreshInterval = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SnapshotPoolRefreshInterval = time.Minute
+	cfg.SnapshotPoolSize = -1
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 func TestFastSyncConfigValidateBasic(t *testing.T) {
@@ -137,6 +228,79 @@ func TestFastSyncConfigValidateBasic(t *testing.T) {
 
 	cfg.Version = "invalid"
 	assert.Error(t, cfg.ValidateBasic())
+	cfg.Version = "v0"
+
+	cfg.MaxBufferedBytes = 0
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.MaxBufferedBytes = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.MaxBufferedBytes = 0
+
+	cfg.ResyncAfter = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ResyncThreshold = 0
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.ResyncThreshold = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.ResyncThreshold = 0
+
+	cfg.Checkpoints = []string{"10:0a0b", "20:0C0D"}
+	assert.NoError(t, cfg.ValidateBasic())
+	assert.Equal(t, map[int64][]byte{10: {0x0a, 0x0b}, 20: {0x0c, 0x0d}}, cfg.CheckpointHashes())
+
+	for _, checkpoints := range [][]string{
+		{"10"},
+		{"10:0a0b:0c"},
+		{"0:0a0b"},
+		{"x:0a0b"},
+		{"10:"},
+		{"10:xyz"},
+		{"10:0a0b", "10:0c0d"},
+	} {
+		cfg.Checkpoints = checkpoints
+		assert.Error(t, cfg.ValidateBasic(), checkpoints)
+	}
+}
+
+func TestStorageConfigValidateBasic(t *testing.T) {
+	cfg := TestStorageConfig()
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.PruningInterval = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.PruningHeightsPerSecond = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.PruningBytesPerSecond = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.BlockRetentionDuration = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.StateCheckpointInterval = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.CompactionInterval = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.SeenBlocksHeights = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.VerifyBlockStore = "partial"
+	assert.Error(t, cfg.ValidateBasic())
+
+	cfg = TestStorageConfig()
+	cfg.VerifyBlockStore = VerifyBlockStoreSampled
+	cfg.VerifyBlockStoreSamples = 0
+	assert.Error(t, cfg.ValidateBasic())
 }
 
 //nolint:lll
@@ -164,6 +328,9 @@ func TestConsensusConfig_ValidateBasic(t *testing.T) {
 		"PeerQueryMaj23SleepDuration":          {func(c *ConsensusConfig) { c.PeerQueryMaj23SleepDuration = time.Second }, false},
 		"PeerQueryMaj23SleepDuration negative": {func(c *ConsensusConfig) { c.PeerQueryMaj23SleepDuration = -1 }, true},
 		"DoubleSignCheckHeight negative":       {func(c *ConsensusConfig) { c.DoubleSignCheckHeight = -1 }, true},
+		"ProposalDeadlineShare above 1":        {func(c *ConsensusConfig) { c.ProposalDeadlineShare = 1.5 }, true},
+		"ProcessProposalTimeoutPolicy accept":  {func(c *ConsensusConfig) { c.ProcessProposalTimeoutPolicy = "accept" }, false},
+		"ProcessProposalTimeoutPolicy unknown": {func(c *ConsensusConfig) { c.ProcessProposalTimeoutPolicy = "maybe" }, true},
 	}
 
 	for desc, tc := range testcases {
@@ -189,6 +356,115 @@ func TestInstrumentationConfigValidateBasic(t *testing.T) {
 	// tamper with maximum open connections
 	cfg.MaxOpenConnections = -1
 	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the CheckTx trace sample rate
+	cfg = TestInstrumentationConfig()
+	cfg.TraceCheckTxSampleRate = 1.5
+	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the trace sample rates
+	cfg = TestInstrumentationConfig()
+	cfg.TraceSampleRates = "mempool_tx=1.5"
+	assert.Error(t, cfg.ValidateBasic())
+
+	// use the otlp tracer without an endpoint
+	cfg = TestInstrumentationConfig()
+	cfg.TraceType = "local,otlp"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.TraceOTLPEndpoint = "localhost:4317"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with the trace file rotation
+	cfg = TestInstrumentationConfig()
+	cfg.TraceFileMaxSize = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg = TestInstrumentationConfig()
+	cfg.TraceFileMaxBackups = -1
+	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the peer bandwidth interval
+	cfg = TestInstrumentationConfig()
+	cfg.TracePeerBandwidthInterval = 0
+	assert.Error(t, cfg.ValidateBasic())
+
+	// tamper with the trace dump buffers
+	cfg = TestInstrumentationConfig()
+	cfg.TraceDumpBufferSize = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg = TestInstrumentationConfig()
+	cfg.TraceDumpBufferSizes = "mempool_tx=-1"
+	assert.Error(t, cfg.ValidateBasic())
+
+	// serve the trace dump endpoint without a token
+	cfg = TestInstrumentationConfig()
+	cfg.TraceDumpAddress = "localhost:26662"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.TraceDumpToken = "secret"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	// tamper with the global labels
+	cfg = TestInstrumentationConfig()
+	cfg.GlobalLabels = "chain_id=other"
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestInstrumentationConfigParseGlobalLabels(t *testing.T) {
+	cfg := TestInstrumentationConfig()
+	labels, err := cfg.ParseGlobalLabels()
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+
+	cfg.GlobalLabels = " region = eu-west,, operator=Acme Corp"
+	labels, err = cfg.ParseGlobalLabels()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"region", "eu-west", "operator", "Acme Corp"}, labels)
+
+	for _, invalid := range []string{
+		"region", "region=", "1region=eu", "re-gion=eu", "__region=eu", "region=eu,region=us",
+		"node_id=abc", "version=1", "region=eu\nwest", "region=\xff",
+	} {
+		cfg.GlobalLabels = invalid
+		_, err = cfg.ParseGlobalLabels()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestInstrumentationConfigParseTraceDumpBufferSizes(t *testing.T) {
+	cfg := TestInstrumentationConfig()
+	cfg.TracingTables = "mempool_tx,consensus_block"
+	cfg.TraceDumpBufferSize = 10
+	cfg.TraceDumpBufferSizes = " mempool_tx = 100,, peers=5"
+	sizes, err := cfg.ParseTraceDumpBufferSizes()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"mempool_tx": 100, "consensus_block": 10, "peers": 5}, sizes)
+
+	for _, invalid := range []string{"mempool_tx", "mempool_tx=abc", "mempool_tx=0"} {
+		cfg.TraceDumpBufferSizes = invalid
+		_, err := cfg.ParseTraceDumpBufferSizes()
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestInstrumentationConfigParseTraceSampleRates(t *testing.T) {
+	cfg := TestInstrumentationConfig()
+	rates, err := cfg.ParseTraceSampleRates()
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+
+	cfg.TraceSampleRates = " mempool_tx = 0.1, abci_call=1/100,, mempool_peer_state=0"
+	rates, err = cfg.ParseTraceSampleRates()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		"mempool_tx":         0.1,
+		"abci_call":          0.01,
+		"mempool_peer_state": 0,
+	}, rates)
+
+	for _, invalid := range []string{"mempool_tx", "mempool_tx=a", "mempool_tx=-0.1", "mempool_tx=2/1", "mempool_tx=1/0"} {
+		cfg.TraceSampleRates = invalid
+		_, err = cfg.ParseTraceSampleRates()
+		assert.Error(t, err, invalid)
+	}
 }
 
 func TestProposeWithCustomTimeout(t *testing.T) {
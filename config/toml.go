@@ -92,7 +92,7 @@ moniker = "{{ .BaseConfig.Moniker }}"
 # and verifying their commits
 fast_sync = {{ .BaseConfig.FastSyncMode }}
 
-# Database backend: goleveldb | cleveldb | boltdb | rocksdb | badgerdb
+# Database backend: goleveldb | cleveldb | boltdb | rocksdb | badgerdb | pebbledb
 # * goleveldb (github.com/syndtr/goleveldb - most popular implementation)
 #   - pure go
 #   - stable
@@ -111,6 +111,11 @@ fast_sync = {{ .BaseConfig.FastSyncMode }}
 # * badgerdb (uses github.com/dgraph-io/badger)
 #   - EXPERIMENTAL
 #   - use badgerdb build tag (go build -tags badgerdb)
+# * pebbledb (uses github.com/cockroachdb/pebble)
+#   - EXPERIMENTAL
+#   - pure go
+#   - lower write amplification than goleveldb on large stores
+#   - not compatible with the on-disk format of other backends
 db_backend = "{{ .BaseConfig.DBBackend }}"
 
 # Database directory
@@ -133,20 +138,56 @@ priv_validator_key_file = "{{ js .BaseConfig.PrivValidatorKey }}"
 # Path to the JSON file containing the last sign state of a validator
 priv_validator_state_file = "{{ js .BaseConfig.PrivValidatorState }}"
 
+# If true, wait for the last sign state of a validator to be flushed to disk,
+# along with its directory, every time it's saved. Disabling it is faster, but
+# a crash may then lose the last signature and lead to double signing
+priv_validator_state_fsync = {{ .BaseConfig.PrivValidatorStateFsync }}
+
 # TCP or UNIX socket address for CometBFT to listen on for
-# connections from an external PrivValidator process
+# connections from an external PrivValidator process. Several comma separated
+# addresses can be given, in priority order, to fail over between several
+# signers. They must all hold the same key: startup fails if two of them
+# don't, and a signer unreachable at startup is only used once it holds it
 priv_validator_laddr = "{{ .BaseConfig.PrivValidatorListenAddr }}"
 
+# Number of consecutive failed requests to an external PrivValidator after
+# which CometBFT fails over to the next address of priv_validator_laddr
+priv_validator_failover_threshold = {{ .BaseConfig.PrivValidatorFailoverThreshold }}
+
+# How often the connection to an external PrivValidator is checked with a ping
+# when idle. It must be less than the read timeout of the PrivValidator process
+priv_validator_ping_interval = "{{ .BaseConfig.PrivValidatorPingInterval }}"
+
+# How long to wait for an external PrivValidator to answer a ping before
+# dropping the connection. Lower it to detect failures of nearby signers
+# faster, raise it for remote signers on slow links
+priv_validator_pong_timeout = "{{ .BaseConfig.PrivValidatorPongTimeout }}"
+
+# How long to wait for an external PrivValidator to answer a sign request
+priv_validator_sign_timeout = "{{ .BaseConfig.PrivValidatorSignTimeout }}"
+
 # Path to the JSON file containing the private key to use for node authentication in the p2p protocol
 node_key_file = "{{ js .BaseConfig.NodeKey }}"
 
 # Mechanism to connect to the ABCI application: socket | grpc
 abci = "{{ .BaseConfig.ABCI }}"
 
+# Maximum number of CheckTx requests outstanding at once. 1 serializes them.
+# Set it higher only if the application supports concurrent CheckTx calls.
+# Consensus calls are always made one at a time. Values above 1 need the grpc
+# transport or an application compiled in with the binary: the socket
+# protocol matches responses to requests by their order.
+abci_check_tx_concurrency = {{ .BaseConfig.ABCICheckTxConcurrency }}
+
 # If true, query the ABCI app on connecting to a new peer
 # so the app can decide if we should keep the connection or not
 filter_peers = {{ .BaseConfig.FilterPeers }}
 
+# How long stopping the node waits for the block being applied to be
+# committed, and for the messages queued for the peers to be flushed. Past it,
+# the node stops without closing its databases.
+shutdown_timeout = "{{ .BaseConfig.ShutdownTimeout }}"
+
 
 #######################################################################
 ###                 Advanced Configuration Options                  ###
@@ -212,6 +253,12 @@ max_subscriptions_per_client = {{ .RPC.MaxSubscriptionsPerClient }}
 # higher event throughput rates (and will use more memory).
 experimental_subscription_buffer_size = {{ .RPC.SubscriptionBufferSize }}
 
+# Experimental parameter to specify what happens to the events published to a
+# subscription whose buffer is full: "terminate" closes the subscription with
+# an error, and "drop_oldest" drops its oldest events, the next event delivered
+# reporting how many were dropped in its "dropped" field.
+experimental_subscription_overflow_policy = "{{ .RPC.SubscriptionOverflowPolicy }}"
+
 # Experimental parameter to specify the maximum number of RPC responses that
 # can be buffered per WebSocket client. If clients cannot read from the
 # WebSocket endpoint fast enough, they will be disconnected, so increasing this
@@ -263,6 +310,22 @@ tls_key_file = "{{ .RPC.TLSKeyFile }}"
 # pprof listen address (https://golang.org/pkg/net/http/pprof)
 pprof_laddr = "{{ .RPC.PprofListenAddress }}"
 
+# The maximum time since the core goroutines of the node (the consensus
+# receive routine and the event bus) last made progress before /livez reports
+# the node as not live. 0 disables the check.
+liveness_max_heartbeat_age = "{{ .RPC.LivenessMaxHeartbeatAge }}"
+
+# The minimum number of peers of the node for /readyz to report it as ready.
+readiness_min_peers = {{ .RPC.ReadinessMinPeers }}
+
+# The maximum number of blocks the node can be behind its highest peer for
+# /readyz to report it as ready.
+readiness_max_sync_lag = {{ .RPC.ReadinessMaxSyncLag }}
+
+# The maximum age of the last block of the node for /readyz to report it as
+# ready. 0 disables the check, which is needed if empty blocks aren't created.
+readiness_max_block_age = "{{ .RPC.ReadinessMaxBlockAge }}"
+
 #######################################################
 ###           P2P Configuration Options             ###
 #######################################################
@@ -404,6 +467,35 @@ ttl-num-blocks = {{ .Mempool.TTLNumBlocks }}
 # Default is 200ms
 max-gossip-delay = "{{ .Mempool.MaxGossipDelay }}"
 
+# max-in-flight-wants-per-peer is the maximum number of transactions requested
+# from a single peer at once. The transactions over the limit are requested from
+# other peers which have them, or once a request to the peer completes.
+# Only applicable to the v2 / CAT mempool
+# Default is 16
+max-in-flight-wants-per-peer = {{ .Mempool.MaxInFlightWantsPerPeer }}
+
+# rebroadcast-after-heights, if non-zero, is the number of heights after which a
+# transaction still in the mempool is broadcast again to the peers which haven't
+# seen it, and again every as many heights.
+# Only applicable to the v2 / CAT mempool
+# Default is 10
+rebroadcast-after-heights = {{ .Mempool.RebroadcastAfterHeights }}
+
+# peer-state-msg-rate is the number of SeenTx and WantTx messages accepted per
+# second from a single peer, and peer-state-msg-burst the number accepted at
+# once. The messages over the limit are dropped, and a peer exceeding it
+# repeatedly is disconnected. A peer-state-msg-rate of 0 disables the limit.
+# Only applicable to the v2 / CAT mempool
+# Default is 1000 per second, with bursts of 5000
+peer-state-msg-rate = {{ .Mempool.PeerStateMsgRate }}
+peer-state-msg-burst = {{ .Mempool.PeerStateMsgBurst }}
+
+# check-tx-timeout, if non-zero, is the deadline of each CheckTx call to the
+# application, passed to it in the request. Calls past their deadline, or
+# cancelled by the caller (e.g. a broadcast_tx_sync RPC request whose client
+# went away), fail and the tx is discarded.
+check-tx-timeout = "{{ .Mempool.CheckTxTimeout }}"
+
 # Experimental parameters to limit gossiping txs to up to the specified number of peers.
 # This feature is only available for the default mempool (version config set to "v0").
 # We use two independent upper values for persistent and non-persistent peers.
@@ -445,17 +537,54 @@ trust_period = "{{ .StateSync.TrustPeriod }}"
 # Time to spend discovering snapshots before initiating a restore.
 discovery_time = "{{ .StateSync.DiscoveryTime }}"
 
+# Time after which state sync is given up if the application didn't accept any
+# snapshot, either because no snapshots were found or because all of them were
+# rejected. The node then falls back to fast sync from genesis, or to consensus
+# if fast sync is disabled. 0 disables the fallback (default: 0s).
+discovery_timeout = "{{ .StateSync.DiscoveryTimeout }}"
+
+# Comma-separated list of the snapshot formats the application can apply. They are
+# advertised to peers, which only offer snapshots in these formats, and snapshots in
+# other formats are ignored. Among the snapshots at the same height, the greatest
+# supported format is preferred. Empty means any format, which the application may
+# then reject.
+snapshot_formats = "{{ range $i, $format := .StateSync.SnapshotFormats }}{{ if $i }},{{ end }}{{ $format }}{{ end }}"
+
 # Temporary directory for state sync snapshot chunks, defaults to the OS tempdir (typically /tmp).
 # Will create a new, randomly named directory within, and remove it when done.
 temp_dir = "{{ .StateSync.TempDir }}"
 
-# The timeout duration before re-requesting a chunk, possibly from a different
-# peer (default: 1 minute).
+# The timeout duration before re-requesting a chunk from a different peer
+# (default: 10 seconds).
 chunk_request_timeout = "{{ .StateSync.ChunkRequestTimeout }}"
 
-# The number of concurrent chunk fetchers to run (default: 1).
+# The number of concurrent chunk fetchers to run (default: 4). Each fetcher
+# requests a distinct chunk, preferably from a peer which isn't busy with
+# another chunk.
 chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 
+# The number of times a chunk is re-requested after a request timed out before
+# the snapshot is rejected (default: 10).
+chunk_retries = {{ .StateSync.ChunkRetries }}
+
+# The maximum size in bytes of the on-disk cache of the snapshot chunks served to
+# peers, which saves loading them from the application again. 0 disables the cache.
+chunk_cache_size = {{ .StateSync.ChunkCacheSize }}
+
+# Whether to compress the snapshot chunks served to peers with snappy, if they
+# request it.
+compress_chunks = {{ .StateSync.CompressChunks }}
+
+# The maximum size in bytes of the most recent local snapshot, whose chunks are
+# written to disk ahead of time so that peers are served without loading them
+# from the application. Snapshots larger than this aren't written. 0 disables
+# the snapshot pool.
+snapshot_pool_size = {{ .StateSync.SnapshotPoolSize }}
+
+# How often the application is checked for a new snapshot to write to the
+# snapshot pool (default: 1 minute).
+snapshot_pool_refresh_interval = "{{ .StateSync.SnapshotPoolRefreshInterval }}"
+
 #######################################################
 ###       Fast Sync Configuration Connections       ###
 #######################################################
@@ -467,6 +596,24 @@ chunk_fetchers = "{{ .StateSync.ChunkFetchers }}"
 #   be completely removed in one of the upcoming releases
 version = "{{ .FastSync.Version }}"
 
+# The maximum size in bytes of the blocks received from peers and not applied
+# yet. No more blocks are requested while it is exceeded, and the number of
+# blocks requested at once shrinks. 0 disables the cap.
+max_buffered_bytes = {{ .FastSync.MaxBufferedBytes }}
+
+# The number of heights the node may lag the median height of its peers once
+# in consensus. If it lags by more for resync_after, it switches back to fast
+# sync until caught up. 0 disables it. Only applies if fast_sync is enabled.
+resync_threshold = {{ .FastSync.ResyncThreshold }}
+resync_after = "{{ .FastSync.ResyncAfter }}"
+
+# Trusted block hashes, as a comma separated list of "height:hash" with the
+# hash hex encoded, e.g. "1000:0A1B...,2000:3C4D...". A block whose hash
+# doesn't match the checkpoint at its height is rejected: the peer which sent
+# it is stopped and the block is requested again from another peer. The
+# commits of all blocks are verified regardless of the checkpoints.
+checkpoints = "{{ StringsJoin .FastSync.Checkpoints "," }}"
+
 #######################################################
 ###         Consensus Configuration Options         ###
 #######################################################
@@ -485,6 +632,17 @@ wal_file = "{{ js .Consensus.WalPath }}"
 timeout_propose = "{{ .Consensus.TimeoutPropose }}"
 # How much timeout_propose increases with each round
 timeout_propose_delta = "{{ .Consensus.TimeoutProposeDelta }}"
+# Share of the propose timeout of the round given to the application to answer
+# PrepareProposal and ProcessProposal. Past it, the proposer proposes the txs
+# reaped from the mempool as they are, and the other validators apply
+# process_proposal_timeout_policy. 0, the default, waits indefinitely.
+# A call past the deadline keeps running on the consensus connection, and the
+# fallback block lacks the data hash and square size computed by the
+# application, hence the deadline is disabled unless set here.
+proposal_deadline_share = {{ .Consensus.ProposalDeadlineShare }}
+# Whether to "accept" or "reject" a proposal the application didn't process
+# before the deadline
+process_proposal_timeout_policy = "{{ .Consensus.ProcessProposalTimeoutPolicy }}"
 # How long we wait after receiving +2/3 prevotes for “anything” (ie. not a single block or nil)
 timeout_prevote = "{{ .Consensus.TimeoutPrevote }}"
 # How much the timeout_prevote increases with each round
@@ -528,6 +686,73 @@ peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
 # reindex events in the command-line tool.
 discard_abci_responses = {{ .Storage.DiscardABCIResponses}}
 
+# When ABCI responses are discarded, only the responses of the last height are
+# kept, and ABCI responses stored before are deleted by the background pruner.
+# Set to true to have /block_results queries for older heights recompute the
+# responses by replaying the block in a read-only session of the application.
+# This requires an application which supports replaying blocks; otherwise,
+# such queries return an error.
+recompute_abci_responses = {{ .Storage.RecomputeABCIResponses }}
+
+# How often blocks below the retain height requested by the application are
+# pruned in the background. Pruning only records the retain height when the
+# application commits, so that deleting many blocks does not stall consensus.
+pruning_interval = "{{ .Storage.PruningInterval }}"
+
+# Maximum number of heights, and bytes of block data, pruned per second.
+# Set to 0 to disable the respective limit.
+pruning_heights_per_second = {{ .Storage.PruningHeightsPerSecond }}
+pruning_bytes_per_second = {{ .Storage.PruningBytesPerSecond }}
+
+# Retain the blocks committed within the given duration (e.g. "504h" for three
+# weeks), regardless of the number of heights this amounts to. The lower of the
+# retain height requested by the application and the one derived from this
+# duration applies; if the application requests no retain height, only the
+# duration applies. The effective retain height is reported by /status.
+# Set to 0 to disable.
+block_retention_duration = "{{ .Storage.BlockRetentionDuration }}"
+
+# The validator sets and consensus params in the state store are pruned along
+# with the blocks, except for those needed to verify evidence which has not
+# expired yet (see the evidence max age consensus params). The validator set
+# and consensus params of every state_checkpoint_interval-th height are kept,
+# so that light clients can still verify headers at these heights.
+# Set to 0 to keep none.
+state_checkpoint_interval = {{ .Storage.StateCheckpointInterval }}
+
+# Compact the key range deleted by pruning, if the database backend supports
+# it (e.g. goleveldb). Without compaction, goleveldb only reclaims the disk
+# space of pruned blocks when it happens to compact the affected files.
+# Compaction runs right after a block was committed, at most once per
+# compaction_interval.
+compact_after_pruning = {{ .Storage.CompactAfterPruning }}
+compaction_interval = "{{ .Storage.CompactionInterval }}"
+
+# Persist all complete proposal blocks, including those that were never
+# committed, for the given number of most recent heights. This is useful to
+# debug consensus failures; the blocks can be queried by hash via the unsafe
+# /unsafe_block_by_hash RPC endpoint. Set to 0 to disable.
+seen_blocks_heights = {{ .Storage.SeenBlocksHeights }}
+
+# Maximum number of seen blocks, and their maximum total size in bytes.
+# Set to 0 to disable the respective bound.
+seen_blocks_max_count = {{ .Storage.SeenBlocksMaxCount }}
+seen_blocks_max_bytes = {{ .Storage.SeenBlocksMaxBytes }}
+
+# Verify the integrity of the block store on startup, by checking that the
+# stored parts of each block match its part set hash, and that the block
+# matches its header hash. Corrupt heights are logged, so that operators can
+# decide whether to resync the node; the node starts regardless.
+# Options:
+#   1) "none" (default) - skip the verification
+#   2) "sampled" - check verify_block_store_samples heights spread evenly
+#      over the block store
+#   3) "full" - check every height, which can take a long time
+# The verification can also be run at any time via the unsafe
+# /unsafe_verify_block_store RPC endpoint.
+verify_block_store = "{{ .Storage.VerifyBlockStore }}"
+verify_block_store_samples = {{ .Storage.VerifyBlockStoreSamples }}
+
 #######################################################
 ###   Transaction Indexer Configuration Options     ###
 #######################################################
@@ -572,6 +797,15 @@ max_open_connections = {{ .Instrumentation.MaxOpenConnections }}
 # Instrumentation namespace
 namespace = "{{ .Instrumentation.Namespace }}"
 
+# Comma separated list of label=value pairs added to every metric, along with
+# the chain_id and node_id labels, e.g. "region=eu-west,operator=acme".
+global_labels = "{{ .Instrumentation.GlobalLabels }}"
+
+# When true, and prometheus is enabled, the latency of database operations,
+# the bytes read and written and the size on disk of every database are
+# reported, labelled by database (blockstore, state, tx_index, ...).
+db_metrics = {{ .Instrumentation.DBMetrics }}
+
 # TracePushConfig is the relative path of the push config.
 # This second config contains credentials for where and how often to
 # push trace data to. For example, if the config is next to this config,
@@ -582,7 +816,10 @@ trace_push_config = "{{ .Instrumentation.TracePushConfig }}"
 # event collection. If empty, the pull based server will not be started.
 trace_pull_address = "{{ .Instrumentation.TracePullAddress }}"
 
-# The tracer to use for collecting trace data.
+# The tracer to use for collecting trace data: "local", "file", "otlp",
+# "buffer" or "noop".
+# Several tracers may be combined as a comma separated list, for example
+# "local,file".
 trace_type = "{{ .Instrumentation.TraceType }}"
 
 # The size of the batches that are sent to the database.
@@ -593,6 +830,52 @@ trace_push_batch_size = {{ .Instrumentation.TraceBufferSize }}
 # comma separate string. For example: "consensus_round_state,mempool_tx".
 tracing_tables = "{{ .Instrumentation.TracingTables }}"
 
+# The fraction of the CheckTx calls written to the abci_call table, between 0
+# and 1. All the other calls to the application are written.
+trace_check_tx_sample_rate = {{ .Instrumentation.TraceCheckTxSampleRate }}
+
+# The fraction of the events written to some tables, for the high frequency
+# ones, as a comma separated list of table=rate pairs. The rate is a
+# probability or a 1/N ratio. For example: "mempool_tx=0.1,abci_call=1/100".
+# Other tables are not sampled. The rate is recorded in the rows of the
+# sampled tables.
+trace_sample_rates = "{{ .Instrumentation.TraceSampleRates }}"
+
+# The directory the "file" tracer writes each traced table to, as newline
+# delimited JSON, relative to the home directory if not absolute.
+trace_file_dir = "{{ js .Instrumentation.TraceFileDir }}"
+
+# The size, in bytes, at which the file of a table is rotated by the "file"
+# tracer, and the number of rotated files kept per table.
+trace_file_max_size = {{ .Instrumentation.TraceFileMaxSize }}
+trace_file_max_backups = {{ .Instrumentation.TraceFileMaxBackups }}
+
+# The address, host:port, of the OpenTelemetry collector the "otlp" tracer
+# exports each row of the traced tables to, as a log record over gRPC.
+trace_otlp_endpoint = "{{ .Instrumentation.TraceOTLPEndpoint }}"
+
+# When true, TLS is disabled when connecting to the OpenTelemetry collector.
+trace_otlp_insecure = {{ .Instrumentation.TraceOTLPInsecure }}
+
+# The interval at which the traffic of each peer, per channel, is written to
+# the peer_bandwidth table, if it is traced.
+trace_peer_bandwidth_interval = "{{ .Instrumentation.TracePeerBandwidthInterval }}"
+
+# The number of rows of each table kept in memory by the "buffer" tracer, and
+# the sizes of some tables overriding it, as a comma separated list of
+# table=size pairs. For example: "mempool_tx=100000".
+trace_dump_buffer_size = {{ .Instrumentation.TraceDumpBufferSize }}
+trace_dump_buffer_sizes = "{{ .Instrumentation.TraceDumpBufferSizes }}"
+
+# The address the "buffer" tracer serves the rows it keeps on, as newline
+# delimited JSON under /trace_dump?table=<table>&since=<unix milliseconds>.
+# If empty, the rows are not served.
+trace_dump_address = "{{ .Instrumentation.TraceDumpAddress }}"
+
+# The bearer token the requests to the trace dump endpoint must be authorized
+# with. It is required to serve the endpoint.
+trace_dump_token = "{{ .Instrumentation.TraceDumpToken }}"
+
 # The URL of the pyroscope instance to use for continuous profiling.
 # If empty, continuous profiling is disabled.
 pyroscope_url = "{{ .Instrumentation.PyroscopeURL }}"
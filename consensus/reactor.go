@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/tendermint/tendermint/libs/bits"
 	cmtevents "github.com/tendermint/tendermint/libs/events"
 	cmtjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/lifecycle"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/p2p"
@@ -36,6 +38,14 @@ const (
 	votesToContributeToBecomeGoodPeer  = 10000
 )
 
+var resyncCheckInterval = time.Second // not const so we can override with tests
+
+// fastSyncReactor is the fast sync reactor the consensus reactor hands over
+// to when falling far behind its peers.
+type fastSyncReactor interface {
+	SwitchToFastSync(sm.State) error
+}
+
 //-----------------------------------------------------------------------------
 
 // Reactor defines a reactor for the consensus service.
@@ -48,9 +58,17 @@ type Reactor struct {
 	waitSync bool
 	eventBus *types.EventBus
 	rs       *cstypes.RoundState
+	// height and round of the last proposal received from a peer
+	lastProposalHeight int64
+	lastProposalRound  int32
 
 	Metrics     *Metrics
 	traceClient trace.Tracer
+	lifecycle   *lifecycle.Bus
+
+	// re-entering fast sync, disabled if resyncThreshold is 0
+	resyncThreshold int64
+	resyncAfter     time.Duration
 }
 
 type ReactorOption func(*Reactor)
@@ -64,6 +82,7 @@ func NewReactor(consensusState *State, waitSync bool, options ...ReactorOption)
 		rs:          consensusState.GetRoundState(),
 		Metrics:     NopMetrics(),
 		traceClient: trace.NoOpTracer(),
+		lifecycle:   lifecycle.NewBus(),
 	}
 	conR.BaseReactor = *p2p.NewBaseReactor("Consensus", conR)
 
@@ -85,6 +104,10 @@ func (conR *Reactor) OnStart() error {
 	conR.subscribeToBroadcastEvents()
 	go conR.updateRoundStateRoutine()
 
+	if conR.resyncThreshold > 0 {
+		go conR.resyncRoutine()
+	}
+
 	if !conR.WaitSync() {
 		err := conR.conS.Start()
 		if err != nil {
@@ -129,8 +152,7 @@ func (conR *Reactor) SwitchToConsensus(state sm.State, skipWAL bool) {
 	conR.mtx.Lock()
 	conR.waitSync = false
 	conR.mtx.Unlock()
-	conR.Metrics.FastSyncing.Set(0)
-	conR.Metrics.StateSyncing.Set(0)
+	conR.lifecycle.SyncMode.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeConsensus})
 
 	if skipWAL {
 		conR.conS.doWALCatchup = false
@@ -147,6 +169,37 @@ conR:
 	}
 }
 
+// switchToFastSync stops the consensus state machine and hands over to the
+// fast sync reactor, which switches back to consensus through
+// SwitchToConsensus once caught up.
+func (conR *Reactor) switchToFastSync() error {
+	bcR, ok := conR.Switch.Reactor("BLOCKCHAIN").(fastSyncReactor)
+	if !ok {
+		return errors.New("no fast sync reactor to switch to")
+	}
+	conR.Logger.Info("SwitchToFastSync")
+
+	conR.mtx.Lock()
+	conR.waitSync = true
+	conR.mtx.Unlock()
+	conR.lifecycle.SyncMode.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeFastSync})
+
+	if err := conR.conS.Stop(); err != nil {
+		return err
+	}
+	conR.conS.Wait()
+	if err := conR.conS.Reset(); err != nil {
+		return err
+	}
+	state := conR.conS.GetState()
+	if err := bcR.SwitchToFastSync(state); err != nil {
+		conR.Logger.Error("Failed to switch to fast sync, resuming consensus", "err", err)
+		conR.SwitchToConsensus(state, false)
+		return err
+	}
+	return nil
+}
+
 // GetChannels implements Reactor
 func (conR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 	// TODO optimize
@@ -376,6 +429,16 @@ func (conR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 		switch msg := msg.(type) {
 		case *ProposalMessage:
 			ps.SetHasProposal(msg.Proposal)
+			if conR.traceClient.IsCollecting(schema.ReceivedProposalTable) && conR.firstProposal(msg.Proposal) {
+				schema.WriteReceivedProposal(
+					conR.traceClient,
+					msg.Proposal,
+					conR.conS.proposer(msg.Proposal.Height, msg.Proposal.Round),
+					string(e.Src.ID()),
+					m.(*cmtcons.Message).Size(),
+					cmttime.Now(),
+				)
+			}
 			conR.conS.peerMsgQueue <- msgInfo{msg, e.Src.ID()}
 			schema.WriteProposal(
 				conR.traceClient,
@@ -495,6 +558,19 @@ func (conR *Reactor) WaitSync() bool {
 	return conR.waitSync
 }
 
+// firstProposal reports whether proposal is the first one received from a
+// peer for its height and round.
+func (conR *Reactor) firstProposal(proposal *types.Proposal) bool {
+	conR.mtx.Lock()
+	defer conR.mtx.Unlock()
+	if proposal.Height < conR.lastProposalHeight ||
+		(proposal.Height == conR.lastProposalHeight && proposal.Round <= conR.lastProposalRound) {
+		return false
+	}
+	conR.lastProposalHeight, conR.lastProposalRound = proposal.Height, proposal.Round
+	return true
+}
+
 //--------------------------------------
 
 // subscribeToBroadcastEvents subscribes for new round steps and votes
@@ -1135,6 +1211,67 @@ OUTER_LOOP:
 	}
 }
 
+// resyncRoutine switches to fast sync when the height of the node lags the
+// median height of its peers by more than resyncThreshold for resyncAfter.
+// Fast sync only switches back to consensus once caught up, far below the
+// threshold, and the lag must then persist for resyncAfter again before
+// fast sync is re-entered, so the node doesn't flap between the two.
+func (conR *Reactor) resyncRoutine() {
+	ticker := time.NewTicker(resyncCheckInterval)
+	defer ticker.Stop()
+
+	var lagSince time.Time
+	for {
+		select {
+		case <-conR.Quit():
+			return
+		case now := <-ticker.C:
+			if conR.WaitSync() {
+				lagSince = time.Time{}
+				continue
+			}
+			height := conR.getRoundState().Height
+			peerHeight, ok := conR.medianPeerHeight()
+			if !ok || peerHeight-height <= conR.resyncThreshold {
+				lagSince = time.Time{}
+				continue
+			}
+			if lagSince.IsZero() {
+				lagSince = now
+			}
+			if now.Sub(lagSince) < conR.resyncAfter {
+				continue
+			}
+			lagSince = time.Time{}
+			conR.Logger.Info("Fell behind peers, re-entering fast sync",
+				"height", height, "peer_height", peerHeight)
+			if err := conR.switchToFastSync(); err != nil {
+				conR.Logger.Error("Failed to switch to fast sync", "err", err)
+			}
+		}
+	}
+}
+
+// medianPeerHeight returns the median of the heights peers reported. It
+// returns false if no peer reported its height yet.
+func (conR *Reactor) medianPeerHeight() (int64, bool) {
+	heights := make([]int64, 0, conR.Switch.Peers().Size())
+	for _, peer := range conR.Switch.Peers().List() {
+		ps, ok := peer.Get(types.PeerStateKey).(*PeerState)
+		if !ok {
+			continue
+		}
+		if height := ps.GetHeight(); height > 0 {
+			heights = append(heights, height)
+		}
+	}
+	if len(heights) == 0 {
+		return 0, false
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights[len(heights)/2], true
+}
+
 func (conR *Reactor) peerStatsRoutine() {
 	for {
 		if !conR.IsRunning() {
@@ -1207,6 +1344,21 @@ func ReactorTracing(traceClient trace.Tracer) ReactorOption {
 	return func(conR *Reactor) { conR.traceClient = traceClient }
 }
 
+// ReactorLifecycle sets the bus the sync mode changes are published on.
+func ReactorLifecycle(bus *lifecycle.Bus) ReactorOption {
+	return func(conR *Reactor) { conR.lifecycle = bus }
+}
+
+// ReactorResync makes the reactor switch to fast sync when the height of the
+// node lags the median height of its peers by more than threshold for the
+// given duration. A threshold of 0 disables it.
+func ReactorResync(threshold int64, after time.Duration) ReactorOption {
+	return func(conR *Reactor) {
+		conR.resyncThreshold = threshold
+		conR.resyncAfter = after
+	}
+}
+
 //-----------------------------------------------------------------------------
 
 var (
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	abcicli "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	abci "github.com/tendermint/tendermint/abci/types"
+	bcv0 "github.com/tendermint/tendermint/blockchain/v0"
 	cfg "github.com/tendermint/tendermint/config"
 	cstypes "github.com/tendermint/tendermint/consensus/types"
 	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
@@ -370,6 +372,29 @@ func TestReactorRecordsVotesAndBlockParts(t *testing.T) {
 	assert.Equal(t, true, ps.BlockPartsSent() > 0, "number of votes sent should have increased")
 }
 
+// Test only the first proposal received for a height and round is traced, with
+// the proposer known to the consensus state.
+func TestReactorFirstProposal(t *testing.T) {
+	cs, _ := randState(4)
+	conR := NewReactor(cs, false)
+
+	proposal := func(height int64, round int32) *types.Proposal {
+		return &types.Proposal{Height: height, Round: round}
+	}
+	assert.True(t, conR.firstProposal(proposal(1, 0)))
+	assert.False(t, conR.firstProposal(proposal(1, 0)))
+	assert.True(t, conR.firstProposal(proposal(1, 2)))
+	assert.False(t, conR.firstProposal(proposal(1, 1)))
+	assert.True(t, conR.firstProposal(proposal(2, 0)))
+	assert.False(t, conR.firstProposal(proposal(1, 3)))
+
+	validators := cs.Validators.Copy()
+	assert.Equal(t, validators.GetProposer().Address.Bytes(), cs.proposer(cs.Height, cs.Round))
+	validators.IncrementProposerPriority(2)
+	assert.Equal(t, validators.GetProposer().Address.Bytes(), cs.proposer(cs.Height, cs.Round+2))
+	assert.Nil(t, cs.proposer(cs.Height+1, 0))
+}
+
 //-------------------------------------------------------------
 // ensure we can make blocks despite cycling a validator set
 
@@ -1073,3 +1098,80 @@ func TestMarshalJSONPeerState(t *testing.T) {
 			"block_parts":"0"}
 		}`, string(data))
 }
+
+// resyncReactor counts the switches of the consensus reactor to fast sync.
+type resyncReactor struct {
+	*bcv0.BlockchainReactor
+	switches atomic.Int32
+}
+
+func (r *resyncReactor) SwitchToFastSync(state sm.State) error {
+	r.switches.Add(1)
+	return r.BlockchainReactor.SwitchToFastSync(state)
+}
+
+// Ensure a node which falls far behind its peers re-enters fast sync, and
+// switches back to consensus once caught up.
+func TestReactorResyncWhenFallingBehind(t *testing.T) {
+	defer func(interval time.Duration) { resyncCheckInterval = interval }(resyncCheckInterval)
+	resyncCheckInterval = 10 * time.Millisecond
+
+	N := 4
+	css, cleanup := randConsensusNet(N, "consensus_reactor_test", NewTimeoutTicker, newCounter)
+	defer cleanup()
+
+	reactors := make([]*Reactor, N)
+	bcReactors := make([]*resyncReactor, N)
+	eventBuses := make([]*types.EventBus, N)
+	for i := 0; i < N; i++ {
+		reactors[i] = NewReactor(css[i], true, ReactorResync(50, 100*time.Millisecond))
+		reactors[i].SetLogger(css[i].Logger)
+		eventBuses[i] = css[i].eventBus
+		reactors[i].SetEventBus(eventBuses[i])
+		require.NoError(t, css[i].blockExec.Store().Save(css[i].state))
+
+		blockStore, ok := css[i].blockStore.(*store.BlockStore)
+		require.True(t, ok)
+		bcReactors[i] = &resyncReactor{
+			BlockchainReactor: bcv0.NewBlockchainReactor(css[i].state.Copy(), css[i].blockExec, blockStore, false),
+		}
+		bcReactors[i].SetLogger(css[i].Logger.With("module", "blockchain"))
+	}
+	// the first node is halted by not connecting it to the others
+	switches := p2p.MakeConnectedSwitches(config.P2P, N, func(i int, s *p2p.Switch) *p2p.Switch {
+		s.AddReactor("CONSENSUS", reactors[i])
+		s.AddReactor("BLOCKCHAIN", bcReactors[i])
+		s.SetLogger(reactors[i].conS.Logger.With("module", "p2p"))
+		return s
+	}, func(switches []*p2p.Switch, i, j int) {
+		if i != 0 {
+			p2p.Connect2Switches(switches, i, j)
+		}
+	})
+	defer stopConsensusNet(log.TestingLogger(), reactors, eventBuses)
+	for i := 0; i < N; i++ {
+		reactors[i].SwitchToConsensus(reactors[i].conS.GetState(), false)
+	}
+
+	// the others advance the chain by 200 heights
+	require.Eventually(t, func() bool {
+		return css[1].GetLastHeight() >= 200
+	}, 2*time.Minute, 10*time.Millisecond)
+	require.EqualValues(t, 0, css[0].GetLastHeight())
+
+	// once reconnected, the first node is too far behind and fast syncs to
+	// the tip, then resumes consensus
+	for j := 1; j < N; j++ {
+		p2p.Connect2Switches(switches, 0, j)
+	}
+	require.Eventually(t, func() bool {
+		return bcReactors[0].switches.Load() > 0
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return !reactors[0].WaitSync() && css[0].GetLastHeight() > 200
+	}, time.Minute, 10*time.Millisecond)
+	assert.EqualValues(t, 1, bcReactors[0].switches.Load())
+	for i := 1; i < N; i++ {
+		assert.EqualValues(t, 0, bcReactors[i].switches.Load())
+	}
+}
@@ -1,6 +1,8 @@
 package consensus
 
 import (
+	"context"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/clist"
 	mempl "github.com/tendermint/tendermint/mempool"
@@ -23,6 +25,10 @@ func (emptyMempool) CheckTx(_ types.Tx, _ func(*abci.Response), _ mempl.TxInfo)
 	return nil
 }
 
+func (emptyMempool) CheckTxWithContext(_ context.Context, _ types.Tx, _ func(*abci.Response), _ mempl.TxInfo) error {
+	return nil
+}
+
 func (txmp emptyMempool) RemoveTxByKey(txKey types.TxKey) error {
 	return nil
 }
@@ -1222,6 +1222,22 @@ func (bs *mockBlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
 	}
 }
 func (bs *mockBlockStore) LoadBlockPart(height int64, index int) *types.Part { return nil }
+func (bs *mockBlockStore) LoadBlockRange(from, to int64) []types.BlockRangeResult {
+	var results []types.BlockRangeResult
+	for h := from; h <= to; h++ {
+		results = append(results, types.BlockRangeResult{
+			Height: h, BlockMeta: bs.LoadBlockMeta(h), Block: bs.LoadBlock(h),
+		})
+	}
+	return results
+}
+func (bs *mockBlockStore) LoadBlockMetaRange(from, to int64) []types.BlockRangeResult {
+	var results []types.BlockRangeResult
+	for h := from; h <= to; h++ {
+		results = append(results, types.BlockRangeResult{Height: h, BlockMeta: bs.LoadBlockMeta(h)})
+	}
+	return results
+}
 func (bs *mockBlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) {
 }
 func (bs *mockBlockStore) SaveTxInfo(block *types.Block, txResponseCodes []uint32, logs []string) error {
@@ -1248,6 +1264,15 @@ func (bs *mockBlockStore) PruneBlocks(height int64) (uint64, error) {
 	return pruned, nil
 }
 
+func (bs *mockBlockStore) TruncateBlocks(height int64) (uint64, error) {
+	truncated := uint64(len(bs.chain)) - uint64(height)
+	bs.chain = bs.chain[:height]
+	bs.commits = bs.commits[:height]
+	return truncated, nil
+}
+
+func (bs *mockBlockStore) SetRetainHeight(height int64) error { return nil }
+
 // ---------------------------------------
 // Test handshake/init chain
 
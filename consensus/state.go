@@ -17,6 +17,7 @@ import (
 	"github.com/tendermint/tendermint/crypto"
 	cmtevents "github.com/tendermint/tendermint/libs/events"
 	"github.com/tendermint/tendermint/libs/fail"
+	"github.com/tendermint/tendermint/libs/heartbeat"
 	cmtjson "github.com/tendermint/tendermint/libs/json"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtmath "github.com/tendermint/tendermint/libs/math"
@@ -28,6 +29,7 @@ import (
 	"github.com/tendermint/tendermint/pkg/trace/schema"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
 	cmttime "github.com/tendermint/tendermint/types/time"
 )
@@ -88,6 +90,9 @@ type State struct {
 	// store blocks and commits
 	blockStore sm.BlockStore
 
+	// optionally store all complete proposal blocks, for debugging
+	seenBlockStore *store.SeenBlockStore
+
 	// create and execute blocks
 	blockExec *sm.BlockExecutor
 
@@ -145,6 +150,12 @@ type State struct {
 	metrics *Metrics
 
 	traceClient trace.Tracer
+	// kind of event being handled by the receive routine, reported in the
+	// round step traces
+	stepTrigger schema.RoundStepTrigger
+
+	// beaten by the receive routine, to detect that it is stuck
+	heartbeat heartbeat.Heartbeat
 }
 
 // StateOption sets an optional parameter on the State.
@@ -217,6 +228,12 @@ func StateMetrics(metrics *Metrics) StateOption {
 	return func(cs *State) { cs.metrics = metrics }
 }
 
+// SetSeenBlockStore sets the store in which all complete proposal blocks are
+// persisted, whether or not they are committed.
+func SetSeenBlockStore(s *store.SeenBlockStore) StateOption {
+	return func(cs *State) { cs.seenBlockStore = s }
+}
+
 // SetTraceClient sets the remote event collector.
 func SetTraceClient(ec trace.Tracer) StateOption {
 	return func(cs *State) { cs.traceClient = ec }
@@ -228,6 +245,12 @@ func (cs *State) String() string {
 	return "ConsensusState"
 }
 
+// Heartbeat returns the heartbeat of the receive routine. It is stopped while
+// the routine isn't running, e.g. during fast sync.
+func (cs *State) Heartbeat() *heartbeat.Heartbeat {
+	return &cs.heartbeat
+}
+
 // GetState returns a copy of the chain state.
 func (cs *State) GetState() sm.State {
 	cs.mtx.RLock()
@@ -434,6 +457,25 @@ func (cs *State) OnStop() {
 	// WAL is stopped in receiveRoutine.
 }
 
+// OnReset implements service.Service. It allows the state to be started again
+// once stopped and waited for, when the node re-enters fast sync after
+// falling behind. The WAL is opened again on start.
+func (cs *State) OnReset() error {
+	if err := cs.timeoutTicker.Reset(); err != nil {
+		return err
+	}
+	if err := cs.evsw.Reset(); err != nil {
+		return err
+	}
+	cs.mtx.Lock()
+	// the block being committed, if any, is fetched by fast sync
+	cs.CommitRound = -1
+	cs.mtx.Unlock()
+	cs.wal = nilWAL{}
+	cs.done = make(chan struct{})
+	return nil
+}
+
 // Wait waits for the the main routine to return.
 // NOTE: be sure to Stop() the event switch and drain
 // any event channels or this may deadlock
@@ -540,12 +582,31 @@ func (cs *State) updateRoundStep(round int32, step cstypes.RoundStepType) {
 		if cs.Step != step {
 			cs.metrics.MarkStep(cs.Step)
 			schema.WriteRoundState(cs.traceClient, cs.Height, round, uint8(step))
+			if cs.traceClient.IsCollecting(schema.RoundStepTable) {
+				cs.traceRoundStep(round, step)
+			}
 		}
 	}
 	cs.Round = round
 	cs.Step = step
 }
 
+// traceRoundStep writes the round step trace for the transition to step.
+func (cs *State) traceRoundStep(round int32, step cstypes.RoundStepType) {
+	var prevotes, precommits int
+	if cs.Votes != nil && cs.Votes.Height() == cs.Height {
+		prevotes = len(cs.Votes.Prevotes(round).List())
+		precommits = len(cs.Votes.Precommits(round).List())
+	}
+	var proposer []byte
+	if cs.Validators != nil && cs.Validators.Size() > 0 {
+		proposer = cs.Validators.GetProposer().Address
+	}
+	isProposer := cs.privValidatorPubKey != nil && bytes.Equal(proposer, cs.privValidatorPubKey.Address())
+	schema.WriteRoundStep(cs.traceClient, cs.Height, round, step.String(), cmttime.Now(),
+		proposer, isProposer, prevotes, precommits, cs.stepTrigger)
+}
+
 // enterNewRound(height, 0) at cs.StartTime.
 func (cs *State) scheduleRound0(rs *cstypes.RoundState) {
 	// cs.Logger.Info("scheduleRound0", "now", cmttime.Now(), "startTime", cs.StartTime)
@@ -666,7 +727,6 @@ func (cs *State) updateToState(state sm.State) {
 
 	// RoundState fields
 	cs.updateHeight(height)
-	cs.updateRoundStep(0, cstypes.RoundStepNewHeight)
 
 	if cs.CommitTime.IsZero() {
 		// "Now" makes it easier to sync up dev nodes.
@@ -705,6 +765,8 @@ func (cs *State) updateToState(state sm.State) {
 	cs.CommitRound = -1
 	cs.LastValidators = state.LastValidators
 	cs.TriggeredTimeoutPrecommit = false
+	// after the validators and votes are reset, which the step traces report
+	cs.updateRoundStep(0, cstypes.RoundStepNewHeight)
 
 	cs.state = state
 
@@ -768,19 +830,27 @@ func (cs *State) receiveRoutine(maxSteps int) {
 		}
 	}()
 
+	// beat while idle, the receive routine blocking in the select below
+	heartbeatTicker := time.NewTicker(heartbeat.Interval)
+	defer heartbeatTicker.Stop()
+
 	for {
 		if maxSteps > 0 {
 			if cs.nSteps >= maxSteps {
 				cs.Logger.Debug("reached max steps; exiting receive routine")
 				cs.nSteps = 0
+				cs.heartbeat.Stop()
 				return
 			}
 		}
+		cs.heartbeat.Beat()
 
 		rs := cs.RoundState
 		var mi msgInfo
 
 		select {
+		case <-heartbeatTicker.C:
+
 		case <-cs.txNotifier.TxsAvailable():
 			cs.handleTxsAvailable()
 
@@ -825,6 +895,7 @@ func (cs *State) receiveRoutine(maxSteps int) {
 			cs.handleTimeout(ti, rs)
 
 		case <-cs.Quit():
+			cs.heartbeat.Stop()
 			onExit(cs)
 			return
 		}
@@ -835,6 +906,7 @@ func (cs *State) receiveRoutine(maxSteps int) {
 func (cs *State) handleMsg(mi msgInfo) {
 	cs.mtx.Lock()
 	defer cs.mtx.Unlock()
+	cs.stepTrigger = schema.TriggerMessage
 	var (
 		added bool
 		err   error
@@ -935,6 +1007,7 @@ func (cs *State) handleTimeout(ti timeoutInfo, rs cstypes.RoundState) {
 	// the timeout will now cause a state transition
 	cs.mtx.Lock()
 	defer cs.mtx.Unlock()
+	cs.stepTrigger = schema.TriggerTimeout
 
 	switch ti.Step {
 	case cstypes.RoundStepNewHeight:
@@ -975,6 +1048,7 @@ func (cs *State) handleTimeout(ti timeoutInfo, rs cstypes.RoundState) {
 func (cs *State) handleTxsAvailable() {
 	cs.mtx.Lock()
 	defer cs.mtx.Unlock()
+	cs.stepTrigger = schema.TriggerTxsAvailable
 
 	// We only need to do this for round 0.
 	if cs.Round != 0 {
@@ -1036,8 +1110,8 @@ func (cs *State) enterNewRound(height int64, round int32) {
 	// Setup new round
 	// we don't fire newStep for this step,
 	// but we fire an event, so update the round step first
-	cs.updateRoundStep(round, cstypes.RoundStepNewRound)
 	cs.Validators = validators
+	cs.updateRoundStep(round, cstypes.RoundStepNewRound)
 	propAddress := validators.GetProposer().PubKey.Address()
 	if round == 0 {
 		// We've already reset these upon new height,
@@ -1162,6 +1236,23 @@ func (cs *State) isProposer(address []byte) bool {
 	return bytes.Equal(cs.Validators.GetProposer().Address, address)
 }
 
+// proposer returns the address of the proposer of height and round, or nil if
+// it isn't known because the height isn't the current one or the round is a
+// past one.
+func (cs *State) proposer(height int64, round int32) []byte {
+	cs.mtx.RLock()
+	defer cs.mtx.RUnlock()
+	if cs.Height != height || round < cs.Round || cs.Validators == nil {
+		return nil
+	}
+	validators := cs.Validators
+	if round > cs.Round {
+		validators = validators.Copy()
+		validators.IncrementProposerPriority(cmtmath.SafeSubInt32(round, cs.Round))
+	}
+	return validators.GetProposer().Address
+}
+
 func (cs *State) defaultDecideProposal(height int64, round int32) {
 	var block *types.Block
 	var blockParts *types.PartSet
@@ -1259,7 +1350,8 @@ func (cs *State) createProposalBlock() (block *types.Block, blockParts *types.Pa
 
 	proposerAddr := cs.privValidatorPubKey.Address()
 
-	return cs.blockExec.CreateProposalBlock(cs.Height, cs.state, commit, proposerAddr)
+	return cs.blockExec.CreateProposalBlockWithTimeout(cs.Height, cs.state, commit, proposerAddr,
+		cs.config.ProposalDeadline(cs.Round))
 }
 
 // Enter: `timeoutPropose` after entering Propose.
@@ -1321,7 +1413,8 @@ func (cs *State) defaultDoPrevote(height int64, round int32) {
 
 	schema.WriteABCI(cs.traceClient, schema.ProcessProposalStart, height, round)
 
-	stateMachineValidBlock, err := cs.blockExec.ProcessProposal(cs.ProposalBlock)
+	stateMachineValidBlock, err := cs.blockExec.ProcessProposalWithTimeout(cs.ProposalBlock,
+		cs.config.ProposalDeadline(round))
 	if err != nil {
 		cs.Logger.Error("state machine returned an error when trying to process proposal block", "err", err)
 		return
@@ -1655,6 +1748,7 @@ func (cs *State) finalizeCommit(height int64) {
 	}
 
 	if err := cs.blockExec.ValidateBlock(cs.state, block); err != nil {
+		cs.blockExec.ReportAppHashMismatch(cs.state, block, err)
 		panic(fmt.Errorf("+2/3 committed an invalid block: %w", err))
 	}
 
@@ -1735,13 +1829,11 @@ func (cs *State) finalizeCommit(height int64) {
 
 	fail.Fail() // XXX
 
-	// Prune old heights, if requested by ABCI app.
+	// Record the retain height requested by the ABCI app. Old heights are
+	// pruned in the background so that large prunes don't stall the commit.
 	if retainHeight > 0 {
-		pruned, err := cs.pruneBlocks(retainHeight)
-		if err != nil {
-			logger.Error("failed to prune blocks", "retain_height", retainHeight, "err", err)
-		} else {
-			logger.Debug("pruned blocks", "pruned", pruned, "retain_height", retainHeight)
+		if err := cs.blockStore.SetRetainHeight(retainHeight); err != nil {
+			logger.Error("failed to set retain height", "retain_height", retainHeight, "err", err)
 		}
 	}
 
@@ -1768,22 +1860,6 @@ func (cs *State) finalizeCommit(height int64) {
 	// * cs.StartTime is set to when we will start round0.
 }
 
-func (cs *State) pruneBlocks(retainHeight int64) (uint64, error) {
-	base := cs.blockStore.Base()
-	if retainHeight <= base {
-		return 0, nil
-	}
-	pruned, err := cs.blockStore.PruneBlocks(retainHeight)
-	if err != nil {
-		return 0, fmt.Errorf("failed to prune block store: %w", err)
-	}
-	err = cs.blockExec.Store().PruneStates(base, retainHeight)
-	if err != nil {
-		return 0, fmt.Errorf("failed to prune state database: %w", err)
-	}
-	return pruned, nil
-}
-
 func (cs *State) recordMetrics(height int64, block *types.Block) {
 	cs.metrics.Validators.Set(float64(cs.Validators.Size()))
 	cs.metrics.ValidatorsPower.Set(float64(cs.Validators.TotalVotingPower()))
@@ -1994,6 +2070,12 @@ func (cs *State) addProposalBlockPart(msg *BlockPartMessage, peerID p2p.ID) (add
 		// NOTE: it's possible to receive complete proposal blocks for future rounds without having the proposal
 		cs.Logger.Info("received complete proposal block", "height", cs.ProposalBlock.Height, "hash", cs.ProposalBlock.Hash())
 
+		if cs.seenBlockStore != nil {
+			if err := cs.seenBlockStore.SaveBlock(block); err != nil {
+				cs.Logger.Error("failed to save seen block", "height", block.Height, "err", err)
+			}
+		}
+
 		if err := cs.eventBus.PublishEventCompleteProposal(cs.CompleteProposalEvent()); err != nil {
 			cs.Logger.Error("failed publishing event complete proposal", "err", err)
 		}
@@ -2341,6 +2423,13 @@ func (cs *State) signAddVote(msgType cmtproto.SignedMsgType, hash []byte, header
 
 	// TODO: pass pubKey to signVote
 	vote, err := cs.signVote(msgType, hash, header)
+	if errors.Is(err, types.ErrConnectionDropped) {
+		// Retrying is safe: the signer either didn't get the request, or
+		// refuses to sign anything conflicting with what it signed. After a
+		// sign timeout though, the signer is likely still busy, so we don't.
+		cs.Logger.Info("lost connection to the signer; retrying to sign vote", "height", cs.Height, "round", cs.Round, "err", err)
+		vote, err = cs.signVote(msgType, hash, header)
+	}
 	if err == nil {
 		cs.sendInternalMessage(msgInfo{&VoteMessage{vote}, ""})
 		cs.Logger.Debug("signed and pushed vote", "height", cs.Height, "round", cs.Round, "vote", vote)
@@ -2359,6 +2448,23 @@ func (cs *State) updatePrivValidatorPubKey() error {
 		return nil
 	}
 
+	// A validator rotating its key signs with whichever of its keys the current
+	// validator set expects.
+	if rpv, ok := cs.privValidator.(types.KeyRotatingPrivValidator); ok && cs.Validators != nil {
+		pubKeys, err := rpv.GetPubKeys()
+		if err != nil {
+			return err
+		}
+		for _, pk := range pubKeys {
+			if cs.Validators.HasAddress(pk.Address()) {
+				if err := rpv.SelectKey(pk.Address()); err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+
 	pubKey, err := cs.privValidator.GetPubKey()
 	if err != nil {
 		return err
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,11 +15,16 @@ import (
 
 	"github.com/tendermint/tendermint/abci/example/counter"
 	cstypes "github.com/tendermint/tendermint/consensus/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/heartbeat"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
 	p2pmock "github.com/tendermint/tendermint/p2p/mock"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
+	"github.com/tendermint/tendermint/privval"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
 )
@@ -213,6 +220,7 @@ func TestStateBadProposal(t *testing.T) {
 	}
 	stateHash[0] = (stateHash[0] + 1) % 255
 	propBlock.AppHash = stateHash
+	propBlock.InvalidateHashes()
 	propBlockParts := propBlock.MakePartSet(partSize)
 	blockID := types.BlockID{Hash: propBlock.Hash(), PartSetHeader: propBlockParts.Header()}
 	proposal := types.NewProposal(vs2.Height, round, -1, blockID)
@@ -383,6 +391,83 @@ func TestStateFullRound1(t *testing.T) {
 	validateLastPrecommit(t, cs, vss[0], propBlockHash)
 }
 
+// roundStepTracer keeps the round step traces written to it in memory.
+type roundStepTracer struct {
+	mtx   sync.Mutex
+	steps []schema.RoundStep
+}
+
+func (r *roundStepTracer) Write(e trace.Entry) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if step, ok := e.(schema.RoundStep); ok {
+		r.steps = append(r.steps, step)
+	}
+}
+
+func (r *roundStepTracer) IsCollecting(table string) bool { return table == schema.RoundStepTable }
+func (r *roundStepTracer) Stop()                          {}
+
+func (r *roundStepTracer) height(height int64) []schema.RoundStep {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	var steps []schema.RoundStep
+	for _, step := range r.steps {
+		if step.Height == height {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+func TestStateRoundStepTraces(t *testing.T) {
+	cs, vss := randState(1)
+	tracer := &roundStepTracer{}
+	cs.traceClient = tracer
+	// wait for the commit timeout, so that new rounds are entered on timeouts
+	csConfig := *cs.config
+	csConfig.SkipTimeoutCommit = false
+	cs.config = &csConfig
+	height, round := cs.Height, cs.Round
+
+	newBlockCh := subscribe(cs.eventBus, types.EventQueryNewBlock)
+	startTestRound(cs, height, round)
+	for h := height; h <= height+2; h++ {
+		ensureNewBlock(newBlockCh, h)
+	}
+
+	pubKey, err := vss[0].GetPubKey()
+	require.NoError(t, err)
+	proposer := pubKey.Address().String()
+
+	// the first height was started without a timeout, check the next ones
+	for h := height + 1; h <= height+2; h++ {
+		steps := tracer.height(h)
+		// one row per step of the single round, about a dozen at most
+		require.GreaterOrEqual(t, len(steps), 6, "height %d", h)
+		require.LessOrEqual(t, len(steps), 12, "height %d", h)
+
+		byStep := make(map[string]schema.RoundStep)
+		for _, step := range steps {
+			assert.Zero(t, step.Round)
+			assert.Equal(t, proposer, step.Proposer)
+			assert.True(t, step.IsProposer)
+			assert.NotZero(t, step.UnixMillisecondTimestamp)
+			byStep[step.Step] = step
+		}
+		assert.Equal(t, string(schema.TriggerMessage), byStep["RoundStepNewHeight"].Trigger)
+		assert.Equal(t, string(schema.TriggerTimeout), byStep["RoundStepNewRound"].Trigger)
+		precommit := byStep["RoundStepPrecommit"]
+		assert.Equal(t, string(schema.TriggerMessage), precommit.Trigger)
+		assert.Equal(t, 1, precommit.Prevotes)
+		assert.Equal(t, 0, precommit.Precommits)
+		commit := byStep["RoundStepCommit"]
+		assert.Equal(t, string(schema.TriggerMessage), commit.Trigger)
+		assert.Equal(t, 1, commit.Prevotes)
+		assert.Equal(t, 1, commit.Precommits)
+	}
+}
+
 // nil is proposed, so prevote and precommit nil
 func TestStateFullRoundNil(t *testing.T) {
 	cs, vss := randState(1)
@@ -1940,6 +2025,37 @@ func TestSignSameVoteTwice(t *testing.T) {
 	require.Equal(t, vote, vote2)
 }
 
+func TestStatePrivValidatorKeyRotation(t *testing.T) {
+	cs, _ := randState(1)
+	dir := t.TempDir()
+
+	pv := privval.GenFilePV(filepath.Join(dir, "priv_validator_key.json"), filepath.Join(dir, "priv_validator_state.json"))
+	pv.Key.NextPrivKey = ed25519.GenPrivKey()
+	pv.Key.NextPubKey = pv.Key.NextPrivKey.PubKey()
+	oldKey, newKey := pv.Key.PubKey, pv.Key.NextPubKey
+
+	// the validator set expects the old key until the rotation height
+	cs.Validators = types.NewValidatorSet([]*types.Validator{types.NewValidator(oldKey, 10)})
+	cs.SetPrivValidator(pv)
+	require.Equal(t, oldKey, cs.privValidatorPubKey)
+
+	vote, err := cs.signVote(cmtproto.PrecommitType, nil, types.PartSetHeader{})
+	require.NoError(t, err)
+	assert.Equal(t, oldKey.Address(), vote.ValidatorAddress)
+	assert.True(t, oldKey.VerifySignature(types.VoteSignBytes(cs.state.ChainID, vote.ToProto()), vote.Signature))
+
+	// from the next height on it expects the new key
+	cs.Height++
+	cs.Validators = types.NewValidatorSet([]*types.Validator{types.NewValidator(newKey, 10)})
+	require.NoError(t, cs.updatePrivValidatorPubKey())
+	require.Equal(t, newKey, cs.privValidatorPubKey)
+
+	vote, err = cs.signVote(cmtproto.PrevoteType, nil, types.PartSetHeader{})
+	require.NoError(t, err)
+	assert.Equal(t, newKey.Address(), vote.ValidatorAddress)
+	assert.True(t, newKey.VerifySignature(types.VoteSignBytes(cs.state.ChainID, vote.ToProto()), vote.Signature))
+}
+
 // subscribe subscribes test client to the given query and returns a channel with cap = 1.
 func subscribe(eventBus *types.EventBus, q cmtpubsub.Query) <-chan cmtpubsub.Message {
 	sub, err := eventBus.Subscribe(context.Background(), testSubscriber, q)
@@ -1985,3 +2101,37 @@ func findBlockSizeLimit(t *testing.T, height, maxBytes int64, cs *State, partSiz
 	require.Fail(t, "We shouldn't hit the end of the loop")
 	return nil, nil
 }
+
+// TestStateHeartbeat checks that the receive routine beats while it runs,
+// stops beating while it is stuck, and stops its heartbeat when it exits.
+func TestStateHeartbeat(t *testing.T) {
+	cs1, _ := randState(1)
+	_, running := cs1.Heartbeat().Last()
+	assert.False(t, running)
+
+	require.NoError(t, cs1.Start())
+	require.Eventually(t, func() bool {
+		_, running := cs1.Heartbeat().Last()
+		return running
+	}, time.Second, 10*time.Millisecond)
+
+	// the receive routine gets stuck on the lock at the next message or
+	// timeout it handles
+	cs1.mtx.Lock()
+	stuck := time.Now()
+	time.Sleep(3 * heartbeat.Interval)
+	last, running := cs1.Heartbeat().Last()
+	assert.True(t, running)
+	assert.Less(t, last.Sub(stuck), heartbeat.Interval, "the receive routine beat while stuck")
+	cs1.mtx.Unlock()
+
+	require.Eventually(t, func() bool {
+		last, _ := cs1.Heartbeat().Last()
+		return time.Since(last) < heartbeat.Interval
+	}, 3*heartbeat.Interval, 10*time.Millisecond)
+
+	require.NoError(t, cs1.Stop())
+	cs1.Wait()
+	_, running = cs1.Heartbeat().Last()
+	assert.False(t, running)
+}
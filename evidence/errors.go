@@ -0,0 +1,46 @@
+package evidence
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrEvidenceExpired is returned when evidence is older than both the
+// maximum age in blocks and the maximum age duration of the evidence
+// consensus parameters.
+type ErrEvidenceExpired struct {
+	Height int64
+	Time   time.Time
+	// MinHeight and MinTime are the height and time of the oldest evidence
+	// which isn't expired.
+	MinHeight int64
+	MinTime   time.Time
+}
+
+func (e ErrEvidenceExpired) Error() string {
+	return fmt.Sprintf(
+		"evidence from height %d (created at: %v) is too old; min height is %d and evidence can not be older than %v",
+		e.Height, e.Time, e.MinHeight, e.MinTime,
+	)
+}
+
+// ErrUnknownHeight is returned when the block at the height of evidence isn't
+// in the block store: it's ahead of the chain, or was pruned.
+type ErrUnknownHeight struct {
+	Height int64
+}
+
+func (e ErrUnknownHeight) Error() string {
+	return fmt.Sprintf("don't have header #%d", e.Height)
+}
+
+// ErrUnknownValidator is returned when the validator which signed the votes
+// of duplicate vote evidence wasn't a validator at their height.
+type ErrUnknownValidator struct {
+	Address []byte
+	Height  int64
+}
+
+func (e ErrUnknownValidator) Error() string {
+	return fmt.Sprintf("address %X was not a validator at height %d", e.Address, e.Height)
+}
@@ -0,0 +1,149 @@
+package evidence
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "evidence"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of evidence sent to peers.
+	SentEvidence metrics.Counter
+	// Number of evidence list messages sent to peers.
+	SentMessages metrics.Counter
+	// Number of evidence not sent to peers again, since they already have it.
+	SuppressedDuplicates metrics.Counter
+	// Number of evidence received from peers, by result: accepted, rejected
+	// or rate_limited.
+	ReceivedEvidence metrics.Counter
+
+	// Number of pending evidence.
+	PendingEvidence metrics.Gauge
+	// Size of the pending evidence, in bytes.
+	PendingEvidenceBytes metrics.Gauge
+	// Age of the oldest pending evidence, in heights.
+	OldestPendingEvidenceHeights metrics.Gauge
+	// Age of the oldest pending evidence, in seconds.
+	OldestPendingEvidenceSeconds metrics.Gauge
+	// Number of evidence committed per block.
+	BlockEvidence metrics.Histogram
+	// Number of evidence added to the pending pool, by type.
+	AddedEvidence metrics.Counter
+	// Number of evidence committed, by type.
+	CommittedEvidence metrics.Counter
+	// Number of pending evidence which expired without being committed, by
+	// type.
+	ExpiredEvidence metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		SentEvidence: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sent_evidence",
+			Help:      "Number of evidence sent to peers.",
+		}, labels).With(labelsAndValues...),
+		SentMessages: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sent_messages",
+			Help:      "Number of evidence list messages sent to peers.",
+		}, labels).With(labelsAndValues...),
+		SuppressedDuplicates: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "suppressed_duplicates",
+			Help:      "Number of evidence not sent to peers again, since they already have it.",
+		}, labels).With(labelsAndValues...),
+		ReceivedEvidence: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "received_evidence",
+			Help:      "Number of evidence received from peers, by result: accepted, rejected or rate_limited.",
+		}, append(labels, "result")).With(labelsAndValues...),
+		PendingEvidence: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pending_evidence",
+			Help:      "Number of pending evidence.",
+		}, labels).With(labelsAndValues...),
+		PendingEvidenceBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pending_evidence_bytes",
+			Help:      "Size of the pending evidence, in bytes.",
+		}, labels).With(labelsAndValues...),
+		OldestPendingEvidenceHeights: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "oldest_pending_evidence_heights",
+			Help:      "Age of the oldest pending evidence, in heights.",
+		}, labels).With(labelsAndValues...),
+		OldestPendingEvidenceSeconds: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "oldest_pending_evidence_seconds",
+			Help:      "Age of the oldest pending evidence, in seconds.",
+		}, labels).With(labelsAndValues...),
+		BlockEvidence: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "block_evidence",
+			Help:      "Number of evidence committed per block.",
+			Buckets:   stdprometheus.LinearBuckets(0, 1, 10),
+		}, labels).With(labelsAndValues...),
+		AddedEvidence: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "added_evidence",
+			Help:      "Number of evidence added to the pending pool, by type.",
+		}, append(labels, "type")).With(labelsAndValues...),
+		CommittedEvidence: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "committed_evidence",
+			Help:      "Number of evidence committed, by type.",
+		}, append(labels, "type")).With(labelsAndValues...),
+		ExpiredEvidence: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "expired_evidence",
+			Help:      "Number of pending evidence which expired without being committed, by type.",
+		}, append(labels, "type")).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		SentEvidence:         discard.NewCounter(),
+		SentMessages:         discard.NewCounter(),
+		SuppressedDuplicates: discard.NewCounter(),
+		ReceivedEvidence:     discard.NewCounter(),
+
+		PendingEvidence:              discard.NewGauge(),
+		PendingEvidenceBytes:         discard.NewGauge(),
+		OldestPendingEvidenceHeights: discard.NewGauge(),
+		OldestPendingEvidenceSeconds: discard.NewGauge(),
+		BlockEvidence:                discard.NewHistogram(),
+		AddedEvidence:                discard.NewCounter(),
+		CommittedEvidence:            discard.NewCounter(),
+		ExpiredEvidence:              discard.NewCounter(),
+	}
+}
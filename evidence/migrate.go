@@ -0,0 +1,110 @@
+package evidence
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// The prefixes of the keys of the legacy evidence database, followed by the
+// height of the evidence in padded hex, a slash and its hash in hex. The
+// values of the committed evidence were their height.
+const (
+	legacyKeyCommitted = byte(0x00)
+	legacyKeyPending   = byte(0x01)
+)
+
+// migrateLegacyKeys moves the pending and committed evidence of the legacy
+// layout to the current one. The time of the committed evidence, which the
+// legacy layout doesn't record, is the time of the block at its height, or
+// the time of the last block if it was pruned, which delays its expiry. The
+// height of the block which committed it isn't known either.
+func (evpool *Pool) migrateLegacyKeys() error {
+	batch := evpool.evidenceStore.NewBatch()
+	defer batch.Close()
+
+	migrated := 0
+	for _, prefix := range []byte{legacyKeyPending, legacyKeyCommitted} {
+		keys, values, err := evpool.legacyEntries(prefix)
+		if err != nil {
+			return err
+		}
+
+		for i, key := range keys {
+			height, hash, err := parseLegacyKey(key)
+			if err != nil {
+				return err
+			}
+
+			newPrefix, value := baseKeyPending, values[i]
+			if prefix == legacyKeyCommitted {
+				newPrefix = baseKeyCommitted
+				if value, err = committedValue(0, evpool.blockTime(height)); err != nil {
+					return err
+				}
+			}
+
+			newKey := append([]byte{newPrefix}, keySuffixOf(height, hash)...)
+			if err := batch.Set(newKey, value); err != nil {
+				return err
+			}
+			if err := batch.Delete(key); err != nil {
+				return err
+			}
+		}
+		migrated += len(keys)
+	}
+	if migrated == 0 {
+		return nil
+	}
+
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+	evpool.logger.Info("Migrated evidence database", "evidence", migrated)
+	return nil
+}
+
+// legacyEntries returns the keys and values with the given legacy prefix.
+func (evpool *Pool) legacyEntries(prefix byte) (keys, values [][]byte, err error) {
+	iter, err := dbm.IteratePrefix(evpool.evidenceStore, []byte{prefix})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		keys = append(keys, append([]byte(nil), iter.Key()...))
+		values = append(values, append([]byte(nil), iter.Value()...))
+	}
+	return keys, values, iter.Error()
+}
+
+// blockTime returns the time of the block at the given height, or the time of
+// the last block if it isn't in the block store.
+func (evpool *Pool) blockTime(height int64) time.Time {
+	if meta := evpool.blockStore.LoadBlockMeta(height); meta != nil {
+		return meta.Header.Time
+	}
+	return evpool.State().LastBlockTime
+}
+
+// parseLegacyKey returns the height and hash of the evidence of a legacy key.
+func parseLegacyKey(key []byte) (int64, []byte, error) {
+	parts := bytes.SplitN(key[1:], []byte("/"), 2)
+	if len(parts) != 2 {
+		return 0, nil, fmt.Errorf("invalid legacy evidence key %X", key)
+	}
+	height, err := strconv.ParseInt(string(parts[0]), 16, 64)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid height in legacy evidence key %X: %w", key, err)
+	}
+	hash, err := hex.DecodeString(string(parts[1]))
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid hash in legacy evidence key %X: %w", key, err)
+	}
+	return height, hash, nil
+}
@@ -2,6 +2,7 @@ package evidence
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"sync"
@@ -9,10 +10,10 @@ import (
 	"time"
 
 	dbm "github.com/cometbft/cometbft-db"
-	"github.com/gogo/protobuf/proto"
 	gogotypes "github.com/gogo/protobuf/types"
 
 	clist "github.com/tendermint/tendermint/libs/clist"
+	cmtdb "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	sm "github.com/tendermint/tendermint/state"
@@ -20,10 +21,27 @@ import (
 )
 
 const (
-	baseKeyCommitted = byte(0x00)
-	baseKeyPending   = byte(0x01)
+	// The keys of the evidence are a prefix, followed by the height of the
+	// evidence in big endian and its hash, so that the evidence of each prefix
+	// is ordered by height. The values of the committed evidence are the height
+	// of the block which committed it in big endian, or 0 if unknown, followed
+	// by the time of the evidence; the ones of the pending evidence are the
+	// evidence.
+	baseKeyPending   = byte(0x02)
+	baseKeyCommitted = byte(0x03)
+
+	// compactionThreshold is the number of committed evidence pruned at once
+	// above which the evidence database is compacted.
+	compactionThreshold = 1000
 )
 
+// compacter is implemented by the evidence databases which can compact a key
+// range, reclaiming the disk space of the pruned evidence. See
+// cmtdb.Compacter.
+type compacter interface {
+	Compact(start, end []byte) error
+}
+
 // Pool maintains a pool of valid evidence to be broadcasted and committed
 type Pool struct {
 	logger log.Logger
@@ -47,11 +65,21 @@ type Pool struct {
 
 	pruningHeight int64
 	pruningTime   time.Time
+
+	metrics *Metrics
+}
+
+// PoolOption sets an optional parameter on the Pool.
+type PoolOption func(*Pool)
+
+// WithPoolMetrics sets the metrics of the pool.
+func WithPoolMetrics(metrics *Metrics) PoolOption {
+	return func(evpool *Pool) { evpool.metrics = metrics }
 }
 
 // NewPool creates an evidence pool. If using an existing evidence store,
 // it will add all pending evidence to the concurrent list.
-func NewPool(evidenceDB dbm.DB, stateDB sm.Store, blockStore BlockStore) (*Pool, error) {
+func NewPool(evidenceDB dbm.DB, stateDB sm.Store, blockStore BlockStore, options ...PoolOption) (*Pool, error) {
 
 	state, err := stateDB.Load()
 	if err != nil {
@@ -66,6 +94,14 @@ func NewPool(evidenceDB dbm.DB, stateDB sm.Store, blockStore BlockStore) (*Pool,
 		evidenceStore:   evidenceDB,
 		evidenceList:    clist.New(),
 		consensusBuffer: make([]duplicateVoteSet, 0),
+		metrics:         NopMetrics(),
+	}
+	for _, option := range options {
+		option(pool)
+	}
+
+	if err := pool.migrateLegacyKeys(); err != nil {
+		return nil, fmt.Errorf("cannot migrate evidence database: %w", err)
 	}
 
 	// if pending evidence already in db, in event of prior failure, then check for expiration,
@@ -80,6 +116,7 @@ func NewPool(evidenceDB dbm.DB, stateDB sm.Store, blockStore BlockStore) (*Pool,
 	for _, ev := range evList {
 		pool.evidenceList.PushBack(ev)
 	}
+	pool.updatePendingMetrics()
 
 	return pool, nil
 }
@@ -103,6 +140,8 @@ func (evpool *Pool) PendingEvidence(maxBytes int64) ([]types.Evidence, int64) {
 //  2. Update the pool's state which contains evidence params relating to expiry.
 //  3. Moves pending evidence that has now been committed into the committed pool.
 //  4. Removes any expired evidence based on both height and time.
+//  5. Removes the committed evidence which expired, only needed to reject
+//     duplicate evidence until it expires.
 func (evpool *Pool) Update(state sm.State, ev types.EvidenceList) {
 	// sanity check
 	if state.LastBlockHeight <= evpool.state.LastBlockHeight {
@@ -122,13 +161,17 @@ func (evpool *Pool) Update(state sm.State, ev types.EvidenceList) {
 	evpool.updateState(state)
 
 	// move committed evidence out from the pending pool and into the committed pool
-	evpool.markEvidenceAsCommitted(ev)
+	evpool.markEvidenceAsCommitted(ev, state.LastBlockHeight)
+	evpool.metrics.BlockEvidence.Observe(float64(len(ev)))
 
 	// prune pending evidence when it has expired. This also updates when the next evidence will expire
 	if evpool.Size() > 0 && state.LastBlockHeight > evpool.pruningHeight &&
 		state.LastBlockTime.After(evpool.pruningTime) {
 		evpool.pruningHeight, evpool.pruningTime = evpool.removeExpiredPendingEvidence()
 	}
+	evpool.pruneCommittedEvidence()
+
+	evpool.updatePendingMetrics()
 }
 
 // AddEvidence checks the evidence is valid and adds it to the pool.
@@ -164,6 +207,8 @@ func (evpool *Pool) AddEvidence(ev types.Evidence) error {
 	evpool.evidenceList.PushBack(ev)
 
 	evpool.logger.Info("Verified new evidence of byzantine behavior", "evidence", ev)
+	evpool.metrics.AddedEvidence.With("type", evidenceType(ev)).Add(1)
+	evpool.updatePendingMetrics()
 
 	return nil
 }
@@ -191,6 +236,13 @@ func (evpool *Pool) ReportConflictingVotes(voteA, voteB *types.Vote) {
 // evidence has already been committed or is being proposed twice. It also adds any
 // evidence that it doesn't currently have so that it can quickly form ABCI Evidence later.
 func (evpool *Pool) CheckEvidence(evList types.EvidenceList) error {
+	added := false
+	defer func() {
+		if added {
+			evpool.updatePendingMetrics()
+		}
+	}()
+
 	hashes := make([][]byte, len(evList))
 	for idx, ev := range evList {
 
@@ -213,6 +265,9 @@ func (evpool *Pool) CheckEvidence(evList types.EvidenceList) error {
 				// Something went wrong with adding the evidence but we already know it is valid
 				// hence we log an error and continue
 				evpool.logger.Error("Can't add evidence to pending list", "err", err, "ev", ev)
+			} else {
+				evpool.metrics.AddedEvidence.With("type", evidenceType(ev)).Add(1)
+				added = true
 			}
 
 			evpool.logger.Info("Check evidence: verified evidence of byzantine behavior", "evidence", ev)
@@ -324,9 +379,9 @@ func (evpool *Pool) removePendingEvidence(evidence types.Evidence) {
 	}
 }
 
-// markEvidenceAsCommitted processes all the evidence in the block, marking it as
-// committed and removing it from the pending database.
-func (evpool *Pool) markEvidenceAsCommitted(evidence types.EvidenceList) {
+// markEvidenceAsCommitted processes all the evidence in the block at the given
+// height, marking it as committed and removing it from the pending database.
+func (evpool *Pool) markEvidenceAsCommitted(evidence types.EvidenceList, height int64) {
 	blockEvidenceMap := make(map[string]struct{}, len(evidence))
 	for _, ev := range evidence {
 		if evpool.isPending(ev) {
@@ -335,11 +390,10 @@ func (evpool *Pool) markEvidenceAsCommitted(evidence types.EvidenceList) {
 		}
 
 		// Add evidence to the committed list. As the evidence is stored in the block store
-		// we only need to record the height that it was saved at.
+		// we only need to record where, and its time to know when it expires.
 		key := keyCommitted(ev)
 
-		h := gogotypes.Int64Value{Value: ev.Height()}
-		evBytes, err := proto.Marshal(&h)
+		evBytes, err := committedValue(height, ev.Time())
 		if err != nil {
 			evpool.logger.Error("failed to marshal committed evidence", "err", err, "key(height/hash)", key)
 			continue
@@ -348,6 +402,7 @@ func (evpool *Pool) markEvidenceAsCommitted(evidence types.EvidenceList) {
 		if err := evpool.evidenceStore.Set(key, evBytes); err != nil {
 			evpool.logger.Error("Unable to save committed evidence", "err", err, "key(height/hash)", key)
 		}
+		evpool.metrics.CommittedEvidence.With("type", evidenceType(ev)).Add(1)
 	}
 
 	// remove committed evidence from the clist
@@ -424,7 +479,9 @@ func (evpool *Pool) removeExpiredPendingEvidence() (int64, time.Time) {
 			return ev.Height() + evpool.State().ConsensusParams.Evidence.MaxAgeNumBlocks + 1,
 				ev.Time().Add(evpool.State().ConsensusParams.Evidence.MaxAgeDuration).Add(time.Second)
 		}
+		evpool.logger.Info("Pending evidence expired without being committed", "evidence", ev)
 		evpool.removePendingEvidence(ev)
+		evpool.metrics.ExpiredEvidence.With("type", evidenceType(ev)).Add(1)
 		blockEvidenceMap[evMapKey(ev)] = struct{}{}
 	}
 	// We either have no pending evidence or all evidence has expired
@@ -434,6 +491,64 @@ func (evpool *Pool) removeExpiredPendingEvidence() (int64, time.Time) {
 	return evpool.State().LastBlockHeight, evpool.State().LastBlockTime
 }
 
+// pruneCommittedEvidence removes the committed evidence which expired, oldest
+// first. As expired evidence is rejected anyway, the committed evidence is only
+// needed to reject duplicate evidence until it expires. The evidence database
+// is compacted after large prunes.
+func (evpool *Pool) pruneCommittedEvidence() {
+	iter, err := dbm.IteratePrefix(evpool.evidenceStore, []byte{baseKeyCommitted})
+	if err != nil {
+		evpool.logger.Error("Unable to iterate over committed evidence", "err", err)
+		return
+	}
+	var keys [][]byte
+	for ; iter.Valid(); iter.Next() {
+		height, _, err := parseKey(iter.Key())
+		if err != nil {
+			evpool.logger.Error("Invalid committed evidence key", "key", iter.Key(), "err", err)
+			continue
+		}
+		_, evTime, err := parseCommittedValue(iter.Value())
+		if err != nil {
+			evpool.logger.Error("Invalid committed evidence time", "key", iter.Key(), "err", err)
+			continue
+		}
+		// the next evidence is at least as recent
+		if !evpool.isExpired(height, evTime) {
+			break
+		}
+		keys = append(keys, append([]byte(nil), iter.Key()...))
+	}
+	if err := iter.Error(); err != nil {
+		evpool.logger.Error("Unable to iterate over committed evidence", "err", err)
+	}
+	iter.Close()
+	if len(keys) == 0 {
+		return
+	}
+
+	batch := evpool.evidenceStore.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			evpool.logger.Error("Unable to delete committed evidence", "err", err)
+			return
+		}
+	}
+	if err := batch.Write(); err != nil {
+		evpool.logger.Error("Unable to delete committed evidence", "err", err)
+		return
+	}
+	evpool.logger.Debug("Pruned committed evidence", "count", len(keys))
+
+	if db, ok := evpool.evidenceStore.(compacter); ok && len(keys) >= compactionThreshold {
+		start, end := keys[0], append(keys[len(keys)-1], 0)
+		if err := db.Compact(start, end); err != nil && !errors.Is(err, cmtdb.ErrCompactionNotSupported) {
+			evpool.logger.Error("Unable to compact evidence database", "err", err)
+		}
+	}
+}
+
 func (evpool *Pool) removeEvidenceFromList(
 	blockEvidenceMap map[string]struct{}) {
 
@@ -447,6 +562,35 @@ func (evpool *Pool) removeEvidenceFromList(
 	}
 }
 
+// updatePendingMetrics sets the metrics of the pending evidence: its number,
+// size and the age of the oldest one.
+func (evpool *Pool) updatePendingMetrics() {
+	evList, size, err := evpool.listEvidence(baseKeyPending, -1)
+	if err != nil {
+		evpool.logger.Error("Unable to retrieve pending evidence", "err", err)
+		return
+	}
+
+	var (
+		state       = evpool.State()
+		ageHeights  int64
+		ageDuration time.Duration
+	)
+	for _, ev := range evList {
+		if age := state.LastBlockHeight - ev.Height(); age > ageHeights {
+			ageHeights = age
+		}
+		if age := state.LastBlockTime.Sub(ev.Time()); age > ageDuration {
+			ageDuration = age
+		}
+	}
+
+	evpool.metrics.PendingEvidence.Set(float64(len(evList)))
+	evpool.metrics.PendingEvidenceBytes.Set(float64(size))
+	evpool.metrics.OldestPendingEvidenceHeights.Set(float64(ageHeights))
+	evpool.metrics.OldestPendingEvidenceSeconds.Set(ageDuration.Seconds())
+}
+
 func (evpool *Pool) updateState(state sm.State) {
 	evpool.mtx.Lock()
 	defer evpool.mtx.Unlock()
@@ -524,6 +668,7 @@ func (evpool *Pool) processConsensusBuffer(state sm.State) {
 		evpool.evidenceList.PushBack(dve)
 
 		evpool.logger.Info("verified new evidence of byzantine behavior", "evidence", dve)
+		evpool.metrics.AddedEvidence.With("type", evidenceType(dve)).Add(1)
 	}
 	// reset consensus buffer
 	evpool.consensusBuffer = make([]duplicateVoteSet, 0)
@@ -544,13 +689,20 @@ func bytesToEv(evBytes []byte) (types.Evidence, error) {
 	return types.EvidenceFromProto(&evpb)
 }
 
-func evMapKey(ev types.Evidence) string {
-	return string(ev.Hash())
+// evidenceType returns the type of the evidence, as a metric label value.
+func evidenceType(ev types.Evidence) string {
+	switch ev.(type) {
+	case *types.DuplicateVoteEvidence:
+		return "duplicate_vote"
+	case *types.LightClientAttackEvidence:
+		return "light_client_attack"
+	default:
+		return "unknown"
+	}
 }
 
-// big endian padded hex
-func bE(h int64) string {
-	return fmt.Sprintf("%0.16X", h)
+func evMapKey(ev types.Evidence) string {
+	return string(ev.Hash())
 }
 
 func keyCommitted(evidence types.Evidence) []byte {
@@ -562,5 +714,44 @@ func keyPending(evidence types.Evidence) []byte {
 }
 
 func keySuffix(evidence types.Evidence) []byte {
-	return []byte(fmt.Sprintf("%s/%X", bE(evidence.Height()), evidence.Hash()))
+	return keySuffixOf(evidence.Height(), evidence.Hash())
+}
+
+func keySuffixOf(height int64, hash []byte) []byte {
+	key := make([]byte, 8, 8+len(hash))
+	binary.BigEndian.PutUint64(key, uint64(height)) //nolint:gosec
+	return append(key, hash...)
+}
+
+// committedValue returns the value of committed evidence: the height of the
+// block which committed it and the time of the evidence.
+func committedValue(height int64, evTime time.Time) ([]byte, error) {
+	timeBytes, err := gogotypes.StdTimeMarshal(evTime)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, 8, 8+len(timeBytes))
+	binary.BigEndian.PutUint64(value, uint64(height)) //nolint:gosec
+	return append(value, timeBytes...), nil
+}
+
+// parseCommittedValue returns the height of the block which committed the
+// evidence, and the time of the evidence, of the value of committed evidence.
+func parseCommittedValue(value []byte) (int64, time.Time, error) {
+	var evTime time.Time
+	if len(value) < 8 {
+		return 0, evTime, fmt.Errorf("value is too short: %d bytes", len(value))
+	}
+	if err := gogotypes.StdTimeUnmarshal(&evTime, value[8:]); err != nil {
+		return 0, evTime, err
+	}
+	return int64(binary.BigEndian.Uint64(value[:8])), evTime, nil
+}
+
+// parseKey returns the height and hash of the evidence of a key.
+func parseKey(key []byte) (int64, []byte, error) {
+	if len(key) <= 9 {
+		return 0, nil, fmt.Errorf("key is too short: %d bytes", len(key))
+	}
+	return int64(binary.BigEndian.Uint64(key[1:9])), key[9:], nil
 }
@@ -1,16 +1,23 @@
 package evidence_test
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/gogo/protobuf/proto"
+	gogotypes "github.com/gogo/protobuf/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	dbm "github.com/cometbft/cometbft-db"
 
+	"github.com/tendermint/tendermint/crypto/tmhash"
 	"github.com/tendermint/tendermint/evidence"
 	"github.com/tendermint/tendermint/evidence/mocks"
 	"github.com/tendermint/tendermint/libs/log"
@@ -214,6 +221,227 @@ func TestEvidencePoolUpdate(t *testing.T) {
 	}
 }
 
+// labeledCounter keeps one generic counter per label values, since the
+// labeled generic counters don't add to their parent.
+type labeledCounter struct {
+	counters map[string]*generic.Counter
+}
+
+func newLabeledCounter() *labeledCounter {
+	return &labeledCounter{counters: make(map[string]*generic.Counter)}
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	key := strings.Join(labelValues, ",")
+	if _, ok := c.counters[key]; !ok {
+		c.counters[key] = generic.NewCounter(key)
+	}
+	return c.counters[key]
+}
+
+func (c *labeledCounter) Add(delta float64) {
+	c.With().Add(delta)
+}
+
+func (c *labeledCounter) Value(labelValues ...string) float64 {
+	return c.With(labelValues...).(*generic.Counter).Value()
+}
+
+func TestEvidencePoolMetrics(t *testing.T) {
+	var (
+		height    = int64(21)
+		added     = newLabeledCounter()
+		committed = newLabeledCounter()
+		expired   = newLabeledCounter()
+		m         = evidence.NopMetrics()
+	)
+	m.PendingEvidence = generic.NewGauge("pending_evidence")
+	m.PendingEvidenceBytes = generic.NewGauge("pending_evidence_bytes")
+	m.OldestPendingEvidenceHeights = generic.NewGauge("oldest_pending_evidence_heights")
+	m.OldestPendingEvidenceSeconds = generic.NewGauge("oldest_pending_evidence_seconds")
+	m.BlockEvidence = generic.NewHistogram("block_evidence", 10)
+	m.AddedEvidence = added
+	m.CommittedEvidence = committed
+	m.ExpiredEvidence = expired
+
+	pool, val := defaultTestPool(height, evidence.WithPoolMetrics(m))
+	state := pool.State()
+
+	expiringEv := types.NewMockDuplicateVoteEvidenceWithValidator(1, defaultEvidenceTime.Add(1*time.Minute),
+		val, evidenceChainID)
+	require.NoError(t, pool.AddEvidence(expiringEv))
+	ev := types.NewMockDuplicateVoteEvidenceWithValidator(height, defaultEvidenceTime.Add(21*time.Minute),
+		val, evidenceChainID)
+	require.NoError(t, pool.AddEvidence(ev))
+
+	assert.EqualValues(t, 2, added.Value("type", "duplicate_vote"))
+	assert.EqualValues(t, 2, m.PendingEvidence.(*generic.Gauge).Value())
+	_, size := pool.PendingEvidence(defaultEvidenceMaxBytes)
+	assert.EqualValues(t, size, m.PendingEvidenceBytes.(*generic.Gauge).Value())
+	assert.EqualValues(t, height-1, m.OldestPendingEvidenceHeights.(*generic.Gauge).Value())
+
+	// commit the recent evidence in a block past the max age of the first
+	// one, which expires
+	state.LastBlockHeight = height + 1
+	state.LastBlockTime = defaultEvidenceTime.Add(22 * time.Minute)
+	pool.Update(state, types.EvidenceList{ev})
+
+	assert.EqualValues(t, 1, committed.Value("type", "duplicate_vote"))
+	assert.EqualValues(t, 1, expired.Value("type", "duplicate_vote"))
+	assert.EqualValues(t, 1, m.BlockEvidence.(*generic.Histogram).Quantile(1))
+	assert.Zero(t, m.PendingEvidence.(*generic.Gauge).Value())
+	assert.Zero(t, m.PendingEvidenceBytes.(*generic.Gauge).Value())
+	assert.Zero(t, m.OldestPendingEvidenceHeights.(*generic.Gauge).Value())
+	assert.Zero(t, m.OldestPendingEvidenceSeconds.(*generic.Gauge).Value())
+}
+
+// Committed evidence is pruned once it expires, and duplicates of it are
+// rejected before and after.
+func TestPruneCommittedEvidence(t *testing.T) {
+	var (
+		height     = int64(21)
+		val        = types.NewMockPV()
+		evidenceDB = dbm.NewMemDB()
+		stateStore = initializeValidatorState(val, height)
+	)
+	state, err := stateStore.Load()
+	require.NoError(t, err)
+	blockStore := initializeBlockStore(dbm.NewMemDB(), state, val.PrivKey.PubKey().Address())
+	pool, err := evidence.NewPool(evidenceDB, stateStore, blockStore)
+	require.NoError(t, err)
+	pool.SetLogger(log.TestingLogger())
+
+	ev := types.NewMockDuplicateVoteEvidenceWithValidator(2, defaultEvidenceTime.Add(2*time.Minute),
+		val, evidenceChainID)
+	require.NoError(t, pool.CheckEvidence(types.EvidenceList{ev}))
+
+	// the evidence is committed at the last height it can be: it's max age
+	// blocks and duration old
+	state.LastBlockHeight = height + 1
+	state.LastBlockTime = defaultEvidenceTime.Add(22 * time.Minute)
+	pool.Update(state, types.EvidenceList{ev})
+	assert.Equal(t, 1, countKeys(t, evidenceDB))
+
+	err = pool.CheckEvidence(types.EvidenceList{ev})
+	if assert.Error(t, err) {
+		assert.Equal(t, "evidence was already committed", err.(*types.ErrInvalidEvidence).Reason.Error())
+	}
+
+	// once the evidence expires, it's pruned, and rejected as too old
+	state.LastBlockHeight++
+	state.LastBlockTime = state.LastBlockTime.Add(time.Minute)
+	pool.Update(state, types.EvidenceList{})
+	assert.Zero(t, countKeys(t, evidenceDB))
+
+	err = pool.CheckEvidence(types.EvidenceList{ev})
+	assert.ErrorContains(t, err, "too old")
+}
+
+func TestEvidenceStatus(t *testing.T) {
+	var (
+		height     = int64(21)
+		val        = types.NewMockPV()
+		stateStore = initializeValidatorState(val, height)
+	)
+	state, err := stateStore.Load()
+	require.NoError(t, err)
+	blockStore := initializeBlockStore(dbm.NewMemDB(), state, val.PrivKey.PubKey().Address())
+	pool, err := evidence.NewPool(dbm.NewMemDB(), stateStore, blockStore)
+	require.NoError(t, err)
+
+	pendingEv := types.NewMockDuplicateVoteEvidenceWithValidator(height, defaultEvidenceTime.Add(21*time.Minute),
+		val, evidenceChainID)
+	committedEv := types.NewMockDuplicateVoteEvidenceWithValidator(height-1, defaultEvidenceTime.Add(20*time.Minute),
+		val, evidenceChainID)
+	require.NoError(t, pool.AddEvidence(pendingEv))
+	require.NoError(t, pool.AddEvidence(committedEv))
+	state.LastBlockHeight = height + 1
+	state.LastBlockTime = defaultEvidenceTime.Add(22 * time.Minute)
+	pool.Update(state, types.EvidenceList{committedEv})
+
+	params := state.ConsensusParams.Evidence
+	status, err := pool.EvidenceStatus(pendingEv.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, evidence.Status{
+		Status:       evidence.StatusPending,
+		Height:       height,
+		ExpiryHeight: height + params.MaxAgeNumBlocks + 1,
+		ExpiryTime:   pendingEv.Time().Add(params.MaxAgeDuration).Add(time.Nanosecond),
+	}, status)
+
+	status, err = pool.EvidenceStatus(committedEv.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, evidence.Status{
+		Status:          evidence.StatusCommitted,
+		Height:          height - 1,
+		CommittedHeight: height + 1,
+		ExpiryHeight:    height + params.MaxAgeNumBlocks,
+		ExpiryTime:      committedEv.Time().Add(params.MaxAgeDuration).Add(time.Nanosecond),
+	}, status)
+
+	status, err = pool.EvidenceStatus(tmhash.Sum([]byte("unknown")))
+	require.NoError(t, err)
+	assert.Equal(t, evidence.Status{Status: evidence.StatusUnknown}, status)
+}
+
+func TestMigrateLegacyEvidenceKeys(t *testing.T) {
+	var (
+		height     = int64(21)
+		val        = types.NewMockPV()
+		evidenceDB = dbm.NewMemDB()
+		stateStore = initializeValidatorState(val, height)
+	)
+	state, err := stateStore.Load()
+	require.NoError(t, err)
+	blockStore := initializeBlockStore(dbm.NewMemDB(), state, val.PrivKey.PubKey().Address())
+
+	pendingEv := types.NewMockDuplicateVoteEvidenceWithValidator(height, defaultEvidenceTime.Add(21*time.Minute),
+		val, evidenceChainID)
+	committedEv := types.NewMockDuplicateVoteEvidenceWithValidator(height-1, defaultEvidenceTime.Add(20*time.Minute),
+		val, evidenceChainID)
+
+	// the legacy layout
+	evpb, err := types.EvidenceToProto(pendingEv)
+	require.NoError(t, err)
+	evBytes, err := evpb.Marshal()
+	require.NoError(t, err)
+	require.NoError(t, evidenceDB.Set(legacyKey(0x01, pendingEv), evBytes))
+	heightBytes, err := proto.Marshal(&gogotypes.Int64Value{Value: committedEv.Height()})
+	require.NoError(t, err)
+	require.NoError(t, evidenceDB.Set(legacyKey(0x00, committedEv), heightBytes))
+
+	pool, err := evidence.NewPool(evidenceDB, stateStore, blockStore)
+	require.NoError(t, err)
+
+	pending, _ := pool.PendingEvidence(defaultEvidenceMaxBytes)
+	assert.Equal(t, []types.Evidence{pendingEv}, pending)
+	err = pool.CheckEvidence(types.EvidenceList{committedEv})
+	if assert.Error(t, err) {
+		assert.Equal(t, "evidence was already committed", err.(*types.ErrInvalidEvidence).Reason.Error())
+	}
+	for _, prefix := range []byte{0x00, 0x01} {
+		iter, err := dbm.IteratePrefix(evidenceDB, []byte{prefix})
+		require.NoError(t, err)
+		assert.False(t, iter.Valid(), "legacy key with prefix %X left", prefix)
+		iter.Close()
+	}
+}
+
+func legacyKey(prefix byte, ev types.Evidence) []byte {
+	return append([]byte{prefix}, fmt.Sprintf("%0.16X/%X", ev.Height(), ev.Hash())...)
+}
+
+func countKeys(t *testing.T, db dbm.DB) int {
+	iter, err := db.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer iter.Close()
+	count := 0
+	for ; iter.Valid(); iter.Next() {
+		count++
+	}
+	return count
+}
+
 func TestVerifyPendingEvidencePasses(t *testing.T) {
 	var height int64 = 1
 	pool, val := defaultTestPool(height)
@@ -411,6 +639,7 @@ func initializeBlockStore(db dbm.DB, state sm.State, valAddr []byte) *store.Bloc
 			state.Validators.GetProposer().Address)
 		block.Header.Time = defaultEvidenceTime.Add(time.Duration(i) * time.Minute)
 		block.Header.Version = cmtversion.Consensus{Block: version.BlockProtocol, App: 1}
+		block.InvalidateHashes()
 		partSet := block.MakePartSet(types.BlockPartSizeBytes)
 
 		seenCommit := makeCommit(i, valAddr)
@@ -430,14 +659,14 @@ func makeCommit(height int64, valAddr []byte) *types.Commit {
 	return types.NewCommit(height, 0, types.BlockID{}, commitSigs)
 }
 
-func defaultTestPool(height int64) (*evidence.Pool, types.MockPV) {
+func defaultTestPool(height int64, options ...evidence.PoolOption) (*evidence.Pool, types.MockPV) {
 	val := types.NewMockPV()
 	valAddress := val.PrivKey.PubKey().Address()
 	evidenceDB := dbm.NewMemDB()
 	stateStore := initializeValidatorState(val, height)
 	state, _ := stateStore.Load()
 	blockStore := initializeBlockStore(dbm.NewMemDB(), state, valAddress)
-	pool, err := evidence.NewPool(evidenceDB, stateStore, blockStore)
+	pool, err := evidence.NewPool(evidenceDB, stateStore, blockStore, options...)
 	if err != nil {
 		panic("test evidence pool could not be created")
 	}
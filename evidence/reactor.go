@@ -5,8 +5,11 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+
+	"github.com/tendermint/tendermint/behaviour"
 	clist "github.com/tendermint/tendermint/libs/clist"
 	"github.com/tendermint/tendermint/libs/log"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/p2p"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
@@ -22,8 +25,23 @@ const (
 	// Most evidence should be committed in the very next block that is why we wait
 	// just over the block production rate before sending evidence again.
 	broadcastEvidenceIntervalS = 10
+	// Evidence already sent to a peer is only sent again this often, in case
+	// the peer dropped it.
+	resendEvidenceIntervalS = 60
 	// If a message fails wait this much before sending it again
 	peerRetryMessageIntervalMS = 100
+
+	// The evidence received from each peer is verified at most at this rate,
+	// per second, with bursts of up to peerEvidenceBurst evidence. The evidence
+	// beyond is dropped unverified, as the peer sends it again later.
+	peerEvidenceRate  = 10
+	peerEvidenceBurst = 100
+	// A peer sending more evidence failing verification than this within
+	// peerEvidenceFailureWindow is disconnected, and banned for
+	// peerEvidenceBanTime by default.
+	maxPeerEvidenceFailures   = 10
+	peerEvidenceFailureWindow = time.Minute
+	peerEvidenceBanTime       = 24 * time.Hour
 )
 
 // Reactor handles evpool evidence broadcasting amongst peers.
@@ -31,14 +49,73 @@ type Reactor struct {
 	p2p.BaseReactor
 	evpool   *Pool
 	eventBus *types.EventBus
+	metrics  *Metrics
+
+	// reports the evidence peers send, whether it's verified or not
+	reporter behaviour.Reporter
+
+	peerRate      float64
+	peerBurst     int
+	maxFailures   int
+	failureWindow time.Duration
+	banTime       time.Duration
+
+	mtx cmtsync.Mutex
+	// the hashes of the evidence each peer has
+	peerEvidence map[p2p.ID]map[string]struct{}
+	// the limits of the evidence verified for each peer
+	peerLimits map[p2p.ID]*peerLimit
+}
+
+// ReactorOption sets an optional parameter on the Reactor.
+type ReactorOption func(*Reactor)
+
+// WithMetrics sets the metrics of the reactor.
+func WithMetrics(metrics *Metrics) ReactorOption {
+	return func(evR *Reactor) { evR.metrics = metrics }
+}
+
+// WithReporter sets the reporter of the behaviour of peers. It defaults to
+// reporting to the switch of the reactor.
+func WithReporter(reporter behaviour.Reporter) ReactorOption {
+	return func(evR *Reactor) { evR.reporter = reporter }
+}
+
+// WithPeerRateLimit sets the rate, per second, and burst at which the evidence
+// of each peer is verified.
+func WithPeerRateLimit(rate float64, burst int) ReactorOption {
+	return func(evR *Reactor) { evR.peerRate, evR.peerBurst = rate, burst }
+}
+
+// WithPeerFailureLimit sets the number of evidence failing verification a peer
+// can send within the window before being disconnected and banned.
+func WithPeerFailureLimit(failures int, window time.Duration) ReactorOption {
+	return func(evR *Reactor) { evR.maxFailures, evR.failureWindow = failures, window }
+}
+
+// WithPeerBanTime sets how long a peer sending too much evidence failing
+// verification is banned for.
+func WithPeerBanTime(banTime time.Duration) ReactorOption {
+	return func(evR *Reactor) { evR.banTime = banTime }
 }
 
 // NewReactor returns a new Reactor with the given config and evpool.
-func NewReactor(evpool *Pool) *Reactor {
+func NewReactor(evpool *Pool, options ...ReactorOption) *Reactor {
 	evR := &Reactor{
-		evpool: evpool,
+		evpool:        evpool,
+		metrics:       NopMetrics(),
+		peerRate:      peerEvidenceRate,
+		peerBurst:     peerEvidenceBurst,
+		maxFailures:   maxPeerEvidenceFailures,
+		failureWindow: peerEvidenceFailureWindow,
+		banTime:       peerEvidenceBanTime,
+		peerEvidence:  make(map[p2p.ID]map[string]struct{}),
+		peerLimits:    make(map[p2p.ID]*peerLimit),
 	}
 	evR.BaseReactor = *p2p.NewBaseReactor("Evidence", evR)
+	for _, option := range options {
+		option(evR)
+	}
 	return evR
 }
 
@@ -48,6 +125,14 @@ func (evR *Reactor) SetLogger(l log.Logger) {
 	evR.evpool.SetLogger(l)
 }
 
+// SetSwitch implements Reactor.
+func (evR *Reactor) SetSwitch(sw *p2p.Switch) {
+	evR.BaseReactor.SetSwitch(sw)
+	if evR.reporter == nil {
+		evR.reporter = behaviour.NewSwitchReporter(sw)
+	}
+}
+
 // GetChannels implements Reactor.
 // It returns the list of channels for this reactor.
 func (evR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
@@ -61,13 +146,32 @@ func (evR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 	}
 }
 
+// InitPeer implements Reactor.
+func (evR *Reactor) InitPeer(peer p2p.Peer) p2p.Peer {
+	evR.mtx.Lock()
+	defer evR.mtx.Unlock()
+	evR.peerEvidence[peer.ID()] = make(map[string]struct{})
+	evR.peerLimits[peer.ID()] = &peerLimit{tokens: float64(evR.peerBurst), updated: time.Now()}
+	return peer
+}
+
 // AddPeer implements Reactor.
 func (evR *Reactor) AddPeer(peer p2p.Peer) {
 	go evR.broadcastEvidenceRoutine(peer)
 }
 
+// RemovePeer implements Reactor.
+func (evR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
+	evR.mtx.Lock()
+	defer evR.mtx.Unlock()
+	delete(evR.peerEvidence, peer.ID())
+	delete(evR.peerLimits, peer.ID())
+	// broadcast routine checks if peer is gone and returns
+}
+
 // Receive implements Reactor.
-// It adds any received evidence to the evpool.
+// It adds any received evidence to the evpool, within the rate limit of the
+// peer. Peers sending too much evidence failing verification are banned.
 func (evR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 	evis, err := evidenceListFromProto(e.Message)
 	if err != nil {
@@ -76,15 +180,30 @@ func (evR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 		return
 	}
 
+	// don't send the evidence back to the peer
+	evR.markPeerEvidence(e.Src.ID(), evis)
+
+	peerID := e.Src.ID()
 	for _, ev := range evis {
+		if !evR.allowPeerEvidence(peerID) {
+			evR.metrics.ReceivedEvidence.With("result", "rate_limited").Add(1)
+			continue
+		}
+
 		err := evR.evpool.AddEvidence(ev)
 		switch err.(type) {
 		case *types.ErrInvalidEvidence:
-			evR.Logger.Error(err.Error())
-			// punish peer
-			evR.Switch.StopPeerForError(e.Src, err)
-			return
+			evR.metrics.ReceivedEvidence.With("result", "rejected").Add(1)
+			evR.Logger.Info("Rejected evidence from peer", "peer", peerID, "err", err)
+			if evR.peerEvidenceFailed(peerID) {
+				evR.Logger.Error("Peer sent too much invalid evidence", "peer", peerID, "err", err)
+				// punish peer
+				_ = evR.reporter.Report(behaviour.InvalidEvidence(peerID, err.Error(), evR.banTime))
+				return
+			}
 		case nil:
+			evR.metrics.ReceivedEvidence.With("result", "accepted").Add(1)
+			_ = evR.reporter.Report(behaviour.VerifiedEvidence(peerID, "sent valid evidence"))
 		default:
 			// continue to the next piece of evidence
 			evR.Logger.Error("Evidence has not been added", "evidence", evis, "err", err)
@@ -113,11 +232,18 @@ func (evR *Reactor) SetEventBus(b *types.EventBus) {
 // Modeled after the mempool routine.
 // - Evidence accumulates in a clist.
 // - Each peer has a routine that iterates through the clist,
-// sending available evidence to the peer.
+// sending available evidence the peer doesn't have yet to the peer, in
+// batches.
 // - If we're waiting for new evidence and the list is not empty,
-// start iterating from the beginning again.
+// start iterating from the beginning again, skipping the evidence the peer
+// already has.
+// - Every resendEvidenceIntervalS, forget which evidence the peer has, so that
+// the evidence which is still pending is sent again.
 func (evR *Reactor) broadcastEvidenceRoutine(peer p2p.Peer) {
-	var next *clist.CElement
+	var (
+		next       *clist.CElement
+		lastResend = time.Now()
+	)
 	for {
 		// This happens because the CElement we were looking at got garbage
 		// collected (removed). That is, .NextWait() returned nil. Go ahead and
@@ -137,10 +263,14 @@ func (evR *Reactor) broadcastEvidenceRoutine(peer p2p.Peer) {
 			return
 		}
 
-		ev := next.Value.(types.Evidence)
-		evis := evR.prepareEvidenceMessage(peer, ev)
+		if time.Since(lastResend) >= resendEvidenceIntervalS*time.Second {
+			evR.resetPeerEvidence(peer.ID())
+			lastResend = time.Now()
+		}
+
+		evis, last := evR.prepareEvidenceMessage(peer, next)
 		if len(evis) > 0 {
-			evR.Logger.Debug("Gossiping evidence to peer", "ev", ev, "peer", peer)
+			evR.Logger.Debug("Gossiping evidence to peer", "evidence", evis, "peer", peer)
 			evp, err := evidenceListToProto(evis)
 			if err != nil {
 				panic(err)
@@ -154,17 +284,19 @@ func (evR *Reactor) broadcastEvidenceRoutine(peer p2p.Peer) {
 				time.Sleep(peerRetryMessageIntervalMS * time.Millisecond)
 				continue
 			}
+			evR.markPeerEvidence(peer.ID(), evis)
+			evR.metrics.SentEvidence.Add(float64(len(evis)))
+			evR.metrics.SentMessages.Add(1)
 		}
 
 		afterCh := time.After(time.Second * broadcastEvidenceIntervalS)
 		select {
 		case <-afterCh:
 			// start from the beginning every tick.
-			// TODO: only do this if we're at the end of the list!
 			next = nil
-		case <-next.NextWaitChan():
+		case <-last.NextWaitChan():
 			// see the start of the for loop for nil check
-			next = next.Next()
+			next = last.Next()
 		case <-peer.Quit():
 			return
 		case <-evR.Quit():
@@ -173,13 +305,46 @@ func (evR *Reactor) broadcastEvidenceRoutine(peer p2p.Peer) {
 	}
 }
 
-// Returns the message to send to the peer, or nil if the evidence is invalid for the peer.
-// If message is nil, we should sleep and try again.
-func (evR Reactor) prepareEvidenceMessage(
+// Returns the evidence to send to the peer, starting from the given element,
+// and the last element considered. The evidence is the one the peer doesn't
+// have yet and is ready for, up to the maximum size of a message. If no
+// evidence is returned, we should wait and try again.
+func (evR *Reactor) prepareEvidenceMessage(
 	peer p2p.Peer,
-	ev types.Evidence,
-) (evis []types.Evidence) {
+	start *clist.CElement,
+) (evis []types.Evidence, last *clist.CElement) {
+	size := 0
+	for e := start; e != nil; e = e.Next() {
+		ev := e.Value.(types.Evidence)
+		if evR.peerHasEvidence(peer.ID(), ev) {
+			evR.metrics.SuppressedDuplicates.Add(1)
+			last = e
+			continue
+		}
+		if !evR.evidenceReadyForPeer(peer, ev) {
+			last = e
+			continue
+		}
 
+		evp, err := types.EvidenceToProto(ev)
+		if err != nil {
+			panic(err)
+		}
+		// the size of the evidence within the list message
+		evSize := 1 + proto.SizeVarint(uint64(evp.Size())) + evp.Size()
+		if len(evis) > 0 && size+evSize > maxMsgSize {
+			break
+		}
+		size += evSize
+		evis = append(evis, ev)
+		last = e
+	}
+	return evis, last
+}
+
+// evidenceReadyForPeer returns whether the evidence can be sent to the peer:
+// it's neither too recent nor too old for it.
+func (evR *Reactor) evidenceReadyForPeer(peer p2p.Peer, ev types.Evidence) bool {
 	// make sure the peer is up to date
 	evHeight := ev.Height()
 	peerState, ok := peer.Get(types.PeerStateKey).(PeerState)
@@ -189,7 +354,7 @@ func (evR Reactor) prepareEvidenceMessage(
 		// different every time due to us using a map. Sometimes other reactors
 		// will be initialized before the consensus reactor. We should wait a few
 		// milliseconds and retry.
-		return nil
+		return false
 	}
 
 	// NOTE: We only send evidence to peers where
@@ -201,7 +366,7 @@ func (evR Reactor) prepareEvidenceMessage(
 	)
 
 	if peerHeight <= evHeight { // peer is behind. sleep while he catches up
-		return nil
+		return false
 	} else if ageNumBlocks > params.MaxAgeNumBlocks { // evidence is too old relative to the peer, skip
 
 		// NOTE: if evidence is too old for an honest peer, then we're behind and
@@ -215,11 +380,44 @@ func (evR Reactor) prepareEvidenceMessage(
 			"peer", peer,
 		)
 
-		return nil
+		return false
+	}
+
+	return true
+}
+
+// markPeerEvidence records that the peer has the evidence, since it was sent
+// to it or received from it.
+func (evR *Reactor) markPeerEvidence(peerID p2p.ID, evis []types.Evidence) {
+	evR.mtx.Lock()
+	defer evR.mtx.Unlock()
+
+	hashes, ok := evR.peerEvidence[peerID]
+	if !ok {
+		// the peer was removed
+		return
+	}
+	for _, ev := range evis {
+		hashes[string(ev.Hash())] = struct{}{}
 	}
+}
+
+func (evR *Reactor) peerHasEvidence(peerID p2p.ID, ev types.Evidence) bool {
+	evR.mtx.Lock()
+	defer evR.mtx.Unlock()
+
+	_, ok := evR.peerEvidence[peerID][string(ev.Hash())]
+	return ok
+}
 
-	// send evidence
-	return []types.Evidence{ev}
+// resetPeerEvidence forgets which evidence the peer has.
+func (evR *Reactor) resetPeerEvidence(peerID p2p.ID) {
+	evR.mtx.Lock()
+	defer evR.mtx.Unlock()
+
+	if _, ok := evR.peerEvidence[peerID]; ok {
+		evR.peerEvidence[peerID] = make(map[string]struct{})
+	}
 }
 
 // PeerState describes the state of a peer.
@@ -264,3 +462,54 @@ func evidenceListFromProto(m proto.Message) ([]types.Evidence, error) {
 
 	return evis, nil
 }
+
+// peerLimit limits the rate at which the evidence of a peer is verified, with
+// a token bucket, and counts the evidence of the peer failing verification.
+type peerLimit struct {
+	tokens  float64
+	updated time.Time
+
+	failures    int
+	windowStart time.Time
+}
+
+// allowPeerEvidence returns whether the next evidence of the peer can be
+// verified, within its rate limit.
+func (evR *Reactor) allowPeerEvidence(peerID p2p.ID) bool {
+	evR.mtx.Lock()
+	defer evR.mtx.Unlock()
+
+	limit, ok := evR.peerLimits[peerID]
+	if !ok {
+		// the peer was removed
+		return false
+	}
+	now := time.Now()
+	limit.tokens += now.Sub(limit.updated).Seconds() * evR.peerRate
+	if limit.tokens > float64(evR.peerBurst) {
+		limit.tokens = float64(evR.peerBurst)
+	}
+	limit.updated = now
+	if limit.tokens < 1 {
+		return false
+	}
+	limit.tokens--
+	return true
+}
+
+// peerEvidenceFailed records that evidence of the peer failed verification,
+// and returns whether the peer exceeded the failures tolerated in the window.
+func (evR *Reactor) peerEvidenceFailed(peerID p2p.ID) bool {
+	evR.mtx.Lock()
+	defer evR.mtx.Unlock()
+
+	limit, ok := evR.peerLimits[peerID]
+	if !ok {
+		return false
+	}
+	if now := time.Now(); now.Sub(limit.windowStart) > evR.failureWindow {
+		limit.failures, limit.windowStart = 0, now
+	}
+	limit.failures++
+	return limit.failures > evR.maxFailures
+}
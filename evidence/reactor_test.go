@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/fortytw2/leaktest"
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/go-kit/log/term"
 	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +17,7 @@ import (
 
 	dbm "github.com/cometbft/cometbft-db"
 
+	"github.com/tendermint/tendermint/behaviour"
 	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/tmhash"
@@ -109,6 +111,53 @@ func TestReactorSelectiveBroadcast(t *testing.T) {
 	assert.Equal(t, 1, len(peers))
 }
 
+// We have two evidence reactors connected to one another. Each evidence crosses
+// the wire once: it isn't sent back to the reactor it was received from, nor
+// sent again when a reactor goes back to the start of its evidence list.
+func TestReactorBroadcastEvidenceOnce(t *testing.T) {
+	config := cfg.TestConfig()
+
+	val := types.NewMockPV()
+	height := int64(numEvidence) + 10
+	stateDB1 := initializeValidatorState(val, height)
+	stateDB2 := initializeValidatorState(val, height)
+
+	var (
+		sentEvidence = generic.NewCounter("sent_evidence")
+		sentMessages = generic.NewCounter("sent_messages")
+		suppressed   = generic.NewCounter("suppressed_duplicates")
+		metrics      = evidence.NopMetrics()
+	)
+	metrics.SentEvidence = sentEvidence
+	metrics.SentMessages = sentMessages
+	metrics.SuppressedDuplicates = suppressed
+
+	reactors, pools := makeAndConnectReactorsAndPools(config, []sm.Store{stateDB1, stateDB2},
+		evidence.WithMetrics(metrics))
+	for _, r := range reactors {
+		for _, peer := range r.Switch.Peers().List() {
+			peer.Set(types.PeerStateKey, peerState{height})
+		}
+	}
+
+	evList := sendEvidence(t, pools[0], val, numEvidence)
+	waitForEvidence(t, evList, pools)
+
+	// committing the last evidence makes the first reactor go back to the
+	// start of its evidence list
+	state, err := stateDB1.Load()
+	require.NoError(t, err)
+	state.LastBlockHeight++
+	pools[0].Update(state, evList[numEvidence-1:])
+	require.EqualValues(t, numEvidence-1, pools[0].Size())
+
+	time.Sleep(300 * time.Millisecond)
+
+	assert.EqualValues(t, numEvidence, sentEvidence.Value())
+	assert.LessOrEqual(t, sentMessages.Value(), float64(numEvidence))
+	assert.GreaterOrEqual(t, suppressed.Value(), float64(numEvidence-1))
+}
+
 // This tests aims to ensure that reactors don't send evidence that they have committed or that ar
 // not ready for the peer through three scenarios.
 // First, committed evidence to a newly connected peer
@@ -216,7 +265,7 @@ func TestReactorBroadcastEvidenceMemoryLeak(t *testing.T) {
 	p.On("Quit").Return(quitChan)
 	ps := peerState{2}
 	p.On("Get", types.PeerStateKey).Return(ps)
-	p.On("ID").Return("ABC")
+	p.On("ID").Return(p2p.ID("ABC"))
 	p.On("String").Return("mock")
 
 	r := evidence.NewReactor(pool)
@@ -240,8 +289,8 @@ func evidenceLogger() log.Logger {
 }
 
 // connect N evidence reactors through N switches
-func makeAndConnectReactorsAndPools(config *cfg.Config, stateStores []sm.Store) ([]*evidence.Reactor,
-	[]*evidence.Pool) {
+func makeAndConnectReactorsAndPools(config *cfg.Config, stateStores []sm.Store,
+	options ...evidence.ReactorOption) ([]*evidence.Reactor, []*evidence.Pool) {
 	N := len(stateStores)
 
 	reactors := make([]*evidence.Reactor, N)
@@ -260,7 +309,7 @@ func makeAndConnectReactorsAndPools(config *cfg.Config, stateStores []sm.Store)
 			panic(err)
 		}
 		pools[i] = pool
-		reactors[i] = evidence.NewReactor(pool)
+		reactors[i] = evidence.NewReactor(pool, options...)
 		reactors[i].SetLogger(logger.With("validator", i))
 	}
 
@@ -386,6 +435,7 @@ func TestLegacyReactorReceiveBasic(t *testing.T) {
 	)
 	quitChan := make(<-chan struct{})
 	peer.On("Quit").Return(quitChan)
+	peer.On("ID").Return(p2p.ID("peer"))
 
 	reactor.InitPeer(peer)
 	reactor.AddPeer(peer)
@@ -398,6 +448,166 @@ func TestLegacyReactorReceiveBasic(t *testing.T) {
 	})
 }
 
+func TestReactorRateLimitsPeerEvidence(t *testing.T) {
+	config := cfg.TestConfig()
+	val := types.NewMockPV()
+	height := int64(10)
+	stateDB := initializeValidatorState(val, height)
+
+	const (
+		rate     = 10
+		burst    = 20
+		messages = 50
+		perMsg   = 20
+	)
+	var (
+		received = newLabeledCounter()
+		metrics  = evidence.NopMetrics()
+		reporter = behaviour.NewMockReporter()
+	)
+	metrics.ReceivedEvidence = received
+
+	reactors, _ := makeAndConnectReactorsAndPools(config, []sm.Store{stateDB},
+		evidence.WithMetrics(metrics),
+		evidence.WithReporter(reporter),
+		evidence.WithPeerRateLimit(rate, burst),
+		evidence.WithPeerFailureLimit(messages*perMsg, time.Minute))
+	reactor := reactors[0]
+
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("peer"))
+	reactor.InitPeer(peer)
+
+	// a peer streaming garbage evidence only gets the evidence allowed by
+	// its rate limit verified
+	start := time.Now()
+	for i := 0; i < messages; i++ {
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			ChannelID: evidence.EvidenceChannel,
+			Src:       peer,
+			Message:   garbageEvidence(t, height, perMsg),
+		})
+	}
+	elapsed := time.Since(start)
+
+	verified := received.Value("result", "accepted") + received.Value("result", "rejected")
+	assert.Zero(t, received.Value("result", "accepted"))
+	assert.LessOrEqual(t, verified, burst+rate*elapsed.Seconds()+1)
+	assert.Equal(t, float64(messages*perMsg)-verified, received.Value("result", "rate_limited"))
+	assert.Empty(t, reporter.GetBehaviours("peer"))
+
+	// evidence from removed peers isn't verified
+	reactor.RemovePeer(peer, nil)
+	rateLimited := received.Value("result", "rate_limited")
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		ChannelID: evidence.EvidenceChannel,
+		Src:       peer,
+		Message:   garbageEvidence(t, height, 1),
+	})
+	assert.Equal(t, rateLimited+1, received.Value("result", "rate_limited"))
+}
+
+func TestReactorReportsPeerEvidence(t *testing.T) {
+	config := cfg.TestConfig()
+	val := types.NewMockPV()
+	height := int64(10)
+	stateDB := initializeValidatorState(val, height)
+
+	const maxFailures = 5
+	reporter := behaviour.NewMockReporter()
+	reactors, pools := makeAndConnectReactorsAndPools(config, []sm.Store{stateDB},
+		evidence.WithReporter(reporter),
+		evidence.WithPeerFailureLimit(maxFailures, time.Minute))
+	reactor := reactors[0]
+
+	peer := &p2pmocks.Peer{}
+	peer.On("ID").Return(p2p.ID("peer"))
+	reactor.InitPeer(peer)
+
+	ev := types.NewMockDuplicateVoteEvidenceWithValidator(height, defaultEvidenceTime, val, evidenceChainID)
+	evp, err := types.EvidenceToProto(ev)
+	require.NoError(t, err)
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		ChannelID: evidence.EvidenceChannel,
+		Src:       peer,
+		Message:   &cmtproto.EvidenceList{Evidence: []cmtproto.Evidence{*evp}},
+	})
+	assert.EqualValues(t, 1, pools[0].Size())
+	assert.Equal(t, []behaviour.PeerBehaviour{
+		behaviour.VerifiedEvidence("peer", "sent valid evidence"),
+	}, reporter.GetBehaviours("peer"))
+
+	// failures up to the limit are tolerated
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		ChannelID: evidence.EvidenceChannel,
+		Src:       peer,
+		Message:   garbageEvidence(t, height, maxFailures),
+	})
+	assert.Len(t, reporter.GetBehaviours("peer"), 1)
+
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		ChannelID: evidence.EvidenceChannel,
+		Src:       peer,
+		Message:   garbageEvidence(t, height, 1),
+	})
+	assert.Len(t, reporter.GetBehaviours("peer"), 2)
+}
+
+// A peer sending a stream of garbage evidence is disconnected after a bounded
+// number of verifications.
+func TestReactorDisconnectsPeerSendingGarbageEvidence(t *testing.T) {
+	config := cfg.TestConfig()
+	val := types.NewMockPV()
+	height := int64(10)
+	stateDB1 := initializeValidatorState(val, height)
+	stateDB2 := initializeValidatorState(val, height)
+
+	const maxFailures = 5
+	var (
+		received = newLabeledCounter()
+		metrics  = evidence.NopMetrics()
+	)
+	metrics.ReceivedEvidence = received
+
+	reactors, _ := makeAndConnectReactorsAndPools(config, []sm.Store{stateDB1, stateDB2},
+		evidence.WithMetrics(metrics),
+		evidence.WithPeerFailureLimit(maxFailures, time.Minute))
+	t.Cleanup(func() {
+		for _, r := range reactors {
+			if err := r.Switch.Stop(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	attacker := reactors[1].Switch.Peers().List()[0]
+	for i := 0; i < 20; i++ {
+		p2p.TrySendEnvelopeShim(attacker, p2p.Envelope{ //nolint: staticcheck
+			ChannelID: evidence.EvidenceChannel,
+			Message:   garbageEvidence(t, height, 10),
+		}, reactors[1].Logger)
+	}
+
+	require.Eventually(t, func() bool {
+		return reactors[0].Switch.Peers().Size() == 0
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Zero(t, received.Value("result", "accepted"))
+	assert.LessOrEqual(t, received.Value("result", "rejected"), float64(maxFailures+1))
+}
+
+// garbageEvidence returns a list of n duplicate vote evidence from unknown
+// validators.
+func garbageEvidence(t *testing.T, height int64, n int) *cmtproto.EvidenceList {
+	evl := &cmtproto.EvidenceList{Evidence: make([]cmtproto.Evidence, n)}
+	for i := 0; i < n; i++ {
+		ev := types.NewMockDuplicateVoteEvidence(height, defaultEvidenceTime, evidenceChainID)
+		evp, err := types.EvidenceToProto(ev)
+		require.NoError(t, err)
+		evl.Evidence[i] = *evp
+	}
+	return evl
+}
+
 //nolint:lll //ignore line length for tests
 func TestEvidenceVectors(t *testing.T) {
 
@@ -0,0 +1,113 @@
+package evidence
+
+import (
+	"bytes"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// The statuses of evidence.
+const (
+	// StatusPending is the status of verified evidence waiting to be
+	// committed.
+	StatusPending = "pending"
+	// StatusCommitted is the status of evidence committed in a block.
+	StatusCommitted = "committed"
+	// StatusExpired is the status of pending evidence which expired without
+	// being committed, until it's pruned.
+	StatusExpired = "expired"
+	// StatusUnknown is the status of evidence the pool doesn't hold: it never
+	// received it, or it expired and was pruned.
+	StatusUnknown = "unknown"
+)
+
+// Status is the status of a piece of evidence in the pool.
+type Status struct {
+	Status string
+	// Height is the height of the evidence, and CommittedHeight the height of
+	// the block which committed it, if committed and known.
+	Height          int64
+	CommittedHeight int64
+	// ExpiryHeight and ExpiryTime are the height and time of the first block
+	// at which the evidence is expired, as it must be past both.
+	ExpiryHeight int64
+	ExpiryTime   time.Time
+}
+
+// EvidenceStatus returns the status of the evidence with the given hash.
+func (evpool *Pool) EvidenceStatus(hash []byte) (Status, error) {
+	key, value, err := evpool.findEvidence(baseKeyPending, hash)
+	if err != nil {
+		return Status{}, err
+	}
+	if key != nil {
+		ev, err := bytesToEv(value)
+		if err != nil {
+			return Status{}, err
+		}
+		status := evpool.statusOf(StatusPending, ev.Height(), ev.Time())
+		if evpool.isExpired(ev.Height(), ev.Time()) {
+			status.Status = StatusExpired
+		}
+		return status, nil
+	}
+
+	key, value, err = evpool.findEvidence(baseKeyCommitted, hash)
+	if err != nil {
+		return Status{}, err
+	}
+	if key != nil {
+		height, _, err := parseKey(key)
+		if err != nil {
+			return Status{}, err
+		}
+		committedHeight, evTime, err := parseCommittedValue(value)
+		if err != nil {
+			return Status{}, err
+		}
+		status := evpool.statusOf(StatusCommitted, height, evTime)
+		status.CommittedHeight = committedHeight
+		return status, nil
+	}
+
+	return Status{Status: StatusUnknown}, nil
+}
+
+// expiryOf returns the height and time of the first block at which evidence
+// of the given height and time is expired.
+func (evpool *Pool) expiryOf(height int64, evTime time.Time) (int64, time.Time) {
+	params := evpool.State().ConsensusParams.Evidence
+	return height + params.MaxAgeNumBlocks + 1, evTime.Add(params.MaxAgeDuration).Add(time.Nanosecond)
+}
+
+func (evpool *Pool) statusOf(status string, height int64, evTime time.Time) Status {
+	expiryHeight, expiryTime := evpool.expiryOf(height, evTime)
+	return Status{
+		Status:       status,
+		Height:       height,
+		ExpiryHeight: expiryHeight,
+		ExpiryTime:   expiryTime,
+	}
+}
+
+// findEvidence returns the key and value of the evidence with the given hash
+// and prefix, or nil if there's none. As the keys are ordered by height, all
+// the evidence of the prefix is scanned.
+func (evpool *Pool) findEvidence(prefix byte, hash []byte) (key, value []byte, err error) {
+	iter, err := dbm.IteratePrefix(evpool.evidenceStore, []byte{prefix})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		_, evHash, err := parseKey(iter.Key())
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(evHash, hash) {
+			return append([]byte(nil), iter.Key()...), append([]byte(nil), iter.Value()...), nil
+		}
+	}
+	return nil, nil, iter.Error()
+}
@@ -27,7 +27,7 @@ func (evpool *Pool) verify(evidence types.Evidence) error {
 	// verify the time of the evidence
 	blockMeta := evpool.blockStore.LoadBlockMeta(evidence.Height())
 	if blockMeta == nil {
-		return fmt.Errorf("don't have header #%d", evidence.Height())
+		return ErrUnknownHeight{Height: evidence.Height()}
 	}
 	evTime := blockMeta.Header.Time
 	if evidence.Time() != evTime {
@@ -38,13 +38,12 @@ func (evpool *Pool) verify(evidence types.Evidence) error {
 
 	// check that the evidence hasn't expired
 	if ageDuration > evidenceParams.MaxAgeDuration && ageNumBlocks > evidenceParams.MaxAgeNumBlocks {
-		return fmt.Errorf(
-			"evidence from height %d (created at: %v) is too old; min height is %d and evidence can not be older than %v",
-			evidence.Height(),
-			evTime,
-			height-evidenceParams.MaxAgeNumBlocks,
-			state.LastBlockTime.Add(evidenceParams.MaxAgeDuration),
-		)
+		return ErrEvidenceExpired{
+			Height:    evidence.Height(),
+			Time:      evTime,
+			MinHeight: height - evidenceParams.MaxAgeNumBlocks,
+			MinTime:   state.LastBlockTime.Add(-evidenceParams.MaxAgeDuration),
+		}
 	}
 
 	// apply the evidence-specific verification logic
@@ -61,7 +60,7 @@ func (evpool *Pool) verify(evidence types.Evidence) error {
 		if err != nil {
 			return err
 		}
-		commonVals, err := evpool.stateDB.LoadValidators(evidence.Height())
+		commonVals, err := evpool.commonValidators(ev, commonHeader)
 		if err != nil {
 			return err
 		}
@@ -100,8 +99,33 @@ func (evpool *Pool) verify(evidence types.Evidence) error {
 
 }
 
+// commonValidators returns the validator set at the common height of the light client attack. It is loaded
+// from the state or, if the state at that height was pruned, taken from the evidence when the protocol allows
+// it: in an equivocation or an amnesia attack the conflicting block is at the common height and was signed by
+// the trusted validators, which the validators hash of the common header identifies.
+func (evpool *Pool) commonValidators(
+	ev *types.LightClientAttackEvidence,
+	commonHeader *types.SignedHeader,
+) (*types.ValidatorSet, error) {
+	vals, err := evpool.stateDB.LoadValidators(ev.CommonHeight)
+	if err == nil {
+		return vals, nil
+	}
+
+	if embedded := ev.ConflictingBlock.ValidatorSet; embedded != nil &&
+		ev.ConflictingBlock.Height == ev.CommonHeight &&
+		bytes.Equal(embedded.Hash(), commonHeader.ValidatorsHash) {
+		evpool.logger.Debug("Using the validators of the evidence at the common height",
+			"height", ev.CommonHeight, "err", err)
+		return embedded, nil
+	}
+
+	return nil, fmt.Errorf("can't load the validators at the common height %d: %w", ev.CommonHeight, err)
+}
+
 // VerifyLightClientAttack verifies LightClientAttackEvidence against the state of the full node. This involves
 // the following checks:
+//   - the common validators are the ones of the common header
 //   - the common header from the full node has at least 1/3 voting power which is also present in
 //     the conflicting header's commit
 //   - 2/3+ of the conflicting validator set correctly signed the conflicting block
@@ -113,6 +137,14 @@ func (evpool *Pool) verify(evidence types.Evidence) error {
 //	must check that the evidence has not expired (i.e. is outside the maximum age threshold)
 func VerifyLightClientAttack(e *types.LightClientAttackEvidence, commonHeader, trustedHeader *types.SignedHeader,
 	commonVals *types.ValidatorSet, now time.Time, trustPeriod time.Duration) error {
+	if commonVals == nil {
+		return errors.New("missing the validators at the common height")
+	}
+	if valsHash := commonVals.Hash(); !bytes.Equal(valsHash, commonHeader.ValidatorsHash) {
+		return fmt.Errorf("common validators don't match the validators hash of the common header (%X != %X)",
+			valsHash, commonHeader.ValidatorsHash)
+	}
+
 	// In the case of lunatic attack there will be a different commonHeader height. Therefore the node perform a single
 	// verification jump between the common header and the conflicting one
 	if commonHeader.Height != e.ConflictingBlock.Height {
@@ -163,7 +195,7 @@ func VerifyLightClientAttack(e *types.LightClientAttackEvidence, commonHeader, t
 func VerifyDuplicateVote(e *types.DuplicateVoteEvidence, chainID string, valSet *types.ValidatorSet) error {
 	_, val := valSet.GetByAddress(e.VoteA.ValidatorAddress)
 	if val == nil {
-		return fmt.Errorf("address %X was not a validator at height %d", e.VoteA.ValidatorAddress, e.Height())
+		return ErrUnknownValidator{Address: e.VoteA.ValidatorAddress, Height: e.Height()}
 	}
 	pubKey := val.PubKey
 
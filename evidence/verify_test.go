@@ -2,6 +2,7 @@ package evidence_test
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 	"time"
 
@@ -350,6 +351,172 @@ func TestVerifyLightClientAttack_Amnesia(t *testing.T) {
 	assert.Equal(t, 1, len(pendingEvs))
 }
 
+func TestVerify_LunaticAttackAcrossValidatorRotation(t *testing.T) {
+	const (
+		height       int64 = 10
+		commonHeight int64 = 4
+	)
+	attackTime := defaultEvidenceTime.Add(1 * time.Hour)
+	commonVals, commonPrivVals := types.RandValidatorSet(4, defaultVotingPower)
+
+	// by the height of the attack, the first two validators of the common set had their power raised and two
+	// new validators joined: they all sign the conflicting block
+	byzVals := make([]*types.Validator, 2)
+	for i, val := range commonVals.Validators[:2] {
+		byzVals[i] = types.NewValidator(val.PubKey, 3*defaultVotingPower)
+	}
+	newVals, newPrivVals := types.RandValidatorSet(2, defaultVotingPower)
+	conflictingVals := newVals.Copy()
+	require.NoError(t, conflictingVals.UpdateWithChangeSet(byzVals))
+	conflictingPrivVals := orderPrivValsByValSet(t, conflictingVals, append(newPrivVals, commonPrivVals[:2]...))
+
+	conflictingHeader := makeHeaderRandom(height)
+	conflictingHeader.Time = attackTime
+	conflictingHeader.ValidatorsHash = conflictingVals.Hash()
+	blockID := makeBlockID(conflictingHeader.Hash(), 1000, []byte("partshash"))
+	voteSet := types.NewVoteSet(evidenceChainID, height, 1, cmtproto.SignedMsgType(2), conflictingVals)
+	commit, err := types.MakeCommit(blockID, height, 1, voteSet, conflictingPrivVals, defaultEvidenceTime)
+	require.NoError(t, err)
+
+	commonHeader := makeHeaderRandom(commonHeight)
+	commonHeader.ValidatorsHash = commonVals.Hash()
+	trustedVals, trustedPrivVals := types.RandValidatorSet(4, defaultVotingPower)
+	trustedHeader := makeHeaderRandom(height)
+	trustedHeader.ValidatorsHash = trustedVals.Hash()
+	trustedBlockID := makeBlockID(trustedHeader.Hash(), 1000, []byte("partshash"))
+	trustedVoteSet := types.NewVoteSet(evidenceChainID, height, 1, cmtproto.SignedMsgType(2), trustedVals)
+	trustedCommit, err := types.MakeCommit(trustedBlockID, height, 1, trustedVoteSet, trustedPrivVals, defaultEvidenceTime)
+	require.NoError(t, err)
+	trusted := &types.SignedHeader{Header: trustedHeader, Commit: trustedCommit}
+
+	ev := &types.LightClientAttackEvidence{
+		ConflictingBlock: &types.LightBlock{
+			SignedHeader: &types.SignedHeader{
+				Header: conflictingHeader,
+				Commit: commit,
+			},
+			ValidatorSet: conflictingVals,
+		},
+		CommonHeight:     commonHeight,
+		TotalVotingPower: commonVals.TotalVotingPower(),
+		Timestamp:        defaultEvidenceTime,
+	}
+	require.NoError(t, ev.ValidateBasic())
+
+	// the byzantine validators are the common validators which signed the conflicting block, with their
+	// power at the common height
+	ev.ByzantineValidators = ev.GetByzantineValidators(commonVals, trusted)
+	require.Len(t, ev.ByzantineValidators, 2)
+	for _, val := range ev.ByzantineValidators {
+		_, commonVal := commonVals.GetByAddress(val.Address)
+		require.NotNil(t, commonVal)
+		assert.EqualValues(t, defaultVotingPower, val.VotingPower)
+	}
+
+	state := sm.State{
+		LastBlockTime:   defaultEvidenceTime.Add(2 * time.Hour),
+		LastBlockHeight: height + 1,
+		ConsensusParams: *types.DefaultConsensusParams(),
+	}
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("LoadBlockMeta", commonHeight).Return(&types.BlockMeta{Header: *commonHeader})
+	blockStore.On("LoadBlockMeta", height).Return(&types.BlockMeta{Header: *trustedHeader})
+	blockStore.On("LoadBlockCommit", commonHeight).Return(&types.Commit{})
+	blockStore.On("LoadBlockCommit", height).Return(trustedCommit)
+	stateStore := &smmocks.Store{}
+	stateStore.On("LoadValidators", commonHeight).Return(commonVals, nil)
+	stateStore.On("Load").Return(state, nil)
+
+	pool, err := evidence.NewPool(dbm.NewMemDB(), stateStore, blockStore)
+	require.NoError(t, err)
+	assert.NoError(t, pool.CheckEvidence(types.EvidenceList{ev}))
+
+	// attributing the attack with the powers of the conflicting validator set should fail
+	rotatedEv := *ev
+	rotatedEv.ByzantineValidators = []*types.Validator{}
+	for _, val := range ev.ByzantineValidators {
+		_, conflictingVal := conflictingVals.GetByAddress(val.Address)
+		rotatedEv.ByzantineValidators = append(rotatedEv.ByzantineValidators, conflictingVal)
+	}
+	pool, err = evidence.NewPool(dbm.NewMemDB(), stateStore, blockStore)
+	require.NoError(t, err)
+	assert.ErrorContains(t, pool.CheckEvidence(types.EvidenceList{&rotatedEv}), "byzantine validator power")
+
+	// the validators at the common height must be the ones of the common header
+	err = evidence.VerifyLightClientAttack(ev, &types.SignedHeader{Header: commonHeader}, trusted, trustedVals,
+		state.LastBlockTime, 3*time.Hour)
+	assert.ErrorContains(t, err, "common validators don't match")
+
+	// the validators of the evidence can't stand in for the ones at the common height, if they were pruned
+	prunedStateStore := &smmocks.Store{}
+	prunedStateStore.On("LoadValidators", commonHeight).Return(nil, errors.New("value retrieved from db is empty"))
+	prunedStateStore.On("Load").Return(state, nil)
+	pool, err = evidence.NewPool(dbm.NewMemDB(), prunedStateStore, blockStore)
+	require.NoError(t, err)
+	assert.ErrorContains(t, pool.CheckEvidence(types.EvidenceList{ev}), "validators at the common height")
+}
+
+func TestVerify_EquivocationAndAmnesiaWithPrunedValidators(t *testing.T) {
+	const height int64 = 10
+
+	equivocation, equivocationTrusted := makeEquivocationEvidence(t, height, 5, 4)
+	amnesia, amnesiaTrusted := makeAmnesiaEvidence(t, height, 5)
+
+	testCases := []struct {
+		name        string
+		ev          *types.LightClientAttackEvidence
+		trusted     *types.LightBlock
+		byzVals     int
+		tamperVals  bool
+		expectedErr string
+	}{
+		{"equivocation", equivocation, equivocationTrusted, 4, false, ""},
+		{"amnesia", amnesia, amnesiaTrusted, 0, false, ""},
+		{"equivocation with other validators", equivocation, equivocationTrusted, 4, true, "validators at the common height"},
+		{"amnesia with other validators", amnesia, amnesiaTrusted, 0, true, "validators at the common height"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Len(t, tc.ev.ByzantineValidators, tc.byzVals)
+			for _, val := range tc.ev.ByzantineValidators {
+				_, trustedVal := tc.trusted.ValidatorSet.GetByAddress(val.Address)
+				assert.Equal(t, trustedVal, val)
+			}
+
+			state := sm.State{
+				LastBlockTime:   defaultEvidenceTime.Add(1 * time.Minute),
+				LastBlockHeight: height + 1,
+				ConsensusParams: *types.DefaultConsensusParams(),
+			}
+			// the state at the height of the attack was pruned, so the validators are taken from the
+			// evidence once they match the trusted header
+			stateStore := &smmocks.Store{}
+			stateStore.On("LoadValidators", height).Return(nil, errors.New("value retrieved from db is empty"))
+			stateStore.On("Load").Return(state, nil)
+			header := *tc.trusted.Header
+			if tc.tamperVals {
+				header.ValidatorsHash = crypto.CRandBytes(tmhash.Size)
+			}
+			blockStore := &mocks.BlockStore{}
+			blockStore.On("LoadBlockMeta", height).Return(&types.BlockMeta{Header: header})
+			blockStore.On("LoadBlockCommit", height).Return(tc.trusted.Commit)
+
+			pool, err := evidence.NewPool(dbm.NewMemDB(), stateStore, blockStore)
+			require.NoError(t, err)
+			err = pool.CheckEvidence(types.EvidenceList{tc.ev})
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			pendingEvs, _ := pool.PendingEvidence(state.ConsensusParams.Evidence.MaxBytes)
+			assert.Len(t, pendingEvs, 1)
+		})
+	}
+}
+
 type voteData struct {
 	vote1 *types.Vote
 	vote2 *types.Vote
@@ -472,6 +639,7 @@ func makeLunaticEvidence(
 
 	commonHeader := makeHeaderRandom(commonHeight)
 	commonHeader.Time = commonTime
+	commonHeader.ValidatorsHash = commonValSet.Hash()
 	trustedHeader := makeHeaderRandom(height)
 
 	conflictingHeader := makeHeaderRandom(height)
@@ -519,13 +687,68 @@ func makeLunaticEvidence(
 	return ev, trusted, common
 }
 
-// func makeEquivocationEvidence() *types.LightClientAttackEvidence {
+// makeEquivocationEvidence returns evidence of the first byzVals validators of the trusted validator set
+// signing a correctly derived conflicting block, in the round of the trusted commit, and the trusted block.
+func makeEquivocationEvidence(
+	t *testing.T, height int64, totalVals, byzVals int,
+) (ev *types.LightClientAttackEvidence, trusted *types.LightBlock) {
+	return makeConflictingBlockEvidence(t, height, totalVals, byzVals, 1)
+}
 
-// }
+// makeAmnesiaEvidence returns evidence of all the validators of the trusted validator set signing a
+// correctly derived conflicting block, in a round before the one of the trusted commit, and the trusted block.
+func makeAmnesiaEvidence(
+	t *testing.T, height int64, totalVals int,
+) (ev *types.LightClientAttackEvidence, trusted *types.LightBlock) {
+	return makeConflictingBlockEvidence(t, height, totalVals, totalVals, 0)
+}
 
-// func makeAmnesiaEvidence() *types.LightClientAttackEvidence {
+func makeConflictingBlockEvidence(
+	t *testing.T, height int64, totalVals, byzVals int, conflictingRound int32,
+) (ev *types.LightClientAttackEvidence, trusted *types.LightBlock) {
+	vals, privVals := types.RandValidatorSet(totalVals, defaultVotingPower)
 
-// }
+	conflictingHeader := makeHeaderRandom(height)
+	conflictingHeader.ValidatorsHash = vals.Hash()
+	trustedHeader := makeHeaderRandom(height)
+	trustedHeader.ValidatorsHash = conflictingHeader.ValidatorsHash
+	trustedHeader.NextValidatorsHash = conflictingHeader.NextValidatorsHash
+	trustedHeader.ConsensusHash = conflictingHeader.ConsensusHash
+	trustedHeader.AppHash = conflictingHeader.AppHash
+	trustedHeader.LastResultsHash = conflictingHeader.LastResultsHash
+
+	blockID := makeBlockID(conflictingHeader.Hash(), 1000, []byte("partshash"))
+	voteSet := types.NewVoteSet(evidenceChainID, height, conflictingRound, cmtproto.SignedMsgType(2), vals)
+	commit, err := types.MakeCommit(blockID, height, conflictingRound, voteSet, privVals[:byzVals], defaultEvidenceTime)
+	require.NoError(t, err)
+
+	trustedBlockID := makeBlockID(trustedHeader.Hash(), 1000, []byte("partshash"))
+	trustedVoteSet := types.NewVoteSet(evidenceChainID, height, 1, cmtproto.SignedMsgType(2), vals)
+	trustedCommit, err := types.MakeCommit(trustedBlockID, height, 1, trustedVoteSet, privVals, defaultEvidenceTime)
+	require.NoError(t, err)
+	trusted = &types.LightBlock{
+		SignedHeader: &types.SignedHeader{
+			Header: trustedHeader,
+			Commit: trustedCommit,
+		},
+		ValidatorSet: vals,
+	}
+
+	ev = &types.LightClientAttackEvidence{
+		ConflictingBlock: &types.LightBlock{
+			SignedHeader: &types.SignedHeader{
+				Header: conflictingHeader,
+				Commit: commit,
+			},
+			ValidatorSet: vals.Copy(),
+		},
+		CommonHeight:     height,
+		TotalVotingPower: vals.TotalVotingPower(),
+		Timestamp:        defaultEvidenceTime,
+	}
+	ev.ByzantineValidators = ev.GetByzantineValidators(vals, trusted.SignedHeader)
+	return ev, trusted
+}
 
 func makeVote(
 	t *testing.T, val types.PrivValidator, chainID string, valIndex int32, height int64,
@@ -20,6 +20,28 @@ const (
 //
 //	ParseLogLevel("consensus:debug,mempool:debug,*:error", log.NewTMLogger(os.Stdout), "info")
 func ParseLogLevel(lvl string, logger log.Logger, defaultLogLevelValue string) (log.Logger, error) {
+	options, err := ParseLogLevelOptions(lvl, defaultLogLevelValue)
+	if err != nil {
+		return nil, err
+	}
+	return log.NewFilter(logger, options...), nil
+}
+
+// SetLogLevel parses lvl, as ParseLogLevel does, and replaces the options of
+// the running filter with the result. The filter is left unchanged if lvl is
+// invalid.
+func SetLogLevel(filter log.LevelFilter, lvl string, defaultLogLevelValue string) error {
+	options, err := ParseLogLevelOptions(lvl, defaultLogLevelValue)
+	if err != nil {
+		return err
+	}
+	filter.SetOptions(options...)
+	return nil
+}
+
+// ParseLogLevelOptions parses lvl, as ParseLogLevel does, into the options of
+// a filter.
+func ParseLogLevelOptions(lvl string, defaultLogLevelValue string) ([]log.Option, error) {
 	if lvl == "" {
 		return nil, errors.New("empty log level")
 	}
@@ -86,5 +108,5 @@ func ParseLogLevel(lvl string, logger log.Logger, defaultLogLevelValue string) (
 		options = append(options, option)
 	}
 
-	return log.NewFilter(logger, options...), nil
+	return options, nil
 }
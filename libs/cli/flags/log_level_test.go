@@ -92,3 +92,27 @@ func TestParseLogLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestSetLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := cmtflags.ParseLogLevel("info", log.NewTMJSONLoggerNoTS(&buf), defaultLogLevelValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := logger.(log.LevelFilter)
+	mempool := logger.With("module", "mempool")
+
+	if err := cmtflags.SetLogLevel(filter, "mempool:some", defaultLogLevelValue); err == nil {
+		t.Fatal("Expected mempool:some to produce error")
+	}
+	if err := cmtflags.SetLogLevel(filter, "mempool:debug,*:error", defaultLogLevelValue); err != nil {
+		t.Fatal(err)
+	}
+
+	mempool.Debug("Kingpin")
+	logger.Info("Gideon")
+	want := `{"_msg":"Kingpin","level":"debug","module":"mempool"}`
+	if have := strings.TrimSpace(buf.String()); have != want {
+		t.Errorf("\nwant '%s'\nhave '%s'", want, have)
+	}
+}
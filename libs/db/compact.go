@@ -0,0 +1,53 @@
+package db
+
+import (
+	"errors"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// ErrCompactionNotSupported is returned by CompactRange if the database
+// backend does not support manual compaction.
+var ErrCompactionNotSupported = errors.New("database backend does not support compaction")
+
+// Compacter is an optional extension of dbm.DB implemented by databases that
+// support manually compacting a key range, e.g. to reclaim the disk space
+// left behind by deleted keys.
+type Compacter interface {
+	// Compact compacts the key range [start, end). A nil start or end denotes
+	// the beginning or the end of the key space respectively.
+	Compact(start, end []byte) error
+}
+
+// CompactRange compacts the key range [start, end) of the given database. It
+// returns the number of bytes reclaimed if the backend exposes it, or 0
+// otherwise. If the backend neither implements Compacter nor is natively
+// supported, ErrCompactionNotSupported is returned. Databases wrapping another
+// one and exposing it through an Unwrap method are unwrapped first.
+func CompactRange(db dbm.DB, start, end []byte) (int64, error) {
+	switch db := db.(type) {
+	case interface{ Unwrap() dbm.DB }:
+		// decorators, such as the instrumented database, compact the
+		// database they wrap
+		return CompactRange(db.Unwrap(), start, end)
+	case Compacter:
+		return 0, db.Compact(start, end)
+	case *dbm.GoLevelDB:
+		r := util.Range{Start: start, Limit: end}
+		before, err := db.DB().SizeOf([]util.Range{r})
+		if err != nil {
+			return 0, err
+		}
+		if err := db.DB().CompactRange(r); err != nil {
+			return 0, err
+		}
+		after, err := db.DB().SizeOf([]util.Range{r})
+		if err != nil {
+			return 0, err
+		}
+		return before.Sum() - after.Sum(), nil
+	default:
+		return 0, ErrCompactionNotSupported
+	}
+}
@@ -0,0 +1,38 @@
+// Package db provides the database backends which are implemented in this
+// repository in addition to the ones provided by cometbft-db.
+package db
+
+import (
+	"errors"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// PebbleDBBackend represents pebble (github.com/cockroachdb/pebble)
+//   - pure go
+//   - EXPERIMENTAL
+//   - lower write amplification and fewer compaction stalls than goleveldb
+//     on large stores
+//   - not compatible with the on-disk format of goleveldb
+const PebbleDBBackend dbm.BackendType = "pebbledb"
+
+var (
+	// errKeyEmpty is returned when attempting to use an empty or nil key.
+	errKeyEmpty = errors.New("key cannot be empty")
+
+	// errValueNil is returned when attempting to set a nil value.
+	errValueNil = errors.New("value cannot be nil")
+
+	// errBatchClosed is returned when a closed or written batch is used.
+	errBatchClosed = errors.New("batch has been written or closed")
+)
+
+// NewDB creates a new database of the given backend type, named name, in the
+// directory dir. Backends implemented in this package are created here, all
+// others are delegated to dbm.NewDB.
+func NewDB(name string, backend dbm.BackendType, dir string) (dbm.DB, error) {
+	if backend == PebbleDBBackend {
+		return NewPebbleDB(name, dir)
+	}
+	return dbm.NewDB(name, backend, dir)
+}
@@ -0,0 +1,297 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackends are the backends the conformance suite is run against. The
+// backends of cometbft-db serve as the reference for the expected behavior.
+var testBackends = []dbm.BackendType{
+	dbm.MemDBBackend,
+	dbm.GoLevelDBBackend,
+	PebbleDBBackend,
+}
+
+// forEachBackend runs the given test against a fresh database of every backend.
+func forEachBackend(t *testing.T, test func(t *testing.T, db dbm.DB)) {
+	for _, backend := range testBackends {
+		backend := backend
+		t.Run(string(backend), func(t *testing.T) {
+			db, err := NewDB("test", backend, t.TempDir())
+			require.NoError(t, err)
+			defer db.Close()
+			test(t, db)
+		})
+	}
+}
+
+func TestNewDB(t *testing.T) {
+	db, err := NewDB("test", PebbleDBBackend, t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+	assert.IsType(t, &PebbleDB{}, db)
+
+	_, err = NewDB("test", dbm.BackendType("unknown"), t.TempDir())
+	require.Error(t, err)
+}
+
+func TestBackendGetSetDelete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db dbm.DB) {
+		value, err := db.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.Nil(t, value)
+		ok, err := db.Has([]byte("a"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, db.Set([]byte("a"), []byte{0x01}))
+		require.NoError(t, db.SetSync([]byte("b"), []byte{}))
+		value, err = db.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x01}, value)
+		value, err = db.Get([]byte("b"))
+		require.NoError(t, err)
+		assert.Equal(t, []byte{}, value)
+		ok, err = db.Has([]byte("b"))
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		// the returned value is not affected by later writes
+		value, err = db.Get([]byte("a"))
+		require.NoError(t, err)
+		require.NoError(t, db.Set([]byte("a"), []byte{0x02}))
+		assert.Equal(t, []byte{0x01}, value)
+
+		require.NoError(t, db.Delete([]byte("a")))
+		require.NoError(t, db.DeleteSync([]byte("b")))
+		require.NoError(t, db.Delete([]byte("c")))
+		value, err = db.Get([]byte("a"))
+		require.NoError(t, err)
+		assert.Nil(t, value)
+		ok, err = db.Has([]byte("b"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestBackendInvalidArguments(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db dbm.DB) {
+		for _, key := range [][]byte{nil, {}} {
+			_, err := db.Get(key)
+			assert.Error(t, err)
+			_, err = db.Has(key)
+			assert.Error(t, err)
+			assert.Error(t, db.Set(key, []byte{0x01}))
+			assert.Error(t, db.SetSync(key, []byte{0x01}))
+			assert.Error(t, db.Delete(key))
+			assert.Error(t, db.DeleteSync(key))
+		}
+		assert.Error(t, db.Set([]byte("a"), nil))
+		assert.Error(t, db.SetSync([]byte("a"), nil))
+
+		_, err := db.Iterator([]byte{}, nil)
+		assert.Error(t, err)
+		_, err = db.Iterator(nil, []byte{})
+		assert.Error(t, err)
+		_, err = db.ReverseIterator([]byte{}, nil)
+		assert.Error(t, err)
+		_, err = db.ReverseIterator(nil, []byte{})
+		assert.Error(t, err)
+	})
+}
+
+func TestBackendIterators(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db dbm.DB) {
+		for _, k := range []string{"a", "b", "c", "c0", "d", "e"} {
+			require.NoError(t, db.Set([]byte(k), []byte("v"+k)))
+		}
+
+		testCases := []struct {
+			start, end string
+			forward    []string
+		}{
+			{"", "", []string{"a", "b", "c", "c0", "d", "e"}},
+			{"b", "", []string{"b", "c", "c0", "d", "e"}},
+			{"bb", "", []string{"c", "c0", "d", "e"}},
+			{"", "d", []string{"a", "b", "c", "c0"}},
+			{"", "cc", []string{"a", "b", "c", "c0"}},
+			{"b", "d", []string{"b", "c", "c0"}},
+			{"c", "c0", []string{"c"}},
+			{"c0", "c", nil},
+			{"f", "", nil},
+			{"", "a", nil},
+		}
+		for _, tc := range testCases {
+			start, end := optKey(tc.start), optKey(tc.end)
+			name := fmt.Sprintf("[%s,%s)", tc.start, tc.end)
+
+			itr, err := db.Iterator(start, end)
+			require.NoError(t, err)
+			gotStart, gotEnd := itr.Domain()
+			assert.Equal(t, start, gotStart, name)
+			assert.Equal(t, end, gotEnd, name)
+			assert.Equal(t, tc.forward, collect(t, itr), name)
+
+			var reverse []string
+			for i := len(tc.forward) - 1; i >= 0; i-- {
+				reverse = append(reverse, tc.forward[i])
+			}
+			itr, err = db.ReverseIterator(start, end)
+			require.NoError(t, err)
+			assert.Equal(t, reverse, collect(t, itr), name)
+		}
+	})
+}
+
+func TestBackendPrefixIterator(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db dbm.DB) {
+		for _, k := range []string{"a", "ab", "abc", "ac", "b", "ba"} {
+			require.NoError(t, db.Set([]byte(k), []byte("v"+k)))
+		}
+		itr, err := dbm.IteratePrefix(db, []byte("ab"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ab", "abc"}, collect(t, itr))
+
+		itr, err = dbm.IteratePrefix(db, []byte("b"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "ba"}, collect(t, itr))
+
+		// values are returned along with their keys
+		itr, err = dbm.IteratePrefix(db, []byte("a"))
+		require.NoError(t, err)
+		for ; itr.Valid(); itr.Next() {
+			assert.Equal(t, "v"+string(itr.Key()), string(itr.Value()))
+		}
+		require.NoError(t, itr.Close())
+
+		itr, err = dbm.IteratePrefix(db, []byte{0xff})
+		require.NoError(t, err)
+		assert.Empty(t, collect(t, itr))
+	})
+}
+
+func TestBackendIteratorInvalid(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db dbm.DB) {
+		itr, err := db.Iterator(nil, nil)
+		require.NoError(t, err)
+		defer itr.Close()
+		assert.False(t, itr.Valid())
+		assert.Panics(t, func() { itr.Key() })
+		assert.Panics(t, func() { itr.Value() })
+		assert.Panics(t, func() { itr.Next() })
+	})
+}
+
+func TestBackendBatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db dbm.DB) {
+		require.NoError(t, db.Set([]byte("a"), []byte{0x01}))
+
+		batch := db.NewBatch()
+		require.NoError(t, batch.Set([]byte("b"), []byte{0x02}))
+		require.NoError(t, batch.Set([]byte("c"), []byte{0x03}))
+		require.NoError(t, batch.Delete([]byte("a")))
+		assert.Error(t, batch.Set(nil, []byte{0x01}))
+		assert.Error(t, batch.Set([]byte("d"), nil))
+		assert.Error(t, batch.Delete([]byte{}))
+
+		// nothing is visible before the batch is written
+		ok, err := db.Has([]byte("b"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, batch.Write())
+		itr, err := db.Iterator(nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "c"}, collect(t, itr))
+
+		// a written batch can't be reused, but may still be closed
+		assert.Error(t, batch.Set([]byte("d"), []byte{0x04}))
+		assert.Error(t, batch.Write())
+		require.NoError(t, batch.Close())
+		require.NoError(t, batch.Close())
+
+		batch = db.NewBatch()
+		require.NoError(t, batch.Delete([]byte("b")))
+		require.NoError(t, batch.WriteSync())
+		require.NoError(t, batch.Close())
+		ok, err = db.Has([]byte("b"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		// a closed batch is discarded
+		batch = db.NewBatch()
+		require.NoError(t, batch.Set([]byte("e"), []byte{0x05}))
+		require.NoError(t, batch.Close())
+		assert.Error(t, batch.WriteSync())
+		ok, err = db.Has([]byte("e"))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestPebbleDBCompactAndStats(t *testing.T) {
+	db, err := NewPebbleDB("test", t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+
+	// compacting an empty database is a no-op
+	require.NoError(t, db.Compact(nil, nil))
+
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, db.Set([]byte(fmt.Sprintf("key%04d", i)), []byte("value")))
+	}
+	for i := 0; i < 900; i++ {
+		require.NoError(t, db.Delete([]byte(fmt.Sprintf("key%04d", i))))
+	}
+	require.NoError(t, db.Compact(nil, []byte("key0900")))
+	require.NoError(t, db.Compact([]byte("key0500"), nil))
+	require.NoError(t, db.Compact(nil, nil))
+	require.NoError(t, db.Compact([]byte("b"), []byte("a")))
+
+	itr, err := db.Iterator(nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, collect(t, itr), 100)
+
+	stats := db.Stats()
+	assert.NotEmpty(t, stats["pebble.metrics"])
+	assert.Contains(t, stats, "pebble.disk-space-usage")
+}
+
+func TestPebbleDBReopen(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewPebbleDB("test", dir)
+	require.NoError(t, err)
+	require.NoError(t, db.SetSync([]byte("a"), []byte{0x01}))
+	require.NoError(t, db.Close())
+
+	db, err = NewPebbleDB("test", dir)
+	require.NoError(t, err)
+	defer db.Close()
+	value, err := db.Get([]byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01}, value)
+}
+
+func optKey(k string) []byte {
+	if k == "" {
+		return nil
+	}
+	return []byte(k)
+}
+
+// collect returns the keys of the given iterator and closes it.
+func collect(t *testing.T, itr dbm.Iterator) []string {
+	t.Helper()
+	defer itr.Close()
+	var keys []string
+	for ; itr.Valid(); itr.Next() {
+		keys = append(keys, string(itr.Key()))
+	}
+	require.NoError(t, itr.Error())
+	return keys
+}
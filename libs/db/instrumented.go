@@ -0,0 +1,242 @@
+package db
+
+import (
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+type operation int
+
+const (
+	opGet operation = iota
+	opHas
+	opSet
+	opSetSync
+	opDelete
+	opDeleteSync
+	opIterator
+	opReverseIterator
+	opBatchWrite
+	opBatchWriteSync
+	numOperations
+)
+
+var operationNames = [numOperations]string{
+	opGet:             "get",
+	opHas:             "has",
+	opSet:             "set",
+	opSetSync:         "set_sync",
+	opDelete:          "delete",
+	opDeleteSync:      "delete_sync",
+	opIterator:        "iterator",
+	opReverseIterator: "reverse_iterator",
+	opBatchWrite:      "batch_write",
+	opBatchWriteSync:  "batch_write_sync",
+}
+
+// InstrumentedDB is a dbm.DB decorator which records the latency of database
+// operations and the number of bytes read and written in Metrics, labelled by
+// the name of the store.
+//
+// An InstrumentedDB without metrics forwards all calls to the wrapped
+// database without recording anything.
+type InstrumentedDB struct {
+	dbm.DB
+
+	// all metrics are nil if metrics are disabled
+	enabled      bool
+	durations    [numOperations]metrics.Histogram
+	bytesRead    metrics.Counter
+	bytesWritten metrics.Counter
+}
+
+var _ dbm.DB = (*InstrumentedDB)(nil)
+
+// NewInstrumentedDB wraps db, recording its operations in the given metrics
+// under the store name. If metrics is nil, nothing is recorded.
+func NewInstrumentedDB(db dbm.DB, name string, metrics *Metrics) *InstrumentedDB {
+	idb := &InstrumentedDB{DB: db}
+	if metrics == nil {
+		return idb
+	}
+	idb.enabled = true
+	for op := operation(0); op < numOperations; op++ {
+		idb.durations[op] = metrics.OperationDuration.With("store", name, "operation", operationNames[op])
+	}
+	idb.bytesRead = metrics.BytesRead.With("store", name)
+	idb.bytesWritten = metrics.BytesWritten.With("store", name)
+	return idb
+}
+
+// Unwrap returns the wrapped database.
+func (db *InstrumentedDB) Unwrap() dbm.DB {
+	return db.DB
+}
+
+func (db *InstrumentedDB) observe(op operation, start time.Time) {
+	db.durations[op].Observe(time.Since(start).Seconds())
+}
+
+// Get implements dbm.DB.
+func (db *InstrumentedDB) Get(key []byte) ([]byte, error) {
+	if !db.enabled {
+		return db.DB.Get(key)
+	}
+	defer db.observe(opGet, time.Now())
+	value, err := db.DB.Get(key)
+	db.bytesRead.Add(float64(len(value)))
+	return value, err
+}
+
+// Has implements dbm.DB.
+func (db *InstrumentedDB) Has(key []byte) (bool, error) {
+	if !db.enabled {
+		return db.DB.Has(key)
+	}
+	defer db.observe(opHas, time.Now())
+	return db.DB.Has(key)
+}
+
+// Set implements dbm.DB.
+func (db *InstrumentedDB) Set(key []byte, value []byte) error {
+	if !db.enabled {
+		return db.DB.Set(key, value)
+	}
+	defer db.observe(opSet, time.Now())
+	err := db.DB.Set(key, value)
+	if err == nil {
+		db.bytesWritten.Add(float64(len(key) + len(value)))
+	}
+	return err
+}
+
+// SetSync implements dbm.DB.
+func (db *InstrumentedDB) SetSync(key []byte, value []byte) error {
+	if !db.enabled {
+		return db.DB.SetSync(key, value)
+	}
+	defer db.observe(opSetSync, time.Now())
+	err := db.DB.SetSync(key, value)
+	if err == nil {
+		db.bytesWritten.Add(float64(len(key) + len(value)))
+	}
+	return err
+}
+
+// Delete implements dbm.DB.
+func (db *InstrumentedDB) Delete(key []byte) error {
+	if !db.enabled {
+		return db.DB.Delete(key)
+	}
+	defer db.observe(opDelete, time.Now())
+	return db.DB.Delete(key)
+}
+
+// DeleteSync implements dbm.DB.
+func (db *InstrumentedDB) DeleteSync(key []byte) error {
+	if !db.enabled {
+		return db.DB.DeleteSync(key)
+	}
+	defer db.observe(opDeleteSync, time.Now())
+	return db.DB.DeleteSync(key)
+}
+
+// Iterator implements dbm.DB. The latency recorded is the one of creating the
+// iterator, the bytes read are recorded as the iterator is consumed.
+func (db *InstrumentedDB) Iterator(start, end []byte) (dbm.Iterator, error) {
+	if !db.enabled {
+		return db.DB.Iterator(start, end)
+	}
+	defer db.observe(opIterator, time.Now())
+	itr, err := db.DB.Iterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedIterator{Iterator: itr, bytesRead: db.bytesRead}, nil
+}
+
+// ReverseIterator implements dbm.DB. See Iterator.
+func (db *InstrumentedDB) ReverseIterator(start, end []byte) (dbm.Iterator, error) {
+	if !db.enabled {
+		return db.DB.ReverseIterator(start, end)
+	}
+	defer db.observe(opReverseIterator, time.Now())
+	itr, err := db.DB.ReverseIterator(start, end)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedIterator{Iterator: itr, bytesRead: db.bytesRead}, nil
+}
+
+// NewBatch implements dbm.DB.
+// Compact implements Compacter by compacting the wrapped database. It returns
+// ErrCompactionNotSupported if the wrapped database doesn't support it.
+func (db *InstrumentedDB) Compact(start, end []byte) error {
+	_, err := CompactRange(db.DB, start, end)
+	return err
+}
+
+func (db *InstrumentedDB) NewBatch() dbm.Batch {
+	if !db.enabled {
+		return db.DB.NewBatch()
+	}
+	return &instrumentedBatch{Batch: db.DB.NewBatch(), db: db}
+}
+
+type instrumentedIterator struct {
+	dbm.Iterator
+	bytesRead metrics.Counter
+}
+
+// Key implements dbm.Iterator.
+func (itr *instrumentedIterator) Key() []byte {
+	key := itr.Iterator.Key()
+	itr.bytesRead.Add(float64(len(key)))
+	return key
+}
+
+// Value implements dbm.Iterator.
+func (itr *instrumentedIterator) Value() []byte {
+	value := itr.Iterator.Value()
+	itr.bytesRead.Add(float64(len(value)))
+	return value
+}
+
+// instrumentedBatch records the bytes set in the batch once it is written.
+type instrumentedBatch struct {
+	dbm.Batch
+	db      *InstrumentedDB
+	pending int
+}
+
+// Set implements dbm.Batch.
+func (b *instrumentedBatch) Set(key, value []byte) error {
+	err := b.Batch.Set(key, value)
+	if err == nil {
+		b.pending += len(key) + len(value)
+	}
+	return err
+}
+
+// Write implements dbm.Batch.
+func (b *instrumentedBatch) Write() error {
+	defer b.db.observe(opBatchWrite, time.Now())
+	return b.written(b.Batch.Write())
+}
+
+// WriteSync implements dbm.Batch.
+func (b *instrumentedBatch) WriteSync() error {
+	defer b.db.observe(opBatchWriteSync, time.Now())
+	return b.written(b.Batch.WriteSync())
+}
+
+func (b *instrumentedBatch) written(err error) error {
+	if err == nil {
+		b.db.bytesWritten.Add(float64(b.pending))
+		b.pending = 0
+	}
+	return err
+}
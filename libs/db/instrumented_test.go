@@ -0,0 +1,206 @@
+package db
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder implements metrics.Histogram and metrics.Gauge, recording the
+// number of observations and the last value by label values. Views created
+// by With share the recorded values.
+type recorder struct {
+	mtx    *sync.Mutex
+	counts map[string]int
+	values map[string]float64
+	lvs    []string
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		mtx:    &sync.Mutex{},
+		counts: make(map[string]int),
+		values: make(map[string]float64),
+	}
+}
+
+func (r *recorder) with(labelValues ...string) *recorder {
+	return &recorder{
+		mtx:    r.mtx,
+		counts: r.counts,
+		values: r.values,
+		lvs:    append(append([]string{}, r.lvs...), labelValues...),
+	}
+}
+
+func (r *recorder) record(value float64) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	key := strings.Join(r.lvs, ",")
+	r.counts[key]++
+	r.values[key] = value
+}
+
+func (r *recorder) count(labelValues ...string) int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.counts[strings.Join(labelValues, ",")]
+}
+
+func (r *recorder) value(labelValues ...string) float64 {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.values[strings.Join(labelValues, ",")]
+}
+
+type recordingHistogram struct{ *recorder }
+
+func (h recordingHistogram) With(labelValues ...string) metrics.Histogram {
+	return recordingHistogram{h.with(labelValues...)}
+}
+
+func (h recordingHistogram) Observe(value float64) { h.record(value) }
+
+type recordingGauge struct{ *recorder }
+
+func (g recordingGauge) With(labelValues ...string) metrics.Gauge {
+	return recordingGauge{g.with(labelValues...)}
+}
+
+func (g recordingGauge) Set(value float64) { g.record(value) }
+
+func (g recordingGauge) Add(delta float64) { g.record(g.value(g.lvs...) + delta) }
+
+func TestInstrumentedDB(t *testing.T) {
+	durations := newRecorder()
+	metrics := NopMetrics()
+	metrics.OperationDuration = recordingHistogram{durations}
+	metrics.BytesRead = generic.NewCounter("bytes_read")
+	metrics.BytesWritten = generic.NewCounter("bytes_written")
+
+	db := NewInstrumentedDB(dbm.NewMemDB(), "test", metrics)
+	bytesRead := db.bytesRead.(*generic.Counter)
+	bytesWritten := db.bytesWritten.(*generic.Counter)
+
+	require.NoError(t, db.Set([]byte("a"), []byte("12345")))
+	require.NoError(t, db.SetSync([]byte("b"), []byte("123")))
+	assert.EqualValues(t, 10, bytesWritten.Value())
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "set"))
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "set_sync"))
+
+	value, err := db.Get([]byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("12345"), value)
+	_, err = db.Get([]byte("missing"))
+	require.NoError(t, err)
+	ok, err := db.Has([]byte("b"))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.EqualValues(t, 5, bytesRead.Value())
+	assert.Equal(t, 2, durations.count("store", "test", "operation", "get"))
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "has"))
+
+	// the bytes read by iterators are recorded as they are consumed
+	itr, err := db.Iterator(nil, nil)
+	require.NoError(t, err)
+	for ; itr.Valid(); itr.Next() {
+		itr.Key()
+		itr.Value()
+	}
+	require.NoError(t, itr.Close())
+	assert.EqualValues(t, 5+10, bytesRead.Value())
+	itr, err = db.ReverseIterator(nil, []byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), itr.Key())
+	require.NoError(t, itr.Close())
+	assert.EqualValues(t, 5+10+1, bytesRead.Value())
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "iterator"))
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "reverse_iterator"))
+
+	// the bytes of a batch are recorded once it was written
+	batch := db.NewBatch()
+	require.NoError(t, batch.Set([]byte("c"), []byte("1")))
+	require.NoError(t, batch.Delete([]byte("a")))
+	assert.EqualValues(t, 10, bytesWritten.Value())
+	require.NoError(t, batch.WriteSync())
+	require.NoError(t, batch.Close())
+	assert.EqualValues(t, 12, bytesWritten.Value())
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "batch_write_sync"))
+
+	// a batch which is discarded is not recorded
+	batch = db.NewBatch()
+	require.NoError(t, batch.Set([]byte("d"), []byte("1")))
+	require.NoError(t, batch.Close())
+	assert.EqualValues(t, 12, bytesWritten.Value())
+
+	require.NoError(t, db.Delete([]byte("b")))
+	require.NoError(t, db.DeleteSync([]byte("c")))
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "delete"))
+	assert.Equal(t, 1, durations.count("store", "test", "operation", "delete_sync"))
+}
+
+func TestInstrumentedDBDisabled(t *testing.T) {
+	memDB := dbm.NewMemDB()
+	db := NewInstrumentedDB(memDB, "test", nil)
+	assert.Same(t, memDB, db.Unwrap())
+
+	require.NoError(t, db.Set([]byte("a"), []byte("1")))
+	value, err := db.Get([]byte("a"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	// without metrics, iterators and batches of the wrapped database are
+	// returned as is
+	itr, err := db.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer itr.Close()
+	_, ok := itr.(*instrumentedIterator)
+	assert.False(t, ok)
+	_, ok = db.NewBatch().(*instrumentedBatch)
+	assert.False(t, ok)
+}
+
+func TestInstrumentedDBCompact(t *testing.T) {
+	db, err := dbm.NewGoLevelDB("test", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// the wrapped database is compacted
+	_, err = CompactRange(NewInstrumentedDB(db, "test", NopMetrics()), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, NewInstrumentedDB(db, "test", nil).Compact(nil, nil))
+
+	_, err = CompactRange(NewInstrumentedDB(dbm.NewMemDB(), "test", nil), nil, nil)
+	require.ErrorIs(t, err, ErrCompactionNotSupported)
+	err = NewInstrumentedDB(dbm.NewMemDB(), "test", nil).Compact(nil, nil)
+	require.ErrorIs(t, err, ErrCompactionNotSupported)
+}
+
+func TestSizeMonitor(t *testing.T) {
+	dir := t.TempDir()
+	sizes := newRecorder()
+	metrics := NopMetrics()
+	metrics.DiskSize = recordingGauge{sizes}
+
+	db, err := NewDB("test", dbm.GoLevelDBBackend, dir)
+	require.NoError(t, err)
+	defer db.Close()
+	for i := 0; i < 100; i++ {
+		require.NoError(t, db.SetSync([]byte{byte(i)}, make([]byte, 1000)))
+	}
+
+	monitor := NewSizeMonitor(dir, metrics)
+	monitor.Add("test")
+	monitor.Add("missing")
+	monitor.record()
+
+	assert.Greater(t, sizes.value("store", "test"), float64(0))
+	assert.Equal(t, 1, sizes.count("store", "missing"))
+	assert.Zero(t, sizes.value("store", "missing"))
+}
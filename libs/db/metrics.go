@@ -0,0 +1,77 @@
+package db
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "db"
+)
+
+// Metrics contains metrics exposed by this package. All metrics are labelled
+// by the name of the store, e.g. "blockstore" or "state".
+type Metrics struct {
+	// Latency of database operations in seconds, labelled by operation.
+	OperationDuration metrics.Histogram
+	// Number of bytes of keys and values read from the database.
+	BytesRead metrics.Counter
+	// Number of bytes of keys and values written to the database.
+	BytesWritten metrics.Counter
+	// Size of the database directory on disk in bytes.
+	DiskSize metrics.Gauge
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	// the store, and operation, labels are bound by InstrumentedDB
+	storeLabels := append(labels[:len(labels):len(labels)], "store")
+	operationLabels := append(storeLabels[:len(storeLabels):len(storeLabels)], "operation")
+	return &Metrics{
+		OperationDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of database operations in seconds, labelled by operation.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.00001, 4, 10),
+		}, operationLabels).With(labelsAndValues...),
+		BytesRead: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bytes_read",
+			Help:      "Number of bytes of keys and values read from the database.",
+		}, storeLabels).With(labelsAndValues...),
+		BytesWritten: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bytes_written",
+			Help:      "Number of bytes of keys and values written to the database.",
+		}, storeLabels).With(labelsAndValues...),
+		DiskSize: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "disk_size_bytes",
+			Help:      "Size of the database directory on disk in bytes.",
+		}, storeLabels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		OperationDuration: discard.NewHistogram(),
+		BytesRead:         discard.NewCounter(),
+		BytesWritten:      discard.NewCounter(),
+		DiskSize:          discard.NewGauge(),
+	}
+}
@@ -0,0 +1,220 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cockroachdb/pebble"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// PebbleDB is a dbm.DB backed by pebble.
+//
+// Caveats:
+//   - The on-disk format is pebble's own and cannot be read by goleveldb (or
+//     vice versa). Switching db_backend on an existing node requires starting
+//     from an empty data directory, e.g. by state syncing.
+//   - Pebble keeps memtables and a block cache in memory. Besides the block
+//     cache (pebbleCacheSize), each open database may use up to two memtables
+//     of pebbleMemTableSize each before writes are stalled, so the memory
+//     footprint of a node grows with the number of databases it opens.
+//   - Values returned by Get and by iterators are copies, so they remain valid
+//     after the iterator has moved on or been closed.
+type PebbleDB struct {
+	db *pebble.DB
+}
+
+var _ dbm.DB = (*PebbleDB)(nil)
+
+const (
+	pebbleCacheSize    = 64 << 20 // 64 MB
+	pebbleMemTableSize = 32 << 20 // 32 MB
+)
+
+// NewPebbleDB opens, or creates, the pebble database named name in the
+// directory dir.
+func NewPebbleDB(name string, dir string) (*PebbleDB, error) {
+	cache := pebble.NewCache(pebbleCacheSize)
+	defer cache.Unref()
+	opts := &pebble.Options{
+		Cache:        cache,
+		MemTableSize: pebbleMemTableSize,
+	}
+	return NewPebbleDBWithOpts(name, dir, opts)
+}
+
+// NewPebbleDBWithOpts opens, or creates, the pebble database named name in the
+// directory dir with the given options.
+func NewPebbleDBWithOpts(name string, dir string, opts *pebble.Options) (*PebbleDB, error) {
+	dbPath := filepath.Join(dir, name+".db")
+	db, err := pebble.Open(dbPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleDB{db: db}, nil
+}
+
+// DB returns the underlying pebble database.
+func (db *PebbleDB) DB() *pebble.DB {
+	return db.db
+}
+
+// Get implements dbm.DB.
+func (db *PebbleDB) Get(key []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, errKeyEmpty
+	}
+	res, closer, err := db.db.Get(key)
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer closer.Close()
+	return cp(res), nil
+}
+
+// Has implements dbm.DB.
+func (db *PebbleDB) Has(key []byte) (bool, error) {
+	bz, err := db.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return bz != nil, nil
+}
+
+// Set implements dbm.DB.
+func (db *PebbleDB) Set(key []byte, value []byte) error {
+	return db.set(key, value, pebble.NoSync)
+}
+
+// SetSync implements dbm.DB.
+func (db *PebbleDB) SetSync(key []byte, value []byte) error {
+	return db.set(key, value, pebble.Sync)
+}
+
+func (db *PebbleDB) set(key []byte, value []byte, opts *pebble.WriteOptions) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+	return db.db.Set(key, value, opts)
+}
+
+// Delete implements dbm.DB.
+func (db *PebbleDB) Delete(key []byte) error {
+	return db.delete(key, pebble.NoSync)
+}
+
+// DeleteSync implements dbm.DB.
+func (db *PebbleDB) DeleteSync(key []byte) error {
+	return db.delete(key, pebble.Sync)
+}
+
+func (db *PebbleDB) delete(key []byte, opts *pebble.WriteOptions) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	return db.db.Delete(key, opts)
+}
+
+// Close implements dbm.DB.
+func (db *PebbleDB) Close() error {
+	return db.db.Close()
+}
+
+// Print implements dbm.DB.
+func (db *PebbleDB) Print() error {
+	fmt.Printf("%v\n", db.db.Metrics())
+	itr, err := db.Iterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", itr.Key(), itr.Value())
+	}
+	return itr.Error()
+}
+
+// Stats implements dbm.DB.
+func (db *PebbleDB) Stats() map[string]string {
+	m := db.db.Metrics()
+	return map[string]string{
+		"pebble.metrics":          m.String(),
+		"pebble.disk-space-usage": fmt.Sprint(m.DiskSpaceUsage()),
+		"pebble.compactions":      fmt.Sprint(m.Compact.Count),
+		"pebble.read-amp":         fmt.Sprint(m.ReadAmp()),
+	}
+}
+
+// Compact compacts the key range [start, end). A nil start or end denotes the
+// beginning or the end of the key space respectively.
+func (db *PebbleDB) Compact(start, end []byte) error {
+	// pebble requires explicit bounds, so resolve open ones to the first key
+	// and past the last key of the database
+	if start == nil || end == nil {
+		itr, err := db.db.NewIter(nil)
+		if err != nil {
+			return err
+		}
+		if start == nil && itr.First() {
+			start = cp(itr.Key())
+		}
+		if end == nil && itr.Last() {
+			end = append(cp(itr.Key()), 0)
+		}
+		if err := itr.Close(); err != nil {
+			return err
+		}
+		if start == nil || end == nil {
+			// the database is empty
+			return nil
+		}
+	}
+	if bytes.Compare(start, end) >= 0 {
+		return nil
+	}
+	return db.db.Compact(start, end, true)
+}
+
+// NewBatch implements dbm.DB.
+func (db *PebbleDB) NewBatch() dbm.Batch {
+	return newPebbleDBBatch(db)
+}
+
+// Iterator implements dbm.DB.
+func (db *PebbleDB) Iterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, false)
+}
+
+// ReverseIterator implements dbm.DB.
+func (db *PebbleDB) ReverseIterator(start, end []byte) (dbm.Iterator, error) {
+	return db.newIterator(start, end, true)
+}
+
+func (db *PebbleDB) newIterator(start, end []byte, isReverse bool) (dbm.Iterator, error) {
+	if (start != nil && len(start) == 0) || (end != nil && len(end) == 0) {
+		return nil, errKeyEmpty
+	}
+	itr, err := db.db.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: end})
+	if err != nil {
+		return nil, err
+	}
+	return newPebbleDBIterator(itr, start, end, isReverse), nil
+}
+
+func cp(bz []byte) []byte {
+	if bz == nil {
+		return nil
+	}
+	ret := make([]byte, len(bz))
+	copy(ret, bz)
+	return ret
+}
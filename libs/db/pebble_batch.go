@@ -0,0 +1,75 @@
+package db
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+type pebbleDBBatch struct {
+	batch *pebble.Batch
+}
+
+var _ dbm.Batch = (*pebbleDBBatch)(nil)
+
+func newPebbleDBBatch(db *PebbleDB) *pebbleDBBatch {
+	return &pebbleDBBatch{
+		batch: db.db.NewBatch(),
+	}
+}
+
+// Set implements dbm.Batch.
+func (b *pebbleDBBatch) Set(key, value []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if value == nil {
+		return errValueNil
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+	return b.batch.Set(key, value, nil)
+}
+
+// Delete implements dbm.Batch.
+func (b *pebbleDBBatch) Delete(key []byte) error {
+	if len(key) == 0 {
+		return errKeyEmpty
+	}
+	if b.batch == nil {
+		return errBatchClosed
+	}
+	return b.batch.Delete(key, nil)
+}
+
+// Write implements dbm.Batch.
+func (b *pebbleDBBatch) Write() error {
+	return b.write(pebble.NoSync)
+}
+
+// WriteSync implements dbm.Batch.
+func (b *pebbleDBBatch) WriteSync() error {
+	return b.write(pebble.Sync)
+}
+
+func (b *pebbleDBBatch) write(opts *pebble.WriteOptions) error {
+	if b.batch == nil {
+		return errBatchClosed
+	}
+	if err := b.batch.Commit(opts); err != nil {
+		return err
+	}
+	// Make sure batch cannot be used afterwards. Callers should still call Close(), for errors.
+	return b.Close()
+}
+
+// Close implements dbm.Batch.
+func (b *pebbleDBBatch) Close() error {
+	if b.batch != nil {
+		err := b.batch.Close()
+		b.batch = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// pebbleDBIterator is a dbm.Iterator over a pebble iterator. The bounds of
+// the domain are enforced by pebble through the iterator options.
+type pebbleDBIterator struct {
+	source    *pebble.Iterator
+	start     []byte
+	end       []byte
+	isReverse bool
+	isInvalid bool
+}
+
+var _ dbm.Iterator = (*pebbleDBIterator)(nil)
+
+func newPebbleDBIterator(source *pebble.Iterator, start, end []byte, isReverse bool) *pebbleDBIterator {
+	if isReverse {
+		source.Last()
+	} else {
+		source.First()
+	}
+	return &pebbleDBIterator{
+		source:    source,
+		start:     start,
+		end:       end,
+		isReverse: isReverse,
+	}
+}
+
+// Domain implements dbm.Iterator.
+func (itr *pebbleDBIterator) Domain() ([]byte, []byte) {
+	return itr.start, itr.end
+}
+
+// Valid implements dbm.Iterator.
+func (itr *pebbleDBIterator) Valid() bool {
+	// Once invalid, forever invalid.
+	if itr.isInvalid {
+		return false
+	}
+	if itr.source.Error() != nil || !itr.source.Valid() {
+		itr.isInvalid = true
+		return false
+	}
+	return true
+}
+
+// Key implements dbm.Iterator.
+func (itr *pebbleDBIterator) Key() []byte {
+	itr.assertIsValid()
+	return cp(itr.source.Key())
+}
+
+// Value implements dbm.Iterator.
+func (itr *pebbleDBIterator) Value() []byte {
+	itr.assertIsValid()
+	return cp(itr.source.Value())
+}
+
+// Next implements dbm.Iterator.
+func (itr *pebbleDBIterator) Next() {
+	itr.assertIsValid()
+	if itr.isReverse {
+		itr.source.Prev()
+	} else {
+		itr.source.Next()
+	}
+}
+
+// Error implements dbm.Iterator.
+func (itr *pebbleDBIterator) Error() error {
+	return itr.source.Error()
+}
+
+// Close implements dbm.Iterator.
+func (itr *pebbleDBIterator) Close() error {
+	return itr.source.Close()
+}
+
+func (itr *pebbleDBIterator) assertIsValid() {
+	if !itr.Valid() {
+		panic("iterator is invalid")
+	}
+}
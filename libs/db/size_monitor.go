@@ -0,0 +1,112 @@
+package db
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/service"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+const (
+	// defaultSizeMonitorInterval is how often the size of the databases is
+	// measured.
+	defaultSizeMonitorInterval = time.Minute
+)
+
+// SizeMonitor is a service that periodically records the on-disk size of the
+// directories of the databases added to it in the DiskSize metric.
+type SizeMonitor struct {
+	service.BaseService
+
+	dir      string
+	metrics  *Metrics
+	interval time.Duration
+
+	mtx   cmtsync.Mutex
+	names []string
+}
+
+// NewSizeMonitor creates a new SizeMonitor for the databases stored in dir.
+func NewSizeMonitor(dir string, metrics *Metrics) *SizeMonitor {
+	m := &SizeMonitor{
+		dir:      dir,
+		metrics:  metrics,
+		interval: defaultSizeMonitorInterval,
+	}
+	m.BaseService = *service.NewBaseService(nil, "DBSizeMonitor", m)
+	return m
+}
+
+// Add adds the database with the given name to the monitored ones.
+func (m *SizeMonitor) Add(name string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.names = append(m.names, name)
+}
+
+// OnStart implements service.Service by spawning the monitoring routine.
+func (m *SizeMonitor) OnStart() error {
+	go m.monitorRoutine()
+	return nil
+}
+
+func (m *SizeMonitor) monitorRoutine() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.record()
+		select {
+		case <-m.Quit():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// record measures the size of every monitored database and sets the gauge.
+func (m *SizeMonitor) record() {
+	m.mtx.Lock()
+	names := m.names
+	m.mtx.Unlock()
+
+	for _, name := range names {
+		size, err := dirSize(filepath.Join(m.dir, name+".db"))
+		if err != nil {
+			m.Logger.Error("failed to measure database size", "db", name, "err", err)
+			continue
+		}
+		m.metrics.DiskSize.With("store", name).Set(float64(size))
+	}
+}
+
+// dirSize returns the total size of the regular files in dir. A missing
+// directory, e.g. of an in-memory database, has a size of 0. Files removed
+// while walking the directory, e.g. by a compaction, are skipped.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
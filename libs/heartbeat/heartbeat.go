@@ -0,0 +1,40 @@
+// Package heartbeat lets the long-running goroutines of a node report that
+// they are making progress, for the liveness probe to detect the stuck ones.
+package heartbeat
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Interval is the interval at which a goroutine beats while it is idle. A
+// goroutine whose last beat is much older than Interval is stuck.
+const Interval = time.Second
+
+// Heartbeat records the last time a goroutine made progress. The zero value is
+// a stopped heartbeat, the one of a goroutine which isn't running. It is thread
+// safe.
+type Heartbeat struct {
+	last atomic.Int64 // unix nanoseconds, 0 if stopped
+}
+
+// Beat records that the goroutine made progress now.
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Stop records that the goroutine exited normally. It must not be called when
+// the goroutine exits because of a failure, so that the failure is detected.
+func (h *Heartbeat) Stop() {
+	h.last.Store(0)
+}
+
+// Last returns the time of the last beat, and false if the heartbeat is
+// stopped.
+func (h *Heartbeat) Last() (time.Time, bool) {
+	last := h.last.Load()
+	if last == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, last), true
+}
@@ -0,0 +1,26 @@
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeat(t *testing.T) {
+	var h Heartbeat
+	_, ok := h.Last()
+	assert.False(t, ok, "the zero heartbeat should be stopped")
+
+	before := time.Now()
+	h.Beat()
+	last, ok := h.Last()
+	require.True(t, ok)
+	assert.False(t, last.Before(before))
+	assert.False(t, last.After(time.Now()))
+
+	h.Stop()
+	_, ok = h.Last()
+	assert.False(t, ok)
+}
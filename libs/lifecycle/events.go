@@ -0,0 +1,70 @@
+package lifecycle
+
+// Bus holds a topic for each lifecycle event of a node. The events are:
+//
+//   - SyncModeChanged, published when the node starts and each time it
+//     switches between state sync, fast sync and consensus.
+//   - StateSyncCompleted, published when the state of the node was restored
+//     from a snapshot.
+//   - PeerConnectivityChanged, published when the node loses its last peer and
+//     when it connects to a peer again.
+type Bus struct {
+	SyncMode           *Topic[SyncModeChanged]
+	StateSyncCompleted *Topic[StateSyncCompleted]
+	PeerConnectivity   *Topic[PeerConnectivityChanged]
+}
+
+// NewBus returns a Bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{
+		SyncMode:           NewTopic[SyncModeChanged](),
+		StateSyncCompleted: NewTopic[StateSyncCompleted](),
+		PeerConnectivity:   NewTopic[PeerConnectivityChanged](),
+	}
+}
+
+// SyncMode is the way a node catches up with the chain.
+type SyncMode int
+
+const (
+	// SyncModeStateSync restores the state of the node from a snapshot.
+	SyncModeStateSync SyncMode = iota + 1
+	// SyncModeFastSync downloads and executes the blocks the node is
+	// missing.
+	SyncModeFastSync
+	// SyncModeConsensus takes part in consensus, the node being caught up.
+	SyncModeConsensus
+)
+
+func (m SyncMode) String() string {
+	switch m {
+	case SyncModeStateSync:
+		return "state_sync"
+	case SyncModeFastSync:
+		return "fast_sync"
+	case SyncModeConsensus:
+		return "consensus"
+	default:
+		return "unknown"
+	}
+}
+
+// SyncModeChanged is published when the node starts in, or switches to, Mode.
+type SyncModeChanged struct {
+	Mode SyncMode
+}
+
+// StateSyncCompleted is published when the state of the node was restored
+// from the snapshot at Height.
+type StateSyncCompleted struct {
+	Height int64
+}
+
+// PeerConnectivityChanged is published when the node loses its last peer, or
+// connects to a peer while it had none.
+type PeerConnectivityChanged struct {
+	// Connected is true if the node has peers.
+	Connected bool
+	// Peers is the number of peers of the node when the event was published.
+	Peers int
+}
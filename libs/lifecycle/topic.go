@@ -0,0 +1,98 @@
+// Package lifecycle is a small typed event bus on which the subsystems of a
+// node publish the changes of their lifecycle, e.g. switching from fast sync
+// to consensus, for the other subsystems to react to them. Unlike the pubsub
+// package, which serves the events of the chain to the users, it is internal
+// to the node: each event type has its own topic, delivery never blocks the
+// publisher, and there is no query language.
+package lifecycle
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Topic delivers the events of type E to its subscribers. Each subscriber
+// receives the events in the order they were published. Publishing never
+// blocks: the events not fitting in the buffer of a subscriber are dropped
+// for it, and counted. It is thread safe, and a nil Topic discards the
+// events.
+type Topic[E any] struct {
+	mtx         sync.Mutex
+	subscribers []*Subscription[E]
+	latest      E
+	published   bool
+}
+
+// NewTopic returns a new Topic without subscribers.
+func NewTopic[E any]() *Topic[E] {
+	return &Topic[E]{}
+}
+
+// Subscription receives the events of a Topic.
+type Subscription[E any] struct {
+	out     chan E
+	dropped atomic.Uint64
+}
+
+// Out returns the channel the events are delivered on. It is never closed.
+func (s *Subscription[E]) Out() <-chan E {
+	return s.out
+}
+
+// Dropped returns the number of events dropped for the subscription because
+// its buffer was full.
+func (s *Subscription[E]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Publish delivers e to the subscribers with room for it in their buffer. It
+// never blocks on a subscriber.
+func (t *Topic[E]) Publish(e E) {
+	if t == nil {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.latest, t.published = e, true
+	for _, s := range t.subscribers {
+		select {
+		case s.out <- e:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe returns a subscription receiving the events published from now
+// on, buffering up to capacity of them.
+func (t *Topic[E]) Subscribe(capacity int) *Subscription[E] {
+	s := &Subscription[E]{out: make(chan E, capacity)}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.subscribers = append(t.subscribers, s)
+	return s
+}
+
+// Unsubscribe stops delivering the events to s. The events already buffered
+// are left in its channel.
+func (t *Topic[E]) Unsubscribe(s *Subscription[E]) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for i, sub := range t.subscribers {
+		if sub == s {
+			t.subscribers = append(t.subscribers[:i], t.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Latest returns the last event published, and false if none was.
+func (t *Topic[E]) Latest() (e E, ok bool) {
+	if t == nil {
+		return e, false
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.latest, t.published
+}
@@ -0,0 +1,108 @@
+package lifecycle_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/lifecycle"
+)
+
+// TestTopicOrdering checks that each subscriber receives the events in the
+// order they were published, while several goroutines publish.
+func TestTopicOrdering(t *testing.T) {
+	const (
+		publishers   = 4
+		perPublisher = 1000
+	)
+	topic := lifecycle.NewTopic[[2]int]()
+	subs := []*lifecycle.Subscription[[2]int]{
+		topic.Subscribe(publishers * perPublisher),
+		topic.Subscribe(publishers * perPublisher),
+	}
+
+	var wg sync.WaitGroup
+	for p := 0; p < publishers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perPublisher; i++ {
+				topic.Publish([2]int{p, i})
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	var first [][2]int
+	for i, s := range subs {
+		next := make([]int, publishers)
+		var received [][2]int
+		for n := 0; n < publishers*perPublisher; n++ {
+			e := <-s.Out()
+			require.Equal(t, next[e[0]], e[1], "events of publisher %d out of order", e[0])
+			next[e[0]]++
+			received = append(received, e)
+		}
+		assert.Zero(t, s.Dropped())
+		if i == 0 {
+			first = received
+		} else {
+			assert.Equal(t, first, received, "the subscribers received the events in different orders")
+		}
+	}
+}
+
+// TestTopicNonBlocking checks that a subscriber not reading its events doesn't
+// block the publisher nor the other subscribers.
+func TestTopicNonBlocking(t *testing.T) {
+	topic := lifecycle.NewTopic[int]()
+	stuck := topic.Subscribe(2)
+	other := topic.Subscribe(10)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			topic.Publish(i)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publishing blocked on a full subscriber")
+	}
+
+	assert.EqualValues(t, 8, stuck.Dropped())
+	assert.Equal(t, 0, <-stuck.Out())
+	assert.Equal(t, 1, <-stuck.Out())
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, i, <-other.Out())
+	}
+	assert.Zero(t, other.Dropped())
+}
+
+func TestTopicLatestAndUnsubscribe(t *testing.T) {
+	topic := lifecycle.NewTopic[lifecycle.SyncModeChanged]()
+	_, ok := topic.Latest()
+	assert.False(t, ok)
+
+	s := topic.Subscribe(1)
+	topic.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeFastSync})
+	topic.Unsubscribe(s)
+	topic.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeConsensus})
+
+	latest, ok := topic.Latest()
+	require.True(t, ok)
+	assert.Equal(t, lifecycle.SyncModeConsensus, latest.Mode)
+	assert.Equal(t, lifecycle.SyncModeFastSync, (<-s.Out()).Mode)
+	assert.Zero(t, s.Dropped(), "events were delivered after unsubscribing")
+
+	// a nil topic discards the events
+	var none *lifecycle.Topic[int]
+	none.Publish(1)
+	_, ok = none.Latest()
+	assert.False(t, ok)
+}
@@ -1,6 +1,10 @@
 package log
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
 
 type level byte
 
@@ -10,11 +14,58 @@ const (
 	levelError
 )
 
+// LevelFilter is a logger filtering the log events by level, whose options can
+// be replaced at runtime. The loggers derived from it with With follow the
+// replacements. It is implemented by the loggers returned by NewFilter.
+type LevelFilter interface {
+	Logger
+
+	// SetOptions replaces the options of the filter, atomically.
+	SetOptions(options ...Option)
+
+	// Levels returns the current levels of the filter.
+	Levels() Levels
+}
+
+// Levels describes the levels of a LevelFilter.
+type Levels struct {
+	// Default is the level of the modules without one.
+	Default string
+	// Modules maps the modules known to the filter, i.e. the modules with a
+	// level and those of the loggers derived with With, to their level.
+	Modules map[string]string
+}
+
 type filter struct {
-	next             Logger
-	allowed          level            // XOR'd levels for default case
-	initiallyAllowed level            // XOR'd levels for initial case
-	allowedKeyvals   map[keyval]level // When key-value match, use this level
+	next  Logger
+	state *filterState
+
+	// parent is the filter this one was derived from with keyvals, nil for
+	// the filter returned by NewFilter.
+	parent  *filter
+	keyvals []interface{}
+
+	// cache holds the level allowed by the current rules.
+	cache atomic.Pointer[filterCache]
+}
+
+// filterState is shared by a filter and all the filters derived from it.
+type filterState struct {
+	rules atomic.Pointer[filterRules]
+
+	mtx     sync.Mutex
+	modules map[string]struct{}
+}
+
+// filterRules are the levels set by the options of a filter.
+type filterRules struct {
+	allowed        level            // XOR'd levels for default case
+	allowedKeyvals map[keyval]level // When key-value match, use this level
+}
+
+type filterCache struct {
+	rules   *filterRules
+	allowed level
 }
 
 type keyval struct {
@@ -25,21 +76,45 @@ type keyval struct {
 // NewFilter wraps next and implements filtering. See the commentary on the
 // Option functions for a detailed description of how to configure levels. If
 // no options are provided, all leveled log events created with Debug, Info or
-// Error helper methods are squelched.
+// Error helper methods are squelched. The returned logger implements
+// LevelFilter, so its options can be replaced at runtime.
 func NewFilter(next Logger, options ...Option) Logger {
 	l := &filter{
-		next:           next,
-		allowedKeyvals: make(map[keyval]level),
+		next:  next,
+		state: &filterState{modules: make(map[string]struct{})},
 	}
+	l.SetOptions(options...)
+	return l
+}
+
+// SetOptions implements LevelFilter.
+func (l *filter) SetOptions(options ...Option) {
+	rules := &filterRules{allowedKeyvals: make(map[keyval]level)}
 	for _, option := range options {
-		option(l)
+		option(rules)
 	}
-	l.initiallyAllowed = l.allowed
-	return l
+	l.state.rules.Store(rules)
+}
+
+// Levels implements LevelFilter.
+func (l *filter) Levels() Levels {
+	rules := l.state.rules.Load()
+	levels := Levels{Default: rules.allowed.String(), Modules: make(map[string]string)}
+	l.state.mtx.Lock()
+	for module := range l.state.modules {
+		levels.Modules[module] = levels.Default
+	}
+	l.state.mtx.Unlock()
+	for kv, allowed := range rules.allowedKeyvals {
+		if module, ok := kv.value.(string); ok && kv.key == moduleKey {
+			levels.Modules[module] = allowed.String()
+		}
+	}
+	return levels
 }
 
 func (l *filter) Info(msg string, keyvals ...interface{}) {
-	levelAllowed := l.allowed&levelInfo != 0
+	levelAllowed := l.allowed()&levelInfo != 0
 	if !levelAllowed {
 		return
 	}
@@ -47,7 +122,7 @@ func (l *filter) Info(msg string, keyvals ...interface{}) {
 }
 
 func (l *filter) Debug(msg string, keyvals ...interface{}) {
-	levelAllowed := l.allowed&levelDebug != 0
+	levelAllowed := l.allowed()&levelDebug != 0
 	if !levelAllowed {
 		return
 	}
@@ -55,7 +130,7 @@ func (l *filter) Debug(msg string, keyvals ...interface{}) {
 }
 
 func (l *filter) Error(msg string, keyvals ...interface{}) {
-	levelAllowed := l.allowed&levelError != 0
+	levelAllowed := l.allowed()&levelError != 0
 	if !levelAllowed {
 		return
 	}
@@ -83,22 +158,48 @@ func (l *filter) Error(msg string, keyvals ...interface{}) {
 //					log.AllowInfoWith("module", "crypto"), log.AllowNoneWith("user", "Sam"))
 //			 logger.With("user", "Sam").With("module", "crypto").Info("Hello") # produces "I... Hello module=crypto user=Sam"
 func (l *filter) With(keyvals ...interface{}) Logger {
-	keyInAllowedKeyvals := false
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if module, ok := keyvals[i+1].(string); ok && keyvals[i] == moduleKey {
+			l.state.mtx.Lock()
+			l.state.modules[module] = struct{}{}
+			l.state.mtx.Unlock()
+		}
+	}
+	return &filter{
+		next:    l.next.With(keyvals...),
+		state:   l.state,
+		parent:  l,
+		keyvals: keyvals,
+	}
+}
 
-	for i := len(keyvals) - 2; i >= 0; i -= 2 {
-		for kv, allowed := range l.allowedKeyvals {
-			if keyvals[i] == kv.key {
+// allowed returns the levels allowed by the current rules.
+func (l *filter) allowed() level {
+	rules := l.state.rules.Load()
+	if cache := l.cache.Load(); cache != nil && cache.rules == rules {
+		return cache.allowed
+	}
+	allowed := l.resolve(rules)
+	l.cache.Store(&filterCache{rules: rules, allowed: allowed})
+	return allowed
+}
+
+// resolve returns the levels allowed by rules.
+func (l *filter) resolve(rules *filterRules) level {
+	if l.parent == nil {
+		return rules.allowed
+	}
+
+	keyInAllowedKeyvals := false
+	for i := len(l.keyvals) - 2; i >= 0; i -= 2 {
+		for kv, allowed := range rules.allowedKeyvals {
+			if l.keyvals[i] == kv.key {
 				keyInAllowedKeyvals = true
 				// Example:
 				//		logger = log.NewFilter(logger, log.AllowError(), log.AllowInfoWith("module", "crypto"))
 				//		logger.With("module", "crypto")
-				if keyvals[i+1] == kv.value {
-					return &filter{
-						next:             l.next.With(keyvals...),
-						allowed:          allowed, // set the desired level
-						allowedKeyvals:   l.allowedKeyvals,
-						initiallyAllowed: l.initiallyAllowed,
-					}
+				if l.keyvals[i+1] == kv.value {
+					return allowed // set the desired level
 				}
 			}
 		}
@@ -108,26 +209,30 @@ func (l *filter) With(keyvals ...interface{}) Logger {
 	//		logger = log.NewFilter(logger, log.AllowError(), log.AllowInfoWith("module", "crypto"))
 	//		logger.With("module", "main")
 	if keyInAllowedKeyvals {
-		return &filter{
-			next:             l.next.With(keyvals...),
-			allowed:          l.initiallyAllowed, // return back to initially allowed
-			allowedKeyvals:   l.allowedKeyvals,
-			initiallyAllowed: l.initiallyAllowed,
-		}
+		return rules.allowed // return back to initially allowed
 	}
 
-	return &filter{
-		next:             l.next.With(keyvals...),
-		allowed:          l.allowed, // simply continue with the current level
-		allowedKeyvals:   l.allowedKeyvals,
-		initiallyAllowed: l.initiallyAllowed,
+	return l.parent.allowed() // simply continue with the current level
+}
+
+// String returns the name of the level, as parsed by AllowLevel.
+func (lvl level) String() string {
+	switch {
+	case lvl&levelDebug != 0:
+		return "debug"
+	case lvl&levelInfo != 0:
+		return "info"
+	case lvl&levelError != 0:
+		return "error"
+	default:
+		return "none"
 	}
 }
 
 //--------------------------------------------------------------------------------
 
 // Option sets a parameter for the filter.
-type Option func(*filter)
+type Option func(*filterRules)
 
 // AllowLevel returns an option for the given level or error if no option exist
 // for such level.
@@ -172,25 +277,25 @@ func AllowNone() Option {
 }
 
 func allowed(allowed level) Option {
-	return func(l *filter) { l.allowed = allowed }
+	return func(l *filterRules) { l.allowed = allowed }
 }
 
 // AllowDebugWith allows error, info and debug level log events to pass for a specific key value pair.
 func AllowDebugWith(key interface{}, value interface{}) Option {
-	return func(l *filter) { l.allowedKeyvals[keyval{key, value}] = levelError | levelInfo | levelDebug }
+	return func(l *filterRules) { l.allowedKeyvals[keyval{key, value}] = levelError | levelInfo | levelDebug }
 }
 
 // AllowInfoWith allows error and info level log events to pass for a specific key value pair.
 func AllowInfoWith(key interface{}, value interface{}) Option {
-	return func(l *filter) { l.allowedKeyvals[keyval{key, value}] = levelError | levelInfo }
+	return func(l *filterRules) { l.allowedKeyvals[keyval{key, value}] = levelError | levelInfo }
 }
 
 // AllowErrorWith allows only error level log events to pass for a specific key value pair.
 func AllowErrorWith(key interface{}, value interface{}) Option {
-	return func(l *filter) { l.allowedKeyvals[keyval{key, value}] = levelError }
+	return func(l *filterRules) { l.allowedKeyvals[keyval{key, value}] = levelError }
 }
 
 // AllowNoneWith allows no leveled log events to pass for a specific key value pair.
 func AllowNoneWith(key interface{}, value interface{}) Option {
-	return func(l *filter) { l.allowedKeyvals[keyval{key, value}] = 0 }
+	return func(l *filterRules) { l.allowedKeyvals[keyval{key, value}] = 0 }
 }
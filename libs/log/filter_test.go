@@ -2,6 +2,7 @@ package log_test
 
 import (
 	"bytes"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -138,3 +139,44 @@ func TestVariousAllowWith(t *testing.T) {
 		t.Errorf("\nwant '%s'\nhave '%s'", want, have)
 	}
 }
+
+func TestFilterSetOptions(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewFilter(log.NewTMJSONLoggerNoTS(&buf), log.AllowInfo())
+	filter, ok := logger.(log.LevelFilter)
+	if !ok {
+		t.Fatal("expected the filter to implement LevelFilter")
+	}
+
+	// derived before the options are replaced
+	mempool := logger.With("module", "mempool")
+	consensus := logger.With("module", "consensus").With("height", 1)
+
+	mempool.Debug("Sabretooth")
+	consensus.Debug("Mystique")
+	if have := strings.TrimSpace(buf.String()); have != "" {
+		t.Fatalf("expected the debug lines to be suppressed, have '%s'", have)
+	}
+
+	filter.SetOptions(log.AllowInfo(), log.AllowDebugWith("module", "mempool"))
+
+	mempool.Debug("Sabretooth")
+	consensus.Debug("Mystique")
+	consensus.Info("Juggernaut")
+	want := strings.Join([]string{
+		`{"_msg":"Sabretooth","level":"debug","module":"mempool"}`,
+		`{"_msg":"Juggernaut","height":1,"level":"info","module":"consensus"}`,
+	}, "\n")
+	if have := strings.TrimSpace(buf.String()); have != want {
+		t.Errorf("\nwant '%s'\nhave '%s'", want, have)
+	}
+
+	levels := filter.Levels()
+	if levels.Default != "info" {
+		t.Errorf("want default level 'info', have '%s'", levels.Default)
+	}
+	wantModules := map[string]string{"mempool": "debug", "consensus": "info"}
+	if !reflect.DeepEqual(wantModules, levels.Modules) {
+		t.Errorf("\nwant modules %v\nhave modules %v", wantModules, levels.Modules)
+	}
+}
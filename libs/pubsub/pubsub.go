@@ -37,7 +37,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/tendermint/tendermint/libs/heartbeat"
 	"github.com/tendermint/tendermint/libs/service"
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
 )
@@ -90,8 +92,10 @@ type cmd struct {
 type Server struct {
 	service.BaseService
 
-	cmds    chan cmd
-	cmdsCap int
+	cmds      chan cmd
+	cmdsCap   int
+	metrics   *Metrics
+	heartbeat heartbeat.Heartbeat
 
 	// check if we have subscription before
 	// subscribing or unsubscribing
@@ -108,6 +112,7 @@ type Option func(*Server)
 func NewServer(options ...Option) *Server {
 	s := &Server{
 		subscriptions: make(map[string]map[string]struct{}),
+		metrics:       NopMetrics(),
 	}
 	s.BaseService = *service.NewBaseService(nil, "PubSub", s)
 
@@ -133,6 +138,13 @@ func BufferCapacity(cap int) Option {
 	}
 }
 
+// WithMetrics sets the metrics of the server.
+func WithMetrics(metrics *Metrics) Option {
+	return func(s *Server) {
+		s.metrics = metrics
+	}
+}
+
 // BufferCapacity returns capacity of the internal server's queue.
 func (s *Server) BufferCapacity() int {
 	return s.cmdsCap
@@ -145,7 +157,8 @@ func (s *Server) BufferCapacity() int {
 //
 // outCapacity can be used to set a capacity for Subscription#Out channel (1 by
 // default). Panics if outCapacity is less than or equal to zero. If you want
-// an unbuffered channel, use SubscribeUnbuffered.
+// an unbuffered channel, use SubscribeUnbuffered. The subscription is
+// terminated when it is full, see SubscribeBounded for other policies.
 func (s *Server) Subscribe(
 	ctx context.Context,
 	clientID string,
@@ -159,17 +172,38 @@ func (s *Server) Subscribe(
 		outCap = outCapacity[0]
 	}
 
-	return s.subscribe(ctx, clientID, query, outCap)
+	return s.subscribe(ctx, clientID, query, NewSubscription(outCap))
 }
 
 // SubscribeUnbuffered does the same as Subscribe, except it returns a
 // subscription with unbuffered channel. Use with caution as it can freeze the
 // server.
 func (s *Server) SubscribeUnbuffered(ctx context.Context, clientID string, query Query) (*Subscription, error) {
-	return s.subscribe(ctx, clientID, query, 0)
+	return s.subscribe(ctx, clientID, query, NewSubscription(0))
 }
 
-func (s *Server) subscribe(ctx context.Context, clientID string, query Query, outCapacity int) (*Subscription, error) {
+// SubscribeBounded does the same as Subscribe, except it returns a
+// subscription buffering up to cfg.Capacity messages, and handling the
+// messages published while it is full as set by cfg.Overflow. An error is
+// returned if cfg is invalid.
+func (s *Server) SubscribeBounded(
+	ctx context.Context,
+	clientID string,
+	query Query,
+	cfg BufferConfig,
+) (*Subscription, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return s.subscribe(ctx, clientID, query, newBoundedSubscription(cfg))
+}
+
+func (s *Server) subscribe(
+	ctx context.Context,
+	clientID string,
+	query Query,
+	subscription *Subscription,
+) (*Subscription, error) {
 	s.mtx.RLock()
 	clientSubscriptions, ok := s.subscriptions[clientID]
 	if ok {
@@ -180,7 +214,6 @@ func (s *Server) subscribe(ctx context.Context, clientID string, query Query, ou
 		return nil, ErrAlreadySubscribed
 	}
 
-	subscription := NewSubscription(outCapacity)
 	select {
 	case s.cmds <- cmd{op: sub, clientID: clientID, query: query, subscription: subscription}:
 		s.mtx.Lock()
@@ -295,6 +328,8 @@ type state struct {
 	subscriptions map[string]map[string]*Subscription
 	// query string -> queryPlusRefCount
 	queries map[string]*queryPlusRefCount
+
+	metrics *Metrics
 }
 
 // queryPlusRefCount holds a pointer to a query and reference counter. When
@@ -309,6 +344,7 @@ func (s *Server) OnStart() error {
 	go s.loop(state{
 		subscriptions: make(map[string]map[string]*Subscription),
 		queries:       make(map[string]*queryPlusRefCount),
+		metrics:       s.metrics,
 	})
 	return nil
 }
@@ -318,9 +354,26 @@ func (s *Server) OnReset() error {
 	return nil
 }
 
+// Heartbeat returns the heartbeat of the goroutine delivering the messages.
+// It is stopped while the server isn't running.
+func (s *Server) Heartbeat() *heartbeat.Heartbeat {
+	return &s.heartbeat
+}
+
 func (s *Server) loop(state state) {
-loop:
-	for cmd := range s.cmds {
+	ticker := time.NewTicker(heartbeat.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.heartbeat.Beat()
+
+		var cmd cmd
+		select {
+		case cmd = <-s.cmds:
+		case <-ticker.C:
+			continue
+		}
+
 		switch cmd.op {
 		case unsub:
 			if cmd.query != nil {
@@ -330,7 +383,8 @@ loop:
 			}
 		case shutdown:
 			state.removeAll(nil)
-			break loop
+			s.heartbeat.Stop()
+			return
 		case sub:
 			state.add(cmd.clientID, cmd.query, cmd.subscription)
 		case pub:
@@ -350,6 +404,7 @@ func (state *state) add(clientID string, q Query, subscription *Subscription) {
 	}
 	// create subscription
 	state.subscriptions[qStr][clientID] = subscription
+	subscription.start()
 
 	// initialize query if needed
 	if _, ok := state.queries[qStr]; !ok {
@@ -414,16 +469,15 @@ func (state *state) send(msg interface{}, events map[string][]string) error {
 
 		if match {
 			for clientID, subscription := range clientSubscriptions {
-				if cap(subscription.out) == 0 {
-					// block on unbuffered channel
-					subscription.out <- NewMessage(msg, events)
-				} else {
-					// don't block on buffered channels
-					select {
-					case subscription.out <- NewMessage(msg, events):
-					default:
-						state.remove(clientID, qStr, ErrOutOfCapacity)
-					}
+				dropped, err := subscription.send(NewMessage(msg, events))
+				if dropped > 0 {
+					state.metrics.DroppedMessages.With(
+						"subscriber", subscription.subscriber,
+						"policy", subscription.overflow.String(),
+					).Add(float64(dropped))
+				}
+				if err != nil {
+					state.remove(clientID, qStr, err)
 				}
 			}
 		}
@@ -2,6 +2,8 @@ package pubsub
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
 )
@@ -15,6 +17,86 @@ var (
 	ErrOutOfCapacity = errors.New("internal subscription event buffer is out of capacity")
 )
 
+// defaultSubscriber is the subscriber label of the subscriptions which don't
+// set one.
+const defaultSubscriber = "other"
+
+// OverflowPolicy defines what a bounded subscription does with the messages
+// published while its buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowTerminate terminates the subscription with ErrOutOfCapacity.
+	OverflowTerminate OverflowPolicy = iota
+	// OverflowBlock blocks the publisher until the subscriber makes room, for
+	// at most BufferConfig.BlockTimeout, after which the subscription is
+	// terminated with ErrOutOfCapacity. Note that all the publishers and
+	// subscribers of the server wait meanwhile.
+	OverflowBlock
+	// OverflowDropOldest drops the oldest buffered message to make room for
+	// the new one. The message delivered after dropped ones reports how many
+	// were dropped, see Message.Dropped.
+	OverflowDropOldest
+)
+
+// String returns the name of the policy, as parsed by ParseOverflowPolicy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowTerminate:
+		return "terminate"
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+// ParseOverflowPolicy returns the policy named s: "terminate", "block" or
+// "drop_oldest".
+func ParseOverflowPolicy(s string) (OverflowPolicy, error) {
+	for _, p := range []OverflowPolicy{OverflowTerminate, OverflowBlock, OverflowDropOldest} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown overflow policy %q", s)
+}
+
+// BufferConfig configures the buffer of a bounded subscription. See
+// Server.SubscribeBounded.
+type BufferConfig struct {
+	// Capacity is the number of messages buffered. It must be positive.
+	Capacity int
+	// Overflow is what happens to the messages published while the buffer is
+	// full.
+	Overflow OverflowPolicy
+	// BlockTimeout is how long a publisher is blocked with OverflowBlock. It
+	// must be positive with OverflowBlock.
+	BlockTimeout time.Duration
+	// Subscriber is the kind of the subscriber, e.g. "indexer", labelling the
+	// metrics of the subscription. Unlike the client IDs, which may be remote
+	// addresses, it should take few values. It defaults to "other".
+	Subscriber string
+}
+
+func (cfg BufferConfig) validate() error {
+	if cfg.Capacity <= 0 {
+		return errors.New("the capacity of a bounded subscription must be positive")
+	}
+	switch cfg.Overflow {
+	case OverflowTerminate, OverflowDropOldest:
+	case OverflowBlock:
+		if cfg.BlockTimeout <= 0 {
+			return errors.New("the block timeout of a bounded subscription must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown overflow policy %v", cfg.Overflow)
+	}
+	return nil
+}
+
 // A Subscription represents a client subscription for a particular query and
 // consists of three things:
 // 1) channel onto which messages and events are published
@@ -23,6 +105,14 @@ var (
 type Subscription struct {
 	out chan Message
 
+	overflow     OverflowPolicy
+	blockTimeout time.Duration
+	// subscriber is the kind of the subscriber, labelling the metrics.
+	subscriber string
+	// buffer holds the messages of the subscriptions dropping their oldest
+	// messages, out is then unbuffered. It is nil otherwise.
+	buffer *messageBuffer
+
 	canceled chan struct{}
 	mtx      cmtsync.RWMutex
 	err      error
@@ -31,11 +121,33 @@ type Subscription struct {
 // NewSubscription returns a new subscription with the given outCapacity.
 func NewSubscription(outCapacity int) *Subscription {
 	return &Subscription{
-		out:      make(chan Message, outCapacity),
-		canceled: make(chan struct{}),
+		out:        make(chan Message, outCapacity),
+		subscriber: defaultSubscriber,
+		canceled:   make(chan struct{}),
 	}
 }
 
+// newBoundedSubscription returns a new subscription buffering messages as set
+// by cfg, which must be valid.
+func newBoundedSubscription(cfg BufferConfig) *Subscription {
+	s := &Subscription{
+		overflow:     cfg.Overflow,
+		blockTimeout: cfg.BlockTimeout,
+		subscriber:   cfg.Subscriber,
+		canceled:     make(chan struct{}),
+	}
+	if s.subscriber == "" {
+		s.subscriber = defaultSubscriber
+	}
+	if cfg.Overflow == OverflowDropOldest {
+		s.out = make(chan Message)
+		s.buffer = newMessageBuffer(cfg.Capacity)
+	} else {
+		s.out = make(chan Message, cfg.Capacity)
+	}
+	return s
+}
+
 // Out returns a channel onto which messages and events are published.
 // Unsubscribe/UnsubscribeAll does not close the channel to avoid clients from
 // receiving a nil message.
@@ -63,6 +175,69 @@ func (s *Subscription) Err() error {
 	return s.err
 }
 
+// start starts moving the buffered messages to the Out channel, for the
+// subscriptions dropping their oldest messages. It returns immediately.
+func (s *Subscription) start() {
+	if s.buffer != nil {
+		go s.forward()
+	}
+}
+
+// send delivers msg according to the overflow policy of the subscription. It
+// returns the number of messages dropped and, if the subscription must be
+// terminated, the reason.
+func (s *Subscription) send(msg Message) (int, error) {
+	if s.buffer != nil {
+		return s.buffer.push(msg), nil
+	}
+	if cap(s.out) == 0 {
+		// block on unbuffered channel
+		s.out <- msg
+		return 0, nil
+	}
+	select {
+	case s.out <- msg:
+		return 0, nil
+	default:
+	}
+	if s.overflow == OverflowBlock {
+		timer := time.NewTimer(s.blockTimeout)
+		defer timer.Stop()
+		select {
+		case s.out <- msg:
+			return 0, nil
+		case <-timer.C:
+		}
+	}
+	return 1, ErrOutOfCapacity
+}
+
+// forward moves the buffered messages to the Out channel until the
+// subscription is terminated.
+func (s *Subscription) forward() {
+	for {
+		select {
+		case <-s.canceled:
+			return
+		default:
+		}
+		msg, ok := s.buffer.pop()
+		if !ok {
+			select {
+			case <-s.buffer.pushed:
+				continue
+			case <-s.canceled:
+				return
+			}
+		}
+		select {
+		case s.out <- msg:
+		case <-s.canceled:
+			return
+		}
+	}
+}
+
 func (s *Subscription) cancel(err error) {
 	s.mtx.Lock()
 	s.err = err
@@ -74,10 +249,12 @@ func (s *Subscription) cancel(err error) {
 type Message struct {
 	data   interface{}
 	events map[string][]string
+
+	dropped int
 }
 
 func NewMessage(data interface{}, events map[string][]string) Message {
-	return Message{data, events}
+	return Message{data: data, events: events}
 }
 
 // Data returns an original data published.
@@ -89,3 +266,68 @@ func (msg Message) Data() interface{} {
 func (msg Message) Events() map[string][]string {
 	return msg.events
 }
+
+// Dropped returns the number of messages dropped right before this one, by a
+// subscription dropping its oldest messages when full.
+func (msg Message) Dropped() int {
+	return msg.dropped
+}
+
+// messageBuffer is the buffer of a subscription dropping its oldest messages.
+// It is thread safe.
+type messageBuffer struct {
+	// pushed is signaled when a message is pushed.
+	pushed chan struct{}
+
+	mtx      cmtsync.Mutex
+	msgs     []Message
+	capacity int
+}
+
+func newMessageBuffer(capacity int) *messageBuffer {
+	return &messageBuffer{
+		pushed:   make(chan struct{}, 1),
+		msgs:     make([]Message, 0, capacity),
+		capacity: capacity,
+	}
+}
+
+// push appends msg to the buffer, dropping the oldest message if it is full.
+// The dropped messages are added to the count of the message following them.
+// It returns the number of messages dropped.
+func (b *messageBuffer) push(msg Message) int {
+	b.mtx.Lock()
+	dropped := 0
+	if len(b.msgs) == b.capacity {
+		dropped = 1
+		carried := b.msgs[0].dropped + 1
+		b.msgs[0] = Message{}
+		b.msgs = b.msgs[1:]
+		if len(b.msgs) > 0 {
+			b.msgs[0].dropped += carried
+		} else {
+			msg.dropped += carried
+		}
+	}
+	b.msgs = append(b.msgs, msg)
+	b.mtx.Unlock()
+
+	select {
+	case b.pushed <- struct{}{}:
+	default:
+	}
+	return dropped
+}
+
+// pop removes and returns the oldest message, if any.
+func (b *messageBuffer) pop() (Message, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if len(b.msgs) == 0 {
+		return Message{}, false
+	}
+	msg := b.msgs[0]
+	b.msgs[0] = Message{}
+	b.msgs = b.msgs[1:]
+	return msg, true
+}
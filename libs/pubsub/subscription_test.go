@@ -0,0 +1,323 @@
+package pubsub_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/libs/pubsub"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+)
+
+// labeledCounter is a counter keeping a value per set of label values.
+type labeledCounter struct {
+	mtx      sync.Mutex
+	counters map[string]*generic.Counter
+}
+
+func newLabeledCounter() *labeledCounter {
+	return &labeledCounter{counters: make(map[string]*generic.Counter)}
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	key := strings.Join(labelValues, ",")
+	if _, ok := c.counters[key]; !ok {
+		c.counters[key] = generic.NewCounter(key)
+	}
+	return c.counters[key]
+}
+
+func (c *labeledCounter) Add(delta float64) {
+	c.With().Add(delta)
+}
+
+func (c *labeledCounter) Value(labelValues ...string) float64 {
+	return c.With(labelValues...).(*generic.Counter).Value()
+}
+
+// startServer starts a server counting the dropped messages in dropped.
+func startServer(t *testing.T, dropped *labeledCounter) *pubsub.Server {
+	m := pubsub.NopMetrics()
+	m.DroppedMessages = dropped
+	s := pubsub.NewServer(pubsub.WithMetrics(m))
+	s.SetLogger(log.TestingLogger())
+	require.NoError(t, s.Start())
+	t.Cleanup(func() {
+		if err := s.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	return s
+}
+
+func TestSubscribeBoundedInvalidConfig(t *testing.T) {
+	s := startServer(t, newLabeledCounter())
+	ctx := context.Background()
+
+	for _, cfg := range []pubsub.BufferConfig{
+		{Capacity: 0, Overflow: pubsub.OverflowTerminate},
+		{Capacity: 1, Overflow: pubsub.OverflowBlock},
+		{Capacity: 1, Overflow: pubsub.OverflowPolicy(42)},
+	} {
+		_, err := s.SubscribeBounded(ctx, clientID, query.Empty{}, cfg)
+		assert.Error(t, err, cfg)
+	}
+	assert.Zero(t, s.NumClients())
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	for _, p := range []pubsub.OverflowPolicy{
+		pubsub.OverflowTerminate,
+		pubsub.OverflowBlock,
+		pubsub.OverflowDropOldest,
+	} {
+		parsed, err := pubsub.ParseOverflowPolicy(p.String())
+		require.NoError(t, err)
+		assert.Equal(t, p, parsed)
+	}
+	_, err := pubsub.ParseOverflowPolicy("drop_newest")
+	assert.Error(t, err)
+}
+
+func TestSubscribeBoundedTerminate(t *testing.T) {
+	dropped := newLabeledCounter()
+	s := startServer(t, dropped)
+	ctx := context.Background()
+
+	subscription, err := s.SubscribeBounded(ctx, clientID, query.Empty{},
+		pubsub.BufferConfig{Capacity: 2, Overflow: pubsub.OverflowTerminate, Subscriber: "test"})
+	require.NoError(t, err)
+	for _, msg := range []string{"Nova", "Havok", "Banshee"} {
+		require.NoError(t, s.Publish(ctx, msg))
+	}
+
+	assertCancelled(t, subscription, pubsub.ErrOutOfCapacity)
+	assertReceive(t, "Nova", subscription.Out())
+	assertReceive(t, "Havok", subscription.Out())
+	assert.EqualValues(t, 1, dropped.Value("subscriber", "test", "policy", "terminate"))
+}
+
+// TestSubscribeBoundedBlock checks that a slow subscriber blocking the
+// publishers receives every message, in order.
+func TestSubscribeBoundedBlock(t *testing.T) {
+	const (
+		publishers   = 4
+		perPublisher = 100
+	)
+	dropped := newLabeledCounter()
+	s := startServer(t, dropped)
+	ctx := context.Background()
+
+	subscription, err := s.SubscribeBounded(ctx, clientID, query.Empty{},
+		pubsub.BufferConfig{Capacity: 3, Overflow: pubsub.OverflowBlock, BlockTimeout: 10 * time.Second})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for p := 0; p < publishers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perPublisher; i++ {
+				assert.NoError(t, s.Publish(ctx, [2]int{p, i}))
+			}
+		}(p)
+	}
+
+	next := make([]int, publishers)
+	for n := 0; n < publishers*perPublisher; n++ {
+		if n%50 == 0 {
+			// let the buffer fill up
+			time.Sleep(10 * time.Millisecond)
+		}
+		select {
+		case msg := <-subscription.Out():
+			pi := msg.Data().([2]int)
+			require.Equal(t, next[pi[0]], pi[1], "messages of publisher %d out of order", pi[0])
+			next[pi[0]]++
+			assert.Zero(t, msg.Dropped())
+		case <-subscription.Cancelled():
+			t.Fatalf("subscription cancelled: %v", subscription.Err())
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a message")
+		}
+	}
+	wg.Wait()
+	assert.Nil(t, subscription.Err())
+	assert.Zero(t, dropped.Value("subscriber", "other", "policy", "block"))
+}
+
+// TestSubscribeBoundedBlockTimeout checks that a subscriber not making room
+// within the block timeout is terminated, releasing the publishers.
+func TestSubscribeBoundedBlockTimeout(t *testing.T) {
+	dropped := newLabeledCounter()
+	s := startServer(t, dropped)
+	ctx := context.Background()
+
+	stuck, err := s.SubscribeBounded(ctx, "stuck", query.Empty{},
+		pubsub.BufferConfig{Capacity: 1, Overflow: pubsub.OverflowBlock, BlockTimeout: 100 * time.Millisecond,
+			Subscriber: "stuck"})
+	require.NoError(t, err)
+	other, err := s.Subscribe(ctx, clientID, query.Empty{}, 10)
+	require.NoError(t, err)
+
+	start := time.Now()
+	for _, msg := range []string{"Rogue", "Gambit", "Bishop"} {
+		require.NoError(t, s.Publish(ctx, msg))
+	}
+	assertCancelled(t, stuck, pubsub.ErrOutOfCapacity)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assertReceive(t, "Rogue", stuck.Out())
+
+	// the other subscriber got every message once the stuck one was removed
+	assertReceive(t, "Rogue", other.Out())
+	assertReceive(t, "Gambit", other.Out())
+	assertReceive(t, "Bishop", other.Out())
+	assert.EqualValues(t, 1, dropped.Value("subscriber", "stuck", "policy", "block"))
+}
+
+// TestSubscribeBoundedDropOldest publishes from several goroutines to a slow
+// subscriber dropping its oldest messages, and checks that the gaps reported
+// in-band account for every message not received.
+func TestSubscribeBoundedDropOldest(t *testing.T) {
+	const (
+		publishers   = 4
+		perPublisher = 500
+	)
+	dropped := newLabeledCounter()
+	s := startServer(t, dropped)
+	ctx := context.Background()
+
+	subscription, err := s.SubscribeBounded(ctx, clientID, query.Empty{},
+		pubsub.BufferConfig{Capacity: 5, Overflow: pubsub.OverflowDropOldest})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for p := 0; p < publishers; p++ {
+			wg.Add(1)
+			go func(p int) {
+				defer wg.Done()
+				for i := 0; i < perPublisher; i++ {
+					assert.NoError(t, s.Publish(ctx, [2]int{p, i}))
+				}
+			}(p)
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("publishing blocked on the slow subscriber")
+	}
+	// the newest message is never dropped, so receiving it drains the buffer
+	require.NoError(t, s.Publish(ctx, "last"))
+
+	var (
+		received int
+		gaps     int
+		last     = make([]int, publishers)
+	)
+	for p := range last {
+		last[p] = -1
+	}
+	for {
+		var msg pubsub.Message
+		select {
+		case msg = <-subscription.Out():
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a message")
+		}
+		received++
+		gaps += msg.Dropped()
+		if msg.Data() == "last" {
+			break
+		}
+		pi := msg.Data().([2]int)
+		require.Greater(t, pi[1], last[pi[0]], "messages of publisher %d out of order", pi[0])
+		last[pi[0]] = pi[1]
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, publishers*perPublisher+1, received+gaps)
+	assert.Positive(t, gaps)
+	assert.EqualValues(t, gaps, dropped.Value("subscriber", "other", "policy", "drop_oldest"))
+	assert.Nil(t, subscription.Err())
+}
+
+// TestSubscribeBoundedDropOldestGap checks that the gap is reported on the
+// message following the dropped ones.
+func TestSubscribeBoundedDropOldestGap(t *testing.T) {
+	s := startServer(t, newLabeledCounter())
+	ctx := context.Background()
+
+	subscription, err := s.SubscribeBounded(ctx, clientID, query.Empty{},
+		pubsub.BufferConfig{Capacity: 2, Overflow: pubsub.OverflowDropOldest})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.Publish(ctx, i))
+	}
+	require.NoError(t, s.Publish(ctx, 10))
+
+	// the first message may have been handed over before the buffer filled
+	// up, the rest is the two newest messages
+	var msgs []pubsub.Message
+	for len(msgs) == 0 || msgs[len(msgs)-1].Data() != 10 {
+		select {
+		case msg := <-subscription.Out():
+			msgs = append(msgs, msg)
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a message")
+		}
+	}
+	require.GreaterOrEqual(t, len(msgs), 2)
+	prev := -1
+	for _, msg := range msgs {
+		v := msg.Data().(int)
+		assert.Equal(t, v-prev-1, msg.Dropped(), "gap before %d", v)
+		prev = v
+	}
+	assert.Equal(t, 9, msgs[len(msgs)-2].Data())
+}
+
+// TestSubscribeBoundedDropOldestUnsubscribe checks that unsubscribing stops
+// the delivery of the buffered messages.
+func TestSubscribeBoundedDropOldestUnsubscribe(t *testing.T) {
+	s := startServer(t, newLabeledCounter())
+	ctx := context.Background()
+
+	subscription, err := s.SubscribeBounded(ctx, clientID, query.Empty{},
+		pubsub.BufferConfig{Capacity: 10, Overflow: pubsub.OverflowDropOldest})
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, s.Publish(ctx, i))
+	}
+	require.NoError(t, s.Unsubscribe(ctx, clientID, query.Empty{}))
+	assertCancelled(t, subscription, pubsub.ErrUnsubscribed)
+
+	// at most the message handed over when unsubscribing is received
+	received := 0
+	for {
+		select {
+		case <-subscription.Out():
+			received++
+			continue
+		case <-time.After(100 * time.Millisecond):
+		}
+		break
+	}
+	assert.LessOrEqual(t, received, 1)
+}
@@ -28,6 +28,8 @@ const (
 	// flush, which still leaves the potential of lingering disk cache.
 	// Never overwrites files
 	atomicWriteFileFlag = os.O_WRONLY | os.O_CREATE | os.O_SYNC | os.O_TRUNC | os.O_EXCL
+	// Same as atomicWriteFileFlag, without forcing a kernel flush
+	atomicWriteFileNoSyncFlag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC | os.O_EXCL
 )
 
 var (
@@ -76,6 +78,28 @@ func randWriteFileSuffix() string {
 // WriteFileAtomic creates a temporary file with data and provided perm and
 // swaps it atomically with filename if successful.
 func WriteFileAtomic(filename string, data []byte, perm os.FileMode) (err error) {
+	return writeFileAtomic(filename, data, perm, atomicWriteFileFlag)
+}
+
+// WriteFileAtomicNoSync is like WriteFileAtomic, but doesn't wait for data to
+// be flushed to disk. The swap remains atomic, but the data may be lost if the
+// machine crashes shortly after.
+func WriteFileAtomicNoSync(filename string, data []byte, perm os.FileMode) error {
+	return writeFileAtomic(filename, data, perm, atomicWriteFileNoSyncFlag)
+}
+
+// SyncDir flushes the entries of the directory dir to disk, so that files
+// created in, renamed into or removed from it persist across crashes.
+func SyncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func writeFileAtomic(filename string, data []byte, perm os.FileMode, flag int) (err error) {
 	// This implementation is inspired by the golang stdlibs method of creating
 	// tempfiles. Notable differences are that we use different flags, a 64 bit LCG
 	// and handle negatives differently.
@@ -94,7 +118,7 @@ func WriteFileAtomic(filename string, data []byte, perm os.FileMode) (err error)
 	i := 0
 	for ; i < atomicWriteFileMaxNumWriteAttempts; i++ {
 		name := filepath.Join(dir, atomicWriteFilePrefix+randWriteFileSuffix())
-		f, err = os.OpenFile(name, atomicWriteFileFlag, perm)
+		f, err = os.OpenFile(name, flag, perm)
 		// If the file already exists, try a new file
 		if os.IsExist(err) {
 			// If the files exists too many times, start reseeding as we've
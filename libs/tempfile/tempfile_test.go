@@ -141,3 +141,22 @@ func TestWriteFileAtomicManyDuplicates(t *testing.T) {
 	require.Nil(t, err, "Error reading resultant file")
 	require.Equal(t, []byte(expectedString), resultantFileBytes, "Written file had incorrect bytes")
 }
+
+func TestWriteFileAtomicNoSync(t *testing.T) {
+	dir := t.TempDir()
+	name := dir + "/write-atomic-no-sync-test"
+	data := []byte(cmtrand.Str(cmtrand.Intn(2048)))
+
+	require.NoError(t, os.WriteFile(name, cmtrand.Bytes(100), 0o600))
+	require.NoError(t, WriteFileAtomicNoSync(name, data, 0o600))
+	require.NoError(t, SyncDir(dir))
+
+	rData, err := os.ReadFile(name)
+	require.NoError(t, err)
+	require.Equal(t, data, rData)
+
+	// the temporary file is removed
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
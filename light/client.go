@@ -23,8 +23,9 @@ const (
 	sequential mode = iota + 1
 	skipping
 
-	defaultPruningSize      = 1000
-	defaultMaxRetryAttempts = 10
+	defaultPruningSize             = 1000
+	defaultMaxRetryAttempts        = 10
+	defaultPrimaryFailureThreshold = 1
 	// For verifySkipping, when using the cache of headers from the previous batch,
 	// they will always be at a height greater than 1/2 (normal verifySkipping) so to
 	// find something in between the range, 9/16 is used.
@@ -41,6 +42,17 @@ const (
 	defaultMaxBlockLag = 10 * time.Second
 )
 
+func (m mode) String() string {
+	switch m {
+	case sequential:
+		return "sequential"
+	case skipping:
+		return "skipping"
+	default:
+		return "unknown"
+	}
+}
+
 // Option sets a parameter for the light client.
 type Option func(*Client)
 
@@ -79,6 +91,26 @@ func PruningSize(h uint16) Option {
 	}
 }
 
+// PruningInterval option sets the interval between the heights of the light
+// blocks kept for historical verification when pruning. With a positive
+// interval, pruning keeps, in addition to the PruningSize most recent light
+// blocks:
+//
+//   - the oldest light block, the root of trust of the client;
+//   - the latest trusted light block, the trust anchor of new verifications;
+//   - one light block every interval heights, so that any older height can be
+//     verified by bisection from a trusted light block at most interval
+//     heights below it;
+//   - the oldest light block within the trusting period, so that the heights
+//     above it can be verified while the light blocks below expired.
+//
+// Default: 0, only the PruningSize most recent light blocks are kept.
+func PruningInterval(interval int64) Option {
+	return func(c *Client) {
+		c.pruningInterval = interval
+	}
+}
+
 // ConfirmationFunction option can be used to prompt to confirm an action. For
 // example, remove newer headers if the light client is being reset with an
 // older header. No confirmation is required by default!
@@ -103,6 +135,14 @@ func MaxRetryAttempts(max uint16) Option {
 	}
 }
 
+// WithMetrics option sets the metrics of the light client. Default: no-op
+// metrics.
+func WithMetrics(metrics *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
 // MaxClockDrift defines how much new header's time can drift into
 // the future relative to the light clients local time. Default: 10s.
 func MaxClockDrift(d time.Duration) Option {
@@ -145,6 +185,24 @@ type Client struct {
 	primary provider.Provider
 	// Providers used to "witness" new headers.
 	witnesses []provider.Provider
+	// Number of witnesses which are kept active, if enough spares are healthy.
+	numWitnesses int
+	// See SpareWitnesses option
+	spareWitnesses []provider.Provider
+	// Witnesses which served conflicting or invalid light blocks.
+	evictedWitnesses []provider.Provider
+	// See WitnessHealthCheckInterval option
+	witnessCheckInterval time.Duration
+	lastWitnessCheck     time.Time
+	witnessHealth        map[provider.Provider]*WitnessHealth
+	// Primaries which were replaced after failing to respond.
+	suspects []provider.Provider
+	// See PrimaryFailureThreshold option
+	primaryFailureThreshold int
+	// Number of consecutive failures of the primary.
+	primaryFailures int
+	// See OnPrimaryFailover option
+	failoverFn func(oldPrimary, newPrimary provider.Provider)
 
 	// Where trusted light blocks are stored.
 	trustedStore store.Store
@@ -153,12 +211,17 @@ type Client struct {
 
 	// See RemoveNoLongerTrustedHeadersPeriod option
 	pruningSize uint16
+	// See PruningInterval option
+	pruningInterval int64
+	// See HeaderRangeBatchSize option
+	headerRangeBatchSize int64
 	// See ConfirmationFunction option
 	confirmationFn func(action string) bool
 
 	quit chan struct{}
 
-	logger log.Logger
+	metrics *Metrics
+	logger  log.Logger
 }
 
 // NewClient returns a new light client. It returns an error if it fails to
@@ -231,7 +294,11 @@ func NewClientFromTrustedStore(
 		pruningSize:      defaultPruningSize,
 		confirmationFn:   func(action string) bool { return true },
 		quit:             make(chan struct{}),
+		metrics:          NopMetrics(),
 		logger:           log.NewNopLogger(),
+
+		headerRangeBatchSize:    defaultHeaderRangeBatchSize,
+		primaryFailureThreshold: defaultPrimaryFailureThreshold,
 	}
 
 	for _, o := range options {
@@ -242,6 +309,7 @@ func NewClientFromTrustedStore(
 	if len(c.witnesses) == 0 {
 		return nil, ErrNoWitnesses
 	}
+	c.numWitnesses = len(c.witnesses)
 
 	// Verify witnesses are all on the same chain.
 	for i, w := range witnesses {
@@ -250,16 +318,30 @@ func NewClientFromTrustedStore(
 				i, w, w.ChainID(), chainID)
 		}
 	}
+	for i, w := range c.spareWitnesses {
+		if w.ChainID() != chainID {
+			return nil, fmt.Errorf("spare witness #%d: %v is on another chain %s, expected %s",
+				i, w, w.ChainID(), chainID)
+		}
+	}
 
 	// Validate trust level.
 	if err := ValidateTrustLevel(c.trustLevel); err != nil {
 		return nil, err
 	}
 
+	if c.headerRangeBatchSize <= 0 {
+		return nil, fmt.Errorf("header range batch size must be positive, got %d", c.headerRangeBatchSize)
+	}
+
 	if err := c.restoreTrustedLightBlock(); err != nil {
 		return nil, err
 	}
 
+	if err := c.prune(); err != nil {
+		return nil, fmt.Errorf("prune: %w", err)
+	}
+
 	return c, nil
 }
 
@@ -577,6 +659,12 @@ func (c *Client) verifyLightBlock(ctx context.Context, newLightBlock *types.Ligh
 		return fmt.Errorf("can't get first light block height: %w", err)
 	}
 
+	start, verificationType := time.Now(), c.verificationMode.String()
+	defer func() {
+		c.metrics.Verifications.With("type", verificationType).Add(1)
+		c.metrics.VerificationDuration.With("type", verificationType).Observe(time.Since(start).Seconds())
+	}()
+
 	switch {
 	// Verifying forwards
 	case newLightBlock.Height >= c.latestTrustedBlock.Height:
@@ -584,6 +672,7 @@ func (c *Client) verifyLightBlock(ctx context.Context, newLightBlock *types.Ligh
 
 	// Verifying backwards
 	case newLightBlock.Height < firstBlockHeight:
+		verificationType = "backwards"
 		var firstBlock *types.LightBlock
 		firstBlock, err = c.trustedStore.LightBlock(firstBlockHeight)
 		if err != nil {
@@ -699,13 +788,14 @@ func (c *Client) verifySequential(
 // see VerifyHeader
 //
 // verifySkipping finds the middle light block between a trusted and new light block,
-// reiterating the action until it verifies a light block. A cache of light blocks
-// requested from source is kept such that when a verification is made, and the
+// reiterating the action until it verifies a light block. The middle light blocks
+// are requested with fetch. A cache of light blocks fetched is kept such that
+// when a verification is made, and the
 // light client tries again to verify the new light block in the middle, the light
 // client does not need to ask for all the same light blocks again.
 func (c *Client) verifySkipping(
 	ctx context.Context,
-	source provider.Provider,
+	fetch func(ctx context.Context, height int64) (*types.LightBlock, error),
 	trustedBlock *types.LightBlock,
 	newLightBlock *types.LightBlock,
 	now time.Time) ([]*types.LightBlock, error) {
@@ -713,10 +803,14 @@ func (c *Client) verifySkipping(
 	var (
 		blockCache = []*types.LightBlock{newLightBlock}
 		depth      = 0
+		maxDepth   = 0
 
 		verifiedBlock = trustedBlock
 		trace         = []*types.LightBlock{trustedBlock}
 	)
+	defer func() {
+		c.metrics.BisectionDepth.Observe(float64(maxDepth))
+	}()
 
 	for {
 		c.logger.Debug("Verify non-adjacent newHeader against verifiedBlock",
@@ -748,7 +842,7 @@ func (c *Client) verifySkipping(
 			if depth == len(blockCache)-1 {
 				pivotHeight := verifiedBlock.Height + (blockCache[depth].Height-verifiedBlock.
 					Height)*verifySkippingNumerator/verifySkippingDenominator
-				interimBlock, providerErr := source.LightBlock(ctx, pivotHeight)
+				interimBlock, providerErr := fetch(ctx, pivotHeight)
 				switch providerErr {
 				case nil:
 					blockCache = append(blockCache, interimBlock)
@@ -765,6 +859,9 @@ func (c *Client) verifySkipping(
 				blockCache = append(blockCache, interimBlock)
 			}
 			depth++
+			if depth > maxDepth {
+				maxDepth = depth
+			}
 
 		default:
 			return nil, ErrVerificationFailed{From: verifiedBlock.Height, To: blockCache[depth].Height, Reason: err}
@@ -780,7 +877,7 @@ func (c *Client) verifySkippingAgainstPrimary(
 	newLightBlock *types.LightBlock,
 	now time.Time) error {
 
-	trace, err := c.verifySkipping(ctx, c.primary, trustedBlock, newLightBlock, now)
+	trace, err := c.verifySkipping(ctx, c.lightBlockFromPrimary, trustedBlock, newLightBlock, now)
 
 	switch errors.Unwrap(err).(type) {
 	case ErrInvalidHeader:
@@ -914,19 +1011,99 @@ func (c *Client) updateTrustedLightBlock(l *types.LightBlock) error {
 		return fmt.Errorf("failed to save trusted header: %w", err)
 	}
 
-	if c.pruningSize > 0 {
-		if err := c.trustedStore.Prune(c.pruningSize); err != nil {
-			return fmt.Errorf("prune: %w", err)
-		}
+	if c.latestTrustedBlock == nil || l.Height > c.latestTrustedBlock.Height {
+		c.latestTrustedBlock = l
 	}
 
-	if c.latestTrustedBlock == nil || l.Height > c.latestTrustedBlock.Height {
+	if err := c.prune(); err != nil {
+		return fmt.Errorf("prune: %w", err)
+	}
+
+	return nil
+}
+
+// updateTrustedLightBlocks saves the light blocks, which are in ascending
+// height order, to the trusted store atomically.
+func (c *Client) updateTrustedLightBlocks(lbs []*types.LightBlock) error {
+	c.logger.Debug("updating trusted light blocks", "from", lbs[0].Height, "to", lbs[len(lbs)-1].Height)
+
+	if err := c.trustedStore.SaveLightBlocks(lbs); err != nil {
+		return fmt.Errorf("failed to save trusted headers: %w", err)
+	}
+
+	if l := lbs[len(lbs)-1]; c.latestTrustedBlock == nil || l.Height > c.latestTrustedBlock.Height {
 		c.latestTrustedBlock = l
 	}
 
+	if err := c.prune(); err != nil {
+		return fmt.Errorf("prune: %w", err)
+	}
+
 	return nil
 }
 
+// prune removes the light blocks which aren't kept according to the
+// PruningSize and PruningInterval options.
+func (c *Client) prune() error {
+	var err error
+	switch {
+	case c.pruningSize == 0:
+	case c.pruningInterval <= 0:
+		err = c.trustedStore.Prune(c.pruningSize)
+	default:
+		err = c.trustedStore.PruneFunc(c.prunableHeights)
+	}
+	c.metrics.TrustedStoreSize.Set(float64(c.trustedStore.Size()))
+	return err
+}
+
+// prunableHeights returns the heights of the light blocks which aren't kept
+// according to the PruningInterval option, given the heights of all the light
+// blocks in ascending order.
+func (c *Client) prunableHeights(heights []int64) ([]int64, error) {
+	if len(heights) <= int(c.pruningSize) {
+		return nil, nil
+	}
+
+	var anchor int64
+	if c.latestTrustedBlock != nil {
+		anchor = c.latestTrustedBlock.Height
+	}
+
+	var (
+		prunable       []int64
+		lastKept       = heights[0] // the root of trust
+		withinTrusting = false      // a kept light block is within the trusting period
+		now            = time.Now()
+	)
+	for _, height := range heights[1 : len(heights)-int(c.pruningSize)] {
+		switch {
+		case height == anchor:
+			continue
+		case height >= lastKept+c.pruningInterval:
+			lastKept = height
+			continue
+		case !withinTrusting:
+			l, err := c.trustedStore.LightBlock(height)
+			if err != nil {
+				return nil, fmt.Errorf("can't get light block %d: %w", height, err)
+			}
+			if !HeaderExpired(l.SignedHeader, c.trustingPeriod, now) {
+				withinTrusting = true
+				lastKept = height
+				continue
+			}
+		}
+		prunable = append(prunable, height)
+	}
+
+	if len(prunable) > 0 {
+		c.logger.Debug("Pruning light blocks", "count", len(prunable),
+			"from", prunable[0], "to", prunable[len(prunable)-1])
+	}
+	return prunable, nil
+}
+
 // backwards verification (see VerifyHeaderBackwards func in the spec) verifies
 // headers before a trusted header. If a sent header is invalid the primary is
 // replaced with another provider and the operation is repeated.
@@ -981,15 +1158,24 @@ func (c *Client) backwards(
 // lightBlockFromPrimary retrieves the lightBlock from the primary provider
 // at the specified height. This method also handles provider behavior as follows:
 //
-//  1. If the provider does not respond or does not have the block, it tries again
-//     with a different provider
+//  1. If the provider does not respond (see PrimaryFailureThreshold) or does not
+//     have the block, it tries again with a different provider, the primary
+//     becoming a suspect
 //  2. If all providers return the same error, the light client forwards the error to
 //     where the initial request came from
 //  3. If the provider provides an invalid light block, is deemed unreliable or returns
 //     any other error, the primary is permanently dropped and is replaced by a witness.
 func (c *Client) lightBlockFromPrimary(ctx context.Context, height int64) (*types.LightBlock, error) {
 	c.providerMutex.Lock()
-	l, err := c.primary.LightBlock(ctx, height)
+	l, err := c.requestLightBlock(ctx, c.primary, height)
+	retry := false
+	switch err {
+	case nil:
+		c.primaryFailures = 0
+	case provider.ErrNoResponse:
+		c.primaryFailures++
+		retry = c.primaryFailures < c.primaryFailureThreshold
+	}
 	c.providerMutex.Unlock()
 
 	switch err {
@@ -1001,6 +1187,12 @@ func (c *Client) lightBlockFromPrimary(ctx context.Context, height int64) (*type
 		return l, err
 
 	case provider.ErrNoResponse, provider.ErrLightBlockNotFound, provider.ErrHeightTooHigh:
+		if retry {
+			c.logger.Info("error from light block request from primary, retrying...",
+				"error", err, "height", height, "primary", c.primary)
+			return c.lightBlockFromPrimary(ctx, height)
+		}
+
 		// we find a new witness to replace the primary
 		c.logger.Info("error from light block request from primary, replacing...",
 			"error", err, "height", height, "primary", c.primary)
@@ -1015,6 +1207,28 @@ func (c *Client) lightBlockFromPrimary(ctx context.Context, height int64) (*type
 	}
 }
 
+// requestLightBlock requests the light block at height from the provider,
+// recording the request in the metrics.
+func (c *Client) requestLightBlock(ctx context.Context, p provider.Provider, height int64) (*types.LightBlock, error) {
+	role := "witness"
+	if p == c.primary {
+		role = "primary"
+	}
+
+	l, err := p.LightBlock(ctx, height)
+	c.recordRequest(role, err)
+	return l, err
+}
+
+// recordRequest records a light block request to a provider with the given
+// role in the metrics.
+func (c *Client) recordRequest(role string, err error) {
+	c.metrics.ProviderRequests.With("role", role).Add(1)
+	if err != nil {
+		c.metrics.ProviderRequestFailures.With("role", role).Add(1)
+	}
+}
+
 // NOTE: requires a providerMutex lock
 func (c *Client) removeWitnesses(indexes []int) error {
 	// check that we will still have witnesses remaining
@@ -1054,6 +1268,8 @@ func (c *Client) findNewPrimary(ctx context.Context, height int64, remove bool)
 	var (
 		witnessResponsesC = make(chan witnessResponse, len(c.witnesses))
 		witnessesToRemove []int
+		reasons           []error
+		statuses          []WitnessStatus
 		lastError         error
 		wg                sync.WaitGroup
 	)
@@ -1066,7 +1282,7 @@ func (c *Client) findNewPrimary(ctx context.Context, height int64, remove bool)
 		go func(witnessIndex int, witnessResponsesC chan witnessResponse) {
 			defer wg.Done()
 
-			lb, err := c.witnesses[witnessIndex].LightBlock(subctx, height)
+			lb, err := c.requestLightBlock(subctx, c.witnesses[witnessIndex], height)
 			witnessResponsesC <- witnessResponse{lb, witnessIndex, err}
 		}(index, witnessResponsesC)
 	}
@@ -1081,21 +1297,20 @@ func (c *Client) findNewPrimary(ctx context.Context, height int64, remove bool)
 
 			wg.Wait() // wait for all goroutines to finish
 
-			// if we are not intending on removing the primary then append the old primary to the end of the witness slice
-			if !remove {
-				c.witnesses = append(c.witnesses, c.primary)
-			}
-
-			// promote respondent as the new primary
-			c.logger.Debug("found new primary", "primary", c.witnesses[response.witnessIndex])
-			c.primary = c.witnesses[response.witnessIndex]
-
-			// add promoted witness to the list of witnesses to be removed
-			witnessesToRemove = append(witnessesToRemove, response.witnessIndex)
+			newPrimary := c.witnesses[response.witnessIndex]
+			c.logger.Debug("found new primary", "primary", newPrimary)
 
-			// remove witnesses marked as bad (the client must do this before we alter the witness slice and change the indexes
+			// evict witnesses marked as bad (the client must do this before we alter the witness slice and change the indexes
 			// of witnesses). Removal is done in descending order
-			if err := c.removeWitnesses(witnessesToRemove); err != nil {
+			if len(witnessesToRemove) > 0 {
+				if err := c.replaceWitnesses(witnessesToRemove, reasons, statuses); err != nil {
+					return nil, err
+				}
+			}
+
+			// promote respondent as the new primary. If we are not intending on removing the primary then it
+			// becomes a suspect
+			if err := c.failover(newPrimary, remove); err != nil {
 				return nil, err
 			}
 
@@ -1109,17 +1324,24 @@ func (c *Client) findNewPrimary(ctx context.Context, height int64, remove bool)
 				"error", response.err, "primary", c.witnesses[response.witnessIndex])
 			continue
 
-		// process malevolent errors like ErrUnreliableProvider and ErrBadLightBlock by removing the witness
+		// process malevolent errors like ErrBadLightBlock by evicting the witness, and
+		// other errors, such as failing to connect, by demoting it to a spare
 		default:
 			lastError = response.err
 			c.logger.Error("error on light block request from witness, removing...",
 				"error", response.err, "primary", c.witnesses[response.witnessIndex])
 			witnessesToRemove = append(witnessesToRemove, response.witnessIndex)
+			reasons = append(reasons, response.err)
+			if errors.As(response.err, &provider.ErrBadLightBlock{}) {
+				statuses = append(statuses, WitnessEvicted)
+			} else {
+				statuses = append(statuses, WitnessSpare)
+			}
 		}
 	}
 
-	// remove witnesses marked as bad. Removal is done in descending order
-	if err := c.removeWitnesses(witnessesToRemove); err != nil {
+	// evict or demote witnesses marked as bad. Removal is done in descending order
+	if err := c.replaceWitnesses(witnessesToRemove, reasons, statuses); err != nil {
 		c.logger.Error("failed to remove witnesses", "err", err, "witnessesToRemove", witnessesToRemove)
 	}
 
@@ -1144,7 +1366,10 @@ func (c *Client) compareFirstLightBlockWithWitnesses(ctx context.Context, l *typ
 		go c.compareNewLightBlockWithWitness(compareCtx, errc, l, witness, i)
 	}
 
-	witnessesToRemove := make([]int, 0, len(c.witnesses))
+	var (
+		witnessesToRemove = make([]int, 0, len(c.witnesses))
+		reasons           = make([]error, 0, len(c.witnesses))
+	)
 
 	// handle errors from the header comparisons as they come in
 	for i := 0; i < cap(errc); i++ {
@@ -1154,6 +1379,7 @@ func (c *Client) compareFirstLightBlockWithWitnesses(ctx context.Context, l *typ
 		case nil:
 			continue
 		case ErrConflictingHeaders:
+			c.metrics.ConflictingHeaders.Add(1)
 			c.logger.Error("Witness reports a conflicting header. "+
 				"Please check if the primary is correct or use a different witness.",
 				"witness", c.witnesses[e.WitnessIndex], "err", err)
@@ -1164,6 +1390,7 @@ func (c *Client) compareFirstLightBlockWithWitnesses(ctx context.Context, l *typ
 				"witness", c.witnesses[e.WitnessIndex],
 				"err", err)
 			witnessesToRemove = append(witnessesToRemove, e.WitnessIndex)
+			reasons = append(reasons, e)
 		case ErrProposerPrioritiesDiverge:
 			c.logger.Error("Witness reports conflicting proposer priorities. "+
 				"Please check if the primary is correct or use a different witness.",
@@ -1181,8 +1408,8 @@ func (c *Client) compareFirstLightBlockWithWitnesses(ctx context.Context, l *typ
 
 	}
 
-	// remove witnesses that have misbehaved
-	if err := c.removeWitnesses(witnessesToRemove); err != nil {
+	// evict witnesses that have misbehaved
+	if err := c.evictWitnesses(witnessesToRemove, reasons); err != nil {
 		c.logger.Error("Failed to remove witnesses", "err", err, "witnessesToRemove", witnessesToRemove)
 	}
 
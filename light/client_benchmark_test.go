@@ -108,3 +108,54 @@ func BenchmarkBackwards(b *testing.B) {
 		}
 	}
 }
+
+// newBenchmarkClient returns a light client of benchmarkFullNode trusting the
+// genesis block, which verifies sequentially.
+func newBenchmarkClient(b *testing.B) *light.Client {
+	c, err := light.NewClient(
+		context.Background(),
+		chainID,
+		light.TrustOptions{
+			Period: 24 * time.Hour,
+			Height: 1,
+			Hash:   genesisBlock.Hash(),
+		},
+		benchmarkFullNode,
+		[]provider.Provider{benchmarkFullNode},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.SequentialVerification(),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+func BenchmarkVerifyHeaderRange(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		c := newBenchmarkClient(b)
+		b.StartTimer()
+
+		err := c.VerifyHeaderRange(context.Background(), 2, 200, bTime.Add(1000*time.Minute))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyHeaderPerHeight(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		c := newBenchmarkClient(b)
+		b.StartTimer()
+
+		for height := int64(2); height <= 200; height++ {
+			_, err := c.VerifyLightBlockAtHeight(context.Background(), height, bTime.Add(1000*time.Minute))
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
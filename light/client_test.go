@@ -779,7 +779,11 @@ func TestClientReplacesPrimaryWithWitnessIfPrimaryIsUnavailable(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.NotEqual(t, c.Primary(), deadNode)
-	assert.Equal(t, 2, len(c.Witnesses()))
+	// the dead primary is a suspect, not a witness
+	assert.Equal(t, 1, len(c.Witnesses()))
+	health := c.WitnessHealth()
+	assert.Equal(t, deadNode, health[len(health)-1].Witness)
+	assert.Equal(t, light.WitnessSuspect, health[len(health)-1].Status)
 }
 
 func TestClient_BackwardsVerification(t *testing.T) {
@@ -1031,6 +1035,79 @@ func TestClientPrunesHeadersAndValidatorSets(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestClientPrunesAtInterval(t *testing.T) {
+	now := time.Now()
+	node := mockp.New(genMockNode(chainID, 100, 3, 0, now.Add(-100*time.Minute)))
+	options := []light.Option{
+		light.Logger(log.TestingLogger()),
+		light.SequentialVerification(),
+		light.PruningSize(3),
+		light.PruningInterval(20),
+	}
+
+	trustedHeights := func(c *light.Client) []int64 {
+		var heights []int64
+		for height := int64(1); height <= 100; height++ {
+			if _, err := c.TrustedLightBlock(height); err == nil {
+				heights = append(heights, height)
+			}
+		}
+		return heights
+	}
+
+	t.Run("after each update", func(t *testing.T) {
+		first, err := node.LightBlock(ctx, 1)
+		require.NoError(t, err)
+		c, err := light.NewClient(
+			ctx,
+			chainID,
+			light.TrustOptions{Period: trustPeriod, Height: 1, Hash: first.Hash()},
+			node,
+			[]provider.Provider{node},
+			dbs.New(dbm.NewMemDB(), chainID),
+			options...,
+		)
+		require.NoError(t, err)
+
+		for height := int64(2); height <= 100; height++ {
+			_, err = c.VerifyLightBlockAtHeight(ctx, height, now)
+			require.NoError(t, err)
+		}
+		// the root of trust, the oldest light block within the trusting
+		// period, one every 20 heights from it and the 3 most recent
+		assert.Equal(t, []int64{1, 2, 22, 42, 62, 82, 98, 99, 100}, trustedHeights(c))
+
+		// heights whose neighbors were pruned are verified by bisection from
+		// the closest trusted light block
+		for _, height := range []int64{45, 31, 97, 89} {
+			_, err = c.VerifyLightBlockAtHeight(ctx, height, now)
+			require.NoError(t, err, height)
+		}
+		// the latest trusted light block is still the trust anchor
+		l, err := c.TrustedLightBlock(0)
+		require.NoError(t, err)
+		assert.EqualValues(t, 100, l.Height)
+	})
+
+	t.Run("on startup", func(t *testing.T) {
+		db := dbs.New(dbm.NewMemDB(), chainID)
+		for height := int64(1); height <= 100; height++ {
+			l, err := node.LightBlock(ctx, height)
+			require.NoError(t, err)
+			require.NoError(t, db.SaveLightBlock(l))
+		}
+
+		// the light blocks below height 30 are out of the trusting period
+		period := 70*time.Minute + 30*time.Second
+		c, err := light.NewClientFromTrustedStore(chainID, period, deadNode, []provider.Provider{deadNode}, db,
+			options...)
+		require.NoError(t, err)
+		expected := []int64{1, 21, 30, 50, 70, 90, 98, 99, 100}
+		assert.Equal(t, expected, trustedHeights(c))
+		assert.EqualValues(t, len(expected), db.Size())
+	})
+}
+
 func TestClientEnsureValidHeadersAndValSets(t *testing.T) {
 	emptyValSet := &types.ValidatorSet{
 		Validators: nil,
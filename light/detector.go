@@ -34,6 +34,7 @@ func (c *Client) detectDivergence(ctx context.Context, primaryTrace []*types.Lig
 		lastVerifiedBlock  = primaryTrace[len(primaryTrace)-1]
 		lastVerifiedHeader = lastVerifiedBlock.SignedHeader
 		witnessesToRemove  = make([]int, 0)
+		reasons            = make([]error, 0)
 	)
 	c.logger.Debug("Running detector against trace", "endBlockHeight", lastVerifiedHeader.Height,
 		"endBlockHash", lastVerifiedHeader.Hash, "length", len(primaryTrace))
@@ -41,6 +42,12 @@ func (c *Client) detectDivergence(ctx context.Context, primaryTrace []*types.Lig
 	c.providerMutex.Lock()
 	defer c.providerMutex.Unlock()
 
+	// replace the witnesses which are offline or behind, so that the
+	// comparisons below don't time out
+	if err := c.maybeCheckWitnesses(ctx, lastVerifiedHeader.Height); err != nil {
+		return err
+	}
+
 	if len(c.witnesses) == 0 {
 		return ErrNoWitnesses
 	}
@@ -60,6 +67,7 @@ func (c *Client) detectDivergence(ctx context.Context, primaryTrace []*types.Lig
 		case nil: // at least one header matched
 			headerMatched = true
 		case ErrConflictingHeaders:
+			c.metrics.ConflictingHeaders.Add(1)
 			// We have conflicting headers. This could possibly imply an attack on the light client.
 			// First we need to verify the witness's header using the same skipping verification and then we
 			// need to find the point that the headers diverge and examine this for any evidence of an attack.
@@ -73,6 +81,7 @@ func (c *Client) detectDivergence(ctx context.Context, primaryTrace []*types.Lig
 			}
 			// if attempt to generate conflicting headers failed then remove witness
 			witnessesToRemove = append(witnessesToRemove, e.WitnessIndex)
+			reasons = append(reasons, e)
 
 		case errBadWitness:
 			// these are all melevolent errors and should result in removing the
@@ -80,6 +89,7 @@ func (c *Client) detectDivergence(ctx context.Context, primaryTrace []*types.Lig
 			c.logger.Info("witness returned an error during header comparison, removing...",
 				"witness", c.witnesses[e.WitnessIndex], "err", err)
 			witnessesToRemove = append(witnessesToRemove, e.WitnessIndex)
+			reasons = append(reasons, e)
 		case ErrProposerPrioritiesDiverge:
 			c.logger.Info("witness reported validator set with different proposer priorities",
 				"witness", c.witnesses[e.WitnessIndex], "err", err)
@@ -94,8 +104,8 @@ func (c *Client) detectDivergence(ctx context.Context, primaryTrace []*types.Lig
 		}
 	}
 
-	// remove witnesses that have misbehaved
-	if err := c.removeWitnesses(witnessesToRemove); err != nil {
+	// evict witnesses that have misbehaved
+	if err := c.evictWitnesses(witnessesToRemove, reasons); err != nil {
 		return err
 	}
 
@@ -123,7 +133,7 @@ func (c *Client) compareNewLightBlockWithWitness(ctx context.Context, errc chan
 ) {
 	h := l.SignedHeader
 
-	lightBlock, err := witness.LightBlock(ctx, h.Height)
+	lightBlock, err := c.requestLightBlock(ctx, witness, h.Height)
 	switch err {
 	// no error means we move on to checking the hash of the two headers
 	case nil:
@@ -334,7 +344,7 @@ func (c *Client) examineConflictingHeaderAgainstTrace(
 			// before sending back the divergent block and trace we need to ensure we have verified
 			// the final gap between the previouslyVerifiedBlock and the targetBlock
 			if previouslyVerifiedBlock.Height != targetBlock.Height {
-				sourceTrace, err = c.verifySkipping(ctx, source, previouslyVerifiedBlock, targetBlock, now)
+				sourceTrace, err = c.verifySkipping(ctx, c.lightBlockFrom(source), previouslyVerifiedBlock, targetBlock, now)
 				if err != nil {
 					return nil, nil, fmt.Errorf("verifySkipping of conflicting header failed: %w", err)
 				}
@@ -346,7 +356,7 @@ func (c *Client) examineConflictingHeaderAgainstTrace(
 		if traceBlock.Height == targetBlock.Height {
 			sourceBlock = targetBlock
 		} else {
-			sourceBlock, err = source.LightBlock(ctx, traceBlock.Height)
+			sourceBlock, err = c.requestLightBlock(ctx, source, traceBlock.Height)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to examine trace: %w", err)
 			}
@@ -365,7 +375,7 @@ func (c *Client) examineConflictingHeaderAgainstTrace(
 
 		// we check that the source provider can verify a block at the same height of the
 		// intermediate height
-		sourceTrace, err = c.verifySkipping(ctx, source, previouslyVerifiedBlock, sourceBlock, now)
+		sourceTrace, err = c.verifySkipping(ctx, c.lightBlockFrom(source), previouslyVerifiedBlock, sourceBlock, now)
 		if err != nil {
 			return nil, nil, fmt.Errorf("verifySkipping of conflicting header failed: %w", err)
 		}
@@ -394,7 +404,7 @@ func (c *Client) getTargetBlockOrLatest(
 	height int64,
 	witness provider.Provider,
 ) (bool, *types.LightBlock, error) {
-	lightBlock, err := witness.LightBlock(ctx, 0)
+	lightBlock, err := c.requestLightBlock(ctx, witness, 0)
 	if err != nil {
 		return false, nil, err
 	}
@@ -409,7 +419,7 @@ func (c *Client) getTargetBlockOrLatest(
 		// the witness has caught up. We recursively call the function again. However in order
 		// to avoud a wild goose chase where the witness sends us one header below and one header
 		// above the height we set a timeout to the context
-		lightBlock, err := witness.LightBlock(ctx, height)
+		lightBlock, err := c.requestLightBlock(ctx, witness, height)
 		return true, lightBlock, err
 	}
 
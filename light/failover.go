@@ -0,0 +1,111 @@
+package light
+
+import (
+	"context"
+
+	"github.com/tendermint/tendermint/light/provider"
+	"github.com/tendermint/tendermint/types"
+)
+
+// PrimaryFailureThreshold option sets the number of consecutive requests the
+// primary must fail to respond to, or health checks it must fail (see
+// WitnessHealthCheckInterval), before it's replaced by a witness. Default: 1.
+func PrimaryFailureThreshold(n uint16) Option {
+	return func(c *Client) {
+		c.primaryFailureThreshold = int(n)
+	}
+}
+
+// OnPrimaryFailover option sets a function called whenever the primary is
+// replaced by a witness. It's called with the providers locked, so it must not
+// call the light client.
+func OnPrimaryFailover(fn func(oldPrimary, newPrimary provider.Provider)) Option {
+	return func(c *Client) {
+		c.failoverFn = fn
+	}
+}
+
+// failover replaces the primary by newPrimary, one of the witnesses, and
+// replenishes the witnesses from the spares. The old primary is dropped if
+// drop is true. Otherwise, it becomes a suspect, which the health checks turn
+// back into a spare once it is healthy, unless no witness would be left to
+// cross-check the new primary against.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) failover(newPrimary provider.Provider, drop bool) error {
+	oldPrimary := c.primary
+	for i, w := range c.witnesses {
+		if w == newPrimary {
+			c.witnesses = append(c.witnesses[:i], c.witnesses[i+1:]...)
+			break
+		}
+	}
+	c.primary = newPrimary
+	c.primaryFailures = 0
+	c.replenishWitnesses(0)
+
+	switch {
+	case drop:
+	case len(c.witnesses) == 0:
+		c.witnesses = append(c.witnesses, oldPrimary)
+	default:
+		c.witnessHealthOf(oldPrimary, WitnessSuspect)
+		c.suspects = append(c.suspects, oldPrimary)
+	}
+
+	c.logger.Info("Replaced primary", "old", oldPrimary, "new", newPrimary)
+	c.metrics.PrimaryFailovers.Add(1)
+	if c.failoverFn != nil {
+		c.failoverFn(oldPrimary, newPrimary)
+	}
+
+	if len(c.witnesses) == 0 {
+		return ErrNoWitnesses
+	}
+	return nil
+}
+
+// checkPrimary counts a failed health check of the primary, and replaces it by
+// the healthiest witness once it failed PrimaryFailureThreshold times in a
+// row: the one with the highest latest height, then the lowest latency.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) checkPrimary(height int64) {
+	if c.witnessHealthOf(c.primary, 0).healthy(height) {
+		c.primaryFailures = 0
+		return
+	}
+
+	c.primaryFailures++
+	if c.primaryFailures < c.primaryFailureThreshold {
+		return
+	}
+
+	var best *WitnessHealth
+	for _, w := range c.witnesses {
+		health := c.witnessHealthOf(w, WitnessActive)
+		if health.LastChecked.IsZero() || !health.healthy(height) {
+			continue
+		}
+		if best == nil || health.LatestHeight > best.LatestHeight ||
+			(health.LatestHeight == best.LatestHeight && health.Latency < best.Latency) {
+			best = health
+		}
+	}
+	if best == nil {
+		c.logger.Info("Primary is unhealthy but no healthy witness can replace it", "primary", c.primary)
+		return
+	}
+
+	if err := c.failover(best.Witness, false); err != nil {
+		c.logger.Error("Failed to replace primary", "err", err)
+	}
+}
+
+// lightBlockFrom returns a function requesting light blocks from the
+// provider.
+func (c *Client) lightBlockFrom(p provider.Provider) func(context.Context, int64) (*types.LightBlock, error) {
+	return func(ctx context.Context, height int64) (*types.LightBlock, error) {
+		return c.requestLightBlock(ctx, p, height)
+	}
+}
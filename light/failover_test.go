@@ -0,0 +1,149 @@
+package light_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/light"
+	"github.com/tendermint/tendermint/light/provider"
+	mockp "github.com/tendermint/tendermint/light/provider/mock"
+	dbs "github.com/tendermint/tendermint/light/store/db"
+	"github.com/tendermint/tendermint/types"
+)
+
+// dyingProvider serves a number of light block requests, then stops
+// responding until revived.
+type dyingProvider struct {
+	*mockp.Mock
+
+	mtx          sync.Mutex
+	requestsLeft int
+}
+
+func (p *dyingProvider) LightBlock(ctx context.Context, height int64) (*types.LightBlock, error) {
+	p.mtx.Lock()
+	if p.requestsLeft == 0 {
+		p.mtx.Unlock()
+		return nil, provider.ErrNoResponse
+	}
+	p.requestsLeft--
+	p.mtx.Unlock()
+
+	return p.Mock.LightBlock(ctx, height)
+}
+
+func (p *dyingProvider) revive() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.requestsLeft = -1
+}
+
+type failover struct {
+	oldPrimary, newPrimary provider.Provider
+}
+
+func TestClientFailsOverWhenPrimaryDiesMidVerification(t *testing.T) {
+	// the validators change every other height, so that verifying the last
+	// height from the first one requires bisection
+	headers, vals, _ := genMockNodeWithKeys(chainID, 10, 5, 2, bTime)
+
+	var (
+		// the primary serves the trusted and target light blocks, then dies
+		primary   = &dyingProvider{Mock: mockp.New(chainID, headers, vals), requestsLeft: 2}
+		witness1  = mockp.New(chainID, headers, vals)
+		witness2  = mockp.New(chainID, headers, vals)
+		failovers []failover
+		m         = light.NopMetrics()
+	)
+	m.PrimaryFailovers = generic.NewCounter("primary_failovers")
+
+	c, err := light.NewClient(
+		ctx,
+		chainID,
+		light.TrustOptions{
+			Period: 4 * time.Hour,
+			Height: 1,
+			Hash:   headers[1].Hash(),
+		},
+		primary,
+		[]provider.Provider{witness1, witness2},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.PrimaryFailureThreshold(2),
+		light.OnPrimaryFailover(func(oldPrimary, newPrimary provider.Provider) {
+			failovers = append(failovers, failover{oldPrimary, newPrimary})
+		}),
+		light.WithMetrics(m),
+	)
+	require.NoError(t, err)
+
+	l, err := c.VerifyLightBlockAtHeight(ctx, 10, bTime.Add(1*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, headers[10].Hash(), l.Hash())
+
+	require.Len(t, failovers, 1)
+	assert.Equal(t, primary, failovers[0].oldPrimary)
+	assert.Equal(t, c.Primary(), failovers[0].newPrimary)
+	assert.EqualValues(t, 1, m.PrimaryFailovers.(*generic.Counter).Value())
+
+	// the new primary is still cross-checked against the other witness
+	require.Len(t, c.Witnesses(), 1)
+	assert.NotSame(t, c.Primary(), c.Witnesses()[0])
+	health := c.WitnessHealth()
+	require.Len(t, health, 2)
+	assert.Equal(t, primary, health[1].Witness)
+	assert.Equal(t, light.WitnessSuspect, health[1].Status)
+}
+
+func TestClientFailsOverWhenPrimaryFailsHealthChecks(t *testing.T) {
+	var (
+		// the primary serves the trusted light block, then dies
+		primary       = &dyingProvider{Mock: mockp.New(chainID, headerSet, valSet), requestsLeft: 1}
+		staleWitness  = mockp.New(chainID, map[int64]*types.SignedHeader{1: h1, 2: h2}, valSet)
+		latestWitness = mockp.New(chainID, headerSet, valSet)
+		failovers     []failover
+	)
+
+	c, err := light.NewClient(
+		ctx,
+		chainID,
+		trustOptions,
+		primary,
+		[]provider.Provider{staleWitness, latestWitness},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.PrimaryFailureThreshold(2),
+		light.OnPrimaryFailover(func(oldPrimary, newPrimary provider.Provider) {
+			failovers = append(failovers, failover{oldPrimary, newPrimary})
+		}),
+	)
+	require.NoError(t, err)
+
+	// a single failed health check is tolerated
+	require.NoError(t, c.CheckWitnesses(ctx))
+	assert.Equal(t, primary, c.Primary())
+	assert.Empty(t, failovers)
+
+	// the witness with the highest latest light block replaces the primary
+	require.NoError(t, c.CheckWitnesses(ctx))
+	assert.Equal(t, latestWitness, c.Primary())
+	assert.Equal(t, []failover{{primary, latestWitness}}, failovers)
+	assert.Equal(t, []provider.Provider{staleWitness}, c.Witnesses())
+
+	// the old primary becomes a witness again once it's healthy
+	primary.revive()
+	require.NoError(t, c.CheckWitnesses(ctx))
+	assert.Equal(t, []provider.Provider{staleWitness, primary}, c.Witnesses())
+	for _, health := range c.WitnessHealth() {
+		assert.Equal(t, light.WitnessActive, health.Status)
+	}
+}
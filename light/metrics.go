@@ -0,0 +1,115 @@
+package light
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "light"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of light blocks verified, by type: sequential, skipping or
+	// backwards.
+	Verifications metrics.Counter
+	// Time spent verifying a light block, by type, in seconds.
+	VerificationDuration metrics.Histogram
+	// Depth of the bisections of skipping verifications: the number of
+	// intermediate light blocks requested at once to verify a light block.
+	BisectionDepth metrics.Histogram
+	// Number of light block requests to providers, by role: primary or
+	// witness.
+	ProviderRequests metrics.Counter
+	// Number of light block requests to providers which failed, by role.
+	ProviderRequestFailures metrics.Counter
+	// Number of light blocks in the trusted store.
+	TrustedStoreSize metrics.Gauge
+	// Number of conflicting headers returned by witnesses.
+	ConflictingHeaders metrics.Counter
+	// Number of times the primary was replaced by a witness.
+	PrimaryFailovers metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Verifications: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "verifications",
+			Help:      "Number of light blocks verified, by type: sequential, skipping or backwards.",
+		}, append(labels, "type")).With(labelsAndValues...),
+		VerificationDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "verification_duration_seconds",
+			Help:      "Time spent verifying a light block, by type, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 12),
+		}, append(labels, "type")).With(labelsAndValues...),
+		BisectionDepth: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "bisection_depth",
+			Help:      "Depth of the bisections of skipping verifications: the number of intermediate light blocks requested at once to verify a light block.",
+			Buckets:   stdprometheus.LinearBuckets(0, 1, 10),
+		}, labels).With(labelsAndValues...),
+		ProviderRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "provider_requests",
+			Help:      "Number of light block requests to providers, by role: primary or witness.",
+		}, append(labels, "role")).With(labelsAndValues...),
+		ProviderRequestFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "provider_request_failures",
+			Help:      "Number of light block requests to providers which failed, by role.",
+		}, append(labels, "role")).With(labelsAndValues...),
+		TrustedStoreSize: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "trusted_store_size",
+			Help:      "Number of light blocks in the trusted store.",
+		}, labels).With(labelsAndValues...),
+		ConflictingHeaders: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "conflicting_headers",
+			Help:      "Number of conflicting headers returned by witnesses.",
+		}, labels).With(labelsAndValues...),
+		PrimaryFailovers: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "primary_failovers",
+			Help:      "Number of times the primary was replaced by a witness.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		Verifications:        discard.NewCounter(),
+		VerificationDuration: discard.NewHistogram(),
+		BisectionDepth:       discard.NewHistogram(),
+
+		ProviderRequests:        discard.NewCounter(),
+		ProviderRequestFailures: discard.NewCounter(),
+
+		TrustedStoreSize:   discard.NewGauge(),
+		ConflictingHeaders: discard.NewCounter(),
+		PrimaryFailovers:   discard.NewCounter(),
+	}
+}
@@ -0,0 +1,102 @@
+package light_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/light"
+	"github.com/tendermint/tendermint/light/provider"
+	mockp "github.com/tendermint/tendermint/light/provider/mock"
+	dbs "github.com/tendermint/tendermint/light/store/db"
+)
+
+// labeledCounter keeps one generic counter per label values, since the
+// labeled generic counters don't add to their parent.
+type labeledCounter struct {
+	mtx      sync.Mutex
+	counters map[string]*generic.Counter
+}
+
+func newLabeledCounter() *labeledCounter {
+	return &labeledCounter{counters: make(map[string]*generic.Counter)}
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	key := strings.Join(labelValues, ",")
+	if _, ok := c.counters[key]; !ok {
+		c.counters[key] = generic.NewCounter(key)
+	}
+	return c.counters[key]
+}
+
+func (c *labeledCounter) Add(delta float64) {
+	c.With().Add(delta)
+}
+
+func (c *labeledCounter) Value(labelValues ...string) float64 {
+	return c.With(labelValues...).(*generic.Counter).Value()
+}
+
+func TestClientMetrics(t *testing.T) {
+	// the validators change every other height, so that verifying the last
+	// height from the first one requires bisection
+	headers, vals, _ := genMockNodeWithKeys(chainID, 10, 5, 2, bTime)
+
+	var (
+		verifications   = newLabeledCounter()
+		requests        = newLabeledCounter()
+		requestFailures = newLabeledCounter()
+		m               = light.NopMetrics()
+	)
+	m.Verifications = verifications
+	m.VerificationDuration = generic.NewHistogram("verification_duration_seconds", 10)
+	m.BisectionDepth = generic.NewHistogram("bisection_depth", 10)
+	m.ProviderRequests = requests
+	m.ProviderRequestFailures = requestFailures
+	m.TrustedStoreSize = generic.NewGauge("trusted_store_size")
+	m.ConflictingHeaders = generic.NewCounter("conflicting_headers")
+
+	c, err := light.NewClient(
+		ctx,
+		chainID,
+		light.TrustOptions{
+			Period: 4 * time.Hour,
+			Height: 1,
+			Hash:   headers[1].Hash(),
+		},
+		mockp.New(chainID, headers, vals),
+		[]provider.Provider{mockp.New(chainID, headers, vals)},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.WithMetrics(m),
+	)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, m.TrustedStoreSize.(*generic.Gauge).Value())
+
+	_, err = c.VerifyLightBlockAtHeight(ctx, 10, bTime.Add(1*time.Hour))
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, verifications.Value("type", "skipping"))
+	assert.Zero(t, verifications.Value("type", "sequential"))
+	assert.Positive(t, m.BisectionDepth.(*generic.Histogram).Quantile(1))
+	// the target light block and at least one pivot from the primary
+	assert.GreaterOrEqual(t, requests.Value("role", "primary"), float64(2))
+	assert.Positive(t, requests.Value("role", "witness"))
+	assert.Zero(t, requestFailures.Value("role", "primary"))
+	assert.Zero(t, requestFailures.Value("role", "witness"))
+	assert.EqualValues(t, 2, m.TrustedStoreSize.(*generic.Gauge).Value())
+	assert.Zero(t, m.ConflictingHeaders.(*generic.Counter).Value())
+}
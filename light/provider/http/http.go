@@ -11,6 +11,7 @@ import (
 	"github.com/tendermint/tendermint/light/provider"
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -101,10 +102,17 @@ func (p *http) LightBlock(ctx context.Context, height int64) (*types.LightBlock,
 	return lb, nil
 }
 
-// ReportEvidence calls `/broadcast_evidence` endpoint.
+// ReportEvidence calls `/broadcast_evidence` endpoint. Evidence the node
+// rejected, other than because it was already committed, is an error.
 func (p *http) ReportEvidence(ctx context.Context, ev types.Evidence) error {
-	_, err := p.client.BroadcastEvidence(ctx, ev)
-	return err
+	res, err := p.client.BroadcastEvidence(ctx, ev)
+	if err != nil {
+		return err
+	}
+	if res.Reason != "" && res.Reason != ctypes.EvidenceRejectedCommitted {
+		return fmt.Errorf("evidence rejected (%s): %s", res.Reason, res.Error)
+	}
+	return nil
 }
 
 func (p *http) validatorSet(ctx context.Context, height *int64) (*types.ValidatorSet, error) {
@@ -0,0 +1,176 @@
+package proxy_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/light"
+	"github.com/tendermint/tendermint/light/provider"
+	httpp "github.com/tendermint/tendermint/light/provider/http"
+	"github.com/tendermint/tendermint/light/proxy"
+	lrpc "github.com/tendermint/tendermint/light/rpc"
+	dbs "github.com/tendermint/tendermint/light/store/db"
+	nm "github.com/tendermint/tendermint/node"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	rpchttp "github.com/tendermint/tendermint/rpc/client/http"
+	rpcserver "github.com/tendermint/tendermint/rpc/jsonrpc/server"
+	rpctest "github.com/tendermint/tendermint/rpc/test"
+)
+
+var node *nm.Node
+
+func TestMain(m *testing.M) {
+	// start a CometBFT node (and kvstore) in the background to test against
+	dir, err := os.MkdirTemp("/tmp", "light-proxy-test")
+	if err != nil {
+		panic(err)
+	}
+
+	app := kvstore.NewPersistentKVStoreApplication(dir)
+	app.SetGenBlockEvents()
+	node = rpctest.StartTendermint(app, rpctest.SuppressStdout)
+
+	code := m.Run()
+
+	// and shut down proper at the end
+	rpctest.StopTendermint(node)
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// startProxy serves the proxy routes of a light client of the node, and
+// returns a client of the proxy and a client of the node.
+func startProxy(t *testing.T) (rpcclient.Client, rpcclient.Client) {
+	var (
+		config  = rpctest.GetConfig()
+		chainID = config.ChainID()
+		ctx     = context.Background()
+	)
+
+	nodeClient, err := rpchttp.New(config.RPC.ListenAddress, "/websocket")
+	require.NoError(t, err)
+	// trust a recent block, as the first one has the time of the genesis,
+	// which is out of the trusting period
+	var trustedHeight int64
+	require.Eventually(t, func() bool {
+		status, err := nodeClient.Status(ctx)
+		if err != nil {
+			return false
+		}
+		trustedHeight = status.SyncInfo.LatestBlockHeight - 1
+		return trustedHeight > 1
+	}, 10*time.Second, 100*time.Millisecond)
+
+	primary, err := httpp.New(chainID, config.RPC.ListenAddress)
+	require.NoError(t, err)
+	block, err := primary.LightBlock(ctx, trustedHeight)
+	require.NoError(t, err)
+
+	lc, err := light.NewClient(
+		ctx,
+		chainID,
+		light.TrustOptions{
+			Period: 504 * time.Hour, // 21 days
+			Height: trustedHeight,
+			Hash:   block.Hash(),
+		},
+		primary,
+		[]provider.Provider{primary},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+	)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	rpcserver.RegisterRPCFuncs(mux, proxy.RPCRoutes(lrpc.NewClient(nodeClient, lc)), log.TestingLogger())
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	proxyClient, err := rpchttp.New(server.URL, "/websocket")
+	require.NoError(t, err)
+	return proxyClient, nodeClient
+}
+
+// commitTx commits a kvstore transaction and waits for the block after it,
+// whose header commits to the results of the transaction.
+func commitTx(t *testing.T, c rpcclient.Client, key string) int64 {
+	ctx := context.Background()
+	res, err := c.BroadcastTxCommit(ctx, []byte(fmt.Sprintf("%s=value", key)))
+	require.NoError(t, err)
+	require.True(t, res.DeliverTx.IsOK())
+
+	require.Eventually(t, func() bool {
+		status, err := c.Status(ctx)
+		return err == nil && status.SyncInfo.LatestBlockHeight > res.Height+1
+	}, 10*time.Second, 100*time.Millisecond)
+	return res.Height
+}
+
+func TestProxyHeader(t *testing.T) {
+	proxyClient, nodeClient := startProxy(t)
+	ctx := context.Background()
+	height := commitTx(t, nodeClient, "header")
+
+	res, err := proxyClient.Header(ctx, &height)
+	require.NoError(t, err)
+	expected, err := nodeClient.Header(ctx, &height)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Header.Hash(), res.Header.Hash())
+}
+
+func TestProxyBlockResults(t *testing.T) {
+	proxyClient, nodeClient := startProxy(t)
+	ctx := context.Background()
+	height := commitTx(t, nodeClient, "block_results")
+
+	res, err := proxyClient.BlockResults(ctx, &height)
+	require.NoError(t, err)
+	assert.Equal(t, height, res.Height)
+	require.Len(t, res.TxsResults, 1)
+	assert.True(t, res.TxsResults[0].IsOK())
+	assert.NotEmpty(t, res.BeginBlockEvents)
+}
+
+func TestProxyTxSearch(t *testing.T) {
+	proxyClient, nodeClient := startProxy(t)
+	ctx := context.Background()
+	height := commitTx(t, nodeClient, "tx_search")
+
+	// NOTE: the kvstore doesn't build share proofs, so the transactions can't
+	// be proven here
+	res, err := proxyClient.TxSearch(ctx, "app.key = 'tx_search'", false, nil, nil, "asc")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 1)
+	assert.Equal(t, height, res.Txs[0].Height)
+	assert.True(t, res.Txs[0].TxResult.IsOK())
+
+	_, err = proxyClient.TxSearch(ctx, "app.key = 'tx_search'", true, nil, nil, "asc")
+	assert.Error(t, err)
+}
+
+func TestProxyBlockSearch(t *testing.T) {
+	proxyClient, nodeClient := startProxy(t)
+	ctx := context.Background()
+	height := commitTx(t, nodeClient, "block_search")
+
+	query := fmt.Sprintf("begin_event.foo = 100 AND block.height >= %d AND block.height <= %d", height-4, height)
+	res, err := proxyClient.BlockSearch(ctx, query, nil, nil, "asc")
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Blocks)
+	for _, block := range res.Blocks {
+		assert.LessOrEqual(t, block.Block.Height, height)
+		assert.Equal(t, block.BlockID.Hash, block.Block.Hash())
+	}
+}
@@ -31,6 +31,7 @@ func RPCRoutes(c *lrpc.Client) map[string]*rpcserver.RPCFunc {
 		"header":               rpcserver.NewRPCFunc(makeHeaderFunc(c), "height", rpcserver.Cacheable("height")),
 		"header_by_hash":       rpcserver.NewRPCFunc(makeHeaderByHashFunc(c), "hash"),
 		"tx":                   rpcserver.NewRPCFunc(makeTxFunc(c), "hash,prove", rpcserver.Cacheable()),
+		"verified_tx":          rpcserver.NewRPCFunc(makeVerifiedTxFunc(c), "hash", rpcserver.Cacheable()),
 		"tx_search":            rpcserver.NewRPCFunc(makeTxSearchFuncMatchEvents(c), "query,prove,page,per_page,order_by,match_events"),
 		"block_search":         rpcserver.NewRPCFunc(makeBlockSearchFuncMatchEvents(c), "query,page,per_page,order_by,match_events"),
 		"validators":           rpcserver.NewRPCFunc(makeValidatorsFunc(c), "height,page,per_page", rpcserver.Cacheable("height")),
@@ -52,6 +53,7 @@ func RPCRoutes(c *lrpc.Client) map[string]*rpcserver.RPCFunc {
 
 		// evidence API
 		"broadcast_evidence": rpcserver.NewRPCFunc(makeBroadcastEvidenceFunc(c), "evidence"),
+		"evidence":           rpcserver.NewRPCFunc(makeEvidenceFunc(c), "hash"),
 	}
 }
 
@@ -168,6 +170,14 @@ func makeTxFunc(c *lrpc.Client) rpcTxFunc {
 	}
 }
 
+type rpcVerifiedTxFunc func(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultVerifiedTx, error)
+
+func makeVerifiedTxFunc(c *lrpc.Client) rpcVerifiedTxFunc {
+	return func(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultVerifiedTx, error) {
+		return c.VerifiedTx(ctx.Context(), hash)
+	}
+}
+
 type rpcTxSearchFuncMatchEvents func(
 	ctx *rpctypes.Context,
 	query string,
@@ -198,7 +208,6 @@ func makeTxSearchFuncMatchEvents(c *lrpc.Client) rpcTxSearchFuncMatchEvents {
 type rpcBlockSearchFuncMatchEvents func(
 	ctx *rpctypes.Context,
 	query string,
-	prove bool,
 	page, perPage *int,
 	orderBy string,
 	matchEvents bool,
@@ -208,7 +217,6 @@ func makeBlockSearchFuncMatchEvents(c *lrpc.Client) rpcBlockSearchFuncMatchEvent
 	return func(
 		ctx *rpctypes.Context,
 		query string,
-		prove bool,
 		page, perPage *int,
 		orderBy string,
 		matchEvents bool,
@@ -325,3 +333,11 @@ func makeBroadcastEvidenceFunc(c *lrpc.Client) rpcBroadcastEvidenceFunc {
 		return c.BroadcastEvidence(ctx.Context(), ev)
 	}
 }
+
+type rpcEvidenceFunc func(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultEvidence, error)
+
+func makeEvidenceFunc(c *lrpc.Client) rpcEvidenceFunc {
+	return func(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultEvidence, error) {
+		return c.Evidence(ctx.Context(), hash)
+	}
+}
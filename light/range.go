@@ -0,0 +1,164 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// defaultHeaderRangeBatchSize is the number of light blocks fetched, verified
+// and saved at once by VerifyHeaderRange.
+const defaultHeaderRangeBatchSize = 100
+
+// HeaderRangeBatchSize option sets the number of light blocks VerifyHeaderRange
+// fetches concurrently from the primary, and saves at once to the trusted
+// store. Default: 100.
+func HeaderRangeBatchSize(size int64) Option {
+	return func(c *Client) {
+		c.headerRangeBatchSize = size
+	}
+}
+
+// lightBlockBatch is a batch of consecutive light blocks fetched from the
+// primary, or the error which prevented fetching it.
+type lightBlockBatch struct {
+	blocks []*types.LightBlock
+	err    error
+}
+
+// VerifyHeaderRange verifies every header from height from to height to,
+// inclusive, sequentially. The light block at height from-1 must be trusted.
+//
+// The light blocks are fetched from the primary in batches (see
+// HeaderRangeBatchSize), the next batch being fetched while the current one is
+// verified. Once verified, the last light block of a batch is compared with
+// the witnesses, and the batch is saved to the trusted store atomically. If
+// verification fails, the batches before the failing one stay trusted.
+//
+// It returns ErrVerificationFailed with the exact height and reason of a
+// failure to fetch or verify a light block.
+func (c *Client) VerifyHeaderRange(ctx context.Context, from, to int64, now time.Time) error {
+	if from <= 1 {
+		return errors.New("from must be greater than 1")
+	}
+	if to < from {
+		return fmt.Errorf("to (%d) is less than from (%d)", to, from)
+	}
+
+	trustedBlock, err := c.TrustedLightBlock(from - 1)
+	if err != nil {
+		return fmt.Errorf("can't get trusted light block at height %d: %w", from-1, err)
+	}
+
+	c.logger.Info("VerifyHeaderRange", "from", from, "to", to)
+
+	start := time.Now()
+	defer func() {
+		c.metrics.VerificationDuration.With("type", sequential.String()).Observe(time.Since(start).Seconds())
+	}()
+
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// fetch at most one batch ahead of the one being verified
+	batches := make(chan lightBlockBatch, 1)
+	go c.fetchHeaderRange(fetchCtx, from, to, batches)
+
+	for batch := range batches {
+		if batch.err != nil {
+			return batch.err
+		}
+
+		trace := append([]*types.LightBlock{trustedBlock}, batch.blocks...)
+		for i, l := range batch.blocks {
+			err := VerifyAdjacent(trace[i].SignedHeader, l.SignedHeader, l.ValidatorSet,
+				c.trustingPeriod, now, c.maxClockDrift)
+			if err != nil {
+				c.logger.Error("Can't verify", "height", l.Height, "err", err)
+				return ErrVerificationFailed{From: trace[i].Height, To: l.Height, Reason: err}
+			}
+		}
+		c.metrics.Verifications.With("type", sequential.String()).Add(float64(len(batch.blocks)))
+
+		if err := c.detectDivergence(ctx, trace, now); err != nil {
+			return err
+		}
+
+		if err := c.updateTrustedLightBlocks(batch.blocks); err != nil {
+			return err
+		}
+		trustedBlock = batch.blocks[len(batch.blocks)-1]
+	}
+
+	return ctx.Err()
+}
+
+// fetchHeaderRange sends the light blocks from height from to height to to
+// batches, and closes it. It stops at the first error.
+func (c *Client) fetchHeaderRange(ctx context.Context, from, to int64, batches chan<- lightBlockBatch) {
+	defer close(batches)
+
+	for start := from; start <= to; start += c.headerRangeBatchSize {
+		end := start + c.headerRangeBatchSize - 1
+		if end > to {
+			end = to
+		}
+
+		blocks, err := c.lightBlocksFromPrimary(ctx, start, end)
+		select {
+		case batches <- lightBlockBatch{blocks, err}:
+		case <-ctx.Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// lightBlocksFromPrimary requests the light blocks from height from to height
+// to from the primary concurrently. The light blocks the primary fails to
+// return are requested again through lightBlockFromPrimary, which replaces the
+// primary if needed.
+func (c *Client) lightBlocksFromPrimary(ctx context.Context, from, to int64) ([]*types.LightBlock, error) {
+	c.providerMutex.Lock()
+	primary := c.primary
+	c.providerMutex.Unlock()
+
+	var (
+		blocks = make([]*types.LightBlock, to-from+1)
+		errs   = make([]error, len(blocks))
+		wg     sync.WaitGroup
+	)
+	for i := range blocks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			blocks[i], errs[i] = primary.LightBlock(ctx, from+int64(i))
+			c.recordRequest("primary", errs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		height := from + int64(i)
+		if err == nil && blocks[i].Height != height {
+			err = fmt.Errorf("primary returned light block at height %d", blocks[i].Height)
+		}
+		if err == nil {
+			continue
+		}
+
+		c.logger.Debug("Retrying light block request", "height", height, "err", err)
+		blocks[i], err = c.lightBlockFromPrimary(ctx, height)
+		if err != nil {
+			return nil, ErrVerificationFailed{From: height - 1, To: height, Reason: err}
+		}
+	}
+
+	return blocks, nil
+}
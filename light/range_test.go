@@ -0,0 +1,107 @@
+package light_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/light"
+	"github.com/tendermint/tendermint/light/provider"
+	mockp "github.com/tendermint/tendermint/light/provider/mock"
+	dbs "github.com/tendermint/tendermint/light/store/db"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestClientVerifyHeaderRange(t *testing.T) {
+	// two validators change at every other height
+	headers, vals, _ := genMockNodeWithKeys(chainID, 25, 5, 2, bTime)
+	require.NotEqual(t, vals[13].Hash(), vals[14].Hash())
+
+	verifications := newLabeledCounter()
+	m := light.NopMetrics()
+	m.Verifications = verifications
+
+	c, err := light.NewClient(
+		ctx,
+		chainID,
+		light.TrustOptions{
+			Period: 4 * time.Hour,
+			Height: 1,
+			Hash:   headers[1].Hash(),
+		},
+		mockp.New(chainID, headers, vals),
+		[]provider.Provider{mockp.New(chainID, headers, vals)},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.HeaderRangeBatchSize(10),
+		light.WithMetrics(m),
+	)
+	require.NoError(t, err)
+
+	err = c.VerifyHeaderRange(ctx, 2, 25, bTime.Add(1*time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 24, verifications.Value("type", "sequential"))
+
+	for height := int64(2); height <= 25; height++ {
+		l, err := c.TrustedLightBlock(height)
+		require.NoError(t, err)
+		assert.Equal(t, headers[height].Hash(), l.Hash())
+	}
+	lastHeight, err := c.LastTrustedHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 25, lastHeight)
+}
+
+func TestClientVerifyHeaderRangeFailure(t *testing.T) {
+	headers, vals, _ := genMockNodeWithKeys(chainID, 25, 5, 2, bTime)
+
+	// the primary serves a header at height 17 signed by other validators
+	var (
+		primaryHeaders = make(map[int64]*types.SignedHeader, len(headers))
+		primaryVals    = make(map[int64]*types.ValidatorSet, len(vals))
+		forgedKeys     = genPrivKeys(5)
+		forgedVals     = forgedKeys.ToValidators(2, 0)
+	)
+	for height := range headers {
+		primaryHeaders[height], primaryVals[height] = headers[height], vals[height]
+	}
+	primaryHeaders[17] = forgedKeys.GenSignedHeaderLastBlockID(chainID, 17, bTime.Add(17*time.Minute), nil,
+		forgedVals, forgedVals, hash("app_hash"), hash("cons_hash"), hash("results_hash"), 0, len(forgedKeys),
+		types.BlockID{Hash: headers[16].Hash()})
+	primaryVals[17] = forgedVals
+
+	c, err := light.NewClient(
+		ctx,
+		chainID,
+		light.TrustOptions{
+			Period: 4 * time.Hour,
+			Height: 1,
+			Hash:   headers[1].Hash(),
+		},
+		mockp.New(chainID, primaryHeaders, primaryVals),
+		[]provider.Provider{mockp.New(chainID, headers, vals)},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.HeaderRangeBatchSize(10),
+	)
+	require.NoError(t, err)
+
+	err = c.VerifyHeaderRange(ctx, 2, 25, bTime.Add(1*time.Hour))
+	var verificationErr light.ErrVerificationFailed
+	require.ErrorAs(t, err, &verificationErr)
+	assert.EqualValues(t, 16, verificationErr.From)
+	assert.EqualValues(t, 17, verificationErr.To)
+	assert.ErrorContains(t, verificationErr.Reason, "next validators")
+
+	// the first batch is trusted, none of the failing one is
+	lastHeight, err := c.LastTrustedHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 11, lastHeight)
+	_, err = c.TrustedLightBlock(16)
+	assert.Error(t, err)
+}
@@ -8,9 +8,6 @@ import (
 	"regexp"
 	"time"
 
-	"github.com/gogo/protobuf/proto"
-
-	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto/merkle"
 	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
 	cmtmath "github.com/tendermint/tendermint/libs/math"
@@ -316,31 +313,41 @@ func (c *Client) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock,
 		return nil, err
 	}
 
+	if err := c.verifyBlock(ctx, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// verifyBlock verifies that the block matches the trusted header at its
+// height.
+func (c *Client) verifyBlock(ctx context.Context, res *ctypes.ResultBlock) error {
 	// Validate res.
 	if err := res.BlockID.ValidateBasic(); err != nil {
-		return nil, err
+		return err
 	}
 	if err := res.Block.ValidateBasic(); err != nil {
-		return nil, err
+		return err
 	}
 	if bmH, bH := res.BlockID.Hash, res.Block.Hash(); !bytes.Equal(bmH, bH) {
-		return nil, fmt.Errorf("blockID %X does not match with block %X",
+		return fmt.Errorf("blockID %X does not match with block %X",
 			bmH, bH)
 	}
 
 	// Update the light client if we're behind.
 	l, err := c.updateLightClientIfNeededTo(ctx, &res.Block.Height)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Verify block.
 	if bH, tH := res.Block.Hash(), l.Hash(); !bytes.Equal(bH, tH) {
-		return nil, fmt.Errorf("block header %X does not match with trusted header %X",
+		return fmt.Errorf("block header %X does not match with trusted header %X",
 			bH, tH)
 	}
 
-	return res, nil
+	return nil
 }
 
 // SignedBlock calls rpcclient#SignedBlock and then verifies the result.
@@ -425,6 +432,12 @@ func (c *Client) BlockByHash(ctx context.Context, hash []byte) (*ctypes.ResultBl
 
 // BlockResults returns the block results for the given height. If no height is
 // provided, the results of the block preceding the latest are returned.
+//
+// The code, data and gas of the transaction results are verified against the
+// trusted LastResultsHash of the next header. The other fields of the
+// transaction results (log, info, events and codespace), the BeginBlock and
+// EndBlock events, the validator updates and the consensus param updates
+// aren't committed to by the header and pass through unverified.
 func (c *Client) BlockResults(ctx context.Context, height *int64) (*ctypes.ResultBlockResults, error) {
 	var h int64
 	if height == nil {
@@ -456,27 +469,8 @@ func (c *Client) BlockResults(ctx context.Context, height *int64) (*ctypes.Resul
 		return nil, err
 	}
 
-	// proto-encode BeginBlock events
-	bbeBytes, err := proto.Marshal(&abci.ResponseBeginBlock{
-		Events: res.BeginBlockEvents,
-	})
-	if err != nil {
-		return nil, err
-	}
-
 	// Build a Merkle tree of proto-encoded DeliverTx results and get a hash.
-	results := types.NewResults(res.TxsResults)
-
-	// proto-encode EndBlock events.
-	ebeBytes, err := proto.Marshal(&abci.ResponseEndBlock{
-		Events: res.EndBlockEvents,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	// Build a Merkle tree out of the above 3 binary slices.
-	rH := merkle.HashFromByteSlices([][]byte{bbeBytes, results.Hash(), ebeBytes})
+	rH := types.NewResults(res.TxsResults).Hash()
 
 	// Verify block results.
 	if !bytes.Equal(rH, trustedBlock.LastResultsHash) {
@@ -561,13 +555,54 @@ func (c *Client) DataRootInclusionProof(
 }
 
 // Tx calls rpcclient#Tx method and then verifies the proof if such was
-// requested.
+// requested. The result of the transaction passes through unverified.
 func (c *Client) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
 	res, err := c.next.Tx(ctx, hash, prove)
 	if err != nil || !prove {
 		return res, err
 	}
 
+	_, err = c.verifyTxProof(ctx, res)
+	return res, err
+}
+
+// VerifiedTx calls rpcclient#Tx method with a proof, and verifies that the
+// transaction is included in its block: the header of the block is verified
+// by the light client, the share proof is verified against its data root, and
+// the proven shares must contain the transaction. Unlike Tx, the result of the
+// transaction isn't returned, as it isn't proven.
+func (c *Client) VerifiedTx(ctx context.Context, hash []byte) (*ctypes.ResultVerifiedTx, error) {
+	res, err := c.next.Tx(ctx, hash, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(res.Tx.Hash(), hash) {
+		return nil, fmt.Errorf("primary returned tx %X, expected %X", res.Tx.Hash(), hash)
+	}
+	if err := verifyTxShares(res.Tx, res.Proof); err != nil {
+		return nil, fmt.Errorf("tx %X: %w", hash, err)
+	}
+
+	l, err := c.verifyTxProof(ctx, res)
+	if err != nil {
+		return nil, fmt.Errorf("tx %X: %w", hash, err)
+	}
+
+	return &ctypes.ResultVerifiedTx{
+		Hash:   hash,
+		Height: res.Height,
+		Index:  res.Index,
+		Tx:     res.Tx,
+		Proof:  res.Proof,
+		Header: *l.Header,
+	}, nil
+}
+
+// verifyTxProof verifies the inclusion proof of the transaction against the
+// trusted data root of the block at its height, and returns the trusted light
+// block.
+func (c *Client) verifyTxProof(ctx context.Context, res *ctypes.ResultTx) (*types.LightBlock, error) {
 	// Validate res.
 	if res.Height <= 0 {
 		return nil, errNegOrZeroHeight
@@ -580,7 +615,10 @@ func (c *Client) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.Resul
 	}
 
 	// Validate the proof.
-	return res, res.Proof.Validate(l.DataHash)
+	if err := res.Proof.Validate(l.DataHash); err != nil {
+		return nil, err
+	}
+	return l, nil
 }
 
 // ProveShares calls rpcclient#ProveShares method and returns an NMT proof for a set
@@ -611,6 +649,10 @@ func (c *Client) ProveSharesV2(
 	return res, err
 }
 
+// TxSearch calls rpcclient#TxSearch method and then verifies the proof of
+// each transaction if such was requested. The results of the transactions
+// pass through unverified, and so does the completeness of the search: the
+// primary may omit matching transactions.
 func (c *Client) TxSearch(
 	ctx context.Context,
 	query string,
@@ -618,16 +660,41 @@ func (c *Client) TxSearch(
 	page, perPage *int,
 	orderBy string,
 ) (*ctypes.ResultTxSearch, error) {
-	return c.next.TxSearch(ctx, query, prove, page, perPage, orderBy)
+	res, err := c.next.TxSearch(ctx, query, prove, page, perPage, orderBy)
+	if err != nil || !prove {
+		return res, err
+	}
+
+	for _, tx := range res.Txs {
+		if _, err := c.verifyTxProof(ctx, tx); err != nil {
+			return nil, fmt.Errorf("tx %X: %w", tx.Hash, err)
+		}
+	}
+
+	return res, nil
 }
 
+// BlockSearch calls rpcclient#BlockSearch method and then verifies each
+// block against the trusted header at its height. The completeness of the
+// search passes through unverified: the primary may omit matching blocks.
 func (c *Client) BlockSearch(
 	ctx context.Context,
 	query string,
 	page, perPage *int,
 	orderBy string,
 ) (*ctypes.ResultBlockSearch, error) {
-	return c.next.BlockSearch(ctx, query, page, perPage, orderBy)
+	res, err := c.next.BlockSearch(ctx, query, page, perPage, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range res.Blocks {
+		if err := c.verifyBlock(ctx, block); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
 }
 
 // Validators fetches and verifies validators.
@@ -665,6 +732,12 @@ func (c *Client) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*cty
 	return c.next.BroadcastEvidence(ctx, ev)
 }
 
+// Evidence calls rpcclient#Evidence. The status of the evidence isn't
+// verified.
+func (c *Client) Evidence(ctx context.Context, hash []byte) (*ctypes.ResultEvidence, error) {
+	return c.next.Evidence(ctx, hash)
+}
+
 func (c *Client) Subscribe(ctx context.Context, subscriber, query string,
 	outCapacity ...int) (out <-chan ctypes.ResultEvent, err error) {
 	return c.next.Subscribe(ctx, subscriber, query, outCapacity...)
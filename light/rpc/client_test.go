@@ -0,0 +1,156 @@
+package rpc_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/nmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/light"
+	lrpc "github.com/tendermint/tendermint/light/rpc"
+	lcmock "github.com/tendermint/tendermint/light/rpc/mocks"
+	"github.com/tendermint/tendermint/pkg/consts"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+const shareSize = 512
+
+// txClient returns a transaction with its proof. The other methods aren't
+// implemented.
+type txClient struct {
+	rpcclient.Client
+
+	res *ctypes.ResultTx
+}
+
+func (c txClient) Tx(ctx context.Context, hash []byte, prove bool) (*ctypes.ResultTx, error) {
+	return c.res, nil
+}
+
+var txNamespaceID = append(make([]byte, consts.NamespaceIDSize-1), 1)
+
+// txShareProof returns a proof of a compact share holding txs to a data root
+// of two rows, and the data root.
+func txShareProof(t *testing.T, txs ...types.Tx) (types.ShareProof, []byte) {
+	var units []byte
+	for _, tx := range txs {
+		units = binary.AppendUvarint(units, uint64(len(tx)))
+		units = append(units, tx...)
+	}
+	namespace := append([]byte{0}, txNamespaceID...)
+	share := append([]byte{}, namespace...)
+	share = append(share, 1)                                         // info byte, starting a sequence
+	share = binary.BigEndian.AppendUint32(share, uint32(len(units))) // sequence length
+	share = binary.BigEndian.AppendUint32(share, uint32(len(share)+4))
+	share = append(share, units...)
+	share = append(share, make([]byte, shareSize-len(share))...)
+
+	tree := nmt.New(sha256.New(), nmt.NamespaceIDSize(consts.NamespaceSize), nmt.IgnoreMaxNamespace(true))
+	require.NoError(t, tree.Push(append(append([]byte{}, namespace...), share...)))
+	rowRoot, err := tree.Root()
+	require.NoError(t, err)
+	nmtProof, err := tree.ProveRange(0, 1)
+	require.NoError(t, err)
+
+	dataRoot, rowProofs := merkle.ProofsFromByteSlices([][]byte{rowRoot, []byte("other row")})
+	return types.ShareProof{
+		Data:        [][]byte{share},
+		ShareProofs: []*cmtproto.NMTProof{{Start: 0, End: 1, Nodes: nmtProof.Nodes()}},
+		NamespaceID: txNamespaceID,
+		RowProof: types.RowProof{
+			RowRoots: []cmtbytes.HexBytes{rowRoot},
+			Proofs:   []*merkle.Proof{rowProofs[0]},
+		},
+	}, dataRoot
+}
+
+func TestClientVerifiedTx(t *testing.T) {
+	const height = 5
+	var (
+		ctx             = context.Background()
+		tx              = types.Tx("verified=tx")
+		proof, dataRoot = txShareProof(t, tx)
+	)
+
+	tamperedProof, _ := txShareProof(t, tx)
+	tamperedProof.Data[0] = append([]byte{}, tamperedProof.Data[0]...)
+	tamperedProof.Data[0][shareSize-1] = 1
+
+	otherProof, _ := txShareProof(t, types.Tx("other=tx"))
+	severalProof, severalDataRoot := txShareProof(t, types.Tx("other=tx"), tx)
+
+	// the length-prefixed tx is part of the bytes of another tx
+	wrapper := append([]byte("wrapper="), byte(len(tx)))
+	wrapper = append(wrapper, tx...)
+	wrapperProof, wrapperDataRoot := txShareProof(t, wrapper)
+
+	testCases := []struct {
+		name      string
+		tx        types.Tx
+		proof     types.ShareProof
+		dataRoot  []byte
+		verifyErr error
+		expErr    string
+	}{
+		{"valid proof", tx, proof, dataRoot, nil, ""},
+		{"proof of several txs", tx, severalProof, severalDataRoot, nil, ""},
+		{"tampered proof", tx, tamperedProof, dataRoot, nil, "share proof failed to verify"},
+		{"proof of another tx", tx, otherProof, dataRoot, nil, "proven shares don't contain the transaction"},
+		{
+			"tx embedded in another tx", tx, wrapperProof, wrapperDataRoot, nil,
+			"proven shares don't contain the transaction",
+		},
+		{"different tx", types.Tx("other=tx"), proof, dataRoot, nil, "primary returned tx"},
+		{
+			"header outside the trusting period", tx, proof, dataRoot,
+			light.ErrOldHeaderExpired{At: time.Now().Add(-time.Hour), Now: time.Now()},
+			"old header has expired",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			next := txClient{res: &ctypes.ResultTx{
+				Hash:   tx.Hash(),
+				Height: height,
+				Tx:     tc.tx,
+				Proof:  tc.proof,
+			}}
+			header := &types.Header{Height: height, DataHash: tc.dataRoot}
+			lightBlock := &types.LightBlock{SignedHeader: &types.SignedHeader{Header: header}}
+			lc := &lcmock.LightClient{}
+			if tc.verifyErr != nil {
+				lc.On("VerifyLightBlockAtHeight", mock.Anything, int64(height), mock.Anything).
+					Return(nil, tc.verifyErr)
+			} else {
+				lc.On("VerifyLightBlockAtHeight", mock.Anything, int64(height), mock.Anything).
+					Return(lightBlock, nil)
+			}
+
+			res, err := lrpc.NewClient(next, lc).VerifiedTx(ctx, tx.Hash())
+			if tc.expErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expErr)
+				assert.Nil(t, res)
+				return
+			}
+			require.NoError(t, err)
+			assert.EqualValues(t, tx.Hash(), res.Hash)
+			assert.EqualValues(t, height, res.Height)
+			assert.Equal(t, tx, res.Tx)
+			assert.Equal(t, *header, res.Header)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/tendermint/pkg/consts"
+	"github.com/tendermint/tendermint/types"
+)
+
+// The layout of compact shares, which hold the transactions of a block,
+// mirrors the share format version 0 of celestia-app: a namespace, an info
+// byte, a sequence length if the share starts a sequence, reserved bytes and
+// the length-delimited transactions.
+const (
+	shareInfoBytes            = 1
+	sequenceLenBytes          = 4
+	compactShareReservedBytes = 4
+
+	// sequenceStartIndicator is the bit of the info byte set in the first
+	// share of a sequence.
+	sequenceStartIndicator = 1
+)
+
+// txNamespaceIDs are the IDs of the reserved namespaces, of version 0, of
+// compact shares holding transactions: the one of ordinary transactions, and
+// the one of the transactions paying for blobs.
+var txNamespaceIDs = [][]byte{
+	append(make([]byte, consts.NamespaceIDSize-1), 1),
+	append(make([]byte, consts.NamespaceIDSize-1), 4),
+}
+
+// verifyTxShares verifies that the shares of the proof are compact shares of
+// a transaction namespace, and that one of the transactions they contain is
+// tx. It doesn't verify the proof itself.
+func verifyTxShares(tx types.Tx, proof types.ShareProof) error {
+	if !isTxNamespace(proof.NamespaceVersion, proof.NamespaceID) {
+		return fmt.Errorf("proof of namespace %X isn't a proof of transactions", proof.NamespaceID)
+	}
+
+	// start is the position in data of the first transaction starting in the
+	// proven shares, as given by the reserved bytes of its share. The shares
+	// before it only hold the end of a transaction starting before them.
+	var (
+		data  []byte
+		start = -1
+	)
+	for i, share := range proof.Data {
+		offset := consts.NamespaceSize + shareInfoBytes
+		if len(share) < offset {
+			return fmt.Errorf("share %d is too short: %d bytes", i, len(share))
+		}
+		if share[consts.NamespaceSize]&sequenceStartIndicator != 0 {
+			offset += sequenceLenBytes
+		}
+		offset += compactShareReservedBytes
+		if len(share) < offset {
+			return fmt.Errorf("share %d is too short: %d bytes", i, len(share))
+		}
+		if start < 0 {
+			first := int(binary.BigEndian.Uint32(share[offset-compactShareReservedBytes : offset]))
+			switch {
+			case first == 0:
+				// no transaction starts in this share
+			case first < offset || first >= len(share):
+				return fmt.Errorf("share %d has an invalid first transaction index %d", i, first)
+			default:
+				start = len(data) + first - offset
+			}
+		}
+		data = append(data, share[offset:]...)
+	}
+	if start < 0 {
+		return errors.New("no transaction starts in the proven shares")
+	}
+
+	// transactions are prefixed by their length, and followed by zero padding
+	for pos := start; pos < len(data); {
+		length, n := binary.Uvarint(data[pos:])
+		if n <= 0 || length == 0 || length > uint64(len(data)-pos-n) {
+			break
+		}
+		unit := data[pos+n : pos+n+int(length)]
+		if bytes.Equal(unit, tx) {
+			return nil
+		}
+		pos += n + int(length)
+	}
+	return errors.New("proven shares don't contain the transaction")
+}
+
+func isTxNamespace(version uint32, id []byte) bool {
+	if version != 0 {
+		return false
+	}
+	for _, txID := range txNamespaceIDs {
+		if bytes.Equal(id, txID) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/tendermint/tendermint/libs/protoio"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// maxBundledLightBlockSize is the maximum size of a light block in a bundle.
+const maxBundledLightBlockSize = 16 << 20 // 16MB
+
+// bundleMagic starts every bundle, and carries its format version.
+var bundleMagic = []byte("CMTLB\x01")
+
+// Export writes all the light blocks of the store to w, in ascending height
+// order, as a bundle which Import reads back into any store. The bundle is:
+//
+//	magic | len-delimited LightBlock protobuf... | SHA256 of what precedes
+func Export(s Store, w io.Writer) error {
+	lbs, err := lightBlocks(s)
+	if err != nil {
+		return err
+	}
+
+	checksum := sha256.New()
+	bw := bufio.NewWriter(w)
+	mw := io.MultiWriter(bw, checksum)
+	if _, err := mw.Write(bundleMagic); err != nil {
+		return err
+	}
+	pw := protoio.NewDelimitedWriter(mw)
+	for _, lb := range lbs {
+		lbpb, err := lb.ToProto()
+		if err != nil {
+			return fmt.Errorf("unable to convert light block %d to protobuf: %w", lb.Height, err)
+		}
+		if _, err := pw.WriteMsg(lbpb); err != nil {
+			return fmt.Errorf("writing light block %d: %w", lb.Height, err)
+		}
+	}
+	if _, err := bw.Write(checksum.Sum(nil)); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Import reads a bundle written by Export, and saves its light blocks to the
+// store, overwriting the light blocks at the same heights.
+//
+// Before saving anything, it checks the checksum of the bundle and the
+// internal consistency of the light blocks: each one must be valid and signed
+// by its validator set, they must all be on the same chain, and consecutive
+// light blocks must be linked by their hashes and validator sets.
+func Import(s Store, r io.Reader) error {
+	lbs, err := readBundle(r)
+	if err != nil {
+		return err
+	}
+	if err := validateBundle(lbs); err != nil {
+		return err
+	}
+	return s.SaveLightBlocks(lbs)
+}
+
+// lightBlocks returns all the light blocks of the store in ascending height
+// order.
+func lightBlocks(s Store) ([]*types.LightBlock, error) {
+	first, err := s.FirstLightBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+	last, err := s.LastLightBlockHeight()
+	if err != nil {
+		return nil, err
+	}
+	if last == -1 {
+		return nil, nil
+	}
+
+	lb, err := s.LightBlock(last)
+	if err != nil {
+		return nil, fmt.Errorf("can't get light block %d: %w", last, err)
+	}
+	lbs := []*types.LightBlock{lb}
+	for lb.Height > first {
+		lb, err = s.LightBlockBefore(lb.Height)
+		if err != nil {
+			return nil, fmt.Errorf("can't get light block before %d: %w", lbs[len(lbs)-1].Height, err)
+		}
+		lbs = append(lbs, lb)
+	}
+
+	// reverse to ascending height order
+	for i, j := 0, len(lbs)-1; i < j; i, j = i+1, j-1 {
+		lbs[i], lbs[j] = lbs[j], lbs[i]
+	}
+	return lbs, nil
+}
+
+// readBundle checks the magic and checksum of the bundle, and decodes its
+// light blocks.
+func readBundle(r io.Reader) ([]*types.LightBlock, error) {
+	bz, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) < len(bundleMagic)+sha256.Size || !bytes.HasPrefix(bz, bundleMagic) {
+		return nil, errors.New("not a light block bundle")
+	}
+
+	body, checksum := bz[:len(bz)-sha256.Size], bz[len(bz)-sha256.Size:]
+	if sum := sha256.Sum256(body); !bytes.Equal(sum[:], checksum) {
+		return nil, fmt.Errorf("bundle checksum mismatch: expected %X, got %X", checksum, sum)
+	}
+
+	var (
+		lbs []*types.LightBlock
+		pr  = protoio.NewDelimitedReader(bytes.NewReader(body[len(bundleMagic):]), maxBundledLightBlockSize)
+	)
+	for {
+		var lbpb cmtproto.LightBlock
+		if _, err := pr.ReadMsg(&lbpb); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading light block #%d: %w", len(lbs), err)
+		}
+		lb, err := types.LightBlockFromProto(&lbpb)
+		if err != nil {
+			return nil, fmt.Errorf("decoding light block #%d: %w", len(lbs), err)
+		}
+		lbs = append(lbs, lb)
+	}
+	return lbs, nil
+}
+
+// validateBundle checks the internal consistency of the light blocks of a
+// bundle.
+func validateBundle(lbs []*types.LightBlock) error {
+	if len(lbs) == 0 {
+		return errors.New("empty bundle")
+	}
+
+	chainID := lbs[0].ChainID
+	for i, lb := range lbs {
+		if err := lb.ValidateBasic(chainID); err != nil {
+			return fmt.Errorf("invalid light block #%d: %w", i, err)
+		}
+		err := lb.ValidatorSet.VerifyCommitLight(chainID, lb.Commit.BlockID, lb.Height, lb.Commit)
+		if err != nil {
+			return fmt.Errorf("invalid commit of light block %d: %w", lb.Height, err)
+		}
+		if i == 0 {
+			continue
+		}
+
+		prev := lbs[i-1]
+		switch {
+		case lb.Height <= prev.Height:
+			return fmt.Errorf("light block %d follows light block %d", lb.Height, prev.Height)
+		case lb.Height == prev.Height+1 && !bytes.Equal(lb.LastBlockID.Hash, prev.Hash()):
+			return fmt.Errorf("light block %d doesn't link to light block %d: last block hash %X, expected %X",
+				lb.Height, prev.Height, lb.LastBlockID.Hash, prev.Hash())
+		case lb.Height == prev.Height+1 && !bytes.Equal(lb.ValidatorsHash, prev.NextValidatorsHash):
+			return fmt.Errorf("light block %d doesn't link to light block %d: validators hash %X, expected %X",
+				lb.Height, prev.Height, lb.ValidatorsHash, prev.NextValidatorsHash)
+		}
+	}
+	return nil
+}
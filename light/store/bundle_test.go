@@ -0,0 +1,154 @@
+package store_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/light/store"
+	dbs "github.com/tendermint/tendermint/light/store/db"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	cmtversion "github.com/tendermint/tendermint/proto/tendermint/version"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tendermint/version"
+)
+
+const chainID = "bundle-test"
+
+func TestExportImport(t *testing.T) {
+	backends := map[string]func(t *testing.T) dbm.DB{
+		"memdb": func(t *testing.T) dbm.DB { return dbm.NewMemDB() },
+		"goleveldb": func(t *testing.T) dbm.DB {
+			db, err := dbm.NewGoLevelDB("light-client-db", t.TempDir())
+			require.NoError(t, err)
+			t.Cleanup(func() { db.Close() })
+			return db
+		},
+	}
+	lbs := genLightBlocks(t, 5)
+
+	for from, newFromDB := range backends {
+		for to, newToDB := range backends {
+			from, newFromDB, to, newToDB := from, newFromDB, to, newToDB
+			t.Run(from+" to "+to, func(t *testing.T) {
+				fromStore := dbs.New(newFromDB(t), chainID)
+				// leave a gap, as pruning does
+				for _, lb := range append(lbs[:2:2], lbs[3:]...) {
+					require.NoError(t, fromStore.SaveLightBlock(lb))
+				}
+
+				var bundle bytes.Buffer
+				require.NoError(t, store.Export(fromStore, &bundle))
+
+				toStore := dbs.New(newToDB(t), chainID)
+				require.NoError(t, store.Import(toStore, &bundle))
+
+				assert.EqualValues(t, 4, toStore.Size())
+				_, err := toStore.LightBlock(3)
+				assert.Equal(t, store.ErrLightBlockNotFound, err)
+				for _, height := range []int64{1, 2, 4, 5} {
+					lb, err := toStore.LightBlock(height)
+					require.NoError(t, err)
+					assert.Equal(t, lbs[height-1].Hash(), lb.Hash())
+					assert.Equal(t, lbs[height-1].ValidatorSet.Hash(), lb.ValidatorSet.Hash())
+				}
+			})
+		}
+	}
+}
+
+func TestImportRejectsInvalidBundles(t *testing.T) {
+	lbs := genLightBlocks(t, 3)
+	otherLbs := genLightBlocks(t, 3)
+
+	export := func(lbs ...*types.LightBlock) []byte {
+		s := dbs.New(dbm.NewMemDB(), chainID)
+		require.NoError(t, s.SaveLightBlocks(lbs))
+		var bundle bytes.Buffer
+		require.NoError(t, store.Export(s, &bundle))
+		return bundle.Bytes()
+	}
+
+	corrupted := export(lbs...)
+	corrupted[len(corrupted)/2] ^= 0xff
+
+	testCases := map[string]struct {
+		bundle []byte
+		errMsg string
+	}{
+		"not a bundle":       {[]byte("not a bundle at all, not a bundle at all"), "not a light block bundle"},
+		"corrupted":          {corrupted, "checksum mismatch"},
+		"empty":              {export(), "empty bundle"},
+		"broken hash link":   {export(lbs[0], otherLbs[1], lbs[2]), "doesn't link"},
+		"from another chain": {export(lbs[0], lbs[1], withChainID(t, lbs[2])), "invalid light block #2"},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			s := dbs.New(dbm.NewMemDB(), chainID)
+			require.NoError(t, s.SaveLightBlock(otherLbs[0]))
+
+			err := store.Import(s, bytes.NewReader(tc.bundle))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+
+			// nothing was overwritten
+			assert.EqualValues(t, 1, s.Size())
+			lb, err := s.LightBlock(1)
+			require.NoError(t, err)
+			assert.Equal(t, otherLbs[0].Hash(), lb.Hash())
+		})
+	}
+}
+
+// genLightBlocks returns a chain of n light blocks signed by the same
+// validators.
+func genLightBlocks(t *testing.T, n int64) []*types.LightBlock {
+	return genLightBlocksOnChain(t, chainID, n)
+}
+
+func genLightBlocksOnChain(t *testing.T, chainID string, n int64) []*types.LightBlock {
+	var (
+		vals, privVals = types.RandValidatorSet(4, 10)
+		bTime          = time.Now()
+		lastBlockID    types.BlockID
+		lbs            []*types.LightBlock
+	)
+	for height := int64(1); height <= n; height++ {
+		header := &types.Header{
+			Version:            cmtversion.Consensus{Block: version.BlockProtocol},
+			ChainID:            chainID,
+			Height:             height,
+			Time:               bTime.Add(time.Duration(height) * time.Minute),
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: vals.Hash(),
+			ProposerAddress:    vals.Proposer.Address,
+		}
+		blockID := types.BlockID{
+			Hash:          header.Hash(),
+			PartSetHeader: types.PartSetHeader{Total: 1, Hash: tmhash.Sum([]byte("parts"))},
+		}
+		voteSet := types.NewVoteSet(chainID, height, 0, cmtproto.PrecommitType, vals)
+		commit, err := types.MakeCommit(blockID, height, 0, voteSet, privVals, header.Time)
+		require.NoError(t, err)
+
+		lbs = append(lbs, &types.LightBlock{
+			SignedHeader: &types.SignedHeader{Header: header, Commit: commit},
+			ValidatorSet: vals,
+		})
+		lastBlockID = blockID
+	}
+	return lbs
+}
+
+// withChainID returns a light block at the height of lb on another chain.
+func withChainID(t *testing.T, lb *types.LightBlock) *types.LightBlock {
+	return genLightBlocksOnChain(t, "other-chain", lb.Height)[lb.Height-1]
+}
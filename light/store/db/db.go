@@ -76,6 +76,57 @@ func (s *dbs) SaveLightBlock(lb *types.LightBlock) error {
 	return nil
 }
 
+// SaveLightBlocks persists the LightBlocks to the db in a single batch.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *dbs) SaveLightBlocks(lbs []*types.LightBlock) error {
+	lbBzs := make([][]byte, len(lbs))
+	for i, lb := range lbs {
+		if lb.Height <= 0 {
+			panic("negative or zero height")
+		}
+
+		lbpb, err := lb.ToProto()
+		if err != nil {
+			return fmt.Errorf("unable to convert light block to protobuf: %w", err)
+		}
+
+		lbBzs[i], err = lbpb.Marshal()
+		if err != nil {
+			return fmt.Errorf("marshaling LightBlock: %w", err)
+		}
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	b := s.db.NewBatch()
+	defer b.Close()
+	size := s.size
+	for i, lb := range lbs {
+		// overwritten light blocks don't change the size
+		exists, err := s.db.Has(s.lbKey(lb.Height))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			size++
+		}
+		if err = b.Set(s.lbKey(lb.Height), lbBzs[i]); err != nil {
+			return err
+		}
+	}
+	if err := b.Set(sizeKey, marshalSize(size)); err != nil {
+		return err
+	}
+	if err := b.WriteSync(); err != nil {
+		return err
+	}
+	s.size = size
+
+	return nil
+}
+
 // DeleteLightBlockAndValidatorSet deletes the LightBlock from
 // the db.
 //
@@ -281,6 +332,76 @@ func (s *dbs) Prune(size uint16) error {
 	return nil
 }
 
+// PruneFunc prunes the header & validator set pairs at the heights returned by
+// prunable.
+//
+// Safe for concurrent use by multiple goroutines.
+func (s *dbs) PruneFunc(prunable func(heights []int64) ([]int64, error)) error {
+	// 1) Collect the heights, closing the iterator before prunable reads from
+	// the db.
+	heights, err := s.heights()
+	if err != nil {
+		return err
+	}
+
+	toPrune, err := prunable(heights)
+	if err != nil {
+		return err
+	}
+	if len(toPrune) == 0 { // nothing to prune
+		return nil
+	}
+
+	// 2) Perform a batch operation.
+	b := s.db.NewBatch()
+	defer b.Close()
+
+	for _, height := range toPrune {
+		if err = b.Delete(s.lbKey(height)); err != nil {
+			return err
+		}
+	}
+
+	err = b.WriteSync()
+	if err != nil {
+		return err
+	}
+
+	// 3) Update size.
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	//nolint:gosec
+	s.size -= uint16(len(toPrune))
+
+	if wErr := s.db.SetSync(sizeKey, marshalSize(s.size)); wErr != nil {
+		return fmt.Errorf("failed to persist size: %w", wErr)
+	}
+
+	return nil
+}
+
+func (s *dbs) heights() ([]int64, error) {
+	itr, err := s.db.Iterator(
+		s.lbKey(1),
+		append(s.lbKey(1<<63-1), byte(0x00)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	var heights []int64
+	for ; itr.Valid(); itr.Next() {
+		_, height, ok := parseLbKey(itr.Key())
+		if ok {
+			heights = append(heights, height)
+		}
+	}
+
+	return heights, itr.Error()
+}
+
 // Size returns the number of header & validator set pairs.
 //
 // Safe for concurrent use by multiple goroutines.
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	"github.com/tendermint/tendermint/light/store"
 	cmtversion "github.com/tendermint/tendermint/proto/tendermint/version"
 	"github.com/tendermint/tendermint/types"
 	"github.com/tendermint/tendermint/version"
@@ -73,6 +75,28 @@ func Test_SaveLightBlock(t *testing.T) {
 
 }
 
+func Test_SaveLightBlocks(t *testing.T) {
+	dbStore := New(dbm.NewMemDB(), "Test_SaveLightBlocks")
+
+	err := dbStore.SaveLightBlock(randLightBlock(1))
+	require.NoError(t, err)
+
+	// overwrites the light block at height 1
+	err = dbStore.SaveLightBlocks([]*types.LightBlock{randLightBlock(1), randLightBlock(2), randLightBlock(3)})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, dbStore.Size())
+
+	for height := int64(1); height <= 3; height++ {
+		h, err := dbStore.LightBlock(height)
+		require.NoError(t, err)
+		assert.EqualValues(t, height, h.Height)
+	}
+
+	height, err := dbStore.LastLightBlockHeight()
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, height)
+}
+
 func Test_LightBlockBefore(t *testing.T) {
 	dbStore := New(dbm.NewMemDB(), "Test_LightBlockBefore")
 
@@ -128,6 +152,40 @@ func Test_Prune(t *testing.T) {
 	assert.EqualValues(t, 7, dbStore.Size())
 }
 
+func Test_PruneFunc(t *testing.T) {
+	dbStore := New(dbm.NewMemDB(), "Test_PruneFunc")
+
+	// Empty store
+	err := dbStore.PruneFunc(func(heights []int64) ([]int64, error) {
+		assert.Empty(t, heights)
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		err = dbStore.SaveLightBlock(randLightBlock(int64(i)))
+		require.NoError(t, err)
+	}
+
+	err = dbStore.PruneFunc(func(heights []int64) ([]int64, error) {
+		assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, heights)
+		return []int64{2, 3, 5, 8}, nil
+	})
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, dbStore.Size())
+
+	for _, height := range []int64{2, 3, 5, 8} {
+		_, err = dbStore.LightBlock(height)
+		assert.Equal(t, store.ErrLightBlockNotFound, err)
+	}
+	err = dbStore.PruneFunc(func(heights []int64) ([]int64, error) {
+		assert.Equal(t, []int64{1, 4, 6, 7, 9, 10}, heights)
+		return nil, errors.New("failed")
+	})
+	assert.Error(t, err)
+	assert.EqualValues(t, 6, dbStore.Size())
+}
+
 func Test_Concurrency(t *testing.T) {
 	dbStore := New(dbm.NewMemDB(), "Test_Prune")
 
@@ -10,6 +10,12 @@ type Store interface {
 	// height must be > 0.
 	SaveLightBlock(lb *types.LightBlock) error
 
+	// SaveLightBlocks saves the light blocks atomically: either all of them
+	// are saved or none is.
+	//
+	// heights must be > 0.
+	SaveLightBlocks(lbs []*types.LightBlock) error
+
 	// DeleteSignedHeaderAndValidatorSet deletes SignedHeader (h: height) and
 	// ValidatorSet (h: height).
 	//
@@ -43,6 +49,11 @@ type Store interface {
 	// defined size (number of header & validator set pairs).
 	Prune(size uint16) error
 
+	// PruneFunc removes the headers & the associated validator sets whose
+	// heights are returned by prunable, which is given the heights of all the
+	// stored pairs in ascending order. prunable may read from the store.
+	PruneFunc(prunable func(heights []int64) ([]int64, error)) error
+
 	// Size returns a number of currently existing header & validator set pairs.
 	Size() uint16
 }
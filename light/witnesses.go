@@ -0,0 +1,325 @@
+package light
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/light/provider"
+)
+
+// defaultWitnessCheckTimeout is the time a witness has to return its latest
+// light block during a health check.
+const defaultWitnessCheckTimeout = 5 * time.Second
+
+// WitnessStatus is the role of a witness in the light client.
+type WitnessStatus uint8
+
+const (
+	// WitnessActive witnesses are cross-checked against the primary.
+	WitnessActive WitnessStatus = iota + 1
+	// WitnessSpare witnesses replace the active witnesses which are unhealthy
+	// or evicted.
+	WitnessSpare
+	// WitnessEvicted witnesses misbehaved and are never used again.
+	WitnessEvicted
+	// WitnessSuspect witnesses are former primaries which failed to respond.
+	// They become spares once healthy.
+	WitnessSuspect
+)
+
+func (s WitnessStatus) String() string {
+	switch s {
+	case WitnessActive:
+		return "active"
+	case WitnessSpare:
+		return "spare"
+	case WitnessEvicted:
+		return "evicted"
+	case WitnessSuspect:
+		return "suspect"
+	default:
+		return "unknown"
+	}
+}
+
+// WitnessHealth is the state of a witness as of its last health check.
+type WitnessHealth struct {
+	Witness provider.Provider
+	Status  WitnessStatus
+
+	// LastChecked is zero if the witness wasn't checked yet.
+	LastChecked  time.Time
+	LatestHeight int64
+	Latency      time.Duration
+	// Err is the error of the last health check, or the reason of the
+	// eviction of an evicted witness.
+	Err error
+}
+
+// healthy returns true if the witness responded to its last health check
+// with a light block at height or above. Witnesses which weren't checked yet
+// are deemed healthy.
+func (h *WitnessHealth) healthy(height int64) bool {
+	return h.LastChecked.IsZero() || (h.Err == nil && h.LatestHeight >= height)
+}
+
+// SpareWitnesses option sets witnesses which replace the active witnesses
+// which are unhealthy or evicted for misbehavior. Unhealthy witnesses become
+// spares in turn.
+func SpareWitnesses(spares ...provider.Provider) Option {
+	return func(c *Client) {
+		c.spareWitnesses = append([]provider.Provider{}, spares...)
+	}
+}
+
+// WitnessHealthCheckInterval option sets the minimum interval between the
+// health checks of the witnesses, run before they are cross-checked against
+// the primary. Witnesses which don't return their latest light block within
+// 5s, or whose latest light block is below the one being cross-checked, are
+// unhealthy and replaced by healthy spares, if any. Default: 0, the witnesses
+// are only checked by CheckWitnesses.
+func WitnessHealthCheckInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.witnessCheckInterval = d
+	}
+}
+
+// WitnessHealth returns the state of the active, spare, evicted and suspect
+// witnesses, in this order.
+func (c *Client) WitnessHealth() []WitnessHealth {
+	c.providerMutex.Lock()
+	defer c.providerMutex.Unlock()
+
+	health := make([]WitnessHealth, 0,
+		len(c.witnesses)+len(c.spareWitnesses)+len(c.evictedWitnesses)+len(c.suspects))
+	for _, w := range c.witnesses {
+		health = append(health, *c.witnessHealthOf(w, WitnessActive))
+	}
+	for _, w := range c.spareWitnesses {
+		health = append(health, *c.witnessHealthOf(w, WitnessSpare))
+	}
+	for _, w := range c.evictedWitnesses {
+		health = append(health, *c.witnessHealthOf(w, WitnessEvicted))
+	}
+	for _, w := range c.suspects {
+		health = append(health, *c.witnessHealthOf(w, WitnessSuspect))
+	}
+	return health
+}
+
+// CheckWitnesses checks the health of the primary and of the active, spare
+// and suspect witnesses. It replaces the active witnesses which are unhealthy
+// by healthy spares, turns the healthy suspects into spares, and replaces the
+// primary if it's unhealthy (see PrimaryFailureThreshold). Providers are
+// unhealthy if they don't return a light block at or above the latest trusted
+// one.
+func (c *Client) CheckWitnesses(ctx context.Context) error {
+	var height int64
+	if l, err := c.TrustedLightBlock(0); err == nil {
+		height = l.Height
+	}
+
+	c.providerMutex.Lock()
+	defer c.providerMutex.Unlock()
+	return c.checkWitnesses(ctx, height)
+}
+
+// maybeCheckWitnesses checks the health of the witnesses if the last check
+// is older than the health check interval.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) maybeCheckWitnesses(ctx context.Context, height int64) error {
+	if c.witnessCheckInterval <= 0 || time.Since(c.lastWitnessCheck) < c.witnessCheckInterval {
+		return nil
+	}
+	return c.checkWitnesses(ctx, height)
+}
+
+// checkWitnesses requests the latest light block of the primary and of the
+// active, spare and suspect witnesses concurrently, and replaces the providers
+// which don't have a light block at height (see CheckWitnesses).
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) checkWitnesses(ctx context.Context, height int64) error {
+	checkCtx, cancel := context.WithTimeout(ctx, defaultWitnessCheckTimeout)
+	defer cancel()
+
+	var (
+		witnesses = append(append(append([]provider.Provider{c.primary}, c.witnesses...), c.spareWitnesses...),
+			c.suspects...)
+		checks = make([]WitnessHealth, len(witnesses))
+		wg     sync.WaitGroup
+	)
+	for i, w := range witnesses {
+		wg.Add(1)
+		go func(i int, w provider.Provider) {
+			defer wg.Done()
+			start := time.Now()
+			l, err := c.requestLightBlock(checkCtx, w, 0)
+			checks[i] = WitnessHealth{LastChecked: time.Now(), Latency: time.Since(start), Err: err}
+			if err == nil {
+				checks[i].LatestHeight = l.Height
+			}
+		}(i, w)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for i, w := range witnesses {
+		health := c.witnessHealthOf(w, 0)
+		health.LastChecked, health.LatestHeight = checks[i].LastChecked, checks[i].LatestHeight
+		health.Latency, health.Err = checks[i].Latency, checks[i].Err
+	}
+	c.lastWitnessCheck = time.Now()
+
+	suspects := c.suspects[:0]
+	for _, w := range c.suspects {
+		if c.witnessHealthOf(w, WitnessSuspect).healthy(height) {
+			c.logger.Info("Suspect witness is healthy again", "witness", w)
+			c.spareWitnesses = append(c.spareWitnesses, w)
+			continue
+		}
+		suspects = append(suspects, w)
+	}
+	c.suspects = suspects
+
+	for i, w := range c.witnesses {
+		health := c.witnessHealthOf(w, WitnessActive)
+		if health.healthy(height) {
+			continue
+		}
+		spare := c.takeHealthySpare(height)
+		if spare == nil {
+			c.logger.Info("Witness is unhealthy but no healthy spare can replace it", "witness", w,
+				"latest_height", health.LatestHeight, "err", health.Err)
+			continue
+		}
+		c.logger.Info("Replacing unhealthy witness by a spare", "witness", w, "spare", spare,
+			"latest_height", health.LatestHeight, "err", health.Err)
+		c.witnesses[i] = spare
+		c.spareWitnesses = append(c.spareWitnesses, w)
+	}
+
+	c.checkPrimary(height)
+
+	// replace the witnesses which were evicted or promoted to primary
+	c.replenishWitnesses(height)
+	return nil
+}
+
+// replenishWitnesses promotes healthy spares until the number of active
+// witnesses is back to the initial one.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) replenishWitnesses(height int64) {
+	for len(c.witnesses) < c.numWitnesses {
+		spare := c.takeHealthySpare(height)
+		if spare == nil {
+			break
+		}
+		c.logger.Info("Promoting spare witness", "spare", spare)
+		c.witnesses = append(c.witnesses, spare)
+	}
+}
+
+// takeHealthySpare removes the spare witness with the lowest latency among
+// the healthy ones from the spares, and returns it. It returns nil if no
+// spare is healthy.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) takeHealthySpare(height int64) provider.Provider {
+	healthy := make([]int, 0, len(c.spareWitnesses))
+	for i, w := range c.spareWitnesses {
+		if c.witnessHealthOf(w, WitnessSpare).healthy(height) {
+			healthy = append(healthy, i)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	sort.SliceStable(healthy, func(i, j int) bool {
+		return c.witnessHealthOf(c.spareWitnesses[healthy[i]], WitnessSpare).Latency <
+			c.witnessHealthOf(c.spareWitnesses[healthy[j]], WitnessSpare).Latency
+	})
+	spare := c.spareWitnesses[healthy[0]]
+	c.spareWitnesses = append(c.spareWitnesses[:healthy[0]], c.spareWitnesses[healthy[0]+1:]...)
+	return spare
+}
+
+// evictWitnesses permanently removes the witnesses at the given indexes for
+// misbehaving, replacing them by healthy spares if possible. The reasons are
+// the misbehavior of each witness.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) evictWitnesses(indexes []int, reasons []error) error {
+	statuses := make([]WitnessStatus, len(indexes))
+	for i := range statuses {
+		statuses[i] = WitnessEvicted
+	}
+	return c.replaceWitnesses(indexes, reasons, statuses)
+}
+
+// replaceWitnesses removes the witnesses at the given indexes, replacing them
+// by healthy spares if possible. Each witness is either evicted for good or
+// demoted to a spare, as per its status, for the given reason. Demoted
+// witnesses are unhealthy until their next health check succeeds.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) replaceWitnesses(indexes []int, reasons []error, statuses []WitnessStatus) error {
+	removed := make([]provider.Provider, len(indexes))
+	for i, index := range indexes {
+		removed[i] = c.witnesses[index]
+	}
+
+	// the spares are appended, so that the indexes still hold
+	for range indexes {
+		spare := c.takeHealthySpare(0)
+		if spare == nil {
+			break
+		}
+		c.logger.Info("Promoting spare witness", "spare", spare)
+		c.witnesses = append(c.witnesses, spare)
+	}
+
+	if err := c.removeWitnesses(indexes); err != nil {
+		return err
+	}
+
+	for i, w := range removed {
+		health := c.witnessHealthOf(w, statuses[i])
+		health.Err = reasons[i]
+		if statuses[i] == WitnessSpare {
+			c.logger.Info("Demoted witness to spare", "witness", w, "reason", reasons[i])
+			health.LastChecked = time.Now()
+			c.spareWitnesses = append(c.spareWitnesses, w)
+			continue
+		}
+		c.logger.Info("Evicted witness", "witness", w, "reason", reasons[i])
+		c.evictedWitnesses = append(c.evictedWitnesses, w)
+	}
+	return nil
+}
+
+// witnessHealthOf returns the health of the witness, creating it with the
+// given status if it doesn't exist yet. A status other than 0 updates the
+// status of the witness.
+//
+// NOTE: requires a providerMutex lock
+func (c *Client) witnessHealthOf(w provider.Provider, status WitnessStatus) *WitnessHealth {
+	if c.witnessHealth == nil {
+		c.witnessHealth = make(map[provider.Provider]*WitnessHealth)
+	}
+	health, ok := c.witnessHealth[w]
+	if !ok {
+		health = &WitnessHealth{Witness: w}
+		c.witnessHealth[w] = health
+	}
+	if status != 0 {
+		health.Status = status
+	}
+	return health
+}
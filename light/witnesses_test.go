@@ -0,0 +1,159 @@
+package light_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/light"
+	"github.com/tendermint/tendermint/light/provider"
+	mockp "github.com/tendermint/tendermint/light/provider/mock"
+	dbs "github.com/tendermint/tendermint/light/store/db"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestClientReplacesStaleWitness(t *testing.T) {
+	var (
+		// the stale witness is stuck at height 2
+		staleWitness = mockp.New(chainID, map[int64]*types.SignedHeader{1: h1, 2: h2}, valSet)
+		spare        = mockp.New(chainID, headerSet, valSet)
+	)
+
+	c, err := light.NewClient(
+		ctx,
+		chainID,
+		trustOptions,
+		fullNode,
+		[]provider.Provider{staleWitness},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.SpareWitnesses(spare),
+		light.WitnessHealthCheckInterval(time.Nanosecond),
+	)
+	require.NoError(t, err)
+
+	_, err = c.VerifyLightBlockAtHeight(ctx, 3, bTime.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, []provider.Provider{spare}, c.Witnesses())
+	health := c.WitnessHealth()
+	require.Len(t, health, 2)
+	assert.Equal(t, spare, health[0].Witness)
+	assert.Equal(t, light.WitnessActive, health[0].Status)
+	assert.EqualValues(t, 3, health[0].LatestHeight)
+	assert.Equal(t, staleWitness, health[1].Witness)
+	assert.Equal(t, light.WitnessSpare, health[1].Status)
+	assert.EqualValues(t, 2, health[1].LatestHeight)
+	assert.False(t, health[1].LastChecked.IsZero())
+}
+
+func TestClientKeepsWitnessOnFork(t *testing.T) {
+	// the witness performs an equivocation attack
+	var (
+		latestHeight      = int64(10)
+		valSize           = 5
+		divergenceHeight  = int64(6)
+		witnessHeaders    = make(map[int64]*types.SignedHeader, latestHeight)
+		witnessValidators = make(map[int64]*types.ValidatorSet, latestHeight)
+	)
+	primaryHeaders, primaryValidators, chainKeys := genMockNodeWithKeys(chainID, latestHeight+2, valSize, 2, bTime)
+	primary := mockp.New(chainID, primaryHeaders, primaryValidators)
+	spare := mockp.New(chainID, primaryHeaders, primaryValidators)
+
+	for height := int64(1); height <= latestHeight; height++ {
+		if height < divergenceHeight {
+			witnessHeaders[height] = primaryHeaders[height]
+			witnessValidators[height] = primaryValidators[height]
+			continue
+		}
+		witnessHeaders[height] = chainKeys[height].GenSignedHeader(chainID, height,
+			bTime.Add(time.Duration(height)*time.Minute), []types.Tx{[]byte("abcd")},
+			primaryValidators[height], primaryValidators[height+1], hash("app_hash"),
+			hash("cons_hash"), hash("results_hash"), 0, len(chainKeys[height])-1)
+		witnessValidators[height] = primaryValidators[height]
+	}
+	witness := mockp.New(chainID, witnessHeaders, witnessValidators)
+
+	c, err := light.NewClient(
+		ctx,
+		chainID,
+		light.TrustOptions{
+			Period: 4 * time.Hour,
+			Height: 1,
+			Hash:   primaryHeaders[1].Hash(),
+		},
+		primary,
+		[]provider.Provider{witness},
+		dbs.New(dbm.NewMemDB(), chainID),
+		light.Logger(log.TestingLogger()),
+		light.MaxRetryAttempts(1),
+		light.SpareWitnesses(spare),
+	)
+	require.NoError(t, err)
+
+	_, err = c.VerifyLightBlockAtHeight(ctx, 10, bTime.Add(1*time.Hour))
+	assert.Equal(t, light.ErrLightClientAttack, err)
+
+	evAgainstWitness := &types.LightClientAttackEvidence{
+		ConflictingBlock: &types.LightBlock{
+			SignedHeader: witnessHeaders[divergenceHeight],
+			ValidatorSet: witnessValidators[divergenceHeight],
+		},
+		CommonHeight: divergenceHeight,
+	}
+	assert.True(t, primary.HasEvidence(evAgainstWitness))
+
+	// both traces verify, hence the chain forked and the witness may be the
+	// honest party, so it stays active
+	assert.Equal(t, []provider.Provider{witness}, c.Witnesses())
+	health := c.WitnessHealth()
+	require.Len(t, health, 2)
+	assert.Equal(t, witness, health[0].Witness)
+	assert.Equal(t, light.WitnessActive, health[0].Status)
+	assert.Equal(t, spare, health[1].Witness)
+	assert.Equal(t, light.WitnessSpare, health[1].Status)
+}
+
+// unreachableProvider fails to connect to its node.
+type unreachableProvider struct {
+	provider.Provider
+}
+
+func (p unreachableProvider) LightBlock(context.Context, int64) (*types.LightBlock, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestClientDemotesUnreachableWitness(t *testing.T) {
+	unreachable := unreachableProvider{fullNode}
+
+	db := dbs.New(dbm.NewMemDB(), chainID)
+	require.NoError(t, db.SaveLightBlock(l1))
+	c, err := light.NewClientFromTrustedStore(
+		chainID,
+		trustPeriod,
+		deadNode,
+		[]provider.Provider{unreachable, deadNode, deadNode},
+		db,
+		light.Logger(log.TestingLogger()),
+		light.MaxRetryAttempts(1),
+	)
+	require.NoError(t, err)
+
+	_, err = c.Update(ctx, bTime.Add(2*time.Hour))
+	require.Error(t, err)
+
+	// the witness which failed to connect may come back, so it is a spare
+	assert.Equal(t, []provider.Provider{deadNode, deadNode}, c.Witnesses())
+	health := c.WitnessHealth()
+	require.Len(t, health, 3)
+	assert.Equal(t, unreachable, health[2].Witness)
+	assert.Equal(t, light.WitnessSpare, health[2].Status)
+	assert.EqualError(t, health[2].Err, "connection refused")
+}
@@ -196,6 +196,17 @@ func (s *SeenTxSet) Get(txKey types.TxKey) map[uint16]struct{} {
 	return peers
 }
 
+// Keys returns the keys of the transactions seen by at least one peer.
+func (s *SeenTxSet) Keys() []types.TxKey {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	keys := make([]types.TxKey, 0, len(s.set))
+	for key := range s.set {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 // Len returns the amount of cached items. Mostly used for testing.
 func (s *SeenTxSet) Len() int {
 	s.mtx.Lock()
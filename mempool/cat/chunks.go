@@ -0,0 +1,165 @@
+package cat
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// DefaultMaxTxMsgSize is the default size over which the txs requested
+	// by a peer are sent to it in chunks.
+	DefaultMaxTxMsgSize = 1024 * 1024 // 1MB
+
+	// maxPartialTxs bounds the number of txs reassembled at once.
+	maxPartialTxs = 16
+
+	// txChunkTimeout is how long the chunks of a tx are kept waiting for
+	// the rest of them.
+	txChunkTimeout = time.Minute
+)
+
+// errTooManyPartialTxs is returned for the first chunk of a tx when maxTxs
+// txs are already being reassembled.
+var errTooManyPartialTxs = errors.New("too many txs being reassembled")
+
+// splitTx splits tx in chunks of up to size bytes.
+func splitTx(tx types.Tx, size int) [][]byte {
+	chunks := make([][]byte, 0, (len(tx)+size-1)/size)
+	for len(tx) > size {
+		chunks = append(chunks, tx[:size])
+		tx = tx[size:]
+	}
+	return append(chunks, tx)
+}
+
+// txAssembler reassembles the txs received in chunks. It holds the chunks of
+// up to maxTxs txs of up to maxTxSize bytes each, and drops the ones which
+// aren't complete within timeout. The chunks of a tx sent by different peers
+// are kept apart, so that a peer sending bad chunks can't fail the tx sent by
+// another. It is thread safe.
+type txAssembler struct {
+	mtx sync.Mutex
+
+	maxTxs    int
+	maxTxSize int
+	timeout   time.Duration
+
+	partial map[partialTxKey]*partialTx
+	now     func() time.Time
+}
+
+// partialTxKey identifies a tx being reassembled from the chunks of a peer.
+type partialTxKey struct {
+	peer uint16
+	key  types.TxKey
+}
+
+type partialTx struct {
+	chunks  [][]byte
+	missing int
+	size    int
+	started time.Time
+}
+
+func newTxAssembler(maxTxs, maxTxSize int, timeout time.Duration) *txAssembler {
+	return &txAssembler{
+		maxTxs:    maxTxs,
+		maxTxSize: maxTxSize,
+		timeout:   timeout,
+		partial:   make(map[partialTxKey]*partialTx),
+		now:       time.Now,
+	}
+}
+
+// add records the chunk index of the total chunks of the tx key sent by peer.
+// Once all of them are received from peer, it returns the tx reassembled. It
+// returns an error if the chunk is invalid, or if the tx reassembled doesn't
+// match its key.
+func (a *txAssembler) add(peer uint16, key types.TxKey, index, total uint32, data []byte) (types.Tx, error) {
+	if total == 0 || index >= total {
+		return nil, fmt.Errorf("invalid chunk %d of %d", index, total)
+	}
+	if len(data) == 0 || int64(total) > int64(a.maxTxSize) {
+		return nil, fmt.Errorf("tx of %d chunks larger than max %d bytes", total, a.maxTxSize)
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	now := a.now()
+	a.prune(now)
+
+	pk := partialTxKey{peer: peer, key: key}
+	p, ok := a.partial[pk]
+	if ok && len(p.chunks) != int(total) {
+		// the peer sends the tx again, split differently
+		delete(a.partial, pk)
+		ok = false
+	}
+	if !ok {
+		if len(a.partial) >= a.maxTxs {
+			return nil, errTooManyPartialTxs
+		}
+		p = &partialTx{chunks: make([][]byte, total), missing: int(total), started: now}
+		a.partial[pk] = p
+	}
+	if p.chunks[index] != nil {
+		return nil, nil
+	}
+	p.size += len(data)
+	if p.size > a.maxTxSize {
+		delete(a.partial, pk)
+		return nil, fmt.Errorf("tx larger than max %d bytes", a.maxTxSize)
+	}
+	p.chunks[index] = data
+	p.missing--
+	if p.missing > 0 {
+		return nil, nil
+	}
+
+	delete(a.partial, pk)
+	tx := make(types.Tx, 0, p.size)
+	for _, chunk := range p.chunks {
+		tx = append(tx, chunk...)
+	}
+	if tx.Key() != key {
+		return nil, fmt.Errorf("chunks of tx %X don't match its key", key)
+	}
+	// the chunks of the tx sent by other peers aren't needed anymore
+	for other := range a.partial {
+		if other.key == key {
+			delete(a.partial, other)
+		}
+	}
+	return tx, nil
+}
+
+// removePeer drops the chunks sent by peer.
+func (a *txAssembler) removePeer(peer uint16) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	for pk := range a.partial {
+		if pk.peer == peer {
+			delete(a.partial, pk)
+		}
+	}
+}
+
+// prune drops the txs started over timeout ago.
+func (a *txAssembler) prune(now time.Time) {
+	for pk, p := range a.partial {
+		if now.Sub(p.started) > a.timeout {
+			delete(a.partial, pk)
+		}
+	}
+}
+
+// len returns the number of txs being reassembled.
+func (a *txAssembler) len() int {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return len(a.partial)
+}
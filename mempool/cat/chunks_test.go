@@ -0,0 +1,100 @@
+package cat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestSplitTx(t *testing.T) {
+	tx := types.Tx("abcdefgh")
+	assert.Equal(t, [][]byte{[]byte("abc"), []byte("def"), []byte("gh")}, splitTx(tx, 3))
+	assert.Equal(t, [][]byte{[]byte("abcd"), []byte("efgh")}, splitTx(tx, 4))
+	assert.Equal(t, [][]byte{[]byte("abcdefgh")}, splitTx(tx, 8))
+}
+
+func TestTxAssembler(t *testing.T) {
+	a := newTxAssembler(2, 10, time.Minute)
+	now := time.Now()
+	a.now = func() time.Time { return now }
+
+	tx := types.Tx("abcdefgh")
+	key := tx.Key()
+	chunks := splitTx(tx, 3)
+
+	// the chunks are reassembled in any order, ignoring the duplicates
+	for _, i := range []uint32{2, 0, 2} {
+		got, err := a.add(1, key, i, 3, chunks[i])
+		require.NoError(t, err)
+		require.Nil(t, got)
+	}
+	got, err := a.add(1, key, 1, 3, chunks[1])
+	require.NoError(t, err)
+	assert.Equal(t, tx, got)
+	assert.Zero(t, a.len())
+
+	// the reassembled tx must match its key
+	other := types.Tx("other").Key()
+	_, err = a.add(1, other, 0, 1, tx)
+	require.Error(t, err)
+
+	// the chunks must be valid and fit in the max tx size
+	_, err = a.add(1, key, 3, 3, chunks[0])
+	require.Error(t, err)
+	_, err = a.add(1, key, 0, 11, chunks[0])
+	require.Error(t, err)
+	_, err = a.add(1, key, 0, 2, []byte("0123456"))
+	require.NoError(t, err)
+	_, err = a.add(1, key, 1, 2, []byte("0123"))
+	require.Error(t, err)
+	assert.Zero(t, a.len(), "the txs too large are dropped")
+
+	// the number of txs reassembled at once is bounded
+	_, err = a.add(1, types.Tx("a").Key(), 0, 2, []byte("a"))
+	require.NoError(t, err)
+	_, err = a.add(1, types.Tx("b").Key(), 0, 2, []byte("b"))
+	require.NoError(t, err)
+	_, err = a.add(1, types.Tx("c").Key(), 0, 2, []byte("c"))
+	require.Equal(t, errTooManyPartialTxs, err)
+
+	// the txs not completed in time are pruned
+	now = now.Add(2 * time.Minute)
+	_, err = a.add(1, types.Tx("c").Key(), 0, 2, []byte("c"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.len())
+}
+
+func TestTxAssemblerPeers(t *testing.T) {
+	a := newTxAssembler(4, 10, time.Minute)
+	tx := types.Tx("abcdefgh")
+	key := tx.Key()
+
+	// the chunks of the peers aren't mixed: the bad chunk of the second
+	// peer fails its own tx only
+	_, err := a.add(1, key, 0, 2, []byte("abcd"))
+	require.NoError(t, err)
+	_, err = a.add(2, key, 0, 2, []byte("xxxx"))
+	require.NoError(t, err)
+	_, err = a.add(2, key, 1, 2, []byte("efgh"))
+	require.Error(t, err)
+
+	// nor does a peer splitting the tx differently reset the others
+	_, err = a.add(2, key, 0, 3, []byte("abc"))
+	require.NoError(t, err)
+	got, err := a.add(1, key, 1, 2, []byte("efgh"))
+	require.NoError(t, err)
+	assert.Equal(t, tx, got)
+	assert.Zero(t, a.len(), "the chunks of the other peers are dropped")
+
+	// the chunks of a removed peer are dropped
+	_, err = a.add(1, key, 0, 2, []byte("abcd"))
+	require.NoError(t, err)
+	_, err = a.add(2, key, 0, 2, []byte("abcd"))
+	require.NoError(t, err)
+	a.removePeer(1)
+	assert.Equal(t, 1, a.len())
+}
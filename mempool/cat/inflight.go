@@ -0,0 +1,158 @@
+package cat
+
+import (
+	"sync"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+// DefaultMaxInFlightWantsPerPeer is the default maximum number of WantTx
+// requests outstanding to a single peer.
+const DefaultMaxInFlightWantsPerPeer = 16
+
+// inFlightRequests bounds the number of outstanding tx requests per peer, so
+// that a burst of missing txs doesn't saturate the send queue of a single
+// peer. The txs which couldn't be requested, because all the peers which have
+// seen them are at the bound, are queued until a request slot frees.
+type inFlightRequests struct {
+	mtx sync.Mutex
+
+	// max is the maximum number of outstanding requests per peer
+	max int
+
+	// byPeer holds the txs with an outstanding request, by peer
+	byPeer map[uint16]map[types.TxKey]struct{}
+
+	// queue holds the txs waiting for a request slot, in the order they
+	// were queued, and queued is the set of them
+	queue  []types.TxKey
+	queued map[types.TxKey]struct{}
+}
+
+func newInFlightRequests(max int) *inFlightRequests {
+	return &inFlightRequests{
+		max:    max,
+		byPeer: make(map[uint16]map[types.TxKey]struct{}),
+		queued: make(map[types.TxKey]struct{}),
+	}
+}
+
+// acquire takes a request slot of peer for the tx key. It returns false if
+// peer has no free slot.
+func (f *inFlightRequests) acquire(peer uint16, key types.TxKey) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	requests, ok := f.byPeer[peer]
+	if !ok {
+		requests = make(map[types.TxKey]struct{})
+		f.byPeer[peer] = requests
+	}
+	if _, ok := requests[key]; ok {
+		return true
+	}
+	if len(requests) >= f.max {
+		return false
+	}
+	requests[key] = struct{}{}
+	return true
+}
+
+// release frees the request slot of peer taken for the tx key. It returns
+// false if there was none, e.g. because it was already released.
+func (f *inFlightRequests) release(peer uint16, key types.TxKey) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	requests, ok := f.byPeer[peer]
+	if !ok {
+		return false
+	}
+	if _, ok := requests[key]; !ok {
+		return false
+	}
+	delete(requests, key)
+	if len(requests) == 0 {
+		delete(f.byPeer, peer)
+	}
+	return true
+}
+
+// releaseAll frees all the request slots of peer.
+func (f *inFlightRequests) releaseAll(peer uint16) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	delete(f.byPeer, peer)
+}
+
+// count returns the number of outstanding requests to peer.
+func (f *inFlightRequests) count(peer uint16) int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return len(f.byPeer[peer])
+}
+
+// enqueue queues the tx key until a request slot frees, unless it is already
+// queued.
+func (f *inFlightRequests) enqueue(key types.TxKey) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if _, ok := f.queued[key]; ok {
+		return
+	}
+	f.queued[key] = struct{}{}
+	f.queue = append(f.queue, key)
+}
+
+// dequeue removes and returns the first queued tx for which take returns true.
+// The queued txs for which drop returns true before it, e.g. because they
+// were received in the meantime, are removed. It returns false if take
+// returned false for all the queued txs.
+func (f *inFlightRequests) dequeue(take, drop func(types.TxKey) bool) (types.TxKey, bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	kept := f.queue[:0]
+	for i, key := range f.queue {
+		if drop(key) {
+			delete(f.queued, key)
+			continue
+		}
+		if take(key) {
+			delete(f.queued, key)
+			f.queue = append(kept, f.queue[i+1:]...)
+			return key, true
+		}
+		kept = append(kept, key)
+	}
+	f.queue = kept
+	return types.TxKey{}, false
+}
+
+// prune removes the queued txs for which drop returns true, e.g. because
+// no connected peer has seen them anymore.
+func (f *inFlightRequests) prune(drop func(types.TxKey) bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	kept := f.queue[:0]
+	for _, key := range f.queue {
+		if drop(key) {
+			delete(f.queued, key)
+			continue
+		}
+		kept = append(kept, key)
+	}
+	f.queue = kept
+}
+
+// queueLen returns the number of queued txs.
+func (f *inFlightRequests) queueLen() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return len(f.queue)
+}
@@ -0,0 +1,84 @@
+package cat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestInFlightRequests(t *testing.T) {
+	f := newInFlightRequests(2)
+	a, b, c := types.Tx("a").Key(), types.Tx("b").Key(), types.Tx("c").Key()
+
+	require.True(t, f.acquire(1, a))
+	require.True(t, f.acquire(1, a), "acquiring the same request twice takes one slot")
+	require.True(t, f.acquire(1, b))
+	require.False(t, f.acquire(1, c))
+	require.True(t, f.acquire(2, c), "the slots are per peer")
+	assert.Equal(t, 2, f.count(1))
+
+	assert.True(t, f.release(1, a))
+	assert.False(t, f.release(1, a), "a request is released once")
+	assert.False(t, f.release(2, b))
+	assert.True(t, f.acquire(1, c))
+
+	f.releaseAll(1)
+	assert.Zero(t, f.count(1))
+	assert.Equal(t, 1, f.count(2))
+}
+
+func TestInFlightRequestsQueue(t *testing.T) {
+	f := newInFlightRequests(1)
+	a, b, c := types.Tx("a").Key(), types.Tx("b").Key(), types.Tx("c").Key()
+	f.enqueue(a)
+	f.enqueue(b)
+	f.enqueue(a)
+	f.enqueue(c)
+	require.Equal(t, 3, f.queueLen())
+
+	never := func(types.TxKey) bool { return false }
+	is := func(key types.TxKey) func(types.TxKey) bool {
+		return func(k types.TxKey) bool { return k == key }
+	}
+
+	_, ok := f.dequeue(never, never)
+	assert.False(t, ok)
+	assert.Equal(t, 3, f.queueLen())
+
+	// b is taken, a is dropped on the way, c is left
+	key, ok := f.dequeue(is(b), is(a))
+	require.True(t, ok)
+	assert.Equal(t, b, key)
+	assert.Equal(t, 1, f.queueLen())
+
+	// a can be queued again once dropped
+	f.enqueue(a)
+	key, ok = f.dequeue(is(a), never)
+	require.True(t, ok)
+	assert.Equal(t, a, key)
+	key, ok = f.dequeue(is(c), never)
+	require.True(t, ok)
+	assert.Equal(t, c, key)
+	assert.Zero(t, f.queueLen())
+}
+
+func TestInFlightRequestsPrune(t *testing.T) {
+	f := newInFlightRequests(1)
+	a, b, c := types.Tx("a").Key(), types.Tx("b").Key(), types.Tx("c").Key()
+	f.enqueue(a)
+	f.enqueue(b)
+	f.enqueue(c)
+
+	f.prune(func(key types.TxKey) bool { return key != b })
+	assert.Equal(t, 1, f.queueLen())
+	key, ok := f.dequeue(func(types.TxKey) bool { return true }, func(types.TxKey) bool { return false })
+	require.True(t, ok)
+	assert.Equal(t, b, key)
+
+	// a can be queued again once pruned
+	f.enqueue(a)
+	assert.Equal(t, 1, f.queueLen())
+}
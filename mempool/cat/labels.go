@@ -0,0 +1,47 @@
+package cat
+
+import (
+	"sync"
+
+	"github.com/tendermint/tendermint/p2p"
+)
+
+const (
+	// maxPeerLabels bounds the number of peers whose metrics are labeled
+	// with their ID, so that the number of series stays bounded as peers
+	// come and go.
+	maxPeerLabels = 100
+
+	// otherPeersLabel labels the metrics of the peers over maxPeerLabels.
+	otherPeersLabel = "other"
+)
+
+// peerLabels hands out the peer_id labels of the per peer metrics. The first
+// max peers seen are labeled with their ID for the lifetime of the node, and
+// the others share otherPeersLabel. It is thread safe.
+type peerLabels struct {
+	mtx    sync.Mutex
+	max    int
+	labels map[p2p.ID]struct{}
+}
+
+func newPeerLabels(max int) *peerLabels {
+	return &peerLabels{
+		max:    max,
+		labels: make(map[p2p.ID]struct{}),
+	}
+}
+
+// label returns the peer_id label of the peer with the given ID.
+func (l *peerLabels) label(id p2p.ID) string {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if _, ok := l.labels[id]; ok {
+		return string(id)
+	}
+	if len(l.labels) >= l.max {
+		return otherPeersLabel
+	}
+	l.labels[id] = struct{}{}
+	return string(id)
+}
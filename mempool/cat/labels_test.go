@@ -0,0 +1,15 @@
+package cat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerLabels(t *testing.T) {
+	l := newPeerLabels(2)
+	assert.Equal(t, "a", l.label("a"))
+	assert.Equal(t, "b", l.label("b"))
+	assert.Equal(t, otherPeersLabel, l.label("c"))
+	assert.Equal(t, "a", l.label("a"), "a labeled peer keeps its label")
+}
@@ -0,0 +1,156 @@
+package cat
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// latencyAlpha is the weight of each request in the moving averages of
+	// the latency and success rate of a peer.
+	latencyAlpha = 0.2
+
+	// latencyHalfLife is the time after which the stats of a peer are half
+	// forgotten, so that a peer isn't judged on its past behavior forever.
+	latencyHalfLife = time.Minute
+
+	// minSuccessRate bounds the success rate the latency of a peer is scaled
+	// by, so that the peers which failed every request are still ordered.
+	minSuccessRate = 0.01
+)
+
+// peerLatencies tracks the moving averages of the time each peer takes to
+// send the txs requested from it, and of the fraction of the requests it
+// answers in time. The stats of a peer decay back to those of an unknown peer,
+// answering every request within prior, as they age. It is thread safe.
+type peerLatencies struct {
+	mtx sync.Mutex
+
+	prior    time.Duration
+	halfLife time.Duration
+
+	peers map[uint16]*peerLatency
+	now   func() time.Time
+}
+
+type peerLatency struct {
+	latency     float64 // in nanoseconds
+	successRate float64
+	updated     time.Time
+	// pending are the start times of the outstanding requests to the peer.
+	pending map[types.TxKey]time.Time
+}
+
+func newPeerLatencies(prior, halfLife time.Duration) *peerLatencies {
+	return &peerLatencies{
+		prior:    prior,
+		halfLife: halfLife,
+		peers:    make(map[uint16]*peerLatency),
+		now:      time.Now,
+	}
+}
+
+// requested records the start of the request of the tx key to peer.
+func (l *peerLatencies) requested(peer uint16, key types.TxKey) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	p, ok := l.peers[peer]
+	if !ok {
+		p = &peerLatency{
+			latency:     float64(l.prior),
+			successRate: 1,
+			updated:     l.now(),
+			pending:     make(map[types.TxKey]time.Time),
+		}
+		l.peers[peer] = p
+	}
+	p.pending[key] = l.now()
+}
+
+// received records that peer answered the request of the tx key. It returns
+// false if there was no such request.
+func (l *peerLatencies) received(peer uint16, key types.TxKey) bool {
+	return l.complete(peer, key, 1)
+}
+
+// failed records that peer didn't answer the request of the tx key in time,
+// or that it couldn't be sent. It returns false if there was no such request.
+func (l *peerLatencies) failed(peer uint16, key types.TxKey) bool {
+	return l.complete(peer, key, 0)
+}
+
+func (l *peerLatencies) complete(peer uint16, key types.TxKey, success float64) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	p, ok := l.peers[peer]
+	if !ok {
+		return false
+	}
+	start, ok := p.pending[key]
+	if !ok {
+		return false
+	}
+	delete(p.pending, key)
+
+	now := l.now()
+	latency, successRate := l.decay(p, now)
+	p.latency = (1-latencyAlpha)*latency + latencyAlpha*float64(now.Sub(start))
+	p.successRate = (1-latencyAlpha)*successRate + latencyAlpha*success
+	p.updated = now
+	return true
+}
+
+// decay returns the stats of p decayed towards the prior from its last update
+// to now.
+func (l *peerLatencies) decay(p *peerLatency, now time.Time) (latency, successRate float64) {
+	w := math.Pow(0.5, float64(now.Sub(p.updated))/float64(l.halfLife))
+	return w*p.latency + (1-w)*float64(l.prior), w*p.successRate + (1 - w)
+}
+
+// stats returns the average latency and success rate of the requests to peer.
+func (l *peerLatencies) stats(peer uint16) (time.Duration, float64) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	p, ok := l.peers[peer]
+	if !ok {
+		return l.prior, 1
+	}
+	latency, successRate := l.decay(p, l.now())
+	return time.Duration(latency), successRate
+}
+
+// order sorts peers by the time they are expected to take to send a tx, i.e.
+// their latency scaled by their success rate, the fastest first.
+func (l *peerLatencies) order(peers []uint16) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := l.now()
+	costs := make(map[uint16]float64, len(peers))
+	for _, peer := range peers {
+		p, ok := l.peers[peer]
+		if !ok {
+			costs[peer] = float64(l.prior)
+			continue
+		}
+		latency, successRate := l.decay(p, now)
+		costs[peer] = latency / math.Max(successRate, minSuccessRate)
+	}
+	sort.Slice(peers, func(i, j int) bool {
+		if costs[peers[i]] != costs[peers[j]] {
+			return costs[peers[i]] < costs[peers[j]]
+		}
+		return peers[i] < peers[j]
+	})
+}
+
+// remove forgets the stats of peer, so that it starts afresh if it
+// reconnects.
+func (l *peerLatencies) remove(peer uint16) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	delete(l.peers, peer)
+}
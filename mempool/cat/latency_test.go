@@ -0,0 +1,69 @@
+package cat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestPeerLatencies(t *testing.T) {
+	l := newPeerLatencies(100*time.Millisecond, time.Minute)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	request := func(peer uint16, key types.TxKey, latency time.Duration, success bool) {
+		l.requested(peer, key)
+		now = now.Add(latency)
+		if success {
+			require.True(t, l.received(peer, key))
+		} else {
+			require.True(t, l.failed(peer, key))
+		}
+	}
+	order := func(peers ...uint16) []uint16 {
+		l.order(peers)
+		return peers
+	}
+	a, b := types.Tx("a").Key(), types.Tx("b").Key()
+
+	// the unknown peers are ordered by ID
+	assert.Equal(t, []uint16{1, 2, 3}, order(3, 2, 1))
+
+	// the fast peer comes first, then the unknown peer, then the slow one
+	for i := 0; i < 5; i++ {
+		request(1, a, 300*time.Millisecond, true)
+		request(2, a, 10*time.Millisecond, true)
+	}
+	assert.Equal(t, []uint16{2, 3, 1}, order(1, 2, 3))
+	latency, successRate := l.stats(2)
+	assert.Less(t, latency, 100*time.Millisecond)
+	assert.Equal(t, 1.0, successRate)
+
+	// only the pending requests are recorded
+	assert.False(t, l.received(2, b))
+	assert.False(t, l.failed(4, b))
+
+	// a peer failing its requests falls behind the slower ones
+	for i := 0; i < 10; i++ {
+		request(2, b, 50*time.Millisecond, false)
+	}
+	_, successRate = l.stats(2)
+	assert.Less(t, successRate, 0.2)
+	assert.Equal(t, []uint16{3, 1, 2}, order(1, 2, 3))
+
+	// the stats decay back to those of an unknown peer
+	now = now.Add(time.Hour)
+	latency, successRate = l.stats(1)
+	assert.InDelta(t, float64(100*time.Millisecond), float64(latency), float64(time.Millisecond))
+	assert.InDelta(t, 1, successRate, 0.01)
+
+	// the stats of a peer are reset when it's removed
+	request(1, a, 300*time.Millisecond, true)
+	l.remove(1)
+	latency, successRate = l.stats(1)
+	assert.Equal(t, 100*time.Millisecond, latency)
+	assert.Equal(t, 1.0, successRate)
+}
@@ -1,16 +1,19 @@
 package cat
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"sync"
 	"time"
 
+	abcicli "github.com/tendermint/tendermint/abci/client"
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
 )
@@ -49,6 +52,9 @@ type TxPool struct {
 	config       *config.MempoolConfig
 	proxyAppConn proxy.AppConnMempool
 	metrics      *mempool.Metrics
+	traceClient  trace.Tracer
+	eventBus     types.MempoolEventPublisher
+	now          func() time.Time
 
 	// these values are modified once per height
 	updateMtx            sync.Mutex
@@ -63,6 +69,9 @@ type TxPool struct {
 	rejectedTxCache *LRUTxCache
 	// Thread-safe cache of evicted transactions for quick look-up
 	evictedTxCache *LRUTxCache
+	// Thread-safe cache of the statuses of the recently evicted, rejected and
+	// committed transactions
+	txStatuses *txStatusCache
 	// Thread-safe list of transactions peers have seen that we have not yet seen
 	seenByPeersSet *SeenTxSet
 
@@ -74,6 +83,23 @@ type TxPool struct {
 	broadcastCh      chan *wrappedTx
 	broadcastMtx     sync.Mutex
 	txsToBeBroadcast []types.TxKey
+	// txsToBeRebroadcast is the queue of pending transactions to broadcast
+	// again to the peers which haven't seen them. It is guarded by
+	// broadcastMtx.
+	txsToBeRebroadcast []types.TxKey
+
+	// recheckMtx guards the state of the recheck of the transactions left
+	// after the latest Update, which runs in the background.
+	recheckMtx sync.Mutex
+	// recheckHeight is the height of the latest recheck. A recheck stops once
+	// a recheck at a later height starts.
+	recheckHeight int64
+	// pendingRechecks are the transactions whose recheck at recheckHeight
+	// hasn't completed. They aren't reaped until it does.
+	pendingRechecks map[types.TxKey]struct{}
+	// recheckDone is closed once the recheck at recheckHeight completes. It
+	// is nil if no recheck was started.
+	recheckDone chan struct{}
 }
 
 // NewTxPool constructs a new, empty content addressable txpool at the specified
@@ -90,8 +116,12 @@ func NewTxPool(
 		config:           cfg,
 		proxyAppConn:     proxyAppConn,
 		metrics:          mempool.NopMetrics(),
+		traceClient:      trace.NoOpTracer(),
+		eventBus:         types.NopEventBus{},
+		now:              time.Now,
 		rejectedTxCache:  NewLRUTxCache(cfg.CacheSize),
 		evictedTxCache:   NewLRUTxCache(cfg.CacheSize / 5),
+		txStatuses:       newTxStatusCache(maxTxStatuses),
 		seenByPeersSet:   NewSeenTxSet(),
 		height:           height,
 		preCheckFn:       func(_ types.Tx) error { return nil },
@@ -99,8 +129,13 @@ func NewTxPool(
 		store:            newStore(),
 		broadcastCh:      make(chan *wrappedTx),
 		txsToBeBroadcast: make([]types.TxKey, 0),
+		pendingRechecks:  make(map[types.TxKey]struct{}),
 	}
 
+	// The responses to the async CheckTx calls of the rechecks are handled
+	// through their requests.
+	proxyAppConn.SetResponseCallback(func(*abci.Request, *abci.Response) {})
+
 	for _, opt := range options {
 		opt(txmp)
 	}
@@ -127,6 +162,16 @@ func WithMetrics(metrics *mempool.Metrics) TxPoolOption {
 	return func(txmp *TxPool) { txmp.metrics = metrics }
 }
 
+// WithTraceClient sets the tracer collecting the mempool's trace events.
+func WithTraceClient(tc trace.Tracer) TxPoolOption {
+	return func(txmp *TxPool) { txmp.traceClient = tc }
+}
+
+// WithEventBus sets the event bus the mempool publishes the expiry of txs on.
+func WithEventBus(eventBus types.MempoolEventPublisher) TxPoolOption {
+	return func(txmp *TxPool) { txmp.eventBus = eventBus }
+}
+
 // Lock is a noop as ABCI calls are serialized
 func (txmp *TxPool) Lock() {}
 
@@ -199,23 +244,39 @@ func (txmp *TxPool) IsRejectedTx(txKey types.TxKey) bool {
 	return txmp.rejectedTxCache.Has(txKey)
 }
 
+// TxStatus returns the status of the transaction with the specified key:
+// pending if it is in the mempool, else evicted, rejected or committed if it
+// was in the last heights, or unknown.
+func (txmp *TxPool) TxStatus(txKey types.TxKey) mempool.TxStatus {
+	if wtx := txmp.store.get(txKey); wtx != nil {
+		return mempool.TxStatus{Status: mempool.TxStatusPending, Height: wtx.height}
+	}
+	if status, ok := txmp.txStatuses.get(txKey); ok {
+		return status
+	}
+	return mempool.TxStatus{Status: mempool.TxStatusUnknown}
+}
+
+// setTxStatus records the status of the transaction txKey at the current
+// height.
+func (txmp *TxPool) setTxStatus(txKey types.TxKey, status mempool.TxStatusCode, code uint32, log string) {
+	txmp.txStatuses.set(txKey, mempool.TxStatus{Status: status, Height: txmp.Height(), Code: code, Log: log})
+}
+
 // CheckToPurgeExpiredTxs checks if there has been adequate time since the last time
 // the txpool looped through all transactions and if so, performs a purge of any transaction
 // that has expired according to the TTLDuration. This is thread safe.
 func (txmp *TxPool) CheckToPurgeExpiredTxs() {
 	txmp.updateMtx.Lock()
 	defer txmp.updateMtx.Unlock()
-	if txmp.config.TTLDuration > 0 && time.Since(txmp.lastPurgeTime) > txmp.config.TTLDuration {
-		expirationAge := time.Now().Add(-txmp.config.TTLDuration)
+	now := txmp.now()
+	if txmp.config.TTLDuration > 0 && now.Sub(txmp.lastPurgeTime) > txmp.config.TTLDuration {
+		expirationAge := now.Add(-txmp.config.TTLDuration)
 		// A height of 0 means no transactions will be removed because of height
 		// (in other words, no transaction has a height less than 0)
-		purgedTxs, numExpired := txmp.store.purgeExpiredTxs(0, expirationAge)
-		// Add the purged transactions to the evicted cache
-		for _, tx := range purgedTxs {
-			txmp.evictedTxCache.Push(tx.key)
-		}
-		txmp.metrics.EvictedTxs.Add(float64(numExpired))
-		txmp.lastPurgeTime = time.Now()
+		purgedTxs, _ := txmp.store.purgeExpiredTxs(0, expirationAge)
+		txmp.expireTxs(purgedTxs, txmp.height)
+		txmp.lastPurgeTime = now
 	}
 }
 
@@ -223,6 +284,17 @@ func (txmp *TxPool) CheckToPurgeExpiredTxs() {
 // application's ABCI CheckTx method. This should be viewed as the entry method for new transactions
 // into the network. In practice this happens via an RPC endpoint
 func (txmp *TxPool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo mempool.TxInfo) error {
+	return txmp.CheckTxWithContext(context.Background(), tx, cb, txInfo)
+}
+
+// CheckTxWithContext is like CheckTx, but the call to the application fails
+// once ctx is done. Then tx is discarded and cb isn't called.
+func (txmp *TxPool) CheckTxWithContext(
+	ctx context.Context,
+	tx types.Tx,
+	cb func(*abci.Response),
+	txInfo mempool.TxInfo,
+) error {
 	// Reject transactions in excess of the configured maximum transaction size.
 	if len(tx) > txmp.config.MaxTxBytes {
 		return mempool.ErrTxTooLarge{Max: txmp.config.MaxTxBytes, Actual: len(tx)}
@@ -231,7 +303,7 @@ func (txmp *TxPool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo mempool
 	// This is a new transaction that we haven't seen before. Verify it against the app and attempt
 	// to add it to the transaction pool.
 	key := tx.Key()
-	rsp, err := txmp.TryAddNewTx(tx, key, txInfo)
+	rsp, err := txmp.TryAddNewTx(ctx, tx, key, txInfo)
 	if err != nil {
 		return err
 	}
@@ -289,11 +361,56 @@ func (txmp *TxPool) markToBeBroadcast(key types.TxKey) {
 	}
 }
 
+// markToBeRebroadcast queues the transactions which have been in the mempool
+// for a multiple of RebroadcastAfterHeights heights at blockHeight to be
+// broadcast again, so that each is rebroadcast once per interval.
+func (txmp *TxPool) markToBeRebroadcast(blockHeight int64) {
+	interval := txmp.config.RebroadcastAfterHeights
+	if !txmp.config.Broadcast || interval == 0 {
+		return
+	}
+
+	var keys []types.TxKey
+	for _, wtx := range txmp.store.getAllTxs() {
+		if age := blockHeight - wtx.height; age > 0 && age%interval == 0 {
+			keys = append(keys, wtx.key)
+		}
+	}
+	if len(keys) == 0 {
+		return
+	}
+	txmp.broadcastMtx.Lock()
+	defer txmp.broadcastMtx.Unlock()
+	txmp.txsToBeRebroadcast = append(txmp.txsToBeRebroadcast, keys...)
+}
+
+// nextRebroadcasts dequeues up to limit transactions to broadcast again. The
+// transactions no longer in the mempool are skipped.
+func (txmp *TxPool) nextRebroadcasts(limit int) []*wrappedTx {
+	txmp.broadcastMtx.Lock()
+	defer txmp.broadcastMtx.Unlock()
+	var txs []*wrappedTx
+	for len(txmp.txsToBeRebroadcast) != 0 && len(txs) < limit {
+		key := txmp.txsToBeRebroadcast[0]
+		txmp.txsToBeRebroadcast = txmp.txsToBeRebroadcast[1:]
+		if wtx := txmp.store.get(key); wtx != nil {
+			txs = append(txs, wtx)
+		}
+	}
+	return txs
+}
+
 // TryAddNewTx attempts to add a tx that has not already been seen before. It first marks it as seen
 // to avoid races with the same tx. It then call `CheckTx` so that the application can validate it.
 // If it passes `CheckTx`, the new transaction is added to the mempool as long as it has
-// sufficient priority and space else if evicted it will return an error
-func (txmp *TxPool) TryAddNewTx(tx types.Tx, key types.TxKey, txInfo mempool.TxInfo) (*abci.ResponseCheckTx, error) {
+// sufficient priority and space else if evicted it will return an error.
+// The call to `CheckTx` fails once ctx is done.
+func (txmp *TxPool) TryAddNewTx(
+	ctx context.Context,
+	tx types.Tx,
+	key types.TxKey,
+	txInfo mempool.TxInfo,
+) (*abci.ResponseCheckTx, error) {
 	// First check any of the caches to see if we can conclude early. We may have already seen and processed
 	// the transaction if:
 	// - We are connected to nodes running v0 or v1 which simply flood the network
@@ -322,6 +439,7 @@ func (txmp *TxPool) TryAddNewTx(tx types.Tx, key types.TxKey, txInfo mempool.TxI
 	// If a precheck hook is defined, call it before invoking the application.
 	if err := txmp.preCheck(tx); err != nil {
 		txmp.metrics.FailedTxs.Add(1)
+		txmp.setTxStatus(key, mempool.TxStatusRejected, 0, err.Error())
 		return nil, mempool.ErrPreCheck{Reason: err}
 	}
 
@@ -331,7 +449,9 @@ func (txmp *TxPool) TryAddNewTx(tx types.Tx, key types.TxKey, txInfo mempool.TxI
 	}
 
 	// Invoke an ABCI CheckTx for this transaction.
-	rsp, err := txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: tx})
+	ctx, cancel := mempool.ContextWithCheckTxTimeout(ctx, txmp.config.CheckTxTimeout)
+	defer cancel()
+	rsp, err := txmp.proxyAppConn.CheckTxSyncWithContext(ctx, abci.RequestCheckTx{Tx: tx})
 	if err != nil {
 		return rsp, err
 	}
@@ -340,6 +460,7 @@ func (txmp *TxPool) TryAddNewTx(tx types.Tx, key types.TxKey, txInfo mempool.TxI
 			txmp.rejectedTxCache.Push(key)
 		}
 		txmp.metrics.FailedTxs.Add(1)
+		txmp.setTxStatus(key, mempool.TxStatusRejected, rsp.Code, rsp.Log)
 		return rsp, fmt.Errorf("application rejected transaction with code %d (Log: %s)", rsp.Code, rsp.Log)
 	}
 
@@ -347,6 +468,7 @@ func (txmp *TxPool) TryAddNewTx(tx types.Tx, key types.TxKey, txInfo mempool.TxI
 	wtx := newWrappedTx(
 		tx, key, txmp.Height(), rsp.GasWanted, rsp.Priority, rsp.Sender,
 	)
+	wtx.timestamp = txmp.now().UTC()
 
 	// Perform the post check
 	err = txmp.postCheck(wtx.tx, rsp)
@@ -355,6 +477,7 @@ func (txmp *TxPool) TryAddNewTx(tx types.Tx, key types.TxKey, txInfo mempool.TxI
 			txmp.rejectedTxCache.Push(key)
 		}
 		txmp.metrics.FailedTxs.Add(1)
+		txmp.setTxStatus(key, mempool.TxStatusRejected, rsp.Code, err.Error())
 		return rsp, fmt.Errorf("rejected bad transaction after post check: %w", err)
 	}
 
@@ -390,10 +513,12 @@ func (txmp *TxPool) Flush() {
 	txmp.seenByPeersSet.Reset()
 	txmp.rejectedTxCache.Reset()
 	txmp.evictedTxCache.Reset()
+	txmp.txStatuses.reset()
 	txmp.metrics.EvictedTxs.Add(float64(size))
 	txmp.broadcastMtx.Lock()
 	defer txmp.broadcastMtx.Unlock()
 	txmp.txsToBeBroadcast = make([]types.TxKey, 0)
+	txmp.txsToBeRebroadcast = nil
 }
 
 // PeerHasTx marks that the transaction has been seen by a peer.
@@ -402,24 +527,10 @@ func (txmp *TxPool) PeerHasTx(peer uint16, txKey types.TxKey) {
 	txmp.seenByPeersSet.Add(txKey, peer)
 }
 
-// allEntriesSorted returns a slice of all the transactions currently in the
-// mempool, sorted in nonincreasing order by priority with ties broken by
-// increasing order of arrival time.
-func (txmp *TxPool) allEntriesSorted() []*wrappedTx {
-	txs := txmp.store.getAllTxs()
-	sort.Slice(txs, func(i, j int) bool {
-		if txs[i].priority == txs[j].priority {
-			return txs[i].timestamp.Before(txs[j].timestamp)
-		}
-		return txs[i].priority > txs[j].priority // N.B. higher priorities first
-	})
-	return txs
-}
-
 // ReapMaxBytesMaxGas returns a slice of valid transactions that fit within the
 // size and gas constraints. The results are ordered by nonincreasing priority,
-// with ties broken by increasing order of arrival. Reaping transactions does
-// not remove them from the mempool
+// with ties broken by increasing order of arrival then by key. Reaping
+// transactions does not remove them from the mempool
 //
 // If maxBytes < 0, no limit is set on the total size in bytes.
 // If maxGas < 0, no limit is set on the total gas cost.
@@ -429,25 +540,33 @@ func (txmp *TxPool) allEntriesSorted() []*wrappedTx {
 func (txmp *TxPool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 	var totalGas, totalBytes int64
 
+	txmp.recheckMtx.Lock()
+	defer txmp.recheckMtx.Unlock()
+
 	var keep []types.Tx //nolint:prealloc
-	for _, w := range txmp.allEntriesSorted() {
+	txmp.store.iterateOrderedTxs(func(w *wrappedTx) bool {
+		if _, ok := txmp.pendingRechecks[w.key]; ok {
+			return true
+		}
 		// N.B. When computing byte size, we need to include the overhead for
 		// encoding as protobuf to send to the application. This actually overestimates it
 		// as we add the proto overhead to each transaction
 		txBytes := types.ComputeProtoSizeForTxs([]types.Tx{w.tx})
 		if (maxGas >= 0 && totalGas+w.gasWanted > maxGas) || (maxBytes >= 0 && totalBytes+txBytes > maxBytes) {
-			continue
+			return true
 		}
 		totalBytes += txBytes
 		totalGas += w.gasWanted
 		keep = append(keep, w.tx)
-	}
+		return true
+	})
 	return keep
 }
 
 // ReapMaxTxs returns up to max transactions from the mempool. The results are
 // ordered by nonincreasing priority with ties broken by increasing order of
-// arrival. Reaping transactions does not remove them from the mempool.
+// arrival then by key. Reaping transactions does not remove them from the
+// mempool.
 //
 // If max < 0, all transactions in the mempool are reaped.
 //
@@ -456,12 +575,19 @@ func (txmp *TxPool) ReapMaxBytesMaxGas(maxBytes, maxGas int64) types.Txs {
 func (txmp *TxPool) ReapMaxTxs(max int) types.Txs {
 	var keep []types.Tx //nolint:prealloc
 
-	for _, w := range txmp.allEntriesSorted() {
+	txmp.recheckMtx.Lock()
+	defer txmp.recheckMtx.Unlock()
+
+	txmp.store.iterateOrderedTxs(func(w *wrappedTx) bool {
 		if max >= 0 && len(keep) >= max {
-			break
+			return false
+		}
+		if _, ok := txmp.pendingRechecks[w.key]; ok {
+			return true
 		}
 		keep = append(keep, w.tx)
-	}
+		return true
+	})
 	return keep
 }
 
@@ -471,8 +597,9 @@ func (txmp *TxPool) ReapMaxTxs(max int) types.Txs {
 // same offset.
 //
 // If the configuration enables recheck, Update sends each remaining
-// transaction after removing blockTxs to the ABCI CheckTx method.  Any
-// transactions marked as invalid during recheck are also removed.
+// transaction after removing blockTxs to the ABCI CheckTx method, in the
+// background.  Any transactions marked as invalid during recheck are also
+// removed, and the transactions not rechecked yet aren't reaped.
 //
 // The caller must hold an exclusive mempool lock (by calling txmp.Lock) before
 // calling Update.
@@ -500,16 +627,20 @@ func (txmp *TxPool) Update(
 	if newPostFn != nil {
 		txmp.postCheckFn = newPostFn
 	}
-	txmp.lastPurgeTime = time.Now()
+	txmp.lastPurgeTime = txmp.now()
 	txmp.updateMtx.Unlock()
 
 	txmp.metrics.SuccessfulTxs.Add(float64(len(blockTxs)))
-	for _, tx := range blockTxs {
+	for i, tx := range blockTxs {
 		// Regardless of success, remove the transaction from the mempool.
-		txmp.removeTxByKey(tx.Key())
+		key := tx.Key()
+		txmp.removeTxByKey(key)
+		txmp.setTxStatus(key, mempool.TxStatusCommitted, deliverTxResponses[i].Code, deliverTxResponses[i].Log)
 	}
+	txmp.txStatuses.prune(blockHeight - txStatusHeights)
 
 	txmp.purgeExpiredTxs(blockHeight)
+	txmp.markToBeRebroadcast(blockHeight)
 
 	// If there any uncommitted transactions left in the mempool, we either
 	// initiate re-CheckTx per remaining transaction or notify that remaining
@@ -519,7 +650,7 @@ func (txmp *TxPool) Update(
 	txmp.metrics.SizeBytes.Set(float64(txmp.SizeBytes()))
 	if size > 0 {
 		if txmp.config.Recheck {
-			txmp.recheckTransactions()
+			txmp.startRecheck(blockHeight)
 		} else {
 			txmp.notifyTxsAvailable()
 		}
@@ -542,56 +673,29 @@ func (txmp *TxPool) Update(
 // Finally, the new transaction is added and size stats updated.
 func (txmp *TxPool) addNewTransaction(wtx *wrappedTx, checkTxRes *abci.ResponseCheckTx) error {
 	// At this point the application has ruled the transaction valid, but the
-	// mempool might be full. If so, find the lowest-priority items with lower
-	// priority than the application assigned to this new one, and evict as many
-	// of them as necessary to make room for tx. If no such items exist, we
-	// discard tx.
-	if !txmp.canAddTx(wtx.size()) {
-		victims, victimBytes := txmp.store.getTxsBelowPriority(wtx.priority)
-
-		// If there are no suitable eviction candidates, or the total size of
-		// those candidates is not enough to make room for the new transaction,
-		// drop the new one.
-		if len(victims) == 0 || victimBytes < wtx.size() {
-			txmp.metrics.EvictedTxs.Add(1)
-			txmp.evictedTxCache.Push(wtx.key)
-			checkTxRes.MempoolError = fmt.Sprintf("rejected valid incoming transaction; mempool is full (%X)",
-				wtx.key)
-			return fmt.Errorf("rejected valid incoming transaction; mempool is full (%X). Size: (%d:%d)",
-				wtx.key.String(), txmp.Size(), txmp.SizeBytes())
-		}
-
-		txmp.logger.Debug("evicting lower-priority transactions",
+	// mempool might be full. If so, the store evicts as many transactions
+	// with a lower priority than the application assigned to this new one as
+	// necessary to make room for it. If that isn't enough, we discard tx.
+	evicted, added := txmp.store.setWithEviction(wtx, txmp.config.Size, txmp.config.MaxTxsBytes)
+	if !added {
+		txmp.metrics.EvictedTxs.Add(1)
+		txmp.evictedTxCache.Push(wtx.key)
+		txmp.setTxStatus(wtx.key, mempool.TxStatusEvicted, 0, "mempool is full")
+		checkTxRes.MempoolError = fmt.Sprintf("rejected valid incoming transaction; mempool is full (%X)",
+			wtx.key)
+		return fmt.Errorf("rejected valid incoming transaction; mempool is full (%X). Size: (%d:%d)",
+			wtx.key.String(), txmp.Size(), txmp.SizeBytes())
+	}
+	if len(evicted) > 0 {
+		txmp.logger.Debug("evicted lower-priority transactions",
 			"new_tx", wtx.key.String(),
 			"new_priority", wtx.priority,
+			"evicted", len(evicted),
 		)
-
-		// Sort lowest priority items first so they will be evicted first.  Break
-		// ties in favor of newer items (to maintain FIFO semantics in a group).
-		sort.Slice(victims, func(i, j int) bool {
-			iw := victims[i]
-			jw := victims[j]
-			if iw.priority == jw.priority {
-				return iw.timestamp.After(jw.timestamp)
-			}
-			return iw.priority < jw.priority
-		})
-
-		// Evict as many of the victims as necessary to make room.
-		availableBytes := txmp.availableBytes()
-		for _, tx := range victims {
-			txmp.evictTx(tx)
-
-			// We may not need to evict all the eligible transactions.  Bail out
-			// early if we have made enough room.
-			availableBytes += tx.size()
-			if availableBytes >= wtx.size() {
-				break
-			}
-		}
 	}
-
-	txmp.store.set(wtx)
+	for _, tx := range evicted {
+		txmp.evictTx(tx)
+	}
 
 	txmp.metrics.TxSizeBytes.Observe(float64(wtx.size()))
 	txmp.metrics.Size.Set(float64(txmp.Size()))
@@ -607,10 +711,14 @@ func (txmp *TxPool) addNewTransaction(wtx *wrappedTx, checkTxRes *abci.ResponseC
 	return nil
 }
 
+// evictTx records the eviction of wtx, which was removed from the store to make
+// room for a higher priority transaction.
 func (txmp *TxPool) evictTx(wtx *wrappedTx) {
-	txmp.store.remove(wtx.key)
 	txmp.evictedTxCache.Push(wtx.key)
+	txmp.setTxStatus(wtx.key, mempool.TxStatusEvicted, 0, "evicted by a higher priority transaction")
+	txmp.seenByPeersSet.RemoveKey(wtx.key)
 	txmp.metrics.EvictedTxs.Add(1)
+	schema.WriteMempoolEvictedTx(txmp.traceClient, wtx.key[:], wtx.size(), wtx.priority)
 	txmp.logger.Debug(
 		"evicted valid existing transaction; mempool full",
 		"old_tx", fmt.Sprintf("%X", wtx.key),
@@ -644,6 +752,11 @@ func (txmp *TxPool) handleRecheckResult(wtx *wrappedTx, checkTxRes *abci.Respons
 		"code", checkTxRes.Code,
 	)
 	txmp.store.remove(wtx.key)
+	if err != nil {
+		txmp.setTxStatus(wtx.key, mempool.TxStatusRejected, checkTxRes.Code, err.Error())
+	} else {
+		txmp.setTxStatus(wtx.key, mempool.TxStatusRejected, checkTxRes.Code, checkTxRes.Log)
+	}
 	if txmp.config.KeepInvalidTxsInCache {
 		txmp.rejectedTxCache.Push(wtx.key)
 	}
@@ -652,20 +765,19 @@ func (txmp *TxPool) handleRecheckResult(wtx *wrappedTx, checkTxRes *abci.Respons
 	txmp.metrics.SizeBytes.Set(float64(txmp.SizeBytes()))
 }
 
-// recheckTransactions initiates re-CheckTx ABCI calls for all the transactions
-// currently in the mempool. It reports the number of recheck calls that were
-// successfully initiated.
+// startRecheck starts the recheck at blockHeight of all the transactions
+// currently in the mempool, which are not reaped until their recheck
+// completes. It stops the recheck in progress, if any.
 //
 // Precondition: The mempool is not empty.
-// The caller must hold txmp.mtx exclusively.
-func (txmp *TxPool) recheckTransactions() {
+func (txmp *TxPool) startRecheck(blockHeight int64) {
 	if txmp.Size() == 0 {
 		panic("mempool: cannot run recheck on an empty mempool")
 	}
 	txmp.logger.Debug(
 		"executing re-CheckTx for all remaining transactions",
 		"num_txs", txmp.Size(),
-		"height", txmp.Height(),
+		"height", blockHeight,
 	)
 
 	// Collect transactions currently in the mempool requiring recheck.
@@ -674,45 +786,96 @@ func (txmp *TxPool) recheckTransactions() {
 	// cause transactions to needlessly be kicked out in RecheckTx
 	wtxs := txmp.store.getAllTxs()
 
-	// Issue CheckTx calls for each remaining transaction, and when all the
-	// rechecks are complete signal watchers that transactions may be available.
+	txmp.recheckMtx.Lock()
+	txmp.recheckHeight = blockHeight
+	txmp.pendingRechecks = make(map[types.TxKey]struct{}, len(wtxs))
+	for _, wtx := range wtxs {
+		txmp.pendingRechecks[wtx.key] = struct{}{}
+	}
+	done := make(chan struct{})
+	txmp.recheckDone = done
+	txmp.recheckMtx.Unlock()
+
+	go func() {
+		defer close(done)
+		txmp.recheckTransactions(blockHeight, wtxs)
+	}()
+}
+
+// recheckTransactions issues re-CheckTx ABCI calls for the transactions wtxs
+// with the async ABCI client, and handles their results as they come. It stops
+// if a recheck at a later height than blockHeight starts.
+func (txmp *TxPool) recheckTransactions(blockHeight int64, wtxs []*wrappedTx) {
+	start := time.Now()
+	reqs := make([]*abcicli.ReqRes, 0, len(wtxs))
+	handlers := make([]func(), 0, len(wtxs))
 	for _, wtx := range wtxs {
+		if !txmp.isCurrentRecheck(blockHeight) {
+			break
+		}
 		wtx := wtx
-		// The response for this CheckTx is handled by the default recheckTxCallback.
-		rsp, err := txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{
+		reqRes := txmp.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
 			Tx:   wtx.tx,
 			Type: abci.CheckTxType_Recheck,
 		})
-		if err != nil {
-			txmp.logger.Error("failed to execute CheckTx during recheck",
-				"err", err, "key", fmt.Sprintf("%x", wtx.key))
-		} else {
-			txmp.handleRecheckResult(wtx, rsp)
+		// The result is handled once, by the callback as soon as the
+		// response comes, or below for the requests failing without one.
+		var once sync.Once
+		handle := func() {
+			once.Do(func() { txmp.handleRecheckResponse(blockHeight, wtx, reqRes) })
 		}
+		reqRes.SetCallback(func(*abci.Response) { handle() })
+		reqs = append(reqs, reqRes)
+		handlers = append(handlers, handle)
 	}
 	_ = txmp.proxyAppConn.FlushAsync()
 
+	for i, reqRes := range reqs {
+		reqRes.Wait()
+		handlers[i]()
+	}
+	if !txmp.isCurrentRecheck(blockHeight) {
+		return
+	}
+	txmp.metrics.RecheckDurationSeconds.Observe(time.Since(start).Seconds())
+
 	// When recheck is complete, trigger a notification for more transactions.
 	txmp.notifyTxsAvailable()
 }
 
-// availableBytes returns the number of bytes available in the mempool.
-func (txmp *TxPool) availableBytes() int64 {
-	return txmp.config.MaxTxsBytes - txmp.SizeBytes()
+// handleRecheckResponse handles the completed re-CheckTx call reqRes for the
+// transaction wtx, unless the recheck at blockHeight was superseded.
+func (txmp *TxPool) handleRecheckResponse(blockHeight int64, wtx *wrappedTx, reqRes *abcicli.ReqRes) {
+	if !txmp.isCurrentRecheck(blockHeight) {
+		return
+	}
+	if rsp := reqRes.Response.GetCheckTx(); rsp != nil {
+		txmp.handleRecheckResult(wtx, rsp)
+	} else {
+		txmp.logger.Error("failed to execute CheckTx during recheck",
+			"err", reqRes.Err(), "key", fmt.Sprintf("%x", wtx.key))
+	}
+	txmp.recheckMtx.Lock()
+	delete(txmp.pendingRechecks, wtx.key)
+	txmp.recheckMtx.Unlock()
 }
 
-// canAddTx returns an error if we cannot insert the provided *wrappedTx into
-// the mempool due to mempool configured constraints. Otherwise, nil is
-// returned and the transaction can be inserted into the mempool.
-func (txmp *TxPool) canAddTx(size int64) bool {
-	numTxs := txmp.Size()
-	txBytes := txmp.SizeBytes()
+// isCurrentRecheck returns true if the latest recheck is the one at
+// blockHeight.
+func (txmp *TxPool) isCurrentRecheck(blockHeight int64) bool {
+	txmp.recheckMtx.Lock()
+	defer txmp.recheckMtx.Unlock()
+	return txmp.recheckHeight == blockHeight
+}
 
-	if numTxs > txmp.config.Size || size+txBytes > txmp.config.MaxTxsBytes {
-		return false
+// waitForRecheck waits for the latest recheck to complete.
+func (txmp *TxPool) waitForRecheck() {
+	txmp.recheckMtx.Lock()
+	done := txmp.recheckDone
+	txmp.recheckMtx.Unlock()
+	if done != nil {
+		<-done
 	}
-
-	return true
 }
 
 // purgeExpiredTxs removes all transactions from the mempool that have exceeded
@@ -728,18 +891,14 @@ func (txmp *TxPool) purgeExpiredTxs(blockHeight int64) {
 		expirationHeight = 0
 	}
 
-	now := time.Now()
+	now := txmp.now()
 	expirationAge := now.Add(-txmp.config.TTLDuration)
 	if txmp.config.TTLDuration == 0 {
 		expirationAge = time.Time{}
 	}
 
-	purgedTxs, numExpired := txmp.store.purgeExpiredTxs(expirationHeight, expirationAge)
-	// Add the purged transactions to the evicted cache
-	for _, tx := range purgedTxs {
-		txmp.evictedTxCache.Push(tx.key)
-	}
-	txmp.metrics.ExpiredTxs.Add(float64(numExpired))
+	purgedTxs, _ := txmp.store.purgeExpiredTxs(expirationHeight, expirationAge)
+	txmp.expireTxs(purgedTxs, blockHeight)
 
 	// purge old evicted and seen transactions
 	if txmp.config.TTLDuration == 0 {
@@ -749,6 +908,24 @@ func (txmp *TxPool) purgeExpiredTxs(blockHeight int64) {
 	txmp.seenByPeersSet.Prune(expirationAge)
 }
 
+// expireTxs records the expiry of the txs purged from the store at the given
+// height: they are added to the evicted cache, forgotten by the seen set, and
+// an event is published for each of them.
+func (txmp *TxPool) expireTxs(purgedTxs []*wrappedTx, height int64) {
+	for _, tx := range purgedTxs {
+		txmp.evictedTxCache.Push(tx.key)
+		txmp.txStatuses.set(tx.key, mempool.TxStatus{Status: mempool.TxStatusEvicted, Height: height, Log: "expired"})
+		txmp.seenByPeersSet.RemoveKey(tx.key)
+		if err := txmp.eventBus.PublishEventTxExpired(types.EventDataTxExpired{
+			Hash:   tx.key[:],
+			Height: height,
+		}); err != nil {
+			txmp.logger.Error("failed to publish tx expired event", "tx", tx.key.String(), "err", err)
+		}
+	}
+	txmp.metrics.ExpiredTxs.Add(float64(len(purgedTxs)))
+}
+
 func (txmp *TxPool) notifyTxsAvailable() {
 	if txmp.Size() == 0 {
 		return // nothing to do
@@ -3,11 +3,13 @@ package cat
 import (
 	"fmt"
 	"math/rand"
+	"sort"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
 )
 
 func BenchmarkTxPool_CheckTx(b *testing.B) {
@@ -30,3 +32,40 @@ func BenchmarkTxPool_CheckTx(b *testing.B) {
 		require.NoError(b, txmp.CheckTx(tx, nil, mempool.TxInfo{}))
 	}
 }
+
+// BenchmarkTxPool_Reap compares reaping by walking the ordered index of the
+// store with sorting all its txs on each reap, as it used to be done.
+func BenchmarkTxPool_Reap(b *testing.B) {
+	txmp := setup(b, 0)
+	txmp.config.Size = 10000
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for n := 0; n < txmp.config.Size; n++ {
+		priority := int64(rng.Intn(9999-1000) + 1000)
+		tx := []byte(fmt.Sprintf("sender%d=%X=%d", n, n, priority))
+		require.NoError(b, txmp.CheckTx(tx, nil, mempool.TxInfo{}))
+	}
+
+	b.Run("ordered", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			txmp.ReapMaxTxs(1000)
+		}
+	})
+
+	b.Run("sorted", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			txs := txmp.store.getAllTxs()
+			sort.Slice(txs, func(i, j int) bool {
+				if txs[i].priority == txs[j].priority {
+					return txs[i].timestamp.Before(txs[j].timestamp)
+				}
+				return txs[i].priority > txs[j].priority
+			})
+			keep := make([]types.Tx, 0, 1000)
+			for _, w := range txs[:1000] {
+				keep = append(keep, w.tx)
+			}
+		}
+	})
+}
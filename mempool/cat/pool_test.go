@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -85,8 +86,12 @@ func (app *application) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
 
 func setup(t testing.TB, cacheSize int, options ...TxPoolOption) *TxPool {
 	t.Helper()
+	return setupWithApp(t, &application{kvstore.NewApplication()}, cacheSize, options...)
+}
+
+func setupWithApp(t testing.TB, app abci.Application, cacheSize int, options ...TxPoolOption) *TxPool {
+	t.Helper()
 
-	app := &application{kvstore.NewApplication()}
 	cc := proxy.NewLocalClientCreator(app)
 
 	cfg := config.TestMempoolConfig()
@@ -260,19 +265,21 @@ func TestTxPool_Eviction(t *testing.T) {
 	require.False(t, txExists("key6=0005=1"))
 	require.True(t, txmp.WasRecentlyEvicted(types.Tx("key6=0005=1").Key()))
 
-	// A new transaction with higher priority should evict key5, which is the
-	// newest of the two transactions with lowest priority.
+	// A new transaction with higher priority should evict key4, which is the
+	// oldest of the two transactions with lowest priority.
+	txmp.seenByPeersSet.Add(types.Tx("key4=0003=3").Key(), 1)
 	mustCheckTx(t, txmp, "key7=0006=7")
 	require.True(t, txExists("key7=0006=7"))  // new transaction added
-	require.False(t, txExists("key5=0004=3")) // newest low-priority tx evicted
-	require.True(t, txmp.WasRecentlyEvicted(types.Tx("key5=0004=3").Key()))
-	require.True(t, txExists("key4=0003=3")) // older low-priority tx retained
+	require.False(t, txExists("key4=0003=3")) // oldest low-priority tx evicted
+	require.True(t, txmp.WasRecentlyEvicted(types.Tx("key4=0003=3").Key()))
+	require.False(t, txmp.seenByPeersSet.Has(types.Tx("key4=0003=3").Key(), 1))
+	require.True(t, txExists("key5=0004=3")) // newer low-priority tx retained
 
 	// Another new transaction evicts the other low-priority element.
 	mustCheckTx(t, txmp, "key8=0007=20")
 	require.True(t, txExists("key8=0007=20"))
-	require.False(t, txExists("key4=0003=3"))
-	require.True(t, txmp.WasRecentlyEvicted(types.Tx("key4=0003=3").Key()))
+	require.False(t, txExists("key5=0004=3"))
+	require.True(t, txmp.WasRecentlyEvicted(types.Tx("key5=0004=3").Key()))
 
 	// Now the lowest-priority tx is 5, so that should be the next to go.
 	mustCheckTx(t, txmp, "key9=0008=9")
@@ -446,6 +453,124 @@ func TestTxPool_ReapMaxTxs(t *testing.T) {
 	require.Len(t, reapedTxs, len(txs)/2)
 }
 
+func TestTxPool_ReapOrder(t *testing.T) {
+	txmp := setup(t, 0)
+	now := time.Now()
+	txmp.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+
+	// few priorities, so that txs of the same priority are ordered by arrival
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	txs := make([]testTx, 200)
+	for i := range txs {
+		priority := int64(rng.Intn(5))
+		txs[i] = testTx{tx: newTx(i, 0, []byte{byte(i)}, priority), priority: priority}
+		require.NoError(t, txmp.CheckTx(txs[i].tx, nil, mempool.TxInfo{}))
+	}
+
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].priority > txs[j].priority })
+	expected := make(types.Txs, len(txs))
+	for i, tx := range txs {
+		expected[i] = tx.tx
+	}
+	require.Equal(t, expected, txmp.ReapMaxBytesMaxGas(-1, -1))
+	require.Equal(t, expected, txmp.ReapMaxTxs(-1))
+	require.Equal(t, expected[:10], txmp.ReapMaxTxs(10))
+	require.Equal(t, expected[:10], txmp.ReapMaxBytesMaxGas(-1, 10))
+
+	// the order holds as txs are removed
+	for _, tx := range expected[:50] {
+		require.NoError(t, txmp.RemoveTxByKey(tx.Key()))
+	}
+	require.Equal(t, expected[50:], txmp.ReapMaxTxs(-1))
+}
+
+// recheckApp holds the rechecks back until they are released, reports the
+// txs rechecked and rejects the ones in invalid.
+type recheckApp struct {
+	*application
+	release   chan struct{}
+	rechecked chan string
+	invalid   map[string]bool
+}
+
+func (app *recheckApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	if req.Type == abci.CheckTxType_Recheck {
+		<-app.release
+		defer func() { app.rechecked <- string(req.Tx) }()
+		if app.invalid[string(req.Tx)] {
+			return abci.ResponseCheckTx{Code: 102}
+		}
+	}
+	return app.application.CheckTx(req)
+}
+
+// countingHistogram counts its observations.
+type countingHistogram struct {
+	mtx sync.Mutex
+	n   int
+}
+
+func (h *countingHistogram) With(...string) metrics.Histogram { return h }
+
+func (h *countingHistogram) Observe(float64) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.n++
+}
+
+func (h *countingHistogram) count() int {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return h.n
+}
+
+func TestTxPool_ReapDuringRecheck(t *testing.T) {
+	const committed, invalid, valid = "key1=0000=30", "key2=0001=20", "key3=0002=10"
+	app := &recheckApp{
+		application: &application{kvstore.NewApplication()},
+		release:     make(chan struct{}),
+		rechecked:   make(chan string, 2),
+		invalid:     map[string]bool{invalid: true},
+	}
+	durations := &countingHistogram{}
+	metrics := mempool.NopMetrics()
+	metrics.RecheckDurationSeconds = durations
+	txmp := setupWithApp(t, app, 100, WithMetrics(metrics))
+	mustCheckTx(t, txmp, committed)
+	mustCheckTx(t, txmp, invalid)
+	mustCheckTx(t, txmp, valid)
+
+	// the recheck of the txs left runs in the background, and none of them
+	// is reaped until its recheck completes
+	require.NoError(t, txmp.Update(2, types.Txs{types.Tx(committed)}, abciResponses(1, abci.CodeTypeOK), nil, nil))
+	require.Equal(t, 2, txmp.Size())
+	require.Empty(t, txmp.ReapMaxTxs(-1))
+	require.Empty(t, txmp.ReapMaxBytesMaxGas(-1, -1))
+
+	// the txs are reaped as their recheck completes, while the recheck of
+	// the other one is pending
+	app.release <- struct{}{}
+	if first := <-app.rechecked; first == valid {
+		require.Eventually(t, func() bool { return len(txmp.ReapMaxTxs(-1)) == 1 }, time.Second, time.Millisecond)
+		require.Equal(t, types.Txs{types.Tx(valid)}, txmp.ReapMaxBytesMaxGas(-1, -1))
+		require.Equal(t, 2, txmp.Size())
+	} else {
+		require.Eventually(t, func() bool { return txmp.Size() == 1 }, time.Second, time.Millisecond)
+		require.Empty(t, txmp.ReapMaxTxs(-1))
+	}
+	assert.Zero(t, durations.count())
+
+	app.release <- struct{}{}
+	<-app.rechecked
+	txmp.waitForRecheck()
+	require.Equal(t, types.Txs{types.Tx(valid)}, txmp.ReapMaxTxs(-1))
+	require.Equal(t, 1, txmp.Size())
+	assert.Equal(t, 1, durations.count())
+}
+
 func TestTxPool_CheckTxExceedsMaxSize(t *testing.T) {
 	txmp := setup(t, 0)
 
@@ -547,12 +672,14 @@ func generateResponses(numResponses int) (responses []*abci.ResponseDeliverTx) {
 func TestTxPool_ExpiredTxs_Timestamp(t *testing.T) {
 	txmp := setup(t, 5000)
 	txmp.config.TTLDuration = 5 * time.Millisecond
+	now := time.Now()
+	txmp.now = func() time.Time { return now }
 
 	added1 := checkTxs(t, txmp, 10, 0)
 	require.Equal(t, len(added1), txmp.Size())
 
-	// Wait a while, then add some more transactions that should not be expired
-	// when the first batch TTLs out.
+	// Move the clock on, then add some more transactions that should not be
+	// expired when the first batch TTLs out.
 	//
 	// ms: 0   1   2   3   4   5   6
 	//     ^           ^       ^   ^
@@ -560,14 +687,11 @@ func TestTxPool_ExpiredTxs_Timestamp(t *testing.T) {
 	//     |           |       +------ first batch expires
 	//     |           +-------------- second batch added
 	//     +-------------------------- first batch added
-	//
-	// The exact intervals are not important except that the delta should be
-	// large relative to the cost of CheckTx (ms vs. ns is fine here).
-	time.Sleep(3 * time.Millisecond)
+	now = now.Add(3 * time.Millisecond)
 	added2 := checkTxs(t, txmp, 10, 1)
 
-	// Wait a while longer, so that the first batch will expire.
-	time.Sleep(3 * time.Millisecond)
+	// Move the clock on again, so that the first batch will expire.
+	now = now.Add(3 * time.Millisecond)
 
 	// Trigger an update so that pruning will occur.
 	txmp.Lock()
@@ -642,6 +766,61 @@ func TestTxPool_ExpiredTxs_NumBlocks(t *testing.T) {
 	require.GreaterOrEqual(t, txmp.Size(), 45)
 }
 
+// TestTxPool_ExpiredTxsEvents checks that the txs expired by either TTL are
+// forgotten by the seen set and published on the event bus.
+func TestTxPool_ExpiredTxsEvents(t *testing.T) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() { require.NoError(t, eventBus.Stop()) })
+	sub, err := eventBus.Subscribe(context.Background(), "test", types.EventQueryTxExpired, 10)
+	require.NoError(t, err)
+	expired := func() types.EventDataTxExpired {
+		select {
+		case msg := <-sub.Out():
+			return msg.Data().(types.EventDataTxExpired)
+		case <-time.After(time.Second):
+			t.Fatal("no expired tx event")
+			return types.EventDataTxExpired{}
+		}
+	}
+
+	txmp := setup(t, 100, WithEventBus(eventBus))
+	txmp.config.TTLNumBlocks = 2
+	txmp.config.TTLDuration = time.Minute
+	now := time.Now()
+	txmp.now = func() time.Time { return now }
+	update := func(height int64) {
+		txmp.Lock()
+		defer txmp.Unlock()
+		require.NoError(t, txmp.Update(height, nil, nil, nil, nil))
+	}
+
+	// txA is checked at height 1, txB at height 2, 30 seconds later.
+	txA := types.Tx("a=1=1")
+	mustCheckTx(t, txmp, string(txA))
+	txmp.seenByPeersSet.Add(txA.Key(), 1)
+	now = now.Add(30 * time.Second)
+	update(2)
+	txB := types.Tx("b=1=1")
+	mustCheckTx(t, txmp, string(txB))
+	txmp.seenByPeersSet.Add(txB.Key(), 1)
+
+	// txA expires by time, while neither is expired by height yet.
+	now = now.Add(31 * time.Second)
+	update(3)
+	require.Equal(t, types.EventDataTxExpired{Hash: txA.Hash(), Height: 3}, expired())
+	require.False(t, txmp.Has(txA.Key()))
+	require.False(t, txmp.seenByPeersSet.Has(txA.Key(), 1))
+	require.True(t, txmp.WasRecentlyEvicted(txA.Key()))
+	require.True(t, txmp.Has(txB.Key()))
+
+	// txB expires by height, without the clock moving on.
+	update(5)
+	require.Equal(t, types.EventDataTxExpired{Hash: txB.Hash(), Height: 5}, expired())
+	require.False(t, txmp.Has(txB.Key()))
+	require.False(t, txmp.seenByPeersSet.Has(txB.Key(), 1))
+}
+
 func TestTxPool_CheckTxPostCheckError(t *testing.T) {
 	cases := []struct {
 		name string
@@ -720,6 +899,44 @@ func abciResponses(n int, code uint32) []*abci.ResponseDeliverTx {
 	return responses
 }
 
+func TestTxPool_TxStatus(t *testing.T) {
+	txmp := setup(t, 100)
+	txmp.config.Size = 1
+	status := func(spec string) mempool.TxStatus {
+		return txmp.TxStatus(types.Tx(spec).Key())
+	}
+
+	require.Equal(t, mempool.TxStatus{Status: mempool.TxStatusUnknown}, status("unknown=0000=1"))
+
+	mustCheckTx(t, txmp, "low=0000=1")
+	require.Equal(t, mempool.TxStatus{Status: mempool.TxStatusPending, Height: 1}, status("low=0000=1"))
+
+	// the application rejects txs which aren't of the form sender=key=value
+	require.Error(t, txmp.CheckTx(types.Tx("invalid"), nil, mempool.TxInfo{}))
+	rejected := status("invalid")
+	require.Equal(t, mempool.TxStatusRejected, rejected.Status)
+	require.EqualValues(t, 101, rejected.Code)
+
+	// the mempool is full, so the low priority tx is evicted
+	mustCheckTx(t, txmp, "high=0000=10")
+	evicted := status("low=0000=1")
+	require.Equal(t, mempool.TxStatusEvicted, evicted.Status)
+	require.EqualValues(t, 1, evicted.Height)
+	require.NotEmpty(t, evicted.Log)
+
+	responses := []*abci.ResponseDeliverTx{{Code: 7, Log: "failed"}}
+	require.NoError(t, txmp.Update(2, types.Txs{types.Tx("high=0000=10")}, responses, nil, nil))
+	require.Equal(t, mempool.TxStatus{Status: mempool.TxStatusCommitted, Height: 2, Code: 7, Log: "failed"}, status("high=0000=10"))
+
+	// the statuses are forgotten after txStatusHeights heights
+	require.NoError(t, txmp.Update(2+txStatusHeights, nil, nil, nil, nil))
+	require.Equal(t, mempool.TxStatusCommitted, status("high=0000=10").Status)
+	require.NoError(t, txmp.Update(3+txStatusHeights, nil, nil, nil, nil))
+	for _, spec := range []string{"invalid", "low=0000=1", "high=0000=10"} {
+		require.Equal(t, mempool.TxStatus{Status: mempool.TxStatusUnknown}, status(spec), spec)
+	}
+}
+
 func TestTxPool_ConcurrentlyAddingTx(t *testing.T) {
 	cacheSize := 500
 	txPool := setup(t, cacheSize)
@@ -732,7 +949,7 @@ func TestTxPool_ConcurrentlyAddingTx(t *testing.T) {
 		wg.Add(1)
 		go func(sender uint16) {
 			defer wg.Done()
-			_, err := txPool.TryAddNewTx(tx, tx.Key(), mempool.TxInfo{SenderID: sender})
+			_, err := txPool.TryAddNewTx(context.Background(), tx, tx.Key(), mempool.TxInfo{SenderID: sender})
 			errCh <- err
 		}(uint16(i + 1))
 	}
@@ -0,0 +1,92 @@
+package cat
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultStateMsgRate is the default number of SeenTx and WantTx messages
+	// accepted per second from a single peer.
+	DefaultStateMsgRate = 1000
+
+	// DefaultStateMsgBurst is the default number of SeenTx and WantTx
+	// messages accepted at once from a single peer.
+	DefaultStateMsgBurst = 5000
+
+	// DefaultMaxDroppedStateMsgs is the default number of SeenTx and WantTx
+	// messages of a single peer dropped for exceeding its rate, after which
+	// the peer is disconnected.
+	DefaultMaxDroppedStateMsgs = 1000
+)
+
+// peerRateLimiter limits the rate of the messages received from each peer,
+// with a token bucket per peer refilled at rate tokens per second up to burst
+// tokens. Each message takes a token, and is dropped if none is left. The
+// dropped messages of a peer are counted until its bucket is full again, i.e.
+// until it stays under its rate long enough.
+type peerRateLimiter struct {
+	mtx sync.Mutex
+
+	rate     float64
+	burst    float64
+	maxDrops int
+
+	buckets map[uint16]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	drops  int
+}
+
+func newPeerRateLimiter(rate, burst, maxDrops int) *peerRateLimiter {
+	return &peerRateLimiter{
+		rate:     float64(rate),
+		burst:    float64(burst),
+		maxDrops: maxDrops,
+		buckets:  make(map[uint16]*tokenBucket),
+		now:      time.Now,
+	}
+}
+
+// allow takes a token from the bucket of peer for a message. It returns false
+// if there was none left and the message must be dropped. It reports the peer
+// as abusive once, when the number of its dropped messages reaches maxDrops.
+// A rate of 0 allows every message.
+func (l *peerRateLimiter) allow(peer uint16) (allowed, abusive bool) {
+	if l.rate == 0 {
+		return true, false
+	}
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[peer]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[peer] = b
+	}
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens >= l.burst {
+		b.drops = 0
+	}
+
+	if b.tokens < 1 {
+		b.drops++
+		return false, b.drops == l.maxDrops
+	}
+	b.tokens--
+	return true, false
+}
+
+// remove forgets the bucket of peer.
+func (l *peerRateLimiter) remove(peer uint16) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	delete(l.buckets, peer)
+}
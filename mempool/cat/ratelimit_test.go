@@ -0,0 +1,64 @@
+package cat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerRateLimiter(t *testing.T) {
+	l := newPeerRateLimiter(10, 5, 3)
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	allow := func(peer uint16, n int) (allowed, abusive int) {
+		for i := 0; i < n; i++ {
+			ok, bad := l.allow(peer)
+			if ok {
+				allowed++
+			}
+			if bad {
+				abusive++
+			}
+		}
+		return allowed, abusive
+	}
+
+	// the burst is accepted, then the peer is reported once on its 3rd drop
+	allowed, abusive := allow(1, 10)
+	assert.Equal(t, 5, allowed)
+	assert.Equal(t, 1, abusive)
+
+	// the buckets are per peer
+	allowed, _ = allow(2, 5)
+	assert.Equal(t, 5, allowed)
+
+	// the bucket refills at the rate
+	now = now.Add(200 * time.Millisecond)
+	allowed, abusive = allow(1, 3)
+	assert.Equal(t, 2, allowed)
+	assert.Zero(t, abusive)
+
+	// the drops are forgotten once the bucket is full again
+	now = now.Add(time.Second)
+	allowed, abusive = allow(1, 7)
+	assert.Equal(t, 5, allowed)
+	assert.Zero(t, abusive)
+	_, abusive = allow(1, 1)
+	assert.Equal(t, 1, abusive)
+
+	l.remove(1)
+	ok, _ := l.allow(1)
+	require.True(t, ok)
+}
+
+func TestPeerRateLimiterDisabled(t *testing.T) {
+	l := newPeerRateLimiter(0, 0, 3)
+	for i := 0; i < 100; i++ {
+		allowed, abusive := l.allow(1)
+		require.True(t, allowed)
+		require.False(t, abusive)
+	}
+}
@@ -1,10 +1,16 @@
 package cat
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"math"
 	"math/rand"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/gogo/protobuf/proto"
 
 	cfg "github.com/tendermint/tendermint/config"
@@ -27,9 +33,25 @@ const (
 	// for cross compatibility
 	MempoolStateChannel = byte(0x31)
 
+	// MempoolWantsChannel carries the WantTxs messages, batching the WantTx
+	// requests to a peer. A node advertising it understands WantTxs, so the
+	// requests to the peers which don't are sent one WantTx at a time.
+	MempoolWantsChannel = byte(0x32)
+
+	// MempoolChunkChannel carries the TxChunk messages, in which the txs
+	// larger than MaxTxMsgSize are sent to the peers requesting them. Only
+	// the peers advertising it are sent chunks.
+	MempoolChunkChannel = byte(0x33)
+
 	// peerHeightDiff signifies the tolerance in difference in height between the peer and the height
 	// the node received the tx
 	peerHeightDiff = 10
+
+	// rebroadcastInterval is how often the reactor broadcasts again up to
+	// maxRebroadcastsPerInterval of the pending transactions queued by the
+	// mempool, so that a large mempool doesn't produce a burst of messages.
+	rebroadcastInterval        = 100 * time.Millisecond
+	maxRebroadcastsPerInterval = 100
 )
 
 // Reactor handles mempool tx broadcasting logic amongst peers. For the main
@@ -41,7 +63,18 @@ type Reactor struct {
 	mempool     *TxPool
 	ids         *mempoolIDs
 	requests    *requestScheduler
+	inFlight    *inFlightRequests
+	latencies   *peerLatencies
+	stateMsgs   *peerRateLimiter
+	wants       *wantBatcher
+	chunks      *txAssembler
+	peerLabels  *peerLabels
 	traceClient trace.Tracer
+
+	// ctx is cancelled on stop, cancelling the CheckTx calls of the txs
+	// received from peers.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 type ReactorOptions struct {
@@ -52,10 +85,33 @@ type ReactorOptions struct {
 	// MaxTxSize is the maximum size of a transaction that can be received
 	MaxTxSize int
 
+	// MaxTxMsgSize is the size over which the transactions requested by a
+	// peer are sent to it in chunks of up to MaxTxMsgSize bytes, if it
+	// supports them.
+	MaxTxMsgSize int
+
 	// MaxGossipDelay is the maximum allotted time that the reactor expects a transaction to
 	// arrive before issuing a new request to a different peer
 	MaxGossipDelay time.Duration
 
+	// MaxInFlightWantsPerPeer is the maximum number of WantTx requests
+	// outstanding to a single peer. The txs which can't be requested from a
+	// peer because of it are requested from another peer which has seen them,
+	// or once a request completes.
+	MaxInFlightWantsPerPeer int
+
+	// StateMsgRate is the number of SeenTx and WantTx messages accepted per
+	// second from a single peer, and StateMsgBurst the number accepted at
+	// once. The messages over the limit are dropped. A StateMsgRate of 0
+	// disables the limit.
+	StateMsgRate  int
+	StateMsgBurst int
+
+	// MaxDroppedStateMsgs is the number of SeenTx and WantTx messages of a
+	// single peer dropped for exceeding its rate, after which the peer is
+	// disconnected.
+	MaxDroppedStateMsgs int
+
 	// TraceClient is the trace client for collecting trace level events
 	TraceClient trace.Tracer
 }
@@ -65,18 +121,54 @@ func (opts *ReactorOptions) VerifyAndComplete() error {
 		opts.MaxTxSize = cfg.DefaultMempoolConfig().MaxTxBytes
 	}
 
+	if opts.MaxTxMsgSize == 0 {
+		opts.MaxTxMsgSize = DefaultMaxTxMsgSize
+	}
+
 	if opts.MaxGossipDelay == 0 {
 		opts.MaxGossipDelay = DefaultGossipDelay
 	}
 
+	if opts.MaxInFlightWantsPerPeer == 0 {
+		opts.MaxInFlightWantsPerPeer = DefaultMaxInFlightWantsPerPeer
+	}
+
+	if opts.StateMsgBurst == 0 {
+		opts.StateMsgBurst = DefaultStateMsgBurst
+	}
+
+	if opts.MaxDroppedStateMsgs == 0 {
+		opts.MaxDroppedStateMsgs = DefaultMaxDroppedStateMsgs
+	}
+
 	if opts.MaxTxSize < 0 {
 		return fmt.Errorf("max tx size (%d) cannot be negative", opts.MaxTxSize)
 	}
 
+	if opts.MaxTxMsgSize < 0 {
+		return fmt.Errorf("max tx msg size (%d) cannot be negative", opts.MaxTxMsgSize)
+	}
+
 	if opts.MaxGossipDelay < 0 {
 		return fmt.Errorf("max gossip delay (%d) cannot be negative", opts.MaxGossipDelay)
 	}
 
+	if opts.MaxInFlightWantsPerPeer < 0 {
+		return fmt.Errorf("max in-flight wants per peer (%d) cannot be negative", opts.MaxInFlightWantsPerPeer)
+	}
+
+	if opts.StateMsgRate < 0 {
+		return fmt.Errorf("state msg rate (%d) cannot be negative", opts.StateMsgRate)
+	}
+
+	if opts.StateMsgBurst < 0 {
+		return fmt.Errorf("state msg burst (%d) cannot be negative", opts.StateMsgBurst)
+	}
+
+	if opts.MaxDroppedStateMsgs < 0 {
+		return fmt.Errorf("max dropped state msgs (%d) cannot be negative", opts.MaxDroppedStateMsgs)
+	}
+
 	return nil
 }
 
@@ -91,9 +183,16 @@ func NewReactor(mempool *TxPool, opts *ReactorOptions) (*Reactor, error) {
 		mempool:     mempool,
 		ids:         newMempoolIDs(),
 		requests:    newRequestScheduler(opts.MaxGossipDelay, defaultGlobalRequestTimeout),
+		inFlight:    newInFlightRequests(opts.MaxInFlightWantsPerPeer),
+		latencies:   newPeerLatencies(opts.MaxGossipDelay/2, latencyHalfLife),
+		stateMsgs:   newPeerRateLimiter(opts.StateMsgRate, opts.StateMsgBurst, opts.MaxDroppedStateMsgs),
+		peerLabels:  newPeerLabels(maxPeerLabels),
 		traceClient: trace.NoOpTracer(),
 	}
+	memR.wants = newWantBatcher(wantBatchWindow, maxWantTxsPerMsg, memR.sendWantTxs)
+	memR.chunks = newTxAssembler(maxPartialTxs, opts.MaxTxSize, txChunkTimeout)
 	memR.BaseReactor = *p2p.NewBaseReactor("Mempool", memR)
+	memR.ctx, memR.cancel = context.WithCancel(context.Background())
 	return memR, nil
 }
 
@@ -118,6 +217,18 @@ func (memR *Reactor) OnStart() error {
 				}
 			}
 		}()
+		go func() {
+			ticker := time.NewTicker(rebroadcastInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					memR.rebroadcastTxs()
+				case <-memR.Quit():
+					return
+				}
+			}
+		}()
 	} else {
 		memR.Logger.Info("Tx broadcasting is disabled")
 	}
@@ -143,6 +254,7 @@ func (memR *Reactor) OnStart() error {
 func (memR *Reactor) OnStop() {
 	// stop all the timers tracking outbound requests
 	memR.requests.Close()
+	memR.cancel()
 }
 
 // GetChannels implements Reactor by returning the list of channels for this
@@ -163,6 +275,26 @@ func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 		},
 	}
 
+	chunkMsg := protomem.Message{
+		Sum: &protomem.Message_TxChunk{
+			TxChunk: &protomem.TxChunk{
+				TxKey: make([]byte, tmhash.Size),
+				Index: math.MaxUint32,
+				Total: math.MaxUint32,
+				Data:  make([]byte, memR.opts.MaxTxMsgSize),
+			},
+		},
+	}
+
+	wantsMsg := protomem.Message{
+		Sum: &protomem.Message_WantTxs{
+			WantTxs: &protomem.WantTxs{TxKeys: make([][]byte, maxWantTxsPerMsg)},
+		},
+	}
+	for i := range wantsMsg.GetWantTxs().TxKeys {
+		wantsMsg.GetWantTxs().TxKeys[i] = make([]byte, tmhash.Size)
+	}
+
 	return []*p2p.ChannelDescriptor{
 		{
 			ID:                  mempool.MempoolChannel,
@@ -176,6 +308,18 @@ func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 			RecvMessageCapacity: stateMsg.Size(),
 			MessageType:         &protomem.Message{},
 		},
+		{
+			ID:                  MempoolWantsChannel,
+			Priority:            5,
+			RecvMessageCapacity: wantsMsg.Size(),
+			MessageType:         &protomem.Message{},
+		},
+		{
+			ID:                  MempoolChunkChannel,
+			Priority:            6,
+			RecvMessageCapacity: chunkMsg.Size(),
+			MessageType:         &protomem.Message{},
+		},
 	}
 }
 
@@ -195,6 +339,15 @@ func (memR *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
 	// remove and rerequest all pending outbound requests to that peer since we know
 	// we won't receive any responses from them.
 	outboundRequests := memR.requests.ClearAllRequestsFrom(peerID)
+	memR.inFlight.releaseAll(peerID)
+	// the queued txs which no connected peer has seen can't be requested
+	memR.inFlight.prune(func(key types.TxKey) bool {
+		return len(memR.mempool.seenByPeersSet.Get(key)) == 0
+	})
+	memR.latencies.remove(peerID)
+	memR.stateMsgs.remove(peerID)
+	memR.wants.remove(peerID)
+	memR.chunks.removePeer(peerID)
 	for key := range outboundRequests {
 		memR.mempool.metrics.RequestedTxs.Add(1)
 		memR.findNewPeerToRequestTx(key)
@@ -219,7 +372,7 @@ func (memR *Reactor) Receive(chID byte, peer p2p.Peer, msgBytes []byte) {
 }
 
 // ReceiveEnvelope implements Reactor.
-// It processes one of three messages: Txs, SeenTx, WantTx.
+// It processes one of five messages: Txs, TxChunk, SeenTx, WantTx, WantTxs.
 func (memR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 	switch msg := e.Message.(type) {
 
@@ -233,34 +386,43 @@ func (memR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 			memR.Logger.Error("received empty txs from peer", "src", e.Src)
 			return
 		}
-		peerID := memR.ids.GetIDForPeer(e.Src.ID())
-		txInfo := mempool.TxInfo{SenderID: peerID}
-		txInfo.SenderP2PID = e.Src.ID()
-
-		var err error
 		for _, tx := range protoTxs {
-			ntx := types.Tx(tx)
-			key := ntx.Key()
-			schema.WriteMempoolTx(memR.traceClient, string(e.Src.ID()), key[:], len(tx), schema.Download)
-			// If we requested the transaction we mark it as received.
-			if memR.requests.Has(peerID, key) {
-				memR.requests.MarkReceived(peerID, key)
-				memR.Logger.Debug("received a response for a requested transaction", "peerID", peerID, "txKey", key)
-			} else {
-				// If we didn't request the transaction we simply mark the peer as having the
-				// tx (we'd have already done it if we were requesting the tx).
-				memR.mempool.PeerHasTx(peerID, key)
-				memR.Logger.Debug("received new trasaction", "peerID", peerID, "txKey", key)
-			}
-			_, err = memR.mempool.TryAddNewTx(ntx, key, txInfo)
-			if err != nil && err != ErrTxInMempool {
-				memR.Logger.Info("Could not add tx", "txKey", key, "err", err)
+			if !memR.receiveTx(e.Src, tx) {
 				return
 			}
-			if !memR.opts.ListenOnly {
-				// We broadcast only transactions that we deem valid and actually have in our mempool.
-				memR.broadcastSeenTx(key)
-			}
+		}
+
+	// A peer has sent us a chunk of a transaction we requested, too large to
+	// be sent at once. Once all its chunks are received, the transaction is
+	// handled as if it was received in a Txs message.
+	case *protomem.TxChunk:
+		txKey, err := types.TxKeyFromBytes(msg.TxKey)
+		if err != nil {
+			memR.Logger.Error("peer sent TxChunk with incorrect tx key", "err", err)
+			memR.Switch.StopPeerForError(e.Src, err)
+			return
+		}
+		// only the chunks of the transactions requested from the peer are
+		// held, so that the peers can't fill the memory with chunks
+		peerID := memR.ids.GetIDForPeer(e.Src.ID())
+		if !memR.requests.Has(peerID, txKey) {
+			memR.Logger.Debug("received a chunk of a tx not requested from the peer", "txKey", txKey, "peer", e.Src.ID())
+			return
+		}
+		// the chunks are reassembled per peer, hence an invalid tx is
+		// made of the chunks sent by this peer only
+		tx, err := memR.chunks.add(peerID, txKey, msg.Index, msg.Total, msg.Data)
+		if err == errTooManyPartialTxs {
+			memR.Logger.Debug("dropping tx chunk", "txKey", txKey, "err", err)
+			return
+		}
+		if err != nil {
+			memR.Logger.Error("peer sent invalid TxChunk", "err", err)
+			memR.Switch.StopPeerForError(e.Src, err)
+			return
+		}
+		if tx != nil {
+			memR.receiveTx(e.Src, tx)
 		}
 
 	// A peer has indicated to us that it has a transaction. We first verify the txkey and
@@ -271,6 +433,9 @@ func (memR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 	// 3. If we recently evicted the tx and still don't have space for it, we do nothing.
 	// 4. Else, we request the transaction from that peer.
 	case *protomem.SeenTx:
+		if !memR.allowStateMsg(e.Src) {
+			return
+		}
 		txKey, err := types.TxKeyFromBytes(msg.TxKey)
 		if err != nil {
 			memR.Logger.Error("peer sent SeenTx with incorrect tx key", "err", err)
@@ -289,52 +454,50 @@ func (memR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 		// Check if we don't already have the transaction and that it was recently rejected
 		if memR.mempool.Has(txKey) || memR.mempool.IsRejectedTx(txKey) {
 			memR.Logger.Debug("received a seen tx for a tx we already have", "txKey", txKey)
+			memR.countStateMsg(memR.mempool.metrics.SeenTxReceived, e.Src, false)
 			return
 		}
 
 		// If we are already requesting that tx, then we don't need to go any further.
 		if memR.requests.ForTx(txKey) != 0 {
 			memR.Logger.Debug("received a SeenTx message for a transaction we are already requesting", "txKey", txKey)
+			memR.countStateMsg(memR.mempool.metrics.SeenTxReceived, e.Src, false)
 			return
 		}
 
 		// We don't have the transaction, nor are we requesting it so we send the node
 		// a want msg
+		memR.countStateMsg(memR.mempool.metrics.SeenTxReceived, e.Src, true)
 		memR.requestTx(txKey, e.Src)
 
 	// A peer is requesting a transaction that we have claimed to have. Find the specified
 	// transaction and broadcast it to the peer. We may no longer have the transaction
 	case *protomem.WantTx:
+		if !memR.allowStateMsg(e.Src) {
+			return
+		}
 		txKey, err := types.TxKeyFromBytes(msg.TxKey)
 		if err != nil {
 			memR.Logger.Error("peer sent WantTx with incorrect tx key", "err", err)
 			memR.Switch.StopPeerForError(e.Src, err)
 			return
 		}
-		schema.WriteMempoolPeerState(
-			memR.traceClient,
-			string(e.Src.ID()),
-			schema.WantTx,
-			txKey[:],
-			schema.Download,
-		)
-		tx, has := memR.mempool.GetTxByKey(txKey)
-		if has && !memR.opts.ListenOnly {
-			peerID := memR.ids.GetIDForPeer(e.Src.ID())
-			memR.Logger.Debug("sending a tx in response to a want msg", "peer", peerID)
-			if p2p.SendEnvelopeShim(e.Src, p2p.Envelope{ //nolint:staticcheck
-				ChannelID: mempool.MempoolChannel,
-				Message:   &protomem.Txs{Txs: [][]byte{tx}},
-			}, memR.Logger) {
-				memR.mempool.PeerHasTx(peerID, txKey)
-				schema.WriteMempoolTx(
-					memR.traceClient,
-					string(e.Src.ID()),
-					txKey[:],
-					len(tx),
-					schema.Upload,
-				)
+		memR.sendWantedTx(e.Src, txKey)
+
+	// A peer is requesting a batch of transactions that we have claimed to
+	// have. Each of them is handled as if it was requested on its own.
+	case *protomem.WantTxs:
+		for _, key := range msg.TxKeys {
+			if !memR.allowStateMsg(e.Src) {
+				return
 			}
+			txKey, err := types.TxKeyFromBytes(key)
+			if err != nil {
+				memR.Logger.Error("peer sent WantTxs with incorrect tx key", "err", err)
+				memR.Switch.StopPeerForError(e.Src, err)
+				return
+			}
+			memR.sendWantedTx(e.Src, txKey)
 		}
 
 	default:
@@ -344,6 +507,127 @@ func (memR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 	}
 }
 
+// receiveTx adds the transaction tx received from src to the mempool, and
+// broadcasts a SeenTx for it. It returns false if the transaction couldn't be
+// added.
+func (memR *Reactor) receiveTx(src p2p.Peer, tx types.Tx) bool {
+	peerID := memR.ids.GetIDForPeer(src.ID())
+	txInfo := mempool.TxInfo{SenderID: peerID}
+	txInfo.SenderP2PID = src.ID()
+
+	key := tx.Key()
+	schema.WriteMempoolTx(memR.traceClient, string(src.ID()), key[:], len(tx), schema.Download)
+	// If we requested the transaction we mark it as received.
+	if memR.requests.Has(peerID, key) {
+		memR.requests.MarkReceived(peerID, key)
+		if memR.latencies.received(peerID, key) {
+			memR.observeLatency(src, peerID)
+		}
+		memR.releaseRequest(peerID, key)
+		memR.Logger.Debug("received a response for a requested transaction", "peerID", peerID, "txKey", key)
+	} else {
+		// If we didn't request the transaction we simply mark the peer as having the
+		// tx (we'd have already done it if we were requesting the tx).
+		memR.mempool.PeerHasTx(peerID, key)
+		memR.Logger.Debug("received new trasaction", "peerID", peerID, "txKey", key)
+	}
+	_, err := memR.mempool.TryAddNewTx(memR.ctx, tx, key, txInfo)
+	if err == ErrTxInMempool {
+		label := memR.peerLabels.label(src.ID())
+		memR.mempool.metrics.DuplicateTxsReceived.With("peer_id", label).Add(1)
+		memR.mempool.metrics.DuplicateTxBytes.With("peer_id", label).Add(float64(len(tx)))
+	}
+	if err != nil && err != ErrTxInMempool {
+		memR.Logger.Info("Could not add tx", "txKey", key, "err", err)
+		return false
+	}
+	if !memR.opts.ListenOnly {
+		// We broadcast only transactions that we deem valid and actually have in our mempool.
+		memR.broadcastSeenTx(key)
+	}
+	return true
+}
+
+// sendWantedTx sends the transaction txKey to peer, which requested it, if
+// it is in the mempool.
+func (memR *Reactor) sendWantedTx(peer p2p.Peer, txKey types.TxKey) {
+	schema.WriteMempoolPeerState(
+		memR.traceClient,
+		string(peer.ID()),
+		schema.WantTx,
+		txKey[:],
+		schema.Download,
+	)
+	tx, has := memR.mempool.GetTxByKey(txKey)
+	memR.countStateMsg(memR.mempool.metrics.WantTxReceived, peer, has && !memR.opts.ListenOnly)
+	if has && !memR.opts.ListenOnly {
+		peerID := memR.ids.GetIDForPeer(peer.ID())
+		memR.Logger.Debug("sending a tx in response to a want msg", "peer", peerID)
+		var sent bool
+		if len(tx) > memR.opts.MaxTxMsgSize && supportsTxChunks(peer) {
+			sent = memR.sendTxChunks(peer, txKey, tx)
+		} else {
+			sent = p2p.SendEnvelopeShim(peer, p2p.Envelope{ //nolint:staticcheck
+				ChannelID: mempool.MempoolChannel,
+				Message:   &protomem.Txs{Txs: [][]byte{tx}},
+			}, memR.Logger)
+		}
+		if sent {
+			memR.mempool.PeerHasTx(peerID, txKey)
+			schema.WriteMempoolTx(
+				memR.traceClient,
+				string(peer.ID()),
+				txKey[:],
+				len(tx),
+				schema.Upload,
+			)
+		}
+	}
+}
+
+// sendTxChunks sends the transaction tx to peer in chunks of up to
+// MaxTxMsgSize bytes. It returns false if a chunk couldn't be sent.
+func (memR *Reactor) sendTxChunks(peer p2p.Peer, txKey types.TxKey, tx types.Tx) bool {
+	chunks := splitTx(tx, memR.opts.MaxTxMsgSize)
+	for i, chunk := range chunks {
+		if !p2p.SendEnvelopeShim(peer, p2p.Envelope{ //nolint:staticcheck
+			ChannelID: MempoolChunkChannel,
+			Message: &protomem.TxChunk{
+				TxKey: txKey[:],
+				Index: uint32(i),
+				Total: uint32(len(chunks)),
+				Data:  chunk,
+			},
+		}, memR.Logger) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowStateMsg applies the rate limit of peer to a SeenTx or WantTx message,
+// or a key of a WantTxs message, it sent. It returns false if the message must be dropped, and disconnects
+// the peer once too many of its messages were dropped.
+func (memR *Reactor) allowStateMsg(peer p2p.Peer) bool {
+	allowed, abusive := memR.stateMsgs.allow(memR.ids.GetIDForPeer(peer.ID()))
+	if allowed {
+		return true
+	}
+	memR.mempool.metrics.DroppedStateMsgs.Add(1)
+	if abusive {
+		memR.Logger.Info("peer exceeded its rate of state messages", "peer", peer.ID())
+		memR.Switch.StopPeerForError(peer, fmt.Errorf("peer exceeded its rate of %d SeenTx and WantTx messages per second",
+			memR.opts.StateMsgRate))
+	}
+	return false
+}
+
+// countStateMsg counts a SeenTx or WantTx message received from peer in
+// counter, labeled by whether it was useful.
+func (memR *Reactor) countStateMsg(counter metrics.Counter, peer p2p.Peer, useful bool) {
+	counter.With("peer_id", memR.peerLabels.label(peer.ID()), "useful", strconv.FormatBool(useful)).Add(1)
+}
+
 // PeerState describes the state of a peer.
 type PeerState interface {
 	GetHeight() int64
@@ -424,14 +708,49 @@ func (memR *Reactor) broadcastNewTx(wtx *wrappedTx) {
 	}
 }
 
+// rebroadcastTxs broadcasts again the next pending transactions queued by the
+// mempool to the peers which haven't seen them, in case the peers it was
+// first broadcast to dropped them.
+func (memR *Reactor) rebroadcastTxs() {
+	for _, wtx := range memR.mempool.nextRebroadcasts(maxRebroadcastsPerInterval) {
+		memR.Logger.Debug("rebroadcasting pending tx", "tx_key", wtx.key.String(), "height", wtx.height)
+		memR.broadcastNewTx(wtx)
+		memR.mempool.metrics.RebroadcastTxs.Add(1)
+	}
+}
+
 // requestTx requests a transaction from a peer and tracks it,
 // requesting it from another peer if the first peer does not respond.
+// If the peer has too many outstanding requests, the transaction is requested
+// from another peer instead.
 func (memR *Reactor) requestTx(txKey types.TxKey, peer p2p.Peer) {
 	if peer == nil {
 		// we have disconnected from the peer
 		return
 	}
+	if !memR.tryRequestTx(txKey, peer) {
+		memR.findNewPeerToRequestTx(txKey)
+	}
+}
+
+// tryRequestTx requests a transaction from a peer like requestTx, unless the
+// peer has too many outstanding requests, in which case it returns false.
+func (memR *Reactor) tryRequestTx(txKey types.TxKey, peer p2p.Peer) bool {
+	peerID := memR.ids.GetIDForPeer(peer.ID())
+	if !memR.inFlight.acquire(peerID, txKey) {
+		memR.Logger.Debug("too many outstanding requests to peer", "txKey", txKey, "peerID", peer.ID())
+		return false
+	}
 	memR.Logger.Debug("requesting tx", "txKey", txKey, "peerID", peer.ID())
+	if supportsWantTxs(peer) {
+		// the request is tracked right away so that the tx isn't requested
+		// again while it waits for its batch to be sent
+		if memR.trackRequest(peerID, txKey) {
+			memR.mempool.metrics.RequestedTxs.Add(1)
+			memR.wants.add(peerID, txKey)
+		}
+		return true
+	}
 	msg := &protomem.Message{
 		Sum: &protomem.Message_WantTx{
 			WantTx: &protomem.WantTx{TxKey: txKey[:]},
@@ -443,47 +762,216 @@ func (memR *Reactor) requestTx(txKey types.TxKey, peer p2p.Peer) {
 	}
 
 	success := peer.Send(MempoolStateChannel, bz) //nolint:staticcheck
-	if success {
-		memR.mempool.metrics.RequestedTxs.Add(1)
-		requested := memR.requests.Add(txKey, memR.ids.GetIDForPeer(peer.ID()), memR.findNewPeerToRequestTx)
-		if !requested {
-			memR.Logger.Error("have already marked a tx as requested", "txKey", txKey, "peerID", peer.ID())
+	if !success {
+		memR.releaseRequest(peerID, txKey)
+		return true
+	}
+	memR.mempool.metrics.RequestedTxs.Add(1)
+	memR.trackRequest(peerID, txKey)
+	return true
+}
+
+// trackRequest records the request of the transaction txKey to peer, to
+// request it from another peer if it doesn't respond in time. It returns
+// false, releasing the request slot, if the tx was already requested.
+func (memR *Reactor) trackRequest(peerID uint16, txKey types.TxKey) bool {
+	requested := memR.requests.Add(txKey, peerID, func(key types.TxKey) {
+		// the peer didn't respond in time
+		memR.failRequest(peerID, key)
+		memR.releaseRequest(peerID, key)
+		memR.findNewPeerToRequestTx(key)
+	})
+	if !requested {
+		memR.Logger.Error("have already marked a tx as requested", "txKey", txKey, "peerID", peerID)
+		memR.releaseRequest(peerID, txKey)
+		return false
+	}
+	memR.latencies.requested(peerID, txKey)
+	return true
+}
+
+// failRequest records that the request of the transaction txKey to peer
+// failed in its latency stats.
+func (memR *Reactor) failRequest(peerID uint16, txKey types.TxKey) {
+	if !memR.latencies.failed(peerID, txKey) {
+		return
+	}
+	if peer := memR.ids.GetPeer(peerID); peer != nil {
+		memR.observeLatency(peer, peerID)
+	}
+}
+
+// observeLatency updates the request latency metrics of peer.
+func (memR *Reactor) observeLatency(peer p2p.Peer, peerID uint16) {
+	latency, successRate := memR.latencies.stats(peerID)
+	label := memR.peerLabels.label(peer.ID())
+	memR.mempool.metrics.PeerRequestLatency.With("peer_id", label).Set(latency.Seconds())
+	memR.mempool.metrics.PeerRequestSuccessRate.With("peer_id", label).Set(successRate)
+}
+
+// sendWantTxs sends the batch of requests for the transactions keys to peer
+// in a single WantTxs message. If it can't be sent, the requests are
+// released like a WantTx which can't be sent.
+func (memR *Reactor) sendWantTxs(peerID uint16, keys []types.TxKey) {
+	peer := memR.ids.GetPeer(peerID)
+	if peer == nil {
+		// we have disconnected from the peer, and cleared its requests
+		return
+	}
+	txKeys := make([][]byte, len(keys))
+	for i, key := range keys {
+		txKeys[i] = key[:]
+	}
+	msg := &protomem.Message{
+		Sum: &protomem.Message_WantTxs{
+			WantTxs: &protomem.WantTxs{TxKeys: txKeys},
+		},
+	}
+	bz, err := msg.Marshal()
+	if err != nil {
+		panic(err)
+	}
+
+	if !peer.Send(MempoolWantsChannel, bz) { //nolint:staticcheck
+		for _, key := range keys {
+			if memR.requests.MarkReceived(peerID, key) {
+				memR.failRequest(peerID, key)
+				memR.releaseRequest(peerID, key)
+			}
 		}
 	}
 }
 
+// supportsTxChunks returns true if peer understands TxChunk messages, i.e. if
+// it advertises MempoolChunkChannel.
+func supportsTxChunks(peer p2p.Peer) bool {
+	nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
+	return ok && nodeInfo.HasChannel(MempoolChunkChannel)
+}
+
+// supportsWantTxs returns true if peer understands WantTxs messages, i.e. if
+// it advertises MempoolWantsChannel.
+func supportsWantTxs(peer p2p.Peer) bool {
+	nodeInfo, ok := peer.NodeInfo().(p2p.DefaultNodeInfo)
+	return ok && nodeInfo.HasChannel(MempoolWantsChannel)
+}
+
 // findNewPeerToSendTx finds a new peer that has already seen the transaction to
-// request a transaction from.
+// request a transaction from, trying the fastest peers to answer requests
+// first. If all of them have too many outstanding requests, the transaction is
+// queued until one of their requests completes.
 func (memR *Reactor) findNewPeerToRequestTx(txKey types.TxKey) {
 	// ensure that we are connected to peers
 	if memR.ids.Len() == 0 {
 		return
 	}
 
-	// pop the next peer in the list of remaining peers that have seen the tx
-	// and does not already have an outbound request for that tx
+	// try the remaining peers that have seen the tx and do not already have
+	// an outbound request for that tx
 	seenMap := memR.mempool.seenByPeersSet.Get(txKey)
-	var peerID uint16
+	possiblePeers := make([]uint16, 0, len(seenMap))
 	for possiblePeer := range seenMap {
-		if !memR.requests.Has(possiblePeer, txKey) {
-			peerID = possiblePeer
-			break
+		possiblePeers = append(possiblePeers, possiblePeer)
+	}
+	memR.latencies.order(possiblePeers)
+	busy := false
+	for _, possiblePeer := range possiblePeers {
+		if memR.requests.Has(possiblePeer, txKey) {
+			continue
+		}
+		peer := memR.ids.GetPeer(possiblePeer)
+		if peer == nil {
+			// we disconnected from that peer
+			continue
 		}
+		if memR.tryRequestTx(txKey, peer) {
+			memR.mempool.metrics.RerequestedTxs.Add(1)
+			return
+		}
+		busy = true
+	}
+
+	if busy {
+		// The peers that have the transaction are busy, request it once one
+		// of them completes a request.
+		memR.inFlight.enqueue(txKey)
+		return
 	}
+	// No other free peer has the transaction we are looking for.
+	// We give up 🤷‍♂️ and hope either a peer responds late or the tx
+	// is gossiped again
+	memR.Logger.Info("no other peer has the tx we are looking for", "txKey", txKey)
+}
 
-	if peerID == 0 {
-		// No other free peer has the transaction we are looking for.
-		// We give up 🤷‍♂️ and hope either a peer responds late or the tx
-		// is gossiped again
-		memR.Logger.Info("no other peer has the tx we are looking for", "txKey", txKey)
+// releaseRequest frees the request slot of peer taken by the request for the
+// transaction txKey, and uses it to request the first queued transaction that
+// the peer has seen.
+func (memR *Reactor) releaseRequest(peerID uint16, txKey types.TxKey) {
+	if !memR.inFlight.release(peerID, txKey) {
 		return
 	}
 	peer := memR.ids.GetPeer(peerID)
 	if peer == nil {
-		// we disconnected from that peer, retry again until we exhaust the list
-		memR.findNewPeerToRequestTx(txKey)
-	} else {
-		memR.mempool.metrics.RerequestedTxs.Add(1)
-		memR.requestTx(txKey, peer)
+		return
+	}
+	next, ok := memR.inFlight.dequeue(
+		func(key types.TxKey) bool {
+			return memR.mempool.seenByPeersSet.Has(key, peerID) && !memR.requests.Has(peerID, key)
+		},
+		func(key types.TxKey) bool {
+			// we got the transaction or are requesting it already
+			return memR.mempool.Has(key) || memR.mempool.IsRejectedTx(key) || memR.requests.ForTx(key) != 0
+		},
+	)
+	if ok {
+		memR.requestTx(next, peer)
+	}
+}
+
+// GossipState returns the gossip state of up to limit of the transactions in
+// the mempool, seen by peers or requested, skipping the first offset ones,
+// and the total number of them. The transactions are sorted by key.
+func (memR *Reactor) GossipState(offset, limit int) ([]mempool.TxGossipState, int) {
+	keys := make(map[types.TxKey]struct{})
+	for _, key := range memR.mempool.store.getAllKeys() {
+		keys[key] = struct{}{}
+	}
+	for _, key := range memR.mempool.seenByPeersSet.Keys() {
+		keys[key] = struct{}{}
+	}
+	for _, key := range memR.requests.Keys() {
+		keys[key] = struct{}{}
+	}
+	sorted := make([]types.TxKey, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+
+	total := len(sorted)
+	if offset > total {
+		offset = total
+	}
+	if limit > total-offset {
+		limit = total - offset
+	}
+	states := make([]mempool.TxGossipState, 0, limit)
+	for _, key := range sorted[offset : offset+limit] {
+		state := mempool.TxGossipState{Key: key, InMempool: memR.mempool.Has(key)}
+		for id := range memR.mempool.seenByPeersSet.Get(key) {
+			if peer := memR.ids.GetPeer(id); peer != nil {
+				state.SeenBy = append(state.SeenBy, peer.ID())
+			}
+		}
+		sort.Slice(state.SeenBy, func(i, j int) bool { return state.SeenBy[i] < state.SeenBy[j] })
+		if id := memR.requests.ForTx(key); id != 0 {
+			if peer := memR.ids.GetPeer(id); peer != nil {
+				state.RequestedFrom = peer.ID()
+			}
+		}
+		states = append(states, state)
 	}
+	return states, total
 }
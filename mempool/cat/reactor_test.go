@@ -1,16 +1,22 @@
 package cat
 
 import (
+	"context"
 	"encoding/hex"
+	"fmt"
+	"net"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics"
 	"github.com/go-kit/log/term"
 	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
@@ -20,6 +26,7 @@ import (
 	cfg "github.com/tendermint/tendermint/config"
 
 	"github.com/tendermint/tendermint/libs/log"
+	tmrand "github.com/tendermint/tendermint/libs/rand"
 	"github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/p2p"
 	"github.com/tendermint/tendermint/p2p/mocks"
@@ -195,6 +202,636 @@ func TestRemovePeerRequestFromOtherPeer(t *testing.T) {
 	require.False(t, reactor.mempool.seenByPeersSet.Has(key, 1))
 }
 
+// TestReactorSpreadsWantTxsAcrossPeers checks that no more than
+// MaxInFlightWantsPerPeer txs are requested from a peer at once, the other
+// txs being requested from the other peers which have seen them, or queued.
+func TestReactorSpreadsWantTxsAcrossPeers(t *testing.T) {
+	reactor, pool := setupReactorWithOptions(t, &ReactorOptions{
+		MaxGossipDelay:          time.Minute,
+		MaxInFlightWantsPerPeer: 2,
+	})
+	peers := genPeers(3)
+	wants := make([]*wantRecorder, len(peers))
+	for i, peer := range peers {
+		wants[i] = recordWants(peer)
+		reactor.InitPeer(peer)
+	}
+
+	const txCount = 8
+	for i := 0; i < txCount; i++ {
+		key := newDefaultTx(fmt.Sprintf("tx%d", i)).Key()
+		for _, peer := range peers {
+			pool.PeerHasTx(reactor.ids.GetIDForPeer(peer.ID()), key)
+		}
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       peers[0],
+			Message:   &protomem.SeenTx{TxKey: key[:]},
+			ChannelID: MempoolStateChannel,
+		})
+	}
+
+	requested := make(map[types.TxKey]bool)
+	for i, peer := range peers {
+		assert.Len(t, wants[i].keys(), 2, "peer %d", i)
+		assert.Equal(t, 2, reactor.inFlight.count(reactor.ids.GetIDForPeer(peer.ID())))
+		for _, key := range wants[i].keys() {
+			assert.False(t, requested[key], "tx requested twice")
+			requested[key] = true
+		}
+	}
+	assert.Equal(t, txCount-6, reactor.inFlight.queueLen())
+
+	// the queued txs are dropped once no connected peer has seen them
+	for _, peer := range peers {
+		reactor.RemovePeer(peer, "test")
+	}
+	assert.Zero(t, reactor.inFlight.queueLen())
+}
+
+// TestReactorReleasesWantTxSlots checks that the request slots of a peer are
+// freed when the txs arrive and when the peer disconnects, and used for the
+// queued txs.
+func TestReactorReleasesWantTxSlots(t *testing.T) {
+	reactor, pool := setupReactorWithOptions(t, &ReactorOptions{
+		MaxGossipDelay:          time.Minute,
+		MaxInFlightWantsPerPeer: 1,
+	})
+	peers := genPeers(2)
+	wants := make([]*wantRecorder, len(peers))
+	for i, peer := range peers {
+		wants[i] = recordWants(peer)
+		peer.On("Send", mempool.MempoolChannel, mock.Anything).Return(true).Maybe()
+		reactor.InitPeer(peer)
+	}
+
+	txs := []types.Tx{newDefaultTx("a"), newDefaultTx("b"), newDefaultTx("c")}
+	for _, tx := range txs {
+		key := tx.Key()
+		for _, peer := range peers {
+			pool.PeerHasTx(reactor.ids.GetIDForPeer(peer.ID()), key)
+		}
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       peers[0],
+			Message:   &protomem.SeenTx{TxKey: key[:]},
+			ChannelID: MempoolStateChannel,
+		})
+	}
+	// a is requested from peer 0, b spills over to peer 1, c is queued
+	assert.Equal(t, []types.TxKey{txs[0].Key()}, wants[0].keys())
+	assert.Equal(t, []types.TxKey{txs[1].Key()}, wants[1].keys())
+	assert.Equal(t, 1, reactor.inFlight.queueLen())
+
+	// b is queued again as peer 0 is busy
+	reactor.RemovePeer(peers[1], "test")
+	assert.Equal(t, 2, reactor.inFlight.queueLen())
+
+	// each tx received from peer 0 frees its slot for the next queued tx
+	for i, tx := range []types.Tx{txs[0], txs[2]} {
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       peers[0],
+			Message:   &protomem.Txs{Txs: [][]byte{tx}},
+			ChannelID: mempool.MempoolChannel,
+		})
+		assert.Equal(t, 1-i, reactor.inFlight.queueLen())
+	}
+	assert.Equal(t, []types.TxKey{txs[0].Key(), txs[2].Key(), txs[1].Key()}, wants[0].keys())
+	assert.Equal(t, 1, reactor.inFlight.count(reactor.ids.GetIDForPeer(peers[0].ID())))
+}
+
+// TestReactorReleasesWantTxSlotOnTimeout checks that the request slot of a
+// peer not responding in time is freed for the queued txs.
+func TestReactorReleasesWantTxSlotOnTimeout(t *testing.T) {
+	reactor, pool := setupReactorWithOptions(t, &ReactorOptions{
+		MaxGossipDelay:          50 * time.Millisecond,
+		MaxInFlightWantsPerPeer: 1,
+	})
+	peer := genPeer()
+	want := recordWants(peer)
+	reactor.InitPeer(peer)
+	peerID := reactor.ids.GetIDForPeer(peer.ID())
+
+	a, b := newDefaultTx("a").Key(), newDefaultTx("b").Key()
+	pool.PeerHasTx(peerID, b)
+	for _, key := range []types.TxKey{a, b} {
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       peer,
+			Message:   &protomem.SeenTx{TxKey: key[:]},
+			ChannelID: MempoolStateChannel,
+		})
+	}
+	assert.Equal(t, []types.TxKey{a}, want.keys())
+	assert.Equal(t, 1, reactor.inFlight.queueLen())
+
+	require.Eventually(t, func() bool {
+		return len(want.keys()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []types.TxKey{a, b}, want.keys())
+	assert.Zero(t, reactor.inFlight.queueLen())
+}
+
+// TestReactorRerequestsTxFromSilentPeer checks that a tx requested from a peer
+// which never answers is requested from another peer which has seen it once
+// the request times out, and recovered from that peer.
+func TestReactorRerequestsTxFromSilentPeer(t *testing.T) {
+	reactor, pool := setupReactorWithOptions(t, &ReactorOptions{MaxGossipDelay: 50 * time.Millisecond})
+	peers := genPeers(2)
+	wants := make([]*wantRecorder, len(peers))
+	for i, peer := range peers {
+		wants[i] = recordWants(peer)
+		reactor.InitPeer(peer)
+	}
+
+	tx := newDefaultTx("hello")
+	key := tx.Key()
+	pool.PeerHasTx(reactor.ids.GetIDForPeer(peers[1].ID()), key)
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		Src:       peers[0],
+		Message:   &protomem.SeenTx{TxKey: key[:]},
+		ChannelID: MempoolStateChannel,
+	})
+	require.Equal(t, []types.TxKey{key}, wants[0].keys())
+	require.Empty(t, wants[1].keys())
+
+	// peer 0 never answers
+	require.Eventually(t, func() bool {
+		return len(wants[1].keys()) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []types.TxKey{key}, wants[1].keys())
+	assert.Len(t, wants[0].keys(), 1, "the tx was requested again from the silent peer")
+
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		Src:       peers[1],
+		Message:   &protomem.Txs{Txs: [][]byte{tx}},
+		ChannelID: mempool.MempoolChannel,
+	})
+	assert.True(t, pool.Has(key))
+	assert.False(t, reactor.requests.Has(reactor.ids.GetIDForPeer(peers[1].ID()), key))
+}
+
+// TestReactorRequestsTxsFromFastestPeer checks that a tx seen by several
+// peers is requested from the peer which has answered the previous requests
+// the fastest.
+func TestReactorRequestsTxsFromFastestPeer(t *testing.T) {
+	reactor, pool := setupReactorWithOptions(t, &ReactorOptions{MaxGossipDelay: time.Minute})
+	now := time.Now()
+	reactor.latencies.now = func() time.Time { return now }
+	peers := genPeers(2)
+	wants := make([]*wantRecorder, len(peers))
+	for i, peer := range peers {
+		wants[i] = recordWants(peer)
+		peer.On("Send", mempool.MempoolChannel, mock.Anything).Return(true).Maybe()
+		reactor.InitPeer(peer)
+	}
+	slow, fast := peers[0], peers[1]
+
+	// each peer answers a request, the slow one in 500ms and the fast one in
+	// 20ms
+	for i, latency := range []time.Duration{500 * time.Millisecond, 20 * time.Millisecond} {
+		tx := newDefaultTx(fmt.Sprintf("warmup%d", i))
+		key := tx.Key()
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       peers[i],
+			Message:   &protomem.SeenTx{TxKey: key[:]},
+			ChannelID: MempoolStateChannel,
+		})
+		now = now.Add(latency)
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       peers[i],
+			Message:   &protomem.Txs{Txs: [][]byte{tx}},
+			ChannelID: mempool.MempoolChannel,
+		})
+		require.True(t, pool.Has(key))
+	}
+	slowLatency, _ := reactor.latencies.stats(reactor.ids.GetIDForPeer(slow.ID()))
+	fastLatency, _ := reactor.latencies.stats(reactor.ids.GetIDForPeer(fast.ID()))
+	require.Less(t, fastLatency, slowLatency)
+
+	// the txs seen by both peers are requested from the fast one
+	var keys []types.TxKey
+	for i := 0; i < 5; i++ {
+		key := newDefaultTx(fmt.Sprintf("tx%d", i)).Key()
+		for _, peer := range peers {
+			pool.PeerHasTx(reactor.ids.GetIDForPeer(peer.ID()), key)
+		}
+		reactor.findNewPeerToRequestTx(key)
+		keys = append(keys, key)
+	}
+	assert.Len(t, wants[0].keys(), 1)
+	assert.Equal(t, keys, wants[1].keys()[1:])
+}
+
+// TestReactorRebroadcastsPendingTxs checks that a tx still in the mempool is
+// broadcast again once every RebroadcastAfterHeights heights, only to the
+// peers which haven't seen it.
+func TestReactorRebroadcastsPendingTxs(t *testing.T) {
+	reactor, pool := setupReactor(t)
+	interval := pool.config.RebroadcastAfterHeights
+	require.NotZero(t, interval)
+
+	tx := newDefaultTx("hello")
+	msg := &protomem.Message{Sum: &protomem.Message_Txs{Txs: &protomem.Txs{Txs: [][]byte{tx}}}}
+	msgBytes, err := msg.Marshal()
+	require.NoError(t, err)
+
+	// peer 0 has seen the tx, so only peer 1 should receive it
+	peers := genPeers(3)
+	peers[1].On("Send", mempool.MempoolChannel, msgBytes).Return(true)
+	peers[2].On("Send", mempool.MempoolChannel, msgBytes).Return(true)
+	reactor.InitPeer(peers[0])
+	reactor.InitPeer(peers[1])
+	pool.PeerHasTx(reactor.ids.GetIDForPeer(peers[0].ID()), tx.Key())
+	require.NoError(t, pool.CheckTx(tx, nil, mempool.TxInfo{}))
+
+	advance := func(heights int64) {
+		for i := int64(0); i < heights; i++ {
+			require.NoError(t, pool.Update(pool.Height()+1, nil, nil, nil, nil))
+			reactor.rebroadcastTxs()
+		}
+	}
+
+	advance(interval - 1)
+	peers[1].AssertNotCalled(t, "Send", mempool.MempoolChannel, msgBytes)
+	advance(1)
+	peers[1].AssertNumberOfCalls(t, "Send", 1)
+
+	// peer 2 connects after the first rebroadcast and receives the second one,
+	// while peer 1 has seen the tx by now
+	reactor.InitPeer(peers[2])
+	advance(interval - 1)
+	peers[2].AssertNotCalled(t, "Send", mempool.MempoolChannel, msgBytes)
+	advance(1)
+	peers[1].AssertNumberOfCalls(t, "Send", 1)
+	peers[2].AssertNumberOfCalls(t, "Send", 1)
+	peers[0].AssertNotCalled(t, "Send", mempool.MempoolChannel, msgBytes)
+}
+
+// TestReactorDisconnectsPeerFloodingStateMsgs checks that the SeenTx messages
+// of a peer over its rate are dropped, and that the peer is disconnected once
+// too many of them were.
+func TestReactorDisconnectsPeerFloodingStateMsgs(t *testing.T) {
+	reactor, pool := setupReactorWithOptions(t, &ReactorOptions{
+		StateMsgRate:        1,
+		StateMsgBurst:       5,
+		MaxDroppedStateMsgs: 10,
+	})
+	now := time.Now()
+	reactor.stateMsgs.now = func() time.Time { return now }
+	reactor.SetSwitch(p2p.MakeSwitch(cfg.DefaultP2PConfig(), 0, "127.0.0.1", "123.123.123",
+		func(_ int, sw *p2p.Switch) *p2p.Switch { return sw }))
+
+	peer := genPeer()
+	peer.On("Send", MempoolStateChannel, mock.Anything).Return(true).Maybe()
+	peer.On("String").Return("flooding peer").Maybe()
+	peer.On("IsRunning").Return(true)
+	peer.On("RemoteAddr").Return(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 26656})
+	peer.On("CloseConn").Return(nil)
+	peer.On("Stop").Return(nil)
+	peer.On("IsPersistent").Return(false)
+	peer.On("HasIPChanged").Return(false)
+	peer.On("SetRemovalFailed")
+	reactor.InitPeer(peer)
+
+	seenTx := func(i int) {
+		key := newDefaultTx(fmt.Sprintf("tx%d", i)).Key()
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       peer,
+			Message:   &protomem.SeenTx{TxKey: key[:]},
+			ChannelID: MempoolStateChannel,
+		})
+	}
+
+	// the burst is processed, the messages over it are dropped
+	for i := 0; i < 14; i++ {
+		seenTx(i)
+	}
+	assert.Equal(t, 5, pool.seenByPeersSet.Len())
+	peer.AssertNotCalled(t, "Stop")
+
+	// the 10th dropped message gets the peer disconnected
+	seenTx(14)
+	assert.Equal(t, 5, pool.seenByPeersSet.Len())
+	peer.AssertCalled(t, "Stop")
+}
+
+// TestReactorCountsRedundantMessages checks the counters of the duplicate txs
+// and of the SeenTx and WantTx messages, by whether they were useful.
+func TestReactorCountsRedundantMessages(t *testing.T) {
+	reactor, pool := setupReactor(t)
+	duplicates, duplicateBytes := newLabeledCounter(), newLabeledCounter()
+	seenTxs, wantTxs := newLabeledCounter(), newLabeledCounter()
+	pool.metrics.DuplicateTxsReceived = duplicates
+	pool.metrics.DuplicateTxBytes = duplicateBytes
+	pool.metrics.SeenTxReceived = seenTxs
+	pool.metrics.WantTxReceived = wantTxs
+
+	peer := genPeer()
+	peer.On("Send", MempoolStateChannel, mock.Anything).Return(true).Maybe()
+	peer.On("SendEnvelope", mock.Anything).Return(true).Maybe()
+	reactor.InitPeer(peer)
+	peerID := string(peer.ID())
+	receive := func(msg proto.Message, chID byte) {
+		reactor.ReceiveEnvelope(p2p.Envelope{Src: peer, Message: msg, ChannelID: chID})
+	}
+
+	tx := newDefaultTx("hello")
+	key := tx.Key()
+	receive(&protomem.Txs{Txs: [][]byte{tx}}, mempool.MempoolChannel)
+	require.True(t, pool.Has(key))
+	assert.Zero(t, duplicates.value(peerID))
+	receive(&protomem.Txs{Txs: [][]byte{tx}}, mempool.MempoolChannel)
+	assert.EqualValues(t, 1, duplicates.value(peerID))
+	assert.EqualValues(t, len(tx), duplicateBytes.value(peerID))
+
+	missing := newDefaultTx("missing").Key()
+	receive(&protomem.SeenTx{TxKey: key[:]}, MempoolStateChannel)
+	receive(&protomem.SeenTx{TxKey: missing[:]}, MempoolStateChannel)
+	receive(&protomem.SeenTx{TxKey: missing[:]}, MempoolStateChannel)
+	assert.EqualValues(t, 2, seenTxs.value(peerID, "false"))
+	assert.EqualValues(t, 1, seenTxs.value(peerID, "true"))
+
+	receive(&protomem.WantTx{TxKey: key[:]}, MempoolStateChannel)
+	receive(&protomem.WantTx{TxKey: missing[:]}, MempoolStateChannel)
+	assert.EqualValues(t, 1, wantTxs.value(peerID, "true"))
+	assert.EqualValues(t, 1, wantTxs.value(peerID, "false"))
+}
+
+// labeledCounter is a metrics.Counter recording the total added for each set
+// of label values, shared with the counters returned by With.
+type labeledCounter struct {
+	mtx    *sync.Mutex
+	totals map[string]float64
+	lvs    []string
+}
+
+func newLabeledCounter() *labeledCounter {
+	return &labeledCounter{mtx: &sync.Mutex{}, totals: make(map[string]float64)}
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	lvs := append(append([]string{}, c.lvs...), labelValues...)
+	return &labeledCounter{mtx: c.mtx, totals: c.totals, lvs: lvs}
+}
+
+func (c *labeledCounter) Add(delta float64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.totals[strings.Join(c.lvs, ",")] += delta
+}
+
+// value returns the total added with the given label values, the label names
+// being left out.
+func (c *labeledCounter) value(values ...string) float64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for lvs, total := range c.totals {
+		parts := strings.Split(lvs, ",")
+		var got []string
+		for i := 1; i < len(parts); i += 2 {
+			got = append(got, parts[i])
+		}
+		if strings.Join(got, ",") == strings.Join(values, ",") {
+			return total
+		}
+	}
+	return 0
+}
+
+func TestReactorGossipState(t *testing.T) {
+	reactor, pool := setupReactorWithOptions(t, &ReactorOptions{MaxGossipDelay: time.Minute})
+	peers := genPeers(2)
+	for _, peer := range peers {
+		peer.On("Send", MempoolStateChannel, mock.Anything).Return(true).Maybe()
+		reactor.InitPeer(peer)
+	}
+
+	// the node has tx a, seen by peer 0, and requests tx b from peer 1
+	a := newDefaultTx("a")
+	require.NoError(t, pool.CheckTx(a, nil, mempool.TxInfo{}))
+	pool.PeerHasTx(reactor.ids.GetIDForPeer(peers[0].ID()), a.Key())
+	b := newDefaultTx("b").Key()
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		Src:       peers[1],
+		Message:   &protomem.SeenTx{TxKey: b[:]},
+		ChannelID: MempoolStateChannel,
+	})
+
+	states, total := reactor.GossipState(0, 10)
+	require.Equal(t, 2, total)
+	require.Len(t, states, 2)
+	byKey := map[types.TxKey]mempool.TxGossipState{states[0].Key: states[0], states[1].Key: states[1]}
+	assert.Equal(t, mempool.TxGossipState{Key: a.Key(), InMempool: true, SeenBy: []p2p.ID{peers[0].ID()}}, byKey[a.Key()])
+	assert.Equal(t, mempool.TxGossipState{Key: b, SeenBy: []p2p.ID{peers[1].ID()}, RequestedFrom: peers[1].ID()}, byKey[b])
+
+	page, total := reactor.GossipState(1, 10)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, states[1:], page)
+	page, _ = reactor.GossipState(5, 10)
+	assert.Empty(t, page)
+}
+
+// wantRecorder records the WantTx and WantTxs requests sent to a mock peer.
+type wantRecorder struct {
+	mtx  sync.Mutex
+	sent []types.TxKey
+	msgs int
+}
+
+// recordWants makes peer accept the messages on the state and wants
+// channels, and records the WantTx and WantTxs requests among them.
+func recordWants(peer *mocks.Peer) *wantRecorder {
+	r := &wantRecorder{}
+	record := func(args mock.Arguments) {
+		msg := &protomem.Message{}
+		if err := proto.Unmarshal(args.Get(1).([]byte), msg); err != nil {
+			panic(err)
+		}
+		var keys [][]byte
+		if want := msg.GetWantTx(); want != nil {
+			keys = [][]byte{want.TxKey}
+		}
+		if wants := msg.GetWantTxs(); wants != nil {
+			keys = wants.TxKeys
+		}
+		r.mtx.Lock()
+		defer r.mtx.Unlock()
+		if len(keys) > 0 {
+			r.msgs++
+		}
+		for _, k := range keys {
+			key, err := types.TxKeyFromBytes(k)
+			if err != nil {
+				panic(err)
+			}
+			r.sent = append(r.sent, key)
+		}
+	}
+	peer.On("Send", MempoolStateChannel, mock.Anything).Run(record).Return(true)
+	peer.On("Send", MempoolWantsChannel, mock.Anything).Run(record).Return(true).Maybe()
+	return r
+}
+
+func (r *wantRecorder) keys() []types.TxKey {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]types.TxKey(nil), r.sent...)
+}
+
+// messages returns the number of messages the requests were sent in.
+func (r *wantRecorder) messages() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.msgs
+}
+
+// genWantTxsPeer generates a peer which understands WantTxs messages.
+func genWantTxsPeer() *mocks.Peer {
+	peer := &mocks.Peer{}
+	nodeKey := p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}
+	peer.On("ID").Return(nodeKey.ID())
+	peer.On("Get", types.PeerStateKey).Return(nil).Maybe()
+	peer.On("NodeInfo").Return(p2p.DefaultNodeInfo{
+		Channels: []byte{mempool.MempoolChannel, MempoolStateChannel, MempoolWantsChannel},
+	}).Maybe()
+	return peer
+}
+
+func TestReactorBatchesWantTxs(t *testing.T) {
+	reactor, _ := setupReactorWithOptions(t, &ReactorOptions{MaxGossipDelay: time.Minute})
+	// leave time for all the requests to be batched, even on a slow machine
+	reactor.wants.window = 200 * time.Millisecond
+	legacyPeer, peer := genPeer(), genWantTxsPeer()
+	legacyWants, wants := recordWants(legacyPeer), recordWants(peer)
+	reactor.InitPeer(legacyPeer)
+	reactor.InitPeer(peer)
+
+	var legacyKeys, keys []types.TxKey
+	for i := 0; i < 10; i++ {
+		legacyKeys = append(legacyKeys, newDefaultTx(fmt.Sprintf("legacy%d", i)).Key())
+		keys = append(keys, newDefaultTx(fmt.Sprintf("tx%d", i)).Key())
+	}
+	seen := func(src p2p.Peer, key types.TxKey) {
+		reactor.ReceiveEnvelope(p2p.Envelope{
+			Src:       src,
+			Message:   &protomem.SeenTx{TxKey: key[:]},
+			ChannelID: MempoolStateChannel,
+		})
+	}
+	for i := range keys {
+		seen(legacyPeer, legacyKeys[i])
+		seen(peer, keys[i])
+	}
+
+	// the legacy peer is sent a WantTx per tx
+	assert.Equal(t, legacyKeys, legacyWants.keys())
+	assert.Equal(t, len(legacyKeys), legacyWants.messages())
+
+	// the other peer is sent a single WantTxs, and is tracked as requested
+	// the txs right away
+	for _, key := range keys {
+		assert.True(t, reactor.requests.Has(reactor.ids.GetIDForPeer(peer.ID()), key))
+	}
+	require.Eventually(t, func() bool { return len(wants.keys()) == len(keys) }, time.Second, time.Millisecond)
+	assert.Equal(t, keys, wants.keys())
+	assert.Equal(t, 1, wants.messages())
+}
+
+func TestReactorSendsTxsAfterReceivingWantTxs(t *testing.T) {
+	reactor, pool := setupReactor(t)
+	peer := genPeer()
+	reactor.InitPeer(peer)
+
+	a, b := newDefaultTx("a"), newDefaultTx("b")
+	require.NoError(t, pool.CheckTx(a, nil, mempool.TxInfo{}))
+	require.NoError(t, pool.CheckTx(b, nil, mempool.TxInfo{}))
+	unknown := newDefaultTx("unknown").Key()
+	for _, tx := range []types.Tx{a, b} {
+		peer.On("SendEnvelope", p2p.Envelope{
+			Message:   &protomem.Txs{Txs: [][]byte{tx}},
+			ChannelID: mempool.MempoolChannel,
+		}).Return(true).Once()
+	}
+
+	aKey, bKey := a.Key(), b.Key()
+	reactor.ReceiveEnvelope(p2p.Envelope{
+		Src:       peer,
+		Message:   &protomem.WantTxs{TxKeys: [][]byte{aKey[:], unknown[:], bKey[:]}},
+		ChannelID: MempoolWantsChannel,
+	})
+
+	peer.AssertExpectations(t)
+	peerID := reactor.ids.GetIDForPeer(peer.ID())
+	assert.True(t, pool.seenByPeersSet.Has(aKey, peerID))
+	assert.True(t, pool.seenByPeersSet.Has(bKey, peerID))
+}
+
+func TestReactorSendsLargeTxInChunks(t *testing.T) {
+	reactors := makeAndConnectReactorsWithOptions(t, cfg.TestConfig(), 2, ReactorOptions{MaxTxSize: 8 * 1024 * 1024})
+	// the message is hex encoded in the tx, which is then of 4MB
+	tx := newDefaultTx(tmrand.Str(2 * 1024 * 1024))
+	key := tx.Key()
+	require.Greater(t, len(tx), DefaultMaxTxMsgSize)
+	require.True(t, supportsTxChunks(reactors[0].Switch.Peers().List()[0]))
+
+	// the first node has the tx, without broadcasting it, and tells the other
+	// one it has seen it
+	_, err := reactors[0].mempool.TryAddNewTx(context.Background(), tx, key, mempool.TxInfo{})
+	require.NoError(t, err)
+	reactors[0].broadcastSeenTx(key)
+
+	require.Eventually(t, func() bool { return reactors[1].mempool.Has(key) }, 30*time.Second, 50*time.Millisecond)
+	received, ok := reactors[1].mempool.GetTxByKey(key)
+	require.True(t, ok)
+	assert.Equal(t, tx, received)
+	assert.Zero(t, reactors[1].chunks.len())
+}
+
+// BenchmarkReactorRecovery requests many txs seen by a single peer, and
+// reports the number of messages the requests take with a peer which only
+// understands WantTx, and with one which understands WantTxs.
+func BenchmarkReactorRecovery(b *testing.B) {
+	const numTxs = 1000
+	keys := make([]types.TxKey, numTxs)
+	for i := range keys {
+		keys[i] = newDefaultTx(fmt.Sprintf("tx%d", i)).Key()
+	}
+	for _, bc := range []struct {
+		name string
+		peer func() *mocks.Peer
+	}{
+		{"WantTx", genPeer},
+		{"WantTxs", genWantTxsPeer},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			msgs := 0
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				reactor, _ := setupReactorWithOptions(b, &ReactorOptions{
+					MaxGossipDelay:          time.Minute,
+					MaxInFlightWantsPerPeer: numTxs,
+					StateMsgBurst:           numTxs,
+				})
+				peer := bc.peer()
+				wants := recordWants(peer)
+				reactor.InitPeer(peer)
+				b.StartTimer()
+
+				for _, key := range keys {
+					reactor.ReceiveEnvelope(p2p.Envelope{
+						Src:       peer,
+						Message:   &protomem.SeenTx{TxKey: key[:]},
+						ChannelID: MempoolStateChannel,
+					})
+				}
+				for len(wants.keys()) < numTxs {
+					time.Sleep(time.Millisecond)
+				}
+				msgs += wants.messages()
+			}
+			b.ReportMetric(float64(msgs)/float64(b.N), "sends/op")
+		})
+	}
+}
+
 func TestMempoolVectors(t *testing.T) {
 	testCases := []struct {
 		testName string
@@ -280,21 +917,31 @@ func TestLegacyReactorReceiveBasic(t *testing.T) {
 }
 
 func setupReactor(t *testing.T) (*Reactor, *TxPool) {
+	return setupReactorWithOptions(t, &ReactorOptions{})
+}
+
+func setupReactorWithOptions(t testing.TB, opts *ReactorOptions) (*Reactor, *TxPool) {
 	app := &application{kvstore.NewApplication()}
 	cc := proxy.NewLocalClientCreator(app)
 	pool, cleanup := newMempoolWithApp(cc)
 	t.Cleanup(cleanup)
-	reactor, err := NewReactor(pool, &ReactorOptions{})
+	reactor, err := NewReactor(pool, opts)
 	require.NoError(t, err)
+	t.Cleanup(reactor.requests.Close)
 	return reactor, pool
 }
 
 func makeAndConnectReactors(t *testing.T, config *cfg.Config, n int) []*Reactor {
+	return makeAndConnectReactorsWithOptions(t, config, n, ReactorOptions{})
+}
+
+func makeAndConnectReactorsWithOptions(t *testing.T, config *cfg.Config, n int, opts ReactorOptions) []*Reactor {
 	reactors := make([]*Reactor, n)
 	logger := mempoolLogger()
 	for i := 0; i < n; i++ {
 		var pool *TxPool
-		reactors[i], pool = setupReactor(t)
+		reactorOpts := opts
+		reactors[i], pool = setupReactorWithOptions(t, &reactorOpts)
 		pool.logger = logger.With("validator", i)
 		reactors[i].SetLogger(logger.With("validator", i))
 	}
@@ -406,5 +1053,6 @@ func genPeer() *mocks.Peer {
 	nodeKey := p2p.NodeKey{PrivKey: ed25519.GenPrivKey()}
 	peer.On("ID").Return(nodeKey.ID())
 	peer.On("Get", types.PeerStateKey).Return(nil).Maybe()
+	peer.On("NodeInfo").Return(p2p.DefaultNodeInfo{Channels: []byte{mempool.MempoolChannel, MempoolStateChannel}}).Maybe()
 	return peer
 }
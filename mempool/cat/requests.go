@@ -93,6 +93,18 @@ func (r *requestScheduler) ForTx(key types.TxKey) uint16 {
 	return r.requestsByTx[key]
 }
 
+// Keys returns the keys of the transactions with an outstanding request.
+func (r *requestScheduler) Keys() []types.TxKey {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	keys := make([]types.TxKey, 0, len(r.requestsByTx))
+	for key := range r.requestsByTx {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 func (r *requestScheduler) Has(peer uint16, key types.TxKey) bool {
 	r.mtx.Lock()
 	defer r.mtx.Unlock()
@@ -0,0 +1,99 @@
+package cat
+
+import (
+	"sync"
+
+	"github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// maxTxStatuses bounds the number of evicted, rejected and committed
+	// transactions whose status is remembered.
+	maxTxStatuses = 10000
+
+	// txStatusHeights is the number of heights for which the status of an
+	// evicted, rejected or committed transaction is remembered.
+	txStatusHeights = 100
+)
+
+// txStatusCache remembers the status of the latest evicted, rejected and
+// committed transactions, in a ring buffer of up to size entries. It is
+// thread safe.
+type txStatusCache struct {
+	mtx     sync.Mutex
+	entries []txStatusEntry
+	next    int
+	// index is the position in entries of the latest status of each key.
+	index map[types.TxKey]int
+}
+
+type txStatusEntry struct {
+	key    types.TxKey
+	status mempool.TxStatus
+}
+
+func newTxStatusCache(size int) *txStatusCache {
+	return &txStatusCache{
+		entries: make([]txStatusEntry, size),
+		index:   make(map[types.TxKey]int),
+	}
+}
+
+// set records the status of the transaction key, overwriting the oldest
+// status if the cache is full.
+func (c *txStatusCache) set(key types.TxKey, status mempool.TxStatus) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if len(c.entries) == 0 {
+		return
+	}
+	c.drop(c.next)
+	c.entries[c.next] = txStatusEntry{key: key, status: status}
+	c.index[key] = c.next
+	c.next = (c.next + 1) % len(c.entries)
+}
+
+// get returns the latest status recorded for the transaction key.
+func (c *txStatusCache) get(key types.TxKey) (mempool.TxStatus, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	i, ok := c.index[key]
+	if !ok {
+		return mempool.TxStatus{}, false
+	}
+	return c.entries[i].status, true
+}
+
+// prune forgets the statuses recorded below height.
+func (c *txStatusCache) prune(height int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for i, entry := range c.entries {
+		if entry.status.Status != mempool.TxStatusUnknown && entry.status.Height < height {
+			c.drop(i)
+		}
+	}
+}
+
+// reset forgets all the statuses.
+func (c *txStatusCache) reset() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.entries = make([]txStatusEntry, len(c.entries))
+	c.index = make(map[types.TxKey]int)
+	c.next = 0
+}
+
+// drop clears the entry i, which may have been superseded by a later status
+// of the same key.
+func (c *txStatusCache) drop(i int) {
+	entry := c.entries[i]
+	if entry.status.Status == mempool.TxStatusUnknown {
+		return
+	}
+	if j, ok := c.index[entry.key]; ok && j == i {
+		delete(c.index, entry.key)
+	}
+	c.entries[i] = txStatusEntry{}
+}
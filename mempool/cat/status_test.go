@@ -0,0 +1,50 @@
+package cat
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestTxStatusCache(t *testing.T) {
+	cache := newTxStatusCache(3)
+	keys := make([]types.TxKey, 5)
+	for i := range keys {
+		keys[i] = types.Tx(fmt.Sprintf("tx%d", i)).Key()
+	}
+
+	for i := 0; i < 3; i++ {
+		cache.set(keys[i], mempool.TxStatus{Status: mempool.TxStatusEvicted, Height: int64(i)})
+	}
+	status, ok := cache.get(keys[1])
+	require.True(t, ok)
+	require.Equal(t, mempool.TxStatus{Status: mempool.TxStatusEvicted, Height: 1}, status)
+
+	// a later status of a key supersedes the earlier one, and the oldest
+	// statuses are overwritten once the cache is full
+	cache.set(keys[1], mempool.TxStatus{Status: mempool.TxStatusCommitted, Height: 3})
+	status, ok = cache.get(keys[1])
+	require.True(t, ok)
+	require.Equal(t, mempool.TxStatusCommitted, status.Status)
+	_, ok = cache.get(keys[0])
+	require.False(t, ok)
+
+	cache.set(keys[3], mempool.TxStatus{Status: mempool.TxStatusRejected, Height: 4})
+	_, ok = cache.get(keys[1])
+	require.True(t, ok, "the superseded status of the key dropped its latest status")
+	_, ok = cache.get(keys[2])
+	require.True(t, ok)
+
+	cache.prune(4)
+	for i, key := range keys {
+		_, ok := cache.get(key)
+		require.Equal(t, i == 3, ok, "tx%d", i)
+	}
+
+	cache.reset()
+	_, ok = cache.get(keys[3])
+	require.False(t, ok)
+}
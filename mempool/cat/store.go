@@ -1,6 +1,8 @@
 package cat
 
 import (
+	"bytes"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,6 +15,9 @@ type store struct {
 	bytes       int64
 	txs         map[types.TxKey]*wrappedTx
 	reservedTxs map[types.TxKey]struct{}
+	// orderedTxs are the transactions of txs in the order they are reaped
+	// in, as defined by txLess.
+	orderedTxs []*wrappedTx
 }
 
 func newStore() *store {
@@ -23,6 +28,40 @@ func newStore() *store {
 	}
 }
 
+// txLess orders the transactions by priority, highest first, then by arrival,
+// oldest first. The ties are broken by key so that the order is total.
+func txLess(a, b *wrappedTx) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	if !a.timestamp.Equal(b.timestamp) {
+		return a.timestamp.Before(b.timestamp)
+	}
+	return bytes.Compare(a.key[:], b.key[:]) < 0
+}
+
+// add adds wtx, which isn't in the store, to txs and orderedTxs.
+func (s *store) add(wtx *wrappedTx) {
+	s.txs[wtx.key] = wtx
+	s.bytes += wtx.size()
+	i := sort.Search(len(s.orderedTxs), func(i int) bool { return txLess(wtx, s.orderedTxs[i]) })
+	s.orderedTxs = append(s.orderedTxs, nil)
+	copy(s.orderedTxs[i+1:], s.orderedTxs[i:])
+	s.orderedTxs[i] = wtx
+}
+
+// delete removes wtx, which is in the store, from txs and orderedTxs.
+func (s *store) delete(wtx *wrappedTx) {
+	delete(s.txs, wtx.key)
+	s.bytes -= wtx.size()
+	i := sort.Search(len(s.orderedTxs), func(i int) bool { return !txLess(s.orderedTxs[i], wtx) })
+	if i < len(s.orderedTxs) && s.orderedTxs[i] == wtx {
+		copy(s.orderedTxs[i:], s.orderedTxs[i+1:])
+		s.orderedTxs[len(s.orderedTxs)-1] = nil
+		s.orderedTxs = s.orderedTxs[:len(s.orderedTxs)-1]
+	}
+}
+
 func (s *store) set(wtx *wrappedTx) bool {
 	if wtx == nil {
 		return false
@@ -30,13 +69,62 @@ func (s *store) set(wtx *wrappedTx) bool {
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 	if _, exists := s.txs[wtx.key]; !exists {
-		s.txs[wtx.key] = wtx
-		s.bytes += wtx.size()
+		s.add(wtx)
 		return true
 	}
 	return false
 }
 
+// setWithEviction adds wtx to the store if it fits within maxTxs transactions
+// and maxBytes bytes. If it doesn't, the transactions with a strictly lower
+// priority are evicted, lowest priority first and oldest first among equal
+// priorities, until it fits. Nothing is evicted if evicting all of them would
+// not make enough room: wtx is then not added. The check and the eviction are
+// done atomically. It returns the evicted transactions and whether wtx was
+// added or was already in the store.
+func (s *store) setWithEviction(wtx *wrappedTx, maxTxs int, maxBytes int64) ([]*wrappedTx, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, exists := s.txs[wtx.key]; exists {
+		return nil, true
+	}
+
+	numTxs, bytes := len(s.txs), s.bytes
+	fits := func() bool { return numTxs < maxTxs && bytes+wtx.size() <= maxBytes }
+	var evicted []*wrappedTx
+	if !fits() {
+		victims := make([]*wrappedTx, 0)
+		for _, tx := range s.txs {
+			if tx.priority < wtx.priority {
+				victims = append(victims, tx)
+			}
+		}
+		sort.Slice(victims, func(i, j int) bool {
+			if victims[i].priority == victims[j].priority {
+				return victims[i].timestamp.Before(victims[j].timestamp)
+			}
+			return victims[i].priority < victims[j].priority
+		})
+		for _, tx := range victims {
+			evicted = append(evicted, tx)
+			numTxs--
+			bytes -= tx.size()
+			if fits() {
+				break
+			}
+		}
+		if !fits() {
+			return nil, false
+		}
+	}
+
+	for _, tx := range evicted {
+		s.delete(tx)
+	}
+	s.add(wtx)
+	return evicted, true
+}
+
 func (s *store) get(txKey types.TxKey) *wrappedTx {
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
@@ -57,8 +145,7 @@ func (s *store) remove(txKey types.TxKey) bool {
 	if !exists {
 		return false
 	}
-	s.bytes -= tx.size()
-	delete(s.txs, txKey)
+	s.delete(tx)
 	return true
 }
 
@@ -126,6 +213,19 @@ func (s *store) getAllTxs() []*wrappedTx {
 	return txs
 }
 
+// iterateOrderedTxs calls fn on the transactions in the order they are reaped
+// in, highest priority first then oldest first, until fn returns false. fn
+// must not call the store.
+func (s *store) iterateOrderedTxs(fn func(wtx *wrappedTx) bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	for _, wtx := range s.orderedTxs {
+		if !fn(wtx) {
+			return
+		}
+	}
+}
+
 func (s *store) getTxsBelowPriority(priority int64) ([]*wrappedTx, int64) {
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
@@ -149,10 +249,9 @@ func (s *store) purgeExpiredTxs(expirationHeight int64, expirationAge time.Time)
 	var purgedTxs []*wrappedTx
 	counter := 0
 
-	for key, tx := range s.txs {
+	for _, tx := range s.txs {
 		if tx.height < expirationHeight || tx.timestamp.Before(expirationAge) {
-			s.bytes -= tx.size()
-			delete(s.txs, key)
+			s.delete(tx)
 			purgedTxs = append(purgedTxs, tx)
 			counter++
 		}
@@ -165,4 +264,5 @@ func (s *store) reset() {
 	defer s.mtx.Unlock()
 	s.bytes = 0
 	s.txs = make(map[types.TxKey]*wrappedTx)
+	s.orderedTxs = nil
 }
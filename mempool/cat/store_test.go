@@ -3,6 +3,7 @@ package cat
 import (
 	"bytes"
 	"fmt"
+	"math/rand"
 	"sync"
 	"testing"
 	"time"
@@ -184,3 +185,113 @@ func TestStoreExpiredTxs(t *testing.T) {
 	store.purgeExpiredTxs(int64(0), time.Now().Add(time.Second))
 	require.Empty(t, store.getAllTxs())
 }
+
+func TestStoreSetWithEviction(t *testing.T) {
+	store := newStore()
+	now := time.Now()
+	newTx := func(name string, priority int64, age time.Duration) *wrappedTx {
+		tx := types.Tx(name)
+		wtx := newWrappedTx(tx, tx.Key(), 1, 1, priority, "")
+		wtx.timestamp = now.Add(-age)
+		return wtx
+	}
+
+	// fill the store with three txs of 3 bytes each
+	newer := newTx("tx1", 1, time.Second)
+	older := newTx("tx2", 1, time.Minute)
+	high := newTx("tx3", 5, time.Hour)
+	for _, wtx := range []*wrappedTx{newer, older, high} {
+		evicted, added := store.setWithEviction(wtx, 3, 9)
+		require.True(t, added)
+		require.Empty(t, evicted)
+	}
+
+	// a tx with the same priority as the lowest one evicts nothing
+	evicted, added := store.setWithEviction(newTx("tx4", 1, 0), 3, 9)
+	require.False(t, added)
+	require.Empty(t, evicted)
+	require.Equal(t, 3, store.size())
+
+	// among the txs with the lowest priority, the oldest is evicted first
+	evicted, added = store.setWithEviction(newTx("tx5", 2, 0), 3, 9)
+	require.True(t, added)
+	require.Equal(t, []*wrappedTx{older}, evicted)
+	require.True(t, store.has(newer.key))
+	require.Equal(t, int64(9), store.totalBytes())
+
+	// nothing is evicted when evicting all the lower priority txs isn't enough
+	evicted, added = store.setWithEviction(newTx("large-tx", 3, 0), 3, 9)
+	require.False(t, added)
+	require.Empty(t, evicted)
+	require.Equal(t, 3, store.size())
+	require.Equal(t, int64(9), store.totalBytes())
+
+	// as many txs as needed are evicted, lowest priority first
+	evicted, added = store.setWithEviction(newTx("tx6-6", 3, 0), 3, 9)
+	require.True(t, added)
+	require.Len(t, evicted, 2)
+	require.Equal(t, newer, evicted[0])
+	require.True(t, store.has(high.key))
+	require.Equal(t, 2, store.size())
+	require.Equal(t, int64(8), store.totalBytes())
+}
+
+func TestStoreOrderedTxs(t *testing.T) {
+	store := newStore()
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	now := time.Now()
+
+	requireOrdered := func() {
+		t.Helper()
+		var ordered []*wrappedTx
+		store.iterateOrderedTxs(func(wtx *wrappedTx) bool {
+			ordered = append(ordered, wtx)
+			return true
+		})
+		require.Len(t, ordered, store.size())
+		for i := 0; i < len(ordered)-1; i++ {
+			a, b := ordered[i], ordered[i+1]
+			require.True(t, store.has(a.key))
+			require.GreaterOrEqual(t, a.priority, b.priority)
+			if a.priority == b.priority {
+				require.False(t, b.timestamp.Before(a.timestamp))
+			}
+			require.True(t, txLess(a, b))
+		}
+	}
+
+	for i := 0; i < 2000; i++ {
+		// few priorities and timestamps, so that they tie often
+		tx := types.Tx(fmt.Sprintf("tx%d", i))
+		wtx := newWrappedTx(tx, tx.Key(), int64(rng.Intn(10)), 1, int64(rng.Intn(10)), "")
+		wtx.timestamp = now.Add(time.Duration(rng.Intn(10)) * time.Second)
+
+		switch rng.Intn(4) {
+		case 0:
+			store.set(wtx)
+		case 1:
+			store.setWithEviction(wtx, 50, 500)
+		case 2:
+			if txs := store.getAllTxs(); len(txs) > 0 {
+				store.remove(txs[rng.Intn(len(txs))].key)
+			}
+		case 3:
+			if rng.Intn(10) == 0 {
+				store.purgeExpiredTxs(int64(rng.Intn(3)), now.Add(time.Duration(rng.Intn(3))*time.Second))
+			}
+		}
+		requireOrdered()
+	}
+
+	// the iteration stops when asked to
+	var n int
+	store.iterateOrderedTxs(func(*wrappedTx) bool {
+		n++
+		return false
+	})
+	require.Equal(t, min(store.size(), 1), n)
+
+	store.reset()
+	requireOrdered()
+	require.Zero(t, store.size())
+}
@@ -0,0 +1,76 @@
+package cat
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// wantBatchWindow is how long the requests to a peer are held back to be
+	// sent together in a single WantTxs message.
+	wantBatchWindow = 5 * time.Millisecond
+
+	// maxWantTxsPerMsg is the maximum number of tx keys in a WantTxs message.
+	// A batch reaching it is sent without waiting for the end of its window.
+	maxWantTxsPerMsg = 1000
+)
+
+// wantBatcher coalesces the keys of the txs requested from the same peer
+// within window into batches, handed to send once the window ends or the batch
+// is full. It is thread safe.
+type wantBatcher struct {
+	mtx     sync.Mutex
+	window  time.Duration
+	max     int
+	pending map[uint16][]types.TxKey
+	send    func(peer uint16, keys []types.TxKey)
+}
+
+func newWantBatcher(window time.Duration, max int, send func(peer uint16, keys []types.TxKey)) *wantBatcher {
+	return &wantBatcher{
+		window:  window,
+		max:     max,
+		pending: make(map[uint16][]types.TxKey),
+		send:    send,
+	}
+}
+
+// add queues the request of the tx key to peer, starting a new batch if there
+// is none pending for the peer.
+func (b *wantBatcher) add(peer uint16, key types.TxKey) {
+	b.mtx.Lock()
+	keys, ok := b.pending[peer]
+	keys = append(keys, key)
+	if len(keys) >= b.max {
+		delete(b.pending, peer)
+		b.mtx.Unlock()
+		b.send(peer, keys)
+		return
+	}
+	b.pending[peer] = keys
+	b.mtx.Unlock()
+
+	if !ok {
+		time.AfterFunc(b.window, func() { b.flush(peer) })
+	}
+}
+
+// flush sends the pending batch of peer, if any.
+func (b *wantBatcher) flush(peer uint16) {
+	b.mtx.Lock()
+	keys, ok := b.pending[peer]
+	delete(b.pending, peer)
+	b.mtx.Unlock()
+	if ok {
+		b.send(peer, keys)
+	}
+}
+
+// remove drops the pending batch of peer.
+func (b *wantBatcher) remove(peer uint16) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	delete(b.pending, peer)
+}
@@ -0,0 +1,51 @@
+package cat
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestWantBatcher(t *testing.T) {
+	var (
+		mtx     sync.Mutex
+		batches = make(map[uint16][][]types.TxKey)
+	)
+	b := newWantBatcher(20*time.Millisecond, 3, func(peer uint16, keys []types.TxKey) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		batches[peer] = append(batches[peer], keys)
+	})
+	sent := func(peer uint16) [][]types.TxKey {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return batches[peer]
+	}
+	a, c, d := types.Tx("a").Key(), types.Tx("c").Key(), types.Tx("d").Key()
+
+	// a full batch is sent right away
+	b.add(1, a)
+	b.add(1, c)
+	b.add(1, d)
+	require.Equal(t, [][]types.TxKey{{a, c, d}}, sent(1))
+
+	// the others are sent at the end of their window
+	b.add(2, a)
+	b.add(2, c)
+	b.add(3, d)
+	assert.Empty(t, sent(2))
+	require.Eventually(t, func() bool { return len(sent(2)) == 1 && len(sent(3)) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, [][]types.TxKey{{a, c}}, sent(2))
+	assert.Equal(t, [][]types.TxKey{{d}}, sent(3))
+
+	// the batch of a removed peer is dropped
+	b.add(4, a)
+	b.remove(4)
+	time.Sleep(40 * time.Millisecond)
+	assert.Empty(t, sent(4))
+}
@@ -1,10 +1,12 @@
 package mempool
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
 	"math"
+	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/types"
@@ -32,6 +34,10 @@ type Mempool interface {
 	// its validity and whether it should be added to the mempool.
 	CheckTx(tx types.Tx, callback func(*abci.Response), txInfo TxInfo) error
 
+	// CheckTxWithContext is like CheckTx, but the call to the application
+	// fails once ctx is done. Then tx is discarded and callback isn't called.
+	CheckTxWithContext(ctx context.Context, tx types.Tx, callback func(*abci.Response), txInfo TxInfo) error
+
 	// RemoveTxByKey removes a transaction, identified by its key,
 	// from the mempool.
 	RemoveTxByKey(txKey types.TxKey) error
@@ -151,6 +157,15 @@ func PostCheckMaxGas(maxGas int64) PostCheckFunc {
 	}
 }
 
+// ContextWithCheckTxTimeout returns ctx with a deadline after timeout, for a
+// CheckTx call to the application, unless timeout is zero.
+func ContextWithCheckTxTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // ErrTxInCache is returned to the client if we saw tx earlier
 var ErrTxInCache = errors.New("tx already exists in cache")
 
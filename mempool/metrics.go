@@ -45,6 +45,10 @@ type Metrics struct {
 	// Number of times transactions are rechecked in the mempool.
 	RecheckTimes metrics.Counter
 
+	// Histogram of the time taken to recheck all the transactions left in
+	// the mempool after a block, in seconds.
+	RecheckDurationSeconds metrics.Histogram
+
 	// AlreadySeenTxs defines the number of transactions that entered the
 	// mempool which were already present in the mempool. This is a good
 	// indicator of the degree of duplication in message gossiping.
@@ -58,6 +62,37 @@ type Metrics struct {
 	// never received a response in time and a new request was made.
 	RerequestedTxs metrics.Counter
 
+	// RebroadcastTxs defines the number of times a transaction still in the
+	// mempool was broadcast again to the peers which hadn't seen it.
+	RebroadcastTxs metrics.Counter
+
+	// DroppedStateMsgs defines the number of SeenTx and WantTx messages
+	// dropped because their peer exceeded its rate.
+	DroppedStateMsgs metrics.Counter
+
+	// DuplicateTxsReceived defines the number of transactions received from
+	// a peer while already in the mempool, by peer_id, and DuplicateTxBytes
+	// their size in bytes.
+	DuplicateTxsReceived metrics.Counter
+	DuplicateTxBytes     metrics.Counter
+
+	// SeenTxReceived defines the number of SeenTx messages received, by
+	// peer_id and whether they were useful, i.e. led to request the
+	// transaction.
+	SeenTxReceived metrics.Counter
+
+	// WantTxReceived defines the number of WantTx messages received, by
+	// peer_id and whether they were useful, i.e. the transaction was sent in
+	// response.
+	WantTxReceived metrics.Counter
+
+	// PeerRequestLatency defines the moving average of the time a peer takes
+	// to send a transaction requested from it, in seconds, by peer_id, and
+	// PeerRequestSuccessRate the moving average of the fraction of the
+	// requests it answers in time.
+	PeerRequestLatency     metrics.Gauge
+	PeerRequestSuccessRate metrics.Gauge
+
 	// Number of connections being actively used for gossiping transactions
 	// (experimental feature).
 	ActiveOutboundConnections metrics.Gauge
@@ -129,6 +164,14 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Help:      "Number of times transactions are rechecked in the mempool.",
 		}, labels).With(labelsAndValues...),
 
+		RecheckDurationSeconds: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "recheck_duration_seconds",
+			Help:      "Time taken to recheck the transactions left in the mempool after a block, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 15),
+		}, labels).With(labelsAndValues...),
+
 		AlreadySeenTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -149,6 +192,62 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "rerequested_txs",
 			Help:      "Number of times a transaction was requested again after a previous request timed out",
 		}, labels).With(labelsAndValues...),
+
+		RebroadcastTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rebroadcasts_total",
+			Help:      "Number of times a pending transaction was broadcast again to the peers which hadn't seen it",
+		}, labels).With(labelsAndValues...),
+
+		DroppedStateMsgs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "dropped_state_msgs",
+			Help:      "Number of SeenTx and WantTx messages dropped because their peer exceeded its rate",
+		}, labels).With(labelsAndValues...),
+
+		DuplicateTxsReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "duplicate_txs_received",
+			Help:      "Number of transactions received from a peer while already in the mempool",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+
+		DuplicateTxBytes: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "duplicate_tx_bytes",
+			Help:      "Size in bytes of the transactions received from a peer while already in the mempool",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+
+		SeenTxReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "seen_tx_received",
+			Help:      "Number of SeenTx messages received, by whether they led to request the transaction",
+		}, append(labels, "peer_id", "useful")).With(labelsAndValues...),
+
+		WantTxReceived: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "want_tx_received",
+			Help:      "Number of WantTx messages received, by whether the transaction was sent in response",
+		}, append(labels, "peer_id", "useful")).With(labelsAndValues...),
+
+		PeerRequestLatency: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_request_latency_seconds",
+			Help:      "Moving average of the time a peer takes to send a requested transaction",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+
+		PeerRequestSuccessRate: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_request_success_rate",
+			Help:      "Moving average of the fraction of the transaction requests a peer answers in time",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
 		ActiveOutboundConnections: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
 			Namespace: namespace,
 			Subsystem: MetricsSubsystem,
@@ -169,9 +268,18 @@ func NopMetrics() *Metrics {
 		ExpiredTxs:                discard.NewCounter(),
 		SuccessfulTxs:             discard.NewCounter(),
 		RecheckTimes:              discard.NewCounter(),
+		RecheckDurationSeconds:    discard.NewHistogram(),
 		AlreadySeenTxs:            discard.NewCounter(),
 		RequestedTxs:              discard.NewCounter(),
 		RerequestedTxs:            discard.NewCounter(),
+		RebroadcastTxs:            discard.NewCounter(),
+		DroppedStateMsgs:          discard.NewCounter(),
+		DuplicateTxsReceived:      discard.NewCounter(),
+		DuplicateTxBytes:          discard.NewCounter(),
+		SeenTxReceived:            discard.NewCounter(),
+		WantTxReceived:            discard.NewCounter(),
+		PeerRequestLatency:        discard.NewGauge(),
+		PeerRequestSuccessRate:    discard.NewGauge(),
 		ActiveOutboundConnections: discard.NewGauge(),
 	}
 }
@@ -2,6 +2,7 @@ package mempool
 
 import (
 	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/types"
 )
 
 // TxInfo are parameters that get passed when attempting to add a tx to the
@@ -15,3 +16,65 @@ type TxInfo struct {
 	// SenderP2PID is the actual p2p.ID of the sender, used e.g. for logging.
 	SenderP2PID p2p.ID
 }
+
+// TxGossipState is the state of the gossip of a transaction known to a
+// mempool reactor which tracks the transactions of its peers, such as the CAT
+// one.
+type TxGossipState struct {
+	Key types.TxKey
+	// InMempool is true if the transaction is in the mempool.
+	InMempool bool
+	// SeenBy are the connected peers which claimed to have the transaction.
+	SeenBy []p2p.ID
+	// RequestedFrom is the peer with an outstanding WantTx for the
+	// transaction, if any.
+	RequestedFrom p2p.ID
+}
+
+// TxStatusCode is the status of a transaction in the mempool.
+type TxStatusCode int
+
+const (
+	// TxStatusUnknown is the status of a transaction which isn't in the
+	// mempool and wasn't recently evicted, rejected or committed.
+	TxStatusUnknown TxStatusCode = iota
+	// TxStatusPending is the status of a transaction in the mempool.
+	TxStatusPending
+	// TxStatusEvicted is the status of a valid transaction recently dropped
+	// from the mempool, because it was full or the transaction expired.
+	TxStatusEvicted
+	// TxStatusRejected is the status of a transaction recently ruled invalid
+	// by the application, on CheckTx or on recheck, or by the mempool.
+	TxStatusRejected
+	// TxStatusCommitted is the status of a transaction recently committed.
+	TxStatusCommitted
+)
+
+// String returns the name of the status, as used by the tx_status RPC.
+func (c TxStatusCode) String() string {
+	switch c {
+	case TxStatusPending:
+		return "PENDING"
+	case TxStatusEvicted:
+		return "EVICTED"
+	case TxStatusRejected:
+		return "REJECTED"
+	case TxStatusCommitted:
+		return "COMMITTED"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// TxStatus is the status of a transaction in the mempool. Height is the height
+// at which the transaction was added to the mempool, evicted, rejected or
+// committed. Code and Log are the code and log of its rejection or execution,
+// or the reason of its eviction. It is reported by the mempools remembering
+// the transactions they recently evicted, rejected or saw committed, such as
+// the CAT one.
+type TxStatus struct {
+	Status TxStatusCode
+	Height int64
+	Code   uint32
+	Log    string
+}
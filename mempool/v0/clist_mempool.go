@@ -2,6 +2,7 @@ package v0
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"sync"
 	"sync/atomic"
@@ -220,6 +221,17 @@ func (mem *CListMempool) CheckTx(
 	cb func(*abci.Response),
 	txInfo mempool.TxInfo,
 ) error {
+	return mem.CheckTxWithContext(context.Background(), tx, cb, txInfo)
+}
+
+// CheckTxWithContext is like CheckTx, but the call to the application fails
+// once ctx is done. Then tx is removed from the cache and cb isn't called.
+func (mem *CListMempool) CheckTxWithContext(
+	ctx context.Context,
+	tx types.Tx,
+	cb func(*abci.Response),
+	txInfo mempool.TxInfo,
+) error {
 
 	mem.updateMtx.RLock()
 	// use defer to unlock mutex because application (*local client*) might panic
@@ -267,9 +279,22 @@ func (mem *CListMempool) CheckTx(
 		return mempool.ErrTxInCache
 	}
 
-	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
+	ctx, cancel := mempool.ContextWithCheckTxTimeout(ctx, mem.config.CheckTxTimeout)
+	reqRes := mem.proxyAppConn.CheckTxAsyncWithContext(ctx, abci.RequestCheckTx{Tx: tx})
 	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, cb))
 
+	if ctx.Done() != nil {
+		// The callback isn't called if the request fails, e.g. once ctx is
+		// done: forget tx then, so that it can be resubmitted.
+		go func() {
+			reqRes.Wait()
+			cancel()
+			if reqRes.Err() != nil {
+				mem.cache.Remove(tx)
+			}
+		}()
+	}
+
 	return nil
 }
 
@@ -2,11 +2,13 @@ package v0
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"fmt"
 	mrand "math/rand"
 	"os"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -280,6 +282,7 @@ func TestMempoolUpdateDoesNotPanicWhenApplicationMissedTx(t *testing.T) {
 		reqRes := abciclient.NewReqRes(abci.ToRequestCheckTx(abci.RequestCheckTx{Tx: tx}))
 		reqRes.Response = abci.ToResponseCheckTx(abci.ResponseCheckTx{Code: abci.CodeTypeOK})
 
+		mockClient.On("CheckTxAsyncWithContext", mock.Anything, mock.Anything).Return(reqRes, nil)
 		mockClient.On("CheckTxAsync", mock.Anything, mock.Anything).Return(reqRes, nil)
 		err := mp.CheckTx(tx, nil, mempool.TxInfo{})
 		require.NoError(t, err)
@@ -723,6 +726,62 @@ func TestMempoolNoCacheOverflow(t *testing.T) {
 	assert.True(t, found == 1)
 }
 
+// blockingCheckTxApp reports the deadline of each CheckTx request, then
+// blocks until released.
+type blockingCheckTxApp struct {
+	*kvstore.Application
+	deadlines chan *time.Time
+	release   chan struct{}
+}
+
+func (app *blockingCheckTxApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	app.deadlines <- req.Deadline
+	<-app.release
+	return app.Application.CheckTx(req)
+}
+
+func TestMempoolCheckTxWithContextCancelled(t *testing.T) {
+	sockPath := fmt.Sprintf("unix:///tmp/echo_%v.sock", cmtrand.Str(6))
+	app := &blockingCheckTxApp{
+		Application: kvstore.NewApplication(),
+		deadlines:   make(chan *time.Time, 2),
+		release:     make(chan struct{}),
+	}
+	_, server := newRemoteApp(t, sockPath, app)
+	t.Cleanup(func() {
+		if err := server.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	cfg := config.ResetTestRoot("mempool_test")
+	cfg.Mempool.CheckTxTimeout = time.Minute
+	mp, cleanup := newMempoolWithAppAndConfig(proxy.NewRemoteClientCreator(sockPath, "socket", true), cfg)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tx := types.Tx("a=1")
+	var called atomic.Bool
+	err := mp.CheckTxWithContext(ctx, tx, func(*abci.Response) { called.Store(true) }, mempool.TxInfo{})
+	require.NoError(t, err)
+
+	// cancel the broadcast while the app checks tx, by the deadline set in
+	// the config
+	deadline := <-app.deadlines
+	require.NotNil(t, deadline)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *deadline, 10*time.Second)
+	cancel()
+
+	// tx is forgotten, so that it can be resubmitted
+	require.Eventually(t, func() bool {
+		return !mp.cache.Has(tx)
+	}, time.Second, 10*time.Millisecond)
+	close(app.release)
+	require.NoError(t, mp.CheckTx(tx, nil, mempool.TxInfo{}))
+	require.NoError(t, mp.FlushAppConn())
+	assert.Equal(t, 1, mp.Size())
+	assert.False(t, called.Load(), "the callback of the cancelled call was called")
+}
+
 // This will non-deterministically catch some concurrency failures like
 // https://github.com/tendermint/tendermint/issues/3509
 // TODO: all of the tests should probably also run using the remote proxy app
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -185,6 +186,17 @@ func (txmp *TxMempool) TxsAvailable() <-chan struct{} { return txmp.txsAvailable
 // the size of tx, and adds tx instead. If no such transactions exist, tx is
 // discarded.
 func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo mempool.TxInfo) error {
+	return txmp.CheckTxWithContext(context.Background(), tx, cb, txInfo)
+}
+
+// CheckTxWithContext is like CheckTx, but the call to the application fails
+// once ctx is done. Then tx is removed from the cache and cb isn't called.
+func (txmp *TxMempool) CheckTxWithContext(
+	ctx context.Context,
+	tx types.Tx,
+	cb func(*abci.Response),
+	txInfo mempool.TxInfo,
+) error {
 
 	// During the initial phase of CheckTx, we do not need to modify any state.
 	// A transaction will not actually be added to the mempool until it survives
@@ -230,7 +242,9 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 	}
 
 	// Invoke an ABCI CheckTx for this transaction.
-	rsp, err := txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: tx})
+	ctx, cancel := mempool.ContextWithCheckTxTimeout(ctx, txmp.config.CheckTxTimeout)
+	defer cancel()
+	rsp, err := txmp.proxyAppConn.CheckTxSyncWithContext(ctx, abci.RequestCheckTx{Tx: tx})
 	if err != nil {
 		txmp.cache.Remove(tx)
 		return err
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -28,6 +29,11 @@ type Reactor struct {
 	mempool     *TxMempool
 	ids         *mempoolIDs
 	traceClient trace.Tracer
+
+	// ctx is cancelled on stop, cancelling the CheckTx calls of the txs
+	// received from peers.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 type mempoolIDs struct {
@@ -102,6 +108,7 @@ func NewReactor(config *cfg.MempoolConfig, mempool *TxMempool, traceClient trace
 		traceClient: traceClient,
 	}
 	memR.BaseReactor = *p2p.NewBaseReactor("Mempool", memR)
+	memR.ctx, memR.cancel = context.WithCancel(context.Background())
 	return memR
 }
 
@@ -140,6 +147,11 @@ func (memR *Reactor) OnStart() error {
 	return nil
 }
 
+// OnStop implements p2p.BaseReactor.
+func (memR *Reactor) OnStop() {
+	memR.cancel()
+}
+
 // GetChannels implements Reactor by returning the list of channels for this
 // reactor.
 func (memR *Reactor) GetChannels() []*p2p.ChannelDescriptor {
@@ -200,7 +212,7 @@ func (memR *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 				len(tx),
 				schema.Download,
 			)
-			err = memR.mempool.CheckTx(ntx, nil, txInfo)
+			err = memR.mempool.CheckTxWithContext(memR.ctx, ntx, nil, txInfo)
 			if errors.Is(err, mempool.ErrTxInCache) {
 				memR.Logger.Debug("Tx already exists in cache", "tx", ntx.String())
 			} else if err != nil {
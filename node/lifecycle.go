@@ -0,0 +1,63 @@
+package node
+
+import (
+	cs "github.com/tendermint/tendermint/consensus"
+	"github.com/tendermint/tendermint/libs/lifecycle"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// lifecycleEventsCapacity is the number of lifecycle events buffered for the
+// watcher. The events are rare, so they are only dropped if it is stuck.
+const lifecycleEventsCapacity = 16
+
+// lifecycleWatcher reacts to the lifecycle events of the node: it keeps the
+// sync metrics up to date and logs the changes of peer connectivity.
+type lifecycleWatcher struct {
+	syncMode     *lifecycle.Subscription[lifecycle.SyncModeChanged]
+	stateSync    *lifecycle.Subscription[lifecycle.StateSyncCompleted]
+	connectivity *lifecycle.Subscription[lifecycle.PeerConnectivityChanged]
+
+	metrics *cs.Metrics
+	logger  log.Logger
+}
+
+// newLifecycleWatcher subscribes to the events of bus. The events published
+// before run is called are buffered.
+func newLifecycleWatcher(bus *lifecycle.Bus, metrics *cs.Metrics, logger log.Logger) *lifecycleWatcher {
+	return &lifecycleWatcher{
+		syncMode:     bus.SyncMode.Subscribe(lifecycleEventsCapacity),
+		stateSync:    bus.StateSyncCompleted.Subscribe(lifecycleEventsCapacity),
+		connectivity: bus.PeerConnectivity.Subscribe(lifecycleEventsCapacity),
+		metrics:      metrics,
+		logger:       logger,
+	}
+}
+
+// run handles the events until quit is closed.
+func (w *lifecycleWatcher) run(quit <-chan struct{}) {
+	for {
+		select {
+		case e := <-w.syncMode.Out():
+			w.metrics.StateSyncing.Set(boolToFloat(e.Mode == lifecycle.SyncModeStateSync))
+			w.metrics.FastSyncing.Set(boolToFloat(e.Mode == lifecycle.SyncModeFastSync))
+			w.logger.Info("Sync mode changed", "mode", e.Mode)
+		case e := <-w.stateSync.Out():
+			w.logger.Info("State sync completed", "height", e.Height)
+		case e := <-w.connectivity.Out():
+			if e.Connected {
+				w.logger.Info("Connected to peers", "peers", e.Peers)
+			} else {
+				w.logger.Info("Lost all peers")
+			}
+		case <-quit:
+			return
+		}
+	}
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
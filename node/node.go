@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	dbm "github.com/cometbft/cometbft-db"
-	"github.com/grafana/pyroscope-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
@@ -27,7 +28,10 @@ import (
 	"github.com/tendermint/tendermint/evidence"
 	"github.com/tendermint/tendermint/pkg/trace"
 
+	cmtdb "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/heartbeat"
 	cmtjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/lifecycle"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	"github.com/tendermint/tendermint/libs/service"
@@ -79,7 +83,24 @@ const readHeaderTimeout = 10 * time.Second
 // specified in the ctx.Config.
 func DefaultDBProvider(ctx *DBContext) (dbm.DB, error) {
 	dbType := dbm.BackendType(ctx.Config.DBBackend)
-	return dbm.NewDB(ctx.ID, dbType, ctx.Config.DBDir())
+	return cmtdb.NewDB(ctx.ID, dbType, ctx.Config.DBDir())
+}
+
+// instrumentedDBProvider wraps the databases returned by dbProvider to record
+// their operations in metrics, and adds them to the size monitor.
+func instrumentedDBProvider(
+	dbProvider DBProvider,
+	metrics *cmtdb.Metrics,
+	sizeMonitor *cmtdb.SizeMonitor,
+) DBProvider {
+	return func(ctx *DBContext) (dbm.DB, error) {
+		db, err := dbProvider(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sizeMonitor.Add(ctx.ID)
+		return cmtdb.NewInstrumentedDB(db, ctx.ID, metrics), nil
+	}
 }
 
 // GenesisDocProvider returns a GenesisDoc.
@@ -107,10 +128,14 @@ func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
 		return nil, fmt.Errorf("failed to load or gen node key %s: %w", config.NodeKeyFile(), err)
 	}
 
+	privValidator := privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	privValidator.SetStateFsync(config.PrivValidatorStateFsync)
+
 	return NewNode(config,
-		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		privValidator,
 		nodeKey,
-		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		proxy.DefaultConcurrentClientCreator(
+			config.ProxyApp, config.ABCI, config.DBDir(), config.ABCICheckTxConcurrency),
 		DefaultGenesisDocProviderFunc(config),
 		DefaultDBProvider,
 		DefaultMetricsProvider(config.Instrumentation),
@@ -118,21 +143,54 @@ func DefaultNewNode(config *cfg.Config, logger log.Logger) (*Node, error) {
 	)
 }
 
-// MetricsProvider returns a consensus, p2p and mempool Metrics.
-type MetricsProvider func(chainID, softwareVersion string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics)
+// MetricsProvider returns a consensus, p2p, mempool, state, store, state sync, fast sync and evidence Metrics.
+type MetricsProvider func(chainID, nodeID, softwareVersion string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics,
+	*sm.Metrics, *store.Metrics, *statesync.Metrics, *bcv0.Metrics, *evidence.Metrics)
 
 // DefaultMetricsProvider returns Metrics build using Prometheus client library
-// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics. The metrics
+// are labelled with the chain ID, the node ID, the software version and the
+// configured global labels.
 func DefaultMetricsProvider(config *cfg.InstrumentationConfig) MetricsProvider {
-	return func(chainID, softwareVersion string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics, *sm.Metrics) {
+	return func(chainID, nodeID, softwareVersion string) (*cs.Metrics, *p2p.Metrics, *mempl.Metrics,
+		*sm.Metrics, *store.Metrics, *statesync.Metrics, *bcv0.Metrics, *evidence.Metrics) {
 		if config.Prometheus {
-			return cs.PrometheusMetrics(config.Namespace, "chain_id", chainID, "version", softwareVersion),
-				p2p.PrometheusMetrics(config.Namespace, "chain_id", chainID, "version", softwareVersion),
-				mempl.PrometheusMetrics(config.Namespace, "chain_id", chainID, "version", softwareVersion),
-				sm.PrometheusMetrics(config.Namespace, "chain_id", chainID, "version", softwareVersion)
+			labels := append(metricsLabels(config, chainID, nodeID), "version", softwareVersion)
+			return cs.PrometheusMetrics(config.Namespace, labels...),
+				p2p.PrometheusMetrics(config.Namespace, labels...),
+				mempl.PrometheusMetrics(config.Namespace, labels...),
+				sm.PrometheusMetrics(config.Namespace, labels...),
+				store.PrometheusMetrics(config.Namespace, labels...),
+				statesync.PrometheusMetrics(config.Namespace, labels...),
+				bcv0.PrometheusMetrics(config.Namespace, labels...),
+				evidence.PrometheusMetrics(config.Namespace, labels...)
 		}
-		return cs.NopMetrics(), p2p.NopMetrics(), mempl.NopMetrics(), sm.NopMetrics()
+		return cs.NopMetrics(), p2p.NopMetrics(), mempl.NopMetrics(), sm.NopMetrics(), store.NopMetrics(),
+			statesync.NopMetrics(), bcv0.NopMetrics(), evidence.NopMetrics()
+	}
+}
+
+// metricsLabels returns the labels, and their values, set on every metric of
+// the node. The global labels are validated when creating the node, see
+// validateMetricsLabels.
+func metricsLabels(config *cfg.InstrumentationConfig, chainID, nodeID string) []string {
+	globalLabels, _ := config.ParseGlobalLabels()
+	return append([]string{"chain_id", chainID, "node_id", nodeID}, globalLabels...)
+}
+
+// validateMetricsLabels returns an error if the labels set on every metric
+// have invalid values, which would fail the registration of the metrics.
+func validateMetricsLabels(config *cfg.InstrumentationConfig, chainID, nodeID string) error {
+	if _, err := config.ParseGlobalLabels(); err != nil {
+		return err
+	}
+	if err := cfg.ValidateMetricLabelValue(chainID); err != nil {
+		return fmt.Errorf("invalid chain_id metric label: %w", err)
+	}
+	if err := cfg.ValidateMetricLabelValue(nodeID); err != nil {
+		return fmt.Errorf("invalid node_id metric label: %w", err)
 	}
+	return nil
 }
 
 // Option sets a parameter for the node.
@@ -193,6 +251,14 @@ func StateProvider(stateProvider statesync.StateProvider) Option {
 	}
 }
 
+// ReplaySessions sets the provider of read-only application sessions used to
+// recompute discarded ABCI responses (see StorageConfig.RecomputeABCIResponses).
+func ReplaySessions(provider sm.ReplaySessionProvider) Option {
+	return func(n *Node) {
+		n.replaySessions = provider
+	}
+}
+
 //------------------------------------------------------------------------------
 
 // Node is the highest level interface to a full CometBFT node.
@@ -215,48 +281,110 @@ type Node struct {
 
 	// services
 	eventBus          *types.EventBus // pub/sub for services
+	lifecycle         *lifecycle.Bus  // lifecycle events of the subsystems
+	lifecycleWatcher  *lifecycleWatcher
 	stateStore        sm.Store
-	blockStore        *store.BlockStore // store the blockchain to disk
-	bcReactor         p2p.Reactor       // for fast-syncing
-	mempoolReactor    p2p.Reactor       // for gossipping transactions
+	blockStore        *store.BlockStore     // store the blockchain to disk
+	pruner            *store.Pruner         // prunes the block store in the background
+	dbSizeMonitor     *cmtdb.SizeMonitor    // reports the size of the databases, if enabled
+	seenBlockStore    *store.SeenBlockStore // optionally stores all proposal blocks
+	bcReactor         p2p.Reactor           // for fast-syncing
+	mempoolReactor    p2p.Reactor           // for gossipping transactions
 	mempool           mempl.Mempool
-	stateSync         bool                    // whether the node should state sync on startup
-	stateSyncReactor  *statesync.Reactor      // for hosting and restoring state sync snapshots
-	stateSyncProvider statesync.StateProvider // provides state data for bootstrapping a node
-	stateSyncGenesis  sm.State                // provides the genesis state for state sync
-	consensusState    *cs.State               // latest consensus state
-	consensusReactor  *cs.Reactor             // for participating in the consensus
-	pexReactor        *pex.Reactor            // for exchanging peer addresses
-	evidencePool      *evidence.Pool          // tracking evidence
-	proxyApp          proxy.AppConns          // connection to the application
-	rpcListeners      []net.Listener          // rpc servers
+	stateSync         bool                     // whether the node should state sync on startup
+	stateSyncReactor  *statesync.Reactor       // for hosting and restoring state sync snapshots
+	stateSyncProvider statesync.StateProvider  // provides state data for bootstrapping a node
+	replaySessions    sm.ReplaySessionProvider // replays blocks to recompute discarded ABCI responses
+	stateSyncGenesis  sm.State                 // provides the genesis state for state sync
+	consensusState    *cs.State                // latest consensus state
+	consensusReactor  *cs.Reactor              // for participating in the consensus
+	pexReactor        *pex.Reactor             // for exchanging peer addresses
+	evidencePool      *evidence.Pool           // tracking evidence
+	proxyApp          proxy.AppConns           // connection to the application
+	rpcListeners      []net.Listener           // rpc servers
 	txIndexer         txindex.TxIndexer
 	blockIndexer      indexer.BlockIndexer
 	indexerService    *txindex.IndexerService
 	prometheusSrv     *http.Server
 	tracer            trace.Tracer
-	pyroscopeProfiler *pyroscope.Profiler
+	profiler          *profiler
 	pyroscopeTracer   *sdktrace.TracerProvider
+
+	// the config with the reloaded values, see ReloadConfig
+	reloadMtx     sync.Mutex
+	runningConfig *cfg.Config
 }
 
-func initDBs(config *cfg.Config, dbProvider DBProvider) (blockStore *store.BlockStore, stateDB dbm.DB, err error) {
-	var blockStoreDB dbm.DB
-	blockStoreDB, err = dbProvider(&DBContext{"blockstore", config})
+func initBlockStore(config *cfg.Config, dbProvider DBProvider) (*store.BlockStore, error) {
+	blockStoreDB, err := dbProvider(&DBContext{"blockstore", config})
 	if err != nil {
-		return
+		return nil, err
 	}
-	blockStore = store.NewBlockStore(blockStoreDB)
+	return store.NewBlockStore(blockStoreDB), nil
+}
 
-	stateDB, err = dbProvider(&DBContext{"state", config})
+func initStateDB(config *cfg.Config, dbProvider DBProvider) (dbm.DB, error) {
+	stateDB, err := dbProvider(&DBContext{"state", config})
 	if err != nil {
+		return nil, err
+	}
+	// refuse to run on a state store with an outdated key layout
+	if err := sm.CheckStoreLayout(stateDB); err != nil {
+		return nil, err
+	}
+	return stateDB, nil
+}
+
+// verifyBlockStore checks the integrity of the block store as configured and
+// logs the corrupt heights found. Corruption doesn't prevent the node from
+// starting; it is up to the operator to decide whether to resync.
+func verifyBlockStore(config *cfg.StorageConfig, blockStore *store.BlockStore, logger log.Logger) {
+	var step int64
+	switch config.VerifyBlockStore {
+	case cfg.VerifyBlockStoreFull:
+		step = 1
+	case cfg.VerifyBlockStoreSampled:
+		step = blockStore.Size() / config.VerifyBlockStoreSamples
+	default:
 		return
 	}
 
-	return
+	logger.Info("Verifying block store", "mode", config.VerifyBlockStore,
+		"base", blockStore.Base(), "height", blockStore.Height())
+	result := blockStore.Verify(blockStore.Base(), blockStore.Height(), step)
+	if result.OK() {
+		logger.Info("Block store verified", "checked", result.Checked)
+		return
+	}
+	for _, corrupt := range result.Corrupt {
+		logger.Error("Corrupt block in block store", "height", corrupt.Height, "reason", corrupt.Err)
+	}
+	logger.Error("Block store verification found corrupt blocks, consider resyncing the node",
+		"checked", result.Checked, "corrupt", len(result.Corrupt))
+}
+
+// checkAppHeight returns a check that the last block height of an application
+// reconnecting matches the one of the node.
+func checkAppHeight(stateStore sm.Store) func(abci.ResponseInfo) error {
+	return func(info abci.ResponseInfo) error {
+		state, err := stateStore.Load()
+		if err != nil {
+			return fmt.Errorf("loading state: %w", err)
+		}
+		if info.LastBlockHeight != state.LastBlockHeight {
+			return fmt.Errorf("application is at height %d but the node is at height %d; restart the node to sync them",
+				info.LastBlockHeight, state.LastBlockHeight)
+		}
+		return nil
+	}
 }
 
-func createAndStartProxyAppConns(clientCreator proxy.ClientCreator, logger log.Logger) (proxy.AppConns, error) {
-	proxyApp := proxy.NewAppConns(clientCreator)
+func createAndStartProxyAppConns(
+	clientCreator proxy.ClientCreator,
+	logger log.Logger,
+	options ...proxy.MultiAppConnOption,
+) (proxy.AppConns, error) {
+	proxyApp := proxy.NewAppConns(clientCreator, options...)
 	proxyApp.SetLogger(logger.With("module", "proxy"))
 	if err := proxyApp.Start(); err != nil {
 		return nil, fmt.Errorf("error starting proxy app connections: %v", err)
@@ -264,8 +392,8 @@ func createAndStartProxyAppConns(clientCreator proxy.ClientCreator, logger log.L
 	return proxyApp, nil
 }
 
-func createAndStartEventBus(logger log.Logger) (*types.EventBus, error) {
-	eventBus := types.NewEventBus()
+func createAndStartEventBus(logger log.Logger, metrics *cmtpubsub.Metrics) (*types.EventBus, error) {
+	eventBus := types.NewEventBus(cmtpubsub.WithMetrics(metrics))
 	eventBus.SetLogger(logger.With("module", "events"))
 	if err := eventBus.Start(); err != nil {
 		return nil, err
@@ -377,6 +505,7 @@ func createMempoolAndMempoolReactor(
 	proxyApp proxy.AppConns,
 	state sm.State,
 	memplMetrics *mempl.Metrics,
+	eventBus *types.EventBus,
 	logger log.Logger,
 	traceClient trace.Tracer,
 ) (mempl.Mempool, p2p.Reactor) {
@@ -388,6 +517,8 @@ func createMempoolAndMempoolReactor(
 			proxyApp.Mempool(),
 			state.LastBlockHeight,
 			mempoolv2.WithMetrics(memplMetrics),
+			mempoolv2.WithTraceClient(traceClient),
+			mempoolv2.WithEventBus(eventBus),
 			mempoolv2.WithPreCheck(sm.TxPreCheck(state)),
 			mempoolv2.WithPostCheck(sm.TxPostCheck(state)),
 		)
@@ -395,10 +526,13 @@ func createMempoolAndMempoolReactor(
 		reactor, err := mempoolv2.NewReactor(
 			mp,
 			&mempoolv2.ReactorOptions{
-				ListenOnly:     !config.Mempool.Broadcast,
-				MaxTxSize:      config.Mempool.MaxTxBytes,
-				TraceClient:    traceClient,
-				MaxGossipDelay: config.Mempool.MaxGossipDelay,
+				ListenOnly:              !config.Mempool.Broadcast,
+				MaxTxSize:               config.Mempool.MaxTxBytes,
+				TraceClient:             traceClient,
+				MaxGossipDelay:          config.Mempool.MaxGossipDelay,
+				MaxInFlightWantsPerPeer: config.Mempool.MaxInFlightWantsPerPeer,
+				StateMsgRate:            config.Mempool.PeerStateMsgRate,
+				StateMsgBurst:           config.Mempool.PeerStateMsgBurst,
 			},
 		)
 		if err != nil {
@@ -464,7 +598,7 @@ func createMempoolAndMempoolReactor(
 }
 
 func createEvidenceReactor(config *cfg.Config, dbProvider DBProvider,
-	stateDB dbm.DB, blockStore *store.BlockStore, logger log.Logger,
+	stateDB dbm.DB, blockStore *store.BlockStore, logger log.Logger, metrics *evidence.Metrics,
 ) (*evidence.Reactor, *evidence.Pool, error) {
 	evidenceDB, err := dbProvider(&DBContext{"evidence", config})
 	if err != nil {
@@ -473,11 +607,11 @@ func createEvidenceReactor(config *cfg.Config, dbProvider DBProvider,
 	evidenceLogger := logger.With("module", "evidence")
 	evidencePool, err := evidence.NewPool(evidenceDB, sm.NewStore(stateDB, sm.StoreOptions{
 		DiscardABCIResponses: config.Storage.DiscardABCIResponses,
-	}), blockStore)
+	}), blockStore, evidence.WithPoolMetrics(metrics))
 	if err != nil {
 		return nil, nil, err
 	}
-	evidenceReactor := evidence.NewReactor(evidencePool)
+	evidenceReactor := evidence.NewReactor(evidencePool, evidence.WithMetrics(metrics))
 	evidenceReactor.SetLogger(evidenceLogger)
 	return evidenceReactor, evidencePool, nil
 }
@@ -488,10 +622,13 @@ func createBlockchainReactor(config *cfg.Config,
 	blockStore *store.BlockStore,
 	fastSync bool,
 	logger log.Logger,
+	metrics *bcv0.Metrics,
 ) (bcReactor p2p.Reactor, err error) {
 	switch config.FastSync.Version {
 	case "v0":
-		bcReactor = bcv0.NewBlockchainReactor(state.Copy(), blockExec, blockStore, fastSync)
+		bcReactor = bcv0.NewBlockchainReactor(state.Copy(), blockExec, blockStore, fastSync,
+			bcv0.WithMetrics(metrics), bcv0.WithMaxBufferedBytes(config.FastSync.MaxBufferedBytes),
+			bcv0.WithCheckpoints(config.FastSync.CheckpointHashes()))
 	case "v1":
 		bcReactor = bcv1.NewBlockchainReactor(state.Copy(), blockExec, blockStore, fastSync)
 	case "v2":
@@ -516,7 +653,16 @@ func createConsensusReactor(config *cfg.Config,
 	eventBus *types.EventBus,
 	consensusLogger log.Logger,
 	traceClient trace.Tracer,
+	seenBlockStore *store.SeenBlockStore,
+	lifecycleBus *lifecycle.Bus,
 ) (*cs.Reactor, *cs.State) {
+	options := []cs.StateOption{
+		cs.StateMetrics(csMetrics),
+		cs.SetTraceClient(traceClient),
+	}
+	if seenBlockStore != nil {
+		options = append(options, cs.SetSeenBlockStore(seenBlockStore))
+	}
 	consensusState := cs.NewState(
 		config.Consensus,
 		state.Copy(),
@@ -524,19 +670,22 @@ func createConsensusReactor(config *cfg.Config,
 		blockStore,
 		mempool,
 		evidencePool,
-		cs.StateMetrics(csMetrics),
-		cs.SetTraceClient(traceClient),
+		options...,
 	)
 	consensusState.SetLogger(consensusLogger)
 	if privValidator != nil {
 		consensusState.SetPrivValidator(privValidator)
 	}
-	consensusReactor := cs.NewReactor(
-		consensusState,
-		waitSync,
+	reactorOptions := []cs.ReactorOption{
 		cs.ReactorMetrics(csMetrics),
 		cs.ReactorTracing(traceClient),
-	)
+		cs.ReactorLifecycle(lifecycleBus),
+	}
+	if config.FastSyncMode {
+		reactorOptions = append(reactorOptions,
+			cs.ReactorResync(config.FastSync.ResyncThreshold, config.FastSync.ResyncAfter))
+	}
+	consensusReactor := cs.NewReactor(consensusState, waitSync, reactorOptions...)
 	consensusReactor.SetLogger(consensusLogger)
 	// services which will be publishing and/or subscribing for messages (events)
 	// consensusReactor will set it on consensusState and blockExecutor
@@ -611,6 +760,8 @@ func createTransport(
 	max := config.P2P.MaxNumInboundPeers + len(splitAndTrimEmpty(config.P2P.UnconditionalPeerIDs, ",", " "))
 	p2p.MultiplexTransportMaxIncomingConnections(max)(transport)
 
+	p2p.MultiplexTransportPeerBandwidthInterval(config.Instrumentation.TracePeerBandwidthInterval)(transport)
+
 	return transport, peerFilters
 }
 
@@ -627,6 +778,7 @@ func createSwitch(config *cfg.Config,
 	nodeKey *p2p.NodeKey,
 	p2pLogger log.Logger,
 	tracer trace.Tracer,
+	lifecycleBus *lifecycle.Bus,
 ) *p2p.Switch {
 	sw := p2p.NewSwitch(
 		config.P2P,
@@ -634,6 +786,7 @@ func createSwitch(config *cfg.Config,
 		p2p.WithMetrics(p2pMetrics),
 		p2p.SwitchPeerFilters(peerFilters...),
 		p2p.WithTracer(tracer),
+		p2p.WithLifecycle(lifecycleBus),
 	)
 	sw.SetLogger(p2pLogger)
 	sw.AddReactor("MEMPOOL", mempoolReactor)
@@ -697,10 +850,13 @@ func createPEXReactorAndAddToSwitch(addrBook pex.AddrBook, config *cfg.Config,
 	return pexReactor
 }
 
-// startStateSync starts an asynchronous state sync process, then switches to fast sync mode.
+// startStateSync starts an asynchronous state sync process, then switches to fast sync mode. If
+// the app doesn't accept any snapshot within the discovery timeout, the node falls back to fast
+// sync from the state returned by fallback.
 func startStateSync(ssR *statesync.Reactor, bcR fastSyncReactor, conR *cs.Reactor,
 	stateProvider statesync.StateProvider, config *cfg.StateSyncConfig, fastSync bool,
 	stateStore sm.Store, blockStore *store.BlockStore, state sm.State,
+	fallback func() (sm.State, error), lifecycleBus *lifecycle.Bus,
 ) error {
 	ssR.Logger.Info("Starting state sync")
 
@@ -723,25 +879,34 @@ func startStateSync(ssR *statesync.Reactor, bcR fastSyncReactor, conR *cs.Reacto
 
 	go func() {
 		state, commit, err := ssR.Sync(stateProvider, config.DiscoveryTime)
-		if err != nil {
+		switch {
+		case errors.Is(err, statesync.ErrDiscoveryTimeout):
+			ssR.Logger.Info("No snapshot was accepted within the discovery timeout, falling back to fast sync",
+				"timeout", config.DiscoveryTimeout)
+			state, err = fallback()
+			if err != nil {
+				ssR.Logger.Error("Failed to fall back from state sync", "err", err)
+				return
+			}
+		case err != nil:
 			ssR.Logger.Error("State sync failed", "err", err)
 			return
-		}
-		err = stateStore.Bootstrap(state)
-		if err != nil {
-			ssR.Logger.Error("Failed to bootstrap node with new state", "err", err)
-			return
-		}
-		err = blockStore.SaveSeenCommit(state.LastBlockHeight, commit)
-		if err != nil {
-			ssR.Logger.Error("Failed to store last seen commit", "err", err)
-			return
+		default:
+			err = stateStore.Bootstrap(state)
+			if err != nil {
+				ssR.Logger.Error("Failed to bootstrap node with new state", "err", err)
+				return
+			}
+			err = blockStore.SaveSeenCommit(state.LastBlockHeight, commit)
+			if err != nil {
+				ssR.Logger.Error("Failed to store last seen commit", "err", err)
+				return
+			}
+			lifecycleBus.StateSyncCompleted.Publish(lifecycle.StateSyncCompleted{Height: state.LastBlockHeight})
 		}
 
 		if fastSync {
-			// FIXME Very ugly to have these metrics bleed through here.
-			conR.Metrics.StateSyncing.Set(0)
-			conR.Metrics.FastSyncing.Set(1)
+			lifecycleBus.SyncMode.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeFastSync})
 			err = bcR.SwitchToFastSync(state)
 			if err != nil {
 				ssR.Logger.Error("Failed to switch to fast sync", "err", err)
@@ -786,22 +951,72 @@ func NewNodeWithContext(ctx context.Context,
 	logger log.Logger,
 	options ...Option,
 ) (*Node, error) {
-	blockStore, stateDB, err := initDBs(config, dbProvider)
+	// The state is loaded first: its genesis doc gives the chain ID labelling
+	// the metrics, including the ones of the databases.
+	stateDB, err := initStateDB(config, dbProvider)
+	if err != nil {
+		return nil, err
+	}
+	state, genDoc, err := LoadStateFromDBOrGenesisDocProvider(stateDB, genesisDocProvider)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateMetricsLabels(config.Instrumentation, genDoc.ChainID, string(nodeKey.ID())); err != nil {
+		return nil, err
+	}
+	metricLabels := metricsLabels(config.Instrumentation, genDoc.ChainID, string(nodeKey.ID()))
+
+	// Optionally record the operations and the size on disk of every database.
+	var dbSizeMonitor *cmtdb.SizeMonitor
+	if config.Instrumentation.Prometheus && config.Instrumentation.DBMetrics {
+		dbMetrics := cmtdb.PrometheusMetrics(config.Instrumentation.Namespace, metricLabels...)
+		dbSizeMonitor = cmtdb.NewSizeMonitor(config.DBDir(), dbMetrics)
+		dbSizeMonitor.SetLogger(logger.With("module", "db"))
+		dbSizeMonitor.Add("state")
+		stateDB = cmtdb.NewInstrumentedDB(stateDB, "state", dbMetrics)
+		dbProvider = instrumentedDBProvider(dbProvider, dbMetrics, dbSizeMonitor)
+	}
+
+	blockStore, err := initBlockStore(config, dbProvider)
+	if err != nil {
+		return nil, err
+	}
+	// older versions could leave a partially written block behind on a crash
+	partialHeight, err := blockStore.DeletePartialBlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete partially written block: %w", err)
+	}
+	if partialHeight > 0 {
+		logger.Info("Deleted partially written block from block store", "module", "store", "height", partialHeight)
+	}
+	verifyBlockStore(config.Storage, blockStore, logger.With("module", "store"))
 
 	stateStore := sm.NewStore(stateDB, sm.StoreOptions{
-		DiscardABCIResponses: config.Storage.DiscardABCIResponses,
+		DiscardABCIResponses:      config.Storage.DiscardABCIResponses,
+		PruningCheckpointInterval: config.Storage.StateCheckpointInterval,
 	})
 
-	state, genDoc, err := LoadStateFromDBOrGenesisDocProvider(stateDB, genesisDocProvider)
+	// create an optional tracer client to collect trace data.
+	traceMetrics := trace.NopMetrics()
+	if config.Instrumentation.Prometheus {
+		traceMetrics = trace.PrometheusMetrics(config.Instrumentation.Namespace, metricLabels...)
+	}
+	tracer, err := trace.NewTracer(
+		config,
+		logger,
+		genDoc.ChainID,
+		string(nodeKey.ID()),
+		trace.WithMetrics(traceMetrics),
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create the proxyApp and establish connections to the ABCI app (consensus, mempool, query).
-	proxyApp, err := createAndStartProxyAppConns(clientCreator, logger)
+	// If the app restarts, resume only once it's back at the height of the node.
+	clientCreator = proxy.WithReconnect(clientCreator, checkAppHeight(stateStore))
+	proxyApp, err := createAndStartProxyAppConns(clientCreator, logger,
+		proxy.WithTraceClient(tracer, config.Instrumentation.TraceCheckTxSampleRate))
 	if err != nil {
 		return nil, err
 	}
@@ -810,7 +1025,11 @@ func NewNodeWithContext(ctx context.Context,
 	// we might need to index the txs of the replayed block as this might not have happened
 	// when the node stopped last time (i.e. the node stopped after it saved the block
 	// but before it indexed the txs, or, endblocker panicked)
-	eventBus, err := createAndStartEventBus(logger)
+	pubsubMetrics := cmtpubsub.NopMetrics()
+	if config.Instrumentation.Prometheus {
+		pubsubMetrics = cmtpubsub.PrometheusMetrics(config.Instrumentation.Namespace, metricLabels...)
+	}
+	eventBus, err := createAndStartEventBus(logger, pubsubMetrics)
 	if err != nil {
 		return nil, err
 	}
@@ -821,20 +1040,36 @@ func NewNodeWithContext(ctx context.Context,
 		return nil, err
 	}
 
+	privvalMetrics := privval.NopMetrics()
+	if config.Instrumentation.Prometheus {
+		privvalMetrics = privval.PrometheusMetrics(config.Instrumentation.Namespace, metricLabels...)
+	}
+
 	// If an address is provided, listen on the socket for a connection from an
 	// external signing process.
+	privValidatorEndpoint := "local"
 	if config.PrivValidatorListenAddr != "" {
 		// FIXME: we should start services inside OnStart
-		privValidator, err = createAndStartPrivValidatorSocketClient(config.PrivValidatorListenAddr, genDoc.ChainID, logger)
+		privValidator, err = createAndStartPrivValidatorSocketClient(config, genDoc.ChainID, privvalMetrics, logger)
 		if err != nil {
 			return nil, fmt.Errorf("error with private validator socket client: %w", err)
 		}
+		privValidatorEndpoint = config.PrivValidatorListenAddr
 	}
 
 	pubKey, err := privValidator.GetPubKey()
 	if err != nil {
 		return nil, fmt.Errorf("can't get pubkey: %w", err)
 	}
+	if rpv, ok := privValidator.(types.KeyRotatingPrivValidator); ok {
+		pubKeys, err := rpv.GetPubKeys()
+		if err != nil {
+			return nil, fmt.Errorf("can't get pubkeys: %w", err)
+		}
+		if len(pubKeys) > 1 {
+			logger.Info("Private validator is rotating its key", "pubKeys", pubKeys)
+		}
+	}
 
 	// Determine whether we should attempt state sync.
 	stateSync := config.StateSync.Enable && !onlyValidatorIsUs(state, pubKey)
@@ -879,24 +1114,34 @@ func NewNodeWithContext(ctx context.Context,
 
 	logNodeStartupInfo(state, pubKey, logger, consensusLogger)
 
-	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID, softwareVersion)
+	csMetrics, p2pMetrics, memplMetrics, smMetrics, storeMetrics, ssMetrics, bcMetrics, evMetrics :=
+		metricsProvider(genDoc.ChainID, string(nodeKey.ID()), softwareVersion)
 
-	// create an optional tracer client to collect trace data.
-	tracer, err := trace.NewTracer(
-		config,
-		logger,
-		genDoc.ChainID,
-		string(nodeKey.ID()),
-	)
-	if err != nil {
-		return nil, err
+	// Blocks below the retain height requested by the application, or older
+	// than the retention duration, are pruned in the background.
+	prunerOptions := []store.PrunerOption{
+		store.WithStatePruner(stateStore),
+		store.WithPruningInterval(config.Storage.PruningInterval),
+		store.WithPruningBudget(config.Storage.PruningHeightsPerSecond, config.Storage.PruningBytesPerSecond),
+		store.WithRetentionDuration(config.Storage.BlockRetentionDuration),
+		store.WithPrunerMetrics(storeMetrics),
+	}
+	if config.Storage.CompactAfterPruning {
+		prunerOptions = append(prunerOptions, store.WithCompaction(config.Storage.CompactionInterval))
 	}
+	if config.Storage.DiscardABCIResponses {
+		// delete the ABCI responses stored before they were discarded
+		prunerOptions = append(prunerOptions, store.WithABCIResponsesPruner(stateStore))
+	}
+	pruner := store.NewPruner(blockStore, prunerOptions...)
+	pruner.SetLogger(logger.With("module", "pruner"))
 
 	// Make MempoolReactor
-	mempool, mempoolReactor := createMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, logger, tracer)
+	mempool, mempoolReactor := createMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, eventBus, logger, tracer)
 
 	// Make Evidence Reactor
-	evidenceReactor, evidencePool, err := createEvidenceReactor(config, dbProvider, stateDB, blockStore, logger)
+	evidenceReactor, evidencePool, err := createEvidenceReactor(config, dbProvider, stateDB, blockStore, logger,
+		evMetrics)
 	if err != nil {
 		return nil, err
 	}
@@ -909,25 +1154,51 @@ func NewNodeWithContext(ctx context.Context,
 		mempool,
 		evidencePool,
 		sm.BlockExecutorWithMetrics(smMetrics),
+		sm.BlockExecutorWithAcceptOnProcessProposalTimeout(config.Consensus.AcceptOnProcessProposalTimeout()),
 		sm.WithBlockStore(blockStore),
+		sm.BlockExecutorWithDiagnosticsDir(config.DBDir()),
+		sm.BlockExecutorWithTraceClient(tracer),
 	)
 
 	// Make BlockchainReactor. Don't start fast sync if we're doing a state sync first.
-	bcReactor, err := createBlockchainReactor(config, state, blockExec, blockStore, fastSync && !stateSync, logger)
+	bcReactor, err := createBlockchainReactor(config, state, blockExec, blockStore, fastSync && !stateSync, logger,
+		bcMetrics)
 	if err != nil {
 		return nil, fmt.Errorf("could not create blockchain reactor: %w", err)
 	}
 
-	// Make ConsensusReactor. Don't enable fully if doing a state sync and/or fast sync first.
-	// FIXME We need to update metrics here, since other reactors don't have access to them.
-	if stateSync {
-		csMetrics.StateSyncing.Set(1)
-	} else if fastSync {
-		csMetrics.FastSyncing.Set(1)
+	// Optionally store all complete proposal blocks for debugging.
+	var seenBlockStore *store.SeenBlockStore
+	if config.Storage.SeenBlocksHeights > 0 {
+		seenBlocksDB, err := dbProvider(&DBContext{"seen_blocks", config})
+		if err != nil {
+			return nil, err
+		}
+		seenBlockStore, err = store.NewSeenBlockStore(seenBlocksDB, config.Storage.SeenBlocksHeights,
+			config.Storage.SeenBlocksMaxCount, config.Storage.SeenBlocksMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("could not create seen block store: %w", err)
+		}
+	}
+
+	// The subsystems publish the changes of their lifecycle, e.g. switching
+	// from fast sync to consensus, on the lifecycle bus.
+	lifecycleBus := lifecycle.NewBus()
+	lifecycleWatcher := newLifecycleWatcher(lifecycleBus, csMetrics, logger.With("module", "lifecycle"))
+	switch {
+	case stateSync:
+		lifecycleBus.SyncMode.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeStateSync})
+	case fastSync:
+		lifecycleBus.SyncMode.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeFastSync})
+	default:
+		lifecycleBus.SyncMode.Publish(lifecycle.SyncModeChanged{Mode: lifecycle.SyncModeConsensus})
 	}
+
+	// Make ConsensusReactor. Don't enable fully if doing a state sync and/or fast sync first.
 	consensusReactor, consensusState := createConsensusReactor(
 		config, state, blockExec, blockStore, mempool, evidencePool,
-		privValidator, csMetrics, stateSync || fastSync, eventBus, consensusLogger, tracer,
+		privval.NewMetricsPrivValidator(privValidator, privValidatorEndpoint, privvalMetrics), csMetrics, stateSync || fastSync, eventBus, consensusLogger, tracer, seenBlockStore,
+		lifecycleBus,
 	)
 
 	logger.Info("Consensus reactor created", "timeout_propose", consensusState.GetState().TimeoutPropose, "timeout_commit", consensusState.GetState().TimeoutCommit)
@@ -935,11 +1206,22 @@ func NewNodeWithContext(ctx context.Context,
 	// FIXME The way we do phased startups (e.g. replay -> fast sync -> consensus) is very messy,
 	// we should clean this whole thing up. See:
 	// https://github.com/tendermint/tendermint/issues/4644
+	ssOptions := []statesync.ReactorOption{statesync.WithMetrics(ssMetrics)}
+	if config.StateSync.ChunkCacheSize > 0 {
+		ssOptions = append(ssOptions, statesync.WithChunkCache(
+			filepath.Join(config.DBDir(), "statesync_chunks"), config.StateSync.ChunkCacheSize))
+	}
+	if config.StateSync.SnapshotPoolSize > 0 {
+		ssOptions = append(ssOptions, statesync.WithSnapshotPool(
+			filepath.Join(config.DBDir(), "statesync_snapshot"), config.StateSync.SnapshotPoolSize,
+			config.StateSync.SnapshotPoolRefreshInterval))
+	}
 	stateSyncReactor := statesync.NewReactor(
 		*config.StateSync,
 		proxyApp.Snapshot(),
 		proxyApp.Query(),
 		config.StateSync.TempDir,
+		ssOptions...,
 	)
 	stateSyncReactor.SetLogger(logger.With("module", "statesync"))
 
@@ -955,7 +1237,7 @@ func NewNodeWithContext(ctx context.Context,
 	p2pLogger := logger.With("module", "p2p")
 	sw := createSwitch(
 		config, transport, p2pMetrics, peerFilters, mempoolReactor, bcReactor,
-		stateSyncReactor, consensusReactor, evidenceReactor, nodeInfo, nodeKey, p2pLogger, tracer,
+		stateSyncReactor, consensusReactor, evidenceReactor, nodeInfo, nodeKey, p2pLogger, tracer, lifecycleBus,
 	)
 
 	err = sw.AddPersistentPeers(splitAndTrimEmpty(config.P2P.PersistentPeers, ",", " "))
@@ -990,16 +1272,17 @@ func NewNodeWithContext(ctx context.Context,
 		pexReactor = createPEXReactorAndAddToSwitch(addrBook, config, sw, logger)
 	}
 
-	if config.RPC.PprofListenAddress != "" {
-		go func() {
-			logger.Info("Starting pprof server", "laddr", config.RPC.PprofListenAddress)
-			//nolint:gosec,nolintlint // G114: Use of net/http serve function that has no support for setting timeouts
-			logger.Error("pprof server error", "err", http.ListenAndServe(config.RPC.PprofListenAddress, nil))
-		}()
-	}
+	profiler := newProfiler(
+		config.RPC.PprofListenAddress,
+		config.Instrumentation.PyroscopeURL,
+		config.Instrumentation.PyroscopeProfileTypes,
+		string(nodeKey.ID()),
+		logger.With("module", "profiler"),
+	)
 
 	node := &Node{
 		config:        config,
+		runningConfig: copyConfig(config),
 		genesisDoc:    genDoc,
 		privValidator: privValidator,
 
@@ -1011,6 +1294,9 @@ func NewNodeWithContext(ctx context.Context,
 
 		stateStore:       stateStore,
 		blockStore:       blockStore,
+		pruner:           pruner,
+		dbSizeMonitor:    dbSizeMonitor,
+		seenBlockStore:   seenBlockStore,
 		bcReactor:        bcReactor,
 		mempoolReactor:   mempoolReactor,
 		mempool:          mempool,
@@ -1026,7 +1312,10 @@ func NewNodeWithContext(ctx context.Context,
 		indexerService:   indexerService,
 		blockIndexer:     blockIndexer,
 		eventBus:         eventBus,
+		lifecycle:        lifecycleBus,
+		lifecycleWatcher: lifecycleWatcher,
 		tracer:           tracer,
+		profiler:         profiler,
 	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
 
@@ -1046,6 +1335,8 @@ func (n *Node) OnStart() error {
 		time.Sleep(genTime.Sub(now))
 	}
 
+	go n.lifecycleWatcher.run(n.Quit())
+
 	// Add private IDs to addrbook to block those peers being added
 	n.addrBook.AddPrivateIDs(splitAndTrimEmpty(n.config.P2P.PrivatePeerIDs, ",", " "))
 
@@ -1064,16 +1355,37 @@ func (n *Node) OnStart() error {
 		n.prometheusSrv = n.startPrometheusServer(n.config.Instrumentation.PrometheusListenAddr)
 	}
 
+	// A pprof server failing to listen doesn't prevent the node from running.
+	if n.config.RPC.PprofListenAddress != "" {
+		if err := n.profiler.ServePprof(""); err != nil {
+			n.Logger.Error("Failed to start the pprof server", "err", err)
+		}
+	}
+
 	if n.config.Instrumentation.PyroscopeURL != "" {
-		profiler, tracer, err := setupPyroscope(
+		tracer, err := setupPyroscopeTracing(
 			n.config.Instrumentation,
 			string(n.nodeKey.ID()),
 		)
 		if err != nil {
 			return err
 		}
-		n.pyroscopeProfiler = profiler
 		n.pyroscopeTracer = tracer
+		if err := n.profiler.StartPyroscope("", nil); err != nil {
+			return err
+		}
+	}
+
+	// Start reporting the size of the databases.
+	if n.dbSizeMonitor != nil {
+		if err := n.dbSizeMonitor.Start(); err != nil {
+			return err
+		}
+	}
+
+	// Start pruning blocks below the retain height in the background.
+	if err := n.pruner.Start(); err != nil {
+		return err
 	}
 
 	// Start the transport.
@@ -1105,8 +1417,18 @@ func (n *Node) OnStart() error {
 		if !ok {
 			return fmt.Errorf("this blockchain reactor does not support switching from state sync")
 		}
+		// The handshake with the app is skipped for state sync, so it's done when falling back.
+		fallback := func() (sm.State, error) {
+			_, err := doHandshake(context.TODO(), n.stateStore, n.stateSyncGenesis, n.blockStore, n.genesisDoc,
+				n.eventBus, n.proxyApp, n.Logger.With("module", "consensus"))
+			if err != nil {
+				return sm.State{}, fmt.Errorf("error during handshake: %w", err)
+			}
+			return n.stateStore.Load()
+		}
 		err := startStateSync(n.stateSyncReactor, bcR, n.consensusReactor, n.stateSyncProvider,
-			n.config.StateSync, n.config.FastSyncMode, n.stateStore, n.blockStore, n.stateSyncGenesis)
+			n.config.StateSync, n.config.FastSyncMode, n.stateStore, n.blockStore, n.stateSyncGenesis, fallback,
+			n.lifecycle)
 		if err != nil {
 			return fmt.Errorf("failed to start state sync: %w", err)
 		}
@@ -1115,22 +1437,43 @@ func (n *Node) OnStart() error {
 	return nil
 }
 
-// OnStop stops the Node. It implements service.Service.
+// OnStop stops the Node. It implements service.Service. The node is stopped in
+// phases, so that the work in flight completes before what it depends on is
+// stopped: the RPC listeners are closed first, then the block being applied,
+// if any, is committed, the messages queued for the peers are flushed, and
+// the databases are closed last. Waiting for the block and the peers is
+// bounded by the shutdown timeout: past it, the databases are left open and
+// the block being applied is replayed on restart.
 func (n *Node) OnStop() {
 	n.BaseService.OnStop()
 
-	n.Logger.Info("Stopping Node")
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), n.config.ShutdownTimeout)
+	defer cancel()
+	phase := func(name string) {
+		n.Logger.Info("Stopping Node", "phase", name, "elapsed", time.Since(start))
+	}
 
-	// first stop the non-reactor services
-	if err := n.eventBus.Stop(); err != nil {
-		n.Logger.Error("Error closing eventBus", "err", err)
+	// first stop accepting new RPC work
+	phase("rpc")
+	for _, l := range n.rpcListeners {
+		n.Logger.Info("Closing rpc listener", "listener", l)
+		if err := l.Close(); err != nil {
+			n.Logger.Error("Error closing listener", "listener", l, "err", err)
+		}
 	}
-	if err := n.indexerService.Stop(); err != nil {
-		n.Logger.Error("Error closing indexerService", "err", err)
+
+	// let the block being applied be committed
+	phase("blocks")
+	committed := n.stopBlockApplication(ctx)
+	if !committed {
+		n.Logger.Error("Timed out waiting for the block being applied to be committed",
+			"timeout", n.config.ShutdownTimeout)
 	}
 
-	// now stop the reactors
-	if err := n.sw.Stop(); err != nil {
+	// now stop the reactors, flushing the messages queued for the peers
+	phase("p2p")
+	if err := n.sw.StopGracefully(ctx); err != nil {
 		n.Logger.Error("Error closing switch", "err", err)
 	}
 
@@ -1140,12 +1483,13 @@ func (n *Node) OnStop() {
 
 	n.isListening = false
 
-	// finally stop the listeners / external services
-	for _, l := range n.rpcListeners {
-		n.Logger.Info("Closing rpc listener", "listener", l)
-		if err := l.Close(); err != nil {
-			n.Logger.Error("Error closing listener", "listener", l, "err", err)
-		}
+	// then the services the reactors use, and the external services
+	phase("services")
+	if err := n.eventBus.Stop(); err != nil {
+		n.Logger.Error("Error closing eventBus", "err", err)
+	}
+	if err := n.indexerService.Stop(); err != nil {
+		n.Logger.Error("Error closing indexerService", "err", err)
 	}
 
 	if pvsc, ok := n.privValidator.(service.Service); ok {
@@ -1161,29 +1505,31 @@ func (n *Node) OnStop() {
 		}
 	}
 
-	if n.blockStore != nil {
-		n.Logger.Info("Closing blockstore")
-		if err := n.blockStore.Close(); err != nil {
-			n.Logger.Error("problem closing blockstore", "err", err)
+	if n.dbSizeMonitor != nil && n.dbSizeMonitor.IsRunning() {
+		if err := n.dbSizeMonitor.Stop(); err != nil {
+			n.Logger.Error("Error stopping database size monitor", "err", err)
 		}
 	}
 
-	if n.stateStore != nil {
-		n.Logger.Info("Closing statestore")
-		if err := n.stateStore.Close(); err != nil {
-			n.Logger.Error("problem closing statestore", "err", err)
+	if n.pruner != nil && n.pruner.IsRunning() {
+		if err := n.pruner.Stop(); err != nil {
+			n.Logger.Error("Error stopping pruner", "err", err)
 		}
 	}
 
+	// finally close the databases, unless a block is still being applied
+	phase("stores")
+	if committed {
+		n.closeStores()
+	} else {
+		n.Logger.Error("Leaving the databases open, a block is still being applied")
+	}
+
 	if n.tracer != nil {
 		n.tracer.Stop()
 	}
 
-	if n.pyroscopeProfiler != nil {
-		if err := n.pyroscopeProfiler.Stop(); err != nil {
-			n.Logger.Error("Pyroscope profiler Stop", "err", err)
-		}
-	}
+	n.profiler.Stop()
 
 	if n.pyroscopeTracer != nil {
 		if err := n.pyroscopeTracer.Shutdown(context.Background()); err != nil {
@@ -1191,6 +1537,57 @@ func (n *Node) OnStop() {
 		}
 	}
 
+	n.Logger.Info("Stopped Node", "duration", time.Since(start), "committed", committed)
+}
+
+// stopBlockApplication stops the fast sync and consensus reactors, which wait
+// for the block they are applying, if any, to be committed. The fast sync
+// reactor is stopped first, since it may switch to consensus. It reports
+// whether they stopped before ctx is done.
+func (n *Node) stopBlockApplication(ctx context.Context) bool {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for _, reactor := range []p2p.Reactor{n.bcReactor, n.consensusReactor} {
+			if !reactor.IsRunning() {
+				continue
+			}
+			if err := reactor.Stop(); err != nil {
+				n.Logger.Error("Error stopping reactor", "reactor", reactor, "err", err)
+			}
+		}
+	}()
+	select {
+	case <-stopped:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// closeStores closes the databases of the node.
+func (n *Node) closeStores() {
+	if n.blockStore != nil {
+		n.Logger.Info("Closing blockstore")
+		if err := n.blockStore.Close(); err != nil {
+			n.Logger.Error("problem closing blockstore", "err", err)
+		}
+	}
+
+	if n.seenBlockStore != nil {
+		n.Logger.Info("Closing seen block store")
+		if err := n.seenBlockStore.Close(); err != nil {
+			n.Logger.Error("problem closing seen block store", "err", err)
+		}
+	}
+
+	if n.stateStore != nil {
+		n.Logger.Info("Closing statestore")
+		if err := n.stateStore.Close(); err != nil {
+			n.Logger.Error("problem closing statestore", "err", err)
+		}
+	}
+
 	if n.evidencePool != nil {
 		n.Logger.Info("Closing evidencestore")
 		if err := n.EvidencePool().Close(); err != nil {
@@ -1205,12 +1602,25 @@ func (n *Node) ConfigureRPC() error {
 	if err != nil {
 		return fmt.Errorf("can't get pubkey: %w", err)
 	}
-	rpccore.SetEnvironment(&rpccore.Environment{
+	var recomputer *sm.ABCIResponsesRecomputer
+	if n.config.Storage.DiscardABCIResponses && n.config.Storage.RecomputeABCIResponses {
+		if n.replaySessions != nil {
+			recomputer = sm.NewABCIResponsesRecomputer(n.replaySessions, n.blockStore, n.stateStore,
+				n.genesisDoc.InitialHeight, n.Logger.With("module", "rpc"))
+		} else {
+			n.Logger.Error("Cannot recompute discarded ABCI responses: no replay session provider configured")
+		}
+	}
+
+	env := &rpccore.Environment{
 		ProxyAppQuery:   n.proxyApp.Query(),
 		ProxyAppMempool: n.proxyApp.Mempool(),
 
 		StateStore:     n.stateStore,
 		BlockStore:     n.blockStore,
+		SeenBlockStore: n.seenBlockStore,
+		Pruner:         n.pruner,
+		Recomputer:     recomputer,
 		EvidencePool:   n.evidencePool,
 		ConsensusState: n.consensusState,
 		P2PPeers:       n.sw,
@@ -1220,14 +1630,34 @@ func (n *Node) ConfigureRPC() error {
 		GenDoc:           n.genesisDoc,
 		TxIndexer:        n.txIndexer,
 		BlockIndexer:     n.blockIndexer,
+		IndexerService:   n.indexerService,
 		ConsensusReactor: n.consensusReactor,
 		EventBus:         n.eventBus,
 		Mempool:          n.mempool,
+		Profiler:         n.profiler,
+		Heartbeats: map[string]*heartbeat.Heartbeat{
+			"consensus": n.consensusState.Heartbeat(),
+			"event_bus": n.eventBus.Heartbeat(),
+		},
 
 		Logger: n.Logger.With("module", "rpc"),
 
-		Config: *n.config.RPC,
-	})
+		Config:         *n.config.RPC,
+		DiagnosticsDir: n.config.DBDir(),
+	}
+	if bcR, ok := n.bcReactor.(*bcv0.BlockchainReactor); ok {
+		env.FastSyncReactor = bcR
+	}
+	if catR, ok := n.mempoolReactor.(*mempoolv2.Reactor); ok {
+		env.MempoolGossip = catR
+	}
+	if toggler, ok := n.tracer.(trace.TableToggler); ok {
+		env.TraceTables = toggler
+	}
+	if filter, ok := n.Logger.(log.LevelFilter); ok {
+		env.LogLevels = filter
+	}
+	rpccore.SetEnvironment(env)
 
 	return rpccore.InitGenesisChunks()
 }
@@ -1273,6 +1703,8 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		)
 		wm.SetLogger(wmLogger)
 		mux.HandleFunc("/websocket", wm.WebsocketHandler)
+		mux.HandleFunc("/livez", rpccore.Livez)
+		mux.HandleFunc("/readyz", rpccore.Readyz)
 		rpcserver.RegisterRPCFuncs(mux, rpccore.Routes, rpcLogger)
 		listener, err := rpcserver.Listen(
 			listenAddr,
@@ -1417,6 +1849,12 @@ func (n *Node) EventBus() *types.EventBus {
 	return n.eventBus
 }
 
+// Lifecycle returns the Node's lifecycle bus, on which its subsystems publish
+// the changes of their lifecycle.
+func (n *Node) Lifecycle() *lifecycle.Bus {
+	return n.lifecycle
+}
+
 // PrivValidator returns the Node's PrivValidator.
 // XXX: for convenience only!
 func (n *Node) PrivValidator() types.PrivValidator {
@@ -1508,7 +1946,8 @@ func makeNodeInfo(
 	}
 
 	if config.Mempool.Version == cfg.MempoolV2 {
-		nodeInfo.Channels = append(nodeInfo.Channels, mempoolv2.MempoolStateChannel)
+		nodeInfo.Channels = append(nodeInfo.Channels, mempoolv2.MempoolStateChannel, mempoolv2.MempoolWantsChannel,
+			mempoolv2.MempoolChunkChannel)
 	}
 
 	lAddr := config.P2P.ExternalAddress
@@ -1585,31 +2024,66 @@ func saveGenesisDoc(db dbm.DB, genDoc *types.GenesisDoc) error {
 }
 
 func createAndStartPrivValidatorSocketClient(
-	listenAddr,
+	config *cfg.Config,
 	chainID string,
+	metrics *privval.Metrics,
 	logger log.Logger,
 ) (types.PrivValidator, error) {
-	pve, err := privval.NewSignerListener(listenAddr, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start private validator: %w", err)
+	listenAddrs := splitAndTrimEmpty(config.PrivValidatorListenAddr, ",", " ")
+	if len(listenAddrs) == 0 {
+		return nil, errors.New("no private validator listen address")
 	}
 
-	pvsc, err := privval.NewSignerClient(pve, chainID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to start private validator: %w", err)
-	}
+	clients := make([]*privval.SignerClient, len(listenAddrs))
+	for i, listenAddr := range listenAddrs {
+		pve, err := privval.NewSignerListener(
+			listenAddr,
+			logger,
+			privval.SignerListenerEndpointPingInterval(config.PrivValidatorPingInterval),
+			privval.SignerListenerEndpointTimeoutPong(config.PrivValidatorPongTimeout),
+			privval.SignerListenerEndpointTimeoutSignRequest(config.PrivValidatorSignTimeout),
+			privval.SignerListenerEndpointMetrics(metrics),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start private validator: %w", err)
+		}
 
-	// try to get a pubkey from private validate first time
-	_, err = pvsc.GetPubKey()
-	if err != nil {
-		return nil, fmt.Errorf("can't get pubkey: %w", err)
+		clients[i], err = privval.NewSignerClient(pve, chainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start private validator: %w", err)
+		}
 	}
 
 	const (
 		retries = 50 // 50 * 100ms = 5s total
 		timeout = 100 * time.Millisecond
 	)
-	pvscWithRetries := privval.NewRetrySignerClient(pvsc, retries, timeout)
+
+	// with several signers, the retries fail over between them. They must all
+	// hold the same key, so that failing over keeps the validator's identity.
+	if len(clients) > 1 {
+		pvfc, err := privval.NewFailoverSignerClient(
+			logger.With("module", "privval"),
+			clients,
+			privval.FailoverSignerClientThreshold(config.PrivValidatorFailoverThreshold),
+			privval.FailoverSignerClientMetrics(metrics),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start private validator: %w", err)
+		}
+		if _, err := pvfc.VerifyPubKeys(); err != nil {
+			return nil, fmt.Errorf("can't verify pubkeys: %w", err)
+		}
+		return privval.NewRetrySignerClient(pvfc, retries, timeout), nil
+	}
+
+	// try to get a pubkey from private validate first time
+	_, err := clients[0].GetPubKey()
+	if err != nil {
+		return nil, fmt.Errorf("can't get pubkey: %w", err)
+	}
+
+	pvscWithRetries := privval.NewRetrySignerClient(clients[0], retries, timeout)
 
 	return pvscWithRetries, nil
 }
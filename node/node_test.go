@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
@@ -13,11 +15,15 @@ import (
 	"github.com/stretchr/testify/require"
 
 	dbm "github.com/cometbft/cometbft-db"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
+	abci "github.com/tendermint/tendermint/abci/types"
 	cfg "github.com/tendermint/tendermint/config"
+	cs "github.com/tendermint/tendermint/consensus"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/evidence"
+	cmtdb "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
 	mempl "github.com/tendermint/tendermint/mempool"
@@ -30,6 +36,7 @@ import (
 	"github.com/tendermint/tendermint/privval"
 	"github.com/tendermint/tendermint/proxy"
 	sm "github.com/tendermint/tendermint/state"
+	ssmocks "github.com/tendermint/tendermint/statesync/mocks"
 	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
 	cmttime "github.com/tendermint/tendermint/types/time"
@@ -78,6 +85,59 @@ func TestNodeStartStop(t *testing.T) {
 	}
 }
 
+func TestNodeStateSyncFallback(t *testing.T) {
+	config := cfg.ResetTestRoot("node_state_sync_fallback_test")
+	defer os.RemoveAll(config.RootDir)
+
+	// a network with no snapshot providers
+	config.StateSync.Enable = true
+	config.StateSync.RPCServers = []string{"127.0.0.1:26657", "127.0.0.1:26658"}
+	config.StateSync.TrustHeight = 1
+	config.StateSync.TrustHash = "0123456789abcdef"
+	config.StateSync.DiscoveryTime = 5 * time.Second
+	config.StateSync.DiscoveryTimeout = time.Second
+	config.FastSyncMode = false
+
+	// state sync is skipped if the node is the only validator, so add another one, which has too
+	// little voting power to prevent the node from producing blocks on its own
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	require.NoError(t, err)
+	val, _ := types.RandValidator(false, 1)
+	genDoc.Validators[0].Power = 10
+	genDoc.Validators = append(genDoc.Validators, types.GenesisValidator{PubKey: val.PubKey, Power: 1})
+
+	nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+	require.NoError(t, err)
+	n, err := NewNode(config,
+		privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+		nodeKey,
+		proxy.DefaultClientCreator(config.ProxyApp, config.ABCI, config.DBDir()),
+		func() (*types.GenesisDoc, error) { return genDoc, nil },
+		DefaultDBProvider,
+		DefaultMetricsProvider(config.Instrumentation),
+		log.TestingLogger(),
+		StateProvider(&ssmocks.StateProvider{}),
+	)
+	require.NoError(t, err)
+	require.True(t, n.stateSync)
+
+	blocksSub, err := n.EventBus().Subscribe(context.Background(), "node_test", types.EventQueryNewBlock)
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer n.Stop() //nolint:errcheck // ignore for tests
+
+	// the node gives up on state sync after the discovery timeout, and syncs from genesis
+	select {
+	case <-blocksSub.Out():
+	case <-blocksSub.Cancelled():
+		t.Fatal("blocksSub was cancelled")
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the node to fall back from state sync")
+	}
+	assert.False(t, n.ConsensusReactor().WaitSync())
+	assert.EqualValues(t, 1, n.BlockStore().Base())
+}
+
 func TestSplitAndTrimEmpty(t *testing.T) {
 	testCases := []struct {
 		s        string
@@ -115,6 +175,23 @@ func TestNodeDelayedStart(t *testing.T) {
 	assert.Equal(t, true, startTime.After(n.GenesisDoc().GenesisTime))
 }
 
+func TestNodePprofAddressInUse(t *testing.T) {
+	config := cfg.ResetTestRoot("node_pprof_in_use_test")
+	defer os.RemoveAll(config.RootDir)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	config.RPC.PprofListenAddress = listener.Addr().String()
+
+	// the node runs without the pprof server
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer n.Stop() //nolint:errcheck // ignore for tests
+	assert.Empty(t, n.profiler.PprofAddress())
+}
+
 func TestNodeSetAppVersion(t *testing.T) {
 	config := cfg.ResetTestRoot("node_app_version_test")
 	defer os.RemoveAll(config.RootDir)
@@ -168,6 +245,40 @@ func TestNodeSetPrivValTCP(t *testing.T) {
 	assert.IsType(t, &privval.RetrySignerClient{}, n.PrivValidator())
 }
 
+// the node gets its pubkey from the second signer if the first is down
+func TestNodeSetPrivValTCPFailover(t *testing.T) {
+	addrs := []string{"tcp://" + testFreeAddr(t), "tcp://" + testFreeAddr(t)}
+
+	config := cfg.ResetTestRoot("node_priv_val_tcp_failover_test")
+	defer os.RemoveAll(config.RootDir)
+	config.BaseConfig.PrivValidatorListenAddr = strings.Join(addrs, ",")
+	config.BaseConfig.PrivValidatorFailoverThreshold = 1
+
+	dialer := privval.DialTCPFn(addrs[1], 100*time.Millisecond, ed25519.GenPrivKey())
+	dialerEndpoint := privval.NewSignerDialerEndpoint(
+		log.TestingLogger(),
+		dialer,
+	)
+
+	signerServer := privval.NewSignerServer(
+		dialerEndpoint,
+		config.ChainID(),
+		types.NewMockPV(),
+	)
+
+	go func() {
+		err := signerServer.Start()
+		if err != nil {
+			panic(err)
+		}
+	}()
+	defer signerServer.Stop() //nolint:errcheck // ignore for tests
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+	assert.IsType(t, &privval.RetrySignerClient{}, n.PrivValidator())
+}
+
 // address without a protocol must result in error
 func TestPrivValidatorListenAddrNoProtocol(t *testing.T) {
 	addrNoPrefix := testFreeAddr(t)
@@ -505,3 +616,168 @@ func state(nVals int, height int64) (sm.State, dbm.DB, []types.PrivValidator) {
 	}
 	return s, stateDB, privVals
 }
+
+func TestDefaultMetricsProviderLabels(t *testing.T) {
+	config := cfg.TestInstrumentationConfig()
+	config.Prometheus = true
+	config.Namespace = "labels_test"
+	config.GlobalLabels = "region=eu-west, operator=acme"
+
+	csMetrics, p2pMetrics, memplMetrics, smMetrics, _, _, _, _ :=
+		DefaultMetricsProvider(config)("test-chain", "test-node", "v1.0.0")
+	csMetrics.Height.Set(1)
+	p2pMetrics.Peers.Set(1)
+	memplMetrics.Size.Set(1)
+	smMetrics.BlockProcessingTime.Observe(1)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	labels := make(map[string]map[string]string)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			pairs := make(map[string]string)
+			for _, pair := range metric.GetLabel() {
+				pairs[pair.GetName()] = pair.GetValue()
+			}
+			labels[family.GetName()] = pairs
+		}
+	}
+
+	expected := map[string]string{
+		"chain_id": "test-chain",
+		"node_id":  "test-node",
+		"version":  "v1.0.0",
+		"region":   "eu-west",
+		"operator": "acme",
+	}
+	for _, name := range []string{
+		"labels_test_" + cs.MetricsSubsystem + "_height",
+		"labels_test_" + p2p.MetricsSubsystem + "_peers",
+		"labels_test_" + mempl.MetricsSubsystem + "_size",
+		"labels_test_" + sm.MetricsSubsystem + "_block_processing_time",
+	} {
+		assert.Equal(t, expected, labels[name], name)
+	}
+}
+
+func TestNodeDBMetricsLabels(t *testing.T) {
+	config := cfg.ResetTestRoot("node_db_metrics_test")
+	defer os.RemoveAll(config.RootDir)
+	config.Instrumentation.Prometheus = true
+	config.Instrumentation.DBMetrics = true
+	config.Instrumentation.Namespace = "db_labels_test"
+
+	n, err := DefaultNewNode(config, log.TestingLogger())
+	require.NoError(t, err)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	var stores []string
+	for _, family := range families {
+		if family.GetName() != "db_labels_test_"+cmtdb.MetricsSubsystem+"_bytes_written" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			pairs := make(map[string]string)
+			for _, pair := range metric.GetLabel() {
+				pairs[pair.GetName()] = pair.GetValue()
+			}
+			assert.Equal(t, n.GenesisDoc().ChainID, pairs["chain_id"])
+			assert.Equal(t, string(n.NodeInfo().ID()), pairs["node_id"])
+			stores = append(stores, pairs["store"])
+		}
+	}
+	assert.Contains(t, stores, "state")
+}
+
+func TestValidateMetricsLabels(t *testing.T) {
+	config := cfg.TestInstrumentationConfig()
+	assert.NoError(t, validateMetricsLabels(config, "test-chain", "test-node"))
+	assert.Error(t, validateMetricsLabels(config, "test\nchain", "test-node"))
+	assert.Error(t, validateMetricsLabels(config, "test-chain", ""))
+	config.GlobalLabels = "region"
+	assert.Error(t, validateMetricsLabels(config, "test-chain", "test-node"))
+}
+
+// blockingCommitApp blocks in Commit, once armed, until released.
+type blockingCommitApp struct {
+	*kvstore.Application
+	armed      atomic.Bool
+	committing chan struct{}
+	release    chan struct{}
+}
+
+func (app *blockingCommitApp) Commit() abci.ResponseCommit {
+	if app.armed.CompareAndSwap(true, false) {
+		close(app.committing)
+		<-app.release
+	}
+	return app.Application.Commit()
+}
+
+// TestNodeStopDuringCommit stops a node while a block is being committed, and
+// checks that stopping waits for the commit and that the node restarts without
+// repairing its WAL.
+func TestNodeStopDuringCommit(t *testing.T) {
+	config := cfg.ResetTestRoot("node_stop_during_commit_test")
+	defer os.RemoveAll(config.RootDir)
+	config.DBBackend = "goleveldb"
+	// the tx index database isn't closed by the node
+	config.TxIndex.Indexer = "null"
+	app := &blockingCommitApp{
+		Application: kvstore.NewApplication(),
+		committing:  make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	newNode := func() *Node {
+		nodeKey, err := p2p.LoadOrGenNodeKey(config.NodeKeyFile())
+		require.NoError(t, err)
+		n, err := NewNode(config,
+			privval.LoadOrGenFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile()),
+			nodeKey,
+			proxy.NewLocalClientCreator(app),
+			DefaultGenesisDocProviderFunc(config),
+			DefaultDBProvider,
+			DefaultMetricsProvider(config.Instrumentation),
+			log.TestingLogger(),
+		)
+		require.NoError(t, err)
+		require.NoError(t, n.Start())
+		return n
+	}
+
+	n := newNode()
+	require.Eventually(t, func() bool { return n.BlockStore().Height() >= 2 }, 10*time.Second, 10*time.Millisecond)
+	app.armed.Store(true)
+	select {
+	case <-app.committing:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a commit")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		assert.NoError(t, n.Stop())
+	}()
+	select {
+	case <-stopped:
+		t.Fatal("the node stopped before the block was committed")
+	case <-time.After(200 * time.Millisecond):
+	}
+	close(app.release)
+	select {
+	case <-stopped:
+	case <-time.After(config.ShutdownTimeout):
+		t.Fatal("timed out stopping the node")
+	}
+	height := n.BlockStore().Height()
+
+	// the node restarts from the committed block, without repairing its WAL
+	n = newNode()
+	defer func() {
+		require.NoError(t, n.Stop())
+	}()
+	require.Eventually(t, func() bool { return n.BlockStore().Height() > height }, 10*time.Second, 10*time.Millisecond)
+	assert.NoFileExists(t, config.Consensus.WalFile()+".CORRUPTED")
+}
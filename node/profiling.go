@@ -0,0 +1,250 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/pyroscope-go"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// pprofShutdownTimeout bounds the time the pprof server waits for the
+// requests in flight, e.g. a CPU profile being captured, when it is stopped.
+const pprofShutdownTimeout = 5 * time.Second
+
+// profiler serves the pprof handlers and runs the pyroscope continuous
+// profiling client. Both can be started, reconfigured and stopped at runtime.
+// It is thread safe.
+type profiler struct {
+	logger log.Logger
+	nodeID string
+
+	// the configured pprof address, pyroscope url and profile types, used
+	// when none are given.
+	pprofAddr             string
+	pyroscopeURL          string
+	pyroscopeProfileTypes []string
+
+	// uploadRate is the rate the profiles are uploaded at, the pyroscope
+	// default if zero.
+	uploadRate time.Duration
+
+	mtx sync.Mutex
+	// the pprof server and the address it was requested on, nil if not
+	// serving.
+	pprofSrv       *http.Server
+	pprofListener  net.Listener
+	pprofRequested string
+	// the pyroscope client and its configuration, nil if not running.
+	pyroscope      *pyroscope.Profiler
+	pyroscopeState pyroscopeState
+}
+
+type pyroscopeState struct {
+	url          string
+	profileTypes []string
+}
+
+func newProfiler(
+	pprofAddr, pyroscopeURL string,
+	pyroscopeProfileTypes []string,
+	nodeID string,
+	logger log.Logger,
+) *profiler {
+	return &profiler{
+		logger:                logger,
+		nodeID:                nodeID,
+		pprofAddr:             pprofAddr,
+		pyroscopeURL:          pyroscopeURL,
+		pyroscopeProfileTypes: pyroscopeProfileTypes,
+	}
+}
+
+// PprofAddress returns the address the pprof handlers are served on, empty if
+// they are not.
+func (p *profiler) PprofAddress() string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.pprofListener == nil {
+		return ""
+	}
+	return p.pprofListener.Addr().String()
+}
+
+// ServePprof serves the pprof handlers on addr, or on the configured address
+// if addr is empty. Serving them on the address they are already served on
+// does nothing, and on another address replaces the server.
+func (p *profiler) ServePprof(addr string) error {
+	if addr == "" {
+		addr = p.pprofAddr
+	}
+	if addr == "" {
+		return errors.New("no pprof address given nor configured with rpc.pprof_laddr")
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.pprofSrv != nil && p.pprofRequested == addr {
+		return nil
+	}
+	p.stopPprof()
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listening for pprof: %w", err)
+	}
+	srv := &http.Server{
+		// the pprof handlers register themselves on the default mux
+		Handler:           http.DefaultServeMux,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+	go func() {
+		if err := srv.Serve(listener); err != http.ErrServerClosed {
+			p.logger.Error("pprof server error", "err", err)
+		}
+	}()
+	p.pprofSrv, p.pprofListener, p.pprofRequested = srv, listener, addr
+	p.logger.Info("Starting pprof server", "laddr", listener.Addr().String())
+	return nil
+}
+
+// StopPprof stops serving the pprof handlers, if they are served.
+func (p *profiler) StopPprof() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.stopPprof()
+}
+
+// stopPprof stops the pprof server. The caller must hold p.mtx.
+func (p *profiler) stopPprof() {
+	if p.pprofSrv == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), pprofShutdownTimeout)
+	defer cancel()
+	if err := p.pprofSrv.Shutdown(ctx); err != nil {
+		p.logger.Error("pprof server Shutdown", "err", err)
+		_ = p.pprofSrv.Close()
+	}
+	p.logger.Info("Stopped pprof server", "laddr", p.pprofListener.Addr().String())
+	p.pprofSrv, p.pprofListener, p.pprofRequested = nil, nil, ""
+}
+
+// Pyroscope returns the url and the profile types of the pyroscope client, an
+// empty url if it is not running.
+func (p *profiler) Pyroscope() (url string, profileTypes []string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.pyroscope == nil {
+		return "", nil
+	}
+	return p.pyroscopeState.url, append([]string(nil), p.pyroscopeState.profileTypes...)
+}
+
+// StartPyroscope starts the pyroscope client, sending the profileTypes to url.
+// The configured url and profile types are used if they are empty. Starting
+// the client with its current configuration does nothing, and with another
+// one restarts it.
+func (p *profiler) StartPyroscope(url string, profileTypes []string) error {
+	if url == "" {
+		url = p.pyroscopeURL
+	}
+	if url == "" {
+		return errors.New("no pyroscope url given nor configured with instrumentation.pyroscope_url")
+	}
+	if len(profileTypes) == 0 {
+		profileTypes = p.pyroscopeProfileTypes
+	}
+	if err := validatePyroscopeProfileTypes(profileTypes); err != nil {
+		return err
+	}
+	state := pyroscopeState{url: url, profileTypes: append([]string(nil), profileTypes...)}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.pyroscope != nil && p.pyroscopeState.equal(state) {
+		return nil
+	}
+	p.stopPyroscope()
+
+	client, err := pyroscope.Start(pyroscope.Config{
+		ApplicationName: "celestia",
+		ServerAddress:   url,
+		Logger:          nil, // use the noop logger by passing nil
+		Tags:            pyroscopeLabels(p.nodeID),
+		ProfileTypes:    toPyroscopeProfiles(profileTypes),
+		UploadRate:      p.uploadRate,
+	})
+	if err != nil {
+		return fmt.Errorf("starting pyroscope: %w", err)
+	}
+	p.pyroscope, p.pyroscopeState = client, state
+	p.logger.Info("Started pyroscope profiling", "url", url, "profile_types", profileTypes)
+	return nil
+}
+
+// StopPyroscope stops the pyroscope client, if it is running. No profile is
+// uploaded once it returns.
+func (p *profiler) StopPyroscope() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.stopPyroscope()
+}
+
+// stopPyroscope stops the pyroscope client. The caller must hold p.mtx.
+func (p *profiler) stopPyroscope() {
+	if p.pyroscope == nil {
+		return
+	}
+	if err := p.pyroscope.Stop(); err != nil {
+		p.logger.Error("Pyroscope profiler Stop", "err", err)
+	}
+	p.logger.Info("Stopped pyroscope profiling", "url", p.pyroscopeState.url)
+	p.pyroscope, p.pyroscopeState = nil, pyroscopeState{}
+}
+
+// Stop stops the pprof server and the pyroscope client.
+func (p *profiler) Stop() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.stopPprof()
+	p.stopPyroscope()
+}
+
+func (s pyroscopeState) equal(other pyroscopeState) bool {
+	if s.url != other.url || len(s.profileTypes) != len(other.profileTypes) {
+		return false
+	}
+	for i, t := range s.profileTypes {
+		if other.profileTypes[i] != t {
+			return false
+		}
+	}
+	return true
+}
+
+func validatePyroscopeProfileTypes(profileTypes []string) error {
+	for _, t := range profileTypes {
+		switch pyroscope.ProfileType(t) {
+		case pyroscope.ProfileCPU,
+			pyroscope.ProfileInuseObjects,
+			pyroscope.ProfileAllocObjects,
+			pyroscope.ProfileInuseSpace,
+			pyroscope.ProfileAllocSpace,
+			pyroscope.ProfileGoroutines,
+			pyroscope.ProfileMutexCount,
+			pyroscope.ProfileMutexDuration,
+			pyroscope.ProfileBlockCount,
+			pyroscope.ProfileBlockDuration:
+		default:
+			return fmt.Errorf("unknown pyroscope profile type %q", t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package node
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestProfilerPprof(t *testing.T) {
+	p := newProfiler("", "", nil, "test_node", log.TestingLogger())
+	t.Cleanup(p.Stop)
+	require.Error(t, p.ServePprof(""))
+	assert.Empty(t, p.PprofAddress())
+
+	require.NoError(t, p.ServePprof("127.0.0.1:0"))
+	addr := p.PprofAddress()
+	require.NotEmpty(t, addr)
+	assert.Equal(t, http.StatusOK, pprofStatus(t, addr))
+
+	// serving the handlers again on the same address keeps the server
+	require.NoError(t, p.ServePprof("127.0.0.1:0"))
+	assert.Equal(t, addr, p.PprofAddress())
+
+	p.StopPprof()
+	p.StopPprof()
+	assert.Empty(t, p.PprofAddress())
+	_, err := http.Get("http://" + addr + "/debug/pprof/")
+	require.Error(t, err)
+}
+
+func pprofStatus(t *testing.T, addr string) int {
+	t.Helper()
+	resp, err := http.Get("http://" + addr + "/debug/pprof/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestProfilerPyroscope checks that the profiles are uploaded while the
+// pyroscope client runs, and no more once it is stopped.
+func TestProfilerPyroscope(t *testing.T) {
+	var uploads atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads.Add(1)
+	}))
+	t.Cleanup(srv.Close)
+
+	p := newProfiler("", srv.URL, []string{"goroutines"}, "test_node", log.TestingLogger())
+	p.uploadRate = 20 * time.Millisecond
+	t.Cleanup(p.Stop)
+	require.Error(t, p.StartPyroscope("", []string{"unknown"}))
+	url, _ := p.Pyroscope()
+	assert.Empty(t, url)
+
+	require.NoError(t, p.StartPyroscope("", nil))
+	client := p.pyroscope
+	require.NoError(t, p.StartPyroscope(srv.URL, []string{"goroutines"}))
+	assert.Same(t, client, p.pyroscope, "starting the client with the same configuration restarted it")
+	url, profileTypes := p.Pyroscope()
+	assert.Equal(t, srv.URL, url)
+	assert.Equal(t, []string{"goroutines"}, profileTypes)
+	require.Eventually(t, func() bool { return uploads.Load() > 0 }, 5*time.Second, 10*time.Millisecond)
+
+	// changing the profile types restarts the client
+	require.NoError(t, p.StartPyroscope("", []string{"goroutines", "cpu"}))
+	assert.NotSame(t, client, p.pyroscope)
+
+	p.StopPyroscope()
+	url, _ = p.Pyroscope()
+	assert.Empty(t, url)
+	stopped := uploads.Load()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, stopped, uploads.Load())
+}
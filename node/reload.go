@@ -0,0 +1,278 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmtflags "github.com/tendermint/tendermint/libs/cli/flags"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
+)
+
+// reloadHook applies the new value of a reloadable config field, read from
+// config, to the running node.
+type reloadHook func(n *Node, config *cfg.Config) error
+
+// reloadableFields are the config fields which can be changed without
+// restarting the node, by their key in the config file, with the hook
+// applying their new value. A field opts in by being added here, with a hook
+// updating the subsystems using it in a thread safe way.
+var reloadableFields = map[string]reloadHook{
+	"log_level":                      reloadLogLevel,
+	"p2p.max_num_inbound_peers":      reloadMaxNumInboundPeers,
+	"p2p.max_num_outbound_peers":     reloadMaxNumOutboundPeers,
+	"instrumentation.tracing_tables": reloadTracingTables,
+}
+
+// ReloadResult lists the config fields changed by a reload, by their key in
+// the config file.
+type ReloadResult struct {
+	// Applied are the changed fields applied to the running node.
+	Applied []string
+	// Rejected are the changed fields which are not reloadable, or whose new
+	// value couldn't be applied. They keep their running value.
+	Rejected []string
+}
+
+// ReloadConfig applies the changes of config, compared to the running
+// config, to the fields which can be changed without restarting the node.
+// The changes of the other fields are rejected and logged. It returns an
+// error, without applying anything, if config is invalid.
+//
+// The config returned by Config is the one the node was started with: the
+// reloaded values are held by the subsystems using them.
+func (n *Node) ReloadConfig(config *cfg.Config) (ReloadResult, error) {
+	var result ReloadResult
+	if err := config.ValidateBasic(); err != nil {
+		return result, fmt.Errorf("invalid config: %w", err)
+	}
+
+	n.reloadMtx.Lock()
+	defer n.reloadMtx.Unlock()
+
+	running := reflect.ValueOf(n.runningConfig).Elem()
+	reloaded := reflect.ValueOf(config).Elem()
+	for _, key := range configDiff(running, reloaded, "") {
+		hook, ok := reloadableFields[key]
+		if !ok {
+			n.Logger.Error("Config field can't be reloaded, restart the node to change it", "field", key)
+			result.Rejected = append(result.Rejected, key)
+			continue
+		}
+		if err := hook(n, config); err != nil {
+			n.Logger.Error("Failed to reload config field", "field", key, "err", err)
+			result.Rejected = append(result.Rejected, key)
+			continue
+		}
+		configField(running, key).Set(configField(reloaded, key))
+		n.Logger.Info("Reloaded config field", "field", key)
+		result.Applied = append(result.Applied, key)
+	}
+	return result, nil
+}
+
+// TrapReloadSignal re-reads configFile each time the process receives SIGHUP,
+// and applies its changes with ReloadConfig, until the node stops. The values
+// set by flags or environment variables when starting the node aren't read
+// again: the ones of the file replace them.
+func (n *Node) TrapReloadSignal(configFile string) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(c)
+		for {
+			select {
+			case <-c:
+				config, err := readConfigFile(configFile, n.config.RootDir)
+				if err != nil {
+					n.Logger.Error("Failed to reload the config", "err", err)
+					continue
+				}
+				if _, err := n.ReloadConfig(config); err != nil {
+					n.Logger.Error("Failed to reload the config", "err", err)
+				}
+			case <-n.Quit():
+				return
+			}
+		}
+	}()
+}
+
+// readConfigFile reads the config of the node with the given home directory
+// from file.
+func readConfigFile(file, home string) (*cfg.Config, error) {
+	config := cfg.DefaultConfig()
+	v := viper.New()
+	v.SetConfigFile(file)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file, err)
+	}
+	if err := v.Unmarshal(config); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", file, err)
+	}
+	config.SetRoot(home)
+	return config, nil
+}
+
+// copyConfig returns a copy of config whose sections can be changed without
+// changing the ones of config.
+func copyConfig(config *cfg.Config) *cfg.Config {
+	c := *config
+	v := reflect.ValueOf(&c).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if f := v.Field(i); f.Kind() == reflect.Ptr && !f.IsNil() && f.Elem().Kind() == reflect.Struct {
+			section := reflect.New(f.Elem().Type())
+			section.Elem().Set(f.Elem())
+			f.Set(section)
+		}
+	}
+	return &c
+}
+
+// configDiff returns the keys of the fields of the config structs a and b
+// whose values differ, sorted.
+func configDiff(a, b reflect.Value, prefix string) []string {
+	var keys []string
+	for i := 0; i < a.NumField(); i++ {
+		key, squash := configKey(a.Type().Field(i))
+		if key == "" && !squash {
+			continue
+		}
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Kind() == reflect.Ptr && fa.Type().Elem().Kind() == reflect.Struct {
+			if fa.IsNil() || fb.IsNil() {
+				if fa.IsNil() != fb.IsNil() {
+					keys = append(keys, prefix+key)
+				}
+				continue
+			}
+			fa, fb = fa.Elem(), fb.Elem()
+		}
+		switch {
+		case squash:
+			keys = append(keys, configDiff(fa, fb, prefix)...)
+		case fa.Kind() == reflect.Struct && fa.Type() != reflect.TypeOf(time.Time{}):
+			keys = append(keys, configDiff(fa, fb, prefix+key+".")...)
+		case !configValuesEqual(fa, fb):
+			keys = append(keys, prefix+key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// configValuesEqual reports whether the config values a and b are equal. An
+// empty slice or map equals a nil one, as both are written the same way in
+// the config file.
+func configValuesEqual(a, b reflect.Value) bool {
+	if k := a.Kind(); (k == reflect.Slice || k == reflect.Map) && a.Len() == 0 && b.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// configField returns the field of the config struct v with the given key.
+func configField(v reflect.Value, key string) reflect.Value {
+	for i := 0; i < v.NumField(); i++ {
+		name, squash := configKey(v.Type().Field(i))
+		f := v.Field(i)
+		if f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.Struct {
+			f = f.Elem()
+		}
+		switch {
+		case squash:
+			if field := configField(f, key); field.IsValid() {
+				return field
+			}
+		case name == key:
+			return f
+		case name != "" && strings.HasPrefix(key, name+".") && f.Kind() == reflect.Struct:
+			return configField(f, strings.TrimPrefix(key, name+"."))
+		}
+	}
+	return reflect.Value{}
+}
+
+// configKey returns the key of a config struct field in the config file, and
+// whether the fields of the struct are squashed in its parent.
+func configKey(field reflect.StructField) (key string, squash bool) {
+	tag := field.Tag.Get("mapstructure")
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "-" || !field.IsExported() {
+		return "", false
+	}
+	return name, opts == "squash"
+}
+
+func reloadLogLevel(n *Node, config *cfg.Config) error {
+	filter, ok := n.Logger.(log.LevelFilter)
+	if !ok {
+		return errors.New("the logger doesn't filter by level")
+	}
+	return cmtflags.SetLogLevel(filter, config.LogLevel, cfg.DefaultLogLevel)
+}
+
+func reloadMaxNumInboundPeers(n *Node, config *cfg.Config) error {
+	n.sw.SetMaxNumInboundPeers(config.P2P.MaxNumInboundPeers)
+	// the unconditional peers aren't reloadable, the running ones still apply
+	unconditional := splitAndTrimEmpty(n.runningConfig.P2P.UnconditionalPeerIDs, ",", " ")
+	n.transport.SetMaxIncomingConnections(config.P2P.MaxNumInboundPeers + len(unconditional))
+	return nil
+}
+
+func reloadMaxNumOutboundPeers(n *Node, config *cfg.Config) error {
+	n.sw.SetMaxNumOutboundPeers(config.P2P.MaxNumOutboundPeers)
+	return nil
+}
+
+// reloadTracingTables starts collecting the tables added to the config, and
+// stops collecting the ones removed from it. The tables enabled or disabled
+// over RPC are left alone.
+func reloadTracingTables(n *Node, config *cfg.Config) error {
+	toggler, ok := n.tracer.(trace.TableToggler)
+	if !ok {
+		return errors.New("the tracer doesn't support changing its tables")
+	}
+	known := make(map[string]bool)
+	for _, table := range schema.AllTables() {
+		known[table] = true
+	}
+	before := splitAndTrimEmpty(n.runningConfig.Instrumentation.TracingTables, ",", " ")
+	after := splitAndTrimEmpty(config.Instrumentation.TracingTables, ",", " ")
+	for _, table := range after {
+		if !known[table] {
+			return fmt.Errorf("unknown trace table %q", table)
+		}
+	}
+	for _, table := range before {
+		if !contains(after, table) {
+			toggler.DisableTable(table)
+		}
+	}
+	for _, table := range after {
+		if !contains(before, table) {
+			toggler.EnableTable(table, 0)
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package node
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// TestNodeReloadSignal checks that on SIGHUP the node applies the changes of
+// the reloadable fields of its config file, and only these.
+func TestNodeReloadSignal(t *testing.T) {
+	config := cfg.ResetTestRoot("node_reload_test")
+	defer os.RemoveAll(config.RootDir)
+
+	logger := log.NewFilter(log.TestingLogger(), log.AllowInfo())
+	n, err := DefaultNewNode(config, logger)
+	require.NoError(t, err)
+	require.NoError(t, n.Start())
+	defer func() {
+		require.NoError(t, n.Stop())
+	}()
+
+	changed := copyConfig(config)
+	changed.LogLevel = "error"
+	changed.P2P.MaxNumOutboundPeers = config.P2P.MaxNumOutboundPeers + 5
+	changed.Moniker = "reloaded"
+	changed.Mempool.Size = config.Mempool.Size * 2
+	file := filepath.Join(config.RootDir, "config", "reload.toml")
+	cfg.WriteConfigFile(file, changed)
+
+	n.TrapReloadSignal(file)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+
+	require.Eventually(t, func() bool {
+		return n.Switch().MaxNumOutboundPeers() == changed.P2P.MaxNumOutboundPeers
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "error", logger.(log.LevelFilter).Levels().Default)
+
+	// the other fields keep their running value
+	n.reloadMtx.Lock()
+	defer n.reloadMtx.Unlock()
+	assert.Equal(t, config.Moniker, n.runningConfig.Moniker)
+	assert.Equal(t, config.Mempool.Size, n.runningConfig.Mempool.Size)
+	assert.Equal(t, config.Moniker, n.Config().Moniker)
+}
+
+func TestNodeReloadConfig(t *testing.T) {
+	config := cfg.ResetTestRoot("node_reload_test")
+	defer os.RemoveAll(config.RootDir)
+
+	n, err := DefaultNewNode(config, log.NewFilter(log.TestingLogger(), log.AllowInfo()))
+	require.NoError(t, err)
+
+	// nothing changed
+	result, err := n.ReloadConfig(copyConfig(config))
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Empty(t, result.Rejected)
+
+	changed := copyConfig(config)
+	changed.LogLevel = "consensus:debug,*:error"
+	changed.P2P.MaxNumInboundPeers = 7
+	changed.Moniker = "reloaded"
+	changed.RPC.ListenAddress = "tcp://127.0.0.1:1"
+	result, err = n.ReloadConfig(changed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log_level", "p2p.max_num_inbound_peers"}, result.Applied)
+	assert.Equal(t, []string{"moniker", "rpc.laddr"}, result.Rejected)
+	assert.Equal(t, 7, n.Switch().MaxNumInboundPeers())
+
+	// the rejected fields are reported again on the next reload, the applied
+	// ones aren't
+	result, err = n.ReloadConfig(changed)
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Equal(t, []string{"moniker", "rpc.laddr"}, result.Rejected)
+
+	// an invalid log level is rejected, the running one is kept
+	changed.LogLevel = "consensus:loud"
+	result, err = n.ReloadConfig(changed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log_level", "moniker", "rpc.laddr"}, result.Rejected)
+
+	// an invalid config isn't applied at all
+	invalid := copyConfig(config)
+	invalid.P2P.MaxNumOutboundPeers = -1
+	_, err = n.ReloadConfig(invalid)
+	require.Error(t, err)
+	assert.Equal(t, config.P2P.MaxNumOutboundPeers, n.Switch().MaxNumOutboundPeers())
+}
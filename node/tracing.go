@@ -12,32 +12,25 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-// setupPyroscope sets up pyroscope profiler and optionally tracing.
-func setupPyroscope(instCfg *config.InstrumentationConfig, nodeID string) (*pyroscope.Profiler, *sdktrace.TracerProvider, error) {
+// setupPyroscopeTracing sets up the tracing of the pyroscope profiles, if
+// enabled. The pyroscope client itself is run by the profiler.
+func setupPyroscopeTracing(instCfg *config.InstrumentationConfig, nodeID string) (*sdktrace.TracerProvider, error) {
+	if !instCfg.PyroscopeTrace {
+		return nil, nil
+	}
 	tp, err := tracerProviderDebug()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-
-	labels := map[string]string{"node_id": nodeID}
-
-	if instCfg.PyroscopeTrace {
-		if _, err = setupTracing(instCfg.PyroscopeURL, labels); err != nil {
-			return nil, nil, err
-		}
-	} else {
-		tp = nil
+	if _, err = setupTracing(instCfg.PyroscopeURL, pyroscopeLabels(nodeID)); err != nil {
+		return nil, err
 	}
+	return tp, nil
+}
 
-	pflr, err := pyroscope.Start(pyroscope.Config{
-		ApplicationName: "celestia",
-		ServerAddress:   instCfg.PyroscopeURL,
-		Logger:          nil, // use the noop logger by passing nil
-		Tags:            labels,
-		ProfileTypes:    toPyroscopeProfiles(instCfg.PyroscopeProfileTypes),
-	})
-
-	return pflr, tp, err
+// pyroscopeLabels returns the labels of the profiles of the node.
+func pyroscopeLabels(nodeID string) map[string]string {
+	return map[string]string{"node_id": nodeID}
 }
 
 func setupTracing(addr string, labels map[string]string) (tp *sdktrace.TracerProvider, err error) {
@@ -117,6 +117,11 @@ type MConnection struct {
 
 	created time.Time // time of creation
 
+	// last round trip time of a ping, in nanoseconds, and time of the last
+	// traffic snapshot, in unix nanoseconds. Accessed atomically.
+	rtt          int64
+	lastSnapshot int64
+
 	_maxPacketMsgSize int
 }
 
@@ -190,6 +195,7 @@ func NewMConnectionWithConfig(
 		config:        config,
 		created:       time.Now(),
 	}
+	mconn.lastSnapshot = mconn.created.UnixNano()
 
 	// Create channels
 	channelsIdx := map[byte]*Channel{}
@@ -426,6 +432,9 @@ func (c *MConnection) sendRoutine() {
 
 	protoWriter := protoio.NewDelimitedWriter(c.bufConnWriter)
 
+	// time the last ping was sent, to measure the round trip time
+	var pingSent time.Time
+
 FOR_LOOP:
 	for {
 		var _n int
@@ -448,6 +457,7 @@ FOR_LOOP:
 				break SELECTION
 			}
 			c.sendMonitor.Update(_n)
+			pingSent = time.Now()
 			c.Logger.Debug("Starting pong timer", "dur", c.config.PongTimeout)
 			c.pongTimer = time.AfterFunc(c.config.PongTimeout, func() {
 				select {
@@ -462,6 +472,9 @@ FOR_LOOP:
 				err = errors.New("pong timeout")
 			} else {
 				c.stopPongTimer()
+				if !pingSent.IsZero() {
+					atomic.StoreInt64(&c.rtt, int64(time.Since(pingSent)))
+				}
 			}
 		case <-c.pong:
 			c.Logger.Debug("Send Pong")
@@ -636,6 +649,7 @@ FOR_LOOP:
 				break FOR_LOOP
 			}
 
+			atomic.AddInt64(&channel.recvBytes, int64(_n))
 			msgBytes, err := channel.recvPacketMsg(*pkt.PacketMsg)
 			if err != nil {
 				if c.IsRunning() {
@@ -645,6 +659,7 @@ FOR_LOOP:
 				break FOR_LOOP
 			}
 			if msgBytes != nil {
+				atomic.AddInt64(&channel.recvMsgs, 1)
 				c.Logger.Debug("Received bytes", "chID", channelID, "msgBytes", msgBytes)
 				// NOTE: This means the reactor.Receive runs in the same thread as the p2p recv routine
 				c.onReceive(channelID, msgBytes)
@@ -719,6 +734,51 @@ func (c *MConnection) Status() ConnectionStatus {
 	return status
 }
 
+// TrafficSnapshot is the traffic of a connection since the previous snapshot.
+type TrafficSnapshot struct {
+	// Interval is the time elapsed since the previous snapshot, or since the
+	// connection was created for the first one.
+	Interval time.Duration
+	// RTT is the round trip time of the last ping answered, or 0 if none was.
+	RTT      time.Duration
+	Channels []ChannelTraffic
+}
+
+// ChannelTraffic is the traffic of a channel since the previous snapshot.
+// Bytes include the framing of the packets.
+type ChannelTraffic struct {
+	ID            byte
+	BytesSent     int64
+	BytesReceived int64
+	MsgsSent      int64
+	MsgsReceived  int64
+	SendQueueSize int
+}
+
+// TrafficSnapshot returns the traffic of each channel since the previous
+// snapshot, and resets the counters. The counters are swapped atomically, so
+// the traffic is counted by exactly one snapshot, but the counters of the
+// channels aren't swapped at the same instant.
+func (c *MConnection) TrafficSnapshot() TrafficSnapshot {
+	now := time.Now().UnixNano()
+	snapshot := TrafficSnapshot{
+		Interval: time.Duration(now - atomic.SwapInt64(&c.lastSnapshot, now)),
+		RTT:      time.Duration(atomic.LoadInt64(&c.rtt)),
+		Channels: make([]ChannelTraffic, len(c.channels)),
+	}
+	for i, channel := range c.channels {
+		snapshot.Channels[i] = ChannelTraffic{
+			ID:            channel.desc.ID,
+			BytesSent:     atomic.SwapInt64(&channel.sentBytes, 0),
+			BytesReceived: atomic.SwapInt64(&channel.recvBytes, 0),
+			MsgsSent:      atomic.SwapInt64(&channel.sentMsgs, 0),
+			MsgsReceived:  atomic.SwapInt64(&channel.recvMsgs, 0),
+			SendQueueSize: channel.loadSendQueueSize(),
+		}
+	}
+	return snapshot
+}
+
 //-----------------------------------------------------------------------------
 
 type ChannelDescriptor struct {
@@ -755,6 +815,10 @@ type Channel struct {
 	sending       []byte
 	recentlySent  int64 // exponential moving average
 
+	// traffic since the last snapshot, accessed atomically
+	sentBytes, recvBytes int64
+	sentMsgs, recvMsgs   int64
+
 	maxPacketMsgPayloadSize int
 
 	Logger log.Logger
@@ -838,6 +902,7 @@ func (ch *Channel) nextPacketMsg() tmp2p.PacketMsg {
 		packet.EOF = true
 		ch.sending = nil
 		atomic.AddInt32(&ch.sendQueueSize, -1) // decrement sendQueueSize
+		atomic.AddInt64(&ch.sentMsgs, 1)
 	} else {
 		packet.EOF = false
 		ch.sending = ch.sending[cmtmath.MinInt(maxSize, len(ch.sending)):]
@@ -851,6 +916,7 @@ func (ch *Channel) writePacketMsgTo(w io.Writer) (n int, err error) {
 	packet := ch.nextPacketMsg()
 	n, err = protoio.NewDelimitedWriter(w).WriteMsg(mustWrapPacket(&packet))
 	atomic.AddInt64(&ch.recentlySent, int64(n))
+	atomic.AddInt64(&ch.sentBytes, int64(n))
 	return
 }
 
@@ -163,6 +163,61 @@ func TestMConnectionStatus(t *testing.T) {
 	assert.Zero(t, status.Channels[0].SendQueueSize)
 }
 
+func TestMConnectionTrafficSnapshot(t *testing.T) {
+	server, client := NetPipe()
+	defer server.Close()
+	defer client.Close()
+
+	receivedCh := make(chan []byte, 3)
+	onReceive := func(chID byte, msgBytes []byte) {
+		receivedCh <- append([]byte(nil), msgBytes...)
+	}
+	onError := func(r interface{}) {}
+	sender := createTestMConnection(client)
+	require.NoError(t, sender.Start())
+	defer sender.Stop() //nolint:errcheck // ignore for tests
+	receiver := createMConnectionWithCallbacks(server, onReceive, onError)
+	require.NoError(t, receiver.Start())
+	defer receiver.Stop() //nolint:errcheck // ignore for tests
+
+	// the last message is split in several packets
+	msgs := [][]byte{[]byte("Ant-Man"), []byte("Spider-Man"), make([]byte, 3*defaultMaxPacketMsgPayloadSize)}
+	for _, msg := range msgs {
+		require.True(t, sender.Send(0x01, msg))
+	}
+	for range msgs {
+		select {
+		case <-receivedCh:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for msgs to be received")
+		}
+	}
+
+	sent, received := sender.TrafficSnapshot(), receiver.TrafficSnapshot()
+	require.Len(t, sent.Channels, 1)
+	require.Len(t, received.Channels, 1)
+	assert.EqualValues(t, 0x01, sent.Channels[0].ID)
+	assert.EqualValues(t, len(msgs), sent.Channels[0].MsgsSent)
+	assert.EqualValues(t, len(msgs), received.Channels[0].MsgsReceived)
+	assert.Greater(t, sent.Channels[0].BytesSent, int64(len(msgs[0])+len(msgs[1])+len(msgs[2])))
+	// both ends count the packets with their framing
+	assert.Equal(t, sent.Channels[0].BytesSent, received.Channels[0].BytesReceived)
+	assert.Zero(t, sent.Channels[0].BytesReceived)
+	assert.Zero(t, received.Channels[0].BytesSent)
+	assert.Positive(t, sent.Interval)
+
+	// the counters are reset by a snapshot
+	time.Sleep(10 * time.Millisecond)
+	next := sender.TrafficSnapshot()
+	assert.Equal(t, ChannelTraffic{ID: 0x01}, next.Channels[0])
+	assert.GreaterOrEqual(t, next.Interval, 10*time.Millisecond)
+
+	// the round trip time is measured once a ping is answered
+	require.Eventually(t, func() bool {
+		return sender.TrafficSnapshot().RTT > 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func TestMConnectionPongTimeoutResultsInError(t *testing.T) {
 	server, client := net.Pipe()
 	defer server.Close()
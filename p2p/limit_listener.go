@@ -0,0 +1,80 @@
+package p2p
+
+import (
+	"net"
+	"sync"
+)
+
+// limitListener is a net.Listener accepting at most limit simultaneous
+// connections, like netutil.LimitListener, but whose limit can be changed
+// while it runs. A limit of 0 means unlimited.
+type limitListener struct {
+	net.Listener
+
+	mtx    sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+	closed bool
+}
+
+func newLimitListener(ln net.Listener, limit int) *limitListener {
+	l := &limitListener{Listener: ln, limit: limit}
+	l.cond = sync.NewCond(&l.mtx)
+	return l
+}
+
+// setLimit changes the limit. Lowering it below the number of open
+// connections closes none of them: new ones wait until enough are closed.
+func (l *limitListener) setLimit(limit int) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.limit = limit
+	l.cond.Broadcast()
+}
+
+// Accept waits for the number of open connections to fall below the limit,
+// then accepts the next connection.
+func (l *limitListener) Accept() (net.Conn, error) {
+	l.mtx.Lock()
+	for !l.closed && l.limit > 0 && l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+	l.mtx.Unlock()
+
+	c, err := l.Listener.Accept()
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+	return &limitListenerConn{Conn: c, release: l.release}, nil
+}
+
+// Close closes the listener, unblocking Accept.
+func (l *limitListener) Close() error {
+	l.mtx.Lock()
+	l.closed = true
+	l.cond.Broadcast()
+	l.mtx.Unlock()
+	return l.Listener.Close()
+}
+
+func (l *limitListener) release() {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.active--
+	l.cond.Broadcast()
+}
+
+type limitListenerConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+func (c *limitListenerConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
@@ -18,7 +18,10 @@ import (
 )
 
 //go:generate ../scripts/mockery_generate.sh Peer
-const metricsTickerDuration = 10 * time.Second
+const (
+	metricsTickerDuration        = 10 * time.Second
+	defaultPeerBandwidthInterval = 10 * time.Second
+)
 
 // Peer is an interface representing a peer connected on a reactor.
 type Peer interface {
@@ -183,6 +186,9 @@ type peer struct {
 	metricsTicker *time.Ticker
 	mlc           *metricsLabelCache
 
+	// interval at which the traffic of the peer is traced
+	bandwidthInterval time.Duration
+
 	// When removal of a peer fails, we set this flag
 	removalAttemptFailed bool
 }
@@ -195,6 +201,14 @@ func WithPeerTracer(t trace.Tracer) PeerOption {
 	}
 }
 
+// WithPeerBandwidthInterval sets the interval at which the traffic of the peer
+// is written to the peer bandwidth table, if it is traced.
+func WithPeerBandwidthInterval(interval time.Duration) PeerOption {
+	return func(p *peer) {
+		p.bandwidthInterval = interval
+	}
+}
+
 func newPeer(
 	pc peerConn,
 	mConfig cmtconn.MConnConfig,
@@ -215,6 +229,8 @@ func newPeer(
 		metrics:       NopMetrics(),
 		mlc:           mlc,
 		traceClient:   trace.NoOpTracer(),
+
+		bandwidthInterval: defaultPeerBandwidthInterval,
 	}
 
 	p.mconn = createMConnection(
@@ -263,6 +279,7 @@ func (p *peer) OnStart() error {
 	}
 
 	go p.metricsReporter()
+	go p.bandwidthReporter()
 	return nil
 }
 
@@ -532,6 +549,36 @@ func (p *peer) metricsReporter() {
 	}
 }
 
+// bandwidthReporter periodically traces the traffic of each channel since the
+// previous snapshot. Idle channels are skipped.
+func (p *peer) bandwidthReporter() {
+	ticker := time.NewTicker(p.bandwidthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// the snapshot is taken even if the table isn't collected, so that
+			// the traffic reported once it is enabled is the recent one
+			snapshot := p.mconn.TrafficSnapshot()
+			if p.traceClient.IsCollecting(schema.PeerBandwidthTable) {
+				writePeerBandwidth(p.traceClient, string(p.ID()), snapshot)
+			}
+		case <-p.Quit():
+			return
+		}
+	}
+}
+
+func writePeerBandwidth(client trace.Tracer, peerID string, snapshot cmtconn.TrafficSnapshot) {
+	for _, ch := range snapshot.Channels {
+		if ch.BytesSent == 0 && ch.BytesReceived == 0 && ch.SendQueueSize == 0 {
+			continue
+		}
+		schema.WritePeerBandwidth(client, peerID, ch.ID, ch.BytesSent, ch.BytesReceived,
+			ch.MsgsSent, ch.MsgsReceived, ch.SendQueueSize, snapshot.RTT, snapshot.Interval)
+	}
+}
+
 //------------------------------------------------------------------
 // helper funcs
 
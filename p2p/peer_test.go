@@ -4,6 +4,8 @@ import (
 	"fmt"
 	golog "log"
 	"net"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,6 +17,8 @@ import (
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/libs/bytes"
 	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
 	"github.com/tendermint/tendermint/proto/tendermint/p2p"
 
 	"github.com/tendermint/tendermint/config"
@@ -75,6 +79,98 @@ func TestPeerSend(t *testing.T) {
 	assert.True(SendEnvelopeShim(p, Envelope{ChannelID: testCh, Message: &p2p.Message{}}, p.Logger))
 }
 
+// bandwidthTracer keeps the peer bandwidth rows written to it, unless
+// disabled.
+type bandwidthTracer struct {
+	mtx      sync.Mutex
+	rows     []schema.PeerBandwidth
+	disabled atomic.Bool
+}
+
+func (b *bandwidthTracer) Write(e trace.Entry) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.rows = append(b.rows, e.(schema.PeerBandwidth))
+}
+
+func (b *bandwidthTracer) IsCollecting(table string) bool {
+	return table == schema.PeerBandwidthTable && !b.disabled.Load()
+}
+
+func (b *bandwidthTracer) Stop() {}
+
+func (b *bandwidthTracer) list() []schema.PeerBandwidth {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return append([]schema.PeerBandwidth(nil), b.rows...)
+}
+
+func TestWritePeerBandwidth(t *testing.T) {
+	tracer := &bandwidthTracer{}
+	writePeerBandwidth(tracer, "peer", cmtconn.TrafficSnapshot{
+		Interval: 10 * time.Second,
+		RTT:      25 * time.Millisecond,
+		Channels: []cmtconn.ChannelTraffic{
+			{ID: 0x20, BytesSent: 1000, BytesReceived: 200, MsgsSent: 4, MsgsReceived: 1},
+			{ID: 0x21}, // idle, skipped
+			{ID: 0x22, SendQueueSize: 3},
+		},
+	})
+	assert.Equal(t, []schema.PeerBandwidth{
+		{
+			PeerID: "peer", Channel: 0x20, BytesSent: 1000, BytesReceived: 200, MsgsSent: 4, MsgsReceived: 1,
+			RTTMilliseconds: 25, IntervalMilliseconds: 10000,
+		},
+		{PeerID: "peer", Channel: 0x22, SendQueueSize: 3, RTTMilliseconds: 25, IntervalMilliseconds: 10000},
+	}, tracer.list())
+}
+
+func TestPeerTracesBandwidth(t *testing.T) {
+	for _, enabledLater := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled_later=%t", enabledLater), func(t *testing.T) {
+			testPeerTracesBandwidth(t, enabledLater)
+		})
+	}
+}
+
+// testPeerTracesBandwidth checks that the traffic of a peer is traced, with
+// the table enabled before the peer starts or once it runs.
+func testPeerTracesBandwidth(t *testing.T, enabledLater bool) {
+	rp := &remotePeer{PrivKey: ed25519.GenPrivKey(), Config: cfg}
+	rp.Start()
+	t.Cleanup(rp.Stop)
+
+	p, err := createOutboundPeerAndPerformHandshake(rp.Addr(), cfg, cmtconn.DefaultMConnConfig())
+	require.NoError(t, err)
+	tracer := &bandwidthTracer{}
+	tracer.disabled.Store(enabledLater)
+	WithPeerTracer(tracer)(p)
+	WithPeerBandwidthInterval(50 * time.Millisecond)(p)
+	require.NoError(t, p.Start())
+	t.Cleanup(func() {
+		if err := p.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	if enabledLater {
+		time.Sleep(100 * time.Millisecond)
+		assert.Empty(t, tracer.list())
+		tracer.disabled.Store(false)
+	}
+
+	require.True(t, SendEnvelopeShim(p, Envelope{ChannelID: testCh, Message: &p2p.Message{}}, p.Logger))
+	require.Eventually(t, func() bool {
+		var msgs int64
+		for _, row := range tracer.list() {
+			assert.Equal(t, string(p.ID()), row.PeerID)
+			assert.EqualValues(t, testCh, row.Channel)
+			msgs += row.MsgsSent
+		}
+		// the message is counted by a single snapshot
+		return msgs == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func createOutboundPeerAndPerformHandshake(
 	addr *NetAddress,
 	config *config.P2PConfig,
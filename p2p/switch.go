@@ -1,14 +1,17 @@
 package p2p
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/cmap"
+	"github.com/tendermint/tendermint/libs/lifecycle"
 	"github.com/tendermint/tendermint/libs/rand"
 	"github.com/tendermint/tendermint/libs/service"
 	"github.com/tendermint/tendermint/p2p/conn"
@@ -53,6 +56,7 @@ type AddrBook interface {
 	AddOurAddress(*NetAddress)
 	OurAddress(*NetAddress) bool
 	MarkGood(ID)
+	MarkBad(*NetAddress, time.Duration)
 	RemoveAddress(*NetAddress)
 	HasAddress(*NetAddress) bool
 	Save()
@@ -82,6 +86,9 @@ type Switch struct {
 	nodeInfo      NodeInfo // our node info
 	nodeKey       *NodeKey // our node privkey
 	addrBook      AddrBook
+	// the peer limits of the config, which can be changed at runtime
+	maxNumInboundPeers  atomic.Int64
+	maxNumOutboundPeers atomic.Int64
 	// peers addresses with whom we'll maintain constant connection
 	persistentPeersAddrs []*NetAddress
 	unconditionalPeerIDs map[ID]struct{}
@@ -96,6 +103,12 @@ type Switch struct {
 	metrics     *Metrics
 	mlc         *metricsLabelCache
 	traceClient trace.Tracer
+
+	// lifecycle is where the switch publishes losing its last peer and
+	// regaining one, connected being true while it has peers.
+	lifecycle    *lifecycle.Bus
+	connectedMtx sync.Mutex
+	connected    bool
 }
 
 // NetAddress returns the address the switch is listening on.
@@ -130,8 +143,12 @@ func NewSwitch(
 		unconditionalPeerIDs: make(map[ID]struct{}),
 		mlc:                  newMetricsLabelCache(),
 		traceClient:          trace.NoOpTracer(),
+		lifecycle:            lifecycle.NewBus(),
 	}
 
+	sw.maxNumInboundPeers.Store(int64(cfg.MaxNumInboundPeers))
+	sw.maxNumOutboundPeers.Store(int64(cfg.MaxNumOutboundPeers))
+
 	// Ensure we have a completely undeterministic PRNG.
 	sw.rng = rand.NewRand()
 
@@ -163,6 +180,11 @@ func WithTracer(tracer trace.Tracer) SwitchOption {
 	return func(sw *Switch) { sw.traceClient = tracer }
 }
 
+// WithLifecycle sets the bus the peer connectivity changes are published on.
+func WithLifecycle(bus *lifecycle.Bus) SwitchOption {
+	return func(sw *Switch) { sw.lifecycle = bus }
+}
+
 //---------------------------------------------------------------------
 // Switch setup
 
@@ -259,8 +281,56 @@ func (sw *Switch) OnStop() {
 	}
 
 	// Stop reactors
+	sw.stopReactors()
+}
+
+// StopGracefully stops the switch like Stop, but stops the reactors first, so
+// that they queue no more messages, and flushes the messages queued for each
+// peer, such as the transactions broadcast by the mempool, before
+// disconnecting it. The connections still flushing once ctx is done are
+// closed, dropping their queued messages.
+func (sw *Switch) StopGracefully(ctx context.Context) error {
+	sw.stopReactors()
+
+	peers := sw.peers.List()
+	var wg sync.WaitGroup
+	for _, p := range peers {
+		wg.Add(1)
+		go func(p Peer) {
+			defer wg.Done()
+			p.FlushStop()
+		}(p)
+	}
+	flushed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+	case <-ctx.Done():
+		sw.Logger.Error("Timed out flushing the messages queued for peers, closing their connections")
+		for _, p := range peers {
+			if err := p.CloseConn(); err != nil {
+				sw.Logger.Debug("error while closing peer connection", "peer", p.ID(), "error", err)
+			}
+		}
+		<-flushed
+	}
+	for _, p := range peers {
+		sw.stopAndRemovePeer(p, nil)
+	}
+
+	return sw.Stop()
+}
+
+// stopReactors stops the reactors still running.
+func (sw *Switch) stopReactors() {
 	sw.Logger.Debug("Switch: Stopping reactors")
 	for _, reactor := range sw.reactors {
+		if !reactor.IsRunning() {
+			continue
+		}
 		if err := reactor.Stop(); err != nil {
 			sw.Logger.Error("error while stopped reactor", "reactor", reactor, "error", err)
 		}
@@ -361,7 +431,24 @@ func (sw *Switch) IsPeerUnconditional(id ID) bool {
 
 // MaxNumOutboundPeers returns a maximum number of outbound peers.
 func (sw *Switch) MaxNumOutboundPeers() int {
-	return sw.config.MaxNumOutboundPeers
+	return int(sw.maxNumOutboundPeers.Load())
+}
+
+// SetMaxNumOutboundPeers changes the maximum number of outbound peers. The
+// peers beyond it are kept, but no more are dialed until there are fewer.
+func (sw *Switch) SetMaxNumOutboundPeers(n int) {
+	sw.maxNumOutboundPeers.Store(int64(n))
+}
+
+// MaxNumInboundPeers returns a maximum number of inbound peers.
+func (sw *Switch) MaxNumInboundPeers() int {
+	return int(sw.maxNumInboundPeers.Load())
+}
+
+// SetMaxNumInboundPeers changes the maximum number of inbound peers. The
+// peers beyond it are kept, but no more are accepted until there are fewer.
+func (sw *Switch) SetMaxNumInboundPeers(n int) {
+	sw.maxNumInboundPeers.Store(int64(n))
 }
 
 // Peers returns the set of peers that are connected to the switch.
@@ -398,6 +485,18 @@ func (sw *Switch) StopPeerForError(peer Peer, reason interface{}) {
 	}
 }
 
+// BanPeerForError disconnects from a peer due to external error, like
+// StopPeerForError, and bans its address for banTime, so that it isn't dialed
+// meanwhile. Persistent peers aren't banned.
+func (sw *Switch) BanPeerForError(peer Peer, reason interface{}, banTime time.Duration) {
+	if sw.addrBook != nil && !peer.IsPersistent() {
+		if addr, err := sw.getPeerAddress(peer); err == nil {
+			sw.addrBook.MarkBad(addr, banTime)
+		}
+	}
+	sw.StopPeerForError(peer, reason)
+}
+
 // getPeerAddress returns the appropriate NetAddress for a given peer,
 // handling both outbound and inbound peers.
 func (sw *Switch) getPeerAddress(peer Peer) (*NetAddress, error) {
@@ -438,6 +537,7 @@ func (sw *Switch) stopAndRemovePeer(peer Peer, reason interface{}) {
 	// https://github.com/tendermint/tendermint/issues/3338
 	if sw.peers.Remove(peer) {
 		sw.metrics.Peers.Add(float64(-1))
+		sw.updateConnectivity()
 	} else {
 		// Removal of the peer has failed. The function above sets a flag within the peer to mark this.
 		// We keep this message here as information to the developer.
@@ -445,6 +545,21 @@ func (sw *Switch) stopAndRemovePeer(peer Peer, reason interface{}) {
 	}
 }
 
+// updateConnectivity publishes a PeerConnectivityChanged event if the switch
+// lost its last peer or connected to a peer while it had none.
+func (sw *Switch) updateConnectivity() {
+	sw.connectedMtx.Lock()
+	defer sw.connectedMtx.Unlock()
+	peers := sw.peers.Size()
+	if connected := peers > 0; connected != sw.connected {
+		sw.connected = connected
+		sw.lifecycle.PeerConnectivity.Publish(lifecycle.PeerConnectivityChanged{
+			Connected: connected,
+			Peers:     peers,
+		})
+	}
+}
+
 // reconnectToPeer tries to reconnect to the addr, first repeatedly
 // with a fixed interval, then with exponential backoff.
 // If no success after all that, it stops trying, and leaves it
@@ -749,12 +864,12 @@ func (sw *Switch) acceptRoutine() {
 		if !sw.IsPeerUnconditional(p.NodeInfo().ID()) {
 			// Ignore connection if we already have enough peers.
 			_, in, _ := sw.NumPeers()
-			if in >= sw.config.MaxNumInboundPeers {
+			if maxIn := sw.MaxNumInboundPeers(); in >= maxIn {
 				sw.Logger.Info(
 					"Ignoring inbound connection: already have enough inbound peers",
 					"address", p.SocketAddr(),
 					"have", in,
-					"max", sw.config.MaxNumInboundPeers,
+					"max", maxIn,
 				)
 
 				sw.transport.Cleanup(p)
@@ -909,6 +1024,7 @@ func (sw *Switch) addPeer(p Peer) error {
 		return err
 	}
 	sw.metrics.Peers.Add(float64(1))
+	sw.updateConnectivity()
 	schema.WritePeerUpdate(sw.traceClient, string(p.ID()), schema.PeerJoin, "")
 
 	// Start all the reactor protocols on the peer.
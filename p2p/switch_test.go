@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/lifecycle"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/p2p/conn"
@@ -191,6 +193,67 @@ func TestSwitches(t *testing.T) {
 		s2.Reactor("bar").(*TestReactor), 200*time.Millisecond, 5*time.Second)
 }
 
+// TestSwitchStopGracefully checks that the messages queued for a peer are
+// sent before the switch stops.
+func TestSwitchStopGracefully(t *testing.T) {
+	s1, s2 := MakeSwitchPair(t, initSwitchFunc)
+	t.Cleanup(func() {
+		if err := s2.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	const numMsgs = 100
+	for i := 0; i < numMsgs; i++ {
+		msg := &p2pproto.PexAddrs{Addrs: []p2pproto.NetAddress{{ID: strconv.Itoa(i)}}}
+		<-s1.BroadcastEnvelope(Envelope{ChannelID: byte(0x00), Message: msg})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, s1.StopGracefully(ctx))
+	assert.False(t, s1.IsRunning())
+	assert.Zero(t, s1.Peers().Size())
+
+	reactor := s2.Reactor("foo").(*TestReactor)
+	require.Eventually(t, func() bool {
+		return len(reactor.getMsgs(byte(0x00))) == numMsgs
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSwitchPeerConnectivity(t *testing.T) {
+	bus := lifecycle.NewBus()
+	events := bus.PeerConnectivity.Subscribe(10)
+	s1, s2 := MakeSwitchPair(t, func(i int, sw *Switch) *Switch {
+		sw = initSwitchFunc(i, sw)
+		if i == 0 {
+			WithLifecycle(bus)(sw)
+		}
+		return sw
+	})
+	t.Cleanup(func() {
+		for _, sw := range []*Switch{s1, s2} {
+			if err := sw.Stop(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	assertConnectivity := func(want lifecycle.PeerConnectivityChanged) {
+		t.Helper()
+		select {
+		case e := <-events.Out():
+			assert.Equal(t, want, e)
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a peer connectivity event")
+		}
+	}
+	assertConnectivity(lifecycle.PeerConnectivityChanged{Connected: true, Peers: 1})
+
+	s1.StopPeerGracefully(s1.Peers().List()[0])
+	assertConnectivity(lifecycle.PeerConnectivityChanged{Connected: false, Peers: 0})
+	assert.Zero(t, events.Dropped())
+}
+
 func assertMsgReceivedWithTimeout(
 	t *testing.T,
 	msg proto.Message,
@@ -487,6 +550,27 @@ func TestSwitchStopPeerForError(t *testing.T) {
 	assert.EqualValues(t, 0, peersMetricValue())
 }
 
+func TestSwitchBanPeerForError(t *testing.T) {
+	sw1, sw2 := MakeSwitchPair(t, initSwitchFunc)
+	t.Cleanup(func() {
+		if err := sw2.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	require.Len(t, sw1.Peers().List(), 1)
+
+	p := sw1.Peers().List()[0]
+	addr, err := sw1.getPeerAddress(p)
+	require.NoError(t, err)
+	book := sw1.addrBook.(*AddrBookMock)
+	require.NoError(t, book.AddAddress(addr, addr))
+
+	sw1.BanPeerForError(p, fmt.Errorf("some err"), time.Hour)
+
+	assert.Len(t, sw1.Peers().List(), 0)
+	assert.False(t, book.HasAddress(addr))
+}
+
 func TestSwitchReconnectsToOutboundPersistentPeer(t *testing.T) {
 	sw := MakeSwitch(cfg, 1, "testing", "123.123.123", initSwitchFunc)
 	err := sw.Start()
@@ -4,10 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync"
 	"time"
 
-	"golang.org/x/net/netutil"
-
 	"github.com/gogo/protobuf/proto"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/protoio"
@@ -134,11 +133,32 @@ func MultiplexTransportMaxIncomingConnections(n int) MultiplexTransportOption {
 	return func(mt *MultiplexTransport) { mt.maxIncomingConnections = n }
 }
 
+// SetMaxIncomingConnections changes the maximum number of simultaneous
+// incoming connections while the transport runs. 0 means unlimited. Lowering
+// it closes no connection.
+func (mt *MultiplexTransport) SetMaxIncomingConnections(n int) {
+	mt.limitMtx.Lock()
+	defer mt.limitMtx.Unlock()
+	mt.maxIncomingConnections = n
+	if mt.limitListener != nil {
+		mt.limitListener.setLimit(n)
+	}
+}
+
+// MultiplexTransportPeerBandwidthInterval sets the interval at which the
+// traffic of the peers is traced. Default: 10s
+func MultiplexTransportPeerBandwidthInterval(interval time.Duration) MultiplexTransportOption {
+	return func(mt *MultiplexTransport) { mt.bandwidthInterval = interval }
+}
+
 // MultiplexTransport accepts and dials tcp connections and upgrades them to
 // multiplexed peers.
 type MultiplexTransport struct {
-	netAddr                NetAddress
-	listener               net.Listener
+	netAddr  NetAddress
+	listener net.Listener
+
+	limitMtx               sync.Mutex
+	limitListener          *limitListener
 	maxIncomingConnections int // see MaxIncomingConnections
 
 	acceptc chan accept
@@ -162,6 +182,8 @@ type MultiplexTransport struct {
 
 	// the tracer is passed to peers for collecting trace data
 	tracer trace.Tracer
+	// interval at which the peers trace their traffic
+	bandwidthInterval time.Duration
 }
 
 // Test multiplexTransport for interface completeness.
@@ -176,17 +198,18 @@ func NewMultiplexTransport(
 	tracer trace.Tracer,
 ) *MultiplexTransport {
 	return &MultiplexTransport{
-		acceptc:          make(chan accept),
-		closec:           make(chan struct{}),
-		dialTimeout:      defaultDialTimeout,
-		filterTimeout:    defaultFilterTimeout,
-		handshakeTimeout: defaultHandshakeTimeout,
-		mConfig:          mConfig,
-		nodeInfo:         nodeInfo,
-		nodeKey:          nodeKey,
-		conns:            NewConnSet(),
-		resolver:         net.DefaultResolver,
-		tracer:           tracer,
+		acceptc:           make(chan accept),
+		closec:            make(chan struct{}),
+		dialTimeout:       defaultDialTimeout,
+		filterTimeout:     defaultFilterTimeout,
+		handshakeTimeout:  defaultHandshakeTimeout,
+		mConfig:           mConfig,
+		nodeInfo:          nodeInfo,
+		nodeKey:           nodeKey,
+		conns:             NewConnSet(),
+		resolver:          net.DefaultResolver,
+		tracer:            tracer,
+		bandwidthInterval: defaultPeerBandwidthInterval,
 	}
 }
 
@@ -258,12 +281,12 @@ func (mt *MultiplexTransport) Listen(addr NetAddress) error {
 		return err
 	}
 
-	if mt.maxIncomingConnections > 0 {
-		ln = netutil.LimitListener(ln, mt.maxIncomingConnections)
-	}
+	mt.limitMtx.Lock()
+	mt.limitListener = newLimitListener(ln, mt.maxIncomingConnections)
+	mt.limitMtx.Unlock()
 
 	mt.netAddr = addr
-	mt.listener = ln
+	mt.listener = mt.limitListener
 
 	go mt.acceptPeers()
 
@@ -534,6 +557,7 @@ func (mt *MultiplexTransport) wrapPeer(
 		cfg.mlc,
 		PeerMetrics(cfg.metrics),
 		WithPeerTracer(mt.tracer),
+		WithPeerBandwidthInterval(mt.bandwidthInterval),
 	)
 
 	return p
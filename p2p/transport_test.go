@@ -185,6 +185,24 @@ func TestTransportMultiplexMaxIncomingConnections(t *testing.T) {
 			t.Errorf("expected i/o timeout error, got %v", err)
 		}
 	}
+
+	// Raising the limit lets one more peer in. The connection of the dialer
+	// which timed out is accepted too, and fails.
+	mt.SetMaxIncomingConnections(maxIncomingConns + 1)
+	errc := make(chan error)
+	go testDialer(*laddr, errc)
+	if err := <-errc; err != nil {
+		t.Errorf("dialer connection failed: %v", err)
+	}
+	accepted := 0
+	for i := 0; i < 2; i++ {
+		if _, err := mt.Accept(peerConfig{}); err == nil {
+			accepted++
+		}
+	}
+	if accepted != 1 {
+		t.Errorf("expected 1 accepted connection, got %d", accepted)
+	}
 }
 
 func TestTransportMultiplexAcceptMultiple(t *testing.T) {
@@ -0,0 +1,273 @@
+package trace
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// dumpChunkSize is the number of rows copied from a buffer at once when
+	// serving a dump, to avoid holding its lock or copying it whole.
+	dumpChunkSize = 256
+
+	dumpShutdownTimeout = 5 * time.Second
+)
+
+// BufferTracer keeps the last rows of each traced table in memory, in a ring
+// buffer per table, and serves them as newline delimited JSON on the trace
+// dump endpoint. It suits ephemeral networks, where the rows can be pulled
+// from the nodes without setting up any collection. It is thread safe.
+type BufferTracer struct {
+	logger log.Logger
+	sampleRates
+	*tableSet
+
+	// buffers maps tables to their buffers. The buffers of the tables enabled
+	// at runtime are created when their first row is buffered.
+	mtx     sync.RWMutex
+	buffers map[string]*ringBuffer
+	// sizes maps tables to their configured buffer size, defaultSize being
+	// used for the others.
+	sizes       map[string]int
+	defaultSize int
+	// queue buffers the rows being written, to avoid blocking the caller
+	// when marshaling them.
+	queue *eventQueue
+
+	token  string
+	server *http.Server
+
+	stopOnce sync.Once
+}
+
+// NewBufferTracer creates a BufferTracer keeping the configured number of rows
+// of each traced table. If a dump address is configured, the server of the
+// dump endpoint is started, as well as the goroutine buffering rows.
+func NewBufferTracer(cfg *config.Config, logger log.Logger, chainID, nodeID string, opts ...Option) (*BufferTracer, error) {
+	rates, err := cfg.Instrumentation.ParseTraceSampleRates()
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := cfg.Instrumentation.ParseTraceDumpBufferSizes()
+	if err != nil {
+		return nil, err
+	}
+
+	buffers := make(map[string]*ringBuffer)
+	tables := splitAndTrimEmpty(cfg.Instrumentation.TracingTables, ",", " ")
+	for _, table := range tables {
+		buffers[table] = newRingBuffer(sizes[table])
+	}
+
+	o := applyOptions(opts)
+	bt := &BufferTracer{
+		logger:      logger,
+		sampleRates: rates,
+		tableSet:    o.tableSet(tables, logger),
+		buffers:     buffers,
+		sizes:       sizes,
+		defaultSize: cfg.Instrumentation.TraceDumpBufferSize,
+		queue:       newEventQueue(chainID, nodeID, cfg.Instrumentation.TraceBufferSize, o.metrics),
+		token:       cfg.Instrumentation.TraceDumpToken,
+	}
+	go bt.queue.run(bt.bufferEvent, nil, bt.IsCollecting)
+
+	if addr := cfg.Instrumentation.TraceDumpAddress; addr != "" {
+		if bt.token == "" {
+			bt.Stop()
+			return nil, fmt.Errorf("trace_dump_token is required to serve the trace dump endpoint")
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/trace_dump", bt.dumpHandler())
+		bt.server = &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			logger.Info("starting trace dump server", "address", addr)
+			if err := bt.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("trace dump server failure", "err", err)
+			}
+		}()
+	}
+	return bt, nil
+}
+
+// Write queues the row to be buffered if its table is traced. It never
+// blocks: rows are dropped if the queue is full, or after Stop.
+func (bt *BufferTracer) Write(e Entry) {
+	if !bt.IsCollecting(e.Table()) {
+		return
+	}
+	bt.queue.push(e)
+}
+
+// Dropped returns the number of rows of table dropped so far.
+func (bt *BufferTracer) Dropped(table string) uint64 {
+	return bt.queue.Dropped(table)
+}
+
+// bufferEvent marshals the row and appends it to the buffer of its table.
+func (bt *BufferTracer) bufferEvent(ev Event[Entry]) {
+	bz, err := json.Marshal(ev)
+	if err != nil {
+		bt.logger.Error("failed to marshal event", "table", ev.Table, "error", err)
+		return
+	}
+	bt.buffer(ev.Table).add(ev.Timestamp, append(bz, '\n'))
+}
+
+// buffer returns the buffer of table, creating it if needed.
+func (bt *BufferTracer) buffer(table string) *ringBuffer {
+	if rb, has := bt.getBuffer(table); has {
+		return rb
+	}
+	bt.mtx.Lock()
+	defer bt.mtx.Unlock()
+	if rb, has := bt.buffers[table]; has {
+		return rb
+	}
+	size, ok := bt.sizes[table]
+	if !ok {
+		size = bt.defaultSize
+	}
+	rb := newRingBuffer(size)
+	bt.buffers[table] = rb
+	return rb
+}
+
+func (bt *BufferTracer) getBuffer(table string) (*ringBuffer, bool) {
+	bt.mtx.RLock()
+	defer bt.mtx.RUnlock()
+	rb, has := bt.buffers[table]
+	return rb, has
+}
+
+// Stop buffers the rows queued so far, within the flush timeout, and stops
+// the dump server. The rows buffered are lost.
+func (bt *BufferTracer) Stop() {
+	bt.stopOnce.Do(func() {
+		bt.queue.stop()
+		if bt.server != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), dumpShutdownTimeout)
+			defer cancel()
+			if err := bt.server.Shutdown(ctx); err != nil {
+				bt.logger.Error("failed to stop trace dump server", "error", err)
+			}
+		}
+	})
+}
+
+// dumpHandler serves the rows buffered for the table parameter as newline
+// delimited JSON, from the oldest to the most recent. If the since parameter,
+// a unix timestamp in milliseconds, is set, only the rows written after it
+// are served. The rows are streamed in chunks, so that a dump neither copies
+// the buffer nor blocks the writes to it.
+func (bt *BufferTracer) dumpHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !bt.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		table := r.FormValue("table")
+		if table == "" {
+			http.Error(w, "no table provided", http.StatusBadRequest)
+			return
+		}
+		rb, has := bt.getBuffer(table)
+		if !has {
+			http.Error(w, fmt.Sprintf("table %s not found", table), http.StatusNotFound)
+			return
+		}
+		var since time.Time
+		if s := r.FormValue("since"); s != "" {
+			ms, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid since: %v", err), http.StatusBadRequest)
+				return
+			}
+			since = time.UnixMilli(ms)
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		// the rows written during the dump aren't served, so that it ends
+		rows := make([]bufferedRow, 0, dumpChunkSize)
+		for seq, end := uint64(0), rb.end(); seq < end; {
+			rows, seq = rb.read(seq, end, rows[:0])
+			for _, row := range rows {
+				if !since.IsZero() && !row.timestamp.After(since) {
+					continue
+				}
+				if _, err := w.Write(row.bz); err != nil {
+					return
+				}
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// authorized reports whether the request carries the bearer token.
+func (bt *BufferTracer) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && bt.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(bt.token)) == 1
+}
+
+// ringBuffer keeps the last rows written to a table, in order. Each row is
+// numbered, so that rows can be read in chunks while others are written.
+type ringBuffer struct {
+	mtx  sync.Mutex
+	rows []bufferedRow
+	next uint64 // number of the next row written
+}
+
+type bufferedRow struct {
+	timestamp time.Time
+	bz        []byte // JSON event, newline terminated
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{rows: make([]bufferedRow, size)}
+}
+
+// add appends a row, overwriting the oldest one if the buffer is full.
+func (rb *ringBuffer) add(timestamp time.Time, bz []byte) {
+	rb.mtx.Lock()
+	defer rb.mtx.Unlock()
+	rb.rows[rb.next%uint64(len(rb.rows))] = bufferedRow{timestamp: timestamp, bz: bz}
+	rb.next++
+}
+
+// end returns the number of the next row written.
+func (rb *ringBuffer) end() uint64 {
+	rb.mtx.Lock()
+	defer rb.mtx.Unlock()
+	return rb.next
+}
+
+// read appends to rows, up to its capacity, the rows numbered from seq to end,
+// or from the oldest row kept if seq was overwritten. It returns the rows and
+// the number of the next row to read.
+func (rb *ringBuffer) read(seq, end uint64, rows []bufferedRow) ([]bufferedRow, uint64) {
+	rb.mtx.Lock()
+	defer rb.mtx.Unlock()
+	size := uint64(len(rb.rows))
+	if rb.next > size && seq < rb.next-size {
+		seq = rb.next - size
+	}
+	for ; seq < end && len(rows) < cap(rows); seq++ {
+		rows = append(rows, rb.rows[seq%size])
+	}
+	return rows, seq
+}
@@ -0,0 +1,148 @@
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const testDumpToken = "secret"
+
+func setupBufferTracer(t *testing.T, bufferSize int) (*BufferTracer, *httptest.Server) {
+	cfg := config.DefaultConfig()
+	cfg.Instrumentation.TraceType = "buffer"
+	cfg.Instrumentation.TracingTables = testEventTable
+	cfg.Instrumentation.TraceDumpBufferSize = bufferSize
+	cfg.Instrumentation.TraceDumpToken = testDumpToken
+
+	client, err := NewBufferTracer(cfg, log.NewNopLogger(), "test_chain", "test_node")
+	require.NoError(t, err)
+	t.Cleanup(client.Stop)
+	server := httptest.NewServer(client.dumpHandler())
+	t.Cleanup(server.Close)
+	return client, server
+}
+
+// writeEvents writes the events numbered from first to last, and waits for
+// them to be buffered.
+func writeEvents(t *testing.T, client *BufferTracer, first, last int) {
+	for i := first; i <= last; i++ {
+		client.Write(testEvent{City: "Annecy", Length: i})
+	}
+	require.Eventually(t, func() bool {
+		return client.buffer(testEventTable).end() == uint64(last+1)
+	}, 5*time.Second, time.Millisecond)
+}
+
+func dump(t *testing.T, server *httptest.Server, token, query string) (int, []byte) {
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/trace_dump?"+query, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, body
+}
+
+// dumpLengths fetches the rows of the test table and returns their lengths.
+func dumpLengths(t *testing.T, server *httptest.Server, query string) []int {
+	status, body := dump(t, server, testDumpToken, query)
+	require.Equal(t, http.StatusOK, status, string(body))
+	lengths := []int{}
+	for _, line := range bytes.Split(bytes.TrimSuffix(body, []byte("\n")), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var ev Event[testEvent]
+		require.NoError(t, json.Unmarshal(line, &ev))
+		assert.Equal(t, "test_chain", ev.ChainID)
+		assert.Equal(t, testEventTable, ev.Table)
+		lengths = append(lengths, ev.Msg.Length)
+	}
+	return lengths
+}
+
+func sequence(first, last int) []int {
+	s := make([]int, 0, last-first+1)
+	for i := first; i <= last; i++ {
+		s = append(s, i)
+	}
+	return s
+}
+
+func TestBufferTracerDump(t *testing.T) {
+	client, server := setupBufferTracer(t, 1000)
+	// more rows than a chunk, to stream several ones
+	writeEvents(t, client, 0, dumpChunkSize+10)
+
+	assert.Equal(t, sequence(0, dumpChunkSize+10), dumpLengths(t, server, "table="+testEventTable))
+
+	for _, tc := range []struct {
+		token, query string
+		status       int
+	}{
+		{"", "table=" + testEventTable, http.StatusUnauthorized},
+		{"wrong", "table=" + testEventTable, http.StatusUnauthorized},
+		{testDumpToken, "", http.StatusBadRequest},
+		{testDumpToken, "table=other", http.StatusNotFound},
+		{testDumpToken, "table=" + testEventTable + "&since=yesterday", http.StatusBadRequest},
+	} {
+		status, _ := dump(t, server, tc.token, tc.query)
+		assert.Equal(t, tc.status, status, "%+v", tc)
+	}
+}
+
+func TestBufferTracerDumpSince(t *testing.T) {
+	client, server := setupBufferTracer(t, 100)
+	writeEvents(t, client, 0, 4)
+	time.Sleep(2 * time.Millisecond)
+	since := time.Now().UnixMilli()
+	time.Sleep(2 * time.Millisecond)
+	writeEvents(t, client, 5, 9)
+
+	query := fmt.Sprintf("table=%s&since=%d", testEventTable, since)
+	assert.Equal(t, sequence(5, 9), dumpLengths(t, server, query))
+	query = fmt.Sprintf("table=%s&since=%d", testEventTable, time.Now().Add(time.Second).UnixMilli())
+	assert.Empty(t, dumpLengths(t, server, query))
+}
+
+func TestBufferTracerOverwritesOldestRows(t *testing.T) {
+	client, server := setupBufferTracer(t, 4)
+	writeEvents(t, client, 0, 9)
+	assert.Equal(t, sequence(6, 9), dumpLengths(t, server, "table="+testEventTable))
+}
+
+func TestRingBufferReadOverwritten(t *testing.T) {
+	rb := newRingBuffer(4)
+	for i := 0; i < 6; i++ {
+		rb.add(time.Now(), []byte{byte(i)})
+	}
+	// rows 0 and 1 were overwritten, the read starts at the oldest one kept
+	rows, next := rb.read(0, rb.end(), make([]bufferedRow, 0, 3))
+	require.Len(t, rows, 3)
+	assert.Equal(t, []byte{2}, rows[0].bz)
+	assert.EqualValues(t, 5, next)
+
+	// the rows written after end aren't read
+	end := rb.end()
+	rb.add(time.Now(), []byte{6})
+	rows, next = rb.read(next, end, rows[:0])
+	require.Len(t, rows, 1)
+	assert.Equal(t, []byte{5}, rows[0].bz)
+	assert.Equal(t, end, next)
+}
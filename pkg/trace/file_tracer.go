@@ -0,0 +1,142 @@
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// FileTracer saves the events of each traced table to a file of newline
+// delimited JSON events in a directory, rotating the file once it reaches a
+// maximum size and keeping a bounded number of rotated files per table. It
+// needs no collection endpoint, which makes it convenient for local
+// investigations. It is thread safe.
+type FileTracer struct {
+	logger log.Logger
+	sampleRates
+	*tableSet
+
+	dir        string
+	maxSize    int64
+	maxBackups int
+	// fileMap maps tables to their files. It is only accessed by the
+	// goroutine of the queue, which opens the files of the tables enabled at
+	// runtime, and by Stop once it returned.
+	fileMap map[string]*rotatingFile
+	// queue buffers the events being written, to avoid blocking the caller
+	// when writing to files.
+	queue *eventQueue
+
+	stopOnce sync.Once
+}
+
+// NewFileTracer creates a FileTracer writing the traced tables to the
+// configured directory, relative to the root directory if not absolute. The
+// goroutine saving events is started in this function.
+func NewFileTracer(cfg *config.Config, logger log.Logger, chainID, nodeID string, opts ...Option) (*FileTracer, error) {
+	rates, err := cfg.Instrumentation.ParseTraceSampleRates()
+	if err != nil {
+		return nil, err
+	}
+	dir := cfg.Instrumentation.TraceFileDir
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(cfg.RootDir, dir)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	fm := make(map[string]*rotatingFile)
+	tables := splitAndTrimEmpty(cfg.Instrumentation.TracingTables, ",", " ")
+	for _, table := range tables {
+		file, err := newRotatingFile(dir, table, cfg.Instrumentation.TraceFileMaxSize,
+			cfg.Instrumentation.TraceFileMaxBackups)
+		if err != nil {
+			for _, f := range fm {
+				f.Close()
+			}
+			return nil, err
+		}
+		fm[table] = file
+	}
+
+	o := applyOptions(opts)
+	ft := &FileTracer{
+		logger:      logger,
+		sampleRates: rates,
+		tableSet:    o.tableSet(tables, logger),
+		dir:         dir,
+		maxSize:     cfg.Instrumentation.TraceFileMaxSize,
+		maxBackups:  cfg.Instrumentation.TraceFileMaxBackups,
+		fileMap:     fm,
+		queue:       newEventQueue(chainID, nodeID, cfg.Instrumentation.TraceBufferSize, o.metrics),
+	}
+	go ft.queue.run(ft.saveEvent, nil, ft.IsCollecting)
+	return ft, nil
+}
+
+// Write queues the event to be saved if its table is traced. Events are
+// dropped if the queue is full, or after Stop.
+func (ft *FileTracer) Write(e Entry) {
+	if !ft.IsCollecting(e.Table()) {
+		return
+	}
+	ft.queue.push(e)
+}
+
+// Dropped returns the number of events of table dropped so far.
+func (ft *FileTracer) Dropped(table string) uint64 {
+	return ft.queue.Dropped(table)
+}
+
+// saveEventToFile marshals an Event into JSON and appends it to the file of its
+// table.
+func (ft *FileTracer) saveEventToFile(event Event[Entry]) error {
+	file, has := ft.fileMap[event.Table]
+	if !has {
+		var err error
+		file, err = newRotatingFile(ft.dir, event.Table, ft.maxSize, ft.maxBackups)
+		if err != nil {
+			return err
+		}
+		ft.fileMap[event.Table] = file
+	}
+
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+
+	if _, err := file.Write(append(eventJSON, '\n')); err != nil {
+		return fmt.Errorf("failed to write event to file: %v", err)
+	}
+
+	return nil
+}
+
+func (ft *FileTracer) saveEvent(ev Event[Entry]) {
+	if err := ft.saveEventToFile(ev); err != nil {
+		ft.logger.Error("failed to save event to file", "error", err)
+	}
+}
+
+// Stop saves the events queued so far, within the flush timeout, and closes
+// all open files.
+func (ft *FileTracer) Stop() {
+	ft.stopOnce.Do(func() {
+		if !ft.queue.stop() {
+			ft.logger.Error("timed out saving the queued events, leaving the files open")
+			return
+		}
+		for _, file := range ft.fileMap {
+			if err := file.Close(); err != nil {
+				ft.logger.Error("failed to close file", "error", err)
+			}
+		}
+	})
+}
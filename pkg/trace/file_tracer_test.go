@@ -0,0 +1,147 @@
+package trace
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func setupFileTracer(t *testing.T, maxSize int64, maxBackups int) (*FileTracer, string) {
+	cfg := config.DefaultConfig()
+	cfg.SetRoot(t.TempDir())
+	cfg.Instrumentation.TraceType = "file"
+	cfg.Instrumentation.TracingTables = testEventTable
+	cfg.Instrumentation.TraceFileMaxSize = maxSize
+	cfg.Instrumentation.TraceFileMaxBackups = maxBackups
+
+	client, err := NewFileTracer(cfg, log.NewNopLogger(), "test_chain", "test_node")
+	require.NoError(t, err)
+	t.Cleanup(client.Stop)
+	return client, filepath.Join(cfg.RootDir, cfg.Instrumentation.TraceFileDir)
+}
+
+func decodeTableFile(t *testing.T, path string) []Event[testEvent] {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	events, err := DecodeFile[testEvent](f)
+	require.NoError(t, err)
+	return events
+}
+
+// TestFileTracerRotation fills a table past the rotation threshold from
+// several goroutines and checks that only the configured number of rotated
+// files is kept, each within the size threshold.
+func TestFileTracerRotation(t *testing.T) {
+	const (
+		maxSize    = 2048
+		maxBackups = 2
+		writers    = 4
+		perWriter  = 100
+	)
+	client, dir := setupFileTracer(t, maxSize, maxBackups)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				client.Write(testEvent{City: "Annecy", Length: i*perWriter + j})
+			}
+		}(i)
+	}
+	wg.Wait()
+	client.Stop()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.ElementsMatch(t, []string{
+		testEventTable + ".jsonl",
+		testEventTable + ".1.jsonl",
+		testEventTable + ".2.jsonl",
+	}, names)
+
+	// the rotated files are full, and the oldest events were dropped
+	seen := make(map[int]bool)
+	for i, name := range []string{
+		testEventTable + ".2.jsonl",
+		testEventTable + ".1.jsonl",
+		testEventTable + ".jsonl",
+	} {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(maxSize), name)
+		events := decodeTableFile(t, path)
+		if i < maxBackups {
+			assert.Greater(t, info.Size(), int64(maxSize/2), name)
+		}
+		for _, ev := range events {
+			assert.Equal(t, "test_chain", ev.ChainID)
+			assert.Equal(t, testEventTable, ev.Table)
+			assert.False(t, seen[ev.Msg.Length], "duplicate event %d", ev.Msg.Length)
+			seen[ev.Msg.Length] = true
+		}
+	}
+	assert.NotEmpty(t, seen)
+	assert.Less(t, len(seen), writers*perWriter)
+
+	// writing after stopping doesn't block, the event is dropped
+	client.Write(testEvent{City: "Paris"})
+}
+
+func TestFileTracerReopen(t *testing.T) {
+	client, dir := setupFileTracer(t, 1024, 1)
+	client.Write(testEvent{City: "Annecy", Length: 1})
+	client.Stop()
+
+	// a new tracer appends to the current file
+	cfg := config.DefaultConfig()
+	cfg.Instrumentation.TraceFileDir = dir
+	cfg.Instrumentation.TracingTables = testEventTable
+	client, err := NewFileTracer(cfg, log.NewNopLogger(), "test_chain", "test_node")
+	require.NoError(t, err)
+	client.Write(testEvent{City: "Paris", Length: 2})
+	client.Stop()
+
+	events := decodeTableFile(t, filepath.Join(dir, testEventTable+".jsonl"))
+	require.Len(t, events, 2)
+	assert.Equal(t, "Annecy", events[0].Msg.City)
+	assert.Equal(t, "Paris", events[1].Msg.City)
+}
+
+func TestNewTracerCombined(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SetRoot(t.TempDir())
+	cfg.Instrumentation.TraceType = "local, file"
+	cfg.Instrumentation.TracingTables = testEventTable
+	cfg.Instrumentation.TraceSampleRates = testEventTable + "=1/4"
+
+	client, err := NewTracer(cfg, log.NewNopLogger(), "test_chain", "test_node")
+	require.NoError(t, err)
+	require.IsType(t, multiTracer{}, client)
+	assert.True(t, client.IsCollecting(testEventTable))
+	assert.False(t, client.IsCollecting("other"))
+	assert.Equal(t, 0.25, client.(Sampler).SampleRate(testEventTable))
+	assert.Equal(t, 1.0, client.(Sampler).SampleRate("other"))
+
+	client.Write(testEvent{City: "Annecy", Length: 1})
+	client.Stop()
+
+	assert.FileExists(t, filepath.Join(cfg.RootDir, "data", "traces", testEventTable+".jsonl"))
+	events := decodeTableFile(t, filepath.Join(cfg.RootDir, cfg.Instrumentation.TraceFileDir, testEventTable+".jsonl"))
+	require.Len(t, events, 1)
+	assert.Equal(t, "Annecy", events[0].Msg.City)
+}
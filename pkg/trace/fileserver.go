@@ -243,7 +243,7 @@ func (lt *LocalTracer) pushLoop() {
 }
 
 func (lt *LocalTracer) PushAll() error {
-	for table := range lt.fileMap {
+	for _, table := range lt.tables() {
 		f, done, err := lt.readTable(table)
 		if err != nil {
 			return err
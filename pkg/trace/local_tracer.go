@@ -7,6 +7,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tendermint/tendermint/config"
@@ -53,14 +54,17 @@ type LocalTracer struct {
 	logger          log.Logger
 	cfg             *config.Config
 	s3Config        S3Config
+	sampleRates
+	*tableSet
 
-	// fileMap maps tables to their open files files are threadsafe, but the map
-	// is not. Therefore don't create new files after initialization to remain
-	// threadsafe.
+	// fileMap maps tables to their open files. The files are threadsafe, and
+	// the map is guarded by mtx since the files of the tables enabled at
+	// runtime are opened when their first event is saved.
+	mtx     sync.RWMutex
 	fileMap map[string]*bufferedFile
-	// canal is a channel for all events that are being written. It acts as an
-	// extra buffer to avoid blocking the caller when writing to files.
-	canal chan Event[Entry]
+	// queue buffers the events that are being written, to avoid blocking the
+	// caller when writing to files.
+	queue *eventQueue
 }
 
 // NewLocalTracer creates a struct that will save all of the events passed to
@@ -69,32 +73,34 @@ type LocalTracer struct {
 // safe to avoid the overhead of locking with each event save. Only pass events
 // to the returned channel. Call CloseAll to close all open files. Goroutine to
 // save events is started in this function.
-func NewLocalTracer(cfg *config.Config, logger log.Logger, chainID, nodeID string) (*LocalTracer, error) {
+func NewLocalTracer(cfg *config.Config, logger log.Logger, chainID, nodeID string, opts ...Option) (*LocalTracer, error) {
+	rates, err := cfg.Instrumentation.ParseTraceSampleRates()
+	if err != nil {
+		return nil, err
+	}
 	fm := make(map[string]*bufferedFile)
-	p := path.Join(cfg.RootDir, "data", "traces")
-	for _, table := range splitAndTrimEmpty(cfg.Instrumentation.TracingTables, ",", " ") {
-		fileName := fmt.Sprintf("%s/%s.jsonl", p, table)
-		err := os.MkdirAll(p, 0700)
+	tables := splitAndTrimEmpty(cfg.Instrumentation.TracingTables, ",", " ")
+	for _, table := range tables {
+		file, err := openTableFile(cfg, table)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create directory %s: %w", p, err)
+			return nil, err
 		}
-		file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
-		if err != nil {
-			return nil, fmt.Errorf("failed to open or create file %s: %w", fileName, err)
-		}
-		fm[table] = newbufferedFile(file)
+		fm[table] = file
 	}
 
+	o := applyOptions(opts)
 	lt := &LocalTracer{
-		fileMap: fm,
-		cfg:     cfg,
-		canal:   make(chan Event[Entry], cfg.Instrumentation.TraceBufferSize),
-		chainID: chainID,
-		nodeID:  nodeID,
-		logger:  logger,
+		fileMap:     fm,
+		cfg:         cfg,
+		queue:       newEventQueue(chainID, nodeID, cfg.Instrumentation.TraceBufferSize, o.metrics),
+		chainID:     chainID,
+		nodeID:      nodeID,
+		logger:      logger,
+		sampleRates: rates,
+		tableSet:    o.tableSet(tables, logger),
 	}
 
-	go lt.drainCanal()
+	go lt.queue.run(lt.saveEvent, nil, lt.IsCollecting)
 	if cfg.Instrumentation.TracePullAddress != "" {
 		logger.Info("starting pull server", "address", cfg.Instrumentation.TracePullAddress)
 		go lt.servePullData()
@@ -138,11 +144,18 @@ func GetPushConfigFromEnv() (S3Config, error) {
 	return s3Config, nil
 }
 
+// Write queues the event to be saved if its table is traced. It never blocks:
+// events are dropped if the queue is full, or after Stop.
 func (lt *LocalTracer) Write(e Entry) {
 	if !lt.IsCollecting(e.Table()) {
 		return
 	}
-	lt.canal <- NewEvent(lt.chainID, lt.nodeID, e.Table(), e)
+	lt.queue.push(e)
+}
+
+// Dropped returns the number of events of table dropped so far.
+func (lt *LocalTracer) Dropped(table string) uint64 {
+	return lt.queue.Dropped(table)
 }
 
 // ReadTable returns a file for the given table. If the table is not being
@@ -156,23 +169,37 @@ func (lt *LocalTracer) readTable(table string) (*os.File, func() error, error) {
 	return bf.File()
 }
 
-func (lt *LocalTracer) IsCollecting(table string) bool {
-	_, has := lt.getFile(table)
-	return has
-}
-
-// getFile gets a file for the given type. This method is purposely
-// not thread-safe to avoid the overhead of locking with each event save.
+// getFile gets a file for the given type.
 func (lt *LocalTracer) getFile(table string) (*bufferedFile, bool) {
+	lt.mtx.RLock()
+	defer lt.mtx.RUnlock()
 	f, has := lt.fileMap[table]
 	return f, has
 }
 
+// tables returns the tables which have a file.
+func (lt *LocalTracer) tables() []string {
+	lt.mtx.RLock()
+	defer lt.mtx.RUnlock()
+	tables := make([]string, 0, len(lt.fileMap))
+	for table := range lt.fileMap {
+		tables = append(tables, table)
+	}
+	return tables
+}
+
 // saveEventToFile marshals an Event into JSON and appends it to a file named after the event's Type.
 func (lt *LocalTracer) saveEventToFile(event Event[Entry]) error {
 	file, has := lt.getFile(event.Table)
 	if !has {
-		return fmt.Errorf("table %s not found", event.Table)
+		var err error
+		file, err = openTableFile(lt.cfg, event.Table)
+		if err != nil {
+			return err
+		}
+		lt.mtx.Lock()
+		lt.fileMap[event.Table] = file
+		lt.mtx.Unlock()
 	}
 
 	eventJSON, err := json.Marshal(event)
@@ -187,19 +214,20 @@ func (lt *LocalTracer) saveEventToFile(event Event[Entry]) error {
 	return nil
 }
 
-// draincanal takes a variadic number of channels of Event pointers and drains them into files.
-func (lt *LocalTracer) drainCanal() {
-	// purposefully do not lock, and rely on the channel to provide sync
-	// actions, to avoid overhead of locking with each event save.
-	for ev := range lt.canal {
-		if err := lt.saveEventToFile(ev); err != nil {
-			lt.logger.Error("failed to save event to file", "error", err)
-		}
+func (lt *LocalTracer) saveEvent(ev Event[Entry]) {
+	if err := lt.saveEventToFile(ev); err != nil {
+		lt.logger.Error("failed to save event to file", "error", err)
 	}
 }
 
-// Stop optionally uploads and closes all open files.
+// Stop saves the events queued so far, within the flush timeout, and then
+// optionally uploads and closes all open files.
 func (lt *LocalTracer) Stop() {
+	if !lt.queue.stop() {
+		lt.logger.Error("timed out saving the queued events, leaving the files open")
+		return
+	}
+
 	if lt.s3Config.SecretKey != "" {
 		lt.logger.Info("pushing all tables before stopping")
 		err := lt.PushAll()
@@ -208,6 +236,8 @@ func (lt *LocalTracer) Stop() {
 		}
 	}
 
+	lt.mtx.Lock()
+	defer lt.mtx.Unlock()
 	for _, file := range lt.fileMap {
 		err := file.Close()
 		if err != nil {
@@ -216,6 +246,20 @@ func (lt *LocalTracer) Stop() {
 	}
 }
 
+// openTableFile opens, or creates, the file of table in the traces directory.
+func openTableFile(cfg *config.Config, table string) (*bufferedFile, error) {
+	p := path.Join(cfg.RootDir, "data", "traces")
+	if err := os.MkdirAll(p, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %w", p, err)
+	}
+	fileName := fmt.Sprintf("%s/%s.jsonl", p, table)
+	file, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open or create file %s: %w", fileName, err)
+	}
+	return newbufferedFile(file), nil
+}
+
 // splitAndTrimEmpty slices s into all subslices separated by sep and returns a
 // slice of the string s with all leading and trailing Unicode code points
 // contained in cutset removed. If sep is empty, SplitAndTrim splits after each
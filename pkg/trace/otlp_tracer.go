@@ -0,0 +1,262 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// OTLPExportMethod is the OTLP/gRPC method exporting log records. Its
+	// request, ExportLogsServiceRequest, is wire compatible with LogsData,
+	// which spares depending on the generated collector service and its HTTP
+	// gateway. The partial success of the response is ignored.
+	OTLPExportMethod = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+
+	// otlpScopeName is the instrumentation scope of the exported log records.
+	otlpScopeName = "github.com/tendermint/tendermint/pkg/trace"
+
+	otlpMaxBatchSize   = 512
+	otlpExportTimeout  = 10 * time.Second
+	otlpMaxRetries     = 5
+	otlpInitialBackoff = 100 * time.Millisecond
+	otlpMaxBackoff     = 5 * time.Second
+)
+
+// OTLPTracer exports the rows of the traced tables to an OpenTelemetry
+// collector, as OTLP log records sent over gRPC. The records of a table share
+// a resource with the chain ID, node ID and table as attributes, and the
+// fields of a row are the attributes of its record.
+//
+// Rows are batched and exported from a goroutine, retrying failed exports
+// with backoff. Write never blocks: rows are dropped, and counted, when the
+// queue is full.
+type OTLPTracer struct {
+	logger log.Logger
+	sampleRates
+	*tableSet
+
+	conn *grpc.ClientConn
+
+	queue *eventQueue
+	// batch is the rows waiting to be exported, only accessed by the
+	// goroutine of the queue.
+	batch []Event[Entry]
+
+	stopOnce sync.Once
+}
+
+// NewOTLPTracer creates an OTLPTracer exporting to the configured endpoint.
+// The connection is established lazily, and the goroutine exporting rows is
+// started in this function.
+func NewOTLPTracer(cfg *config.Config, logger log.Logger, chainID, nodeID string, opts ...Option) (*OTLPTracer, error) {
+	rates, err := cfg.Instrumentation.ParseTraceSampleRates()
+	if err != nil {
+		return nil, err
+	}
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if cfg.Instrumentation.TraceOTLPInsecure {
+		creds = insecure.NewCredentials()
+	}
+	conn, err := grpc.NewClient(cfg.Instrumentation.TraceOTLPEndpoint, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp client for %s: %w", cfg.Instrumentation.TraceOTLPEndpoint, err)
+	}
+
+	tables := splitAndTrimEmpty(cfg.Instrumentation.TracingTables, ",", " ")
+
+	o := applyOptions(opts)
+	ot := &OTLPTracer{
+		logger:      logger,
+		sampleRates: rates,
+		tableSet:    o.tableSet(tables, logger),
+		conn:        conn,
+		queue:       newEventQueue(chainID, nodeID, cfg.Instrumentation.TraceBufferSize, o.metrics),
+		batch:       make([]Event[Entry], 0, otlpMaxBatchSize),
+	}
+	go ot.queue.run(ot.batchEvent, ot.flush, ot.IsCollecting)
+	return ot, nil
+}
+
+// Write queues the row to be exported if its table is traced, or drops it if
+// the queue is full.
+func (ot *OTLPTracer) Write(e Entry) {
+	if !ot.IsCollecting(e.Table()) {
+		return
+	}
+	ot.queue.push(e)
+}
+
+// Dropped returns the number of rows of table dropped so far, because the
+// queue was full or their export failed.
+func (ot *OTLPTracer) Dropped(table string) uint64 {
+	return ot.queue.Dropped(table)
+}
+
+// Stop exports the rows queued so far, within the flush timeout and without
+// retrying, and closes the connection.
+func (ot *OTLPTracer) Stop() {
+	ot.stopOnce.Do(func() {
+		if !ot.queue.stop() {
+			ot.logger.Error("timed out exporting the queued trace rows")
+		}
+		if err := ot.conn.Close(); err != nil {
+			ot.logger.Error("failed to close otlp connection", "error", err)
+		}
+	})
+}
+
+// batchEvent adds the row to the batch, which is exported once full.
+func (ot *OTLPTracer) batchEvent(ev Event[Entry]) {
+	ot.batch = append(ot.batch, ev)
+	if len(ot.batch) == otlpMaxBatchSize {
+		ot.flush()
+	}
+}
+
+// flush exports the batch, retrying unless the tracer is stopping.
+func (ot *OTLPTracer) flush() {
+	if len(ot.batch) == 0 {
+		return
+	}
+	retry := true
+	select {
+	case <-ot.queue.stopping():
+		retry = false
+	default:
+	}
+	ot.export(ot.batch, retry)
+	ot.batch = ot.batch[:0]
+}
+
+// export sends the batch to the collector, retrying with exponential backoff
+// if retry is true, until the tracer is stopped. The rows are dropped if the
+// export fails.
+func (ot *OTLPTracer) export(batch []Event[Entry], retry bool) {
+	req := ot.exportRequest(batch)
+	backoff := otlpInitialBackoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+		err := ot.conn.Invoke(ctx, OTLPExportMethod, req, &emptypb.Empty{})
+		cancel()
+		if err == nil {
+			return
+		}
+		if !retry || attempt == otlpMaxRetries {
+			for _, ev := range batch {
+				ot.queue.drop(ev.Table, 1)
+			}
+			ot.logger.Error("failed to export trace rows", "rows", len(batch), "attempts", attempt+1, "error", err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ot.queue.stopping():
+			retry = false
+		}
+		if backoff *= 2; backoff > otlpMaxBackoff {
+			backoff = otlpMaxBackoff
+		}
+	}
+}
+
+// exportRequest maps the batch to log records, grouped by table.
+func (ot *OTLPTracer) exportRequest(batch []Event[Entry]) *logspb.LogsData {
+	req := &logspb.LogsData{}
+	scopeLogs := make(map[string]*logspb.ScopeLogs)
+	for _, ev := range batch {
+		sl, ok := scopeLogs[ev.Table]
+		if !ok {
+			sl = &logspb.ScopeLogs{Scope: &commonpb.InstrumentationScope{Name: otlpScopeName}}
+			scopeLogs[ev.Table] = sl
+			req.ResourceLogs = append(req.ResourceLogs, &logspb.ResourceLogs{
+				Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+					stringKeyValue("chain_id", ev.ChainID),
+					stringKeyValue("node_id", ev.NodeID),
+					stringKeyValue("table", ev.Table),
+				}},
+				ScopeLogs: []*logspb.ScopeLogs{sl},
+			})
+		}
+		sl.LogRecords = append(sl.LogRecords, ot.logRecord(ev))
+	}
+	return req
+}
+
+// logRecord maps the row of ev to a log record, whose attributes are the JSON
+// fields of the row.
+func (ot *OTLPTracer) logRecord(ev Event[Entry]) *logspb.LogRecord {
+	record := &logspb.LogRecord{
+		TimeUnixNano:         uint64(ev.Timestamp.UnixNano()),
+		ObservedTimeUnixNano: uint64(ev.Timestamp.UnixNano()),
+		SeverityNumber:       logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+		Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: ev.Table}},
+	}
+	bz, err := json.Marshal(ev.Msg)
+	if err != nil {
+		ot.logger.Error("failed to marshal trace row", "table", ev.Table, "error", err)
+		return record
+	}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var fields map[string]interface{}
+	if err := dec.Decode(&fields); err != nil {
+		// not a JSON object, export it as the body
+		record.Body = &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: string(bz)}}
+		return record
+	}
+	for key, value := range fields {
+		record.Attributes = append(record.Attributes, &commonpb.KeyValue{Key: key, Value: anyValue(value)})
+	}
+	return record
+}
+
+func stringKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+// anyValue maps a value decoded from JSON, with numbers decoded as
+// json.Number, to an OTLP value.
+func anyValue(v interface{}) *commonpb.AnyValue {
+	switch v := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+		}
+		f, _ := v.Float64()
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: f}}
+	case []interface{}:
+		values := make([]*commonpb.AnyValue, len(v))
+		for i, e := range v {
+			values[i] = anyValue(e)
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: values}}}
+	case map[string]interface{}:
+		kvs := make([]*commonpb.KeyValue, 0, len(v))
+		for key, e := range v {
+			kvs = append(kvs, &commonpb.KeyValue{Key: key, Value: anyValue(e)})
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: &commonpb.KeyValueList{Values: kvs}}}
+	default: // null
+		return &commonpb.AnyValue{}
+	}
+}
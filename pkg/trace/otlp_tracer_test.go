@@ -0,0 +1,182 @@
+package trace
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// otlpReceiver is an in-process OTLP/gRPC logs receiver.
+type otlpReceiver struct {
+	mtx      sync.Mutex
+	calls    int
+	failures int           // number of exports to fail
+	block    chan struct{} // if not nil, exports wait for it to be closed
+	logs     []*logspb.ResourceLogs
+}
+
+func (r *otlpReceiver) export(req *logspb.LogsData) (*emptypb.Empty, error) {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.calls++
+	if r.failures > 0 {
+		r.failures--
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+	r.logs = append(r.logs, req.ResourceLogs...)
+	return &emptypb.Empty{}, nil
+}
+
+// records returns the records received, by table.
+func (r *otlpReceiver) records() map[string][]*logspb.LogRecord {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	records := make(map[string][]*logspb.LogRecord)
+	for _, rl := range r.logs {
+		table := attribute(rl.GetResource().GetAttributes(), "table").GetStringValue()
+		for _, sl := range rl.ScopeLogs {
+			records[table] = append(records[table], sl.LogRecords...)
+		}
+	}
+	return records
+}
+
+func attribute(kvs []*commonpb.KeyValue, key string) *commonpb.AnyValue {
+	for _, kv := range kvs {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
+var otlpLogsServiceDesc = grpc.ServiceDesc{
+	ServiceName: "opentelemetry.proto.collector.logs.v1.LogsService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Export",
+		Handler: func(srv interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := &logspb.LogsData{}
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			return srv.(*otlpReceiver).export(req)
+		},
+	}},
+}
+
+func setupOTLPTracer(t *testing.T, receiver *otlpReceiver, bufferSize int) *OTLPTracer {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	server.RegisterService(&otlpLogsServiceDesc, receiver)
+	go server.Serve(lis) //nolint:errcheck // ignore for tests
+	t.Cleanup(server.Stop)
+
+	cfg := config.DefaultConfig()
+	cfg.Instrumentation.TraceType = "otlp"
+	cfg.Instrumentation.TraceOTLPEndpoint = lis.Addr().String()
+	cfg.Instrumentation.TraceOTLPInsecure = true
+	cfg.Instrumentation.TraceBufferSize = bufferSize
+	cfg.Instrumentation.TracingTables = testEventTable + ",other"
+
+	client, err := NewOTLPTracer(cfg, log.NewNopLogger(), "test_chain", "test_node")
+	require.NoError(t, err)
+	t.Cleanup(client.Stop)
+	return client
+}
+
+type otherEvent struct {
+	Ratio float64 `json:"ratio"`
+	Ok    bool    `json:"ok"`
+}
+
+func (otherEvent) Table() string { return "other" }
+
+func TestOTLPTracerExport(t *testing.T) {
+	receiver := &otlpReceiver{}
+	client := setupOTLPTracer(t, receiver, 100)
+
+	client.Write(testEvent{City: "Annecy", Length: 420})
+	client.Write(otherEvent{Ratio: 0.5, Ok: true})
+	client.Write(testEvent{City: "Paris", Length: 720})
+	client.Stop()
+
+	require.Len(t, receiver.logs, 2)
+	for _, rl := range receiver.logs {
+		attrs := rl.GetResource().GetAttributes()
+		assert.Equal(t, "test_chain", attribute(attrs, "chain_id").GetStringValue())
+		assert.Equal(t, "test_node", attribute(attrs, "node_id").GetStringValue())
+	}
+
+	records := receiver.records()
+	require.Len(t, records[testEventTable], 2)
+	for i, want := range []testEvent{{"Annecy", 420}, {"Paris", 720}} {
+		record := records[testEventTable][i]
+		assert.NotZero(t, record.TimeUnixNano)
+		assert.Equal(t, testEventTable, record.Body.GetStringValue())
+		assert.Equal(t, want.City, attribute(record.Attributes, "city").GetStringValue())
+		assert.EqualValues(t, want.Length, attribute(record.Attributes, "length").GetIntValue())
+	}
+	require.Len(t, records["other"], 1)
+	assert.Equal(t, 0.5, attribute(records["other"][0].Attributes, "ratio").GetDoubleValue())
+	assert.True(t, attribute(records["other"][0].Attributes, "ok").GetBoolValue())
+	assert.Zero(t, client.Dropped(testEventTable))
+}
+
+func TestOTLPTracerRetry(t *testing.T) {
+	receiver := &otlpReceiver{failures: 2}
+	client := setupOTLPTracer(t, receiver, 100)
+
+	client.Write(testEvent{City: "Annecy", Length: 420})
+	require.Eventually(t, func() bool {
+		return len(receiver.records()[testEventTable]) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+
+	receiver.mtx.Lock()
+	assert.Equal(t, 3, receiver.calls)
+	receiver.mtx.Unlock()
+	assert.Zero(t, client.Dropped(testEventTable))
+}
+
+func TestOTLPTracerDropsWhenFull(t *testing.T) {
+	receiver := &otlpReceiver{block: make(chan struct{})}
+	client := setupOTLPTracer(t, receiver, 10)
+
+	// the exporter is stuck on the collector, writes don't block
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10*otlpMaxBatchSize; i++ {
+			client.Write(testEvent{City: "Annecy", Length: i})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writing blocked")
+	}
+	assert.Greater(t, client.Dropped(testEventTable), uint64(0))
+
+	close(receiver.block)
+	client.Stop()
+	// the rows received and the dropped ones add up to the rows written
+	assert.EqualValues(t, 10*otlpMaxBatchSize, uint64(len(receiver.records()[testEventTable]))+client.Dropped(testEventTable))
+}
@@ -0,0 +1,222 @@
+package trace
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	// DroppedEventsTable is the name of the table a tracer reports the
+	// events it dropped to, periodically.
+	DroppedEventsTable = "trace_dropped_events"
+
+	defaultFlushInterval      = time.Second
+	defaultFlushTimeout       = 5 * time.Second
+	defaultDropReportInterval = 10 * time.Second
+)
+
+// DroppedEvents describes schema for the "trace_dropped_events" table. A row
+// reports the events of a table dropped since the previous row for it.
+type DroppedEvents struct {
+	DroppedTable string `json:"dropped_table"`
+	Dropped      uint64 `json:"dropped"`
+	Total        uint64 `json:"total"`
+}
+
+func (d DroppedEvents) Table() string {
+	return DroppedEventsTable
+}
+
+// Option sets an optional parameter of a tracer.
+type Option func(*options)
+
+type options struct {
+	metrics *Metrics
+	tables  *tableSet
+}
+
+// WithMetrics sets the metrics a tracer reports its dropped events to.
+func WithMetrics(metrics *Metrics) Option {
+	return func(o *options) { o.metrics = metrics }
+}
+
+// withTables sets the set of tables collected by a tracer, so that the
+// tracers created from the same config share it.
+func withTables(tables *tableSet) Option {
+	return func(o *options) { o.tables = tables }
+}
+
+func applyOptions(opts []Option) options {
+	o := options{metrics: NopMetrics()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// tableSet returns the set of tables set as option, or a new set of the
+// given tables.
+func (o options) tableSet(tables []string, logger log.Logger) *tableSet {
+	if o.tables != nil {
+		return o.tables
+	}
+	return newTableSet(tables, logger)
+}
+
+// eventQueue is the bounded queue between the Write calls of a tracer and the
+// goroutine writing the events to its sink, so that a slow sink never blocks
+// the callers. The events are dropped, and counted by table, when the queue is
+// full.
+type eventQueue struct {
+	chainID, nodeID string
+	metrics         *Metrics
+
+	events chan Event[Entry]
+	// dropped maps tables to the *atomic.Uint64 counting their events
+	// dropped.
+	dropped sync.Map
+
+	flushInterval      time.Duration
+	flushTimeout       time.Duration
+	dropReportInterval time.Duration
+
+	stopOnce sync.Once
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+func newEventQueue(chainID, nodeID string, size int, metrics *Metrics) *eventQueue {
+	return &eventQueue{
+		chainID:            chainID,
+		nodeID:             nodeID,
+		metrics:            metrics,
+		events:             make(chan Event[Entry], size),
+		flushInterval:      defaultFlushInterval,
+		flushTimeout:       defaultFlushTimeout,
+		dropReportInterval: defaultDropReportInterval,
+		quit:               make(chan struct{}),
+		done:               make(chan struct{}),
+	}
+}
+
+// push queues the event, or drops it if the queue is full or stopped. It
+// never blocks.
+func (q *eventQueue) push(e Entry) {
+	select {
+	case <-q.quit:
+		q.drop(e.Table(), 1)
+		return
+	default:
+	}
+	select {
+	case q.events <- NewEvent(q.chainID, q.nodeID, e.Table(), e):
+	default:
+		q.drop(e.Table(), 1)
+	}
+}
+
+// drop counts n dropped events of table.
+func (q *eventQueue) drop(table string, n uint64) {
+	counter, ok := q.dropped.Load(table)
+	if !ok {
+		counter, _ = q.dropped.LoadOrStore(table, &atomic.Uint64{})
+	}
+	counter.(*atomic.Uint64).Add(n)
+	q.metrics.DroppedEvents.With("table", table).Add(float64(n))
+}
+
+// Dropped returns the number of events of table dropped so far.
+func (q *eventQueue) Dropped(table string) uint64 {
+	if counter, ok := q.dropped.Load(table); ok {
+		return counter.(*atomic.Uint64).Load()
+	}
+	return 0
+}
+
+// stopping returns a channel closed once the queue is stopped.
+func (q *eventQueue) stopping() <-chan struct{} {
+	return q.quit
+}
+
+// run writes the queued events with write until the queue is stopped. It
+// calls flush, if not nil, periodically and once the queue is drained, and
+// reports the dropped events with write while isCollecting reports that the
+// sink collects them.
+func (q *eventQueue) run(write func(Event[Entry]), flush func(), isCollecting func(table string) bool) {
+	defer close(q.done)
+	reportTicker := time.NewTicker(q.dropReportInterval)
+	defer reportTicker.Stop()
+	flushTicker := time.NewTicker(q.flushInterval)
+	defer flushTicker.Stop()
+
+	reported := make(map[string]uint64)
+	report := func() {
+		if !isCollecting(DroppedEventsTable) {
+			return
+		}
+		q.dropped.Range(func(key, counter any) bool {
+			table := key.(string)
+			total := counter.(*atomic.Uint64).Load()
+			if total == reported[table] {
+				return true
+			}
+			write(NewEvent[Entry](q.chainID, q.nodeID, DroppedEventsTable, DroppedEvents{
+				DroppedTable: table,
+				Dropped:      total - reported[table],
+				Total:        total,
+			}))
+			reported[table] = total
+			return true
+		})
+	}
+	for {
+		select {
+		case ev := <-q.events:
+			write(ev)
+		case <-flushTicker.C:
+			if flush != nil {
+				flush()
+			}
+		case <-reportTicker.C:
+			report()
+		case <-q.quit:
+			// drain the queue, dropping the events left past the deadline
+			deadline := time.Now().Add(q.flushTimeout)
+		DRAIN:
+			for {
+				select {
+				case ev := <-q.events:
+					if time.Now().After(deadline) {
+						q.drop(ev.Table, 1)
+					} else {
+						write(ev)
+					}
+				default:
+					break DRAIN
+				}
+			}
+			report()
+			if flush != nil {
+				flush()
+			}
+			return
+		}
+	}
+}
+
+// stop stops queuing events and waits for the queued ones to be written, up to
+// the flush timeout: the events still queued then are dropped. It reports
+// whether the writing goroutine returned in time, the sink may be closed only
+// if it did.
+func (q *eventQueue) stop() bool {
+	q.stopOnce.Do(func() { close(q.quit) })
+	select {
+	case <-q.done:
+		return true
+	case <-time.After(q.flushTimeout + time.Second):
+		return false
+	}
+}
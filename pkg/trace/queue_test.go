@@ -0,0 +1,166 @@
+package trace
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stalledSink records the events written to it, once unblocked.
+type stalledSink struct {
+	unblock chan struct{}
+
+	mtx    sync.Mutex
+	events []Event[Entry]
+}
+
+func newStalledSink() *stalledSink {
+	return &stalledSink{unblock: make(chan struct{})}
+}
+
+func (s *stalledSink) write(ev Event[Entry]) {
+	<-s.unblock
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.events = append(s.events, ev)
+}
+
+// count returns the number of events written to table.
+func (s *stalledSink) count(table string) int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	n := 0
+	for _, ev := range s.events {
+		if ev.Table == table {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *stalledSink) dropReports() []DroppedEvents {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var reports []DroppedEvents
+	for _, ev := range s.events {
+		if report, ok := ev.Msg.(DroppedEvents); ok {
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// collecting returns an IsCollecting function collecting the given tables.
+func collecting(tables ...string) func(string) bool {
+	return func(table string) bool {
+		for _, t := range tables {
+			if t == table {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// TestEventQueueStalledSink writes from several goroutines to a queue whose
+// sink is stalled, and checks that writes don't block and that every event is
+// either written or counted as dropped.
+func TestEventQueueStalledSink(t *testing.T) {
+	const (
+		writers   = 8
+		perWriter = 1000
+	)
+	sink := newStalledSink()
+	q := newEventQueue("test_chain", "test_node", 100, NopMetrics())
+	go q.run(sink.write, nil, collecting())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for i := 0; i < writers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < perWriter; j++ {
+					q.push(testEvent{City: "Annecy", Length: j})
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("writing blocked on the stalled sink")
+	}
+	// at most the queue and the event being written were kept
+	assert.GreaterOrEqual(t, q.Dropped(testEventTable), uint64(writers*perWriter-101))
+
+	close(sink.unblock)
+	require.True(t, q.stop())
+	assert.EqualValues(t, writers*perWriter, uint64(sink.count(testEventTable))+q.Dropped(testEventTable))
+	assert.Zero(t, q.Dropped("other"))
+}
+
+// TestEventQueueStopDeadline checks that stopping a queue whose sink stays
+// stalled returns after the flush timeout.
+func TestEventQueueStopDeadline(t *testing.T) {
+	sink := newStalledSink()
+	defer close(sink.unblock)
+	q := newEventQueue("test_chain", "test_node", 10, NopMetrics())
+	q.flushTimeout = 50 * time.Millisecond
+	go q.run(sink.write, nil, collecting())
+	for i := 0; i < 5; i++ {
+		q.push(testEvent{City: "Annecy", Length: i})
+	}
+
+	start := time.Now()
+	assert.False(t, q.stop())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// events written after stop are dropped
+	dropped := q.Dropped(testEventTable)
+	q.push(testEvent{City: "Paris"})
+	assert.Equal(t, dropped+1, q.Dropped(testEventTable))
+}
+
+// TestEventQueueDrainsOnStop checks that the events queued when stopping are
+// written if the sink recovers within the flush timeout.
+func TestEventQueueDrainsOnStop(t *testing.T) {
+	sink := newStalledSink()
+	q := newEventQueue("test_chain", "test_node", 10, NopMetrics())
+	go q.run(sink.write, nil, collecting())
+	for i := 0; i < 10; i++ {
+		q.push(testEvent{City: "Annecy", Length: i})
+	}
+
+	time.AfterFunc(50*time.Millisecond, func() { close(sink.unblock) })
+	require.True(t, q.stop())
+	assert.Equal(t, 10, sink.count(testEventTable))
+	assert.Zero(t, q.Dropped(testEventTable))
+}
+
+// TestEventQueueReportsDrops checks that the events dropped are periodically
+// reported to the sink, once per table with new drops.
+func TestEventQueueReportsDrops(t *testing.T) {
+	sink := newStalledSink()
+	close(sink.unblock)
+	q := newEventQueue("test_chain", "test_node", 10, NopMetrics())
+	q.dropReportInterval = 10 * time.Millisecond
+	go q.run(sink.write, nil, collecting(DroppedEventsTable))
+
+	q.drop(testEventTable, 3)
+	require.Eventually(t, func() bool { return len(sink.dropReports()) == 1 }, 5*time.Second, time.Millisecond)
+	q.drop(testEventTable, 2)
+	require.Eventually(t, func() bool { return len(sink.dropReports()) == 2 }, 5*time.Second, time.Millisecond)
+	require.True(t, q.stop())
+
+	assert.Equal(t, []DroppedEvents{
+		{DroppedTable: testEventTable, Dropped: 3, Total: 3},
+		{DroppedTable: testEventTable, Dropped: 2, Total: 5},
+	}, sink.dropReports())
+}
@@ -0,0 +1,126 @@
+package trace
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingFile is a file that is rotated once writing to it would make it
+// larger than a maximum size. The current file is named table.jsonl and the
+// rotated ones table.1.jsonl, table.2.jsonl, ..., from the most recent to the
+// oldest. At most maxBackups rotated files are kept. It is thread safe.
+type rotatingFile struct {
+	dir, table string
+	maxSize    int64
+	maxBackups int
+
+	// mut protects the fields below.
+	mut  sync.Mutex
+	file *os.File
+	wr   *bufio.Writer
+	// size is the size of the current file, including buffered writes.
+	size int64
+}
+
+// newRotatingFile opens, or creates, the current file of table in dir.
+func newRotatingFile(dir, table string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{
+		dir:        dir,
+		table:      table,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// path returns the path of the file rotated i times, the current file being
+// rotated 0 times.
+func (f *rotatingFile) path(i int) string {
+	if i == 0 {
+		return filepath.Join(f.dir, f.table+".jsonl")
+	}
+	return filepath.Join(f.dir, fmt.Sprintf("%s.%d.jsonl", f.table, i))
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path(0), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open or create file %s: %w", f.path(0), err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	if f.wr == nil {
+		f.wr = bufio.NewWriter(file)
+	} else {
+		f.wr.Reset(file)
+	}
+	return nil
+}
+
+// Write writes b, a whole line, to the current file, rotating it first if it
+// would grow larger than the maximum size. A line larger than the maximum size
+// is written to a file of its own.
+func (f *rotatingFile) Write(b []byte) (int, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.size > 0 && f.size+int64(len(b)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, fmt.Errorf("failed to rotate %s: %w", f.path(0), err)
+		}
+	}
+	n, err := f.wr.Write(b)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate closes the current file, shifts the rotated files, dropping the
+// oldest one, and opens a new current file.
+func (f *rotatingFile) rotate() error {
+	if err := f.closeFile(); err != nil {
+		return err
+	}
+	if err := os.Remove(f.path(f.maxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := f.maxBackups - 1; i >= 0; i-- {
+		if err := os.Rename(f.path(i), f.path(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) closeFile() error {
+	file := f.file
+	f.file = nil
+	if err := f.wr.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Close flushes and closes the current file. Writes fail afterwards.
+func (f *rotatingFile) Close() error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	return f.closeFile()
+}
@@ -1,6 +1,9 @@
 package schema
 
 import (
+	"time"
+
+	"github.com/tendermint/tendermint/libs/bytes"
 	"github.com/tendermint/tendermint/pkg/trace"
 	"github.com/tendermint/tendermint/types"
 )
@@ -10,11 +13,13 @@ import (
 func ConsensusTables() []string {
 	return []string{
 		RoundStateTable,
+		RoundStepTable,
 		BlockPartsTable,
 		BlockTable,
 		VoteTable,
 		ConsensusStateTable,
 		ProposalTable,
+		ReceivedProposalTable,
 	}
 }
 
@@ -43,6 +48,76 @@ func WriteRoundState(client trace.Tracer, height int64, round int32, step uint8)
 	client.Write(RoundState{Height: height, Round: round, Step: step})
 }
 
+// Schema constants for the "consensus_round_step" table.
+const (
+	// RoundStepTable is the name of the table that stores a row per
+	// (height, round, step) transition of the consensus state machine.
+	RoundStepTable = "consensus_round_step"
+)
+
+// RoundStepTrigger is the kind of event that caused a consensus step
+// transition.
+type RoundStepTrigger string
+
+const (
+	// TriggerMessage is set for transitions caused by a proposal, block part
+	// or vote, whether received from a peer or created by this node.
+	TriggerMessage RoundStepTrigger = "message"
+	// TriggerTimeout is set for transitions caused by a consensus timeout.
+	TriggerTimeout RoundStepTrigger = "timeout"
+	// TriggerTxsAvailable is set for transitions caused by txs becoming
+	// available in the mempool.
+	TriggerTxsAvailable RoundStepTrigger = "txs_available"
+)
+
+// RoundStep describes schema for the "consensus_round_step" table.
+type RoundStep struct {
+	Height                   int64  `json:"height"`
+	Round                    int32  `json:"round"`
+	Step                     string `json:"step"`
+	UnixMillisecondTimestamp int64  `json:"unix_millisecond_timestamp"`
+	Proposer                 string `json:"proposer"`
+	IsProposer               bool   `json:"is_proposer"`
+	Prevotes                 int    `json:"prevotes"`
+	Precommits               int    `json:"precommits"`
+	Trigger                  string `json:"trigger"`
+}
+
+// Table returns the table name for the RoundStep struct.
+func (r RoundStep) Table() string {
+	return RoundStepTable
+}
+
+// WriteRoundStep writes a tracing point for a transition to step at height
+// and round. Prevotes and precommits are the number of votes seen for the
+// round at the time of the transition.
+func WriteRoundStep(
+	client trace.Tracer,
+	height int64,
+	round int32,
+	step string,
+	timestamp time.Time,
+	proposer []byte,
+	isProposer bool,
+	prevotes, precommits int,
+	trigger RoundStepTrigger,
+) {
+	if !client.IsCollecting(RoundStepTable) {
+		return
+	}
+	client.Write(RoundStep{
+		Height:                   height,
+		Round:                    round,
+		Step:                     step,
+		UnixMillisecondTimestamp: timestamp.UnixMilli(),
+		Proposer:                 bytes.HexBytes(proposer).String(),
+		IsProposer:               isProposer,
+		Prevotes:                 prevotes,
+		Precommits:               precommits,
+		Trigger:                  string(trigger),
+	})
+}
+
 // Schema constants for the "consensus_block_parts" table.
 const (
 	// BlockPartsTable is the name of the table that stores the consensus block
@@ -248,3 +323,50 @@ func WriteProposal(
 		TransferType: transferType,
 	})
 }
+
+const (
+	// ReceivedProposalTable is the name of the table that stores a row per
+	// proposal, when first received from a peer.
+	ReceivedProposalTable = "consensus_received_proposal"
+)
+
+// ReceivedProposal describes schema for the "consensus_received_proposal"
+// table. It can be joined with the other consensus tables on (height, round)
+// to compute the propagation latency of proposals across the network.
+type ReceivedProposal struct {
+	Height                           int64  `json:"height"`
+	Round                            int32  `json:"round"`
+	Proposer                         string `json:"proposer"`
+	Peer                             string `json:"peer"`
+	ProposalSize                     int    `json:"proposal_size"`
+	BlockParts                       uint32 `json:"block_parts"`
+	ProposalUnixMillisecondTimestamp int64  `json:"proposal_unix_millisecond_timestamp"`
+	UnixMillisecondTimestamp         int64  `json:"unix_millisecond_timestamp"`
+}
+
+func (r ReceivedProposal) Table() string {
+	return ReceivedProposalTable
+}
+
+// WriteReceivedProposal writes a tracing point for a proposal first received
+// from peer at the local time receivedAt. Proposer is empty if it isn't known
+// yet, and size is the size of the proposal message.
+func WriteReceivedProposal(
+	client trace.Tracer,
+	proposal *types.Proposal,
+	proposer []byte,
+	peer string,
+	size int,
+	receivedAt time.Time,
+) {
+	client.Write(ReceivedProposal{
+		Height:                           proposal.Height,
+		Round:                            proposal.Round,
+		Proposer:                         bytes.HexBytes(proposer).String(),
+		Peer:                             peer,
+		ProposalSize:                     size,
+		BlockParts:                       proposal.BlockID.PartSetHeader.Total,
+		ProposalUnixMillisecondTimestamp: proposal.Timestamp.UnixMilli(),
+		UnixMillisecondTimestamp:         receivedAt.UnixMilli(),
+	})
+}
@@ -0,0 +1,90 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/types"
+)
+
+// recordingTracer keeps the entries written to it for the tables it collects.
+type recordingTracer struct {
+	tables  []string
+	entries []trace.Entry
+}
+
+func (r *recordingTracer) Write(e trace.Entry) {
+	if r.IsCollecting(e.Table()) {
+		r.entries = append(r.entries, e)
+	}
+}
+
+func (r *recordingTracer) IsCollecting(table string) bool {
+	for _, t := range r.tables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recordingTracer) Stop() {}
+
+func TestWriteRoundStep(t *testing.T) {
+	tracer := &recordingTracer{tables: []string{RoundStepTable}}
+	now := time.UnixMilli(1700000000123)
+	WriteRoundStep(tracer, 10, 1, "RoundStepPrecommit", now, []byte{0xab, 0xcd}, true, 3, 1, TriggerTimeout)
+
+	require.Len(t, tracer.entries, 1)
+	assert.Equal(t, RoundStep{
+		Height:                   10,
+		Round:                    1,
+		Step:                     "RoundStepPrecommit",
+		UnixMillisecondTimestamp: 1700000000123,
+		Proposer:                 "ABCD",
+		IsProposer:               true,
+		Prevotes:                 3,
+		Precommits:               1,
+		Trigger:                  "timeout",
+	}, tracer.entries[0])
+	assert.Equal(t, RoundStepTable, tracer.entries[0].Table())
+}
+
+func TestWriteRoundStepNotCollecting(t *testing.T) {
+	tracer := &recordingTracer{tables: []string{RoundStateTable}}
+	WriteRoundStep(tracer, 10, 0, "RoundStepPropose", time.Now(), nil, false, 0, 0, TriggerMessage)
+	assert.Empty(t, tracer.entries)
+}
+
+func TestConsensusTablesIncludeRoundStep(t *testing.T) {
+	assert.Contains(t, ConsensusTables(), RoundStepTable)
+	assert.Contains(t, AllTables(), RoundStepTable)
+}
+
+func TestWriteReceivedProposal(t *testing.T) {
+	tracer := &recordingTracer{tables: []string{ReceivedProposalTable}}
+	proposal := &types.Proposal{
+		Height:    10,
+		Round:     2,
+		BlockID:   types.BlockID{PartSetHeader: types.PartSetHeader{Total: 3}},
+		Timestamp: time.UnixMilli(1700000000000),
+	}
+	WriteReceivedProposal(tracer, proposal, []byte{0xab, 0xcd}, "peer", 256, time.UnixMilli(1700000000123))
+
+	require.Len(t, tracer.entries, 1)
+	assert.Equal(t, ReceivedProposal{
+		Height:                           10,
+		Round:                            2,
+		Proposer:                         "ABCD",
+		Peer:                             "peer",
+		ProposalSize:                     256,
+		BlockParts:                       3,
+		ProposalUnixMillisecondTimestamp: 1700000000000,
+		UnixMillisecondTimestamp:         1700000000123,
+	}, tracer.entries[0])
+	assert.Contains(t, ConsensusTables(), ReceivedProposalTable)
+}
@@ -10,6 +10,7 @@ func MempoolTables() []string {
 	return []string{
 		MempoolTxTable,
 		MempoolPeerStateTable,
+		MempoolEvictedTxTable,
 	}
 }
 
@@ -26,6 +27,7 @@ type MempoolTx struct {
 	Peer         string       `json:"peer"`
 	Size         int          `json:"size"`
 	TransferType TransferType `json:"transfer_type"`
+	SampleRate   float64      `json:"sample_rate"`
 }
 
 // Table returns the table name for the MempoolTx struct.
@@ -34,11 +36,10 @@ func (m MempoolTx) Table() string {
 }
 
 // WriteMempoolTx writes a tracing point for a tx using the predetermined
-// schema for mempool tracing.
+// schema for mempool tracing. Txs are sampled by hash.
 func WriteMempoolTx(client trace.Tracer, peer string, txHash []byte, size int, transferType TransferType) {
-	// this check is redundant to what is checked during client.Write, although it
-	// is an optimization to avoid allocations from the map of fields.
-	if !client.IsCollecting(MempoolTxTable) {
+	rate, ok := Sample(client, MempoolTxTable, txHash)
+	if !ok {
 		return
 	}
 	client.Write(MempoolTx{
@@ -46,6 +47,7 @@ func WriteMempoolTx(client trace.Tracer, peer string, txHash []byte, size int, t
 		Peer:         peer,
 		Size:         size,
 		TransferType: transferType,
+		SampleRate:   rate,
 	})
 }
 
@@ -70,6 +72,7 @@ type MempoolPeerState struct {
 	StateUpdate  MempoolStateUpdateType `json:"state_update"`
 	TxHash       string                 `json:"tx_hash"`
 	TransferType TransferType           `json:"transfer_type"`
+	SampleRate   float64                `json:"sample_rate"`
 }
 
 // Table returns the table name for the MempoolPeerState struct.
@@ -78,7 +81,7 @@ func (m MempoolPeerState) Table() string {
 }
 
 // WriteMempoolPeerState writes a tracing point for the mempool state using
-// the predetermined schema for mempool tracing.
+// the predetermined schema for mempool tracing. Txs are sampled by hash.
 func WriteMempoolPeerState(
 	client trace.Tracer,
 	peer string,
@@ -86,9 +89,8 @@ func WriteMempoolPeerState(
 	txHash []byte,
 	transferType TransferType,
 ) {
-	// this check is redundant to what is checked during client.Write, although it
-	// is an optimization to avoid allocations from creating the map of fields.
-	if !client.IsCollecting(MempoolPeerStateTable) {
+	rate, ok := Sample(client, MempoolPeerStateTable, txHash)
+	if !ok {
 		return
 	}
 	client.Write(MempoolPeerState{
@@ -96,5 +98,42 @@ func WriteMempoolPeerState(
 		StateUpdate:  stateUpdate,
 		TransferType: transferType,
 		TxHash:       bytes.HexBytes(txHash).String(),
+		SampleRate:   rate,
+	})
+}
+
+const (
+	// MempoolEvictedTxTable is the tracing "measurement" (aka table) for the
+	// mempool that stores the transactions evicted to make room for higher
+	// priority ones.
+	MempoolEvictedTxTable = "mempool_evicted_tx"
+)
+
+// MempoolEvictedTx describes the schema for the "mempool_evicted_tx" table.
+type MempoolEvictedTx struct {
+	TxHash     string  `json:"tx_hash"`
+	Size       int64   `json:"size"`
+	Priority   int64   `json:"priority"`
+	SampleRate float64 `json:"sample_rate"`
+}
+
+// Table returns the table name for the MempoolEvictedTx struct.
+func (m MempoolEvictedTx) Table() string {
+	return MempoolEvictedTxTable
+}
+
+// WriteMempoolEvictedTx writes a tracing point for a tx evicted from the
+// mempool using the predetermined schema for mempool tracing. Txs are sampled
+// by hash.
+func WriteMempoolEvictedTx(client trace.Tracer, txHash []byte, size, priority int64) {
+	rate, ok := Sample(client, MempoolEvictedTxTable, txHash)
+	if !ok {
+		return
+	}
+	client.Write(MempoolEvictedTx{
+		TxHash:     bytes.HexBytes(txHash).String(),
+		Size:       size,
+		Priority:   priority,
+		SampleRate: rate,
 	})
 }
@@ -1,6 +1,11 @@
 package schema
 
-import "github.com/tendermint/tendermint/pkg/trace"
+import (
+	"time"
+
+	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/pkg/trace"
+)
 
 const (
 	ABCITable = "abci"
@@ -40,3 +45,93 @@ func WriteABCI(client trace.Tracer, traceType ABCIUpdate, height int64, round in
 		Round:     round,
 	})
 }
+
+const (
+	// ABCICallTable is the tracing "measurement" (aka table) that stores a
+	// row per call made to the application through the proxy app
+	// connections.
+	ABCICallTable = "abci_call"
+)
+
+// ABCICall describes schema for the "abci_call" table.
+type ABCICall struct {
+	Method              string  `json:"method"`
+	Height              int64   `json:"height"`
+	RequestSize         int     `json:"request_size"`
+	ResponseSize        int     `json:"response_size"`
+	LatencyMicroseconds int64   `json:"latency_microseconds"`
+	Code                uint32  `json:"code"`
+	Error               string  `json:"error"`
+	SampleRate          float64 `json:"sample_rate"`
+}
+
+// Table returns the table name for the ABCICall struct and fullfills the
+// trace.Entry interface.
+func (m ABCICall) Table() string {
+	return ABCICallTable
+}
+
+// WriteABCICall writes a trace for a call to the application. Height is 0 for
+// methods not tied to a height, and code is the code of the response, if it
+// has one. Calls are sampled at random, and sampleRate is the fraction of the
+// calls to method already sampled by the caller.
+func WriteABCICall(
+	client trace.Tracer,
+	method string,
+	height int64,
+	requestSize, responseSize int,
+	latency time.Duration,
+	code uint32,
+	err error,
+	sampleRate float64,
+) {
+	rate, ok := Sample(client, ABCICallTable, nil)
+	if !ok {
+		return
+	}
+	call := ABCICall{
+		Method:              method,
+		Height:              height,
+		RequestSize:         requestSize,
+		ResponseSize:        responseSize,
+		LatencyMicroseconds: latency.Microseconds(),
+		Code:                code,
+		SampleRate:          rate * sampleRate,
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	client.Write(call)
+}
+
+const (
+	// AppHashMismatchTable is the tracing "measurement" (aka table) that
+	// stores a row when the app hash computed by the application diverges
+	// from the one in a committed block.
+	AppHashMismatchTable = "app_hash_mismatch"
+)
+
+// AppHashMismatch describes schema for the "app_hash_mismatch" table.
+type AppHashMismatch struct {
+	Height          int64  `json:"height"`
+	ExpectedAppHash string `json:"expected_app_hash"`
+	ActualAppHash   string `json:"actual_app_hash"`
+	ReportFile      string `json:"report_file"`
+}
+
+// Table returns the table name for the AppHashMismatch struct and fullfills
+// the trace.Entry interface.
+func (m AppHashMismatch) Table() string {
+	return AppHashMismatchTable
+}
+
+// WriteAppHashMismatch writes a trace for an app hash mismatch at height,
+// whose diagnostic report was written to reportFile, if any.
+func WriteAppHashMismatch(client trace.Tracer, height int64, expected, actual []byte, reportFile string) {
+	client.Write(AppHashMismatch{
+		Height:          height,
+		ExpectedAppHash: bytes.HexBytes(expected).String(),
+		ActualAppHash:   bytes.HexBytes(actual).String(),
+		ReportFile:      reportFile,
+	})
+}
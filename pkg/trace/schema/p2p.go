@@ -1,6 +1,10 @@
 package schema
 
-import "github.com/tendermint/tendermint/pkg/trace"
+import (
+	"time"
+
+	"github.com/tendermint/tendermint/pkg/trace"
+)
 
 // P2PTables returns the list of tables that are used for p2p tracing.
 func P2PTables() []string {
@@ -8,6 +12,7 @@ func P2PTables() []string {
 		PeersTable,
 		PendingBytesTable,
 		ReceivedBytesTable,
+		PeerBandwidthTable,
 	}
 }
 
@@ -80,3 +85,54 @@ func (s ReceivedBytes) Table() string {
 func WriteReceivedBytes(client trace.Tracer, peerID string, channel byte, bytes int) {
 	client.Write(ReceivedBytes{PeerID: peerID, Channel: channel, Bytes: bytes})
 }
+
+const (
+	// PeerBandwidthTable is the name of the table that stores the traffic of
+	// each channel of each peer, written periodically.
+	PeerBandwidthTable = "peer_bandwidth"
+)
+
+// PeerBandwidth describes schema for the "peer_bandwidth" table. Each row is
+// the traffic of a channel of a peer during the interval since the previous
+// row for that peer. Bytes include the framing of the packets, and the RTT is
+// the one of the last ping answered by the peer.
+type PeerBandwidth struct {
+	PeerID               string `json:"peer_id"`
+	Channel              byte   `json:"channel"`
+	BytesSent            int64  `json:"bytes_sent"`
+	BytesReceived        int64  `json:"bytes_received"`
+	MsgsSent             int64  `json:"msgs_sent"`
+	MsgsReceived         int64  `json:"msgs_received"`
+	SendQueueSize        int    `json:"send_queue_size"`
+	RTTMilliseconds      int64  `json:"rtt_milliseconds"`
+	IntervalMilliseconds int64  `json:"interval_milliseconds"`
+}
+
+// Table returns the table name for the PeerBandwidth struct.
+func (p PeerBandwidth) Table() string {
+	return PeerBandwidthTable
+}
+
+// WritePeerBandwidth writes a tracing point for the traffic of a channel of a
+// peer during interval.
+func WritePeerBandwidth(
+	client trace.Tracer,
+	peerID string,
+	channel byte,
+	bytesSent, bytesReceived int64,
+	msgsSent, msgsReceived int64,
+	sendQueueSize int,
+	rtt, interval time.Duration,
+) {
+	client.Write(PeerBandwidth{
+		PeerID:               peerID,
+		Channel:              channel,
+		BytesSent:            bytesSent,
+		BytesReceived:        bytesReceived,
+		MsgsSent:             msgsSent,
+		MsgsReceived:         msgsReceived,
+		SendQueueSize:        sendQueueSize,
+		RTTMilliseconds:      rtt.Milliseconds(),
+		IntervalMilliseconds: interval.Milliseconds(),
+	})
+}
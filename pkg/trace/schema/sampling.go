@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/pkg/trace"
+)
+
+// Sample reports whether to write an event to table, according to the sample
+// rate the client has for table, and returns that rate, to be recorded in the
+// row so that analysis can re-weight the sampled events.
+//
+// If key isn't nil, e.g. the hash of a tx, the decision only depends on the
+// key and the rate: the events with the same key are either all written or all
+// dropped, and those written to a table are also written to the tables with a
+// higher rate, so that they can be correlated across tables. Otherwise events
+// are sampled at random.
+func Sample(client trace.Tracer, table string, key []byte) (float64, bool) {
+	if !client.IsCollecting(table) {
+		return 0, false
+	}
+	rate := 1.0
+	if s, ok := client.(trace.Sampler); ok {
+		rate = s.SampleRate(table)
+	}
+	switch {
+	case rate >= 1:
+		return rate, true
+	case rate <= 0:
+		return rate, false
+	case key != nil:
+		return rate, sampleKey(key) < rate
+	default:
+		return rate, rand.Float64() < rate //nolint:gosec
+	}
+}
+
+// sampleKey maps key to a uniformly distributed number in [0, 1).
+func sampleKey(key []byte) float64 {
+	h := binary.BigEndian.Uint64(tmhash.Sum(key))
+	return float64(h>>11) / float64(uint64(1)<<53)
+}
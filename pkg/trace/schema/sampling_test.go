@@ -0,0 +1,122 @@
+package schema
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// samplingTracer records the entries of the tables it collects, sampled at the
+// given rates.
+type samplingTracer struct {
+	recordingTracer
+	rates map[string]float64
+}
+
+func (s *samplingTracer) SampleRate(table string) float64 {
+	if rate, ok := s.rates[table]; ok {
+		return rate
+	}
+	return 1
+}
+
+func testKey(i int) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(i))
+	return key
+}
+
+func TestSampleRate(t *testing.T) {
+	const n = 20000
+	tracer := &samplingTracer{
+		recordingTracer: recordingTracer{tables: []string{MempoolTxTable, ABCICallTable, BlockTable}},
+		rates:           map[string]float64{MempoolTxTable: 0.1, ABCICallTable: 0.25, BlockTable: 0},
+	}
+	for _, tc := range []struct {
+		table string
+		keyed bool
+		rate  float64
+	}{
+		{MempoolTxTable, true, 0.1},
+		{ABCICallTable, false, 0.25},
+		{BlockTable, false, 0},
+		{MempoolPeerStateTable, false, 0}, // not collected
+	} {
+		sampled := 0
+		for i := 0; i < n; i++ {
+			var key []byte
+			if tc.keyed {
+				key = testKey(i)
+			}
+			if _, ok := Sample(tracer, tc.table, key); ok {
+				sampled++
+			}
+		}
+		assert.InDelta(t, tc.rate, float64(sampled)/n, 0.02, tc.table)
+	}
+
+	// tables without a rate, or traced by a tracer that doesn't sample, are
+	// all written
+	rate, ok := Sample(tracer, BlockTable+"_other", nil)
+	assert.False(t, ok)
+	assert.Zero(t, rate)
+	tracer.tables = append(tracer.tables, VoteTable)
+	rate, ok = Sample(tracer, VoteTable, nil)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, rate)
+	rate, ok = Sample(&recordingTracer{tables: []string{MempoolTxTable}}, MempoolTxTable, testKey(1))
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, rate)
+}
+
+func TestSampleKeyStable(t *testing.T) {
+	tracer := &samplingTracer{
+		recordingTracer: recordingTracer{tables: []string{MempoolTxTable, MempoolPeerStateTable, ABCICallTable}},
+		rates:           map[string]float64{MempoolTxTable: 0.2, MempoolPeerStateTable: 0.2, ABCICallTable: 0.5},
+	}
+	for i := 0; i < 1000; i++ {
+		key := testKey(i)
+		_, tx := Sample(tracer, MempoolTxTable, key)
+		// the decision is the same for a key, in tables with the same rate
+		_, again := Sample(tracer, MempoolTxTable, key)
+		_, state := Sample(tracer, MempoolPeerStateTable, key)
+		assert.Equal(t, tx, again)
+		assert.Equal(t, tx, state)
+		// and keys sampled at a lower rate are sampled at a higher one
+		if _, call := Sample(tracer, ABCICallTable, key); tx {
+			assert.True(t, call)
+		}
+	}
+}
+
+func TestWriteMempoolTxSampleRate(t *testing.T) {
+	tracer := &samplingTracer{
+		recordingTracer: recordingTracer{tables: []string{MempoolTxTable}},
+		rates:           map[string]float64{MempoolTxTable: 0.5},
+	}
+	written := 0
+	for i := 0; i < 100; i++ {
+		key := testKey(i)
+		if _, ok := Sample(tracer, MempoolTxTable, key); ok {
+			written++
+		}
+		WriteMempoolTx(tracer, "peer", key, 10, Download)
+	}
+	require.Len(t, tracer.entries, written)
+	for _, e := range tracer.entries {
+		assert.Equal(t, 0.5, e.(MempoolTx).SampleRate)
+	}
+}
+
+func TestWriteABCICallSampleRate(t *testing.T) {
+	tracer := &samplingTracer{
+		recordingTracer: recordingTracer{tables: []string{ABCICallTable}},
+		rates:           map[string]float64{ABCICallTable: 1},
+	}
+	WriteABCICall(tracer, "check_tx", 0, 1, 1, 0, 0, nil, 0.1)
+	require.Len(t, tracer.entries, 1)
+	// the rate includes the sampling by the caller
+	assert.Equal(t, 0.1, tracer.entries[0].(ABCICall).SampleRate)
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/pkg/trace"
 )
 
 func init() {
@@ -15,7 +16,8 @@ func AllTables() []string {
 	tables = append(tables, MempoolTables()...)
 	tables = append(tables, ConsensusTables()...)
 	tables = append(tables, P2PTables()...)
-	tables = append(tables, ABCITable)
+	tables = append(tables, ABCITable, ABCICallTable, AppHashMismatchTable)
+	tables = append(tables, trace.DroppedEventsTable)
 	return tables
 }
 
@@ -0,0 +1,142 @@
+package trace
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+// TableToggler is implemented by the tracers whose tables can be enabled and
+// disabled at runtime.
+type TableToggler interface {
+	// EnabledTables returns the tables being collected, sorted by name.
+	EnabledTables() []TableStatus
+	// EnableTable starts collecting table. If d is positive, the table is
+	// disabled again after d, otherwise it stays enabled.
+	EnableTable(table string, d time.Duration)
+	// DisableTable stops collecting table.
+	DisableTable(table string)
+}
+
+// TableStatus describes a table being collected.
+type TableStatus struct {
+	Table string
+	// DisableAt is the time the table is disabled at, zero if it stays
+	// enabled.
+	DisableAt time.Time
+}
+
+// tableSet is the set of tables collected by a tracer, which can be changed at
+// runtime. Reads don't lock, since they happen for every event: the set is
+// copied on write. It is thread safe.
+type tableSet struct {
+	logger log.Logger
+
+	// enabled maps the enabled tables to the time they are disabled at, zero
+	// if they stay enabled.
+	enabled atomic.Pointer[map[string]time.Time]
+
+	// mtx serializes the changes to the set.
+	mtx    sync.Mutex
+	timers map[string]*time.Timer
+}
+
+func newTableSet(tables []string, logger log.Logger) *tableSet {
+	enabled := make(map[string]time.Time, len(tables))
+	for _, table := range tables {
+		enabled[table] = time.Time{}
+	}
+	s := &tableSet{logger: logger, timers: make(map[string]*time.Timer)}
+	s.enabled.Store(&enabled)
+	return s
+}
+
+// IsCollecting reports whether table is enabled.
+func (s *tableSet) IsCollecting(table string) bool {
+	_, has := (*s.enabled.Load())[table]
+	return has
+}
+
+// EnabledTables returns the enabled tables, sorted by name.
+func (s *tableSet) EnabledTables() []TableStatus {
+	enabled := *s.enabled.Load()
+	tables := make([]TableStatus, 0, len(enabled))
+	for table, disableAt := range enabled {
+		tables = append(tables, TableStatus{Table: table, DisableAt: disableAt})
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Table < tables[j].Table })
+	return tables
+}
+
+// EnableTable enables table, for d if positive. Enabling a table already
+// enabled resets its duration.
+func (s *tableSet) EnableTable(table string, d time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.stopTimer(table)
+	var disableAt time.Time
+	if d > 0 {
+		disableAt = time.Now().Add(d)
+		var timer *time.Timer
+		// the timer is assigned before the function can lock s.mtx
+		timer = time.AfterFunc(d, func() { s.expire(table, timer) })
+		s.timers[table] = timer
+	}
+	s.update(func(enabled map[string]time.Time) { enabled[table] = disableAt })
+	if d > 0 {
+		s.logger.Info("enabled trace table", "table", table, "duration", d)
+	} else {
+		s.logger.Info("enabled trace table", "table", table)
+	}
+}
+
+// DisableTable disables table.
+func (s *tableSet) DisableTable(table string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.disable(table)
+}
+
+// expire disables table once its duration elapsed, unless it was enabled
+// again since the timer was started.
+func (s *tableSet) expire(table string, timer *time.Timer) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.timers[table] != timer {
+		return
+	}
+	s.disable(table)
+}
+
+// disable removes table from the set. The caller must hold s.mtx.
+func (s *tableSet) disable(table string) {
+	s.stopTimer(table)
+	if !s.IsCollecting(table) {
+		return
+	}
+	s.update(func(enabled map[string]time.Time) { delete(enabled, table) })
+	s.logger.Info("disabled trace table", "table", table)
+}
+
+// stopTimer stops the timer disabling table, if any. The caller must hold
+// s.mtx.
+func (s *tableSet) stopTimer(table string) {
+	if timer, ok := s.timers[table]; ok {
+		timer.Stop()
+		delete(s.timers, table)
+	}
+}
+
+// update stores a copy of the set modified by f. The caller must hold s.mtx.
+func (s *tableSet) update(f func(map[string]time.Time)) {
+	current := *s.enabled.Load()
+	enabled := make(map[string]time.Time, len(current)+1)
+	for table, disableAt := range current {
+		enabled[table] = disableAt
+	}
+	f(enabled)
+	s.enabled.Store(&enabled)
+}
@@ -0,0 +1,81 @@
+package trace
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+func TestTableSetToggle(t *testing.T) {
+	s := newTableSet([]string{"configured"}, log.NewNopLogger())
+	assert.True(t, s.IsCollecting("configured"))
+	assert.False(t, s.IsCollecting(testEventTable))
+
+	s.EnableTable(testEventTable, 0)
+	assert.True(t, s.IsCollecting(testEventTable))
+	assert.Equal(t, []TableStatus{{Table: "configured"}, {Table: testEventTable}}, s.EnabledTables())
+
+	s.DisableTable("configured")
+	s.DisableTable("unknown")
+	assert.False(t, s.IsCollecting("configured"))
+	assert.Equal(t, []TableStatus{{Table: testEventTable}}, s.EnabledTables())
+}
+
+func TestTableSetEnableFor(t *testing.T) {
+	s := newTableSet(nil, log.NewNopLogger())
+
+	start := time.Now()
+	s.EnableTable(testEventTable, 50*time.Millisecond)
+	assert.True(t, s.IsCollecting(testEventTable))
+	status := s.EnabledTables()
+	require.Len(t, status, 1)
+	assert.WithinDuration(t, start.Add(50*time.Millisecond), status[0].DisableAt, 50*time.Millisecond)
+	require.Eventually(t, func() bool { return !s.IsCollecting(testEventTable) }, 5*time.Second, time.Millisecond)
+
+	// enabling the table again cancels its timer
+	s.EnableTable(testEventTable, 50*time.Millisecond)
+	s.EnableTable(testEventTable, 0)
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, s.IsCollecting(testEventTable))
+	assert.Equal(t, []TableStatus{{Table: testEventTable}}, s.EnabledTables())
+}
+
+// TestNewTracerToggleTable enables a table of a combined tracer mid-run, and
+// checks that its events are saved by both tracers until it is disabled.
+func TestNewTracerToggleTable(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SetRoot(t.TempDir())
+	cfg.Instrumentation.TraceType = "local, file"
+	cfg.Instrumentation.TracingTables = ""
+
+	client, err := NewTracer(cfg, log.NewNopLogger(), "test_chain", "test_node")
+	require.NoError(t, err)
+	toggler, ok := client.(TableToggler)
+	require.True(t, ok)
+
+	client.Write(testEvent{City: "Annecy", Length: 0})
+	toggler.EnableTable(testEventTable, 0)
+	assert.True(t, client.IsCollecting(testEventTable))
+	client.Write(testEvent{City: "Paris", Length: 1})
+	client.Write(testEvent{City: "Paris", Length: 2})
+	toggler.DisableTable(testEventTable)
+	assert.False(t, client.IsCollecting(testEventTable))
+	client.Write(testEvent{City: "Migennes", Length: 3})
+	client.Stop()
+
+	for _, path := range []string{
+		filepath.Join(cfg.RootDir, "data", "traces", testEventTable+".jsonl"),
+		filepath.Join(cfg.RootDir, cfg.Instrumentation.TraceFileDir, testEventTable+".jsonl"),
+	} {
+		events := decodeTableFile(t, path)
+		require.Len(t, events, 2, path)
+		assert.Equal(t, 1, events[0].Msg.Length)
+		assert.Equal(t, 2, events[1].Msg.Length)
+	}
+}
@@ -3,6 +3,7 @@ package trace
 import (
 	"errors"
 	"os"
+	"time"
 
 	"github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/libs/log"
@@ -22,18 +23,139 @@ type Tracer interface {
 	Stop()
 }
 
-func NewTracer(cfg *config.Config, logger log.Logger, chainID, nodeID string) (Tracer, error) {
-	switch cfg.Instrumentation.TraceType {
+// Sampler is implemented by the tracers that write only a sample of the
+// events of some tables.
+type Sampler interface {
+	// SampleRate returns the fraction of the events of table to write,
+	// between 0 and 1.
+	SampleRate(table string) float64
+}
+
+// sampleRates maps tables to their sample rate. Tables missing from the map
+// are not sampled.
+type sampleRates map[string]float64
+
+func (s sampleRates) SampleRate(table string) float64 {
+	if rate, ok := s[table]; ok {
+		return rate
+	}
+	return 1
+}
+
+// NewTracer creates the tracer of the configured type. Several types may be
+// configured as a comma separated list, in which case the returned tracer
+// writes to all of them. The tables collected by the tracer can be changed at
+// runtime if it implements TableToggler.
+func NewTracer(cfg *config.Config, logger log.Logger, chainID, nodeID string, opts ...Option) (Tracer, error) {
+	traceTypes := splitAndTrimEmpty(cfg.Instrumentation.TraceType, ",", " ")
+	if len(traceTypes) == 1 {
+		return newTracer(traceTypes[0], cfg, logger, chainID, nodeID, opts)
+	}
+
+	// the tracers share their tables, so that toggling one toggles it for all
+	tables := newTableSet(splitAndTrimEmpty(cfg.Instrumentation.TracingTables, ",", " "), logger)
+	opts = append(opts, withTables(tables))
+	tracers := make(multiTracer, 0, len(traceTypes))
+	for _, traceType := range traceTypes {
+		tracer, err := newTracer(traceType, cfg, logger, chainID, nodeID, opts)
+		if err != nil {
+			tracers.Stop()
+			return nil, err
+		}
+		tracers = append(tracers, tracer)
+	}
+	return tracers, nil
+}
+
+func newTracer(
+	traceType string,
+	cfg *config.Config,
+	logger log.Logger,
+	chainID, nodeID string,
+	opts []Option,
+) (Tracer, error) {
+	switch traceType {
 	case "local":
-		return NewLocalTracer(cfg, logger, chainID, nodeID)
+		return NewLocalTracer(cfg, logger, chainID, nodeID, opts...)
+	case "file":
+		return NewFileTracer(cfg, logger, chainID, nodeID, opts...)
+	case "otlp":
+		return NewOTLPTracer(cfg, logger, chainID, nodeID, opts...)
+	case "buffer":
+		return NewBufferTracer(cfg, logger, chainID, nodeID, opts...)
 	case "noop":
 		return NoOpTracer(), nil
 	default:
-		logger.Error("unknown tracer type, using noop", "type", cfg.Instrumentation.TraceType)
+		logger.Error("unknown tracer type, using noop", "type", traceType)
 		return NoOpTracer(), nil
 	}
 }
 
+// multiTracer writes to several tracers.
+type multiTracer []Tracer
+
+func (m multiTracer) Write(e Entry) {
+	for _, t := range m {
+		t.Write(e)
+	}
+}
+
+func (m multiTracer) IsCollecting(table string) bool {
+	for _, t := range m {
+		if t.IsCollecting(table) {
+			return true
+		}
+	}
+	return false
+}
+
+// SampleRate returns the sample rate of the first tracer sampling events,
+// since all the tracers are created from the same config.
+func (m multiTracer) SampleRate(table string) float64 {
+	for _, t := range m {
+		if s, ok := t.(Sampler); ok {
+			return s.SampleRate(table)
+		}
+	}
+	return 1
+}
+
+// EnabledTables returns the tables enabled in the first tracer whose tables
+// can be toggled, since all the tracers share their tables.
+func (m multiTracer) EnabledTables() []TableStatus {
+	if t, ok := m.toggler(); ok {
+		return t.EnabledTables()
+	}
+	return nil
+}
+
+func (m multiTracer) EnableTable(table string, d time.Duration) {
+	if t, ok := m.toggler(); ok {
+		t.EnableTable(table, d)
+	}
+}
+
+func (m multiTracer) DisableTable(table string) {
+	if t, ok := m.toggler(); ok {
+		t.DisableTable(table)
+	}
+}
+
+func (m multiTracer) toggler() (TableToggler, bool) {
+	for _, t := range m {
+		if toggler, ok := t.(TableToggler); ok {
+			return toggler, true
+		}
+	}
+	return nil, false
+}
+
+func (m multiTracer) Stop() {
+	for _, t := range m {
+		t.Stop()
+	}
+}
+
 func NoOpTracer() Tracer {
 	return &noOpTracer{}
 }
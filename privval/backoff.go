@@ -0,0 +1,44 @@
+package privval
+
+import (
+	"time"
+
+	cmtrand "github.com/tendermint/tendermint/libs/rand"
+)
+
+// backoff computes the waits between attempts to reach a remote peer. The
+// waits double from min up to max with each failed attempt, and are jittered
+// so that many clients don't retry in lockstep.
+type backoff struct {
+	min  time.Duration
+	max  time.Duration
+	next time.Duration
+}
+
+func newBackoff(min, max time.Duration) *backoff {
+	if max < min {
+		max = min
+	}
+	return &backoff{min: min, max: max, next: min}
+}
+
+// wait returns the wait before the next attempt, between half and all of the
+// current backoff, and doubles the backoff.
+func (b *backoff) wait() time.Duration {
+	wait := b.next
+	if b.next < b.max {
+		b.next *= 2
+		if b.next > b.max {
+			b.next = b.max
+		}
+	}
+	if wait < 2 {
+		return wait
+	}
+	return wait/2 + time.Duration(cmtrand.Int63n(int64(wait/2)))
+}
+
+// reset starts the backoff over from min, after a successful attempt.
+func (b *backoff) reset() {
+	b.next = b.min
+}
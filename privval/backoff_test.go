@@ -0,0 +1,28 @@
+package privval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	b := newBackoff(100*time.Millisecond, time.Second)
+
+	// the waits are jittered down to half of the doubling backoff, which is
+	// capped at the maximum
+	for _, backoff := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		wait := b.wait()
+		assert.GreaterOrEqual(t, wait, backoff*time.Millisecond/2)
+		assert.Less(t, wait, backoff*time.Millisecond)
+	}
+
+	b.reset()
+	assert.Less(t, b.wait(), 100*time.Millisecond)
+
+	// without a maximum, the wait doesn't grow
+	b = newBackoff(100*time.Millisecond, 0)
+	b.wait()
+	assert.Less(t, b.wait(), 100*time.Millisecond)
+}
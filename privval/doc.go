@@ -6,6 +6,22 @@ Package privval provides different implementations of the types.PrivValidator.
 FilePV is the simplest implementation and developer default.
 It uses one file for the private key and another to store state.
 
+During a consensus key rotation, the key file holds both the old and the new
+key. The new key is stored in the optional "next_priv_key" field next to
+"priv_key" ("next_pub_key" is derived from it on load):
+
+	{
+	  "address": "...",
+	  "pub_key": {...},
+	  "priv_key": {...},
+	  "next_pub_key": {...},
+	  "next_priv_key": {...}
+	}
+
+FilePV then signs with whichever key the current validator set contains. Once
+the old key has left the validator set, the rotation is completed by moving the
+next key into "priv_key" and "pub_key" and removing the "next_" fields.
+
 # SignerListenerEndpoint
 
 SignerListenerEndpoint establishes a connection to an external process,
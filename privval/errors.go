@@ -22,6 +22,20 @@ var (
 	ErrWriteTimeout       = errors.New("endpoint write timed out")
 )
 
+// DoubleSignError is returned when a signer refuses to sign a vote or a
+// proposal conflicting with what it signed last, since it would double sign.
+type DoubleSignError struct {
+	Reason string
+}
+
+func (e *DoubleSignError) Error() string {
+	return e.Reason
+}
+
+// RemoteSignerErrorCodeDoubleSign is the code of the RemoteSignerError
+// replied by signers refusing to double sign.
+const RemoteSignerErrorCodeDoubleSign = 1
+
 // RemoteSignerError allows (remote) validators to include meaningful error
 // descriptions in their reply.
 type RemoteSignerError struct {
@@ -0,0 +1,408 @@
+package privval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/libs/log"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultFailoverThreshold = 3
+	defaultFailbackMinWait   = 100 * time.Millisecond
+	defaultFailbackMaxWait   = 10 * time.Second
+)
+
+// The states of the endpoints of a FailoverSignerClient.
+const (
+	signerEndpointActive  = "active"
+	signerEndpointStandby = "standby"
+	signerEndpointFailed  = "failed"
+)
+
+var signerEndpointStates = []string{signerEndpointActive, signerEndpointStandby, signerEndpointFailed}
+
+// FailoverSignerClientOption sets an optional parameter on the
+// FailoverSignerClient.
+type FailoverSignerClientOption func(*FailoverSignerClient)
+
+// FailoverSignerClientThreshold sets the number of consecutive failed
+// requests to an endpoint after which the client fails over to the next one.
+//
+// Default: 3
+func FailoverSignerClientThreshold(failures int) FailoverSignerClientOption {
+	return func(fc *FailoverSignerClient) { fc.threshold = failures }
+}
+
+// FailoverSignerClientBackoff sets the minimum and maximum waits between
+// attempts to reach a failed endpoint again.
+//
+// Default: 100ms and 10s
+func FailoverSignerClientBackoff(minWait, maxWait time.Duration) FailoverSignerClientOption {
+	return func(fc *FailoverSignerClient) { fc.minWait, fc.maxWait = minWait, maxWait }
+}
+
+// FailoverSignerClientMetrics sets the metrics.
+func FailoverSignerClientMetrics(metrics *Metrics) FailoverSignerClientOption {
+	return func(fc *FailoverSignerClient) { fc.metrics = metrics }
+}
+
+// FailoverSignerClient implements PrivValidator over several remote signer
+// endpoints in priority order. Requests go to the active endpoint, the first
+// one at start. After a number of consecutive failed requests, the endpoint
+// is marked as failed and the client fails over to the endpoint with the
+// highest priority which hasn't failed. Failed endpoints are pinged, with an
+// exponential backoff, until they recover; the client then fails back to them
+// if they have a higher priority than the active endpoint.
+type FailoverSignerClient struct {
+	logger    log.Logger
+	metrics   *Metrics
+	threshold int
+	minWait   time.Duration
+	maxWait   time.Duration
+
+	mtx       cmtsync.Mutex
+	endpoints []*failoverEndpoint
+	active    int
+	// address is the address of the key all the endpoints must sign with,
+	// once verified by VerifyPubKeys.
+	address types.Address
+
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+type failoverEndpoint struct {
+	client   *SignerClient
+	name     string
+	state    string
+	failures int
+}
+
+var _ types.KeyRotatingPrivValidator = (*FailoverSignerClient)(nil)
+
+// NewFailoverSignerClient returns a FailoverSignerClient sending requests to
+// the given clients, in priority order.
+func NewFailoverSignerClient(
+	logger log.Logger,
+	clients []*SignerClient,
+	options ...FailoverSignerClientOption,
+) (*FailoverSignerClient, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no signer clients")
+	}
+
+	fc := &FailoverSignerClient{
+		logger:    logger,
+		metrics:   NopMetrics(),
+		threshold: defaultFailoverThreshold,
+		minWait:   defaultFailbackMinWait,
+		maxWait:   defaultFailbackMaxWait,
+		endpoints: make([]*failoverEndpoint, len(clients)),
+		quit:      make(chan struct{}),
+	}
+	for _, optionFunc := range options {
+		optionFunc(fc)
+	}
+
+	for i, client := range clients {
+		ep := &failoverEndpoint{client: client, name: fmt.Sprintf("#%d", i), state: signerEndpointStandby}
+		if client.endpoint.listener != nil {
+			ep.name = client.endpoint.listener.Addr().String()
+		}
+		fc.endpoints[i] = ep
+	}
+	fc.endpoints[0].state = signerEndpointActive
+	for _, ep := range fc.endpoints {
+		fc.setStateMetrics(ep)
+	}
+
+	return fc, nil
+}
+
+// Close stops pinging the failed endpoints and closes the connections to all
+// of them.
+func (fc *FailoverSignerClient) Close() error {
+	fc.closeOnce.Do(func() { close(fc.quit) })
+	for _, ep := range fc.endpoints {
+		if err := ep.client.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsConnected indicates whether the active endpoint is connected to a remote
+// signer.
+func (fc *FailoverSignerClient) IsConnected() bool {
+	_, client := fc.activeEndpoint()
+	return client.IsConnected()
+}
+
+// WaitForConnection waits maxWait for the active endpoint to be connected or
+// returns a timeout error.
+func (fc *FailoverSignerClient) WaitForConnection(maxWait time.Duration) error {
+	_, client := fc.activeEndpoint()
+	return client.WaitForConnection(maxWait)
+}
+
+//--------------------------------------------------------
+// Implement PrivValidator
+
+// Ping sends a ping request to the active remote signer.
+func (fc *FailoverSignerClient) Ping() error {
+	return fc.do(pingSigner)
+}
+
+// GetPubKey retrieves a public key from the active remote signer.
+func (fc *FailoverSignerClient) GetPubKey() (crypto.PubKey, error) {
+	var pk crypto.PubKey
+	err := fc.do(func(sc *SignerClient) (err error) {
+		pk, err = sc.GetPubKey()
+		return err
+	})
+	return pk, err
+}
+
+// VerifyPubKeys retrieves the public key from every remote signer, and
+// returns it if they all hold the same key. Otherwise, failing over could
+// switch the identity of the validator. The endpoints which can't be reached
+// are marked as failed, and only used once they recover with the same key.
+func (fc *FailoverSignerClient) VerifyPubKeys() (crypto.PubKey, error) {
+	var (
+		pubKey      crypto.PubKey
+		pubKeyName  string
+		unreachable []int
+	)
+	for i, ep := range fc.endpoints {
+		pk, err := ep.client.GetPubKey()
+		if err != nil {
+			fc.logger.Error("Can't get pubkey from signer endpoint", "endpoint", ep.name, "err", err)
+			unreachable = append(unreachable, i)
+			continue
+		}
+		if pubKey == nil {
+			pubKey, pubKeyName = pk, ep.name
+			continue
+		}
+		if !pk.Equals(pubKey) {
+			return nil, fmt.Errorf("signer endpoint %s has pubkey %v, while %s has %v",
+				ep.name, pk, pubKeyName, pubKey)
+		}
+	}
+	if pubKey == nil {
+		return nil, errors.New("can't get pubkey from any signer endpoint")
+	}
+
+	fc.mtx.Lock()
+	defer fc.mtx.Unlock()
+	fc.address = pubKey.Address()
+	for _, idx := range unreachable {
+		fc.setState(fc.endpoints[idx], signerEndpointFailed)
+		go fc.pingUntilRecovered(idx)
+	}
+	if fc.endpoints[fc.active].state == signerEndpointFailed {
+		for next := range fc.endpoints {
+			if fc.endpoints[next].state != signerEndpointFailed {
+				fc.activate(next)
+				break
+			}
+		}
+	}
+	return pubKey, nil
+}
+
+// GetPubKeys retrieves all the public keys from the active remote signer.
+func (fc *FailoverSignerClient) GetPubKeys() ([]crypto.PubKey, error) {
+	var pks []crypto.PubKey
+	err := fc.do(func(sc *SignerClient) (err error) {
+		pks, err = sc.GetPubKeys()
+		return err
+	})
+	return pks, err
+}
+
+// SelectKey selects the key all the remote signers sign with.
+func (fc *FailoverSignerClient) SelectKey(address types.Address) error {
+	for _, ep := range fc.endpoints {
+		if err := ep.client.SelectKey(address); err != nil {
+			return err
+		}
+	}
+	fc.mtx.Lock()
+	fc.address = address
+	fc.mtx.Unlock()
+	return nil
+}
+
+// SignVote requests the active remote signer to sign a vote.
+func (fc *FailoverSignerClient) SignVote(chainID string, vote *cmtproto.Vote) error {
+	return fc.do(func(sc *SignerClient) error { return sc.SignVote(chainID, vote) })
+}
+
+// SignProposal requests the active remote signer to sign a proposal.
+func (fc *FailoverSignerClient) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
+	return fc.do(func(sc *SignerClient) error { return sc.SignProposal(chainID, proposal) })
+}
+
+//--------------------------------------------------------
+
+func (fc *FailoverSignerClient) activeEndpoint() (int, *SignerClient) {
+	fc.mtx.Lock()
+	defer fc.mtx.Unlock()
+	return fc.active, fc.endpoints[fc.active].client
+}
+
+// do sends a request to the active endpoint, and fails over to the next
+// endpoint if it failed too many times in a row.
+func (fc *FailoverSignerClient) do(request func(*SignerClient) error) error {
+	idx, client := fc.activeEndpoint()
+	err := request(client)
+
+	fc.mtx.Lock()
+	defer fc.mtx.Unlock()
+
+	ep := fc.endpoints[idx]
+	// errors from the remote signer itself mean the endpoint works
+	if _, ok := err.(*RemoteSignerError); err == nil || ok {
+		ep.failures = 0
+		return err
+	}
+
+	ep.failures++
+	fc.metrics.SignerEndpointFailures.With("endpoint", ep.name).Add(1)
+	if ep.state == signerEndpointFailed || ep.failures < fc.threshold {
+		return err
+	}
+
+	fc.logger.Error("Signer endpoint failed", "endpoint", ep.name, "failures", ep.failures, "err", err)
+	fc.setState(ep, signerEndpointFailed)
+	go fc.pingUntilRecovered(idx)
+
+	if idx == fc.active {
+		for next := range fc.endpoints {
+			if fc.endpoints[next].state != signerEndpointFailed {
+				fc.activate(next)
+				break
+			}
+		}
+	}
+
+	return err
+}
+
+// pingUntilRecovered pings a failed endpoint, with an exponential backoff,
+// until it answers, and then makes it active again if it has a higher
+// priority than the active endpoint. An endpoint holding another key than the
+// verified one stays failed for good.
+func (fc *FailoverSignerClient) pingUntilRecovered(idx int) {
+	ep := fc.endpoints[idx]
+	backoff := newBackoff(fc.minWait, fc.maxWait)
+	for {
+		select {
+		case <-time.After(backoff.wait()):
+		case <-fc.quit:
+			return
+		}
+
+		if err := pingSigner(ep.client); err != nil {
+			fc.logger.Debug("Signer endpoint still failing", "endpoint", ep.name, "err", err)
+			continue
+		}
+		if ok, err := fc.hasVerifiedKey(ep); err != nil {
+			fc.logger.Debug("Signer endpoint still failing", "endpoint", ep.name, "err", err)
+			continue
+		} else if !ok {
+			fc.logger.Error("Signer endpoint holds another key than the other endpoints, not using it",
+				"endpoint", ep.name)
+			return
+		}
+
+		fc.mtx.Lock()
+		ep.failures = 0
+		fc.logger.Info("Signer endpoint recovered", "endpoint", ep.name)
+		switch {
+		case idx == fc.active:
+			fc.setState(ep, signerEndpointActive)
+		case idx < fc.active || fc.endpoints[fc.active].state == signerEndpointFailed:
+			fc.setState(ep, signerEndpointStandby)
+			fc.activate(idx)
+		default:
+			fc.setState(ep, signerEndpointStandby)
+		}
+		fc.mtx.Unlock()
+		return
+	}
+}
+
+// hasVerifiedKey returns true if the endpoint signs with the key verified by
+// VerifyPubKeys, if any.
+func (fc *FailoverSignerClient) hasVerifiedKey(ep *failoverEndpoint) (bool, error) {
+	fc.mtx.Lock()
+	address := fc.address
+	fc.mtx.Unlock()
+	if address == nil {
+		return true, nil
+	}
+	pk, err := ep.client.GetPubKey()
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(pk.Address(), address), nil
+}
+
+// activate makes the endpoint at the given index the active one. The caller
+// must hold the lock.
+func (fc *FailoverSignerClient) activate(idx int) {
+	prev := fc.endpoints[fc.active]
+	if prev.state == signerEndpointActive {
+		fc.setState(prev, signerEndpointStandby)
+	}
+	fc.active = idx
+	fc.setState(fc.endpoints[idx], signerEndpointActive)
+
+	fc.logger.Info("Switched signer endpoint", "from", prev.name, "to", fc.endpoints[idx].name)
+	fc.metrics.SignerFailovers.Add(1)
+}
+
+// setState logs and exports the transition of an endpoint to a new state.
+// The caller must hold the lock.
+func (fc *FailoverSignerClient) setState(ep *failoverEndpoint, state string) {
+	if ep.state == state {
+		return
+	}
+	fc.logger.Info("Signer endpoint state changed", "endpoint", ep.name, "from", ep.state, "to", state)
+	ep.state = state
+	fc.setStateMetrics(ep)
+}
+
+func (fc *FailoverSignerClient) setStateMetrics(ep *failoverEndpoint) {
+	for _, state := range signerEndpointStates {
+		value := 0.0
+		if state == ep.state {
+			value = 1
+		}
+		fc.metrics.SignerEndpointState.With("endpoint", ep.name, "state", state).Set(value)
+	}
+}
+
+// pingSigner sends a ping request to the remote signer of a client. Unlike
+// SignerClient.Ping, it returns an error if the signer can't be reached.
+func pingSigner(sc *SignerClient) error {
+	res, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.PingRequest{}))
+	if err != nil {
+		return err
+	}
+	if res.GetPingResponse() == nil {
+		return ErrUnexpectedResponse
+	}
+	return nil
+}
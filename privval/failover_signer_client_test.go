@@ -0,0 +1,229 @@
+package privval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/log"
+	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestFailoverSignerClient(t *testing.T) {
+	var (
+		logger    = log.TestingLogger()
+		chainID   = cmtrand.Str(12)
+		mockPV    = types.NewMockPV()
+		addrs     = []string{GetFreeLocalhostAddrPort(), GetFreeLocalhostAddrPort()}
+		clients   = make([]*SignerClient, len(addrs))
+		failovers = generic.NewCounter("failovers")
+		metrics   = NopMetrics()
+	)
+	metrics.SignerFailovers = failovers
+
+	for i, addr := range addrs {
+		sc, err := NewSignerClient(newSignerListenerEndpoint(logger, addr, testTimeoutReadWrite), chainID)
+		require.NoError(t, err)
+		clients[i] = sc
+	}
+	fc, err := NewFailoverSignerClient(logger, clients,
+		FailoverSignerClientThreshold(2),
+		FailoverSignerClientBackoff(10*time.Millisecond, 100*time.Millisecond),
+		FailoverSignerClientMetrics(metrics))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := fc.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// the first signer is killed while signing the second vote
+	primary := startFailoverTestSigner(t, addrs[0], chainID, mockPV)
+	signedVotes := 0
+	primary.SetRequestHandler(func(
+		privVal types.PrivValidator,
+		req privvalproto.Message,
+		chainID string,
+	) (privvalproto.Message, error) {
+		if req.GetSignVoteRequest() != nil {
+			signedVotes++
+			if signedVotes == 2 {
+				_ = primary.Stop()
+			}
+		}
+		return DefaultValidationRequestHandler(privVal, req, chainID)
+	})
+	startFailoverTestSigner(t, addrs[1], chainID, mockPV)
+
+	require.NoError(t, fc.SignVote(chainID, failoverTestVote()))
+	assert.Equal(t, 0, fc.active)
+
+	assert.Error(t, fc.SignVote(chainID, failoverTestVote()))
+	assert.Equal(t, 0, fc.active)
+
+	// fails over to the second signer
+	require.Eventually(t, func() bool {
+		return fc.SignVote(chainID, failoverTestVote()) == nil
+	}, 20*time.Second, 10*time.Millisecond)
+	fc.mtx.Lock()
+	assert.Equal(t, 1, fc.active)
+	assert.Equal(t, signerEndpointFailed, fc.endpoints[0].state)
+	assert.Equal(t, signerEndpointActive, fc.endpoints[1].state)
+	fc.mtx.Unlock()
+	assert.EqualValues(t, 1, failovers.Value())
+
+	// and back to the first one once it recovers
+	startFailoverTestSigner(t, addrs[0], chainID, mockPV)
+	require.Eventually(t, func() bool {
+		fc.mtx.Lock()
+		defer fc.mtx.Unlock()
+		return fc.active == 0
+	}, 20*time.Second, 10*time.Millisecond)
+	fc.mtx.Lock()
+	assert.Equal(t, signerEndpointActive, fc.endpoints[0].state)
+	assert.Equal(t, signerEndpointStandby, fc.endpoints[1].state)
+	fc.mtx.Unlock()
+	assert.EqualValues(t, 2, failovers.Value())
+
+	require.NoError(t, fc.SignVote(chainID, failoverTestVote()))
+}
+
+func TestFailoverSignerClientRemoteSignerError(t *testing.T) {
+	var (
+		logger  = log.TestingLogger()
+		chainID = cmtrand.Str(12)
+		addrs   = []string{GetFreeLocalhostAddrPort(), GetFreeLocalhostAddrPort()}
+		clients = make([]*SignerClient, len(addrs))
+	)
+
+	for i, addr := range addrs {
+		sc, err := NewSignerClient(newSignerListenerEndpoint(logger, addr, testTimeoutReadWrite), chainID)
+		require.NoError(t, err)
+		clients[i] = sc
+	}
+	fc, err := NewFailoverSignerClient(logger, clients, FailoverSignerClientThreshold(1))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := fc.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	for _, addr := range addrs {
+		startFailoverTestSigner(t, addr, chainID, types.NewErroringMockPV())
+	}
+
+	// errors of the remote signer don't make the client fail over
+	for i := 0; i < 3; i++ {
+		err := fc.SignVote(chainID, failoverTestVote())
+		require.Error(t, err)
+		assert.IsType(t, &RemoteSignerError{}, err)
+	}
+	assert.Equal(t, 0, fc.active)
+}
+
+func TestFailoverSignerClientVerifyPubKeys(t *testing.T) {
+	newFailoverClient := func(t *testing.T, chainID string, addrs []string) *FailoverSignerClient {
+		clients := make([]*SignerClient, len(addrs))
+		for i, addr := range addrs {
+			sc, err := NewSignerClient(newSignerListenerEndpoint(log.TestingLogger(), addr, testTimeoutReadWrite),
+				chainID)
+			require.NoError(t, err)
+			clients[i] = sc
+		}
+		fc, err := NewFailoverSignerClient(log.TestingLogger(), clients,
+			FailoverSignerClientBackoff(10*time.Millisecond, 100*time.Millisecond))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			if err := fc.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+		return fc
+	}
+
+	t.Run("different keys", func(t *testing.T) {
+		var (
+			chainID = cmtrand.Str(12)
+			addrs   = []string{GetFreeLocalhostAddrPort(), GetFreeLocalhostAddrPort()}
+			fc      = newFailoverClient(t, chainID, addrs)
+		)
+		startFailoverTestSigner(t, addrs[0], chainID, types.NewMockPV())
+		startFailoverTestSigner(t, addrs[1], chainID, types.NewMockPV())
+
+		_, err := fc.VerifyPubKeys()
+		assert.Error(t, err)
+	})
+
+	t.Run("unreachable endpoint", func(t *testing.T) {
+		var (
+			chainID = cmtrand.Str(12)
+			addrs   = []string{GetFreeLocalhostAddrPort(), GetFreeLocalhostAddrPort()}
+			fc      = newFailoverClient(t, chainID, addrs)
+			mockPV  = types.NewMockPV()
+		)
+		startFailoverTestSigner(t, addrs[1], chainID, mockPV)
+
+		pubKey, err := fc.VerifyPubKeys()
+		require.NoError(t, err)
+		expected, err := mockPV.GetPubKey()
+		require.NoError(t, err)
+		assert.Equal(t, expected, pubKey)
+
+		// the first endpoint wasn't verified, hence it isn't used
+		fc.mtx.Lock()
+		assert.Equal(t, 1, fc.active)
+		assert.Equal(t, signerEndpointFailed, fc.endpoints[0].state)
+		fc.mtx.Unlock()
+
+		// nor once it recovers with another key
+		startFailoverTestSigner(t, addrs[0], chainID, types.NewMockPV())
+		assert.Never(t, func() bool {
+			fc.mtx.Lock()
+			defer fc.mtx.Unlock()
+			return fc.endpoints[0].state != signerEndpointFailed
+		}, time.Second, 10*time.Millisecond)
+		require.NoError(t, fc.SignVote(chainID, failoverTestVote()))
+	})
+}
+
+func startFailoverTestSigner(t *testing.T, addr, chainID string, privVal types.PrivValidator) *SignerServer {
+	dialerEndpoint := NewSignerDialerEndpoint(
+		log.TestingLogger(),
+		DialTCPFn(addr, testTimeoutReadWrite, ed25519.GenPrivKey()),
+		SignerDialerEndpointTimeoutReadWrite(testTimeoutReadWrite),
+		SignerDialerEndpointConnRetries(1e6),
+		SignerDialerEndpointRetryBackoff(time.Second),
+	)
+	ss := NewSignerServer(dialerEndpoint, chainID, privVal)
+	require.NoError(t, ss.Start())
+	t.Cleanup(func() {
+		if ss.IsRunning() {
+			if err := ss.Stop(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	return ss
+}
+
+func failoverTestVote() *cmtproto.Vote {
+	hash := cmtrand.Bytes(tmhash.Size)
+	return &cmtproto.Vote{
+		Type:             cmtproto.PrecommitType,
+		Height:           1,
+		Round:            2,
+		BlockID:          cmtproto.BlockID{Hash: hash, PartSetHeader: cmtproto.PartSetHeader{Hash: hash, Total: 2}},
+		Timestamp:        time.Now(),
+		ValidatorAddress: cmtrand.Bytes(20),
+		ValidatorIndex:   1,
+	}
+}
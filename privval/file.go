@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -13,6 +14,7 @@ import (
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
 	cmtjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/log"
 	cmtos "github.com/tendermint/tendermint/libs/os"
 	"github.com/tendermint/tendermint/libs/protoio"
 	"github.com/tendermint/tendermint/libs/tempfile"
@@ -29,6 +31,23 @@ const (
 	stepPrecommit int8 = 3
 )
 
+// stateBackupSuffix is appended to the path of the FilePVLastSignState file to
+// get the path of the backup of its previous version.
+const stateBackupSuffix = ".bak"
+
+// writeStateFile atomically writes the FilePVLastSignState file, flushing it
+// and its directory to disk if fsync is true. It's a variable so tests can
+// inject write failures.
+var writeStateFile = func(filePath string, data []byte, fsync bool) error {
+	if !fsync {
+		return tempfile.WriteFileAtomicNoSync(filePath, data, 0o600)
+	}
+	if err := tempfile.WriteFileAtomic(filePath, data, 0o600); err != nil {
+		return err
+	}
+	return tempfile.SyncDir(filepath.Dir(filePath))
+}
+
 // A vote is either stepPrevote or stepPrecommit.
 func voteToStep(vote *cmtproto.Vote) int8 {
 	switch vote.Type {
@@ -49,6 +68,10 @@ type FilePVKey struct {
 	PubKey  crypto.PubKey  `json:"pub_key"`
 	PrivKey crypto.PrivKey `json:"priv_key"`
 
+	// The new key of a validator rotating its consensus key, if any.
+	NextPubKey  crypto.PubKey  `json:"next_pub_key,omitempty"`
+	NextPrivKey crypto.PrivKey `json:"next_priv_key,omitempty"`
+
 	filePath string
 }
 
@@ -79,7 +102,8 @@ type FilePVLastSignState struct {
 	Signature []byte            `json:"signature,omitempty"`
 	SignBytes cmtbytes.HexBytes `json:"signbytes,omitempty"`
 
-	filePath string
+	filePath  string
+	skipFsync bool
 }
 
 // CheckHRS checks the given height, round, step (HRS) against that of the
@@ -91,23 +115,27 @@ type FilePVLastSignState struct {
 // It panics if the HRS matches the arguments, there's a SignBytes, but no Signature.
 func (lss *FilePVLastSignState) CheckHRS(height int64, round int32, step int8) (bool, error) {
 	if lss.Height > height {
-		return false, fmt.Errorf("height regression. Got %v, last height %v", height, lss.Height)
+		return false, &DoubleSignError{
+			Reason: fmt.Sprintf("height regression. Got %v, last height %v", height, lss.Height),
+		}
 	}
 
 	if lss.Height == height {
 		if lss.Round > round {
-			return false, fmt.Errorf("round regression at height %v. Got %v, last round %v", height, round, lss.Round)
+			return false, &DoubleSignError{
+				Reason: fmt.Sprintf("round regression at height %v. Got %v, last round %v", height, round, lss.Round),
+			}
 		}
 
 		if lss.Round == round {
 			if lss.Step > step {
-				return false, fmt.Errorf(
+				return false, &DoubleSignError{Reason: fmt.Sprintf(
 					"step regression at height %v round %v. Got %v, last step %v",
 					height,
 					round,
 					step,
 					lss.Step,
-				)
+				)}
 			} else if lss.Step == step {
 				if lss.SignBytes != nil {
 					if lss.Signature == nil {
@@ -122,7 +150,8 @@ func (lss *FilePVLastSignState) CheckHRS(height int64, round int32, step int8) (
 	return false, nil
 }
 
-// Save persists the FilePvLastSignState to its filePath.
+// Save persists the FilePvLastSignState to its filePath, keeping the previous
+// state, if valid, as a backup next to it.
 func (lss *FilePVLastSignState) Save() {
 	outFile := lss.filePath
 	if outFile == "" {
@@ -132,12 +161,59 @@ func (lss *FilePVLastSignState) Save() {
 	if err != nil {
 		panic(err)
 	}
-	err = tempfile.WriteFileAtomic(outFile, jsonBytes, 0o600)
+	if prevBytes, err := os.ReadFile(outFile); err == nil {
+		if _, err := unmarshalFilePVLastSignState(prevBytes); err == nil {
+			err = writeStateFile(outFile+stateBackupSuffix, prevBytes, !lss.skipFsync)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+	err = writeStateFile(outFile, jsonBytes, !lss.skipFsync)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// loadFilePVLastSignState loads the FilePVLastSignState from filePath. If the
+// file is corrupt, it falls back to the backup of the previous state.
+func loadFilePVLastSignState(filePath string, logger log.Logger) (FilePVLastSignState, error) {
+	stateJSONBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return FilePVLastSignState{}, err
+	}
+	lss, err := unmarshalFilePVLastSignState(stateJSONBytes)
+	if err == nil {
+		return lss, nil
+	}
+
+	backupPath := filePath + stateBackupSuffix
+	backupJSONBytes, backupErr := os.ReadFile(backupPath)
+	if backupErr != nil {
+		return FilePVLastSignState{}, fmt.Errorf("error reading PrivValidator state from %v: %w", filePath, err)
+	}
+	lss, backupErr = unmarshalFilePVLastSignState(backupJSONBytes)
+	if backupErr != nil {
+		return FilePVLastSignState{}, fmt.Errorf("error reading PrivValidator state from %v: %w, and from its backup: %v",
+			filePath, err, backupErr)
+	}
+
+	logger.Error("PrivValidator state file is corrupt, using the backup of the previous state instead. "+
+		"The last signature may be lost: check the disk before signing again",
+		"file", filePath, "backup", backupPath, "err", err,
+		"height", lss.Height, "round", lss.Round, "step", lss.Step)
+	return lss, nil
+}
+
+func unmarshalFilePVLastSignState(jsonBytes []byte) (FilePVLastSignState, error) {
+	lss := FilePVLastSignState{}
+	if len(jsonBytes) == 0 {
+		return lss, errors.New("empty state file")
+	}
+	err := cmtjson.Unmarshal(jsonBytes, &lss)
+	return lss, err
+}
+
 //-------------------------------------------------------------------------------
 
 // FilePV implements PrivValidator using data persisted to disk
@@ -148,8 +224,13 @@ func (lss *FilePVLastSignState) Save() {
 type FilePV struct {
 	Key           FilePVKey
 	LastSignState FilePVLastSignState
+
+	// whether to sign with the next key of Key
+	useNextKey bool
 }
 
+var _ types.KeyRotatingPrivValidator = (*FilePV)(nil)
+
 // NewFilePV generates a new validator from the given key and paths.
 func NewFilePV(privKey crypto.PrivKey, keyFilePath, stateFilePath string) *FilePV {
 	return &FilePV{
@@ -200,19 +281,19 @@ func loadFilePV(keyFilePath, stateFilePath string, loadState bool) *FilePV {
 	// overwrite pubkey and address for convenience
 	pvKey.PubKey = pvKey.PrivKey.PubKey()
 	pvKey.Address = pvKey.PubKey.Address()
+	if pvKey.NextPrivKey != nil {
+		pvKey.NextPubKey = pvKey.NextPrivKey.PubKey()
+	}
 	pvKey.filePath = keyFilePath
 
 	pvState := FilePVLastSignState{}
 
 	if loadState {
-		stateJSONBytes, err := os.ReadFile(stateFilePath)
+		logger := log.NewTMLogger(log.NewSyncWriter(os.Stderr))
+		pvState, err = loadFilePVLastSignState(stateFilePath, logger)
 		if err != nil {
 			cmtos.Exit(err.Error())
 		}
-		err = cmtjson.Unmarshal(stateJSONBytes, &pvState)
-		if err != nil {
-			cmtos.Exit(fmt.Sprintf("Error reading PrivValidator state from %v: %v\n", stateFilePath, err))
-		}
 	}
 
 	pvState.filePath = stateFilePath
@@ -236,23 +317,61 @@ func LoadOrGenFilePV(keyFilePath, stateFilePath string) *FilePV {
 	return pv
 }
 
-// GetAddress returns the address of the validator.
+// GetAddress returns the address of the selected key of the validator.
 // Implements PrivValidator.
 func (pv *FilePV) GetAddress() types.Address {
+	if pv.useNextKey {
+		return pv.Key.NextPubKey.Address()
+	}
 	return pv.Key.Address
 }
 
-// GetPubKey returns the public key of the validator.
+// GetPubKey returns the selected public key of the validator.
 // Implements PrivValidator.
 func (pv *FilePV) GetPubKey() (crypto.PubKey, error) {
+	if pv.useNextKey {
+		return pv.Key.NextPubKey, nil
+	}
 	return pv.Key.PubKey, nil
 }
 
+// GetPubKeys returns the public key of the validator and, while it rotates
+// its consensus key, the next one.
+// Implements KeyRotatingPrivValidator.
+func (pv *FilePV) GetPubKeys() ([]crypto.PubKey, error) {
+	if pv.Key.NextPubKey == nil {
+		return []crypto.PubKey{pv.Key.PubKey}, nil
+	}
+	return []crypto.PubKey{pv.Key.PubKey, pv.Key.NextPubKey}, nil
+}
+
+// SelectKey selects the key to sign with: the key of the validator or its
+// next key.
+// Implements KeyRotatingPrivValidator.
+func (pv *FilePV) SelectKey(address types.Address) error {
+	switch {
+	case bytes.Equal(address, pv.Key.Address):
+		pv.useNextKey = false
+	case pv.Key.NextPubKey != nil && bytes.Equal(address, pv.Key.NextPubKey.Address()):
+		pv.useNextKey = true
+	default:
+		return fmt.Errorf("no key with address %v", address)
+	}
+	return nil
+}
+
+func (pv *FilePV) privKey() crypto.PrivKey {
+	if pv.useNextKey {
+		return pv.Key.NextPrivKey
+	}
+	return pv.Key.PrivKey
+}
+
 // SignVote signs a canonical representation of the vote, along with the
 // chainID. Implements PrivValidator.
 func (pv *FilePV) SignVote(chainID string, vote *cmtproto.Vote) error {
 	if err := pv.signVote(chainID, vote); err != nil {
-		return fmt.Errorf("error signing vote: %v", err)
+		return fmt.Errorf("error signing vote: %w", err)
 	}
 	return nil
 }
@@ -261,7 +380,7 @@ func (pv *FilePV) SignVote(chainID string, vote *cmtproto.Vote) error {
 // the chainID. Implements PrivValidator.
 func (pv *FilePV) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
 	if err := pv.signProposal(chainID, proposal); err != nil {
-		return fmt.Errorf("error signing proposal: %v", err)
+		return fmt.Errorf("error signing proposal: %w", err)
 	}
 	return nil
 }
@@ -272,6 +391,12 @@ func (pv *FilePV) Save() {
 	pv.LastSignState.Save()
 }
 
+// SetStateFsync sets whether saving the last sign state waits for it to be
+// flushed to disk. It's enabled by default.
+func (pv *FilePV) SetStateFsync(fsync bool) {
+	pv.LastSignState.skipFsync = !fsync
+}
+
 // Reset resets all fields in the FilePV.
 // NOTE: Unsafe!
 func (pv *FilePV) Reset() {
@@ -318,19 +443,22 @@ func (pv *FilePV) signVote(chainID string, vote *cmtproto.Vote) error {
 	// If they only differ by timestamp, use last timestamp and signature
 	// Otherwise, return error
 	if sameHRS {
+		if !pv.privKey().PubKey().VerifySignature(lss.SignBytes, lss.Signature) {
+			return &DoubleSignError{Reason: "signed with another key"}
+		}
 		if bytes.Equal(signBytes, lss.SignBytes) {
 			vote.Signature = lss.Signature
 		} else if timestamp, ok := checkVotesOnlyDifferByTimestamp(lss.SignBytes, signBytes); ok {
 			vote.Timestamp = timestamp
 			vote.Signature = lss.Signature
 		} else {
-			err = fmt.Errorf("conflicting data")
+			err = &DoubleSignError{Reason: "conflicting data"}
 		}
 		return err
 	}
 
 	// It passed the checks. Sign the vote
-	sig, err := pv.Key.PrivKey.Sign(signBytes)
+	sig, err := pv.privKey().Sign(signBytes)
 	if err != nil {
 		return err
 	}
@@ -360,19 +488,22 @@ func (pv *FilePV) signProposal(chainID string, proposal *cmtproto.Proposal) erro
 	// If they only differ by timestamp, use last timestamp and signature
 	// Otherwise, return error
 	if sameHRS {
+		if !pv.privKey().PubKey().VerifySignature(lss.SignBytes, lss.Signature) {
+			return &DoubleSignError{Reason: "signed with another key"}
+		}
 		if bytes.Equal(signBytes, lss.SignBytes) {
 			proposal.Signature = lss.Signature
 		} else if timestamp, ok := checkProposalsOnlyDifferByTimestamp(lss.SignBytes, signBytes); ok {
 			proposal.Timestamp = timestamp
 			proposal.Signature = lss.Signature
 		} else {
-			err = fmt.Errorf("conflicting data")
+			err = &DoubleSignError{Reason: "conflicting data"}
 		}
 		return err
 	}
 
 	// It passed the checks. Sign the proposal
-	sig, err := pv.Key.PrivKey.Sign(signBytes)
+	sig, err := pv.privKey().Sign(signBytes)
 	if err != nil {
 		return err
 	}
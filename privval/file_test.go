@@ -2,17 +2,21 @@ package privval
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmtjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/log"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
@@ -327,6 +331,160 @@ func TestDifferByTimestamp(t *testing.T) {
 	}
 }
 
+func TestFilePVStateBackup(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "priv_validator_state.json")
+	privVal := GenFilePV(filepath.Join(dir, "priv_validator_key.json"), stateFile)
+	privVal.Save()
+
+	vote := newVote(privVal.Key.Address, 0, 10, 1, cmtproto.PrevoteType, types.BlockID{})
+	require.NoError(t, privVal.SignVote("mychainid", vote.ToProto()))
+	vote = newVote(privVal.Key.Address, 0, 10, 1, cmtproto.PrecommitType, types.BlockID{})
+	require.NoError(t, privVal.SignVote("mychainid", vote.ToProto()))
+
+	// the backup holds the state before the last signature
+	backup, err := loadFilePVLastSignState(stateFile+stateBackupSuffix, log.TestingLogger())
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, backup.Height)
+	assert.Equal(t, stepPrevote, backup.Step)
+
+	state, err := loadFilePVLastSignState(stateFile, log.TestingLogger())
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, state.Height)
+	assert.Equal(t, stepPrecommit, state.Step)
+}
+
+func TestFilePVStateWriteFailure(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "priv_validator_key.json")
+	stateFile := filepath.Join(dir, "priv_validator_state.json")
+	privVal := GenFilePV(keyFile, stateFile)
+	privVal.SetStateFsync(false)
+	privVal.Save()
+
+	vote := newVote(privVal.Key.Address, 0, 10, 1, cmtproto.PrevoteType, types.BlockID{})
+	require.NoError(t, privVal.SignVote("mychainid", vote.ToProto()))
+
+	writeErr := errors.New("no space left on device")
+	testCases := []struct {
+		name  string
+		write func(filePath string, data []byte, fsync bool) error
+	}{
+		// the file is left untouched, as with an atomic write
+		{"atomic write failure", func(filePath string, data []byte, fsync bool) error {
+			return writeErr
+		}},
+		// the file is left truncated, as with a crash in the middle of an
+		// in place write
+		{"torn write", func(filePath string, data []byte, fsync bool) error {
+			if err := os.WriteFile(filePath, data[:len(data)/2], 0o600); err != nil {
+				return err
+			}
+			return writeErr
+		}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			defer func(write func(string, []byte, bool) error) { writeStateFile = write }(writeStateFile)
+			defaultWrite := writeStateFile
+			writeStateFile = func(filePath string, data []byte, fsync bool) error {
+				if filePath == stateFile {
+					return tc.write(filePath, data, fsync)
+				}
+				return defaultWrite(filePath, data, fsync)
+			}
+
+			privVal := LoadFilePV(keyFile, stateFile)
+			vote := newVote(privVal.Key.Address, 0, 10, 1, cmtproto.PrecommitType, types.BlockID{})
+			assert.Panics(t, func() { _ = privVal.SignVote("mychainid", vote.ToProto()) })
+
+			// the state of the prevote is recovered from the file or its backup
+			state, err := loadFilePVLastSignState(stateFile, log.TestingLogger())
+			require.NoError(t, err)
+			assert.EqualValues(t, 10, state.Height)
+			assert.EqualValues(t, 1, state.Round)
+			assert.Equal(t, stepPrevote, state.Step)
+		})
+	}
+}
+
+func TestFilePVStateCorruptBackup(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "priv_validator_state.json")
+
+	_, err := loadFilePVLastSignState(stateFile, log.TestingLogger())
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(stateFile, []byte(`{"height": "1`), 0o600))
+	_, err = loadFilePVLastSignState(stateFile, log.TestingLogger())
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(stateFile+stateBackupSuffix, []byte(`{"heig`), 0o600))
+	_, err = loadFilePVLastSignState(stateFile, log.TestingLogger())
+	require.Error(t, err)
+}
+
+func genRotatingFilePV(t *testing.T) *FilePV {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "priv_validator_key.json")
+	stateFile := filepath.Join(dir, "priv_validator_state.json")
+	privVal := GenFilePV(keyFile, stateFile)
+	privVal.Key.NextPrivKey = ed25519.GenPrivKey()
+	privVal.Key.NextPubKey = privVal.Key.NextPrivKey.PubKey()
+	privVal.Save()
+	return LoadFilePV(keyFile, stateFile)
+}
+
+func TestFilePVRotatingKeys(t *testing.T) {
+	privVal := genRotatingFilePV(t)
+	require.NotNil(t, privVal.Key.NextPrivKey)
+	oldKey, newKey := privVal.Key.PubKey, privVal.Key.NextPubKey
+	assert.Equal(t, newKey, privVal.Key.NextPrivKey.PubKey(), "expected next pubkey to be derived on load")
+
+	pks, err := privVal.GetPubKeys()
+	require.NoError(t, err)
+	assert.Equal(t, []crypto.PubKey{oldKey, newKey}, pks)
+
+	// the old key is used until the new one is selected
+	pk, err := privVal.GetPubKey()
+	require.NoError(t, err)
+	assert.Equal(t, oldKey, pk)
+
+	vote := newVote(oldKey.Address(), 0, 10, 0, cmtproto.PrevoteType, types.BlockID{})
+	v := vote.ToProto()
+	require.NoError(t, privVal.SignVote("mychainid", v))
+	assert.True(t, oldKey.VerifySignature(types.VoteSignBytes("mychainid", v), v.Signature))
+
+	require.NoError(t, privVal.SelectKey(newKey.Address()))
+	assert.Equal(t, newKey.Address(), privVal.GetAddress())
+
+	vote = newVote(newKey.Address(), 0, 11, 0, cmtproto.PrevoteType, types.BlockID{})
+	v = vote.ToProto()
+	require.NoError(t, privVal.SignVote("mychainid", v))
+	assert.True(t, newKey.VerifySignature(types.VoteSignBytes("mychainid", v), v.Signature))
+
+	assert.Error(t, privVal.SelectKey(ed25519.GenPrivKey().PubKey().Address()))
+}
+
+func TestFilePVRotatingKeysNoSignatureReuse(t *testing.T) {
+	privVal := genRotatingFilePV(t)
+	oldKey, newKey := privVal.Key.PubKey, privVal.Key.NextPubKey
+
+	vote := newVote(oldKey.Address(), 0, 10, 0, cmtproto.PrevoteType, types.BlockID{})
+	require.NoError(t, privVal.SignVote("mychainid", vote.ToProto()))
+
+	// the same vote can't be signed again with the other key, nor can the
+	// signature of the old key be returned for it
+	require.NoError(t, privVal.SelectKey(newKey.Address()))
+	vote = newVote(newKey.Address(), 0, 10, 0, cmtproto.PrevoteType, types.BlockID{})
+	v := vote.ToProto()
+	err := privVal.SignVote("mychainid", v)
+	var dsErr *DoubleSignError
+	require.True(t, errors.As(err, &dsErr), "expected double sign error, got %v", err)
+	assert.Nil(t, v.Signature)
+}
+
 func newVote(addr types.Address, idx int32, height int64, round int32,
 	typ cmtproto.SignedMsgType, blockID types.BlockID,
 ) *types.Vote {
@@ -0,0 +1,116 @@
+package privval
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "privval"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Time taken to sign votes, by endpoint.
+	SignVoteLatency metrics.Histogram
+	// Time taken to sign proposals, by endpoint.
+	SignProposalLatency metrics.Histogram
+	// Number of failed signatures, by endpoint and type of error:
+	// double_sign, timeout, connection or signer.
+	SignErrors metrics.Counter
+	// Whether the signer of each endpoint is connected: 1 if connected, 0
+	// otherwise.
+	SignerConnected metrics.Gauge
+	// Round trip time of pings to the signer of each endpoint.
+	SignerRTT metrics.Histogram
+
+	// State of each signer endpoint: 1 for its current state (active, standby
+	// or failed), 0 for the others.
+	SignerEndpointState metrics.Gauge
+	// Number of failed requests to each signer endpoint.
+	SignerEndpointFailures metrics.Counter
+	// Number of times the active signer endpoint changed.
+	SignerFailovers metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		SignVoteLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sign_vote_latency_seconds",
+			Help:      "Time taken to sign votes, by endpoint.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 14),
+		}, append(labels, "endpoint")).With(labelsAndValues...),
+		SignProposalLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sign_proposal_latency_seconds",
+			Help:      "Time taken to sign proposals, by endpoint.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 14),
+		}, append(labels, "endpoint")).With(labelsAndValues...),
+		SignErrors: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "sign_errors",
+			Help:      "Number of failed signatures, by endpoint and type of error: double_sign, timeout, connection or signer.",
+		}, append(labels, "endpoint", "type")).With(labelsAndValues...),
+		SignerConnected: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "signer_connected",
+			Help:      "Whether the signer of each endpoint is connected: 1 if connected, 0 otherwise.",
+		}, append(labels, "endpoint")).With(labelsAndValues...),
+		SignerRTT: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "signer_rtt_seconds",
+			Help:      "Round trip time of pings to the signer of each endpoint.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0001, 2, 16),
+		}, append(labels, "endpoint")).With(labelsAndValues...),
+		SignerEndpointState: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "signer_endpoint_state",
+			Help:      "State of each signer endpoint: 1 for its current state (active, standby or failed), 0 for the others.",
+		}, append(labels, "endpoint", "state")).With(labelsAndValues...),
+		SignerEndpointFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "signer_endpoint_failures",
+			Help:      "Number of failed requests to each signer endpoint.",
+		}, append(labels, "endpoint")).With(labelsAndValues...),
+		SignerFailovers: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "signer_failovers",
+			Help:      "Number of times the active signer endpoint changed.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		SignVoteLatency:     discard.NewHistogram(),
+		SignProposalLatency: discard.NewHistogram(),
+		SignErrors:          discard.NewCounter(),
+		SignerConnected:     discard.NewGauge(),
+		SignerRTT:           discard.NewHistogram(),
+
+		SignerEndpointState:    discard.NewGauge(),
+		SignerEndpointFailures: discard.NewCounter(),
+		SignerFailovers:        discard.NewCounter(),
+	}
+}
@@ -0,0 +1,143 @@
+package privval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// The types of signing errors.
+const (
+	signErrorDoubleSign = "double_sign"
+	signErrorTimeout    = "timeout"
+	signErrorConnection = "connection"
+	signErrorSigner     = "signer"
+)
+
+// MetricsPrivValidator wraps a PrivValidator, file-based or remote, to record
+// the latency and the errors of its signatures, and whether it's connected to
+// its signer, labelled by endpoint.
+type MetricsPrivValidator struct {
+	types.PrivValidator
+
+	endpoint string
+	metrics  *Metrics
+}
+
+var _ types.KeyRotatingPrivValidator = (*MetricsPrivValidator)(nil)
+
+// NewMetricsPrivValidator returns a MetricsPrivValidator recording the
+// metrics of the given PrivValidator under the given endpoint.
+func NewMetricsPrivValidator(
+	privVal types.PrivValidator,
+	endpoint string,
+	metrics *Metrics,
+) *MetricsPrivValidator {
+	pv := &MetricsPrivValidator{
+		PrivValidator: privVal,
+		endpoint:      endpoint,
+		metrics:       metrics,
+	}
+	pv.recordConnected()
+	return pv
+}
+
+// SignVote signs a vote with the wrapped PrivValidator.
+func (pv *MetricsPrivValidator) SignVote(chainID string, vote *cmtproto.Vote) error {
+	start := time.Now()
+	err := pv.PrivValidator.SignVote(chainID, vote)
+	pv.metrics.SignVoteLatency.With("endpoint", pv.endpoint).Observe(time.Since(start).Seconds())
+	pv.recordResult(err)
+	return err
+}
+
+// SignProposal signs a proposal with the wrapped PrivValidator.
+func (pv *MetricsPrivValidator) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
+	start := time.Now()
+	err := pv.PrivValidator.SignProposal(chainID, proposal)
+	pv.metrics.SignProposalLatency.With("endpoint", pv.endpoint).Observe(time.Since(start).Seconds())
+	pv.recordResult(err)
+	return err
+}
+
+// GetPubKeys returns the public keys of the wrapped PrivValidator, or its only
+// public key if it can't rotate its key.
+func (pv *MetricsPrivValidator) GetPubKeys() ([]crypto.PubKey, error) {
+	if rpv, ok := pv.PrivValidator.(types.KeyRotatingPrivValidator); ok {
+		return rpv.GetPubKeys()
+	}
+	pk, err := pv.GetPubKey()
+	if err != nil {
+		return nil, err
+	}
+	return []crypto.PubKey{pk}, nil
+}
+
+// SelectKey selects the key the wrapped PrivValidator signs with.
+func (pv *MetricsPrivValidator) SelectKey(address types.Address) error {
+	if rpv, ok := pv.PrivValidator.(types.KeyRotatingPrivValidator); ok {
+		return rpv.SelectKey(address)
+	}
+	pk, err := pv.GetPubKey()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pk.Address(), address) {
+		return fmt.Errorf("no key with address %v", address)
+	}
+	return nil
+}
+
+func (pv *MetricsPrivValidator) recordResult(err error) {
+	if err != nil {
+		pv.metrics.SignErrors.With("endpoint", pv.endpoint, "type", pv.signErrorType(err)).Add(1)
+	}
+	pv.recordConnected()
+}
+
+// recordConnected records whether a remote PrivValidator is connected to its
+// signer. Other PrivValidators are always connected.
+func (pv *MetricsPrivValidator) recordConnected() {
+	connected := 1.0
+	if remote, ok := pv.PrivValidator.(interface{ IsConnected() bool }); ok && !remote.IsConnected() {
+		connected = 0
+	}
+	pv.metrics.SignerConnected.With("endpoint", pv.endpoint).Set(connected)
+}
+
+// signErrorType returns the type of an error returned by the wrapped
+// PrivValidator: the signer refused to double sign, timed out, couldn't be
+// reached, or failed otherwise.
+func (pv *MetricsPrivValidator) signErrorType(err error) string {
+	var (
+		doubleSignErr *DoubleSignError
+		remoteErr     *RemoteSignerError
+		netErr        net.Error
+	)
+	switch {
+	case errors.Is(err, types.ErrSignTimeout):
+		return signErrorTimeout
+	case errors.Is(err, types.ErrConnectionDropped):
+		return signErrorConnection
+	case errors.As(err, &doubleSignErr):
+		return signErrorDoubleSign
+	case errors.As(err, &remoteErr):
+		if remoteErr.Code == RemoteSignerErrorCodeDoubleSign {
+			return signErrorDoubleSign
+		}
+		return signErrorSigner
+	case errors.Is(err, ErrReadTimeout), errors.Is(err, ErrWriteTimeout),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return signErrorTimeout
+	}
+	if _, ok := pv.PrivValidator.(interface{ IsConnected() bool }); ok {
+		return signErrorConnection
+	}
+	return signErrorSigner
+}
@@ -0,0 +1,215 @@
+package privval
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// slowPrivValidator is a PrivValidator taking delay to sign.
+type slowPrivValidator struct {
+	types.PrivValidator
+	delay time.Duration
+}
+
+func (pv slowPrivValidator) SignVote(chainID string, vote *cmtproto.Vote) error {
+	time.Sleep(pv.delay)
+	return pv.PrivValidator.SignVote(chainID, vote)
+}
+
+func (pv slowPrivValidator) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
+	time.Sleep(pv.delay)
+	return pv.PrivValidator.SignProposal(chainID, proposal)
+}
+
+func TestMetricsPrivValidatorFile(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	var (
+		chainID         = cmtrand.Str(12)
+		filePV          = newMetricsTestFilePV(t)
+		voteLatency     = generic.NewHistogram("vote_latency", 10)
+		proposalLatency = generic.NewHistogram("proposal_latency", 10)
+		errs            = newLabeledCounter()
+		connected       = newLabeledGauge()
+		m               = NopMetrics()
+	)
+	m.SignVoteLatency = voteLatency
+	m.SignProposalLatency = proposalLatency
+	m.SignErrors = errs
+	m.SignerConnected = connected
+
+	pv := NewMetricsPrivValidator(slowPrivValidator{filePV, delay}, "file", m)
+	assert.EqualValues(t, 1, connected.Value("endpoint", "file"))
+
+	proposal := newProposal(10, 1, metricsTestBlockID())
+	require.NoError(t, pv.SignProposal(chainID, proposal.ToProto()))
+	assert.GreaterOrEqual(t, proposalLatency.Quantile(0.5), delay.Seconds())
+
+	vote := newVote(filePV.Key.Address, 0, 10, 1, cmtproto.PrevoteType, metricsTestBlockID())
+	require.NoError(t, pv.SignVote(chainID, vote.ToProto()))
+	assert.GreaterOrEqual(t, voteLatency.Quantile(0.5), delay.Seconds())
+	assert.Zero(t, errs.Total())
+
+	// signing a conflicting vote is refused
+	vote = newVote(filePV.Key.Address, 0, 10, 1, cmtproto.PrevoteType, metricsTestBlockID())
+	require.Error(t, pv.SignVote(chainID, vote.ToProto()))
+	assert.EqualValues(t, 1, errs.Value("endpoint", "file", "type", signErrorDoubleSign))
+	assert.EqualValues(t, 1, errs.Total())
+	assert.EqualValues(t, 1, connected.Value("endpoint", "file"))
+}
+
+func TestMetricsPrivValidatorSocket(t *testing.T) {
+	for _, tc := range getSignerTestCases(t) {
+		tc := tc
+		t.Cleanup(func() {
+			if err := tc.signerServer.Stop(); err != nil {
+				t.Error(err)
+			}
+		})
+		t.Cleanup(func() {
+			if err := tc.signerClient.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		var (
+			filePV      = newMetricsTestFilePV(t)
+			delay       int64
+			voteLatency = generic.NewHistogram("vote_latency", 10)
+			errs        = newLabeledCounter()
+			connected   = newLabeledGauge()
+			m           = NopMetrics()
+		)
+		m.SignVoteLatency = voteLatency
+		m.SignErrors = errs
+		m.SignerConnected = connected
+
+		// the signer waits for delay before signing with the file PV
+		tc.signerServer.SetRequestHandler(func(
+			_ types.PrivValidator,
+			req privvalproto.Message,
+			chainID string,
+		) (privvalproto.Message, error) {
+			if req.GetSignVoteRequest() != nil {
+				time.Sleep(time.Duration(atomic.LoadInt64(&delay)))
+			}
+			return DefaultValidationRequestHandler(filePV, req, chainID)
+		})
+
+		pv := NewMetricsPrivValidator(tc.signerClient, "signer", m)
+
+		atomic.StoreInt64(&delay, int64(testTimeoutReadWrite/2))
+		vote := newVote(filePV.Key.Address, 0, 10, 1, cmtproto.PrevoteType, metricsTestBlockID())
+		require.NoError(t, pv.SignVote(tc.chainID, vote.ToProto()))
+		assert.GreaterOrEqual(t, voteLatency.Quantile(0.5), (testTimeoutReadWrite / 2).Seconds())
+		assert.EqualValues(t, 1, connected.Value("endpoint", "signer"))
+		assert.Zero(t, errs.Total())
+
+		// the signer refuses to double sign
+		atomic.StoreInt64(&delay, 0)
+		vote = newVote(filePV.Key.Address, 0, 10, 1, cmtproto.PrevoteType, metricsTestBlockID())
+		require.Error(t, pv.SignVote(tc.chainID, vote.ToProto()))
+		assert.EqualValues(t, 1, errs.Value("endpoint", "signer", "type", signErrorDoubleSign))
+
+		// the signer is slower than the timeout, so the connection is dropped
+		atomic.StoreInt64(&delay, int64(2*testTimeoutReadWrite))
+		vote = newVote(filePV.Key.Address, 0, 11, 1, cmtproto.PrevoteType, metricsTestBlockID())
+		require.Error(t, pv.SignVote(tc.chainID, vote.ToProto()))
+		assert.EqualValues(t, 1, errs.Value("endpoint", "signer", "type", signErrorTimeout))
+		assert.EqualValues(t, 0, connected.Value("endpoint", "signer"))
+		assert.EqualValues(t, 2, errs.Total())
+	}
+}
+
+func newMetricsTestFilePV(t *testing.T) *FilePV {
+	tempKeyFile, err := os.CreateTemp("", "priv_validator_key_")
+	require.NoError(t, err)
+	tempStateFile, err := os.CreateTemp("", "priv_validator_state_")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.Remove(tempKeyFile.Name())
+		os.Remove(tempStateFile.Name())
+	})
+	return GenFilePV(tempKeyFile.Name(), tempStateFile.Name())
+}
+
+func metricsTestBlockID() types.BlockID {
+	hash := cmtrand.Bytes(tmhash.Size)
+	return types.BlockID{Hash: hash, PartSetHeader: types.PartSetHeader{Total: 5, Hash: hash}}
+}
+
+// labeledCounter is a counter keeping a generic counter per label values, as
+// labeled generic counters don't add to their parent.
+type labeledCounter struct {
+	counters map[string]*generic.Counter
+}
+
+func newLabeledCounter() *labeledCounter {
+	return &labeledCounter{counters: make(map[string]*generic.Counter)}
+}
+
+func (c *labeledCounter) With(labelValues ...string) metrics.Counter {
+	key := strings.Join(labelValues, ",")
+	if _, ok := c.counters[key]; !ok {
+		c.counters[key] = generic.NewCounter(key)
+	}
+	return c.counters[key]
+}
+
+func (c *labeledCounter) Add(delta float64) {
+	c.With().Add(delta)
+}
+
+func (c *labeledCounter) Value(labelValues ...string) float64 {
+	return c.With(labelValues...).(*generic.Counter).Value()
+}
+
+func (c *labeledCounter) Total() float64 {
+	total := 0.0
+	for _, counter := range c.counters {
+		total += counter.Value()
+	}
+	return total
+}
+
+// labeledGauge is a gauge keeping a generic gauge per label values.
+type labeledGauge struct {
+	gauges map[string]*generic.Gauge
+}
+
+func newLabeledGauge() *labeledGauge {
+	return &labeledGauge{gauges: make(map[string]*generic.Gauge)}
+}
+
+func (g *labeledGauge) With(labelValues ...string) metrics.Gauge {
+	key := strings.Join(labelValues, ",")
+	if _, ok := g.gauges[key]; !ok {
+		g.gauges[key] = generic.NewGauge(key)
+	}
+	return g.gauges[key]
+}
+
+func (g *labeledGauge) Set(value float64) {
+	g.With().Set(value)
+}
+
+func (g *labeledGauge) Add(delta float64) {
+	g.With().Add(delta)
+}
+
+func (g *labeledGauge) Value(labelValues ...string) float64 {
+	return g.With(labelValues...).(*generic.Gauge).Value()
+}
@@ -32,9 +32,23 @@ func mustWrapMsg(pb proto.Message) privvalproto.Message {
 		msg.Sum = &privvalproto.Message_PingRequest{PingRequest: pb}
 	case *privvalproto.PingResponse:
 		msg.Sum = &privvalproto.Message_PingResponse{PingResponse: pb}
+	case *privvalproto.SigningInProgress:
+		msg.Sum = &privvalproto.Message_SigningInProgress{SigningInProgress: pb}
 	default:
 		panic(fmt.Errorf("unknown message type %T", pb))
 	}
 
 	return msg
 }
+
+// msgRequestID returns the request ID of a signing request, if any.
+func msgRequestID(msg privvalproto.Message) string {
+	switch r := msg.Sum.(type) {
+	case *privvalproto.Message_SignVoteRequest:
+		return r.SignVoteRequest.GetRequestId()
+	case *privvalproto.Message_SignProposalRequest:
+		return r.SignProposalRequest.GetRequestId()
+	default:
+		return ""
+	}
+}
@@ -1,6 +1,7 @@
 package privval
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -9,21 +10,36 @@ import (
 	"github.com/tendermint/tendermint/types"
 )
 
-// RetrySignerClient wraps SignerClient adding retry for each operation (except
-// Ping) w/ a timeout.
+// remoteSigner is a client of remote signers: a SignerClient or a
+// FailoverSignerClient.
+type remoteSigner interface {
+	types.KeyRotatingPrivValidator
+	Close() error
+	IsConnected() bool
+	WaitForConnection(maxWait time.Duration) error
+	Ping() error
+}
+
+var (
+	_ remoteSigner = (*SignerClient)(nil)
+	_ remoteSigner = (*FailoverSignerClient)(nil)
+)
+
+// RetrySignerClient wraps SignerClient or FailoverSignerClient adding retry
+// for each operation (except Ping) w/ a timeout.
 type RetrySignerClient struct {
-	next    *SignerClient
+	next    remoteSigner
 	retries int
 	timeout time.Duration
 }
 
 // NewRetrySignerClient returns RetrySignerClient. If +retries+ is 0, the
 // client will be retrying each operation indefinitely.
-func NewRetrySignerClient(sc *SignerClient, retries int, timeout time.Duration) *RetrySignerClient {
+func NewRetrySignerClient(sc remoteSigner, retries int, timeout time.Duration) *RetrySignerClient {
 	return &RetrySignerClient{sc, retries, timeout}
 }
 
-var _ types.PrivValidator = (*RetrySignerClient)(nil)
+var _ types.KeyRotatingPrivValidator = (*RetrySignerClient)(nil)
 
 func (sc *RetrySignerClient) Close() error {
 	return sc.next.Close()
@@ -63,6 +79,29 @@ func (sc *RetrySignerClient) GetPubKey() (crypto.PubKey, error) {
 	return nil, fmt.Errorf("exhausted all attempts to get pubkey: %w", err)
 }
 
+func (sc *RetrySignerClient) GetPubKeys() ([]crypto.PubKey, error) {
+	var (
+		pks []crypto.PubKey
+		err error
+	)
+	for i := 0; i < sc.retries || sc.retries == 0; i++ {
+		pks, err = sc.next.GetPubKeys()
+		if err == nil {
+			return pks, nil
+		}
+		// If remote signer errors, we don't retry.
+		if _, ok := err.(*RemoteSignerError); ok {
+			return nil, err
+		}
+		time.Sleep(sc.timeout)
+	}
+	return nil, fmt.Errorf("exhausted all attempts to get pubkeys: %w", err)
+}
+
+func (sc *RetrySignerClient) SelectKey(address types.Address) error {
+	return sc.next.SelectKey(address)
+}
+
 func (sc *RetrySignerClient) SignVote(chainID string, vote *cmtproto.Vote) error {
 	var err error
 	for i := 0; i < sc.retries || sc.retries == 0; i++ {
@@ -74,6 +113,11 @@ func (sc *RetrySignerClient) SignVote(chainID string, vote *cmtproto.Vote) error
 		if _, ok := err.(*RemoteSignerError); ok {
 			return err
 		}
+		// Nor if it timed out: the signer may have signed already, and
+		// consensus decides whether to sign again.
+		if errors.Is(err, types.ErrSignTimeout) {
+			return err
+		}
 		time.Sleep(sc.timeout)
 	}
 	return fmt.Errorf("exhausted all attempts to sign vote: %w", err)
@@ -90,6 +134,11 @@ func (sc *RetrySignerClient) SignProposal(chainID string, proposal *cmtproto.Pro
 		if _, ok := err.(*RemoteSignerError); ok {
 			return err
 		}
+		// Nor if it timed out: the signer may have signed already, and
+		// consensus decides whether to sign again.
+		if errors.Is(err, types.ErrSignTimeout) {
+			return err
+		}
 		time.Sleep(sc.timeout)
 	}
 	return fmt.Errorf("exhausted all attempts to sign proposal: %w", err)
@@ -0,0 +1,54 @@
+package privval
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// failingSigner fails every sign request with err.
+type failingSigner struct {
+	remoteSigner
+	err   error
+	calls int
+}
+
+func (s *failingSigner) SignVote(string, *cmtproto.Vote) error {
+	s.calls++
+	return s.err
+}
+
+func (s *failingSigner) SignProposal(string, *cmtproto.Proposal) error {
+	s.calls++
+	return s.err
+}
+
+func TestRetrySignerClientSignTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		err   error
+		calls int
+	}{
+		"timeout":            {fmt.Errorf("signing: %w", types.ErrSignTimeout), 1},
+		"remote signer":      {&RemoteSignerError{Code: 1, Description: "refused"}, 1},
+		"connection dropped": {fmt.Errorf("signing: %w", types.ErrConnectionDropped), 3},
+	}
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			signer := &failingSigner{err: tc.err}
+			sc := NewRetrySignerClient(signer, 3, time.Millisecond)
+
+			assert.ErrorIs(t, sc.SignVote("chain", &cmtproto.Vote{}), tc.err)
+			assert.Equal(t, tc.calls, signer.calls)
+
+			signer.calls = 0
+			assert.ErrorIs(t, sc.SignProposal("chain", &cmtproto.Proposal{}), tc.err)
+			assert.Equal(t, tc.calls, signer.calls)
+		})
+	}
+}
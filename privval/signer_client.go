@@ -1,11 +1,14 @@
 package privval
 
 import (
+	"bytes"
 	"fmt"
 	"time"
 
 	"github.com/tendermint/tendermint/crypto"
 	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
@@ -16,9 +19,12 @@ import (
 type SignerClient struct {
 	endpoint *SignerListenerEndpoint
 	chainID  string
+
+	mtx        cmtsync.Mutex
+	keyAddress types.Address // address of the selected key, if any
 }
 
-var _ types.PrivValidator = (*SignerClient)(nil)
+var _ types.KeyRotatingPrivValidator = (*SignerClient)(nil)
 
 // NewSignerClient returns an instance of SignerClient.
 // it will start the endpoint (if not already started)
@@ -66,33 +72,94 @@ func (sc *SignerClient) Ping() error {
 	return nil
 }
 
-// GetPubKey retrieves a public key from a remote signer
+// GetPubKey retrieves a public key from a remote signer: the selected key, if
+// any, or its default key.
 // returns an error if client is not able to provide the key
 func (sc *SignerClient) GetPubKey() (crypto.PubKey, error) {
+	pk, pks, err := sc.getPubKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	keyAddress := sc.selectedKey()
+	if keyAddress == nil {
+		return pk, nil
+	}
+	for _, pk := range pks {
+		if bytes.Equal(pk.Address(), keyAddress) {
+			return pk, nil
+		}
+	}
+	return nil, fmt.Errorf("remote signer has no key with address %v", keyAddress)
+}
+
+// GetPubKeys retrieves all the public keys from a remote signer, e.g. the old
+// and the new keys of a validator rotating its consensus key.
+func (sc *SignerClient) GetPubKeys() ([]crypto.PubKey, error) {
+	_, pks, err := sc.getPubKeys()
+	return pks, err
+}
+
+// SelectKey selects the key the remote signer signs with. The remote signer
+// checks it has it when signing.
+func (sc *SignerClient) SelectKey(address types.Address) error {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	sc.keyAddress = address
+	return nil
+}
+
+func (sc *SignerClient) selectedKey() types.Address {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	return sc.keyAddress
+}
+
+// getPubKeys retrieves the default public key of a remote signer and all its
+// public keys. Signers which don't tell all their keys have only the default
+// one.
+func (sc *SignerClient) getPubKeys() (crypto.PubKey, []crypto.PubKey, error) {
 	response, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.PubKeyRequest{ChainId: sc.chainID}))
 	if err != nil {
-		return nil, fmt.Errorf("send: %w", err)
+		return nil, nil, fmt.Errorf("send: %w", err)
 	}
 
 	resp := response.GetPubKeyResponse()
 	if resp == nil {
-		return nil, ErrUnexpectedResponse
+		return nil, nil, ErrUnexpectedResponse
 	}
 	if resp.Error != nil {
-		return nil, &RemoteSignerError{Code: int(resp.Error.Code), Description: resp.Error.Description}
+		return nil, nil, &RemoteSignerError{Code: int(resp.Error.Code), Description: resp.Error.Description}
 	}
 
 	pk, err := cryptoenc.PubKeyFromProto(resp.PubKey)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if len(resp.PubKeys) == 0 {
+		return pk, []crypto.PubKey{pk}, nil
 	}
 
-	return pk, nil
+	pks := make([]crypto.PubKey, len(resp.PubKeys))
+	for i, pkProto := range resp.PubKeys {
+		if pks[i], err = cryptoenc.PubKeyFromProto(pkProto); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pk, pks, nil
 }
 
 // SignVote requests a remote signer to sign a vote
 func (sc *SignerClient) SignVote(chainID string, vote *cmtproto.Vote) error {
-	response, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.SignVoteRequest{Vote: vote, ChainId: chainID}))
+	signBytes := types.VoteSignBytes(chainID, vote)
+	keyAddress := sc.selectedKey()
+	response, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.SignVoteRequest{
+		Vote:          vote,
+		ChainId:       chainID,
+		SignBytes:     signBytes,
+		RequestId:     signRequestID(signBytes, keyAddress),
+		PubKeyAddress: keyAddress,
+	}))
 	if err != nil {
 		return err
 	}
@@ -112,9 +179,15 @@ func (sc *SignerClient) SignVote(chainID string, vote *cmtproto.Vote) error {
 
 // SignProposal requests a remote signer to sign a proposal
 func (sc *SignerClient) SignProposal(chainID string, proposal *cmtproto.Proposal) error {
-	response, err := sc.endpoint.SendRequest(mustWrapMsg(
-		&privvalproto.SignProposalRequest{Proposal: proposal, ChainId: chainID},
-	))
+	signBytes := types.ProposalSignBytes(chainID, proposal)
+	keyAddress := sc.selectedKey()
+	response, err := sc.endpoint.SendRequest(mustWrapMsg(&privvalproto.SignProposalRequest{
+		Proposal:      proposal,
+		ChainId:       chainID,
+		SignBytes:     signBytes,
+		RequestId:     signRequestID(signBytes, keyAddress),
+		PubKeyAddress: keyAddress,
+	}))
 	if err != nil {
 		return err
 	}
@@ -131,3 +204,13 @@ func (sc *SignerClient) SignProposal(chainID string, proposal *cmtproto.Proposal
 
 	return nil
 }
+
+// signRequestID returns the ID of a request to sign the given bytes with the
+// key with the given address. It's derived from them, so that retries of a
+// request have the same ID.
+func signRequestID(signBytes []byte, keyAddress types.Address) string {
+	hasher := tmhash.New()
+	hasher.Write(signBytes)
+	hasher.Write(keyAddress)
+	return fmt.Sprintf("%X", hasher.Sum(nil))
+}
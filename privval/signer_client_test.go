@@ -2,6 +2,7 @@ package privval
 
 import (
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/tmhash"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
 	cryptoproto "github.com/tendermint/tendermint/proto/tendermint/crypto"
@@ -437,3 +439,217 @@ func TestSignerUnexpectedResponse(t *testing.T) {
 		assert.EqualError(t, e, "empty response")
 	}
 }
+
+func TestSignerVoteRetrySameRequestID(t *testing.T) {
+	for _, tc := range getSignerTestCases(t) {
+		tc := tc
+		t.Cleanup(func() {
+			if err := tc.signerServer.Stop(); err != nil {
+				t.Error(err)
+			}
+		})
+		t.Cleanup(func() {
+			if err := tc.signerClient.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		var signed int32
+		tc.signerServer.SetRequestHandler(func(
+			privVal types.PrivValidator,
+			req privvalproto.Message,
+			chainID string,
+		) (privvalproto.Message, error) {
+			if req.GetSignVoteRequest() != nil {
+				atomic.AddInt32(&signed, 1)
+			}
+			return DefaultValidationRequestHandler(privVal, req, chainID)
+		})
+
+		want := failoverTestVote()
+		have := *want
+		require.NoError(t, tc.mockPV.SignVote(tc.chainID, want))
+		require.NoError(t, tc.signerClient.SignVote(tc.chainID, &have))
+		assert.Equal(t, want.Signature, have.Signature)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&signed))
+
+		// retries have the same request ID, so they get the response to the
+		// first request without signing again
+		retry := *want
+		retry.Signature = nil
+		require.NoError(t, tc.signerClient.SignVote(tc.chainID, &retry))
+		assert.Equal(t, want.Signature, retry.Signature)
+		assert.EqualValues(t, 1, atomic.LoadInt32(&signed))
+
+		// other requests are signed
+		require.NoError(t, tc.signerClient.SignVote(tc.chainID, failoverTestVote()))
+		assert.EqualValues(t, 2, atomic.LoadInt32(&signed))
+	}
+}
+
+func TestSignerVoteSigningInProgress(t *testing.T) {
+	for _, tc := range getSignerTestCases(t) {
+		tc := tc
+		t.Cleanup(func() {
+			if err := tc.signerServer.Stop(); err != nil {
+				t.Error(err)
+			}
+		})
+		t.Cleanup(func() {
+			if err := tc.signerClient.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		// signing takes several times the read timeout, but the signer tells
+		// it's still working on it
+		tc.signerServer.SetSigningKeepAlive(testTimeoutReadWrite / 3)
+		tc.signerServer.SetRequestHandler(slowSignHandler(3 * testTimeoutReadWrite))
+
+		want := failoverTestVote()
+		have := *want
+		require.NoError(t, tc.mockPV.SignVote(tc.chainID, want))
+		require.NoError(t, tc.signerClient.SignVote(tc.chainID, &have))
+		assert.Equal(t, want.Signature, have.Signature)
+
+		// up to the signing timeout
+		SignerListenerEndpointTimeoutSigning(testTimeoutReadWrite)(tc.signerClient.endpoint)
+		err := tc.signerClient.SignVote(tc.chainID, failoverTestVote())
+		require.ErrorIs(t, err, ErrReadTimeout)
+	}
+}
+
+func TestSignerVoteSigningInProgressWithoutRequestID(t *testing.T) {
+	for _, tc := range getSignerTestCases(t) {
+		tc := tc
+		t.Cleanup(func() {
+			if err := tc.signerServer.Stop(); err != nil {
+				t.Error(err)
+			}
+		})
+		t.Cleanup(func() {
+			if err := tc.signerClient.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		tc.signerServer.SetSigningKeepAlive(testTimeoutReadWrite / 3)
+		tc.signerServer.SetRequestHandler(slowSignHandler(3 * testTimeoutReadWrite))
+
+		// requests from older clients, without request ID, time out
+		_, err := tc.signerClient.endpoint.SendRequest(mustWrapMsg(
+			&privvalproto.SignVoteRequest{Vote: failoverTestVote(), ChainId: tc.chainID},
+		))
+		require.ErrorIs(t, err, ErrReadTimeout)
+	}
+}
+
+// slowSignHandler returns a request handler taking delay to sign votes.
+func slowSignHandler(delay time.Duration) ValidationRequestHandlerFunc {
+	return func(
+		privVal types.PrivValidator,
+		req privvalproto.Message,
+		chainID string,
+	) (privvalproto.Message, error) {
+		if req.GetSignVoteRequest() != nil {
+			time.Sleep(delay)
+		}
+		return DefaultValidationRequestHandler(privVal, req, chainID)
+	}
+}
+
+func TestSignerSignBytesMismatch(t *testing.T) {
+	var (
+		chainID  = cmtrand.Str(12)
+		mockPV   = types.NewMockPV()
+		vote     = failoverTestVote()
+		proposal = exampleProposal().ToProto()
+	)
+
+	req := mustWrapMsg(&privvalproto.SignVoteRequest{
+		Vote: vote, ChainId: chainID, SignBytes: types.VoteSignBytes("other", vote),
+	})
+	res, err := DefaultValidationRequestHandler(mockPV, req, chainID)
+	require.Error(t, err)
+	require.NotNil(t, res.GetSignedVoteResponse().GetError())
+
+	req = mustWrapMsg(&privvalproto.SignProposalRequest{
+		Proposal: proposal, ChainId: chainID, SignBytes: types.ProposalSignBytes("other", proposal),
+	})
+	res, err = DefaultValidationRequestHandler(mockPV, req, chainID)
+	require.Error(t, err)
+	require.NotNil(t, res.GetSignedProposalResponse().GetError())
+}
+
+func TestSignerRotatingKeys(t *testing.T) {
+	for _, dtc := range getDialerTestCases(t) {
+		chainID := cmtrand.Str(12)
+		privVal := genRotatingFilePV(t)
+		oldKey, newKey := privVal.Key.PubKey, privVal.Key.NextPubKey
+
+		sl, sd := getMockEndpoints(t, dtc.addr, dtc.dialer)
+		sc, err := NewSignerClient(sl, chainID)
+		require.NoError(t, err)
+		ss := NewSignerServer(sd, chainID, privVal)
+		require.NoError(t, ss.Start())
+		t.Cleanup(func() {
+			if err := ss.Stop(); err != nil {
+				t.Error(err)
+			}
+		})
+		t.Cleanup(func() {
+			if err := sc.Close(); err != nil {
+				t.Error(err)
+			}
+		})
+
+		pks, err := sc.GetPubKeys()
+		require.NoError(t, err)
+		assert.Equal(t, []crypto.PubKey{oldKey, newKey}, pks)
+
+		// sign at the last height of the old validator set with the old key
+		pk, err := sc.GetPubKey()
+		require.NoError(t, err)
+		assert.Equal(t, oldKey, pk)
+		vote := newVote(oldKey.Address(), 0, 10, 0, cmtproto.PrecommitType, types.BlockID{}).ToProto()
+		require.NoError(t, sc.SignVote(chainID, vote))
+		assert.True(t, oldKey.VerifySignature(types.VoteSignBytes(chainID, vote), vote.Signature))
+
+		// and at the first height of the new one with the new key
+		require.NoError(t, sc.SelectKey(newKey.Address()))
+		pk, err = sc.GetPubKey()
+		require.NoError(t, err)
+		assert.Equal(t, newKey, pk)
+		vote = newVote(newKey.Address(), 0, 11, 0, cmtproto.PrevoteType, types.BlockID{}).ToProto()
+		require.NoError(t, sc.SignVote(chainID, vote))
+		assert.True(t, newKey.VerifySignature(types.VoteSignBytes(chainID, vote), vote.Signature))
+
+		// the signer refuses to sign with a key it doesn't have
+		require.NoError(t, sc.SelectKey(ed25519.GenPrivKey().PubKey().Address()))
+		vote = newVote(newKey.Address(), 0, 12, 0, cmtproto.PrevoteType, types.BlockID{}).ToProto()
+		assert.Error(t, sc.SignVote(chainID, vote))
+	}
+}
+
+func TestSignerSelectUnknownKey(t *testing.T) {
+	var (
+		chainID = cmtrand.Str(12)
+		mockPV  = types.NewMockPV()
+		vote    = failoverTestVote()
+	)
+
+	pk, err := mockPV.GetPubKey()
+	require.NoError(t, err)
+	req := mustWrapMsg(&privvalproto.SignVoteRequest{
+		Vote: vote, ChainId: chainID, PubKeyAddress: pk.Address(),
+	})
+	_, err = DefaultValidationRequestHandler(mockPV, req, chainID)
+	require.NoError(t, err)
+
+	req = mustWrapMsg(&privvalproto.SignVoteRequest{
+		Vote: vote, ChainId: chainID, PubKeyAddress: ed25519.GenPrivKey().PubKey().Address(),
+	})
+	res, err := DefaultValidationRequestHandler(mockPV, req, chainID)
+	require.Error(t, err)
+	require.NotNil(t, res.GetSignedVoteResponse().GetError())
+}
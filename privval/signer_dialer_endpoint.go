@@ -33,6 +33,14 @@ func SignerDialerEndpointRetryWaitInterval(interval time.Duration) SignerService
 	return func(ss *SignerDialerEndpoint) { ss.retryWait = interval }
 }
 
+// SignerDialerEndpointRetryBackoff makes the wait between retries double
+// after each failed attempt, from the retry wait interval up to maxWait. The
+// waits are jittered, so that signers don't retry in lockstep. Without it,
+// the client waits the retry wait interval between retries.
+func SignerDialerEndpointRetryBackoff(maxWait time.Duration) SignerServiceEndpointOption {
+	return func(ss *SignerDialerEndpoint) { ss.maxRetryWait = maxWait }
+}
+
 // SignerDialerEndpoint dials using its dialer and responds to any signature
 // requests using its privVal.
 type SignerDialerEndpoint struct {
@@ -41,6 +49,7 @@ type SignerDialerEndpoint struct {
 	dialer SocketDialer
 
 	retryWait      time.Duration
+	maxRetryWait   time.Duration
 	maxConnRetries int
 }
 
@@ -74,6 +83,10 @@ func (sd *SignerDialerEndpoint) ensureConnection() error {
 		return nil
 	}
 
+	var backoff *backoff
+	if sd.maxRetryWait > 0 {
+		backoff = newBackoff(sd.retryWait, sd.maxRetryWait)
+	}
 	retries := 0
 	for retries < sd.maxConnRetries {
 		conn, err := sd.dialer()
@@ -82,7 +95,11 @@ func (sd *SignerDialerEndpoint) ensureConnection() error {
 			retries++
 			sd.Logger.Debug("SignerDialer: Reconnection failed", "retries", retries, "max", sd.maxConnRetries, "err", err)
 			// Wait between retries
-			time.Sleep(sd.retryWait)
+			if backoff != nil {
+				time.Sleep(backoff.wait())
+			} else {
+				time.Sleep(sd.retryWait)
+			}
 		} else {
 			sd.SetConnection(conn)
 			sd.Logger.Debug("SignerDialer: Connection Ready")
@@ -81,6 +81,11 @@ func (se *signerEndpoint) DropConnection() {
 
 // ReadMessage reads a message from the endpoint
 func (se *signerEndpoint) ReadMessage() (msg privvalproto.Message, err error) {
+	return se.readMessage(se.timeoutReadWrite)
+}
+
+// readMessage reads a message from the endpoint, waiting for it up to timeout.
+func (se *signerEndpoint) readMessage(timeout time.Duration) (msg privvalproto.Message, err error) {
 	se.connMtx.Lock()
 	defer se.connMtx.Unlock()
 
@@ -88,7 +93,7 @@ func (se *signerEndpoint) ReadMessage() (msg privvalproto.Message, err error) {
 		return msg, fmt.Errorf("endpoint is not connected: %w", ErrNoConnection)
 	}
 	// Reset read deadline
-	deadline := time.Now().Add(se.timeoutReadWrite)
+	deadline := time.Now().Add(timeout)
 
 	err = se.conn.SetReadDeadline(deadline)
 	if err != nil {
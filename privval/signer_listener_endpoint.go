@@ -1,6 +1,7 @@
 package privval
 
 import (
+	"errors"
 	"fmt"
 	"net"
 	"time"
@@ -9,6 +10,11 @@ import (
 	"github.com/tendermint/tendermint/libs/service"
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	defaultTimeoutSigningSeconds = 30
 )
 
 // SignerListenerEndpointOption sets an optional parameter on the SignerListenerEndpoint.
@@ -22,11 +28,51 @@ func SignerListenerEndpointTimeoutReadWrite(timeout time.Duration) SignerListene
 	return func(sl *SignerListenerEndpoint) { sl.signerEndpoint.timeoutReadWrite = timeout }
 }
 
+// SignerListenerEndpointTimeoutSigning sets how long an external signing
+// process can keep signing a request, sending SigningInProgress messages to
+// tell it's still working on it, e.g. during a threshold signing ceremony.
+//
+// Default: 30s
+func SignerListenerEndpointTimeoutSigning(timeout time.Duration) SignerListenerEndpointOption {
+	return func(sl *SignerListenerEndpoint) { sl.timeoutSigning = timeout }
+}
+
+// SignerListenerEndpointTimeoutSignRequest sets how long to wait for the
+// external signing process to answer a sign request, or to tell it's still
+// signing it.
+//
+// Default: the read/write timeout
+func SignerListenerEndpointTimeoutSignRequest(timeout time.Duration) SignerListenerEndpointOption {
+	return func(sl *SignerListenerEndpoint) { sl.timeoutSignRequest = timeout }
+}
+
+// SignerListenerEndpointPingInterval sets how often the connection is checked
+// with a ping when idle. It must be less than the read timeout of the external
+// signing process, which otherwise drops the connection.
+//
+// Default: 2/3 of the read/write timeout
+func SignerListenerEndpointPingInterval(interval time.Duration) SignerListenerEndpointOption {
+	return func(sl *SignerListenerEndpoint) { sl.pingInterval = interval }
+}
+
+// SignerListenerEndpointTimeoutPong sets how long to wait for the external
+// signing process to answer a ping before dropping the connection.
+//
+// Default: the read/write timeout
+func SignerListenerEndpointTimeoutPong(timeout time.Duration) SignerListenerEndpointOption {
+	return func(sl *SignerListenerEndpoint) { sl.timeoutPong = timeout }
+}
+
+// SignerListenerEndpointMetrics sets the metrics.
+func SignerListenerEndpointMetrics(metrics *Metrics) SignerListenerEndpointOption {
+	return func(sl *SignerListenerEndpoint) { sl.metrics = metrics }
+}
+
 // SignerListenerEndpoint listens for an external process to dial in and keeps
 // the connection alive by dropping and reconnecting.
 //
-// The process will send pings every ~3s (read/write timeout * 2/3) to keep the
-// connection alive.
+// The process will send pings every ~3s (read/write timeout * 2/3) by default
+// to keep the connection alive.
 type SignerListenerEndpoint struct {
 	signerEndpoint
 
@@ -34,9 +80,14 @@ type SignerListenerEndpoint struct {
 	connectRequestCh      chan struct{}
 	connectionAvailableCh chan net.Conn
 
-	timeoutAccept time.Duration
-	pingTimer     *time.Ticker
-	pingInterval  time.Duration
+	timeoutAccept      time.Duration
+	timeoutSigning     time.Duration
+	timeoutSignRequest time.Duration
+	timeoutPong        time.Duration
+	pingTimer          *time.Ticker
+	pingInterval       time.Duration
+
+	metrics *Metrics
 
 	instanceMtx cmtsync.Mutex // Ensures instance public methods access, i.e. SendRequest
 }
@@ -48,8 +99,10 @@ func NewSignerListenerEndpoint(
 	options ...SignerListenerEndpointOption,
 ) *SignerListenerEndpoint {
 	sl := &SignerListenerEndpoint{
-		listener:      listener,
-		timeoutAccept: defaultTimeoutAcceptSeconds * time.Second,
+		listener:       listener,
+		timeoutAccept:  defaultTimeoutAcceptSeconds * time.Second,
+		timeoutSigning: defaultTimeoutSigningSeconds * time.Second,
+		metrics:        NopMetrics(),
 	}
 
 	sl.BaseService = *service.NewBaseService(logger, "SignerListenerEndpoint", sl)
@@ -59,6 +112,17 @@ func NewSignerListenerEndpoint(
 		optionFunc(sl)
 	}
 
+	if sl.timeoutSignRequest == 0 {
+		sl.timeoutSignRequest = sl.timeoutReadWrite
+	}
+	if sl.timeoutPong == 0 {
+		sl.timeoutPong = sl.timeoutReadWrite
+	}
+	if sl.pingInterval == 0 {
+		// NOTE: ping interval must be less than read/write timeout
+		sl.pingInterval = time.Duration(sl.timeoutReadWrite.Milliseconds()*2/3) * time.Millisecond
+	}
+
 	return sl
 }
 
@@ -67,8 +131,6 @@ func (sl *SignerListenerEndpoint) OnStart() error {
 	sl.connectRequestCh = make(chan struct{})
 	sl.connectionAvailableCh = make(chan net.Conn)
 
-	// NOTE: ping timeout must be less than read/write timeout
-	sl.pingInterval = time.Duration(sl.signerEndpoint.timeoutReadWrite.Milliseconds()*2/3) * time.Millisecond
 	sl.pingTimer = time.NewTicker(sl.pingInterval)
 
 	go sl.serviceLoop()
@@ -113,15 +175,40 @@ func (sl *SignerListenerEndpoint) SendRequest(request privvalproto.Message) (*pr
 		return nil, err
 	}
 
+	start := time.Now()
 	err = sl.WriteMessage(request)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %w", types.ErrConnectionDropped, err)
 	}
 
-	res, err := sl.ReadMessage()
+	res, err := sl.readResponse(request)
 	if err != nil {
 		return nil, err
 	}
+	if request.GetPingRequest() != nil {
+		sl.metrics.SignerRTT.With("endpoint", sl.name()).Observe(time.Since(start).Seconds())
+	}
+
+	// The signer may tell it's still signing the request before responding,
+	// which extends the read deadline up to timeoutSigning.
+	deadline := time.Now().Add(sl.timeoutSigning)
+	for res.GetSigningInProgress() != nil {
+		requestID := msgRequestID(request)
+		if requestID == "" || res.GetSigningInProgress().RequestId != requestID {
+			sl.DropConnection()
+			return nil, ErrUnexpectedResponse
+		}
+		if time.Now().After(deadline) {
+			sl.DropConnection()
+			return nil, fmt.Errorf("signing for more than %v: %w: %w", sl.timeoutSigning, types.ErrSignTimeout, ErrReadTimeout)
+		}
+		sl.Logger.Debug("SignerListener: Signer still signing", "request_id", requestID)
+
+		res, err = sl.readResponse(request)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	// Reset pingTimer to avoid sending unnecessary pings.
 	sl.pingTimer.Reset(sl.pingInterval)
@@ -129,6 +216,42 @@ func (sl *SignerListenerEndpoint) SendRequest(request privvalproto.Message) (*pr
 	return &res, nil
 }
 
+// readResponse reads the response to a request, waiting for it up to the
+// timeout of this type of request. Its errors tell a sign request timing out
+// from the connection dropping.
+func (sl *SignerListenerEndpoint) readResponse(request privvalproto.Message) (privvalproto.Message, error) {
+	var (
+		timeout     = sl.timeoutReadWrite
+		signRequest bool
+	)
+	switch request.Sum.(type) {
+	case *privvalproto.Message_PingRequest:
+		timeout = sl.timeoutPong
+	case *privvalproto.Message_SignVoteRequest, *privvalproto.Message_SignProposalRequest:
+		timeout, signRequest = sl.timeoutSignRequest, true
+	}
+
+	res, err := sl.readMessage(timeout)
+	switch {
+	case err == nil:
+		return res, nil
+	case errors.Is(err, ErrReadTimeout) && signRequest:
+		return res, fmt.Errorf("%w: %w", types.ErrSignTimeout, err)
+	case errors.Is(err, ErrReadTimeout):
+		return res, err
+	default:
+		return res, fmt.Errorf("%w: %w", types.ErrConnectionDropped, err)
+	}
+}
+
+// name returns the address the endpoint listens on, to label its metrics.
+func (sl *SignerListenerEndpoint) name() string {
+	if sl.listener == nil {
+		return ""
+	}
+	return sl.listener.Addr().String()
+}
+
 func (sl *SignerListenerEndpoint) ensureConnection(maxWait time.Duration) error {
 	if sl.IsConnected() {
 		return nil
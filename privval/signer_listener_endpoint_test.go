@@ -2,9 +2,11 @@ package privval
 
 import (
 	"net"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/tendermint/tendermint/libs/log"
 	cmtnet "github.com/tendermint/tendermint/libs/net"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -145,7 +148,124 @@ func TestRetryConnToRemoteSigner(t *testing.T) {
 	}
 }
 
-func newSignerListenerEndpoint(logger log.Logger, addr string, timeoutReadWrite time.Duration) *SignerListenerEndpoint {
+// delayedSigner starts a signer answering the listener endpoint built with
+// the given options, and a client of it. The signer waits for the delay
+// stored in pingDelay before answering pings and in signDelay before signing.
+func delayedSigner(
+	t *testing.T,
+	dtc dialerTestCase,
+	pingDelay, signDelay *int64,
+	options ...SignerListenerEndpointOption,
+) (*SignerClient, *SignerServer, *SignerDialerEndpoint) {
+	chainID := cmtrand.Str(12)
+	sl, sd := getMockEndpoints(t, dtc.addr, dtc.dialer, options...)
+	sc, err := NewSignerClient(sl, chainID)
+	require.NoError(t, err)
+	ss := NewSignerServer(sd, chainID, types.NewMockPV())
+	ss.SetRequestHandler(func(
+		privVal types.PrivValidator,
+		req privvalproto.Message,
+		chainID string,
+	) (privvalproto.Message, error) {
+		switch {
+		case req.GetPingRequest() != nil:
+			time.Sleep(time.Duration(atomic.LoadInt64(pingDelay)))
+		case req.GetSignVoteRequest() != nil:
+			time.Sleep(time.Duration(atomic.LoadInt64(signDelay)))
+		}
+		return DefaultValidationRequestHandler(privVal, req, chainID)
+	})
+	require.NoError(t, ss.Start())
+	t.Cleanup(func() {
+		if err := ss.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Cleanup(func() {
+		if err := sc.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return sc, ss, sd
+}
+
+func TestSignerListenerTimeoutSignRequest(t *testing.T) {
+	for _, dtc := range getDialerTestCases(t) {
+		var pingDelay, signDelay int64
+		sc, _, _ := delayedSigner(t, dtc, &pingDelay, &signDelay,
+			SignerListenerEndpointTimeoutSignRequest(4*testTimeoutReadWrite))
+
+		// signing can take longer than the read/write timeout
+		atomic.StoreInt64(&signDelay, int64(2*testTimeoutReadWrite))
+		require.NoError(t, sc.SignVote(sc.chainID, failoverTestVote()))
+
+		// but not longer than the sign request timeout
+		atomic.StoreInt64(&signDelay, int64(6*testTimeoutReadWrite))
+		err := sc.SignVote(sc.chainID, failoverTestVote())
+		assert.ErrorIs(t, err, types.ErrSignTimeout)
+		assert.ErrorIs(t, err, ErrReadTimeout)
+		assert.NotErrorIs(t, err, types.ErrConnectionDropped)
+	}
+}
+
+func TestSignerListenerTimeoutPong(t *testing.T) {
+	for _, dtc := range getDialerTestCases(t) {
+		var (
+			pingDelay, signDelay int64
+			rtt                  = generic.NewHistogram("rtt", 10)
+			m                    = NopMetrics()
+		)
+		m.SignerRTT = rtt
+		sc, _, _ := delayedSigner(t, dtc, &pingDelay, &signDelay,
+			SignerListenerEndpointTimeoutPong(testTimeoutReadWrite/2),
+			SignerListenerEndpointPingInterval(testTimeoutReadWrite/4),
+			SignerListenerEndpointMetrics(m))
+
+		// pings measure the round trip time to the signer
+		ping := mustWrapMsg(&privvalproto.PingRequest{})
+		_, err := sc.endpoint.SendRequest(ping)
+		require.NoError(t, err)
+		assert.Greater(t, rtt.Quantile(0.99), 0.0)
+
+		// a signer answering pings past the pong timeout is dropped, even if
+		// within the read/write timeout
+		atomic.StoreInt64(&pingDelay, int64(testTimeoutReadWrite*3/4))
+		_, err = sc.endpoint.SendRequest(ping)
+		assert.ErrorIs(t, err, ErrReadTimeout)
+		assert.NotErrorIs(t, err, types.ErrSignTimeout)
+		assert.NotErrorIs(t, err, types.ErrConnectionDropped)
+		assert.False(t, sc.IsConnected())
+	}
+}
+
+func TestSignerListenerConnectionDropped(t *testing.T) {
+	for _, dtc := range getDialerTestCases(t) {
+		var pingDelay, signDelay int64
+		sc, ss, sd := delayedSigner(t, dtc, &pingDelay, &signDelay)
+
+		// the signer drops the connection while signing
+		ss.SetRequestHandler(func(
+			privVal types.PrivValidator,
+			req privvalproto.Message,
+			chainID string,
+		) (privvalproto.Message, error) {
+			if req.GetSignVoteRequest() != nil {
+				sd.DropConnection()
+			}
+			return DefaultValidationRequestHandler(privVal, req, chainID)
+		})
+		err := sc.SignVote(sc.chainID, failoverTestVote())
+		assert.ErrorIs(t, err, types.ErrConnectionDropped)
+		assert.NotErrorIs(t, err, types.ErrSignTimeout)
+	}
+}
+
+func newSignerListenerEndpoint(
+	logger log.Logger,
+	addr string,
+	timeoutReadWrite time.Duration,
+	options ...SignerListenerEndpointOption,
+) *SignerListenerEndpoint {
 	proto, address := cmtnet.ProtocolAndAddress(addr)
 
 	ln, err := net.Listen(proto, address)
@@ -171,7 +291,7 @@ func newSignerListenerEndpoint(logger log.Logger, addr string, timeoutReadWrite
 	return NewSignerListenerEndpoint(
 		logger,
 		listener,
-		SignerListenerEndpointTimeoutReadWrite(testTimeoutReadWrite),
+		append([]SignerListenerEndpointOption{SignerListenerEndpointTimeoutReadWrite(testTimeoutReadWrite)}, options...)...,
 	)
 }
 
@@ -187,6 +307,7 @@ func getMockEndpoints(
 	t *testing.T,
 	addr string,
 	socketDialer SocketDialer,
+	options ...SignerListenerEndpointOption,
 ) (*SignerListenerEndpoint, *SignerDialerEndpoint) {
 
 	var (
@@ -198,7 +319,7 @@ func getMockEndpoints(
 			socketDialer,
 		)
 
-		listenerEndpoint = newSignerListenerEndpoint(logger, addr, testTimeoutReadWrite)
+		listenerEndpoint = newSignerListenerEndpoint(logger, addr, testTimeoutReadWrite, options...)
 	)
 
 	SignerDialerEndpointTimeoutReadWrite(testTimeoutReadWrite)(dialerEndpoint)
@@ -1,10 +1,13 @@
 package privval
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/tendermint/tendermint/crypto"
 	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
 	cryptoproto "github.com/tendermint/tendermint/proto/tendermint/crypto"
 	privvalproto "github.com/tendermint/tendermint/proto/tendermint/privval"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
@@ -40,11 +43,16 @@ func DefaultValidationRequestHandler(
 			return res, err
 		}
 
+		var pks []cryptoproto.PublicKey
+		if rpv, ok := privVal.(types.KeyRotatingPrivValidator); ok {
+			pks, err = pubKeysToProto(rpv)
+		}
+
 		if err != nil {
 			res = mustWrapMsg(&privvalproto.PubKeyResponse{
 				PubKey: cryptoproto.PublicKey{}, Error: &privvalproto.RemoteSignerError{Code: 0, Description: err.Error()}})
 		} else {
-			res = mustWrapMsg(&privvalproto.PubKeyResponse{PubKey: pk, Error: nil})
+			res = mustWrapMsg(&privvalproto.PubKeyResponse{PubKey: pk, Error: nil, PubKeys: pks})
 		}
 
 	case *privvalproto.Message_SignVoteRequest:
@@ -57,10 +65,21 @@ func DefaultValidationRequestHandler(
 
 		vote := r.SignVoteRequest.Vote
 
-		err = privVal.SignVote(chainID, vote)
+		signBytes := r.SignVoteRequest.SignBytes
+		if len(signBytes) > 0 && !bytes.Equal(signBytes, types.VoteSignBytes(chainID, vote)) {
+			res = mustWrapMsg(&privvalproto.SignedVoteResponse{
+				Vote: cmtproto.Vote{}, Error: &privvalproto.RemoteSignerError{
+					Code: 0, Description: "sign bytes don't match the vote"}})
+			return res, errors.New("sign bytes don't match the vote")
+		}
+
+		err = selectKey(privVal, r.SignVoteRequest.PubKeyAddress)
+		if err == nil {
+			err = privVal.SignVote(chainID, vote)
+		}
 		if err != nil {
 			res = mustWrapMsg(&privvalproto.SignedVoteResponse{
-				Vote: cmtproto.Vote{}, Error: &privvalproto.RemoteSignerError{Code: 0, Description: err.Error()}})
+				Vote: cmtproto.Vote{}, Error: signErrorToProto(err)})
 		} else {
 			res = mustWrapMsg(&privvalproto.SignedVoteResponse{Vote: *vote, Error: nil})
 		}
@@ -76,10 +95,21 @@ func DefaultValidationRequestHandler(
 
 		proposal := r.SignProposalRequest.Proposal
 
-		err = privVal.SignProposal(chainID, proposal)
+		signBytes := r.SignProposalRequest.SignBytes
+		if len(signBytes) > 0 && !bytes.Equal(signBytes, types.ProposalSignBytes(chainID, proposal)) {
+			res = mustWrapMsg(&privvalproto.SignedProposalResponse{
+				Proposal: cmtproto.Proposal{}, Error: &privvalproto.RemoteSignerError{
+					Code: 0, Description: "sign bytes don't match the proposal"}})
+			return res, errors.New("sign bytes don't match the proposal")
+		}
+
+		err = selectKey(privVal, r.SignProposalRequest.PubKeyAddress)
+		if err == nil {
+			err = privVal.SignProposal(chainID, proposal)
+		}
 		if err != nil {
 			res = mustWrapMsg(&privvalproto.SignedProposalResponse{
-				Proposal: cmtproto.Proposal{}, Error: &privvalproto.RemoteSignerError{Code: 0, Description: err.Error()}})
+				Proposal: cmtproto.Proposal{}, Error: signErrorToProto(err)})
 		} else {
 			res = mustWrapMsg(&privvalproto.SignedProposalResponse{Proposal: *proposal, Error: nil})
 		}
@@ -92,3 +122,46 @@ func DefaultValidationRequestHandler(
 
 	return res, err
 }
+
+// signErrorToProto converts an error signing a vote or a proposal to a remote
+// signer error, with the code of double signing if it's one.
+func signErrorToProto(err error) *privvalproto.RemoteSignerError {
+	var doubleSignErr *DoubleSignError
+	if errors.As(err, &doubleSignErr) {
+		return &privvalproto.RemoteSignerError{Code: RemoteSignerErrorCodeDoubleSign, Description: err.Error()}
+	}
+	return &privvalproto.RemoteSignerError{Code: 0, Description: err.Error()}
+}
+
+func pubKeysToProto(privVal types.KeyRotatingPrivValidator) ([]cryptoproto.PublicKey, error) {
+	pubKeys, err := privVal.GetPubKeys()
+	if err != nil {
+		return nil, err
+	}
+	pks := make([]cryptoproto.PublicKey, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		if pks[i], err = cryptoenc.PubKeyToProto(pubKey); err != nil {
+			return nil, err
+		}
+	}
+	return pks, nil
+}
+
+// selectKey selects the key of privVal with the given address, if any, to
+// sign with. PrivValidators which can't rotate their key only have one.
+func selectKey(privVal types.PrivValidator, address []byte) error {
+	if len(address) == 0 {
+		return nil
+	}
+	if rpv, ok := privVal.(types.KeyRotatingPrivValidator); ok {
+		return rpv.SelectKey(address)
+	}
+	pubKey, err := privVal.GetPubKey()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pubKey.Address(), address) {
+		return fmt.Errorf("no key with address %v", cmtbytes.HexBytes(address))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package privval
 
 import (
 	"io"
+	"time"
 
 	"github.com/tendermint/tendermint/libs/service"
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
@@ -9,6 +10,10 @@ import (
 	"github.com/tendermint/tendermint/types"
 )
 
+// signedResponsesCacheSize is the number of responses to signing requests the
+// SignerServer keeps, to reply to retries of these requests.
+const signedResponsesCacheSize = 16
+
 // ValidationRequestHandlerFunc handles different remoteSigner requests
 type ValidationRequestHandlerFunc func(
 	privVal types.PrivValidator,
@@ -24,6 +29,15 @@ type SignerServer struct {
 
 	handlerMtx               cmtsync.Mutex
 	validationRequestHandler ValidationRequestHandlerFunc
+	keepAliveInterval        time.Duration
+
+	// responses to the last signing requests, by request ID
+	signedResponses []signedResponse
+}
+
+type signedResponse struct {
+	requestID string
+	res       privvalproto.Message
 }
 
 func NewSignerServer(endpoint *SignerDialerEndpoint, chainID string, privVal types.PrivValidator) *SignerServer {
@@ -58,6 +72,17 @@ func (ss *SignerServer) SetRequestHandler(validationRequestHandler ValidationReq
 	ss.validationRequestHandler = validationRequestHandler
 }
 
+// SetSigningKeepAlive makes the server send a SigningInProgress message every
+// interval while it handles a signing request, so slow signers, e.g. taking
+// part in a threshold signing ceremony, aren't mistaken for dead ones. It's
+// only done for requests with a request ID, which older clients don't set.
+// An interval of 0, the default, disables it.
+func (ss *SignerServer) SetSigningKeepAlive(interval time.Duration) {
+	ss.handlerMtx.Lock()
+	defer ss.handlerMtx.Unlock()
+	ss.keepAliveInterval = interval
+}
+
 func (ss *SignerServer) servicePendingRequest() {
 	if !ss.IsRunning() {
 		return // Ignore error from closing.
@@ -71,15 +96,22 @@ func (ss *SignerServer) servicePendingRequest() {
 		return
 	}
 
-	var res privvalproto.Message
-	{
+	requestID := msgRequestID(req)
+	res, ok := ss.signedResponse(requestID)
+	if ok {
+		ss.Logger.Info("SignerServer: replying to a retried request", "request_id", requestID)
+	} else {
 		// limit the scope of the lock
 		ss.handlerMtx.Lock()
 		defer ss.handlerMtx.Unlock()
+		stopKeepAlive := ss.keepAlive(requestID)
 		res, err = ss.validationRequestHandler(ss.privVal, req, ss.chainID)
+		stopKeepAlive()
 		if err != nil {
 			// only log the error; we'll reply with an error in res
 			ss.Logger.Error("SignerServer: handleMessage", "err", err)
+		} else {
+			ss.saveSignedResponse(requestID, res)
 		}
 	}
 
@@ -89,6 +121,68 @@ func (ss *SignerServer) servicePendingRequest() {
 	}
 }
 
+// keepAlive sends SigningInProgress messages for the request with the given
+// ID until the returned function is called.
+func (ss *SignerServer) keepAlive(requestID string) (stop func()) {
+	if requestID == "" || ss.keepAliveInterval <= 0 {
+		return func() {}
+	}
+
+	var (
+		quit = make(chan struct{})
+		done = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(ss.keepAliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				err := ss.endpoint.WriteMessage(mustWrapMsg(&privvalproto.SigningInProgress{RequestId: requestID}))
+				if err != nil {
+					ss.Logger.Error("SignerServer: keepAlive", "err", err)
+				}
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(quit)
+		<-done
+	}
+}
+
+// signedResponse returns the response to the signing request with the given
+// ID, if it was handled already.
+func (ss *SignerServer) signedResponse(requestID string) (privvalproto.Message, bool) {
+	if requestID == "" {
+		return privvalproto.Message{}, false
+	}
+	for _, sr := range ss.signedResponses {
+		if sr.requestID == requestID {
+			return sr.res, true
+		}
+	}
+	return privvalproto.Message{}, false
+}
+
+// saveSignedResponse keeps the response to the signing request with the given
+// ID, unless it's an error which may be temporary.
+func (ss *SignerServer) saveSignedResponse(requestID string, res privvalproto.Message) {
+	if requestID == "" ||
+		res.GetSignedVoteResponse().GetError() != nil ||
+		res.GetSignedProposalResponse().GetError() != nil {
+		return
+	}
+	if len(ss.signedResponses) == signedResponsesCacheSize {
+		ss.signedResponses = ss.signedResponses[1:]
+	}
+	ss.signedResponses = append(ss.signedResponses, signedResponse{requestID: requestID, res: res})
+}
+
 func (ss *SignerServer) serviceLoop() {
 	for {
 		select {
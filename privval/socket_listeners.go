@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	p2pconn "github.com/tendermint/tendermint/p2p/conn"
 )
 
@@ -152,23 +153,33 @@ func (ln *UnixListener) Accept() (net.Conn, error) {
 var _ net.Conn = (*timeoutConn)(nil)
 
 // timeoutConn wraps a net.Conn to standardize protocol timeouts / deadline resets.
+// Deadlines explicitly set on the conn take precedence over the timeout.
 type timeoutConn struct {
 	net.Conn
 	timeout time.Duration
+
+	mtx           cmtsync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
 }
 
 // newTimeoutConn returns an instance of timeoutConn.
 func newTimeoutConn(conn net.Conn, timeout time.Duration) *timeoutConn {
 	return &timeoutConn{
-		conn,
-		timeout,
+		Conn:    conn,
+		timeout: timeout,
 	}
 }
 
 // Read implements net.Conn.
-func (c timeoutConn) Read(b []byte) (n int, err error) {
+func (c *timeoutConn) Read(b []byte) (n int, err error) {
 	// Reset deadline
-	deadline := time.Now().Add(c.timeout)
+	c.mtx.Lock()
+	deadline := c.readDeadline
+	c.mtx.Unlock()
+	if deadline.IsZero() {
+		deadline = time.Now().Add(c.timeout)
+	}
 	err = c.Conn.SetReadDeadline(deadline)
 	if err != nil {
 		return
@@ -178,9 +189,14 @@ func (c timeoutConn) Read(b []byte) (n int, err error) {
 }
 
 // Write implements net.Conn.
-func (c timeoutConn) Write(b []byte) (n int, err error) {
+func (c *timeoutConn) Write(b []byte) (n int, err error) {
 	// Reset deadline
-	deadline := time.Now().Add(c.timeout)
+	c.mtx.Lock()
+	deadline := c.writeDeadline
+	c.mtx.Unlock()
+	if deadline.IsZero() {
+		deadline = time.Now().Add(c.timeout)
+	}
 	err = c.Conn.SetWriteDeadline(deadline)
 	if err != nil {
 		return
@@ -188,3 +204,27 @@ func (c timeoutConn) Write(b []byte) (n int, err error) {
 
 	return c.Conn.Write(b)
 }
+
+// SetDeadline implements net.Conn.
+func (c *timeoutConn) SetDeadline(t time.Time) error {
+	c.mtx.Lock()
+	c.readDeadline, c.writeDeadline = t, t
+	c.mtx.Unlock()
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn.
+func (c *timeoutConn) SetReadDeadline(t time.Time) error {
+	c.mtx.Lock()
+	c.readDeadline = t
+	c.mtx.Unlock()
+	return c.Conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline implements net.Conn.
+func (c *timeoutConn) SetWriteDeadline(t time.Time) error {
+	c.mtx.Lock()
+	c.writeDeadline = t
+	c.mtx.Unlock()
+	return c.Conn.SetWriteDeadline(t)
+}
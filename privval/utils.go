@@ -26,7 +26,11 @@ func IsConnTimeout(err error) bool {
 }
 
 // NewSignerListener creates a new SignerListenerEndpoint using the corresponding listen address
-func NewSignerListener(listenAddr string, logger log.Logger) (*SignerListenerEndpoint, error) {
+func NewSignerListener(
+	listenAddr string,
+	logger log.Logger,
+	options ...SignerListenerEndpointOption,
+) (*SignerListenerEndpoint, error) {
 	var listener net.Listener
 
 	protocol, address := cmtnet.ProtocolAndAddress(listenAddr)
@@ -47,7 +51,7 @@ func NewSignerListener(listenAddr string, logger log.Logger) (*SignerListenerEnd
 		)
 	}
 
-	pve := NewSignerListenerEndpoint(logger.With("module", "privval"), listener)
+	pve := NewSignerListenerEndpoint(logger.With("module", "privval"), listener, options...)
 
 	return pve, nil
 }
@@ -11,6 +11,8 @@ var (
 	_ p2p.Wrapper   = &Txs{}
 	_ p2p.Wrapper   = &SeenTx{}
 	_ p2p.Wrapper   = &WantTx{}
+	_ p2p.Wrapper   = &WantTxs{}
+	_ p2p.Wrapper   = &TxChunk{}
 	_ p2p.Unwrapper = &Message{}
 )
 
@@ -35,6 +37,20 @@ func (m *WantTx) Wrap() proto.Message {
 	return mm
 }
 
+// Wrap implements the p2p Wrapper interface and wraps a mempool want txs message.
+func (m *WantTxs) Wrap() proto.Message {
+	mm := &Message{}
+	mm.Sum = &Message_WantTxs{WantTxs: m}
+	return mm
+}
+
+// Wrap implements the p2p Wrapper interface and wraps a mempool tx chunk message.
+func (m *TxChunk) Wrap() proto.Message {
+	mm := &Message{}
+	mm.Sum = &Message_TxChunk{TxChunk: m}
+	return mm
+}
+
 // Unwrap implements the p2p Wrapper interface and unwraps a wrapped mempool
 // message.
 func (m *Message) Unwrap() (proto.Message, error) {
@@ -48,6 +64,12 @@ func (m *Message) Unwrap() (proto.Message, error) {
 	case *Message_WantTx:
 		return m.GetWantTx(), nil
 
+	case *Message_WantTxs:
+		return m.GetWantTxs(), nil
+
+	case *Message_TxChunk:
+		return m.GetTxChunk(), nil
+
 	default:
 		return nil, fmt.Errorf("unknown message: %T", msg)
 	}
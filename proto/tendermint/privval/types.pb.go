@@ -163,6 +163,9 @@ func (m *PubKeyRequest) GetChainId() string {
 type PubKeyResponse struct {
 	PubKey crypto.PublicKey   `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key"`
 	Error  *RemoteSignerError `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// pub_keys are all the public keys of the signer, e.g. the old and the new
+	// keys of a validator rotating its consensus key.
+	PubKeys []crypto.PublicKey `protobuf:"bytes,3,rep,name=pub_keys,json=pubKeys,proto3" json:"pub_keys"`
 }
 
 func (m *PubKeyResponse) Reset()         { *m = PubKeyResponse{} }
@@ -212,10 +215,26 @@ func (m *PubKeyResponse) GetError() *RemoteSignerError {
 	return nil
 }
 
+func (m *PubKeyResponse) GetPubKeys() []crypto.PublicKey {
+	if m != nil {
+		return m.PubKeys
+	}
+	return nil
+}
+
 // SignVoteRequest is a request to sign a vote
 type SignVoteRequest struct {
 	Vote    *types.Vote `protobuf:"bytes,1,opt,name=vote,proto3" json:"vote,omitempty"`
 	ChainId string      `protobuf:"bytes,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	// sign_bytes are the bytes to sign, including the chain ID.
+	SignBytes []byte `protobuf:"bytes,3,opt,name=sign_bytes,json=signBytes,proto3" json:"sign_bytes,omitempty"`
+	// request_id identifies the request, retries included, so the signer can
+	// reply to a retry without signing again. If set, the signer may send
+	// SigningInProgress messages while signing.
+	RequestId string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// pub_key_address is the address of the key to sign with, among pub_keys.
+	// If empty, the signer signs with its pub_key.
+	PubKeyAddress []byte `protobuf:"bytes,5,opt,name=pub_key_address,json=pubKeyAddress,proto3" json:"pub_key_address,omitempty"`
 }
 
 func (m *SignVoteRequest) Reset()         { *m = SignVoteRequest{} }
@@ -265,6 +284,27 @@ func (m *SignVoteRequest) GetChainId() string {
 	return ""
 }
 
+func (m *SignVoteRequest) GetSignBytes() []byte {
+	if m != nil {
+		return m.SignBytes
+	}
+	return nil
+}
+
+func (m *SignVoteRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *SignVoteRequest) GetPubKeyAddress() []byte {
+	if m != nil {
+		return m.PubKeyAddress
+	}
+	return nil
+}
+
 // SignedVoteResponse is a response containing a signed vote or an error
 type SignedVoteResponse struct {
 	Vote  types.Vote         `protobuf:"bytes,1,opt,name=vote,proto3" json:"vote"`
@@ -322,6 +362,15 @@ func (m *SignedVoteResponse) GetError() *RemoteSignerError {
 type SignProposalRequest struct {
 	Proposal *types.Proposal `protobuf:"bytes,1,opt,name=proposal,proto3" json:"proposal,omitempty"`
 	ChainId  string          `protobuf:"bytes,2,opt,name=chain_id,json=chainId,proto3" json:"chain_id,omitempty"`
+	// sign_bytes are the bytes to sign, including the chain ID.
+	SignBytes []byte `protobuf:"bytes,3,opt,name=sign_bytes,json=signBytes,proto3" json:"sign_bytes,omitempty"`
+	// request_id identifies the request, retries included, so the signer can
+	// reply to a retry without signing again. If set, the signer may send
+	// SigningInProgress messages while signing.
+	RequestId string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// pub_key_address is the address of the key to sign with, among pub_keys.
+	// If empty, the signer signs with its pub_key.
+	PubKeyAddress []byte `protobuf:"bytes,5,opt,name=pub_key_address,json=pubKeyAddress,proto3" json:"pub_key_address,omitempty"`
 }
 
 func (m *SignProposalRequest) Reset()         { *m = SignProposalRequest{} }
@@ -371,6 +420,27 @@ func (m *SignProposalRequest) GetChainId() string {
 	return ""
 }
 
+func (m *SignProposalRequest) GetSignBytes() []byte {
+	if m != nil {
+		return m.SignBytes
+	}
+	return nil
+}
+
+func (m *SignProposalRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *SignProposalRequest) GetPubKeyAddress() []byte {
+	if m != nil {
+		return m.PubKeyAddress
+	}
+	return nil
+}
+
 // SignedProposalResponse is response containing a signed proposal or an error
 type SignedProposalResponse struct {
 	Proposal types.Proposal     `protobuf:"bytes,1,opt,name=proposal,proto3" json:"proposal"`
@@ -498,6 +568,53 @@ func (m *PingResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PingResponse proto.InternalMessageInfo
 
+// SigningInProgress is sent by the signer, before the response, to tell that
+// it's still signing the request with the given request_id, e.g. during a
+// threshold signing ceremony.
+type SigningInProgress struct {
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+}
+
+func (m *SigningInProgress) Reset()         { *m = SigningInProgress{} }
+func (m *SigningInProgress) String() string { return proto.CompactTextString(m) }
+func (*SigningInProgress) ProtoMessage()    {}
+func (*SigningInProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_cb4e437a5328cf9c, []int{9}
+}
+func (m *SigningInProgress) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SigningInProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SigningInProgress.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SigningInProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SigningInProgress.Merge(m, src)
+}
+func (m *SigningInProgress) XXX_Size() int {
+	return m.Size()
+}
+func (m *SigningInProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_SigningInProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SigningInProgress proto.InternalMessageInfo
+
+func (m *SigningInProgress) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
 type Message struct {
 	// Types that are valid to be assigned to Sum:
 	//	*Message_PubKeyRequest
@@ -508,6 +625,7 @@ type Message struct {
 	//	*Message_SignedProposalResponse
 	//	*Message_PingRequest
 	//	*Message_PingResponse
+	//	*Message_SigningInProgress
 	Sum isMessage_Sum `protobuf_oneof:"sum"`
 }
 
@@ -515,7 +633,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_cb4e437a5328cf9c, []int{9}
+	return fileDescriptor_cb4e437a5328cf9c, []int{10}
 }
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -574,6 +692,9 @@ type Message_PingRequest struct {
 type Message_PingResponse struct {
 	PingResponse *PingResponse `protobuf:"bytes,8,opt,name=ping_response,json=pingResponse,proto3,oneof" json:"ping_response,omitempty"`
 }
+type Message_SigningInProgress struct {
+	SigningInProgress *SigningInProgress `protobuf:"bytes,9,opt,name=signing_in_progress,json=signingInProgress,proto3,oneof" json:"signing_in_progress,omitempty"`
+}
 
 func (*Message_PubKeyRequest) isMessage_Sum()          {}
 func (*Message_PubKeyResponse) isMessage_Sum()         {}
@@ -583,6 +704,7 @@ func (*Message_SignProposalRequest) isMessage_Sum()    {}
 func (*Message_SignedProposalResponse) isMessage_Sum() {}
 func (*Message_PingRequest) isMessage_Sum()            {}
 func (*Message_PingResponse) isMessage_Sum()           {}
+func (*Message_SigningInProgress) isMessage_Sum()      {}
 
 func (m *Message) GetSum() isMessage_Sum {
 	if m != nil {
@@ -647,6 +769,13 @@ func (m *Message) GetPingResponse() *PingResponse {
 	return nil
 }
 
+func (m *Message) GetSigningInProgress() *SigningInProgress {
+	if x, ok := m.GetSum().(*Message_SigningInProgress); ok {
+		return x.SigningInProgress
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Message) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -658,6 +787,7 @@ func (*Message) XXX_OneofWrappers() []interface{} {
 		(*Message_SignedProposalResponse)(nil),
 		(*Message_PingRequest)(nil),
 		(*Message_PingResponse)(nil),
+		(*Message_SigningInProgress)(nil),
 	}
 }
 
@@ -672,60 +802,69 @@ func init() {
 	proto.RegisterType((*SignedProposalResponse)(nil), "tendermint.privval.SignedProposalResponse")
 	proto.RegisterType((*PingRequest)(nil), "tendermint.privval.PingRequest")
 	proto.RegisterType((*PingResponse)(nil), "tendermint.privval.PingResponse")
+	proto.RegisterType((*SigningInProgress)(nil), "tendermint.privval.SigningInProgress")
 	proto.RegisterType((*Message)(nil), "tendermint.privval.Message")
 }
 
 func init() { proto.RegisterFile("tendermint/privval/types.proto", fileDescriptor_cb4e437a5328cf9c) }
 
 var fileDescriptor_cb4e437a5328cf9c = []byte{
-	// 750 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0x4d, 0x4f, 0x13, 0x41,
-	0x18, 0xde, 0x85, 0x7e, 0xc0, 0x5b, 0x5a, 0xca, 0x80, 0x58, 0x1a, 0x5c, 0x6a, 0x8d, 0x4a, 0x7a,
-	0x68, 0x0d, 0x26, 0x26, 0x06, 0x2f, 0x02, 0x1b, 0xdb, 0x34, 0x6c, 0xeb, 0xb4, 0x08, 0x21, 0x31,
-	0x9b, 0x7e, 0x8c, 0xcb, 0x06, 0xba, 0x3b, 0xee, 0x6c, 0x49, 0x7a, 0xf6, 0xe6, 0xc9, 0xc4, 0x3f,
-	0xe1, 0xd9, 0x5f, 0xc1, 0x91, 0xa3, 0x27, 0x63, 0xe0, 0x8f, 0x98, 0xce, 0x4e, 0xb7, 0xdb, 0x2f,
-	0xa2, 0xe1, 0xb6, 0xf3, 0xbe, 0xef, 0x3c, 0x1f, 0x33, 0xcf, 0x66, 0x40, 0x71, 0x89, 0xd5, 0x26,
-	0x4e, 0xc7, 0xb4, 0xdc, 0x02, 0x75, 0xcc, 0xcb, 0xcb, 0xc6, 0x45, 0xc1, 0xed, 0x51, 0xc2, 0xf2,
-	0xd4, 0xb1, 0x5d, 0x1b, 0xa1, 0x61, 0x3f, 0x2f, 0xfa, 0xe9, 0xcd, 0xc0, 0x9e, 0x96, 0xd3, 0xa3,
-	0xae, 0x5d, 0x38, 0x27, 0x3d, 0xb1, 0x63, 0xa4, 0xcb, 0x91, 0x82, 0x78, 0xe9, 0x35, 0xc3, 0x36,
-	0x6c, 0xfe, 0x59, 0xe8, 0x7f, 0x79, 0xd5, 0x6c, 0x09, 0x56, 0x30, 0xe9, 0xd8, 0x2e, 0xa9, 0x99,
-	0x86, 0x45, 0x1c, 0xd5, 0x71, 0x6c, 0x07, 0x21, 0x08, 0xb5, 0xec, 0x36, 0x49, 0xc9, 0x19, 0x79,
-	0x3b, 0x8c, 0xf9, 0x37, 0xca, 0x40, 0xac, 0x4d, 0x58, 0xcb, 0x31, 0xa9, 0x6b, 0xda, 0x56, 0x6a,
-	0x2e, 0x23, 0x6f, 0x2f, 0xe2, 0x60, 0x29, 0x9b, 0x83, 0x78, 0xb5, 0xdb, 0x2c, 0x93, 0x1e, 0x26,
-	0x9f, 0xbb, 0x84, 0xb9, 0x68, 0x03, 0x16, 0x5a, 0x67, 0x0d, 0xd3, 0xd2, 0xcd, 0x36, 0x87, 0x5a,
-	0xc4, 0x51, 0xbe, 0x2e, 0xb5, 0xb3, 0x5f, 0x65, 0x48, 0x0c, 0x86, 0x19, 0xb5, 0x2d, 0x46, 0xd0,
-	0x2e, 0x44, 0x69, 0xb7, 0xa9, 0x9f, 0x93, 0x1e, 0x1f, 0x8e, 0xed, 0x6c, 0xe6, 0x03, 0x27, 0xe0,
-	0xb9, 0xcd, 0x57, 0xbb, 0xcd, 0x0b, 0xb3, 0x55, 0x26, 0xbd, 0xbd, 0xd0, 0xd5, 0xef, 0x2d, 0x09,
-	0x47, 0x28, 0x07, 0x41, 0xbb, 0x10, 0x26, 0x7d, 0xe9, 0x5c, 0x57, 0x6c, 0xe7, 0x69, 0x7e, 0xf2,
-	0xf0, 0xf2, 0x13, 0x3e, 0xb1, 0xb7, 0x27, 0x7b, 0x02, 0xcb, 0xfd, 0xea, 0x07, 0xdb, 0x25, 0x03,
-	0xe9, 0x39, 0x08, 0x5d, 0xda, 0x2e, 0x11, 0x4a, 0xd6, 0x83, 0x70, 0xde, 0x99, 0xf2, 0x61, 0x3e,
-	0x33, 0x62, 0x73, 0x6e, 0xd4, 0xe6, 0x17, 0x19, 0x10, 0x27, 0x6c, 0x7b, 0xe0, 0xc2, 0xea, 0x8b,
-	0x7f, 0x41, 0x17, 0x0e, 0x3d, 0x8e, 0x7b, 0xf9, 0x3b, 0x83, 0xd5, 0x7e, 0xb5, 0xea, 0xd8, 0xd4,
-	0x66, 0x8d, 0x8b, 0x81, 0xc7, 0x57, 0xb0, 0x40, 0x45, 0x49, 0x28, 0x49, 0x4f, 0x2a, 0xf1, 0x37,
-	0xf9, 0xb3, 0x77, 0xf9, 0xfd, 0x2e, 0xc3, 0xba, 0xe7, 0x77, 0x48, 0x26, 0x3c, 0xbf, 0xf9, 0x1f,
-	0x36, 0xe1, 0x7d, 0xc8, 0x79, 0x2f, 0xff, 0x71, 0x88, 0x55, 0x4d, 0xcb, 0x10, 0xbe, 0xb3, 0x09,
-	0x58, 0xf2, 0x96, 0x9e, 0xb2, 0xec, 0xcf, 0x30, 0x44, 0x0f, 0x09, 0x63, 0x0d, 0x83, 0xa0, 0x32,
-	0x2c, 0x8b, 0x10, 0xea, 0x8e, 0x37, 0x2e, 0xc4, 0x3e, 0x9e, 0xc6, 0x38, 0x12, 0xf7, 0xa2, 0x84,
-	0xe3, 0x74, 0x24, 0xff, 0x1a, 0x24, 0x87, 0x60, 0x1e, 0x99, 0xd0, 0x9f, 0xbd, 0x0b, 0xcd, 0x9b,
-	0x2c, 0x4a, 0x38, 0x41, 0x47, 0xff, 0x90, 0xf7, 0xb0, 0xc2, 0x4c, 0xc3, 0xd2, 0xfb, 0x89, 0xf0,
-	0xe5, 0xcd, 0x73, 0xc0, 0x27, 0xd3, 0x00, 0xc7, 0x42, 0x5d, 0x94, 0xf0, 0x32, 0x1b, 0xcb, 0xf9,
-	0x29, 0xac, 0x31, 0x7e, 0x5f, 0x03, 0x50, 0x21, 0x33, 0xc4, 0x51, 0x9f, 0xcd, 0x42, 0x1d, 0xcd,
-	0x73, 0x51, 0xc2, 0x88, 0x4d, 0xa6, 0xfc, 0x23, 0x3c, 0xe0, 0x72, 0x07, 0x97, 0xe8, 0x4b, 0x0e,
-	0x73, 0xf0, 0xe7, 0xb3, 0xc0, 0xc7, 0x72, 0x5a, 0x94, 0xf0, 0x2a, 0x9b, 0x12, 0xdf, 0x4f, 0x90,
-	0x12, 0xd2, 0x03, 0x04, 0x42, 0x7e, 0x84, 0x33, 0xe4, 0x66, 0xcb, 0x1f, 0x8f, 0x67, 0x51, 0xc2,
-	0xeb, 0x6c, 0x7a, 0x70, 0x0f, 0x60, 0x89, 0x9a, 0x96, 0xe1, 0xab, 0x8f, 0x72, 0xec, 0xad, 0xa9,
-	0x37, 0x38, 0x4c, 0x59, 0x51, 0xc2, 0x31, 0x3a, 0x5c, 0xa2, 0x77, 0x10, 0x17, 0x28, 0x42, 0xe2,
-	0x02, 0x87, 0xc9, 0xcc, 0x86, 0xf1, 0x85, 0x2d, 0xd1, 0xc0, 0x7a, 0x2f, 0x0c, 0xf3, 0xac, 0xdb,
-	0xc9, 0xfd, 0x90, 0x21, 0xc2, 0x43, 0xce, 0x10, 0x82, 0x84, 0x8a, 0x71, 0x05, 0xd7, 0xf4, 0x23,
-	0xad, 0xac, 0x55, 0x8e, 0xb5, 0xa4, 0x84, 0x14, 0x48, 0xfb, 0x35, 0xf5, 0xa4, 0xaa, 0xee, 0xd7,
-	0xd5, 0x03, 0x1d, 0xab, 0xb5, 0x6a, 0x45, 0xab, 0xa9, 0x49, 0x19, 0xa5, 0x60, 0x4d, 0xf4, 0xb5,
-	0x8a, 0xbe, 0x5f, 0xd1, 0x34, 0x75, 0xbf, 0x5e, 0xaa, 0x68, 0xc9, 0x39, 0xf4, 0x08, 0x36, 0x44,
-	0x67, 0x58, 0xd6, 0xeb, 0xa5, 0x43, 0xb5, 0x72, 0x54, 0x4f, 0xce, 0xa3, 0x87, 0xb0, 0x2a, 0xda,
-	0x58, 0x7d, 0x7b, 0xe0, 0x37, 0x42, 0x01, 0xc4, 0x63, 0x5c, 0xaa, 0xab, 0x7e, 0x27, 0xbc, 0x57,
-	0xbb, 0xba, 0x51, 0xe4, 0xeb, 0x1b, 0x45, 0xfe, 0x73, 0xa3, 0xc8, 0xdf, 0x6e, 0x15, 0xe9, 0xfa,
-	0x56, 0x91, 0x7e, 0xdd, 0x2a, 0xd2, 0xe9, 0x6b, 0xc3, 0x74, 0xcf, 0xba, 0xcd, 0x7c, 0xcb, 0xee,
-	0x14, 0x82, 0x6f, 0x57, 0xf0, 0x61, 0xec, 0xbf, 0x57, 0x93, 0x2f, 0x65, 0x33, 0xc2, 0x3b, 0x2f,
-	0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0xa5, 0x2a, 0xe5, 0x4a, 0x46, 0x07, 0x00, 0x00,
+	// 869 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xcf, 0x6f, 0xe3, 0x44,
+	0x14, 0xb6, 0x9b, 0x5f, 0xcd, 0x4b, 0x9b, 0x26, 0xd3, 0x52, 0xb2, 0xd1, 0xae, 0x37, 0x18, 0xb1,
+	0x54, 0x39, 0x24, 0xa8, 0x48, 0x48, 0x68, 0xe1, 0xb0, 0x69, 0x2d, 0x1c, 0x45, 0xeb, 0x84, 0x49,
+	0x96, 0xa2, 0x95, 0x90, 0x95, 0xc4, 0x83, 0xd7, 0xda, 0xc6, 0x36, 0x1e, 0xa7, 0x52, 0xce, 0x1c,
+	0xb9, 0x20, 0xf1, 0x4f, 0xf0, 0x57, 0x70, 0x5e, 0x2e, 0x68, 0x8f, 0x9c, 0x10, 0x6a, 0xff, 0x11,
+	0xe4, 0x99, 0x89, 0xed, 0xfc, 0x42, 0x8b, 0x7a, 0xe0, 0x36, 0xf3, 0xde, 0xbc, 0x6f, 0xbe, 0xef,
+	0x7b, 0xf3, 0x2c, 0x83, 0x12, 0x12, 0xd7, 0x22, 0xc1, 0xcc, 0x71, 0xc3, 0xb6, 0x1f, 0x38, 0x37,
+	0x37, 0xe3, 0xeb, 0x76, 0xb8, 0xf0, 0x09, 0x6d, 0xf9, 0x81, 0x17, 0x7a, 0x08, 0x25, 0xf9, 0x96,
+	0xc8, 0xd7, 0x1f, 0xa6, 0x6a, 0xa6, 0xc1, 0xc2, 0x0f, 0xbd, 0xf6, 0x6b, 0xb2, 0x10, 0x15, 0x2b,
+	0x59, 0x86, 0x94, 0xc6, 0xab, 0x9f, 0xd8, 0x9e, 0xed, 0xb1, 0x65, 0x3b, 0x5a, 0xf1, 0xa8, 0xda,
+	0x85, 0x2a, 0x26, 0x33, 0x2f, 0x24, 0x43, 0xc7, 0x76, 0x49, 0xa0, 0x05, 0x81, 0x17, 0x20, 0x04,
+	0xd9, 0xa9, 0x67, 0x91, 0x9a, 0xdc, 0x90, 0xcf, 0x72, 0x98, 0xad, 0x51, 0x03, 0x4a, 0x16, 0xa1,
+	0xd3, 0xc0, 0xf1, 0x43, 0xc7, 0x73, 0x6b, 0x7b, 0x0d, 0xf9, 0xac, 0x88, 0xd3, 0x21, 0xb5, 0x09,
+	0x87, 0x83, 0xf9, 0xa4, 0x47, 0x16, 0x98, 0xfc, 0x30, 0x27, 0x34, 0x44, 0x0f, 0x60, 0x7f, 0xfa,
+	0x6a, 0xec, 0xb8, 0xa6, 0x63, 0x31, 0xa8, 0x22, 0x2e, 0xb0, 0x7d, 0xd7, 0x52, 0x7f, 0x97, 0xa1,
+	0xbc, 0x3c, 0x4c, 0x7d, 0xcf, 0xa5, 0x04, 0x3d, 0x85, 0x82, 0x3f, 0x9f, 0x98, 0xaf, 0xc9, 0x82,
+	0x1d, 0x2e, 0x9d, 0x3f, 0x6c, 0xa5, 0x1c, 0xe0, 0x6a, 0x5b, 0x83, 0xf9, 0xe4, 0xda, 0x99, 0xf6,
+	0xc8, 0xa2, 0x93, 0x7d, 0xf3, 0xd7, 0x63, 0x09, 0xe7, 0x7d, 0x06, 0x82, 0x9e, 0x42, 0x8e, 0x44,
+	0xd4, 0x19, 0xaf, 0xd2, 0xf9, 0x47, 0xad, 0x4d, 0xf3, 0x5a, 0x1b, 0x3a, 0x31, 0xaf, 0x41, 0x5f,
+	0xc2, 0xbe, 0xb8, 0x99, 0xd6, 0x32, 0x8d, 0xcc, 0x3b, 0x5e, 0x5d, 0xe0, 0x57, 0x53, 0xf5, 0x37,
+	0x19, 0x8e, 0x22, 0xd4, 0x6f, 0xbc, 0x90, 0x2c, 0xa5, 0x37, 0x21, 0x7b, 0xe3, 0x85, 0x44, 0x28,
+	0x39, 0x4d, 0xc3, 0xf1, 0x9e, 0xb0, 0xc3, 0xec, 0xcc, 0x8a, 0x4d, 0x7b, 0x2b, 0x36, 0xa1, 0x47,
+	0x00, 0xd4, 0xb1, 0x5d, 0x73, 0xb2, 0x08, 0x49, 0xc4, 0x4d, 0x3e, 0x3b, 0xc0, 0xc5, 0x28, 0xd2,
+	0x89, 0x02, 0x51, 0x3a, 0xe0, 0x17, 0x46, 0xb5, 0x59, 0x56, 0x5b, 0x14, 0x91, 0xae, 0x85, 0x9e,
+	0xc0, 0x91, 0xd0, 0x65, 0x8e, 0x2d, 0x2b, 0x20, 0x94, 0xd6, 0x72, 0x0c, 0xe2, 0x90, 0x53, 0x7f,
+	0xc6, 0x83, 0xea, 0x8f, 0x32, 0x20, 0x66, 0x8b, 0xc5, 0x25, 0x88, 0x86, 0x7c, 0xf2, 0x2e, 0x1a,
+	0x84, 0x19, 0x5c, 0xc9, 0x7d, 0xba, 0xa0, 0xfe, 0x21, 0xc3, 0x71, 0x14, 0x1e, 0x04, 0x9e, 0xef,
+	0xd1, 0xf1, 0xf5, 0xd2, 0xca, 0xcf, 0x60, 0xdf, 0x17, 0x21, 0x41, 0xa5, 0xbe, 0x49, 0x25, 0x2e,
+	0x8a, 0xcf, 0xfe, 0xff, 0xb6, 0xfe, 0x22, 0xc3, 0x29, 0xb7, 0x35, 0x91, 0x24, 0xac, 0xfd, 0xe2,
+	0xbf, 0x68, 0x12, 0x16, 0x27, 0xca, 0xee, 0x65, 0xf3, 0x21, 0x94, 0x06, 0x8e, 0x6b, 0x0b, 0x77,
+	0xd5, 0x32, 0x1c, 0xf0, 0x2d, 0x67, 0xa6, 0x9e, 0x43, 0x35, 0x2a, 0x72, 0x5c, 0xbb, 0x1b, 0x75,
+	0xc2, 0x8e, 0x94, 0xac, 0x19, 0x22, 0xaf, 0x19, 0xa2, 0xfe, 0x94, 0x87, 0xc2, 0x73, 0x42, 0xe9,
+	0xd8, 0x26, 0xa8, 0x97, 0x98, 0x23, 0x0e, 0x08, 0x81, 0x1f, 0x6c, 0x63, 0xb9, 0xf2, 0xbd, 0xd0,
+	0xa5, 0xa5, 0x83, 0xcb, 0xd6, 0x1b, 0x50, 0x49, 0xc0, 0x38, 0x41, 0xa1, 0x59, 0xfd, 0x37, 0x34,
+	0x7e, 0x52, 0x97, 0x70, 0xd9, 0x5f, 0x89, 0xa0, 0xaf, 0xa1, 0xca, 0xfa, 0x1e, 0x3d, 0xd6, 0x98,
+	0x5e, 0x86, 0x01, 0x7e, 0xb8, 0x0d, 0x70, 0x6d, 0xaa, 0x75, 0x09, 0x1f, 0xd1, 0xb5, 0x41, 0x7f,
+	0x09, 0x27, 0x94, 0xf5, 0x78, 0x09, 0x2a, 0x68, 0x66, 0x19, 0xea, 0x93, 0x5d, 0xa8, 0xab, 0xa3,
+	0xa6, 0x4b, 0x18, 0xd1, 0xcd, 0x01, 0xfc, 0x0e, 0xde, 0x63, 0x74, 0x97, 0x8d, 0x8f, 0x29, 0xe7,
+	0x18, 0xf8, 0xc7, 0xbb, 0xc0, 0xd7, 0x26, 0x48, 0x97, 0xf0, 0x31, 0xdd, 0x0c, 0xa3, 0xef, 0xa1,
+	0x26, 0xa8, 0xa7, 0x2e, 0x10, 0xf4, 0xf3, 0xec, 0x86, 0xe6, 0x6e, 0xfa, 0xeb, 0x4f, 0x5a, 0x97,
+	0xf0, 0x29, 0xdd, 0xfe, 0xd8, 0x2f, 0xe1, 0xc0, 0x77, 0x5c, 0x3b, 0x66, 0x5f, 0x60, 0xd8, 0x8f,
+	0xb7, 0x76, 0x30, 0x79, 0x99, 0xba, 0x84, 0x4b, 0x7e, 0xb2, 0x45, 0x5f, 0xc1, 0xa1, 0x40, 0x11,
+	0x14, 0xf7, 0x19, 0x4c, 0x63, 0x37, 0x4c, 0x4c, 0xec, 0xc0, 0x4f, 0xed, 0xd1, 0x15, 0x30, 0x37,
+	0x22, 0x2c, 0x87, 0x79, 0xcb, 0xde, 0x78, 0xad, 0xb8, 0x7b, 0x96, 0x36, 0x06, 0x42, 0x97, 0x70,
+	0x95, 0xae, 0x07, 0x3b, 0x39, 0xc8, 0xd0, 0xf9, 0xac, 0xf9, 0xab, 0x0c, 0x79, 0x36, 0x71, 0x14,
+	0x21, 0x28, 0x6b, 0x18, 0xf7, 0xf1, 0xd0, 0x7c, 0x61, 0xf4, 0x8c, 0xfe, 0x95, 0x51, 0x91, 0x90,
+	0x02, 0xf5, 0x38, 0xa6, 0x7d, 0x3b, 0xd0, 0x2e, 0x46, 0xda, 0xa5, 0x89, 0xb5, 0xe1, 0xa0, 0x6f,
+	0x0c, 0xb5, 0x8a, 0x8c, 0x6a, 0x70, 0x22, 0xf2, 0x46, 0xdf, 0xbc, 0xe8, 0x1b, 0x86, 0x76, 0x31,
+	0xea, 0xf6, 0x8d, 0xca, 0x1e, 0x7a, 0x04, 0x0f, 0x44, 0x26, 0x09, 0x9b, 0xa3, 0xee, 0x73, 0xad,
+	0xff, 0x62, 0x54, 0xc9, 0xa0, 0xf7, 0xe1, 0x58, 0xa4, 0xb1, 0xf6, 0xec, 0x32, 0x4e, 0x64, 0x53,
+	0x88, 0x57, 0xb8, 0x3b, 0xd2, 0xe2, 0x4c, 0xae, 0x33, 0x7c, 0x73, 0xab, 0xc8, 0x6f, 0x6f, 0x15,
+	0xf9, 0xef, 0x5b, 0x45, 0xfe, 0xf9, 0x4e, 0x91, 0xde, 0xde, 0x29, 0xd2, 0x9f, 0x77, 0x8a, 0xf4,
+	0xf2, 0x73, 0xdb, 0x09, 0x5f, 0xcd, 0x27, 0xad, 0xa9, 0x37, 0x6b, 0xa7, 0xff, 0x2a, 0x92, 0x25,
+	0xff, 0x93, 0xd8, 0xfc, 0x87, 0x99, 0xe4, 0x59, 0xe6, 0xd3, 0x7f, 0x06, 0x00, 0xea, 0x00, 0xae,
+	0x19, 0xe0, 0x08, 0x00, 0x00,
 }
 
 func (m *RemoteSignerError) Marshal() (dAtA []byte, err error) {
@@ -813,6 +952,20 @@ func (m *PubKeyResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.PubKeys) > 0 {
+		for iNdEx := len(m.PubKeys) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.PubKeys[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTypes(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if m.Error != nil {
 		{
 			size, err := m.Error.MarshalToSizedBuffer(dAtA[:i])
@@ -858,6 +1011,27 @@ func (m *SignVoteRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.PubKeyAddress) > 0 {
+		i -= len(m.PubKeyAddress)
+		copy(dAtA[i:], m.PubKeyAddress)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.PubKeyAddress)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.RequestId) > 0 {
+		i -= len(m.RequestId)
+		copy(dAtA[i:], m.RequestId)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.RequestId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.SignBytes) > 0 {
+		i -= len(m.SignBytes)
+		copy(dAtA[i:], m.SignBytes)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.SignBytes)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.ChainId) > 0 {
 		i -= len(m.ChainId)
 		copy(dAtA[i:], m.ChainId)
@@ -945,6 +1119,27 @@ func (m *SignProposalRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.PubKeyAddress) > 0 {
+		i -= len(m.PubKeyAddress)
+		copy(dAtA[i:], m.PubKeyAddress)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.PubKeyAddress)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.RequestId) > 0 {
+		i -= len(m.RequestId)
+		copy(dAtA[i:], m.RequestId)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.RequestId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.SignBytes) > 0 {
+		i -= len(m.SignBytes)
+		copy(dAtA[i:], m.SignBytes)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.SignBytes)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.ChainId) > 0 {
 		i -= len(m.ChainId)
 		copy(dAtA[i:], m.ChainId)
@@ -1058,6 +1253,36 @@ func (m *PingResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *SigningInProgress) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SigningInProgress) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SigningInProgress) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.RequestId) > 0 {
+		i -= len(m.RequestId)
+		copy(dAtA[i:], m.RequestId)
+		i = encodeVarintTypes(dAtA, i, uint64(len(m.RequestId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *Message) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -1258,6 +1483,27 @@ func (m *Message_PingResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	}
 	return len(dAtA) - i, nil
 }
+func (m *Message_SigningInProgress) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Message_SigningInProgress) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.SigningInProgress != nil {
+		{
+			size, err := m.SigningInProgress.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintTypes(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	return len(dAtA) - i, nil
+}
 func encodeVarintTypes(dAtA []byte, offset int, v uint64) int {
 	offset -= sovTypes(v)
 	base := offset
@@ -1310,6 +1556,12 @@ func (m *PubKeyResponse) Size() (n int) {
 		l = m.Error.Size()
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if len(m.PubKeys) > 0 {
+		for _, e := range m.PubKeys {
+			l = e.Size()
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -1327,6 +1579,18 @@ func (m *SignVoteRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	l = len(m.SignBytes)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.RequestId)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.PubKeyAddress)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -1359,6 +1623,18 @@ func (m *SignProposalRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	l = len(m.SignBytes)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.RequestId)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	l = len(m.PubKeyAddress)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
 	return n
 }
 
@@ -1395,6 +1671,19 @@ func (m *PingResponse) Size() (n int) {
 	return n
 }
 
+func (m *SigningInProgress) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.RequestId)
+	if l > 0 {
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
+
 func (m *Message) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1503,6 +1792,18 @@ func (m *Message_PingResponse) Size() (n int) {
 	}
 	return n
 }
+func (m *Message_SigningInProgress) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SigningInProgress != nil {
+		l = m.SigningInProgress.Size()
+		n += 1 + l + sovTypes(uint64(l))
+	}
+	return n
+}
 
 func sovTypes(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
@@ -1791,6 +2092,40 @@ func (m *PubKeyResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKeys", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PubKeys = append(m.PubKeys, crypto.PublicKey{})
+			if err := m.PubKeys[len(m.PubKeys)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1909,6 +2244,106 @@ func (m *SignVoteRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.ChainId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignBytes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SignBytes = append(m.SignBytes[:0], dAtA[iNdEx:postIndex]...)
+			if m.SignBytes == nil {
+				m.SignBytes = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKeyAddress", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PubKeyAddress = append(m.PubKeyAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.PubKeyAddress == nil {
+				m.PubKeyAddress = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -2146,6 +2581,106 @@ func (m *SignProposalRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.ChainId = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SignBytes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SignBytes = append(m.SignBytes[:0], dAtA[iNdEx:postIndex]...)
+			if m.SignBytes == nil {
+				m.SignBytes = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PubKeyAddress", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PubKeyAddress = append(m.PubKeyAddress[:0], dAtA[iNdEx:postIndex]...)
+			if m.PubKeyAddress == nil {
+				m.PubKeyAddress = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -2386,6 +2921,88 @@ func (m *PingResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *SigningInProgress) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTypes
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SigningInProgress: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SigningInProgress: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTypes(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *Message) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -2695,6 +3312,41 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			}
 			m.Sum = &Message_PingResponse{v}
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SigningInProgress", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &SigningInProgress{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Message_SigningInProgress{v}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
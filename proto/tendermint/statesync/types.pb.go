@@ -24,7 +24,6 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 type Message struct {
 	// Types that are valid to be assigned to Sum:
-	//
 	//	*Message_SnapshotsRequest
 	//	*Message_SnapshotsResponse
 	//	*Message_ChunkRequest
@@ -135,6 +134,8 @@ func (*Message) XXX_OneofWrappers() []interface{} {
 }
 
 type SnapshotsRequest struct {
+	// the snapshot formats the requester can apply, or empty for any format
+	Formats []uint32 `protobuf:"varint,1,rep,packed,name=formats,proto3" json:"formats,omitempty"`
 }
 
 func (m *SnapshotsRequest) Reset()         { *m = SnapshotsRequest{} }
@@ -170,12 +171,23 @@ func (m *SnapshotsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SnapshotsRequest proto.InternalMessageInfo
 
+func (m *SnapshotsRequest) GetFormats() []uint32 {
+	if m != nil {
+		return m.Formats
+	}
+	return nil
+}
+
 type SnapshotsResponse struct {
 	Height   uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
 	Format   uint32 `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
 	Chunks   uint32 `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks,omitempty"`
 	Hash     []byte `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`
 	Metadata []byte `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// whether the peer can send the chunks of the snapshot snappy-compressed
+	CompressedChunks bool `protobuf:"varint,6,opt,name=compressed_chunks,json=compressedChunks,proto3" json:"compressed_chunks,omitempty"`
+	// the SHA-256 hashes of the chunks of the snapshot, if provided by the app
+	ChunkHashes [][]byte `protobuf:"bytes,7,rep,name=chunk_hashes,json=chunkHashes,proto3" json:"chunk_hashes,omitempty"`
 }
 
 func (m *SnapshotsResponse) Reset()         { *m = SnapshotsResponse{} }
@@ -246,10 +258,26 @@ func (m *SnapshotsResponse) GetMetadata() []byte {
 	return nil
 }
 
+func (m *SnapshotsResponse) GetCompressedChunks() bool {
+	if m != nil {
+		return m.CompressedChunks
+	}
+	return false
+}
+
+func (m *SnapshotsResponse) GetChunkHashes() [][]byte {
+	if m != nil {
+		return m.ChunkHashes
+	}
+	return nil
+}
+
 type ChunkRequest struct {
 	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
 	Format uint32 `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
 	Index  uint32 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	// whether the chunk should be sent snappy-compressed
+	Compressed bool `protobuf:"varint,4,opt,name=compressed,proto3" json:"compressed,omitempty"`
 }
 
 func (m *ChunkRequest) Reset()         { *m = ChunkRequest{} }
@@ -306,12 +334,21 @@ func (m *ChunkRequest) GetIndex() uint32 {
 	return 0
 }
 
+func (m *ChunkRequest) GetCompressed() bool {
+	if m != nil {
+		return m.Compressed
+	}
+	return false
+}
+
 type ChunkResponse struct {
 	Height  uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
 	Format  uint32 `protobuf:"varint,2,opt,name=format,proto3" json:"format,omitempty"`
 	Index   uint32 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
 	Chunk   []byte `protobuf:"bytes,4,opt,name=chunk,proto3" json:"chunk,omitempty"`
 	Missing bool   `protobuf:"varint,5,opt,name=missing,proto3" json:"missing,omitempty"`
+	// whether the chunk is snappy-compressed
+	Compressed bool `protobuf:"varint,6,opt,name=compressed,proto3" json:"compressed,omitempty"`
 }
 
 func (m *ChunkResponse) Reset()         { *m = ChunkResponse{} }
@@ -382,6 +419,13 @@ func (m *ChunkResponse) GetMissing() bool {
 	return false
 }
 
+func (m *ChunkResponse) GetCompressed() bool {
+	if m != nil {
+		return m.Compressed
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "tendermint.statesync.Message")
 	proto.RegisterType((*SnapshotsRequest)(nil), "tendermint.statesync.SnapshotsRequest")
@@ -393,32 +437,36 @@ func init() {
 func init() { proto.RegisterFile("tendermint/statesync/types.proto", fileDescriptor_a1c2869546ca7914) }
 
 var fileDescriptor_a1c2869546ca7914 = []byte{
-	// 393 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0xcd, 0x6a, 0xdb, 0x40,
-	0x18, 0x94, 0xfc, 0xcf, 0x57, 0xab, 0xd8, 0x8b, 0x29, 0xa2, 0x07, 0x61, 0x54, 0x68, 0x7b, 0x92,
-	0xa0, 0x3d, 0xf6, 0xe6, 0x5e, 0x5c, 0x68, 0x2f, 0xdb, 0x18, 0x42, 0x2e, 0x61, 0x2d, 0x6f, 0x24,
-	0x11, 0xb4, 0x52, 0xf4, 0xad, 0x20, 0x7e, 0x80, 0x9c, 0x72, 0xc9, 0x63, 0xe5, 0xe8, 0x63, 0xc8,
-	0x29, 0xd8, 0x2f, 0x12, 0xb4, 0x92, 0x65, 0xc5, 0x31, 0x09, 0x81, 0xdc, 0x76, 0xc6, 0xe3, 0xd1,
-	0xcc, 0xc0, 0x07, 0x63, 0xc9, 0xc5, 0x82, 0xa7, 0x51, 0x28, 0xa4, 0x8b, 0x92, 0x49, 0x8e, 0x4b,
-	0xe1, 0xb9, 0x72, 0x99, 0x70, 0x74, 0x92, 0x34, 0x96, 0x31, 0x19, 0xed, 0x14, 0x4e, 0xa5, 0xb0,
-	0xef, 0x1b, 0xd0, 0xfd, 0xc7, 0x11, 0x99, 0xcf, 0xc9, 0x0c, 0x86, 0x28, 0x58, 0x82, 0x41, 0x2c,
-	0xf1, 0x34, 0xe5, 0x17, 0x19, 0x47, 0x69, 0xea, 0x63, 0xfd, 0xfb, 0x87, 0x1f, 0x5f, 0x9d, 0x43,
-	0xff, 0x76, 0xfe, 0x6f, 0xe5, 0xb4, 0x50, 0x4f, 0x35, 0x3a, 0xc0, 0x3d, 0x8e, 0x1c, 0x03, 0xa9,
-	0xdb, 0x62, 0x12, 0x0b, 0xe4, 0x66, 0x43, 0xf9, 0x7e, 0x7b, 0xd5, 0xb7, 0x90, 0x4f, 0x35, 0x3a,
-	0xc4, 0x7d, 0x92, 0xfc, 0x01, 0xc3, 0x0b, 0x32, 0x71, 0x5e, 0x85, 0x6d, 0x2a, 0x53, 0xfb, 0xb0,
-	0xe9, 0xef, 0x5c, 0xba, 0x0b, 0xda, 0xf7, 0x6a, 0x98, 0xfc, 0x85, 0x8f, 0x5b, 0xab, 0x32, 0x60,
-	0x4b, 0x79, 0x7d, 0x79, 0xd1, 0xab, 0x0a, 0x67, 0x78, 0x75, 0x62, 0xd2, 0x86, 0x26, 0x66, 0x91,
-	0x4d, 0x60, 0xb0, 0xbf, 0x90, 0x7d, 0xad, 0xc3, 0xf0, 0x59, 0x3d, 0xf2, 0x09, 0x3a, 0x01, 0x0f,
-	0xfd, 0xa0, 0xd8, 0xbb, 0x45, 0x4b, 0x94, 0xf3, 0x67, 0x71, 0x1a, 0x31, 0xa9, 0xf6, 0x32, 0x68,
-	0x89, 0x72, 0x5e, 0x7d, 0x11, 0x55, 0x65, 0x83, 0x96, 0x88, 0x10, 0x68, 0x05, 0x0c, 0x03, 0x15,
-	0xbe, 0x4f, 0xd5, 0x9b, 0x7c, 0x86, 0x5e, 0xc4, 0x25, 0x5b, 0x30, 0xc9, 0xcc, 0xb6, 0xe2, 0x2b,
-	0x6c, 0x1f, 0x41, 0xbf, 0x3e, 0xcb, 0x9b, 0x73, 0x8c, 0xa0, 0x1d, 0x8a, 0x05, 0xbf, 0x2c, 0x63,
-	0x14, 0xc0, 0xbe, 0xd2, 0xc1, 0x78, 0xb2, 0xd0, 0xfb, 0xf8, 0xe6, 0xac, 0xea, 0x59, 0xd6, 0x2b,
-	0x00, 0x31, 0xa1, 0x1b, 0x85, 0x88, 0xa1, 0xf0, 0x55, 0xbd, 0x1e, 0xdd, 0xc2, 0xc9, 0xec, 0x76,
-	0x6d, 0xe9, 0xab, 0xb5, 0xa5, 0x3f, 0xac, 0x2d, 0xfd, 0x66, 0x63, 0x69, 0xab, 0x8d, 0xa5, 0xdd,
-	0x6d, 0x2c, 0xed, 0xe4, 0x97, 0x1f, 0xca, 0x20, 0x9b, 0x3b, 0x5e, 0x1c, 0xb9, 0xb5, 0xcb, 0xa9,
-	0x3d, 0xd5, 0xd1, 0xb8, 0x87, 0xae, 0x6a, 0xde, 0x51, 0xbf, 0xfd, 0x7c, 0x0c, 0x00, 0x00, 0xff,
-	0xff, 0xcc, 0x16, 0xc2, 0x8b, 0x74, 0x03, 0x00, 0x00,
+	// 459 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0x4d, 0xab, 0xd3, 0x40,
+	0x14, 0xcd, 0xbc, 0x7e, 0x72, 0x5f, 0x23, 0xed, 0x50, 0x24, 0xb8, 0x08, 0x31, 0x82, 0x16, 0x94,
+	0x14, 0x74, 0xe9, 0xee, 0xbd, 0x4d, 0x05, 0xdd, 0x8c, 0x3c, 0x10, 0x37, 0x65, 0x5e, 0x3a, 0x36,
+	0x41, 0x32, 0x89, 0xb9, 0x53, 0xf0, 0xfd, 0x0b, 0xff, 0x84, 0xff, 0xc5, 0xe5, 0x5b, 0x8a, 0x6e,
+	0xa4, 0xfd, 0x23, 0x92, 0x3b, 0x69, 0x1b, 0x63, 0x51, 0x04, 0x77, 0x39, 0x67, 0xee, 0x9c, 0x9c,
+	0x73, 0x2e, 0x03, 0x81, 0x51, 0x7a, 0xa5, 0xca, 0x2c, 0xd5, 0x66, 0x8e, 0x46, 0x1a, 0x85, 0x37,
+	0x3a, 0x9e, 0x9b, 0x9b, 0x42, 0x61, 0x54, 0x94, 0xb9, 0xc9, 0xf9, 0xf4, 0x38, 0x11, 0x1d, 0x26,
+	0xc2, 0x6f, 0x67, 0x30, 0x78, 0xa5, 0x10, 0xe5, 0x5a, 0xf1, 0x2b, 0x98, 0xa0, 0x96, 0x05, 0x26,
+	0xb9, 0xc1, 0x65, 0xa9, 0x3e, 0x6c, 0x14, 0x1a, 0x8f, 0x05, 0x6c, 0x76, 0xfe, 0xf4, 0x61, 0x74,
+	0xea, 0x76, 0xf4, 0x7a, 0x3f, 0x2e, 0xec, 0xf4, 0xc2, 0x11, 0x63, 0x6c, 0x71, 0xfc, 0x0d, 0xf0,
+	0xa6, 0x2c, 0x16, 0xb9, 0x46, 0xe5, 0x9d, 0x91, 0xee, 0xa3, 0xbf, 0xea, 0xda, 0xf1, 0x85, 0x23,
+	0x26, 0xd8, 0x26, 0xf9, 0x0b, 0x70, 0xe3, 0x64, 0xa3, 0xdf, 0x1f, 0xcc, 0x76, 0x48, 0x34, 0x3c,
+	0x2d, 0x7a, 0x59, 0x8d, 0x1e, 0x8d, 0x8e, 0xe2, 0x06, 0xe6, 0x2f, 0xe1, 0xce, 0x5e, 0xaa, 0x36,
+	0xd8, 0x25, 0xad, 0x07, 0x7f, 0xd4, 0x3a, 0x98, 0x73, 0xe3, 0x26, 0x71, 0xd1, 0x83, 0x0e, 0x6e,
+	0xb2, 0xf0, 0x09, 0x8c, 0xdb, 0x0d, 0x71, 0x0f, 0x06, 0xef, 0xf2, 0x32, 0x93, 0x06, 0x3d, 0x16,
+	0x74, 0x66, 0xae, 0xd8, 0xc3, 0xf0, 0x3b, 0x83, 0xc9, 0x6f, 0xc1, 0xf9, 0x5d, 0xe8, 0x27, 0x2a,
+	0x5d, 0x27, 0x76, 0x13, 0x5d, 0x51, 0xa3, 0x8a, 0xb7, 0x17, 0xa9, 0x49, 0x57, 0xd4, 0xa8, 0xe2,
+	0xc9, 0x0b, 0x52, 0x19, 0xae, 0xa8, 0x11, 0xe7, 0xd0, 0x4d, 0x24, 0x26, 0x14, 0x6b, 0x24, 0xe8,
+	0x9b, 0xdf, 0x83, 0x61, 0xa6, 0x8c, 0x5c, 0x49, 0x23, 0xbd, 0x1e, 0xf1, 0x07, 0xcc, 0x1f, 0xc3,
+	0x24, 0xce, 0xb3, 0xa2, 0x54, 0x88, 0x6a, 0xb5, 0xac, 0x25, 0xfb, 0x01, 0x9b, 0x0d, 0xc5, 0xf8,
+	0x78, 0x70, 0x69, 0xc5, 0xef, 0x83, 0x6d, 0x73, 0x59, 0xc9, 0x2a, 0xf4, 0x06, 0x41, 0x67, 0x36,
+	0x12, 0xe7, 0xc4, 0x2d, 0x88, 0x0a, 0x0d, 0x8c, 0x9a, 0x0b, 0xf8, 0xe7, 0x5c, 0x53, 0xe8, 0xa5,
+	0x7a, 0xa5, 0x3e, 0xd6, 0xb1, 0x2c, 0xe0, 0x3e, 0xc0, 0xd1, 0x0c, 0x65, 0x1b, 0x8a, 0x06, 0x13,
+	0x7e, 0x66, 0xe0, 0xfe, 0xb2, 0xab, 0xff, 0xf4, 0xdf, 0x29, 0xf4, 0x28, 0x5c, 0x5d, 0xa7, 0x05,
+	0xd5, 0x6e, 0xb3, 0x14, 0x31, 0xd5, 0x6b, 0xaa, 0x73, 0x28, 0xf6, 0xb0, 0xe5, 0xb3, 0xdf, 0xf6,
+	0x79, 0x71, 0xf5, 0x65, 0xeb, 0xb3, 0xdb, 0xad, 0xcf, 0x7e, 0x6c, 0x7d, 0xf6, 0x69, 0xe7, 0x3b,
+	0xb7, 0x3b, 0xdf, 0xf9, 0xba, 0xf3, 0x9d, 0xb7, 0xcf, 0xd7, 0xa9, 0x49, 0x36, 0xd7, 0x51, 0x9c,
+	0x67, 0xf3, 0xc6, 0x1b, 0x6f, 0x7c, 0xd2, 0xf3, 0x9e, 0x9f, 0x7a, 0xff, 0xd7, 0x7d, 0x3a, 0x7b,
+	0xf6, 0x73, 0x00, 0x8e, 0x09, 0x9f, 0xac, 0x1e, 0x04, 0x00, 0x00,
 }
 
 func (m *Message) Marshal() (dAtA []byte, err error) {
@@ -557,6 +605,24 @@ func (m *SnapshotsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Formats) > 0 {
+		dAtA6 := make([]byte, len(m.Formats)*10)
+		var j5 int
+		for _, num := range m.Formats {
+			for num >= 1<<7 {
+				dAtA6[j5] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j5++
+			}
+			dAtA6[j5] = uint8(num)
+			j5++
+		}
+		i -= j5
+		copy(dAtA[i:], dAtA6[:j5])
+		i = encodeVarintTypes(dAtA, i, uint64(j5))
+		i--
+		dAtA[i] = 0xa
+	}
 	return len(dAtA) - i, nil
 }
 
@@ -580,6 +646,25 @@ func (m *SnapshotsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.ChunkHashes) > 0 {
+		for iNdEx := len(m.ChunkHashes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ChunkHashes[iNdEx])
+			copy(dAtA[i:], m.ChunkHashes[iNdEx])
+			i = encodeVarintTypes(dAtA, i, uint64(len(m.ChunkHashes[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.CompressedChunks {
+		i--
+		if m.CompressedChunks {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.Metadata) > 0 {
 		i -= len(m.Metadata)
 		copy(dAtA[i:], m.Metadata)
@@ -632,6 +717,16 @@ func (m *ChunkRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Compressed {
+		i--
+		if m.Compressed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.Index != 0 {
 		i = encodeVarintTypes(dAtA, i, uint64(m.Index))
 		i--
@@ -670,6 +765,16 @@ func (m *ChunkResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Compressed {
+		i--
+		if m.Compressed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
 	if m.Missing {
 		i--
 		if m.Missing {
@@ -782,6 +887,13 @@ func (m *SnapshotsRequest) Size() (n int) {
 	}
 	var l int
 	_ = l
+	if len(m.Formats) > 0 {
+		l = 0
+		for _, e := range m.Formats {
+			l += sovTypes(uint64(e))
+		}
+		n += 1 + sovTypes(uint64(l)) + l
+	}
 	return n
 }
 
@@ -808,6 +920,15 @@ func (m *SnapshotsResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovTypes(uint64(l))
 	}
+	if m.CompressedChunks {
+		n += 2
+	}
+	if len(m.ChunkHashes) > 0 {
+		for _, b := range m.ChunkHashes {
+			l = len(b)
+			n += 1 + l + sovTypes(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -826,6 +947,9 @@ func (m *ChunkRequest) Size() (n int) {
 	if m.Index != 0 {
 		n += 1 + sovTypes(uint64(m.Index))
 	}
+	if m.Compressed {
+		n += 2
+	}
 	return n
 }
 
@@ -851,6 +975,9 @@ func (m *ChunkResponse) Size() (n int) {
 	if m.Missing {
 		n += 2
 	}
+	if m.Compressed {
+		n += 2
+	}
 	return n
 }
 
@@ -1079,6 +1206,82 @@ func (m *SnapshotsRequest) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: SnapshotsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType == 0 {
+				var v uint32
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint32(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Formats = append(m.Formats, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowTypes
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthTypes
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthTypes
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.Formats) == 0 {
+					m.Formats = make([]uint32, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint32
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowTypes
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Formats = append(m.Formats, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Formats", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1254,6 +1457,58 @@ func (m *SnapshotsResponse) Unmarshal(dAtA []byte) error {
 				m.Metadata = []byte{}
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CompressedChunks", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.CompressedChunks = bool(v != 0)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChunkHashes", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthTypes
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTypes
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChunkHashes = append(m.ChunkHashes, make([]byte, postIndex-iNdEx))
+			copy(m.ChunkHashes[len(m.ChunkHashes)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1361,6 +1616,26 @@ func (m *ChunkRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compressed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Compressed = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
@@ -1522,6 +1797,26 @@ func (m *ChunkResponse) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.Missing = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compressed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTypes
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Compressed = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTypes(dAtA[iNdEx:])
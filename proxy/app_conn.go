@@ -1,6 +1,8 @@
 package proxy
 
 import (
+	"context"
+
 	abcicli "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/abci/types"
 )
@@ -31,6 +33,8 @@ type AppConnMempool interface {
 
 	CheckTxAsync(types.RequestCheckTx) *abcicli.ReqRes
 	CheckTxSync(types.RequestCheckTx) (*types.ResponseCheckTx, error)
+	CheckTxAsyncWithContext(context.Context, types.RequestCheckTx) *abcicli.ReqRes
+	CheckTxSyncWithContext(context.Context, types.RequestCheckTx) (*types.ResponseCheckTx, error)
 
 	FlushAsync() *abcicli.ReqRes
 	FlushSync() error
@@ -145,6 +149,17 @@ func (app *appConnMempool) CheckTxSync(req types.RequestCheckTx) (*types.Respons
 	return app.appConn.CheckTxSync(req)
 }
 
+func (app *appConnMempool) CheckTxAsyncWithContext(ctx context.Context, req types.RequestCheckTx) *abcicli.ReqRes {
+	return app.appConn.CheckTxAsyncWithContext(ctx, req)
+}
+
+func (app *appConnMempool) CheckTxSyncWithContext(
+	ctx context.Context,
+	req types.RequestCheckTx,
+) (*types.ResponseCheckTx, error) {
+	return app.appConn.CheckTxSyncWithContext(ctx, req)
+}
+
 //------------------------------------------------
 // Implements AppConnQuery (subset of abcicli.Client)
 
@@ -40,6 +40,27 @@ func (l *localClientCreator) NewABCIClient() (abcicli.Client, error) {
 	return abcicli.NewLocalClient(l.mtx, l.app), nil
 }
 
+type concurrentLocalClientCreator struct {
+	mtx         *cmtsync.RWMutex
+	app         types.Application
+	concurrency int
+}
+
+// NewConcurrentLocalClientCreator returns a ClientCreator for the given app,
+// which will be running locally and serving up to concurrency CheckTx calls
+// at once.
+func NewConcurrentLocalClientCreator(app types.Application, concurrency int) ClientCreator {
+	return &concurrentLocalClientCreator{
+		mtx:         new(cmtsync.RWMutex),
+		app:         app,
+		concurrency: concurrency,
+	}
+}
+
+func (l *concurrentLocalClientCreator) NewABCIClient() (abcicli.Client, error) {
+	return abcicli.NewConcurrentLocalClient(l.mtx, l.app, l.concurrency), nil
+}
+
 //---------------------------------------------------------------
 // remote proxy opens new connections to an external app process
 
@@ -47,6 +68,8 @@ type remoteClientCreator struct {
 	addr        string
 	transport   string
 	mustConnect bool
+	concurrency int
+	reconnect   *abcicli.ReconnectConfig
 }
 
 // NewRemoteClientCreator returns a ClientCreator for the given address (e.g.
@@ -60,8 +83,35 @@ func NewRemoteClientCreator(addr, transport string, mustConnect bool) ClientCrea
 	}
 }
 
+// NewConcurrentRemoteClientCreator returns a ClientCreator like
+// NewRemoteClientCreator, whose clients allow up to concurrency outstanding
+// CheckTx requests.
+func NewConcurrentRemoteClientCreator(addr, transport string, mustConnect bool, concurrency int) ClientCreator {
+	return &remoteClientCreator{
+		addr:        addr,
+		transport:   transport,
+		mustConnect: mustConnect,
+		concurrency: concurrency,
+	}
+}
+
 func (r *remoteClientCreator) NewABCIClient() (abcicli.Client, error) {
-	remoteApp, err := abcicli.NewClient(r.addr, r.transport, r.mustConnect)
+	var (
+		remoteApp abcicli.Client
+		err       error
+	)
+	switch {
+	case r.reconnect != nil && r.transport == "socket":
+		remoteApp = abcicli.NewReconnectingSocketClient(r.addr, r.mustConnect, *r.reconnect)
+	case r.transport == "grpc" && r.concurrency <= 1:
+		// concurrent CheckTx calls need unary calls, otherwise prefer the
+		// stream if the application supports it
+		remoteApp = abcicli.NewStreamingGRPCClient(r.addr, r.mustConnect)
+	case r.concurrency > 1:
+		remoteApp, err = abcicli.NewConcurrentClient(r.addr, r.transport, r.mustConnect, r.concurrency)
+	default:
+		remoteApp, err = abcicli.NewClient(r.addr, r.transport, r.mustConnect)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
 	}
@@ -69,29 +119,61 @@ func (r *remoteClientCreator) NewABCIClient() (abcicli.Client, error) {
 	return remoteApp, nil
 }
 
+// WithReconnect returns a ClientCreator like clientCreator, whose socket
+// clients reconnect to the application when the connection drops. They resume
+// once reconnected only if checkInfo accepts the Info response of the
+// application, and stop with its error otherwise. Other ClientCreators are
+// returned as is.
+func WithReconnect(clientCreator ClientCreator, checkInfo func(types.ResponseInfo) error) ClientCreator {
+	r, ok := clientCreator.(*remoteClientCreator)
+	if !ok || r.transport != "socket" {
+		return clientCreator
+	}
+	reconnecting := *r
+	reconnecting.reconnect = &abcicli.ReconnectConfig{
+		RequestInfo: RequestInfo,
+		CheckInfo:   checkInfo,
+	}
+	return &reconnecting
+}
+
 // DefaultClientCreator returns a default ClientCreator, which will create a
 // local client if addr is one of: 'counter', 'counter_serial', 'kvstore',
 // 'persistent_kvstore' or 'noop', otherwise - a remote client.
 func DefaultClientCreator(addr, transport, dbDir string) ClientCreator {
+	return DefaultConcurrentClientCreator(addr, transport, dbDir, 1)
+}
+
+// DefaultConcurrentClientCreator returns a default ClientCreator like
+// DefaultClientCreator, whose clients allow up to checkTxConcurrency CheckTx
+// requests at once. A checkTxConcurrency of 1 serializes them.
+func DefaultConcurrentClientCreator(addr, transport, dbDir string, checkTxConcurrency int) ClientCreator {
+	newLocalClientCreator := NewLocalClientCreator
+	if checkTxConcurrency > 1 {
+		newLocalClientCreator = func(app types.Application) ClientCreator {
+			return NewConcurrentLocalClientCreator(app, checkTxConcurrency)
+		}
+	}
+
 	switch addr {
 	case "counter":
-		return NewLocalClientCreator(counter.NewApplication(false))
+		return newLocalClientCreator(counter.NewApplication(false))
 	case "counter_serial":
-		return NewLocalClientCreator(counter.NewApplication(true))
+		return newLocalClientCreator(counter.NewApplication(true))
 	case "kvstore":
-		return NewLocalClientCreator(kvstore.NewApplication())
+		return newLocalClientCreator(kvstore.NewApplication())
 	case "persistent_kvstore":
-		return NewLocalClientCreator(kvstore.NewPersistentKVStoreApplication(dbDir))
+		return newLocalClientCreator(kvstore.NewPersistentKVStoreApplication(dbDir))
 	case "e2e":
 		app, err := e2e.NewApplication(e2e.DefaultConfig(dbDir))
 		if err != nil {
 			panic(err)
 		}
-		return NewLocalClientCreator(app)
+		return newLocalClientCreator(app)
 	case "noop":
-		return NewLocalClientCreator(types.NewBaseApplication())
+		return newLocalClientCreator(types.NewBaseApplication())
 	default:
 		mustConnect := false // loop retrying
-		return NewRemoteClientCreator(addr, transport, mustConnect)
+		return NewConcurrentRemoteClientCreator(addr, transport, mustConnect, checkTxConcurrency)
 	}
 }
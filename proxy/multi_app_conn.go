@@ -7,6 +7,7 @@ import (
 	cmtlog "github.com/tendermint/tendermint/libs/log"
 	cmtos "github.com/tendermint/tendermint/libs/os"
 	"github.com/tendermint/tendermint/libs/service"
+	"github.com/tendermint/tendermint/pkg/trace"
 )
 
 const (
@@ -32,8 +33,8 @@ type AppConns interface {
 }
 
 // NewAppConns calls NewMultiAppConn.
-func NewAppConns(clientCreator ClientCreator) AppConns {
-	return NewMultiAppConn(clientCreator)
+func NewAppConns(clientCreator ClientCreator, options ...MultiAppConnOption) AppConns {
+	return NewMultiAppConn(clientCreator, options...)
 }
 
 // multiAppConn implements AppConns.
@@ -55,13 +56,29 @@ type multiAppConn struct {
 	snapshotConnClient  abcicli.Client
 
 	clientCreator ClientCreator
+	callTracer    *callTracer
+}
+
+// MultiAppConnOption sets an optional parameter on the multiAppConn.
+type MultiAppConnOption func(*multiAppConn)
+
+// WithTraceClient sets the tracer writing the abci_call table, with a row
+// per call made to the application. Only checkTxSampleRate of the CheckTx
+// calls are written.
+func WithTraceClient(tracer trace.Tracer, checkTxSampleRate float64) MultiAppConnOption {
+	return func(app *multiAppConn) {
+		app.callTracer = newCallTracer(tracer, checkTxSampleRate)
+	}
 }
 
 // NewMultiAppConn makes all necessary abci connections to the application.
-func NewMultiAppConn(clientCreator ClientCreator) AppConns {
+func NewMultiAppConn(clientCreator ClientCreator, options ...MultiAppConnOption) AppConns {
 	multiAppConn := &multiAppConn{
 		clientCreator: clientCreator,
 	}
+	for _, option := range options {
+		option(multiAppConn)
+	}
 	multiAppConn.BaseService = *service.NewBaseService(nil, "multiAppConn", multiAppConn)
 	return multiAppConn
 }
@@ -114,6 +131,13 @@ func (app *multiAppConn) OnStart() error {
 	app.consensusConnClient = c
 	app.consensusConn = NewAppConnConsensus(c)
 
+	if app.callTracer != nil {
+		app.queryConn = &tracedAppConnQuery{app.queryConn, app.callTracer}
+		app.snapshotConn = &tracedAppConnSnapshot{app.snapshotConn, app.callTracer}
+		app.mempoolConn = &tracedAppConnMempool{app.mempoolConn, app.callTracer}
+		app.consensusConn = &tracedAppConnConsensus{app.consensusConn, app.callTracer}
+	}
+
 	// Kill CometBFT if the ABCI application crashes.
 	go app.killTMOnClientError()
 
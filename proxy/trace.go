@@ -0,0 +1,258 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
+)
+
+// errNoResponse is traced for asynchronous calls completed without a
+// response, e.g. because the connection was lost.
+var errNoResponse = errors.New("no response")
+
+// sizer is implemented by the ABCI requests and responses.
+type sizer interface {
+	Size() int
+}
+
+// callTracer writes a row to the abci_call table per call made on the
+// connections, sampling CheckTx calls.
+type callTracer struct {
+	tracer            trace.Tracer
+	checkTxSampleRate float64
+	checkTxCalls      uint64 // atomic
+	height            int64  // atomic, height of the block being executed
+}
+
+func newCallTracer(tracer trace.Tracer, checkTxSampleRate float64) *callTracer {
+	return &callTracer{
+		tracer:            tracer,
+		checkTxSampleRate: checkTxSampleRate,
+	}
+}
+
+func (t *callTracer) enabled() bool {
+	return t.tracer.IsCollecting(schema.ABCICallTable)
+}
+
+// sampleCheckTx reports whether to trace the next CheckTx call. Calls are
+// sampled evenly, so that exactly the sample rate of them are traced.
+func (t *callTracer) sampleCheckTx() bool {
+	if !t.enabled() {
+		return false
+	}
+	n := atomic.AddUint64(&t.checkTxCalls, 1)
+	return uint64(float64(n)*t.checkTxSampleRate) > uint64(float64(n-1)*t.checkTxSampleRate)
+}
+
+func (t *callTracer) trace(method string, height int64, req, res sizer, start time.Time, code uint32, err error) {
+	if !t.enabled() {
+		return
+	}
+	sampleRate := 1.0
+	if method == "check_tx" {
+		sampleRate = t.checkTxSampleRate
+	}
+	schema.WriteABCICall(t.tracer, method, height, req.Size(), res.Size(), time.Since(start), code, err, sampleRate)
+}
+
+// traceAsync traces the call of reqres, made with req, once it completes.
+func (t *callTracer) traceAsync(method string, height int64, req sizer, reqres *abcicli.ReqRes, start time.Time) {
+	go func() {
+		reqres.Wait()
+		var (
+			res  sizer = &types.Response{}
+			code uint32
+			err  error
+		)
+		switch r := reqres.Response.GetValue().(type) {
+		case *types.Response_CheckTx:
+			res, code = r.CheckTx, r.CheckTx.GetCode()
+		case *types.Response_DeliverTx:
+			res, code = r.DeliverTx, r.DeliverTx.GetCode()
+		default:
+			if err = reqres.Err(); err == nil {
+				err = errNoResponse
+			}
+		}
+		t.trace(method, height, req, res, start, code, err)
+	}()
+}
+
+//----------------------------------------------------------------------------------------
+
+type tracedAppConnConsensus struct {
+	AppConnConsensus
+	*callTracer
+}
+
+func (app *tracedAppConnConsensus) InitChainSync(req types.RequestInitChain) (*types.ResponseInitChain, error) {
+	start := time.Now()
+	res, err := app.AppConnConsensus.InitChainSync(req)
+	app.trace("init_chain", req.InitialHeight, &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnConsensus) BeginBlockSync(req types.RequestBeginBlock) (*types.ResponseBeginBlock, error) {
+	atomic.StoreInt64(&app.height, req.Header.Height)
+	start := time.Now()
+	res, err := app.AppConnConsensus.BeginBlockSync(req)
+	app.trace("begin_block", req.Header.Height, &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnConsensus) DeliverTxAsync(req types.RequestDeliverTx) *abcicli.ReqRes {
+	start := time.Now()
+	reqres := app.AppConnConsensus.DeliverTxAsync(req)
+	if app.enabled() {
+		app.traceAsync("deliver_tx", atomic.LoadInt64(&app.height), &req, reqres, start)
+	}
+	return reqres
+}
+
+func (app *tracedAppConnConsensus) EndBlockSync(req types.RequestEndBlock) (*types.ResponseEndBlock, error) {
+	start := time.Now()
+	res, err := app.AppConnConsensus.EndBlockSync(req)
+	app.trace("end_block", req.Height, &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnConsensus) CommitSync() (*types.ResponseCommit, error) {
+	start := time.Now()
+	res, err := app.AppConnConsensus.CommitSync()
+	app.trace("commit", atomic.LoadInt64(&app.height), &types.RequestCommit{}, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnConsensus) PrepareProposalSync(
+	req types.RequestPrepareProposal,
+) (*types.ResponsePrepareProposal, error) {
+	start := time.Now()
+	res, err := app.AppConnConsensus.PrepareProposalSync(req)
+	app.trace("prepare_proposal", req.Height, &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnConsensus) ProcessProposalSync(
+	req types.RequestProcessProposal,
+) (*types.ResponseProcessProposal, error) {
+	start := time.Now()
+	res, err := app.AppConnConsensus.ProcessProposalSync(req)
+	app.trace("process_proposal", req.Header.Height, &req, res, start, 0, err)
+	return res, err
+}
+
+//------------------------------------------------
+
+type tracedAppConnMempool struct {
+	AppConnMempool
+	*callTracer
+}
+
+func (app *tracedAppConnMempool) CheckTxAsync(req types.RequestCheckTx) *abcicli.ReqRes {
+	start := time.Now()
+	reqres := app.AppConnMempool.CheckTxAsync(req)
+	if app.sampleCheckTx() {
+		app.traceAsync("check_tx", 0, &req, reqres, start)
+	}
+	return reqres
+}
+
+func (app *tracedAppConnMempool) CheckTxSync(req types.RequestCheckTx) (*types.ResponseCheckTx, error) {
+	start := time.Now()
+	res, err := app.AppConnMempool.CheckTxSync(req)
+	if app.sampleCheckTx() {
+		app.trace("check_tx", 0, &req, res, start, res.GetCode(), err)
+	}
+	return res, err
+}
+
+func (app *tracedAppConnMempool) CheckTxAsyncWithContext(
+	ctx context.Context,
+	req types.RequestCheckTx,
+) *abcicli.ReqRes {
+	start := time.Now()
+	reqres := app.AppConnMempool.CheckTxAsyncWithContext(ctx, req)
+	if app.sampleCheckTx() {
+		app.traceAsync("check_tx", 0, &req, reqres, start)
+	}
+	return reqres
+}
+
+func (app *tracedAppConnMempool) CheckTxSyncWithContext(
+	ctx context.Context,
+	req types.RequestCheckTx,
+) (*types.ResponseCheckTx, error) {
+	start := time.Now()
+	res, err := app.AppConnMempool.CheckTxSyncWithContext(ctx, req)
+	if app.sampleCheckTx() {
+		app.trace("check_tx", 0, &req, res, start, res.GetCode(), err)
+	}
+	return res, err
+}
+
+//------------------------------------------------
+
+type tracedAppConnQuery struct {
+	AppConnQuery
+	*callTracer
+}
+
+func (app *tracedAppConnQuery) InfoSync(req types.RequestInfo) (*types.ResponseInfo, error) {
+	start := time.Now()
+	res, err := app.AppConnQuery.InfoSync(req)
+	app.trace("info", 0, &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnQuery) QuerySync(req types.RequestQuery) (*types.ResponseQuery, error) {
+	start := time.Now()
+	res, err := app.AppConnQuery.QuerySync(req)
+	app.trace("query", req.Height, &req, res, start, res.GetCode(), err)
+	return res, err
+}
+
+//------------------------------------------------
+
+type tracedAppConnSnapshot struct {
+	AppConnSnapshot
+	*callTracer
+}
+
+func (app *tracedAppConnSnapshot) ListSnapshotsSync(
+	req types.RequestListSnapshots) (*types.ResponseListSnapshots, error) {
+	start := time.Now()
+	res, err := app.AppConnSnapshot.ListSnapshotsSync(req)
+	app.trace("list_snapshots", 0, &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnSnapshot) OfferSnapshotSync(
+	req types.RequestOfferSnapshot) (*types.ResponseOfferSnapshot, error) {
+	start := time.Now()
+	res, err := app.AppConnSnapshot.OfferSnapshotSync(req)
+	app.trace("offer_snapshot", int64(req.Snapshot.GetHeight()), &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnSnapshot) LoadSnapshotChunkSync(
+	req types.RequestLoadSnapshotChunk) (*types.ResponseLoadSnapshotChunk, error) {
+	start := time.Now()
+	res, err := app.AppConnSnapshot.LoadSnapshotChunkSync(req)
+	app.trace("load_snapshot_chunk", int64(req.Height), &req, res, start, 0, err)
+	return res, err
+}
+
+func (app *tracedAppConnSnapshot) ApplySnapshotChunkSync(
+	req types.RequestApplySnapshotChunk) (*types.ResponseApplySnapshotChunk, error) {
+	start := time.Now()
+	res, err := app.AppConnSnapshot.ApplySnapshotChunkSync(req)
+	app.trace("apply_snapshot_chunk", 0, &req, res, start, 0, err)
+	return res, err
+}
@@ -0,0 +1,110 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/abci/example/kvstore"
+	"github.com/tendermint/tendermint/abci/types"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+)
+
+// recordingTracer keeps the entries written to it in memory.
+type recordingTracer struct {
+	mtx     cmtsync.Mutex
+	entries []trace.Entry
+}
+
+func (r *recordingTracer) Write(e trace.Entry) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+func (r *recordingTracer) IsCollecting(string) bool { return true }
+func (r *recordingTracer) Stop()                    {}
+
+// calls returns the traced calls to method.
+func (r *recordingTracer) calls(method string) []schema.ABCICall {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	var calls []schema.ABCICall
+	for _, e := range r.entries {
+		if call, ok := e.(schema.ABCICall); ok && call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func startTracedAppConns(t *testing.T, tracer trace.Tracer, checkTxSampleRate float64) AppConns {
+	appConns := NewAppConns(NewLocalClientCreator(kvstore.NewApplication()),
+		WithTraceClient(tracer, checkTxSampleRate))
+	require.NoError(t, appConns.Start())
+	t.Cleanup(func() {
+		if err := appConns.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	appConns.Consensus().SetResponseCallback(func(*types.Request, *types.Response) {})
+	appConns.Mempool().SetResponseCallback(func(*types.Request, *types.Response) {})
+	return appConns
+}
+
+func TestTraceBlockExecution(t *testing.T) {
+	tracer := &recordingTracer{}
+	consensus := startTracedAppConns(t, tracer, 1).Consensus()
+
+	const height = 3
+	_, err := consensus.BeginBlockSync(types.RequestBeginBlock{Header: cmtproto.Header{Height: height}})
+	require.NoError(t, err)
+	for _, tx := range []string{"a=1", "b=2"} {
+		consensus.DeliverTxAsync(types.RequestDeliverTx{Tx: []byte(tx)})
+	}
+	_, err = consensus.EndBlockSync(types.RequestEndBlock{Height: height})
+	require.NoError(t, err)
+	_, err = consensus.CommitSync()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(tracer.calls("deliver_tx")) == 2
+	}, time.Second, 5*time.Millisecond)
+	for _, method := range []string{"begin_block", "deliver_tx", "end_block", "commit"} {
+		calls := tracer.calls(method)
+		require.NotEmpty(t, calls, method)
+		for _, call := range calls {
+			assert.EqualValues(t, height, call.Height, method)
+			assert.Positive(t, call.ResponseSize+call.RequestSize, method)
+			assert.Empty(t, call.Error, method)
+		}
+	}
+	assert.Equal(t, len("a=1")+2, tracer.calls("deliver_tx")[0].RequestSize) // tag and length prefix
+}
+
+func TestTraceCheckTxSampling(t *testing.T) {
+	tracer := &recordingTracer{}
+	mempool := startTracedAppConns(t, tracer, 0.1).Mempool()
+
+	for i := 0; i < 50; i++ {
+		mempool.CheckTxAsync(types.RequestCheckTx{Tx: []byte("a=1")})
+		_, err := mempool.CheckTxSync(types.RequestCheckTx{Tx: []byte("a=1")})
+		require.NoError(t, err)
+	}
+	require.Eventually(t, func() bool {
+		return len(tracer.calls("check_tx")) == 10
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, tracer.calls("check_tx"), 10)
+
+	none := &recordingTracer{}
+	mempool = startTracedAppConns(t, none, 0).Mempool()
+	_, err := mempool.CheckTxSync(types.RequestCheckTx{Tx: []byte("a=1")})
+	require.NoError(t, err)
+	assert.Empty(t, none.calls("check_tx"))
+}
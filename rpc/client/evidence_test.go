@@ -126,6 +126,7 @@ func TestBroadcastEvidence_DuplicateVoteEvidence(t *testing.T) {
 		result, err := c.BroadcastEvidence(context.Background(), correct)
 		require.NoError(t, err, "BroadcastEvidence(%s) failed", correct)
 		assert.Equal(t, correct.Hash(), result.Hash, "expected result hash to match evidence hash")
+		assert.True(t, result.Accepted, "expected evidence to be accepted, got %s", result.Error)
 
 		status, err := c.Status(context.Background())
 		require.NoError(t, err)
@@ -149,9 +150,16 @@ func TestBroadcastEvidence_DuplicateVoteEvidence(t *testing.T) {
 		require.EqualValues(t, rawpub, pk, "Stored PubKey not equal with expected, value %v", string(qres.Value))
 		require.Equal(t, int64(9), v.Power, "Stored Power not equal with expected, value %v", string(qres.Value))
 
+		evStatus, err := c.Evidence(context.Background(), correct.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, "committed", evStatus.Status)
+		assert.Greater(t, evStatus.CommittedHeight, correct.Height())
+
 		for _, fake := range fakes {
-			_, err := c.BroadcastEvidence(context.Background(), fake)
-			require.Error(t, err, "BroadcastEvidence(%s) succeeded, but the evidence was fake", fake)
+			result, err := c.BroadcastEvidence(context.Background(), fake)
+			require.NoError(t, err)
+			require.False(t, result.Accepted, "BroadcastEvidence(%s) succeeded, but the evidence was fake", fake)
+			assert.NotEmpty(t, result.Reason)
 		}
 	}
 }
@@ -703,6 +703,15 @@ func (c *baseRPCClient) BroadcastEvidence(
 	return result, nil
 }
 
+func (c *baseRPCClient) Evidence(ctx context.Context, hash []byte) (*ctypes.ResultEvidence, error) {
+	result := new(ctypes.ResultEvidence)
+	_, err := c.caller.Call(ctx, "evidence", map[string]interface{}{"hash": hash}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 //-----------------------------------------------------------------------------
 // WSEvents
 
@@ -158,9 +158,10 @@ type MempoolClient interface {
 }
 
 // EvidenceClient is used for submitting an evidence of the malicious
-// behaviour.
+// behaviour, and querying its status.
 type EvidenceClient interface {
 	BroadcastEvidence(context.Context, types.Evidence) (*ctypes.ResultBroadcastEvidence, error)
+	Evidence(ctx context.Context, hash []byte) (*ctypes.ResultEvidence, error)
 }
 
 // RemoteClient is a Client, which can also return the remote network address.
@@ -261,6 +261,10 @@ func (c *Local) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*ctyp
 	return core.BroadcastEvidence(c.ctx, ev)
 }
 
+func (c *Local) Evidence(ctx context.Context, hash []byte) (*ctypes.ResultEvidence, error) {
+	return core.Evidence(c.ctx, hash)
+}
+
 func (c *Local) Subscribe(
 	ctx context.Context,
 	subscriber,
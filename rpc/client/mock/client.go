@@ -194,3 +194,7 @@ func (c Client) Validators(ctx context.Context, height *int64, page, perPage *in
 func (c Client) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*ctypes.ResultBroadcastEvidence, error) {
 	return core.BroadcastEvidence(&rpctypes.Context{}, ev)
 }
+
+func (c Client) Evidence(ctx context.Context, hash []byte) (*ctypes.ResultEvidence, error) {
+	return core.Evidence(&rpctypes.Context{}, hash)
+}
@@ -11,9 +11,12 @@ import (
 	"github.com/tendermint/tendermint/libs/bytes"
 	cmtmath "github.com/tendermint/tendermint/libs/math"
 	cmtquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	sm "github.com/tendermint/tendermint/state"
 	blockidxnull "github.com/tendermint/tendermint/state/indexer/block/null"
+	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -43,10 +46,13 @@ func BlockchainInfo(ctx *rpctypes.Context, minHeight, maxHeight int64) (*ctypes.
 	}
 	env.Logger.Debug("BlockchainInfoHandler", "maxHeight", maxHeight, "minHeight", minHeight)
 
-	blockMetas := []*types.BlockMeta{}
-	for height := maxHeight; height >= minHeight; height-- {
-		blockMeta := env.BlockStore.LoadBlockMeta(height)
-		blockMetas = append(blockMetas, blockMeta)
+	results := env.BlockStore.LoadBlockMetaRange(minHeight, maxHeight)
+	blockMetas := make([]*types.BlockMeta, 0, len(results))
+	for i := len(results) - 1; i >= 0; i-- {
+		if err := results[i].Err; err != nil && !errors.Is(err, store.ErrBlockNotFound) {
+			return nil, fmt.Errorf("failed to load block meta at height %d: %w", results[i].Height, err)
+		}
+		blockMetas = append(blockMetas, results[i].BlockMeta)
 	}
 
 	return &ctypes.ResultBlockchainInfo{
@@ -423,7 +429,9 @@ func proveDataRootTuples(tuples []DataRootTuple, height int64) (*merkle.Proof, e
 
 // BlockResults gets ABCIResults at a given height.
 // If no height is provided, it will fetch results for the latest block.
-// When DiscardABCIResponses is enabled, an error will be returned.
+// When DiscardABCIResponses is enabled, only the results of the latest block
+// are available. For older heights, an error will be returned, unless the
+// node is configured to recompute them (see RecomputeABCIResponses).
 //
 // Results are for the height of the block containing the txs.
 // Thus response.results.deliver_tx[5] is the results of executing
@@ -437,6 +445,9 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	}
 
 	results, err := env.StateStore.LoadABCIResponses(height)
+	if errors.Is(err, sm.ErrABCIResponsesNotPersisted) {
+		results, err = loadDiscardedABCIResponses(env, height)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -451,6 +462,20 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	}, nil
 }
 
+// loadDiscardedABCIResponses returns the ABCI responses of the given height
+// if the node discards them: the responses of the last height are always
+// kept, those of older heights are recomputed if enabled.
+func loadDiscardedABCIResponses(env *Environment, height int64) (*cmtstate.ABCIResponses, error) {
+	if results, err := env.StateStore.LoadLastABCIResponse(height); err == nil {
+		return results, nil
+	}
+	if env.Recomputer == nil {
+		return nil, fmt.Errorf("ABCI responses for height %d are not retained by this node: %w",
+			height, sm.ErrABCIResponsesNotPersisted)
+	}
+	return env.Recomputer.Recompute(height)
+}
+
 func BlockSearchMatchEvents(
 	ctx *rpctypes.Context,
 	query string,
@@ -12,7 +12,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	dbm "github.com/cometbft/cometbft-db"
+	abcicli "github.com/tendermint/tendermint/abci/client"
 	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/libs/log"
 	"github.com/tendermint/tendermint/libs/pubsub/query"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
 	"github.com/tendermint/tendermint/types"
@@ -20,6 +22,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	cmtstore "github.com/tendermint/tendermint/proto/tendermint/store"
+	"github.com/tendermint/tendermint/proxy"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 	sm "github.com/tendermint/tendermint/state"
@@ -126,6 +129,69 @@ func TestBlockResults(t *testing.T) {
 	}
 }
 
+type replaySessions struct {
+	app abci.Application
+}
+
+func (s replaySessions) ReplaySession(height int64) (sm.ReplaySession, error) {
+	conn := proxy.NewAppConnConsensus(abcicli.NewLocalClient(nil, s.app))
+	return replaySession{conn}, nil
+}
+
+type replaySession struct {
+	proxy.AppConnConsensus
+}
+
+func (replaySession) Close() error { return nil }
+
+type echoApp struct {
+	abci.BaseApplication
+}
+
+func (echoApp) DeliverTx(req abci.RequestDeliverTx) abci.ResponseDeliverTx {
+	return abci.ResponseDeliverTx{Data: req.Tx}
+}
+
+func TestBlockResultsDiscarded(t *testing.T) {
+	results := &cmtstate.ABCIResponses{
+		DeliverTxs: []*abci.ResponseDeliverTx{{Code: 0, Data: []byte{0x01}, Log: "ok"}},
+		EndBlock:   &abci.ResponseEndBlock{},
+		BeginBlock: &abci.ResponseBeginBlock{},
+	}
+
+	env := &Environment{}
+	env.StateStore = sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{
+		DiscardABCIResponses: true,
+	})
+	require.NoError(t, env.StateStore.SaveABCIResponses(2, results))
+	blocks := randomBlocks(2)
+	blocks[1].LastCommit = &types.Commit{}
+	env.BlockStore = mockBlockStore{height: 2, blocks: blocks}
+	SetEnvironment(env)
+
+	// the results of the last height are kept
+	height := int64(2)
+	res, err := BlockResults(&rpctypes.Context{}, &height)
+	require.NoError(t, err)
+	assert.Equal(t, results.DeliverTxs, res.TxsResults)
+
+	// the results of older heights are not retained
+	height = 1
+	_, err = BlockResults(&rpctypes.Context{}, &height)
+	require.ErrorIs(t, err, sm.ErrABCIResponsesNotPersisted)
+	assert.Contains(t, err.Error(), "not retained")
+
+	// unless they can be recomputed
+	env.Recomputer = sm.NewABCIResponsesRecomputer(replaySessions{echoApp{}},
+		mockBlockStore{height: 1, blocks: blocks}, env.StateStore, 1, log.TestingLogger())
+	res, err = BlockResults(&rpctypes.Context{}, &height)
+	require.NoError(t, err)
+	require.Len(t, res.TxsResults, len(blocks[1].Txs))
+	for i, tx := range blocks[1].Txs {
+		assert.EqualValues(t, tx, res.TxsResults[i].Data)
+	}
+}
+
 func TestEncodeDataRootTuple(t *testing.T) {
 	height := uint64(2)
 	dataRoot, err := hex.DecodeString("82dc1607d84557d3579ce602a45f5872e821c36dbda7ec926dfa17ebc8d5c013")
@@ -299,6 +365,8 @@ func (mockBlockStore) LoadBlockMetaByHash(hash []byte) *types.BlockMeta  { retur
 func (mockBlockStore) LoadBlockCommit(height int64) *types.Commit        { return nil }
 func (mockBlockStore) LoadSeenCommit(height int64) *types.Commit         { return nil }
 func (mockBlockStore) PruneBlocks(height int64) (uint64, error)          { return 0, nil }
+func (mockBlockStore) TruncateBlocks(height int64) (uint64, error)       { return 0, nil }
+func (mockBlockStore) SetRetainHeight(height int64) error                { return nil }
 func (mockBlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) {
 }
 func (mockBlockStore) SaveTxInfo(block *types.Block, txResponseCodes []uint32, logs []string) error {
@@ -323,6 +391,24 @@ func (store mockBlockStore) LoadBlock(height int64) *types.Block {
 	return store.blocks[height]
 }
 
+func (store mockBlockStore) LoadBlockRange(from, to int64) []types.BlockRangeResult {
+	var results []types.BlockRangeResult
+	for h := from; h <= to; h++ {
+		results = append(results, types.BlockRangeResult{
+			Height: h, BlockMeta: store.LoadBlockMeta(h), Block: store.LoadBlock(h),
+		})
+	}
+	return results
+}
+
+func (store mockBlockStore) LoadBlockMetaRange(from, to int64) []types.BlockRangeResult {
+	var results []types.BlockRangeResult
+	for h := from; h <= to; h++ {
+		results = append(results, types.BlockRangeResult{Height: h, BlockMeta: store.LoadBlockMeta(h)})
+	}
+	return results
+}
+
 func (store mockBlockStore) LoadTxInfo(hash []byte) *cmtstore.TxInfo {
 	for _, block := range store.blocks {
 		for i, tx := range block.Data.Txs {
@@ -1,10 +1,14 @@
 package core
 
 import (
+	"errors"
+	"os"
+
 	cm "github.com/tendermint/tendermint/consensus"
 	cmtmath "github.com/tendermint/tendermint/libs/math"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -106,3 +110,21 @@ func ConsensusParams(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultCon
 		BlockHeight:     height,
 		ConsensusParams: consensusParams}, nil
 }
+
+// AppHashMismatch returns the diagnostic report collected the last time the
+// app hash computed by the application diverged from the one in a committed
+// block, which halted the node. The report is nil if it never happened.
+func AppHashMismatch(ctx *rpctypes.Context) (*ctypes.ResultAppHashMismatch, error) {
+	env := GetEnvironment()
+	if env.DiagnosticsDir == "" {
+		return nil, errors.New("app hash mismatch reports are disabled")
+	}
+	report, err := sm.LoadAppHashMismatchReport(env.DiagnosticsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ctypes.ResultAppHashMismatch{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultAppHashMismatch{Report: report}, nil
+}
@@ -1,8 +1,21 @@
 package core
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmtflags "github.com/tendermint/tendermint/libs/cli/flags"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/store"
+	"github.com/tendermint/tendermint/types"
 )
 
 // UnsafeFlushMempool removes all transactions from the mempool.
@@ -10,3 +23,289 @@ func UnsafeFlushMempool(ctx *rpctypes.Context) (*ctypes.ResultUnsafeFlushMempool
 	GetEnvironment().Mempool.Flush()
 	return &ctypes.ResultUnsafeFlushMempool{}, nil
 }
+
+// UnsafeMempoolGossipState returns, for a page of the txs known to the CAT
+// mempool reactor sorted by key, whether the node has the tx, the peers which
+// claimed to have it and the peer it is requested from, if any. It is meant
+// to diagnose the gossip of txs.
+func UnsafeMempoolGossipState(
+	ctx *rpctypes.Context,
+	pagePtr, perPagePtr *int,
+) (*ctypes.ResultMempoolGossipState, error) {
+	env := GetEnvironment()
+	if env.MempoolGossip == nil {
+		return nil, errors.New("the gossip state is only available with the CAT mempool (v2)")
+	}
+
+	perPage := validatePerPage(perPagePtr)
+	skipCount := 0
+	if pagePtr != nil {
+		skipCount = validateSkipCount(*pagePtr, perPage)
+	}
+	states, total := env.MempoolGossip.GossipState(skipCount, perPage)
+	if _, err := validatePage(pagePtr, perPage, total); err != nil {
+		return nil, err
+	}
+
+	txs := make([]ctypes.MempoolTxGossipState, len(states))
+	for i, state := range states {
+		txs[i] = ctypes.MempoolTxGossipState{
+			Key:           state.Key[:],
+			InMempool:     state.InMempool,
+			SeenBy:        state.SeenBy,
+			RequestedFrom: state.RequestedFrom,
+		}
+	}
+	return &ctypes.ResultMempoolGossipState{Count: len(txs), Total: total, Txs: txs}, nil
+}
+
+// UnsafeBlockByHash gets a block by hash from the blocks seen as complete
+// proposals, including blocks that were never committed. It requires
+// storage.seen_blocks_heights to be enabled. If the block is not found, a nil
+// block is returned.
+func UnsafeBlockByHash(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultBlock, error) {
+	env := GetEnvironment()
+	if env.SeenBlockStore == nil {
+		return nil, errors.New("seen block store is disabled, enable it with storage.seen_blocks_heights")
+	}
+	block := env.SeenBlockStore.LoadBlockByHash(hash)
+	if block == nil {
+		return &ctypes.ResultBlock{BlockID: types.BlockID{}, Block: nil}, nil
+	}
+	partSet := block.MakePartSet(types.BlockPartSizeBytes)
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: partSet.Header()}
+	return &ctypes.ResultBlock{BlockID: blockID, Block: block}, nil
+}
+
+// UnsafeVerifyBlockStore verifies the integrity of the blocks stored between
+// the heights from and to (inclusive), checking every step-th height. The
+// range defaults to the full block store and the step to 1. Corrupt heights
+// are reported in the result, so that the operator can decide whether to
+// resync the node.
+func UnsafeVerifyBlockStore(ctx *rpctypes.Context, from, to, step int64) (*ctypes.ResultVerifyBlockStore, error) {
+	verifier, ok := GetEnvironment().BlockStore.(interface {
+		Verify(from, to, step int64) store.VerificationResult
+	})
+	if !ok {
+		return nil, errors.New("block store does not support verification")
+	}
+	result := verifier.Verify(from, to, step)
+	corrupt := make([]ctypes.CorruptBlock, len(result.Corrupt))
+	for i, c := range result.Corrupt {
+		corrupt[i] = ctypes.CorruptBlock{Height: c.Height, Reason: c.Err.Error()}
+	}
+	return &ctypes.ResultVerifyBlockStore{
+		From:    result.From,
+		To:      result.To,
+		Checked: result.Checked,
+		Corrupt: corrupt,
+	}, nil
+}
+
+// UnsafeTraceTables returns the trace tables being collected.
+func UnsafeTraceTables(ctx *rpctypes.Context) (*ctypes.ResultTraceTables, error) {
+	toggler, err := traceTables()
+	if err != nil {
+		return nil, err
+	}
+	return traceTablesResult(toggler), nil
+}
+
+// UnsafeEnableTraceTable starts collecting a trace table, without restarting
+// the node. If duration, e.g. "10m", is set, the table is disabled again once
+// it elapsed. It returns the trace tables being collected.
+func UnsafeEnableTraceTable(ctx *rpctypes.Context, table, duration string) (*ctypes.ResultTraceTables, error) {
+	toggler, err := traceTables()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTraceTable(table); err != nil {
+		return nil, err
+	}
+	var d time.Duration
+	if duration != "" {
+		d, err = time.ParseDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration: %w", err)
+		}
+		if d <= 0 {
+			return nil, errors.New("duration must be positive")
+		}
+	}
+	toggler.EnableTable(table, d)
+	return traceTablesResult(toggler), nil
+}
+
+// UnsafeDisableTraceTable stops collecting a trace table, without restarting
+// the node. It returns the trace tables being collected.
+func UnsafeDisableTraceTable(ctx *rpctypes.Context, table string) (*ctypes.ResultTraceTables, error) {
+	toggler, err := traceTables()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTraceTable(table); err != nil {
+		return nil, err
+	}
+	toggler.DisableTable(table)
+	return traceTablesResult(toggler), nil
+}
+
+func traceTables() (trace.TableToggler, error) {
+	toggler := GetEnvironment().TraceTables
+	if toggler == nil {
+		return nil, errors.New("trace tables can't be toggled, enable a tracer with instrumentation.trace_type")
+	}
+	return toggler, nil
+}
+
+func validateTraceTable(table string) error {
+	for _, t := range schema.AllTables() {
+		if t == table {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown trace table %q", table)
+}
+
+func traceTablesResult(toggler trace.TableToggler) *ctypes.ResultTraceTables {
+	enabled := toggler.EnabledTables()
+	tables := make([]ctypes.TraceTable, len(enabled))
+	for i, status := range enabled {
+		tables[i] = ctypes.TraceTable{Table: status.Table}
+		if !status.DisableAt.IsZero() {
+			disableAt := status.DisableAt
+			tables[i].DisableAt = &disableAt
+		}
+	}
+	return &ctypes.ResultTraceTables{Tables: tables}
+}
+
+// UnsafeProfiling returns the state of the pprof server and of the pyroscope
+// continuous profiling client.
+func UnsafeProfiling(ctx *rpctypes.Context) (*ctypes.ResultProfiling, error) {
+	p, err := getProfiler()
+	if err != nil {
+		return nil, err
+	}
+	return profilingResult(p), nil
+}
+
+// UnsafeEnablePprof serves the pprof handlers on address, or on
+// rpc.pprof_laddr if empty, without restarting the node. If they are served
+// on another address, they are moved to address. It returns the state of the
+// profilers.
+func UnsafeEnablePprof(ctx *rpctypes.Context, address string) (*ctypes.ResultProfiling, error) {
+	p, err := getProfiler()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.ServePprof(address); err != nil {
+		return nil, err
+	}
+	return profilingResult(p), nil
+}
+
+// UnsafeDisablePprof stops serving the pprof handlers. It returns the state of
+// the profilers.
+func UnsafeDisablePprof(ctx *rpctypes.Context) (*ctypes.ResultProfiling, error) {
+	p, err := getProfiler()
+	if err != nil {
+		return nil, err
+	}
+	p.StopPprof()
+	return profilingResult(p), nil
+}
+
+// UnsafeEnablePyroscope starts the pyroscope continuous profiling client,
+// without restarting the node. The url and the comma separated profile types,
+// e.g. "cpu,goroutines", default to instrumentation.pyroscope_url and
+// instrumentation.pyroscope_profile_types. If the client runs with another
+// configuration, it is restarted. It returns the state of the profilers.
+func UnsafeEnablePyroscope(ctx *rpctypes.Context, url, profileTypes string) (*ctypes.ResultProfiling, error) {
+	p, err := getProfiler()
+	if err != nil {
+		return nil, err
+	}
+	var types []string
+	for _, t := range strings.Split(profileTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	if err := p.StartPyroscope(url, types); err != nil {
+		return nil, err
+	}
+	return profilingResult(p), nil
+}
+
+// UnsafeDisablePyroscope stops the pyroscope continuous profiling client. No
+// profile is uploaded once it returns. It returns the state of the profilers.
+func UnsafeDisablePyroscope(ctx *rpctypes.Context) (*ctypes.ResultProfiling, error) {
+	p, err := getProfiler()
+	if err != nil {
+		return nil, err
+	}
+	p.StopPyroscope()
+	return profilingResult(p), nil
+}
+
+func getProfiler() (profiler, error) {
+	p := GetEnvironment().Profiler
+	if p == nil {
+		return nil, errors.New("profiling can't be toggled")
+	}
+	return p, nil
+}
+
+func profilingResult(p profiler) *ctypes.ResultProfiling {
+	url, profileTypes := p.Pyroscope()
+	return &ctypes.ResultProfiling{
+		PprofAddress:          p.PprofAddress(),
+		PyroscopeURL:          url,
+		PyroscopeProfileTypes: profileTypes,
+	}
+}
+
+// UnsafeLogLevel returns the default log level and the level of every module
+// known to the logger.
+func UnsafeLogLevel(ctx *rpctypes.Context) (*ctypes.ResultLogLevel, error) {
+	filter, err := logLevels()
+	if err != nil {
+		return nil, err
+	}
+	return logLevelResult(filter), nil
+}
+
+// UnsafeSetLogLevel replaces the log levels of the running logger, without
+// restarting the node. The level has the format of the log_level option, e.g.
+// "consensus:debug,*:info", and replaces every level previously set. It
+// returns the new levels.
+func UnsafeSetLogLevel(ctx *rpctypes.Context, level string) (*ctypes.ResultLogLevel, error) {
+	filter, err := logLevels()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmtflags.SetLogLevel(filter, level, cfg.DefaultLogLevel); err != nil {
+		return nil, err
+	}
+	GetEnvironment().Logger.Info("Changed the log level", "level", level)
+	return logLevelResult(filter), nil
+}
+
+func logLevels() (log.LevelFilter, error) {
+	filter := GetEnvironment().LogLevels
+	if filter == nil {
+		return nil, errors.New("the log level can't be changed")
+	}
+	return filter, nil
+}
+
+func logLevelResult(filter log.LevelFilter) *ctypes.ResultLogLevel {
+	levels := filter.Levels()
+	modules := make([]ctypes.ModuleLogLevel, 0, len(levels.Modules))
+	for module, level := range levels.Modules {
+		modules = append(modules, ctypes.ModuleLogLevel{Module: module, Level: level})
+	}
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Module < modules[j].Module })
+	return &ctypes.ResultLogLevel{Default: levels.Default, Modules: modules}
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	mempl "github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/pkg/trace"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestUnsafeToggleTraceTables(t *testing.T) {
+	env := &Environment{}
+	SetEnvironment(env)
+	_, err := UnsafeTraceTables(&rpctypes.Context{})
+	require.Error(t, err)
+
+	config := cfg.ResetTestRoot("rpc_trace_tables_test")
+	config.Instrumentation.TraceType = "file"
+	config.Instrumentation.TracingTables = schema.RoundStateTable
+	tracer, err := trace.NewTracer(config, log.TestingLogger(), "test_chain", "test_node")
+	require.NoError(t, err)
+	t.Cleanup(tracer.Stop)
+	env.TraceTables = tracer.(trace.TableToggler)
+
+	res, err := UnsafeTraceTables(&rpctypes.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{schema.RoundStateTable}, traceTableNames(res.Tables))
+
+	_, err = UnsafeEnableTraceTable(&rpctypes.Context{}, "unknown", "")
+	require.Error(t, err)
+	_, err = UnsafeEnableTraceTable(&rpctypes.Context{}, schema.BlockTable, "soon")
+	require.Error(t, err)
+
+	res, err = UnsafeEnableTraceTable(&rpctypes.Context{}, schema.BlockTable, "1h")
+	require.NoError(t, err)
+	assert.True(t, tracer.IsCollecting(schema.BlockTable))
+	require.Equal(t, []string{schema.BlockTable, schema.RoundStateTable}, traceTableNames(res.Tables))
+	require.NotNil(t, res.Tables[0].DisableAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *res.Tables[0].DisableAt, time.Minute)
+	assert.Nil(t, res.Tables[1].DisableAt)
+
+	res, err = UnsafeDisableTraceTable(&rpctypes.Context{}, schema.RoundStateTable)
+	require.NoError(t, err)
+	assert.False(t, tracer.IsCollecting(schema.RoundStateTable))
+	assert.Equal(t, []string{schema.BlockTable}, traceTableNames(res.Tables))
+}
+
+func TestUnsafeMempoolGossipState(t *testing.T) {
+	env := &Environment{}
+	SetEnvironment(env)
+	_, err := UnsafeMempoolGossipState(&rpctypes.Context{}, nil, nil)
+	require.Error(t, err, "the gossip state requires the CAT mempool")
+
+	gossip := make(fakeMempoolGossip, 35)
+	for i := range gossip {
+		gossip[i] = mempl.TxGossipState{
+			Key:       types.Tx(fmt.Sprintf("tx%d", i)).Key(),
+			InMempool: i%2 == 0,
+			SeenBy:    []p2p.ID{"peer"},
+		}
+	}
+	gossip[0].RequestedFrom = "other"
+	env.MempoolGossip = gossip
+
+	res, err := UnsafeMempoolGossipState(&rpctypes.Context{}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 35, res.Total)
+	require.Equal(t, defaultPerPage, res.Count)
+	assert.EqualValues(t, gossip[0].Key[:], res.Txs[0].Key)
+	assert.True(t, res.Txs[0].InMempool)
+	assert.Equal(t, []p2p.ID{"peer"}, res.Txs[0].SeenBy)
+	assert.Equal(t, p2p.ID("other"), res.Txs[0].RequestedFrom)
+
+	page, perPage := 4, 10
+	res, err = UnsafeMempoolGossipState(&rpctypes.Context{}, &page, &perPage)
+	require.NoError(t, err)
+	require.Equal(t, 5, res.Count)
+	assert.EqualValues(t, gossip[30].Key[:], res.Txs[0].Key)
+
+	page = 5
+	_, err = UnsafeMempoolGossipState(&rpctypes.Context{}, &page, &perPage)
+	require.Error(t, err)
+}
+
+// fakeMempoolGossip serves its gossip states, in order.
+type fakeMempoolGossip []mempl.TxGossipState
+
+func (g fakeMempoolGossip) GossipState(offset, limit int) ([]mempl.TxGossipState, int) {
+	if offset > len(g) {
+		offset = len(g)
+	}
+	end := offset + limit
+	if end > len(g) {
+		end = len(g)
+	}
+	return g[offset:end], len(g)
+}
+
+func traceTableNames(tables []ctypes.TraceTable) []string {
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = table.Table
+	}
+	return names
+}
+
+// stubProfiler records the profiling state it is set to.
+type stubProfiler struct {
+	pprofAddr    string
+	url          string
+	profileTypes []string
+}
+
+func (p *stubProfiler) PprofAddress() string { return p.pprofAddr }
+
+func (p *stubProfiler) ServePprof(addr string) error {
+	p.pprofAddr = addr
+	return nil
+}
+
+func (p *stubProfiler) StopPprof() { p.pprofAddr = "" }
+
+func (p *stubProfiler) Pyroscope() (string, []string) { return p.url, p.profileTypes }
+
+func (p *stubProfiler) StartPyroscope(url string, profileTypes []string) error {
+	p.url, p.profileTypes = url, profileTypes
+	return nil
+}
+
+func (p *stubProfiler) StopPyroscope() { p.url, p.profileTypes = "", nil }
+
+func TestUnsafeToggleProfiling(t *testing.T) {
+	env := &Environment{}
+	SetEnvironment(env)
+	_, err := UnsafeProfiling(&rpctypes.Context{})
+	require.Error(t, err)
+
+	env.Profiler = &stubProfiler{}
+	res, err := UnsafeEnablePprof(&rpctypes.Context{}, "localhost:6060")
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:6060", res.PprofAddress)
+
+	res, err = UnsafeEnablePyroscope(&rpctypes.Context{}, "http://pyroscope:4040", "cpu, goroutines")
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultProfiling{
+		PprofAddress:          "localhost:6060",
+		PyroscopeURL:          "http://pyroscope:4040",
+		PyroscopeProfileTypes: []string{"cpu", "goroutines"},
+	}, res)
+
+	_, err = UnsafeDisablePprof(&rpctypes.Context{})
+	require.NoError(t, err)
+	res, err = UnsafeDisablePyroscope(&rpctypes.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultProfiling{}, res)
+}
+
+func TestUnsafeSetLogLevel(t *testing.T) {
+	env := &Environment{Logger: log.NewNopLogger()}
+	SetEnvironment(env)
+	_, err := UnsafeLogLevel(&rpctypes.Context{})
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	logger := log.NewFilter(log.NewTMJSONLoggerNoTS(&buf), log.AllowInfo())
+	mempool := logger.With("module", "mempool")
+	env.LogLevels = logger.(log.LevelFilter)
+
+	res, err := UnsafeLogLevel(&rpctypes.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultLogLevel{
+		Default: "info",
+		Modules: []ctypes.ModuleLogLevel{{Module: "mempool", Level: "info"}},
+	}, res)
+
+	mempool.Debug("suppressed")
+	assert.Empty(t, buf.String())
+
+	_, err = UnsafeSetLogLevel(&rpctypes.Context{}, "mempool:verbose")
+	require.Error(t, err)
+	res, err = UnsafeSetLogLevel(&rpctypes.Context{}, "consensus:error,mempool:debug")
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultLogLevel{
+		Default: "info",
+		Modules: []ctypes.ModuleLogLevel{
+			{Module: "consensus", Level: "error"},
+			{Module: "mempool", Level: "debug"},
+		},
+	}, res)
+
+	mempool.Debug("shown")
+	assert.Contains(t, buf.String(), "shown")
+}
@@ -38,6 +38,7 @@ Endpoints that require arguments:
 /dial_seeds?seeds=_
 /dial_persistent_peers?persistent_peers=_
 /subscribe?event=_
+/unsafe_block_by_hash?hash=_
 /tx?hash=_&prove=_
 /unsubscribe?event=_
 ```
@@ -6,17 +6,22 @@ import (
 	"sync"
 	"time"
 
+	bcv0 "github.com/tendermint/tendermint/blockchain/v0"
 	cfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/consensus"
 	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/evidence"
+	"github.com/tendermint/tendermint/libs/heartbeat"
 	cmtjson "github.com/tendermint/tendermint/libs/json"
 	"github.com/tendermint/tendermint/libs/log"
 	mempl "github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/pkg/trace"
 	"github.com/tendermint/tendermint/proxy"
 	sm "github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/state/indexer"
 	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -72,6 +77,15 @@ type transport interface {
 	NodeInfo() p2p.NodeInfo
 }
 
+type evidencePool interface {
+	sm.EvidencePool
+	EvidenceStatus(hash []byte) (evidence.Status, error)
+}
+
+type fastSyncReactor interface {
+	Progress() bcv0.Progress
+}
+
 type peers interface {
 	AddPersistentPeers([]string) error
 	AddUnconditionalPeerIDs([]string) error
@@ -80,6 +94,27 @@ type peers interface {
 	Peers() p2p.IPeerSet
 }
 
+type mempoolGossip interface {
+	GossipState(offset, limit int) ([]mempl.TxGossipState, int)
+}
+
+type mempoolTxStatus interface {
+	TxStatus(key types.TxKey) mempl.TxStatus
+}
+
+type indexerService interface {
+	Err() error
+}
+
+type profiler interface {
+	PprofAddress() string
+	ServePprof(addr string) error
+	StopPprof()
+	Pyroscope() (url string, profileTypes []string)
+	StartPyroscope(url string, profileTypes []string) error
+	StopPyroscope()
+}
+
 // ----------------------------------------------
 // Environment contains objects and interfaces used by the RPC. It is expected
 // to be setup once during startup.
@@ -91,7 +126,10 @@ type Environment struct {
 	// interfaces defined in types and above
 	StateStore     sm.Store
 	BlockStore     sm.BlockStore
-	EvidencePool   sm.EvidencePool
+	SeenBlockStore *store.SeenBlockStore       // nil unless enabled
+	Pruner         *store.Pruner               // reports the effective retain height
+	Recomputer     *sm.ABCIResponsesRecomputer // nil unless enabled
+	EvidencePool   evidencePool
 	ConsensusState Consensus
 	P2PPeers       peers
 	P2PTransport   transport
@@ -101,14 +139,24 @@ type Environment struct {
 	GenDoc           *types.GenesisDoc // cache the genesis structure
 	TxIndexer        txindex.TxIndexer
 	BlockIndexer     indexer.BlockIndexer
+	IndexerService   indexerService // reports the indexer stopping, checked by health
 	ConsensusReactor *consensus.Reactor
+	FastSyncReactor  fastSyncReactor // nil unless the v0 fast sync reactor is used
 	EventBus         *types.EventBus // thread safe
 	Mempool          mempl.Mempool
+	MempoolGossip    mempoolGossip      // nil unless the CAT mempool is used
+	TraceTables      trace.TableToggler // nil unless the tracer supports it
+	Profiler         profiler
+	LogLevels        log.LevelFilter                 // nil unless the logger filters by level
+	Heartbeats       map[string]*heartbeat.Heartbeat // checked by /livez, by goroutine name
 
 	Logger log.Logger
 
 	Config cfg.RPCConfig
 
+	// DiagnosticsDir is where app hash mismatch reports are written.
+	DiagnosticsDir string
+
 	// cache of chunked genesis data.
 	genChunks []string
 }
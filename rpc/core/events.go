@@ -42,7 +42,15 @@ func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, er
 	subCtx, cancel := context.WithTimeout(ctx.Context(), SubscribeTimeout)
 	defer cancel()
 
-	sub, err := env.EventBus.Subscribe(subCtx, addr, q, env.Config.SubscriptionBufferSize)
+	overflow, err := cmtpubsub.ParseOverflowPolicy(env.Config.SubscriptionOverflowPolicy)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := env.EventBus.SubscribeBounded(subCtx, addr, q, cmtpubsub.BufferConfig{
+		Capacity:   env.Config.SubscriptionBufferSize,
+		Overflow:   overflow,
+		Subscriber: "websocket",
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -56,8 +64,13 @@ func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, er
 			select {
 			case msg := <-sub.Out():
 				var (
-					resultEvent = &ctypes.ResultEvent{Query: query, Data: msg.Data(), Events: msg.Events()}
-					resp        = rpctypes.NewRPCSuccessResponse(subscriptionID, resultEvent)
+					resultEvent = &ctypes.ResultEvent{
+						Query:   query,
+						Data:    msg.Data(),
+						Events:  msg.Events(),
+						Dropped: msg.Dropped(),
+					}
+					resp = rpctypes.NewRPCSuccessResponse(subscriptionID, resultEvent)
 				)
 				writeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 				defer cancel()
@@ -4,24 +4,100 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/tendermint/tendermint/evidence"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 	"github.com/tendermint/tendermint/types"
 )
 
-// BroadcastEvidence broadcasts evidence of the misbehavior.
+// BroadcastEvidence verifies evidence of the misbehavior and adds it to the
+// evidence pool, to be broadcast. Evidence which fails verification is
+// rejected, with the reason in the result.
 // More: https://docs.cometbft.com/v0.34/rpc/#/Info/broadcast_evidence
 func BroadcastEvidence(ctx *rpctypes.Context, ev types.Evidence) (*ctypes.ResultBroadcastEvidence, error) {
 	if ev == nil {
 		return nil, errors.New("no evidence was provided")
 	}
 
+	env := GetEnvironment()
+	result := &ctypes.ResultBroadcastEvidence{LatestHeight: env.BlockStore.Height()}
 	if err := ev.ValidateBasic(); err != nil {
-		return nil, fmt.Errorf("evidence.ValidateBasic failed: %w", err)
+		return rejectEvidence(result, ctypes.EvidenceRejectedMalformed,
+			fmt.Errorf("evidence.ValidateBasic failed: %w", err)), nil
 	}
+	result.Hash, result.Height = ev.Hash(), ev.Height()
 
-	if err := GetEnvironment().EvidencePool.AddEvidence(ev); err != nil {
-		return nil, fmt.Errorf("failed to add evidence: %w", err)
+	status, err := env.EvidencePool.EvidenceStatus(result.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up evidence: %w", err)
 	}
-	return &ctypes.ResultBroadcastEvidence{Hash: ev.Hash()}, nil
+	switch status.Status {
+	case evidence.StatusCommitted:
+		result.CommittedHeight = status.CommittedHeight
+		return rejectEvidence(result, ctypes.EvidenceRejectedCommitted,
+			errors.New("evidence was already committed")), nil
+	case evidence.StatusExpired:
+		result.ExpiryHeight = status.ExpiryHeight
+		return rejectEvidence(result, ctypes.EvidenceRejectedExpired,
+			errors.New("pending evidence expired without being committed")), nil
+	}
+
+	if err := env.EvidencePool.AddEvidence(ev); err != nil {
+		var invalidErr *types.ErrInvalidEvidence
+		if !errors.As(err, &invalidErr) {
+			return nil, fmt.Errorf("failed to add evidence: %w", err)
+		}
+
+		var (
+			reason           = ctypes.EvidenceRejectedInvalid
+			expiredErr       evidence.ErrEvidenceExpired
+			unknownHeightErr evidence.ErrUnknownHeight
+			unknownValErr    evidence.ErrUnknownValidator
+		)
+		switch {
+		case errors.As(err, &expiredErr):
+			reason = ctypes.EvidenceRejectedExpired
+			result.MinHeight = expiredErr.MinHeight
+		case errors.As(err, &unknownHeightErr):
+			reason = ctypes.EvidenceRejectedUnknownHeight
+		case errors.As(err, &unknownValErr):
+			reason = ctypes.EvidenceRejectedUnknownValidator
+		}
+		return rejectEvidence(result, reason, invalidErr.Reason), nil
+	}
+
+	if status, err = env.EvidencePool.EvidenceStatus(result.Hash); err != nil {
+		return nil, fmt.Errorf("failed to look up evidence: %w", err)
+	}
+	result.Accepted = true
+	result.ExpiryHeight = status.ExpiryHeight
+	return result, nil
+}
+
+// Evidence returns the status of evidence by its hash: whether it's pending,
+// committed or expired. Evidence which the node never received, or which
+// expired and was pruned, is unknown.
+// More: https://docs.cometbft.com/v0.34/rpc/#/Info/evidence
+func Evidence(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultEvidence, error) {
+	status, err := GetEnvironment().EvidencePool.EvidenceStatus(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up evidence: %w", err)
+	}
+	return &ctypes.ResultEvidence{
+		Hash:            hash,
+		Status:          status.Status,
+		Height:          status.Height,
+		CommittedHeight: status.CommittedHeight,
+		ExpiryHeight:    status.ExpiryHeight,
+		ExpiryTime:      status.ExpiryTime,
+	}, nil
+}
+
+func rejectEvidence(
+	result *ctypes.ResultBroadcastEvidence,
+	reason string,
+	err error,
+) *ctypes.ResultBroadcastEvidence {
+	result.Reason, result.Error = reason, err.Error()
+	return result
 }
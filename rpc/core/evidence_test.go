@@ -0,0 +1,197 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/evidence"
+	"github.com/tendermint/tendermint/rpc/core"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/mocks"
+	"github.com/tendermint/tendermint/types"
+)
+
+const evidenceChainID = "evidence-chain"
+
+func TestBroadcastEvidence(t *testing.T) {
+	const (
+		latestHeight = int64(10)
+		maxAgeBlocks = int64(5)
+		futureHeight = latestHeight + 10
+	)
+	var (
+		evTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		val    = types.NewMockPV()
+		other  = types.NewMockPV()
+		pubKey = val.PrivKey.PubKey()
+		valSet = types.NewValidatorSet([]*types.Validator{types.NewValidator(pubKey, 10)})
+		params = types.DefaultConsensusParams()
+		rpcCtx = &rpctypes.Context{}
+		newEv  = func(height int64, pv types.PrivValidator) *types.DuplicateVoteEvidence {
+			return types.NewMockDuplicateVoteEvidenceWithValidator(height, evTime, pv, evidenceChainID)
+		}
+	)
+	params.Evidence.MaxAgeNumBlocks = maxAgeBlocks
+	params.Evidence.MaxAgeDuration = time.Hour
+
+	// all the blocks are at the time of the evidence, two hours before the
+	// latest one, so that evidence older than maxAgeBlocks is expired
+	state := sm.State{
+		ChainID:         evidenceChainID,
+		LastBlockHeight: latestHeight,
+		LastBlockTime:   evTime.Add(2 * time.Hour),
+		ConsensusParams: *params,
+		Validators:      valSet,
+		LastValidators:  valSet,
+	}
+	stateStore := &mocks.Store{}
+	stateStore.On("Load").Return(state, nil)
+	stateStore.On("LoadValidators", mock.AnythingOfType("int64")).Return(valSet, nil)
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(latestHeight)
+	blockStore.On("LoadBlockMeta", futureHeight).Return(nil)
+	blockStore.On("LoadBlockMeta", mock.AnythingOfType("int64")).Return(
+		&types.BlockMeta{Header: types.Header{Time: evTime}})
+
+	pool, err := evidence.NewPool(dbm.NewMemDB(), stateStore, blockStore)
+	require.NoError(t, err)
+	core.SetEnvironment(&core.Environment{EvidencePool: pool, BlockStore: blockStore})
+
+	validEv := newEv(8, val)
+	committedEv := newEv(9, val)
+	invalidEv := newEv(8, val)
+	invalidEv.TotalVotingPower = 20
+
+	_, err = core.BroadcastEvidence(rpcCtx, committedEv)
+	require.NoError(t, err)
+	state.LastBlockHeight++
+	pool.Update(state, types.EvidenceList{committedEv})
+
+	testCases := []struct {
+		name     string
+		ev       types.Evidence
+		expected ctypes.ResultBroadcastEvidence
+	}{
+		{"valid", validEv, ctypes.ResultBroadcastEvidence{
+			Accepted:     true,
+			Height:       8,
+			ExpiryHeight: 8 + maxAgeBlocks + 1,
+		}},
+		{"already pending", validEv, ctypes.ResultBroadcastEvidence{
+			Accepted:     true,
+			Height:       8,
+			ExpiryHeight: 8 + maxAgeBlocks + 1,
+		}},
+		{"malformed", &types.DuplicateVoteEvidence{}, ctypes.ResultBroadcastEvidence{
+			Reason: ctypes.EvidenceRejectedMalformed,
+		}},
+		{"already committed", committedEv, ctypes.ResultBroadcastEvidence{
+			Reason:          ctypes.EvidenceRejectedCommitted,
+			Height:          9,
+			CommittedHeight: latestHeight + 1,
+		}},
+		{"expired", newEv(3, val), ctypes.ResultBroadcastEvidence{
+			Reason:    ctypes.EvidenceRejectedExpired,
+			Height:    3,
+			MinHeight: latestHeight + 1 - maxAgeBlocks,
+		}},
+		{"unknown height", newEv(futureHeight, val), ctypes.ResultBroadcastEvidence{
+			Reason: ctypes.EvidenceRejectedUnknownHeight,
+			Height: futureHeight,
+		}},
+		{"unknown validator", newEv(8, other), ctypes.ResultBroadcastEvidence{
+			Reason: ctypes.EvidenceRejectedUnknownValidator,
+			Height: 8,
+		}},
+		{"invalid", invalidEv, ctypes.ResultBroadcastEvidence{
+			Reason: ctypes.EvidenceRejectedInvalid,
+			Height: 8,
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := core.BroadcastEvidence(rpcCtx, tc.ev)
+			require.NoError(t, err)
+
+			if tc.expected.Reason == "" {
+				assert.Empty(t, result.Error)
+			} else {
+				assert.NotEmpty(t, result.Error)
+			}
+			if tc.expected.Reason != ctypes.EvidenceRejectedMalformed {
+				assert.EqualValues(t, tc.ev.Hash(), result.Hash)
+			}
+			tc.expected.Hash, tc.expected.Error = result.Hash, result.Error
+			tc.expected.LatestHeight = latestHeight
+			assert.Equal(t, tc.expected, *result)
+		})
+	}
+
+	_, err = core.BroadcastEvidence(rpcCtx, nil)
+	assert.Error(t, err)
+}
+
+func TestEvidence(t *testing.T) {
+	const latestHeight = int64(10)
+	var (
+		evTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		val    = types.NewMockPV()
+		valSet = types.NewValidatorSet([]*types.Validator{types.NewValidator(val.PrivKey.PubKey(), 10)})
+		rpcCtx = &rpctypes.Context{}
+	)
+	state := sm.State{
+		ChainID:         evidenceChainID,
+		LastBlockHeight: latestHeight,
+		LastBlockTime:   evTime,
+		ConsensusParams: *types.DefaultConsensusParams(),
+		Validators:      valSet,
+		LastValidators:  valSet,
+	}
+	stateStore := &mocks.Store{}
+	stateStore.On("Load").Return(state, nil)
+	stateStore.On("LoadValidators", mock.AnythingOfType("int64")).Return(valSet, nil)
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(latestHeight)
+	blockStore.On("LoadBlockMeta", mock.AnythingOfType("int64")).Return(
+		&types.BlockMeta{Header: types.Header{Time: evTime}})
+
+	pool, err := evidence.NewPool(dbm.NewMemDB(), stateStore, blockStore)
+	require.NoError(t, err)
+	core.SetEnvironment(&core.Environment{EvidencePool: pool, BlockStore: blockStore})
+
+	ev := types.NewMockDuplicateVoteEvidenceWithValidator(latestHeight, evTime, val, evidenceChainID)
+	result, err := core.BroadcastEvidence(rpcCtx, ev)
+	require.NoError(t, err)
+	require.True(t, result.Accepted)
+
+	status, err := core.Evidence(rpcCtx, ev.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, evidence.StatusPending, status.Status)
+	assert.Equal(t, latestHeight, status.Height)
+	assert.Equal(t, result.ExpiryHeight, status.ExpiryHeight)
+	assert.Equal(t, evTime.Add(state.ConsensusParams.Evidence.MaxAgeDuration).Add(time.Nanosecond), status.ExpiryTime)
+
+	state.LastBlockHeight++
+	pool.Update(state, types.EvidenceList{ev})
+	status, err = core.Evidence(rpcCtx, ev.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, evidence.StatusCommitted, status.Status)
+	assert.Equal(t, latestHeight+1, status.CommittedHeight)
+
+	status, err = core.Evidence(rpcCtx, tmhash.Sum([]byte("unknown")))
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultEvidence{
+		Hash:   tmhash.Sum([]byte("unknown")),
+		Status: evidence.StatusUnknown,
+	}, status)
+}
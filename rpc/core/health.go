@@ -1,13 +1,146 @@
 package core
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	cm "github.com/tendermint/tendermint/consensus"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
 )
 
 // Health gets node health. Returns empty result (200 OK) on success, no
-// response - in case of an error.
+// response - in case of an error. The node is unhealthy if the indexer
+// stopped, e.g. because it fell behind.
 // More: https://docs.cometbft.com/v0.34/rpc/#/Info/health
 func Health(ctx *rpctypes.Context) (*ctypes.ResultHealth, error) {
+	env := GetEnvironment()
+	if env.IndexerService != nil {
+		if err := env.IndexerService.Err(); err != nil {
+			return nil, fmt.Errorf("the indexer stopped: %w", err)
+		}
+	}
 	return &ctypes.ResultHealth{}, nil
 }
+
+// probeCheck is the result of one of the checks of a probe.
+type probeCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// probeResult is the body of the responses of /livez and /readyz.
+type probeResult struct {
+	OK     bool         `json:"ok"`
+	Checks []probeCheck `json:"checks"`
+}
+
+// Livez is the liveness probe of the node: it responds with 200 OK if the
+// core goroutines of the node made progress recently, and 503 Service
+// Unavailable if one of them is stuck. The goroutines not running, e.g. the
+// consensus receive routine during fast sync, aren't checked. The RPC server
+// is live as long as it responds.
+//
+// It is served as a plain HTTP endpoint, for orchestrators like Kubernetes.
+func Livez(w http.ResponseWriter, r *http.Request) {
+	env := GetEnvironment()
+	now := time.Now()
+
+	names := make([]string, 0, len(env.Heartbeats))
+	for name := range env.Heartbeats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checks := make([]probeCheck, 0, len(names))
+	for _, name := range names {
+		last, running := env.Heartbeats[name].Last()
+		switch age := now.Sub(last); {
+		case !running:
+			checks = append(checks, probeCheck{Name: name, OK: true, Detail: "not running"})
+		case env.Config.LivenessMaxHeartbeatAge > 0 && age > env.Config.LivenessMaxHeartbeatAge:
+			checks = append(checks, probeCheck{Name: name, Detail: fmt.Sprintf(
+				"no progress for %v, the maximum is %v", age.Round(time.Millisecond), env.Config.LivenessMaxHeartbeatAge)})
+		default:
+			checks = append(checks, probeCheck{Name: name, OK: true, Detail: fmt.Sprintf(
+				"made progress %v ago", age.Round(time.Millisecond))})
+		}
+	}
+	writeProbeResult(w, checks)
+}
+
+// Readyz is the readiness probe of the node: it responds with 200 OK if the
+// node can serve queries, i.e. it is caught up with its peers and has enough
+// of them, and 503 Service Unavailable if it can't.
+//
+// It is served as a plain HTTP endpoint, for orchestrators like Kubernetes.
+func Readyz(w http.ResponseWriter, r *http.Request) {
+	env := GetEnvironment()
+	config := env.Config
+
+	syncing := env.ConsensusReactor != nil && env.ConsensusReactor.WaitSync()
+	checks := []probeCheck{{Name: "sync", OK: !syncing, Detail: "caught up"}}
+	if syncing {
+		checks[0].Detail = "syncing"
+	}
+
+	peers := env.P2PPeers.Peers().List()
+	checks = append(checks, probeCheck{
+		Name:   "peers",
+		OK:     len(peers) >= config.ReadinessMinPeers,
+		Detail: fmt.Sprintf("%d peers, the minimum is %d", len(peers), config.ReadinessMinPeers),
+	})
+
+	height := env.BlockStore.Height()
+	var maxPeerHeight int64
+	for _, peer := range peers {
+		if peerState, ok := peer.Get(types.PeerStateKey).(*cm.PeerState); ok {
+			// the height a peer is at is the one of the block it is deciding
+			if h := peerState.GetHeight() - 1; h > maxPeerHeight {
+				maxPeerHeight = h
+			}
+		}
+	}
+	lag := maxPeerHeight - height
+	if lag < 0 {
+		lag = 0
+	}
+	checks = append(checks, probeCheck{
+		Name:   "sync_lag",
+		OK:     lag <= config.ReadinessMaxSyncLag,
+		Detail: fmt.Sprintf("%d blocks behind the highest peer, the maximum is %d", lag, config.ReadinessMaxSyncLag),
+	})
+
+	if config.ReadinessMaxBlockAge > 0 {
+		check := probeCheck{Name: "block_age", Detail: "no block"}
+		if meta := env.BlockStore.LoadBlockMeta(height); meta != nil {
+			age := time.Since(meta.Header.Time)
+			check.OK = age <= config.ReadinessMaxBlockAge
+			check.Detail = fmt.Sprintf("the last block is %v old, the maximum is %v",
+				age.Round(time.Millisecond), config.ReadinessMaxBlockAge)
+		}
+		checks = append(checks, check)
+	}
+	writeProbeResult(w, checks)
+}
+
+// writeProbeResult writes the checks of a probe, with the status 200 OK if
+// they all passed, and 503 Service Unavailable otherwise.
+func writeProbeResult(w http.ResponseWriter, checks []probeCheck) {
+	result := probeResult{OK: true, Checks: checks}
+	for _, check := range checks {
+		result.OK = result.OK && check.OK
+	}
+	status := http.StatusOK
+	if !result.OK {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(result)
+}
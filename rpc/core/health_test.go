@@ -0,0 +1,149 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/heartbeat"
+	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	"github.com/tendermint/tendermint/p2p"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/state/mocks"
+	"github.com/tendermint/tendermint/types"
+)
+
+type stubIndexerService struct {
+	err error
+}
+
+func (s *stubIndexerService) Err() error { return s.err }
+
+// TestHealth checks that the node is unhealthy once the indexer stopped.
+func TestHealth(t *testing.T) {
+	indexer := &stubIndexerService{}
+	SetEnvironment(&Environment{IndexerService: indexer})
+
+	_, err := Health(&rpctypes.Context{})
+	require.NoError(t, err)
+
+	indexer.err = cmtpubsub.ErrOutOfCapacity
+	_, err = Health(&rpctypes.Context{})
+	assert.ErrorIs(t, err, cmtpubsub.ErrOutOfCapacity)
+}
+
+// TestLivez checks that /livez fails while the consensus receive routine is
+// stalled, i.e. stops beating, and passes again once it is stopped.
+func TestLivez(t *testing.T) {
+	var consensus, eventBus heartbeat.Heartbeat
+	env := &Environment{
+		Heartbeats: map[string]*heartbeat.Heartbeat{
+			"consensus": &consensus,
+			"event_bus": &eventBus,
+		},
+		Config: *cfg.DefaultRPCConfig(),
+	}
+	env.Config.LivenessMaxHeartbeatAge = 100 * time.Millisecond
+	SetEnvironment(env)
+
+	// nothing is running
+	status, result := probe(t, Livez, "/livez")
+	assert.Equal(t, http.StatusOK, status)
+	require.Len(t, result.Checks, 2)
+	assert.Equal(t, "consensus", result.Checks[0].Name)
+	assert.Equal(t, "event_bus", result.Checks[1].Name)
+
+	consensus.Beat()
+	eventBus.Beat()
+	status, result = probe(t, Livez, "/livez")
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, result.OK)
+
+	// the consensus receive routine is stuck, the event bus isn't
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-time.After(10 * time.Millisecond):
+				eventBus.Beat()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	time.Sleep(200 * time.Millisecond)
+	status, result = probe(t, Livez, "/livez")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.False(t, result.OK)
+	assert.False(t, result.Checks[0].OK)
+	assert.True(t, result.Checks[1].OK)
+
+	// the consensus receive routine exited normally, e.g. to fast sync
+	consensus.Stop()
+	status, _ = probe(t, Livez, "/livez")
+	assert.Equal(t, http.StatusOK, status)
+
+	// the check is disabled
+	consensus.Beat()
+	env.Config.LivenessMaxHeartbeatAge = 0
+	time.Sleep(200 * time.Millisecond)
+	status, _ = probe(t, Livez, "/livez")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestReadyz(t *testing.T) {
+	sw := p2p.MakeSwitch(cfg.DefaultP2PConfig(), 1, "testing", "123.123.123",
+		func(n int, sw *p2p.Switch) *p2p.Switch { return sw })
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(int64(10))
+	blockStore.On("LoadBlockMeta", int64(10)).Return(&types.BlockMeta{
+		Header: types.Header{Height: 10, Time: time.Now().Add(-time.Hour)},
+	})
+	env := &Environment{
+		P2PPeers:   sw,
+		BlockStore: blockStore,
+		Config:     *cfg.DefaultRPCConfig(),
+	}
+	SetEnvironment(env)
+
+	status, result := probe(t, Readyz, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	require.Len(t, result.Checks, 3)
+	assert.Equal(t, probeCheck{Name: "sync", OK: true, Detail: "caught up"}, result.Checks[0])
+	assert.Equal(t, "peers", result.Checks[1].Name)
+	assert.False(t, result.Checks[1].OK)
+	assert.Equal(t, "sync_lag", result.Checks[2].Name)
+	assert.True(t, result.Checks[2].OK)
+
+	env.Config.ReadinessMinPeers = 0
+	status, result = probe(t, Readyz, "/readyz")
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, result.OK)
+
+	env.Config.ReadinessMaxBlockAge = time.Minute
+	status, result = probe(t, Readyz, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	require.Len(t, result.Checks, 4)
+	assert.Equal(t, "block_age", result.Checks[3].Name)
+	assert.False(t, result.Checks[3].OK)
+
+	env.Config.ReadinessMaxBlockAge = 2 * time.Hour
+	status, _ = probe(t, Readyz, "/readyz")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func probe(t *testing.T, handler http.HandlerFunc, path string) (int, probeResult) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var result probeResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	return rec.Code, result
+}
@@ -5,6 +5,7 @@
 package mock_mempool
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -50,6 +51,20 @@ func (mr *MockMempoolMockRecorder) CheckTx(tx, callback, txInfo interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTx", reflect.TypeOf((*MockMempool)(nil).CheckTx), tx, callback, txInfo)
 }
 
+// CheckTxWithContext mocks base method.
+func (m *MockMempool) CheckTxWithContext(ctx context.Context, tx types0.Tx, callback func(*types.Response), txInfo mempool.TxInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckTxWithContext", ctx, tx, callback, txInfo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckTxWithContext indicates an expected call of CheckTxWithContext.
+func (mr *MockMempoolMockRecorder) CheckTxWithContext(ctx, tx, callback, txInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTxWithContext", reflect.TypeOf((*MockMempool)(nil).CheckTxWithContext), ctx, tx, callback, txInfo)
+}
+
 // EnableTxsAvailable mocks base method.
 func (m *MockMempool) EnableTxsAvailable() {
 	m.ctrl.T.Helper()
@@ -39,6 +39,7 @@ var Routes = map[string]*rpc.RPCFunc{
 	"dump_consensus_state":      rpc.NewRPCFunc(DumpConsensusState, ""),
 	"consensus_state":           rpc.NewRPCFunc(ConsensusState, ""),
 	"consensus_params":          rpc.NewRPCFunc(ConsensusParams, "height", rpc.Cacheable("height")),
+	"app_hash_mismatch":         rpc.NewRPCFunc(AppHashMismatch, ""),
 	"unconfirmed_txs":           rpc.NewRPCFunc(UnconfirmedTxs, "limit"),
 	"num_unconfirmed_txs":       rpc.NewRPCFunc(NumUnconfirmedTxs, ""),
 	"tx_status":                 rpc.NewRPCFunc(TxStatus, "hash"),
@@ -54,6 +55,7 @@ var Routes = map[string]*rpc.RPCFunc{
 
 	// evidence API
 	"broadcast_evidence": rpc.NewRPCFunc(BroadcastEvidence, "evidence"),
+	"evidence":           rpc.NewRPCFunc(Evidence, "hash"),
 }
 
 // AddUnsafeRoutes adds unsafe routes.
@@ -62,4 +64,17 @@ func AddUnsafeRoutes() {
 	Routes["dial_seeds"] = rpc.NewRPCFunc(UnsafeDialSeeds, "seeds")
 	Routes["dial_peers"] = rpc.NewRPCFunc(UnsafeDialPeers, "peers,persistent,unconditional,private")
 	Routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(UnsafeFlushMempool, "")
+	Routes["unsafe_mempool_gossip_state"] = rpc.NewRPCFunc(UnsafeMempoolGossipState, "page,per_page")
+	Routes["unsafe_block_by_hash"] = rpc.NewRPCFunc(UnsafeBlockByHash, "hash")
+	Routes["unsafe_verify_block_store"] = rpc.NewRPCFunc(UnsafeVerifyBlockStore, "from,to,step")
+	Routes["unsafe_trace_tables"] = rpc.NewRPCFunc(UnsafeTraceTables, "")
+	Routes["unsafe_enable_trace_table"] = rpc.NewRPCFunc(UnsafeEnableTraceTable, "table,duration")
+	Routes["unsafe_disable_trace_table"] = rpc.NewRPCFunc(UnsafeDisableTraceTable, "table")
+	Routes["unsafe_profiling"] = rpc.NewRPCFunc(UnsafeProfiling, "")
+	Routes["unsafe_enable_pprof"] = rpc.NewRPCFunc(UnsafeEnablePprof, "address")
+	Routes["unsafe_disable_pprof"] = rpc.NewRPCFunc(UnsafeDisablePprof, "")
+	Routes["unsafe_enable_pyroscope"] = rpc.NewRPCFunc(UnsafeEnablePyroscope, "url,profile_types")
+	Routes["unsafe_disable_pyroscope"] = rpc.NewRPCFunc(UnsafeDisablePyroscope, "")
+	Routes["unsafe_log_level"] = rpc.NewRPCFunc(UnsafeLogLevel, "")
+	Routes["unsafe_set_log_level"] = rpc.NewRPCFunc(UnsafeSetLogLevel, "level")
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/tendermint/tendermint/p2p"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -45,6 +46,11 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 		}
 	}
 
+	retainHeight, retainHeightSource := int64(0), store.RetainHeightSourceNone
+	if env.Pruner != nil {
+		retainHeight, retainHeightSource = env.Pruner.EffectiveRetainHeight()
+	}
+
 	// Return the very last voting power, not the voting power of this validator
 	// during the last block.
 	var votingPower int64
@@ -63,6 +69,8 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 			EarliestAppHash:     earliestAppHash,
 			EarliestBlockHeight: earliestBlockHeight,
 			EarliestBlockTime:   time.Unix(0, earliestBlockTimeNano),
+			RetainHeight:        retainHeight,
+			RetainHeightSource:  retainHeightSource,
 			CatchingUp:          env.ConsensusReactor.WaitSync(),
 		},
 		ValidatorInfo: ctypes.ValidatorInfo{
@@ -71,6 +79,19 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 			VotingPower: votingPower,
 		},
 	}
+	if env.FastSyncReactor != nil {
+		progress := env.FastSyncReactor.Progress()
+		result.FastSyncInfo = &ctypes.FastSyncInfo{
+			Syncing:         progress.Syncing,
+			Height:          progress.Height,
+			MaxPeerHeight:   progress.MaxPeerHeight,
+			BlocksPerSecond: progress.BlocksPerSecond,
+			BytesPerSecond:  progress.BytesPerSecond,
+			ActivePeers:     progress.ActivePeers,
+			RequestTimeouts: progress.RequestTimeouts,
+			ETASeconds:      progress.ETA.Seconds(),
+		}
+	}
 
 	return result, nil
 }
@@ -21,6 +21,7 @@ const (
 	TxStatusUnknown   string = "UNKNOWN"
 	TxStatusPending   string = "PENDING"
 	TxStatusEvicted   string = "EVICTED"
+	TxStatusRejected  string = "REJECTED"
 	TxStatusCommitted string = "COMMITTED"
 )
 
@@ -222,7 +223,8 @@ func ProveShares(
 
 // TxStatus retrieves the status of a transaction by its hash. It returns a ResultTxStatus
 // with the transaction's height and index if committed, or its pending, evicted, or unknown status.
-// It also includes the execution code and log for failed txs.
+// It also includes the execution code and log for failed txs. With the CAT mempool, the txs
+// recently rejected are reported as such, with their rejection code and log.
 func TxStatus(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultTxStatus, error) {
 	env := GetEnvironment()
 
@@ -238,6 +240,18 @@ func TxStatus(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultTxStatus, error
 		return nil, fmt.Errorf("failed to get tx key from hash: %v", err)
 	}
 
+	// The CAT mempool also remembers the txs it recently evicted, rejected
+	// or saw committed
+	if mempool, ok := env.Mempool.(mempoolTxStatus); ok {
+		status := mempool.TxStatus(txKey)
+		return &ctypes.ResultTxStatus{
+			Height:        status.Height,
+			ExecutionCode: status.Code,
+			Error:         status.Log,
+			Status:        status.Status.String(),
+		}, nil
+	}
+
 	// Check if the tx is in the mempool
 	txInMempool, ok := env.Mempool.GetTxByKey(txKey)
 	if txInMempool != nil && ok {
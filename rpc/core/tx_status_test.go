@@ -5,6 +5,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	mempl "github.com/tendermint/tendermint/mempool"
 	mock "github.com/tendermint/tendermint/rpc/core/mocks"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 	types "github.com/tendermint/tendermint/types"
@@ -133,3 +134,45 @@ func TestTxStatus(t *testing.T) {
 		})
 	}
 }
+
+// catMempool is a mempool reporting the statuses of the CAT mempool.
+type catMempool struct {
+	*mock.MockMempool
+	statuses map[types.TxKey]mempl.TxStatus
+}
+
+func (m catMempool) TxStatus(key types.TxKey) mempl.TxStatus {
+	return m.statuses[key]
+}
+
+// TestTxStatusCATMempool checks that the status of the txs not in the block
+// store is the one reported by the CAT mempool.
+func TestTxStatusCATMempool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	txs := makeTxs(2)
+	statuses := map[types.TxKey]mempl.TxStatus{
+		txs[0].Key(): {Status: mempl.TxStatusPending, Height: 1},
+		txs[1].Key(): {Status: mempl.TxStatusRejected, Height: 2, Code: 5, Log: "invalid"},
+	}
+	SetEnvironment(&Environment{
+		Mempool:    catMempool{MockMempool: mock.NewMockMempool(ctrl), statuses: statuses},
+		BlockStore: mockBlockStore{},
+	})
+
+	txStatus, err := TxStatus(&rpctypes.Context{}, txs[0].Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, TxStatusPending, txStatus.Status)
+
+	txStatus, err = TxStatus(&rpctypes.Context{}, txs[1].Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, TxStatusRejected, txStatus.Status)
+	assert.EqualValues(t, 2, txStatus.Height)
+	assert.EqualValues(t, 5, txStatus.ExecutionCode)
+	assert.Equal(t, "invalid", txStatus.Error)
+
+	txStatus, err = TxStatus(&rpctypes.Context{}, types.Tx("unknown").Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, TxStatusUnknown, txStatus.Status)
+}
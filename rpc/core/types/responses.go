@@ -114,9 +114,32 @@ type SyncInfo struct {
 	EarliestBlockHeight int64          `json:"earliest_block_height"`
 	EarliestBlockTime   time.Time      `json:"earliest_block_time"`
 
+	// RetainHeight is the height below which blocks are pruned, and
+	// RetainHeightSource what it was derived from: "app", "time" or "none".
+	RetainHeight       int64  `json:"retain_height"`
+	RetainHeightSource string `json:"retain_height_source"`
+
 	CatchingUp bool `json:"catching_up"`
 }
 
+// Info about the progress of fast sync
+type FastSyncInfo struct {
+	Syncing bool `json:"syncing"`
+	// Height is the height of the next block to apply, and MaxPeerHeight the
+	// highest height reported by peers.
+	Height        int64 `json:"height"`
+	MaxPeerHeight int64 `json:"max_peer_height"`
+
+	BlocksPerSecond float64 `json:"blocks_per_second"`
+	BytesPerSecond  float64 `json:"bytes_per_second"`
+	ActivePeers     int     `json:"active_peers"`
+	RequestTimeouts int64   `json:"request_timeouts"`
+
+	// ETASeconds is the estimated time to reach the network tip, or -1 if the
+	// tip isn't being caught up with.
+	ETASeconds float64 `json:"eta_seconds"`
+}
+
 // Info about the node's validator
 type ValidatorInfo struct {
 	Address     bytes.HexBytes `json:"address"`
@@ -129,6 +152,7 @@ type ResultStatus struct {
 	NodeInfo      p2p.DefaultNodeInfo `json:"node_info"`
 	SyncInfo      SyncInfo            `json:"sync_info"`
 	ValidatorInfo ValidatorInfo       `json:"validator_info"`
+	FastSyncInfo  *FastSyncInfo       `json:"fast_sync_info,omitempty"`
 }
 
 // Is TxIndexing enabled
@@ -232,6 +256,18 @@ type ResultTx struct {
 	Proof    types.ShareProof       `json:"proof,omitempty"`
 }
 
+// Result of a transaction whose inclusion in a block was verified by a light
+// client: the header of the block is trusted, and the proof of the
+// transaction is verified against its data root.
+type ResultVerifiedTx struct {
+	Hash   bytes.HexBytes   `json:"hash"`
+	Height int64            `json:"height"`
+	Index  uint32           `json:"index"`
+	Tx     types.Tx         `json:"tx"`
+	Proof  types.ShareProof `json:"proof"`
+	Header types.Header     `json:"header"`
+}
+
 // Result of searching for txs
 type ResultTxSearch struct {
 	Txs        []*ResultTx `json:"txs"`
@@ -262,9 +298,120 @@ type ResultABCIQuery struct {
 	Response abci.ResponseQuery `json:"response"`
 }
 
-// Result of broadcasting evidence
+// Reasons for which broadcast evidence is rejected
+const (
+	// The evidence is malformed.
+	EvidenceRejectedMalformed = "malformed"
+	// The evidence was already committed, at CommittedHeight if known.
+	EvidenceRejectedCommitted = "already_committed"
+	// The evidence is older than MinHeight and the maximum age duration, or
+	// expired while pending, at ExpiryHeight.
+	EvidenceRejectedExpired = "expired"
+	// The node doesn't have the block at the height of the evidence: it's
+	// ahead of LatestHeight, or was pruned.
+	EvidenceRejectedUnknownHeight = "unknown_height"
+	// The validator of the evidence wasn't a validator at its height.
+	EvidenceRejectedUnknownValidator = "unknown_validator"
+	// The evidence failed verification for another reason, such as invalid
+	// signatures.
+	EvidenceRejectedInvalid = "invalid"
+)
+
+// Result of broadcasting evidence: it's either accepted into the evidence
+// pool, or rejected for a reason, one of the EvidenceRejected constants
 type ResultBroadcastEvidence struct {
-	Hash []byte `json:"hash"`
+	Hash     []byte `json:"hash"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	Height       int64 `json:"height,omitempty"`
+	LatestHeight int64 `json:"latest_height"`
+	// ExpiryHeight is the height from which evidence expires if it isn't
+	// committed, once the maximum age duration is also exceeded.
+	ExpiryHeight    int64 `json:"expiry_height,omitempty"`
+	CommittedHeight int64 `json:"committed_height,omitempty"`
+	MinHeight       int64 `json:"min_height,omitempty"`
+}
+
+// Status of evidence in the evidence pool: "pending", "committed" (at
+// CommittedHeight if known), "expired" or "unknown", if the node never
+// received it or already pruned it
+type ResultEvidence struct {
+	Hash            bytes.HexBytes `json:"hash"`
+	Status          string         `json:"status"`
+	Height          int64          `json:"height,omitempty"`
+	CommittedHeight int64          `json:"committed_height,omitempty"`
+	ExpiryHeight    int64          `json:"expiry_height,omitempty"`
+	ExpiryTime      time.Time      `json:"expiry_time,omitempty"`
+}
+
+// Last app hash mismatch report, nil if none
+type ResultAppHashMismatch struct {
+	Report *types.AppHashMismatchReport `json:"report"`
+}
+
+// Result of verifying the block store
+type ResultVerifyBlockStore struct {
+	From    int64          `json:"from"`
+	To      int64          `json:"to"`
+	Checked int64          `json:"checked"`
+	Corrupt []CorruptBlock `json:"corrupt"`
+}
+
+// A height of the block store which failed verification
+type CorruptBlock struct {
+	Height int64  `json:"height"`
+	Reason string `json:"reason"`
+}
+
+// Trace tables enabled at runtime
+type ResultTraceTables struct {
+	Tables []TraceTable `json:"tables"`
+}
+
+// Gossip state of the mempool txs
+type ResultMempoolGossipState struct {
+	Count int                    `json:"count"`
+	Total int                    `json:"total"`
+	Txs   []MempoolTxGossipState `json:"txs"`
+}
+
+// Gossip state of a mempool tx: whether the node has it, the peers which
+// claimed to have it, and the peer it is requested from, if any
+type MempoolTxGossipState struct {
+	Key           bytes.HexBytes `json:"key"`
+	InMempool     bool           `json:"in_mempool"`
+	SeenBy        []p2p.ID       `json:"seen_by"`
+	RequestedFrom p2p.ID         `json:"requested_from,omitempty"`
+}
+
+// A trace table being collected, and the time it is disabled at, if any
+type TraceTable struct {
+	Table     string     `json:"table"`
+	DisableAt *time.Time `json:"disable_at,omitempty"`
+}
+
+// State of the profilers of the node
+type ResultProfiling struct {
+	// empty if the pprof handlers are not served
+	PprofAddress string `json:"pprof_address"`
+	// empty if the pyroscope client is not running
+	PyroscopeURL          string   `json:"pyroscope_url"`
+	PyroscopeProfileTypes []string `json:"pyroscope_profile_types"`
+}
+
+// Log levels of the node
+type ResultLogLevel struct {
+	// level of the modules without one
+	Default string           `json:"default"`
+	Modules []ModuleLogLevel `json:"modules"`
+}
+
+// The log level of a module
+type ModuleLogLevel struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
 }
 
 // empty results
@@ -281,6 +428,9 @@ type ResultEvent struct {
 	Query  string              `json:"query"`
 	Data   types.TMEventData   `json:"data"`
 	Events map[string][]string `json:"events"`
+	// Number of events dropped right before this one, because the
+	// subscription was full
+	Dropped int `json:"dropped,omitempty"`
 }
 
 // ResultShareProof is an API response that contains a ShareProof.
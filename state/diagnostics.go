@@ -0,0 +1,108 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	cmtjson "github.com/tendermint/tendermint/libs/json"
+	"github.com/tendermint/tendermint/libs/tempfile"
+	"github.com/tendermint/tendermint/pkg/trace/schema"
+	"github.com/tendermint/tendermint/types"
+)
+
+// AppHashMismatchReportFile is the name of the file, in the diagnostics
+// directory, holding the last app hash mismatch report.
+const AppHashMismatchReportFile = "app_hash_mismatch.json"
+
+// NewAppHashMismatchReport collects the report for the committed block,
+// whose app hash doesn't match the one in state, from store.
+func NewAppHashMismatchReport(store Store, state State, block *types.Block) *types.AppHashMismatchReport {
+	report := &types.AppHashMismatchReport{
+		Time:            time.Now(),
+		Header:          block.Header,
+		ExpectedAppHash: block.AppHash,
+		ActualAppHash:   state.AppHash,
+		LastCommit:      block.LastCommit,
+	}
+
+	abciResponses, err := store.LoadABCIResponses(state.LastBlockHeight)
+	if errors.Is(err, ErrABCIResponsesNotPersisted) {
+		abciResponses, err = store.LoadLastABCIResponse(state.LastBlockHeight)
+	}
+	if err != nil {
+		report.TxResultsError = err.Error()
+		return report
+	}
+	report.TxResults = make([]types.TxResultSummary, len(abciResponses.DeliverTxs))
+	for i, res := range abciResponses.DeliverTxs {
+		report.TxResults[i] = types.TxResultSummary{
+			Code:      res.GetCode(),
+			Codespace: res.GetCodespace(),
+			GasWanted: res.GetGasWanted(),
+			GasUsed:   res.GetGasUsed(),
+			Events:    len(res.GetEvents()),
+		}
+	}
+	return report
+}
+
+// SaveAppHashMismatchReport writes report to dir, replacing the last one.
+// It returns the path of the file written.
+func SaveAppHashMismatchReport(dir string, report *types.AppHashMismatchReport) (string, error) {
+	bz, err := cmtjson.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, AppHashMismatchReportFile)
+	return path, tempfile.WriteFileAtomic(path, bz, 0o600)
+}
+
+// LoadAppHashMismatchReport loads the last report written to dir. It returns
+// an error wrapping os.ErrNotExist if there is none.
+func LoadAppHashMismatchReport(dir string) (*types.AppHashMismatchReport, error) {
+	bz, err := os.ReadFile(filepath.Join(dir, AppHashMismatchReportFile))
+	if err != nil {
+		return nil, err
+	}
+	report := new(types.AppHashMismatchReport)
+	if err := cmtjson.Unmarshal(bz, report); err != nil {
+		return nil, fmt.Errorf("failed to decode app hash mismatch report: %w", err)
+	}
+	return report, nil
+}
+
+// ReportAppHashMismatch collects, logs and saves the diagnostic report if
+// err, returned by ValidateBlock for a committed block, is an app hash
+// mismatch. It returns the report, or nil for other errors.
+func (blockExec *BlockExecutor) ReportAppHashMismatch(state State, block *types.Block, err error) *types.AppHashMismatchReport {
+	var mismatch ErrAppHashMismatch
+	if !errors.As(err, &mismatch) {
+		return nil
+	}
+
+	report := NewAppHashMismatchReport(blockExec.store, state, block)
+	blockExec.logger.Error("App hash mismatch",
+		"height", block.Height,
+		"expected", report.ExpectedAppHash,
+		"actual", report.ActualAppHash,
+		"txs", len(report.TxResults))
+
+	var path string
+	if blockExec.diagnosticsDir != "" {
+		path, err = SaveAppHashMismatchReport(blockExec.diagnosticsDir, report)
+		if err != nil {
+			blockExec.logger.Error("Failed to save app hash mismatch report", "err", err)
+			path = ""
+		} else {
+			blockExec.logger.Info("Saved app hash mismatch report", "file", path)
+		}
+	}
+	schema.WriteAppHashMismatch(blockExec.traceClient, block.Height, report.ExpectedAppHash, report.ActualAppHash, path)
+	return report
+}
@@ -0,0 +1,103 @@
+package state_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	mmock "github.com/tendermint/tendermint/mempool/mock"
+	"github.com/tendermint/tendermint/proxy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+// divergentApp computes an app hash the network doesn't agree on.
+type divergentApp struct {
+	testApp
+}
+
+func (app *divergentApp) DeliverTx(req abci.RequestDeliverTx) abci.ResponseDeliverTx {
+	return abci.ResponseDeliverTx{
+		Code:      uint32(req.Tx[1] % 2),
+		Codespace: "test",
+		GasWanted: 10,
+		GasUsed:   int64(req.Tx[1]),
+		Events:    make([]abci.Event, req.Tx[1]),
+	}
+}
+
+func (app *divergentApp) Commit() abci.ResponseCommit {
+	return abci.ResponseCommit{Data: []byte("divergent")}
+}
+
+func TestReportAppHashMismatch(t *testing.T) {
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(&divergentApp{}))
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, privVals := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{
+		DiscardABCIResponses: false,
+	})
+	dir := t.TempDir()
+	blockExec := sm.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		mmock.Mempool{}, sm.EmptyEvidencePool{}, sm.BlockExecutorWithDiagnosticsDir(dir))
+
+	// execute block 1, after which the app hash diverges
+	block := makeBlock(state, 1)
+	blockID := types.BlockID{Hash: block.Hash(),
+		PartSetHeader: types.PartSetHeader{Total: 3, Hash: cmtrand.Bytes(32)}}
+	state, _, err := blockExec.ApplyBlock(state, blockID, block, nil)
+	require.NoError(t, err)
+	lastCommit, err := makeValidCommit(1, blockID, state.Validators, privVals)
+	require.NoError(t, err)
+
+	block, _ = state.MakeBlock(2, makeBlock(state, 2).Data, lastCommit, nil,
+		state.Validators.GetProposer().Address)
+	block.AppHash = []byte("network")
+
+	err = blockExec.ValidateBlock(state, block)
+	var mismatch sm.ErrAppHashMismatch
+	require.True(t, errors.As(err, &mismatch), "unexpected error %v", err)
+
+	report := blockExec.ReportAppHashMismatch(state, block, err)
+	require.NotNil(t, report)
+	assert.EqualValues(t, 2, report.Header.Height)
+	assert.EqualValues(t, "network", report.ExpectedAppHash)
+	assert.EqualValues(t, "divergent", report.ActualAppHash)
+	assert.Equal(t, lastCommit, report.LastCommit)
+	assert.Empty(t, report.TxResultsError)
+	require.Len(t, report.TxResults, nTxsPerBlock)
+	for i, res := range report.TxResults {
+		assert.Equal(t, types.TxResultSummary{
+			Code:      uint32(i % 2),
+			Codespace: "test",
+			GasWanted: 10,
+			GasUsed:   int64(i),
+			Events:    i,
+		}, res)
+	}
+
+	// the report is saved
+	saved, err := sm.LoadAppHashMismatchReport(dir)
+	require.NoError(t, err)
+	assert.Equal(t, report.Header.Hash(), saved.Header.Hash())
+	assert.Equal(t, report.ExpectedAppHash, saved.ExpectedAppHash)
+	assert.Equal(t, report.ActualAppHash, saved.ActualAppHash)
+	assert.Equal(t, report.TxResults, saved.TxResults)
+	assert.Equal(t, report.LastCommit.Hash(), saved.LastCommit.Hash())
+
+	// other errors aren't reported
+	assert.Nil(t, blockExec.ReportAppHashMismatch(state, block, errors.New("other")))
+}
+
+func TestLoadAppHashMismatchReportNotFound(t *testing.T) {
+	_, err := sm.LoadAppHashMismatchReport(t.TempDir())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -3,6 +3,8 @@ package state
 import (
 	"errors"
 	"fmt"
+
+	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
 )
 
 type (
@@ -19,6 +21,14 @@ type (
 		Height   int64
 	}
 
+	// ErrAppHashMismatch is returned when validating a block whose app hash
+	// differs from the one computed by the application.
+	ErrAppHashMismatch struct {
+		Height        int64
+		AppHash       cmtbytes.HexBytes // computed by the application
+		HeaderAppHash cmtbytes.HexBytes // in the block header
+	}
+
 	ErrAppBlockHeightTooHigh struct {
 		CoreHeight int64
 		AppHeight  int64
@@ -51,6 +61,16 @@ type (
 	ErrNoABCIResponsesForHeight struct {
 		Height int64
 	}
+
+	ErrStatePruned struct {
+		Height int64
+		Base   int64
+	}
+
+	ErrStoreLayoutMismatch struct {
+		Got  int64
+		Want int64
+	}
 )
 
 func (e ErrUnknownBlock) Error() string {
@@ -66,6 +86,13 @@ func (e ErrBlockHashMismatch) Error() string {
 	)
 }
 
+func (e ErrAppHashMismatch) Error() string {
+	return fmt.Sprintf("wrong Block.Header.AppHash.  Expected %X, got %v",
+		e.AppHash,
+		e.HeaderAppHash,
+	)
+}
+
 func (e ErrAppBlockHeightTooHigh) Error() string {
 	return fmt.Sprintf("app block height (%d) is higher than core (%d)", e.AppHeight, e.CoreHeight)
 }
@@ -103,4 +130,13 @@ func (e ErrNoABCIResponsesForHeight) Error() string {
 	return fmt.Sprintf("could not find results for height #%d", e.Height)
 }
 
+func (e ErrStatePruned) Error() string {
+	return fmt.Sprintf("state at height %d was pruned, the lowest available height is %d", e.Height, e.Base)
+}
+
+func (e ErrStoreLayoutMismatch) Error() string {
+	return fmt.Sprintf("state store uses key layout v%d but v%d is required: stop the node and "+
+		"run the `migrate-state-store` command to convert the store, then restart the node", e.Got, e.Want)
+}
+
 var ErrABCIResponsesNotPersisted = errors.New("node is not persisting abci responses")
@@ -10,6 +10,7 @@ import (
 	"github.com/tendermint/tendermint/libs/fail"
 	"github.com/tendermint/tendermint/libs/log"
 	mempl "github.com/tendermint/tendermint/mempool"
+	"github.com/tendermint/tendermint/pkg/trace"
 	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/proxy"
@@ -43,6 +44,14 @@ type BlockExecutor struct {
 	logger log.Logger
 
 	metrics *Metrics
+
+	// acceptOnProcessProposalTimeout tells whether to accept proposals the
+	// application didn't process before the deadline.
+	acceptOnProcessProposalTimeout bool
+
+	// diagnosticsDir is where app hash mismatch reports are written, if set.
+	diagnosticsDir string
+	traceClient    trace.Tracer
 }
 
 type BlockExecutorOption func(executor *BlockExecutor)
@@ -53,6 +62,31 @@ func BlockExecutorWithMetrics(metrics *Metrics) BlockExecutorOption {
 	}
 }
 
+// BlockExecutorWithAcceptOnProcessProposalTimeout sets whether to accept, or
+// reject, proposals the application didn't process before the deadline.
+// Default: reject.
+func BlockExecutorWithAcceptOnProcessProposalTimeout(accept bool) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.acceptOnProcessProposalTimeout = accept
+	}
+}
+
+// BlockExecutorWithDiagnosticsDir sets the directory app hash mismatch
+// reports are written to. By default, they're only logged.
+func BlockExecutorWithDiagnosticsDir(dir string) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.diagnosticsDir = dir
+	}
+}
+
+// BlockExecutorWithTraceClient sets the tracer app hash mismatches are
+// traced with.
+func BlockExecutorWithTraceClient(traceClient trace.Tracer) BlockExecutorOption {
+	return func(blockExec *BlockExecutor) {
+		blockExec.traceClient = traceClient
+	}
+}
+
 // WithBlockStore optionally stores txInfo
 func WithBlockStore(blockStore BlockStore) BlockExecutorOption {
 	return func(blockExec *BlockExecutor) {
@@ -71,13 +105,14 @@ func NewBlockExecutor(
 	options ...BlockExecutorOption,
 ) *BlockExecutor {
 	res := &BlockExecutor{
-		store:    stateStore,
-		proxyApp: proxyApp,
-		eventBus: types.NopEventBus{},
-		mempool:  mempool,
-		evpool:   evpool,
-		logger:   logger,
-		metrics:  NopMetrics(),
+		store:       stateStore,
+		proxyApp:    proxyApp,
+		eventBus:    types.NopEventBus{},
+		mempool:     mempool,
+		evpool:      evpool,
+		logger:      logger,
+		metrics:     NopMetrics(),
+		traceClient: trace.NoOpTracer(),
 	}
 
 	for _, option := range options {
@@ -108,6 +143,18 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 	state State, commit *types.Commit,
 	proposerAddr []byte,
 ) (*types.Block, *types.PartSet) {
+	return blockExec.CreateProposalBlockWithTimeout(height, state, commit, proposerAddr, 0)
+}
+
+// CreateProposalBlockWithTimeout is like CreateProposalBlock, but if the
+// application doesn't answer PrepareProposal within timeout, it proposes the
+// txs reaped from the mempool as they are. A timeout of 0 waits indefinitely.
+func (blockExec *BlockExecutor) CreateProposalBlockWithTimeout(
+	height int64,
+	state State, commit *types.Commit,
+	proposerAddr []byte,
+	timeout time.Duration,
+) (*types.Block, *types.PartSet) {
 
 	maxBytes := state.ConsensusParams.Block.MaxBytes
 	maxGas := state.ConsensusParams.Block.MaxGas
@@ -125,16 +172,26 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 		timestamp = MedianTime(commit, state.LastValidators)
 	}
 
-	preparedProposal, err := blockExec.proxyApp.PrepareProposalSync(
-		abci.RequestPrepareProposal{
-			BlockData:     &cmtproto.Data{Txs: txs.ToSliceOfBytes()},
-			BlockDataSize: maxDataBytes,
-			ChainId:       state.ChainID,
-			Height:        height,
-			Time:          timestamp,
-		},
-	)
-	if err != nil {
+	start := time.Now()
+	preparedProposal, timedOut, err := callWithTimeout(timeout, func() (*abci.ResponsePrepareProposal, error) {
+		return blockExec.proxyApp.PrepareProposalSync(
+			abci.RequestPrepareProposal{
+				BlockData:     &cmtproto.Data{Txs: txs.ToSliceOfBytes()},
+				BlockDataSize: maxDataBytes,
+				ChainId:       state.ChainID,
+				Height:        height,
+				Time:          timestamp,
+			},
+		)
+	})
+	blockExec.metrics.PrepareProposalLatency.With("outcome", callOutcome(err, timedOut, "ok")).
+		Observe(time.Since(start).Seconds())
+	if timedOut {
+		blockExec.logger.Error("application didn't prepare the proposal in time; proposing the reaped txs",
+			"height", height, "timeout", timeout, "txs", len(txs))
+		blockExec.metrics.ProposalTimeouts.With("method", "prepare_proposal").Add(1)
+		preparedProposal = &abci.ResponsePrepareProposal{BlockData: &cmtproto.Data{Txs: txs.ToSliceOfBytes()}}
+	} else if err != nil {
 		// The App MUST ensure that only valid (and hence 'processable') transactions
 		// enter the mempool. Hence, at this point, we can't have any non-processable
 		// transaction causing an error.
@@ -178,6 +235,16 @@ func (blockExec *BlockExecutor) CreateProposalBlock(
 
 func (blockExec *BlockExecutor) ProcessProposal(
 	block *types.Block,
+) (bool, error) {
+	return blockExec.ProcessProposalWithTimeout(block, 0)
+}
+
+// ProcessProposalWithTimeout is like ProcessProposal, but if the application
+// doesn't answer within timeout, it accepts or rejects the proposal as
+// configured. A timeout of 0 waits indefinitely.
+func (blockExec *BlockExecutor) ProcessProposalWithTimeout(
+	block *types.Block,
+	timeout time.Duration,
 ) (bool, error) {
 	pData := block.Data.ToProto()
 	req := abci.RequestProcessProposal{
@@ -185,7 +252,22 @@ func (blockExec *BlockExecutor) ProcessProposal(
 		Header:    *block.Header.ToProto(),
 	}
 
-	resp, err := blockExec.proxyApp.ProcessProposalSync(req)
+	start := time.Now()
+	resp, timedOut, err := callWithTimeout(timeout, func() (*abci.ResponseProcessProposal, error) {
+		return blockExec.proxyApp.ProcessProposalSync(req)
+	})
+	outcome := "accepted"
+	if resp != nil && !resp.IsOK() {
+		outcome = "rejected"
+	}
+	blockExec.metrics.ProcessProposalLatency.With("outcome", callOutcome(err, timedOut, outcome)).
+		Observe(time.Since(start).Seconds())
+	if timedOut {
+		blockExec.logger.Error("application didn't process the proposal in time",
+			"height", block.Height, "timeout", timeout, "accept", blockExec.acceptOnProcessProposalTimeout)
+		blockExec.metrics.ProposalTimeouts.With("method", "process_proposal").Add(1)
+		return blockExec.acceptOnProcessProposalTimeout, nil
+	}
 	if err != nil {
 		return false, ErrInvalidBlock(err)
 	}
@@ -197,6 +279,47 @@ func (blockExec *BlockExecutor) ProcessProposal(
 	return resp.IsOK(), nil
 }
 
+// callWithTimeout calls fn and waits up to timeout for it to return, or
+// indefinitely if timeout is 0. Calls to the application can't be cancelled,
+// so fn keeps running after timing out.
+func callWithTimeout[T any](timeout time.Duration, fn func() (T, error)) (res T, timedOut bool, err error) {
+	if timeout <= 0 {
+		res, err = fn()
+		return res, false, err
+	}
+
+	type result struct {
+		res T
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		res, err := fn()
+		resCh <- result{res, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r := <-resCh:
+		return r.res, false, r.err
+	case <-timer.C:
+		return res, true, nil
+	}
+}
+
+// callOutcome labels the outcome of a call to the application in metrics.
+func callOutcome(err error, timedOut bool, success string) string {
+	switch {
+	case timedOut:
+		return "timeout"
+	case err != nil:
+		return "error"
+	default:
+		return success
+	}
+}
+
 // ValidateBlock validates the given block against the given state.
 // If the block is invalid, it returns an error.
 // Validation does not mutate state, but does require historical information from the stateDB,
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -256,6 +257,7 @@ func TestBeginBlockByzantineValidators(t *testing.T) {
 	block := makeBlock(state, 1)
 	block.Evidence = types.EvidenceData{Evidence: ev}
 	block.Header.EvidenceHash = block.Evidence.Hash()
+	block.InvalidateHashes()
 	blockID = types.BlockID{Hash: block.Hash(), PartSetHeader: block.MakePartSet(testPartSize).Header()}
 
 	state, retainHeight, err := blockExec.ApplyBlock(state, blockID, block, nil)
@@ -298,6 +300,80 @@ func TestProcessProposal(t *testing.T) {
 	runTest(badTxs, false)
 }
 
+// slowProposalApp sleeps for delay in PrepareProposal and ProcessProposal.
+type slowProposalApp struct {
+	testApp
+	delay time.Duration
+}
+
+func (app *slowProposalApp) PrepareProposal(req abci.RequestPrepareProposal) abci.ResponsePrepareProposal {
+	time.Sleep(app.delay)
+	return abci.ResponsePrepareProposal{BlockData: &cmtproto.Data{}}
+}
+
+func (app *slowProposalApp) ProcessProposal(req abci.RequestProcessProposal) abci.ResponseProcessProposal {
+	time.Sleep(app.delay)
+	return abci.ResponseProcessProposal{Result: abci.ResponseProcessProposal_REJECT}
+}
+
+// txsMempool reaps txs.
+type txsMempool struct {
+	mmock.Mempool
+	txs types.Txs
+}
+
+func (mp txsMempool) ReapMaxBytesMaxGas(_, _ int64) types.Txs { return mp.txs }
+
+func TestCreateProposalBlockTimeout(t *testing.T) {
+	app := &slowProposalApp{delay: 200 * time.Millisecond}
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app))
+	require.NoError(t, proxyApp.Start())
+	defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+	state, stateDB, _ := makeState(1, height)
+	txs := factory.MakeTenTxs(int64(height))
+	metrics := sm.NopMetrics()
+	latency := generic.NewHistogram("latency", 10)
+	metrics.PrepareProposalLatency = latency
+	blockExec := sm.NewBlockExecutor(sm.NewStore(stateDB, sm.StoreOptions{}), log.TestingLogger(),
+		proxyApp.Consensus(), txsMempool{txs: txs}, sm.EmptyEvidencePool{}, sm.BlockExecutorWithMetrics(metrics))
+	commit := types.NewCommit(0, 0, types.BlockID{}, nil)
+
+	// past the deadline, the reaped txs are proposed as they are
+	block, _ := blockExec.CreateProposalBlockWithTimeout(int64(height), state, commit, nil, 20*time.Millisecond)
+	assert.Equal(t, types.Txs(txs), block.Txs)
+	assert.Less(t, latency.Quantile(0.5), 0.2)
+
+	// within the deadline, the txs prepared by the app are proposed
+	block, _ = blockExec.CreateProposalBlockWithTimeout(int64(height), state, commit, nil, time.Second)
+	assert.Empty(t, block.Txs)
+}
+
+func TestProcessProposalTimeout(t *testing.T) {
+	for _, accept := range []bool{true, false} {
+		app := &slowProposalApp{delay: 200 * time.Millisecond}
+		proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app))
+		require.NoError(t, proxyApp.Start())
+		defer proxyApp.Stop() //nolint:errcheck // ignore for tests
+
+		state, stateDB, _ := makeState(1, height)
+		blockExec := sm.NewBlockExecutor(sm.NewStore(stateDB, sm.StoreOptions{}), log.TestingLogger(),
+			proxyApp.Consensus(), mmock.Mempool{}, sm.EmptyEvidencePool{},
+			sm.BlockExecutorWithAcceptOnProcessProposalTimeout(accept))
+		block := makeAcceptedBlock(state, height)
+
+		// past the deadline, the proposal is handled as configured
+		ok, err := blockExec.ProcessProposalWithTimeout(block, 20*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, accept, ok)
+
+		// within the deadline, the app decides
+		ok, err = blockExec.ProcessProposalWithTimeout(block, time.Second)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+}
+
 func TestProcessProposalRejectedMetric(t *testing.T) {
 	server := httptest.NewServer(promhttp.Handler())
 	defer server.Close()
@@ -1,6 +1,10 @@
 package state
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
 	dbm "github.com/cometbft/cometbft-db"
 
 	abci "github.com/tendermint/tendermint/abci/types"
@@ -46,3 +50,49 @@ func SaveValidatorsInfo(db dbm.DB, height, lastHeightChanged int64, valSet *type
 	stateStore := dbStore{db, StoreOptions{DiscardABCIResponses: false}}
 	return stateStore.saveValidatorsInfo(height, lastHeightChanged, valSet)
 }
+
+// ConvertToV1StoreLayout rewrites all keys of the state store in db to the v1
+// layout, exclusively and explicitly for testing the migration.
+func ConvertToV1StoreLayout(db dbm.DB) error {
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	itr, err := db.Iterator(nil, nil)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		key, value := itr.Key(), itr.Value()
+		var v1Key []byte
+		switch {
+		case bytes.Equal(key, layoutVersionKey):
+		case bytes.Equal(key, stateKey):
+			v1Key = v1StateKey
+		case bytes.Equal(key, lastABCIResponseKey):
+			v1Key = v1LastABCIResponseKey
+		default:
+			for _, p := range v1HeightPrefixes {
+				if len(key) == 9 && key[0] == p.v2 {
+					height := int64(binary.BigEndian.Uint64(key[1:]))
+					v1Key = []byte(fmt.Sprintf("%s%d", p.v1, height))
+				}
+			}
+			if v1Key == nil {
+				continue
+			}
+		}
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+		if v1Key != nil {
+			if err := batch.Set(v1Key, value); err != nil {
+				return err
+			}
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	return batch.WriteSync()
+}
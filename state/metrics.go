@@ -21,6 +21,15 @@ type Metrics struct {
 	ProcessProposalRejected metrics.Counter
 	// Count of transactions rejected by application.
 	RejectedTransactions metrics.Counter
+	// Time the application took to answer PrepareProposal, by outcome: ok,
+	// error or timeout.
+	PrepareProposalLatency metrics.Histogram
+	// Time the application took to answer ProcessProposal, by outcome:
+	// accepted, rejected, error or timeout.
+	ProcessProposalLatency metrics.Histogram
+	// Count of PrepareProposal and ProcessProposal calls the application
+	// didn't answer before their deadline, by method.
+	ProposalTimeouts metrics.Counter
 }
 
 // PrometheusMetrics returns Metrics build using Prometheus client library.
@@ -51,6 +60,26 @@ func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
 			Name:      "rejected_transactions",
 			Help:      "Count of transactions rejected by application",
 		}, labels).With(labelsAndValues...),
+		PrepareProposalLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "prepare_proposal_latency_seconds",
+			Help:      "Time the application took to answer PrepareProposal, by outcome: ok, error or timeout.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 14),
+		}, append(labels, "outcome")).With(labelsAndValues...),
+		ProcessProposalLatency: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "process_proposal_latency_seconds",
+			Help:      "Time the application took to answer ProcessProposal, by outcome: accepted, rejected, error or timeout.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.001, 2, 14),
+		}, append(labels, "outcome")).With(labelsAndValues...),
+		ProposalTimeouts: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "proposal_timeouts",
+			Help:      "Count of PrepareProposal and ProcessProposal calls the application didn't answer before their deadline, by method.",
+		}, append(labels, "method")).With(labelsAndValues...),
 	}
 }
 
@@ -60,5 +89,8 @@ func NopMetrics() *Metrics {
 		BlockProcessingTime:     discard.NewHistogram(),
 		ProcessProposalRejected: discard.NewCounter(),
 		RejectedTransactions:    discard.NewCounter(),
+		PrepareProposalLatency:  discard.NewHistogram(),
+		ProcessProposalLatency:  discard.NewHistogram(),
+		ProposalTimeouts:        discard.NewCounter(),
 	}
 }
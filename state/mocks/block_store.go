@@ -182,6 +182,26 @@ func (_m *BlockStore) LoadBlockMetaByHash(hash []byte) *types.BlockMeta {
 	return r0
 }
 
+// LoadBlockMetaRange provides a mock function with given fields: from, to
+func (_m *BlockStore) LoadBlockMetaRange(from int64, to int64) []types.BlockRangeResult {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadBlockMetaRange")
+	}
+
+	var r0 []types.BlockRangeResult
+	if rf, ok := ret.Get(0).(func(int64, int64) []types.BlockRangeResult); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.BlockRangeResult)
+		}
+	}
+
+	return r0
+}
+
 // LoadBlockPart provides a mock function with given fields: height, index
 func (_m *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 	ret := _m.Called(height, index)
@@ -202,6 +222,26 @@ func (_m *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 	return r0
 }
 
+// LoadBlockRange provides a mock function with given fields: from, to
+func (_m *BlockStore) LoadBlockRange(from int64, to int64) []types.BlockRangeResult {
+	ret := _m.Called(from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadBlockRange")
+	}
+
+	var r0 []types.BlockRangeResult
+	if rf, ok := ret.Get(0).(func(int64, int64) []types.BlockRangeResult); ok {
+		r0 = rf(from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.BlockRangeResult)
+		}
+	}
+
+	return r0
+}
+
 // LoadSeenCommit provides a mock function with given fields: height
 func (_m *BlockStore) LoadSeenCommit(height int64) *types.Commit {
 	ret := _m.Called(height)
@@ -250,6 +290,52 @@ func (_m *BlockStore) PruneBlocks(height int64) (uint64, error) {
 	return r0, r1
 }
 
+// SetRetainHeight provides a mock function with given fields: height
+func (_m *BlockStore) SetRetainHeight(height int64) error {
+	ret := _m.Called(height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRetainHeight")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(height)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TruncateBlocks provides a mock function with given fields: height
+func (_m *BlockStore) TruncateBlocks(height int64) (uint64, error) {
+	ret := _m.Called(height)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TruncateBlocks")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (uint64, error)); ok {
+		return rf(height)
+	}
+	if rf, ok := ret.Get(0).(func(int64) uint64); ok {
+		r0 = rf(height)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SaveTxInfo provides a mock function with given fields: block, txResponseCode
 func (_m *BlockStore) SaveTxInfo(block *types.Block, txResponseCodes []uint32, logs []string) error {
 	ret := _m.Called(block, txResponseCodes, logs)
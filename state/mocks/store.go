@@ -18,6 +18,34 @@ type Store struct {
 	mock.Mock
 }
 
+// Base provides a mock function with given fields:
+func (_m *Store) Base() (int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Base")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Bootstrap provides a mock function with given fields: _a0
 func (_m *Store) Bootstrap(_a0 state.State) error {
 	ret := _m.Called(_a0)
@@ -256,6 +284,34 @@ func (_m *Store) LoadValidators(_a0 int64) (*tenderminttypes.ValidatorSet, error
 	return r0, r1
 }
 
+// PruneABCIResponses provides a mock function with given fields: _a0
+func (_m *Store) PruneABCIResponses(_a0 int64) (uint64, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneABCIResponses")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (uint64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int64) uint64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PruneStates provides a mock function with given fields: _a0, _a1
 func (_m *Store) PruneStates(_a0 int64, _a1 int64) error {
 	ret := _m.Called(_a0, _a1)
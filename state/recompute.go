@@ -0,0 +1,106 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	"github.com/tendermint/tendermint/proxy"
+)
+
+// ErrReplayNotSupported is returned by a ReplaySessionProvider if the
+// application cannot replay the block at the requested height, e.g. because
+// it doesn't keep the state of that height anymore.
+var ErrReplayNotSupported = errors.New("application does not support replaying the block")
+
+// ReplaySession is a connection to a read-only session of the application.
+// The blocks executed in a session never affect the state of the application.
+type ReplaySession interface {
+	proxy.AppConnConsensus
+
+	// Close ends the session, discarding all changes made by the blocks
+	// executed in it.
+	Close() error
+}
+
+// ReplaySessionProvider is implemented by applications which keep historical
+// versions of their state and are hence able to execute an already committed
+// block again.
+type ReplaySessionProvider interface {
+	// ReplaySession opens a read-only session on the state of the application
+	// as of the commit of the block at height-1, in which the block at height
+	// can be executed. It returns ErrReplayNotSupported if the state of that
+	// height is not available.
+	ReplaySession(height int64) (ReplaySession, error)
+}
+
+// ABCIResponsesRecomputer recomputes the ABCI responses of committed blocks
+// which were not retained by the state store (see
+// StoreOptions.DiscardABCIResponses), by replaying the block in a read-only
+// session of the application.
+type ABCIResponsesRecomputer struct {
+	sessions      ReplaySessionProvider
+	blockStore    BlockStore
+	stateStore    Store
+	initialHeight int64
+	logger        log.Logger
+}
+
+// NewABCIResponsesRecomputer creates a new ABCIResponsesRecomputer.
+func NewABCIResponsesRecomputer(
+	sessions ReplaySessionProvider,
+	blockStore BlockStore,
+	stateStore Store,
+	initialHeight int64,
+	logger log.Logger,
+) *ABCIResponsesRecomputer {
+	return &ABCIResponsesRecomputer{
+		sessions:      sessions,
+		blockStore:    blockStore,
+		stateStore:    stateStore,
+		initialHeight: initialHeight,
+		logger:        logger,
+	}
+}
+
+// Recompute replays the block at the given height and returns its ABCI
+// responses. If the results hash of the following block is available, the
+// recomputed responses are verified against it.
+func (r *ABCIResponsesRecomputer) Recompute(height int64) (*cmtstate.ABCIResponses, error) {
+	block := r.blockStore.LoadBlock(height)
+	if block == nil {
+		return nil, fmt.Errorf("block at height %d not found", height)
+	}
+	// the commit info passed to BeginBlock requires the previous validator set
+	if height > r.initialHeight {
+		if _, err := r.stateStore.LoadValidators(height - 1); err != nil {
+			return nil, fmt.Errorf("cannot replay block at height %d: %w", height, err)
+		}
+	}
+
+	session, err := r.sessions.ReplaySession(height)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := session.Close(); err != nil {
+			r.logger.Error("failed to close replay session", "height", height, "err", err)
+		}
+	}()
+
+	abciResponses, err := execBlockOnProxyApp(r.logger, session, block, r.stateStore, r.initialHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to replay block at height %d: %w", height, err)
+	}
+
+	if meta := r.blockStore.LoadBlockMeta(height + 1); meta != nil {
+		resultsHash := ABCIResponsesResultsHash(abciResponses)
+		if !bytes.Equal(resultsHash, meta.Header.LastResultsHash) {
+			return nil, fmt.Errorf("replayed results of block at height %d don't match: expected hash %X, got %X",
+				height, meta.Header.LastResultsHash, resultsHash)
+		}
+	}
+	return abciResponses, nil
+}
@@ -0,0 +1,97 @@
+package state_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abcicli "github.com/tendermint/tendermint/abci/client"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/proxy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/mocks"
+	"github.com/tendermint/tendermint/types"
+)
+
+type replaySessions struct {
+	app    abci.Application
+	opened []int64
+	closed int
+}
+
+func (s *replaySessions) ReplaySession(height int64) (sm.ReplaySession, error) {
+	if s.app == nil {
+		return nil, sm.ErrReplayNotSupported
+	}
+	s.opened = append(s.opened, height)
+	conn := proxy.NewAppConnConsensus(abcicli.NewLocalClient(nil, s.app))
+	return &replaySession{AppConnConsensus: conn, sessions: s}, nil
+}
+
+type replaySession struct {
+	proxy.AppConnConsensus
+	sessions *replaySessions
+}
+
+func (s *replaySession) Close() error {
+	s.sessions.closed++
+	return nil
+}
+
+type resultsApp struct {
+	abci.BaseApplication
+}
+
+func (resultsApp) DeliverTx(req abci.RequestDeliverTx) abci.ResponseDeliverTx {
+	return abci.ResponseDeliverTx{Data: req.Tx, Events: []abci.Event{{Type: "tx"}}}
+}
+
+func TestABCIResponsesRecomputer(t *testing.T) {
+	state, stateDB, _ := makeState(1, 1)
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{DiscardABCIResponses: true})
+	block := makeBlock(state, 1)
+
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("LoadBlock", int64(1)).Return(block)
+	blockStore.On("LoadBlock", mock.Anything).Return(nil)
+
+	// the results hash of the block is recorded in the header of the next one
+	var expected []*abci.ResponseDeliverTx
+	for _, tx := range block.Txs {
+		expected = append(expected, &abci.ResponseDeliverTx{Data: tx, Events: []abci.Event{{Type: "tx"}}})
+	}
+	nextMeta := &types.BlockMeta{Header: types.Header{LastResultsHash: types.NewResults(expected).Hash()}}
+	blockStore.On("LoadBlockMeta", int64(2)).Return(nextMeta)
+
+	sessions := &replaySessions{app: resultsApp{}}
+	recomputer := sm.NewABCIResponsesRecomputer(sessions, blockStore, stateStore, 1, log.TestingLogger())
+
+	responses, err := recomputer.Recompute(1)
+	require.NoError(t, err)
+	assert.Equal(t, expected, responses.DeliverTxs)
+	assert.NotNil(t, responses.BeginBlock)
+	assert.NotNil(t, responses.EndBlock)
+	assert.Equal(t, []int64{1}, sessions.opened)
+	assert.Equal(t, 1, sessions.closed)
+
+	// the block is not available
+	_, err = recomputer.Recompute(5)
+	require.Error(t, err)
+	assert.Len(t, sessions.opened, 1)
+
+	// the application doesn't support replaying the block
+	recomputer = sm.NewABCIResponsesRecomputer(&replaySessions{}, blockStore, stateStore, 1, log.TestingLogger())
+	_, err = recomputer.Recompute(1)
+	require.ErrorIs(t, err, sm.ErrReplayNotSupported)
+
+	// the application returns results which don't match the committed ones
+	sessions = &replaySessions{app: abci.NewBaseApplication()}
+	recomputer = sm.NewABCIResponsesRecomputer(sessions, blockStore, stateStore, 1, log.TestingLogger())
+	_, err = recomputer.Recompute(1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "don't match")
+	assert.Equal(t, 1, sessions.closed)
+}
@@ -110,3 +110,133 @@ func Rollback(bs BlockStore, ss Store) (int64, []byte, error) {
 
 	return rolledBackState.LastBlockHeight, rolledBackState.AppHash, nil
 }
+
+// RollbackToHeight overwrites the current CometBFT state with the state as of
+// the given target height, and removes all blocks above the target height
+// from the block store.
+//
+// Unlike Rollback, which keeps the block of the rolled back height so that it
+// is executed again on restart, the blocks above the target height are
+// removed: their headers commit to the application results which are to be
+// corrected, hence they have to be produced again. The target height can't
+// be below the base of the block store, nor below the heights the state
+// store holds validators and consensus params for, i.e. it can't cross a
+// pruned range or the snapshot height of a state sync.
+//
+// The state is persisted before the blocks are removed, so that an
+// interrupted rollback can be completed by running it again with the same
+// target height. Note that this function does not affect application state,
+// nor the state of the private validator.
+func RollbackToHeight(bs BlockStore, ss Store, targetHeight int64) (int64, []byte, error) {
+	store, ok := ss.(dbStore)
+	if !ok {
+		return -1, nil, errors.New("rolling back to a height requires a database state store")
+	}
+	state, err := ss.Load()
+	if err != nil {
+		return -1, nil, err
+	}
+	if state.IsEmpty() {
+		return -1, nil, errors.New("no state found")
+	}
+	if targetHeight < state.InitialHeight {
+		return -1, nil, fmt.Errorf("target height %d is below the initial height %d",
+			targetHeight, state.InitialHeight)
+	}
+	if targetHeight > state.LastBlockHeight {
+		return -1, nil, fmt.Errorf("target height %d is above the latest state height %d",
+			targetHeight, state.LastBlockHeight)
+	}
+
+	if targetHeight < state.LastBlockHeight {
+		state, err = store.stateAtHeight(bs, state, targetHeight)
+		if err != nil {
+			return -1, nil, err
+		}
+		if err := ss.Save(state); err != nil {
+			return -1, nil, fmt.Errorf("failed to save rolled back state: %w", err)
+		}
+	}
+
+	if _, err := bs.TruncateBlocks(targetHeight); err != nil {
+		return -1, nil, fmt.Errorf("failed to remove blocks above height %d: %w", targetHeight, err)
+	}
+	return state.LastBlockHeight, state.AppHash, nil
+}
+
+// stateAtHeight rebuilds the state as of the given height from the block
+// store and the validators and consensus params stored for the following
+// heights.
+func (store dbStore) stateAtHeight(bs BlockStore, latest State, height int64) (State, error) {
+	if base := bs.Base(); height < base {
+		return State{}, fmt.Errorf("cannot roll back to height %d, the blocks below height %d were pruned "+
+			"or never synced", height, base)
+	}
+	meta := bs.LoadBlockMeta(height)
+	if meta == nil {
+		return State{}, fmt.Errorf("block at height %d not found", height)
+	}
+	// the app hash and last results hash are only agreed upon in the
+	// following block
+	nextMeta := bs.LoadBlockMeta(height + 1)
+	if nextMeta == nil {
+		return State{}, fmt.Errorf("block at height %d not found", height+1)
+	}
+
+	unavailable := func(err error) error {
+		return fmt.Errorf("cannot roll back to height %d, the state of that height is not available "+
+			"(pruned or state synced): %w", height, err)
+	}
+	lastValidators, err := store.LoadValidators(height)
+	if err != nil {
+		return State{}, unavailable(err)
+	}
+	validators, err := store.LoadValidators(height + 1)
+	if err != nil {
+		return State{}, unavailable(err)
+	}
+	nextValidators, err := store.LoadValidators(height + 2)
+	if err != nil {
+		return State{}, unavailable(err)
+	}
+	valsInfo, err := loadValidatorsInfo(store.db, height+2)
+	if err != nil {
+		return State{}, unavailable(err)
+	}
+	params, err := store.LoadConsensusParams(height + 1)
+	if err != nil {
+		return State{}, unavailable(err)
+	}
+	paramsInfo, err := store.loadConsensusParamsInfo(height + 1)
+	if err != nil {
+		return State{}, unavailable(err)
+	}
+
+	return State{
+		Version: cmtstate.Version{
+			Consensus: cmtversion.Consensus{
+				Block: version.BlockProtocol,
+				App:   params.Version.AppVersion,
+			},
+			Software: version.TMCoreSemVer,
+		},
+		// immutable fields
+		ChainID:       latest.ChainID,
+		InitialHeight: latest.InitialHeight,
+
+		LastBlockHeight: meta.Header.Height,
+		LastBlockID:     meta.BlockID,
+		LastBlockTime:   meta.Header.Time,
+
+		NextValidators:              nextValidators,
+		Validators:                  validators,
+		LastValidators:              lastValidators,
+		LastHeightValidatorsChanged: valsInfo.LastHeightChanged,
+
+		ConsensusParams:                  params,
+		LastHeightConsensusParamsChanged: paramsInfo.LastHeightChanged,
+
+		LastResultsHash: nextMeta.Header.LastResultsHash,
+		AppHash:         nextMeta.Header.AppHash,
+	}, nil
+}
@@ -7,12 +7,18 @@ import (
 	dbm "github.com/cometbft/cometbft-db"
 	"github.com/stretchr/testify/require"
 
+	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/log"
+	memmock "github.com/tendermint/tendermint/mempool/mock"
 	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	cmtversion "github.com/tendermint/tendermint/proto/tendermint/version"
+	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/state"
 	"github.com/tendermint/tendermint/state/mocks"
+	"github.com/tendermint/tendermint/store"
+	"github.com/tendermint/tendermint/test/factory"
 	"github.com/tendermint/tendermint/types"
 	"github.com/tendermint/tendermint/version"
 )
@@ -117,6 +123,133 @@ func TestRollbackDifferentStateHeight(t *testing.T) {
 	require.Equal(t, err.Error(), "statestore height (100) is not one below or equal to blockstore height (102)")
 }
 
+// makeChain applies and stores blocks up to the given height, changing the
+// power of a validator with the block at changeValsHeight. It returns the
+// state after each height.
+func makeChain(t *testing.T, height, changeValsHeight int64) (
+	state.Store, *store.BlockStore, *state.BlockExecutor, map[string]types.PrivValidator, map[int64]state.State,
+) {
+	app := &testApp{}
+	proxyApp := proxy.NewAppConns(proxy.NewLocalClientCreator(app))
+	require.NoError(t, proxyApp.Start())
+	t.Cleanup(func() { _ = proxyApp.Stop() })
+
+	st, stateDB, privVals := makeState(3, 1)
+	stateStore := state.NewStore(stateDB, state.StoreOptions{DiscardABCIResponses: false})
+	blockStore := store.NewBlockStore(dbm.NewMemDB())
+	blockExec := state.NewBlockExecutor(stateStore, log.TestingLogger(), proxyApp.Consensus(),
+		memmock.Mempool{}, state.EmptyEvidencePool{})
+
+	_, val := st.Validators.GetByIndex(0)
+	valUpdate := abci.UpdateValidator(val.PubKey.Bytes(), 500, "")
+	states := map[int64]state.State{}
+	lastCommit := types.NewCommit(0, 0, types.BlockID{}, nil)
+	for h := int64(1); h <= height; h++ {
+		app.ValidatorUpdates = nil
+		if h == changeValsHeight {
+			app.ValidatorUpdates = []abci.ValidatorUpdate{valUpdate}
+		}
+		st, lastCommit = applyBlock(t, st, h, lastCommit, blockExec, blockStore, privVals)
+		states[h] = st
+	}
+	return stateStore, blockStore, blockExec, privVals, states
+}
+
+func applyBlock(t *testing.T, st state.State, height int64, lastCommit *types.Commit,
+	blockExec *state.BlockExecutor, blockStore *store.BlockStore, privVals map[string]types.PrivValidator,
+) (state.State, *types.Commit) {
+	block, partSet := st.MakeBlock(height, factory.MakeData(makeTxs(height)), lastCommit, nil,
+		st.Validators.GetProposer().Address)
+	blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: partSet.Header()}
+	require.NoError(t, blockExec.ValidateBlock(st, block), "height %d", height)
+	vals := st.Validators
+	st, _, err := blockExec.ApplyBlock(st, blockID, block, lastCommit)
+	require.NoError(t, err, "height %d", height)
+	commit, err := makeValidCommit(height, blockID, vals, privVals)
+	require.NoError(t, err)
+	blockStore.SaveBlock(block, partSet, commit)
+	return st, commit
+}
+
+func TestRollbackToHeight(t *testing.T) {
+	// the validator set changes with block 3, taking effect at height 5, and
+	// with block 7, taking effect at height 9
+	stateStore, blockStore, blockExec, privVals, states := makeChain(t, 10, 3)
+
+	rollbackHeight, rollbackHash, err := state.RollbackToHeight(blockStore, stateStore, 5)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, rollbackHeight)
+	require.Equal(t, states[5].AppHash, rollbackHash)
+
+	// the state and the block store are back at height 5
+	loadedState, err := stateStore.Load()
+	require.NoError(t, err)
+	require.Equal(t, states[5].Bytes(), loadedState.Bytes())
+	require.EqualValues(t, 5, blockStore.Height())
+	require.Nil(t, blockStore.LoadBlock(6))
+	require.NotNil(t, blockStore.LoadBlock(5))
+
+	// rolling back to the same height again is a no-op
+	rollbackHeight, _, err = state.RollbackToHeight(blockStore, stateStore, 5)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, rollbackHeight)
+
+	// block production resumes from the rolled back state
+	st, lastCommit := loadedState, blockStore.LoadSeenCommit(5)
+	for h := int64(6); h <= 10; h++ {
+		st, lastCommit = applyBlock(t, st, h, lastCommit, blockExec, blockStore, privVals)
+	}
+	require.EqualValues(t, 10, blockStore.Height())
+	loadedState, err = stateStore.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, 10, loadedState.LastBlockHeight)
+	require.Equal(t, states[10].Validators.Hash(), loadedState.Validators.Hash())
+}
+
+func TestRollbackToHeightAcrossValidatorChange(t *testing.T) {
+	// the validator set changes with block 7, taking effect at height 9
+	stateStore, blockStore, _, _, states := makeChain(t, 10, 7)
+	require.EqualValues(t, 9, states[10].LastHeightValidatorsChanged)
+
+	_, _, err := state.RollbackToHeight(blockStore, stateStore, 4)
+	require.NoError(t, err)
+	loadedState, err := stateStore.Load()
+	require.NoError(t, err)
+	require.Equal(t, states[4].Bytes(), loadedState.Bytes())
+	require.EqualValues(t, 1, loadedState.LastHeightValidatorsChanged)
+}
+
+func TestRollbackToHeightBoundaries(t *testing.T) {
+	stateStore, blockStore, _, _, _ := makeChain(t, 10, 0)
+
+	_, _, err := state.RollbackToHeight(blockStore, stateStore, 11)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "above the latest state height")
+
+	_, _, err = state.RollbackToHeight(blockStore, stateStore, 0)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "below the initial height")
+
+	// blocks below the base of the block store can't be restored
+	_, err = blockStore.PruneBlocks(4)
+	require.NoError(t, err)
+	_, _, err = state.RollbackToHeight(blockStore, stateStore, 3)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pruned")
+	require.EqualValues(t, 10, blockStore.Height())
+
+	// nor can the state of heights which were pruned
+	require.NoError(t, stateStore.PruneStates(1, 8))
+	_, _, err = state.RollbackToHeight(blockStore, stateStore, 5)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "state of that height is not available")
+	require.EqualValues(t, 10, blockStore.Height())
+
+	// the database state store is required
+	_, _, err = state.RollbackToHeight(blockStore, &mocks.Store{}, 5)
+	require.Error(t, err)
+}
+
 func setupStateStore(t *testing.T, height int64) state.Store {
 	stateStore := state.NewStore(dbm.NewMemDB(), state.StoreOptions{DiscardABCIResponses: false})
 	valSet, _ := types.RandValidatorSet(5, 10)
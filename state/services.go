@@ -24,11 +24,15 @@ type BlockStore interface {
 	LoadBaseMeta() *types.BlockMeta
 	LoadBlockMeta(height int64) *types.BlockMeta
 	LoadBlock(height int64) *types.Block
+	LoadBlockRange(from, to int64) []types.BlockRangeResult
+	LoadBlockMetaRange(from, to int64) []types.BlockRangeResult
 
 	SaveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit)
 	SaveTxInfo(block *types.Block, txResponseCodes []uint32, logs []string) error
 
 	PruneBlocks(height int64) (uint64, error)
+	TruncateBlocks(height int64) (uint64, error)
+	SetRetainHeight(height int64) error
 
 	LoadBlockByHash(hash []byte) *types.Block
 	LoadBlockMetaByHash(hash []byte) *types.BlockMeta
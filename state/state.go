@@ -17,13 +17,6 @@ import (
 	"github.com/tendermint/tendermint/version"
 )
 
-// database key
-var (
-	stateKey = []byte("stateKey")
-)
-
-//-----------------------------------------------------------------------------
-
 // InitStateVersion sets the Consensus.Block, Consensus.App and Software versions
 func InitStateVersion(appVersion uint64) cmtstate.Version {
 	return cmtstate.Version{
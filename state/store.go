@@ -1,6 +1,7 @@
 package state
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -25,22 +26,43 @@ const (
 
 //------------------------------------------------------------------------
 
+// Keys of the store use the v2 layout (see StoreLayoutVersion): each kind of
+// data has its own single byte prefix, followed by the big-endian encoded
+// height, so that iterating over a prefix yields the entries ordered by height.
+
+const (
+	prefixMetadata        = byte(0x00)
+	prefixValidators      = byte(0x01)
+	prefixConsensusParams = byte(0x02)
+	prefixABCIResponses   = byte(0x03)
+)
+
 func calcValidatorsKey(height int64) []byte {
-	return []byte(fmt.Sprintf("validatorsKey:%v", height))
+	return calcHeightKey(prefixValidators, height)
 }
 
 func calcConsensusParamsKey(height int64) []byte {
-	return []byte(fmt.Sprintf("consensusParamsKey:%v", height))
+	return calcHeightKey(prefixConsensusParams, height)
 }
 
 func calcABCIResponsesKey(height int64) []byte {
-	return []byte(fmt.Sprintf("abciResponsesKey:%v", height))
+	return calcHeightKey(prefixABCIResponses, height)
+}
+
+func calcHeightKey(prefix byte, height int64) []byte {
+	key := make([]byte, 9)
+	key[0] = prefix
+	binary.BigEndian.PutUint64(key[1:], uint64(height)) //nolint:gosec
+	return key
 }
 
 //----------------------
 
 var (
-	lastABCIResponseKey = []byte("lastABCIResponseKey")
+	layoutVersionKey    = []byte{prefixMetadata, 0x00}
+	stateKey            = []byte{prefixMetadata, 0x01}
+	lastABCIResponseKey = []byte{prefixMetadata, 0x02}
+	baseKey             = []byte{prefixMetadata, 0x03}
 )
 
 //go:generate ../scripts/mockery_generate.sh Store
@@ -74,6 +96,11 @@ type Store interface {
 	Bootstrap(State) error
 	// PruneStates takes the height from which to start prning and which height stop at
 	PruneStates(int64, int64) error
+	// Base returns the lowest height whose state was not pruned
+	Base() (int64, error)
+	// PruneABCIResponses deletes up to the given number of ABCI responses
+	// which are no longer retained, returning the number deleted
+	PruneABCIResponses(int64) (uint64, error)
 	// Close closes the connection with the database
 	Close() error
 }
@@ -92,6 +119,11 @@ type StoreOptions struct {
 	// the store will maintain only the response object from the latest
 	// height.
 	DiscardABCIResponses bool
+
+	// PruningCheckpointInterval keeps the validator set and consensus params
+	// of every n-th height when states are pruned, so that light clients can
+	// still verify headers at these heights. 0 keeps none.
+	PruningCheckpointInterval int64
 }
 
 var _ Store = (*dbStore)(nil)
@@ -266,6 +298,12 @@ func (store dbStore) PruneStates(from int64, to int64) error {
 	if paramsInfo.ConsensusParams.Equal(&cmtproto.ConsensusParams{}) {
 		keepParams[paramsInfo.LastHeightChanged] = true
 	}
+	if interval := store.PruningCheckpointInterval; interval > 0 {
+		for h := from + (interval-from%interval)%interval; h < to; h += interval {
+			keepVals[h] = true
+			keepParams[h] = true
+		}
+	}
 
 	batch := store.db.NewBatch()
 	defer batch.Close()
@@ -357,6 +395,18 @@ func (store dbStore) PruneStates(from int64, to int64) error {
 		}
 	}
 
+	base, err := store.Base()
+	if err != nil {
+		return err
+	}
+	// heights between the base and from are still present
+	if from <= base && to > base {
+		err = batch.Set(baseKey, int64ToBytes(to))
+		if err != nil {
+			return err
+		}
+	}
+
 	err = batch.WriteSync()
 	if err != nil {
 		return err
@@ -365,6 +415,58 @@ func (store dbStore) PruneStates(from int64, to int64) error {
 	return nil
 }
 
+// Base returns the lowest height whose validators, consensus params and ABCI
+// responses were not pruned. Below it, only the entries kept by PruneStates
+// remain, e.g. checkpoints (see StoreOptions.PruningCheckpointInterval). If
+// no states were pruned, it is the lowest height stored, or 0 if the store
+// is empty.
+func (store dbStore) Base() (int64, error) {
+	base, err := store.prunedBase()
+	if err != nil || base > 0 {
+		return base, err
+	}
+
+	itr, err := dbm.IteratePrefix(store.db, []byte{prefixValidators})
+	if err != nil {
+		return 0, err
+	}
+	defer itr.Close()
+	if !itr.Valid() {
+		return 0, itr.Error()
+	}
+	return int64FromBytes(itr.Key()[1:]), nil
+}
+
+// prunedBase returns the height up to which states were pruned, or 0 if
+// they were never pruned.
+func (store dbStore) prunedBase() (int64, error) {
+	bz, err := store.db.Get(baseKey)
+	if err != nil || len(bz) == 0 {
+		return 0, err
+	}
+	return int64FromBytes(bz), nil
+}
+
+// prunedError returns ErrStatePruned if the state at the given height was
+// pruned, or the given error otherwise.
+func (store dbStore) prunedError(height int64, err error) error {
+	base, baseErr := store.prunedBase()
+	if baseErr == nil && height < base {
+		return ErrStatePruned{Height: height, Base: base}
+	}
+	return err
+}
+
+func int64ToBytes(i int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(i)) //nolint:gosec
+	return bz
+}
+
+func int64FromBytes(bz []byte) int64 {
+	return int64(binary.BigEndian.Uint64(bz)) //nolint:gosec
+}
+
 //------------------------------------------------------------------------
 
 // ABCIResponsesResultsHash returns the root hash of a Merkle tree of
@@ -404,6 +506,44 @@ func (store dbStore) LoadABCIResponses(height int64) (*cmtstate.ABCIResponses, e
 	return abciResponses, nil
 }
 
+// PruneABCIResponses deletes up to limit ABCI responses which were stored
+// before the node was configured to discard them (see
+// StoreOptions.DiscardABCIResponses), lowest heights first. It returns the
+// number of responses deleted, which is 0 once all of them are gone or if
+// the store retains ABCI responses.
+func (store dbStore) PruneABCIResponses(limit int64) (uint64, error) {
+	if !store.DiscardABCIResponses || limit <= 0 {
+		return 0, nil
+	}
+
+	itr, err := dbm.IteratePrefix(store.db, []byte{prefixABCIResponses})
+	if err != nil {
+		return 0, err
+	}
+	defer itr.Close()
+
+	batch := store.db.NewBatch()
+	defer batch.Close()
+
+	pruned := uint64(0)
+	for ; itr.Valid() && pruned < uint64(limit); itr.Next() {
+		if err := batch.Delete(itr.Key()); err != nil {
+			return 0, err
+		}
+		pruned++
+	}
+	if err := itr.Error(); err != nil {
+		return 0, err
+	}
+	if pruned == 0 {
+		return 0, nil
+	}
+	if err := batch.WriteSync(); err != nil {
+		return 0, err
+	}
+	return pruned, nil
+}
+
 // LoadLastABCIResponse loads the ABCIResponses from the most recent height.
 // The height parameter is used to ensure that the response corresponds to the latest height.
 // If not, an error is returned.
@@ -484,7 +624,7 @@ func (store dbStore) SaveABCIResponses(height int64, abciResponses *cmtstate.ABC
 func (store dbStore) LoadValidators(height int64) (*types.ValidatorSet, error) {
 	valInfo, err := loadValidatorsInfo(store.db, height)
 	if err != nil {
-		return nil, ErrNoValSetForHeight{height}
+		return nil, store.prunedError(height, ErrNoValSetForHeight{height})
 	}
 	if valInfo.ValidatorSet == nil {
 		lastStoredHeight := lastStoredHeightFor(height, valInfo.LastHeightChanged)
@@ -594,7 +734,8 @@ func (store dbStore) LoadConsensusParams(height int64) (cmtproto.ConsensusParams
 
 	paramsInfo, err := store.loadConsensusParamsInfo(height)
 	if err != nil {
-		return empty, fmt.Errorf("could not find consensus params for height #%d: %w", height, err)
+		return empty, store.prunedError(height,
+			fmt.Errorf("could not find consensus params for height #%d: %w", height, err))
 	}
 
 	if paramsInfo.ConsensusParams.Equal(&empty) {
@@ -0,0 +1,268 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+const (
+	// StoreLayoutVersion is the version of the key layout used by the state
+	// store. A store with a different layout must be migrated with
+	// MigrateStoreLayout before it can be used.
+	//
+	// v1: keys are strings of the form "<kind>Key:<decimal height>"
+	// v2: keys are a single byte prefix per kind followed by the big-endian
+	// encoded height
+	StoreLayoutVersion = int64(2)
+
+	// legacyStoreLayoutVersion is the version of stores which don't record
+	// a layout version.
+	legacyStoreLayoutVersion = int64(1)
+)
+
+// v1 layout keys
+
+var (
+	v1ValidatorsPrefix      = []byte("validatorsKey:")
+	v1ConsensusParamsPrefix = []byte("consensusParamsKey:")
+	v1ABCIResponsesPrefix   = []byte("abciResponsesKey:")
+
+	v1StateKey            = []byte("stateKey")
+	v1LastABCIResponseKey = []byte("lastABCIResponseKey")
+)
+
+// v1HeightPrefixes maps the prefixes of the v1 per-height keys to the
+// respective v2 prefixes.
+var v1HeightPrefixes = []struct {
+	v1 []byte
+	v2 byte
+}{
+	{v1ValidatorsPrefix, prefixValidators},
+	{v1ConsensusParamsPrefix, prefixConsensusParams},
+	{v1ABCIResponsesPrefix, prefixABCIResponses},
+}
+
+// LoadStoreLayoutVersion returns the key layout version of the state store
+// in the given database. Stores without any data are reported as using the
+// current StoreLayoutVersion.
+func LoadStoreLayoutVersion(db dbm.DB) (int64, error) {
+	bz, err := db.Get(layoutVersionKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(bz) == 8 {
+		return int64(binary.BigEndian.Uint64(bz)), nil
+	}
+	if len(bz) != 0 {
+		return 0, fmt.Errorf("invalid state store layout version %X", bz)
+	}
+
+	hasLegacyData, err := hasV1Data(db)
+	if err != nil {
+		return 0, err
+	}
+	if hasLegacyData {
+		return legacyStoreLayoutVersion, nil
+	}
+	return StoreLayoutVersion, nil
+}
+
+// CheckStoreLayout verifies that the state store in the given database uses
+// the current key layout, and returns ErrStoreLayoutMismatch otherwise. The
+// layout version is recorded for stores which don't contain any data yet.
+func CheckStoreLayout(db dbm.DB) error {
+	version, err := LoadStoreLayoutVersion(db)
+	if err != nil {
+		return err
+	}
+	if version != StoreLayoutVersion {
+		return ErrStoreLayoutMismatch{Got: version, Want: StoreLayoutVersion}
+	}
+	return saveStoreLayoutVersion(db, StoreLayoutVersion)
+}
+
+func saveStoreLayoutVersion(db dbm.DB, version int64) error {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(version)) //nolint:gosec
+	return db.SetSync(layoutVersionKey, bz)
+}
+
+func hasV1Data(db dbm.DB) (bool, error) {
+	for _, key := range [][]byte{v1StateKey, v1LastABCIResponseKey} {
+		ok, err := db.Has(key)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	for _, p := range v1HeightPrefixes {
+		itr, err := dbm.IteratePrefix(db, p.v1)
+		if err != nil {
+			return false, err
+		}
+		ok := itr.Valid()
+		itr.Close()
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MigrationProgress is called by MigrateStoreLayout after every batch with
+// the number of keys migrated so far and the total number of keys to migrate.
+type MigrationProgress func(migrated, total int64)
+
+// MigrateStoreLayout converts the state store in the given database in place
+// from the v1 to the current key layout. Keys are migrated in batches of
+// batchSize, each written atomically, so an interrupted migration can be
+// resumed by running it again. Keys which don't belong to the state store,
+// e.g. the genesis document stored by the node, are left untouched.
+//
+// The node must not be running while the store is migrated.
+func MigrateStoreLayout(db dbm.DB, batchSize int, progress MigrationProgress) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("batch size must be greater than 0, got %d", batchSize)
+	}
+	version, err := LoadStoreLayoutVersion(db)
+	if err != nil {
+		return err
+	}
+	if version == StoreLayoutVersion {
+		return saveStoreLayoutVersion(db, StoreLayoutVersion)
+	}
+	if version != legacyStoreLayoutVersion {
+		return fmt.Errorf("cannot migrate state store from layout v%d", version)
+	}
+
+	total, err := countV1Keys(db)
+	if err != nil {
+		return err
+	}
+	var migrated int64
+	report := func(n int) {
+		migrated += int64(n)
+		if progress != nil {
+			progress(migrated, total)
+		}
+	}
+
+	// singleton keys
+	for _, keys := range [][2][]byte{
+		{v1StateKey, stateKey},
+		{v1LastABCIResponseKey, lastABCIResponseKey},
+	} {
+		n, err := migrateKeys(db, [][]byte{keys[0]}, func([]byte) ([]byte, error) { return keys[1], nil })
+		if err != nil {
+			return err
+		}
+		report(n)
+	}
+
+	// per height keys
+	for _, p := range v1HeightPrefixes {
+		p := p
+		convert := func(key []byte) ([]byte, error) {
+			height, err := strconv.ParseInt(string(key[len(p.v1):]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key %q: %w", key, err)
+			}
+			return calcHeightKey(p.v2, height), nil
+		}
+		for {
+			keys, err := collectKeys(db, p.v1, batchSize)
+			if err != nil {
+				return err
+			}
+			if len(keys) == 0 {
+				break
+			}
+			n, err := migrateKeys(db, keys, convert)
+			if err != nil {
+				return err
+			}
+			report(n)
+		}
+	}
+
+	return saveStoreLayoutVersion(db, StoreLayoutVersion)
+}
+
+// migrateKeys moves the values of the given keys, if they exist, to the keys
+// returned by convert in a single batch. It returns the number of keys moved.
+func migrateKeys(db dbm.DB, keys [][]byte, convert func([]byte) ([]byte, error)) (int, error) {
+	batch := db.NewBatch()
+	defer batch.Close()
+
+	n := 0
+	for _, key := range keys {
+		value, err := db.Get(key)
+		if err != nil {
+			return 0, err
+		}
+		if value == nil {
+			continue
+		}
+		newKey, err := convert(key)
+		if err != nil {
+			return 0, err
+		}
+		if err := batch.Set(newKey, value); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(key); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, batch.WriteSync()
+}
+
+// collectKeys returns up to limit keys with the given prefix.
+func collectKeys(db dbm.DB, prefix []byte, limit int) ([][]byte, error) {
+	itr, err := dbm.IteratePrefix(db, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+
+	var keys [][]byte
+	for ; itr.Valid() && len(keys) < limit; itr.Next() {
+		keys = append(keys, bytes.Clone(itr.Key()))
+	}
+	return keys, itr.Error()
+}
+
+func countV1Keys(db dbm.DB) (int64, error) {
+	var total int64
+	for _, key := range [][]byte{v1StateKey, v1LastABCIResponseKey} {
+		ok, err := db.Has(key)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			total++
+		}
+	}
+	for _, p := range v1HeightPrefixes {
+		itr, err := dbm.IteratePrefix(db, p.v1)
+		if err != nil {
+			return 0, err
+		}
+		for ; itr.Valid(); itr.Next() {
+			total++
+		}
+		err = itr.Error()
+		itr.Close()
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
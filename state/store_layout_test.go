@@ -0,0 +1,177 @@
+package state_test
+
+import (
+	"bytes"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+// populateStateStore saves states and ABCI responses for the given number of
+// heights. Validators change every 10 heights and params every 7 heights.
+func populateStateStore(t *testing.T, db dbm.DB, heights int64) {
+	stateStore := sm.NewStore(db, sm.StoreOptions{DiscardABCIResponses: false})
+
+	valsChanged, paramsChanged := int64(0), int64(0)
+	for h := int64(1); h <= heights; h++ {
+		if valsChanged == 0 || h%10 == 0 {
+			valsChanged = h + 1
+		}
+		if paramsChanged == 0 || h%7 == 0 {
+			paramsChanged = h
+		}
+		pk := ed25519.GenPrivKey().PubKey()
+		validator := &types.Validator{Address: pk.Address(), VotingPower: 100, PubKey: pk}
+		validatorSet := &types.ValidatorSet{
+			Validators: []*types.Validator{validator},
+			Proposer:   validator,
+		}
+		state := sm.State{
+			InitialHeight:   1,
+			LastBlockHeight: h - 1,
+			Validators:      validatorSet,
+			NextValidators:  validatorSet,
+			ConsensusParams: cmtproto.ConsensusParams{
+				Block: cmtproto.BlockParams{MaxBytes: 10e6 + paramsChanged},
+			},
+			LastHeightValidatorsChanged:      valsChanged,
+			LastHeightConsensusParamsChanged: paramsChanged,
+		}
+		if state.LastBlockHeight >= 1 {
+			state.LastValidators = state.Validators
+		}
+		require.NoError(t, stateStore.Save(state))
+		require.NoError(t, stateStore.SaveABCIResponses(h, &cmtstate.ABCIResponses{
+			DeliverTxs: []*abci.ResponseDeliverTx{{Data: []byte{byte(h)}}},
+		}))
+	}
+}
+
+func dumpDB(t *testing.T, db dbm.DB) map[string][]byte {
+	itr, err := db.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer itr.Close()
+	contents := make(map[string][]byte)
+	for ; itr.Valid(); itr.Next() {
+		contents[string(itr.Key())] = bytes.Clone(itr.Value())
+	}
+	require.NoError(t, itr.Error())
+	return contents
+}
+
+func copyDB(t *testing.T, contents map[string][]byte) dbm.DB {
+	db := dbm.NewMemDB()
+	for k, v := range contents {
+		require.NoError(t, db.Set([]byte(k), v))
+	}
+	return db
+}
+
+func TestCheckStoreLayout(t *testing.T) {
+	// a fresh store is tagged with the current layout
+	db := dbm.NewMemDB()
+	require.NoError(t, sm.CheckStoreLayout(db))
+	version, err := sm.LoadStoreLayoutVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, sm.StoreLayoutVersion, version)
+
+	// unrelated keys, such as the genesis doc, don't make a store legacy
+	db = dbm.NewMemDB()
+	require.NoError(t, db.Set([]byte("genesisDoc"), []byte("{}")))
+	require.NoError(t, sm.CheckStoreLayout(db))
+
+	// a store using the v1 layout is refused
+	db = dbm.NewMemDB()
+	populateStateStore(t, db, 5)
+	require.NoError(t, sm.ConvertToV1StoreLayout(db))
+	err = sm.CheckStoreLayout(db)
+	require.Error(t, err)
+	assert.Equal(t, sm.ErrStoreLayoutMismatch{Got: 1, Want: sm.StoreLayoutVersion}, err)
+	assert.Contains(t, err.Error(), "migrate-state-store")
+}
+
+func TestMigrateStoreLayoutRoundTrip(t *testing.T) {
+	const heights = 250
+
+	db := dbm.NewMemDB()
+	populateStateStore(t, db, heights)
+	require.NoError(t, db.Set([]byte("genesisDoc"), []byte("{}")))
+	require.NoError(t, sm.CheckStoreLayout(db))
+	want := dumpDB(t, db)
+
+	// load everything through the store before the migration
+	stateStore := sm.NewStore(db, sm.StoreOptions{DiscardABCIResponses: false})
+	wantState, err := stateStore.Load()
+	require.NoError(t, err)
+
+	require.NoError(t, sm.ConvertToV1StoreLayout(db))
+	require.NotEqual(t, want, dumpDB(t, db))
+
+	var calls, lastMigrated, lastTotal int64
+	err = sm.MigrateStoreLayout(db, 100, func(migrated, total int64) {
+		calls++
+		require.GreaterOrEqual(t, migrated, lastMigrated)
+		lastMigrated, lastTotal = migrated, total
+	})
+	require.NoError(t, err)
+	assert.Greater(t, calls, int64(3))
+	assert.Equal(t, lastTotal, lastMigrated)
+
+	// the migrated store is identical to the original one
+	assert.Equal(t, want, dumpDB(t, db))
+	require.NoError(t, sm.CheckStoreLayout(db))
+
+	gotState, err := stateStore.Load()
+	require.NoError(t, err)
+	assert.Equal(t, wantState.Bytes(), gotState.Bytes())
+	for h := int64(1); h <= heights; h++ {
+		_, err := stateStore.LoadValidators(h)
+		require.NoError(t, err, "validators at height %d", h)
+		_, err = stateStore.LoadConsensusParams(h)
+		require.NoError(t, err, "params at height %d", h)
+		_, err = stateStore.LoadABCIResponses(h)
+		require.NoError(t, err, "abci responses at height %d", h)
+	}
+
+	// migrating again is a no-op
+	require.NoError(t, sm.MigrateStoreLayout(db, 100, nil))
+	assert.Equal(t, want, dumpDB(t, db))
+}
+
+func TestMigrateStoreLayoutResume(t *testing.T) {
+	db := dbm.NewMemDB()
+	populateStateStore(t, db, 100)
+	require.NoError(t, sm.CheckStoreLayout(db))
+	want := dumpDB(t, db)
+	require.NoError(t, sm.ConvertToV1StoreLayout(db))
+	v1Contents := dumpDB(t, db)
+
+	// interrupt the migration after the first few batches by copying the
+	// database as it was at that point
+	var interrupted dbm.DB
+	err := sm.MigrateStoreLayout(db, 10, func(migrated, total int64) {
+		if interrupted == nil && migrated >= 30 {
+			interrupted = copyDB(t, dumpDB(t, db))
+		}
+	})
+	require.NoError(t, err)
+	require.NotNil(t, interrupted)
+	require.NotEqual(t, v1Contents, dumpDB(t, interrupted))
+
+	// the node refuses to run on a partially migrated store
+	require.Error(t, sm.CheckStoreLayout(interrupted))
+
+	// resuming completes the migration
+	require.NoError(t, sm.MigrateStoreLayout(interrupted, 10, nil))
+	assert.Equal(t, want, dumpDB(t, interrupted))
+	require.NoError(t, sm.CheckStoreLayout(interrupted))
+}
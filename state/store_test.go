@@ -112,54 +112,7 @@ func TestPruneStates(t *testing.T) {
 			stateStore := sm.NewStore(db, sm.StoreOptions{
 				DiscardABCIResponses: false,
 			})
-			pk := ed25519.GenPrivKey().PubKey()
-
-			// Generate a bunch of state data. Validators change for heights ending with 3, and
-			// parameters when ending with 5.
-			validator := &types.Validator{Address: pk.Address(), VotingPower: 100, PubKey: pk}
-			validatorSet := &types.ValidatorSet{
-				Validators: []*types.Validator{validator},
-				Proposer:   validator,
-			}
-			valsChanged := int64(0)
-			paramsChanged := int64(0)
-
-			for h := int64(1); h <= tc.makeHeights; h++ {
-				if valsChanged == 0 || h%10 == 2 {
-					valsChanged = h + 1 // Have to add 1, since NextValidators is what's stored
-				}
-				if paramsChanged == 0 || h%10 == 5 {
-					paramsChanged = h
-				}
-
-				state := sm.State{
-					InitialHeight:   1,
-					LastBlockHeight: h - 1,
-					Validators:      validatorSet,
-					NextValidators:  validatorSet,
-					ConsensusParams: cmtproto.ConsensusParams{
-						Block: cmtproto.BlockParams{MaxBytes: 10e6},
-					},
-					LastHeightValidatorsChanged:      valsChanged,
-					LastHeightConsensusParamsChanged: paramsChanged,
-				}
-
-				if state.LastBlockHeight >= 1 {
-					state.LastValidators = state.Validators
-				}
-
-				err := stateStore.Save(state)
-				require.NoError(t, err)
-
-				err = stateStore.SaveABCIResponses(h, &cmtstate.ABCIResponses{
-					DeliverTxs: []*abci.ResponseDeliverTx{
-						{Data: []byte{1}},
-						{Data: []byte{2}},
-						{Data: []byte{3}},
-					},
-				})
-				require.NoError(t, err)
-			}
+			saveTestStates(t, stateStore, tc.makeHeights)
 
 			// Test assertions
 			err := stateStore.PruneStates(tc.pruneFrom, tc.pruneTo)
@@ -169,6 +122,16 @@ func TestPruneStates(t *testing.T) {
 			}
 			require.NoError(t, err)
 
+			// heights below the base are reported as pruned
+			base, err := stateStore.Base()
+			require.NoError(t, err)
+			noValSetErr := func(h int64) error {
+				if h < base {
+					return sm.ErrStatePruned{Height: h, Base: base}
+				}
+				return sm.ErrNoValSetForHeight{Height: h}
+			}
+
 			expectVals := sliceToMap(tc.expectVals)
 			expectParams := sliceToMap(tc.expectParams)
 			expectABCI := sliceToMap(tc.expectABCI)
@@ -180,7 +143,7 @@ func TestPruneStates(t *testing.T) {
 					require.NotNil(t, vals)
 				} else {
 					require.Error(t, err, "validators height %v", h)
-					require.Equal(t, sm.ErrNoValSetForHeight{Height: h}, err)
+					require.Equal(t, noValSetErr(h), err)
 				}
 
 				params, err := stateStore.LoadConsensusParams(h)
@@ -204,6 +167,99 @@ func TestPruneStates(t *testing.T) {
 	}
 }
 
+// saveTestStates saves the states up to the given height. Validators change
+// for heights ending with 3, and parameters when ending with 5.
+func saveTestStates(t *testing.T, stateStore sm.Store, height int64) {
+	pk := ed25519.GenPrivKey().PubKey()
+
+	validator := &types.Validator{Address: pk.Address(), VotingPower: 100, PubKey: pk}
+	validatorSet := &types.ValidatorSet{
+		Validators: []*types.Validator{validator},
+		Proposer:   validator,
+	}
+	valsChanged := int64(0)
+	paramsChanged := int64(0)
+
+	for h := int64(1); h <= height; h++ {
+		if valsChanged == 0 || h%10 == 2 {
+			valsChanged = h + 1 // Have to add 1, since NextValidators is what's stored
+		}
+		if paramsChanged == 0 || h%10 == 5 {
+			paramsChanged = h
+		}
+
+		state := sm.State{
+			InitialHeight:   1,
+			LastBlockHeight: h - 1,
+			Validators:      validatorSet,
+			NextValidators:  validatorSet,
+			ConsensusParams: cmtproto.ConsensusParams{
+				Block: cmtproto.BlockParams{MaxBytes: 10e6},
+			},
+			LastHeightValidatorsChanged:      valsChanged,
+			LastHeightConsensusParamsChanged: paramsChanged,
+		}
+
+		if state.LastBlockHeight >= 1 {
+			state.LastValidators = state.Validators
+		}
+
+		err := stateStore.Save(state)
+		require.NoError(t, err)
+
+		err = stateStore.SaveABCIResponses(h, &cmtstate.ABCIResponses{
+			DeliverTxs: []*abci.ResponseDeliverTx{
+				{Data: []byte{1}},
+				{Data: []byte{2}},
+				{Data: []byte{3}},
+			},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestPruneStatesCheckpoints(t *testing.T) {
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{
+		DiscardABCIResponses:      false,
+		PruningCheckpointInterval: 20,
+	})
+	saveTestStates(t, stateStore, 100)
+
+	base, err := stateStore.Base()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, base)
+
+	// pruning in batches keeps every 20th height
+	require.NoError(t, stateStore.PruneStates(1, 30))
+	require.NoError(t, stateStore.PruneStates(30, 70))
+	base, err = stateStore.Base()
+	require.NoError(t, err)
+	require.EqualValues(t, 70, base)
+
+	for _, h := range []int64{20, 40, 60} {
+		vals, err := stateStore.LoadValidators(h)
+		require.NoError(t, err, "validators height %v", h)
+		require.NotNil(t, vals)
+		params, err := stateStore.LoadConsensusParams(h)
+		require.NoError(t, err, "params height %v", h)
+		require.False(t, params.Equal(&cmtproto.ConsensusParams{}))
+	}
+
+	// other heights below the base are reported as pruned
+	_, err = stateStore.LoadValidators(50)
+	require.Equal(t, sm.ErrStatePruned{Height: 50, Base: 70}, err)
+	_, err = stateStore.LoadConsensusParams(31)
+	require.ErrorAs(t, err, &sm.ErrStatePruned{})
+
+	// pruning a range above the base doesn't move it
+	require.NoError(t, stateStore.PruneStates(80, 90))
+	base, err = stateStore.Base()
+	require.NoError(t, err)
+	require.EqualValues(t, 70, base)
+	_, err = stateStore.LoadValidators(85)
+	require.Equal(t, sm.ErrNoValSetForHeight{Height: 85}, err)
+}
+
 func TestABCIResponsesResultsHash(t *testing.T) {
 	responses := &cmtstate.ABCIResponses{
 		BeginBlock: &abci.ResponseBeginBlock{},
@@ -302,3 +358,41 @@ func TestLastABCIResponses(t *testing.T) {
 	})
 
 }
+
+func TestPruneABCIResponses(t *testing.T) {
+	stateDB := dbm.NewMemDB()
+	stateStore := sm.NewStore(stateDB, sm.StoreOptions{DiscardABCIResponses: false})
+	for h := int64(1); h <= 25; h++ {
+		err := stateStore.SaveABCIResponses(h, &cmtstate.ABCIResponses{
+			DeliverTxs: []*abci.ResponseDeliverTx{{Data: []byte{byte(h)}}},
+		})
+		require.NoError(t, err)
+	}
+
+	// responses are not pruned while they are retained
+	pruned, err := stateStore.PruneABCIResponses(10)
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+
+	// once discarded, the stored responses are pruned in batches, lowest
+	// heights first, but the last response is kept
+	discardingStore := sm.NewStore(stateDB, sm.StoreOptions{DiscardABCIResponses: true})
+	pruned, err = discardingStore.PruneABCIResponses(10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, pruned)
+	_, err = stateStore.LoadABCIResponses(10)
+	require.Error(t, err)
+	_, err = stateStore.LoadABCIResponses(11)
+	require.NoError(t, err)
+
+	pruned, err = discardingStore.PruneABCIResponses(100)
+	require.NoError(t, err)
+	assert.EqualValues(t, 15, pruned)
+	pruned, err = discardingStore.PruneABCIResponses(100)
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+
+	last, err := discardingStore.LoadLastABCIResponse(25)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{25}, last.DeliverTxs[0].Data)
+}
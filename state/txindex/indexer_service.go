@@ -2,8 +2,11 @@ package txindex
 
 import (
 	"context"
+	"time"
 
+	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	"github.com/tendermint/tendermint/libs/service"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/state/indexer"
 	"github.com/tendermint/tendermint/types"
 )
@@ -12,6 +15,14 @@ import (
 
 const (
 	subscriber = "IndexerService"
+
+	// subscriptionBufferSize is the number of events of each kind buffered
+	// for the indexer.
+	subscriptionBufferSize = 1000
+
+	// subscriptionBlockTimeout is how long the publishers of the events wait
+	// for the indexer to make room, before its subscriptions are terminated.
+	subscriptionBlockTimeout = time.Minute
 )
 
 // IndexerService connects event bus, transaction and block indexers together in
@@ -23,6 +34,11 @@ type IndexerService struct {
 	blockIdxr        indexer.BlockIndexer
 	eventBus         *types.EventBus
 	terminateOnError bool
+
+	mtx cmtsync.Mutex
+	// err is the reason the subscriptions of the indexer were terminated,
+	// stopping it, nil if they weren't.
+	err error
 }
 
 // NewIndexerService returns a new service instance.
@@ -41,31 +57,50 @@ func NewIndexerService(
 // OnStart implements service.Service by subscribing for all transactions
 // and indexing them by events.
 func (is *IndexerService) OnStart() error {
-	// Use SubscribeUnbuffered here to ensure both subscriptions does not get
-	// canceled due to not pulling messages fast enough. Cause this might
-	// sometimes happen when there are no other subscribers.
-	blockHeadersSub, err := is.eventBus.SubscribeUnbuffered(
+	// The publishers wait for the indexer to make room, so that no event is
+	// missed, but not forever: the subscriptions are terminated if the
+	// indexer is stuck.
+	bufferCfg := cmtpubsub.BufferConfig{
+		Capacity:     subscriptionBufferSize,
+		Overflow:     cmtpubsub.OverflowBlock,
+		BlockTimeout: subscriptionBlockTimeout,
+		Subscriber:   "indexer",
+	}
+	blockHeadersSub, err := is.eventBus.SubscribeBounded(
 		context.Background(),
 		subscriber,
-		types.EventQueryNewBlockHeader)
+		types.EventQueryNewBlockHeader,
+		bufferCfg)
 	if err != nil {
 		return err
 	}
 
-	txsSub, err := is.eventBus.SubscribeUnbuffered(context.Background(), subscriber, types.EventQueryTx)
+	txsSub, err := is.eventBus.SubscribeBounded(context.Background(), subscriber, types.EventQueryTx, bufferCfg)
 	if err != nil {
 		return err
 	}
 
 	go func() {
 		for {
-			msg := <-blockHeadersSub.Out()
+			var msg cmtpubsub.Message
+			select {
+			case msg = <-blockHeadersSub.Out():
+			case <-blockHeadersSub.Cancelled():
+				is.subscriptionCancelled(blockHeadersSub)
+				return
+			}
 			eventDataHeader := msg.Data().(types.EventDataNewBlockHeader)
 			height := eventDataHeader.Header.Height
 			batch := NewBatch(eventDataHeader.NumTxs)
 
 			for i := int64(0); i < eventDataHeader.NumTxs; i++ {
-				msg2 := <-txsSub.Out()
+				var msg2 cmtpubsub.Message
+				select {
+				case msg2 = <-txsSub.Out():
+				case <-txsSub.Cancelled():
+					is.subscriptionCancelled(txsSub)
+					return
+				}
 				txResult := msg2.Data().(types.EventDataTx).TxResult
 
 				if err = batch.Add(&txResult); err != nil {
@@ -113,6 +148,36 @@ func (is *IndexerService) OnStart() error {
 	return nil
 }
 
+// subscriptionCancelled stops the service if sub was terminated, e.g. because
+// the indexer fell behind, since the events missed can't be indexed anymore.
+// The error is reported by Err.
+func (is *IndexerService) subscriptionCancelled(sub types.Subscription) {
+	err := sub.Err()
+	if err == nil || err == cmtpubsub.ErrUnsubscribed {
+		return
+	}
+	is.mtx.Lock()
+	is.err = err
+	is.mtx.Unlock()
+
+	if err == cmtpubsub.ErrOutOfCapacity {
+		is.Logger.Error("The indexer fell behind and missed events, stopping it", "err", err)
+	} else {
+		is.Logger.Error("The subscriptions of the indexer were terminated, stopping it", "err", err)
+	}
+	if err := is.Stop(); err != nil {
+		is.Logger.Error("failed to stop", "err", err)
+	}
+}
+
+// Err returns the reason the indexer stopped indexing the events, e.g.
+// cmtpubsub.ErrOutOfCapacity if it fell behind, or nil if it didn't.
+func (is *IndexerService) Err() error {
+	is.mtx.Lock()
+	defer is.mtx.Unlock()
+	return is.err
+}
+
 // OnStop implements service.Service by unsubscribing from all transactions.
 func (is *IndexerService) OnStop() {
 	if is.eventBus.IsRunning() {
@@ -52,10 +52,11 @@ func validateBlock(state State, block *types.Block) error {
 
 	// Validate app info
 	if !bytes.Equal(block.AppHash, state.AppHash) {
-		return fmt.Errorf("wrong Block.Header.AppHash.  Expected %X, got %v",
-			state.AppHash,
-			block.AppHash,
-		)
+		return ErrAppHashMismatch{
+			Height:        block.Height,
+			AppHash:       state.AppHash,
+			HeaderAppHash: block.AppHash,
+		}
 	}
 	hashCP := types.HashConsensusParams(state.ConsensusParams)
 	if !bytes.Equal(block.ConsensusHash, hashCP) {
@@ -0,0 +1,122 @@
+package statesync
+
+import (
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+// chunkCacheKey identifies a chunk of a snapshot.
+type chunkCacheKey struct {
+	Height uint64
+	Format uint32
+	Index  uint32
+}
+
+// chunkCache is an on-disk cache of the snapshot chunks served to peers, which saves a round-trip
+// to the ABCI app for chunks which were requested before. Chunks are stored as files in a
+// directory, which is emptied when the cache is created. The total size of the cached chunks is
+// bounded, and the least recently used chunks are evicted first.
+type chunkCache struct {
+	dir     string
+	maxSize int64
+
+	mtx     cmtsync.Mutex
+	size    int64
+	lru     *list.List // of chunkCacheEntry, most recently used first
+	entries map[chunkCacheKey]*list.Element
+}
+
+type chunkCacheEntry struct {
+	key  chunkCacheKey
+	size int64
+}
+
+// newChunkCache creates a chunk cache in dir, which holds at most maxSize bytes of chunks.
+func newChunkCache(dir string, maxSize int64) (*chunkCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create chunk cache directory: %w", err)
+	}
+	// chunks cached by a previous run are dropped, since they aren't accounted for
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chunk cache directory: %w", err)
+	}
+	for _, file := range files {
+		if err := os.Remove(filepath.Join(dir, file.Name())); err != nil {
+			return nil, fmt.Errorf("failed to clear chunk cache: %w", err)
+		}
+	}
+	return &chunkCache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: make(map[chunkCacheKey]*list.Element),
+	}, nil
+}
+
+func (c *chunkCache) path(key chunkCacheKey) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%d-%d-%d", key.Height, key.Format, key.Index))
+}
+
+// Get returns a cached chunk, marking it as recently used. It returns false if the chunk isn't
+// cached.
+func (c *chunkCache) Get(key chunkCacheKey) ([]byte, bool) {
+	c.mtx.Lock()
+	elem, ok := c.entries[key]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.mtx.Unlock()
+	if !ok {
+		return nil, false
+	}
+	// the chunk may have been evicted concurrently
+	chunk, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return chunk, true
+}
+
+// Put adds a chunk to the cache, evicting the least recently used chunks if the cache is full.
+// Chunks larger than the cache are not cached.
+func (c *chunkCache) Put(key chunkCacheKey, chunk []byte) error {
+	size := int64(len(chunk))
+	if size > c.maxSize {
+		return nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return nil
+	}
+	if err := os.WriteFile(c.path(key), chunk, 0o600); err != nil {
+		return fmt.Errorf("failed to cache chunk: %w", err)
+	}
+	c.entries[key] = c.lru.PushFront(chunkCacheEntry{key: key, size: size})
+	c.size += size
+
+	for c.size > c.maxSize {
+		elem := c.lru.Back()
+		entry := elem.Value.(chunkCacheEntry)
+		if err := os.Remove(c.path(entry.key)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict chunk from cache: %w", err)
+		}
+		c.lru.Remove(elem)
+		delete(c.entries, entry.key)
+		c.size -= entry.size
+	}
+	return nil
+}
+
+// Size returns the total size of the cached chunks.
+func (c *chunkCache) Size() int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.size
+}
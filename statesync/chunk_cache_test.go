@@ -0,0 +1,77 @@
+package statesync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := newChunkCache(dir, 10)
+	require.NoError(t, err)
+
+	key := func(index uint32) chunkCacheKey { return chunkCacheKey{Height: 1, Format: 1, Index: index} }
+
+	_, ok := cache.Get(key(0))
+	assert.False(t, ok)
+
+	// chunks are populated on first put
+	require.NoError(t, cache.Put(key(0), []byte{0, 0, 0, 0}))
+	require.NoError(t, cache.Put(key(1), []byte{1, 1, 1, 1}))
+	chunk, ok := cache.Get(key(0))
+	require.True(t, ok)
+	assert.Equal(t, []byte{0, 0, 0, 0}, chunk)
+	assert.EqualValues(t, 8, cache.Size())
+
+	// a chunk of another snapshot is a distinct entry
+	_, ok = cache.Get(chunkCacheKey{Height: 2, Format: 1, Index: 0})
+	assert.False(t, ok)
+
+	// chunks which are cached already are not replaced
+	require.NoError(t, cache.Put(key(0), []byte{9, 9, 9, 9}))
+	chunk, ok = cache.Get(key(0))
+	require.True(t, ok)
+	assert.Equal(t, []byte{0, 0, 0, 0}, chunk)
+
+	// the least recently used chunk 1 is evicted when the cache is full
+	require.NoError(t, cache.Put(key(2), []byte{2, 2, 2, 2}))
+	assert.EqualValues(t, 8, cache.Size())
+	_, ok = cache.Get(key(1))
+	assert.False(t, ok)
+	_, err = os.Stat(filepath.Join(dir, "1-1-1"))
+	assert.True(t, os.IsNotExist(err))
+	_, ok = cache.Get(key(0))
+	assert.True(t, ok)
+	_, ok = cache.Get(key(2))
+	assert.True(t, ok)
+
+	// a large chunk evicts several chunks, and chunks larger than the cache aren't cached
+	require.NoError(t, cache.Put(key(3), []byte{3, 3, 3, 3, 3, 3, 3, 3, 3}))
+	assert.EqualValues(t, 9, cache.Size())
+	_, ok = cache.Get(key(0))
+	assert.False(t, ok)
+	_, ok = cache.Get(key(2))
+	assert.False(t, ok)
+	require.NoError(t, cache.Put(key(4), make([]byte, 11)))
+	_, ok = cache.Get(key(4))
+	assert.False(t, ok)
+	assert.EqualValues(t, 9, cache.Size())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	// the cache is emptied when it is created again
+	cache, err = newChunkCache(dir, 10)
+	require.NoError(t, err)
+	_, ok = cache.Get(key(3))
+	assert.False(t, ok)
+	assert.Zero(t, cache.Size())
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
@@ -1,19 +1,24 @@
 package statesync
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
-	"time"
 
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/p2p"
 )
 
-// errDone is returned by chunkQueue.Next() when all chunks have been returned.
-var errDone = errors.New("chunk queue has completed")
+var (
+	// errDone is returned by chunkQueue.Next() when all chunks have been returned.
+	errDone = errors.New("chunk queue has completed")
+	// errChunkHashMismatch is returned by chunkQueue.Add() when a chunk doesn't match its hash.
+	errChunkHashMismatch = errors.New("chunk doesn't match its hash")
+)
 
 // chunk contains data for a chunk.
 type chunk struct {
@@ -35,7 +40,9 @@ type chunkQueue struct {
 	chunkSenders   map[uint32]p2p.ID          // the peer who sent the given chunk
 	chunkAllocated map[uint32]bool            // chunks that have been allocated via Allocate()
 	chunkReturned  map[uint32]bool            // chunks returned via Next()
+	chunkFailed    map[uint32]bool            // chunks which couldn't be fetched, see Fail()
 	waiters        map[uint32][]chan<- uint32 // signals WaitFor() waiters about chunk arrival
+	refetchers     map[uint32][]chan<- uint32 // signals WaitForRefetch() waiters about refetches
 }
 
 // newChunkQueue creates a new chunk queue for a snapshot, using a temp dir for storage.
@@ -55,11 +62,15 @@ func newChunkQueue(snapshot *snapshot, tempDir string) (*chunkQueue, error) {
 		chunkSenders:   make(map[uint32]p2p.ID, snapshot.Chunks),
 		chunkAllocated: make(map[uint32]bool, snapshot.Chunks),
 		chunkReturned:  make(map[uint32]bool, snapshot.Chunks),
+		chunkFailed:    make(map[uint32]bool),
 		waiters:        make(map[uint32][]chan<- uint32),
+		refetchers:     make(map[uint32][]chan<- uint32),
 	}, nil
 }
 
-// Add adds a chunk to the queue. It ignores chunks that already exist, returning false.
+// Add adds a chunk to the queue. It ignores chunks that already exist, returning false. If the
+// snapshot has chunk hashes, chunks which don't match their hash are rejected with
+// errChunkHashMismatch.
 func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 	if chunk == nil || chunk.Chunk == nil {
 		return false, errors.New("cannot add nil chunk")
@@ -78,6 +89,12 @@ func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 	if chunk.Index >= q.snapshot.Chunks {
 		return false, fmt.Errorf("received unexpected chunk %v", chunk.Index)
 	}
+	if len(q.snapshot.ChunkHashes) > 0 {
+		hash := sha256.Sum256(chunk.Chunk)
+		if !bytes.Equal(hash[:], q.snapshot.ChunkHashes[chunk.Index]) {
+			return false, fmt.Errorf("%w: chunk %v from peer %v", errChunkHashMismatch, chunk.Index, chunk.Sender)
+		}
+	}
 	if q.chunkFiles[chunk.Index] != "" {
 		return false, nil
 	}
@@ -96,6 +113,7 @@ func (q *chunkQueue) Add(chunk *chunk) (bool, error) {
 		close(waiter)
 	}
 	delete(q.waiters, chunk.Index)
+	delete(q.refetchers, chunk.Index)
 
 	return true, nil
 }
@@ -121,6 +139,22 @@ func (q *chunkQueue) Allocate() (uint32, error) {
 	return 0, errDone
 }
 
+// Fail marks a chunk as failed, since it couldn't be fetched from any peer. Waiters for the chunk
+// are released, and Next() returns errTimeout once it reaches the chunk. Chunks which have been
+// added to the queue meanwhile can't fail.
+func (q *chunkQueue) Fail(index uint32) {
+	q.Lock()
+	defer q.Unlock()
+	if q.snapshot == nil || q.chunkFiles[index] != "" {
+		return
+	}
+	q.chunkFailed[index] = true
+	for _, waiter := range q.waiters[index] {
+		close(waiter)
+	}
+	delete(q.waiters, index)
+}
+
 // Close closes the chunk queue, cleaning up all temporary files.
 func (q *chunkQueue) Close() error {
 	q.Lock()
@@ -134,6 +168,12 @@ func (q *chunkQueue) Close() error {
 		}
 	}
 	q.waiters = nil
+	for _, refetchers := range q.refetchers {
+		for _, refetcher := range refetchers {
+			close(refetcher)
+		}
+	}
+	q.refetchers = nil
 	q.snapshot = nil
 	err := os.RemoveAll(q.dir)
 	if err != nil {
@@ -223,7 +263,8 @@ func (q *chunkQueue) load(index uint32) (*chunk, error) {
 }
 
 // Next returns the next chunk from the queue, or errDone if all chunks have been returned. It
-// blocks until the chunk is available. Concurrent Next() calls may return the same chunk.
+// blocks until the chunk is available, or returns errTimeout if the chunk failed. Concurrent
+// Next() calls may return the same chunk.
 func (q *chunkQueue) Next() (*chunk, error) {
 	q.Lock()
 	var chunk *chunk
@@ -239,13 +280,13 @@ func (q *chunkQueue) Next() (*chunk, error) {
 		return chunk, err
 	}
 
-	select {
-	case _, ok := <-q.WaitFor(index):
-		if !ok {
-			return nil, errDone // queue closed
+	if _, ok := <-q.WaitFor(index); !ok {
+		q.Lock()
+		defer q.Unlock()
+		if q.chunkFailed[index] {
+			return nil, errTimeout
 		}
-	case <-time.After(chunkTimeout):
-		return nil, errTimeout
+		return nil, errDone // queue closed
 	}
 
 	q.Lock()
@@ -298,7 +339,7 @@ func (q *chunkQueue) Size() uint32 {
 
 // WaitFor returns a channel that receives a chunk index when it arrives in the queue, or
 // immediately if it has already arrived. The channel is closed without a value if the queue is
-// closed or if the chunk index is not valid.
+// closed, if the chunk failed or if the chunk index is not valid.
 func (q *chunkQueue) WaitFor(index uint32) <-chan uint32 {
 	q.Lock()
 	defer q.Unlock()
@@ -306,7 +347,7 @@ func (q *chunkQueue) WaitFor(index uint32) <-chan uint32 {
 	switch {
 	case q.snapshot == nil:
 		close(ch)
-	case index >= q.snapshot.Chunks:
+	case index >= q.snapshot.Chunks, q.chunkFailed[index]:
 		close(ch)
 	case q.chunkFiles[index] != "":
 		ch <- index
@@ -319,3 +360,33 @@ func (q *chunkQueue) WaitFor(index uint32) <-chan uint32 {
 	}
 	return ch
 }
+
+// Refetch signals the fetchers waiting for a chunk via WaitForRefetch() to request it again right
+// away, e.g. because a peer sent a chunk which didn't match its hash. It does nothing if the chunk
+// is in the queue already.
+func (q *chunkQueue) Refetch(index uint32) {
+	q.Lock()
+	defer q.Unlock()
+	if q.snapshot == nil || q.chunkFiles[index] != "" {
+		return
+	}
+	for _, refetcher := range q.refetchers[index] {
+		refetcher <- index
+		close(refetcher)
+	}
+	delete(q.refetchers, index)
+}
+
+// WaitForRefetch returns a channel that receives a chunk index when the chunk is to be refetched,
+// see Refetch(). The channel is closed without a value if the queue is closed.
+func (q *chunkQueue) WaitForRefetch(index uint32) <-chan uint32 {
+	q.Lock()
+	defer q.Unlock()
+	ch := make(chan uint32, 1)
+	if q.snapshot == nil {
+		close(ch)
+		return ch
+	}
+	q.refetchers[index] = append(q.refetchers[index], ch)
+	return ch
+}
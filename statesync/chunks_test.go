@@ -1,6 +1,7 @@
 package statesync
 
 import (
+	"crypto/sha256"
 	"os"
 	"testing"
 
@@ -548,3 +549,93 @@ func TestChunkQueue_WaitFor(t *testing.T) {
 	_, ok = <-w
 	assert.False(t, ok)
 }
+
+func TestChunkQueue_Fail(t *testing.T) {
+	queue, teardown := setupChunkQueue(t)
+	defer teardown()
+
+	_, err := queue.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}})
+	require.NoError(t, err)
+
+	// Failing a chunk releases its waiters, and Next() times out once it reaches the chunk.
+	waitFor1 := queue.WaitFor(1)
+	queue.Fail(1)
+	_, ok := <-waitFor1
+	assert.False(t, ok)
+	_, ok = <-queue.WaitFor(1)
+	assert.False(t, ok)
+
+	c, err := queue.Next()
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, c.Index)
+	_, err = queue.Next()
+	assert.Equal(t, errTimeout, err)
+
+	// A chunk which has been added already can't fail.
+	queue.Fail(0)
+	assert.True(t, queue.Has(0))
+	assert.EqualValues(t, 0, <-queue.WaitFor(0))
+}
+
+func TestChunkQueue_Add_ChunkHashes(t *testing.T) {
+	s := &snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte{7}}
+	for i := byte(0); i < 2; i++ {
+		hash := sha256.Sum256([]byte{3, 1, i})
+		s.ChunkHashes = append(s.ChunkHashes, hash[:])
+	}
+	queue, err := newChunkQueue(s, "")
+	require.NoError(t, err)
+	defer queue.Close()
+
+	// Chunks are verified against their hash
+	added, err := queue.Add(&chunk{Height: 3, Format: 1, Index: 0, Chunk: []byte{3, 1, 0}})
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 1, Chunk: []byte{3, 1, 0}, Sender: "a"})
+	require.ErrorIs(t, err, errChunkHashMismatch)
+	assert.False(t, added)
+	assert.False(t, queue.Has(1))
+
+	added, err = queue.Add(&chunk{Height: 3, Format: 1, Index: 1, Chunk: []byte{3, 1, 1}, Sender: "b"})
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.EqualValues(t, "b", queue.GetSender(1))
+}
+
+func TestChunkQueue_Refetch(t *testing.T) {
+	queue, teardown := setupChunkQueue(t)
+	defer teardown()
+
+	refetch0 := queue.WaitForRefetch(0)
+	refetch1 := queue.WaitForRefetch(1)
+
+	// Refetching a chunk signals its waiters only
+	queue.Refetch(0)
+	assert.EqualValues(t, 0, <-refetch0)
+	_, ok := <-refetch0
+	assert.False(t, ok)
+	select {
+	case <-refetch1:
+		require.Fail(t, "WaitForRefetch(1) should not trigger on 0")
+	default:
+	}
+
+	// Chunks which are in the queue already aren't refetched
+	_, err := queue.Add(&chunk{Height: 3, Format: 1, Index: 1, Chunk: []byte{3, 1, 1}})
+	require.NoError(t, err)
+	refetch1 = queue.WaitForRefetch(1)
+	queue.Refetch(1)
+	select {
+	case <-refetch1:
+		require.Fail(t, "WaitForRefetch(1) should not trigger once the chunk was added")
+	default:
+	}
+
+	// Closing the queue closes the channels
+	err = queue.Close()
+	require.NoError(t, err)
+	_, ok = <-refetch1
+	assert.False(t, ok)
+	_, ok = <-queue.WaitForRefetch(2)
+	assert.False(t, ok)
+}
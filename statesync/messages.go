@@ -1,10 +1,12 @@
 package statesync
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 
 	ssproto "github.com/tendermint/tendermint/proto/tendermint/statesync"
 )
@@ -36,6 +38,9 @@ func validateMsg(pb proto.Message) error {
 		if !msg.Missing && msg.Chunk == nil {
 			return errors.New("chunk cannot be nil")
 		}
+		if msg.Missing && msg.Compressed {
+			return errors.New("missing chunk cannot be compressed")
+		}
 	case *ssproto.SnapshotsRequest:
 	case *ssproto.SnapshotsResponse:
 		if msg.Height == 0 {
@@ -47,8 +52,34 @@ func validateMsg(pb proto.Message) error {
 		if msg.Chunks == 0 {
 			return errors.New("snapshot has no chunks")
 		}
+		//nolint:gosec
+		if len(msg.ChunkHashes) > 0 && uint32(len(msg.ChunkHashes)) != msg.Chunks {
+			return fmt.Errorf("snapshot has %v chunk hashes, expected %v", len(msg.ChunkHashes), msg.Chunks)
+		}
+		for i, hash := range msg.ChunkHashes {
+			if len(hash) != sha256.Size {
+				return fmt.Errorf("invalid hash of chunk %v", i)
+			}
+		}
 	default:
 		return fmt.Errorf("unknown message type %T", msg)
 	}
 	return nil
 }
+
+// decompressChunk decompresses a snappy-compressed chunk. Like uncompressed chunks, the
+// decompressed chunk can't be larger than chunkMsgSize.
+func decompressChunk(compressed []byte) ([]byte, error) {
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed chunk: %w", err)
+	}
+	if size > chunkMsgSize {
+		return nil, fmt.Errorf("decompressed chunk size %d exceeds the maximum of %d bytes", size, chunkMsgSize)
+	}
+	chunk, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed chunk: %w", err)
+	}
+	return chunk, nil
+}
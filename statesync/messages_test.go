@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendermint/tendermint/p2p"
@@ -52,6 +53,12 @@ func TestValidateMsg(t *testing.T) {
 		"ChunkResponse missing with body": {
 			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Missing: true, Chunk: []byte{1}},
 			false},
+		"ChunkResponse compressed": {
+			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: []byte{1}, Compressed: true},
+			true},
+		"ChunkResponse missing compressed": {
+			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Missing: true, Compressed: true},
+			false},
 
 		"SnapshotsRequest valid": {&ssproto.SnapshotsRequest{}, true},
 
@@ -70,6 +77,18 @@ func TestValidateMsg(t *testing.T) {
 		"SnapshotsResponse no hash": {
 			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{}},
 			false},
+		"SnapshotsResponse chunk hashes": {
+			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1},
+				ChunkHashes: [][]byte{make([]byte, 32), make([]byte, 32)}},
+			true},
+		"SnapshotsResponse too few chunk hashes": {
+			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1},
+				ChunkHashes: [][]byte{make([]byte, 32)}},
+			false},
+		"SnapshotsResponse invalid chunk hash": {
+			&ssproto.SnapshotsResponse{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1},
+				ChunkHashes: [][]byte{make([]byte, 32), {1}}},
+			false},
 	}
 	for name, tc := range testcases {
 		tc := tc
@@ -85,6 +104,20 @@ func TestValidateMsg(t *testing.T) {
 }
 
 //nolint:lll // ignore line length
+func TestDecompressChunk(t *testing.T) {
+	chunk := []byte("a chunk, a chunk, a chunk, a chunk")
+	decompressed, err := decompressChunk(snappy.Encode(nil, chunk))
+	require.NoError(t, err)
+	require.Equal(t, chunk, decompressed)
+
+	_, err = decompressChunk([]byte("not snappy"))
+	require.Error(t, err)
+
+	// chunks larger than chunkMsgSize are rejected before they are decompressed
+	_, err = decompressChunk(snappy.Encode(nil, make([]byte, chunkMsgSize+1)))
+	require.Error(t, err)
+}
+
 func TestStateSyncVectors(t *testing.T) {
 
 	testCases := []struct {
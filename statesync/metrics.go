@@ -0,0 +1,131 @@
+package statesync
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "statesync"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of snapshot chunks requested from peers and not received yet.
+	ChunksInFlight metrics.Gauge
+	// Number of snapshot chunk requests which timed out and were retried.
+	ChunkRetries metrics.Counter
+	// Rate at which snapshot chunks are received, in bytes per second.
+	ChunkFetchRate metrics.Gauge
+	// Rate at which a peer served the snapshot chunks requested from it, in
+	// bytes per second, counting timed out requests.
+	PeerChunkThroughput metrics.Gauge
+	// Number of snapshot chunk requests to a peer which timed out.
+	PeerChunkFailures metrics.Counter
+	// Number of snapshot peers no chunks are requested from, since they
+	// serve chunks far slower than the others.
+	SlowPeers metrics.Gauge
+	// Size of the snapshot chunks written to the snapshot pool, in bytes.
+	SnapshotPoolSize metrics.Gauge
+	// Number of chunk requests served from the snapshot pool.
+	SnapshotPoolHits metrics.Counter
+	// Number of chunk requests which couldn't be served from the snapshot pool.
+	SnapshotPoolMisses metrics.Counter
+	// Time spent writing a new snapshot to the snapshot pool, in seconds.
+	SnapshotPoolRefreshDuration metrics.Histogram
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		ChunksInFlight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunks_in_flight",
+			Help:      "Number of snapshot chunks requested from peers and not received yet.",
+		}, labels).With(labelsAndValues...),
+		ChunkRetries: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunk_retries",
+			Help:      "Number of snapshot chunk requests which timed out and were retried.",
+		}, labels).With(labelsAndValues...),
+		ChunkFetchRate: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "chunk_fetch_rate",
+			Help:      "Rate at which snapshot chunks are received, in bytes per second.",
+		}, labels).With(labelsAndValues...),
+		PeerChunkThroughput: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_chunk_throughput",
+			Help:      "Rate at which a peer served the snapshot chunks requested from it, in bytes per second, counting timed out requests.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+		PeerChunkFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "peer_chunk_failures",
+			Help:      "Number of snapshot chunk requests to a peer which timed out.",
+		}, append(labels, "peer_id")).With(labelsAndValues...),
+		SlowPeers: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "slow_peers",
+			Help:      "Number of snapshot peers no chunks are requested from, since they serve chunks far slower than the others.",
+		}, labels).With(labelsAndValues...),
+		SnapshotPoolSize: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshot_pool_size",
+			Help:      "Size of the snapshot chunks written to the snapshot pool, in bytes.",
+		}, labels).With(labelsAndValues...),
+		SnapshotPoolHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshot_pool_hits",
+			Help:      "Number of chunk requests served from the snapshot pool.",
+		}, labels).With(labelsAndValues...),
+		SnapshotPoolMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshot_pool_misses",
+			Help:      "Number of chunk requests which couldn't be served from the snapshot pool.",
+		}, labels).With(labelsAndValues...),
+		SnapshotPoolRefreshDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "snapshot_pool_refresh_duration_seconds",
+			Help:      "Time spent writing a new snapshot to the snapshot pool, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.1, 2, 12),
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		ChunksInFlight: discard.NewGauge(),
+		ChunkRetries:   discard.NewCounter(),
+		ChunkFetchRate: discard.NewGauge(),
+
+		PeerChunkThroughput: discard.NewGauge(),
+		PeerChunkFailures:   discard.NewCounter(),
+		SlowPeers:           discard.NewGauge(),
+
+		SnapshotPoolSize:            discard.NewGauge(),
+		SnapshotPoolHits:            discard.NewCounter(),
+		SnapshotPoolMisses:          discard.NewCounter(),
+		SnapshotPoolRefreshDuration: discard.NewHistogram(),
+	}
+}
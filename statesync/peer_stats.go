@@ -0,0 +1,70 @@
+package statesync
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tendermint/tendermint/p2p"
+)
+
+const (
+	// minPeerChunkRequests is the number of chunk requests to a peer after which its throughput
+	// is compared to the other peers.
+	minPeerChunkRequests = 3
+	// slowPeerThroughputRatio is the fraction of the median throughput of the peers below which
+	// a peer is slow, and no further chunks are requested from it.
+	slowPeerThroughputRatio = 0.1
+	// minActiveChunkPeers is the number of peers serving chunks below which more snapshot peers
+	// are discovered.
+	minActiveChunkPeers = 3
+)
+
+var snapshotsRequestInterval = 10 * time.Second // not const so we can override with tests
+
+// peerChunkStats are the statistics of the chunk requests to a peer during a restoration.
+type peerChunkStats struct {
+	chunks    int           // requests answered by the peer
+	failures  int           // requests which timed out
+	bytes     int64         // size of the chunks received from the peer
+	fetchTime time.Duration // total time of the requests, the timeout for timed out ones
+}
+
+func (st *peerChunkStats) requests() int {
+	return st.chunks + st.failures
+}
+
+// throughput returns the rate at which the peer served the chunks requested from it, in bytes
+// per second.
+func (st *peerChunkStats) throughput() float64 {
+	if st.requests() == 0 {
+		return 0
+	}
+	// chunks served instantly count as served in a millisecond
+	return float64(st.bytes) / math.Max(st.fetchTime.Seconds(), 0.001)
+}
+
+// slowPeers returns the peers whose throughput is far below the median throughput of the peers
+// with enough requests to be compared, or nil if fewer than two peers can be compared.
+func slowPeers(stats map[p2p.ID]*peerChunkStats) map[p2p.ID]bool {
+	throughputs := make(map[p2p.ID]float64, len(stats))
+	sorted := make([]float64, 0, len(stats))
+	for id, st := range stats {
+		if st.requests() >= minPeerChunkRequests {
+			throughputs[id] = st.throughput()
+			sorted = append(sorted, throughputs[id])
+		}
+	}
+	if len(sorted) < 2 {
+		return nil
+	}
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+	slow := make(map[p2p.ID]bool)
+	for id, throughput := range throughputs {
+		if throughput < median*slowPeerThroughputRatio {
+			slow[id] = true
+		}
+	}
+	return slow
+}
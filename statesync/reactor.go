@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
@@ -22,7 +23,7 @@ const (
 	SnapshotChannel = byte(0x60)
 	// ChunkChannel exchanges chunk contents
 	ChunkChannel = byte(0x61)
-	// recentSnapshots is the number of recent snapshots to send and receive per peer.
+	// recentSnapshots is the number of recent snapshots to send and receive per peer and format.
 	recentSnapshots = 10
 )
 
@@ -35,6 +36,18 @@ type Reactor struct {
 	conn      proxy.AppConnSnapshot
 	connQuery proxy.AppConnQuery
 	tempDir   string
+	metrics   *Metrics
+
+	// chunkCache caches the chunks served to peers, if enabled via WithChunkCache.
+	chunkCacheDir  string
+	chunkCacheSize int64
+	chunkCache     *chunkCache
+
+	// servingPool holds the chunks of the most recent snapshot, if enabled via WithSnapshotPool.
+	servingPoolDir      string
+	servingPoolSize     int64
+	servingPoolInterval time.Duration
+	servingPool         *servingPool
 
 	// This will only be set when a state sync is in progress. It is used to feed received
 	// snapshots and chunks into the sync.
@@ -42,20 +55,53 @@ type Reactor struct {
 	syncer *syncer
 }
 
+// ReactorOption sets an optional parameter on the Reactor.
+type ReactorOption func(*Reactor)
+
+// WithMetrics sets the metrics of the reactor.
+func WithMetrics(metrics *Metrics) ReactorOption {
+	return func(r *Reactor) { r.metrics = metrics }
+}
+
+// WithChunkCache enables an on-disk cache in dir for the snapshot chunks served to peers, which
+// holds at most size bytes of chunks. The directory is emptied when the reactor is started.
+func WithChunkCache(dir string, size int64) ReactorOption {
+	return func(r *Reactor) {
+		r.chunkCacheDir = dir
+		r.chunkCacheSize = size
+	}
+}
+
+// WithSnapshotPool enables writing the chunks of the most recent local snapshot of at most size
+// bytes to dir, from which they are served to peers. The app is checked for a new snapshot every
+// interval. The directory is emptied when the reactor is started.
+func WithSnapshotPool(dir string, size int64, interval time.Duration) ReactorOption {
+	return func(r *Reactor) {
+		r.servingPoolDir = dir
+		r.servingPoolSize = size
+		r.servingPoolInterval = interval
+	}
+}
+
 // NewReactor creates a new state sync reactor.
 func NewReactor(
 	cfg config.StateSyncConfig,
 	conn proxy.AppConnSnapshot,
 	connQuery proxy.AppConnQuery,
 	tempDir string,
+	options ...ReactorOption,
 ) *Reactor {
 
 	r := &Reactor{
 		cfg:       cfg,
 		conn:      conn,
 		connQuery: connQuery,
+		metrics:   NopMetrics(),
 	}
 	r.BaseReactor = *p2p.NewBaseReactor("StateSync", r)
+	for _, option := range options {
+		option(r)
+	}
 
 	return r
 }
@@ -82,9 +128,41 @@ func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
 
 // OnStart implements p2p.Reactor.
 func (r *Reactor) OnStart() error {
+	if r.chunkCacheDir != "" && r.chunkCache == nil {
+		cache, err := newChunkCache(r.chunkCacheDir, r.chunkCacheSize)
+		if err != nil {
+			return err
+		}
+		r.chunkCache = cache
+	}
+	if r.servingPoolDir != "" && r.servingPool == nil {
+		pool, err := newServingPool(r.servingPoolDir, r.servingPoolSize, r.conn, r.metrics, r.Logger)
+		if err != nil {
+			return err
+		}
+		r.servingPool = pool
+		go r.refreshServingPoolRoutine()
+	}
 	return nil
 }
 
+// refreshServingPoolRoutine writes new snapshots of the app to the snapshot pool, until the
+// reactor is stopped.
+func (r *Reactor) refreshServingPoolRoutine() {
+	ticker := time.NewTicker(r.servingPoolInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.servingPool.Refresh(); err != nil {
+			r.Logger.Error("Failed to refresh snapshot pool", "err", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-r.Quit():
+			return
+		}
+	}
+}
+
 // AddPeer implements p2p.Reactor.
 func (r *Reactor) AddPeer(peer p2p.Peer) {
 	r.mtx.RLock()
@@ -120,7 +198,7 @@ func (r *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 	case SnapshotChannel:
 		switch msg := e.Message.(type) {
 		case *ssproto.SnapshotsRequest:
-			snapshots, err := r.recentSnapshots(recentSnapshots)
+			snapshots, err := r.recentSnapshots(recentSnapshots, msg.Formats)
 			if err != nil {
 				r.Logger.Error("Failed to fetch snapshots", "err", err)
 				return
@@ -131,11 +209,13 @@ func (r *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 				p2p.SendEnvelopeShim(e.Src, p2p.Envelope{ //nolint: staticcheck
 					ChannelID: e.ChannelID,
 					Message: &ssproto.SnapshotsResponse{
-						Height:   snapshot.Height,
-						Format:   snapshot.Format,
-						Chunks:   snapshot.Chunks,
-						Hash:     snapshot.Hash,
-						Metadata: snapshot.Metadata,
+						Height:           snapshot.Height,
+						Format:           snapshot.Format,
+						Chunks:           snapshot.Chunks,
+						Hash:             snapshot.Hash,
+						Metadata:         snapshot.Metadata,
+						CompressedChunks: r.cfg.CompressChunks,
+						ChunkHashes:      snapshot.ChunkHashes,
 					},
 				}, r.Logger)
 			}
@@ -149,11 +229,12 @@ func (r *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 			}
 			r.Logger.Debug("Received snapshot", "height", msg.Height, "format", msg.Format, "peer", e.Src.ID())
 			_, err := r.syncer.AddSnapshot(e.Src, &snapshot{
-				Height:   msg.Height,
-				Format:   msg.Format,
-				Chunks:   msg.Chunks,
-				Hash:     msg.Hash,
-				Metadata: msg.Metadata,
+				Height:      msg.Height,
+				Format:      msg.Format,
+				Chunks:      msg.Chunks,
+				Hash:        msg.Hash,
+				Metadata:    msg.Metadata,
+				ChunkHashes: msg.ChunkHashes,
 			})
 			// TODO: We may want to consider punishing the peer for certain errors
 			if err != nil {
@@ -161,6 +242,9 @@ func (r *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 					"peer", e.Src.ID(), "err", err)
 				return
 			}
+			if msg.CompressedChunks {
+				r.syncer.AddCompressionPeer(e.Src.ID())
+			}
 
 		default:
 			r.Logger.Error("Received unknown message %T", msg)
@@ -171,45 +255,64 @@ func (r *Reactor) ReceiveEnvelope(e p2p.Envelope) {
 		case *ssproto.ChunkRequest:
 			r.Logger.Debug("Received chunk request", "height", msg.Height, "format", msg.Format,
 				"chunk", msg.Index, "peer", e.Src.ID())
-			resp, err := r.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
-				Height: msg.Height,
-				Format: msg.Format,
-				Chunk:  msg.Index,
-			})
+			chunk, err := r.loadChunk(msg)
 			if err != nil {
 				r.Logger.Error("Failed to load chunk", "height", msg.Height, "format", msg.Format,
 					"chunk", msg.Index, "err", err)
 				return
 			}
+			resp := &ssproto.ChunkResponse{
+				Height:  msg.Height,
+				Format:  msg.Format,
+				Index:   msg.Index,
+				Chunk:   chunk,
+				Missing: chunk == nil,
+			}
+			if msg.Compressed && r.cfg.CompressChunks && chunk != nil {
+				resp.Chunk = snappy.Encode(nil, chunk)
+				resp.Compressed = true
+			}
 			r.Logger.Debug("Sending chunk", "height", msg.Height, "format", msg.Format,
-				"chunk", msg.Index, "peer", e.Src.ID())
+				"chunk", msg.Index, "peer", e.Src.ID(), "compressed", resp.Compressed)
 			p2p.SendEnvelopeShim(e.Src, p2p.Envelope{ //nolint: staticcheck
 				ChannelID: ChunkChannel,
-				Message: &ssproto.ChunkResponse{
-					Height:  msg.Height,
-					Format:  msg.Format,
-					Index:   msg.Index,
-					Chunk:   resp.Chunk,
-					Missing: resp.Chunk == nil,
-				},
+				Message:   resp,
 			}, r.Logger)
 
 		case *ssproto.ChunkResponse:
+			// The lock isn't held while adding the chunk, since stopping the peer for an invalid
+			// chunk removes it from the syncer via RemovePeer(), which takes the lock as well.
 			r.mtx.RLock()
-			defer r.mtx.RUnlock()
-			if r.syncer == nil {
+			syncer := r.syncer
+			r.mtx.RUnlock()
+			if syncer == nil {
 				r.Logger.Debug("Received unexpected chunk, no state sync in progress", "peer", e.Src.ID())
 				return
 			}
 			r.Logger.Debug("Received chunk, adding to sync", "height", msg.Height, "format", msg.Format,
 				"chunk", msg.Index, "peer", e.Src.ID())
-			_, err := r.syncer.AddChunk(&chunk{
+			body := msg.Chunk
+			if msg.Compressed {
+				body, err = decompressChunk(msg.Chunk)
+				if err != nil {
+					r.Logger.Error("Invalid compressed chunk", "peer", e.Src, "chunk", msg.Index, "err", err)
+					r.Switch.StopPeerForError(e.Src, err)
+					return
+				}
+			}
+			_, err = syncer.AddChunk(&chunk{
 				Height: msg.Height,
 				Format: msg.Format,
 				Index:  msg.Index,
-				Chunk:  msg.Chunk,
+				Chunk:  body,
 				Sender: e.Src.ID(),
 			})
+			if errors.Is(err, errChunkHashMismatch) {
+				r.Logger.Error("Received chunk not matching its hash", "peer", e.Src, "height", msg.Height,
+					"format", msg.Format, "chunk", msg.Index)
+				r.Switch.StopPeerForError(e.Src, err)
+				return
+			}
 			if err != nil {
 				r.Logger.Error("Failed to add chunk", "height", msg.Height, "format", msg.Format,
 					"chunk", msg.Index, "err", err)
@@ -243,8 +346,41 @@ func (r *Reactor) Receive(chID byte, peer p2p.Peer, msgBytes []byte) {
 	})
 }
 
-// recentSnapshots fetches the n most recent snapshots from the app
-func (r *Reactor) recentSnapshots(n uint32) ([]*snapshot, error) {
+// loadChunk loads a chunk requested by a peer from the snapshot pool or the chunk cache, if
+// enabled, or otherwise from the app. Chunks loaded from the app are added to the cache. It
+// returns nil if the chunk is missing.
+func (r *Reactor) loadChunk(msg *ssproto.ChunkRequest) ([]byte, error) {
+	if r.servingPool != nil {
+		if chunk, ok := r.servingPool.Get(msg.Height, msg.Format, msg.Index); ok {
+			return chunk, nil
+		}
+	}
+	key := chunkCacheKey{Height: msg.Height, Format: msg.Format, Index: msg.Index}
+	if r.chunkCache != nil {
+		if chunk, ok := r.chunkCache.Get(key); ok {
+			return chunk, nil
+		}
+	}
+	resp, err := r.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if r.chunkCache != nil && resp.Chunk != nil {
+		if err := r.chunkCache.Put(key, resp.Chunk); err != nil {
+			r.Logger.Error("Failed to cache chunk", "height", msg.Height, "format", msg.Format,
+				"chunk", msg.Index, "err", err)
+		}
+	}
+	return resp.Chunk, nil
+}
+
+// recentSnapshots fetches the n most recent snapshots of every format from the app. If formats are
+// given, only snapshots in these formats are returned.
+func (r *Reactor) recentSnapshots(n uint32, formats []uint32) ([]*snapshot, error) {
 	resp, err := r.conn.ListSnapshotsSync(abci.RequestListSnapshots{})
 	if err != nil {
 		return nil, err
@@ -261,17 +397,27 @@ func (r *Reactor) recentSnapshots(n uint32) ([]*snapshot, error) {
 			return false
 		}
 	})
+	supported := make(map[uint32]bool, len(formats))
+	for _, format := range formats {
+		supported[format] = true
+	}
 	snapshots := make([]*snapshot, 0, n)
-	for i, s := range resp.Snapshots {
-		if i >= recentSnapshots {
-			break
+	perFormat := make(map[uint32]uint32)
+	for _, s := range resp.Snapshots {
+		if len(supported) > 0 && !supported[s.Format] {
+			continue
+		}
+		if perFormat[s.Format] >= n {
+			continue
 		}
+		perFormat[s.Format]++
 		snapshots = append(snapshots, &snapshot{
-			Height:   s.Height,
-			Format:   s.Format,
-			Chunks:   s.Chunks,
-			Hash:     s.Hash,
-			Metadata: s.Metadata,
+			Height:      s.Height,
+			Format:      s.Format,
+			Chunks:      s.Chunks,
+			Hash:        s.Hash,
+			Metadata:    s.Metadata,
+			ChunkHashes: s.ChunkHashes,
 		})
 	}
 	return snapshots, nil
@@ -285,7 +431,7 @@ func (r *Reactor) Sync(stateProvider StateProvider, discoveryTime time.Duration)
 		r.mtx.Unlock()
 		return sm.State{}, nil, errors.New("a state sync is already in progress")
 	}
-	r.syncer = newSyncer(r.cfg, r.Logger, r.conn, r.connQuery, stateProvider, r.tempDir)
+	r.syncer = newSyncer(r.cfg, r.Logger, r.conn, r.connQuery, stateProvider, r.tempDir, r.metrics)
 	r.mtx.Unlock()
 
 	hook := func() {
@@ -294,7 +440,7 @@ func (r *Reactor) Sync(stateProvider StateProvider, discoveryTime time.Duration)
 
 		r.Switch.BroadcastEnvelope(p2p.Envelope{
 			ChannelID: SnapshotChannel,
-			Message:   &ssproto.SnapshotsRequest{},
+			Message:   &ssproto.SnapshotsRequest{Formats: r.cfg.SnapshotFormats},
 		})
 	}
 
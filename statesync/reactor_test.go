@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -21,19 +22,45 @@ func TestReactor_Receive_ChunkRequest(t *testing.T) {
 	testcases := map[string]struct {
 		request        *ssproto.ChunkRequest
 		chunk          []byte
+		compress       bool
 		expectResponse *ssproto.ChunkResponse
 	}{
 		"chunk is returned": {
 			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1},
 			[]byte{1, 2, 3},
+			false,
 			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: []byte{1, 2, 3}}},
 		"empty chunk is returned, as nil": {
 			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1},
 			[]byte{},
+			false,
 			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: nil}},
 		"nil (missing) chunk is returned as missing": {
 			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1},
 			nil,
+			false,
+			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Missing: true},
+		},
+		"compressed chunk is returned if requested": {
+			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1, Compressed: true},
+			[]byte{1, 2, 3},
+			true,
+			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: snappy.Encode(nil, []byte{1, 2, 3}),
+				Compressed: true}},
+		"uncompressed chunk is returned if not requested": {
+			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1},
+			[]byte{1, 2, 3},
+			true,
+			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: []byte{1, 2, 3}}},
+		"uncompressed chunk is returned if compression is disabled": {
+			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1, Compressed: true},
+			[]byte{1, 2, 3},
+			false,
+			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Chunk: []byte{1, 2, 3}}},
+		"missing chunk is not compressed": {
+			&ssproto.ChunkRequest{Height: 1, Format: 1, Index: 1, Compressed: true},
+			nil,
+			true,
 			&ssproto.ChunkResponse{Height: 1, Format: 1, Index: 1, Missing: true},
 		},
 	}
@@ -71,6 +98,7 @@ func TestReactor_Receive_ChunkRequest(t *testing.T) {
 
 			// Start a reactor and send a ssproto.ChunkRequest, then wait for and check response
 			cfg := config.DefaultStateSyncConfig()
+			cfg.CompressChunks = tc.compress
 			r := NewReactor(*cfg, conn, nil, "")
 			err := r.Start()
 			require.NoError(t, err)
@@ -94,13 +122,131 @@ func TestReactor_Receive_ChunkRequest(t *testing.T) {
 	}
 }
 
+func TestReactor_Receive_ChunkRequest_Cache(t *testing.T) {
+	// The app serves every chunk once, further requests must be served from the cache.
+	conn := &proxymocks.AppConnSnapshot{}
+	for i := uint32(0); i < 3; i++ {
+		conn.On("LoadSnapshotChunkSync", abci.RequestLoadSnapshotChunk{Height: 1, Format: 1, Chunk: i}).
+			Return(&abci.ResponseLoadSnapshotChunk{Chunk: []byte{byte(i), 1, 2, 3}}, nil).Once()
+	}
+
+	responses := make(chan *ssproto.ChunkResponse, 10)
+	peer := &p2pmocks.PeerEnvelopeSender{}
+	peer.On("ID").Return(p2p.ID("id"))
+	peer.On("SendEnvelope", mock.Anything).Run(func(args mock.Arguments) {
+		responses <- args[0].(p2p.Envelope).Message.(*ssproto.ChunkResponse)
+	}).Return(true)
+
+	cfg := config.DefaultStateSyncConfig()
+	cfg.CompressChunks = true
+	r := NewReactor(*cfg, conn, nil, "", WithChunkCache(t.TempDir(), 8))
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	request := func(index uint32, compressed bool) *ssproto.ChunkResponse {
+		r.ReceiveEnvelope(p2p.Envelope{
+			ChannelID: ChunkChannel,
+			Src:       peer,
+			Message:   &ssproto.ChunkRequest{Height: 1, Format: 1, Index: index, Compressed: compressed},
+		})
+		select {
+		case resp := <-responses:
+			return resp
+		case <-time.After(time.Second):
+			require.FailNow(t, "timed out waiting for chunk response")
+			return nil
+		}
+	}
+
+	// the first request populates the cache, the following ones bypass the app, both for peers
+	// requesting compressed and uncompressed chunks
+	assert.Equal(t, []byte{0, 1, 2, 3}, request(0, false).Chunk)
+	assert.Equal(t, []byte{0, 1, 2, 3}, request(0, false).Chunk)
+	resp := request(0, true)
+	assert.True(t, resp.Compressed)
+	assert.Equal(t, snappy.Encode(nil, []byte{0, 1, 2, 3}), resp.Chunk)
+	assert.Equal(t, snappy.Encode(nil, []byte{1, 1, 2, 3}), request(1, true).Chunk)
+	assert.Equal(t, []byte{1, 1, 2, 3}, request(1, false).Chunk)
+	assert.EqualValues(t, 8, r.chunkCache.Size())
+
+	// chunk 2 evicts the least recently used chunk 0, which can't be loaded from the app again
+	assert.Equal(t, []byte{2, 1, 2, 3}, request(2, false).Chunk)
+	assert.Equal(t, []byte{1, 1, 2, 3}, request(1, false).Chunk)
+	assert.Equal(t, []byte{2, 1, 2, 3}, request(2, false).Chunk)
+	conn.AssertExpectations(t)
+	conn.AssertNumberOfCalls(t, "LoadSnapshotChunkSync", 3)
+}
+
+func TestReactor_Receive_ChunkRequest_SnapshotPool(t *testing.T) {
+	// The chunks of the most recent snapshot are loaded once when it is pooled, and chunk requests
+	// are served from disk without calling the app.
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("ListSnapshotsSync", abci.RequestListSnapshots{}).Return(&abci.ResponseListSnapshots{
+		Snapshots: []*abci.Snapshot{{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1}}},
+	}, nil)
+	for i := uint32(0); i < 2; i++ {
+		conn.On("LoadSnapshotChunkSync", abci.RequestLoadSnapshotChunk{Height: 1, Format: 1, Chunk: i}).
+			Return(&abci.ResponseLoadSnapshotChunk{Chunk: []byte{byte(i), 1, 2, 3}}, nil).Once()
+	}
+
+	responses := make(chan *ssproto.ChunkResponse, 10)
+	peer := &p2pmocks.PeerEnvelopeSender{}
+	peer.On("ID").Return(p2p.ID("id"))
+	peer.On("SendEnvelope", mock.Anything).Run(func(args mock.Arguments) {
+		responses <- args[0].(p2p.Envelope).Message.(*ssproto.ChunkResponse)
+	}).Return(true)
+
+	cfg := config.DefaultStateSyncConfig()
+	r := NewReactor(*cfg, conn, nil, "", WithSnapshotPool(t.TempDir(), 1024, time.Hour))
+	require.NoError(t, r.Start())
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	require.Eventually(t, func() bool { return r.servingPool.Snapshot() != nil }, time.Second, 10*time.Millisecond)
+
+	for i := 0; i < 3; i++ {
+		for index := uint32(0); index < 2; index++ {
+			r.ReceiveEnvelope(p2p.Envelope{
+				ChannelID: ChunkChannel,
+				Src:       peer,
+				Message:   &ssproto.ChunkRequest{Height: 1, Format: 1, Index: index},
+			})
+			select {
+			case resp := <-responses:
+				assert.Equal(t, []byte{byte(index), 1, 2, 3}, resp.Chunk)
+			case <-time.After(time.Second):
+				require.FailNow(t, "timed out waiting for chunk response")
+			}
+		}
+	}
+	conn.AssertExpectations(t)
+	conn.AssertNumberOfCalls(t, "LoadSnapshotChunkSync", 2)
+}
+
 func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 	testcases := map[string]struct {
+		formats         []uint32
 		snapshots       []*abci.Snapshot
 		expectResponses []*ssproto.SnapshotsResponse
 	}{
-		"no snapshots": {nil, []*ssproto.SnapshotsResponse{}},
-		">10 unordered snapshots": {
+		"no snapshots": {nil, nil, []*ssproto.SnapshotsResponse{}},
+		"snapshot with chunk hashes": {
+			nil,
+			[]*abci.Snapshot{
+				{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1}, ChunkHashes: [][]byte{{1}, {2}}},
+			},
+			[]*ssproto.SnapshotsResponse{
+				{Height: 1, Format: 1, Chunks: 2, Hash: []byte{1}, ChunkHashes: [][]byte{{1}, {2}}},
+			},
+		},
+		"unordered snapshots in multiple formats": {
+			nil,
 			[]*abci.Snapshot{
 				{Height: 1, Format: 2, Chunks: 7, Hash: []byte{1, 2}, Metadata: []byte{1}},
 				{Height: 2, Format: 2, Chunks: 7, Hash: []byte{2, 2}, Metadata: []byte{2}},
@@ -126,7 +272,41 @@ func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 				{Height: 2, Format: 1, Chunks: 7, Hash: []byte{2, 1}, Metadata: []byte{5}},
 				{Height: 1, Format: 4, Chunks: 7, Hash: []byte{1, 4}, Metadata: []byte{7}},
 				{Height: 1, Format: 3, Chunks: 7, Hash: []byte{1, 3}, Metadata: []byte{10}},
+				{Height: 1, Format: 2, Chunks: 7, Hash: []byte{1, 2}, Metadata: []byte{1}},
+				{Height: 1, Format: 1, Chunks: 7, Hash: []byte{1, 1}, Metadata: []byte{4}},
+			},
+		},
+		"only requested formats": {
+			[]uint32{1, 3},
+			[]*abci.Snapshot{
+				{Height: 1, Format: 1, Chunks: 7, Hash: []byte{1, 1}},
+				{Height: 1, Format: 2, Chunks: 7, Hash: []byte{1, 2}},
+				{Height: 1, Format: 3, Chunks: 7, Hash: []byte{1, 3}},
+				{Height: 2, Format: 2, Chunks: 7, Hash: []byte{2, 2}},
 			},
+			[]*ssproto.SnapshotsResponse{
+				{Height: 1, Format: 3, Chunks: 7, Hash: []byte{1, 3}},
+				{Height: 1, Format: 1, Chunks: 7, Hash: []byte{1, 1}},
+			},
+		},
+		">10 snapshots per format": {
+			nil,
+			func() []*abci.Snapshot {
+				snapshots := []*abci.Snapshot{}
+				for h := uint64(1); h <= 12; h++ {
+					snapshots = append(snapshots,
+						&abci.Snapshot{Height: h, Format: 1, Chunks: 1, Hash: []byte{byte(h)}})
+				}
+				return append(snapshots, &abci.Snapshot{Height: 1, Format: 2, Chunks: 1, Hash: []byte{1}})
+			}(),
+			func() []*ssproto.SnapshotsResponse {
+				responses := []*ssproto.SnapshotsResponse{}
+				for h := uint64(12); h >= 3; h-- {
+					responses = append(responses,
+						&ssproto.SnapshotsResponse{Height: h, Format: 1, Chunks: 1, Hash: []byte{byte(h)}})
+				}
+				return append(responses, &ssproto.SnapshotsResponse{Height: 1, Format: 2, Chunks: 1, Hash: []byte{1}})
+			}(),
 		},
 	}
 
@@ -173,7 +353,7 @@ func TestReactor_Receive_SnapshotsRequest(t *testing.T) {
 			r.ReceiveEnvelope(p2p.Envelope{
 				ChannelID: SnapshotChannel,
 				Src:       peer,
-				Message:   &ssproto.SnapshotsRequest{},
+				Message:   &ssproto.SnapshotsRequest{Formats: tc.formats},
 			})
 			time.Sleep(100 * time.Millisecond)
 			assert.Equal(t, tc.expectResponses, responses)
@@ -0,0 +1,213 @@
+package statesync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/proxy"
+)
+
+// servingPool writes the chunks of the most recent local snapshot to disk ahead of time, so that
+// chunk requests from peers are served without loading chunks from the ABCI app. The snapshot is
+// replaced by Refresh when the app takes a new one. The chunks of a new snapshot are written to a
+// temporary directory first, and the snapshot is only swapped in once all of them are on disk, so
+// the previous snapshot is served in the meantime. Snapshots larger than the size limit are not
+// written at all.
+type servingPool struct {
+	dir     string
+	maxSize int64
+	conn    proxy.AppConnSnapshot
+	metrics *Metrics
+	logger  log.Logger
+
+	mtx      cmtsync.RWMutex
+	current  *snapshot // nil if no snapshot was written
+	size     int64
+	tooLarge snapshotKey // the last snapshot not written because of its size
+}
+
+// newServingPool creates a snapshot pool in dir, which holds a snapshot of at most maxSize bytes.
+// The directory is emptied, since snapshots written by a previous run may be pruned by the app.
+func newServingPool(
+	dir string,
+	maxSize int64,
+	conn proxy.AppConnSnapshot,
+	metrics *Metrics,
+	logger log.Logger,
+) (*servingPool, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, fmt.Errorf("failed to clear snapshot pool: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot pool directory: %w", err)
+	}
+	metrics.SnapshotPoolSize.Set(0)
+	return &servingPool{
+		dir:     dir,
+		maxSize: maxSize,
+		conn:    conn,
+		metrics: metrics,
+		logger:  logger,
+	}, nil
+}
+
+func (p *servingPool) snapshotDir(height uint64, format uint32) string {
+	return filepath.Join(p.dir, fmt.Sprintf("%d-%d", height, format))
+}
+
+// Get returns a chunk of the pooled snapshot. It returns false if the chunk isn't pooled.
+func (p *servingPool) Get(height uint64, format uint32, index uint32) ([]byte, bool) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	if p.current == nil || p.current.Height != height || p.current.Format != format ||
+		index >= p.current.Chunks {
+		p.metrics.SnapshotPoolMisses.Add(1)
+		return nil, false
+	}
+	// the snapshot directory isn't removed while the read lock is held
+	chunk, err := os.ReadFile(filepath.Join(p.snapshotDir(height, format), strconv.FormatUint(uint64(index), 10)))
+	if err != nil {
+		p.logger.Error("Failed to read pooled chunk", "height", height, "format", format,
+			"chunk", index, "err", err)
+		p.metrics.SnapshotPoolMisses.Add(1)
+		return nil, false
+	}
+	p.metrics.SnapshotPoolHits.Add(1)
+	return chunk, true
+}
+
+// Snapshot returns the pooled snapshot, or nil if there is none.
+func (p *servingPool) Snapshot() *snapshot {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.current
+}
+
+// Size returns the total size of the pooled chunks.
+func (p *servingPool) Size() int64 {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.size
+}
+
+// Refresh writes the most recent snapshot of the app to the pool, if it isn't pooled already, and
+// removes the previously pooled snapshot. The most recent snapshot is the one with the highest
+// height, and the highest format at that height. Refresh must not be called concurrently.
+func (p *servingPool) Refresh() error {
+	resp, err := p.conn.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	var latest *abci.Snapshot
+	for _, s := range resp.Snapshots {
+		if latest == nil || s.Height > latest.Height || (s.Height == latest.Height && s.Format > latest.Format) {
+			latest = s
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	s := &snapshot{
+		Height:      latest.Height,
+		Format:      latest.Format,
+		Chunks:      latest.Chunks,
+		Hash:        latest.Hash,
+		Metadata:    latest.Metadata,
+		ChunkHashes: latest.ChunkHashes,
+	}
+	key := s.Key()
+	p.mtx.RLock()
+	skip := (p.current != nil && p.current.Key() == key) || p.tooLarge == key
+	p.mtx.RUnlock()
+	if skip {
+		return nil
+	}
+
+	start := time.Now()
+	tmpDir := filepath.Join(p.dir, "tmp")
+	size, err := p.write(s, tmpDir)
+	if err != nil {
+		if rmErr := os.RemoveAll(tmpDir); rmErr != nil {
+			p.logger.Error("Failed to remove partially pooled snapshot", "err", rmErr)
+		}
+		return err
+	}
+	if size > p.maxSize {
+		p.logger.Info("Snapshot too large for the snapshot pool", "height", s.Height, "format", s.Format,
+			"size", size, "max", p.maxSize)
+		p.mtx.Lock()
+		p.tooLarge = key
+		p.mtx.Unlock()
+		return os.RemoveAll(tmpDir)
+	}
+	dir := p.snapshotDir(s.Height, s.Format)
+	// a snapshot with the same height and format but different contents may be pooled already
+	p.mtx.Lock()
+	prev := p.current
+	if prev != nil && prev.Height == s.Height && prev.Format == s.Format {
+		p.current, p.size = nil, 0
+		prev = nil
+	}
+	p.mtx.Unlock()
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove pooled snapshot: %w", err)
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return fmt.Errorf("failed to pool snapshot: %w", err)
+	}
+
+	p.mtx.Lock()
+	p.current, p.size = s, size
+	p.mtx.Unlock()
+	p.metrics.SnapshotPoolSize.Set(float64(size))
+	p.metrics.SnapshotPoolRefreshDuration.Observe(time.Since(start).Seconds())
+	p.logger.Info("Pooled snapshot", "height", s.Height, "format", s.Format, "chunks", s.Chunks,
+		"size", size, "duration", time.Since(start))
+
+	if prev != nil {
+		if err := os.RemoveAll(p.snapshotDir(prev.Height, prev.Format)); err != nil {
+			return fmt.Errorf("failed to remove pooled snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// write loads the chunks of a snapshot from the app and writes them to dir. It stops once the
+// chunks exceed the size limit, and returns their total size.
+func (p *servingPool) write(s *snapshot, dir string) (int64, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to remove partially pooled snapshot: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return 0, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	var size int64
+	for index := uint32(0); index < s.Chunks; index++ {
+		resp, err := p.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+			Height: s.Height,
+			Format: s.Format,
+			Chunk:  index,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to load chunk %v: %w", index, err)
+		}
+		if resp.Chunk == nil {
+			return 0, fmt.Errorf("app is missing chunk %v of snapshot at height %v", index, s.Height)
+		}
+		size += int64(len(resp.Chunk))
+		if size > p.maxSize {
+			return size, nil
+		}
+		path := filepath.Join(dir, strconv.FormatUint(uint64(index), 10))
+		if err := os.WriteFile(path, resp.Chunk, 0o600); err != nil {
+			return 0, fmt.Errorf("failed to write chunk %v: %w", index, err)
+		}
+	}
+	return size, nil
+}
@@ -0,0 +1,155 @@
+package statesync
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+	proxymocks "github.com/tendermint/tendermint/proxy/mocks"
+)
+
+// poolApp mocks an app whose most recent snapshot can be replaced, with 4-byte chunks whose
+// first bytes are the snapshot height and chunk index.
+func poolApp(latest *atomic.Uint64) *proxymocks.AppConnSnapshot {
+	conn := &proxymocks.AppConnSnapshot{}
+	conn.On("ListSnapshotsSync", abci.RequestListSnapshots{}).Return(
+		func(abci.RequestListSnapshots) (*abci.ResponseListSnapshots, error) {
+			height := latest.Load()
+			return &abci.ResponseListSnapshots{Snapshots: []*abci.Snapshot{
+				{Height: height - 1, Format: 1, Chunks: 3, Hash: []byte{byte(height - 1)}},
+				{Height: height, Format: 1, Chunks: 3, Hash: []byte{byte(height)}},
+				{Height: height, Format: 2, Chunks: 3, Hash: []byte{byte(height), 2}},
+			}}, nil
+		})
+	conn.On("LoadSnapshotChunkSync", mock.Anything).Return(
+		func(req abci.RequestLoadSnapshotChunk) (*abci.ResponseLoadSnapshotChunk, error) {
+			return &abci.ResponseLoadSnapshotChunk{
+				Chunk: []byte{byte(req.Height), byte(req.Chunk), byte(req.Format), 0},
+			}, nil
+		})
+	return conn
+}
+
+func TestServingPool(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stale"), []byte{1}, 0o600))
+
+	var latest atomic.Uint64
+	latest.Store(2)
+	conn := poolApp(&latest)
+	pool, err := newServingPool(dir, 12, conn, NopMetrics(), log.TestingLogger())
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "stale"))
+	assert.True(t, os.IsNotExist(err))
+	assert.Nil(t, pool.Snapshot())
+	_, ok := pool.Get(2, 2, 0)
+	assert.False(t, ok)
+
+	// the snapshot with the highest height and format is pooled
+	require.NoError(t, pool.Refresh())
+	require.NotNil(t, pool.Snapshot())
+	assert.EqualValues(t, 2, pool.Snapshot().Height)
+	assert.EqualValues(t, 2, pool.Snapshot().Format)
+	assert.EqualValues(t, 12, pool.Size())
+	chunk, ok := pool.Get(2, 2, 1)
+	require.True(t, ok)
+	assert.Equal(t, []byte{2, 1, 2, 0}, chunk)
+	_, ok = pool.Get(2, 1, 1)
+	assert.False(t, ok)
+	_, ok = pool.Get(2, 2, 3)
+	assert.False(t, ok)
+
+	// refreshing without a new snapshot doesn't load chunks again
+	require.NoError(t, pool.Refresh())
+	conn.AssertNumberOfCalls(t, "LoadSnapshotChunkSync", 3)
+
+	// a new snapshot replaces the previous one on disk
+	latest.Store(3)
+	require.NoError(t, pool.Refresh())
+	conn.AssertNumberOfCalls(t, "LoadSnapshotChunkSync", 6)
+	_, ok = pool.Get(2, 2, 1)
+	assert.False(t, ok)
+	chunk, ok = pool.Get(3, 2, 1)
+	require.True(t, ok)
+	assert.Equal(t, []byte{3, 1, 2, 0}, chunk)
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "3-2", files[0].Name())
+
+	// a snapshot larger than the pool isn't pooled, and isn't loaded again on the next refresh
+	pool.maxSize = 11
+	latest.Store(4)
+	require.NoError(t, pool.Refresh())
+	conn.AssertNumberOfCalls(t, "LoadSnapshotChunkSync", 9)
+	require.NoError(t, pool.Refresh())
+	conn.AssertNumberOfCalls(t, "LoadSnapshotChunkSync", 9)
+	assert.EqualValues(t, 3, pool.Snapshot().Height)
+	_, ok = pool.Get(3, 2, 1)
+	assert.True(t, ok)
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestServingPool_RefreshWhileServing(t *testing.T) {
+	var latest atomic.Uint64
+	latest.Store(2)
+	conn := poolApp(&latest)
+	pool, err := newServingPool(t.TempDir(), 12, conn, NopMetrics(), log.TestingLogger())
+	require.NoError(t, err)
+	require.NoError(t, pool.Refresh())
+
+	// readers request chunks of the current and previous snapshots while new snapshots are
+	// pooled, and must only ever get complete and correct chunks
+	var (
+		wg   sync.WaitGroup
+		done = make(chan struct{})
+		hits atomic.Int64
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				height := latest.Load()
+				for _, h := range []uint64{height - 1, height} {
+					for index := uint32(0); index < 3; index++ {
+						chunk, ok := pool.Get(h, 2, index)
+						if !ok {
+							continue
+						}
+						hits.Add(1)
+						if !assert.Equal(t, []byte{byte(h), byte(index), 2, 0}, chunk) {
+							return
+						}
+					}
+				}
+			}
+		}()
+	}
+
+	for height := uint64(3); height <= 20; height++ {
+		served := hits.Load()
+		require.Eventually(t, func() bool { return hits.Load() > served }, time.Second, time.Millisecond)
+		latest.Store(height)
+		require.NoError(t, pool.Refresh())
+		assert.EqualValues(t, height, pool.Snapshot().Height)
+	}
+	close(done)
+	wg.Wait()
+}
@@ -21,29 +21,45 @@ type snapshot struct {
 	Hash     []byte
 	Metadata []byte
 
+	// ChunkHashes are the SHA-256 hashes of the chunks, if provided by the app. Chunks are verified
+	// against them before they are applied.
+	ChunkHashes [][]byte
+
 	trustedAppHash    []byte // populated by light client
 	trustedAppVersion uint64 // populated by light client
 }
 
 // Key generates a snapshot key, used for lookups. It takes into account not only the height and
-// format, but also the chunks, hash, and metadata in case peers have generated snapshots in a
-// non-deterministic manner. All fields must be equal for the snapshot to be considered the same.
+// format, but also the chunks, hash, metadata and chunk hashes in case peers have generated
+// snapshots in a non-deterministic manner. All fields must be equal for the snapshot to be
+// considered the same.
 func (s *snapshot) Key() snapshotKey {
 	// Hash.Write() never returns an error.
 	hasher := sha256.New()
 	hasher.Write([]byte(fmt.Sprintf("%v:%v:%v", s.Height, s.Format, s.Chunks)))
 	hasher.Write(s.Hash)
 	hasher.Write(s.Metadata)
+	for _, hash := range s.ChunkHashes {
+		hasher.Write(hash)
+	}
 	var key snapshotKey
 	copy(key[:], hasher.Sum(nil))
 	return key
 }
 
+// snapshotOffer identifies a snapshot offered by a peer. A peer offers at most one snapshot per
+// height and format.
+type snapshotOffer struct {
+	height uint64
+	format uint32
+}
+
 // snapshotPool discovers and aggregates snapshots across peers.
 type snapshotPool struct {
 	cmtsync.Mutex
 	snapshots     map[snapshotKey]*snapshot
 	snapshotPeers map[snapshotKey]map[p2p.ID]p2p.Peer
+	peerOffers    map[p2p.ID]map[snapshotOffer]snapshotKey
 
 	// indexes for fast searches
 	formatIndex map[uint32]map[snapshotKey]bool
@@ -61,6 +77,7 @@ func newSnapshotPool() *snapshotPool {
 	return &snapshotPool{
 		snapshots:         make(map[snapshotKey]*snapshot),
 		snapshotPeers:     make(map[snapshotKey]map[p2p.ID]p2p.Peer),
+		peerOffers:        make(map[p2p.ID]map[snapshotOffer]snapshotKey),
 		formatIndex:       make(map[uint32]map[snapshotKey]bool),
 		heightIndex:       make(map[uint64]map[snapshotKey]bool),
 		peerIndex:         make(map[p2p.ID]map[snapshotKey]bool),
@@ -70,11 +87,13 @@ func newSnapshotPool() *snapshotPool {
 	}
 }
 
-// Add adds a snapshot to the pool, unless the peer has already sent recentSnapshots snapshots. It
-// returns true if this was a new, non-blacklisted snapshot. The snapshot height is verified using
-// the light client, and the expected app hash is set for the snapshot.
+// Add adds a snapshot to the pool, unless the peer has already sent recentSnapshots snapshots in
+// its format. It returns true if this was a new, non-blacklisted snapshot. If the peer offered a
+// different snapshot at the same height and format before, the new one replaces it. The snapshot
+// height is verified using the light client, and the expected app hash is set for the snapshot.
 func (p *snapshotPool) Add(peer p2p.Peer, snapshot *snapshot) (bool, error) {
 	key := snapshot.Key()
+	offer := snapshotOffer{height: snapshot.Height, format: snapshot.Format}
 
 	p.Lock()
 	defer p.Unlock()
@@ -86,9 +105,22 @@ func (p *snapshotPool) Add(peer p2p.Peer, snapshot *snapshot) (bool, error) {
 		return false, nil
 	case p.snapshotBlacklist[key]:
 		return false, nil
-	case len(p.peerIndex[peer.ID()]) >= recentSnapshots:
+	}
+
+	offers := p.peerOffers[peer.ID()]
+	if prev, ok := offers[offer]; ok {
+		if prev == key {
+			return false, nil
+		}
+		p.removeOffer(peer.ID(), prev)
+	} else if p.countOffers(peer.ID(), snapshot.Format) >= recentSnapshots {
 		return false, nil
 	}
+	if offers == nil {
+		offers = make(map[snapshotOffer]snapshotKey)
+		p.peerOffers[peer.ID()] = offers
+	}
+	offers[offer] = key
 
 	if p.snapshotPeers[key] == nil {
 		p.snapshotPeers[key] = make(map[p2p.ID]p2p.Peer)
@@ -237,6 +269,32 @@ func (p *snapshotPool) removePeer(peerID p2p.ID) {
 		}
 	}
 	delete(p.peerIndex, peerID)
+	delete(p.peerOffers, peerID)
+}
+
+// removeOffer removes the offer of a snapshot by a peer, and the snapshot if no other peer offers
+// it. The caller must hold the mutex lock.
+func (p *snapshotPool) removeOffer(peerID p2p.ID, key snapshotKey) {
+	if snapshot := p.snapshots[key]; snapshot != nil {
+		delete(p.peerOffers[peerID], snapshotOffer{height: snapshot.Height, format: snapshot.Format})
+	}
+	delete(p.peerIndex[peerID], key)
+	delete(p.snapshotPeers[key], peerID)
+	if len(p.snapshotPeers[key]) == 0 {
+		p.removeSnapshot(key)
+	}
+}
+
+// countOffers returns the number of snapshots a peer offers in a format. The caller must hold the
+// mutex lock.
+func (p *snapshotPool) countOffers(peerID p2p.ID, format uint32) int {
+	count := 0
+	for offer := range p.peerOffers[peerID] {
+		if offer.format == format {
+			count++
+		}
+	}
+	return count
 }
 
 // removeSnapshot removes a snapshot. The caller must hold the mutex lock.
@@ -249,8 +307,12 @@ func (p *snapshotPool) removeSnapshot(key snapshotKey) {
 	delete(p.snapshots, key)
 	delete(p.formatIndex[snapshot.Format], key)
 	delete(p.heightIndex[snapshot.Height], key)
+	offer := snapshotOffer{height: snapshot.Height, format: snapshot.Format}
 	for peerID := range p.snapshotPeers[key] {
 		delete(p.peerIndex[peerID], key)
+		if p.peerOffers[peerID][offer] == key {
+			delete(p.peerOffers[peerID], offer)
+		}
 	}
 	delete(p.snapshotPeers, key)
 }
@@ -19,6 +19,8 @@ func TestSnapshot_Key(t *testing.T) {
 		"new chunk count": {func(s *snapshot) { s.Chunks = 9 }},
 		"new hash":        {func(s *snapshot) { s.Hash = []byte{9} }},
 		"no metadata":     {func(s *snapshot) { s.Metadata = nil }},
+		"no chunk hashes": {func(s *snapshot) { s.ChunkHashes = nil }},
+		"new chunk hash":  {func(s *snapshot) { s.ChunkHashes[6] = []byte{9} }},
 	}
 	for name, tc := range testcases {
 		tc := tc
@@ -30,6 +32,9 @@ func TestSnapshot_Key(t *testing.T) {
 				Hash:     []byte{1, 2, 3},
 				Metadata: []byte{255},
 			}
+			for i := uint32(0); i < s.Chunks; i++ {
+				s.ChunkHashes = append(s.ChunkHashes, []byte{byte(i)})
+			}
 			before := s.Key()
 			tc.modify(&s)
 			after := s.Key()
@@ -70,6 +75,60 @@ func TestSnapshotPool_Add(t *testing.T) {
 	require.NotNil(t, snapshot)
 }
 
+func TestSnapshotPool_Add_Offers(t *testing.T) {
+	peerA := &p2pmocks.Peer{}
+	peerA.On("ID").Return(p2p.ID("a"))
+	peerB := &p2pmocks.Peer{}
+	peerB.On("ID").Return(p2p.ID("b"))
+	pool := newSnapshotPool()
+
+	// A peer offers one snapshot per height and format, a new one replaces the previous one
+	s1 := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	s1b := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{2}}
+	s1f2 := &snapshot{Height: 1, Format: 2, Chunks: 1, Hash: []byte{1}}
+	for _, s := range []*snapshot{s1, s1f2} {
+		added, err := pool.Add(peerA, s)
+		require.NoError(t, err)
+		assert.True(t, added)
+	}
+	_, err := pool.Add(peerB, s1)
+	require.NoError(t, err)
+
+	added, err := pool.Add(peerA, s1b)
+	require.NoError(t, err)
+	assert.True(t, added)
+	assert.Len(t, pool.Ranked(), 3)
+	assert.Len(t, pool.GetPeers(s1), 1)
+	assert.Len(t, pool.GetPeers(s1b), 1)
+	assert.Len(t, pool.GetPeers(s1f2), 1)
+
+	// The replaced snapshot is removed once no peer offers it
+	_, err = pool.Add(peerB, s1b)
+	require.NoError(t, err)
+	assert.Len(t, pool.Ranked(), 2)
+	assert.Empty(t, pool.GetPeers(s1))
+	assert.Len(t, pool.GetPeers(s1b), 2)
+
+	// A peer offers at most recentSnapshots snapshots per format
+	for h := uint64(2); h <= recentSnapshots; h++ {
+		added, err := pool.Add(peerA, &snapshot{Height: h, Format: 1, Chunks: 1, Hash: []byte{1}})
+		require.NoError(t, err)
+		assert.True(t, added)
+	}
+	added, err = pool.Add(peerA, &snapshot{Height: recentSnapshots + 1, Format: 1, Chunks: 1, Hash: []byte{1}})
+	require.NoError(t, err)
+	assert.False(t, added)
+	added, err = pool.Add(peerA, &snapshot{Height: recentSnapshots + 1, Format: 2, Chunks: 1, Hash: []byte{1}})
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	// Rejected snapshots no longer count towards the limit
+	pool.Reject(s1b)
+	added, err = pool.Add(peerA, &snapshot{Height: recentSnapshots + 1, Format: 1, Chunks: 1, Hash: []byte{1}})
+	require.NoError(t, err)
+	assert.True(t, added)
+}
+
 func TestSnapshotPool_GetPeer(t *testing.T) {
 	pool := newSnapshotPool()
 
@@ -131,13 +190,13 @@ func TestSnapshotPool_Ranked_Best(t *testing.T) {
 
 	// snapshots in expected order (best to worst). Highest height wins, then highest format.
 	// Snapshots with different chunk hashes are considered different, and the most peers is
-	// tie-breaker.
+	// tie-breaker. A peer offers at most one snapshot per height and format.
 	expectSnapshots := []struct {
 		snapshot *snapshot
 		peers    []string
 	}{
 		{&snapshot{Height: 2, Format: 2, Chunks: 4, Hash: []byte{1, 3}}, []string{"a", "b", "c"}},
-		{&snapshot{Height: 2, Format: 2, Chunks: 5, Hash: []byte{1, 2}}, []string{"a"}},
+		{&snapshot{Height: 2, Format: 2, Chunks: 5, Hash: []byte{1, 2}}, []string{"d"}},
 		{&snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte{1, 2}}, []string{"a", "b"}},
 		{&snapshot{Height: 1, Format: 2, Chunks: 5, Hash: []byte{1, 2}}, []string{"a", "b"}},
 		{&snapshot{Height: 1, Format: 1, Chunks: 4, Hash: []byte{1, 2}}, []string{"a", "b", "c"}},
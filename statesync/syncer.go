@@ -5,10 +5,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"time"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/flowrate"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/light"
@@ -20,9 +22,6 @@ import (
 )
 
 const (
-	// chunkTimeout is the timeout while waiting for the next chunk from the chunk queue.
-	chunkTimeout = 2 * time.Minute
-
 	// minimumDiscoveryTime is the lowest allowable time for a
 	// SyncAny discovery time.
 	minimumDiscoveryTime = 5 * time.Second
@@ -41,10 +40,16 @@ var (
 	errRejectSender = errors.New("snapshot sender was rejected")
 	// errVerifyFailed is returned by Sync() when app hash or last height verification fails.
 	errVerifyFailed = errors.New("verification failed")
-	// errTimeout is returned by Sync() when we've waited too long to receive a chunk.
+	// errTimeout is returned by Sync() when a chunk couldn't be fetched within the configured
+	// number of retries.
 	errTimeout = errors.New("timed out waiting for chunk")
 	// errNoSnapshots is returned by SyncAny() if no snapshots are found and discovery is disabled.
 	errNoSnapshots = errors.New("no suitable snapshots found")
+
+	// ErrDiscoveryTimeout is returned by SyncAny() if the app didn't accept any snapshot within
+	// the discovery timeout, either because none were found or because all were rejected. The
+	// app state is untouched in this case, hence the node can fall back to fast sync.
+	ErrDiscoveryTimeout = errors.New("no snapshot was accepted within the discovery timeout")
 )
 
 // syncer runs a state sync against an ABCI app. Use either SyncAny() to automatically attempt to
@@ -59,9 +64,37 @@ type syncer struct {
 	tempDir       string
 	chunkFetchers int32
 	retryTimeout  time.Duration
+	chunkRetries  int32
+	metrics       *Metrics
+	fetchRate     *flowrate.Monitor
+
+	// discoveryTimeout bounds the time until the app accepts a snapshot, if non-zero. Once the app
+	// accepted a snapshot its state may have been modified, hence the timeout no longer applies.
+	discoveryTimeout time.Duration
+	accepted         bool
+
+	// formats are the snapshot formats the app can apply, advertised to peers. If empty, snapshots
+	// in any format are offered to the app.
+	formats []uint32
 
 	mtx    cmtsync.RWMutex
 	chunks *chunkQueue
+
+	// peerRequests is the number of chunk requests in flight per peer, and compressionPeers the
+	// peers which can send snappy-compressed chunks.
+	peerMtx          cmtsync.Mutex
+	peerRequests     map[p2p.ID]int
+	compressionPeers map[p2p.ID]bool
+
+	// peerStats are the chunk request statistics of the peers during the current restoration,
+	// and slowPeers the peers no chunks are requested from since they are far slower than others.
+	peerStats map[p2p.ID]*peerChunkStats
+	slowPeers map[p2p.ID]bool
+
+	// requestSnapshots requests snapshots from all connected peers, to discover further peers of
+	// the snapshot being restored while few peers serve chunks.
+	requestSnapshots     func()
+	lastSnapshotsRequest time.Time
 }
 
 // newSyncer creates a new syncer.
@@ -72,6 +105,7 @@ func newSyncer(
 	connQuery proxy.AppConnQuery,
 	stateProvider StateProvider,
 	tempDir string,
+	metrics *Metrics,
 ) *syncer {
 
 	return &syncer{
@@ -83,11 +117,22 @@ func newSyncer(
 		tempDir:       tempDir,
 		chunkFetchers: cfg.ChunkFetchers,
 		retryTimeout:  cfg.ChunkRequestTimeout,
+		chunkRetries:  cfg.ChunkRetries,
+		metrics:       metrics,
+		fetchRate:     flowrate.New(time.Second, 10*time.Second),
+		peerRequests:  make(map[p2p.ID]int),
+		peerStats:     make(map[p2p.ID]*peerChunkStats),
+
+		compressionPeers: make(map[p2p.ID]bool),
+		discoveryTimeout: cfg.DiscoveryTimeout,
+		formats:          cfg.SnapshotFormats,
 	}
 }
 
 // AddChunk adds a chunk to the chunk queue, if any. It returns false if the chunk has already
-// been added to the queue, or an error if there's no sync in progress.
+// been added to the queue, or an error if there's no sync in progress. If the chunk doesn't match
+// its hash, the sender is rejected, the chunk is refetched from another peer, and an error
+// wrapping errChunkHashMismatch is returned.
 func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 	s.mtx.RLock()
 	defer s.mtx.RUnlock()
@@ -95,10 +140,23 @@ func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 		return false, errors.New("no state sync in progress")
 	}
 	added, err := s.chunks.Add(chunk)
+	if errors.Is(err, errChunkHashMismatch) {
+		// The peer sent bogus data, so don't fetch any further chunks from it.
+		s.logger.Error("Rejecting peer which sent a chunk not matching its hash", "peer", chunk.Sender,
+			"height", chunk.Height, "format", chunk.Format, "chunk", chunk.Index)
+		s.snapshots.RejectPeer(chunk.Sender)
+		s.chunks.Refetch(chunk.Index)
+		return false, err
+	}
 	if err != nil {
 		return false, err
 	}
 	if added {
+		s.updatePeerStats(chunk.Sender, func(st *peerChunkStats) {
+			st.bytes += int64(len(chunk.Chunk))
+		})
+		s.fetchRate.Update(len(chunk.Chunk))
+		s.metrics.ChunkFetchRate.Set(float64(s.fetchRate.Status().CurRate))
 		s.logger.Debug("Added chunk to queue", "height", chunk.Height, "format", chunk.Format,
 			"chunk", chunk.Index)
 	} else {
@@ -109,8 +167,14 @@ func (s *syncer) AddChunk(chunk *chunk) (bool, error) {
 }
 
 // AddSnapshot adds a snapshot to the snapshot pool. It returns true if a new, previously unseen
-// snapshot was accepted and added.
+// snapshot was accepted and added. Snapshots in formats the app can't apply are ignored, since
+// peers which don't support format negotiation offer snapshots in any format.
 func (s *syncer) AddSnapshot(peer p2p.Peer, snapshot *snapshot) (bool, error) {
+	if !s.supportsFormat(snapshot.Format) {
+		s.logger.Debug("Ignoring snapshot in unsupported format", "height", snapshot.Height,
+			"format", snapshot.Format, "peer", peer.ID())
+		return false, nil
+	}
 	added, err := s.snapshots.Add(peer, snapshot)
 	if err != nil {
 		return false, err
@@ -122,21 +186,45 @@ func (s *syncer) AddSnapshot(peer p2p.Peer, snapshot *snapshot) (bool, error) {
 	return added, nil
 }
 
+// AddCompressionPeer records that a peer can send snappy-compressed chunks, as advertised with its
+// snapshots. Chunks are requested compressed from such peers.
+func (s *syncer) AddCompressionPeer(peerID p2p.ID) {
+	s.peerMtx.Lock()
+	defer s.peerMtx.Unlock()
+	s.compressionPeers[peerID] = true
+}
+
 // AddPeer adds a peer to the pool. For now we just keep it simple and send a single request
 // to discover snapshots, later we may want to do retries and stuff.
 func (s *syncer) AddPeer(peer p2p.Peer) {
 	s.logger.Debug("Requesting snapshots from peer", "peer", peer.ID())
 	e := p2p.Envelope{
 		ChannelID: SnapshotChannel,
-		Message:   &ssproto.SnapshotsRequest{},
+		Message:   &ssproto.SnapshotsRequest{Formats: s.formats},
 	}
 	p2p.SendEnvelopeShim(peer, e, s.logger) //nolint: staticcheck
 }
 
+// supportsFormat returns true if the app can apply snapshots in the given format.
+func (s *syncer) supportsFormat(format uint32) bool {
+	if len(s.formats) == 0 {
+		return true
+	}
+	for _, f := range s.formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
 // RemovePeer removes a peer from the pool.
 func (s *syncer) RemovePeer(peer p2p.Peer) {
 	s.logger.Debug("Removing peer from sync", "peer", peer.ID())
 	s.snapshots.RemovePeer(peer.ID())
+	s.peerMtx.Lock()
+	delete(s.compressionPeers, peer.ID())
+	s.peerMtx.Unlock()
 }
 
 // SyncAny tries to sync any of the snapshots in the snapshot pool, waiting to discover further
@@ -147,10 +235,16 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 		discoveryTime = 5 * minimumDiscoveryTime
 	}
 
+	// Until the app accepts a snapshot, discovery is bounded by the discovery timeout, if any.
+	var deadline time.Time
+	if s.discoveryTimeout > 0 {
+		deadline = time.Now().Add(s.discoveryTimeout)
+	}
+
 	if discoveryTime > 0 {
-		s.logger.Info("sync any", "msg", log.NewLazySprintf("Discovering snapshots for %v", discoveryTime))
-		time.Sleep(discoveryTime)
+		s.discover(discoveryTime, deadline)
 	}
+	s.requestSnapshots = retryHook
 
 	// The app may ask us to retry a snapshot restoration, in which case we need to reuse
 	// the snapshot and chunk queue from the previous loop iteration.
@@ -169,9 +263,14 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 			if discoveryTime == 0 {
 				return sm.State{}, nil, errNoSnapshots
 			}
+			if s.accepted {
+				deadline = time.Time{}
+			}
+			if !deadline.IsZero() && !time.Now().Before(deadline) {
+				return sm.State{}, nil, ErrDiscoveryTimeout
+			}
 			retryHook()
-			s.logger.Info("sync any", "msg", log.NewLazySprintf("Discovering snapshots for %v", discoveryTime))
-			time.Sleep(discoveryTime)
+			s.discover(discoveryTime, deadline)
 			continue
 		}
 		if chunks == nil {
@@ -236,6 +335,18 @@ func (s *syncer) SyncAny(discoveryTime time.Duration, retryHook func()) (sm.Stat
 	}
 }
 
+// discover waits for snapshots to be discovered for the given time, or until the deadline if it is
+// set and earlier.
+func (s *syncer) discover(discoveryTime time.Duration, deadline time.Time) {
+	if !deadline.IsZero() {
+		if remaining := time.Until(deadline); remaining < discoveryTime {
+			discoveryTime = remaining
+		}
+	}
+	s.logger.Info("sync any", "msg", log.NewLazySprintf("Discovering snapshots for %v", discoveryTime))
+	time.Sleep(discoveryTime)
+}
+
 // Sync executes a sync for a specific snapshot, returning the latest state and block commit which
 // the caller must use to bootstrap the node.
 func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.Commit, error) {
@@ -246,10 +357,16 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 	}
 	s.chunks = chunks
 	s.mtx.Unlock()
+	s.peerMtx.Lock()
+	s.peerStats = make(map[p2p.ID]*peerChunkStats)
+	s.slowPeers = nil
+	s.peerMtx.Unlock()
+	s.metrics.SlowPeers.Set(0)
 	defer func() {
 		s.mtx.Lock()
 		s.chunks = nil
 		s.mtx.Unlock()
+		s.metrics.ChunkFetchRate.Set(0)
 	}()
 
 	hctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
@@ -283,6 +400,7 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 	if err != nil {
 		return sm.State{}, nil, err
 	}
+	s.accepted = true
 
 	// Spawn chunk fetchers. They will terminate when the chunk queue is closed or context cancelled.
 	fetchCtx, cancel := context.WithCancel(context.TODO())
@@ -318,6 +436,7 @@ func (s *syncer) Sync(snapshot *snapshot, chunks *chunkQueue) (sm.State, *types.
 	// Done! 🎉
 	s.logger.Info("Snapshot restored", "height", snapshot.Height, "format", snapshot.Format,
 		"hash", snapshot.Hash)
+	s.logPeerStats()
 
 	return state, commit, nil
 }
@@ -329,11 +448,12 @@ func (s *syncer) offerSnapshot(snapshot *snapshot) error {
 		"format", snapshot.Format, "hash", snapshot.Hash)
 	resp, err := s.conn.OfferSnapshotSync(abci.RequestOfferSnapshot{
 		Snapshot: &abci.Snapshot{
-			Height:   snapshot.Height,
-			Format:   snapshot.Format,
-			Chunks:   snapshot.Chunks,
-			Hash:     snapshot.Hash,
-			Metadata: snapshot.Metadata,
+			Height:      snapshot.Height,
+			Format:      snapshot.Format,
+			Chunks:      snapshot.Chunks,
+			Hash:        snapshot.Hash,
+			Metadata:    snapshot.Metadata,
+			ChunkHashes: snapshot.ChunkHashes,
 		},
 		AppHash:    snapshot.trustedAppHash,
 		AppVersion: snapshot.trustedAppVersion,
@@ -419,72 +539,248 @@ func (s *syncer) applyChunks(chunks *chunkQueue) error {
 // fetchChunks requests chunks from peers, receiving allocations from the chunk queue. Chunks
 // will be received from the reactor via syncer.AddChunks() to chunkQueue.Add().
 func (s *syncer) fetchChunks(ctx context.Context, snapshot *snapshot, chunks *chunkQueue) {
-	var (
-		next  = true
-		index uint32
-		err   error
-	)
-
 	for {
-		if next {
-			index, err = chunks.Allocate()
-			if errors.Is(err, errDone) {
-				// Keep checking until the context is canceled (restore is done), in case any
-				// chunks need to be refetched.
-				select {
-				case <-ctx.Done():
-					return
-				default:
-				}
-				time.Sleep(2 * time.Second)
-				continue
-			}
-			if err != nil {
-				s.logger.Error("Failed to allocate chunk from queue", "err", err)
+		index, err := chunks.Allocate()
+		if errors.Is(err, errDone) {
+			// Keep checking until the context is canceled (restore is done), in case any
+			// chunks need to be refetched.
+			select {
+			case <-ctx.Done():
 				return
+			default:
 			}
+			time.Sleep(2 * time.Second)
+			continue
 		}
-		s.logger.Info("Fetching snapshot chunk", "height", snapshot.Height,
-			"format", snapshot.Format, "chunk", index, "total", chunks.Size())
-
-		ticker := time.NewTicker(s.retryTimeout)
-		defer ticker.Stop()
+		if err != nil {
+			s.logger.Error("Failed to allocate chunk from queue", "err", err)
+			return
+		}
+		if !s.fetchChunk(ctx, snapshot, chunks, index) {
+			return
+		}
+	}
+}
 
-		s.requestChunk(snapshot, index)
+// fetchChunk requests a chunk until it arrives, re-requesting it from a different peer every
+// time a request times out or a chunk not matching its hash is received. Once the retries are
+// exhausted, the chunk is marked as failed in the queue. It returns false if the context was
+// canceled.
+func (s *syncer) fetchChunk(ctx context.Context, snapshot *snapshot, chunks *chunkQueue, index uint32) bool {
+	tried := make(map[p2p.ID]bool)
+	var last p2p.ID
+	for attempt := int32(0); ; attempt++ {
+		if attempt > s.chunkRetries {
+			s.logger.Error("Failed to fetch snapshot chunk, giving up", "height", snapshot.Height,
+				"format", snapshot.Format, "chunk", index, "attempts", attempt)
+			chunks.Fail(index)
+			return true
+		}
+		if attempt > 0 {
+			s.metrics.ChunkRetries.Add(1)
+		}
+		s.logger.Info("Fetching snapshot chunk", "height", snapshot.Height,
+			"format", snapshot.Format, "chunk", index, "total", chunks.Size(), "attempt", attempt+1)
+
+		arrived, refetch := chunks.WaitFor(index), chunks.WaitForRefetch(index)
+		start := time.Now()
+		peer := s.requestChunk(snapshot, index, tried, last)
+		if peer != nil {
+			last = peer.ID()
+			tried[last] = true
+			s.metrics.ChunksInFlight.Add(1)
+		}
 
+		timer := time.NewTimer(s.retryTimeout)
+		var received, timedOut, canceled bool
 		select {
-		case <-chunks.WaitFor(index):
-			next = true
-
-		case <-ticker.C:
-			next = false
-
+		case <-arrived:
+			received = true
+		case _, ok := <-refetch:
+			// a chunk which didn't match its hash was received, request it again right away
+			received = !ok
+		case <-timer.C:
+			timedOut = true
 		case <-ctx.Done():
-			return
+			canceled = true
+		}
+		timer.Stop()
+
+		if peer != nil {
+			// the stats are updated before the peer is released, so that it isn't chosen again
+			// before it is found to be slow
+			switch {
+			case received && chunks.GetSender(index) == peer.ID():
+				elapsed := time.Since(start)
+				s.updatePeerStats(peer.ID(), func(st *peerChunkStats) {
+					st.chunks++
+					st.fetchTime += elapsed
+				})
+			case timedOut:
+				s.metrics.PeerChunkFailures.With("peer_id", string(peer.ID())).Add(1)
+				s.updatePeerStats(peer.ID(), func(st *peerChunkStats) {
+					st.failures++
+					st.fetchTime += s.retryTimeout
+				})
+			}
+			s.releasePeer(peer.ID())
+			s.metrics.ChunksInFlight.Add(-1)
+		}
+		switch {
+		case canceled:
+			return false
+		case received:
+			return true
 		}
-
-		ticker.Stop()
 	}
 }
 
-// requestChunk requests a chunk from a peer.
-func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32) {
-	peer := s.snapshots.GetPeer(snapshot)
+// requestChunk requests a chunk from a peer chosen by choosePeer, returning the peer or nil if
+// no peer was found. The caller must release the peer with releasePeer once the request is done.
+func (s *syncer) requestChunk(snapshot *snapshot, chunk uint32, tried map[p2p.ID]bool, last p2p.ID) p2p.Peer {
+	peer := s.choosePeer(snapshot, tried, last)
 	if peer == nil {
 		s.logger.Error("No valid peers found for snapshot", "height", snapshot.Height,
 			"format", snapshot.Format, "hash", snapshot.Hash)
-		return
+		return nil
 	}
+	s.peerMtx.Lock()
+	compressed := s.compressionPeers[peer.ID()]
+	s.peerMtx.Unlock()
 	s.logger.Debug("Requesting snapshot chunk", "height", snapshot.Height,
-		"format", snapshot.Format, "chunk", chunk, "peer", peer.ID())
+		"format", snapshot.Format, "chunk", chunk, "peer", peer.ID(), "compressed", compressed)
 	p2p.SendEnvelopeShim(peer, p2p.Envelope{ //nolint: staticcheck
 		ChannelID: ChunkChannel,
 		Message: &ssproto.ChunkRequest{
-			Height: snapshot.Height,
-			Format: snapshot.Format,
-			Index:  chunk,
+			Height:     snapshot.Height,
+			Format:     snapshot.Format,
+			Index:      chunk,
+			Compressed: compressed,
 		},
 	}, s.logger)
+	return peer
+}
+
+// choosePeer chooses a peer of the snapshot to request a chunk from. Peers which weren't tried for
+// the chunk yet are preferred, and among those the peers with the fewest requests in flight, so
+// that concurrent fetchers request distinct chunks from distinct peers. Ties are broken randomly.
+// Once every peer was tried, tried is cleared and all peers but the last one are tried again.
+func (s *syncer) choosePeer(snapshot *snapshot, tried map[p2p.ID]bool, last p2p.ID) p2p.Peer {
+	peers := s.activePeers(snapshot)
+	candidates := make([]p2p.Peer, 0, len(peers))
+	for _, peer := range peers {
+		if !tried[peer.ID()] {
+			candidates = append(candidates, peer)
+		}
+	}
+	if len(candidates) == 0 {
+		for id := range tried {
+			delete(tried, id)
+		}
+		for _, peer := range peers {
+			if peer.ID() != last || len(peers) == 1 {
+				candidates = append(candidates, peer)
+			}
+		}
+	}
+
+	s.peerMtx.Lock()
+	defer s.peerMtx.Unlock()
+	var idlest []p2p.Peer
+	for _, peer := range candidates {
+		requests := s.peerRequests[peer.ID()]
+		if len(idlest) > 0 {
+			if min := s.peerRequests[idlest[0].ID()]; requests > min {
+				continue
+			} else if requests < min {
+				idlest = idlest[:0]
+			}
+		}
+		idlest = append(idlest, peer)
+	}
+	if len(idlest) == 0 {
+		return nil
+	}
+	peer := idlest[rand.Intn(len(idlest))] //nolint:gosec // G404: Use of weak random number generator
+	s.peerRequests[peer.ID()]++
+	return peer
+}
+
+// releasePeer releases a peer chosen by choosePeer once its request is done.
+func (s *syncer) releasePeer(peerID p2p.ID) {
+	s.peerMtx.Lock()
+	defer s.peerMtx.Unlock()
+	if s.peerRequests[peerID] <= 1 {
+		delete(s.peerRequests, peerID)
+	} else {
+		s.peerRequests[peerID]--
+	}
+}
+
+// activePeers returns the peers of the snapshot chunks are requested from, i.e. all of them but the
+// slow ones, unless all of them are slow. Snapshots are requested from all connected peers, at most
+// every snapshotsRequestInterval, while fewer than minActiveChunkPeers peers are active.
+func (s *syncer) activePeers(snapshot *snapshot) []p2p.Peer {
+	peers := s.snapshots.GetPeers(snapshot)
+	active := make([]p2p.Peer, 0, len(peers))
+	s.peerMtx.Lock()
+	for _, peer := range peers {
+		if !s.slowPeers[peer.ID()] {
+			active = append(active, peer)
+		}
+	}
+	discover := len(active) < minActiveChunkPeers && s.requestSnapshots != nil &&
+		time.Since(s.lastSnapshotsRequest) >= snapshotsRequestInterval
+	if discover {
+		s.lastSnapshotsRequest = time.Now()
+	}
+	s.peerMtx.Unlock()
+
+	if discover {
+		s.logger.Info("Few peers serving snapshot chunks, discovering more", "active", len(active),
+			"peers", len(peers))
+		s.requestSnapshots()
+	}
+	if len(active) == 0 {
+		return peers
+	}
+	return active
+}
+
+// updatePeerStats updates the chunk request statistics of a peer, and the slow peers.
+func (s *syncer) updatePeerStats(peerID p2p.ID, update func(*peerChunkStats)) {
+	s.peerMtx.Lock()
+	defer s.peerMtx.Unlock()
+	st, ok := s.peerStats[peerID]
+	if !ok {
+		st = &peerChunkStats{}
+		s.peerStats[peerID] = st
+	}
+	update(st)
+	if st.requests() > 0 {
+		s.metrics.PeerChunkThroughput.With("peer_id", string(peerID)).Set(st.throughput())
+	}
+
+	slow := slowPeers(s.peerStats)
+	for id := range slow {
+		if !s.slowPeers[id] {
+			s.logger.Info("Not requesting further chunks from slow peer", "peer", id,
+				"throughput", s.peerStats[id].throughput(), "failures", s.peerStats[id].failures)
+		}
+	}
+	s.slowPeers = slow
+	s.metrics.SlowPeers.Set(float64(len(slow)))
+}
+
+// logPeerStats logs a summary of the chunk request statistics of the peers.
+func (s *syncer) logPeerStats() {
+	s.peerMtx.Lock()
+	defer s.peerMtx.Unlock()
+	for id, st := range s.peerStats {
+		s.logger.Debug("Snapshot chunk peer stats", "peer", id, "chunks", st.chunks,
+			"failures", st.failures, "bytes", st.bytes, "throughput", st.throughput(),
+			"slow", s.slowPeers[id])
+	}
 }
 
 // verifyApp verifies the sync, checking the app hash, last block height and app version
@@ -1,10 +1,14 @@
 package statesync
 
 import (
+	"context"
+	"crypto/sha256"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-kit/kit/metrics/generic"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -39,7 +43,7 @@ func setupOfferSyncer(t *testing.T) (*syncer, *proxymocks.AppConnSnapshot) {
 	stateProvider.On("State", mock.AnythingOfType("*context.timerCtx"), uint64(4)).Return(sm.State{}, nil)
 
 	cfg := config.DefaultStateSyncConfig()
-	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 	return syncer, connSnapshot
 }
@@ -95,7 +99,7 @@ func TestSyncer_SyncAny(t *testing.T) {
 	connQuery := &proxymocks.AppConnQuery{}
 
 	cfg := config.DefaultStateSyncConfig()
-	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 	// Adding a chunk should error when no sync is in progress
 	_, err := syncer.AddChunk(&chunk{Height: 1, Format: 1, Index: 0, Chunk: []byte{1}})
@@ -243,6 +247,31 @@ func TestSyncer_SyncAny_noSnapshots(t *testing.T) {
 	assert.Equal(t, errNoSnapshots, err)
 }
 
+func TestSyncer_SyncAny_discoveryTimeout(t *testing.T) {
+	syncer, connSnapshot := setupOfferSyncer(t)
+	syncer.discoveryTimeout = 200 * time.Millisecond
+
+	// without snapshots, discovery is given up after the timeout rather than the discovery time
+	start := time.Now()
+	_, _, err := syncer.SyncAny(minimumDiscoveryTime, func() {})
+	assert.Equal(t, ErrDiscoveryTimeout, err)
+	assert.Less(t, time.Since(start), minimumDiscoveryTime)
+
+	// as it is if the app rejects all snapshots
+	s := &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1, 2, 3}}
+	_, err = syncer.AddSnapshot(simplePeer("id"), s)
+	require.NoError(t, err)
+	connSnapshot.On("OfferSnapshotSync", abci.RequestOfferSnapshot{
+		Snapshot: toABCI(s), AppHash: []byte("app_hash"),
+	}).Once().Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_REJECT}, nil)
+
+	start = time.Now()
+	_, _, err = syncer.SyncAny(minimumDiscoveryTime, func() {})
+	assert.Equal(t, ErrDiscoveryTimeout, err)
+	assert.Less(t, time.Since(start), minimumDiscoveryTime)
+	connSnapshot.AssertExpectations(t)
+}
+
 func TestSyncer_SyncAny_abort(t *testing.T) {
 	syncer, connSnapshot := setupOfferSyncer(t)
 
@@ -316,6 +345,63 @@ func TestSyncer_SyncAny_reject_format(t *testing.T) {
 	connSnapshot.AssertExpectations(t)
 }
 
+func TestSyncer_SyncAny_mixedFormats(t *testing.T) {
+	syncer, connSnapshot := setupOfferSyncer(t)
+	syncer.formats = []uint32{1, 2}
+
+	// The network has providers of the old format 1, the new format 2, and a format 3 which the
+	// app can't apply. The greatest supported format at the greatest height is offered first.
+	s11 := &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1, 1}}
+	s21 := &snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte{2, 1}}
+	s22 := &snapshot{Height: 2, Format: 2, Chunks: 3, Hash: []byte{2, 2}}
+	s23 := &snapshot{Height: 2, Format: 3, Chunks: 3, Hash: []byte{2, 3}}
+	s43 := &snapshot{Height: 4, Format: 3, Chunks: 3, Hash: []byte{4, 3}}
+	providers := map[string][]*snapshot{
+		"old":    {s11, s21},
+		"new":    {s21, s22, s23},
+		"newest": {s23, s43},
+	}
+	for id, snapshots := range providers {
+		for _, s := range snapshots {
+			added, err := syncer.AddSnapshot(simplePeer(id), s)
+			require.NoError(t, err)
+			if s.Format == 3 {
+				assert.False(t, added)
+			}
+		}
+	}
+	assert.Len(t, syncer.snapshots.Ranked(), 3)
+
+	// The app turns out to be unable to apply format 2 after all, so format 1 is used instead.
+	connSnapshot.On("OfferSnapshotSync", abci.RequestOfferSnapshot{
+		Snapshot: toABCI(s22), AppHash: []byte("app_hash"),
+	}).Once().Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_REJECT_FORMAT}, nil)
+	connSnapshot.On("OfferSnapshotSync", abci.RequestOfferSnapshot{
+		Snapshot: toABCI(s21), AppHash: []byte("app_hash"),
+	}).Once().Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ABORT}, nil)
+
+	_, _, err := syncer.SyncAny(0, func() {})
+	assert.Equal(t, errAbort, err)
+	connSnapshot.AssertExpectations(t)
+}
+
+func TestSyncer_AddPeer_formats(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	cfg.SnapshotFormats = []uint32{2, 1}
+	syncer := newSyncer(*cfg, log.NewNopLogger(), &proxymocks.AppConnSnapshot{}, &proxymocks.AppConnQuery{},
+		&mocks.StateProvider{}, "", NopMetrics())
+
+	// The supported formats are advertised when requesting snapshots
+	peer := &p2pmocks.PeerEnvelopeSender{}
+	peer.On("ID").Return(p2p.ID("id"))
+	peer.On("SendEnvelope", p2p.Envelope{
+		ChannelID: SnapshotChannel,
+		Message:   &ssproto.SnapshotsRequest{Formats: []uint32{2, 1}},
+	}).Once().Return(true)
+	syncer.AddPeer(peer)
+	peer.AssertExpectations(t)
+}
+
 func TestSyncer_SyncAny_reject_sender(t *testing.T) {
 	syncer, connSnapshot := setupOfferSyncer(t)
 
@@ -438,7 +524,7 @@ func TestSyncer_applyChunks_Results(t *testing.T) {
 			stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			body := []byte{1, 2, 3}
 			chunks, err := newChunkQueue(&snapshot{Height: 1, Format: 1, Chunks: 1}, "")
@@ -491,7 +577,7 @@ func TestSyncer_applyChunks_RefetchChunks(t *testing.T) {
 			stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			chunks, err := newChunkQueue(&snapshot{Height: 1, Format: 1, Chunks: 3}, "")
 			require.NoError(t, err)
@@ -556,7 +642,7 @@ func TestSyncer_applyChunks_RejectSenders(t *testing.T) {
 			stateProvider.On("AppHash", mock.Anything, mock.Anything).Return([]byte("app_hash"), nil)
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			// Set up three peers across two snapshots, and ask for one of them to be banned.
 			// It should be banned from all snapshots.
@@ -678,7 +764,7 @@ func TestSyncer_verifyApp(t *testing.T) {
 			stateProvider := &mocks.StateProvider{}
 
 			cfg := config.DefaultStateSyncConfig()
-			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "")
+			syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
 
 			connQuery.On("InfoSync", proxy.RequestInfo).Return(tc.response, tc.err)
 			_, err := syncer.verifyApp(s, appVersion)
@@ -693,10 +779,405 @@ func TestSyncer_verifyApp(t *testing.T) {
 
 func toABCI(s *snapshot) *abci.Snapshot {
 	return &abci.Snapshot{
-		Height:   s.Height,
-		Format:   s.Format,
-		Chunks:   s.Chunks,
-		Hash:     s.Hash,
-		Metadata: s.Metadata,
+		Height:      s.Height,
+		Format:      s.Format,
+		Chunks:      s.Chunks,
+		Hash:        s.Hash,
+		Metadata:    s.Metadata,
+		ChunkHashes: s.ChunkHashes,
+	}
+}
+
+// chunkPeer is a mock peer serving the chunks of a snapshot. serve is called with the index of
+// every chunk requested from the peer.
+func chunkPeer(id string, serve func(index uint32)) *p2pmocks.PeerEnvelopeSender {
+	peer := &p2pmocks.PeerEnvelopeSender{}
+	peer.On("ID").Return(p2p.ID(id))
+	peer.On("SendEnvelope", mock.MatchedBy(func(i interface{}) bool {
+		e, ok := i.(p2p.Envelope)
+		return ok && e.ChannelID == ChunkChannel
+	})).Maybe().Run(func(args mock.Arguments) {
+		serve(args[0].(p2p.Envelope).Message.(*ssproto.ChunkRequest).Index)
+	}).Return(true)
+	return peer
+}
+
+// chunkRequests records the peers every chunk was requested from.
+type chunkRequests struct {
+	cmtsync.Mutex
+	peers map[uint32][]p2p.ID
+}
+
+func (r *chunkRequests) record(index uint32, peerID string) {
+	r.Lock()
+	defer r.Unlock()
+	r.peers[index] = append(r.peers[index], p2p.ID(peerID))
+}
+
+func (r *chunkRequests) get(index uint32) []p2p.ID {
+	r.Lock()
+	defer r.Unlock()
+	return append([]p2p.ID(nil), r.peers[index]...)
+}
+
+func testMetrics() *Metrics {
+	return &Metrics{
+		ChunksInFlight: generic.NewGauge("chunks_in_flight"),
+		ChunkRetries:   generic.NewCounter("chunk_retries"),
+		ChunkFetchRate: generic.NewGauge("chunk_fetch_rate"),
+
+		PeerChunkThroughput: generic.NewGauge("peer_chunk_throughput"),
+		PeerChunkFailures:   generic.NewCounter("peer_chunk_failures"),
+		SlowPeers:           generic.NewGauge("slow_peers"),
+	}
+}
+
+// setupChunkSyncer sets up a syncer restoring the given snapshot from peers, which are created
+// with the given serve functions by their ID.
+func setupChunkSyncer(
+	t *testing.T,
+	cfg *config.StateSyncConfig,
+	s *snapshot,
+	serve map[string]func(syncer *syncer, peerID p2p.ID, index uint32),
+) (*syncer, *chunkQueue, *chunkRequests) {
+	syncer := newSyncer(*cfg, log.NewNopLogger(), &proxymocks.AppConnSnapshot{}, &proxymocks.AppConnQuery{},
+		&mocks.StateProvider{}, "", testMetrics())
+	chunks, err := newChunkQueue(s, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { chunks.Close() })
+	syncer.chunks = chunks
+
+	requests := &chunkRequests{peers: make(map[uint32][]p2p.ID)}
+	for id, fn := range serve {
+		id, fn := id, fn
+		peer := chunkPeer(id, func(index uint32) {
+			requests.record(index, id)
+			fn(syncer, p2p.ID(id), index)
+		})
+		_, err := syncer.AddSnapshot(peer, s)
+		require.NoError(t, err)
+	}
+	return syncer, chunks, requests
+}
+
+// serveChunk returns a serve function sending back the chunk after the given delay.
+func serveChunk(delay time.Duration) func(*syncer, p2p.ID, uint32) {
+	return func(syncer *syncer, peerID p2p.ID, index uint32) {
+		go func() {
+			time.Sleep(delay)
+			syncer.AddChunk(&chunk{Height: 1, Format: 1, Index: index, Chunk: []byte{byte(index)}, Sender: peerID}) //nolint:errcheck
+		}()
+	}
+}
+
+// dropChunk is a serve function which never sends back the chunk.
+func dropChunk(*syncer, p2p.ID, uint32) {}
+
+func TestSyncer_fetchChunks_SlowAndFailingPeers(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ChunkRequestTimeout = 100 * time.Millisecond
+	s := &snapshot{Height: 1, Format: 1, Chunks: 12, Hash: []byte{1}}
+	syncer, chunks, requests := setupChunkSyncer(t, cfg, s, map[string]func(*syncer, p2p.ID, uint32){
+		"fast":    serveChunk(0),
+		"slow":    serveChunk(300 * time.Millisecond),
+		"failing": dropChunk,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		go syncer.fetchChunks(ctx, s, chunks)
+	}
+	for i := uint32(0); i < s.Chunks; i++ {
+		c, err := chunks.Next()
+		require.NoError(t, err)
+		require.EqualValues(t, i, c.Index)
+	}
+	cancel()
+
+	retried := 0
+	for i := uint32(0); i < s.Chunks; i++ {
+		peers := requests.get(i)
+		require.NotEmpty(t, peers, "chunk %d", i)
+		// timed out requests are re-requested from a different peer
+		for j := 1; j < len(peers); j++ {
+			assert.NotEqual(t, peers[j-1], peers[j], "chunk %d was requested from the same peer again", i)
+		}
+		if len(peers) > 1 {
+			retried++
+		}
+	}
+	assert.Positive(t, retried)
+	metrics := syncer.metrics
+	assert.Positive(t, metrics.ChunkRetries.(*generic.Counter).Value())
+	assert.Eventually(t, func() bool {
+		return metrics.ChunksInFlight.(*generic.Gauge).Value() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSyncer_fetchChunks_SlowPeerSidelined(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ChunkFetchers = 4
+	s := &snapshot{Height: 1, Format: 1, Chunks: 300, Hash: []byte{1}}
+	syncer, chunks, requests := setupChunkSyncer(t, cfg, s, map[string]func(*syncer, p2p.ID, uint32){
+		"a":    serveChunk(5 * time.Millisecond),
+		"b":    serveChunk(5 * time.Millisecond),
+		"c":    serveChunk(5 * time.Millisecond),
+		"slow": serveChunk(100 * time.Millisecond),
+	})
+	var discovered int
+	syncer.requestSnapshots = func() { discovered++ }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i := int32(0); i < cfg.ChunkFetchers; i++ {
+		go syncer.fetchChunks(ctx, s, chunks)
+	}
+	for i := uint32(0); i < s.Chunks; i++ {
+		_, err := chunks.Next()
+		require.NoError(t, err)
+	}
+	cancel()
+
+	// the slow peer is no longer requested chunks from once its throughput was measured
+	slow := 0
+	for i := uint32(0); i < s.Chunks; i++ {
+		for _, peerID := range requests.get(i) {
+			if peerID == "slow" {
+				slow++
+			}
+		}
+	}
+	assert.LessOrEqual(t, slow, minPeerChunkRequests)
+	syncer.peerMtx.Lock()
+	assert.Equal(t, map[p2p.ID]bool{"slow": true}, syncer.slowPeers)
+	assert.EqualValues(t, minPeerChunkRequests, syncer.peerStats["slow"].chunks)
+	assert.Greater(t, syncer.peerStats["a"].throughput(), syncer.peerStats["slow"].throughput())
+	syncer.peerMtx.Unlock()
+	assert.EqualValues(t, 1, syncer.metrics.SlowPeers.(*generic.Gauge).Value())
+	// there are enough active peers not to discover further ones
+	assert.Zero(t, discovered)
+}
+
+func TestSyncer_activePeers(t *testing.T) {
+	defer func(interval time.Duration) { snapshotsRequestInterval = interval }(snapshotsRequestInterval)
+	snapshotsRequestInterval = time.Hour
+
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	syncer, _, _ := setupChunkSyncer(t, config.DefaultStateSyncConfig(), s, map[string]func(*syncer, p2p.ID, uint32){
+		"fast": dropChunk,
+		"slow": dropChunk,
+	})
+	var discovered int
+	syncer.requestSnapshots = func() { discovered++ }
+	for i := 0; i < minPeerChunkRequests; i++ {
+		syncer.updatePeerStats("fast", func(st *peerChunkStats) {
+			st.chunks++
+			st.bytes += 1000
+			st.fetchTime += 10 * time.Millisecond
+		})
+		syncer.updatePeerStats("slow", func(st *peerChunkStats) {
+			st.failures++
+			st.fetchTime += time.Second
+		})
+	}
+
+	// the slow peer is sidelined, and since too few peers are active, snapshots are requested
+	// from all peers, but not again within the interval
+	peers := syncer.activePeers(s)
+	require.Len(t, peers, 1)
+	assert.EqualValues(t, "fast", peers[0].ID())
+	assert.Equal(t, 1, discovered)
+	syncer.activePeers(s)
+	assert.Equal(t, 1, discovered)
+
+	// once all peers are slow, they are all used again
+	syncer.RemovePeer(peers[0])
+	peers = syncer.activePeers(s)
+	require.Len(t, peers, 1)
+	assert.EqualValues(t, "slow", peers[0].ID())
+}
+
+func TestSyncer_fetchChunks_DistinctPeers(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ChunkFetchers = 3
+	s := &snapshot{Height: 1, Format: 1, Chunks: 3, Hash: []byte{1}}
+	syncer, chunks, requests := setupChunkSyncer(t, cfg, s, map[string]func(*syncer, p2p.ID, uint32){
+		"a": serveChunk(200 * time.Millisecond),
+		"b": serveChunk(200 * time.Millisecond),
+		"c": serveChunk(200 * time.Millisecond),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i := int32(0); i < cfg.ChunkFetchers; i++ {
+		go syncer.fetchChunks(ctx, s, chunks)
 	}
+	for i := uint32(0); i < s.Chunks; i++ {
+		_, err := chunks.Next()
+		require.NoError(t, err)
+	}
+
+	// the chunks are fetched in parallel, each from a different peer
+	peers := make(map[p2p.ID]bool)
+	for i := uint32(0); i < s.Chunks; i++ {
+		requested := requests.get(i)
+		require.Len(t, requested, 1)
+		peers[requested[0]] = true
+	}
+	assert.Len(t, peers, 3)
+}
+
+func TestSyncer_fetchChunks_RetriesExhausted(t *testing.T) {
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ChunkRequestTimeout = 50 * time.Millisecond
+	cfg.ChunkRetries = 2
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+	syncer, chunks, requests := setupChunkSyncer(t, cfg, s, map[string]func(*syncer, p2p.ID, uint32){
+		"a": dropChunk,
+		"b": dropChunk,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go syncer.fetchChunks(ctx, s, chunks)
+
+	_, err := chunks.Next()
+	require.Equal(t, errTimeout, err)
+
+	// the chunk was requested once and retried twice, alternating between the peers
+	peers := requests.get(0)
+	require.Len(t, peers, 3)
+	assert.NotEqual(t, peers[0], peers[1])
+	assert.Equal(t, peers[0], peers[2])
+	assert.EqualValues(t, 2, syncer.metrics.ChunkRetries.(*generic.Counter).Value())
+}
+
+func TestSyncer_requestChunk_Compressed(t *testing.T) {
+	syncer := newSyncer(*config.DefaultStateSyncConfig(), log.NewNopLogger(), &proxymocks.AppConnSnapshot{},
+		&proxymocks.AppConnQuery{}, &mocks.StateProvider{}, "", NopMetrics())
+	s := &snapshot{Height: 1, Format: 1, Chunks: 1, Hash: []byte{1}}
+
+	requests := make(map[p2p.ID]*ssproto.ChunkRequest)
+	peer := func(id p2p.ID) *p2pmocks.PeerEnvelopeSender {
+		peer := &p2pmocks.PeerEnvelopeSender{}
+		peer.On("ID").Return(id)
+		peer.On("SendEnvelope", mock.Anything).Run(func(args mock.Arguments) {
+			requests[id] = args[0].(p2p.Envelope).Message.(*ssproto.ChunkRequest)
+		}).Return(true)
+		_, err := syncer.AddSnapshot(peer, s)
+		require.NoError(t, err)
+		return peer
+	}
+	legacy, compressing := peer("legacy"), peer("compressing")
+	syncer.AddCompressionPeer(compressing.ID())
+
+	// chunks are only requested compressed from peers which advertised it, the untried peer is
+	// chosen each time
+	require.NotNil(t, syncer.requestChunk(s, 0, map[p2p.ID]bool{compressing.ID(): true}, ""))
+	require.NotNil(t, syncer.requestChunk(s, 0, map[p2p.ID]bool{legacy.ID(): true}, ""))
+	assert.False(t, requests[legacy.ID()].Compressed)
+	assert.True(t, requests[compressing.ID()].Compressed)
+
+	// and no longer once the peer is removed
+	syncer.RemovePeer(compressing)
+	_, err := syncer.AddSnapshot(compressing, s)
+	require.NoError(t, err)
+	require.NotNil(t, syncer.requestChunk(s, 0, map[p2p.ID]bool{legacy.ID(): true}, ""))
+	assert.False(t, requests[compressing.ID()].Compressed)
+}
+
+func TestSyncer_Sync_CorruptChunk(t *testing.T) {
+	state := sm.State{
+		Version:         cmtstate.Version{Consensus: cmtversion.Consensus{App: testAppVersion}},
+		LastBlockHeight: 1,
+		AppHash:         []byte("app_hash"),
+	}
+	commit := &types.Commit{BlockID: types.BlockID{Hash: []byte("blockhash")}}
+
+	s := &snapshot{Height: 1, Format: 1, Chunks: 4, Hash: []byte{1}}
+	for i := byte(0); i < 4; i++ {
+		hash := sha256.Sum256([]byte{i})
+		s.ChunkHashes = append(s.ChunkHashes, hash[:])
+	}
+
+	stateProvider := &mocks.StateProvider{}
+	stateProvider.On("AppHash", mock.Anything, uint64(1)).Return(state.AppHash, nil)
+	stateProvider.On("State", mock.Anything, uint64(1)).Return(state, nil)
+	stateProvider.On("Commit", mock.Anything, uint64(1)).Return(commit, nil)
+
+	// The app is offered the snapshot once, and only ever gets valid chunks to apply
+	connSnapshot := &proxymocks.AppConnSnapshot{}
+	connSnapshot.On("OfferSnapshotSync", abci.RequestOfferSnapshot{
+		Snapshot: toABCI(s), AppHash: state.AppHash,
+	}).Once().Return(&abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}, nil)
+	for i := byte(0); i < 4; i++ {
+		connSnapshot.On("ApplySnapshotChunkSync", mock.MatchedBy(func(req abci.RequestApplySnapshotChunk) bool {
+			return req.Index == uint32(i)
+		})).Once().Run(func(args mock.Arguments) {
+			req := args[0].(abci.RequestApplySnapshotChunk)
+			require.Equal(t, []byte{byte(req.Index)}, req.Chunk)
+			require.EqualValues(t, "honest", req.Sender)
+		}).Return(&abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}, nil)
+	}
+	connQuery := &proxymocks.AppConnQuery{}
+	connQuery.On("InfoSync", proxy.RequestInfo).Return(&abci.ResponseInfo{
+		AppVersion:       testAppVersion,
+		LastBlockHeight:  1,
+		LastBlockAppHash: state.AppHash,
+	}, nil)
+
+	// The requests time out long after the test is done, so the chunks sent by the corrupt peer
+	// must be refetched from the honest peer right away
+	cfg := config.DefaultStateSyncConfig()
+	cfg.ChunkRequestTimeout = time.Minute
+	syncer := newSyncer(*cfg, log.NewNopLogger(), connSnapshot, connQuery, stateProvider, "", NopMetrics())
+	requests := &chunkRequests{peers: make(map[uint32][]p2p.ID)}
+	var serve func(id string, body func(index uint32) []byte)
+	serve = func(id string, body func(index uint32) []byte) {
+		peer := chunkPeer(id, func(index uint32) {
+			requests.record(index, id)
+			go syncer.AddChunk(&chunk{ //nolint:errcheck
+				Height: 1, Format: 1, Index: index, Chunk: body(index), Sender: p2p.ID(id),
+			})
+		})
+		_, err := syncer.AddSnapshot(peer, s)
+		require.NoError(t, err)
+	}
+	// The corrupt peer is the only one until it is asked for a chunk, so that
+	// it is asked for one whatever the peers are chosen in
+	var addHonest sync.Once
+	serve("corrupt", func(index uint32) []byte {
+		addHonest.Do(func() {
+			serve("honest", func(index uint32) []byte { return []byte{byte(index)} })
+		})
+		return []byte{byte(index), 0xff}
+	})
+
+	chunks, err := newChunkQueue(s, "")
+	require.NoError(t, err)
+	defer chunks.Close()
+
+	start := time.Now()
+	newState, lastCommit, err := syncer.Sync(s, chunks)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), cfg.ChunkRequestTimeout)
+	assert.Equal(t, state, newState)
+	assert.Equal(t, commit, lastCommit)
+
+	// The corrupt peer was asked for chunks, and rejected for sending them
+	corrupt := 0
+	for i := uint32(0); i < s.Chunks; i++ {
+		for _, peer := range requests.get(i) {
+			if peer == "corrupt" {
+				corrupt++
+			}
+		}
+	}
+	assert.Positive(t, corrupt)
+	peers := syncer.snapshots.GetPeers(s)
+	require.Len(t, peers, 1)
+	assert.EqualValues(t, "honest", peers[0].ID())
+
+	connSnapshot.AssertExpectations(t)
 }
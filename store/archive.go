@@ -0,0 +1,277 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/tendermint/tendermint/libs/protoio"
+	cmtstore "github.com/tendermint/tendermint/proto/tendermint/store"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ArchiveVersion is the version of the block archive format written by
+// ExportBlocks.
+const ArchiveVersion = 1
+
+// maxArchiveFrameSize bounds the size of a single frame of a block archive.
+// A frame holds a block, including the proofs of its parts, and its commit.
+const maxArchiveFrameSize = 2 * types.MaxBlockSizeBytes
+
+// ExportBlocks writes the blocks between the heights from and to (inclusive)
+// to w as a block archive, which can be imported into the block store of
+// another node with ImportBlocks. It returns the number of blocks written.
+//
+// The archive is a sequence of length-prefixed protobuf frames: an
+// ArchiveManifest describing the range, followed by an ArchiveBlock per
+// height holding the block meta, the block parts and the seen commit as
+// encoded in the block store, along with a checksum of this data.
+func (bs *BlockStore) ExportBlocks(w io.Writer, from, to int64) (int64, error) {
+	base, height := bs.Base(), bs.Height()
+	if from < base || to > height || from > to {
+		return 0, fmt.Errorf("cannot export heights %d to %d, the block store contains heights %d to %d",
+			from, to, base, height)
+	}
+	meta, err := bs.loadBlockMeta(from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load block meta at height %d: %w", from, err)
+	}
+
+	writer := protoio.NewDelimitedWriter(w)
+	manifest := &cmtstore.ArchiveManifest{
+		Version:    ArchiveVersion,
+		ChainId:    meta.Header.ChainID,
+		FromHeight: from,
+		ToHeight:   to,
+	}
+	if _, err := writer.WriteMsg(manifest); err != nil {
+		return 0, fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+
+	for h := from; h <= to; h++ {
+		frame, err := bs.archiveBlock(h)
+		if err != nil {
+			return h - from, err
+		}
+		if _, err := writer.WriteMsg(frame); err != nil {
+			return h - from, fmt.Errorf("failed to write block at height %d: %w", h, err)
+		}
+	}
+	return to - from + 1, nil
+}
+
+func (bs *BlockStore) archiveBlock(height int64) (*cmtstore.ArchiveBlock, error) {
+	metaBz, err := bs.db.Get(calcBlockMetaKey(height))
+	if err != nil {
+		return nil, err
+	}
+	if len(metaBz) == 0 {
+		return nil, fmt.Errorf("block meta at height %d not found", height)
+	}
+	meta, err := bs.loadBlockMeta(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block meta at height %d: %w", height, err)
+	}
+	parts, err := bs.readBlockParts(height, int(meta.BlockID.PartSetHeader.Total))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block parts at height %d: %w", height, err)
+	}
+	seenCommitBz, err := bs.db.Get(calcSeenCommitKey(height))
+	if err != nil {
+		return nil, err
+	}
+	if len(seenCommitBz) == 0 {
+		return nil, fmt.Errorf("seen commit at height %d not found", height)
+	}
+
+	frame := &cmtstore.ArchiveBlock{
+		Height:     height,
+		BlockMeta:  metaBz,
+		Parts:      parts,
+		SeenCommit: seenCommitBz,
+	}
+	frame.Checksum = archiveChecksum(frame)
+	return frame, nil
+}
+
+// archiveChecksum returns the SHA-256 hash of the block data of a frame.
+func archiveChecksum(frame *cmtstore.ArchiveBlock) []byte {
+	h := sha256.New()
+	h.Write(frame.BlockMeta)
+	for _, part := range frame.Parts {
+		h.Write(part)
+	}
+	h.Write(frame.SeenCommit)
+	return h.Sum(nil)
+}
+
+// ImportBlocks reads a block archive written by ExportBlocks from r and
+// saves its blocks in the block store. It returns the number of blocks
+// saved.
+//
+// The archive must continue the chain of the store: it can't start above
+// the height following the latest height of the store, nor below its base.
+// Each block is validated against its checksum, its block ID and the block
+// ID of the previous block before it is saved. Heights which are already in
+// the store are skipped if they match the archive, and every height is saved
+// atomically, hence an interrupted import can be resumed by importing the
+// same archive again. The store must not be in use by a running node.
+func (bs *BlockStore) ImportBlocks(r io.Reader) (int64, error) {
+	reader := protoio.NewDelimitedReader(r, maxArchiveFrameSize)
+	manifest := new(cmtstore.ArchiveManifest)
+	if _, err := reader.ReadMsg(manifest); err != nil {
+		return 0, fmt.Errorf("failed to read archive manifest: %w", err)
+	}
+	if manifest.Version != ArchiveVersion {
+		return 0, fmt.Errorf("unsupported archive version %d, expected %d", manifest.Version, ArchiveVersion)
+	}
+	from, to := manifest.FromHeight, manifest.ToHeight
+	if from <= 0 || from > to {
+		return 0, fmt.Errorf("invalid archive range %d to %d", from, to)
+	}
+
+	base, height := bs.Base(), bs.Height()
+	var prevBlockID *types.BlockID
+	if height > 0 {
+		if from < base || from > height+1 {
+			return 0, fmt.Errorf("archive starts at height %d, but the block store contains heights %d to %d",
+				from, base, height)
+		}
+		meta, err := bs.loadBlockMeta(height)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load block meta at height %d: %w", height, err)
+		}
+		if meta.Header.ChainID != manifest.ChainId {
+			return 0, fmt.Errorf("archive is for chain %q, but the block store is for chain %q",
+				manifest.ChainId, meta.Header.ChainID)
+		}
+		if from > base {
+			prevMeta, err := bs.loadBlockMeta(from - 1)
+			if err != nil {
+				return 0, fmt.Errorf("failed to load block meta at height %d: %w", from-1, err)
+			}
+			prevBlockID = &prevMeta.BlockID
+		}
+	}
+
+	imported := int64(0)
+	for h := from; h <= to; h++ {
+		frame := new(cmtstore.ArchiveBlock)
+		if _, err := reader.ReadMsg(frame); err != nil {
+			if errors.Is(err, io.EOF) {
+				return imported, fmt.Errorf("archive ends before height %d", h)
+			}
+			return imported, fmt.Errorf("failed to read block at height %d: %w", h, err)
+		}
+		if frame.Height != h {
+			return imported, fmt.Errorf("expected block at height %d, got height %d", h, frame.Height)
+		}
+		meta, block, err := validateArchiveBlock(frame, manifest.ChainId, prevBlockID)
+		if err != nil {
+			return imported, fmt.Errorf("invalid block at height %d: %w", h, err)
+		}
+
+		if h <= bs.Height() {
+			// the block was imported before, or is part of the store already
+			existing, err := bs.loadBlockMeta(h)
+			if err != nil {
+				return imported, fmt.Errorf("failed to load block meta at height %d: %w", h, err)
+			}
+			if !existing.BlockID.Equals(meta.BlockID) {
+				return imported, fmt.Errorf("block at height %d conflicts with the block store: "+
+					"archive has %v, store has %v", h, meta.BlockID, existing.BlockID)
+			}
+		} else {
+			if err := bs.saveArchiveBlock(frame, block); err != nil {
+				return imported, fmt.Errorf("failed to save block at height %d: %w", h, err)
+			}
+			imported++
+		}
+		prevBlockID = &meta.BlockID
+	}
+	return imported, nil
+}
+
+// validateArchiveBlock decodes and validates the block of an archive frame.
+// If prevBlockID is not nil, the block must link to it.
+func validateArchiveBlock(
+	frame *cmtstore.ArchiveBlock,
+	chainID string,
+	prevBlockID *types.BlockID,
+) (*types.BlockMeta, *types.Block, error) {
+	if !bytes.Equal(archiveChecksum(frame), frame.Checksum) {
+		return nil, nil, errors.New("checksum mismatch")
+	}
+
+	pbbm := new(cmtproto.BlockMeta)
+	if err := proto.Unmarshal(frame.BlockMeta, pbbm); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal to cmtproto.BlockMeta: %w", err)
+	}
+	meta, err := types.BlockMetaFromProto(pbbm)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error from proto blockMeta: %w", err)
+	}
+	if meta.Header.Height != frame.Height {
+		return nil, nil, fmt.Errorf("block meta has height %d", meta.Header.Height)
+	}
+	if meta.Header.ChainID != chainID {
+		return nil, nil, fmt.Errorf("block is for chain %q", meta.Header.ChainID)
+	}
+	if hash := meta.Header.Hash(); !bytes.Equal(hash, meta.BlockID.Hash) {
+		return nil, nil, fmt.Errorf("header hash %X doesn't match block ID hash %X", hash, meta.BlockID.Hash)
+	}
+	block, err := assembleBlock(meta, frame.Parts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if prevBlockID != nil && !block.LastBlockID.Equals(*prevBlockID) {
+		return nil, nil, fmt.Errorf("block doesn't link to the previous block: expected last block ID %v, got %v",
+			*prevBlockID, block.LastBlockID)
+	}
+
+	pbc := new(cmtproto.Commit)
+	if err := proto.Unmarshal(frame.SeenCommit, pbc); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal to cmtproto.Commit: %w", err)
+	}
+	seenCommit, err := types.CommitFromProto(pbc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error from proto commit: %w", err)
+	}
+	if seenCommit.Height != frame.Height || !seenCommit.BlockID.Equals(meta.BlockID) {
+		return nil, nil, fmt.Errorf("seen commit is for block %v at height %d", seenCommit.BlockID, seenCommit.Height)
+	}
+	return meta, block, nil
+}
+
+// saveArchiveBlock saves the block of an archive frame, which must be the
+// block following the latest height of the store, in a single batch.
+func (bs *BlockStore) saveArchiveBlock(frame *cmtstore.ArchiveBlock, block *types.Block) error {
+	height := frame.Height
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	for i, part := range frame.Parts {
+		if err := batch.Set(calcBlockPartKey(height, i), part); err != nil {
+			return err
+		}
+	}
+	if err := batch.Set(calcBlockMetaKey(height), frame.BlockMeta); err != nil {
+		return err
+	}
+	if err := batch.Set(calcBlockHashKey(block.Hash()), []byte(fmt.Sprintf("%d", height))); err != nil {
+		return err
+	}
+	if err := batch.Set(calcBlockCommitKey(height-1), mustEncode(block.LastCommit.ToProto())); err != nil {
+		return err
+	}
+	if err := batch.Set(calcSeenCommitKey(height), frame.SeenCommit); err != nil {
+		return err
+	}
+
+	return bs.saveHeight(batch, height)
+}
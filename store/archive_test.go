@@ -0,0 +1,231 @@
+package store
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/libs/protoio"
+	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	cmtstore "github.com/tendermint/tendermint/proto/tendermint/store"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+// makeChainBlockStore creates a block store with a chain of linked blocks up
+// to the given height. Every 50th block spans multiple parts.
+func makeChainBlockStore(t *testing.T, height int64) *BlockStore {
+	state, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	t.Cleanup(cleanup)
+
+	bs := NewBlockStore(dbm.NewMemDB())
+	saveTestBlocks(bs, state, height, func(state sm.State, h int64, lastCommit *types.Commit) *types.Block {
+		txSize := 100
+		if h%50 == 0 {
+			txSize = 2 * int(types.BlockPartSizeBytes)
+		}
+		block, _ := state.MakeBlock(h, types.Data{Txs: []types.Tx{cmtrand.Bytes(txSize)}}, lastCommit, nil,
+			state.Validators.GetProposer().Address)
+		block.Time = state.LastBlockTime.Add(time.Duration(h) * time.Second)
+		block.InvalidateHashes()
+		return block
+	})
+	return bs
+}
+
+func exportBlocks(t *testing.T, bs *BlockStore, from, to int64) []byte {
+	var buf bytes.Buffer
+	exported, err := bs.ExportBlocks(&buf, from, to)
+	require.NoError(t, err)
+	require.Equal(t, to-from+1, exported)
+	return buf.Bytes()
+}
+
+// rewriteArchive decodes the frames of an archive and encodes them again,
+// after modifying them with the given functions.
+func rewriteArchive(
+	t *testing.T,
+	archive []byte,
+	modifyManifest func(*cmtstore.ArchiveManifest),
+	modifyBlock func(*cmtstore.ArchiveBlock),
+) []byte {
+	reader := protoio.NewDelimitedReader(bytes.NewReader(archive), maxArchiveFrameSize)
+	var buf bytes.Buffer
+	writer := protoio.NewDelimitedWriter(&buf)
+
+	manifest := new(cmtstore.ArchiveManifest)
+	_, err := reader.ReadMsg(manifest)
+	require.NoError(t, err)
+	if modifyManifest != nil {
+		modifyManifest(manifest)
+	}
+	_, err = writer.WriteMsg(manifest)
+	require.NoError(t, err)
+
+	for h := manifest.FromHeight; h <= manifest.ToHeight; h++ {
+		frame := new(cmtstore.ArchiveBlock)
+		_, err := reader.ReadMsg(frame)
+		require.NoError(t, err)
+		if modifyBlock != nil {
+			modifyBlock(frame)
+		}
+		_, err = writer.WriteMsg(frame)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func TestExportImportBlocks(t *testing.T) {
+	src := makeChainBlockStore(t, 300)
+	archive := exportBlocks(t, src, 1, 300)
+
+	dst := NewBlockStore(dbm.NewMemDB())
+	imported, err := dst.ImportBlocks(bytes.NewReader(archive))
+	require.NoError(t, err)
+	assert.EqualValues(t, 300, imported)
+	assert.EqualValues(t, 1, dst.Base())
+	assert.EqualValues(t, 300, dst.Height())
+
+	for h := int64(1); h <= 300; h++ {
+		require.Equal(t, src.LoadBlockMeta(h), dst.LoadBlockMeta(h), "height %d", h)
+		require.Equal(t, src.LoadBlock(h).Hash(), dst.LoadBlock(h).Hash(), "height %d", h)
+		require.Equal(t, src.LoadSeenCommit(h).Hash(), dst.LoadSeenCommit(h).Hash(), "height %d", h)
+		require.Equal(t, h, dst.LoadBlockByHash(src.LoadBlockMeta(h).BlockID.Hash).Height)
+		if h < 300 {
+			require.Equal(t, src.LoadBlockCommit(h).Hash(), dst.LoadBlockCommit(h).Hash(), "height %d", h)
+		}
+	}
+	assert.True(t, dst.Verify(0, 0, 1).OK())
+
+	// the store state is persisted
+	dst = NewBlockStore(dst.db)
+	assert.EqualValues(t, 1, dst.Base())
+	assert.EqualValues(t, 300, dst.Height())
+
+	// exporting heights outside the store fails
+	_, err = src.ExportBlocks(&bytes.Buffer{}, 0, 10)
+	require.Error(t, err)
+	_, err = src.ExportBlocks(&bytes.Buffer{}, 290, 301)
+	require.Error(t, err)
+}
+
+func TestImportBlocksAppendAndResume(t *testing.T) {
+	src := makeChainBlockStore(t, 300)
+
+	// a node which pruned its early blocks can import later ones
+	dst := NewBlockStore(dbm.NewMemDB())
+	_, err := dst.ImportBlocks(bytes.NewReader(exportBlocks(t, src, 1, 150)))
+	require.NoError(t, err)
+	_, err = dst.PruneBlocks(100)
+	require.NoError(t, err)
+
+	// an archive starting above the next height leaves a gap
+	_, err = dst.ImportBlocks(bytes.NewReader(exportBlocks(t, src, 152, 300)))
+	require.Error(t, err)
+	// nor can pruned blocks be imported again
+	_, err = dst.ImportBlocks(bytes.NewReader(exportBlocks(t, src, 50, 300)))
+	require.Error(t, err)
+	assert.EqualValues(t, 150, dst.Height())
+
+	// an interrupted import keeps the heights imported so far
+	archive := exportBlocks(t, src, 101, 300)
+	imported, err := dst.ImportBlocks(bytes.NewReader(archive[:len(archive)*2/3]))
+	require.Error(t, err)
+	require.Positive(t, imported)
+	require.Less(t, imported, int64(150))
+	assert.EqualValues(t, 150+imported, dst.Height())
+	assert.True(t, dst.Verify(0, 0, 1).OK())
+
+	// and importing the archive again resumes it, skipping existing heights
+	resumed, err := dst.ImportBlocks(bytes.NewReader(archive))
+	require.NoError(t, err)
+	assert.EqualValues(t, 150-imported, resumed)
+	assert.EqualValues(t, 100, dst.Base())
+	assert.EqualValues(t, 300, dst.Height())
+	assert.True(t, dst.Verify(0, 0, 1).OK())
+
+	resumed, err = dst.ImportBlocks(bytes.NewReader(archive))
+	require.NoError(t, err)
+	assert.Zero(t, resumed)
+}
+
+func TestImportBlocksValidation(t *testing.T) {
+	src := makeChainBlockStore(t, 100)
+	archive := exportBlocks(t, src, 51, 100)
+
+	testcases := map[string]struct {
+		archive []byte
+		errMsg  string
+	}{
+		"wrong version": {
+			rewriteArchive(t, archive, func(m *cmtstore.ArchiveManifest) { m.Version = 2 }, nil),
+			"unsupported archive version",
+		},
+		"wrong chain": {
+			rewriteArchive(t, archive, func(m *cmtstore.ArchiveManifest) { m.ChainId = "other" }, nil),
+			"archive is for chain",
+		},
+		"corrupt data": {
+			rewriteArchive(t, archive, nil, func(b *cmtstore.ArchiveBlock) {
+				if b.Height == 60 {
+					b.Parts[0][10] ^= 0xff
+				}
+			}),
+			"invalid block at height 60: checksum mismatch",
+		},
+		"modified block": {
+			rewriteArchive(t, archive, nil, func(b *cmtstore.ArchiveBlock) {
+				if b.Height == 70 {
+					b.Parts[0][10] ^= 0xff
+					b.Checksum = archiveChecksum(b)
+				}
+			}),
+			"invalid block at height 70",
+		},
+		"missing height": {
+			rewriteArchive(t, archive, nil, func(b *cmtstore.ArchiveBlock) {
+				if b.Height >= 80 {
+					b.Height++
+				}
+			}),
+			"expected block at height 80",
+		},
+		"truncated": {
+			archive[:len(archive)-10],
+			"at height 100",
+		},
+	}
+	for name, tc := range testcases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			dst := NewBlockStore(dbm.NewMemDB())
+			_, err := dst.ImportBlocks(bytes.NewReader(exportBlocks(t, src, 1, 50)))
+			require.NoError(t, err)
+
+			_, err = dst.ImportBlocks(bytes.NewReader(tc.archive))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errMsg)
+			assert.True(t, dst.Verify(0, 0, 1).OK())
+		})
+	}
+
+	// the blocks must link to the blocks of the store
+	other := makeChainBlockStore(t, 100)
+	dst := NewBlockStore(dbm.NewMemDB())
+	_, err := dst.ImportBlocks(bytes.NewReader(exportBlocks(t, src, 1, 50)))
+	require.NoError(t, err)
+	_, err = dst.ImportBlocks(bytes.NewReader(exportBlocks(t, other, 51, 100)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "doesn't link to the previous block")
+	assert.EqualValues(t, 50, dst.Height())
+
+	// and blocks which are in the store already must match
+	_, err = dst.ImportBlocks(bytes.NewReader(exportBlocks(t, other, 1, 100)))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "conflicts with the block store")
+}
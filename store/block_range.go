@@ -0,0 +1,186 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ErrBlockNotFound is reported by LoadBlockRange and LoadBlockMetaRange for
+// heights which are not in the block store.
+var ErrBlockNotFound = errors.New("block not found")
+
+// ErrMissingBlockPart is reported for a block whose meta is in the block
+// store while one of its parts isn't, i.e. a corrupt block.
+type ErrMissingBlockPart struct {
+	Height int64
+	Index  int
+}
+
+func (e ErrMissingBlockPart) Error() string {
+	return fmt.Sprintf("block store is corrupt: part %d of the block at height %d is missing, but its block meta exists",
+		e.Index, e.Height)
+}
+
+// LoadBlockRange loads the blocks between the heights from and to
+// (inclusive) and returns a result per height, in ascending order. Unlike
+// LoadBlock, it doesn't panic on corrupt data, but reports an error for the
+// affected height. Heights which are not in the store are reported with
+// ErrBlockNotFound, and blocks missing a part with ErrMissingBlockPart.
+//
+// The database is read in a single pass over the range, after which the
+// blocks are decoded in parallel by a bounded pool of workers (see
+// WithLoadRangeWorkers). This is faster than loading the blocks one by one,
+// in particular on machines with multiple CPUs.
+func (bs *BlockStore) LoadBlockRange(from, to int64) []types.BlockRangeResult {
+	return bs.loadRange(from, to, false)
+}
+
+// LoadBlockMetaRange is like LoadBlockRange, but only loads the block metas.
+func (bs *BlockStore) LoadBlockMetaRange(from, to int64) []types.BlockRangeResult {
+	return bs.loadRange(from, to, true)
+}
+
+func (bs *BlockStore) loadRange(from, to int64, metasOnly bool) []types.BlockRangeResult {
+	if to < from {
+		return nil
+	}
+	results := make([]types.BlockRangeResult, to-from+1)
+	parts := make([][][]byte, len(results))
+	for i := range results {
+		height := from + int64(i)
+		results[i].Height = height
+		meta, err := bs.loadBlockMeta(height)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].BlockMeta = meta
+		if !metasOnly {
+			parts[i], results[i].Err = bs.readBlockParts(height, int(meta.BlockID.PartSetHeader.Total))
+		}
+	}
+	if metasOnly {
+		return results
+	}
+
+	decode := func(i int) {
+		if results[i].Err == nil {
+			results[i].Block, results[i].Err = decodeBlockParts(parts[i])
+		}
+		parts[i] = nil
+	}
+	workers := bs.loadRangeWorkers
+	if workers > len(results) {
+		workers = len(results)
+	}
+	if workers <= 1 {
+		for i := range results {
+			decode(i)
+		}
+		return results
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				decode(i)
+			}
+		}()
+	}
+	for i := range results {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// loadBlockMeta is like LoadBlockMeta, but returns an error instead of
+// panicking.
+func (bs *BlockStore) loadBlockMeta(height int64) (*types.BlockMeta, error) {
+	bz, err := bs.db.Get(calcBlockMetaKey(height))
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) == 0 {
+		return nil, ErrBlockNotFound
+	}
+	pbbm := new(cmtproto.BlockMeta)
+	if err := proto.Unmarshal(bz, pbbm); err != nil {
+		return nil, fmt.Errorf("unmarshal to cmtproto.BlockMeta: %w", err)
+	}
+	meta, err := types.BlockMetaFromProto(pbbm)
+	if err != nil {
+		return nil, fmt.Errorf("error from proto blockMeta: %w", err)
+	}
+	return meta, nil
+}
+
+// readBlockParts reads the encoded parts of the block at the given height.
+func (bs *BlockStore) readBlockParts(height int64, total int) ([][]byte, error) {
+	parts := make([][]byte, total)
+	for i := range parts {
+		bz, err := bs.db.Get(calcBlockPartKey(height, i))
+		if err != nil {
+			return nil, err
+		}
+		if len(bz) == 0 {
+			return nil, bs.missingPartError(height, i)
+		}
+		parts[i] = bz
+	}
+	return parts, nil
+}
+
+// missingPartError returns the error for a part of the block at the given
+// height which wasn't found. If the block meta is gone too, the block has been
+// pruned after its meta was read and ErrBlockNotFound is returned. Otherwise,
+// the block is corrupt and an ErrMissingBlockPart is returned.
+func (bs *BlockStore) missingPartError(height int64, index int) error {
+	has, err := bs.db.Has(calcBlockMetaKey(height))
+	if err != nil {
+		return err
+	}
+	if !has {
+		return ErrBlockNotFound
+	}
+	return ErrMissingBlockPart{Height: height, Index: index}
+}
+
+// decodeBlockParts decodes the block from its encoded parts.
+func decodeBlockParts(parts [][]byte) (*types.Block, error) {
+	// the encoded parts are slightly larger than their data, hence their
+	// total size is enough to assemble the block without reallocating
+	size := 0
+	for _, bz := range parts {
+		size += len(bz)
+	}
+	buf := make([]byte, 0, size)
+	pbpart := new(cmtproto.Part)
+	for i, bz := range parts {
+		pbpart.Reset()
+		if err := proto.Unmarshal(bz, pbpart); err != nil {
+			return nil, fmt.Errorf("unmarshal block part %d: %w", i, err)
+		}
+		buf = append(buf, pbpart.Bytes...)
+	}
+	pbb := new(cmtproto.Block)
+	if err := proto.Unmarshal(buf, pbb); err != nil {
+		return nil, fmt.Errorf("unmarshal to cmtproto.Block: %w", err)
+	}
+	block, err := types.BlockFromProto(pbb)
+	if err != nil {
+		return nil, fmt.Errorf("error from proto block: %w", err)
+	}
+	return block, nil
+}
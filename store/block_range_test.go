@@ -0,0 +1,100 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBlockRange(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			db := dbm.NewMemDB()
+			makeBlockStoreWithBlocks(t, db, 10)
+			bs := NewBlockStore(db, WithLoadRangeWorkers(workers))
+
+			results := bs.LoadBlockRange(1, 10)
+			require.Len(t, results, 10)
+			for i, result := range results {
+				height := int64(i + 1)
+				require.NoError(t, result.Err)
+				assert.Equal(t, height, result.Height)
+				assert.Equal(t, bs.LoadBlockMeta(height), result.BlockMeta)
+				assert.Equal(t, bs.LoadBlock(height).Hash(), result.Block.Hash())
+			}
+
+			// corrupt data and missing heights are reported per height
+			flipByte(t, db, calcBlockMetaKey(3))
+			require.NoError(t, db.Set(calcBlockPartKey(5, 0), []byte{0xff, 0xff}))
+			require.NoError(t, db.Delete(calcBlockPartKey(7, 1)))
+
+			results = bs.LoadBlockRange(2, 12)
+			require.Len(t, results, 11)
+			for _, result := range results {
+				switch result.Height {
+				case 3, 5:
+					assert.Error(t, result.Err, "height %d", result.Height)
+					assert.Nil(t, result.Block)
+				case 7:
+					assert.ErrorAs(t, result.Err, &ErrMissingBlockPart{}, "height %d", result.Height)
+					assert.Nil(t, result.Block)
+				case 11, 12:
+					assert.ErrorIs(t, result.Err, ErrBlockNotFound, "height %d", result.Height)
+					assert.Nil(t, result.Block)
+				default:
+					assert.NoError(t, result.Err, "height %d", result.Height)
+					assert.Equal(t, result.Height, result.Block.Height)
+				}
+			}
+
+			assert.Empty(t, bs.LoadBlockRange(5, 4))
+		})
+	}
+}
+
+func TestLoadBlockMetaRange(t *testing.T) {
+	bs := makeBlockStoreWithBlocks(t, dbm.NewMemDB(), 5)
+
+	results := bs.LoadBlockMetaRange(4, 6)
+	require.Len(t, results, 3)
+	for _, result := range results[:2] {
+		require.NoError(t, result.Err)
+		assert.Equal(t, bs.LoadBlockMeta(result.Height), result.BlockMeta)
+		assert.Nil(t, result.Block)
+	}
+	assert.ErrorIs(t, results[2].Err, ErrBlockNotFound)
+}
+
+func benchmarkBlockStore(b *testing.B, options ...BlockStoreOption) *BlockStore {
+	db, err := dbm.NewGoLevelDB("blockstore", b.TempDir())
+	require.NoError(b, err)
+	b.Cleanup(func() { db.Close() })
+	makeBlockStoreWithBlocks(b, db, 100)
+	return NewBlockStore(db, options...)
+}
+
+func BenchmarkLoadBlocks(b *testing.B) {
+	bs := benchmarkBlockStore(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for h := int64(1); h <= 100; h++ {
+			_ = bs.LoadBlockMeta(h)
+			_ = bs.LoadBlock(h)
+		}
+	}
+}
+
+func BenchmarkLoadBlockRange(b *testing.B) {
+	for _, workers := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			bs := benchmarkBlockStore(b, WithLoadRangeWorkers(workers))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = bs.LoadBlockRange(1, 100)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package store
+
+import "bytes"
+
+// keyRange tracks the smallest and largest of a set of keys.
+type keyRange struct {
+	start []byte
+	end   []byte
+}
+
+func (r *keyRange) add(key []byte) {
+	if r.start == nil || bytes.Compare(key, r.start) < 0 {
+		r.start = append([]byte(nil), key...)
+	}
+	if r.end == nil || bytes.Compare(key, r.end) > 0 {
+		r.end = append([]byte(nil), key...)
+	}
+}
+
+func (r *keyRange) merge(other keyRange) {
+	if other.isEmpty() {
+		return
+	}
+	r.add(other.start)
+	r.add(other.end)
+}
+
+func (r *keyRange) isEmpty() bool {
+	return r.start == nil
+}
+
+// limit returns the exclusive upper bound of the range.
+func (r *keyRange) limit() []byte {
+	return append(append([]byte(nil), r.end...), 0)
+}
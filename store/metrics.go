@@ -0,0 +1,73 @@
+package store
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "store"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of heights pruned in the last pruning interval.
+	PrunedHeights metrics.Gauge
+	// Number of heights below the retain height still waiting to be pruned.
+	PruningBacklog metrics.Gauge
+	// Time spent compacting the key range deleted by pruning, in seconds.
+	CompactionDuration metrics.Histogram
+	// Number of bytes reclaimed by compaction, if the database exposes it.
+	CompactionBytesReclaimed metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		PrunedHeights: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pruned_heights",
+			Help:      "Number of heights pruned in the last pruning interval.",
+		}, labels).With(labelsAndValues...),
+		PruningBacklog: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "pruning_backlog",
+			Help:      "Number of heights below the retain height still waiting to be pruned.",
+		}, labels).With(labelsAndValues...),
+		CompactionDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "compaction_duration_seconds",
+			Help:      "Time spent compacting the key range deleted by pruning, in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 12),
+		}, labels).With(labelsAndValues...),
+		CompactionBytesReclaimed: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "compaction_bytes_reclaimed",
+			Help:      "Number of bytes reclaimed by compaction, if the database exposes it.",
+		}, labels).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		PrunedHeights:            discard.NewGauge(),
+		PruningBacklog:           discard.NewGauge(),
+		CompactionDuration:       discard.NewHistogram(),
+		CompactionBytesReclaimed: discard.NewCounter(),
+	}
+}
@@ -0,0 +1,487 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cmtdb "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/service"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	cmttime "github.com/tendermint/tendermint/types/time"
+)
+
+const (
+	// defaultPruningInterval is how often the pruner wakes up to delete a batch
+	// of blocks below the retain height.
+	defaultPruningInterval = time.Second
+
+	// defaultABCIResponsesPerInterval is how many of the ABCI responses which
+	// are no longer retained are deleted per interval if there is no budget
+	// of heights.
+	defaultABCIResponsesPerInterval = 1000
+)
+
+// Sources of the effective retain height, see Pruner.EffectiveRetainHeight.
+const (
+	// RetainHeightSourceNone means that no blocks are pruned.
+	RetainHeightSourceNone = "none"
+	// RetainHeightSourceApp means that the retain height requested by the
+	// application applies.
+	RetainHeightSourceApp = "app"
+	// RetainHeightSourceTime means that the retain height derived from the
+	// retention duration applies.
+	RetainHeightSourceTime = "time"
+)
+
+// StatePruner removes the state data kept for a range of heights. It is
+// implemented by the state store and allows the pruner to prune the state
+// store along with the block store.
+type StatePruner interface {
+	PruneStates(from int64, to int64) error
+	// Base returns the lowest height whose state was not pruned.
+	Base() (int64, error)
+	// LoadConsensusParams is used to determine the heights whose state is
+	// still needed to verify evidence.
+	LoadConsensusParams(height int64) (cmtproto.ConsensusParams, error)
+}
+
+// ABCIResponsesPruner removes the ABCI responses which are no longer
+// retained. It is implemented by the state store.
+type ABCIResponsesPruner interface {
+	PruneABCIResponses(limit int64) (uint64, error)
+}
+
+// Pruner is a service that deletes blocks below the retain height recorded
+// in the BlockStore (see BlockStore.SetRetainHeight) in the background.
+//
+// Every interval the pruner deletes a small batch of blocks, bounded by a
+// per-second budget of heights and/or bytes, so that a large increase of the
+// retain height does not stall block commits. Progress is persisted as the
+// base of the block store, hence a prune interrupted by a restart resumes
+// where it stopped.
+//
+// Optionally, blocks are retained for a duration rather than up to a height
+// (see WithRetentionDuration). The duration is translated into a height using
+// the block times, and the lower of this height and the one requested by the
+// application applies.
+//
+// The state store, if set, is pruned up to the base of the block store, but
+// the validator sets and consensus params needed to verify evidence which is
+// not expired yet (see EvidenceParams.MaxAgeNumBlocks and MaxAgeDuration) are
+// always retained.
+type Pruner struct {
+	service.BaseService
+
+	bs                  *BlockStore
+	statePruner         StatePruner
+	abciResponsesPruner ABCIResponsesPruner
+	metrics             *Metrics
+
+	interval         time.Duration
+	heightsPerSecond int64
+	bytesPerSecond   int64
+
+	// compaction of the pruned key range, if enabled, runs right after a
+	// block was saved and at most once per compactionInterval.
+	compact            bool
+	compactionInterval time.Duration
+	lastCompaction     time.Time
+	pendingCompaction  keyRange
+
+	// retention is the duration for which blocks are retained, if positive.
+	// retentionHeight caches the height derived from it, which only ever
+	// increases, so that the block times are not searched from the base of
+	// the store every time. It is guarded by retentionMtx, since the
+	// effective retain height is also queried via RPC.
+	retention       time.Duration
+	retentionMtx    cmtsync.Mutex
+	retentionHeight int64
+	now             func() time.Time
+
+	// mtx is held while a batch is pruned, so that stopping the pruner can
+	// wait for it to complete.
+	mtx cmtsync.Mutex
+}
+
+// PrunerOption sets an optional parameter on the Pruner.
+type PrunerOption func(*Pruner)
+
+// NewPruner creates a new Pruner for the given block store. By default it
+// runs every second and prunes without any budget.
+func NewPruner(bs *BlockStore, options ...PrunerOption) *Pruner {
+	p := &Pruner{
+		bs:       bs,
+		metrics:  NopMetrics(),
+		interval: defaultPruningInterval,
+		now:      cmttime.Now,
+	}
+	p.BaseService = *service.NewBaseService(nil, "Pruner", p)
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// WithStatePruner sets the state store which is pruned together with the
+// block store.
+func WithStatePruner(sp StatePruner) PrunerOption {
+	return func(p *Pruner) { p.statePruner = sp }
+}
+
+// WithABCIResponsesPruner sets the state store whose ABCI responses are no
+// longer retained and are to be deleted in the background, e.g. because the
+// node was configured to discard them after they were stored.
+func WithABCIResponsesPruner(ap ABCIResponsesPruner) PrunerOption {
+	return func(p *Pruner) { p.abciResponsesPruner = ap }
+}
+
+// WithPruningInterval sets how often the pruner deletes a batch of blocks.
+func WithPruningInterval(interval time.Duration) PrunerOption {
+	return func(p *Pruner) {
+		if interval > 0 {
+			p.interval = interval
+		}
+	}
+}
+
+// WithPruningBudget limits the number of heights and the number of bytes of
+// block data deleted per second. A value of 0 disables the respective limit.
+func WithPruningBudget(heightsPerSecond, bytesPerSecond int64) PrunerOption {
+	return func(p *Pruner) {
+		p.heightsPerSecond = heightsPerSecond
+		p.bytesPerSecond = bytesPerSecond
+	}
+}
+
+// WithCompaction enables compacting the key range deleted by pruning, at
+// most once per interval. Compaction is only triggered right after a block
+// was saved, so that it does not coincide with the next block commit.
+func WithCompaction(interval time.Duration) PrunerOption {
+	return func(p *Pruner) {
+		p.compact = true
+		p.compactionInterval = interval
+	}
+}
+
+// WithRetentionDuration retains the blocks whose time is within the given
+// duration of the current time, in addition to the blocks retained by the
+// application. If the application did not request a retain height, only the
+// duration applies. A duration of 0 disables time-based retention.
+func WithRetentionDuration(retention time.Duration) PrunerOption {
+	return func(p *Pruner) { p.retention = retention }
+}
+
+// WithPrunerMetrics sets the metrics.
+func WithPrunerMetrics(metrics *Metrics) PrunerOption {
+	return func(p *Pruner) { p.metrics = metrics }
+}
+
+// OnStart implements service.Service by spawning the pruning routine.
+func (p *Pruner) OnStart() error {
+	go p.pruneRoutine()
+	return nil
+}
+
+// OnStop implements service.Service. It waits for a batch that is being
+// pruned to complete, so that the block store can be closed safely afterwards.
+func (p *Pruner) OnStop() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+}
+
+func (p *Pruner) pruneRoutine() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	// a nil channel blocks forever, hence block saves are ignored unless
+	// compaction is enabled
+	var saved <-chan struct{}
+	if p.compact {
+		saved = p.bs.saved
+	}
+
+	for {
+		select {
+		case <-p.Quit():
+			return
+		case <-ticker.C:
+			p.mtx.Lock()
+			if p.IsRunning() {
+				p.prune()
+				p.pruneStates()
+				p.pruneABCIResponses()
+			}
+			p.mtx.Unlock()
+		case <-saved:
+			p.mtx.Lock()
+			if p.IsRunning() && p.shouldCompact() {
+				p.compactPruned()
+			}
+			p.mtx.Unlock()
+		}
+	}
+}
+
+// EffectiveRetainHeight returns the height below which blocks are pruned,
+// along with its source (one of the RetainHeightSource constants). A height
+// of 0 means that no blocks are pruned.
+func (p *Pruner) EffectiveRetainHeight() (int64, string) {
+	appHeight := p.bs.RetainHeight()
+	var timeHeight int64
+	if p.retention > 0 {
+		timeHeight = p.timeRetainHeight()
+	}
+
+	switch {
+	case appHeight > 0 && (timeHeight == 0 || appHeight <= timeHeight):
+		return appHeight, RetainHeightSourceApp
+	case timeHeight > 0:
+		return timeHeight, RetainHeightSourceTime
+	default:
+		return 0, RetainHeightSourceNone
+	}
+}
+
+// timeRetainHeight returns the lowest height whose block is not older than
+// the retention duration. The latest block is always retained, and 0 is
+// returned if the store is empty.
+func (p *Pruner) timeRetainHeight() int64 {
+	p.retentionMtx.Lock()
+	defer p.retentionMtx.Unlock()
+
+	height := p.bs.Height()
+	if height == 0 {
+		return 0
+	}
+	lo, hi := p.bs.Base(), height
+	if p.retentionHeight > lo {
+		lo = p.retentionHeight
+	}
+	if lo > hi {
+		lo = hi
+	}
+
+	p.retentionHeight = p.searchBlockTime(lo, hi, p.now().Add(-p.retention))
+	return p.retentionHeight
+}
+
+// searchBlockTime returns the lowest height between lo and hi whose block is
+// not older than cutoff, or hi if there is none.
+func (p *Pruner) searchBlockTime(lo, hi int64, cutoff time.Time) int64 {
+	// block times increase with the height, hence the boundary can be found
+	// by a binary search over the block metas
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		meta := p.bs.LoadBlockMeta(mid)
+		if meta == nil || !meta.Header.Time.Before(cutoff) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// evidenceRetainHeight returns the lowest height at which evidence could
+// still be committed, i.e. whose state is needed to verify the evidence.
+// Evidence expires once it is both older than MaxAgeNumBlocks and
+// MaxAgeDuration. As the times of pruned blocks are unknown, 0 is returned
+// while the base of the block store is within MaxAgeDuration.
+func (p *Pruner) evidenceRetainHeight() (int64, error) {
+	height := p.bs.Height()
+	if height == 0 {
+		return 0, nil
+	}
+	params, err := p.statePruner.LoadConsensusParams(height)
+	if err != nil {
+		return 0, err
+	}
+	meta := p.bs.LoadBlockMeta(height)
+	if meta == nil {
+		return 0, fmt.Errorf("block meta at height %d not found", height)
+	}
+
+	base := p.bs.Base()
+	cutoff := meta.Header.Time.Add(-params.Evidence.MaxAgeDuration)
+	if baseMeta := p.bs.LoadBlockMeta(base); baseMeta == nil || !baseMeta.Header.Time.Before(cutoff) {
+		return 0, nil
+	}
+	retainHeight := p.searchBlockTime(base, height, cutoff)
+	if h := height - params.Evidence.MaxAgeNumBlocks; h < retainHeight {
+		retainHeight = h
+	}
+	return retainHeight, nil
+}
+
+// pruneStates deletes the next batch of states below both the base of the
+// block store and the evidence retain height. The batch is bounded by the
+// budget of heights, if any.
+func (p *Pruner) pruneStates() {
+	pruned, err := p.pruneStatesBatch()
+	if err != nil {
+		p.Logger.Error("failed to prune state database", "err", err)
+		return
+	}
+	if pruned > 0 {
+		p.Logger.Debug("pruned states", "pruned", pruned)
+	}
+}
+
+func (p *Pruner) pruneStatesBatch() (int64, error) {
+	if p.statePruner == nil {
+		return 0, nil
+	}
+	from, err := p.statePruner.Base()
+	if err != nil || from == 0 {
+		return 0, err
+	}
+	target, err := p.evidenceRetainHeight()
+	if err != nil {
+		return 0, err
+	}
+	if base := p.bs.Base(); base < target {
+		target = base
+	}
+	if p.heightsPerSecond > 0 {
+		maxHeights := int64(float64(p.heightsPerSecond) * p.interval.Seconds())
+		if maxHeights < 1 {
+			maxHeights = 1
+		}
+		if from+maxHeights < target {
+			target = from + maxHeights
+		}
+	}
+	if target <= from {
+		return 0, nil
+	}
+	if err := p.statePruner.PruneStates(from, target); err != nil {
+		return 0, err
+	}
+	return target - from, nil
+}
+
+func (p *Pruner) prune() {
+	pruned, err := p.pruneBatch()
+	if err != nil {
+		p.Logger.Error("failed to prune blocks", "err", err)
+		return
+	}
+	if pruned > 0 {
+		retainHeight, source := p.EffectiveRetainHeight()
+		p.Logger.Debug("pruned blocks", "pruned", pruned, "base", p.bs.Base(),
+			"retain_height", retainHeight, "source", source)
+	}
+}
+
+// pruneABCIResponses deletes the next batch of ABCI responses which are no
+// longer retained. The batch is bounded by the budget of heights, if any.
+func (p *Pruner) pruneABCIResponses() {
+	if p.abciResponsesPruner == nil {
+		return
+	}
+	limit := int64(defaultABCIResponsesPerInterval)
+	if p.heightsPerSecond > 0 {
+		limit = int64(float64(p.heightsPerSecond) * p.interval.Seconds())
+		if limit < 1 {
+			limit = 1
+		}
+	}
+	pruned, err := p.abciResponsesPruner.PruneABCIResponses(limit)
+	if err != nil {
+		p.Logger.Error("failed to prune ABCI responses", "err", err)
+		return
+	}
+	if pruned == 0 {
+		// all of them are gone, there is no need to check again
+		p.abciResponsesPruner = nil
+		return
+	}
+	p.Logger.Debug("pruned ABCI responses", "pruned", pruned)
+}
+
+// pruneBatch deletes the next batch of blocks below the retain height that
+// fits into the budget of a single interval. It returns the number of heights
+// pruned.
+func (p *Pruner) pruneBatch() (uint64, error) {
+	base := p.bs.Base()
+	retainHeight, _ := p.EffectiveRetainHeight()
+	if retainHeight <= base {
+		p.metrics.PrunedHeights.Set(0)
+		p.metrics.PruningBacklog.Set(0)
+		return 0, nil
+	}
+
+	target := p.nextTarget(base, retainHeight)
+	var deleted keyRange
+	pruned, err := p.bs.pruneBlocks(target, &deleted)
+	if p.compact {
+		p.pendingCompaction.merge(deleted)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune block store: %w", err)
+	}
+
+	p.metrics.PrunedHeights.Set(float64(pruned))
+	p.metrics.PruningBacklog.Set(float64(retainHeight - target))
+	return pruned, nil
+}
+
+// nextTarget returns the height up to which (exclusive) the next batch
+// should prune, taking the budget per interval into account. At least one
+// height is pruned per batch so that pruning always makes progress.
+func (p *Pruner) nextTarget(base, retainHeight int64) int64 {
+	target := retainHeight
+	if p.heightsPerSecond > 0 {
+		maxHeights := int64(float64(p.heightsPerSecond) * p.interval.Seconds())
+		if maxHeights < 1 {
+			maxHeights = 1
+		}
+		if base+maxHeights < target {
+			target = base + maxHeights
+		}
+	}
+	if p.bytesPerSecond > 0 {
+		maxBytes := int64(float64(p.bytesPerSecond) * p.interval.Seconds())
+		var size int64
+		for h := base; h < target; h++ {
+			if meta := p.bs.LoadBlockMeta(h); meta != nil {
+				size += int64(meta.BlockSize)
+			}
+			if size > maxBytes && h > base {
+				target = h
+				break
+			}
+		}
+	}
+	return target
+}
+
+func (p *Pruner) shouldCompact() bool {
+	return !p.pendingCompaction.isEmpty() && time.Since(p.lastCompaction) >= p.compactionInterval
+}
+
+// compactPruned compacts the key range deleted by pruning since the last
+// compaction.
+func (p *Pruner) compactPruned() {
+	start, end := p.pendingCompaction.start, p.pendingCompaction.limit()
+	p.pendingCompaction = keyRange{}
+	startTime := time.Now()
+	p.lastCompaction = startTime
+
+	reclaimed, err := cmtdb.CompactRange(p.bs.db, start, end)
+	if err != nil {
+		if errors.Is(err, cmtdb.ErrCompactionNotSupported) {
+			p.Logger.Info("disabling compaction", "err", err)
+			p.compact = false
+			return
+		}
+		p.Logger.Error("failed to compact pruned blocks", "err", err)
+		return
+	}
+	took := time.Since(startTime)
+	p.metrics.CompactionDuration.Observe(took.Seconds())
+	p.metrics.CompactionBytesReclaimed.Add(float64(reclaimed))
+	p.Logger.Debug("compacted pruned blocks", "reclaimed_bytes", reclaimed, "took", took)
+}
@@ -0,0 +1,406 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	cmtdb "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+func makeBlockStoreWithBlocks(t testing.TB, db dbm.DB, height int64) *BlockStore {
+	genesis, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	t.Cleanup(cleanup)
+
+	bs := NewBlockStore(db)
+	saveTestBlocks(bs, genesis, height, func(_ sm.State, h int64, _ *types.Commit) *types.Block {
+		// random txs, so that blocks can't be compressed by the database
+		txs := []types.Tx{cmtrand.Bytes(int(types.BlockPartSizeBytes))}
+		block, _ := genesis.MakeBlock(h, types.Data{Txs: txs}, new(types.Commit), nil,
+			genesis.Validators.GetProposer().Address)
+		return block
+	})
+	return bs
+}
+
+// makeBlockStoreWithBlockTimes creates a block store with a block at each of
+// the given times, starting at height 1.
+func makeBlockStoreWithBlockTimes(t *testing.T, times []time.Time) *BlockStore {
+	genesis, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	t.Cleanup(cleanup)
+
+	bs := NewBlockStore(dbm.NewMemDB())
+	saveTestBlocks(bs, genesis, int64(len(times)), func(_ sm.State, h int64, _ *types.Commit) *types.Block {
+		block, _ := genesis.MakeBlock(h, types.Data{}, new(types.Commit), nil,
+			genesis.Validators.GetProposer().Address)
+		block.Time = times[h-1]
+		block.InvalidateHashes()
+		return block
+	})
+	return bs
+}
+
+func TestBlockStoreSetRetainHeight(t *testing.T) {
+	db := dbm.NewMemDB()
+	bs := makeBlockStoreWithBlocks(t, db, 10)
+
+	require.Error(t, bs.SetRetainHeight(0))
+	require.Error(t, bs.SetRetainHeight(11))
+
+	require.NoError(t, bs.SetRetainHeight(5))
+	assert.EqualValues(t, 5, bs.RetainHeight())
+	// setting the retain height only records it
+	assert.EqualValues(t, 1, bs.Base())
+
+	// lowering the retain height is a no-op
+	require.NoError(t, bs.SetRetainHeight(3))
+	assert.EqualValues(t, 5, bs.RetainHeight())
+
+	// the retain height is persisted
+	assert.EqualValues(t, 5, NewBlockStore(db).RetainHeight())
+}
+
+func TestPrunerHeightsBudget(t *testing.T) {
+	bs := makeBlockStoreWithBlocks(t, dbm.NewMemDB(), 50)
+	require.NoError(t, bs.SetRetainHeight(40))
+
+	pruner := NewPruner(bs,
+		WithPruningInterval(time.Second),
+		WithPruningBudget(10, 0),
+	)
+
+	pruned, err := pruner.pruneBatch()
+	require.NoError(t, err)
+	assert.EqualValues(t, 10, pruned)
+	assert.EqualValues(t, 11, bs.Base())
+	assert.Nil(t, bs.LoadBlock(10))
+	assert.NotNil(t, bs.LoadBlock(11))
+}
+
+func TestPrunerBytesBudget(t *testing.T) {
+	bs := makeBlockStoreWithBlocks(t, dbm.NewMemDB(), 50)
+	require.NoError(t, bs.SetRetainHeight(40))
+	blockSize := int64(bs.LoadBlockMeta(1).BlockSize)
+
+	pruner := NewPruner(bs,
+		WithPruningInterval(time.Second),
+		WithPruningBudget(0, 5*blockSize),
+	)
+
+	pruned, err := pruner.pruneBatch()
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, pruned)
+	assert.EqualValues(t, 6, bs.Base())
+
+	// a budget smaller than a single block still makes progress
+	pruner = NewPruner(bs,
+		WithPruningInterval(time.Second),
+		WithPruningBudget(0, 1),
+	)
+	pruned, err = pruner.pruneBatch()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, pruned)
+	assert.EqualValues(t, 7, bs.Base())
+}
+
+func TestPrunerResumesAfterRestart(t *testing.T) {
+	db := dbm.NewMemDB()
+	bs := makeBlockStoreWithBlocks(t, db, 100)
+	require.NoError(t, bs.SetRetainHeight(80))
+
+	pruner := NewPruner(bs, WithPruningBudget(20, 0))
+	_, err := pruner.pruneBatch()
+	require.NoError(t, err)
+	require.EqualValues(t, 21, bs.Base())
+
+	// simulate a restart by reloading the block store from the same database
+	bs = NewBlockStore(db)
+	require.EqualValues(t, 21, bs.Base())
+	require.EqualValues(t, 80, bs.RetainHeight())
+
+	pruner = NewPruner(bs,
+		WithPruningInterval(10*time.Millisecond),
+		WithPruningBudget(1000, 0),
+	)
+	require.NoError(t, pruner.Start())
+	t.Cleanup(func() { _ = pruner.Stop() })
+
+	require.Eventually(t, func() bool {
+		return bs.Base() == 80
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Nil(t, bs.LoadBlock(79))
+	assert.NotNil(t, bs.LoadBlock(80))
+	assert.EqualValues(t, 100, bs.Height())
+}
+
+func TestPrunerRetentionDuration(t *testing.T) {
+	// blocks at varying intervals: 1 minute up to height 10, 10 minutes up
+	// to height 20 and 1 minute again up to height 30
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := make([]time.Time, 30)
+	blockTime := start
+	for i := range times {
+		interval := time.Minute
+		if i >= 10 && i < 20 {
+			interval = 10 * time.Minute
+		}
+		blockTime = blockTime.Add(interval)
+		times[i] = blockTime
+	}
+	bs := makeBlockStoreWithBlockTimes(t, times)
+
+	// expectedHeight returns the lowest height whose block is not older than
+	// the cutoff, retaining the latest block regardless
+	expectedHeight := func(cutoff time.Time) int64 {
+		for i, blockTime := range times {
+			if !blockTime.Before(cutoff) {
+				return int64(i + 1)
+			}
+		}
+		return int64(len(times))
+	}
+
+	now := times[29].Add(5 * time.Minute)
+	pruner := NewPruner(bs, WithRetentionDuration(time.Hour))
+	pruner.now = func() time.Time { return now }
+
+	height, source := pruner.EffectiveRetainHeight()
+	assert.Equal(t, expectedHeight(now.Add(-time.Hour)), height)
+	assert.EqualValues(t, 16, height)
+	assert.Equal(t, RetainHeightSourceTime, source)
+
+	// the lower retain height of the application is more conservative
+	require.NoError(t, bs.SetRetainHeight(12))
+	height, source = pruner.EffectiveRetainHeight()
+	assert.EqualValues(t, 12, height)
+	assert.Equal(t, RetainHeightSourceApp, source)
+
+	// as time passes, the retention boundary moves up
+	require.NoError(t, bs.SetRetainHeight(25))
+	for _, elapsed := range []time.Duration{10 * time.Minute, 20 * time.Minute, 30 * time.Minute} {
+		now = times[29].Add(5*time.Minute + elapsed)
+		height, source = pruner.EffectiveRetainHeight()
+		assert.Equal(t, expectedHeight(now.Add(-time.Hour)), height, "elapsed %v", elapsed)
+		assert.Equal(t, RetainHeightSourceTime, source)
+	}
+
+	// blocks are pruned up to the effective retain height
+	pruned, err := pruner.pruneBatch()
+	require.NoError(t, err)
+	assert.EqualValues(t, height-1, pruned)
+	assert.Equal(t, height, bs.Base())
+
+	// once all blocks are older than the retention duration, the application
+	// decides, and the latest block is always retained
+	now = times[29].Add(24 * time.Hour)
+	height, source = pruner.EffectiveRetainHeight()
+	assert.EqualValues(t, 25, height)
+	assert.Equal(t, RetainHeightSourceApp, source)
+
+	pruner = NewPruner(bs, WithRetentionDuration(time.Hour))
+	pruner.now = func() time.Time { return now }
+	assert.EqualValues(t, 30, pruner.timeRetainHeight())
+}
+
+func TestPrunerRetainHeightSource(t *testing.T) {
+	bs := makeBlockStoreWithBlocks(t, dbm.NewMemDB(), 10)
+
+	// without an application retain height nor a retention duration, no
+	// blocks are pruned
+	pruner := NewPruner(bs)
+	height, source := pruner.EffectiveRetainHeight()
+	assert.Zero(t, height)
+	assert.Equal(t, RetainHeightSourceNone, source)
+
+	require.NoError(t, bs.SetRetainHeight(5))
+	height, source = pruner.EffectiveRetainHeight()
+	assert.EqualValues(t, 5, height)
+	assert.Equal(t, RetainHeightSourceApp, source)
+}
+
+func TestPrunerCompactsGoLevelDB(t *testing.T) {
+	db, err := dbm.NewGoLevelDB("blockstore", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	bs := makeBlockStoreWithBlocks(t, db, 100)
+	require.NoError(t, bs.SetRetainHeight(95))
+
+	// flush everything written so far to sstables, so that the size of the
+	// database reflects the stored blocks
+	_, err = cmtdb.CompactRange(db, nil, nil)
+	require.NoError(t, err)
+	sizeBefore := dbSize(t, db)
+
+	pruner := NewPruner(bs, WithCompaction(0))
+	pruned, err := pruner.pruneBatch()
+	require.NoError(t, err)
+	require.EqualValues(t, 94, pruned)
+	require.True(t, pruner.shouldCompact())
+
+	// without compaction, deleting the blocks does not shrink the database
+	assert.Equal(t, sizeBefore, dbSize(t, db))
+
+	pruner.compactPruned()
+	assert.False(t, pruner.shouldCompact())
+	assert.True(t, pruner.compact, "compaction should remain enabled for goleveldb")
+
+	sizeAfter := dbSize(t, db)
+	assert.Less(t, sizeAfter, sizeBefore/10, "expected compaction to reclaim the space of pruned blocks")
+	assert.NotNil(t, bs.LoadBlock(95))
+}
+
+func TestPrunerCompactionUnsupported(t *testing.T) {
+	bs := makeBlockStoreWithBlocks(t, dbm.NewMemDB(), 10)
+	require.NoError(t, bs.SetRetainHeight(5))
+
+	pruner := NewPruner(bs, WithCompaction(0))
+	_, err := pruner.pruneBatch()
+	require.NoError(t, err)
+
+	pruner.compactPruned()
+	assert.False(t, pruner.compact, "compaction should be disabled for unsupported backends")
+}
+
+type abciResponsesPruner struct {
+	remaining int64
+	limits    []int64
+}
+
+func (p *abciResponsesPruner) PruneABCIResponses(limit int64) (uint64, error) {
+	p.limits = append(p.limits, limit)
+	pruned := limit
+	if pruned > p.remaining {
+		pruned = p.remaining
+	}
+	p.remaining -= pruned
+	return uint64(pruned), nil
+}
+
+func TestPrunerPrunesABCIResponses(t *testing.T) {
+	bs := makeBlockStoreWithBlocks(t, dbm.NewMemDB(), 10)
+	ap := &abciResponsesPruner{remaining: 25}
+	pruner := NewPruner(bs,
+		WithABCIResponsesPruner(ap),
+		WithPruningInterval(time.Second),
+		WithPruningBudget(10, 0),
+	)
+
+	for i := 0; i < 5; i++ {
+		pruner.pruneABCIResponses()
+	}
+	assert.Zero(t, ap.remaining)
+	// the pruner stops checking once all responses are gone
+	assert.Equal(t, []int64{10, 10, 10, 10}, ap.limits)
+}
+
+type statePruner struct {
+	base     int64
+	evidence cmtproto.EvidenceParams
+	pruned   [][2]int64
+}
+
+func (p *statePruner) PruneStates(from, to int64) error {
+	p.pruned = append(p.pruned, [2]int64{from, to})
+	p.base = to
+	return nil
+}
+
+func (p *statePruner) Base() (int64, error) {
+	return p.base, nil
+}
+
+func (p *statePruner) LoadConsensusParams(int64) (cmtproto.ConsensusParams, error) {
+	return cmtproto.ConsensusParams{Evidence: p.evidence}, nil
+}
+
+func TestPrunerRetainsEvidenceStates(t *testing.T) {
+	// blocks at 1 minute intervals
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := make([]time.Time, 100)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Minute)
+	}
+	bs := makeBlockStoreWithBlockTimes(t, times)
+	require.NoError(t, bs.SetRetainHeight(90))
+
+	// evidence of height 80 is 20 blocks old and hence not expired yet
+	sp := &statePruner{base: 1, evidence: cmtproto.EvidenceParams{
+		MaxAgeNumBlocks: 20,
+		MaxAgeDuration:  5 * time.Minute,
+	}}
+	pruner := NewPruner(bs, WithStatePruner(sp), WithPruningBudget(0, 0))
+	_, err := pruner.pruneBatch()
+	require.NoError(t, err)
+	require.EqualValues(t, 90, bs.Base())
+
+	pruned, err := pruner.pruneStatesBatch()
+	require.NoError(t, err)
+	assert.EqualValues(t, 79, pruned)
+	assert.Equal(t, [][2]int64{{1, 80}}, sp.pruned)
+
+	// nothing else is pruned until the evidence expires
+	pruned, err = pruner.pruneStatesBatch()
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+
+	// evidence is only expired once it exceeds both the number of blocks and
+	// the duration; the blocks below the base are within the duration, and as
+	// their times are unknown, their states are retained
+	sp.evidence.MaxAgeDuration = 15 * time.Minute
+	sp.evidence.MaxAgeNumBlocks = 5
+	sp.base, sp.pruned = 1, nil
+	pruned, err = pruner.pruneStatesBatch()
+	require.NoError(t, err)
+	assert.Zero(t, pruned)
+
+	// with a shorter duration, states are pruned up to the block store base
+	sp.evidence.MaxAgeDuration = 5 * time.Minute
+	pruned, err = pruner.pruneStatesBatch()
+	require.NoError(t, err)
+	assert.EqualValues(t, 89, pruned)
+	assert.Equal(t, [][2]int64{{1, 90}}, sp.pruned)
+}
+
+func TestPrunerStatesBudget(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := make([]time.Time, 50)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Minute)
+	}
+	bs := makeBlockStoreWithBlockTimes(t, times)
+	require.NoError(t, bs.SetRetainHeight(40))
+
+	sp := &statePruner{base: 1}
+	pruner := NewPruner(bs,
+		WithStatePruner(sp),
+		WithPruningInterval(time.Second),
+		WithPruningBudget(10, 0),
+	)
+	for i := 0; i < 5; i++ {
+		_, err := pruner.pruneBatch()
+		require.NoError(t, err)
+		_, err = pruner.pruneStatesBatch()
+		require.NoError(t, err)
+	}
+	// the states are pruned in step with the blocks
+	assert.EqualValues(t, 40, bs.Base())
+	assert.Equal(t, [][2]int64{{1, 11}, {11, 21}, {21, 31}, {31, 40}}, sp.pruned)
+}
+
+func dbSize(t *testing.T, db *dbm.GoLevelDB) int64 {
+	// all keys of the block store are printable, hence lower than 0xff
+	sizes, err := db.DB().SizeOf([]util.Range{{Limit: []byte{0xff}}})
+	require.NoError(t, err)
+	return sizes.Sum()
+}
@@ -0,0 +1,247 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/gogo/protobuf/proto"
+
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+/*
+SeenBlockStore persists complete proposal blocks, whether or not they were
+eventually committed, keyed by their hash. It is meant for debugging consensus
+failures, where one needs to inspect a block that was proposed but never made
+it into the canonical chain and is hence unknown to the BlockStore.
+
+Only the blocks of the last maxHeights heights are kept, and the store is
+further bounded by the number of blocks and the total number of bytes it
+holds. When a bound is exceeded, blocks of the lowest heights are removed
+first.
+*/
+type SeenBlockStore struct {
+	db dbm.DB
+
+	maxHeights int64
+	maxCount   int
+	maxBytes   int64
+
+	// mtx guards the in-memory index of the stored blocks below. The index is
+	// ordered by height (and insertion order within a height) and is used to
+	// find the blocks to prune without scanning the database.
+	mtx        cmtsync.Mutex
+	entries    []seenBlockEntry
+	totalBytes int64
+}
+
+type seenBlockEntry struct {
+	height int64
+	hash   []byte
+	size   int64
+}
+
+// NewSeenBlockStore returns a SeenBlockStore backed by the given DB, which
+// keeps the blocks of the last maxHeights heights, at most maxCount blocks
+// and at most maxBytes bytes. A maxCount or maxBytes of 0 disables the
+// respective bound.
+func NewSeenBlockStore(db dbm.DB, maxHeights int64, maxCount int, maxBytes int64) (*SeenBlockStore, error) {
+	if maxHeights <= 0 {
+		return nil, fmt.Errorf("max heights must be greater than 0, got %d", maxHeights)
+	}
+	s := &SeenBlockStore{
+		db:         db,
+		maxHeights: maxHeights,
+		maxCount:   maxCount,
+		maxBytes:   maxBytes,
+	}
+
+	itr, err := dbm.IteratePrefix(db, seenBlockPrefix)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		height, hash, err := parseSeenBlockKey(itr.Key())
+		if err != nil {
+			return nil, err
+		}
+		size := int64(len(itr.Value()))
+		s.entries = append(s.entries, seenBlockEntry{height: height, hash: hash, size: size})
+		s.totalBytes += size
+	}
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveBlock persists the given block and prunes the store down to its
+// bounds. Saving a block that is already stored is a no-op.
+func (s *SeenBlockStore) SaveBlock(block *types.Block) error {
+	if block == nil {
+		return fmt.Errorf("cannot save a nil block")
+	}
+	hash := block.Hash()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, e := range s.entries {
+		if e.height == block.Height && string(e.hash) == string(hash) {
+			return nil
+		}
+	}
+
+	pbb, err := block.ToProto()
+	if err != nil {
+		return fmt.Errorf("unable to convert block to proto: %w", err)
+	}
+	bz, err := proto.Marshal(pbb)
+	if err != nil {
+		return fmt.Errorf("unable to marshal block: %w", err)
+	}
+	if s.maxBytes > 0 && int64(len(bz)) > s.maxBytes {
+		return fmt.Errorf("block of %d bytes exceeds the maximum size of the store (%d bytes)", len(bz), s.maxBytes)
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	if err := batch.Set(calcSeenBlockKey(block.Height, hash), bz); err != nil {
+		return err
+	}
+	if err := batch.Set(calcSeenBlockHashKey(hash), int64ToBytes(block.Height)); err != nil {
+		return err
+	}
+
+	// insert the entry keeping the index ordered by height
+	entry := seenBlockEntry{height: block.Height, hash: hash, size: int64(len(bz))}
+	i := len(s.entries)
+	for i > 0 && s.entries[i-1].height > entry.height {
+		i--
+	}
+	s.entries = append(s.entries, seenBlockEntry{})
+	copy(s.entries[i+1:], s.entries[i:])
+	s.entries[i] = entry
+	s.totalBytes += entry.size
+
+	pruned, err := s.prune(batch)
+	if err != nil {
+		return err
+	}
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+	s.entries = s.entries[pruned:]
+	return nil
+}
+
+// prune adds the deletion of the blocks exceeding the bounds of the store to
+// the batch and returns the number of entries, from the start of the index,
+// that were pruned.
+func (s *SeenBlockStore) prune(batch dbm.Batch) (int, error) {
+	if len(s.entries) == 0 {
+		return 0, nil
+	}
+	minHeight := s.entries[len(s.entries)-1].height - s.maxHeights + 1
+	count := len(s.entries)
+
+	pruned := 0
+	for pruned < len(s.entries) {
+		e := s.entries[pruned]
+		if e.height >= minHeight &&
+			(s.maxCount <= 0 || count <= s.maxCount) &&
+			(s.maxBytes <= 0 || s.totalBytes <= s.maxBytes) {
+			break
+		}
+		if err := batch.Delete(calcSeenBlockKey(e.height, e.hash)); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(calcSeenBlockHashKey(e.hash)); err != nil {
+			return 0, err
+		}
+		s.totalBytes -= e.size
+		count--
+		pruned++
+	}
+	return pruned, nil
+}
+
+// LoadBlockByHash returns the block with the given hash, or nil if no such
+// block was seen or it has been pruned since.
+func (s *SeenBlockStore) LoadBlockByHash(hash []byte) *types.Block {
+	bz, err := s.db.Get(calcSeenBlockHashKey(hash))
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return nil
+	}
+	if len(bz) != 8 {
+		panic(fmt.Sprintf("failed to extract height from %X", bz))
+	}
+	height := int64(binary.BigEndian.Uint64(bz))
+
+	bz, err = s.db.Get(calcSeenBlockKey(height, hash))
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return nil
+	}
+	pbb := new(cmtproto.Block)
+	if err := proto.Unmarshal(bz, pbb); err != nil {
+		panic(fmt.Sprintf("Error reading seen block: %v", err))
+	}
+	block, err := types.BlockFromProto(pbb)
+	if err != nil {
+		panic(fmt.Errorf("error from proto block: %w", err))
+	}
+	return block
+}
+
+// Size returns the number of blocks and the number of bytes stored.
+func (s *SeenBlockStore) Size() (int, int64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return len(s.entries), s.totalBytes
+}
+
+// Close closes the underlying database.
+func (s *SeenBlockStore) Close() error {
+	return s.db.Close()
+}
+
+//-----------------------------------------------------------------------------
+
+var seenBlockPrefix = []byte("SB:")
+
+// calcSeenBlockKey encodes the height big-endian, so that iterating over the
+// seen blocks yields them ordered by height.
+func calcSeenBlockKey(height int64, hash []byte) []byte {
+	key := make([]byte, 0, len(seenBlockPrefix)+8+1+2*len(hash))
+	key = append(key, seenBlockPrefix...)
+	key = append(key, int64ToBytes(height)...)
+	key = append(key, ':')
+	return append(key, hex.EncodeToString(hash)...)
+}
+
+func parseSeenBlockKey(key []byte) (int64, []byte, error) {
+	rest := key[len(seenBlockPrefix):]
+	if len(rest) < 9 || rest[8] != ':' {
+		return 0, nil, fmt.Errorf("invalid seen block key %X", key)
+	}
+	hash, err := hex.DecodeString(string(rest[9:]))
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid seen block key %X: %w", key, err)
+	}
+	return int64(binary.BigEndian.Uint64(rest[:8])), hash, nil
+}
+
+func calcSeenBlockHashKey(hash []byte) []byte {
+	return []byte(fmt.Sprintf("SBH:%x", hash))
+}
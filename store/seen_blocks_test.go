@@ -0,0 +1,122 @@
+package store
+
+import (
+	"os"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+func makeSeenBlock(t *testing.T, state sm.State, height int64, txSize int) *types.Block {
+	txs := []types.Tx{cmtrand.Bytes(txSize)}
+	block, _ := state.MakeBlock(height, types.Data{Txs: txs}, new(types.Commit), nil,
+		state.Validators.GetProposer().Address)
+	return block
+}
+
+func loadTestState(t *testing.T) sm.State {
+	config := cfg.ResetTestRoot("seen_blocks_test")
+	t.Cleanup(func() { os.RemoveAll(config.RootDir) })
+	stateStore := sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{
+		DiscardABCIResponses: false,
+	})
+	state, err := stateStore.LoadFromDBOrGenesisFile(config.GenesisFile())
+	require.NoError(t, err)
+	return state
+}
+
+func TestSeenBlockStoreSaveLoad(t *testing.T) {
+	state := loadTestState(t)
+	db := dbm.NewMemDB()
+	bs := NewBlockStore(dbm.NewMemDB())
+	sbs, err := NewSeenBlockStore(db, 10, 0, 0)
+	require.NoError(t, err)
+
+	// two competing proposals for the same height, none of them committed
+	proposal1 := makeSeenBlock(t, state, 1, 100)
+	proposal2 := makeSeenBlock(t, state, 1, 100)
+	require.NoError(t, sbs.SaveBlock(proposal1))
+	require.NoError(t, sbs.SaveBlock(proposal2))
+	// saving a block twice is a no-op
+	require.NoError(t, sbs.SaveBlock(proposal1))
+
+	require.Nil(t, bs.LoadBlockByHash(proposal1.Hash()))
+	got := sbs.LoadBlockByHash(proposal1.Hash())
+	require.NotNil(t, got)
+	assert.Equal(t, proposal1.Hash(), got.Hash())
+	got = sbs.LoadBlockByHash(proposal2.Hash())
+	require.NotNil(t, got)
+	assert.Equal(t, proposal2.Hash(), got.Hash())
+	assert.Nil(t, sbs.LoadBlockByHash([]byte("unknown")))
+
+	count, _ := sbs.Size()
+	assert.Equal(t, 2, count)
+
+	// the index is restored from the database
+	sbs, err = NewSeenBlockStore(db, 10, 0, 0)
+	require.NoError(t, err)
+	count, _ = sbs.Size()
+	assert.Equal(t, 2, count)
+	assert.NotNil(t, sbs.LoadBlockByHash(proposal2.Hash()))
+}
+
+func TestSeenBlockStoreBounds(t *testing.T) {
+	state := loadTestState(t)
+
+	t.Run("heights", func(t *testing.T) {
+		sbs, err := NewSeenBlockStore(dbm.NewMemDB(), 3, 0, 0)
+		require.NoError(t, err)
+		blocks := make([]*types.Block, 0, 5)
+		for h := int64(1); h <= 5; h++ {
+			block := makeSeenBlock(t, state, h, 100)
+			require.NoError(t, sbs.SaveBlock(block))
+			blocks = append(blocks, block)
+		}
+		assert.Nil(t, sbs.LoadBlockByHash(blocks[0].Hash()))
+		assert.Nil(t, sbs.LoadBlockByHash(blocks[1].Hash()))
+		for _, block := range blocks[2:] {
+			assert.NotNil(t, sbs.LoadBlockByHash(block.Hash()))
+		}
+		count, _ := sbs.Size()
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		sbs, err := NewSeenBlockStore(dbm.NewMemDB(), 100, 2, 0)
+		require.NoError(t, err)
+		blocks := make([]*types.Block, 0, 3)
+		for h := int64(1); h <= 3; h++ {
+			block := makeSeenBlock(t, state, h, 100)
+			require.NoError(t, sbs.SaveBlock(block))
+			blocks = append(blocks, block)
+		}
+		assert.Nil(t, sbs.LoadBlockByHash(blocks[0].Hash()))
+		assert.NotNil(t, sbs.LoadBlockByHash(blocks[1].Hash()))
+		assert.NotNil(t, sbs.LoadBlockByHash(blocks[2].Hash()))
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		sbs, err := NewSeenBlockStore(dbm.NewMemDB(), 100, 0, 5000)
+		require.NoError(t, err)
+		blocks := make([]*types.Block, 0, 3)
+		for h := int64(1); h <= 3; h++ {
+			block := makeSeenBlock(t, state, h, 2000)
+			require.NoError(t, sbs.SaveBlock(block))
+			blocks = append(blocks, block)
+		}
+		assert.Nil(t, sbs.LoadBlockByHash(blocks[0].Hash()))
+		assert.NotNil(t, sbs.LoadBlockByHash(blocks[2].Hash()))
+		_, size := sbs.Size()
+		assert.LessOrEqual(t, size, int64(5000))
+
+		// a block larger than the store is rejected
+		require.Error(t, sbs.SaveBlock(makeSeenBlock(t, state, 4, 6000)))
+	})
+}
@@ -1,7 +1,10 @@
 package store
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"runtime"
 	"strconv"
 
 	dbm "github.com/cometbft/cometbft-db"
@@ -39,20 +42,50 @@ type BlockStore struct {
 	// database contents. The only reason for keeping these fields in the struct is that the data
 	// can't efficiently be queried from the database since the key encoding we use is not
 	// lexicographically ordered.
-	mtx    cmtsync.RWMutex
-	base   int64
-	height int64
+	mtx          cmtsync.RWMutex
+	base         int64
+	height       int64
+	retainHeight int64
+
+	// saved is signaled after a block was saved. It allows the pruner to
+	// schedule work in between block commits.
+	saved chan struct{}
+
+	// loadRangeWorkers is the number of workers decoding blocks in
+	// LoadBlockRange.
+	loadRangeWorkers int
+}
+
+// BlockStoreOption sets an optional parameter on the BlockStore.
+type BlockStoreOption func(*BlockStore)
+
+// WithLoadRangeWorkers sets the number of workers decoding blocks in parallel
+// in LoadBlockRange. With 1 worker, blocks are decoded sequentially. It
+// defaults to GOMAXPROCS.
+func WithLoadRangeWorkers(workers int) BlockStoreOption {
+	return func(bs *BlockStore) {
+		if workers > 0 {
+			bs.loadRangeWorkers = workers
+		}
+	}
 }
 
 // NewBlockStore returns a new BlockStore with the given DB,
 // initialized to the last height that was committed to the DB.
-func NewBlockStore(db dbm.DB) *BlockStore {
-	bs := LoadBlockStoreState(db)
-	return &BlockStore{
-		base:   bs.Base,
-		height: bs.Height,
-		db:     db,
-	}
+func NewBlockStore(db dbm.DB, options ...BlockStoreOption) *BlockStore {
+	bss := LoadBlockStoreState(db)
+	bs := &BlockStore{
+		base:             bss.Base,
+		height:           bss.Height,
+		retainHeight:     loadRetainHeight(db),
+		db:               db,
+		saved:            make(chan struct{}, 1),
+		loadRangeWorkers: runtime.GOMAXPROCS(0),
+	}
+	for _, option := range options {
+		option(bs)
+	}
+	return bs
 }
 
 // Base returns the first known contiguous block height, or 0 for empty block stores.
@@ -91,6 +124,7 @@ func (bs *BlockStore) LoadBaseMeta() *types.BlockMeta {
 
 // LoadBlock returns the block with the given height.
 // If no block is found for that height, it returns nil.
+// Panics with an ErrMissingBlockPart if a part of the block is missing.
 func (bs *BlockStore) LoadBlock(height int64) *types.Block {
 	var blockMeta = bs.LoadBlockMeta(height)
 	if blockMeta == nil {
@@ -101,10 +135,15 @@ func (bs *BlockStore) LoadBlock(height int64) *types.Block {
 	buf := []byte{}
 	for i := 0; i < int(blockMeta.BlockID.PartSetHeader.Total); i++ {
 		part := bs.LoadBlockPart(height, i)
-		// If the part is missing (e.g. since it has been deleted after we
-		// loaded the block meta) we consider the whole block to be missing.
+		// If the part is missing since the block has been pruned after we
+		// loaded the block meta, we consider the whole block to be missing.
+		// Otherwise, the block is corrupt.
 		if part == nil {
-			return nil
+			err := bs.missingPartError(height, i)
+			if errors.Is(err, ErrBlockNotFound) {
+				return nil
+			}
+			panic(err)
 		}
 		buf = append(buf, part.Bytes...)
 	}
@@ -266,6 +305,12 @@ func (bs *BlockStore) LoadSeenCommit(height int64) *types.Commit {
 
 // PruneBlocks removes block up to (but not including) a height. It returns number of blocks pruned.
 func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
+	return bs.pruneBlocks(height, nil)
+}
+
+// pruneBlocks implements PruneBlocks and records the range of deleted keys in
+// deleted, if not nil.
+func (bs *BlockStore) pruneBlocks(height int64, deleted *keyRange) (uint64, error) {
 	if height <= 0 {
 		return 0, fmt.Errorf("height must be greater than 0")
 	}
@@ -300,32 +345,12 @@ func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
 	}
 
 	for h := base; h < height; h++ {
-		meta := bs.LoadBlockMeta(h)
-		if meta == nil { // assume already deleted
-			continue
-		}
-		block := bs.LoadBlock(h)
-		for _, tx := range block.Txs {
-			if err := batch.Delete(calcTxHashKey(tx.Hash())); err != nil {
-				return 0, err
-			}
-		}
-		if err := batch.Delete(calcBlockMetaKey(h)); err != nil {
-			return 0, err
-		}
-		if err := batch.Delete(calcBlockHashKey(meta.BlockID.Hash)); err != nil {
-			return 0, err
-		}
-		if err := batch.Delete(calcBlockCommitKey(h)); err != nil {
-			return 0, err
-		}
-		if err := batch.Delete(calcSeenCommitKey(h)); err != nil {
+		ok, err := bs.deleteHeight(batch, h, deleted)
+		if err != nil {
 			return 0, err
 		}
-		for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
-			if err := batch.Delete(calcBlockPartKey(h, p)); err != nil {
-				return 0, err
-			}
+		if !ok { // assume already deleted
+			continue
 		}
 		pruned++
 
@@ -347,6 +372,193 @@ func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
 	return pruned, nil
 }
 
+// deleteHeight adds the deletion of all data stored for the block at the
+// given height to the batch and records the deleted keys in deleted, if not
+// nil. It returns false if no block meta was found for the height.
+func (bs *BlockStore) deleteHeight(batch dbm.Batch, h int64, deleted *keyRange) (bool, error) {
+	meta := bs.LoadBlockMeta(h)
+	if meta == nil {
+		return false, nil
+	}
+	keys := [][]byte{
+		calcBlockMetaKey(h),
+		calcBlockHashKey(meta.BlockID.Hash),
+		calcBlockCommitKey(h),
+		calcSeenCommitKey(h),
+	}
+	// the txs of a corrupt block are unknown, but its other keys are deleted
+	if parts, err := bs.readBlockParts(h, int(meta.BlockID.PartSetHeader.Total)); err == nil {
+		if block, err := decodeBlockParts(parts); err == nil {
+			for _, tx := range block.Txs {
+				keys = append(keys, calcTxHashKey(tx.Hash()))
+			}
+		}
+	}
+	for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
+		keys = append(keys, calcBlockPartKey(h, p))
+	}
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return false, err
+		}
+		if deleted != nil {
+			deleted.add(key)
+		}
+	}
+	return true, nil
+}
+
+// TruncateBlocks removes all blocks above the given height, which becomes the
+// latest height of the store, and lowers the retain height to it if needed.
+// It is used to roll back multiple heights and must not be called while the
+// store is in use by a running node. It returns the number of blocks removed.
+func (bs *BlockStore) TruncateBlocks(height int64) (uint64, error) {
+	bs.mtx.RLock()
+	base, latest := bs.base, bs.height
+	bs.mtx.RUnlock()
+	if height < base {
+		return 0, fmt.Errorf("cannot truncate to height %v, it is lower than base height %v", height, base)
+	}
+	if height >= latest {
+		return 0, nil
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+	removed := uint64(0)
+	for h := latest; h > height; h-- {
+		ok, err := bs.deleteHeight(batch, h, nil)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			removed++
+		}
+	}
+	// the commit of the new latest block was stored with the block above it
+	if err := batch.Delete(calcBlockCommitKey(height)); err != nil {
+		return 0, err
+	}
+
+	// As with pruning, update the height first, so that no one tries to
+	// access the removed blocks if the batch isn't written atomically.
+	bs.mtx.Lock()
+	bs.height = height
+	if bs.retainHeight > height {
+		bs.retainHeight = height
+		if err := batch.Set(retainHeightKey, int64ToBytes(height)); err != nil {
+			bs.mtx.Unlock()
+			return 0, err
+		}
+	}
+	bs.mtx.Unlock()
+	bs.saveState()
+
+	if err := batch.WriteSync(); err != nil {
+		return 0, fmt.Errorf("failed to truncate to height %v: %w", height, err)
+	}
+	return removed, nil
+}
+
+// DeletePartialBlock deletes the keys of a partially written block above the
+// latest height of the store. Older versions didn't save blocks atomically and
+// could leave such keys behind when crashing while saving a block, which
+// confuses the load paths once the height is saved again. It returns the
+// height of the deleted block, or 0 if there was none. It must be called
+// before the store is used, e.g. on startup.
+func (bs *BlockStore) DeletePartialBlock() (int64, error) {
+	latest := bs.Height()
+	if latest == 0 {
+		// the first height of an empty store is unknown
+		return 0, nil
+	}
+	height := latest + 1
+
+	var keys [][]byte
+	for _, key := range [][]byte{
+		calcBlockMetaKey(height),
+		calcBlockCommitKey(latest), // saved along with the block above it
+		calcSeenCommitKey(height),
+	} {
+		has, err := bs.db.Has(key)
+		if err != nil {
+			return 0, err
+		}
+		if has {
+			keys = append(keys, key)
+		}
+	}
+	// the hash key can only be found through the block meta
+	meta, err := bs.loadBlockMeta(height)
+	switch {
+	case err == nil:
+		keys = append(keys, calcBlockHashKey(meta.BlockID.Hash))
+	case !errors.Is(err, ErrBlockNotFound):
+		return 0, fmt.Errorf("failed to load block meta at height %d: %w", height, err)
+	}
+	// the number of parts written is unknown without the block meta
+	it, err := dbm.IteratePrefix(bs.db, []byte(fmt.Sprintf("P:%v:", height)))
+	if err != nil {
+		return 0, err
+	}
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+	}
+	if err := it.Error(); err != nil {
+		it.Close()
+		return 0, err
+	}
+	if err := it.Close(); err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	if err := batch.WriteSync(); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
+
+// RetainHeight returns the retain height last recorded with SetRetainHeight,
+// or 0 if none was recorded.
+func (bs *BlockStore) RetainHeight() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.retainHeight
+}
+
+// SetRetainHeight records the height below which blocks may be pruned. It
+// does not delete anything itself: the blocks are removed in the background
+// by the Pruner. The retain height is persisted so that an interrupted prune
+// resumes after a restart. Lowering the retain height is a no-op.
+func (bs *BlockStore) SetRetainHeight(height int64) error {
+	if height <= 0 {
+		return fmt.Errorf("height must be greater than 0")
+	}
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+	if height > bs.height {
+		return fmt.Errorf("cannot retain beyond the latest height %v", bs.height)
+	}
+	if height <= bs.retainHeight {
+		return nil
+	}
+	if err := bs.db.SetSync(retainHeightKey, int64ToBytes(height)); err != nil {
+		return err
+	}
+	bs.retainHeight = height
+	return nil
+}
+
 // SaveBlock persists the given block, blockParts, and seenCommit to the underlying db.
 // blockParts: Must be parts of the block
 // seenCommit: The +2/3 precommits that were seen which committed at height.
@@ -369,13 +581,16 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 		panic("BlockStore can only save complete block part sets")
 	}
 
-	// Save block parts. This must be done before the block meta, since callers
-	// typically load the block meta first as an indication that the block exists
-	// and then go on to load block parts - we must make sure the block is
-	// complete as soon as the block meta is written.
+	// All keys of the block are written in a single batch, along with the new
+	// BlockStoreState descriptor, so that a crash can't leave a partially
+	// written block behind.
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+
+	// Save block parts
 	for i := 0; i < int(blockParts.Total()); i++ {
 		part := blockParts.GetPart(i)
-		bs.saveBlockPart(height, i, part)
+		bs.saveBlockPart(batch, height, i, part)
 	}
 
 	// Save block meta
@@ -385,17 +600,17 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 		panic("nil blockmeta")
 	}
 	metaBytes := mustEncode(pbm)
-	if err := bs.db.Set(calcBlockMetaKey(height), metaBytes); err != nil {
+	if err := batch.Set(calcBlockMetaKey(height), metaBytes); err != nil {
 		panic(err)
 	}
-	if err := bs.db.Set(calcBlockHashKey(hash), []byte(fmt.Sprintf("%d", height))); err != nil {
+	if err := batch.Set(calcBlockHashKey(hash), []byte(fmt.Sprintf("%d", height))); err != nil {
 		panic(err)
 	}
 
 	// Save block commit (duplicate and separate from the Block)
 	pbc := block.LastCommit.ToProto()
 	blockCommitBytes := mustEncode(pbc)
-	if err := bs.db.Set(calcBlockCommitKey(height-1), blockCommitBytes); err != nil {
+	if err := batch.Set(calcBlockCommitKey(height-1), blockCommitBytes); err != nil {
 		panic(err)
 	}
 
@@ -403,33 +618,58 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	// NOTE: we can delete this at a later height
 	pbsc := seenCommit.ToProto()
 	seenCommitBytes := mustEncode(pbsc)
-	if err := bs.db.Set(calcSeenCommitKey(height), seenCommitBytes); err != nil {
+	if err := batch.Set(calcSeenCommitKey(height), seenCommitBytes); err != nil {
 		panic(err)
 	}
 
-	// Done!
-	bs.mtx.Lock()
-	bs.height = height
-	if bs.base == 0 {
-		bs.base = height
+	// Save new BlockStoreState descriptor and flush the database
+	if err := bs.saveHeight(batch, height); err != nil {
+		panic(err)
 	}
-	bs.mtx.Unlock()
 
-	// Save new BlockStoreState descriptor. This also flushes the database.
-	bs.saveState()
+	select {
+	case bs.saved <- struct{}{}:
+	default:
+	}
 }
 
-func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
+func (bs *BlockStore) saveBlockPart(batch dbm.Batch, height int64, index int, part *types.Part) {
 	pbp, err := part.ToProto()
 	if err != nil {
 		panic(fmt.Errorf("unable to make part into proto: %w", err))
 	}
 	partBytes := mustEncode(pbp)
-	if err := bs.db.Set(calcBlockPartKey(height, index), partBytes); err != nil {
+	if err := batch.Set(calcBlockPartKey(height, index), partBytes); err != nil {
 		panic(err)
 	}
 }
 
+// saveHeight adds the BlockStoreState descriptor with the given latest height
+// to a batch holding the keys of the block at that height, and writes the
+// batch. The height is only visible to readers once the batch was written.
+func (bs *BlockStore) saveHeight(batch dbm.Batch, height int64) error {
+	bs.mtx.RLock()
+	bss := cmtstore.BlockStoreState{Base: bs.base, Height: height}
+	bs.mtx.RUnlock()
+	if bss.Base == 0 {
+		bss.Base = height
+	}
+	if err := batch.Set(blockStoreKey, mustEncode(&bss)); err != nil {
+		return err
+	}
+	if err := batch.WriteSync(); err != nil {
+		return err
+	}
+
+	bs.mtx.Lock()
+	bs.height = height
+	if bs.base == 0 {
+		bs.base = height
+	}
+	bs.mtx.Unlock()
+	return nil
+}
+
 func (bs *BlockStore) saveState() {
 	bs.mtx.RLock()
 	bss := cmtstore.BlockStoreState{
@@ -520,7 +760,10 @@ func calcTxHashKey(hash []byte) []byte {
 
 //-----------------------------------------------------------------------------
 
-var blockStoreKey = []byte("blockStore")
+var (
+	blockStoreKey   = []byte("blockStore")
+	retainHeightKey = []byte("retainHeight")
+)
 
 // SaveBlockStoreState persists the blockStore state to the database.
 func SaveBlockStoreState(bsj *cmtstore.BlockStoreState, db dbm.DB) {
@@ -560,6 +803,26 @@ func LoadBlockStoreState(db dbm.DB) cmtstore.BlockStoreState {
 	return bsj
 }
 
+func loadRetainHeight(db dbm.DB) int64 {
+	bz, err := db.Get(retainHeightKey)
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return 0
+	}
+	if len(bz) != 8 {
+		panic(fmt.Sprintf("Could not unmarshal retain height: %X", bz))
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+func int64ToBytes(i int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(i)) //nolint:gosec
+	return bz
+}
+
 // LoadTxInfo loads the TxInfo from disk given its hash.
 func (bs *BlockStore) LoadTxInfo(txHash []byte) *cmtstore.TxInfo {
 	bz, err := bs.db.Get(calcTxHashKey(txHash))
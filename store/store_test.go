@@ -3,6 +3,7 @@ package store
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"runtime/debug"
@@ -70,6 +71,30 @@ func makeStateAndBlockStore(logger log.Logger) (sm.State, *BlockStore, cleanupFu
 	return state, NewBlockStore(blockDB), func() { os.RemoveAll(config.RootDir) }
 }
 
+// saveTestBlocks saves to bs the blocks from height 1 to height made by
+// makeBlock, each along with a seen commit for it at its time. makeBlock is
+// given the state as of the previous block, and the seen commit of that block.
+func saveTestBlocks(
+	bs *BlockStore,
+	state sm.State,
+	height int64,
+	makeBlock func(state sm.State, height int64, lastCommit *types.Commit) *types.Block,
+) {
+	lastCommit := new(types.Commit)
+	for h := int64(1); h <= height; h++ {
+		block := makeBlock(state, h, lastCommit)
+		partSet := block.MakePartSet(types.BlockPartSizeBytes)
+		blockID := types.BlockID{Hash: block.Hash(), PartSetHeader: partSet.Header()}
+		seenCommit := makeTestCommit(h, block.Time)
+		seenCommit.BlockID = blockID
+		bs.SaveBlock(block, partSet, seenCommit)
+
+		state.LastBlockID = blockID
+		state.LastBlockHeight = h
+		lastCommit = seenCommit
+	}
+}
+
 func TestLoadBlockStoreState(t *testing.T) {
 
 	type blockStoreTest struct {
@@ -661,6 +686,260 @@ func TestPruneBlocksPrunesTxs(t *testing.T) {
 	}
 }
 
+func TestTruncateBlocks(t *testing.T) {
+	db := dbm.NewMemDB()
+	bs := makeBlockStoreWithBlocks(t, db, 10)
+	_, err := bs.PruneBlocks(3)
+	require.NoError(t, err)
+	require.NoError(t, bs.SetRetainHeight(8))
+
+	// truncating to the latest height or above removes nothing
+	removed, err := bs.TruncateBlocks(10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, removed)
+
+	// blocks below the base can't be restored
+	_, err = bs.TruncateBlocks(2)
+	require.Error(t, err)
+	assert.EqualValues(t, 10, bs.Height())
+
+	removed, err = bs.TruncateBlocks(6)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, removed)
+	assert.EqualValues(t, 3, bs.Base())
+	assert.EqualValues(t, 6, bs.Height())
+	assert.EqualValues(t, 6, bs.RetainHeight())
+	assert.NotNil(t, bs.LoadBlock(6))
+	for h := int64(7); h <= 10; h++ {
+		assert.Nil(t, bs.LoadBlock(h))
+		assert.Nil(t, bs.LoadBlockMeta(h))
+		assert.Nil(t, bs.LoadBlockCommit(h-1))
+		assert.Nil(t, bs.LoadSeenCommit(h))
+	}
+	assert.NotNil(t, bs.LoadSeenCommit(6))
+
+	// the new heights are persisted
+	bs = NewBlockStore(db)
+	assert.EqualValues(t, 3, bs.Base())
+	assert.EqualValues(t, 6, bs.Height())
+	assert.EqualValues(t, 6, bs.RetainHeight())
+}
+
+var errCrash = errors.New("simulated crash")
+
+// crashingDB wraps a database and fails all writes after a number of writes,
+// simulating a crash. Writes to a batch count individually, so that a batch
+// can fail before it is written.
+type crashingDB struct {
+	dbm.DB
+	writes int
+}
+
+func (db *crashingDB) write() error {
+	if db.writes == 0 {
+		return errCrash
+	}
+	db.writes--
+	return nil
+}
+
+func (db *crashingDB) Set(key, value []byte) error {
+	if err := db.write(); err != nil {
+		return err
+	}
+	return db.DB.Set(key, value)
+}
+
+func (db *crashingDB) SetSync(key, value []byte) error {
+	if err := db.write(); err != nil {
+		return err
+	}
+	return db.DB.SetSync(key, value)
+}
+
+func (db *crashingDB) Delete(key []byte) error {
+	if err := db.write(); err != nil {
+		return err
+	}
+	return db.DB.Delete(key)
+}
+
+func (db *crashingDB) DeleteSync(key []byte) error {
+	if err := db.write(); err != nil {
+		return err
+	}
+	return db.DB.DeleteSync(key)
+}
+
+func (db *crashingDB) NewBatch() dbm.Batch {
+	return &crashingBatch{Batch: db.DB.NewBatch(), db: db}
+}
+
+type crashingBatch struct {
+	dbm.Batch
+	db *crashingDB
+}
+
+func (b *crashingBatch) Set(key, value []byte) error {
+	if err := b.db.write(); err != nil {
+		return err
+	}
+	return b.Batch.Set(key, value)
+}
+
+func (b *crashingBatch) Delete(key []byte) error {
+	if err := b.db.write(); err != nil {
+		return err
+	}
+	return b.Batch.Delete(key)
+}
+
+func (b *crashingBatch) Write() error {
+	if err := b.db.write(); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
+
+func (b *crashingBatch) WriteSync() error {
+	if err := b.db.write(); err != nil {
+		return err
+	}
+	return b.Batch.WriteSync()
+}
+
+// makeBlockStoreForCrash returns a block store with 5 blocks, along with the
+// block and seen commit at height 6, which spans multiple parts.
+func makeBlockStoreForCrash(t *testing.T) (dbm.DB, *types.Block, *types.PartSet, *types.Commit) {
+	state, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	t.Cleanup(cleanup)
+	makeBlock := func(height int64) (*types.Block, *types.PartSet) {
+		txs := []types.Tx{cmtrand.Bytes(int(types.BlockPartSizeBytes))}
+		return state.MakeBlock(height, types.Data{Txs: txs}, makeTestCommit(height-1, cmttime.Now()), nil,
+			state.Validators.GetProposer().Address)
+	}
+
+	db := dbm.NewMemDB()
+	bs := NewBlockStore(db)
+	for h := int64(1); h <= 5; h++ {
+		block, partSet := makeBlock(h)
+		bs.SaveBlock(block, partSet, makeTestCommit(h, cmttime.Now()))
+	}
+	block, partSet := makeBlock(6)
+	require.Greater(t, partSet.Total(), uint32(1))
+	return db, block, partSet, makeTestCommit(6, cmttime.Now())
+}
+
+func TestSaveBlockCrash(t *testing.T) {
+	db, block, partSet, seenCommit := makeBlockStoreForCrash(t)
+
+	// crash at every write of SaveBlock until it succeeds
+	writes := 0
+	for ; ; writes++ {
+		bs := NewBlockStore(&crashingDB{DB: db, writes: writes})
+		_, _, panicErr := doFn(func() (interface{}, error) {
+			bs.SaveBlock(block, partSet, seenCommit)
+			return nil, nil
+		})
+		if panicErr == nil {
+			break
+		}
+		require.ErrorIs(t, panicErr, errCrash)
+		assert.EqualValues(t, 5, bs.Height(), "writes=%d", writes)
+
+		// after a restart, nothing of the block is left behind
+		bs = NewBlockStore(db)
+		require.EqualValues(t, 5, bs.Height(), "writes=%d", writes)
+		partialHeight, err := bs.DeletePartialBlock()
+		require.NoError(t, err)
+		require.Zero(t, partialHeight, "writes=%d", writes)
+		require.Nil(t, bs.LoadBlockCommit(5), "writes=%d", writes)
+	}
+	require.Greater(t, writes, int(partSet.Total()))
+
+	bs := NewBlockStore(db)
+	assert.EqualValues(t, 1, bs.Base())
+	assert.EqualValues(t, 6, bs.Height())
+	assert.Equal(t, block.Hash(), bs.LoadBlock(6).Hash())
+	assert.True(t, bs.Verify(0, 0, 1).OK())
+}
+
+func TestDeletePartialBlock(t *testing.T) {
+	db, block, partSet, seenCommit := makeBlockStoreForCrash(t)
+
+	// the keys of the block in the order older versions wrote them, without a
+	// batch, before writing the block store state
+	type entry struct{ key, value []byte }
+	var entries []entry
+	for i := 0; i < int(partSet.Total()); i++ {
+		pbp, err := partSet.GetPart(i).ToProto()
+		require.NoError(t, err)
+		entries = append(entries, entry{calcBlockPartKey(6, i), mustEncode(pbp)})
+	}
+	entries = append(entries,
+		entry{calcBlockMetaKey(6), mustEncode(types.NewBlockMeta(block, partSet).ToProto())},
+		entry{calcBlockHashKey(block.Hash()), []byte("6")},
+		entry{calcBlockCommitKey(5), mustEncode(block.LastCommit.ToProto())},
+		entry{calcSeenCommitKey(6), mustEncode(seenCommit.ToProto())},
+	)
+
+	for written := 0; written < len(entries); written++ {
+		for _, e := range entries[:written] {
+			require.NoError(t, db.Set(e.key, e.value))
+		}
+
+		bs := NewBlockStore(db)
+		require.EqualValues(t, 5, bs.Height())
+		partialHeight, err := bs.DeletePartialBlock()
+		require.NoError(t, err)
+		if written == 0 {
+			assert.Zero(t, partialHeight)
+		} else {
+			assert.EqualValues(t, 6, partialHeight, "written=%d", written)
+		}
+		for _, e := range entries {
+			has, err := db.Has(e.key)
+			require.NoError(t, err)
+			require.False(t, has, "written=%d, key=%s", written, e.key)
+		}
+		require.True(t, bs.Verify(0, 0, 1).OK(), "written=%d", written)
+		require.NotNil(t, bs.LoadSeenCommit(5))
+	}
+
+	// the height can be saved again
+	bs := NewBlockStore(db)
+	bs.SaveBlock(block, partSet, seenCommit)
+	assert.Equal(t, block.Hash(), bs.LoadBlock(6).Hash())
+	assert.True(t, bs.Verify(0, 0, 1).OK())
+
+	// an empty store has nothing to delete
+	partialHeight, err := NewBlockStore(dbm.NewMemDB()).DeletePartialBlock()
+	require.NoError(t, err)
+	assert.Zero(t, partialHeight)
+}
+
+func TestLoadBlockMissingPart(t *testing.T) {
+	db := dbm.NewMemDB()
+	bs := makeBlockStoreWithBlocks(t, db, 3)
+	require.NoError(t, db.Delete(calcBlockPartKey(2, 1)))
+
+	// a missing part of an existing block is an error
+	_, _, panicErr := doFn(func() (interface{}, error) {
+		return bs.LoadBlock(2), nil
+	})
+	require.ErrorAs(t, panicErr, &ErrMissingBlockPart{})
+	assert.Contains(t, panicErr.Error(), "part 1 of the block at height 2 is missing")
+	assert.ErrorAs(t, bs.LoadBlockRange(2, 2)[0].Err, &ErrMissingBlockPart{})
+
+	// the corrupt block can still be pruned, after which it is just missing
+	pruned, err := bs.PruneBlocks(3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, pruned)
+	assert.Nil(t, bs.LoadBlock(2))
+	assert.ErrorIs(t, bs.missingPartError(2, 1), ErrBlockNotFound)
+	assert.NotNil(t, bs.LoadBlock(3))
+}
+
 func TestLoadBlockMeta(t *testing.T) {
 	bs, db := freshBlockStore()
 	height := int64(10)
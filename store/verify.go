@@ -0,0 +1,180 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/gogo/protobuf/proto"
+
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// ErrCorruptBlock is reported for a height of the block store whose data
+// failed verification, with the reason in Err.
+type ErrCorruptBlock struct {
+	Height int64
+	Err    error
+}
+
+func (e ErrCorruptBlock) Error() string {
+	return fmt.Sprintf("block at height %d is corrupt: %v", e.Height, e.Err)
+}
+
+func (e ErrCorruptBlock) Unwrap() error {
+	return e.Err
+}
+
+// VerificationResult is the outcome of verifying a range of the block store.
+type VerificationResult struct {
+	// From and To are the first and last height of the verified range, after
+	// clamping the requested range to the base and height of the store.
+	From int64
+	To   int64
+	// Checked is the number of heights which were verified.
+	Checked int64
+	// Corrupt lists the heights which failed verification, in ascending
+	// order.
+	Corrupt []ErrCorruptBlock
+}
+
+// OK returns true if no corrupt heights were found.
+func (r VerificationResult) OK() bool {
+	return len(r.Corrupt) == 0
+}
+
+// CorruptHeights returns the heights which failed verification.
+func (r VerificationResult) CorruptHeights() []int64 {
+	heights := make([]int64, len(r.Corrupt))
+	for i, c := range r.Corrupt {
+		heights[i] = c.Height
+	}
+	return heights
+}
+
+// Verify checks the integrity of the blocks stored between the heights from
+// and to (inclusive), which are clamped to the base and height of the store.
+// Only every step-th height is checked, starting at from; the last height of
+// the range is always checked. A step of 1 verifies the full range.
+//
+// For each checked height, the block meta and all block parts must be
+// present and decodable, the parts must match the part set hash of the block
+// ID, and the assembled block must match both the header stored in the meta
+// and the block hash. Unlike the Load methods, Verify never panics on
+// corrupt data; corrupt heights are reported in the result instead.
+func (bs *BlockStore) Verify(from, to, step int64) VerificationResult {
+	base, height := bs.Base(), bs.Height()
+	if from < base {
+		from = base
+	}
+	if to <= 0 || to > height {
+		to = height
+	}
+	if step < 1 {
+		step = 1
+	}
+	result := VerificationResult{From: from, To: to}
+	if base == 0 || from > to {
+		return result
+	}
+
+	for h := from; h <= to; h = nextVerifiedHeight(h, to, step) {
+		result.Checked++
+		if err := bs.verifyBlock(h); err != nil {
+			result.Corrupt = append(result.Corrupt, ErrCorruptBlock{Height: h, Err: err})
+		}
+	}
+	return result
+}
+
+// nextVerifiedHeight returns the height checked after height, making sure
+// the last height of the range is always checked.
+func nextVerifiedHeight(height, to, step int64) int64 {
+	if height < to && height+step > to {
+		return to
+	}
+	return height + step
+}
+
+func (bs *BlockStore) verifyBlock(height int64) (err error) {
+	// the conversions from proto validate the decoded data, but corrupt data
+	// might still make them panic
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while verifying block: %v", r)
+		}
+	}()
+
+	meta, err := bs.loadBlockMeta(height)
+	if err != nil {
+		return fmt.Errorf("failed to load block meta: %w", err)
+	}
+	if meta.Header.Height != height {
+		return fmt.Errorf("block meta has height %d", meta.Header.Height)
+	}
+	if hash := meta.Header.Hash(); !bytes.Equal(hash, meta.BlockID.Hash) {
+		return fmt.Errorf("header hash %X doesn't match block ID hash %X", hash, meta.BlockID.Hash)
+	}
+
+	var parts [][]byte
+	for i := 0; i < int(meta.BlockID.PartSetHeader.Total); i++ {
+		bz, err := bs.db.Get(calcBlockPartKey(height, i))
+		if err != nil {
+			return fmt.Errorf("failed to read block part %d: %w", i, err)
+		}
+		if len(bz) == 0 {
+			return fmt.Errorf("block part %d not found", i)
+		}
+		parts = append(parts, bz)
+	}
+	_, err = assembleBlock(meta, parts)
+	return err
+}
+
+// assembleBlock decodes the block from its encoded parts, checking that the
+// parts match the part set hash of the block ID, and that the block matches
+// the block hash.
+func assembleBlock(meta *types.BlockMeta, parts [][]byte) (*types.Block, error) {
+	if len(parts) != int(meta.BlockID.PartSetHeader.Total) {
+		return nil, fmt.Errorf("expected %d block parts, got %d", meta.BlockID.PartSetHeader.Total, len(parts))
+	}
+	partSet := types.NewPartSetFromHeader(meta.BlockID.PartSetHeader)
+	for i, bz := range parts {
+		pbpart := new(cmtproto.Part)
+		if err := proto.Unmarshal(bz, pbpart); err != nil {
+			return nil, fmt.Errorf("unmarshal block part %d: %w", i, err)
+		}
+		part, err := types.PartFromProto(pbpart)
+		if err != nil {
+			return nil, fmt.Errorf("error from proto block part %d: %w", i, err)
+		}
+		if part.Index != uint32(i) {
+			return nil, fmt.Errorf("block part %d has index %d", i, part.Index)
+		}
+		// AddPart verifies the proof of the part against the part set hash
+		if _, err := partSet.AddPart(part); err != nil {
+			return nil, fmt.Errorf("block part %d doesn't match the part set hash: %w", i, err)
+		}
+	}
+	if !partSet.IsComplete() {
+		return nil, fmt.Errorf("block parts are incomplete")
+	}
+
+	bz, err := io.ReadAll(partSet.GetReader())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block parts: %w", err)
+	}
+	pbb := new(cmtproto.Block)
+	if err := proto.Unmarshal(bz, pbb); err != nil {
+		return nil, fmt.Errorf("unmarshal to cmtproto.Block: %w", err)
+	}
+	block, err := types.BlockFromProto(pbb)
+	if err != nil {
+		return nil, fmt.Errorf("error from proto block: %w", err)
+	}
+	if hash := block.Hash(); !bytes.Equal(hash, meta.BlockID.Hash) {
+		return nil, fmt.Errorf("block hash %X doesn't match block ID hash %X", hash, meta.BlockID.Hash)
+	}
+	return block, nil
+}
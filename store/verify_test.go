@@ -0,0 +1,70 @@
+package store
+
+import (
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flipByte inverts the byte in the middle of the value stored at key.
+func flipByte(t *testing.T, db dbm.DB, key []byte) {
+	bz, err := db.Get(key)
+	require.NoError(t, err)
+	require.NotEmpty(t, bz)
+	bz = append([]byte{}, bz...)
+	bz[len(bz)/2] ^= 0xff
+	require.NoError(t, db.Set(key, bz))
+}
+
+func TestBlockStoreVerify(t *testing.T) {
+	db := dbm.NewMemDB()
+	bs := makeBlockStoreWithBlocks(t, db, 10)
+
+	result := bs.Verify(0, 0, 1)
+	assert.True(t, result.OK())
+	assert.EqualValues(t, 1, result.From)
+	assert.EqualValues(t, 10, result.To)
+	assert.EqualValues(t, 10, result.Checked)
+
+	// corrupt the data of a block part, the meta of a block and remove a part
+	flipByte(t, db, calcBlockPartKey(3, 1))
+	flipByte(t, db, calcBlockMetaKey(5))
+	require.NoError(t, db.Delete(calcBlockPartKey(8, 0)))
+
+	result = bs.Verify(0, 0, 1)
+	assert.False(t, result.OK())
+	assert.EqualValues(t, 10, result.Checked)
+	assert.Equal(t, []int64{3, 5, 8}, result.CorruptHeights())
+	assert.ErrorContains(t, result.Corrupt[0], "part 1")
+	assert.ErrorContains(t, result.Corrupt[2], "block part 0 not found")
+
+	// a sampled range always includes the last height
+	result = bs.Verify(2, 8, 3)
+	assert.EqualValues(t, 3, result.Checked)
+	assert.Equal(t, []int64{5, 8}, result.CorruptHeights())
+
+	// the range is clamped to the heights of the store
+	result = bs.Verify(9, 100, 1)
+	assert.EqualValues(t, 9, result.From)
+	assert.EqualValues(t, 10, result.To)
+	assert.True(t, result.OK())
+}
+
+func TestBlockStoreVerifyPruned(t *testing.T) {
+	db := dbm.NewMemDB()
+	bs := makeBlockStoreWithBlocks(t, db, 10)
+	_, err := bs.PruneBlocks(6)
+	require.NoError(t, err)
+
+	result := bs.Verify(1, 10, 1)
+	assert.True(t, result.OK())
+	assert.EqualValues(t, 6, result.From)
+	assert.EqualValues(t, 5, result.Checked)
+
+	// an empty store has nothing to verify
+	result = NewBlockStore(dbm.NewMemDB()).Verify(0, 0, 1)
+	assert.True(t, result.OK())
+	assert.Zero(t, result.Checked)
+}
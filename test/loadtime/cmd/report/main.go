@@ -11,6 +11,7 @@ import (
 
 	dbm "github.com/cometbft/cometbft-db"
 
+	cmtdb "github.com/tendermint/tendermint/libs/db"
 	"github.com/tendermint/tendermint/store"
 	"github.com/tendermint/tendermint/test/loadtime/report"
 )
@@ -42,7 +43,7 @@ func main() {
 		panic(err)
 	}
 	dbType := dbm.BackendType(*db)
-	db, err := dbm.NewDB("blockstore", dbType, d)
+	db, err := cmtdb.NewDB("blockstore", dbType, d)
 	if err != nil {
 		panic(err)
 	}
@@ -40,6 +40,10 @@ const (
 )
 
 // Block defines the atomic unit of a CometBFT blockchain.
+//
+// A block is treated as immutable once built: its hash and part set are
+// computed once and cached. Code mutating a block after that must call
+// InvalidateHashes.
 type Block struct {
 	mtx cmtsync.Mutex
 
@@ -47,6 +51,11 @@ type Block struct {
 	Data       `json:"data"`
 	Evidence   EvidenceData `json:"evidence"`
 	LastCommit *Commit      `json:"last_commit"`
+
+	// Volatile. Used as cache, guarded by mtx
+	hash     cmtbytes.HexBytes
+	partSet  *PartSet
+	partSize uint32
 }
 
 // ValidateBasic performs basic validation that doesn't involve state data. It
@@ -124,12 +133,34 @@ func (b *Block) Hash() cmtbytes.HexBytes {
 	if b.LastCommit == nil {
 		return nil
 	}
-	b.fillHeader()
-	return b.Header.Hash()
+	if b.hash == nil {
+		b.fillHeader()
+		// nil, and so not cached, while the header is incomplete
+		b.hash = b.Header.Hash()
+	}
+	return b.hash
+}
+
+// InvalidateHashes drops the cached hashes and part set of the block, and of
+// its evidence, so that they are computed again from its current content. It
+// must be called after mutating a block whose hash or part set may have been
+// computed. The header fields derived from the content, e.g. DataHash, are
+// part of the block and are not reset.
+func (b *Block) InvalidateHashes() {
+	if b == nil {
+		return
+	}
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.hash = nil
+	b.partSet, b.partSize = nil, 0
+	b.Evidence.hash, b.Evidence.byteSize = nil, 0
 }
 
 // MakePartSet returns a PartSet containing parts of a serialized block.
-// This is the form in which the block is gossipped to peers.
+// This is the form in which the block is gossipped to peers. The part set is
+// cached, so the callers must not modify it.
 // CONTRACT: partSize is greater than zero.
 func (b *Block) MakePartSet(partSize uint32) *PartSet {
 	if b == nil {
@@ -138,6 +169,13 @@ func (b *Block) MakePartSet(partSize uint32) *PartSet {
 	b.mtx.Lock()
 	defer b.mtx.Unlock()
 
+	if b.partSet == nil || b.partSize != partSize {
+		b.partSet, b.partSize = b.makePartSet(partSize), partSize
+	}
+	return b.partSet
+}
+
+func (b *Block) makePartSet(partSize uint32) *PartSet {
 	pbb, err := b.ToProto()
 	if err != nil {
 		panic(err)
@@ -163,6 +201,14 @@ func (b *Block) HashesTo(hash []byte) bool {
 
 // Size returns size of the block in bytes.
 func (b *Block) Size() int {
+	b.mtx.Lock()
+	partSet := b.partSet
+	b.mtx.Unlock()
+	if partSet != nil {
+		// the parts are the serialized block
+		return int(partSet.ByteSize())
+	}
+
 	pbb, err := b.ToProto()
 	if err != nil {
 		return 0
@@ -76,3 +76,14 @@ func (bm *BlockMeta) ValidateBasic() error {
 	}
 	return nil
 }
+
+// BlockRangeResult is the result of loading a single height of a range of
+// blocks from a block store. If the height could not be loaded, Err is set
+// and BlockMeta and Block may be nil. Block is nil if only the block metas
+// were requested.
+type BlockRangeResult struct {
+	Height    int64
+	BlockMeta *BlockMeta
+	Block     *Block
+	Err       error
+}
@@ -9,6 +9,7 @@ import (
 	"math"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -129,6 +130,138 @@ func TestBlockMakePartSetWithEvidence(t *testing.T) {
 	assert.EqualValues(t, 4, partSet.Total())
 }
 
+// TestBlockHashCache checks that the cached hash and part set of a block are
+// the ones of its content, along the mutation paths of a block: completing
+// the header after MakeBlock, and mutating it then invalidating the hashes.
+func TestBlockHashCache(t *testing.T) {
+	lastID := makeBlockIDRandom()
+	h := int64(3)
+	voteSet, valSet, vals := randVoteSet(h-1, 1, cmtproto.PrecommitType, 10, 1)
+	commit, err := MakeCommit(lastID, h-1, 1, voteSet, vals, time.Now())
+	require.NoError(t, err)
+	ev := NewMockDuplicateVoteEvidenceWithValidator(h, time.Now(), vals[0], "block-test-chain")
+
+	block := MakeBlock(h, makeData([]Tx{Tx("Hello World")}), commit, []Evidence{ev})
+	assert.Nil(t, block.Hash(), "the hash of an incomplete header")
+	block.ValidatorsHash = valSet.Hash()
+	block.ProposerAddress = valSet.GetProposer().Address
+	hash := block.Hash()
+	require.NotNil(t, hash, "the nil hash of the incomplete header was cached")
+	assertFreshHashes(t, block)
+
+	parts := block.MakePartSet(512)
+	assert.Same(t, parts, block.MakePartSet(512))
+	assert.NotEqual(t, parts.Total(), block.MakePartSet(1024).Total())
+
+	block.Time = block.Time.Add(time.Second)
+	block.InvalidateHashes()
+	assert.NotEqual(t, hash, block.Hash())
+	assertFreshHashes(t, block)
+
+	block.Evidence.Evidence = nil
+	block.InvalidateHashes()
+	block.EvidenceHash = block.Evidence.Hash()
+	assert.Equal(t, EvidenceList(nil).Hash(), []byte(block.EvidenceHash))
+	require.NoError(t, block.ValidateBasic())
+	assertFreshHashes(t, block)
+}
+
+// assertFreshHashes checks that the hash and part set of block are the ones of
+// a copy computing them from scratch.
+func assertFreshHashes(t *testing.T, block *Block) {
+	t.Helper()
+	pb, err := block.ToProto()
+	require.NoError(t, err)
+	fresh, err := BlockFromProto(pb)
+	require.NoError(t, err)
+	assert.Equal(t, fresh.Hash(), block.Hash())
+	assert.Equal(t, fresh.MakePartSet(512).Header(), block.MakePartSet(512).Header())
+	assert.Equal(t, fresh.Size(), block.Size())
+}
+
+func TestBlockHashConcurrent(t *testing.T) {
+	block := makeBenchmarkBlock(t, 1<<16)
+	want, wantParts := block.Hash(), block.MakePartSet(BlockPartSizeBytes).Header()
+	block.InvalidateHashes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				assert.Equal(t, want, block.Hash())
+				assert.Equal(t, wantParts, block.MakePartSet(BlockPartSizeBytes).Header())
+				block.InvalidateHashes()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// makeBenchmarkBlock returns a complete block of about size bytes of txs.
+func makeBenchmarkBlock(t testing.TB, size int) *Block {
+	const txSize = 1024
+	lastID := makeBlockIDRandom()
+	h := int64(3)
+	voteSet, valSet, vals := randVoteSet(h-1, 1, cmtproto.PrecommitType, 10, 1)
+	commit, err := MakeCommit(lastID, h-1, 1, voteSet, vals, time.Now())
+	require.NoError(t, err)
+
+	txs := make([]Tx, size/txSize)
+	for i := range txs {
+		txs[i] = cmtrand.Bytes(txSize)
+	}
+	block := MakeBlock(h, makeData(txs), commit, nil)
+	block.ValidatorsHash = valSet.Hash()
+	return block
+}
+
+// BenchmarkBlockHashes2MB measures the hashing and the part set construction
+// of a 2MB block along its flow from the proposer to the block store and the
+// RPC, with the hashes cached, and invalidated before each use as they were
+// before being cached.
+func BenchmarkBlockHashes2MB(b *testing.B) {
+	block := makeBenchmarkBlock(b, 2<<20)
+	flow := func(invalidate func()) {
+		// proposing: state.MakeBlock returns the part set of the proposal
+		invalidate()
+		parts := block.MakePartSet(BlockPartSizeBytes)
+		invalidate()
+		blockID := BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+		// consensus checks the proposal block against the block ID when
+		// validating it, prevoting, precommitting and committing it
+		for i := 0; i < 4; i++ {
+			invalidate()
+			if !block.HashesTo(blockID.Hash) {
+				b.Fatal("wrong block hash")
+			}
+		}
+		// storing it
+		invalidate()
+		_ = NewBlockMeta(block, parts)
+		// the RPC checking the part set of the block
+		invalidate()
+		if !block.MakePartSet(BlockPartSizeBytes).HasHeader(blockID.PartSetHeader) {
+			b.Fatal("wrong part set")
+		}
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			block.InvalidateHashes()
+			flow(func() {})
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			flow(block.InvalidateHashes)
+		}
+	})
+}
+
 func TestBlockHashesTo(t *testing.T) {
 	assert.False(t, (*Block)(nil).HashesTo(nil))
 
@@ -0,0 +1,34 @@
+package types
+
+import (
+	"time"
+
+	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
+)
+
+// AppHashMismatchReport is the diagnostic bundle collected when the app hash
+// computed by the application diverges from the one in a committed block,
+// before the node halts.
+type AppHashMismatchReport struct {
+	Time time.Time `json:"time"`
+	// Header is the header of the committed block, whose app hash results
+	// from executing the previous block.
+	Header          Header            `json:"header"`
+	ExpectedAppHash cmtbytes.HexBytes `json:"expected_app_hash"` // in Header
+	ActualAppHash   cmtbytes.HexBytes `json:"actual_app_hash"`   // computed by the application
+	// LastCommit is the commit of the previous block, in the committed block.
+	LastCommit *Commit `json:"last_commit"`
+	// TxResults summarizes the responses to the txs of the previous block.
+	// TxResultsError is set instead if they aren't available.
+	TxResults      []TxResultSummary `json:"tx_results"`
+	TxResultsError string            `json:"tx_results_error,omitempty"`
+}
+
+// TxResultSummary summarizes the response of the application to a tx.
+type TxResultSummary struct {
+	Code      uint32 `json:"code"`
+	Codespace string `json:"codespace,omitempty"`
+	GasWanted int64  `json:"gas_wanted"`
+	GasUsed   int64  `json:"gas_used"`
+	Events    int    `json:"events"`
+}
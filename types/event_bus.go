@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/heartbeat"
 	"github.com/tendermint/tendermint/libs/log"
 	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	"github.com/tendermint/tendermint/libs/service"
@@ -35,15 +36,16 @@ type EventBus struct {
 	pubsub *cmtpubsub.Server
 }
 
-// NewEventBus returns a new event bus.
-func NewEventBus() *EventBus {
-	return NewEventBusWithBufferCapacity(defaultCapacity)
+// NewEventBus returns a new event bus. The options configure the underlying
+// pubsub server.
+func NewEventBus(options ...cmtpubsub.Option) *EventBus {
+	return NewEventBusWithBufferCapacity(defaultCapacity, options...)
 }
 
 // NewEventBusWithBufferCapacity returns a new event bus with the given buffer capacity.
-func NewEventBusWithBufferCapacity(cap int) *EventBus {
+func NewEventBusWithBufferCapacity(cap int, options ...cmtpubsub.Option) *EventBus {
 	// capacity could be exposed later if needed
-	pubsub := cmtpubsub.NewServer(cmtpubsub.BufferCapacity(cap))
+	pubsub := cmtpubsub.NewServer(append([]cmtpubsub.Option{cmtpubsub.BufferCapacity(cap)}, options...)...)
 	b := &EventBus{pubsub: pubsub}
 	b.BaseService = *service.NewBaseService(nil, "EventBus", b)
 	return b
@@ -64,6 +66,11 @@ func (b *EventBus) OnStop() {
 	}
 }
 
+// Heartbeat returns the heartbeat of the goroutine delivering the events.
+func (b *EventBus) Heartbeat() *heartbeat.Heartbeat {
+	return b.pubsub.Heartbeat()
+}
+
 func (b *EventBus) NumClients() int {
 	return b.pubsub.NumClients()
 }
@@ -91,6 +98,18 @@ func (b *EventBus) SubscribeUnbuffered(
 	return b.pubsub.SubscribeUnbuffered(ctx, subscriber, query)
 }
 
+// SubscribeBounded subscribes with a buffer of cfg.Capacity events, handling
+// the events published while it is full as set by cfg.Overflow. See
+// cmtpubsub.OverflowPolicy.
+func (b *EventBus) SubscribeBounded(
+	ctx context.Context,
+	subscriber string,
+	query cmtpubsub.Query,
+	cfg cmtpubsub.BufferConfig,
+) (Subscription, error) {
+	return b.pubsub.SubscribeBounded(ctx, subscriber, query, cfg)
+}
+
 func (b *EventBus) Unsubscribe(ctx context.Context, subscriber string, query cmtpubsub.Query) error {
 	return b.pubsub.Unsubscribe(ctx, subscriber, query)
 }
@@ -191,6 +210,19 @@ func (b *EventBus) PublishEventTx(data EventDataTx) error {
 	return b.pubsub.PublishWithEvents(ctx, data, events)
 }
 
+// PublishEventTxExpired publishes the expiry of a tx. Note it will add the
+// predefined keys EventTypeKey and TxHashKey.
+func (b *EventBus) PublishEventTxExpired(data EventDataTxExpired) error {
+	// no explicit deadline for publishing events
+	ctx := context.Background()
+
+	events := map[string][]string{
+		EventTypeKey: {EventTxExpired},
+		TxHashKey:    {data.Hash.String()},
+	}
+	return b.pubsub.PublishWithEvents(ctx, data, events)
+}
+
 func (b *EventBus) PublishEventNewRoundStep(data EventDataRoundState) error {
 	return b.Publish(EventNewRoundStep, data)
 }
@@ -314,3 +346,7 @@ func (NopEventBus) PublishEventLock(data EventDataRoundState) error {
 func (NopEventBus) PublishEventValidatorSetUpdates(data EventDataValidatorSetUpdates) error {
 	return nil
 }
+
+func (NopEventBus) PublishEventTxExpired(data EventDataTxExpired) error {
+	return nil
+}
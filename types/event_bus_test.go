@@ -65,6 +65,31 @@ func TestEventBusPublishEventTx(t *testing.T) {
 	}
 }
 
+func TestEventBusPublishEventTxExpired(t *testing.T) {
+	eventBus := NewEventBus()
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	tx := Tx("foo")
+	query := fmt.Sprintf("tm.event='TxExpired' AND tx.hash='%X'", tx.Hash())
+	sub, err := eventBus.Subscribe(context.Background(), "test", cmtquery.MustParse(query))
+	require.NoError(t, err)
+
+	data := EventDataTxExpired{Hash: tx.Hash(), Height: 5}
+	require.NoError(t, eventBus.PublishEventTxExpired(data))
+
+	select {
+	case msg := <-sub.Out():
+		assert.Equal(t, data, msg.Data())
+	case <-time.After(1 * time.Second):
+		t.Fatal("did not receive the expired tx after 1 sec.")
+	}
+}
+
 func TestEventBusPublishEventIndexWrapper(t *testing.T) {
 	eventBus := NewEventBus()
 	err := eventBus.Start()
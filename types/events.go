@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
 	cmtjson "github.com/tendermint/tendermint/libs/json"
 	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	cmtquery "github.com/tendermint/tendermint/libs/pubsub/query"
@@ -37,6 +38,12 @@ const (
 	EventUnlock           = "Unlock"
 	EventValidBlock       = "ValidBlock"
 	EventVote             = "Vote"
+
+	// Mempool events.
+	// EventTxExpired is fired when a transaction is purged from the mempool
+	// because it exceeded its TTL, so that its sender can learn it will not
+	// be committed.
+	EventTxExpired = "TxExpired"
 )
 
 // ENCODING / DECODING
@@ -52,6 +59,7 @@ func init() {
 	cmtjson.RegisterType(EventDataNewBlockHeader{}, "tendermint/event/NewBlockHeader")
 	cmtjson.RegisterType(EventDataNewEvidence{}, "tendermint/event/NewEvidence")
 	cmtjson.RegisterType(EventDataTx{}, "tendermint/event/Tx")
+	cmtjson.RegisterType(EventDataTxExpired{}, "tendermint/event/TxExpired")
 	cmtjson.RegisterType(EventDataRoundState{}, "tendermint/event/RoundState")
 	cmtjson.RegisterType(EventDataNewRound{}, "tendermint/event/NewRound")
 	cmtjson.RegisterType(EventDataCompleteProposal{}, "tendermint/event/CompleteProposal")
@@ -98,6 +106,14 @@ type EventDataTx struct {
 	abci.TxResult
 }
 
+// EventDataTxExpired is fired for each tx purged from the mempool because
+// it exceeded its TTL.
+type EventDataTxExpired struct {
+	Hash cmtbytes.HexBytes `json:"hash"`
+	// Height is the height of the last block committed when the tx expired.
+	Height int64 `json:"height"`
+}
+
 // NOTE: This goes into the replay WAL
 type EventDataRoundState struct {
 	Height int64  `json:"height"`
@@ -172,6 +188,7 @@ var (
 	EventQueryTimeoutPropose      = QueryForEvent(EventTimeoutPropose)
 	EventQueryTimeoutWait         = QueryForEvent(EventTimeoutWait)
 	EventQueryTx                  = QueryForEvent(EventTx)
+	EventQueryTxExpired           = QueryForEvent(EventTxExpired)
 	EventQueryUnlock              = QueryForEvent(EventUnlock)
 	EventQueryValidatorSetUpdates = QueryForEvent(EventValidatorSetUpdates)
 	EventQueryValidBlock          = QueryForEvent(EventValidBlock)
@@ -199,3 +216,8 @@ type BlockEventPublisher interface {
 type TxEventPublisher interface {
 	PublishEventTx(EventDataTx) error
 }
+
+// MempoolEventPublisher publishes the events of the mempool.
+type MempoolEventPublisher interface {
+	PublishEventTxExpired(EventDataTxExpired) error
+}
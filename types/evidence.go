@@ -252,21 +252,29 @@ func (l *LightClientAttackEvidence) GetByzantineValidators(commonVals *Validator
 		return validators
 	} else if trusted.Commit.Round == l.ConflictingBlock.Commit.Round {
 		// This is an equivocation attack as both commits are in the same round. We then find the validators
-		// from the conflicting light block validator set that voted in both headers.
-		// Validator hashes are the same therefore the indexing order of validators are the same and thus we
-		// only need a single loop to find the validators that voted twice.
-		for i := 0; i < len(l.ConflictingBlock.Commit.Signatures); i++ {
-			sigA := l.ConflictingBlock.Commit.Signatures[i]
+		// from the conflicting light block validator set that voted in both headers. Validators are matched
+		// by address rather than by index, so that a commit that isn't aligned with the validator set can't
+		// attribute a vote to the wrong validator.
+		signedTrusted := make(map[string]struct{}, len(trusted.Commit.Signatures))
+		for _, sigB := range trusted.Commit.Signatures {
+			if !sigB.Absent() {
+				signedTrusted[string(sigB.ValidatorAddress)] = struct{}{}
+			}
+		}
+
+		for _, sigA := range l.ConflictingBlock.Commit.Signatures {
 			if sigA.Absent() {
 				continue
 			}
-
-			sigB := trusted.Commit.Signatures[i]
-			if sigB.Absent() {
+			if _, ok := signedTrusted[string(sigA.ValidatorAddress)]; !ok {
 				continue
 			}
 
 			_, val := l.ConflictingBlock.ValidatorSet.GetByAddress(sigA.ValidatorAddress)
+			if val == nil {
+				// validator wasn't in the conflicting validator set
+				continue
+			}
 			validators = append(validators, val)
 		}
 		sort.Sort(ValidatorsByVotingPower(validators))
@@ -533,6 +541,11 @@ func (err *ErrInvalidEvidence) Error() string {
 	return fmt.Sprintf("Invalid evidence: %v. Evidence: %v", err.Reason, err.Evidence)
 }
 
+// Unwrap returns the reason why the evidence is invalid.
+func (err *ErrInvalidEvidence) Unwrap() error {
+	return err.Reason
+}
+
 // ErrEvidenceOverflow is for when there the amount of evidence exceeds the max bytes.
 type ErrEvidenceOverflow struct {
 	Max int64
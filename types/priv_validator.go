@@ -19,6 +19,32 @@ type PrivValidator interface {
 	SignProposal(chainID string, proposal *cmtproto.Proposal) error
 }
 
+// KeyRotatingPrivValidator is a PrivValidator which can hold both the old and
+// the new consensus keys of a validator while it rotates its key. It signs
+// with the selected key, which GetPubKey returns.
+type KeyRotatingPrivValidator interface {
+	PrivValidator
+
+	// GetPubKeys returns all the public keys, the old key first.
+	GetPubKeys() ([]crypto.PubKey, error)
+	// SelectKey selects the key, among GetPubKeys, with the given address.
+	SelectKey(address Address) error
+}
+
+// Errors of PrivValidators signing remotely, telling whether retrying a sign
+// request within the same consensus step is safe.
+var (
+	// ErrSignTimeout is returned when the signer didn't answer a sign request
+	// in time. It may still be signing it, so retrying would likely time out
+	// again, or send the signed message twice.
+	ErrSignTimeout = errors.New("sign request timed out")
+	// ErrConnectionDropped is returned when the connection to the signer
+	// failed while sending a sign request or waiting for its response. The
+	// request can be retried once reconnected: the signer either didn't get
+	// it, or refuses to sign anything conflicting with what it signed.
+	ErrConnectionDropped = errors.New("endpoint connection dropped")
+)
+
 type PrivValidatorsByAddress []PrivValidator
 
 func (pvs PrivValidatorsByAddress) Len() int {
@@ -42,8 +42,8 @@ func (tx Tx) Hash() []byte {
 // Key returns the sha256 hash of the wire encoded transaction. It attempts to
 // unwrap the transaction if it is a BlobTx or a IndexWrapper.
 func (tx Tx) Key() TxKey {
-	if blobTx, isBlobTx := UnmarshalBlobTx(tx); isBlobTx {
-		return sha256.Sum256(blobTx.Tx)
+	if key, err := BlobTxKey(tx); err == nil {
+		return key
 	}
 	if indexWrapper, isIndexWrapper := UnmarshalIndexWrapper(tx); isIndexWrapper {
 		return sha256.Sum256(indexWrapper.Tx)
@@ -247,23 +247,8 @@ func MarshalIndexWrapper(tx Tx, shareIndexes ...uint32) (Tx, error) {
 // UnmarshalBlobTx attempts to unmarshal a transaction into blob transaction. If an
 // error is thrown, false is returned.
 func UnmarshalBlobTx(tx Tx) (bTx cmtproto.BlobTx, isBlob bool) {
-	err := bTx.Unmarshal(tx)
-	if err != nil {
-		return cmtproto.BlobTx{}, false
-	}
-	// perform some quick basic checks to prevent false positives
-	if bTx.TypeId != consts.ProtoBlobTxTypeID {
-		return bTx, false
-	}
-	if len(bTx.Blobs) == 0 {
-		return bTx, false
-	}
-	for _, b := range bTx.Blobs {
-		if len(b.NamespaceId) != consts.NamespaceIDSize {
-			return bTx, false
-		}
-	}
-	return bTx, true
+	bTx, err := decodeBlobTx(tx)
+	return bTx, err == nil
 }
 
 // MarshalBlobTx creates a BlobTx using a normal transaction and some number of
@@ -279,3 +264,177 @@ func MarshalBlobTx(tx []byte, blobs ...*cmtproto.Blob) (Tx, error) {
 	}
 	return bTx.Marshal()
 }
+
+// BlobTxLimits bounds the blobs of a BlobTx. A zero field sets no bound.
+type BlobTxLimits struct {
+	// MaxBlobs is the maximum number of blobs.
+	MaxBlobs int
+	// MaxBlobSize is the maximum size of the data of a blob, in bytes.
+	MaxBlobSize int
+	// MaxTotalBlobSize is the maximum size of the data of all the blobs, in
+	// bytes.
+	MaxTotalBlobSize int
+}
+
+// ValidateBlobTx returns an error if tx isn't a well-formed BlobTx, with a
+// non-empty inner transaction and blobs within limits. The error is
+// ErrNotBlobTx, ErrBlobTxNoBlobs or ErrBlobTxEmptyTx, possibly wrapped, or one
+// of the ErrBlobTx* struct types.
+//
+// NOTE: the inner transaction, e.g. the MsgPayForBlobs it must contain, is
+// defined by the application and must be checked by it.
+func ValidateBlobTx(tx Tx, limits BlobTxLimits) error {
+	var bTx cmtproto.BlobTx
+	if err := bTx.Unmarshal(tx); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotBlobTx, err)
+	}
+	if bTx.TypeId != consts.ProtoBlobTxTypeID {
+		return fmt.Errorf("%w: type ID %q", ErrNotBlobTx, bTx.TypeId)
+	}
+	if err := checkBlobTxEnvelope(bTx); err != nil {
+		return err
+	}
+	return validateBlobTx(bTx, limits)
+}
+
+// BlobTxKey returns the key of the BlobTx tx, the sha256 hash of its inner
+// transaction, which is also its key in the mempool. It returns an error if
+// tx isn't a BlobTx, the bare ErrNotBlobTx for a transaction which doesn't
+// unmarshal into one.
+func BlobTxKey(tx Tx) (TxKey, error) {
+	bTx, err := decodeBlobTx(tx)
+	if err != nil {
+		return TxKey{}, err
+	}
+	return sha256.Sum256(bTx.Tx), nil
+}
+
+// NewBlobTx creates a BlobTx from the transaction tx and blobs, and returns an
+// error, like ValidateBlobTx, if it isn't valid.
+func NewBlobTx(tx []byte, limits BlobTxLimits, blobs ...*cmtproto.Blob) (Tx, error) {
+	bTx := cmtproto.BlobTx{
+		Tx:     tx,
+		Blobs:  blobs,
+		TypeId: consts.ProtoBlobTxTypeID,
+	}
+	if err := checkBlobTxEnvelope(bTx); err != nil {
+		return nil, err
+	}
+	if err := validateBlobTx(bTx, limits); err != nil {
+		return nil, err
+	}
+	return bTx.Marshal()
+}
+
+// decodeBlobTx unmarshals tx into a BlobTx and checks its envelope, so that a
+// transaction which isn't a BlobTx is unlikely to be taken for one. As it
+// runs for the key of every transaction, a transaction which doesn't unmarshal
+// into a BlobTx gets the bare ErrNotBlobTx, without details to format.
+func decodeBlobTx(tx Tx) (cmtproto.BlobTx, error) {
+	var bTx cmtproto.BlobTx
+	if err := bTx.Unmarshal(tx); err != nil || bTx.TypeId != consts.ProtoBlobTxTypeID {
+		return cmtproto.BlobTx{}, ErrNotBlobTx
+	}
+	return bTx, checkBlobTxEnvelope(bTx)
+}
+
+func checkBlobTxEnvelope(bTx cmtproto.BlobTx) error {
+	if len(bTx.Blobs) == 0 {
+		return ErrBlobTxNoBlobs
+	}
+	for i, b := range bTx.Blobs {
+		if len(b.GetNamespaceId()) != consts.NamespaceIDSize {
+			return &ErrBlobTxInvalidNamespace{Index: i, Size: len(b.GetNamespaceId())}
+		}
+	}
+	return nil
+}
+
+// validateBlobTx checks the content of the well-formed BlobTx bTx.
+func validateBlobTx(bTx cmtproto.BlobTx, limits BlobTxLimits) error {
+	if len(bTx.Tx) == 0 {
+		return ErrBlobTxEmptyTx
+	}
+	if limits.MaxBlobs > 0 && len(bTx.Blobs) > limits.MaxBlobs {
+		return &ErrBlobTxTooManyBlobs{Max: limits.MaxBlobs, Got: len(bTx.Blobs)}
+	}
+	total := 0
+	for i, b := range bTx.Blobs {
+		if len(b.Data) == 0 {
+			return &ErrBlobTxEmptyBlob{Index: i}
+		}
+		if limits.MaxBlobSize > 0 && len(b.Data) > limits.MaxBlobSize {
+			return &ErrBlobTxBlobTooLarge{Index: i, Max: limits.MaxBlobSize, Got: len(b.Data)}
+		}
+		total += len(b.Data)
+	}
+	if limits.MaxTotalBlobSize > 0 && total > limits.MaxTotalBlobSize {
+		return &ErrBlobTxBlobsTooLarge{Max: limits.MaxTotalBlobSize, Got: total}
+	}
+	return nil
+}
+
+var (
+	// ErrNotBlobTx is returned for a transaction which doesn't unmarshal into
+	// a BlobTx, or doesn't have its type ID.
+	ErrNotBlobTx = errors.New("not a blob tx")
+	// ErrBlobTxNoBlobs is returned for a BlobTx without blobs.
+	ErrBlobTxNoBlobs = errors.New("blob tx has no blobs")
+	// ErrBlobTxEmptyTx is returned for a BlobTx whose inner transaction is
+	// empty.
+	ErrBlobTxEmptyTx = errors.New("blob tx has an empty inner tx")
+)
+
+// ErrBlobTxInvalidNamespace is returned for a BlobTx with a blob whose
+// namespace ID doesn't have the right size.
+type ErrBlobTxInvalidNamespace struct {
+	Index int
+	Size  int
+}
+
+func (err *ErrBlobTxInvalidNamespace) Error() string {
+	return fmt.Sprintf("blob %d of blob tx has a namespace ID of %d bytes, expected %d",
+		err.Index, err.Size, consts.NamespaceIDSize)
+}
+
+// ErrBlobTxEmptyBlob is returned for a BlobTx with a blob without data.
+type ErrBlobTxEmptyBlob struct {
+	Index int
+}
+
+func (err *ErrBlobTxEmptyBlob) Error() string {
+	return fmt.Sprintf("blob %d of blob tx is empty", err.Index)
+}
+
+// ErrBlobTxTooManyBlobs is returned for a BlobTx with more blobs than allowed.
+type ErrBlobTxTooManyBlobs struct {
+	Max int
+	Got int
+}
+
+func (err *ErrBlobTxTooManyBlobs) Error() string {
+	return fmt.Sprintf("blob tx has too many blobs: max %d, got %d", err.Max, err.Got)
+}
+
+// ErrBlobTxBlobTooLarge is returned for a BlobTx with a blob larger than
+// allowed.
+type ErrBlobTxBlobTooLarge struct {
+	Index int
+	Max   int
+	Got   int
+}
+
+func (err *ErrBlobTxBlobTooLarge) Error() string {
+	return fmt.Sprintf("blob %d of blob tx is too large: max %d bytes, got %d", err.Index, err.Max, err.Got)
+}
+
+// ErrBlobTxBlobsTooLarge is returned for a BlobTx whose blobs are larger, in
+// total, than allowed.
+type ErrBlobTxBlobsTooLarge struct {
+	Max int
+	Got int
+}
+
+func (err *ErrBlobTxBlobsTooLarge) Error() string {
+	return fmt.Sprintf("blobs of blob tx are too large: max %d bytes, got %d", err.Max, err.Got)
+}
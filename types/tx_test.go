@@ -2,6 +2,8 @@ package types
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -111,6 +113,184 @@ func TestUnmarshalBlobTxFalsePositive(t *testing.T) {
 	require.False(t, isBlob)
 }
 
+func TestValidateBlobTx(t *testing.T) {
+	namespace := bytes.Repeat([]byte{1}, consts.NamespaceIDSize)
+	blob := func(size int) *cmtproto.Blob {
+		return &cmtproto.Blob{NamespaceId: namespace, Data: bytes.Repeat([]byte{2}, size)}
+	}
+	marshal := func(bTx cmtproto.BlobTx) Tx {
+		bz, err := bTx.Marshal()
+		require.NoError(t, err)
+		return bz
+	}
+	envelope := func(tx []byte, blobs ...*cmtproto.Blob) Tx {
+		return marshal(cmtproto.BlobTx{Tx: tx, Blobs: blobs, TypeId: consts.ProtoBlobTxTypeID})
+	}
+	valid := envelope([]byte("pfb"), blob(10), blob(20))
+	indexWrapper, err := MarshalIndexWrapper([]byte("pfb"), 1)
+	require.NoError(t, err)
+	limits := BlobTxLimits{MaxBlobs: 2, MaxBlobSize: 20, MaxTotalBlobSize: 30}
+
+	testCases := []struct {
+		name    string
+		tx      Tx
+		limits  BlobTxLimits
+		wantErr error
+	}{
+		{"valid", valid, limits, nil},
+		{"valid without limits", valid, BlobTxLimits{}, nil},
+		{"not protobuf", Tx("sender-193-0=D16B687628035716B1DA53BE1491A1B3D4CEA3AB=1025"), limits, ErrNotBlobTx},
+		{"truncated", valid[:len(valid)-3], limits, ErrNotBlobTx},
+		{"empty", Tx{}, limits, ErrNotBlobTx},
+		{"index wrapper", indexWrapper, limits, ErrNotBlobTx},
+		{
+			"wrong type ID",
+			marshal(cmtproto.BlobTx{Tx: []byte("pfb"), Blobs: []*cmtproto.Blob{blob(1)}, TypeId: "BLOC"}),
+			limits, ErrNotBlobTx,
+		},
+		{
+			"no blobs",
+			envelope([]byte("pfb")),
+			limits, ErrBlobTxNoBlobs,
+		},
+		{
+			"short namespace",
+			envelope([]byte("pfb"), blob(1), &cmtproto.Blob{NamespaceId: namespace[1:], Data: []byte{1}}),
+			limits, &ErrBlobTxInvalidNamespace{Index: 1, Size: consts.NamespaceIDSize - 1},
+		},
+		{
+			"empty inner tx",
+			envelope(nil, blob(1)),
+			limits, ErrBlobTxEmptyTx,
+		},
+		{
+			"empty blob",
+			envelope([]byte("pfb"), blob(1), blob(0)),
+			limits, &ErrBlobTxEmptyBlob{Index: 1},
+		},
+		{
+			"too many blobs",
+			envelope([]byte("pfb"), blob(1), blob(1), blob(1)),
+			limits, &ErrBlobTxTooManyBlobs{Max: 2, Got: 3},
+		},
+		{
+			"oversized blob",
+			envelope([]byte("pfb"), blob(1), blob(21)),
+			limits, &ErrBlobTxBlobTooLarge{Index: 1, Max: 20, Got: 21},
+		},
+		{
+			"oversized blobs",
+			envelope([]byte("pfb"), blob(15), blob(16)),
+			limits, &ErrBlobTxBlobsTooLarge{Max: 30, Got: 31},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateBlobTx(tc.tx, tc.limits)
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			if errors.Is(tc.wantErr, ErrNotBlobTx) {
+				// the validation details why tx isn't a blob tx
+				assert.NotEqual(t, ErrNotBlobTx.Error(), err.Error())
+			}
+			if !errors.Is(err, tc.wantErr) {
+				assert.Equal(t, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewBlobTx(t *testing.T) {
+	namespace := bytes.Repeat([]byte{1}, consts.NamespaceIDSize)
+	blob := &cmtproto.Blob{NamespaceId: namespace, Data: []byte("data"), ShareVersion: 1}
+
+	tx, err := NewBlobTx([]byte("pfb"), BlobTxLimits{MaxBlobs: 1}, blob)
+	require.NoError(t, err)
+	require.NoError(t, ValidateBlobTx(tx, BlobTxLimits{MaxBlobs: 1}))
+	bTx, isBlob := UnmarshalBlobTx(tx)
+	require.True(t, isBlob)
+	assert.Equal(t, []byte("pfb"), bTx.Tx)
+	assert.Equal(t, []*cmtproto.Blob{blob}, bTx.Blobs)
+
+	_, err = NewBlobTx([]byte("pfb"), BlobTxLimits{MaxBlobs: 1}, blob, blob)
+	assert.Equal(t, &ErrBlobTxTooManyBlobs{Max: 1, Got: 2}, err)
+	_, err = NewBlobTx([]byte("pfb"), BlobTxLimits{})
+	assert.Equal(t, ErrBlobTxNoBlobs, err)
+	_, err = NewBlobTx(nil, BlobTxLimits{}, blob)
+	assert.Equal(t, ErrBlobTxEmptyTx, err)
+	_, err = NewBlobTx([]byte("pfb"), BlobTxLimits{}, &cmtproto.Blob{Data: []byte("data")})
+	assert.Equal(t, &ErrBlobTxInvalidNamespace{Index: 0, Size: 0}, err)
+}
+
+// TestBlobTxKey checks that BlobTxKey derives the same key as the mempool,
+// which uses Tx.Key, did before it was added.
+func TestBlobTxKey(t *testing.T) {
+	// the derivation of Tx.Key before BlobTxKey was added
+	legacyKey := func(tx Tx) TxKey {
+		var bTx cmtproto.BlobTx
+		if err := bTx.Unmarshal(tx); err == nil && bTx.TypeId == consts.ProtoBlobTxTypeID && len(bTx.Blobs) > 0 {
+			isBlob := true
+			for _, b := range bTx.Blobs {
+				isBlob = isBlob && len(b.NamespaceId) == consts.NamespaceIDSize
+			}
+			if isBlob {
+				return sha256.Sum256(bTx.Tx)
+			}
+		}
+		if indexWrapper, isIndexWrapper := UnmarshalIndexWrapper(tx); isIndexWrapper {
+			return sha256.Sum256(indexWrapper.Tx)
+		}
+		return sha256.Sum256(tx)
+	}
+
+	namespace := bytes.Repeat([]byte{1}, consts.NamespaceIDSize)
+	blobTx, err := MarshalBlobTx([]byte("pfb"), &cmtproto.Blob{NamespaceId: namespace, Data: []byte("data")})
+	require.NoError(t, err)
+	// envelopes the mempool takes for blob txs, while they aren't valid
+	emptyInner, err := MarshalBlobTx(nil, &cmtproto.Blob{NamespaceId: namespace})
+	require.NoError(t, err)
+	noBlobs, err := MarshalBlobTx([]byte("pfb"))
+	require.NoError(t, err)
+	indexWrapper, err := MarshalIndexWrapper([]byte("pfb"), 1)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name   string
+		tx     Tx
+		isBlob bool
+	}{
+		{"blob tx", blobTx, true},
+		{"empty inner tx", emptyInner, true},
+		{"no blobs", noBlobs, false},
+		{"index wrapper", indexWrapper, false},
+		{"plain tx", Tx("sender-193-0=D16B687628035716B1DA53BE1491A1B3D4CEA3AB=1025"), false},
+		{"random tx", cmtrand.Bytes(64), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, legacyKey(tc.tx), tc.tx.Key())
+			key, err := BlobTxKey(tc.tx)
+			if !tc.isBlob {
+				require.Error(t, err)
+				if tc.name != "no blobs" {
+					// the key of a tx which isn't a blob tx is derived
+					// without formatting an error
+					assert.Same(t, ErrNotBlobTx, err)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.tx.Key(), key)
+			bTx, isBlob := UnmarshalBlobTx(tc.tx)
+			require.True(t, isBlob)
+			assert.Equal(t, TxKey(sha256.Sum256(bTx.Tx)), key)
+		})
+	}
+}
+
 func TestTxKeyFromBytes(t *testing.T) {
 	tx := Tx("hello")
 	key := tx.Key()